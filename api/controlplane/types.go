@@ -3,6 +3,10 @@ package controlplane
 import (
 	"fmt"
 	"regexp"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/identifiers"
 )
 
 // OutcomeKind mirrors docs/ContractArtifacts.schema.json decision_outcome.outcome_kind.
@@ -15,6 +19,7 @@ const (
 	OutcomeShed             OutcomeKind = "shed"
 	OutcomeStaleEpochReject OutcomeKind = "stale_epoch_reject"
 	OutcomeDeauthorized     OutcomeKind = "deauthorized_drain"
+	OutcomeBudgetExceeded   OutcomeKind = "budget_exceeded"
 )
 
 // OutcomePhase mirrors docs/ContractArtifacts.schema.json decision_outcome.phase.
@@ -49,17 +54,72 @@ const (
 
 // DecisionOutcome models deterministic admission/authority outputs.
 type DecisionOutcome struct {
-	OutcomeKind        OutcomeKind    `json:"outcome_kind"`
-	Phase              OutcomePhase   `json:"phase"`
-	Scope              OutcomeScope   `json:"scope"`
-	SessionID          string         `json:"session_id"`
-	TurnID             string         `json:"turn_id,omitempty"`
-	EventID            string         `json:"event_id"`
-	RuntimeTimestampMS int64          `json:"runtime_timestamp_ms"`
-	WallClockMS        int64          `json:"wall_clock_timestamp_ms"`
-	EmittedBy          OutcomeEmitter `json:"emitted_by"`
-	AuthorityEpoch     *int64         `json:"authority_epoch,omitempty"`
-	Reason             string         `json:"reason"`
+	OutcomeKind        OutcomeKind            `json:"outcome_kind"`
+	Phase              OutcomePhase           `json:"phase"`
+	Scope              OutcomeScope           `json:"scope"`
+	SessionID          string                 `json:"session_id"`
+	TurnID             string                 `json:"turn_id,omitempty"`
+	EventID            string                 `json:"event_id"`
+	RuntimeTimestampMS int64                  `json:"runtime_timestamp_ms"`
+	WallClockMS        int64                  `json:"wall_clock_timestamp_ms"`
+	EmittedBy          OutcomeEmitter         `json:"emitted_by"`
+	AuthorityEpoch     *int64                 `json:"authority_epoch,omitempty"`
+	Reason             string                 `json:"reason"`
+	FailureDomain      eventabi.FailureDomain `json:"failure_domain,omitempty"`
+	Explain            *DecisionExplain       `json:"explain,omitempty"`
+}
+
+// DecisionExplain mirrors docs/ContractArtifacts.schema.json decision_explain. It is attached
+// only when decision explainability is enabled and records the evidence behind an outcome.
+type DecisionExplain struct {
+	PolicyRule    string                `json:"policy_rule"`
+	Inputs        map[string]string     `json:"inputs,omitempty"`
+	QuotaCounters []QuotaCounter        `json:"quota_counters,omitempty"`
+	Thresholds    []ThresholdEvaluation `json:"thresholds,omitempty"`
+}
+
+// QuotaCounter captures one quota counter observed when the decision was made.
+type QuotaCounter struct {
+	Name  string `json:"name"`
+	Used  int64  `json:"used"`
+	Limit int64  `json:"limit"`
+}
+
+// ThresholdEvaluation captures one threshold comparison evaluated for the decision.
+type ThresholdEvaluation struct {
+	Name      string  `json:"name"`
+	Observed  float64 `json:"observed"`
+	Threshold float64 `json:"threshold"`
+	Breached  bool    `json:"breached"`
+}
+
+// Validate checks explain payload shape.
+func (e DecisionExplain) Validate() error {
+	if e.PolicyRule == "" {
+		return fmt.Errorf("explain policy_rule is required")
+	}
+	for _, counter := range e.QuotaCounters {
+		if counter.Name == "" {
+			return fmt.Errorf("explain quota_counters name is required")
+		}
+		if counter.Used < 0 || counter.Limit < 0 {
+			return fmt.Errorf("explain quota_counters %s must be >= 0", counter.Name)
+		}
+	}
+	for _, threshold := range e.Thresholds {
+		if threshold.Name == "" {
+			return fmt.Errorf("explain thresholds name is required")
+		}
+	}
+	return nil
+}
+
+// ResolvedFailureDomain returns the tagged failure domain, falling back to emitter inference.
+func (d DecisionOutcome) ResolvedFailureDomain() (eventabi.FailureDomain, bool) {
+	if d.FailureDomain != "" {
+		return d.FailureDomain, true
+	}
+	return eventabi.FailureDomainForEmitter(string(d.EmittedBy))
 }
 
 func (d DecisionOutcome) Validate() error {
@@ -69,18 +129,39 @@ func (d DecisionOutcome) Validate() error {
 	if d.SessionID == "" || d.EventID == "" || d.Reason == "" {
 		return fmt.Errorf("session_id, event_id, and reason are required")
 	}
+	if err := identifiers.ValidateSessionID(d.SessionID); err != nil {
+		return err
+	}
+	if err := identifiers.ValidateEventID(d.EventID); err != nil {
+		return err
+	}
+	if d.TurnID != "" {
+		if err := identifiers.ValidateTurnID(d.TurnID); err != nil {
+			return err
+		}
+	}
+	if d.FailureDomain != "" {
+		if err := d.FailureDomain.Validate(); err != nil {
+			return err
+		}
+	}
 	if d.RuntimeTimestampMS < 0 || d.WallClockMS < 0 {
 		return fmt.Errorf("timestamps must be >= 0")
 	}
+	if d.Explain != nil {
+		if err := d.Explain.Validate(); err != nil {
+			return err
+		}
+	}
 
 	switch d.OutcomeKind {
 	case OutcomeAdmit, OutcomeReject, OutcomeDefer:
 		if d.EmittedBy != EmitterRK25 && d.EmittedBy != EmitterCP05 {
 			return fmt.Errorf("%s must be emitted by RK-25 or CP-05", d.OutcomeKind)
 		}
-	case OutcomeShed:
+	case OutcomeShed, OutcomeBudgetExceeded:
 		if d.EmittedBy != EmitterRK25 || d.Phase != PhaseScheduling {
-			return fmt.Errorf("shed requires emitted_by=RK-25 and phase=scheduling_point")
+			return fmt.Errorf("%s requires emitted_by=RK-25 and phase=scheduling_point", d.OutcomeKind)
 		}
 	case OutcomeStaleEpochReject, OutcomeDeauthorized:
 		if d.EmittedBy != EmitterRK24 {
@@ -392,10 +473,81 @@ func (s SheddingStrategyByLane) Validate() error {
 	return nil
 }
 
+// DegradeStep is one rung of the flow-control degrade ladder, ordered mildest first.
+type DegradeStep string
+
+const (
+	DegradeReduceTelemetryDetail  DegradeStep = "reduce_telemetry_detail"
+	DegradeBypassAudioEnhancement DegradeStep = "bypass_audio_enhancement"
+	DegradeDropSTTPartials        DegradeStep = "drop_stt_partials"
+	DegradeShortenLLMMaxTokens    DegradeStep = "shorten_llm_max_tokens"
+	DegradeShed                   DegradeStep = "shed"
+)
+
+// DegradeSteps returns the ladder steps in severity order.
+func DegradeSteps() []DegradeStep {
+	return []DegradeStep{
+		DegradeReduceTelemetryDetail,
+		DegradeBypassAudioEnhancement,
+		DegradeDropSTTPartials,
+		DegradeShortenLLMMaxTokens,
+		DegradeShed,
+	}
+}
+
+// DegradeRung engages Step once queue pressure reaches SoftLimit.
+type DegradeRung struct {
+	Step      DegradeStep `json:"step"`
+	SoftLimit int         `json:"soft_limit"`
+	// LLMMaxTokens caps LLM output when Step is shorten_llm_max_tokens.
+	LLMMaxTokens int `json:"llm_max_tokens,omitempty"`
+}
+
+// DegradeLadder is an ordered set of soft-limit rungs plus a hard limit that always sheds.
+type DegradeLadder struct {
+	Rungs     []DegradeRung `json:"rungs"`
+	HardLimit int           `json:"hard_limit"`
+}
+
+func (l DegradeLadder) Validate() error {
+	if len(l.Rungs) < 1 {
+		return fmt.Errorf("flow_control.degrade_ladder requires at least one rung")
+	}
+	severity := map[DegradeStep]int{}
+	for idx, step := range DegradeSteps() {
+		severity[step] = idx
+	}
+	prevSeverity, prevLimit := -1, 0
+	for _, rung := range l.Rungs {
+		rank, ok := severity[rung.Step]
+		if !ok {
+			return fmt.Errorf("invalid flow_control.degrade_ladder step: %s", rung.Step)
+		}
+		if rank <= prevSeverity {
+			return fmt.Errorf("flow_control.degrade_ladder steps must be unique and ordered mildest first")
+		}
+		if rung.SoftLimit <= prevLimit {
+			return fmt.Errorf("flow_control.degrade_ladder soft_limit must be >=1 and strictly increasing")
+		}
+		if rung.Step == DegradeShortenLLMMaxTokens && rung.LLMMaxTokens < 1 {
+			return fmt.Errorf("flow_control.degrade_ladder shorten_llm_max_tokens requires llm_max_tokens>=1")
+		}
+		if rung.Step != DegradeShortenLLMMaxTokens && rung.LLMMaxTokens != 0 {
+			return fmt.Errorf("flow_control.degrade_ladder llm_max_tokens is only valid for shorten_llm_max_tokens")
+		}
+		prevSeverity, prevLimit = rank, rung.SoftLimit
+	}
+	if l.HardLimit <= prevLimit {
+		return fmt.Errorf("flow_control.degrade_ladder hard_limit must exceed every soft_limit")
+	}
+	return nil
+}
+
 type FlowControl struct {
 	ModeByLane             ModeByLane             `json:"mode_by_lane"`
 	Watermarks             FlowWatermarks         `json:"watermarks"`
 	SheddingStrategyByLane SheddingStrategyByLane `json:"shedding_strategy_by_lane"`
+	DegradeLadder          *DegradeLadder         `json:"degrade_ladder,omitempty"`
 }
 
 func (f FlowControl) Validate() error {
@@ -408,6 +560,11 @@ func (f FlowControl) Validate() error {
 	if err := f.SheddingStrategyByLane.Validate(); err != nil {
 		return err
 	}
+	if f.DegradeLadder != nil {
+		if err := f.DegradeLadder.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -419,6 +576,9 @@ type SnapshotProvenance struct {
 	VersionResolutionSnapshot string `json:"version_resolution_snapshot"`
 	PolicyResolutionSnapshot  string `json:"policy_resolution_snapshot"`
 	ProviderHealthSnapshot    string `json:"provider_health_snapshot"`
+	// ShardMapSnapshot and ShardOwner record session shard ownership when sharding is enabled.
+	ShardMapSnapshot string `json:"shard_map_snapshot,omitempty"`
+	ShardOwner       string `json:"shard_owner,omitempty"`
 }
 
 func (s SnapshotProvenance) Validate() error {
@@ -430,9 +590,379 @@ func (s SnapshotProvenance) Validate() error {
 		s.ProviderHealthSnapshot == "" {
 		return fmt.Errorf("all snapshot_provenance refs are required")
 	}
+	if (s.ShardMapSnapshot == "") != (s.ShardOwner == "") {
+		return fmt.Errorf("snapshot_provenance shard_map_snapshot and shard_owner must be set together")
+	}
+	return nil
+}
+
+// STTEndpointing configures when an STT utterance is considered complete.
+type STTEndpointing struct {
+	// SilenceDurationMS is the trailing silence that ends an utterance.
+	SilenceDurationMS int64 `json:"silence_duration_ms"`
+	// MinUtteranceMS discards shorter speech bursts as noise.
+	MinUtteranceMS int64 `json:"min_utterance_ms"`
+	// MaxUtteranceMS force-ends an utterance that runs without qualifying silence.
+	MaxUtteranceMS int64 `json:"max_utterance_ms"`
+	// NoSpeechTimeoutMS ends listening when no speech starts within the window.
+	NoSpeechTimeoutMS int64 `json:"no_speech_timeout_ms"`
+}
+
+func (e STTEndpointing) Validate() error {
+	if e.SilenceDurationMS < 1 || e.NoSpeechTimeoutMS < 1 {
+		return fmt.Errorf("stt_endpointing silence_duration_ms and no_speech_timeout_ms must be >=1")
+	}
+	if e.MinUtteranceMS < 0 {
+		return fmt.Errorf("stt_endpointing min_utterance_ms must be >=0")
+	}
+	if e.MaxUtteranceMS <= e.MinUtteranceMS {
+		return fmt.Errorf("stt_endpointing max_utterance_ms must exceed min_utterance_ms")
+	}
+	return nil
+}
+
+// STTEndpointingProvenance is the effective endpointing config frozen into a resolved plan.
+type STTEndpointingProvenance struct {
+	STTEndpointing
+	DefaultingSource string `json:"defaulting_source"`
+}
+
+func (e STTEndpointingProvenance) Validate() error {
+	if err := e.STTEndpointing.Validate(); err != nil {
+		return err
+	}
+	if !inStringSet(e.DefaultingSource, []string{"pipeline_version", "execution_profile_default"}) {
+		return fmt.Errorf("invalid stt_endpointing defaulting_source: %s", e.DefaultingSource)
+	}
+	return nil
+}
+
+// AudioEnhancement configures the optional noise-suppression node that runs ahead of STT.
+type AudioEnhancement struct {
+	Enabled bool `json:"enabled"`
+	// Engine is rnnoise, the in-process suppressor run ahead of STT; no remote enhancement
+	// service is wired, so no other engine is accepted.
+	Engine string `json:"engine,omitempty"`
+	// Quality trades CPU cost for suppression strength: low, balanced, or high.
+	Quality string `json:"quality,omitempty"`
+}
+
+func (a AudioEnhancement) Validate() error {
+	if !a.Enabled {
+		if a.Engine != "" || a.Quality != "" {
+			return fmt.Errorf("audio_enhancement engine and quality require enabled=true")
+		}
+		return nil
+	}
+	if !inStringSet(a.Engine, []string{"rnnoise"}) {
+		return fmt.Errorf("invalid audio_enhancement engine: %s", a.Engine)
+	}
+	if !inStringSet(a.Quality, []string{"low", "balanced", "high"}) {
+		return fmt.Errorf("invalid audio_enhancement quality: %s", a.Quality)
+	}
+	return nil
+}
+
+// Summarization configures the session summarization node, which periodically condenses
+// accumulated turn context with an LLM into a derived_summary payload bound into later turns.
+type Summarization struct {
+	// IntervalTurns is the number of completed turns folded into each new summary.
+	IntervalTurns int `json:"interval_turns"`
+	// ProviderID is the LLM provider that produces summaries.
+	ProviderID       string `json:"provider_id"`
+	MaxSummaryTokens int    `json:"max_summary_tokens"`
+	// RetentionMS bounds how long a summary stays bindable; 0 uses the tenant derived_summary
+	// retention window, which also caps any explicit value.
+	RetentionMS int64 `json:"retention_ms,omitempty"`
+}
+
+func (s Summarization) Validate() error {
+	if s.IntervalTurns < 1 {
+		return fmt.Errorf("summarization interval_turns must be >=1")
+	}
+	if s.ProviderID == "" {
+		return fmt.Errorf("summarization provider_id is required")
+	}
+	if s.MaxSummaryTokens < 1 {
+		return fmt.Errorf("summarization max_summary_tokens must be >=1")
+	}
+	if s.RetentionMS < 0 {
+		return fmt.Errorf("summarization retention_ms must be >=0")
+	}
+	return nil
+}
+
+// OutputLimits caps assistant output length per pipeline version. The runtime truncates
+// output at the cap and emits a length_capped signal; 0 leaves a modality uncapped.
+type OutputLimits struct {
+	MaxLLMOutputTokens int   `json:"max_llm_output_tokens,omitempty"`
+	MaxTTSAudioMS      int64 `json:"max_tts_audio_ms,omitempty"`
+}
+
+func (l OutputLimits) Validate() error {
+	if l.MaxLLMOutputTokens < 0 {
+		return fmt.Errorf("output_limits max_llm_output_tokens must be >=0")
+	}
+	if l.MaxTTSAudioMS < 0 {
+		return fmt.Errorf("output_limits max_tts_audio_ms must be >=0")
+	}
+	if l.MaxLLMOutputTokens == 0 && l.MaxTTSAudioMS == 0 {
+		return fmt.Errorf("output_limits requires max_llm_output_tokens or max_tts_audio_ms")
+	}
 	return nil
 }
 
+var localeTagPattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z][a-z]{3})?(-([A-Z]{2}|[0-9]{3}))?$`)
+
+// LocaleLanguage returns the primary language subtag of a well-formed locale tag.
+func LocaleLanguage(tag string) (string, bool) {
+	if !localeTagPattern.MatchString(tag) {
+		return "", false
+	}
+	language, _, _ := strings.Cut(tag, "-")
+	return language, true
+}
+
+// SessionLocale selects the LLM prompt language, TTS voice, and STT language hints of a
+// session jointly. Locale is required; the other fields override the locale defaults.
+type SessionLocale struct {
+	// Locale is a BCP-47 tag such as en-US or pt-BR.
+	Locale            string   `json:"locale"`
+	LLMPromptLanguage string   `json:"llm_prompt_language,omitempty"`
+	TTSVoice          string   `json:"tts_voice,omitempty"`
+	TTSVoiceLocale    string   `json:"tts_voice_locale,omitempty"`
+	STTLanguageHints  []string `json:"stt_language_hints,omitempty"`
+}
+
+func (l SessionLocale) Validate() error {
+	if _, ok := LocaleLanguage(l.Locale); !ok {
+		return fmt.Errorf("invalid locale: %q", l.Locale)
+	}
+	if l.LLMPromptLanguage != "" && !localeTagPattern.MatchString(l.LLMPromptLanguage) {
+		return fmt.Errorf("invalid locale llm_prompt_language: %q", l.LLMPromptLanguage)
+	}
+	if l.TTSVoiceLocale != "" && !localeTagPattern.MatchString(l.TTSVoiceLocale) {
+		return fmt.Errorf("invalid locale tts_voice_locale: %q", l.TTSVoiceLocale)
+	}
+	for _, hint := range l.STTLanguageHints {
+		if !localeTagPattern.MatchString(hint) {
+			return fmt.Errorf("invalid locale stt_language_hints entry: %q", hint)
+		}
+	}
+	return nil
+}
+
+// ResolvedLocale is the effective session locale frozen into a resolved plan.
+type ResolvedLocale struct {
+	SessionLocale
+	DefaultingSource string `json:"defaulting_source"`
+}
+
+// Validate enforces that prompt language, TTS voice, and STT hints agree with the locale
+// language so a session never speaks one language and listens for another.
+func (l ResolvedLocale) Validate() error {
+	if err := l.SessionLocale.Validate(); err != nil {
+		return err
+	}
+	language, _ := LocaleLanguage(l.Locale)
+	if l.LLMPromptLanguage == "" || l.TTSVoice == "" || l.TTSVoiceLocale == "" || len(l.STTLanguageHints) == 0 {
+		return fmt.Errorf("locale llm_prompt_language, tts_voice, tts_voice_locale, and stt_language_hints are required")
+	}
+	if promptLanguage, _ := LocaleLanguage(l.LLMPromptLanguage); promptLanguage != language {
+		return fmt.Errorf("locale llm_prompt_language %s does not match locale %s", l.LLMPromptLanguage, l.Locale)
+	}
+	if voiceLanguage, _ := LocaleLanguage(l.TTSVoiceLocale); voiceLanguage != language {
+		return fmt.Errorf("locale tts_voice %s (%s) does not match locale %s", l.TTSVoice, l.TTSVoiceLocale, l.Locale)
+	}
+	if hintLanguage, _ := LocaleLanguage(l.STTLanguageHints[0]); hintLanguage != language {
+		return fmt.Errorf("locale primary stt_language_hint %s does not match locale %s", l.STTLanguageHints[0], l.Locale)
+	}
+	if !inStringSet(l.DefaultingSource, []string{"session", "locale_default"}) {
+		return fmt.Errorf("invalid locale defaulting_source: %s", l.DefaultingSource)
+	}
+	return nil
+}
+
+// TriggerMode selects how a session opens turns upstream of the turn arbiter.
+type TriggerMode string
+
+const (
+	// TriggerContinuous opens a turn on speech onset while the session is always listening.
+	TriggerContinuous TriggerMode = "continuous"
+	// TriggerPushToTalk opens a turn on a transport capture_start message and ends capture on
+	// capture_end.
+	TriggerPushToTalk TriggerMode = "push_to_talk"
+	// TriggerWakeWord opens a turn when the local wake-word detector fires.
+	TriggerWakeWord TriggerMode = "wake_word"
+)
+
+func (m TriggerMode) Validate() error {
+	switch m {
+	case TriggerContinuous, TriggerPushToTalk, TriggerWakeWord:
+		return nil
+	default:
+		return fmt.Errorf("invalid trigger mode: %s", m)
+	}
+}
+
+// SessionTrigger configures the session trigger mode.
+type SessionTrigger struct {
+	Mode TriggerMode `json:"mode"`
+	// WakeWords restricts wake_word mode to these phrases; empty accepts any detection.
+	WakeWords []string `json:"wake_words,omitempty"`
+	// WakeWordMinConfidence drops wake-word detections below this confidence.
+	WakeWordMinConfidence float64 `json:"wake_word_min_confidence,omitempty"`
+	// MaxCaptureMS force-ends push_to_talk capture when capture_end never arrives; 0 disables.
+	MaxCaptureMS int64 `json:"max_capture_ms,omitempty"`
+}
+
+func (t SessionTrigger) Validate() error {
+	if err := t.Mode.Validate(); err != nil {
+		return err
+	}
+	if t.Mode != TriggerWakeWord && (len(t.WakeWords) > 0 || t.WakeWordMinConfidence != 0) {
+		return fmt.Errorf("wake_words and wake_word_min_confidence require mode=%s", TriggerWakeWord)
+	}
+	if t.Mode != TriggerPushToTalk && t.MaxCaptureMS != 0 {
+		return fmt.Errorf("max_capture_ms requires mode=%s", TriggerPushToTalk)
+	}
+	for _, word := range t.WakeWords {
+		if strings.TrimSpace(word) == "" {
+			return fmt.Errorf("wake_words entries must be non-empty")
+		}
+	}
+	if t.WakeWordMinConfidence < 0 || t.WakeWordMinConfidence > 1 {
+		return fmt.Errorf("wake_word_min_confidence must be within [0,1]")
+	}
+	if t.MaxCaptureMS < 0 {
+		return fmt.Errorf("max_capture_ms must be >=0")
+	}
+	return nil
+}
+
+// TurnTrigger records what opened a turn: the trigger mode, when it fired, and when capture
+// ended (nil while capture is still open).
+type TurnTrigger struct {
+	Mode           TriggerMode `json:"mode"`
+	TriggeredAtMS  int64       `json:"triggered_at_ms"`
+	CaptureEndAtMS *int64      `json:"capture_end_at_ms,omitempty"`
+	// CaptureEndReason is capture_end, endpoint, or max_capture when capture has ended.
+	CaptureEndReason   string  `json:"capture_end_reason,omitempty"`
+	WakeWord           string  `json:"wake_word,omitempty"`
+	WakeWordConfidence float64 `json:"wake_word_confidence,omitempty"`
+}
+
+func (t TurnTrigger) Validate() error {
+	if err := t.Mode.Validate(); err != nil {
+		return err
+	}
+	if t.TriggeredAtMS < 0 {
+		return fmt.Errorf("trigger triggered_at_ms must be >=0")
+	}
+	if t.CaptureEndAtMS != nil {
+		if *t.CaptureEndAtMS < t.TriggeredAtMS {
+			return fmt.Errorf("trigger capture_end_at_ms must be >= triggered_at_ms")
+		}
+		if !inStringSet(t.CaptureEndReason, []string{"capture_end", "endpoint", "max_capture"}) {
+			return fmt.Errorf("invalid trigger capture_end_reason: %s", t.CaptureEndReason)
+		}
+	} else if t.CaptureEndReason != "" {
+		return fmt.Errorf("trigger capture_end_reason requires capture_end_at_ms")
+	}
+	if (t.Mode == TriggerWakeWord) != (t.WakeWord != "") {
+		return fmt.Errorf("trigger wake_word is required exactly for mode=%s", TriggerWakeWord)
+	}
+	if t.WakeWordConfidence < 0 || t.WakeWordConfidence > 1 {
+		return fmt.Errorf("trigger wake_word_confidence must be within [0,1]")
+	}
+	return nil
+}
+
+// SLATier is a tenant-declared service level: the first-output latency bound every turn is
+// held to and the minimum fraction of turns that must complete.
+type SLATier struct {
+	Tier                    string  `json:"tier"`
+	MaxFirstOutputLatencyMS int64   `json:"max_first_output_latency_ms"`
+	MinAvailability         float64 `json:"min_availability"`
+}
+
+func (t SLATier) Validate() error {
+	if strings.TrimSpace(t.Tier) == "" {
+		return fmt.Errorf("sla tier is required")
+	}
+	if t.MaxFirstOutputLatencyMS < 1 {
+		return fmt.Errorf("sla max_first_output_latency_ms must be >=1")
+	}
+	if t.MinAvailability <= 0 || t.MinAvailability > 1 {
+		return fmt.Errorf("sla min_availability must be within (0,1]")
+	}
+	return nil
+}
+
+// TurnSLA annotates a turn with the SLA tier its tenant declared.
+type TurnSLA struct {
+	TenantID string `json:"tenant_id"`
+	SLATier
+}
+
+func (s TurnSLA) Validate() error {
+	if strings.TrimSpace(s.TenantID) == "" {
+		return fmt.Errorf("sla tenant_id is required")
+	}
+	return s.SLATier.Validate()
+}
+
+// TenantBudget caps what one tenant's turns may consume. A zero field is unlimited.
+type TenantBudget struct {
+	MaxTokensPerTurn   int64   `json:"max_tokens_per_turn,omitempty"`
+	MaxSessionSpendUSD float64 `json:"max_session_spend_usd,omitempty"`
+}
+
+func (b TenantBudget) Validate() error {
+	if b.MaxTokensPerTurn < 0 {
+		return fmt.Errorf("budget max_tokens_per_turn must be >=0")
+	}
+	if b.MaxSessionSpendUSD < 0 {
+		return fmt.Errorf("budget max_session_spend_usd must be >=0")
+	}
+	return nil
+}
+
+// Unlimited reports whether the budget caps nothing.
+func (b TenantBudget) Unlimited() bool {
+	return b.MaxTokensPerTurn == 0 && b.MaxSessionSpendUSD == 0
+}
+
+// BudgetPolicy is the policy-bundle set of tenant budgets; tenants without an entry fall
+// back to Default.
+type BudgetPolicy struct {
+	Default  TenantBudget            `json:"default,omitempty"`
+	ByTenant map[string]TenantBudget `json:"by_tenant,omitempty"`
+}
+
+func (p BudgetPolicy) Validate() error {
+	if err := p.Default.Validate(); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+	for tenantID, budget := range p.ByTenant {
+		if strings.TrimSpace(tenantID) == "" {
+			return fmt.Errorf("budget by_tenant key is required")
+		}
+		if err := budget.Validate(); err != nil {
+			return fmt.Errorf("by_tenant[%s]: %w", tenantID, err)
+		}
+	}
+	return nil
+}
+
+// For returns the budget that applies to tenantID.
+func (p BudgetPolicy) For(tenantID string) TenantBudget {
+	if budget, ok := p.ByTenant[tenantID]; ok {
+		return budget
+	}
+	return p.Default
+}
+
 type RecordingPolicy struct {
 	RecordingLevel     string   `json:"recording_level"`
 	AllowedReplayModes []string `json:"allowed_replay_modes"`
@@ -533,6 +1063,11 @@ type ResolvedTurnPlan struct {
 	SnapshotProvenance     SnapshotProvenance          `json:"snapshot_provenance"`
 	RecordingPolicy        RecordingPolicy             `json:"recording_policy"`
 	Determinism            Determinism                 `json:"determinism"`
+	STTEndpointing         *STTEndpointingProvenance   `json:"stt_endpointing,omitempty"`
+	AudioEnhancement       *AudioEnhancement           `json:"audio_enhancement,omitempty"`
+	Locale                 *ResolvedLocale             `json:"locale,omitempty"`
+	Summarization          *Summarization              `json:"summarization,omitempty"`
+	OutputLimits           *OutputLimits               `json:"output_limits,omitempty"`
 }
 
 func (p ResolvedTurnPlan) Validate() error {
@@ -592,6 +1127,31 @@ func (p ResolvedTurnPlan) Validate() error {
 	if err := p.Determinism.Validate(); err != nil {
 		return err
 	}
+	if p.STTEndpointing != nil {
+		if err := p.STTEndpointing.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.AudioEnhancement != nil {
+		if err := p.AudioEnhancement.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.Locale != nil {
+		if err := p.Locale.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.Summarization != nil {
+		if err := p.Summarization.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.OutputLimits != nil {
+		if err := p.OutputLimits.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -606,7 +1166,7 @@ func inStringSet(v string, set []string) bool {
 
 func isOutcomeKind(v OutcomeKind) bool {
 	switch v {
-	case OutcomeAdmit, OutcomeReject, OutcomeDefer, OutcomeShed, OutcomeStaleEpochReject, OutcomeDeauthorized:
+	case OutcomeAdmit, OutcomeReject, OutcomeDefer, OutcomeShed, OutcomeStaleEpochReject, OutcomeDeauthorized, OutcomeBudgetExceeded:
 		return true
 	default:
 		return false
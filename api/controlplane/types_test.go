@@ -24,6 +24,43 @@ func TestCT005DecisionOutcomeMappingRules(t *testing.T) {
 		mutate    func(*DecisionOutcome)
 		shouldErr bool
 	}{
+		{
+			name: "failure_domain tag accepted",
+			mutate: func(out *DecisionOutcome) {
+				out.FailureDomain = "admission"
+			},
+		},
+		{
+			name: "explain payload accepted",
+			mutate: func(out *DecisionOutcome) {
+				out.Explain = &DecisionExplain{
+					PolicyRule:    "rk25.pre_turn.capacity_reject",
+					QuotaCounters: []QuotaCounter{{Name: "tenant_concurrent_turns", Used: 4, Limit: 4}},
+					Thresholds:    []ThresholdEvaluation{{Name: "pool_utilization", Observed: 0.95, Threshold: 0.9, Breached: true}},
+				}
+			},
+		},
+		{
+			name: "explain without policy rule rejected",
+			mutate: func(out *DecisionOutcome) {
+				out.Explain = &DecisionExplain{}
+			},
+			shouldErr: true,
+		},
+		{
+			name: "explain negative quota counter rejected",
+			mutate: func(out *DecisionOutcome) {
+				out.Explain = &DecisionExplain{PolicyRule: "rk25.pre_turn.capacity_reject", QuotaCounters: []QuotaCounter{{Name: "turns", Used: -1}}}
+			},
+			shouldErr: true,
+		},
+		{
+			name: "unknown failure_domain rejected",
+			mutate: func(out *DecisionOutcome) {
+				out.FailureDomain = "network"
+			},
+			shouldErr: true,
+		},
 		{
 			name: "authority outcome valid RK-24",
 			mutate: func(out *DecisionOutcome) {
@@ -71,6 +108,27 @@ func TestCT005DecisionOutcomeMappingRules(t *testing.T) {
 			},
 			shouldErr: true,
 		},
+		{
+			name: "budget exceeded valid at scheduling point",
+			mutate: func(out *DecisionOutcome) {
+				out.OutcomeKind = OutcomeBudgetExceeded
+				out.Phase = PhaseScheduling
+				out.Scope = ScopeNodeDispatch
+				out.EmittedBy = EmitterRK25
+				out.Reason = "budget_max_tokens_per_turn_exceeded"
+			},
+		},
+		{
+			name: "budget exceeded requires RK-25",
+			mutate: func(out *DecisionOutcome) {
+				out.OutcomeKind = OutcomeBudgetExceeded
+				out.Phase = PhaseScheduling
+				out.Scope = ScopeNodeDispatch
+				out.EmittedBy = EmitterCP05
+				out.Reason = "budget_max_tokens_per_turn_exceeded"
+			},
+			shouldErr: true,
+		},
 		{
 			name: "scope turn requires turn_id",
 			mutate: func(out *DecisionOutcome) {
@@ -101,3 +159,226 @@ func TestCT005DecisionOutcomeMappingRules(t *testing.T) {
 		})
 	}
 }
+
+func TestDegradeLadderValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		ladder    DegradeLadder
+		shouldErr bool
+	}{
+		{
+			name: "ordered ladder accepted",
+			ladder: DegradeLadder{Rungs: []DegradeRung{
+				{Step: DegradeReduceTelemetryDetail, SoftLimit: 4},
+				{Step: DegradeShortenLLMMaxTokens, SoftLimit: 8, LLMMaxTokens: 64},
+			}, HardLimit: 12},
+		},
+		{name: "empty rungs rejected", ladder: DegradeLadder{HardLimit: 4}, shouldErr: true},
+		{
+			name: "out of order steps rejected",
+			ladder: DegradeLadder{Rungs: []DegradeRung{
+				{Step: DegradeDropSTTPartials, SoftLimit: 4},
+				{Step: DegradeReduceTelemetryDetail, SoftLimit: 8},
+			}, HardLimit: 12},
+			shouldErr: true,
+		},
+		{
+			name: "non-increasing soft limit rejected",
+			ladder: DegradeLadder{Rungs: []DegradeRung{
+				{Step: DegradeReduceTelemetryDetail, SoftLimit: 4},
+				{Step: DegradeDropSTTPartials, SoftLimit: 4},
+			}, HardLimit: 12},
+			shouldErr: true,
+		},
+		{
+			name:      "missing llm max tokens rejected",
+			ladder:    DegradeLadder{Rungs: []DegradeRung{{Step: DegradeShortenLLMMaxTokens, SoftLimit: 4}}, HardLimit: 8},
+			shouldErr: true,
+		},
+		{
+			name:      "hard limit at soft limit rejected",
+			ladder:    DegradeLadder{Rungs: []DegradeRung{{Step: DegradeReduceTelemetryDetail, SoftLimit: 4}}, HardLimit: 4},
+			shouldErr: true,
+		},
+	}
+	for _, tc := range tests {
+		err := tc.ladder.Validate()
+		if tc.shouldErr && err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestAudioEnhancementValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		enhancement AudioEnhancement
+		shouldErr   bool
+	}{
+		{name: "disabled accepted", enhancement: AudioEnhancement{}},
+		{name: "rnnoise accepted", enhancement: AudioEnhancement{Enabled: true, Engine: "rnnoise", Quality: "balanced"}},
+		{name: "remote provider engine rejected", enhancement: AudioEnhancement{Enabled: true, Engine: "provider", Quality: "high"}, shouldErr: true},
+		{name: "unknown engine rejected", enhancement: AudioEnhancement{Enabled: true, Engine: "speex", Quality: "low"}, shouldErr: true},
+		{name: "missing quality rejected", enhancement: AudioEnhancement{Enabled: true, Engine: "rnnoise"}, shouldErr: true},
+		{name: "settings without enable rejected", enhancement: AudioEnhancement{Engine: "rnnoise", Quality: "low"}, shouldErr: true},
+	}
+	for _, tc := range tests {
+		err := tc.enhancement.Validate()
+		if tc.shouldErr && err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestOutputLimitsValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		limits    OutputLimits
+		shouldErr bool
+	}{
+		{name: "llm cap accepted", limits: OutputLimits{MaxLLMOutputTokens: 256}},
+		{name: "tts cap accepted", limits: OutputLimits{MaxTTSAudioMS: 20_000}},
+		{name: "empty rejected", limits: OutputLimits{}, shouldErr: true},
+		{name: "negative tokens rejected", limits: OutputLimits{MaxLLMOutputTokens: -1, MaxTTSAudioMS: 1}, shouldErr: true},
+		{name: "negative audio rejected", limits: OutputLimits{MaxLLMOutputTokens: 1, MaxTTSAudioMS: -1}, shouldErr: true},
+	}
+	for _, tc := range tests {
+		err := tc.limits.Validate()
+		if tc.shouldErr && err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestSessionTriggerValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		trigger   SessionTrigger
+		shouldErr bool
+	}{
+		{name: "continuous accepted", trigger: SessionTrigger{Mode: TriggerContinuous}},
+		{name: "push to talk with max capture accepted", trigger: SessionTrigger{Mode: TriggerPushToTalk, MaxCaptureMS: 30000}},
+		{name: "wake word accepted", trigger: SessionTrigger{Mode: TriggerWakeWord, WakeWords: []string{"hey rspp"}, WakeWordMinConfidence: 0.6}},
+		{name: "unknown mode rejected", trigger: SessionTrigger{Mode: "clap"}, shouldErr: true},
+		{name: "wake words outside wake word mode rejected", trigger: SessionTrigger{Mode: TriggerContinuous, WakeWords: []string{"hey rspp"}}, shouldErr: true},
+		{name: "max capture outside push to talk rejected", trigger: SessionTrigger{Mode: TriggerWakeWord, MaxCaptureMS: 1000}, shouldErr: true},
+		{name: "blank wake word rejected", trigger: SessionTrigger{Mode: TriggerWakeWord, WakeWords: []string{" "}}, shouldErr: true},
+		{name: "confidence out of range rejected", trigger: SessionTrigger{Mode: TriggerWakeWord, WakeWordMinConfidence: 1.5}, shouldErr: true},
+	}
+	for _, tc := range tests {
+		err := tc.trigger.Validate()
+		if tc.shouldErr && err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestTurnTriggerValidate(t *testing.T) {
+	t.Parallel()
+
+	end := int64(900)
+	early := int64(100)
+	tests := []struct {
+		name      string
+		trigger   TurnTrigger
+		shouldErr bool
+	}{
+		{name: "open push to talk capture accepted", trigger: TurnTrigger{Mode: TriggerPushToTalk, TriggeredAtMS: 200}},
+		{name: "ended capture accepted", trigger: TurnTrigger{Mode: TriggerPushToTalk, TriggeredAtMS: 200, CaptureEndAtMS: &end, CaptureEndReason: "capture_end"}},
+		{name: "wake word accepted", trigger: TurnTrigger{Mode: TriggerWakeWord, TriggeredAtMS: 200, WakeWord: "hey rspp", WakeWordConfidence: 0.9}},
+		{name: "wake word missing phrase rejected", trigger: TurnTrigger{Mode: TriggerWakeWord, TriggeredAtMS: 200}, shouldErr: true},
+		{name: "phrase outside wake word mode rejected", trigger: TurnTrigger{Mode: TriggerContinuous, TriggeredAtMS: 200, WakeWord: "hey rspp"}, shouldErr: true},
+		{name: "capture end before trigger rejected", trigger: TurnTrigger{Mode: TriggerPushToTalk, TriggeredAtMS: 200, CaptureEndAtMS: &early, CaptureEndReason: "capture_end"}, shouldErr: true},
+		{name: "capture end without reason rejected", trigger: TurnTrigger{Mode: TriggerContinuous, TriggeredAtMS: 200, CaptureEndAtMS: &end}, shouldErr: true},
+		{name: "reason without capture end rejected", trigger: TurnTrigger{Mode: TriggerContinuous, TriggeredAtMS: 200, CaptureEndReason: "endpoint"}, shouldErr: true},
+	}
+	for _, tc := range tests {
+		err := tc.trigger.Validate()
+		if tc.shouldErr && err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestTurnSLAValidate(t *testing.T) {
+	t.Parallel()
+
+	gold := SLATier{Tier: "gold", MaxFirstOutputLatencyMS: 800, MinAvailability: 0.999}
+	tests := []struct {
+		name      string
+		sla       TurnSLA
+		shouldErr bool
+	}{
+		{name: "tenant tier accepted", sla: TurnSLA{TenantID: "tenant-a", SLATier: gold}},
+		{name: "full availability accepted", sla: TurnSLA{TenantID: "tenant-a", SLATier: SLATier{Tier: "gold", MaxFirstOutputLatencyMS: 800, MinAvailability: 1}}},
+		{name: "missing tenant rejected", sla: TurnSLA{SLATier: gold}, shouldErr: true},
+		{name: "missing tier rejected", sla: TurnSLA{TenantID: "tenant-a", SLATier: SLATier{MaxFirstOutputLatencyMS: 800, MinAvailability: 0.99}}, shouldErr: true},
+		{name: "zero latency bound rejected", sla: TurnSLA{TenantID: "tenant-a", SLATier: SLATier{Tier: "gold", MinAvailability: 0.99}}, shouldErr: true},
+		{name: "zero availability rejected", sla: TurnSLA{TenantID: "tenant-a", SLATier: SLATier{Tier: "gold", MaxFirstOutputLatencyMS: 800}}, shouldErr: true},
+		{name: "availability above one rejected", sla: TurnSLA{TenantID: "tenant-a", SLATier: SLATier{Tier: "gold", MaxFirstOutputLatencyMS: 800, MinAvailability: 1.5}}, shouldErr: true},
+	}
+	for _, tc := range tests {
+		err := tc.sla.Validate()
+		if tc.shouldErr && err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestBudgetPolicyResolvesTenantBudgets(t *testing.T) {
+	t.Parallel()
+
+	policy := BudgetPolicy{
+		Default:  TenantBudget{MaxTokensPerTurn: 4000},
+		ByTenant: map[string]TenantBudget{"tenant-a": {MaxTokensPerTurn: 1000, MaxSessionSpendUSD: 0.5}},
+	}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("unexpected budget policy validation error: %v", err)
+	}
+	if got := policy.For("tenant-a"); got.MaxTokensPerTurn != 1000 || got.MaxSessionSpendUSD != 0.5 {
+		t.Fatalf("expected tenant-a override, got %+v", got)
+	}
+	if got := policy.For("tenant-b"); got.MaxTokensPerTurn != 4000 || got.MaxSessionSpendUSD != 0 {
+		t.Fatalf("expected default budget for unlisted tenant, got %+v", got)
+	}
+
+	tests := []struct {
+		name   string
+		policy BudgetPolicy
+	}{
+		{name: "negative default tokens", policy: BudgetPolicy{Default: TenantBudget{MaxTokensPerTurn: -1}}},
+		{name: "negative tenant spend", policy: BudgetPolicy{ByTenant: map[string]TenantBudget{"tenant-a": {MaxSessionSpendUSD: -0.1}}}},
+		{name: "blank tenant key", policy: BudgetPolicy{ByTenant: map[string]TenantBudget{" ": {MaxTokensPerTurn: 10}}}},
+	}
+	for _, tc := range tests {
+		if err := tc.policy.Validate(); err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package controlplaneclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/backoff"
+)
+
+const (
+	// EnvURL configures the control-plane API base URL.
+	EnvURL = "RSPP_CP_API_URL"
+	// EnvAuthBearerToken configures the bearer token sent to the control-plane API.
+	EnvAuthBearerToken = "RSPP_CP_API_AUTH_BEARER_TOKEN"
+	// EnvTimeoutMS configures the per-attempt request timeout in milliseconds.
+	EnvTimeoutMS = "RSPP_CP_API_TIMEOUT_MS"
+	// EnvRetryMaxAttempts configures the max attempts per call.
+	EnvRetryMaxAttempts = "RSPP_CP_API_RETRY_MAX_ATTEMPTS"
+
+	defaultTimeout          = 2 * time.Second
+	defaultRetryMaxAttempts = 3
+	defaultRetryBackoff     = 100 * time.Millisecond
+	defaultRetryMaxBackoff  = time.Second
+	maxResponseBytes        = 1 << 20
+)
+
+// Config configures a control-plane API client.
+type Config struct {
+	BaseURL         string
+	AuthBearerToken string
+	// Timeout bounds each attempt; the caller context bounds the whole call.
+	Timeout          time.Duration
+	HTTPClient       *http.Client
+	RetryMaxAttempts int
+	RetryPolicy      backoff.Policy
+
+	// Sleep replaces the context-aware retry wait, for tests.
+	Sleep func(time.Duration)
+}
+
+// ConfigFromEnv resolves client config from environment.
+func ConfigFromEnv() (Config, error) {
+	baseURL := strings.TrimSpace(os.Getenv(EnvURL))
+	if baseURL == "" {
+		return Config{}, fmt.Errorf("%s is required", EnvURL)
+	}
+	cfg := Config{
+		BaseURL:         baseURL,
+		AuthBearerToken: strings.TrimSpace(os.Getenv(EnvAuthBearerToken)),
+	}
+	if raw := strings.TrimSpace(os.Getenv(EnvTimeoutMS)); raw != "" {
+		ms, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || ms <= 0 {
+			return Config{}, fmt.Errorf("%s must be a positive integer", EnvTimeoutMS)
+		}
+		cfg.Timeout = time.Duration(ms) * time.Millisecond
+	}
+	if raw := strings.TrimSpace(os.Getenv(EnvRetryMaxAttempts)); raw != "" {
+		attempts, err := strconv.Atoi(raw)
+		if err != nil || attempts <= 0 {
+			return Config{}, fmt.Errorf("%s must be a positive integer", EnvRetryMaxAttempts)
+		}
+		cfg.RetryMaxAttempts = attempts
+	}
+	return cfg, nil
+}
+
+// Client calls the control-plane API with bounded retries and typed error mapping.
+type Client struct {
+	baseURL     string
+	token       string
+	timeout     time.Duration
+	httpClient  *http.Client
+	maxAttempts int
+	retry       backoff.Policy
+	sleep       func(time.Duration)
+}
+
+// New validates cfg and returns a client.
+func New(cfg Config) (*Client, error) {
+	baseURL := strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if baseURL == "" {
+		return nil, fmt.Errorf("base url is required")
+	}
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		return nil, fmt.Errorf("base url must be http or https: %q", baseURL)
+	}
+	retry := cfg.RetryPolicy.Normalize(defaultRetryBackoff)
+	if cfg.RetryPolicy.Max == 0 && retry.Max < defaultRetryMaxBackoff {
+		retry.Max = defaultRetryMaxBackoff
+	}
+	if err := retry.Validate(); err != nil {
+		return nil, err
+	}
+	client := &Client{
+		baseURL:     baseURL,
+		token:       cfg.AuthBearerToken,
+		timeout:     cfg.Timeout,
+		httpClient:  cfg.HTTPClient,
+		maxAttempts: cfg.RetryMaxAttempts,
+		retry:       retry,
+		sleep:       cfg.Sleep,
+	}
+	if client.timeout <= 0 {
+		client.timeout = defaultTimeout
+	}
+	if client.httpClient == nil {
+		client.httpClient = &http.Client{}
+	}
+	if client.maxAttempts <= 0 {
+		client.maxAttempts = defaultRetryMaxAttempts
+	}
+	return client, nil
+}
+
+// NewFromEnv returns a client configured from environment.
+func NewFromEnv() (*Client, error) {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return New(cfg)
+}
+
+// PublishPipeline publishes a pipeline version and optionally activates it.
+func (c *Client) PublishPipeline(ctx context.Context, req PublishPipelineRequest) (PublishPipelineResponse, error) {
+	if err := req.Validate(); err != nil {
+		return PublishPipelineResponse{}, invalidRequest(PathPublishPipeline, err)
+	}
+	var out PublishPipelineResponse
+	return out, c.call(ctx, PathPublishPipeline, req, &out)
+}
+
+// RollbackPipeline reactivates a previously published pipeline version.
+func (c *Client) RollbackPipeline(ctx context.Context, req RollbackPipelineRequest) (RollbackPipelineResponse, error) {
+	var out RollbackPipelineResponse
+	return out, c.call(ctx, PathRollbackPipeline, req, &out)
+}
+
+// ResolveSessionRoute resolves the pipeline version and routing snapshot for a session.
+func (c *Client) ResolveSessionRoute(ctx context.Context, req ResolveSessionRouteRequest) (SessionRoute, error) {
+	if err := req.Validate(); err != nil {
+		return SessionRoute{}, invalidRequest(PathResolveSessionRoute, err)
+	}
+	var out SessionRoute
+	return out, c.call(ctx, PathResolveSessionRoute, req, &out)
+}
+
+// IssueSessionToken issues a short-lived runtime token for a session.
+func (c *Client) IssueSessionToken(ctx context.Context, req IssueSessionTokenRequest) (SessionToken, error) {
+	if err := req.Validate(); err != nil {
+		return SessionToken{}, invalidRequest(PathIssueSessionToken, err)
+	}
+	var out SessionToken
+	return out, c.call(ctx, PathIssueSessionToken, req, &out)
+}
+
+// SetSessionStatus records a session lifecycle status.
+func (c *Client) SetSessionStatus(ctx context.Context, req SetSessionStatusRequest) (SessionStatus, error) {
+	if err := req.Validate(); err != nil {
+		return SessionStatus{}, invalidRequest(PathSetSessionStatus, err)
+	}
+	var out SessionStatus
+	return out, c.call(ctx, PathSetSessionStatus, req, &out)
+}
+
+// RegisterRolloutPools registers an environment's blue and green runtime pools.
+func (c *Client) RegisterRolloutPools(ctx context.Context, req RegisterRolloutPoolsRequest) (RolloutStatus, error) {
+	if err := req.Validate(); err != nil {
+		return RolloutStatus{}, invalidRequest(PathRegisterRolloutPools, err)
+	}
+	var out RolloutStatus
+	return out, c.call(ctx, PathRegisterRolloutPools, req, &out)
+}
+
+// ShiftRollout shifts new-session traffic toward one pool of an environment.
+func (c *Client) ShiftRollout(ctx context.Context, req ShiftRolloutRequest) (RolloutStatus, error) {
+	if err := req.Validate(); err != nil {
+		return RolloutStatus{}, invalidRequest(PathShiftRollout, err)
+	}
+	var out RolloutStatus
+	return out, c.call(ctx, PathShiftRollout, req, &out)
+}
+
+// EvaluateRollout gates an environment's cutover on live SLOs, promoting or aborting it.
+func (c *Client) EvaluateRollout(ctx context.Context, req EvaluateRolloutRequest) (RolloutStatus, error) {
+	var out RolloutStatus
+	return out, c.call(ctx, PathEvaluateRollout, req, &out)
+}
+
+// AbortRollout rolls an environment's cutover back to the non-target pool.
+func (c *Client) AbortRollout(ctx context.Context, req AbortRolloutRequest) (RolloutStatus, error) {
+	var out RolloutStatus
+	return out, c.call(ctx, PathAbortRollout, req, &out)
+}
+
+// ReportPoolSessions reports a pool's remaining sessions while it drains.
+func (c *Client) ReportPoolSessions(ctx context.Context, req ReportPoolSessionsRequest) (RolloutStatus, error) {
+	var out RolloutStatus
+	return out, c.call(ctx, PathReportPoolSessions, req, &out)
+}
+
+// RouteRolloutSession returns the pool of an environment's blue/green deployment a new session
+// routes to by weight.
+func (c *Client) RouteRolloutSession(ctx context.Context, req RouteRolloutSessionRequest) (RolloutPool, error) {
+	if err := req.Validate(); err != nil {
+		return RolloutPool{}, invalidRequest(PathRouteRolloutSession, err)
+	}
+	var out RolloutPool
+	return out, c.call(ctx, PathRouteRolloutSession, req, &out)
+}
+
+// PublishShardMap replaces the runtime shard map, advancing the authority epoch.
+func (c *Client) PublishShardMap(ctx context.Context, req PublishShardMapRequest) (ShardMap, error) {
+	if err := req.Validate(); err != nil {
+		return ShardMap{}, invalidRequest(PathPublishShardMap, err)
+	}
+	var out ShardMap
+	return out, c.call(ctx, PathPublishShardMap, req, &out)
+}
+
+func (c *Client) call(ctx context.Context, path string, in any, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("encode %s request: %w", path, err)
+	}
+	return c.withRetries(ctx, path, func() (time.Duration, error) {
+		return c.attempt(ctx, path, body, out)
+	})
+}
+
+// withRetries runs attempt until it succeeds, fails with a non-retryable error, or the attempt
+// budget, backoff sequence, or caller context runs out.
+func (c *Client) withRetries(ctx context.Context, path string, attempt func() (time.Duration, error)) error {
+	delays := c.retry.NewSequence(path)
+	var lastErr error
+	for n := 1; n <= c.maxAttempts; n++ {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(err, lastErr)
+		}
+		var retryAfter time.Duration
+		retryAfter, lastErr = attempt()
+		if lastErr == nil {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return errors.Join(err, lastErr)
+		}
+		var apiErr *APIError
+		if !errors.As(lastErr, &apiErr) || !apiErr.Retryable || n == c.maxAttempts {
+			return lastErr
+		}
+		delay, ok := delays.Next()
+		if !ok {
+			return lastErr
+		}
+		if err := c.wait(ctx, max(delay, retryAfter)); err != nil {
+			return errors.Join(err, lastErr)
+		}
+	}
+	return lastErr
+}
+
+func (c *Client) wait(ctx context.Context, delay time.Duration) error {
+	if c.sleep != nil {
+		c.sleep(delay)
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, path string, body []byte, out any) (time.Duration, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(attemptCtx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("build %s request: %w", path, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Transport failures are retried unless the caller gave up.
+		return 0, &APIError{Path: path, Code: CodeUnavailable, Message: err.Error(), Retryable: ctx.Err() == nil, cause: ErrUnavailable}
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return 0, &APIError{Path: path, Status: resp.StatusCode, Code: CodeUnavailable, Message: err.Error(), Retryable: true, cause: ErrUnavailable}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return retryAfter(resp.Header.Get("Retry-After")), mapHTTPError(path, resp.StatusCode, raw)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return 0, fmt.Errorf("decode %s response: %w", path, err)
+	}
+	return 0, nil
+}
+
+func retryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(header))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
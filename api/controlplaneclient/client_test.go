@@ -0,0 +1,218 @@
+package controlplaneclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *[]time.Duration) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	var sleeps []time.Duration
+	client, err := New(Config{
+		BaseURL:         server.URL + "/",
+		AuthBearerToken: "test-token",
+		Sleep:           func(d time.Duration) { sleeps = append(sleeps, d) },
+	})
+	if err != nil {
+		t.Fatalf("unexpected client error: %v", err)
+	}
+	return client, &sleeps
+}
+
+func TestClientTypedMethodsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer test-token" || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "bad request shape", http.StatusBadRequest)
+			return
+		}
+		var in map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		var out any
+		switch r.URL.Path {
+		case PathPublishPipeline:
+			out = PublishPipelineResponse{PipelineVersion: in["pipeline_version"].(string), SpecHash: "sha256:abc", Active: true, PublishedAtMS: 10}
+		case PathResolveSessionRoute:
+			out = SessionRoute{TenantID: in["tenant_id"].(string), SessionID: in["session_id"].(string), PipelineVersion: "pipeline-v2", RoutingViewSnapshot: "routing-view/v2"}
+		case PathIssueSessionToken:
+			out = SessionToken{SessionID: in["session_id"].(string), Token: "tok-1", ExpiresAtMS: 60000}
+		case PathSetSessionStatus:
+			out = SessionStatus{SessionID: in["session_id"].(string), Status: SessionStatusValue(in["status"].(string)), UpdatedAtMS: 20}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	})
+	ctx := context.Background()
+
+	published, err := client.PublishPipeline(ctx, PublishPipelineRequest{PipelineVersion: "pipeline-v2", GraphDefinitionRef: "graph/v2", Spec: json.RawMessage(`{"nodes":[]}`), Activate: true})
+	if err != nil || published.PipelineVersion != "pipeline-v2" || !published.Active {
+		t.Fatalf("unexpected publish result: %+v (%v)", published, err)
+	}
+	route, err := client.ResolveSessionRoute(ctx, ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: "sess-1"})
+	if err != nil || route.PipelineVersion != "pipeline-v2" || route.SessionID != "sess-1" {
+		t.Fatalf("unexpected route result: %+v (%v)", route, err)
+	}
+	token, err := client.IssueSessionToken(ctx, IssueSessionTokenRequest{TenantID: "tenant-a", SessionID: "sess-1"})
+	if err != nil || token.Token != "tok-1" {
+		t.Fatalf("unexpected token result: %+v (%v)", token, err)
+	}
+	status, err := client.SetSessionStatus(ctx, SetSessionStatusRequest{SessionID: "sess-1", Status: SessionStatusEnded})
+	if err != nil || status.Status != SessionStatusEnded {
+		t.Fatalf("unexpected status result: %+v (%v)", status, err)
+	}
+}
+
+func TestClientRetriesTransientFailures(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	client, sleeps := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_ = json.NewEncoder(w).Encode(SessionToken{SessionID: "sess-1", Token: "tok-1"})
+		}
+	})
+
+	token, err := client.IssueSessionToken(context.Background(), IssueSessionTokenRequest{TenantID: "tenant-a", SessionID: "sess-1"})
+	if err != nil || token.Token != "tok-1" {
+		t.Fatalf("expected retry to succeed, got %+v (%v)", token, err)
+	}
+	if calls.Load() != 3 || len(*sleeps) != 2 || (*sleeps)[0] != defaultRetryBackoff || (*sleeps)[1] != 2*time.Second {
+		t.Fatalf("expected two paced retries honoring Retry-After, got calls=%d sleeps=%v", calls.Load(), *sleeps)
+	}
+}
+
+func TestClientErrorMapping(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name          string
+		status        int
+		body          string
+		wantSentinel  error
+		wantCode      string
+		wantCalls     int32
+		wantRetryable bool
+	}{
+		{name: "not found with code", status: http.StatusNotFound, body: `{"code":"pipeline_missing","message":"no pipeline-v9"}`, wantSentinel: ErrNotFound, wantCode: "pipeline_missing", wantCalls: 1},
+		{name: "conflict problem json", status: http.StatusConflict, body: `{"title":"Conflict","detail":"version already published"}`, wantSentinel: ErrConflict, wantCode: CodeConflict, wantCalls: 1},
+		{name: "unauthorized", status: http.StatusUnauthorized, body: ``, wantSentinel: ErrUnauthorized, wantCode: CodeUnauthorized, wantCalls: 1},
+		{name: "invalid", status: http.StatusBadRequest, body: `not json`, wantSentinel: ErrInvalidRequest, wantCode: CodeInvalidRequest, wantCalls: 1},
+		{name: "unavailable exhausts retries", status: http.StatusBadGateway, body: ``, wantSentinel: ErrUnavailable, wantCode: CodeUnavailable, wantCalls: defaultRetryMaxAttempts, wantRetryable: true},
+	}
+	for _, tc := range cases {
+		var calls atomic.Int32
+		client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(tc.status)
+			_, _ = w.Write([]byte(tc.body))
+		})
+		_, err := client.ResolveSessionRoute(context.Background(), ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: "sess-1"})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !errors.Is(err, tc.wantSentinel) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.wantSentinel, err)
+		}
+		if apiErr.Code != tc.wantCode || apiErr.Status != tc.status || apiErr.Retryable != tc.wantRetryable {
+			t.Fatalf("%s: unexpected api error: %+v", tc.name, apiErr)
+		}
+		if calls.Load() != tc.wantCalls {
+			t.Fatalf("%s: expected %d calls, got %d", tc.name, tc.wantCalls, calls.Load())
+		}
+	}
+}
+
+func TestClientRejectsInvalidRequestsWithoutCalling(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) { calls.Add(1) })
+	ctx := context.Background()
+
+	errs := []error{
+		func() error {
+			_, err := client.PublishPipeline(ctx, PublishPipelineRequest{PipelineVersion: "pipeline-v2"})
+			return err
+		}(),
+		func() error {
+			_, err := client.PublishPipeline(ctx, PublishPipelineRequest{PipelineVersion: "pipeline-v2", GraphDefinitionRef: "graph/v2", Spec: json.RawMessage(`{`)})
+			return err
+		}(),
+		func() error {
+			_, err := client.ResolveSessionRoute(ctx, ResolveSessionRouteRequest{SessionID: "sess-1"})
+			return err
+		}(),
+		func() error {
+			_, err := client.IssueSessionToken(ctx, IssueSessionTokenRequest{TenantID: "t", SessionID: "s", TTLMS: -1})
+			return err
+		}(),
+		func() error {
+			_, err := client.SetSessionStatus(ctx, SetSessionStatusRequest{SessionID: "sess-1", Status: "paused"})
+			return err
+		}(),
+	}
+	for i, err := range errs {
+		if !errors.Is(err, ErrInvalidRequest) {
+			t.Fatalf("case %d: expected invalid request error, got %v", i, err)
+		}
+	}
+	if calls.Load() != 0 {
+		t.Fatalf("expected no calls for invalid requests, got %d", calls.Load())
+	}
+}
+
+func TestClientStopsRetryingWhenContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	_, err := client.SetSessionStatus(ctx, SetSessionStatusRequest{SessionID: "sess-1", Status: SessionStatusActive})
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("expected cancellation joined with last error, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected one call before cancellation, got %d", calls.Load())
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvURL, "http://cp.internal:8080")
+	t.Setenv(EnvTimeoutMS, "500")
+	t.Setenv(EnvRetryMaxAttempts, "5")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected env config error: %v", err)
+	}
+	if cfg.BaseURL != "http://cp.internal:8080" || cfg.Timeout != 500*time.Millisecond || cfg.RetryMaxAttempts != 5 {
+		t.Fatalf("unexpected env config: %+v", cfg)
+	}
+
+	t.Setenv(EnvRetryMaxAttempts, "zero")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Fatalf("expected invalid retry attempts to fail")
+	}
+	if _, err := New(Config{BaseURL: "cp.internal"}); err == nil {
+		t.Fatalf("expected non-http base url to fail")
+	}
+}
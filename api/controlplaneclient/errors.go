@@ -0,0 +1,102 @@
+package controlplaneclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Machine-readable error codes returned by the control-plane API.
+const (
+	CodeInvalidRequest = "invalid_request"
+	CodeUnauthorized   = "unauthorized"
+	CodeForbidden      = "forbidden"
+	CodeNotFound       = "not_found"
+	CodeConflict       = "conflict"
+	CodeRateLimited    = "rate_limited"
+	CodeUnavailable    = "unavailable"
+	CodeInternal       = "internal"
+)
+
+// Sentinel errors for branching with errors.Is regardless of the server's code string.
+var (
+	ErrInvalidRequest = errors.New("control-plane request invalid")
+	ErrUnauthorized   = errors.New("control-plane request unauthorized")
+	ErrNotFound       = errors.New("control-plane resource not found")
+	ErrConflict       = errors.New("control-plane request conflicts with current state")
+	ErrUnavailable    = errors.New("control-plane unavailable")
+)
+
+// APIError is a failed control-plane API call.
+type APIError struct {
+	Path      string
+	Status    int
+	Code      string
+	Message   string
+	Retryable bool
+
+	cause error
+}
+
+func (e *APIError) Error() string {
+	if e.Status == 0 {
+		return fmt.Sprintf("control-plane %s: %s: %s", e.Path, e.Code, e.Message)
+	}
+	return fmt.Sprintf("control-plane %s: http %d %s: %s", e.Path, e.Status, e.Code, e.Message)
+}
+
+// Unwrap exposes the sentinel matching the error class.
+func (e *APIError) Unwrap() error {
+	return e.cause
+}
+
+// errorBody accepts both {"code","message"} and problem+json {"title","detail","code"} bodies.
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Title   string `json:"title"`
+	Detail  string `json:"detail"`
+}
+
+func invalidRequest(path string, err error) error {
+	return &APIError{Path: path, Code: CodeInvalidRequest, Message: err.Error(), cause: ErrInvalidRequest}
+}
+
+func mapHTTPError(path string, status int, raw []byte) error {
+	apiErr := &APIError{Path: path, Status: status}
+	var body errorBody
+	if json.Unmarshal(raw, &body) == nil {
+		apiErr.Code = strings.TrimSpace(body.Code)
+		apiErr.Message = strings.TrimSpace(body.Message)
+		if apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(strings.Join([]string{body.Title, body.Detail}, " "))
+		}
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(status)
+	}
+
+	defaultCode := CodeInternal
+	switch {
+	case status == http.StatusBadRequest || status == http.StatusUnprocessableEntity:
+		defaultCode, apiErr.cause = CodeInvalidRequest, ErrInvalidRequest
+	case status == http.StatusUnauthorized:
+		defaultCode, apiErr.cause = CodeUnauthorized, ErrUnauthorized
+	case status == http.StatusForbidden:
+		defaultCode, apiErr.cause = CodeForbidden, ErrUnauthorized
+	case status == http.StatusNotFound:
+		defaultCode, apiErr.cause = CodeNotFound, ErrNotFound
+	case status == http.StatusConflict || status == http.StatusPreconditionFailed:
+		defaultCode, apiErr.cause = CodeConflict, ErrConflict
+	case status == http.StatusTooManyRequests:
+		defaultCode, apiErr.cause, apiErr.Retryable = CodeRateLimited, ErrUnavailable, true
+	case status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout:
+		defaultCode, apiErr.cause, apiErr.Retryable = CodeUnavailable, ErrUnavailable, true
+	}
+	if apiErr.Code == "" {
+		apiErr.Code = defaultCode
+	}
+	return apiErr
+}
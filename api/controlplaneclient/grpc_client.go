@@ -0,0 +1,148 @@
+package controlplaneclient
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient/sessionroutepb"
+)
+
+// GRPCClient calls the rspp.controlplane.v1.SessionRouteService gRPC service with the same
+// per-attempt timeouts, retries, and typed errors as Client. The attempt deadline is propagated
+// to the server, so it can stop work the runtime no longer waits for.
+type GRPCClient struct {
+	client *Client
+	conn   *grpc.ClientConn
+	stub   sessionroutepb.SessionRouteServiceClient
+}
+
+// NewGRPC validates cfg and returns a gRPC client. BaseURL names the control plane as for New:
+// http:// dials cleartext HTTP/2 (h2c) and https:// dials TLS. HTTPClient is not used. The
+// connection is established on the first call; Close releases it.
+func NewGRPC(cfg Config) (*GRPCClient, error) {
+	client, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	target, creds, err := grpcTarget(client.baseURL)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("grpc client for %s: %w", client.baseURL, err)
+	}
+	return &GRPCClient{client: client, conn: conn, stub: sessionroutepb.NewSessionRouteServiceClient(conn)}, nil
+}
+
+// NewGRPCFromEnv returns a gRPC client configured from environment.
+func NewGRPCFromEnv() (*GRPCClient, error) {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewGRPC(cfg)
+}
+
+// grpcTarget turns a base URL into a dial target and the transport credentials its scheme asks
+// for. The service is served at the root, so a base URL with a path is rejected.
+func grpcTarget(baseURL string) (string, credentials.TransportCredentials, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return "", nil, fmt.Errorf("base url: %w", err)
+	}
+	if parsed.Path != "" {
+		return "", nil, fmt.Errorf("grpc base url must not have a path: %q", baseURL)
+	}
+	port, creds := "80", insecure.NewCredentials()
+	if parsed.Scheme == "https" {
+		port, creds = "443", credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	if parsed.Port() != "" {
+		port = parsed.Port()
+	}
+	return net.JoinHostPort(parsed.Hostname(), port), creds, nil
+}
+
+// Close releases the client's connection.
+func (g *GRPCClient) Close() error {
+	return g.conn.Close()
+}
+
+// ResolveSessionRoute resolves the pipeline version and routing snapshot for a session.
+func (g *GRPCClient) ResolveSessionRoute(ctx context.Context, req ResolveSessionRouteRequest) (SessionRoute, error) {
+	method := sessionroutepb.SessionRouteService_ResolveSessionRoute_FullMethodName
+	if err := req.Validate(); err != nil {
+		return SessionRoute{}, invalidRequest(method, err)
+	}
+	var out *sessionroutepb.SessionRoute
+	err := g.invoke(ctx, method, func(ctx context.Context) (err error) {
+		out, err = g.stub.ResolveSessionRoute(ctx, req.Proto())
+		return err
+	})
+	if err != nil {
+		return SessionRoute{}, err
+	}
+	return SessionRouteFromProto(out), nil
+}
+
+// IssueSessionToken issues a short-lived runtime token for a session.
+func (g *GRPCClient) IssueSessionToken(ctx context.Context, req IssueSessionTokenRequest) (SessionToken, error) {
+	method := sessionroutepb.SessionRouteService_IssueSessionToken_FullMethodName
+	if err := req.Validate(); err != nil {
+		return SessionToken{}, invalidRequest(method, err)
+	}
+	var out *sessionroutepb.SessionToken
+	err := g.invoke(ctx, method, func(ctx context.Context) (err error) {
+		out, err = g.stub.IssueSessionToken(ctx, req.Proto())
+		return err
+	})
+	if err != nil {
+		return SessionToken{}, err
+	}
+	return SessionTokenFromProto(out), nil
+}
+
+// SessionStatus returns the recorded lifecycle status of a session.
+func (g *GRPCClient) SessionStatus(ctx context.Context, req SessionStatusRequest) (SessionStatus, error) {
+	method := sessionroutepb.SessionRouteService_SessionStatus_FullMethodName
+	if err := req.Validate(); err != nil {
+		return SessionStatus{}, invalidRequest(method, err)
+	}
+	var out *sessionroutepb.SessionStatus
+	err := g.invoke(ctx, method, func(ctx context.Context) (err error) {
+		out, err = g.stub.SessionStatus(ctx, req.Proto())
+		return err
+	})
+	if err != nil {
+		return SessionStatus{}, err
+	}
+	return SessionStatusFromProto(out), nil
+}
+
+// invoke runs call with a per-attempt deadline and the bearer token as call metadata, retrying
+// as Client does.
+func (g *GRPCClient) invoke(ctx context.Context, method string, call func(context.Context) error) error {
+	c := g.client
+	return c.withRetries(ctx, method, func() (time.Duration, error) {
+		attemptCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+		if c.token != "" {
+			attemptCtx = metadata.AppendToOutgoingContext(attemptCtx, "authorization", "Bearer "+c.token)
+		}
+		if err := call(attemptCtx); err != nil {
+			return 0, grpcError(method, status.Convert(err), ctx.Err() == nil)
+		}
+		return 0, nil
+	})
+}
@@ -0,0 +1,103 @@
+package controlplaneclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient/sessionroutepb"
+)
+
+// flakySessionRoutes is a stock generated server that fails its first SessionStatus call.
+type flakySessionRoutes struct {
+	sessionroutepb.UnimplementedSessionRouteServiceServer
+
+	t     *testing.T
+	calls atomic.Int32
+}
+
+func (s *flakySessionRoutes) SessionStatus(ctx context.Context, req *sessionroutepb.SessionStatusRequest) (*sessionroutepb.SessionStatus, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if _, ok := ctx.Deadline(); !ok || len(md.Get("authorization")) != 1 || md.Get("authorization")[0] != "Bearer test-token" {
+		s.t.Errorf("expected a call with deadline and token, got metadata %v", md)
+	}
+	if s.calls.Add(1) == 1 {
+		return nil, status.Error(codes.Unavailable, "draining")
+	}
+	return &sessionroutepb.SessionStatus{SessionId: req.GetSessionId(), Status: string(SessionStatusActive)}, nil
+}
+
+func TestGRPCClientInteropsWithGeneratedServer(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %v", err)
+	}
+	routes := &flakySessionRoutes{t: t}
+	server := grpc.NewServer()
+	sessionroutepb.RegisterSessionRouteServiceServer(server, routes)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	var sleeps []time.Duration
+	client, err := NewGRPC(Config{BaseURL: "http://" + listener.Addr().String(), AuthBearerToken: "test-token", Sleep: func(d time.Duration) { sleeps = append(sleeps, d) }})
+	if err != nil {
+		t.Fatalf("unexpected client error: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	sessionStatus, err := client.SessionStatus(ctx, SessionStatusRequest{SessionID: "sess-1"})
+	if err != nil || sessionStatus.Status != SessionStatusActive || sessionStatus.SessionID != "sess-1" {
+		t.Fatalf("expected retry to succeed, got %+v (%v)", sessionStatus, err)
+	}
+	if routes.calls.Load() != 2 || len(sleeps) != 1 {
+		t.Fatalf("expected one paced retry, got calls=%d sleeps=%v", routes.calls.Load(), sleeps)
+	}
+
+	if _, err := client.SessionStatus(ctx, SessionStatusRequest{SessionID: "sess 1"}); !errors.Is(err, ErrInvalidRequest) || routes.calls.Load() != 2 {
+		t.Fatalf("expected invalid request rejected locally, got %v calls=%d", err, routes.calls.Load())
+	}
+	var apiErr *APIError
+	if _, err := client.ResolveSessionRoute(ctx, ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: "sess-1"}); !errors.As(err, &apiErr) || apiErr.Code != CodeInternal || apiErr.Retryable {
+		t.Fatalf("expected an unimplemented method to fail without retry, got %v", err)
+	}
+}
+
+func TestGRPCTargetFollowsBaseURLScheme(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		baseURL    string
+		wantTarget string
+		wantTLS    bool
+		wantErr    bool
+	}{
+		{name: "h2c with port", baseURL: "http://127.0.0.1:8080", wantTarget: "127.0.0.1:8080"},
+		{name: "h2c default port", baseURL: "http://cp.internal", wantTarget: "cp.internal:80"},
+		{name: "tls default port", baseURL: "https://cp.example.com", wantTarget: "cp.example.com:443", wantTLS: true},
+		{name: "path prefix", baseURL: "https://cp.example.com/api", wantErr: true},
+	}
+	for _, tc := range tests {
+		target, creds, err := grpcTarget(tc.baseURL)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("%s: expected error=%v, got %v", tc.name, tc.wantErr, err)
+		}
+		if err != nil {
+			continue
+		}
+		if target != tc.wantTarget || (creds.Info().SecurityProtocol == "tls") != tc.wantTLS {
+			t.Fatalf("%s: expected target %s tls=%v, got %s %+v", tc.name, tc.wantTarget, tc.wantTLS, target, creds.Info())
+		}
+	}
+}
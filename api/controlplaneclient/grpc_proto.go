@@ -0,0 +1,154 @@
+package controlplaneclient
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient/sessionroutepb"
+)
+
+// GRPCCode maps an API error code onto the gRPC status code the session-route service answers
+// with.
+func GRPCCode(code string) codes.Code {
+	switch code {
+	case "":
+		return codes.OK
+	case CodeInvalidRequest:
+		return codes.InvalidArgument
+	case CodeUnauthorized:
+		return codes.Unauthenticated
+	case CodeForbidden:
+		return codes.PermissionDenied
+	case CodeNotFound:
+		return codes.NotFound
+	case CodeConflict:
+		return codes.FailedPrecondition
+	case CodeRateLimited:
+		return codes.ResourceExhausted
+	case CodeUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+// grpcError maps a failed gRPC call back onto the typed errors REST calls return. Transport
+// failures and attempt timeouts surface as UNAVAILABLE and DEADLINE_EXCEEDED; both are retried
+// unless the caller gave up, as REST transport failures are.
+func grpcError(method string, st *status.Status, callerLive bool) *APIError {
+	apiErr := &APIError{Path: method, Code: CodeInternal, Message: st.Message()}
+	switch st.Code() {
+	case codes.InvalidArgument:
+		apiErr.Code, apiErr.cause = CodeInvalidRequest, ErrInvalidRequest
+	case codes.Unauthenticated:
+		apiErr.Code, apiErr.cause = CodeUnauthorized, ErrUnauthorized
+	case codes.PermissionDenied:
+		apiErr.Code, apiErr.cause = CodeForbidden, ErrUnauthorized
+	case codes.NotFound:
+		apiErr.Code, apiErr.cause = CodeNotFound, ErrNotFound
+	case codes.FailedPrecondition:
+		apiErr.Code, apiErr.cause = CodeConflict, ErrConflict
+	case codes.ResourceExhausted:
+		apiErr.Code, apiErr.cause, apiErr.Retryable = CodeRateLimited, ErrUnavailable, true
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		apiErr.Code, apiErr.cause, apiErr.Retryable = CodeUnavailable, ErrUnavailable, callerLive
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = "grpc status " + st.Code().String()
+	}
+	return apiErr
+}
+
+// Proto converts the request to its sessionroutepb message.
+func (r ResolveSessionRouteRequest) Proto() *sessionroutepb.ResolveSessionRouteRequest {
+	return &sessionroutepb.ResolveSessionRouteRequest{
+		TenantId:                 r.TenantID,
+		SessionId:                r.SessionID,
+		RequestedPipelineVersion: r.RequestedPipelineVersion,
+		Environment:              r.Environment,
+	}
+}
+
+// ResolveSessionRouteRequestFromProto converts a sessionroutepb request; nil yields the zero request.
+func ResolveSessionRouteRequestFromProto(in *sessionroutepb.ResolveSessionRouteRequest) ResolveSessionRouteRequest {
+	return ResolveSessionRouteRequest{
+		TenantID:                 in.GetTenantId(),
+		SessionID:                in.GetSessionId(),
+		RequestedPipelineVersion: in.GetRequestedPipelineVersion(),
+		Environment:              in.GetEnvironment(),
+	}
+}
+
+// Proto converts the route to its sessionroutepb message.
+func (r SessionRoute) Proto() *sessionroutepb.SessionRoute {
+	return &sessionroutepb.SessionRoute{
+		TenantId:            r.TenantID,
+		SessionId:           r.SessionID,
+		PipelineVersion:     r.PipelineVersion,
+		GraphDefinitionRef:  r.GraphDefinitionRef,
+		ExecutionProfile:    r.ExecutionProfile,
+		SpecHash:            r.SpecHash,
+		RoutingViewSnapshot: r.RoutingViewSnapshot,
+		AuthorityEpoch:      r.AuthorityEpoch,
+		RuntimeEndpoint:     r.RuntimeEndpoint,
+		ShardMapSnapshot:    r.ShardMapSnapshot,
+		ShardOwner:          r.ShardOwner,
+	}
+}
+
+// SessionRouteFromProto converts a sessionroutepb route; nil yields the zero route.
+func SessionRouteFromProto(in *sessionroutepb.SessionRoute) SessionRoute {
+	return SessionRoute{
+		TenantID:            in.GetTenantId(),
+		SessionID:           in.GetSessionId(),
+		PipelineVersion:     in.GetPipelineVersion(),
+		GraphDefinitionRef:  in.GetGraphDefinitionRef(),
+		ExecutionProfile:    in.GetExecutionProfile(),
+		SpecHash:            in.GetSpecHash(),
+		RoutingViewSnapshot: in.GetRoutingViewSnapshot(),
+		AuthorityEpoch:      in.GetAuthorityEpoch(),
+		RuntimeEndpoint:     in.GetRuntimeEndpoint(),
+		ShardMapSnapshot:    in.GetShardMapSnapshot(),
+		ShardOwner:          in.GetShardOwner(),
+	}
+}
+
+// Proto converts the request to its sessionroutepb message.
+func (r IssueSessionTokenRequest) Proto() *sessionroutepb.IssueSessionTokenRequest {
+	return &sessionroutepb.IssueSessionTokenRequest{TenantId: r.TenantID, SessionId: r.SessionID, TtlMs: r.TTLMS}
+}
+
+// IssueSessionTokenRequestFromProto converts a sessionroutepb request; nil yields the zero request.
+func IssueSessionTokenRequestFromProto(in *sessionroutepb.IssueSessionTokenRequest) IssueSessionTokenRequest {
+	return IssueSessionTokenRequest{TenantID: in.GetTenantId(), SessionID: in.GetSessionId(), TTLMS: in.GetTtlMs()}
+}
+
+// Proto converts the token to its sessionroutepb message.
+func (t SessionToken) Proto() *sessionroutepb.SessionToken {
+	return &sessionroutepb.SessionToken{SessionId: t.SessionID, Token: t.Token, ExpiresAtMs: t.ExpiresAtMS}
+}
+
+// SessionTokenFromProto converts a sessionroutepb token; nil yields the zero token.
+func SessionTokenFromProto(in *sessionroutepb.SessionToken) SessionToken {
+	return SessionToken{SessionID: in.GetSessionId(), Token: in.GetToken(), ExpiresAtMS: in.GetExpiresAtMs()}
+}
+
+// Proto converts the request to its sessionroutepb message.
+func (r SessionStatusRequest) Proto() *sessionroutepb.SessionStatusRequest {
+	return &sessionroutepb.SessionStatusRequest{SessionId: r.SessionID}
+}
+
+// SessionStatusRequestFromProto converts a sessionroutepb request; nil yields the zero request.
+func SessionStatusRequestFromProto(in *sessionroutepb.SessionStatusRequest) SessionStatusRequest {
+	return SessionStatusRequest{SessionID: in.GetSessionId()}
+}
+
+// Proto converts the status to its sessionroutepb message.
+func (s SessionStatus) Proto() *sessionroutepb.SessionStatus {
+	return &sessionroutepb.SessionStatus{SessionId: s.SessionID, Status: string(s.Status), Reason: s.Reason, UpdatedAtMs: s.UpdatedAtMS}
+}
+
+// SessionStatusFromProto converts a sessionroutepb status; nil yields the zero status.
+func SessionStatusFromProto(in *sessionroutepb.SessionStatus) SessionStatus {
+	return SessionStatus{SessionID: in.GetSessionId(), Status: SessionStatusValue(in.GetStatus()), Reason: in.GetReason(), UpdatedAtMS: in.GetUpdatedAtMs()}
+}
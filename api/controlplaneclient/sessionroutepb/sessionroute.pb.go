@@ -0,0 +1,562 @@
+// Session route resolution for edge runtimes. Field names and meanings match the JSON bodies of
+// the REST API (see api/controlplaneclient/types.go). Regenerate the Go stubs with go generate
+// after changing this file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: sessionroute.proto
+
+package sessionroutepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ResolveSessionRouteRequest struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	TenantId                 string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	SessionId                string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	RequestedPipelineVersion string                 `protobuf:"bytes,3,opt,name=requested_pipeline_version,json=requestedPipelineVersion,proto3" json:"requested_pipeline_version,omitempty"`
+	// Deployment environment whose active pipeline version applies; empty uses the default.
+	Environment   string `protobuf:"bytes,4,opt,name=environment,proto3" json:"environment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveSessionRouteRequest) Reset() {
+	*x = ResolveSessionRouteRequest{}
+	mi := &file_sessionroute_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveSessionRouteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveSessionRouteRequest) ProtoMessage() {}
+
+func (x *ResolveSessionRouteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionroute_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveSessionRouteRequest.ProtoReflect.Descriptor instead.
+func (*ResolveSessionRouteRequest) Descriptor() ([]byte, []int) {
+	return file_sessionroute_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ResolveSessionRouteRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ResolveSessionRouteRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ResolveSessionRouteRequest) GetRequestedPipelineVersion() string {
+	if x != nil {
+		return x.RequestedPipelineVersion
+	}
+	return ""
+}
+
+func (x *ResolveSessionRouteRequest) GetEnvironment() string {
+	if x != nil {
+		return x.Environment
+	}
+	return ""
+}
+
+type SessionRoute struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	TenantId            string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	SessionId           string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	PipelineVersion     string                 `protobuf:"bytes,3,opt,name=pipeline_version,json=pipelineVersion,proto3" json:"pipeline_version,omitempty"`
+	GraphDefinitionRef  string                 `protobuf:"bytes,4,opt,name=graph_definition_ref,json=graphDefinitionRef,proto3" json:"graph_definition_ref,omitempty"`
+	ExecutionProfile    string                 `protobuf:"bytes,5,opt,name=execution_profile,json=executionProfile,proto3" json:"execution_profile,omitempty"`
+	SpecHash            string                 `protobuf:"bytes,6,opt,name=spec_hash,json=specHash,proto3" json:"spec_hash,omitempty"`
+	RoutingViewSnapshot string                 `protobuf:"bytes,7,opt,name=routing_view_snapshot,json=routingViewSnapshot,proto3" json:"routing_view_snapshot,omitempty"`
+	AuthorityEpoch      int64                  `protobuf:"varint,8,opt,name=authority_epoch,json=authorityEpoch,proto3" json:"authority_epoch,omitempty"`
+	// Endpoint of the runtime worker that owns the session when a shard map is published.
+	RuntimeEndpoint  string `protobuf:"bytes,9,opt,name=runtime_endpoint,json=runtimeEndpoint,proto3" json:"runtime_endpoint,omitempty"`
+	ShardMapSnapshot string `protobuf:"bytes,10,opt,name=shard_map_snapshot,json=shardMapSnapshot,proto3" json:"shard_map_snapshot,omitempty"`
+	ShardOwner       string `protobuf:"bytes,11,opt,name=shard_owner,json=shardOwner,proto3" json:"shard_owner,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SessionRoute) Reset() {
+	*x = SessionRoute{}
+	mi := &file_sessionroute_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionRoute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionRoute) ProtoMessage() {}
+
+func (x *SessionRoute) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionroute_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionRoute.ProtoReflect.Descriptor instead.
+func (*SessionRoute) Descriptor() ([]byte, []int) {
+	return file_sessionroute_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SessionRoute) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SessionRoute) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionRoute) GetPipelineVersion() string {
+	if x != nil {
+		return x.PipelineVersion
+	}
+	return ""
+}
+
+func (x *SessionRoute) GetGraphDefinitionRef() string {
+	if x != nil {
+		return x.GraphDefinitionRef
+	}
+	return ""
+}
+
+func (x *SessionRoute) GetExecutionProfile() string {
+	if x != nil {
+		return x.ExecutionProfile
+	}
+	return ""
+}
+
+func (x *SessionRoute) GetSpecHash() string {
+	if x != nil {
+		return x.SpecHash
+	}
+	return ""
+}
+
+func (x *SessionRoute) GetRoutingViewSnapshot() string {
+	if x != nil {
+		return x.RoutingViewSnapshot
+	}
+	return ""
+}
+
+func (x *SessionRoute) GetAuthorityEpoch() int64 {
+	if x != nil {
+		return x.AuthorityEpoch
+	}
+	return 0
+}
+
+func (x *SessionRoute) GetRuntimeEndpoint() string {
+	if x != nil {
+		return x.RuntimeEndpoint
+	}
+	return ""
+}
+
+func (x *SessionRoute) GetShardMapSnapshot() string {
+	if x != nil {
+		return x.ShardMapSnapshot
+	}
+	return ""
+}
+
+func (x *SessionRoute) GetShardOwner() string {
+	if x != nil {
+		return x.ShardOwner
+	}
+	return ""
+}
+
+type IssueSessionTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	TtlMs         int64                  `protobuf:"varint,3,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueSessionTokenRequest) Reset() {
+	*x = IssueSessionTokenRequest{}
+	mi := &file_sessionroute_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueSessionTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueSessionTokenRequest) ProtoMessage() {}
+
+func (x *IssueSessionTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionroute_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueSessionTokenRequest.ProtoReflect.Descriptor instead.
+func (*IssueSessionTokenRequest) Descriptor() ([]byte, []int) {
+	return file_sessionroute_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *IssueSessionTokenRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *IssueSessionTokenRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *IssueSessionTokenRequest) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+type SessionToken struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	ExpiresAtMs   int64                  `protobuf:"varint,3,opt,name=expires_at_ms,json=expiresAtMs,proto3" json:"expires_at_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionToken) Reset() {
+	*x = SessionToken{}
+	mi := &file_sessionroute_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionToken) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionToken) ProtoMessage() {}
+
+func (x *SessionToken) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionroute_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionToken.ProtoReflect.Descriptor instead.
+func (*SessionToken) Descriptor() ([]byte, []int) {
+	return file_sessionroute_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SessionToken) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionToken) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *SessionToken) GetExpiresAtMs() int64 {
+	if x != nil {
+		return x.ExpiresAtMs
+	}
+	return 0
+}
+
+type SessionStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionStatusRequest) Reset() {
+	*x = SessionStatusRequest{}
+	mi := &file_sessionroute_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionStatusRequest) ProtoMessage() {}
+
+func (x *SessionStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionroute_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionStatusRequest.ProtoReflect.Descriptor instead.
+func (*SessionStatusRequest) Descriptor() ([]byte, []int) {
+	return file_sessionroute_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SessionStatusRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type SessionStatus struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SessionId string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// One of active, ended, failed.
+	Status        string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	UpdatedAtMs   int64  `protobuf:"varint,4,opt,name=updated_at_ms,json=updatedAtMs,proto3" json:"updated_at_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionStatus) Reset() {
+	*x = SessionStatus{}
+	mi := &file_sessionroute_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionStatus) ProtoMessage() {}
+
+func (x *SessionStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionroute_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionStatus.ProtoReflect.Descriptor instead.
+func (*SessionStatus) Descriptor() ([]byte, []int) {
+	return file_sessionroute_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SessionStatus) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SessionStatus) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SessionStatus) GetUpdatedAtMs() int64 {
+	if x != nil {
+		return x.UpdatedAtMs
+	}
+	return 0
+}
+
+var File_sessionroute_proto protoreflect.FileDescriptor
+
+const file_sessionroute_proto_rawDesc = "" +
+	"\n" +
+	"\x12sessionroute.proto\x12\x14rspp.controlplane.v1\"\xb8\x01\n" +
+	"\x1aResolveSessionRouteRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12<\n" +
+	"\x1arequested_pipeline_version\x18\x03 \x01(\tR\x18requestedPipelineVersion\x12 \n" +
+	"\venvironment\x18\x04 \x01(\tR\venvironment\"\xc8\x03\n" +
+	"\fSessionRoute\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12)\n" +
+	"\x10pipeline_version\x18\x03 \x01(\tR\x0fpipelineVersion\x120\n" +
+	"\x14graph_definition_ref\x18\x04 \x01(\tR\x12graphDefinitionRef\x12+\n" +
+	"\x11execution_profile\x18\x05 \x01(\tR\x10executionProfile\x12\x1b\n" +
+	"\tspec_hash\x18\x06 \x01(\tR\bspecHash\x122\n" +
+	"\x15routing_view_snapshot\x18\a \x01(\tR\x13routingViewSnapshot\x12'\n" +
+	"\x0fauthority_epoch\x18\b \x01(\x03R\x0eauthorityEpoch\x12)\n" +
+	"\x10runtime_endpoint\x18\t \x01(\tR\x0fruntimeEndpoint\x12,\n" +
+	"\x12shard_map_snapshot\x18\n" +
+	" \x01(\tR\x10shardMapSnapshot\x12\x1f\n" +
+	"\vshard_owner\x18\v \x01(\tR\n" +
+	"shardOwner\"m\n" +
+	"\x18IssueSessionTokenRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x15\n" +
+	"\x06ttl_ms\x18\x03 \x01(\x03R\x05ttlMs\"g\n" +
+	"\fSessionToken\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12\"\n" +
+	"\rexpires_at_ms\x18\x03 \x01(\x03R\vexpiresAtMs\"5\n" +
+	"\x14SessionStatusRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"\x82\x01\n" +
+	"\rSessionStatus\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\"\n" +
+	"\rupdated_at_ms\x18\x04 \x01(\x03R\vupdatedAtMs2\xcd\x02\n" +
+	"\x13SessionRouteService\x12k\n" +
+	"\x13ResolveSessionRoute\x120.rspp.controlplane.v1.ResolveSessionRouteRequest\x1a\".rspp.controlplane.v1.SessionRoute\x12g\n" +
+	"\x11IssueSessionToken\x12..rspp.controlplane.v1.IssueSessionTokenRequest\x1a\".rspp.controlplane.v1.SessionToken\x12`\n" +
+	"\rSessionStatus\x12*.rspp.controlplane.v1.SessionStatusRequest\x1a#.rspp.controlplane.v1.SessionStatusBQZOgithub.com/tiger/realtime-speech-pipeline/api/controlplaneclient/sessionroutepbb\x06proto3"
+
+var (
+	file_sessionroute_proto_rawDescOnce sync.Once
+	file_sessionroute_proto_rawDescData []byte
+)
+
+func file_sessionroute_proto_rawDescGZIP() []byte {
+	file_sessionroute_proto_rawDescOnce.Do(func() {
+		file_sessionroute_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_sessionroute_proto_rawDesc), len(file_sessionroute_proto_rawDesc)))
+	})
+	return file_sessionroute_proto_rawDescData
+}
+
+var file_sessionroute_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_sessionroute_proto_goTypes = []any{
+	(*ResolveSessionRouteRequest)(nil), // 0: rspp.controlplane.v1.ResolveSessionRouteRequest
+	(*SessionRoute)(nil),               // 1: rspp.controlplane.v1.SessionRoute
+	(*IssueSessionTokenRequest)(nil),   // 2: rspp.controlplane.v1.IssueSessionTokenRequest
+	(*SessionToken)(nil),               // 3: rspp.controlplane.v1.SessionToken
+	(*SessionStatusRequest)(nil),       // 4: rspp.controlplane.v1.SessionStatusRequest
+	(*SessionStatus)(nil),              // 5: rspp.controlplane.v1.SessionStatus
+}
+var file_sessionroute_proto_depIdxs = []int32{
+	0, // 0: rspp.controlplane.v1.SessionRouteService.ResolveSessionRoute:input_type -> rspp.controlplane.v1.ResolveSessionRouteRequest
+	2, // 1: rspp.controlplane.v1.SessionRouteService.IssueSessionToken:input_type -> rspp.controlplane.v1.IssueSessionTokenRequest
+	4, // 2: rspp.controlplane.v1.SessionRouteService.SessionStatus:input_type -> rspp.controlplane.v1.SessionStatusRequest
+	1, // 3: rspp.controlplane.v1.SessionRouteService.ResolveSessionRoute:output_type -> rspp.controlplane.v1.SessionRoute
+	3, // 4: rspp.controlplane.v1.SessionRouteService.IssueSessionToken:output_type -> rspp.controlplane.v1.SessionToken
+	5, // 5: rspp.controlplane.v1.SessionRouteService.SessionStatus:output_type -> rspp.controlplane.v1.SessionStatus
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_sessionroute_proto_init() }
+func file_sessionroute_proto_init() {
+	if File_sessionroute_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sessionroute_proto_rawDesc), len(file_sessionroute_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_sessionroute_proto_goTypes,
+		DependencyIndexes: file_sessionroute_proto_depIdxs,
+		MessageInfos:      file_sessionroute_proto_msgTypes,
+	}.Build()
+	File_sessionroute_proto = out.File
+	file_sessionroute_proto_goTypes = nil
+	file_sessionroute_proto_depIdxs = nil
+}
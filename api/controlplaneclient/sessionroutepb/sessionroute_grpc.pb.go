@@ -0,0 +1,209 @@
+// Session route resolution for edge runtimes. Field names and meanings match the JSON bodies of
+// the REST API (see api/controlplaneclient/types.go). Regenerate the Go stubs with go generate
+// after changing this file.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: sessionroute.proto
+
+package sessionroutepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SessionRouteService_ResolveSessionRoute_FullMethodName = "/rspp.controlplane.v1.SessionRouteService/ResolveSessionRoute"
+	SessionRouteService_IssueSessionToken_FullMethodName   = "/rspp.controlplane.v1.SessionRouteService/IssueSessionToken"
+	SessionRouteService_SessionStatus_FullMethodName       = "/rspp.controlplane.v1.SessionRouteService/SessionStatus"
+)
+
+// SessionRouteServiceClient is the client API for SessionRouteService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SessionRouteServiceClient interface {
+	// ResolveSessionRoute resolves the pipeline version, routing snapshot, and authority epoch a
+	// new session runs with.
+	ResolveSessionRoute(ctx context.Context, in *ResolveSessionRouteRequest, opts ...grpc.CallOption) (*SessionRoute, error)
+	// IssueSessionToken issues a short-lived token binding a tenant and session.
+	IssueSessionToken(ctx context.Context, in *IssueSessionTokenRequest, opts ...grpc.CallOption) (*SessionToken, error)
+	// SessionStatus returns the recorded lifecycle status of a session.
+	SessionStatus(ctx context.Context, in *SessionStatusRequest, opts ...grpc.CallOption) (*SessionStatus, error)
+}
+
+type sessionRouteServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSessionRouteServiceClient(cc grpc.ClientConnInterface) SessionRouteServiceClient {
+	return &sessionRouteServiceClient{cc}
+}
+
+func (c *sessionRouteServiceClient) ResolveSessionRoute(ctx context.Context, in *ResolveSessionRouteRequest, opts ...grpc.CallOption) (*SessionRoute, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionRoute)
+	err := c.cc.Invoke(ctx, SessionRouteService_ResolveSessionRoute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionRouteServiceClient) IssueSessionToken(ctx context.Context, in *IssueSessionTokenRequest, opts ...grpc.CallOption) (*SessionToken, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionToken)
+	err := c.cc.Invoke(ctx, SessionRouteService_IssueSessionToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionRouteServiceClient) SessionStatus(ctx context.Context, in *SessionStatusRequest, opts ...grpc.CallOption) (*SessionStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionStatus)
+	err := c.cc.Invoke(ctx, SessionRouteService_SessionStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SessionRouteServiceServer is the server API for SessionRouteService service.
+// All implementations must embed UnimplementedSessionRouteServiceServer
+// for forward compatibility.
+type SessionRouteServiceServer interface {
+	// ResolveSessionRoute resolves the pipeline version, routing snapshot, and authority epoch a
+	// new session runs with.
+	ResolveSessionRoute(context.Context, *ResolveSessionRouteRequest) (*SessionRoute, error)
+	// IssueSessionToken issues a short-lived token binding a tenant and session.
+	IssueSessionToken(context.Context, *IssueSessionTokenRequest) (*SessionToken, error)
+	// SessionStatus returns the recorded lifecycle status of a session.
+	SessionStatus(context.Context, *SessionStatusRequest) (*SessionStatus, error)
+	mustEmbedUnimplementedSessionRouteServiceServer()
+}
+
+// UnimplementedSessionRouteServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSessionRouteServiceServer struct{}
+
+func (UnimplementedSessionRouteServiceServer) ResolveSessionRoute(context.Context, *ResolveSessionRouteRequest) (*SessionRoute, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveSessionRoute not implemented")
+}
+func (UnimplementedSessionRouteServiceServer) IssueSessionToken(context.Context, *IssueSessionTokenRequest) (*SessionToken, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IssueSessionToken not implemented")
+}
+func (UnimplementedSessionRouteServiceServer) SessionStatus(context.Context, *SessionStatusRequest) (*SessionStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SessionStatus not implemented")
+}
+func (UnimplementedSessionRouteServiceServer) mustEmbedUnimplementedSessionRouteServiceServer() {}
+func (UnimplementedSessionRouteServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeSessionRouteServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SessionRouteServiceServer will
+// result in compilation errors.
+type UnsafeSessionRouteServiceServer interface {
+	mustEmbedUnimplementedSessionRouteServiceServer()
+}
+
+func RegisterSessionRouteServiceServer(s grpc.ServiceRegistrar, srv SessionRouteServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSessionRouteServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SessionRouteService_ServiceDesc, srv)
+}
+
+func _SessionRouteService_ResolveSessionRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveSessionRouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionRouteServiceServer).ResolveSessionRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionRouteService_ResolveSessionRoute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionRouteServiceServer).ResolveSessionRoute(ctx, req.(*ResolveSessionRouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SessionRouteService_IssueSessionToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueSessionTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionRouteServiceServer).IssueSessionToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionRouteService_IssueSessionToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionRouteServiceServer).IssueSessionToken(ctx, req.(*IssueSessionTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SessionRouteService_SessionStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionRouteServiceServer).SessionStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionRouteService_SessionStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionRouteServiceServer).SessionStatus(ctx, req.(*SessionStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SessionRouteService_ServiceDesc is the grpc.ServiceDesc for SessionRouteService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SessionRouteService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rspp.controlplane.v1.SessionRouteService",
+	HandlerType: (*SessionRouteServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ResolveSessionRoute",
+			Handler:    _SessionRouteService_ResolveSessionRoute_Handler,
+		},
+		{
+			MethodName: "IssueSessionToken",
+			Handler:    _SessionRouteService_IssueSessionToken_Handler,
+		},
+		{
+			MethodName: "SessionStatus",
+			Handler:    _SessionRouteService_SessionStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sessionroute.proto",
+}
@@ -0,0 +1,369 @@
+package controlplaneclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/identifiers"
+)
+
+// API paths served by the control plane. All operations are JSON POSTs.
+const (
+	PathPublishPipeline     = "/v1/pipelines/publish"
+	PathRollbackPipeline    = "/v1/pipelines/rollback"
+	PathResolveSessionRoute = "/v1/sessions/route"
+	PathIssueSessionToken   = "/v1/sessions/token"
+	PathSetSessionStatus    = "/v1/sessions/status"
+
+	PathRegisterRolloutPools = "/v1/rollouts/pools"
+	PathShiftRollout         = "/v1/rollouts/shift"
+	PathEvaluateRollout      = "/v1/rollouts/evaluate"
+	PathAbortRollout         = "/v1/rollouts/abort"
+	PathReportPoolSessions   = "/v1/rollouts/sessions"
+	PathRouteRolloutSession  = "/v1/rollouts/route"
+
+	PathPublishShardMap = "/v1/shards/publish"
+)
+
+// Read-only paths served as GETs for dashboards: PathPipelines lists published versions,
+// PathPipelines + "/<pipeline_version>" returns one record, and PathSetSessionStatus +
+// "?session_id=<id>" returns a recorded session status.
+const PathPipelines = "/v1/pipelines"
+
+// PathRollouts + "/<environment>" returns an environment's blue/green status, and
+// PathRollouts + "/<environment>/route?session_id=<id>" returns the pool a new session routes to.
+const PathRollouts = "/v1/rollouts"
+
+// PathShardMap returns the published runtime shard map, and PathDistribution returns the
+// distribution state runtimes resolve turn starts from; point RSPP_CP_DISTRIBUTION_HTTP_URL at
+// it so runtimes follow shard map and version changes made through this API.
+const (
+	PathShardMap     = "/v1/shards"
+	PathDistribution = "/v1/distribution"
+)
+
+// SessionStatusValue is the lifecycle status a caller reports for a session.
+type SessionStatusValue string
+
+const (
+	SessionStatusActive SessionStatusValue = "active"
+	SessionStatusEnded  SessionStatusValue = "ended"
+	SessionStatusFailed SessionStatusValue = "failed"
+)
+
+// PublishPipelineRequest publishes a pipeline version. Spec, when set, is stored content-addressed
+// and its spec_hash is recorded on the pipeline record.
+type PublishPipelineRequest struct {
+	PipelineVersion    string          `json:"pipeline_version"`
+	GraphDefinitionRef string          `json:"graph_definition_ref"`
+	ExecutionProfile   string          `json:"execution_profile,omitempty"`
+	Spec               json.RawMessage `json:"spec,omitempty"`
+	// Activate makes the published version the rollout default for new sessions.
+	Activate bool `json:"activate,omitempty"`
+}
+
+// Validate enforces required publish fields.
+func (r PublishPipelineRequest) Validate() error {
+	if strings.TrimSpace(r.PipelineVersion) == "" {
+		return fmt.Errorf("pipeline_version is required")
+	}
+	if strings.TrimSpace(r.GraphDefinitionRef) == "" {
+		return fmt.Errorf("graph_definition_ref is required")
+	}
+	if len(r.Spec) > 0 && !json.Valid(r.Spec) {
+		return fmt.Errorf("spec must be valid JSON")
+	}
+	return nil
+}
+
+// PublishPipelineResponse reports the stored pipeline record.
+type PublishPipelineResponse struct {
+	PipelineVersion string `json:"pipeline_version"`
+	SpecHash        string `json:"spec_hash,omitempty"`
+	Active          bool   `json:"active"`
+	PublishedAtMS   int64  `json:"published_at_ms"`
+}
+
+// RollbackPipelineRequest reactivates a previously published pipeline version.
+type RollbackPipelineRequest struct {
+	// PipelineVersion is the version to activate; empty rolls back to the version that was
+	// active before the last change.
+	PipelineVersion string `json:"pipeline_version,omitempty"`
+}
+
+// RollbackPipelineResponse reports the active version after a rollback.
+type RollbackPipelineResponse struct {
+	ActivePipelineVersion   string `json:"active_pipeline_version"`
+	PreviousPipelineVersion string `json:"previous_pipeline_version"`
+}
+
+// PipelineList lists published pipeline versions.
+type PipelineList struct {
+	ActivePipelineVersion string   `json:"active_pipeline_version"`
+	PipelineVersions      []string `json:"pipeline_versions"`
+	// RollbackPipelineVersion is the version an empty rollback request would activate.
+	RollbackPipelineVersion string `json:"rollback_pipeline_version,omitempty"`
+	StateSHA256             string `json:"state_sha256"`
+}
+
+// Pipeline is one published pipeline record.
+type Pipeline struct {
+	PipelineVersion    string `json:"pipeline_version"`
+	GraphDefinitionRef string `json:"graph_definition_ref"`
+	ExecutionProfile   string `json:"execution_profile,omitempty"`
+	SpecHash           string `json:"spec_hash,omitempty"`
+	Active             bool   `json:"active"`
+}
+
+// ResolveSessionRouteRequest asks the control plane where a session should run.
+type ResolveSessionRouteRequest struct {
+	TenantID                 string `json:"tenant_id"`
+	SessionID                string `json:"session_id"`
+	RequestedPipelineVersion string `json:"requested_pipeline_version,omitempty"`
+	// Environment selects the deployment environment's active pipeline version; empty resolves
+	// the default.
+	Environment string `json:"environment,omitempty"`
+}
+
+// Validate enforces required route fields.
+func (r ResolveSessionRouteRequest) Validate() error {
+	if strings.TrimSpace(r.TenantID) == "" {
+		return fmt.Errorf("tenant_id is required")
+	}
+	if err := identifiers.ValidateSessionID(r.SessionID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SessionRoute is the resolved pipeline and routing identity for a session.
+type SessionRoute struct {
+	TenantID            string `json:"tenant_id"`
+	SessionID           string `json:"session_id"`
+	PipelineVersion     string `json:"pipeline_version"`
+	GraphDefinitionRef  string `json:"graph_definition_ref"`
+	ExecutionProfile    string `json:"execution_profile"`
+	SpecHash            string `json:"spec_hash,omitempty"`
+	RoutingViewSnapshot string `json:"routing_view_snapshot"`
+	AuthorityEpoch      int64  `json:"authority_epoch"`
+	// RuntimeEndpoint is the shard owner's endpoint when a shard map is published.
+	RuntimeEndpoint string `json:"runtime_endpoint,omitempty"`
+	// ShardMapSnapshot and ShardOwner name the runtime worker that owns the session.
+	ShardMapSnapshot string `json:"shard_map_snapshot,omitempty"`
+	ShardOwner       string `json:"shard_owner,omitempty"`
+}
+
+// IssueSessionTokenRequest asks for a short-lived runtime session token.
+type IssueSessionTokenRequest struct {
+	TenantID  string `json:"tenant_id"`
+	SessionID string `json:"session_id"`
+	// TTLMS requests a token lifetime; zero uses the control-plane default.
+	TTLMS int64 `json:"ttl_ms,omitempty"`
+}
+
+// Validate enforces required token fields.
+func (r IssueSessionTokenRequest) Validate() error {
+	if strings.TrimSpace(r.TenantID) == "" {
+		return fmt.Errorf("tenant_id is required")
+	}
+	if err := identifiers.ValidateSessionID(r.SessionID); err != nil {
+		return err
+	}
+	if r.TTLMS < 0 {
+		return fmt.Errorf("ttl_ms must be >=0")
+	}
+	return nil
+}
+
+// SessionToken is an issued session token.
+type SessionToken struct {
+	SessionID   string `json:"session_id"`
+	Token       string `json:"token"`
+	ExpiresAtMS int64  `json:"expires_at_ms"`
+}
+
+// SetSessionStatusRequest records a session lifecycle status.
+type SetSessionStatusRequest struct {
+	SessionID string             `json:"session_id"`
+	Status    SessionStatusValue `json:"status"`
+	Reason    string             `json:"reason,omitempty"`
+}
+
+// Validate enforces required status fields.
+func (r SetSessionStatusRequest) Validate() error {
+	if err := identifiers.ValidateSessionID(r.SessionID); err != nil {
+		return err
+	}
+	switch r.Status {
+	case SessionStatusActive, SessionStatusEnded, SessionStatusFailed:
+		return nil
+	default:
+		return fmt.Errorf("status must be active|ended|failed")
+	}
+}
+
+// SessionStatus is the recorded session status.
+type SessionStatus struct {
+	SessionID   string             `json:"session_id"`
+	Status      SessionStatusValue `json:"status"`
+	Reason      string             `json:"reason,omitempty"`
+	UpdatedAtMS int64              `json:"updated_at_ms"`
+}
+
+// SessionStatusRequest reads a session's recorded status.
+type SessionStatusRequest struct {
+	SessionID string `json:"session_id"`
+}
+
+// Validate enforces required status lookup fields.
+func (r SessionStatusRequest) Validate() error {
+	return identifiers.ValidateSessionID(r.SessionID)
+}
+
+// RolloutPool is one side of a blue/green runtime deployment.
+type RolloutPool struct {
+	PoolID string `json:"pool_id"`
+	// Color is blue or green.
+	Color string `json:"color"`
+	// PipelineVersion must be a published pipeline version.
+	PipelineVersion string `json:"pipeline_version"`
+	// Weight is the 0-100 share of new sessions routed to the pool.
+	Weight int `json:"weight"`
+	// State is active, draining, or drained; it is ignored on registration.
+	State string `json:"state,omitempty"`
+}
+
+// RolloutGate is the live SLO gate result a cutover evaluation acted on.
+type RolloutGate struct {
+	Passed bool `json:"passed"`
+	// Pending reports too little live evidence to decide; the cutover keeps shifting.
+	Pending bool   `json:"pending,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// RolloutStatus is an environment's blue/green deployment view.
+type RolloutStatus struct {
+	Environment string `json:"environment"`
+	// Phase is stable, shifting, promoted, or aborted.
+	Phase  string        `json:"phase"`
+	Target string        `json:"target,omitempty"`
+	Pools  []RolloutPool `json:"pools"`
+	// ActivePipelineVersion is the environment's active pipeline version after the change; a
+	// promotion activates the promoted pool's pipeline version for the environment only.
+	ActivePipelineVersion string `json:"active_pipeline_version,omitempty"`
+	// Gate is set on evaluate responses.
+	Gate *RolloutGate `json:"gate,omitempty"`
+}
+
+// RegisterRolloutPoolsRequest registers an environment's blue and green pools. Weights must sum
+// to 100.
+type RegisterRolloutPoolsRequest struct {
+	Environment string      `json:"environment"`
+	Blue        RolloutPool `json:"blue"`
+	Green       RolloutPool `json:"green"`
+}
+
+// Validate enforces required registration fields.
+func (r RegisterRolloutPoolsRequest) Validate() error {
+	if strings.TrimSpace(r.Environment) == "" {
+		return fmt.Errorf("environment is required")
+	}
+	if strings.TrimSpace(r.Blue.PipelineVersion) == "" || strings.TrimSpace(r.Green.PipelineVersion) == "" {
+		return fmt.Errorf("blue and green pipeline_version are required")
+	}
+	return nil
+}
+
+// ShiftRolloutRequest routes Weight percent of new sessions to the Target pool.
+type ShiftRolloutRequest struct {
+	Environment string `json:"environment"`
+	Target      string `json:"target"`
+	Weight      int    `json:"weight"`
+}
+
+// Validate enforces required shift fields.
+func (r ShiftRolloutRequest) Validate() error {
+	if strings.TrimSpace(r.Environment) == "" {
+		return fmt.Errorf("environment is required")
+	}
+	if r.Weight < 0 || r.Weight > 100 {
+		return fmt.Errorf("weight must be within [0,100]")
+	}
+	return nil
+}
+
+// RouteRolloutSessionRequest asks which pool of an environment a new session routes to.
+type RouteRolloutSessionRequest struct {
+	Environment string `json:"environment"`
+	SessionID   string `json:"session_id"`
+}
+
+// Validate enforces required routing fields.
+func (r RouteRolloutSessionRequest) Validate() error {
+	if strings.TrimSpace(r.Environment) == "" {
+		return fmt.Errorf("environment is required")
+	}
+	return identifiers.ValidateSessionID(r.SessionID)
+}
+
+// EvaluateRolloutRequest runs the live SLO gate against an environment's shifting pool.
+type EvaluateRolloutRequest struct {
+	Environment string `json:"environment"`
+}
+
+// AbortRolloutRequest rolls an environment's cutover back without a gate evaluation.
+type AbortRolloutRequest struct {
+	Environment string `json:"environment"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// ReportPoolSessionsRequest reports a pool's remaining session count so a draining pool can be
+// marked drained.
+type ReportPoolSessionsRequest struct {
+	Environment    string `json:"environment"`
+	Color          string `json:"color"`
+	ActiveSessions int    `json:"active_sessions"`
+}
+
+// ShardWorker is a runtime worker sharing session load.
+type ShardWorker struct {
+	WorkerID string `json:"worker_id"`
+	// Endpoint is the runtime endpoint sessions the worker owns connect to.
+	Endpoint string `json:"endpoint"`
+}
+
+// ShardMap is the published assignment of sessions to runtime workers by consistent hashing
+// on session_id.
+type ShardMap struct {
+	ShardMapSnapshot string        `json:"shard_map_snapshot,omitempty"`
+	AuthorityEpoch   int64         `json:"authority_epoch,omitempty"`
+	Workers          []ShardWorker `json:"workers"`
+	VirtualNodes     int           `json:"virtual_nodes,omitempty"`
+}
+
+// PublishShardMapRequest replaces the shard map; no workers disables sharding.
+type PublishShardMapRequest struct {
+	Workers []ShardWorker `json:"workers"`
+	// VirtualNodes is the ring points per worker; zero uses the default.
+	VirtualNodes int `json:"virtual_nodes,omitempty"`
+}
+
+// Validate enforces unique workers with endpoints.
+func (r PublishShardMapRequest) Validate() error {
+	if r.VirtualNodes < 0 {
+		return fmt.Errorf("virtual_nodes must be >=0")
+	}
+	seen := make(map[string]struct{}, len(r.Workers))
+	for _, worker := range r.Workers {
+		id := strings.TrimSpace(worker.WorkerID)
+		if id == "" || strings.TrimSpace(worker.Endpoint) == "" {
+			return fmt.Errorf("every worker requires worker_id and endpoint")
+		}
+		if _, ok := seen[id]; ok {
+			return fmt.Errorf("duplicate worker_id: %s", id)
+		}
+		seen[id] = struct{}{}
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package eventabi
 import (
 	"fmt"
 	"regexp"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/identifiers"
 )
 
 // Lane mirrors docs/ContractArtifacts.schema.json $defs.lane.
@@ -34,6 +36,74 @@ const (
 	PayloadMetadata       PayloadClass = "metadata"
 )
 
+// FailureDomain mirrors docs/ContractArtifacts.schema.json $defs.failure_domain and
+// attributes a decision or control signal to the subsystem that caused it.
+type FailureDomain string
+
+const (
+	FailureDomainTransport    FailureDomain = "transport"
+	FailureDomainAdmission    FailureDomain = "admission"
+	FailureDomainScheduler    FailureDomain = "scheduler"
+	FailureDomainProviderSTT  FailureDomain = "provider:stt"
+	FailureDomainProviderLLM  FailureDomain = "provider:llm"
+	FailureDomainProviderTTS  FailureDomain = "provider:tts"
+	FailureDomainControlPlane FailureDomain = "control-plane"
+)
+
+// FailureDomains returns every failure domain in stable report order.
+func FailureDomains() []FailureDomain {
+	return []FailureDomain{
+		FailureDomainTransport,
+		FailureDomainAdmission,
+		FailureDomainScheduler,
+		FailureDomainProviderSTT,
+		FailureDomainProviderLLM,
+		FailureDomainProviderTTS,
+		FailureDomainControlPlane,
+	}
+}
+
+// Validate enforces supported failure domain values.
+func (d FailureDomain) Validate() error {
+	for _, domain := range FailureDomains() {
+		if d == domain {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid failure_domain: %q", d)
+}
+
+// ProviderFailureDomain maps a provider modality (stt|llm|tts) to its failure domain.
+func ProviderFailureDomain(modality string) (FailureDomain, bool) {
+	switch modality {
+	case "stt":
+		return FailureDomainProviderSTT, true
+	case "llm":
+		return FailureDomainProviderLLM, true
+	case "tts":
+		return FailureDomainProviderTTS, true
+	default:
+		return "", false
+	}
+}
+
+// FailureDomainForEmitter infers the failure domain of a module id (RK-xx/CP-xx).
+// RK-11 provider signals need modality context and return false.
+func FailureDomainForEmitter(emittedBy string) (FailureDomain, bool) {
+	switch emittedBy {
+	case "RK-02", "RK-22", "RK-23":
+		return FailureDomainTransport, true
+	case "RK-25", "CP-05":
+		return FailureDomainAdmission, true
+	case "RK-03", "RK-07", "RK-08", "RK-12", "RK-13", "RK-14", "RK-15", "RK-16", "RK-17", "RK-26", "OR-02":
+		return FailureDomainScheduler, true
+	case "RK-24", "CP-07", "CP-08":
+		return FailureDomainControlPlane, true
+	default:
+		return "", false
+	}
+}
+
 // RedactionAction mirrors docs/SecurityDataHandlingBaseline.md action matrix.
 type RedactionAction string
 
@@ -117,29 +187,32 @@ type EventRecord struct {
 
 // ControlSignal mirrors the control_signal artifact shape.
 type ControlSignal struct {
-	SchemaVersion      string       `json:"schema_version"`
-	EventScope         EventScope   `json:"event_scope"`
-	SessionID          string       `json:"session_id"`
-	TurnID             string       `json:"turn_id,omitempty"`
-	PipelineVersion    string       `json:"pipeline_version"`
-	EdgeID             string       `json:"edge_id,omitempty"`
-	SyncDomain         string       `json:"sync_domain,omitempty"`
-	DiscontinuityID    string       `json:"discontinuity_id,omitempty"`
-	EventID            string       `json:"event_id"`
-	Lane               Lane         `json:"lane"`
-	TargetLane         Lane         `json:"target_lane,omitempty"`
-	TransportSequence  *int64       `json:"transport_sequence"`
-	RuntimeSequence    int64        `json:"runtime_sequence"`
-	AuthorityEpoch     int64        `json:"authority_epoch"`
-	RuntimeTimestampMS int64        `json:"runtime_timestamp_ms"`
-	WallClockMS        int64        `json:"wall_clock_timestamp_ms"`
-	PayloadClass       PayloadClass `json:"payload_class"`
-	Signal             string       `json:"signal"`
-	EmittedBy          string       `json:"emitted_by"`
-	Reason             string       `json:"reason,omitempty"`
-	SeqRange           *SeqRange    `json:"seq_range,omitempty"`
-	Amount             *int64       `json:"amount,omitempty"`
-	Scope              string       `json:"scope,omitempty"`
+	SchemaVersion      string        `json:"schema_version"`
+	EventScope         EventScope    `json:"event_scope"`
+	SessionID          string        `json:"session_id"`
+	TurnID             string        `json:"turn_id,omitempty"`
+	PipelineVersion    string        `json:"pipeline_version"`
+	EdgeID             string        `json:"edge_id,omitempty"`
+	SyncDomain         string        `json:"sync_domain,omitempty"`
+	DiscontinuityID    string        `json:"discontinuity_id,omitempty"`
+	EventID            string        `json:"event_id"`
+	Lane               Lane          `json:"lane"`
+	TargetLane         Lane          `json:"target_lane,omitempty"`
+	TransportSequence  *int64        `json:"transport_sequence"`
+	RuntimeSequence    int64         `json:"runtime_sequence"`
+	AuthorityEpoch     int64         `json:"authority_epoch"`
+	RuntimeTimestampMS int64         `json:"runtime_timestamp_ms"`
+	WallClockMS        int64         `json:"wall_clock_timestamp_ms"`
+	PayloadClass       PayloadClass  `json:"payload_class"`
+	Signal             string        `json:"signal"`
+	EmittedBy          string        `json:"emitted_by"`
+	Reason             string        `json:"reason,omitempty"`
+	SeqRange           *SeqRange     `json:"seq_range,omitempty"`
+	Amount             *int64        `json:"amount,omitempty"`
+	Scope              string        `json:"scope,omitempty"`
+	FailureDomain      FailureDomain `json:"failure_domain,omitempty"`
+	QueuePosition      *int64        `json:"queue_position,omitempty"`
+	EstimatedWaitMS    *int64        `json:"estimated_wait_ms,omitempty"`
 }
 
 var schemaVersionRE = regexp.MustCompile(`^v[0-9]+\.[0-9]+(?:\.[0-9]+)?$`)
@@ -162,6 +235,9 @@ func (e EventRecord) Validate() error {
 	if e.SessionID == "" || e.PipelineVersion == "" || e.EventID == "" {
 		return fmt.Errorf("session_id, pipeline_version, and event_id are required")
 	}
+	if err := validateIdentifiers(e.SessionID, e.TurnID, e.EventID); err != nil {
+		return err
+	}
 	if !isLane(e.Lane) {
 		return fmt.Errorf("invalid lane: %q", e.Lane)
 	}
@@ -191,6 +267,14 @@ func (e EventRecord) Validate() error {
 	return nil
 }
 
+// ResolvedFailureDomain returns the tagged failure domain, falling back to emitter inference.
+func (c ControlSignal) ResolvedFailureDomain() (FailureDomain, bool) {
+	if c.FailureDomain != "" {
+		return c.FailureDomain, true
+	}
+	return FailureDomainForEmitter(c.EmittedBy)
+}
+
 func (c ControlSignal) Validate() error {
 	if !schemaVersionRE.MatchString(c.SchemaVersion) {
 		return fmt.Errorf("invalid schema_version: %q", c.SchemaVersion)
@@ -204,6 +288,9 @@ func (c ControlSignal) Validate() error {
 	if c.SessionID == "" || c.PipelineVersion == "" || c.EventID == "" {
 		return fmt.Errorf("session_id, pipeline_version, and event_id are required")
 	}
+	if err := validateIdentifiers(c.SessionID, c.TurnID, c.EventID); err != nil {
+		return err
+	}
 	if c.Lane != LaneControl {
 		return fmt.Errorf("control_signal lane must be ControlLane")
 	}
@@ -225,6 +312,11 @@ func (c ControlSignal) Validate() error {
 	if c.Signal == "" {
 		return fmt.Errorf("signal is required")
 	}
+	if c.FailureDomain != "" {
+		if err := c.FailureDomain.Validate(); err != nil {
+			return err
+		}
+	}
 	if !isControlSignalName(c.Signal) {
 		return fmt.Errorf("invalid signal: %q", c.Signal)
 	}
@@ -251,6 +343,18 @@ func (c ControlSignal) Validate() error {
 		}
 	}
 
+	if c.Signal == "queue_eta" {
+		if c.EmittedBy != "RK-25" || c.Reason == "" || c.QueuePosition == nil || c.EstimatedWaitMS == nil {
+			return fmt.Errorf("queue_eta requires emitted_by=RK-25, reason, queue_position, and estimated_wait_ms")
+		}
+	}
+	if c.QueuePosition != nil && *c.QueuePosition < 1 {
+		return fmt.Errorf("queue_position must be >=1")
+	}
+	if c.EstimatedWaitMS != nil && *c.EstimatedWaitMS < 0 {
+		return fmt.Errorf("estimated_wait_ms must be >=0")
+	}
+
 	if c.Signal == "shed" {
 		if c.EmittedBy != "RK-25" || c.Reason == "" {
 			return fmt.Errorf("shed requires emitted_by=RK-25 and reason")
@@ -306,6 +410,9 @@ func (c ControlSignal) Validate() error {
 		if c.EmittedBy != "RK-11" || c.Reason == "" {
 			return fmt.Errorf("%s requires emitted_by=RK-11 and reason", c.Signal)
 		}
+		if c.FailureDomain != "" && !inStringSet(string(c.FailureDomain), []string{string(FailureDomainProviderSTT), string(FailureDomainProviderLLM), string(FailureDomainProviderTTS)}) {
+			return fmt.Errorf("%s failure_domain must be a provider domain", c.Signal)
+		}
 	}
 
 	if inStringSet(c.Signal, []string{"lease_issued", "lease_rotated", "migration_start", "migration_finish", "session_handoff"}) {
@@ -332,6 +439,18 @@ func (c ControlSignal) Validate() error {
 		}
 	}
 
+	if inStringSet(c.Signal, []string{"capture_start", "capture_end"}) {
+		if !inStringSet(c.EmittedBy, []string{"RK-22", "RK-23"}) {
+			return fmt.Errorf("%s requires emitted_by RK-22|RK-23", c.Signal)
+		}
+	}
+
+	if c.Signal == "length_capped" {
+		if c.EmittedBy != "RK-11" || c.Reason == "" {
+			return fmt.Errorf("length_capped requires emitted_by=RK-11 and reason")
+		}
+	}
+
 	if c.Signal == "recording_level_downgraded" {
 		if c.EmittedBy != "OR-02" || c.Reason == "" {
 			return fmt.Errorf("recording_level_downgraded requires emitted_by=OR-02 and reason")
@@ -379,7 +498,7 @@ func isControlSignalEmitter(v string) bool {
 
 func isControlSignalName(v string) bool {
 	switch v {
-	case "turn_open_proposed", "turn_open", "commit", "abort", "close", "barge_in", "stop", "cancel", "watermark", "budget_warning", "budget_exhausted", "degrade", "fallback", "discontinuity", "drop_notice", "flow_xoff", "flow_xon", "credit_grant", "provider_error", "circuit_event", "provider_switch", "lease_issued", "lease_rotated", "migration_start", "migration_finish", "session_handoff", "admit", "reject", "defer", "shed", "stale_epoch_reject", "deauthorized_drain", "connected", "reconnecting", "disconnected", "ended", "silence", "stall", "output_accepted", "playback_started", "playback_completed", "playback_cancelled", "recording_level_downgraded":
+	case "turn_open_proposed", "turn_open", "commit", "abort", "close", "barge_in", "stop", "cancel", "watermark", "budget_warning", "budget_exhausted", "degrade", "fallback", "discontinuity", "drop_notice", "flow_xoff", "flow_xon", "credit_grant", "provider_error", "circuit_event", "provider_switch", "lease_issued", "lease_rotated", "migration_start", "migration_finish", "session_handoff", "admit", "reject", "defer", "queue_eta", "shed", "stale_epoch_reject", "deauthorized_drain", "connected", "reconnecting", "disconnected", "ended", "silence", "stall", "output_accepted", "playback_started", "playback_completed", "playback_cancelled", "recording_level_downgraded", "capture_start", "capture_end", "length_capped":
 		return true
 	default:
 		return false
@@ -394,3 +513,17 @@ func inStringSet(v string, set []string) bool {
 	}
 	return false
 }
+
+// validateIdentifiers applies the shared identifier format to the IDs evidence joins on; an
+// empty turnID is left to the event_scope checks.
+func validateIdentifiers(sessionID, turnID, eventID string) error {
+	if err := identifiers.ValidateSessionID(sessionID); err != nil {
+		return err
+	}
+	if turnID != "" {
+		if err := identifiers.ValidateTurnID(turnID); err != nil {
+			return err
+		}
+	}
+	return identifiers.ValidateEventID(eventID)
+}
@@ -88,6 +88,38 @@ func TestCT002ControlSignalEmitterMappingAndUnknownSignal(t *testing.T) {
 				sig.SeqRange = &SeqRange{Start: 10, End: 20}
 			},
 		},
+		{
+			name: "failure_domain tag accepted",
+			mutate: func(sig *ControlSignal) {
+				sig.FailureDomain = FailureDomainScheduler
+			},
+		},
+		{
+			name: "unknown failure_domain rejected",
+			mutate: func(sig *ControlSignal) {
+				sig.FailureDomain = "network"
+			},
+			shouldErr: true,
+		},
+		{
+			name: "provider_error requires provider failure_domain",
+			mutate: func(sig *ControlSignal) {
+				sig.Signal = "provider_error"
+				sig.EmittedBy = "RK-11"
+				sig.Reason = "timeout"
+				sig.FailureDomain = FailureDomainTransport
+			},
+			shouldErr: true,
+		},
+		{
+			name: "provider_error provider failure_domain",
+			mutate: func(sig *ControlSignal) {
+				sig.Signal = "provider_error"
+				sig.EmittedBy = "RK-11"
+				sig.Reason = "timeout"
+				sig.FailureDomain = FailureDomainProviderTTS
+			},
+		},
 		{
 			name: "unknown signal rejected",
 			mutate: func(sig *ControlSignal) {
@@ -128,3 +160,33 @@ func TestRedactionDecisionValidate(t *testing.T) {
 		t.Fatalf("expected invalid redaction action to fail validation")
 	}
 }
+
+func TestFailureDomainInference(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		emittedBy string
+		want      FailureDomain
+		ok        bool
+	}{
+		{emittedBy: "RK-23", want: FailureDomainTransport, ok: true},
+		{emittedBy: "CP-05", want: FailureDomainAdmission, ok: true},
+		{emittedBy: "RK-14", want: FailureDomainScheduler, ok: true},
+		{emittedBy: "RK-24", want: FailureDomainControlPlane, ok: true},
+		{emittedBy: "RK-11", ok: false},
+	}
+	for _, tc := range tests {
+		got, ok := FailureDomainForEmitter(tc.emittedBy)
+		if got != tc.want || ok != tc.ok {
+			t.Fatalf("expected %s -> (%q,%v), got (%q,%v)", tc.emittedBy, tc.want, tc.ok, got, ok)
+		}
+	}
+
+	tagged := ControlSignal{EmittedBy: "RK-11", FailureDomain: FailureDomainProviderSTT}
+	if domain, ok := tagged.ResolvedFailureDomain(); !ok || domain != FailureDomainProviderSTT {
+		t.Fatalf("expected explicit tag to win, got %q", domain)
+	}
+	if domain, ok := ProviderFailureDomain("llm"); !ok || domain != FailureDomainProviderLLM {
+		t.Fatalf("expected llm modality to map to provider:llm, got %q", domain)
+	}
+}
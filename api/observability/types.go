@@ -2,6 +2,7 @@ package observability
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
 )
@@ -35,6 +36,145 @@ const (
 	ReplayFidelityL2 ReplayFidelity = "L2"
 )
 
+// ReplayMode mirrors docs/ContractArtifacts.schema.json recording_policy.allowed_replay_modes.
+type ReplayMode string
+
+const (
+	ReplayModeReSimulateNodes                 ReplayMode = "re_simulate_nodes"
+	ReplayModePlaybackRecordedProviderOutputs ReplayMode = "playback_recorded_provider_outputs"
+	ReplayModeReplayDecisions                 ReplayMode = "replay_decisions"
+	ReplayModeRecomputeDecisions              ReplayMode = "recompute_decisions"
+)
+
+// ReplayRunFilter narrows replay execution to a subset of recorded evidence.
+// Empty fields match everything. The turn range is inclusive and follows the recorded order of
+// the turns; bounds that were not recorded are placed by CompareTurnIDs.
+type ReplayRunFilter struct {
+	SessionID         string            `json:"session_id,omitempty"`
+	TurnIDFrom        string            `json:"turn_id_from,omitempty"`
+	TurnIDTo          string            `json:"turn_id_to,omitempty"`
+	Lane              eventabi.Lane     `json:"lane,omitempty"`
+	DivergenceClasses []DivergenceClass `json:"divergence_classes,omitempty"`
+}
+
+// Validate enforces replay filter value constraints.
+func (f ReplayRunFilter) Validate() error {
+	if f.TurnIDFrom != "" && f.TurnIDTo != "" {
+		if order, ok := CompareTurnIDs(f.TurnIDFrom, f.TurnIDTo); ok && order > 0 {
+			return fmt.Errorf("turn_id_from must be <= turn_id_to")
+		}
+	}
+	if f.Lane != "" && f.Lane != eventabi.LaneData && f.Lane != eventabi.LaneControl && f.Lane != eventabi.LaneTelemetry {
+		return fmt.Errorf("invalid replay filter lane: %q", f.Lane)
+	}
+	for _, class := range f.DivergenceClasses {
+		if !isDivergenceClass(class) {
+			return fmt.Errorf("invalid replay filter divergence class: %q", class)
+		}
+	}
+	return nil
+}
+
+// CompareTurnIDs orders two turn ids by their intrinsic order: ids sharing a prefix with a
+// numeric suffix compare numerically ("turn-2" before "turn-10"), and ULIDs compare lexically,
+// which is their time order. ok is false when the ids have no intrinsic order.
+func CompareTurnIDs(a, b string) (int, bool) {
+	if isULID(a) && isULID(b) {
+		return strings.Compare(a, b), true
+	}
+	prefixA, numberA := splitNumericSuffix(a)
+	prefixB, numberB := splitNumericSuffix(b)
+	if numberA == "" || numberB == "" || prefixA != prefixB {
+		return 0, false
+	}
+	numberA, numberB = strings.TrimLeft(numberA, "0"), strings.TrimLeft(numberB, "0")
+	if len(numberA) != len(numberB) {
+		if len(numberA) < len(numberB) {
+			return -1, true
+		}
+		return 1, true
+	}
+	return strings.Compare(numberA, numberB), true
+}
+
+func splitNumericSuffix(id string) (string, string) {
+	end := len(id)
+	for end > 0 && id[end-1] >= '0' && id[end-1] <= '9' {
+		end--
+	}
+	return id[:end], id[end:]
+}
+
+// isULID reports a 26-character Crockford base32 ULID.
+func isULID(id string) bool {
+	if len(id) != 26 || id[0] > '7' {
+		return false
+	}
+	for _, c := range id {
+		if !strings.ContainsRune("0123456789ABCDEFGHJKMNPQRSTVWXYZ", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// ReplayCursor resumes replay after a recorded event and bounds the number evaluated.
+type ReplayCursor struct {
+	AfterEventID string `json:"after_event_id,omitempty"`
+	Limit        int    `json:"limit,omitempty"`
+}
+
+// Validate enforces replay cursor value constraints.
+func (c ReplayCursor) Validate() error {
+	if c.Limit < 0 {
+		return fmt.Errorf("replay cursor limit must be >=0")
+	}
+	return nil
+}
+
+// ReplayRunRequest captures OR-03 replay execution inputs.
+type ReplayRunRequest struct {
+	BaselineRef  string           `json:"baseline_ref"`
+	CandidateRef string           `json:"candidate_ref,omitempty"`
+	Mode         ReplayMode       `json:"mode"`
+	Filter       *ReplayRunFilter `json:"filter,omitempty"`
+	Cursor       *ReplayCursor    `json:"cursor,omitempty"`
+}
+
+// Validate enforces minimal replay run request requirements.
+func (r ReplayRunRequest) Validate() error {
+	if r.BaselineRef == "" {
+		return fmt.Errorf("baseline_ref is required")
+	}
+	if !isReplayMode(r.Mode) {
+		return fmt.Errorf("invalid replay mode: %q", r.Mode)
+	}
+	if r.Filter != nil {
+		if err := r.Filter.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.Cursor != nil {
+		if err := r.Cursor.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplayRunResult captures OR-03 replay execution outputs. The applied filter and
+// cursor are echoed so partial replays are reproducible.
+type ReplayRunResult struct {
+	BaselineRef   string             `json:"baseline_ref"`
+	CandidateRef  string             `json:"candidate_ref,omitempty"`
+	Mode          ReplayMode         `json:"mode"`
+	AppliedFilter *ReplayRunFilter   `json:"applied_filter,omitempty"`
+	AppliedCursor *ReplayCursor      `json:"applied_cursor,omitempty"`
+	NextCursor    *ReplayCursor      `json:"next_cursor,omitempty"`
+	EvaluatedIDs  []string           `json:"evaluated_ids"`
+	Divergences   []ReplayDivergence `json:"divergences"`
+}
+
 // ReplayAccessRequest captures minimum replay authorization attributes.
 type ReplayAccessRequest struct {
 	TenantID          string                  `json:"tenant_id"`
@@ -105,6 +245,24 @@ func (e ReplayAuditEvent) Validate() error {
 	return nil
 }
 
+func isDivergenceClass(v DivergenceClass) bool {
+	switch v {
+	case PlanDivergence, OutcomeDivergence, OrderingDivergence, TimingDivergence, AuthorityDivergence:
+		return true
+	default:
+		return false
+	}
+}
+
+func isReplayMode(v ReplayMode) bool {
+	switch v {
+	case ReplayModeReSimulateNodes, ReplayModePlaybackRecordedProviderOutputs, ReplayModeReplayDecisions, ReplayModeRecomputeDecisions:
+		return true
+	default:
+		return false
+	}
+}
+
 func isReplayFidelity(v ReplayFidelity) bool {
 	switch v {
 	case ReplayFidelityL0, ReplayFidelityL1, ReplayFidelityL2:
@@ -63,3 +63,96 @@ func TestReplayAuditEventValidate(t *testing.T) {
 		t.Fatalf("expected denied event without reason to fail validation")
 	}
 }
+
+func TestReplayRunRequestValidate(t *testing.T) {
+	t.Parallel()
+
+	req := ReplayRunRequest{
+		BaselineRef:  "baseline/runtime-baseline.json",
+		CandidateRef: "policy-bundle/v2",
+		Mode:         ReplayModeRecomputeDecisions,
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("expected valid replay run request, got %v", err)
+	}
+
+	req.Mode = "replay_everything"
+	if err := req.Validate(); err == nil {
+		t.Fatalf("expected invalid replay mode to fail validation")
+	}
+
+	req.Mode = ReplayModeReplayDecisions
+	req.BaselineRef = ""
+	if err := req.Validate(); err == nil {
+		t.Fatalf("expected missing baseline_ref to fail validation")
+	}
+}
+
+func TestReplayRunRequestValidatesFilterAndCursor(t *testing.T) {
+	t.Parallel()
+
+	req := ReplayRunRequest{
+		BaselineRef: "baseline/runtime-baseline.json",
+		Mode:        ReplayModeRecomputeDecisions,
+		Filter: &ReplayRunFilter{
+			SessionID:         "sess-1",
+			TurnIDFrom:        "turn-001",
+			TurnIDTo:          "turn-010",
+			Lane:              eventabi.LaneControl,
+			DivergenceClasses: []DivergenceClass{OutcomeDivergence},
+		},
+		Cursor: &ReplayCursor{AfterEventID: "evt-5", Limit: 10},
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("expected valid filtered replay request, got %v", err)
+	}
+
+	req.Filter.TurnIDFrom = "turn-020"
+	if err := req.Validate(); err == nil {
+		t.Fatalf("expected inverted turn range to fail validation")
+	}
+	req.Filter.TurnIDFrom, req.Filter.TurnIDTo = "turn-2", "turn-10"
+	if err := req.Validate(); err != nil {
+		t.Fatalf("expected numeric turn order to accept turn-2..turn-10, got %v", err)
+	}
+	req.Filter.TurnIDFrom, req.Filter.TurnIDTo = "turn-b", "turn-a"
+	if err := req.Validate(); err != nil {
+		t.Fatalf("expected turn ids without intrinsic order to be left to recorded order, got %v", err)
+	}
+	req.Filter.TurnIDFrom, req.Filter.TurnIDTo = "turn-001", "turn-010"
+
+	req.Filter.DivergenceClasses = []DivergenceClass{"NOISE_DIVERGENCE"}
+	if err := req.Validate(); err == nil {
+		t.Fatalf("expected unknown divergence class to fail validation")
+	}
+	req.Filter.DivergenceClasses = nil
+
+	req.Cursor.Limit = -1
+	if err := req.Validate(); err == nil {
+		t.Fatalf("expected negative cursor limit to fail validation")
+	}
+}
+
+func TestCompareTurnIDs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		a, b  string
+		order int
+		ok    bool
+	}{
+		{name: "numeric suffix", a: "turn-2", b: "turn-10", order: -1, ok: true},
+		{name: "zero padded suffix", a: "turn-010", b: "turn-9", order: 1, ok: true},
+		{name: "equal", a: "sess-1-turn-3", b: "sess-1-turn-3", order: 0, ok: true},
+		{name: "ulid", a: "01HZX3M8K2QF7T9V4B6N1C5D8E", b: "01HZX3M8K2QF7T9V4B6N1C5D9A", order: -1, ok: true},
+		{name: "different prefix", a: "turn-2", b: "call-10", ok: false},
+		{name: "no numeric suffix", a: "turn-b", b: "turn-a", ok: false},
+	}
+	for _, tc := range tests {
+		order, ok := CompareTurnIDs(tc.a, tc.b)
+		if ok != tc.ok || (ok && order != tc.order) {
+			t.Fatalf("%s: expected order=%d ok=%v, got order=%d ok=%v", tc.name, tc.order, tc.ok, order, ok)
+		}
+	}
+}
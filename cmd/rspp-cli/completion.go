@@ -0,0 +1,48 @@
+package main
+
+import "github.com/tiger/realtime-speech-pipeline/internal/tooling/completion"
+
+// cliCompletionSpec mirrors the commands dispatched by main; positional arguments complete
+// as files.
+func cliCompletionSpec() completion.Spec {
+	commands := []completion.Command{}
+	for _, name := range []string{
+		"validate-contracts",
+		"validate-arbiter-fixtures",
+		"validate-contracts-report",
+		"contract-coverage-report",
+		"validate-spec",
+		"validate-bindings",
+		"replay-smoke-report",
+		"replay-regression-report",
+		"generate-runtime-baseline",
+		"slo-gates-report",
+		"llm-eval-report",
+		"redaction-eval-report",
+		"failure-domain-report",
+		"sla-report",
+		"cost-report",
+		"debug-bundle",
+		"explain-decision",
+		"replay-decisions",
+		"replay-shell",
+		"synthesize-fixture",
+		"runbook-report",
+		"compliance-report",
+		"provider-benchmark",
+		"evaluate-alerts",
+		"publish-release",
+		"get-spec",
+		"execute-rollback",
+		"export-recording",
+		"scrub-artifact",
+		"verify-artifacts",
+	} {
+		commands = append(commands, completion.Command{Name: name})
+	}
+	commands = append(commands,
+		completion.Command{Name: "artifacts", Subcommands: []string{"export", "import"}},
+		completion.CompletionCommand(),
+	)
+	return completion.Spec{Program: "rspp-cli", Commands: commands}
+}
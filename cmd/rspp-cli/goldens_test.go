@@ -0,0 +1,424 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/decisionindex"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/llmeval"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/ops"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/redactioneval"
+	toolingrelease "github.com/tiger/realtime-speech-pipeline/internal/tooling/release"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/runbook"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/validation"
+)
+
+// updateGoldens rewrites testdata/golden from the renderers: go test ./cmd/rspp-cli -run TestRenderersMatchGoldens -update
+var updateGoldens = flag.Bool("update", false, "rewrite renderer golden files under testdata/golden")
+
+const (
+	goldenDir            = "testdata/golden"
+	goldenGeneratedAtUTC = "2026-01-02T03:04:05Z"
+)
+
+type renderGoldenCase struct {
+	name   string
+	render func() string
+}
+
+// renderGoldenCases renders every markdown summary from fixed inputs. Cases cover both
+// passing and failing variants where a renderer branches on status.
+func renderGoldenCases() []renderGoldenCase {
+	threshold := int64(250)
+	turnOpenP95 := int64(80)
+	firstOutputP95 := int64(640)
+	epoch := int64(7)
+
+	return []renderGoldenCase{
+		{name: "replay-regression-pass", render: func() string {
+			return renderReplayRegressionSummary(replayRegressionReport{
+				GeneratedAtUTC:   goldenGeneratedAtUTC,
+				Gate:             "full",
+				MetadataPath:     defaultReplayMetadataPath,
+				FixtureCount:     3,
+				TotalDivergences: 2,
+				ByClass:          map[string]int{string(obs.TimingDivergence): 2},
+				PipelineVersions: []string{"pipeline-v1", "pipeline-v2"},
+			})
+		}},
+		{name: "replay-regression-fail", render: func() string {
+			return renderReplayRegressionSummary(replayRegressionReport{
+				GeneratedAtUTC:     goldenGeneratedAtUTC,
+				Gate:               "quick",
+				MetadataPath:       defaultReplayMetadataPath,
+				FixtureCount:       2,
+				TotalDivergences:   3,
+				FailingCount:       2,
+				UnexplainedCount:   1,
+				MissingExpected:    1,
+				ByClass:            map[string]int{string(obs.PlanDivergence): 1, string(obs.AuthorityDivergence): 2},
+				FailingDivergences: []string{"rd-002:AUTHORITY_DIVERGENCE", "rd-003:PLAN_DIVERGENCE"},
+			})
+		}},
+		{name: "replay-fixture-pass", render: func() string {
+			return renderReplayFixtureSummary(replayFixtureArtifact{
+				GeneratedAtUTC: goldenGeneratedAtUTC,
+				MetadataPath:   defaultReplayMetadataPath,
+				replayFixtureExecutionReport: replayFixtureExecutionReport{
+					FixtureID:                      "rd-001-smoke",
+					Gate:                           "full",
+					TimingToleranceMS:              15,
+					FinalAttemptLatencyThresholdMS: &threshold,
+					TotalDivergences:               1,
+					ExpectedConfigured:             1,
+					ByClass:                        map[string]int{string(obs.TimingDivergence): 1},
+				},
+				Status: "PASS",
+			})
+		}},
+		{name: "replay-fixture-fail", render: func() string {
+			return renderReplayFixtureSummary(replayFixtureArtifact{
+				GeneratedAtUTC: goldenGeneratedAtUTC,
+				MetadataPath:   defaultReplayMetadataPath,
+				replayFixtureExecutionReport: replayFixtureExecutionReport{
+					FixtureID:                         "rd-ordering-approved-1",
+					Gate:                              "full",
+					TimingToleranceMS:                 15,
+					TotalInvocationLatencyThresholdMS: &threshold,
+					InvocationLatencyBreaches:         1,
+					TotalDivergences:                  2,
+					FailingCount:                      1,
+					UnexplainedCount:                  1,
+					ByClass:                           map[string]int{string(obs.OrderingDivergence): 2},
+					FailingClasses:                    []string{string(obs.OrderingDivergence)},
+				},
+				Status: "FAIL",
+			})
+		}},
+		{name: "replay-smoke-fail", render: func() string {
+			return renderReplaySmokeSummary(replaySmokeReport{
+				GeneratedAtUTC:     goldenGeneratedAtUTC,
+				FixtureID:          replaySmokeFixtureID,
+				MetadataPath:       defaultReplayMetadataPath,
+				TimingToleranceMS:  replaySmokeTimingToleranceMS,
+				TotalDivergences:   2,
+				ByClass:            map[string]int{string(obs.OutcomeDivergence): 1, string(obs.TimingDivergence): 1},
+				FailingCount:       1,
+				UnexplainedCount:   1,
+				ExpectedConfigured: 1,
+				FailingDivergences: []string{"OUTCOME_DIVERGENCE"},
+			})
+		}},
+		{name: "rollback", render: func() string {
+			return renderRollbackSummary(toolingrelease.RollbackReport{
+				ReleaseID:             "rel-golden",
+				GeneratedAtUTC:        goldenGeneratedAtUTC,
+				FromPipelineVersion:   "pipeline-v2",
+				ToPipelineVersion:     "pipeline-v1",
+				PreviousActiveVersion: "pipeline-v2",
+				DistributionPath:      "pipelines/compat/cp_distribution.json",
+				Steps: []toolingrelease.RollbackStep{
+					{Name: "load_manifest", Passed: true},
+					{Name: "replay_smoke", Passed: false, Detail: "1 failing divergence"},
+				},
+			})
+		}},
+		{name: "slo-gates", render: func() string {
+			return renderSLOGatesSummary(sloGateArtifact{
+				GeneratedAtUTC:       goldenGeneratedAtUTC,
+				BaselineArtifactPath: defaultRuntimeBaselineArtifactPath,
+				QualityCorpusPath:    ops.DefaultSTTGoldenCorpusPath,
+				Report: ops.MVPSLOGateReport{
+					Samples:                   4,
+					AcceptedTurns:             3,
+					HappyPathTurns:            2,
+					CancelObservedTurns:       1,
+					BaselineCompletenessRatio: 1,
+					TerminalCorrectnessRatio:  1,
+					TurnOpenDecisionP95MS:     &turnOpenP95,
+					FirstOutputP95MS:          &firstOutputP95,
+					Violations:                []string{"cancel_fence_p95_ms unavailable"},
+					ByPipelineVersion: map[string]ops.MVPSLOGateReport{
+						"pipeline-v1":                {Samples: 3, AcceptedTurns: 2, Passed: true},
+						ops.UnversionedPipelineSlice: {Samples: 1, AcceptedTurns: 1},
+					},
+				},
+				ErrorBudget: &ops.ErrorBudgetReport{
+					Policy:               ops.DefaultErrorBudgetPolicy(),
+					TotalEvents:          200,
+					BadEvents:            4,
+					BudgetConsumedRatio:  2,
+					BudgetRemainingRatio: 0,
+					FastBurnRate:         20,
+					SlowBurnRate:         8,
+					Alerts:               []ops.BurnRateAlert{{Severity: ops.BurnSeverityFast, WindowMS: 3600000, BurnRate: 20, Threshold: 14.4}},
+				},
+				Quality: &ops.STTQualityReport{
+					Providers: []ops.STTProviderQuality{
+						{ProviderID: "stt-a", ThresholdWER: 0.15, AggregateWER: 0.08, Passed: true},
+						{ProviderID: "stt-b", ThresholdWER: 0.15, AggregateWER: 0.21},
+					},
+					Violations: []string{"stt-b aggregate WER 0.210 exceeds threshold 0.150"},
+				},
+			})
+		}},
+		{name: "failure-domains", render: func() string {
+			return renderFailureDomainSummary(failureDomainReportArtifact{
+				GeneratedAtUTC:       goldenGeneratedAtUTC,
+				BaselineArtifactPath: defaultRuntimeBaselineArtifactPath,
+				Report: ops.FailureDomainReport{
+					BucketMS:    60000,
+					TotalTurns:  5,
+					FailedTurns: 3,
+					ByDomain:    map[string]int{"provider": 2, "admission": 1},
+					Buckets: []ops.FailureDomainBucket{
+						{StartMS: 0, FailedTurns: 2, ByDomain: map[string]int{"provider": 1, "admission": 1}},
+						{StartMS: 60000, FailedTurns: 1, ByDomain: map[string]int{"provider": 1}},
+					},
+				},
+			})
+		}},
+		{name: "debug-bundle", render: func() string {
+			return renderDebugBundleSummary(debugBundleArtifact{
+				GeneratedAtUTC:       goldenGeneratedAtUTC,
+				BaselineArtifactPath: defaultRuntimeBaselineArtifactPath,
+				SessionID:            "sess-golden",
+				TurnID:               "turn-1",
+				DecisionSource:       debugBundleDecisionSourceAdminSocket,
+				Decisions: []controlplane.DecisionOutcome{
+					{TurnID: "turn-1", RuntimeTimestampMS: 100, Phase: controlplane.PhasePreTurn, OutcomeKind: controlplane.OutcomeAdmit, Reason: "admission_capacity_allow", EmittedBy: controlplane.EmitterRK25},
+					{TurnID: "turn-1", RuntimeTimestampMS: 180, Phase: controlplane.PhaseActiveTurn, OutcomeKind: controlplane.OutcomeStaleEpochReject, Reason: "authority_epoch_mismatch", EmittedBy: controlplane.EmitterRK24, AuthorityEpoch: &epoch},
+				},
+				DecisionWindow: decisionindex.Stats{Size: 2, Capacity: decisionindex.DefaultCapacity},
+				Baseline:       []timeline.BaselineEvidence{{SessionID: "sess-golden", TurnID: "turn-1"}},
+			})
+		}},
+		{name: "decision-replay", render: func() string {
+			return renderDecisionReplaySummary(decisionReplayArtifact{
+				GeneratedAtUTC:       goldenGeneratedAtUTC,
+				BaselineArtifactPath: defaultRuntimeBaselineArtifactPath,
+				CandidatePath:        "candidate-distribution.json",
+				RecordedInputs:       2,
+				Result: obs.ReplayRunResult{
+					BaselineRef:   defaultRuntimeBaselineArtifactPath,
+					CandidateRef:  "candidate-distribution.json",
+					Mode:          obs.ReplayModeRecomputeDecisions,
+					AppliedFilter: &obs.ReplayRunFilter{SessionID: "sess-golden", DivergenceClasses: []obs.DivergenceClass{obs.PlanDivergence}},
+					AppliedCursor: &obs.ReplayCursor{Limit: 2},
+					NextCursor:    &obs.ReplayCursor{AfterEventID: "turn-2-open", Limit: 2},
+					EvaluatedIDs:  []string{"turn-1-open", "turn-2-open"},
+					Divergences: []obs.ReplayDivergence{
+						{Class: obs.PlanDivergence, Scope: "turn:turn-1", Message: "recomputed plan hash mismatch at index=0 baseline=a replay=b"},
+					},
+				},
+			})
+		}},
+		{name: "explain-decision", render: func() string {
+			return renderExplainDecisionSummary(explainDecisionArtifact{
+				GeneratedAtUTC:       goldenGeneratedAtUTC,
+				BaselineArtifactPath: defaultRuntimeBaselineArtifactPath,
+				EventID:              "evt-shed-1",
+				Decision: controlplane.DecisionOutcome{
+					SessionID: "sess-golden", TurnID: "turn-1", EventID: "evt-shed-1",
+					OutcomeKind: controlplane.OutcomeShed, Phase: controlplane.PhaseScheduling, Scope: controlplane.ScopeEdgeEnqueue,
+					EmittedBy: controlplane.EmitterRK25, Reason: "scheduling_point_shed",
+					Explain: &controlplane.DecisionExplain{
+						PolicyRule:    "rk25.scheduling_point.shed",
+						Inputs:        map[string]string{"scope": "edge_enqueue", "reason": "scheduling_point_shed"},
+						QuotaCounters: []controlplane.QuotaCounter{{Name: "tenant_concurrent_turns", Used: 32, Limit: 32}},
+						Thresholds:    []controlplane.ThresholdEvaluation{{Name: "edge_queue_depth", Observed: 480, Threshold: 256, Breached: true}},
+					},
+				},
+			})
+		}},
+		{name: "runbook", render: func() string {
+			return renderRunbookSummary(runbookDecisionsArtifact{
+				GeneratedAtUTC:     goldenGeneratedAtUTC,
+				RunbookConfigPath:  "ops/runbook.json",
+				SLOGatesReportPath: ".codex/ops/slo-gates-report.json",
+				Mode:               runbook.ModeSuggest,
+				Violations:         []string{"first_output_p95_ms exceeded"},
+				Decisions: []runbook.Decision{
+					{ActionID: "shift-stt", Kind: runbook.ActionShiftProviderWeights, Status: runbook.StatusSuggested, Violation: "first_output_p95_ms exceeded"},
+					{ActionID: "scale-hint", Kind: runbook.ActionScaleHintWebhook, Status: runbook.StatusExecutorUnavailable, Violation: "first_output_p95_ms exceeded", Error: "no executor configured"},
+				},
+			})
+		}},
+		{name: "llm-eval-fail", render: func() string {
+			return renderLLMEvalSummary(llmEvalReportArtifact{
+				GeneratedAtUTC: goldenGeneratedAtUTC,
+				SuitePath:      "test/llm/eval-suite.json",
+				Report: llmeval.Report{
+					Providers: []llmeval.ProviderResult{
+						{ProviderID: "llm-a", MeanScore: 0.91, PassRate: 1, Passed: true},
+						{ProviderID: "llm-b", MeanScore: 0.42, PassRate: 0.5},
+					},
+					Violations: []string{"llm-b pass rate 0.500 below 0.800"},
+				},
+			})
+		}},
+		{name: "redaction-eval-fail", render: func() string {
+			return renderRedactionEvalSummary(redactionEvalReportArtifact{
+				GeneratedAtUTC: goldenGeneratedAtUTC,
+				SuitePath:      redactioneval.DefaultSuitePath,
+				Report: redactioneval.Report{
+					Fixtures: 3,
+					Classes: []redactioneval.ClassResult{
+						{Class: eventabi.PayloadPII, Thresholds: redactioneval.Thresholds{MinPrecision: 0.9, MinRecall: 0.9}, TruePositives: 4, Precision: 1, Recall: 1, Passed: true},
+						{Class: eventabi.PayloadPHI, Thresholds: redactioneval.Thresholds{MinPrecision: 0.9, MinRecall: 0.9}, TruePositives: 1, FalsePositives: 1, FalseNegatives: 1, Precision: 0.5, Recall: 0.5},
+					},
+					Misses: []redactioneval.Miss{
+						{FixtureID: "phi-1", Kind: "false_negative", Class: eventabi.PayloadPHI, Text: "celiac disease"},
+						{FixtureID: "phi-2", Kind: "false_positive", Class: eventabi.PayloadPHI, Rule: "health_condition", Text: "depression"},
+					},
+					Violations: []string{"class PHI precision=0.500 recall=0.500 below thresholds min_precision=0.900 min_recall=0.900"},
+				},
+			})
+		}},
+		{name: "contract-coverage", render: func() string {
+			return renderContractCoverageSummary(contractCoverageReportArtifact{
+				GeneratedAtUTC: goldenGeneratedAtUTC,
+				FixtureRoot:    "test/contract/fixtures",
+				Report: validation.ContractCoverageReport{
+					Dimensions: []validation.DimensionCoverage{
+						{Name: "lane", Total: 3, Covered: 3},
+						{Name: "outcome_kind", Total: 6, Covered: 5, Uncovered: []string{"shed"}},
+					},
+					Fields: []validation.DimensionCoverage{
+						{Name: "turn_transition", Total: 4, Covered: 2, Uncovered: []string{"reason", "trigger"}},
+					},
+					TotalElements: 13,
+					Covered:       10,
+					CoverageRatio: 10.0 / 13.0,
+					MinRatio:      validation.DefaultMinCoverageRatio,
+					Passed:        true,
+				},
+			})
+		}},
+		{name: "contracts-report-fail", render: func() string {
+			return renderContractsReportSummary(contractsReportArtifact{
+				GeneratedAtUTC: goldenGeneratedAtUTC,
+				FixtureRoot:    "test/contract/fixtures",
+				Summary: validation.ContractValidationSummary{
+					Total:    12,
+					Failed:   1,
+					Failures: []string{"event/invalid/missing-lane.json: expected validation failure"},
+				},
+			})
+		}},
+		{name: "spec-report-fail", render: func() string {
+			return renderSpecReportSummary(specReportArtifact{
+				GeneratedAtUTC: goldenGeneratedAtUTC,
+				SpecPath:       "test/spec/fixtures/budgeted-pipeline.json",
+				Report: validation.SpecValidationReport{
+					PipelineVersion: "pipeline-v1",
+					Nodes:           3,
+					BudgetedNodes:   2,
+					Findings: []validation.SpecFinding{
+						{NodeID: "llm", ProviderID: "llm-anthropic", Code: validation.SpecFindingLatencyBudgetUnmet, Detail: "budget max_first_output_latency_ms=200 below provider typical 600ms"},
+						{NodeID: "tts", ProviderID: "tts-elevenlabs", Code: validation.SpecFindingCostBudgetUnmet, Detail: "budget max_cost_per_turn_usd=0.0010 below provider typical $0.0060"},
+					},
+				},
+			})
+		}},
+		{name: "bindings-report-fail", render: func() string {
+			return renderBindingsReportSummary(bindingsReportArtifact{
+				GeneratedAtUTC: goldenGeneratedAtUTC,
+				SpecPath:       "test/spec/fixtures/budgeted-pipeline.json",
+				Report: validation.BindingValidationReport{
+					PipelineVersion: "pipeline-v1",
+					Nodes:           3,
+					StreamingNodes:  1,
+					Bindings: []validation.NodeBinding{
+						{NodeID: "stt", Modality: "stt", ProviderID: "stt-deepgram", Status: validation.BindingStatusAvailable, RequiresStreaming: true},
+						{NodeID: "llm", Modality: "llm", ProviderID: "llm-gemini", Status: validation.BindingStatusAvailable},
+						{NodeID: "tts", Modality: "tts", ProviderID: "tts-azure-speech", Status: validation.BindingStatusDisabled},
+					},
+					Findings: []validation.SpecFinding{
+						{NodeID: "stt", ProviderID: "stt-deepgram", Code: validation.SpecFindingStreamingUnsupported, Detail: "node requires streaming but stt adapter stt-deepgram only supports unary invocation"},
+						{NodeID: "tts", ProviderID: "tts-azure-speech", Code: validation.SpecFindingProviderDisabled, Detail: "tts provider tts-azure-speech is disabled; set RSPP_TTS_AZURE_ENABLE to enable it"},
+					},
+				},
+			})
+		}},
+		{name: "release-manifest", render: func() string {
+			return renderReleaseManifestSummary(toolingrelease.ReleaseManifest{
+				ReleaseID:      "rel-golden",
+				GeneratedAtUTC: goldenGeneratedAtUTC,
+				SpecRef:        "specs/voice-agent@v2",
+				RolloutConfig: toolingrelease.RolloutConfig{
+					PipelineVersion: "pipeline-v2",
+					Strategy:        "canary",
+					RollbackPosture: toolingrelease.RollbackPosture{Mode: "automatic", Trigger: "slo_breach"},
+				},
+				Readiness: toolingrelease.ReadinessResult{
+					Checks: []toolingrelease.GateStatus{
+						{Name: "contracts", Path: ".codex/ops/contracts-report.json", Passed: true},
+						{Name: "slo_gates", Path: ".codex/ops/slo-gates-report.json", Reason: "artifact older than max age"},
+					},
+					Violations: []string{"slo_gates: artifact older than max age"},
+				},
+				SourceArtifacts: map[string]toolingrelease.ArtifactSource{
+					"slo_gates": {Path: ".codex/ops/slo-gates-report.json", SHA256: "bbbb"},
+					"contracts": {Path: ".codex/ops/contracts-report.json", SHA256: "aaaa", GeneratedAtUTC: goldenGeneratedAtUTC},
+				},
+			})
+		}},
+	}
+}
+
+func TestRenderersMatchGoldens(t *testing.T) {
+	t.Parallel()
+
+	if *updateGoldens {
+		if err := os.MkdirAll(goldenDir, 0o755); err != nil {
+			t.Fatalf("unexpected golden dir error: %v", err)
+		}
+	}
+	for _, tc := range renderGoldenCases() {
+		path := filepath.Join(goldenDir, tc.name+".md")
+		got := tc.render()
+		if *updateGoldens {
+			if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+				t.Fatalf("%s: write golden: %v", tc.name, err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: read golden: %v (run go test ./cmd/rspp-cli -run TestRenderersMatchGoldens -update)", tc.name, err)
+		}
+		if got != string(want) {
+			t.Fatalf("%s: rendered summary differs from golden; if intentional run go test ./cmd/rspp-cli -run TestRenderersMatchGoldens -update and review the diff\n--- got ---\n%s--- want ---\n%s", tc.name, got, want)
+		}
+	}
+}
+
+func TestGoldenFilesHaveRenderCases(t *testing.T) {
+	t.Parallel()
+
+	entries, err := os.ReadDir(goldenDir)
+	if err != nil {
+		t.Fatalf("unexpected read dir error: %v", err)
+	}
+	onDisk := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		onDisk = append(onDisk, strings.TrimSuffix(entry.Name(), ".md"))
+	}
+	cases := make([]string, 0)
+	for _, tc := range renderGoldenCases() {
+		cases = append(cases, tc.name)
+	}
+	sort.Strings(cases)
+	if strings.Join(onDisk, ",") != strings.Join(cases, ",") {
+		t.Fatalf("expected golden files %v, got %v", cases, onDisk)
+	}
+}
@@ -1,23 +1,57 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
 	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/specstore"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/cost"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/decisionindex"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/recording"
 	replaycmp "github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/diagnostics"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/localadmission"
+	providerbootstrap "github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/bootstrap"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	providerregistry "github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/turnarbiter"
+	"github.com/tiger/realtime-speech-pipeline/internal/security/piidetect"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/exitcode"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/alerting"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/artifactbundle"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/artifactwriter"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/completion"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/compliance"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/fixturesynth"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/llmeval"
 	"github.com/tiger/realtime-speech-pipeline/internal/tooling/ops"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/providerbench"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/redactioneval"
 	"github.com/tiger/realtime-speech-pipeline/internal/tooling/regression"
 	toolingrelease "github.com/tiger/realtime-speech-pipeline/internal/tooling/release"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/replayshell"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/reportsink"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/runbook"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/schemaregistry"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/scrub"
 	"github.com/tiger/realtime-speech-pipeline/internal/tooling/validation"
 )
 
@@ -30,7 +64,35 @@ const (
 	replayFixtureReportsDirName              = "fixtures"
 	defaultRuntimeBaselineArtifactPath       = ".codex/replay/runtime-baseline.json"
 	defaultContractsReportPath               = ".codex/ops/contracts-report.json"
+	defaultContractCoverageReportPath        = ".codex/ops/contract-coverage-report.json"
+	defaultSpecReportPath                    = ".codex/ops/spec-report.json"
+	defaultSpecLintReportPath                = ".codex/ops/spec-lint-report.json"
+	defaultBindingsReportPath                = ".codex/ops/spec-bindings-report.json"
 	defaultSLOGatesReportPath                = ".codex/ops/slo-gates-report.json"
+	defaultLLMEvalReportPath                 = ".codex/ops/llm-eval-report.json"
+	defaultRedactionEvalReportPath           = ".codex/ops/redaction-eval-report.json"
+	defaultFailureDomainReportPath           = ".codex/ops/failure-domain-report.json"
+	defaultSLAReportPath                     = ".codex/ops/sla-report.json"
+	defaultCostReportPath                    = ".codex/ops/cost-report.json"
+	defaultRecordingExportDir                = ".codex/recordings"
+	defaultDebugBundlePath                   = ".codex/ops/debug-bundle.json"
+	defaultExplainDecisionPath               = ".codex/ops/explain-decision.json"
+	defaultDecisionReplayReportPath          = ".codex/replay/decision-replay-report.json"
+	defaultArtifactImportDir                 = ".codex/artifact-imports"
+	defaultComplianceReportPath              = ".codex/ops/compliance-report.json"
+	defaultProviderBenchmarkPath             = ".codex/ops/provider-benchmark.json"
+	defaultAlertsReportPath                  = ".codex/ops/alerts.json"
+	envRecordingExportPrincipal              = "RSPP_RECORDING_EXPORT_PRINCIPAL_ID"
+	envRecordingExportRole                   = "RSPP_RECORDING_EXPORT_ROLE"
+	envRecordingExportPurpose                = "RSPP_RECORDING_EXPORT_PURPOSE"
+	// envSTTQualityTranscriber selects the STT quality gate transcriber: providers (default) or
+	// the offline recorded mode.
+	envSTTQualityTranscriber = "RSPP_STT_QUALITY_TRANSCRIBER"
+	// envLLMEvalResponder selects the LLM eval responder: providers (default) or the offline
+	// recorded mode.
+	envLLMEvalResponder = "RSPP_LLM_EVAL_RESPONDER"
+	// envLLMEvalJudgeProvider names the LLM provider that judges eval responses in providers mode.
+	envLLMEvalJudgeProvider = "RSPP_LLM_EVAL_JUDGE_PROVIDER"
 )
 
 func main() {
@@ -39,6 +101,12 @@ func main() {
 		return
 	}
 
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(int(exitcode.UsageError))
+	}
+
 	switch os.Args[1] {
 	case "validate-contracts":
 		fixtureRoot := filepath.Join("test", "contract", "fixtures")
@@ -48,30 +116,118 @@ func main() {
 		summary, err := validation.ValidateContractFixtures(fixtureRoot)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "contract validation failed to execute: %v\n", err)
-			os.Exit(1)
+			os.Exit(int(exitcode.For(err)))
 		}
 		fmt.Println(validation.RenderSummary(summary))
 		if summary.Failed > 0 {
-			os.Exit(1)
+			os.Exit(int(exitcode.GateFailure))
+		}
+	case "validate-arbiter-fixtures":
+		fixtureRoot := filepath.Join("test", "arbiter", "fixtures")
+		if len(os.Args) >= 3 {
+			fixtureRoot = os.Args[2]
+		}
+		summary, err := turnarbiter.ValidateFixtures(fixtureRoot, nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "arbiter fixture validation failed to execute: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		fmt.Println(turnarbiter.RenderFixtureSummary(summary))
+		if summary.Failed > 0 {
+			os.Exit(int(exitcode.GateFailure))
 		}
 	case "validate-contracts-report":
 		fixtureRoot := filepath.Join("test", "contract", "fixtures")
-		outputPath := defaultContractsReportPath
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultContractsReportPath)
 		if len(os.Args) >= 3 {
 			fixtureRoot = os.Args[2]
 		}
 		if len(os.Args) >= 4 {
 			outputPath = os.Args[3]
 		}
-		if err := writeContractsReport(outputPath, fixtureRoot); err != nil {
+		err := writeContractsReport(outputPath, fixtureRoot)
+		publishGateReport("validate-contracts-report", environment, outputPath, err)
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to write contracts report: %v\n", err)
-			os.Exit(1)
+			os.Exit(int(exitcode.For(err)))
 		}
-		summaryPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".md"
+		summaryPath := summaryPathFor(outputPath)
 		fmt.Printf("contracts report written: %s\n", outputPath)
 		fmt.Printf("contracts summary written: %s\n", summaryPath)
+	case "contract-coverage-report":
+		fixtureRoot := filepath.Join("test", "contract", "fixtures")
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultContractCoverageReportPath)
+		minRatio := validation.DefaultMinCoverageRatio
+		if len(os.Args) >= 3 {
+			fixtureRoot = os.Args[2]
+		}
+		if len(os.Args) >= 4 {
+			outputPath = os.Args[3]
+		}
+		if len(os.Args) >= 5 {
+			parsed, err := strconv.ParseFloat(os.Args[4], 64)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "invalid min_ratio %q: %v\n", os.Args[4], err)
+				os.Exit(int(exitcode.UsageError))
+			}
+			minRatio = parsed
+		}
+		err := writeContractCoverageReport(outputPath, fixtureRoot, minRatio)
+		publishGateReport("contract-coverage-report", environment, outputPath, err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write contract coverage report: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		summaryPath := summaryPathFor(outputPath)
+		fmt.Printf("contract coverage report written: %s\n", outputPath)
+		fmt.Printf("contract coverage summary written: %s\n", summaryPath)
+	case "validate-spec":
+		args, lint, fix := parseSpecLintFlags(os.Args[2:])
+		if len(args) < 1 {
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultSpecReportPath)
+		if len(args) >= 2 {
+			outputPath = args[1]
+		}
+		if lint {
+			lintPath := toolingrelease.EnvironmentArtifactPath(environment, defaultSpecLintReportPath)
+			if err := writeSpecLintReport(lintPath, args[0], fix); err != nil {
+				fmt.Fprintf(os.Stderr, "spec lint failed: %v\n", err)
+				os.Exit(int(exitcode.For(err)))
+			}
+			fmt.Printf("spec lint report written: %s\n", lintPath)
+			fmt.Printf("spec lint summary written: %s\n", summaryPathFor(lintPath))
+		}
+		err := writeSpecReport(outputPath, args[0])
+		publishGateReport("validate-spec", environment, outputPath, err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "spec validation failed: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		summaryPath := summaryPathFor(outputPath)
+		fmt.Printf("spec report written: %s\n", outputPath)
+		fmt.Printf("spec summary written: %s\n", summaryPath)
+	case "validate-bindings":
+		if len(os.Args) < 3 {
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultBindingsReportPath)
+		if len(os.Args) >= 4 {
+			outputPath = os.Args[3]
+		}
+		err := writeBindingsReport(outputPath, os.Args[2])
+		publishGateReport("validate-bindings", environment, outputPath, err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "binding validation failed: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		fmt.Printf("binding report written: %s\n", outputPath)
+		fmt.Printf("binding summary written: %s\n", summaryPathFor(outputPath))
 	case "replay-smoke-report":
-		outputPath := filepath.Join(".codex", "replay", "smoke-report.json")
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, filepath.Join(".codex", "replay", "smoke-report.json"))
 		metadataPath := defaultReplayMetadataPath
 		if len(os.Args) >= 3 {
 			outputPath = os.Args[2]
@@ -79,15 +235,17 @@ func main() {
 		if len(os.Args) >= 4 {
 			metadataPath = os.Args[3]
 		}
-		if err := writeReplaySmokeReport(outputPath, metadataPath); err != nil {
+		err := writeReplaySmokeReport(outputPath, metadataPath, "")
+		publishGateReport("replay-smoke-report", environment, outputPath, err)
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to write replay smoke report: %v\n", err)
-			os.Exit(1)
+			os.Exit(int(exitcode.For(err)))
 		}
-		summaryPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".md"
+		summaryPath := summaryPathFor(outputPath)
 		fmt.Printf("replay smoke report written: %s\n", outputPath)
 		fmt.Printf("replay smoke summary written: %s\n", summaryPath)
 	case "replay-regression-report":
-		outputPath := defaultReplayRegressionReportPath
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultReplayRegressionReportPath)
 		metadataPath := defaultReplayMetadataPath
 		gate := replayRegressionDefaultGate
 		if len(os.Args) >= 3 {
@@ -99,51 +257,341 @@ func main() {
 		if len(os.Args) >= 5 {
 			gate = os.Args[4]
 		}
-		if err := writeReplayRegressionReport(outputPath, metadataPath, gate); err != nil {
+		err := writeReplayRegressionReport(outputPath, metadataPath, gate)
+		publishGateReport("replay-regression-report", environment, outputPath, err)
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to write replay regression report: %v\n", err)
-			os.Exit(1)
+			os.Exit(int(exitcode.For(err)))
 		}
-		summaryPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".md"
+		summaryPath := summaryPathFor(outputPath)
 		fmt.Printf("replay regression report written: %s\n", outputPath)
 		fmt.Printf("replay regression summary written: %s\n", summaryPath)
 	case "generate-runtime-baseline":
-		outputPath := defaultRuntimeBaselineArtifactPath
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultRuntimeBaselineArtifactPath)
 		if len(os.Args) >= 3 {
 			outputPath = os.Args[2]
 		}
 		if err := writeRuntimeBaselineArtifact(outputPath); err != nil {
 			fmt.Fprintf(os.Stderr, "failed to generate runtime baseline artifact: %v\n", err)
-			os.Exit(1)
+			os.Exit(int(exitcode.For(err)))
 		}
 		fmt.Printf("runtime baseline artifact written: %s\n", outputPath)
 	case "slo-gates-report":
-		outputPath := defaultSLOGatesReportPath
-		baselineArtifactPath := defaultRuntimeBaselineArtifactPath
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultSLOGatesReportPath)
+		baselineArtifactPath := toolingrelease.EnvironmentArtifactPath(environment, defaultRuntimeBaselineArtifactPath)
 		if len(os.Args) >= 3 {
 			outputPath = os.Args[2]
 		}
+		qualityCorpusPath := ops.DefaultSTTGoldenCorpusPath
 		if len(os.Args) >= 4 {
 			baselineArtifactPath = os.Args[3]
 		}
-		if err := writeSLOGatesReport(outputPath, baselineArtifactPath); err != nil {
+		if len(os.Args) >= 5 {
+			qualityCorpusPath = os.Args[4]
+		}
+		err := writeSLOGatesReportWithQuality(outputPath, baselineArtifactPath, qualityCorpusPath, os.Getenv(envSTTQualityTranscriber))
+		publishGateReport("slo-gates-report", environment, outputPath, err)
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to write slo gates report: %v\n", err)
-			os.Exit(1)
+			os.Exit(int(exitcode.For(err)))
 		}
-		summaryPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".md"
+		summaryPath := summaryPathFor(outputPath)
 		fmt.Printf("slo gates report written: %s\n", outputPath)
 		fmt.Printf("slo gates summary written: %s\n", summaryPath)
+	case "llm-eval-report":
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultLLMEvalReportPath)
+		suitePath := llmeval.DefaultSuitePath
+		if len(os.Args) >= 3 {
+			outputPath = os.Args[2]
+		}
+		if len(os.Args) >= 4 {
+			suitePath = os.Args[3]
+		}
+		err := writeLLMEvalReport(outputPath, suitePath, os.Getenv(envLLMEvalResponder), os.Getenv(envLLMEvalJudgeProvider))
+		publishGateReport("llm-eval-report", environment, outputPath, err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write llm eval report: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		summaryPath := summaryPathFor(outputPath)
+		fmt.Printf("llm eval report written: %s\n", outputPath)
+		fmt.Printf("llm eval summary written: %s\n", summaryPath)
+	case "redaction-eval-report":
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultRedactionEvalReportPath)
+		suitePath := redactioneval.DefaultSuitePath
+		if len(os.Args) >= 3 {
+			outputPath = os.Args[2]
+		}
+		if len(os.Args) >= 4 {
+			suitePath = os.Args[3]
+		}
+		err := writeRedactionEvalReport(outputPath, suitePath)
+		publishGateReport("redaction-eval-report", environment, outputPath, err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write redaction eval report: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		summaryPath := summaryPathFor(outputPath)
+		fmt.Printf("redaction eval report written: %s\n", outputPath)
+		fmt.Printf("redaction eval summary written: %s\n", summaryPath)
+	case "failure-domain-report":
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultFailureDomainReportPath)
+		baselineArtifactPath := toolingrelease.EnvironmentArtifactPath(environment, defaultRuntimeBaselineArtifactPath)
+		if len(os.Args) >= 3 {
+			outputPath = os.Args[2]
+		}
+		if len(os.Args) >= 4 {
+			baselineArtifactPath = os.Args[3]
+		}
+		if err := writeFailureDomainReport(outputPath, baselineArtifactPath, ops.DefaultFailureDomainBucketMS); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write failure domain report: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		summaryPath := summaryPathFor(outputPath)
+		fmt.Printf("failure domain report written: %s\n", outputPath)
+		fmt.Printf("failure domain summary written: %s\n", summaryPath)
+	case "sla-report":
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultSLAReportPath)
+		baselineArtifactPath := toolingrelease.EnvironmentArtifactPath(environment, defaultRuntimeBaselineArtifactPath)
+		periodMS := ops.DefaultSLAReportPeriodMS
+		if len(os.Args) >= 3 {
+			outputPath = os.Args[2]
+		}
+		if len(os.Args) >= 4 {
+			baselineArtifactPath = os.Args[3]
+		}
+		if len(os.Args) >= 5 {
+			parsed, err := strconv.ParseInt(os.Args[4], 10, 64)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "invalid sla report period_ms %q: %v\n", os.Args[4], err)
+				os.Exit(int(exitcode.UsageError))
+			}
+			periodMS = parsed
+		}
+		if err := writeSLAReport(outputPath, baselineArtifactPath, periodMS); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write sla report: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		summaryPath := summaryPathFor(outputPath)
+		fmt.Printf("sla report written: %s\n", outputPath)
+		fmt.Printf("sla summary written: %s\n", summaryPath)
+	case "cost-report":
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultCostReportPath)
+		baselineArtifactPath := toolingrelease.EnvironmentArtifactPath(environment, defaultRuntimeBaselineArtifactPath)
+		if len(os.Args) >= 3 {
+			outputPath = os.Args[2]
+		}
+		if len(os.Args) >= 4 {
+			baselineArtifactPath = os.Args[3]
+		}
+		if err := writeCostReport(outputPath, baselineArtifactPath); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write cost report: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		summaryPath := summaryPathFor(outputPath)
+		fmt.Printf("cost report written: %s\n", outputPath)
+		fmt.Printf("cost summary written: %s\n", summaryPath)
+	case "debug-bundle":
+		if len(os.Args) < 3 {
+			fmt.Fprintln(os.Stderr, "debug-bundle requires session_id")
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		query := decisionindex.Query{SessionID: os.Args[2]}
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultDebugBundlePath)
+		baselineArtifactPath := toolingrelease.EnvironmentArtifactPath(environment, defaultRuntimeBaselineArtifactPath)
+		if len(os.Args) >= 4 {
+			query.TurnID = os.Args[3]
+		}
+		if len(os.Args) >= 5 {
+			outputPath = os.Args[4]
+		}
+		if len(os.Args) >= 6 {
+			baselineArtifactPath = os.Args[5]
+		}
+		if err := writeDebugBundle(outputPath, baselineArtifactPath, diagnostics.AdminSocketPathFromEnv(), query); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write debug bundle: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		summaryPath := summaryPathFor(outputPath)
+		fmt.Printf("debug bundle written: %s\n", outputPath)
+		fmt.Printf("debug bundle summary written: %s\n", summaryPath)
+	case "explain-decision":
+		if len(os.Args) < 4 {
+			fmt.Fprintln(os.Stderr, "explain-decision requires baseline_artifact_path and event_id")
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultExplainDecisionPath)
+		if len(os.Args) >= 5 {
+			outputPath = os.Args[4]
+		}
+		summary, err := writeExplainDecision(outputPath, os.Args[2], os.Args[3])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to explain decision: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		fmt.Print(summary)
+		fmt.Printf("decision explanation written: %s\n", outputPath)
+	case "replay-decisions":
+		args, filter, cursor, err := parseReplayDecisionFlags(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "replay-decisions: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		if len(args) < 1 {
+			fmt.Fprintln(os.Stderr, "replay-decisions requires baseline_artifact_path")
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		candidatePath := ""
+		if len(args) >= 2 {
+			candidatePath = args[1]
+		}
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultDecisionReplayReportPath)
+		if len(args) >= 3 {
+			outputPath = args[2]
+		}
+		summary, err := writeDecisionReplayReport(outputPath, args[0], candidatePath, filter, cursor)
+		if summary != "" {
+			fmt.Print(summary)
+			fmt.Printf("decision replay report written: %s\n", outputPath)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "decision replay failed: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+	case "replay-shell":
+		if len(os.Args) < 3 {
+			fmt.Fprintln(os.Stderr, "replay-shell requires baseline_artifact_path")
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		candidatePath := ""
+		if len(os.Args) >= 4 {
+			candidatePath = os.Args[3]
+		}
+		if err := runReplayShell(os.Args[2], candidatePath, os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "replay shell failed: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+	case "synthesize-fixture":
+		if len(os.Args) < 5 {
+			fmt.Fprintln(os.Stderr, "synthesize-fixture requires fixture_id, baseline_artifact_path, and candidate_artifact_path")
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		metadataPath := defaultReplayMetadataPath
+		if len(os.Args) >= 6 {
+			metadataPath = os.Args[5]
+		}
+		source := ""
+		if len(os.Args) >= 7 {
+			source = os.Args[6]
+		}
+		summary, err := synthesizeReplayFixture(os.Args[2], os.Args[3], os.Args[4], metadataPath, source)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to synthesize replay fixture: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		fmt.Print(summary)
+	case "runbook-report":
+		if len(os.Args) < 3 {
+			fmt.Fprintln(os.Stderr, "runbook-report requires runbook_cfg_path")
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		runbookConfigPath := os.Args[2]
+		sloGatesReportPath := toolingrelease.EnvironmentArtifactPath(environment, defaultSLOGatesReportPath)
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, runbook.DefaultDecisionsPath)
+		if len(os.Args) >= 4 {
+			sloGatesReportPath = os.Args[3]
+		}
+		if len(os.Args) >= 5 {
+			outputPath = os.Args[4]
+		}
+		if err := writeRunbookDecisions(outputPath, runbookConfigPath, sloGatesReportPath, diagnostics.AdminSocketPathFromEnv(), time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to evaluate runbook: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		summaryPath := summaryPathFor(outputPath)
+		fmt.Printf("runbook decisions written: %s\n", outputPath)
+		fmt.Printf("runbook summary written: %s\n", summaryPath)
+	case "compliance-report":
+		if len(os.Args) < 5 {
+			fmt.Fprintln(os.Stderr, "compliance-report requires inputs_cfg_path, window_start, and window_end (RFC 3339)")
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultComplianceReportPath)
+		if len(os.Args) >= 6 {
+			outputPath = os.Args[5]
+		}
+		report, err := writeComplianceReport(outputPath, os.Args[2], os.Args[3], os.Args[4], environment, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write compliance report: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		summaryPath := summaryPathFor(outputPath)
+		fmt.Printf("compliance report written: %s (%d tenants, %d exceptions)\n", outputPath, len(report.Tenants), len(report.Exceptions))
+		fmt.Printf("compliance summary written: %s\n", summaryPath)
+	case "provider-benchmark":
+		if len(os.Args) < 4 {
+			fmt.Fprintln(os.Stderr, "provider-benchmark requires window_start and window_end (RFC 3339 or YYYY-MM-DD)")
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultProviderBenchmarkPath)
+		if len(os.Args) >= 5 {
+			outputPath = os.Args[4]
+		}
+		baselineArtifactPaths := []string{toolingrelease.EnvironmentArtifactPath(environment, defaultRuntimeBaselineArtifactPath)}
+		if len(os.Args) >= 6 {
+			baselineArtifactPaths = os.Args[5:]
+		}
+		report, err := writeProviderBenchmark(outputPath, baselineArtifactPaths, os.Args[2], os.Args[3], environment, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write provider benchmark: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		summaryPath := summaryPathFor(outputPath)
+		fmt.Printf("provider benchmark written: %s (%d turns, %d modalities)\n", outputPath, report.TurnsInWindow, len(report.Modalities))
+		fmt.Printf("provider benchmark summary written: %s\n", summaryPath)
+	case "evaluate-alerts":
+		if len(os.Args) < 3 {
+			fmt.Fprintln(os.Stderr, "evaluate-alerts requires rules_path")
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, defaultAlertsReportPath)
+		if len(os.Args) >= 4 {
+			outputPath = os.Args[3]
+		}
+		report, err := writeAlertsReport(outputPath, os.Args[2], environment, time.Now())
+		if err == nil && !report.Passed {
+			err = exitcode.Gatef("%d critical alert(s) firing", report.Firing[alerting.SeverityCritical])
+		}
+		publishGateReport("evaluate-alerts", environment, outputPath, err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "evaluate alerts failed: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		summaryPath := summaryPathFor(outputPath)
+		fmt.Printf("alerts written: %s (%d rules, %d warning, %d info firing)\n", outputPath, report.Evaluated, report.Firing[alerting.SeverityWarning], report.Firing[alerting.SeverityInfo])
+		fmt.Printf("alerts summary written: %s\n", summaryPath)
 	case "publish-release":
 		if len(os.Args) < 4 {
 			fmt.Fprintln(os.Stderr, "publish-release requires spec_ref and rollout_cfg_path")
 			printUsage()
-			os.Exit(2)
+			os.Exit(int(exitcode.UsageError))
 		}
 		specRef := os.Args[2]
 		rolloutConfigPath := os.Args[3]
-		outputPath := toolingrelease.DefaultReleaseManifestPath
-		contractsReportPath := defaultContractsReportPath
-		replayRegressionReportPath := defaultReplayRegressionReportPath
-		sloGatesReportPath := defaultSLOGatesReportPath
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, toolingrelease.DefaultReleaseManifestPath)
+		contractsReportPath := toolingrelease.EnvironmentArtifactPath(environment, defaultContractsReportPath)
+		replayRegressionReportPath := toolingrelease.EnvironmentArtifactPath(environment, defaultReplayRegressionReportPath)
+		sloGatesReportPath := toolingrelease.EnvironmentArtifactPath(environment, defaultSLOGatesReportPath)
+		llmEvalReportPath := ""
 		if len(os.Args) >= 5 {
 			outputPath = os.Args[4]
 		}
@@ -156,6 +604,9 @@ func main() {
 		if len(os.Args) >= 8 {
 			sloGatesReportPath = os.Args[7]
 		}
+		if len(os.Args) >= 9 {
+			llmEvalReportPath = os.Args[8]
+		}
 		manifest, err := writeReleaseManifest(
 			outputPath,
 			specRef,
@@ -163,54 +614,284 @@ func main() {
 			contractsReportPath,
 			replayRegressionReportPath,
 			sloGatesReportPath,
+			llmEvalReportPath,
 			time.Now(),
 		)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to publish release: %v\n", err)
-			os.Exit(1)
+			os.Exit(int(exitcode.For(err)))
 		}
-		summaryPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".md"
+		summaryPath := summaryPathFor(outputPath)
 		fmt.Printf("release manifest written: %s\n", outputPath)
 		fmt.Printf("release summary written: %s\n", summaryPath)
 		fmt.Printf("release id: %s\n", manifest.ReleaseID)
+		if manifest.BreakGlass != nil {
+			fmt.Printf("BREAK-GLASS: gates %s bypassed by %s (audit entry %d: %s)\n", strings.Join(manifest.BreakGlass.BypassedGates, ","), manifest.BreakGlass.Operator, manifest.BreakGlass.AuditSequence, manifest.BreakGlass.AuditEntryHash)
+		}
+		if manifest.SpecHash != "" {
+			fmt.Printf("spec hash: %s\n", manifest.SpecHash)
+		}
+		if manifest.BreakGlass != nil {
+			os.Exit(int(exitcode.Partial))
+		}
+	case "get-spec":
+		if len(os.Args) < 3 {
+			fmt.Fprintln(os.Stderr, "get-spec requires spec_hash")
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		outputPath := ""
+		if len(os.Args) >= 4 {
+			outputPath = os.Args[3]
+		}
+		if err := writePublishedSpec(os.Args[2], outputPath, environment, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "get-spec failed: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		if outputPath != "" {
+			fmt.Printf("spec written: %s\n", outputPath)
+		}
+	case "execute-rollback":
+		if len(os.Args) < 3 {
+			fmt.Fprintln(os.Stderr, "execute-rollback requires manifest_path")
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		manifestPath := os.Args[2]
+		distributionPath := strings.TrimSpace(os.Getenv(distribution.EnvFileAdapterPath))
+		outputPath := toolingrelease.EnvironmentArtifactPath(environment, toolingrelease.DefaultRollbackReportPath)
+		metadataPath := defaultReplayMetadataPath
+		if len(os.Args) >= 4 {
+			distributionPath = os.Args[3]
+		}
+		if len(os.Args) >= 5 {
+			outputPath = os.Args[4]
+		}
+		if len(os.Args) >= 6 {
+			metadataPath = os.Args[5]
+		}
+		report, err := writeRollbackReport(outputPath, manifestPath, distributionPath, metadataPath, time.Now())
+		summaryPath := summaryPathFor(outputPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "rollback failed: %v\n", err)
+			if report.ReleaseID != "" {
+				fmt.Fprintf(os.Stderr, "rollback report written: %s\n", outputPath)
+			}
+			os.Exit(int(exitcode.For(err)))
+		}
+		fmt.Printf("rollback report written: %s\n", outputPath)
+		fmt.Printf("rollback summary written: %s\n", summaryPath)
+		fmt.Printf("active pipeline version: %s\n", report.ToPipelineVersion)
+	case "export-recording":
+		if len(os.Args) < 3 {
+			fmt.Fprintln(os.Stderr, "export-recording requires session_audio_path")
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		sessionAudioPath := os.Args[2]
+		outputDir := toolingrelease.EnvironmentArtifactPath(environment, defaultRecordingExportDir)
+		format := recording.FormatWAV
+		if len(os.Args) >= 4 {
+			outputDir = os.Args[3]
+		}
+		if len(os.Args) >= 5 {
+			format = os.Args[4]
+		}
+		if format != recording.FormatWAV && format != recording.FormatOGG {
+			fmt.Fprintf(os.Stderr, "export-recording: unsupported format %q (supported: %s, %s)\n", format, recording.FormatWAV, recording.FormatOGG)
+			os.Exit(int(exitcode.UsageError))
+		}
+		var captionFormats []string
+		if len(os.Args) >= 6 {
+			captionFormats = strings.Split(os.Args[5], ",")
+		}
+		sidecar, err := writeRecordingExport(sessionAudioPath, outputDir, format, captionFormats)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "recording export failed: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		for _, track := range sidecar.Tracks {
+			fmt.Printf("recording track written: %s\n", filepath.Join(outputDir, track.FileName))
+		}
+		for _, name := range sidecar.CaptionFiles {
+			fmt.Printf("recording captions written: %s\n", filepath.Join(outputDir, name))
+		}
+		fmt.Printf("recording transcript written: %s\n", filepath.Join(outputDir, recording.SidecarFileName(sidecar.SessionID)))
+	case "scrub-artifact":
+		if len(os.Args) < 3 {
+			fmt.Fprintln(os.Stderr, "scrub-artifact requires input_path")
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		inputPath := os.Args[2]
+		outputPath := shareablePathFor(inputPath)
+		if len(os.Args) >= 4 {
+			outputPath = os.Args[3]
+		}
+		report, err := writeScrubbedArtifact(inputPath, outputPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "artifact scrub failed: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		fmt.Printf("scrubbed artifact written: %s\n", outputPath)
+		fmt.Printf("identifiers=%d tokenized_fields=%d hashed_payloads=%d rewritten_strings=%d preserved_numerics=%d\n", report.Identifiers, report.TokenizedFields, report.HashedPayloads, report.RewrittenStrings, report.PreservedNumerics)
+	case "artifacts":
+		if len(os.Args) < 4 || (os.Args[2] != "export" && os.Args[2] != "import") {
+			fmt.Fprintln(os.Stderr, "artifacts requires export <output_path> [path...] or import <bundle_path> [dest_dir]")
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		if os.Args[2] == "export" {
+			outputPath := os.Args[3]
+			manifest, err := writeArtifactBundle(outputPath, os.Args[4:], environment, time.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "artifact export failed: %v\n", err)
+				os.Exit(int(exitcode.For(err)))
+			}
+			printArtifactManifest(manifest)
+			fmt.Printf("artifact bundle written: %s\n", outputPath)
+			return
+		}
+		destDir := toolingrelease.EnvironmentArtifactPath(environment, defaultArtifactImportDir)
+		if len(os.Args) >= 5 {
+			destDir = os.Args[4]
+		}
+		manifest, err := artifactbundle.Import(os.Args[3], destDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "artifact import failed: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		printArtifactManifest(manifest)
+		fmt.Printf("artifact bundle imported: %s\n", destDir)
+	case "verify-artifacts":
+		indexPath := filepath.Join(".codex", artifactwriter.IndexFileName)
+		if len(os.Args) >= 3 {
+			indexPath = os.Args[2]
+		}
+		verification, err := artifactwriter.VerifyIndex(indexPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "artifact verification failed to execute: %v\n", err)
+			os.Exit(int(exitcode.For(err)))
+		}
+		fmt.Print(renderArtifactVerification(verification))
+		if !verification.Passed() {
+			os.Exit(int(exitcode.GateFailure))
+		}
+	case "completion":
+		if len(os.Args) < 3 {
+			fmt.Fprintln(os.Stderr, "completion requires bash|zsh|fish")
+			printUsage()
+			os.Exit(int(exitcode.UsageError))
+		}
+		script, err := completion.Script(os.Args[2], cliCompletionSpec())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "completion failed: %v\n", err)
+			os.Exit(int(exitcode.UsageError))
+		}
+		fmt.Print(script)
 	default:
 		printUsage()
-		os.Exit(2)
+		os.Exit(int(exitcode.UsageError))
 	}
 }
 
 func printUsage() {
 	fmt.Println("rspp-cli usage:")
 	fmt.Println("  rspp-cli validate-contracts [fixture_root]")
+	fmt.Println("  rspp-cli validate-arbiter-fixtures [fixture_root]")
 	fmt.Println("  rspp-cli validate-contracts-report [fixture_root] [output_path]")
+	fmt.Println("  rspp-cli contract-coverage-report [fixture_root] [output_path] [min_ratio]")
+	fmt.Println("  rspp-cli validate-spec <spec_path> [output_path] [--lint] [--fix]")
+	fmt.Println("  rspp-cli validate-bindings <spec_path> [output_path]")
 	fmt.Println("  rspp-cli replay-smoke-report [output_path] [metadata_path]")
 	fmt.Println("  rspp-cli replay-regression-report [output_path] [metadata_path] [gate]")
 	fmt.Println("  rspp-cli generate-runtime-baseline [output_path]")
-	fmt.Println("  rspp-cli slo-gates-report [output_path] [baseline_artifact_path]")
-	fmt.Println("  rspp-cli publish-release <spec_ref> <rollout_cfg_path> [output_path] [contracts_report_path] [replay_report_path] [slo_report_path]")
+	fmt.Println("  rspp-cli slo-gates-report [output_path] [baseline_artifact_path] [stt_golden_corpus_path]")
+	fmt.Println("  rspp-cli llm-eval-report [output_path] [suite_path]")
+	fmt.Println("  rspp-cli redaction-eval-report [output_path] [suite_path]")
+	fmt.Println("  rspp-cli failure-domain-report [output_path] [baseline_artifact_path]")
+	fmt.Println("  rspp-cli sla-report [output_path] [baseline_artifact_path] [period_ms]")
+	fmt.Println("  rspp-cli cost-report [output_path] [baseline_artifact_path]")
+	fmt.Println("  rspp-cli debug-bundle <session_id> [turn_id] [output_path] [baseline_artifact_path]")
+	fmt.Println("  rspp-cli explain-decision <baseline_artifact_path> <event_id> [output_path]")
+	fmt.Println("  rspp-cli replay-decisions <baseline_artifact_path> [candidate_cp_distribution_path] [output_path] [--session=id] [--turn-from=id] [--turn-to=id] [--lane=lane] [--class=CLASS,...] [--after-event=id] [--limit=n]")
+	fmt.Println("  rspp-cli replay-shell <baseline_artifact_path> [candidate_artifact_path]")
+	fmt.Println("  rspp-cli synthesize-fixture <fixture_id> <baseline_artifact_path> <candidate_artifact_path> [metadata_path] [source]")
+	fmt.Println("  rspp-cli runbook-report <runbook_cfg_path> [slo_report_path] [output_path]")
+	fmt.Println("  rspp-cli compliance-report <inputs_cfg_path> <window_start> <window_end> [output_path]")
+	fmt.Println("  rspp-cli provider-benchmark <window_start> <window_end> [output_path] [baseline_artifact_path...]")
+	fmt.Println("  rspp-cli evaluate-alerts <rules_path> [output_path]")
+	fmt.Println("  rspp-cli publish-release <spec_ref> <rollout_cfg_path> [output_path] [contracts_report_path] [replay_report_path] [slo_report_path] [llm_eval_report_path]")
+	fmt.Println("  rspp-cli get-spec <spec_hash> [output_path]")
+	fmt.Println("  rspp-cli execute-rollback <manifest_path> [cp_distribution_path] [output_path] [metadata_path]")
+	fmt.Println("  rspp-cli export-recording <session_audio_path> [output_dir] [wav|ogg] [vtt,srt]")
+	fmt.Println("  rspp-cli scrub-artifact <input_path> [output_path]")
+	fmt.Println("  rspp-cli artifacts export <output_path> [path...]")
+	fmt.Println("  rspp-cli artifacts import <bundle_path> [dest_dir]")
+	fmt.Println("  rspp-cli verify-artifacts [index_path]")
+	fmt.Println("  rspp-cli completion <bash|zsh|fish>")
+	fmt.Println("  RSPP_RECORDING_EXPORT_PRINCIPAL_ID and RSPP_RECORDING_EXPORT_ROLE identify the QA reviewer for export-recording")
+	fmt.Println("  RSPP_SCRUB_SALT=<secret> pins scrub-artifact tokens so separately scrubbed artifacts correlate; by default each run uses a fresh random salt")
+	fmt.Println("  RSPP_CP_SPEC_STORE_DIR=<dir> overrides the content-addressed spec store used by publish-release and get-spec")
+	fmt.Println("  RSPP_REPORT_SINKS_CONFIG=<path> publishes gate report artifacts to filesystem|s3|github_pr_comment|slack sinks")
+	fmt.Println("  RSPP_SUMMARY_PATH=<path> overrides the Markdown summary path; by default a .json report pairs with .md and any other output path gets .md appended")
+	fmt.Println("  RSPP_ENVIRONMENT=dev|staging|prod tags report artifacts and namespaces default .codex paths")
+	fmt.Println("  RSPP_BREAK_GLASS_OPERATOR, RSPP_BREAK_GLASS_TOKEN, RSPP_BREAK_GLASS_REASON, and RSPP_BREAK_GLASS_GATES=<gate,...> let an operator registered in RSPP_BREAK_GLASS_OPERATORS=<operator=sha256(token),...> publish-release past failing readiness gates; each use is appended to the hash-chained RSPP_BREAK_GLASS_AUDIT_LOG")
+}
+
+type contractCoverageReportArtifact struct {
+	GeneratedAtUTC string                            `json:"generated_at_utc"`
+	Environment    string                            `json:"environment,omitempty"`
+	FixtureRoot    string                            `json:"fixture_root"`
+	Report         validation.ContractCoverageReport `json:"report"`
+}
+
+type specLintReportArtifact struct {
+	GeneratedAtUTC string                    `json:"generated_at_utc"`
+	Environment    string                    `json:"environment,omitempty"`
+	SpecPath       string                    `json:"spec_path"`
+	Report         validation.SpecLintReport `json:"report"`
+}
+
+type specReportArtifact struct {
+	GeneratedAtUTC string                          `json:"generated_at_utc"`
+	Environment    string                          `json:"environment,omitempty"`
+	SpecPath       string                          `json:"spec_path"`
+	Report         validation.SpecValidationReport `json:"report"`
+}
+
+type bindingsReportArtifact struct {
+	GeneratedAtUTC    string                             `json:"generated_at_utc"`
+	Environment       string                             `json:"environment,omitempty"`
+	SpecPath          string                             `json:"spec_path"`
+	DisabledProviders []validation.DisabledProvider      `json:"disabled_providers,omitempty"`
+	Report            validation.BindingValidationReport `json:"report"`
 }
 
 type replaySmokeReport struct {
-	GeneratedAtUTC     string                 `json:"generated_at_utc"`
-	FixtureID          string                 `json:"fixture_id"`
-	MetadataPath       string                 `json:"metadata_path"`
-	TimingToleranceMS  int64                  `json:"timing_tolerance_ms"`
-	TotalDivergences   int                    `json:"total_divergences"`
-	ByClass            map[string]int         `json:"by_class"`
-	Divergences        []obs.ReplayDivergence `json:"divergences"`
-	FailingCount       int                    `json:"failing_count"`
-	UnexplainedCount   int                    `json:"unexplained_count"`
-	MissingExpected    int                    `json:"missing_expected"`
-	ExpectedConfigured int                    `json:"expected_configured"`
-	FailingDivergences []string               `json:"failing_divergences"`
-}
-
-func writeReplaySmokeReport(outputPath string, metadataPath string) error {
+	GeneratedAtUTC          string                 `json:"generated_at_utc"`
+	Environment             string                 `json:"environment,omitempty"`
+	PipelineVersion         string                 `json:"pipeline_version,omitempty"`
+	FixtureID               string                 `json:"fixture_id"`
+	MetadataPath            string                 `json:"metadata_path"`
+	TimingToleranceMS       int64                  `json:"timing_tolerance_ms"`
+	ScopeTimingTolerancesMS map[string]int64       `json:"scope_timing_tolerances_ms,omitempty"`
+	TotalDivergences        int                    `json:"total_divergences"`
+	ByClass                 map[string]int         `json:"by_class"`
+	Divergences             []obs.ReplayDivergence `json:"divergences"`
+	FailingCount            int                    `json:"failing_count"`
+	UnexplainedCount        int                    `json:"unexplained_count"`
+	MissingExpected         int                    `json:"missing_expected"`
+	ExpectedConfigured      int                    `json:"expected_configured"`
+	FailingDivergences      []string               `json:"failing_divergences"`
+}
+
+func writeReplaySmokeReport(outputPath string, metadataPath string, pipelineVersion string) error {
 	policy, effectiveTimingToleranceMS, err := loadReplayFixturePolicy(metadataPath, replaySmokeFixtureID, replaySmokeTimingToleranceMS)
 	if err != nil {
 		return err
 	}
-	divergences := buildReplaySmokeDivergences(effectiveTimingToleranceMS)
+	divergences := buildReplaySmokeDivergences(replaycmp.CompareConfig{TimingToleranceMS: effectiveTimingToleranceMS, ScopeTimingTolerancesMS: policy.ScopeTimingTolerancesMS})
 	evaluation := regression.EvaluateDivergences(divergences, policy)
 
 	byClass := map[string]int{
@@ -223,39 +904,38 @@ func writeReplaySmokeReport(outputPath string, metadataPath string) error {
 	for _, d := range divergences {
 		byClass[string(d.Class)]++
 	}
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return err
+	}
 	report := replaySmokeReport{
-		GeneratedAtUTC:     time.Now().UTC().Format(time.RFC3339),
-		FixtureID:          replaySmokeFixtureID,
-		MetadataPath:       metadataPath,
-		TimingToleranceMS:  effectiveTimingToleranceMS,
-		TotalDivergences:   len(divergences),
-		ByClass:            byClass,
-		Divergences:        divergences,
-		FailingCount:       len(evaluation.Failing),
-		UnexplainedCount:   len(evaluation.Unexplained),
-		MissingExpected:    len(evaluation.MissingExpected),
-		ExpectedConfigured: len(policy.Expected),
-		FailingDivergences: uniqueFailingClasses(evaluation.Failing),
+		Environment:             environment,
+		PipelineVersion:         pipelineVersion,
+		GeneratedAtUTC:          time.Now().UTC().Format(time.RFC3339),
+		FixtureID:               replaySmokeFixtureID,
+		MetadataPath:            metadataPath,
+		TimingToleranceMS:       effectiveTimingToleranceMS,
+		ScopeTimingTolerancesMS: policy.ScopeTimingTolerancesMS,
+		TotalDivergences:        len(divergences),
+		ByClass:                 byClass,
+		Divergences:             divergences,
+		FailingCount:            len(evaluation.Failing),
+		UnexplainedCount:        len(evaluation.Unexplained),
+		MissingExpected:         len(evaluation.MissingExpected),
+		ExpectedConfigured:      len(policy.Expected),
+		FailingDivergences:      uniqueFailingClasses(evaluation.Failing),
 	}
 
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(report, "", "  ")
+	pair, err := reportPair(outputPath)
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(outputPath, data, 0o644); err != nil {
-		return err
-	}
-
-	summaryPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".md"
-	if err := os.WriteFile(summaryPath, []byte(renderReplaySmokeSummary(report)), 0o644); err != nil {
+	if err := pair.Write(report, renderReplaySmokeSummary(report)); err != nil {
 		return err
 	}
 
 	if report.FailingCount > 0 {
-		return fmt.Errorf("forbidden replay divergences present: %v", report.FailingDivergences)
+		return exitcode.Gatef("forbidden replay divergences present: %v", report.FailingDivergences)
 	}
 	return nil
 }
@@ -267,10 +947,16 @@ type replayFixtureMetadata struct {
 type replayFixturePolicy struct {
 	Gate                              string                          `json:"gate,omitempty"`
 	TimingToleranceMS                 *int64                          `json:"timing_tolerance_ms,omitempty"`
+	ScopeTimingTolerancesMS           replaycmp.ScopeTolerances       `json:"scope_timing_tolerances_ms,omitempty"`
 	FinalAttemptLatencyThresholdMS    *int64                          `json:"final_attempt_latency_threshold_ms,omitempty"`
 	TotalInvocationLatencyThresholdMS *int64                          `json:"total_invocation_latency_threshold_ms,omitempty"`
 	InvocationLatencyScopes           []string                        `json:"invocation_latency_scopes,omitempty"`
 	ExpectedDivergences               []regression.ExpectedDivergence `json:"expected_divergences,omitempty"`
+	// BaselineArtifact and CandidateArtifact back fixtures synthesized from observed
+	// divergences; paths are relative to the metadata file.
+	BaselineArtifact  string `json:"baseline_artifact,omitempty"`
+	CandidateArtifact string `json:"candidate_artifact,omitempty"`
+	Source            string `json:"source,omitempty"`
 }
 
 func loadReplayFixturePolicy(metadataPath string, fixtureID string, defaultTimingToleranceMS int64) (regression.DivergencePolicy, int64, error) {
@@ -284,13 +970,17 @@ func loadReplayFixturePolicy(metadataPath string, fixtureID string, defaultTimin
 		return regression.DivergencePolicy{}, 0, fmt.Errorf("fixture %s not found in metadata %s", fixtureID, metadataPath)
 	}
 
-	timingToleranceMS := fixtureTimingTolerance(fixturePolicy, defaultTimingToleranceMS)
+	compareConfig, err := fixtureCompareConfig(fixtureID, fixturePolicy, defaultTimingToleranceMS)
+	if err != nil {
+		return regression.DivergencePolicy{}, 0, err
+	}
 
 	policy := regression.DivergencePolicy{
-		TimingToleranceMS: timingToleranceMS,
-		Expected:          fixturePolicy.ExpectedDivergences,
+		TimingToleranceMS:       compareConfig.TimingToleranceMS,
+		ScopeTimingTolerancesMS: compareConfig.ScopeTimingTolerancesMS,
+		Expected:                fixturePolicy.ExpectedDivergences,
 	}
-	return policy, timingToleranceMS, nil
+	return policy, compareConfig.TimingToleranceMS, nil
 }
 
 func loadReplayFixtureMetadata(metadataPath string) (replayFixtureMetadata, error) {
@@ -319,20 +1009,33 @@ func fixtureTimingTolerance(policy replayFixturePolicy, defaultTimingToleranceMS
 	return timingToleranceMS
 }
 
+// fixtureCompareConfig resolves a fixture's blanket and per-scope timing tolerances.
+func fixtureCompareConfig(fixtureID string, policy replayFixturePolicy, defaultTimingToleranceMS int64) (replaycmp.CompareConfig, error) {
+	if err := policy.ScopeTimingTolerancesMS.Validate(); err != nil {
+		return replaycmp.CompareConfig{}, fmt.Errorf("replay fixture %s: %w", fixtureID, err)
+	}
+	return replaycmp.CompareConfig{
+		TimingToleranceMS:       fixtureTimingTolerance(policy, defaultTimingToleranceMS),
+		ScopeTimingTolerancesMS: policy.ScopeTimingTolerancesMS,
+	}, nil
+}
+
 type replayFixtureExecutionReport struct {
-	FixtureID                         string         `json:"fixture_id"`
-	Gate                              string         `json:"gate"`
-	TimingToleranceMS                 int64          `json:"timing_tolerance_ms"`
-	FinalAttemptLatencyThresholdMS    *int64         `json:"final_attempt_latency_threshold_ms,omitempty"`
-	TotalInvocationLatencyThresholdMS *int64         `json:"total_invocation_latency_threshold_ms,omitempty"`
-	InvocationLatencyBreaches         int            `json:"invocation_latency_breaches,omitempty"`
-	TotalDivergences                  int            `json:"total_divergences"`
-	FailingCount                      int            `json:"failing_count"`
-	UnexplainedCount                  int            `json:"unexplained_count"`
-	MissingExpected                   int            `json:"missing_expected"`
-	ExpectedConfigured                int            `json:"expected_configured"`
-	ByClass                           map[string]int `json:"by_class"`
-	FailingClasses                    []string       `json:"failing_classes,omitempty"`
+	FixtureID                         string           `json:"fixture_id"`
+	Gate                              string           `json:"gate"`
+	TimingToleranceMS                 int64            `json:"timing_tolerance_ms"`
+	ScopeTimingTolerancesMS           map[string]int64 `json:"scope_timing_tolerances_ms,omitempty"`
+	FinalAttemptLatencyThresholdMS    *int64           `json:"final_attempt_latency_threshold_ms,omitempty"`
+	TotalInvocationLatencyThresholdMS *int64           `json:"total_invocation_latency_threshold_ms,omitempty"`
+	InvocationLatencyBreaches         int              `json:"invocation_latency_breaches,omitempty"`
+	PipelineVersions                  []string         `json:"pipeline_versions,omitempty"`
+	TotalDivergences                  int              `json:"total_divergences"`
+	FailingCount                      int              `json:"failing_count"`
+	UnexplainedCount                  int              `json:"unexplained_count"`
+	MissingExpected                   int              `json:"missing_expected"`
+	ExpectedConfigured                int              `json:"expected_configured"`
+	ByClass                           map[string]int   `json:"by_class"`
+	FailingClasses                    []string         `json:"failing_classes,omitempty"`
 }
 
 type replayFixtureArtifact struct {
@@ -343,7 +1046,9 @@ type replayFixtureArtifact struct {
 }
 
 type replayRegressionReport struct {
+	SchemaVersion      string                         `json:"schema_version"`
 	GeneratedAtUTC     string                         `json:"generated_at_utc"`
+	Environment        string                         `json:"environment,omitempty"`
 	Gate               string                         `json:"gate"`
 	MetadataPath       string                         `json:"metadata_path"`
 	FixtureCount       int                            `json:"fixture_count"`
@@ -353,13 +1058,15 @@ type replayRegressionReport struct {
 	MissingExpected    int                            `json:"missing_expected"`
 	ByClass            map[string]int                 `json:"by_class"`
 	FailingDivergences []string                       `json:"failing_divergences"`
+	PipelineVersions   []string                       `json:"pipeline_versions,omitempty"`
 	Fixtures           []replayFixtureExecutionReport `json:"fixtures"`
 }
 
-type replayFixtureBuilder func(timingToleranceMS int64) []obs.ReplayDivergence
+type replayFixtureBuilder func(cfg replaycmp.CompareConfig) []obs.ReplayDivergence
 
 type invocationLatencySample struct {
 	Scope                    string
+	PipelineVersion          string
 	FinalAttemptLatencyMS    int64
 	TotalInvocationLatencyMS int64
 }
@@ -370,7 +1077,7 @@ func writeReplayRegressionReport(outputPath string, metadataPath string, gate st
 		normalizedGate = replayRegressionDefaultGate
 	}
 	if normalizedGate != "quick" && normalizedGate != "full" {
-		return fmt.Errorf("unsupported replay regression gate %q (expected quick|full)", gate)
+		return exitcode.Usagef("unsupported replay regression gate %q (expected quick|full)", gate)
 	}
 
 	metadata, err := loadReplayFixtureMetadata(metadataPath)
@@ -401,18 +1108,30 @@ func writeReplayRegressionReport(outputPath string, metadataPath string, gate st
 
 	for _, fixtureID := range fixtureIDs {
 		policy := metadata.Fixtures[fixtureID]
-		builder, ok := builders[fixtureID]
-		if !ok {
-			return fmt.Errorf("no replay fixture builder registered for %s", fixtureID)
+		compareConfig, err := fixtureCompareConfig(fixtureID, policy, replaySmokeTimingToleranceMS)
+		if err != nil {
+			return err
+		}
+		timingToleranceMS := compareConfig.TimingToleranceMS
+		var divergences []obs.ReplayDivergence
+		if policy.BaselineArtifact != "" {
+			divergences, err = fixturesynth.ArtifactDivergences(metadataPath, policy.BaselineArtifact, policy.CandidateArtifact, compareConfig)
+			if err != nil {
+				return fmt.Errorf("replay fixture %s: %w", fixtureID, err)
+			}
+		} else {
+			builder, ok := builders[fixtureID]
+			if !ok {
+				return fmt.Errorf("no replay fixture builder registered for %s", fixtureID)
+			}
+			divergences = builder(compareConfig)
 		}
-
-		timingToleranceMS := fixtureTimingTolerance(policy, replaySmokeTimingToleranceMS)
-		divergences := builder(timingToleranceMS)
 		latencyThresholdDivergences := buildInvocationLatencyThresholdDivergences(fixtureID, policy, latencySamplesByScope, latencySamplesErr)
 		divergences = append(divergences, latencyThresholdDivergences...)
 		evaluation := regression.EvaluateDivergences(divergences, regression.DivergencePolicy{
-			TimingToleranceMS: timingToleranceMS,
-			Expected:          policy.ExpectedDivergences,
+			TimingToleranceMS:       timingToleranceMS,
+			ScopeTimingTolerancesMS: compareConfig.ScopeTimingTolerancesMS,
+			Expected:                policy.ExpectedDivergences,
 		})
 
 		byClass := map[string]int{
@@ -431,9 +1150,11 @@ func writeReplayRegressionReport(outputPath string, metadataPath string, gate st
 			FixtureID:                         fixtureID,
 			Gate:                              normalizedGate,
 			TimingToleranceMS:                 timingToleranceMS,
+			ScopeTimingTolerancesMS:           compareConfig.ScopeTimingTolerancesMS,
 			FinalAttemptLatencyThresholdMS:    normalizeNonNegativeThreshold(policy.FinalAttemptLatencyThresholdMS),
 			TotalInvocationLatencyThresholdMS: normalizeNonNegativeThreshold(policy.TotalInvocationLatencyThresholdMS),
 			InvocationLatencyBreaches:         len(latencyThresholdDivergences),
+			PipelineVersions:                  invocationLatencyPipelineVersions(fixtureID, policy, latencySamplesByScope),
 			TotalDivergences:                  len(divergences),
 			FailingCount:                      len(evaluation.Failing),
 			UnexplainedCount:                  len(evaluation.Unexplained),
@@ -451,7 +1172,17 @@ func writeReplayRegressionReport(outputPath string, metadataPath string, gate st
 		failingEntries = append(failingEntries, evaluation.Failing...)
 	}
 
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return err
+	}
+	schemaVersion, err := schemaregistry.Default().CurrentVersion(schemaregistry.KindReplayRegressionReport)
+	if err != nil {
+		return err
+	}
 	summary := replayRegressionReport{
+		SchemaVersion:      schemaVersion,
+		Environment:        environment,
 		GeneratedAtUTC:     time.Now().UTC().Format(time.RFC3339),
 		Gate:               normalizedGate,
 		MetadataPath:       metadataPath,
@@ -462,21 +1193,15 @@ func writeReplayRegressionReport(outputPath string, metadataPath string, gate st
 		MissingExpected:    totalMissingExpected,
 		ByClass:            totalByClass,
 		FailingDivergences: uniqueFailingClasses(failingEntries),
+		PipelineVersions:   replayReportPipelineVersions(fixtureReports),
 		Fixtures:           fixtureReports,
 	}
 
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(summary, "", "  ")
+	pair, err := reportPair(outputPath)
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(outputPath, data, 0o644); err != nil {
-		return err
-	}
-	summaryPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".md"
-	if err := os.WriteFile(summaryPath, []byte(renderReplayRegressionSummary(summary)), 0o644); err != nil {
+	if err := pair.Write(summary, renderReplayRegressionSummary(summary)); err != nil {
 		return err
 	}
 
@@ -486,7 +1211,7 @@ func writeReplayRegressionReport(outputPath string, metadataPath string, gate st
 	}
 
 	if summary.FailingCount > 0 {
-		return fmt.Errorf("replay regression gate failed: %v", summary.FailingDivergences)
+		return exitcode.Gatef("replay regression gate failed: %v", summary.FailingDivergences)
 	}
 	return nil
 }
@@ -507,15 +1232,8 @@ func writeReplayFixtureArtifacts(outputDir string, generatedAtUTC string, metada
 		jsonPath := filepath.Join(outputDir, filename+".json")
 		markdownPath := filepath.Join(outputDir, filename+".md")
 
-		data, err := json.MarshalIndent(artifact, "", "  ")
-		if err != nil {
-			return fmt.Errorf("encode replay fixture artifact %s: %w", report.FixtureID, err)
-		}
-		if err := os.WriteFile(jsonPath, data, 0o644); err != nil {
-			return fmt.Errorf("write replay fixture artifact %s: %w", jsonPath, err)
-		}
-		if err := os.WriteFile(markdownPath, []byte(renderReplayFixtureSummary(artifact)), 0o644); err != nil {
-			return fmt.Errorf("write replay fixture summary %s: %w", markdownPath, err)
+		if err := (artifactwriter.Pair{JSONPath: jsonPath, SummaryPath: markdownPath}).Write(artifact, renderReplayFixtureSummary(artifact)); err != nil {
+			return fmt.Errorf("write replay fixture artifact %s: %w", report.FixtureID, err)
 		}
 	}
 	return nil
@@ -578,6 +1296,7 @@ func replayFixtureBuilders() map[string]replayFixtureBuilder {
 		"cf-002-provider-late-output":          buildReplayNoDivergence,
 		"cf-003-cancel-terminalization":        buildReplayNoDivergence,
 		"cf-004-cancel-observability":          buildReplayNoDivergence,
+		"cf-005-barge-in-late-output":          buildReplayNoDivergence,
 		"f1-admission-overload":                buildReplayNoDivergence,
 		"f2-node-timeout-failure":              buildReplayNoDivergence,
 		"f3-provider-failure":                  buildReplayNoDivergence,
@@ -672,7 +1391,7 @@ func invocationLatencySamplesFromBaselineEntries(entries []timeline.BaselineEvid
 		scope := "turn:" + entry.TurnID
 		current, hasCurrent := samples[scope]
 		if !hasCurrent {
-			current = invocationLatencySample{Scope: scope}
+			current = invocationLatencySample{Scope: scope, PipelineVersion: entry.PipelineVersion}
 		}
 		for _, outcome := range entry.InvocationOutcomes {
 			if outcome.FinalAttemptLatencyMS > current.FinalAttemptLatencyMS {
@@ -687,6 +1406,48 @@ func invocationLatencySamplesFromBaselineEntries(entries []timeline.BaselineEvid
 	return samples
 }
 
+// invocationLatencyPipelineVersions lists the pipeline versions of the runtime evidence a
+// fixture's latency thresholds were checked against, so a breach is attributed to a version.
+func invocationLatencyPipelineVersions(fixtureID string, policy replayFixturePolicy, samplesByScope map[string]invocationLatencySample) []string {
+	if normalizeNonNegativeThreshold(policy.FinalAttemptLatencyThresholdMS) == nil && normalizeNonNegativeThreshold(policy.TotalInvocationLatencyThresholdMS) == nil {
+		return nil
+	}
+	seen := make(map[string]struct{})
+	versions := make([]string, 0)
+	for _, scope := range invocationLatencyScopesForFixture(fixtureID, policy) {
+		sample, ok := samplesByScope[scope]
+		if !ok || sample.PipelineVersion == "" {
+			continue
+		}
+		if _, dup := seen[sample.PipelineVersion]; dup {
+			continue
+		}
+		seen[sample.PipelineVersion] = struct{}{}
+		versions = append(versions, sample.PipelineVersion)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+func replayReportPipelineVersions(reports []replayFixtureExecutionReport) []string {
+	seen := make(map[string]struct{})
+	versions := make([]string, 0)
+	for _, report := range reports {
+		for _, version := range report.PipelineVersions {
+			if _, dup := seen[version]; dup {
+				continue
+			}
+			seen[version] = struct{}{}
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 0 {
+		return nil
+	}
+	sort.Strings(versions)
+	return versions
+}
+
 func invocationLatencyScopesForFixture(fixtureID string, policy replayFixturePolicy) []string {
 	metadataScopes := normalizeInvocationLatencyScopes(policy.InvocationLatencyScopes)
 	if len(metadataScopes) > 0 {
@@ -770,11 +1531,11 @@ func isDigits(v string) bool {
 	return true
 }
 
-func buildReplayNoDivergence(timingToleranceMS int64) []obs.ReplayDivergence {
-	return buildReplaySmokeDivergences(timingToleranceMS)
+func buildReplayNoDivergence(cfg replaycmp.CompareConfig) []obs.ReplayDivergence {
+	return buildReplaySmokeDivergences(cfg)
 }
 
-func buildReplayTimingDivergenceWithinTolerance(timingToleranceMS int64) []obs.ReplayDivergence {
+func buildReplayTimingDivergenceWithinTolerance(cfg replaycmp.CompareConfig) []obs.ReplayDivergence {
 	decision := controlplane.DecisionOutcome{
 		OutcomeKind:        controlplane.OutcomeAdmit,
 		Phase:              controlplane.PhasePreTurn,
@@ -803,10 +1564,10 @@ func buildReplayTimingDivergenceWithinTolerance(timingToleranceMS int64) []obs.R
 		AuthorityEpoch:        7,
 		RuntimeTimestampMS:    112,
 	}}
-	return replaycmp.CompareTraceArtifacts(baseline, replayed, replaycmp.CompareConfig{TimingToleranceMS: timingToleranceMS})
+	return replaycmp.CompareTraceArtifacts(baseline, replayed, cfg)
 }
 
-func buildReplayPlanDivergence(timingToleranceMS int64) []obs.ReplayDivergence {
+func buildReplayPlanDivergence(cfg replaycmp.CompareConfig) []obs.ReplayDivergence {
 	decision := controlplane.DecisionOutcome{
 		OutcomeKind:        controlplane.OutcomeAdmit,
 		Phase:              controlplane.PhasePreTurn,
@@ -835,10 +1596,10 @@ func buildReplayPlanDivergence(timingToleranceMS int64) []obs.ReplayDivergence {
 		AuthorityEpoch:        9,
 		RuntimeTimestampMS:    100,
 	}}
-	return replaycmp.CompareTraceArtifacts(baseline, replayed, replaycmp.CompareConfig{TimingToleranceMS: timingToleranceMS})
+	return replaycmp.CompareTraceArtifacts(baseline, replayed, cfg)
 }
 
-func buildReplayOrderingDivergence(timingToleranceMS int64) []obs.ReplayDivergence {
+func buildReplayOrderingDivergence(cfg replaycmp.CompareConfig) []obs.ReplayDivergence {
 	decision := controlplane.DecisionOutcome{
 		OutcomeKind:        controlplane.OutcomeAdmit,
 		Phase:              controlplane.PhasePreTurn,
@@ -867,10 +1628,10 @@ func buildReplayOrderingDivergence(timingToleranceMS int64) []obs.ReplayDivergen
 		AuthorityEpoch:        11,
 		RuntimeTimestampMS:    300,
 	}}
-	return replaycmp.CompareTraceArtifacts(baseline, replayed, replaycmp.CompareConfig{TimingToleranceMS: timingToleranceMS})
+	return replaycmp.CompareTraceArtifacts(baseline, replayed, cfg)
 }
 
-func buildReplayML003OutcomeDivergence(_ int64) []obs.ReplayDivergence {
+func buildReplayML003OutcomeDivergence(_ replaycmp.CompareConfig) []obs.ReplayDivergence {
 	baseline := []replaycmp.LineageRecord{
 		{EventID: "evt-ml003-drop", Dropped: true, MergeGroupID: ""},
 		{EventID: "evt-ml003-merge", Dropped: false, MergeGroupID: "merge-ml003"},
@@ -893,9 +1654,11 @@ func renderReplayRegressionSummary(report replayRegressionReport) string {
 		fmt.Sprintf("Failing divergences: %d", report.FailingCount),
 		fmt.Sprintf("Unexplained divergences: %d", report.UnexplainedCount),
 		fmt.Sprintf("Missing expected divergences: %d", report.MissingExpected),
-		"",
-		"## By class",
 	}
+	if len(report.PipelineVersions) > 0 {
+		lines = append(lines, "Pipeline versions: "+strings.Join(report.PipelineVersions, ", "))
+	}
+	lines = append(lines, "", "## By class")
 	for _, cls := range []obs.DivergenceClass{
 		obs.PlanDivergence,
 		obs.OutcomeDivergence,
@@ -921,7 +1684,7 @@ func renderReplayFixtureSummary(report replayFixtureArtifact) string {
 		"Fixture: " + report.FixtureID,
 		"Gate: " + report.Gate,
 		"Metadata path: " + report.MetadataPath,
-		fmt.Sprintf("Timing tolerance (ms): %d", report.TimingToleranceMS),
+		renderTimingToleranceSummary(report.TimingToleranceMS, report.ScopeTimingTolerancesMS),
 		renderThresholdSummary("Final-attempt latency threshold (ms)", report.FinalAttemptLatencyThresholdMS),
 		renderThresholdSummary("Total-invocation latency threshold (ms)", report.TotalInvocationLatencyThresholdMS),
 		fmt.Sprintf("Invocation latency threshold breaches: %d", report.InvocationLatencyBreaches),
@@ -953,6 +1716,23 @@ func renderReplayFixtureSummary(report replayFixtureArtifact) string {
 	return strings.Join(lines, "\n") + "\n"
 }
 
+func renderTimingToleranceSummary(toleranceMS int64, scoped map[string]int64) string {
+	line := fmt.Sprintf("Timing tolerance (ms): %d", toleranceMS)
+	if len(scoped) == 0 {
+		return line
+	}
+	patterns := make([]string, 0, len(scoped))
+	for pattern := range scoped {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	overrides := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		overrides = append(overrides, fmt.Sprintf("%s=%d", pattern, scoped[pattern]))
+	}
+	return line + " (scoped: " + strings.Join(overrides, ", ") + ")"
+}
+
 func renderThresholdSummary(label string, threshold *int64) string {
 	if threshold == nil {
 		return label + ": unset"
@@ -960,7 +1740,7 @@ func renderThresholdSummary(label string, threshold *int64) string {
 	return fmt.Sprintf("%s: %d", label, *threshold)
 }
 
-func buildReplaySmokeDivergences(timingToleranceMS int64) []obs.ReplayDivergence {
+func buildReplaySmokeDivergences(cfg replaycmp.CompareConfig) []obs.ReplayDivergence {
 	epoch := int64(7)
 	baseline := []replaycmp.TraceArtifact{
 		{
@@ -1003,7 +1783,7 @@ func buildReplaySmokeDivergences(timingToleranceMS int64) []obs.ReplayDivergence
 		},
 	}
 	replayed := append([]replaycmp.TraceArtifact(nil), baseline...)
-	return replaycmp.CompareTraceArtifacts(baseline, replayed, replaycmp.CompareConfig{TimingToleranceMS: timingToleranceMS})
+	return replaycmp.CompareTraceArtifacts(baseline, replayed, cfg)
 }
 
 func uniqueFailingClasses(failing []obs.ReplayDivergence) []string {
@@ -1040,7 +1820,7 @@ func renderReplaySmokeSummary(report replaySmokeReport) string {
 		"Generated at (UTC): " + report.GeneratedAtUTC,
 		"Fixture: " + report.FixtureID,
 		"Metadata path: " + report.MetadataPath,
-		fmt.Sprintf("Timing tolerance (ms): %d", report.TimingToleranceMS),
+		renderTimingToleranceSummary(report.TimingToleranceMS, report.ScopeTimingTolerancesMS),
 		fmt.Sprintf("Total divergences: %d", report.TotalDivergences),
 		fmt.Sprintf("Failing divergences: %d", report.FailingCount),
 		fmt.Sprintf("Unexplained divergences: %d", report.UnexplainedCount),
@@ -1068,206 +1848,1539 @@ func renderReplaySmokeSummary(report replaySmokeReport) string {
 }
 
 type sloGateArtifact struct {
-	GeneratedAtUTC       string               `json:"generated_at_utc"`
-	BaselineArtifactPath string               `json:"baseline_artifact_path"`
-	Thresholds           ops.MVPSLOThresholds `json:"thresholds"`
-	Report               ops.MVPSLOGateReport `json:"report"`
+	SchemaVersion        string                 `json:"schema_version"`
+	GeneratedAtUTC       string                 `json:"generated_at_utc"`
+	Environment          string                 `json:"environment,omitempty"`
+	BaselineArtifactPath string                 `json:"baseline_artifact_path"`
+	Thresholds           ops.MVPSLOThresholds   `json:"thresholds"`
+	Report               ops.MVPSLOGateReport   `json:"report"`
+	QualityCorpusPath    string                 `json:"quality_corpus_path,omitempty"`
+	QualityTranscriber   string                 `json:"quality_transcriber,omitempty"`
+	Quality              *ops.STTQualityReport  `json:"quality,omitempty"`
+	ErrorBudget          *ops.ErrorBudgetReport `json:"error_budget,omitempty"`
+	Passed               bool                   `json:"passed"`
+}
+
+type failureDomainReportArtifact struct {
+	GeneratedAtUTC       string                  `json:"generated_at_utc"`
+	Environment          string                  `json:"environment,omitempty"`
+	BaselineArtifactPath string                  `json:"baseline_artifact_path"`
+	Report               ops.FailureDomainReport `json:"report"`
+}
+
+type slaReportArtifact struct {
+	GeneratedAtUTC       string        `json:"generated_at_utc"`
+	Environment          string        `json:"environment,omitempty"`
+	BaselineArtifactPath string        `json:"baseline_artifact_path"`
+	Report               ops.SLAReport `json:"report"`
+}
+
+type costReportArtifact struct {
+	GeneratedAtUTC       string      `json:"generated_at_utc"`
+	Environment          string      `json:"environment,omitempty"`
+	BaselineArtifactPath string      `json:"baseline_artifact_path"`
+	Report               cost.Report `json:"report"`
+}
+
+// Debug bundle decision sources.
+const (
+	debugBundleDecisionSourceAdminSocket = "admin_socket"
+	debugBundleDecisionSourceBaseline    = "baseline_artifact"
+)
+
+type debugBundleArtifact struct {
+	GeneratedAtUTC       string                         `json:"generated_at_utc"`
+	Environment          string                         `json:"environment,omitempty"`
+	BaselineArtifactPath string                         `json:"baseline_artifact_path"`
+	SessionID            string                         `json:"session_id"`
+	TurnID               string                         `json:"turn_id,omitempty"`
+	DecisionSource       string                         `json:"decision_source"`
+	Decisions            []controlplane.DecisionOutcome `json:"decisions"`
+	DecisionWindow       decisionindex.Stats            `json:"decision_window"`
+	Baseline             []timeline.BaselineEvidence    `json:"baseline"`
+}
+
+type explainDecisionArtifact struct {
+	GeneratedAtUTC       string                       `json:"generated_at_utc"`
+	Environment          string                       `json:"environment,omitempty"`
+	BaselineArtifactPath string                       `json:"baseline_artifact_path"`
+	EventID              string                       `json:"event_id"`
+	Decision             controlplane.DecisionOutcome `json:"decision"`
+}
+
+// decisionReplayArtifact is the recompute_decisions divergence report for a recorded timeline.
+type decisionReplayArtifact struct {
+	GeneratedAtUTC       string              `json:"generated_at_utc"`
+	Environment          string              `json:"environment,omitempty"`
+	BaselineArtifactPath string              `json:"baseline_artifact_path"`
+	CandidatePath        string              `json:"candidate_path,omitempty"`
+	RecordedInputs       int                 `json:"recorded_inputs"`
+	Result               obs.ReplayRunResult `json:"result"`
+}
+
+type runbookDecisionsArtifact struct {
+	GeneratedAtUTC     string             `json:"generated_at_utc"`
+	Environment        string             `json:"environment,omitempty"`
+	RunbookConfigPath  string             `json:"runbook_config_path"`
+	SLOGatesReportPath string             `json:"slo_gates_report_path"`
+	Mode               runbook.Mode       `json:"mode"`
+	Violations         []string           `json:"violations"`
+	Decisions          []runbook.Decision `json:"decisions"`
+}
+
+type llmEvalReportArtifact struct {
+	GeneratedAtUTC string         `json:"generated_at_utc"`
+	Environment    string         `json:"environment,omitempty"`
+	SuitePath      string         `json:"suite_path"`
+	Responder      string         `json:"responder,omitempty"`
+	JudgeProvider  string         `json:"judge_provider,omitempty"`
+	Report         llmeval.Report `json:"report"`
+	Passed         bool           `json:"passed"`
+}
+
+type redactionEvalReportArtifact struct {
+	GeneratedAtUTC string               `json:"generated_at_utc"`
+	Environment    string               `json:"environment,omitempty"`
+	SuitePath      string               `json:"suite_path"`
+	Report         redactioneval.Report `json:"report"`
+	Passed         bool                 `json:"passed"`
 }
 
 type contractsReportArtifact struct {
 	GeneratedAtUTC string                               `json:"generated_at_utc"`
+	Environment    string                               `json:"environment,omitempty"`
 	FixtureRoot    string                               `json:"fixture_root"`
 	Summary        validation.ContractValidationSummary `json:"summary"`
 	Passed         bool                                 `json:"passed"`
 }
 
 func writeSLOGatesReport(outputPath string, baselineArtifactPath string) error {
+	return writeSLOGatesReportWithQuality(outputPath, baselineArtifactPath, ops.DefaultSTTGoldenCorpusPath, ops.ProviderTranscriberMode)
+}
+
+// writeSLOGatesReportWithQuality evaluates latency/correctness SLO gates and, when the
+// golden transcript corpus is present, the STT WER quality section with the transcriber
+// selected by transcriberMode (see sttQualityTranscriber).
+func writeSLOGatesReportWithQuality(outputPath string, baselineArtifactPath string, qualityCorpusPath string, transcriberMode string) error {
 	entries, effectiveArtifactPath, err := loadRuntimeBaselineEntries(baselineArtifactPath)
 	if err != nil {
 		return err
 	}
 
 	thresholds := ops.DefaultMVPSLOThresholds()
-	report := ops.EvaluateMVPSLOGates(toTurnMetrics(entries), thresholds)
+	report := ops.EvaluateMVPSLOGates(ops.TurnMetricsFromBaseline(entries), thresholds)
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return err
+	}
+	schemaVersion, err := schemaregistry.Default().CurrentVersion(schemaregistry.KindSLOGatesReport)
+	if err != nil {
+		return err
+	}
 	artifact := sloGateArtifact{
+		SchemaVersion:        schemaVersion,
+		Environment:          environment,
 		GeneratedAtUTC:       time.Now().UTC().Format(time.RFC3339),
 		BaselineArtifactPath: effectiveArtifactPath,
 		Thresholds:           thresholds,
 		Report:               report,
+		Passed:               report.Passed,
 	}
-
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(artifact, "", "  ")
+	events, evaluatedAtMS := toSLOEvents(entries)
+	budget, err := ops.EvaluateErrorBudget(events, ops.DefaultErrorBudgetPolicy(), evaluatedAtMS)
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(outputPath, data, 0o644); err != nil {
-		return err
+	ops.EmitBurnRateAlerts(budget, "turn_success")
+	artifact.ErrorBudget = &budget
+	if qualityCorpusPath != "" {
+		resolvedCorpusPath, err := resolveProjectRelativePath(qualityCorpusPath)
+		if err != nil {
+			return fmt.Errorf("resolve stt golden corpus: %w", err)
+		}
+		corpus, err := ops.LoadSTTGoldenCorpus(resolvedCorpusPath)
+		if err != nil {
+			return err
+		}
+		mode, providerIDs, transcriber, err := sttQualityTranscriber(transcriberMode, corpus)
+		if err != nil {
+			return err
+		}
+		quality := ops.EvaluateSTTQuality(corpus, providerIDs, transcriber)
+		artifact.QualityCorpusPath = qualityCorpusPath
+		artifact.QualityTranscriber = mode
+		artifact.Quality = &quality
+		artifact.Passed = artifact.Passed && quality.Passed
 	}
 
-	summaryPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".md"
-	if err := os.WriteFile(summaryPath, []byte(renderSLOGatesSummary(artifact)), 0o644); err != nil {
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := pair.Write(artifact, renderSLOGatesSummary(artifact)); err != nil {
 		return err
 	}
 
 	if !artifact.Report.Passed {
-		return fmt.Errorf("mvp slo gate failed: %v", artifact.Report.Violations)
+		return exitcode.Gatef("mvp slo gate failed: %v", artifact.Report.Violations)
+	}
+	if artifact.Quality != nil && !artifact.Quality.Passed {
+		return exitcode.Gatef("mvp quality gate failed: %v", artifact.Quality.Violations)
 	}
 	return nil
 }
 
-func writeContractsReport(outputPath string, fixtureRoot string) error {
-	if fixtureRoot == "" {
-		fixtureRoot = filepath.Join("test", "contract", "fixtures")
+// sttQualityTranscriber resolves the STT quality gate transcriber. The default providers mode
+// runs fixture audio through the provider invocation controller against every STT adapter of
+// the configured provider profile; recorded mode scores the corpus recorded transcripts without
+// calling any provider and must be selected explicitly.
+func sttQualityTranscriber(mode string, corpus ops.STTGoldenCorpus) (string, []string, ops.Transcriber, error) {
+	switch mode = strings.ToLower(strings.TrimSpace(mode)); mode {
+	case "", ops.ProviderTranscriberMode:
+		providers, err := providerbootstrap.BuildProfile(providerbootstrap.ProfileFromEnv())
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("stt quality providers: %w", err)
+		}
+		ids, err := providers.Catalog.ProviderIDs(contracts.ModalitySTT)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("stt quality providers: %w", err)
+		}
+		return ops.ProviderTranscriberMode, ids, ops.InvocationTranscriber{Invoker: providers.Controller, ResolveAudioRef: resolveProjectRelativePath}, nil
+	case ops.RecordedTranscriberMode:
+		return mode, corpus.RecordedProviderIDs(), ops.RecordedTranscriber{}, nil
+	default:
+		return "", nil, nil, exitcode.Usagef("unsupported %s %q (expected %s|%s)", envSTTQualityTranscriber, mode, ops.ProviderTranscriberMode, ops.RecordedTranscriberMode)
 	}
-	resolvedFixtureRoot, err := resolveProjectRelativePath(fixtureRoot)
+}
+
+// writeFailureDomainReport attributes failed baseline turns to failure domains in
+// bucketMS windows so blast radius can be tracked against the f1-f8 taxonomy.
+func writeFailureDomainReport(outputPath string, baselineArtifactPath string, bucketMS int64) error {
+	entries, effectiveArtifactPath, err := loadRuntimeBaselineEntries(baselineArtifactPath)
 	if err != nil {
 		return err
 	}
-	schemaPath, err := resolveProjectRelativePath(filepath.Join("docs", "ContractArtifacts.schema.json"))
+	report, err := ops.AttributeFailureDomains(toTurnFailureSamples(entries), bucketMS)
 	if err != nil {
 		return err
 	}
-
-	summary, err := validation.ValidateContractFixturesWithSchema(schemaPath, resolvedFixtureRoot)
+	environment, err := toolingrelease.EnvironmentFromEnv()
 	if err != nil {
 		return err
 	}
-	artifact := contractsReportArtifact{
-		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
-		FixtureRoot:    resolvedFixtureRoot,
-		Summary:        summary,
-		Passed:         summary.Failed == 0,
+	artifact := failureDomainReportArtifact{
+		Environment:          environment,
+		GeneratedAtUTC:       time.Now().UTC().Format(time.RFC3339),
+		BaselineArtifactPath: effectiveArtifactPath,
+		Report:               report,
+	}
+
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return err
 	}
+	return pair.Write(artifact, renderFailureDomainSummary(artifact))
+}
 
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+// writeSLAReport computes per-tenant SLA attainment over periodMS periods from the SLA tags in
+// baseline evidence. Unlike the SLO gates it never fails on a missed SLA.
+func writeSLAReport(outputPath string, baselineArtifactPath string, periodMS int64) error {
+	entries, effectiveArtifactPath, err := loadRuntimeBaselineEntries(baselineArtifactPath)
+	if err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(artifact, "", "  ")
+	report, err := ops.EvaluateTenantSLAs(toTenantTurnSamples(entries), periodMS)
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(outputPath, data, 0o644); err != nil {
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
 		return err
 	}
+	artifact := slaReportArtifact{
+		Environment:          environment,
+		GeneratedAtUTC:       time.Now().UTC().Format(time.RFC3339),
+		BaselineArtifactPath: effectiveArtifactPath,
+		Report:               report,
+	}
 
-	summaryPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".md"
-	if err := os.WriteFile(summaryPath, []byte(renderContractsReportSummary(artifact)), 0o644); err != nil {
+	pair, err := reportPair(outputPath)
+	if err != nil {
 		return err
 	}
-	if !artifact.Passed {
-		return fmt.Errorf("contract fixtures failed: %d failures", artifact.Summary.Failed)
-	}
-	return nil
+	return pair.Write(artifact, renderSLASummary(artifact))
 }
 
-func resolveProjectRelativePath(path string) (string, error) {
-	trimmed := strings.TrimSpace(path)
-	if trimmed == "" {
-		return "", fmt.Errorf("path is required")
+// writeCostReport rolls the turn cost evidence in baseline evidence up per turn, session,
+// tenant and provider. Costs are estimates from the runtime price table, not invoices.
+func writeCostReport(outputPath string, baselineArtifactPath string) error {
+	entries, effectiveArtifactPath, err := loadRuntimeBaselineEntries(baselineArtifactPath)
+	if err != nil {
+		return err
 	}
-	if filepath.IsAbs(trimmed) {
-		return trimmed, nil
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return err
 	}
-	if _, err := os.Stat(trimmed); err == nil {
-		return trimmed, nil
+	artifact := costReportArtifact{
+		Environment:          environment,
+		GeneratedAtUTC:       time.Now().UTC().Format(time.RFC3339),
+		BaselineArtifactPath: effectiveArtifactPath,
+		Report:               cost.BuildReport(entries),
 	}
-	wd, err := os.Getwd()
+
+	pair, err := reportPair(outputPath)
 	if err != nil {
-		return "", err
-	}
-	dir := wd
-	for {
-		candidate := filepath.Join(dir, trimmed)
-		if _, err := os.Stat(candidate); err == nil {
-			return candidate, nil
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			break
-		}
-		dir = parent
+		return err
 	}
-	return "", fmt.Errorf("path not found: %s", trimmed)
+	return pair.Write(artifact, renderCostSummary(artifact))
 }
 
-func writeReleaseManifest(
-	outputPath string,
-	specRef string,
-	rolloutConfigPath string,
-	contractsReportPath string,
-	replayRegressionReportPath string,
-	sloGatesReportPath string,
-	now time.Time,
-) (toolingrelease.ReleaseManifest, error) {
-	rolloutCfg, rolloutSource, err := toolingrelease.LoadRolloutConfig(rolloutConfigPath)
+// writeDebugBundle bundles the decisions and baseline evidence of one session (optionally one
+// turn) for triage. Decisions come from the decision index of the runtime serving on the admin
+// socket at adminSocketPath; with no socket there, the baseline artifact's decisions are
+// indexed offline instead.
+func writeDebugBundle(outputPath string, baselineArtifactPath string, adminSocketPath string, query decisionindex.Query) error {
+	if strings.TrimSpace(query.SessionID) == "" {
+		return fmt.Errorf("debug bundle session_id is required")
+	}
+	entries, effectiveArtifactPath, err := loadRuntimeBaselineEntries(baselineArtifactPath)
 	if err != nil {
-		return toolingrelease.ReleaseManifest{}, err
+		return err
+	}
+	baseline := make([]timeline.BaselineEvidence, 0)
+	for _, entry := range entries {
+		if entry.SessionID == query.SessionID && (query.TurnID == "" || entry.TurnID == query.TurnID) {
+			baseline = append(baseline, entry)
+		}
+	}
+	decisions, decisionSource, err := queryDebugBundleDecisions(entries, adminSocketPath, query)
+	if err != nil {
+		return err
+	}
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return err
+	}
+	artifact := debugBundleArtifact{
+		GeneratedAtUTC:       time.Now().UTC().Format(time.RFC3339),
+		Environment:          environment,
+		BaselineArtifactPath: effectiveArtifactPath,
+		SessionID:            query.SessionID,
+		TurnID:               query.TurnID,
+		DecisionSource:       decisionSource,
+		Decisions:            decisions.Decisions,
+		DecisionWindow:       decisions.Window,
+		Baseline:             baseline,
+	}
+	if len(artifact.Decisions) == 0 && len(artifact.Baseline) == 0 {
+		return fmt.Errorf("no decisions or baseline evidence for session %s", query.SessionID)
 	}
 
-	readiness, sources := toolingrelease.EvaluateReadiness(toolingrelease.ReadinessInput{
-		ContractsReportPath:        contractsReportPath,
-		ReplayRegressionReportPath: replayRegressionReportPath,
-		SLOGatesReportPath:         sloGatesReportPath,
-		Now:                        now,
-		MaxArtifactAge:             toolingrelease.DefaultMaxArtifactAge,
-	})
-	if !readiness.Passed {
-		return toolingrelease.ReleaseManifest{}, fmt.Errorf("release readiness failed: %v", readiness.Violations)
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return err
 	}
+	return pair.Write(artifact, renderDebugBundleSummary(artifact))
+}
 
-	if sources == nil {
-		sources = make(map[string]toolingrelease.ArtifactSource, 4)
+// queryDebugBundleDecisions queries the live runtime's decision index on the admin socket, or
+// indexes the baseline entries when no runtime socket exists at adminSocketPath.
+func queryDebugBundleDecisions(entries []timeline.BaselineEvidence, adminSocketPath string, query decisionindex.Query) (decisionindex.QueryResponse, string, error) {
+	adminSocketPath = strings.TrimSpace(adminSocketPath)
+	if adminSocketPath != "" {
+		if _, err := os.Stat(adminSocketPath); err == nil {
+			response, err := diagnostics.FetchDecisions(adminSocketPath, 5*time.Second, query)
+			if err != nil {
+				return decisionindex.QueryResponse{}, "", fmt.Errorf("debug bundle decisions: %w", err)
+			}
+			return response, debugBundleDecisionSourceAdminSocket, nil
+		}
 	}
-	sources["rollout_config"] = rolloutSource
+	index := decisionindex.New(decisionindex.Config{})
+	for _, entry := range entries {
+		if err := index.Record(entry.DecisionOutcomes...); err != nil {
+			return decisionindex.QueryResponse{}, "", err
+		}
+	}
+	return decisionindex.QueryResponse{Decisions: index.Query(query), Window: index.Stats()}, debugBundleDecisionSourceBaseline, nil
+}
 
-	manifest, err := toolingrelease.BuildReleaseManifest(specRef, rolloutCfg, readiness, sources, now)
+// writeExplainDecision finds one decision outcome by event_id in a runtime baseline artifact
+// and renders its explain payload, returning the rendered summary.
+func writeExplainDecision(outputPath string, baselineArtifactPath string, eventID string) (string, error) {
+	if strings.TrimSpace(eventID) == "" {
+		return "", fmt.Errorf("explain decision event_id is required")
+	}
+	entries, effectiveArtifactPath, err := loadRuntimeBaselineEntries(baselineArtifactPath)
 	if err != nil {
-		return toolingrelease.ReleaseManifest{}, err
+		return "", err
 	}
-
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
-		return toolingrelease.ReleaseManifest{}, err
+	var decision *controlplane.DecisionOutcome
+	for _, entry := range entries {
+		for i := range entry.DecisionOutcomes {
+			if entry.DecisionOutcomes[i].EventID == eventID {
+				decision = &entry.DecisionOutcomes[i]
+			}
+		}
+	}
+	if decision == nil {
+		return "", fmt.Errorf("no decision outcome with event_id %s in %s", eventID, effectiveArtifactPath)
 	}
-	data, err := json.MarshalIndent(manifest, "", "  ")
+	if decision.Explain == nil {
+		return "", fmt.Errorf("decision %s has no explain payload; record with %s=true", eventID, localadmission.EnvDecisionExplain)
+	}
+	environment, err := toolingrelease.EnvironmentFromEnv()
 	if err != nil {
-		return toolingrelease.ReleaseManifest{}, err
+		return "", err
 	}
-	if err := os.WriteFile(outputPath, data, 0o644); err != nil {
-		return toolingrelease.ReleaseManifest{}, err
+	artifact := explainDecisionArtifact{
+		GeneratedAtUTC:       time.Now().UTC().Format(time.RFC3339),
+		Environment:          environment,
+		BaselineArtifactPath: effectiveArtifactPath,
+		EventID:              eventID,
+		Decision:             *decision,
 	}
 
-	summaryPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".md"
-	if err := os.WriteFile(summaryPath, []byte(renderReleaseManifestSummary(manifest)), 0o644); err != nil {
-		return toolingrelease.ReleaseManifest{}, err
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return "", err
 	}
-	return manifest, nil
+	summary := renderExplainDecisionSummary(artifact)
+	if err := pair.Write(artifact, summary); err != nil {
+		return "", err
+	}
+	return summary, nil
 }
 
-func writeRuntimeBaselineArtifact(outputPath string) error {
-	_, err := generateRuntimeBaselineArtifact(outputPath)
-	return err
+// parseReplayDecisionFlags splits replay-decisions' --name=value filter and cursor flags from
+// its positional arguments. The filter or cursor is nil when none of its flags were given.
+func parseReplayDecisionFlags(args []string) ([]string, *obs.ReplayRunFilter, *obs.ReplayCursor, error) {
+	positional := make([]string, 0, len(args))
+	var filter obs.ReplayRunFilter
+	var cursor obs.ReplayCursor
+	hasFilter, hasCursor := false, false
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || !strings.HasPrefix(name, "--") {
+			if strings.HasPrefix(arg, "--") {
+				return nil, nil, nil, exitcode.Usagef("flag %s requires a value", arg)
+			}
+			positional = append(positional, arg)
+			continue
+		}
+		switch name {
+		case "--session":
+			filter.SessionID, hasFilter = value, true
+		case "--turn-from":
+			filter.TurnIDFrom, hasFilter = value, true
+		case "--turn-to":
+			filter.TurnIDTo, hasFilter = value, true
+		case "--lane":
+			filter.Lane, hasFilter = eventabi.Lane(value), true
+		case "--class":
+			for _, class := range strings.Split(value, ",") {
+				filter.DivergenceClasses = append(filter.DivergenceClasses, obs.DivergenceClass(strings.TrimSpace(class)))
+			}
+			hasFilter = true
+		case "--after-event":
+			cursor.AfterEventID, hasCursor = value, true
+		case "--limit":
+			limit, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, nil, nil, exitcode.Usagef("invalid --limit %q: %v", value, err)
+			}
+			cursor.Limit, hasCursor = limit, true
+		default:
+			return nil, nil, nil, exitcode.Usagef("unknown flag %s", name)
+		}
+	}
+	var filterOut *obs.ReplayRunFilter
+	if hasFilter {
+		if err := filter.Validate(); err != nil {
+			return nil, nil, nil, exitcode.Usage(err)
+		}
+		filterOut = &filter
+	}
+	var cursorOut *obs.ReplayCursor
+	if hasCursor {
+		if err := cursor.Validate(); err != nil {
+			return nil, nil, nil, exitcode.Usage(err)
+		}
+		cursorOut = &cursor
+	}
+	return positional, filterOut, cursorOut, nil
 }
 
-func loadRuntimeBaselineEntries(baselineArtifactPath string) ([]timeline.BaselineEvidence, string, error) {
-	if baselineArtifactPath == "" {
-		baselineArtifactPath = defaultRuntimeBaselineArtifactPath
+// writeDecisionReplayReport recomputes the turn-open decisions recorded in a runtime baseline
+// against the current control plane, or against a candidate CP distribution file when
+// candidatePath is set, and writes the decision divergences. filter and cursor narrow the
+// replayed inputs; a cursor after an unrecorded event is a usage error. The report is written
+// before a divergence fails the command, so the returned summary is set whenever it was written.
+func writeDecisionReplayReport(outputPath string, baselineArtifactPath string, candidatePath string, filter *obs.ReplayRunFilter, cursor *obs.ReplayCursor) (string, error) {
+	entries, effectiveArtifactPath, err := loadRuntimeBaselineEntries(baselineArtifactPath)
+	if err != nil {
+		return "", err
+	}
+	var resolver turnarbiter.TurnStartBundleResolver
+	if candidatePath != "" {
+		backends, err := turnarbiter.NewControlPlaneBackendsFromDistributionFile(candidatePath)
+		if err != nil {
+			return "", err
+		}
+		resolver = turnarbiter.NewControlPlaneBundleResolverWithBackends(backends)
+	}
+	inputs := replaycmp.DecisionInputsFromBaseline(entries)
+	result, err := replaycmp.RunDecisionReplay(obs.ReplayRunRequest{
+		BaselineRef:  effectiveArtifactPath,
+		CandidateRef: candidatePath,
+		Mode:         obs.ReplayModeRecomputeDecisions,
+		Filter:       filter,
+		Cursor:       cursor,
+	}, inputs, turnarbiter.NewDecisionRecomputer(resolver))
+	if errors.Is(err, replaycmp.ErrReplayCursorEventNotFound) {
+		return "", exitcode.Usage(err)
+	}
+	if err != nil {
+		return "", err
+	}
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return "", err
+	}
+	artifact := decisionReplayArtifact{
+		GeneratedAtUTC:       time.Now().UTC().Format(time.RFC3339),
+		Environment:          environment,
+		BaselineArtifactPath: effectiveArtifactPath,
+		CandidatePath:        candidatePath,
+		RecordedInputs:       len(inputs),
+		Result:               result,
 	}
 
-	artifact, err := timeline.ReadBaselineArtifact(baselineArtifactPath)
+	pair, err := reportPair(outputPath)
 	if err != nil {
-		return nil, baselineArtifactPath, fmt.Errorf("load runtime baseline artifact %s: %w", baselineArtifactPath, err)
+		return "", err
 	}
-	return artifact.Entries, baselineArtifactPath, nil
+	summary := renderDecisionReplaySummary(artifact)
+	if err := pair.Write(artifact, summary); err != nil {
+		return "", err
+	}
+	if len(result.Divergences) > 0 {
+		return summary, exitcode.Gatef("decision replay found %d divergences", len(result.Divergences))
+	}
+	return summary, nil
 }
 
-func generateRuntimeBaselineArtifact(baselineArtifactPath string) ([]timeline.BaselineEvidence, error) {
-	if baselineArtifactPath == "" {
-		baselineArtifactPath = defaultRuntimeBaselineArtifactPath
+// writeComplianceReport aggregates retention, consent, redaction, and erasure evidence for the
+// [windowStart, windowEnd) RFC 3339 window into one auditor-facing artifact.
+func writeComplianceReport(outputPath string, inputsPath string, windowStart string, windowEnd string, environment string, now time.Time) (compliance.Report, error) {
+	inputs, err := compliance.LoadInputs(inputsPath)
+	if err != nil {
+		return compliance.Report{}, err
 	}
-	recorder := timeline.NewRecorder(timeline.StageAConfig{
+	start, err := time.Parse(time.RFC3339, windowStart)
+	if err != nil {
+		return compliance.Report{}, exitcode.Usage(fmt.Errorf("invalid window_start: %w", err))
+	}
+	end, err := time.Parse(time.RFC3339, windowEnd)
+	if err != nil {
+		return compliance.Report{}, exitcode.Usage(fmt.Errorf("invalid window_end: %w", err))
+	}
+	report, err := compliance.Build(inputs, compliance.Window{Start: start, End: end}, environment, now)
+	if err != nil {
+		return compliance.Report{}, err
+	}
+
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return compliance.Report{}, err
+	}
+	if err := pair.Write(report, renderComplianceSummary(report)); err != nil {
+		return compliance.Report{}, err
+	}
+	return report, nil
+}
+
+// writeProviderBenchmark ranks providers per modality over the invocation evidence of every
+// baseline artifact within the window. Date-only bounds cover whole UTC days, end inclusive.
+func writeProviderBenchmark(outputPath string, baselineArtifactPaths []string, windowStart string, windowEnd string, environment string, now time.Time) (providerbench.Report, error) {
+	start, err := parseBenchmarkBound(windowStart, false)
+	if err != nil {
+		return providerbench.Report{}, exitcode.Usage(fmt.Errorf("invalid window_start: %w", err))
+	}
+	end, err := parseBenchmarkBound(windowEnd, true)
+	if err != nil {
+		return providerbench.Report{}, exitcode.Usage(fmt.Errorf("invalid window_end: %w", err))
+	}
+	entries := make([]timeline.BaselineEvidence, 0)
+	for _, path := range baselineArtifactPaths {
+		loaded, _, err := loadRuntimeBaselineEntries(path)
+		if err != nil {
+			return providerbench.Report{}, err
+		}
+		entries = append(entries, loaded...)
+	}
+	report, err := providerbench.Build(entries, providerbench.Window{Start: start, End: end}, providerregistry.DefaultCapabilities(), environment, now)
+	if err != nil {
+		return providerbench.Report{}, err
+	}
+	report.BaselineArtifacts = append([]string(nil), baselineArtifactPaths...)
+
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return providerbench.Report{}, err
+	}
+	if err := pair.Write(report, renderProviderBenchmarkSummary(report)); err != nil {
+		return providerbench.Report{}, err
+	}
+	return report, nil
+}
+
+// writeAlertsReport evaluates a rules file against the active environment's artifacts and
+// writes the alerts artifact even when critical rules fire, so the failure is inspectable.
+func writeAlertsReport(outputPath string, rulesPath string, environment string, now time.Time) (alerting.Report, error) {
+	rules, err := alerting.LoadRules(rulesPath)
+	if err != nil {
+		return alerting.Report{}, err
+	}
+	report, err := alerting.Evaluate(rules, func(path string) string {
+		return toolingrelease.EnvironmentArtifactPath(environment, path)
+	}, environment, now)
+	if err != nil {
+		return alerting.Report{}, err
+	}
+	report.RulesPath = rulesPath
+
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return alerting.Report{}, err
+	}
+	if err := pair.Write(report, renderAlertsSummary(report)); err != nil {
+		return alerting.Report{}, err
+	}
+	return report, nil
+}
+
+func parseBenchmarkBound(value string, end bool) (time.Time, error) {
+	if day, err := time.Parse(time.DateOnly, value); err == nil {
+		if end {
+			return day.Add(24 * time.Hour), nil
+		}
+		return day, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// runReplayShell steps through a runtime baseline artifact; with a candidate artifact each step
+// carries the replay divergences against it, so breakpoints can target divergence classes.
+func runReplayShell(baselineArtifactPath string, candidateArtifactPath string, in *os.File, out io.Writer) error {
+	baseline, _, err := loadRuntimeBaselineEntries(baselineArtifactPath)
+	if err != nil {
+		return err
+	}
+	var candidate []timeline.BaselineEvidence
+	if candidateArtifactPath != "" {
+		if candidate, _, err = loadRuntimeBaselineEntries(candidateArtifactPath); err != nil {
+			return err
+		}
+	}
+	built, err := replayshell.Build(baseline, candidate, replaycmp.CompareConfig{TimingToleranceMS: replaySmokeTimingToleranceMS})
+	if err != nil {
+		return err
+	}
+	interactive := false
+	if info, err := in.Stat(); err == nil {
+		interactive = info.Mode()&os.ModeCharDevice != 0
+	}
+	if interactive {
+		fmt.Fprintf(out, "replay shell: %d steps from %s (help for commands)\n", len(built.Steps), baselineArtifactPath)
+	}
+	return replayshell.New(built, out).Run(in, interactive)
+}
+
+// synthesizeReplayFixture snapshots the divergent scopes of a live or shadow run into an
+// artifact-backed replay fixture and scaffolds its metadata entry.
+func synthesizeReplayFixture(fixtureID string, baselineArtifactPath string, candidateArtifactPath string, metadataPath string, source string) (string, error) {
+	result, err := fixturesynth.Synthesize(fixturesynth.Request{
+		FixtureID:         fixtureID,
+		BaselinePath:      baselineArtifactPath,
+		CandidatePath:     candidateArtifactPath,
+		MetadataPath:      metadataPath,
+		Source:            source,
+		TimingToleranceMS: replaySmokeTimingToleranceMS,
+	})
+	if err != nil {
+		return "", err
+	}
+	lines := []string{
+		fmt.Sprintf("replay fixture %s written: %s", result.FixtureID, result.FixtureDir),
+		fmt.Sprintf("scopes: %s (baseline_turns=%d candidate_turns=%d)", strings.Join(result.Scopes, ", "), result.BaselineTurns, result.CandidateTurns),
+		fmt.Sprintf("metadata entry scaffolded in %s with %d expected divergences", metadataPath, len(result.Entry.ExpectedDivergences)),
+	}
+	for _, divergence := range result.Failing {
+		lines = append(lines, fmt.Sprintf("needs triage: %s %s: %s", divergence.Class, divergence.Scope, divergence.Message))
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// writeRecordingExport exports stored session audio for QA review under the default replay
+// access policy of the session tenant; the reviewer identity comes from the environment.
+// captionFormats optionally adds WebVTT/SRT captions built from the redacted transcript.
+func writeRecordingExport(sessionAudioPath string, outputDir string, format string, captionFormats []string) (recording.Sidecar, error) {
+	audio, err := recording.LoadSessionAudio(sessionAudioPath)
+	if err != nil {
+		return recording.Sidecar{}, err
+	}
+	purpose := strings.TrimSpace(os.Getenv(envRecordingExportPurpose))
+	if purpose == "" {
+		purpose = "eval"
+	}
+	exporter := recording.NewExporter(replaycmp.DefaultAccessPolicy(audio.TenantID))
+	return exporter.Export(audio, recording.Request{
+		Format:         format,
+		CaptionFormats: captionFormats,
+		Access: obs.ReplayAccessRequest{
+			TenantID:          audio.TenantID,
+			PrincipalID:       strings.TrimSpace(os.Getenv(envRecordingExportPrincipal)),
+			Role:              strings.TrimSpace(os.Getenv(envRecordingExportRole)),
+			Purpose:           purpose,
+			RequestedScope:    audio.SessionID,
+			RequestedFidelity: obs.ReplayFidelityL0,
+		},
+	}, outputDir)
+}
+
+// writeScrubbedArtifact writes a shareable copy of a JSON debug bundle or replay artifact with
+// identifiers tokenized and payloads reduced to class+hash. The input is never overwritten.
+func writeScrubbedArtifact(inputPath string, outputPath string) (scrub.Report, error) {
+	if filepath.Clean(inputPath) == filepath.Clean(outputPath) {
+		return scrub.Report{}, fmt.Errorf("scrub output must not overwrite the input artifact %s", inputPath)
+	}
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return scrub.Report{}, err
+	}
+	salt := []byte(os.Getenv(scrub.EnvSalt))
+	if len(salt) == 0 {
+		salt = make([]byte, 32)
+		if _, err := rand.Read(salt); err != nil {
+			return scrub.Report{}, err
+		}
+	}
+	scrubbed, report, err := scrub.JSON(data, scrub.Config{Salt: salt})
+	if err != nil {
+		return scrub.Report{}, fmt.Errorf("scrub %s: %w", inputPath, err)
+	}
+	return report, artifactwriter.WriteFile(outputPath, scrubbed)
+}
+
+// shareablePathFor derives the default scrub output path beside the input artifact.
+func shareablePathFor(inputPath string) string {
+	return strings.TrimSuffix(inputPath, ".json") + ".shareable.json"
+}
+
+// writeArtifactBundle packages paths into a portable bundle. Without paths it bundles the
+// default runtime baseline, regression report, and fixture metadata that exist.
+func writeArtifactBundle(outputPath string, paths []string, environment string, now time.Time) (artifactbundle.Manifest, error) {
+	if len(paths) == 0 {
+		for _, candidate := range []string{
+			toolingrelease.EnvironmentArtifactPath(environment, defaultRuntimeBaselineArtifactPath),
+			toolingrelease.EnvironmentArtifactPath(environment, defaultReplayRegressionReportPath),
+			defaultReplayMetadataPath,
+		} {
+			if _, err := os.Stat(candidate); err == nil {
+				paths = append(paths, candidate)
+			}
+		}
+	}
+	manifest, err := artifactbundle.Export(outputPath, artifactbundle.ExportOptions{
+		Paths:       paths,
+		Environment: environment,
+		Now:         func() time.Time { return now },
+	})
+	if err != nil {
+		return artifactbundle.Manifest{}, err
+	}
+	return manifest, artifactwriter.Record(outputPath)
+}
+
+// renderArtifactVerification lists integrity problems, failures first, then a status line.
+func renderArtifactVerification(verification artifactwriter.Verification) string {
+	lines := []string{fmt.Sprintf("artifact index %s: %d artifacts checked", verification.IndexPath, verification.Checked)}
+	for _, failing := range []bool{true, false} {
+		for _, problem := range verification.Problems {
+			if (problem.Kind != artifactwriter.ProblemUnindexed) != failing {
+				continue
+			}
+			line := fmt.Sprintf("  %s %s", problem.Kind, problem.Path)
+			if problem.Detail != "" {
+				line += " (" + problem.Detail + ")"
+			}
+			lines = append(lines, line)
+		}
+	}
+	if verification.Passed() {
+		lines = append(lines, "Status: PASS")
+	} else {
+		lines = append(lines, "Status: FAIL")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func printArtifactManifest(manifest artifactbundle.Manifest) {
+	for _, entry := range manifest.Entries {
+		schema := entry.ArtifactSchemaVersion
+		if schema == "" {
+			schema = "-"
+		}
+		fmt.Printf("  %s kind=%s schema=%s sha256=%s size=%d\n", entry.Path, entry.Kind, schema, entry.SHA256[:12], entry.SizeBytes)
+	}
+}
+
+// writeRunbookDecisions evaluates operator runbook actions against SLO gate violations
+// and records every matched action as an ops decision artifact. Auto-executed breaker and
+// weight actions are applied to the runtime instance on adminSocketPath.
+func writeRunbookDecisions(outputPath string, runbookConfigPath string, sloGatesReportPath string, adminSocketPath string, now time.Time) error {
+	cfg, err := runbook.LoadConfig(runbookConfigPath)
+	if err != nil {
+		return err
+	}
+	raw, err := os.ReadFile(sloGatesReportPath)
+	if err != nil {
+		return fmt.Errorf("read slo gates report %s: %w", sloGatesReportPath, err)
+	}
+	upgraded, err := schemaregistry.Default().Upgrade(schemaregistry.KindSLOGatesReport, raw)
+	if err != nil {
+		return fmt.Errorf("slo gates report %s: %w", sloGatesReportPath, err)
+	}
+	var slo sloGateArtifact
+	if err := json.Unmarshal(upgraded.Raw, &slo); err != nil {
+		return fmt.Errorf("decode slo gates report %s: %w", sloGatesReportPath, err)
+	}
+	violations := append([]string{}, slo.Report.Violations...)
+	if slo.Quality != nil {
+		violations = append(violations, slo.Quality.Violations...)
+	}
+
+	admin := runbook.AdminExecutor{SocketPath: adminSocketPath}
+	executors := map[runbook.ActionKind]runbook.Executor{
+		runbook.ActionOpenBreaker:          admin,
+		runbook.ActionShiftProviderWeights: admin,
+		runbook.ActionScaleHintWebhook:     runbook.WebhookExecutor{},
+	}
+	if err := cfg.ValidateExecutors(executors); err != nil {
+		return fmt.Errorf("runbook config %s: %w", runbookConfigPath, err)
+	}
+	decisions, err := runbook.Evaluate(context.Background(), cfg, violations, executors, now)
+	if err != nil {
+		return err
+	}
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return err
+	}
+	artifact := runbookDecisionsArtifact{
+		Environment:        environment,
+		GeneratedAtUTC:     now.UTC().Format(time.RFC3339),
+		RunbookConfigPath:  runbookConfigPath,
+		SLOGatesReportPath: sloGatesReportPath,
+		Mode:               cfg.Mode,
+		Violations:         violations,
+		Decisions:          decisions,
+	}
+
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return err
+	}
+	return pair.Write(artifact, renderRunbookSummary(artifact))
+}
+
+// writeLLMEvalReport scores the LLM eval suite with the responder selected by responderMode
+// (see llmEvalResponder), judged by judgeProvider when set.
+func writeLLMEvalReport(outputPath string, suitePath string, responderMode string, judgeProvider string) error {
+	if suitePath == "" {
+		suitePath = llmeval.DefaultSuitePath
+	}
+	resolvedSuitePath, err := resolveProjectRelativePath(suitePath)
+	if err != nil {
+		return fmt.Errorf("resolve llm eval suite: %w", err)
+	}
+	suite, err := llmeval.LoadSuite(resolvedSuitePath)
+	if err != nil {
+		return err
+	}
+
+	mode, providerIDs, responder, scorer, err := llmEvalResponder(responderMode, judgeProvider, suite)
+	if err != nil {
+		return err
+	}
+	report := llmeval.Evaluate(suite, providerIDs, responder, scorer)
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return err
+	}
+	artifact := llmEvalReportArtifact{
+		Environment:    environment,
+		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
+		SuitePath:      suitePath,
+		Responder:      mode,
+		JudgeProvider:  strings.TrimSpace(judgeProvider),
+		Report:         report,
+		Passed:         report.Passed,
+	}
+
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := pair.Write(artifact, renderLLMEvalSummary(artifact)); err != nil {
+		return err
+	}
+
+	if !artifact.Passed {
+		return exitcode.Gatef("llm eval gate failed: %v", artifact.Report.Violations)
+	}
+	return nil
+}
+
+// llmEvalResponder resolves the LLM eval responder and scorer. The default providers mode runs
+// suite prompts through the invocation controller against every LLM adapter of the configured
+// provider profile and grades replies with the rubric assertions and, when judgeProvider is
+// set, the strictest of the assertions and that provider's judgement; recorded mode scores the
+// suite recorded responses without calling any provider and must be selected explicitly.
+func llmEvalResponder(mode string, judgeProvider string, suite llmeval.Suite) (string, []string, llmeval.Responder, llmeval.Scorer, error) {
+	judgeProvider = strings.TrimSpace(judgeProvider)
+	switch mode = strings.ToLower(strings.TrimSpace(mode)); mode {
+	case "", llmeval.ProviderResponderMode:
+		providers, err := providerbootstrap.BuildProfile(providerbootstrap.ProfileFromEnv())
+		if err != nil {
+			return "", nil, nil, nil, fmt.Errorf("llm eval providers: %w", err)
+		}
+		ids, err := providers.Catalog.ProviderIDs(contracts.ModalityLLM)
+		if err != nil {
+			return "", nil, nil, nil, fmt.Errorf("llm eval providers: %w", err)
+		}
+		responder := llmeval.InvocationResponder{Invoker: providers.Controller}
+		var scorer llmeval.Scorer = llmeval.AssertionScorer{}
+		if judgeProvider != "" {
+			if !slices.Contains(ids, judgeProvider) {
+				return "", nil, nil, nil, exitcode.Usagef("%s %q is not a configured llm provider (have %s)", envLLMEvalJudgeProvider, judgeProvider, strings.Join(ids, ","))
+			}
+			judge := llmeval.JudgeScorer{Judge: llmeval.LLMJudge{Responder: responder, ProviderID: judgeProvider}}
+			scorer = llmeval.StrictestScorer{Scorers: []llmeval.Scorer{llmeval.AssertionScorer{}, judge}}
+		}
+		return llmeval.ProviderResponderMode, ids, responder, scorer, nil
+	case llmeval.RecordedResponderMode:
+		if judgeProvider != "" {
+			return "", nil, nil, nil, exitcode.Usagef("%s requires %s=%s", envLLMEvalJudgeProvider, envLLMEvalResponder, llmeval.ProviderResponderMode)
+		}
+		return mode, suite.RecordedProviderIDs(), llmeval.RecordedResponder{}, llmeval.AssertionScorer{}, nil
+	default:
+		return "", nil, nil, nil, exitcode.Usagef("unsupported %s %q (expected %s|%s)", envLLMEvalResponder, mode, llmeval.ProviderResponderMode, llmeval.RecordedResponderMode)
+	}
+}
+
+// writeRedactionEvalReport scores the transcript redaction rules against the labeled PII/PHI
+// suite and fails when a class falls below its precision or recall threshold.
+func writeRedactionEvalReport(outputPath string, suitePath string) error {
+	if suitePath == "" {
+		suitePath = redactioneval.DefaultSuitePath
+	}
+	resolvedSuitePath, err := resolveProjectRelativePath(suitePath)
+	if err != nil {
+		return fmt.Errorf("resolve redaction eval suite: %w", err)
+	}
+	suite, err := redactioneval.LoadSuite(resolvedSuitePath)
+	if err != nil {
+		return err
+	}
+
+	report := redactioneval.Evaluate(suite, piidetect.NewDefaultDetector())
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return err
+	}
+	artifact := redactionEvalReportArtifact{
+		Environment:    environment,
+		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
+		SuitePath:      suitePath,
+		Report:         report,
+		Passed:         report.Passed,
+	}
+
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := pair.Write(artifact, renderRedactionEvalSummary(artifact)); err != nil {
+		return err
+	}
+
+	if !artifact.Passed {
+		return exitcode.Gatef("redaction eval gate failed: %v", artifact.Report.Violations)
+	}
+	return nil
+}
+
+func writeContractsReport(outputPath string, fixtureRoot string) error {
+	if fixtureRoot == "" {
+		fixtureRoot = filepath.Join("test", "contract", "fixtures")
+	}
+	resolvedFixtureRoot, err := resolveProjectRelativePath(fixtureRoot)
+	if err != nil {
+		return err
+	}
+	schemaPath, err := resolveProjectRelativePath(filepath.Join("docs", "ContractArtifacts.schema.json"))
+	if err != nil {
+		return err
+	}
+
+	summary, err := validation.ValidateContractFixturesWithSchema(schemaPath, resolvedFixtureRoot)
+	if err != nil {
+		return err
+	}
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return err
+	}
+	artifact := contractsReportArtifact{
+		Environment:    environment,
+		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
+		FixtureRoot:    resolvedFixtureRoot,
+		Summary:        summary,
+		Passed:         summary.Failed == 0,
+	}
+
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := pair.Write(artifact, renderContractsReportSummary(artifact)); err != nil {
+		return err
+	}
+	if !artifact.Passed {
+		return exitcode.Gatef("contract fixtures failed: %d failures", artifact.Summary.Failed)
+	}
+	return nil
+}
+
+// writeContractCoverageReport maps valid contract fixtures onto the schema enums and fields
+// and fails when coverage is below minRatio.
+func writeContractCoverageReport(outputPath string, fixtureRoot string, minRatio float64) error {
+	if fixtureRoot == "" {
+		fixtureRoot = filepath.Join("test", "contract", "fixtures")
+	}
+	resolvedFixtureRoot, err := resolveProjectRelativePath(fixtureRoot)
+	if err != nil {
+		return err
+	}
+	schemaPath, err := resolveProjectRelativePath(filepath.Join("docs", "ContractArtifacts.schema.json"))
+	if err != nil {
+		return err
+	}
+
+	report, err := validation.AnalyzeContractCoverage(schemaPath, resolvedFixtureRoot, minRatio)
+	if err != nil {
+		return err
+	}
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return err
+	}
+	artifact := contractCoverageReportArtifact{
+		Environment:    environment,
+		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
+		FixtureRoot:    resolvedFixtureRoot,
+		Report:         report,
+	}
+
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := pair.Write(artifact, renderContractCoverageSummary(artifact)); err != nil {
+		return err
+	}
+	if !report.Passed {
+		return exitcode.Gatef("contract fixture coverage %.2f below required %.2f", report.CoverageRatio, report.MinRatio)
+	}
+	return nil
+}
+
+// writeSpecReport checks per-node latency/cost budgets in a pipeline spec against the
+// default provider capability metadata and fails when a binding cannot meet its budget.
+func writeSpecReport(outputPath string, specPath string) error {
+	resolvedSpecPath, err := resolveProjectRelativePath(specPath)
+	if err != nil {
+		return err
+	}
+	spec, err := validation.LoadPipelineSpec(resolvedSpecPath)
+	if err != nil {
+		return err
+	}
+	report, err := validation.ValidatePipelineSpec(spec, providerregistry.DefaultCapabilities())
+	if err != nil {
+		return err
+	}
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return err
+	}
+	artifact := specReportArtifact{
+		Environment:    environment,
+		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
+		SpecPath:       resolvedSpecPath,
+		Report:         report,
+	}
+
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := pair.Write(artifact, renderSpecReportSummary(artifact)); err != nil {
+		return err
+	}
+	if !report.Passed {
+		return exitcode.Gatef("pipeline spec %s promises budgets its bindings cannot meet: %d findings", report.PipelineVersion, len(report.Findings))
+	}
+	return nil
+}
+
+// writeBindingsReport dry-resolves a pipeline spec's provider bindings against the provider
+// catalog this environment would register, including the provider profile and enable-flag
+// state.
+func writeBindingsReport(outputPath string, specPath string) error {
+	resolvedSpecPath, err := resolveProjectRelativePath(specPath)
+	if err != nil {
+		return err
+	}
+	spec, err := validation.LoadPipelineSpec(resolvedSpecPath)
+	if err != nil {
+		return err
+	}
+	profile := providerbootstrap.ProfileFromEnv()
+	providers, err := providerbootstrap.BuildProfile(profile)
+	if err != nil {
+		return err
+	}
+	disabled := make([]validation.DisabledProvider, 0)
+	for _, optional := range providerbootstrap.OptionalProviders() {
+		if profile == providerbootstrap.ProfileMVP && !optional.Enabled {
+			disabled = append(disabled, validation.DisabledProvider{ProviderID: optional.ProviderID, Modality: optional.Modality, EnableEnv: optional.EnableEnv})
+		}
+	}
+	report, err := validation.ValidateSpecBindings(spec, providers.Catalog, disabled)
+	if err != nil {
+		return err
+	}
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return err
+	}
+	artifact := bindingsReportArtifact{
+		Environment:       environment,
+		GeneratedAtUTC:    time.Now().UTC().Format(time.RFC3339),
+		SpecPath:          resolvedSpecPath,
+		DisabledProviders: disabled,
+		Report:            report,
+	}
+
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := pair.Write(artifact, renderBindingsReportSummary(artifact)); err != nil {
+		return err
+	}
+	if !report.Passed {
+		return exitcode.Gatef("pipeline spec %s binds providers the catalog cannot serve: %d findings", report.PipelineVersion, len(report.Findings))
+	}
+	return nil
+}
+
+// parseSpecLintFlags splits validate-spec's --lint and --fix flags from its positional
+// arguments; --fix implies --lint.
+func parseSpecLintFlags(args []string) ([]string, bool, bool) {
+	positional := make([]string, 0, len(args))
+	lint, fix := false, false
+	for _, arg := range args {
+		switch arg {
+		case "--lint":
+			lint = true
+		case "--fix":
+			lint, fix = true, true
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	return positional, lint, fix
+}
+
+// writeSpecLintReport lints a pipeline spec and writes the applied and skipped suggestions.
+// With fix set, safe fixes are written back to the spec file before it is validated. Lint
+// suggestions are advisory and never fail the command.
+func writeSpecLintReport(outputPath string, specPath string, fix bool) error {
+	resolvedSpecPath, err := resolveProjectRelativePath(specPath)
+	if err != nil {
+		return err
+	}
+	spec, err := validation.LoadPipelineSpec(resolvedSpecPath)
+	if err != nil {
+		return err
+	}
+	fixed, report := validation.LintPipelineSpec(spec, fix)
+	if report.Applied > 0 {
+		if err := artifactwriter.WriteJSON(resolvedSpecPath, fixed); err != nil {
+			return err
+		}
+	}
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return err
+	}
+	artifact := specLintReportArtifact{
+		Environment:    environment,
+		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
+		SpecPath:       resolvedSpecPath,
+		Report:         report,
+	}
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return err
+	}
+	return pair.Write(artifact, renderSpecLintSummary(artifact))
+}
+
+// publishGateReport hands a completed gate command's artifacts to the report sinks named by
+// RSPP_REPORT_SINKS_CONFIG. Sink failures are reported but never change the gate outcome.
+func publishGateReport(command string, environment string, outputPath string, gateErr error) {
+	if _, err := os.Stat(outputPath); err != nil {
+		return
+	}
+	cfg, err := reportsink.ConfigFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "report sinks skipped: %v\n", err)
+		return
+	}
+	sinks, err := reportsink.Build(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "report sinks skipped: %v\n", err)
+		return
+	}
+	report := newGateReport(command, environment, outputPath, gateErr, time.Now())
+	if err := reportsink.Dispatch(context.Background(), sinks, report); err != nil {
+		fmt.Fprintf(os.Stderr, "report sink failed: %v\n", err)
+	}
+}
+
+// reportPair resolves the JSON/Markdown pair a report command writes to outputPath, honoring
+// RSPP_SUMMARY_PATH and rejecting colliding paths.
+func reportPair(outputPath string) (artifactwriter.Pair, error) {
+	return artifactwriter.NewPair(outputPath, os.Getenv(artifactwriter.EnvSummaryPath))
+}
+
+// summaryPathFor returns the Markdown summary path paired with outputPath.
+func summaryPathFor(outputPath string) string {
+	return artifactwriter.SummaryPath(outputPath, os.Getenv(artifactwriter.EnvSummaryPath))
+}
+
+func newGateReport(command string, environment string, outputPath string, gateErr error, now time.Time) reportsink.Report {
+	report := reportsink.Report{
+		Command:        command,
+		Environment:    environment,
+		ArtifactPath:   outputPath,
+		Passed:         gateErr == nil,
+		CompletedAtUTC: now.UTC().Format(time.RFC3339),
+	}
+	if gateErr != nil {
+		report.Detail = gateErr.Error()
+	}
+	summaryPath := summaryPathFor(outputPath)
+	if raw, err := os.ReadFile(summaryPath); err == nil {
+		report.SummaryPath = summaryPath
+		report.Summary = string(raw)
+	}
+	return report
+}
+
+func resolveProjectRelativePath(path string) (string, error) {
+	trimmed := strings.TrimSpace(path)
+	if trimmed == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	if filepath.IsAbs(trimmed) {
+		return trimmed, nil
+	}
+	if _, err := os.Stat(trimmed); err == nil {
+		return trimmed, nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	dir := wd
+	for {
+		candidate := filepath.Join(dir, trimmed)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return "", fmt.Errorf("path not found: %s", trimmed)
+}
+
+func writeReleaseManifest(
+	outputPath string,
+	specRef string,
+	rolloutConfigPath string,
+	contractsReportPath string,
+	replayRegressionReportPath string,
+	sloGatesReportPath string,
+	llmEvalReportPath string,
+	now time.Time,
+) (toolingrelease.ReleaseManifest, error) {
+	rolloutCfg, rolloutSource, err := toolingrelease.LoadRolloutConfig(rolloutConfigPath)
+	if err != nil {
+		return toolingrelease.ReleaseManifest{}, err
+	}
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return toolingrelease.ReleaseManifest{}, err
+	}
+
+	readiness, sources := toolingrelease.EvaluateReadiness(toolingrelease.ReadinessInput{
+		Environment:                environment,
+		ContractsReportPath:        contractsReportPath,
+		ReplayRegressionReportPath: replayRegressionReportPath,
+		SLOGatesReportPath:         sloGatesReportPath,
+		LLMEvalReportPath:          llmEvalReportPath,
+		Now:                        now,
+		MaxArtifactAge:             toolingrelease.DefaultMaxArtifactAge,
+	})
+	breakGlassRequest, breakGlassRequested := toolingrelease.BreakGlassRequestFromEnv()
+	var breakGlass toolingrelease.BreakGlassRecord
+	operatorTokenSHA256 := ""
+	if breakGlassRequested {
+		operators, err := toolingrelease.ParseBreakGlassOperators(os.Getenv(toolingrelease.EnvBreakGlassOperators))
+		if err != nil {
+			return toolingrelease.ReleaseManifest{}, err
+		}
+		if operatorTokenSHA256, err = toolingrelease.AuthorizeBreakGlass(breakGlassRequest, operators); err != nil {
+			return toolingrelease.ReleaseManifest{}, err
+		}
+		if readiness, breakGlass, err = toolingrelease.ApplyBreakGlass(readiness, breakGlassRequest); err != nil {
+			return toolingrelease.ReleaseManifest{}, err
+		}
+	}
+	if !readiness.Passed {
+		return toolingrelease.ReleaseManifest{}, exitcode.Gatef("release readiness failed: %v", readiness.Violations)
+	}
+
+	if sources == nil {
+		sources = make(map[string]toolingrelease.ArtifactSource, 4)
+	}
+	sources["rollout_config"] = rolloutSource
+
+	manifest, err := toolingrelease.BuildReleaseManifest(specRef, rolloutCfg, readiness, sources, now)
+	if err != nil {
+		return toolingrelease.ReleaseManifest{}, err
+	}
+	specHash, err := storeReleaseSpec(specRef, environment)
+	if err != nil {
+		return toolingrelease.ReleaseManifest{}, err
+	}
+	manifest.SpecHash = specHash
+
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return toolingrelease.ReleaseManifest{}, err
+	}
+	if breakGlassRequested {
+		// The audit entry is appended before the manifest exists, so no break-glass release
+		// can be handed off without its link in the chain.
+		auditPath := strings.TrimSpace(os.Getenv(toolingrelease.EnvBreakGlassAuditLog))
+		if auditPath == "" {
+			auditPath = toolingrelease.EnvironmentArtifactPath(environment, toolingrelease.DefaultBreakGlassAuditLogPath)
+		}
+		entry, err := toolingrelease.AppendBreakGlassAudit(auditPath, toolingrelease.BreakGlassAuditEntry{
+			Environment:         environment,
+			ReleaseID:           manifest.ReleaseID,
+			SpecRef:             manifest.SpecRef,
+			PipelineVersion:     manifest.RolloutConfig.PipelineVersion,
+			Operator:            breakGlass.Operator,
+			OperatorTokenSHA256: operatorTokenSHA256,
+			Reason:              breakGlass.Reason,
+			BypassedGates:       breakGlass.BypassedGates,
+			BypassedViolations:  breakGlass.BypassedViolations,
+		}, now)
+		if err != nil {
+			return toolingrelease.ReleaseManifest{}, err
+		}
+		breakGlass.AuditLogPath = auditPath
+		breakGlass.AuditSequence = entry.Sequence
+		breakGlass.AuditEntryHash = entry.EntryHash
+		manifest.BreakGlass = &breakGlass
+	}
+	if err := pair.Write(manifest, renderReleaseManifestSummary(manifest)); err != nil {
+		return toolingrelease.ReleaseManifest{}, err
+	}
+	return manifest, nil
+}
+
+// storeReleaseSpec stores the spec named by specRef in the control-plane spec store when it
+// is a local file, returning its spec_hash. Non-file refs are published as pointers only.
+func storeReleaseSpec(specRef string, environment string) (string, error) {
+	info, err := os.Stat(specRef)
+	if err != nil || !info.Mode().IsRegular() {
+		return "", nil
+	}
+	content, err := os.ReadFile(specRef)
+	if err != nil {
+		return "", fmt.Errorf("read spec %s: %w", specRef, err)
+	}
+	store, err := specStoreForEnvironment(environment)
+	if err != nil {
+		return "", err
+	}
+	specHash, err := store.Put(content)
+	if err != nil {
+		return "", fmt.Errorf("store spec %s: %w", specRef, err)
+	}
+	return specHash, nil
+}
+
+// writePublishedSpec fetches an integrity-verified spec by spec_hash and writes it to
+// outputPath, or to stdout when outputPath is empty.
+func writePublishedSpec(specHash string, outputPath string, environment string, stdout io.Writer) error {
+	store, err := specStoreForEnvironment(environment)
+	if err != nil {
+		return err
+	}
+	content, err := store.Get(strings.TrimSpace(specHash))
+	if err != nil {
+		return err
+	}
+	if outputPath == "" {
+		_, err := stdout.Write(content)
+		return err
+	}
+	return artifactwriter.WriteFile(outputPath, content)
+}
+
+func specStoreForEnvironment(environment string) (specstore.FileStore, error) {
+	if strings.TrimSpace(os.Getenv(specstore.EnvStoreDir)) != "" {
+		return specstore.NewFileStoreFromEnv()
+	}
+	return specstore.NewFileStore(toolingrelease.EnvironmentArtifactPath(environment, specstore.DefaultStoreDir))
+}
+
+// writeRollbackReport executes the manifest's rollback and writes the report even when a
+// step fails, so operators can see how far the rollback got.
+func writeRollbackReport(
+	outputPath string,
+	manifestPath string,
+	distributionPath string,
+	metadataPath string,
+	now time.Time,
+) (toolingrelease.RollbackReport, error) {
+	manifest, manifestSource, err := toolingrelease.LoadReleaseManifest(manifestPath)
+	if err != nil {
+		return toolingrelease.RollbackReport{}, err
+	}
+	environment, err := toolingrelease.EnvironmentFromEnv()
+	if err != nil {
+		return toolingrelease.RollbackReport{}, err
+	}
+	smokePath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + "-smoke-report.json"
+
+	report, rollbackErr := toolingrelease.ExecuteRollback(toolingrelease.RollbackInput{
+		Environment:      environment,
+		Manifest:         manifest,
+		ManifestSource:   manifestSource,
+		DistributionPath: distributionPath,
+		SmokeGate: func(pipelineVersion string) (toolingrelease.ArtifactSource, error) {
+			if err := writeReplaySmokeReport(smokePath, metadataPath, pipelineVersion); err != nil {
+				return toolingrelease.ArtifactSource{Path: smokePath}, err
+			}
+			raw, err := os.ReadFile(smokePath)
+			if err != nil {
+				return toolingrelease.ArtifactSource{Path: smokePath}, err
+			}
+			sum := sha256.Sum256(raw)
+			return toolingrelease.ArtifactSource{Path: smokePath, SHA256: hex.EncodeToString(sum[:])}, nil
+		},
+		Now: now,
+	})
+
+	pair, err := reportPair(outputPath)
+	if err != nil {
+		return report, err
+	}
+	if err := pair.Write(report, renderRollbackSummary(report)); err != nil {
+		return report, err
+	}
+	return report, rollbackErr
+}
+
+func renderRollbackSummary(report toolingrelease.RollbackReport) string {
+	lines := []string{
+		"# Release Rollback",
+		"",
+		"Generated at (UTC): " + report.GeneratedAtUTC,
+		"Release ID: " + report.ReleaseID,
+		"From pipeline version: " + report.FromPipelineVersion,
+		"To pipeline version: " + report.ToPipelineVersion,
+		"Previous active version: " + report.PreviousActiveVersion,
+		"Distribution: " + report.DistributionPath,
+		"",
+		"## Steps",
+	}
+	for _, step := range report.Steps {
+		status := "PASS"
+		if !step.Passed {
+			status = "FAIL"
+		}
+		line := fmt.Sprintf("- %s: %s", step.Name, status)
+		if step.Detail != "" {
+			line += " - " + step.Detail
+		}
+		lines = append(lines, line)
+	}
+	if report.Passed {
+		lines = append(lines, "", "Status: PASS")
+	} else {
+		lines = append(lines, "", "Status: FAIL")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func writeRuntimeBaselineArtifact(outputPath string) error {
+	_, err := generateRuntimeBaselineArtifact(outputPath)
+	return err
+}
+
+func loadRuntimeBaselineEntries(baselineArtifactPath string) ([]timeline.BaselineEvidence, string, error) {
+	if baselineArtifactPath == "" {
+		baselineArtifactPath = defaultRuntimeBaselineArtifactPath
+	}
+
+	artifact, err := timeline.ReadBaselineArtifact(baselineArtifactPath)
+	if err != nil {
+		return nil, baselineArtifactPath, fmt.Errorf("load runtime baseline artifact %s: %w", baselineArtifactPath, err)
+	}
+	return artifact.Entries, baselineArtifactPath, nil
+}
+
+func generateRuntimeBaselineArtifact(baselineArtifactPath string) ([]timeline.BaselineEvidence, error) {
+	if baselineArtifactPath == "" {
+		baselineArtifactPath = defaultRuntimeBaselineArtifactPath
+	}
+	recorder := timeline.NewRecorder(timeline.StageAConfig{
 		BaselineCapacity: 64,
 		DetailCapacity:   128,
 	})
@@ -1545,6 +3658,9 @@ func generateRuntimeBaselineArtifact(baselineArtifactPath string) ([]timeline.Ba
 	if err := timeline.WriteBaselineArtifact(baselineArtifactPath, entries); err != nil {
 		return nil, err
 	}
+	if err := artifactwriter.Record(baselineArtifactPath); err != nil {
+		return nil, err
+	}
 	return entries, nil
 }
 
@@ -1574,31 +3690,85 @@ func sloAdmitDecision(sessionID string, turnID string, eventID string, runtimeMS
 	}
 }
 
-func toTurnMetrics(entries []timeline.BaselineEvidence) []ops.TurnMetrics {
-	samples := make([]ops.TurnMetrics, 0, len(entries))
+func toTurnFailureSamples(entries []timeline.BaselineEvidence) []ops.TurnFailureSample {
+	samples := make([]ops.TurnFailureSample, 0, len(entries))
+	for _, entry := range entries {
+		sample := ops.TurnFailureSample{
+			SessionID:       entry.SessionID,
+			TurnID:          entry.TurnID,
+			TerminalOutcome: entry.TerminalOutcome,
+			TerminalReason:  entry.TerminalReason,
+			Decisions:       entry.DecisionOutcomes,
+			Signals:         entry.ControlSignals,
+		}
+		switch {
+		case entry.TurnOpenProposedAtMS != nil:
+			sample.AtMS = *entry.TurnOpenProposedAtMS
+		case len(entry.DecisionOutcomes) > 0:
+			sample.AtMS = entry.DecisionOutcomes[0].RuntimeTimestampMS
+		}
+		for _, invocation := range entry.InvocationOutcomes {
+			if invocation.OutcomeClass == "success" {
+				continue
+			}
+			sample.FailedInvocations = append(sample.FailedInvocations, ops.FailedInvocation{
+				Modality:      invocation.Modality,
+				ProviderID:    invocation.ProviderID,
+				OutcomeClass:  invocation.OutcomeClass,
+				RetryDecision: invocation.RetryDecision,
+			})
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// toTenantTurnSamples counts a turn as available when it was accepted and did not end in a
+// non-cancel abort, matching the turn-success SLO events.
+func toTenantTurnSamples(entries []timeline.BaselineEvidence) []ops.TenantTurnSample {
+	samples := make([]ops.TenantTurnSample, 0, len(entries))
 	for _, entry := range entries {
-		terminalEvents := []string{entry.TerminalOutcome}
-		if entry.CloseEmitted {
-			terminalEvents = append(terminalEvents, "close")
-		}
-		sample := ops.TurnMetrics{
-			TurnID:                   entry.TurnID,
-			Accepted:                 entry.IsAcceptedTurn(),
-			HappyPath:                entry.TurnOpenAtMS != nil && entry.FirstOutputAtMS != nil,
-			TurnOpenProposedAtMS:     entry.TurnOpenProposedAtMS,
-			TurnOpenAtMS:             entry.TurnOpenAtMS,
-			FirstOutputAtMS:          entry.FirstOutputAtMS,
-			CancelAcceptedAtMS:       entry.CancelAcceptedAtMS,
-			CancelFenceAppliedAtMS:   entry.CancelFenceAppliedAtMS,
-			BaselineComplete:         entry.ValidateCompleteness() == nil,
-			AcceptedStaleEpochOutput: entry.AcceptedStaleEpochOutput,
-			TerminalEvents:           terminalEvents,
+		sample := ops.TenantTurnSample{
+			SessionID: entry.SessionID,
+			TurnID:    entry.TurnID,
+			SLA:       entry.SLA,
+			Available: entry.IsAcceptedTurn() && (entry.TerminalOutcome != "abort" || entry.TerminalReason == "cancelled"),
+		}
+		switch {
+		case entry.TurnOpenProposedAtMS != nil:
+			sample.AtMS = *entry.TurnOpenProposedAtMS
+		case len(entry.DecisionOutcomes) > 0:
+			sample.AtMS = entry.DecisionOutcomes[0].RuntimeTimestampMS
+		}
+		if entry.TurnOpenAtMS != nil && entry.FirstOutputAtMS != nil {
+			latency := *entry.FirstOutputAtMS - *entry.TurnOpenAtMS
+			sample.FirstOutputLatencyMS = &latency
 		}
 		samples = append(samples, sample)
 	}
 	return samples
 }
 
+// toSLOEvents counts accepted turns as turn-success SLO events (bad on non-cancel abort)
+// and evaluates at the latest observed turn so budget windows track the trend data itself.
+func toSLOEvents(entries []timeline.BaselineEvidence) ([]ops.SLOEvent, int64) {
+	events := make([]ops.SLOEvent, 0, len(entries))
+	evaluatedAtMS := int64(0)
+	for _, entry := range entries {
+		if !entry.IsAcceptedTurn() {
+			continue
+		}
+		atMS := int64(0)
+		if entry.TurnOpenProposedAtMS != nil {
+			atMS = *entry.TurnOpenProposedAtMS
+		}
+		evaluatedAtMS = max(evaluatedAtMS, atMS)
+		good := entry.TerminalOutcome != "abort" || entry.TerminalReason == "cancelled"
+		events = append(events, ops.SLOEvent{AtMS: atMS, Good: good})
+	}
+	return events, evaluatedAtMS
+}
+
 func renderSLOGatesSummary(artifact sloGateArtifact) string {
 	report := artifact.Report
 	lines := []string{
@@ -1614,27 +3784,595 @@ func renderSLOGatesSummary(artifact sloGateArtifact) string {
 		fmt.Sprintf("Stale accepted outputs: %d", report.StaleAcceptedOutputs),
 		fmt.Sprintf("Terminal correctness: %.2f", report.TerminalCorrectnessRatio),
 	}
+	if report.LengthCappedTurns > 0 {
+		lines = append(lines, fmt.Sprintf("Length-capped turns: %d", report.LengthCappedTurns))
+	}
 	if report.TurnOpenDecisionP95MS != nil {
 		lines = append(lines, fmt.Sprintf("Turn-open p95: %d ms", *report.TurnOpenDecisionP95MS))
 	}
 	if report.FirstOutputP95MS != nil {
 		lines = append(lines, fmt.Sprintf("First-output p95: %d ms", *report.FirstOutputP95MS))
 	}
+	if report.PerceivedFirstAudioP95MS != nil {
+		lines = append(lines, fmt.Sprintf("Perceived first-audio p95: %d ms (playback-acked turns: %d)", *report.PerceivedFirstAudioP95MS, report.PlaybackAckedTurns))
+	}
+	if report.BargeInTurns > 0 {
+		lines = append(lines, fmt.Sprintf("Barge-in turns: %d", report.BargeInTurns))
+	}
 	if report.CancelFenceP95MS != nil {
 		lines = append(lines, fmt.Sprintf("Cancel-fence p95: %d ms", *report.CancelFenceP95MS))
 	}
 
-	if report.Passed {
+	if len(report.ByPipelineVersion) > 0 {
+		lines = append(lines, "", "## By pipeline version")
+		versions := make([]string, 0, len(report.ByPipelineVersion))
+		for version := range report.ByPipelineVersion {
+			versions = append(versions, version)
+		}
+		sort.Strings(versions)
+		for _, version := range versions {
+			slice := report.ByPipelineVersion[version]
+			status := "PASS"
+			if !slice.Passed {
+				status = "FAIL"
+			}
+			lines = append(lines, fmt.Sprintf("- %s samples=%d accepted=%d %s", version, slice.Samples, slice.AcceptedTurns, status))
+		}
+	}
+
+	if budget := artifact.ErrorBudget; budget != nil {
+		lines = append(lines,
+			"",
+			"## Error budget",
+			fmt.Sprintf("Objective: %.4f", budget.Policy.Objective),
+			fmt.Sprintf("Events: %d (bad=%d)", budget.TotalEvents, budget.BadEvents),
+			fmt.Sprintf("Budget consumed: %.2f remaining: %.2f", budget.BudgetConsumedRatio, budget.BudgetRemainingRatio),
+			fmt.Sprintf("Burn rate fast=%.2f slow=%.2f", budget.FastBurnRate, budget.SlowBurnRate),
+		)
+		for _, alert := range budget.Alerts {
+			lines = append(lines, fmt.Sprintf("- ALERT %s burn_rate=%.2f threshold=%.2f window=%d ms", alert.Severity, alert.BurnRate, alert.Threshold, alert.WindowMS))
+		}
+	}
+
+	violations := append([]string(nil), report.Violations...)
+	if quality := artifact.Quality; quality != nil {
+		lines = append(lines, "", "## Quality", "Golden corpus: "+artifact.QualityCorpusPath)
+		if artifact.QualityTranscriber != "" {
+			lines = append(lines, "Transcriber: "+artifact.QualityTranscriber)
+		}
+		for _, provider := range quality.Providers {
+			status := "PASS"
+			if !provider.Passed {
+				status = "FAIL"
+			}
+			lines = append(lines, fmt.Sprintf("- %s WER=%.3f threshold=%.3f fixtures=%d %s", provider.ProviderID, provider.AggregateWER, provider.ThresholdWER, len(provider.Fixtures), status))
+		}
+		violations = append(violations, quality.Violations...)
+	}
+
+	if len(violations) == 0 {
+		lines = append(lines, "", "Status: PASS")
+	} else {
+		lines = append(lines, "", "Status: FAIL", "## Violations")
+		for _, violation := range violations {
+			lines = append(lines, "- "+violation)
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderFailureDomainSummary(artifact failureDomainReportArtifact) string {
+	report := artifact.Report
+	lines := []string{
+		"# Failure Domain Report",
+		"",
+		"Generated at (UTC): " + artifact.GeneratedAtUTC,
+		"Baseline artifact: " + artifact.BaselineArtifactPath,
+		fmt.Sprintf("Turns: %d", report.TotalTurns),
+		fmt.Sprintf("Failed turns: %d", report.FailedTurns),
+		fmt.Sprintf("Bucket: %d ms", report.BucketMS),
+		"",
+		"## By domain",
+	}
+	domains := make([]string, 0, len(report.ByDomain))
+	for domain := range report.ByDomain {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	for _, domain := range domains {
+		lines = append(lines, fmt.Sprintf("- %s: %d", domain, report.ByDomain[domain]))
+	}
+	if len(report.Buckets) > 0 {
+		lines = append(lines, "", "## Over time")
+		for _, bucket := range report.Buckets {
+			parts := make([]string, 0, len(bucket.ByDomain))
+			for domain, count := range bucket.ByDomain {
+				parts = append(parts, fmt.Sprintf("%s=%d", domain, count))
+			}
+			sort.Strings(parts)
+			lines = append(lines, fmt.Sprintf("- t=%d ms failed=%d %s", bucket.StartMS, bucket.FailedTurns, strings.Join(parts, " ")))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderSLASummary(artifact slaReportArtifact) string {
+	report := artifact.Report
+	lines := []string{
+		"# Tenant SLA Attainment Report",
+		"",
+		"Generated at (UTC): " + artifact.GeneratedAtUTC,
+		"Baseline artifact: " + artifact.BaselineArtifactPath,
+		fmt.Sprintf("Turns: %d", report.TotalTurns),
+		fmt.Sprintf("Untagged turns: %d", report.UntaggedTurns),
+		fmt.Sprintf("Period: %d ms", report.PeriodMS),
+	}
+	if len(report.Tenants) > 0 {
+		lines = append(lines, "", "## Tenants")
+		for _, tenant := range report.Tenants {
+			status := "met"
+			if !tenant.Overall.Met {
+				status = "missed"
+			}
+			lines = append(lines, fmt.Sprintf("- %s (%s: first output <= %d ms, availability >= %.4f): %s attainment=%.4f availability=%.4f latency_breaches=%d turns=%d",
+				tenant.TenantID, tenant.SLA.Tier, tenant.SLA.MaxFirstOutputLatencyMS, tenant.SLA.MinAvailability,
+				status, tenant.Overall.Attainment, tenant.Overall.Availability, tenant.Overall.LatencyBreaches, tenant.Overall.Turns))
+		}
+	}
+	if len(report.Misses) > 0 {
+		lines = append(lines, "", "## Missed periods")
+		for _, miss := range report.Misses {
+			lines = append(lines, fmt.Sprintf("- %s (%s) t=%d ms attainment=%.4f target=%.4f", miss.TenantID, miss.Tier, miss.StartMS, miss.Attainment, miss.Target))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderCostSummary(artifact costReportArtifact) string {
+	report := artifact.Report
+	lines := []string{
+		"# Cost Report",
+		"",
+		"Generated at (UTC): " + artifact.GeneratedAtUTC,
+		"Baseline artifact: " + artifact.BaselineArtifactPath,
+		fmt.Sprintf("Turns: %d", report.TotalTurns),
+		fmt.Sprintf("Unaccounted turns: %d", report.UnaccountedTurns),
+		"Total: " + renderCostUsage(report.Total),
+	}
+	if len(report.Tenants) > 0 {
+		lines = append(lines, "", "## Tenants")
+		for _, tenant := range report.Tenants {
+			lines = append(lines, fmt.Sprintf("- %s sessions=%d turns=%d %s", tenant.TenantID, tenant.Sessions, tenant.Turns, renderCostUsage(tenant.Usage)))
+		}
+	}
+	if len(report.Providers) > 0 {
+		lines = append(lines, "", "## Providers")
+		for _, provider := range report.Providers {
+			lines = append(lines, fmt.Sprintf("- %s (%s) attempts=%d %s", provider.ProviderID, provider.Modality, provider.Attempts, renderCostUsage(provider.Usage)))
+		}
+	}
+	if len(report.Sessions) > 0 {
+		lines = append(lines, "", "## Sessions")
+		for _, session := range report.Sessions {
+			lines = append(lines, fmt.Sprintf("- %s/%s turns=%d %s", session.TenantID, session.SessionID, session.Turns, renderCostUsage(session.Usage)))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderCostUsage(usage cost.Usage) string {
+	rendered := fmt.Sprintf("cost_usd=%.6f tokens_in=%d tokens_out=%d audio_in_ms=%d audio_out_ms=%d",
+		usage.EstimatedCostUSD, usage.InputTokens, usage.OutputTokens, usage.AudioInputMS, usage.AudioOutputMS)
+	if usage.Unpriced {
+		rendered += " (includes unpriced usage)"
+	}
+	if usage.TokensEstimated {
+		rendered += " (tokens estimated)"
+	}
+	return rendered
+}
+
+func renderDebugBundleSummary(artifact debugBundleArtifact) string {
+	scope := artifact.SessionID
+	if artifact.TurnID != "" {
+		scope += "/" + artifact.TurnID
+	}
+	lines := []string{
+		"# Debug Bundle",
+		"",
+		"Generated at (UTC): " + artifact.GeneratedAtUTC,
+		"Baseline artifact: " + artifact.BaselineArtifactPath,
+		"Scope: " + scope,
+		fmt.Sprintf("Baseline turns: %d", len(artifact.Baseline)),
+		fmt.Sprintf("Decisions: %d (window %d/%d, source %s)", len(artifact.Decisions), artifact.DecisionWindow.Size, artifact.DecisionWindow.Capacity, artifact.DecisionSource),
+		"",
+		"## Decisions",
+	}
+	for _, decision := range artifact.Decisions {
+		lines = append(lines, fmt.Sprintf("- t=%d ms %s %s %s: %s (%s)", decision.RuntimeTimestampMS, decision.TurnID, decision.Phase, decision.OutcomeKind, decision.Reason, decision.EmittedBy))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderDecisionReplaySummary(artifact decisionReplayArtifact) string {
+	candidate := artifact.CandidatePath
+	if candidate == "" {
+		candidate = "current control plane"
+	}
+	lines := []string{
+		"# Decision Replay",
+		"",
+		"Generated at (UTC): " + artifact.GeneratedAtUTC,
+		"Baseline artifact: " + artifact.BaselineArtifactPath,
+		"Candidate: " + candidate,
+		fmt.Sprintf("Recorded turn opens: %d", artifact.RecordedInputs),
+		fmt.Sprintf("Evaluated: %d", len(artifact.Result.EvaluatedIDs)),
+		fmt.Sprintf("Divergences: %d", len(artifact.Result.Divergences)),
+	}
+	if filter := artifact.Result.AppliedFilter; filter != nil {
+		classes := make([]string, 0, len(filter.DivergenceClasses))
+		for _, class := range filter.DivergenceClasses {
+			classes = append(classes, string(class))
+		}
+		lines = append(lines, fmt.Sprintf("Filter: session=%s turns=%s..%s lane=%s classes=%s", filter.SessionID, filter.TurnIDFrom, filter.TurnIDTo, filter.Lane, strings.Join(classes, ",")))
+	}
+	if cursor := artifact.Result.AppliedCursor; cursor != nil {
+		lines = append(lines, fmt.Sprintf("Cursor: after=%s limit=%d", cursor.AfterEventID, cursor.Limit))
+	}
+	if next := artifact.Result.NextCursor; next != nil {
+		lines = append(lines, fmt.Sprintf("Next page: --after-event=%s --limit=%d", next.AfterEventID, next.Limit))
+	}
+	if len(artifact.Result.Divergences) > 0 {
+		lines = append(lines, "", "| Class | Scope | Message |", "| --- | --- | --- |")
+		for _, divergence := range artifact.Result.Divergences {
+			lines = append(lines, fmt.Sprintf("| %s | %s | %s |", divergence.Class, divergence.Scope, divergence.Message))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderExplainDecisionSummary(artifact explainDecisionArtifact) string {
+	decision := artifact.Decision
+	scope := decision.SessionID
+	if decision.TurnID != "" {
+		scope += "/" + decision.TurnID
+	}
+	lines := []string{
+		"# Decision Explanation",
+		"",
+		"Generated at (UTC): " + artifact.GeneratedAtUTC,
+		"Baseline artifact: " + artifact.BaselineArtifactPath,
+		"Event: " + artifact.EventID,
+		"Scope: " + scope,
+		fmt.Sprintf("Outcome: %s at %s/%s by %s", decision.OutcomeKind, decision.Phase, decision.Scope, decision.EmittedBy),
+		"Reason: " + decision.Reason,
+	}
+	if decision.Explain == nil {
+		return strings.Join(lines, "\n") + "\n"
+	}
+	lines = append(lines, "Policy rule: "+decision.Explain.PolicyRule)
+	if len(decision.Explain.Inputs) > 0 {
+		keys := make([]string, 0, len(decision.Explain.Inputs))
+		for key := range decision.Explain.Inputs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		lines = append(lines, "", "## Inputs")
+		for _, key := range keys {
+			lines = append(lines, fmt.Sprintf("- %s: %s", key, decision.Explain.Inputs[key]))
+		}
+	}
+	if len(decision.Explain.QuotaCounters) > 0 {
+		lines = append(lines, "", "## Quota Counters")
+		for _, counter := range decision.Explain.QuotaCounters {
+			lines = append(lines, fmt.Sprintf("- %s: %d/%d", counter.Name, counter.Used, counter.Limit))
+		}
+	}
+	if len(decision.Explain.Thresholds) > 0 {
+		lines = append(lines, "", "## Thresholds")
+		for _, threshold := range decision.Explain.Thresholds {
+			status := "ok"
+			if threshold.Breached {
+				status = "BREACHED"
+			}
+			lines = append(lines, fmt.Sprintf("- %s: observed %.4g vs threshold %.4g (%s)", threshold.Name, threshold.Observed, threshold.Threshold, status))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderRunbookSummary(artifact runbookDecisionsArtifact) string {
+	lines := []string{
+		"# Runbook Decisions",
+		"",
+		"Generated at (UTC): " + artifact.GeneratedAtUTC,
+		"Runbook config: " + artifact.RunbookConfigPath,
+		"SLO gates report: " + artifact.SLOGatesReportPath,
+		"Mode: " + string(artifact.Mode),
+		fmt.Sprintf("Violations: %d", len(artifact.Violations)),
+		fmt.Sprintf("Decisions: %d", len(artifact.Decisions)),
+	}
+	if len(artifact.Decisions) > 0 {
+		lines = append(lines, "", "## Decisions")
+		for _, decision := range artifact.Decisions {
+			line := fmt.Sprintf("- %s (%s) %s: %s", decision.ActionID, decision.Kind, decision.Status, decision.Violation)
+			if decision.Error != "" {
+				line += " error=" + decision.Error
+			}
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderComplianceSummary(report compliance.Report) string {
+	lines := []string{
+		"# Compliance Report",
+		"",
+		"Generated at (UTC): " + report.GeneratedAtUTC,
+		"Window (UTC): " + report.WindowStartUTC + " to " + report.WindowEndUTC,
+		fmt.Sprintf("Tenants: %d", len(report.Tenants)),
+		fmt.Sprintf("Exceptions: %d", len(report.Exceptions)),
+	}
+	if report.Environment != "" {
+		lines = append(lines, "Environment: "+report.Environment)
+	}
+	if len(report.Tenants) > 0 {
+		lines = append(lines, "", "## Tenants", "", "| Tenant | Sweep runs | Expired | Deleted | Consent (granted/records) | Redacted turns | Erasures | Exceptions |", "| --- | --- | --- | --- | --- | --- | --- | --- |")
+		for _, tenant := range report.Tenants {
+			lines = append(lines, fmt.Sprintf("| %s | %d | %d | %d | %d/%d | %d | %d | %d |",
+				tenant.TenantID, tenant.RetentionSweepRuns, tenant.ArtifactsExpired, tenant.ArtifactsDeleted,
+				tenant.ConsentGranted, tenant.ConsentRecords, tenant.RedactedTurns, tenant.Erasures, tenant.Exceptions))
+		}
+	}
+	if len(report.Exceptions) > 0 {
+		lines = append(lines, "", "## Exceptions")
+		for _, exception := range report.Exceptions {
+			lines = append(lines, fmt.Sprintf("- %s %s: %s (%s)", exception.TenantID, exception.Kind, exception.Detail, exception.Source))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderAlertsSummary(report alerting.Report) string {
+	status := "PASS"
+	if !report.Passed {
+		status = "FAIL"
+	}
+	lines := []string{
+		"# Alerts",
+		"",
+		"Generated at (UTC): " + report.GeneratedAtUTC,
+		"Rules: " + report.RulesPath,
+		"Status: " + status,
+		fmt.Sprintf("Firing: %d critical, %d warning, %d info (of %d rules)", report.Firing[alerting.SeverityCritical], report.Firing[alerting.SeverityWarning], report.Firing[alerting.SeverityInfo], report.Evaluated),
+	}
+	if report.Environment != "" {
+		lines = append(lines, "Environment: "+report.Environment)
+	}
+	firing := make([]string, 0)
+	for _, result := range report.Results {
+		if !result.Firing {
+			continue
+		}
+		detail := fmt.Sprintf("observed %v %s %v", result.Observed, result.Comparator, result.Threshold)
+		if result.Error != "" {
+			detail = result.Error
+		}
+		firing = append(firing, fmt.Sprintf("- [%s] %s: %s (%s in %s)", result.Severity, result.RuleID, detail, result.Metric, result.Artifact))
+	}
+	if len(firing) > 0 {
+		lines = append(lines, "", "## Firing")
+		lines = append(lines, firing...)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderProviderBenchmarkSummary(report providerbench.Report) string {
+	lines := []string{
+		"# Provider Benchmark",
+		"",
+		"Generated at (UTC): " + report.GeneratedAtUTC,
+		"Window (UTC): " + report.WindowStartUTC + " to " + report.WindowEndUTC,
+		fmt.Sprintf("Turns in window: %d (%d outside)", report.TurnsInWindow, report.TurnsOutsideWindow),
+		"Ranked by: " + report.RankedBy,
+	}
+	if report.Environment != "" {
+		lines = append(lines, "Environment: "+report.Environment)
+	}
+	for _, modality := range report.Modalities {
+		lines = append(lines, "", "## "+strings.ToUpper(modality.Modality), "", "| Rank | Provider | Invocations | Error rate | p50/p95/p99 ms | First chunk p50/p95 ms | Cost/turn (est.) |", "| --- | --- | --- | --- | --- | --- | --- |")
+		for _, provider := range modality.Providers {
+			firstChunk := "n/a"
+			if provider.FirstChunkP50MS != nil {
+				firstChunk = fmt.Sprintf("%d/%d", *provider.FirstChunkP50MS, *provider.FirstChunkP95MS)
+			}
+			cost := "n/a"
+			if provider.EstimatedCostPerTurnUSD != nil {
+				cost = fmt.Sprintf("$%.4f", *provider.EstimatedCostPerTurnUSD)
+			}
+			lines = append(lines, fmt.Sprintf("| %d | %s | %d | %.1f%% | %d/%d/%d | %s | %s |",
+				provider.Rank, provider.ProviderID, provider.Invocations, provider.ErrorRate*100,
+				provider.LatencyP50MS, provider.LatencyP95MS, provider.LatencyP99MS, firstChunk, cost))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderLLMEvalSummary(artifact llmEvalReportArtifact) string {
+	lines := []string{
+		"# LLM Eval Report",
+		"",
+		"Generated at (UTC): " + artifact.GeneratedAtUTC,
+		"Suite: " + artifact.SuitePath,
+	}
+	if artifact.Responder != "" {
+		lines = append(lines, "Responder: "+artifact.Responder)
+	}
+	if artifact.JudgeProvider != "" {
+		lines = append(lines, "Judge: "+artifact.JudgeProvider)
+	}
+	lines = append(lines, fmt.Sprintf("Providers: %d", len(artifact.Report.Providers)))
+	for _, provider := range artifact.Report.Providers {
+		status := "PASS"
+		if !provider.Passed {
+			status = "FAIL"
+		}
+		lines = append(lines, fmt.Sprintf("- %s mean_score=%.3f pass_rate=%.3f %s", provider.ProviderID, provider.MeanScore, provider.PassRate, status))
+	}
+
+	if artifact.Passed {
+		lines = append(lines, "", "Status: PASS")
+	} else {
+		lines = append(lines, "", "Status: FAIL", "## Violations")
+		for _, violation := range artifact.Report.Violations {
+			lines = append(lines, "- "+violation)
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderRedactionEvalSummary(artifact redactionEvalReportArtifact) string {
+	lines := []string{
+		"# Redaction Eval Report",
+		"",
+		"Generated at (UTC): " + artifact.GeneratedAtUTC,
+		"Suite: " + artifact.SuitePath,
+		fmt.Sprintf("Fixtures: %d", artifact.Report.Fixtures),
+	}
+	for _, class := range artifact.Report.Classes {
+		status := "PASS"
+		if !class.Passed {
+			status = "FAIL"
+		}
+		lines = append(lines, fmt.Sprintf("- %s precision=%.3f recall=%.3f (tp=%d fp=%d fn=%d, min_precision=%.3f min_recall=%.3f) %s",
+			class.Class, class.Precision, class.Recall, class.TruePositives, class.FalsePositives, class.FalseNegatives,
+			class.Thresholds.MinPrecision, class.Thresholds.MinRecall, status))
+	}
+	if len(artifact.Report.Misses) > 0 {
+		lines = append(lines, "", "## Misses")
+		for _, miss := range artifact.Report.Misses {
+			lines = append(lines, fmt.Sprintf("- %s %s %s %q", miss.FixtureID, miss.Kind, miss.Class, miss.Text))
+		}
+	}
+
+	if artifact.Passed {
 		lines = append(lines, "", "Status: PASS")
 	} else {
 		lines = append(lines, "", "Status: FAIL", "## Violations")
-		for _, violation := range report.Violations {
+		for _, violation := range artifact.Report.Violations {
 			lines = append(lines, "- "+violation)
 		}
 	}
 	return strings.Join(lines, "\n") + "\n"
 }
 
+func renderContractCoverageSummary(artifact contractCoverageReportArtifact) string {
+	report := artifact.Report
+	lines := []string{
+		"# Contract Fixture Coverage Report",
+		"",
+		"Generated at (UTC): " + artifact.GeneratedAtUTC,
+		"Fixture root: " + artifact.FixtureRoot,
+		fmt.Sprintf("Covered schema elements: %d/%d (%.2f)", report.Covered, report.TotalElements, report.CoverageRatio),
+		fmt.Sprintf("Required coverage: %.2f", report.MinRatio),
+	}
+	for _, group := range []struct {
+		heading    string
+		dimensions []validation.DimensionCoverage
+	}{
+		{heading: "## Enums", dimensions: report.Dimensions},
+		{heading: "## Fields", dimensions: report.Fields},
+	} {
+		lines = append(lines, "", group.heading)
+		for _, coverage := range group.dimensions {
+			line := fmt.Sprintf("- %s: %d/%d", coverage.Name, coverage.Covered, coverage.Total)
+			if len(coverage.Uncovered) > 0 {
+				line += " (uncovered: " + strings.Join(coverage.Uncovered, ", ") + ")"
+			}
+			lines = append(lines, line)
+		}
+	}
+	if report.Passed {
+		lines = append(lines, "", "Status: PASS")
+	} else {
+		lines = append(lines, "", "Status: FAIL")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderSpecReportSummary(artifact specReportArtifact) string {
+	report := artifact.Report
+	lines := []string{
+		"# Pipeline Spec Validation Report",
+		"",
+		"Generated at (UTC): " + artifact.GeneratedAtUTC,
+		"Spec: " + artifact.SpecPath,
+		"Pipeline version: " + report.PipelineVersion,
+		fmt.Sprintf("Nodes: %d (budgeted: %d)", report.Nodes, report.BudgetedNodes),
+	}
+	if report.Passed {
+		lines = append(lines, "", "Status: PASS")
+	} else {
+		lines = append(lines, "", "Status: FAIL", "## Findings")
+		for _, finding := range report.Findings {
+			lines = append(lines, fmt.Sprintf("- %s (%s) %s: %s", finding.NodeID, finding.ProviderID, finding.Code, finding.Detail))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderBindingsReportSummary(artifact bindingsReportArtifact) string {
+	report := artifact.Report
+	lines := []string{
+		"# Pipeline Spec Binding Report",
+		"",
+		"Generated at (UTC): " + artifact.GeneratedAtUTC,
+		"Spec: " + artifact.SpecPath,
+		"Pipeline version: " + report.PipelineVersion,
+		fmt.Sprintf("Nodes: %d (requiring streaming: %d)", report.Nodes, report.StreamingNodes),
+	}
+	if len(report.Bindings) > 0 {
+		lines = append(lines, "", "## Bindings")
+		for _, binding := range report.Bindings {
+			lines = append(lines, fmt.Sprintf("- %s %s -> %s: %s (streaming: %t)", binding.NodeID, binding.Modality, binding.ProviderID, binding.Status, binding.Streaming))
+		}
+	}
+	if report.Passed {
+		lines = append(lines, "", "Status: PASS")
+	} else {
+		lines = append(lines, "", "Status: FAIL", "## Findings")
+		for _, finding := range report.Findings {
+			lines = append(lines, fmt.Sprintf("- %s (%s) %s: %s", finding.NodeID, finding.ProviderID, finding.Code, finding.Detail))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderSpecLintSummary(artifact specLintReportArtifact) string {
+	report := artifact.Report
+	lines := []string{
+		"# Pipeline Spec Lint Report",
+		"",
+		"Generated at (UTC): " + artifact.GeneratedAtUTC,
+		"Spec: " + artifact.SpecPath,
+		"Pipeline version: " + report.PipelineVersion,
+		fmt.Sprintf("Autofix requested: %t", report.FixRequested),
+		fmt.Sprintf("Suggestions: %d (applied: %d, skipped: %d)", len(report.Suggestions), report.Applied, report.Skipped),
+	}
+	if len(report.Suggestions) > 0 {
+		lines = append(lines, "", "## Suggestions")
+		for _, suggestion := range report.Suggestions {
+			status := "applied"
+			if !suggestion.Applied {
+				status = "skipped: " + suggestion.SkippedReason
+			}
+			lines = append(lines, fmt.Sprintf("- %s %s: %s; fix: %s (%s)", suggestion.Target, suggestion.Rule, suggestion.Detail, suggestion.Fix, status))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
 func renderContractsReportSummary(artifact contractsReportArtifact) string {
 	lines := []string{
 		"# Contract Validation Report",
@@ -1662,19 +4400,41 @@ func renderReleaseManifestSummary(manifest toolingrelease.ReleaseManifest) strin
 	lines := []string{
 		"# Release Manifest",
 		"",
-		"Generated at (UTC): " + manifest.GeneratedAtUTC,
-		"Release ID: " + manifest.ReleaseID,
-		"Spec ref: " + manifest.SpecRef,
-		"Pipeline version: " + manifest.RolloutConfig.PipelineVersion,
-		"Strategy: " + manifest.RolloutConfig.Strategy,
-		"Rollback mode: " + manifest.RolloutConfig.RollbackPosture.Mode,
-		"Rollback trigger: " + manifest.RolloutConfig.RollbackPosture.Trigger,
+	}
+	if bg := manifest.BreakGlass; bg != nil {
+		lines = append(lines,
+			"## BREAK-GLASS RELEASE",
+			"",
+			"Readiness gates were bypassed under emergency break-glass authorization.",
+			"",
+			"- Operator: "+bg.Operator,
+			"- Reason: "+bg.Reason,
+			"- Bypassed gates: "+strings.Join(bg.BypassedGates, ", "),
+			fmt.Sprintf("- Audit entry: %s #%d (sha256=%s)", bg.AuditLogPath, bg.AuditSequence, bg.AuditEntryHash),
+			"",
+		)
+	}
+	lines = append(lines,
+		"Generated at (UTC): "+manifest.GeneratedAtUTC,
+		"Release ID: "+manifest.ReleaseID,
+		"Spec ref: "+manifest.SpecRef,
+	)
+	if manifest.SpecHash != "" {
+		lines = append(lines, "Spec hash: "+manifest.SpecHash)
+	}
+	lines = append(lines,
+		"Pipeline version: "+manifest.RolloutConfig.PipelineVersion,
+		"Strategy: "+manifest.RolloutConfig.Strategy,
+		"Rollback mode: "+manifest.RolloutConfig.RollbackPosture.Mode,
+		"Rollback trigger: "+manifest.RolloutConfig.RollbackPosture.Trigger,
 		"",
 		"## Readiness Checks",
-	}
+	)
 	for _, check := range manifest.Readiness.Checks {
 		status := "PASS"
-		if !check.Passed {
+		if check.BypassedByBreakGlass {
+			status = "BYPASSED (break-glass)"
+		} else if !check.Passed {
 			status = "FAIL"
 		}
 		line := fmt.Sprintf("- %s: %s (%s)", check.Name, status, check.Path)
@@ -1,9 +1,17 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -11,10 +19,28 @@ import (
 	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
 	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/specstore"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/cost"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/decisionindex"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/recording"
+	replaycmp "github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/demo"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/diagnostics"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/localadmission"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/exitcode"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/alerting"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/artifactwriter"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/completion"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/compliance"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/llmeval"
 	"github.com/tiger/realtime-speech-pipeline/internal/tooling/ops"
 	"github.com/tiger/realtime-speech-pipeline/internal/tooling/regression"
 	toolingrelease "github.com/tiger/realtime-speech-pipeline/internal/tooling/release"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/replayshell"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/runbook"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/scrub"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/validation"
 )
 
 func TestLoadReplayFixturePolicy(t *testing.T) {
@@ -24,8 +50,9 @@ func TestLoadReplayFixturePolicy(t *testing.T) {
 	metadata := replayFixtureMetadata{
 		Fixtures: map[string]replayFixturePolicy{
 			"fixture-a": {
-				Gate:              "full",
-				TimingToleranceMS: int64Ptr(27),
+				Gate:                    "full",
+				TimingToleranceMS:       int64Ptr(27),
+				ScopeTimingTolerancesMS: replaycmp.ScopeTolerances{"turn:*": 10, "invocation_latency_final:*": 50},
 				ExpectedDivergences: []regression.ExpectedDivergence{{
 					Class:    obs.OrderingDivergence,
 					Scope:    "turn:turn-a",
@@ -52,6 +79,9 @@ func TestLoadReplayFixturePolicy(t *testing.T) {
 	if policy.TimingToleranceMS != 27 {
 		t.Fatalf("expected policy tolerance 27, got %d", policy.TimingToleranceMS)
 	}
+	if len(policy.ScopeTimingTolerancesMS) != 2 || policy.ScopeTimingTolerancesMS["turn:*"] != 10 {
+		t.Fatalf("expected scope tolerances carried into policy, got %+v", policy.ScopeTimingTolerancesMS)
+	}
 	if len(policy.Expected) != 1 {
 		t.Fatalf("expected one expected divergence, got %+v", policy.Expected)
 	}
@@ -75,6 +105,39 @@ func TestLoadReplayFixturePolicyMissingFixture(t *testing.T) {
 	}
 }
 
+func TestLoadReplayFixturePolicyRejectsInvalidScopeTolerances(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		metadata string
+	}{
+		{name: "inner wildcard", metadata: `{"fixtures":{"fixture-a":{"scope_timing_tolerances_ms":{"turn:*:open":10}}}}`},
+		{name: "negative tolerance", metadata: `{"fixtures":{"fixture-a":{"scope_timing_tolerances_ms":{"turn:*":-1}}}}`},
+	}
+	for _, tc := range tests {
+		metadataPath := filepath.Join(t.TempDir(), "metadata.json")
+		if err := osWriteFile(metadataPath, []byte(tc.metadata)); err != nil {
+			t.Fatalf("%s: unexpected write error: %v", tc.name, err)
+		}
+		if _, _, err := loadReplayFixturePolicy(metadataPath, "fixture-a", 15); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
+
+func TestRenderTimingToleranceSummaryListsScopedOverrides(t *testing.T) {
+	t.Parallel()
+
+	if got := renderTimingToleranceSummary(15, nil); got != "Timing tolerance (ms): 15" {
+		t.Fatalf("expected blanket tolerance line, got %q", got)
+	}
+	got := renderTimingToleranceSummary(15, map[string]int64{"turn_open:*": 10, "invocation_latency_final:*": 50})
+	if got != "Timing tolerance (ms): 15 (scoped: invocation_latency_final:*=50, turn_open:*=10)" {
+		t.Fatalf("unexpected scoped tolerance line %q", got)
+	}
+}
+
 func TestGenerateRuntimeBaselineArtifactFeedsSLOGates(t *testing.T) {
 	t.Parallel()
 
@@ -95,7 +158,7 @@ func TestGenerateRuntimeBaselineArtifactFeedsSLOGates(t *testing.T) {
 		t.Fatalf("artifact entries mismatch: got=%d want=%d", len(artifact.Entries), len(entries))
 	}
 
-	report := ops.EvaluateMVPSLOGates(toTurnMetrics(entries), ops.DefaultMVPSLOThresholds())
+	report := ops.EvaluateMVPSLOGates(ops.TurnMetricsFromBaseline(entries), ops.DefaultMVPSLOThresholds())
 	if !report.Passed {
 		t.Fatalf("expected generated runtime baseline metrics to pass SLO gates, got %+v", report.Violations)
 	}
@@ -275,6 +338,9 @@ func TestWriteReplayRegressionReportInvocationLatencyThresholdPass(t *testing.T)
 	if fixtureReport.InvocationLatencyBreaches != 0 || fixtureReport.FailingCount != 0 {
 		t.Fatalf("unexpected pass fixture report: %+v", fixtureReport)
 	}
+	if len(fixtureReport.PipelineVersions) != 1 || fixtureReport.PipelineVersions[0] != "pipeline-v1" {
+		t.Fatalf("expected latency evidence attributed to pipeline-v1, got %+v", fixtureReport.PipelineVersions)
+	}
 }
 
 func TestWriteReplayRegressionReportInvocationLatencyThresholdPassWithMetadataScope(t *testing.T) {
@@ -543,7 +609,7 @@ func TestToTurnMetricsComputesCompleteness(t *testing.T) {
 		},
 	}
 
-	samples := toTurnMetrics(entries)
+	samples := ops.TurnMetricsFromBaseline(entries)
 	if len(samples) != 1 {
 		t.Fatalf("expected one sample, got %d", len(samples))
 	}
@@ -553,6 +619,18 @@ func TestToTurnMetricsComputesCompleteness(t *testing.T) {
 	if !samples[0].HappyPath {
 		t.Fatalf("expected happy path sample to be true")
 	}
+
+	played := int64(640)
+	entries[0].FirstAudioPlayedAtMS = &played
+	samples = ops.TurnMetricsFromBaseline(entries)
+	if samples[0].FirstAudioPlayedAtMS == nil || *samples[0].FirstAudioPlayedAtMS != 640 {
+		t.Fatalf("expected playback ack to reach the slo sample, got %+v", samples[0])
+	}
+	report := ops.EvaluateMVPSLOGates(samples, ops.DefaultMVPSLOThresholds())
+	summary := renderSLOGatesSummary(sloGateArtifact{Report: report})
+	if !strings.Contains(summary, "Perceived first-audio p95: 540 ms (playback-acked turns: 1)") {
+		t.Fatalf("expected perceived first-audio line in slo summary, got %s", summary)
+	}
 }
 
 func TestWriteSLOGatesReportRequiresBaselineArtifact(t *testing.T) {
@@ -575,9 +653,195 @@ func TestWriteSLOGatesReportFromRuntimeArtifact(t *testing.T) {
 	if err := writeRuntimeBaselineArtifact(artifactPath); err != nil {
 		t.Fatalf("unexpected runtime baseline generation error: %v", err)
 	}
-	if err := writeSLOGatesReport(outputPath, artifactPath); err != nil {
+	// Unit tests score the recorded transcripts; the default providers mode calls live STT.
+	if err := writeSLOGatesReportWithQuality(outputPath, artifactPath, ops.DefaultSTTGoldenCorpusPath, ops.RecordedTranscriberMode); err != nil {
 		t.Fatalf("expected slo report generation from runtime artifact to pass, got %v", err)
 	}
+
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected slo report read error: %v", err)
+	}
+	var artifact sloGateArtifact
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		t.Fatalf("unexpected slo report decode error: %v", err)
+	}
+	slice, ok := artifact.Report.ByPipelineVersion["pipeline-v1"]
+	if !ok || slice.Samples != artifact.Report.Samples {
+		t.Fatalf("expected pipeline-v1 slice covering every sample, got %+v", artifact.Report.ByPipelineVersion)
+	}
+	summary, err := os.ReadFile(filepath.Join(tmp, "slo.md"))
+	if err != nil {
+		t.Fatalf("unexpected slo summary read error: %v", err)
+	}
+	if !strings.Contains(string(summary), "## By pipeline version") {
+		t.Fatalf("expected pipeline version section in slo summary, got %s", summary)
+	}
+}
+
+func TestSTTQualityTranscriberModes(t *testing.T) {
+	t.Setenv("RSPP_PROVIDER_PROFILE", "local")
+
+	corpus := ops.STTGoldenCorpus{Fixtures: []ops.STTGoldenFixture{{FixtureID: "fx-1", AudioRef: "audio/fx-1.wav", GoldenTranscript: "hello", RecordedTranscripts: map[string]string{"stt-a": "hello"}}}}
+	tests := []struct {
+		mode     string
+		wantMode string
+		wantIDs  []string
+	}{
+		{mode: "", wantMode: ops.ProviderTranscriberMode, wantIDs: []string{"stt-whisper-cpp"}},
+		{mode: "providers", wantMode: ops.ProviderTranscriberMode, wantIDs: []string{"stt-whisper-cpp"}},
+		{mode: " Recorded ", wantMode: ops.RecordedTranscriberMode, wantIDs: []string{"stt-a"}},
+	}
+	for _, tc := range tests {
+		mode, ids, transcriber, err := sttQualityTranscriber(tc.mode, corpus)
+		if err != nil || mode != tc.wantMode || !reflect.DeepEqual(ids, tc.wantIDs) || transcriber == nil {
+			t.Fatalf("%q: expected mode %s with providers %v, got %s %v err=%v", tc.mode, tc.wantMode, tc.wantIDs, mode, ids, err)
+		}
+	}
+	if _, _, _, err := sttQualityTranscriber("fixtures", corpus); exitcode.For(err) != exitcode.UsageError {
+		t.Fatalf("expected unknown transcriber mode to be a usage error, got %v", err)
+	}
+}
+
+func TestWriteSLOGatesReportIncludesQualitySection(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	artifactPath := filepath.Join(tmp, "runtime-baseline.json")
+	outputPath := filepath.Join(tmp, "slo.json")
+	corpusPath := filepath.Join(tmp, "corpus.json")
+
+	if err := writeRuntimeBaselineArtifact(artifactPath); err != nil {
+		t.Fatalf("unexpected runtime baseline generation error: %v", err)
+	}
+	if err := osWriteFile(corpusPath, []byte(`{
+  "default_wer_threshold": 0.1,
+  "fixtures": [
+    {
+      "fixture_id": "fx-1",
+      "audio_ref": "audio/fx-1.wav",
+      "golden_transcript": "turn the lights off",
+      "recorded_transcripts": {"stt-a": "turn lights on"}
+    }
+  ]
+}`)); err != nil {
+		t.Fatalf("unexpected corpus write error: %v", err)
+	}
+
+	if err := writeSLOGatesReportWithQuality(outputPath, artifactPath, corpusPath, ops.RecordedTranscriberMode); err == nil {
+		t.Fatalf("expected quality gate failure for high WER corpus")
+	}
+
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected slo report read error: %v", err)
+	}
+	var artifact sloGateArtifact
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		t.Fatalf("unexpected slo report decode error: %v", err)
+	}
+	if !artifact.Report.Passed || artifact.Passed || artifact.Quality == nil || artifact.Quality.Passed || artifact.QualityTranscriber != ops.RecordedTranscriberMode {
+		t.Fatalf("expected latency gates pass and recorded quality gate fail, got %+v", artifact)
+	}
+	if artifact.ErrorBudget == nil || artifact.ErrorBudget.TotalEvents == 0 || len(artifact.ErrorBudget.Alerts) != 0 {
+		t.Fatalf("expected error budget section without burn alerts, got %+v", artifact.ErrorBudget)
+	}
+
+	summary, err := os.ReadFile(strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".md")
+	if err != nil {
+		t.Fatalf("unexpected slo summary read error: %v", err)
+	}
+	if !strings.Contains(string(summary), "## Quality") || !strings.Contains(string(summary), "Status: FAIL") {
+		t.Fatalf("expected quality section and failing status in summary, got %s", summary)
+	}
+}
+
+func TestWriteLLMEvalReport(t *testing.T) {
+	t.Parallel()
+
+	outputPath := filepath.Join(t.TempDir(), "llm-eval.json")
+	if err := writeLLMEvalReport(outputPath, "", llmeval.RecordedResponderMode, ""); err != nil {
+		t.Fatalf("expected default llm eval suite to pass, got %v", err)
+	}
+
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected llm eval report read error: %v", err)
+	}
+	var artifact llmEvalReportArtifact
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		t.Fatalf("unexpected llm eval report decode error: %v", err)
+	}
+	if !artifact.Passed || len(artifact.Report.Providers) == 0 || artifact.Responder != llmeval.RecordedResponderMode {
+		t.Fatalf("unexpected llm eval report content: %+v", artifact)
+	}
+	summaryPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".md"
+	if _, err := os.Stat(summaryPath); err != nil {
+		t.Fatalf("expected llm eval summary markdown artifact, got %v", err)
+	}
+}
+
+func TestLLMEvalResponderModes(t *testing.T) {
+	t.Setenv("RSPP_PROVIDER_PROFILE", "local")
+
+	suite := llmeval.Suite{Fixtures: []llmeval.Fixture{{FixtureID: "fx-1", Prompt: "say hello", RecordedResponses: map[string]string{"llm-a": "hello"}}}}
+	tests := []struct {
+		mode      string
+		judge     string
+		wantMode  string
+		wantIDs   []string
+		wantJudge bool
+	}{
+		{mode: "", wantMode: llmeval.ProviderResponderMode, wantIDs: []string{"llm-llama-cpp"}},
+		{mode: "providers", judge: "llm-llama-cpp", wantMode: llmeval.ProviderResponderMode, wantIDs: []string{"llm-llama-cpp"}, wantJudge: true},
+		{mode: " Recorded ", wantMode: llmeval.RecordedResponderMode, wantIDs: []string{"llm-a"}},
+	}
+	for _, tc := range tests {
+		mode, ids, responder, scorer, err := llmEvalResponder(tc.mode, tc.judge, suite)
+		if err != nil || mode != tc.wantMode || !reflect.DeepEqual(ids, tc.wantIDs) || responder == nil {
+			t.Fatalf("%q: expected mode %s with providers %v, got %s %v err=%v", tc.mode, tc.wantMode, tc.wantIDs, mode, ids, err)
+		}
+		if _, judged := scorer.(llmeval.StrictestScorer); judged != tc.wantJudge {
+			t.Fatalf("%q: expected judged scorer=%v, got %T", tc.mode, tc.wantJudge, scorer)
+		}
+	}
+	for _, tc := range []struct{ mode, judge string }{
+		{mode: "fixtures"},
+		{mode: "recorded", judge: "llm-llama-cpp"},
+		{mode: "providers", judge: "llm-missing"},
+	} {
+		if _, _, _, _, err := llmEvalResponder(tc.mode, tc.judge, suite); exitcode.For(err) != exitcode.UsageError {
+			t.Fatalf("%q judge=%q: expected usage error, got %v", tc.mode, tc.judge, err)
+		}
+	}
+}
+
+func TestWriteRedactionEvalReport(t *testing.T) {
+	t.Parallel()
+
+	outputPath := filepath.Join(t.TempDir(), "redaction-eval.json")
+	if err := writeRedactionEvalReport(outputPath, ""); err != nil {
+		t.Fatalf("expected default redaction eval suite to pass, got %v", err)
+	}
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected redaction eval report read error: %v", err)
+	}
+	var artifact redactionEvalReportArtifact
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		t.Fatalf("unexpected redaction eval report decode error: %v", err)
+	}
+	if !artifact.Passed || len(artifact.Report.Classes) != 2 || len(artifact.Report.Misses) == 0 {
+		t.Fatalf("unexpected redaction eval report content: %+v", artifact)
+	}
+
+	strictSuite := filepath.Join(t.TempDir(), "strict.json")
+	if err := os.WriteFile(strictSuite, []byte(`{"default_thresholds":{"min_precision":1,"min_recall":1},"fixtures":[{"fixture_id":"f1","transcript":"call four one five five five five zero one three two","labels":[{"class":"PII","text":"four one five five five five zero one three two"}]}]}`), 0o600); err != nil {
+		t.Fatalf("unexpected suite write error: %v", err)
+	}
+	if err := writeRedactionEvalReport(outputPath, strictSuite); err == nil || !strings.Contains(err.Error(), "redaction eval gate failed") {
+		t.Fatalf("expected missed PII below threshold to fail the gate, got %v", err)
+	}
 }
 
 func TestWriteContractsReport(t *testing.T) {
@@ -609,6 +873,222 @@ func TestWriteContractsReport(t *testing.T) {
 	}
 }
 
+func TestWriteContractCoverageReport(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	outputPath := filepath.Join(tmp, "contract-coverage.json")
+	fixtureRoot := filepath.Join("test", "contract", "fixtures")
+
+	if err := writeContractCoverageReport(outputPath, fixtureRoot, 0); err != nil {
+		t.Fatalf("expected contract coverage report to pass default threshold, got %v", err)
+	}
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected contract coverage report read error: %v", err)
+	}
+	var artifact contractCoverageReportArtifact
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		t.Fatalf("unexpected contract coverage report decode error: %v", err)
+	}
+	if !artifact.Report.Passed || artifact.Report.TotalElements == 0 || len(artifact.Report.Dimensions) == 0 {
+		t.Fatalf("unexpected contract coverage report content: %+v", artifact.Report)
+	}
+	summary, err := os.ReadFile(filepath.Join(tmp, "contract-coverage.md"))
+	if err != nil {
+		t.Fatalf("unexpected contract coverage summary read error: %v", err)
+	}
+	if !strings.Contains(string(summary), "# Contract Fixture Coverage Report") {
+		t.Fatalf("expected coverage summary heading, got %s", summary)
+	}
+
+	if err := writeContractCoverageReport(filepath.Join(tmp, "strict.json"), fixtureRoot, 1.0); err == nil {
+		t.Fatalf("expected full-coverage threshold to fail")
+	}
+}
+
+func TestWriteSpecReport(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	outputPath := filepath.Join(tmp, "spec-report.json")
+	if err := writeSpecReport(outputPath, filepath.Join("test", "spec", "fixtures", "budgeted-pipeline.json")); err != nil {
+		t.Fatalf("expected repo spec fixture to pass, got %v", err)
+	}
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected spec report read error: %v", err)
+	}
+	var artifact specReportArtifact
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		t.Fatalf("unexpected spec report decode error: %v", err)
+	}
+	if !artifact.Report.Passed || artifact.Report.BudgetedNodes != 3 {
+		t.Fatalf("unexpected spec report content: %+v", artifact.Report)
+	}
+
+	tightSpec := filepath.Join(tmp, "tight-spec.json")
+	if err := os.WriteFile(tightSpec, []byte(`{"pipeline_version":"pipeline-v1","graph_definition_ref":"graph/default","execution_profile":"simple","nodes":[{"node_id":"llm","modality":"llm","provider_id":"llm-anthropic","budget":{"max_first_output_latency_ms":100}}]}`), 0o644); err != nil {
+		t.Fatalf("unexpected spec write error: %v", err)
+	}
+	failPath := filepath.Join(tmp, "tight-report.json")
+	if err := writeSpecReport(failPath, tightSpec); err == nil {
+		t.Fatalf("expected unmeetable latency budget to fail")
+	}
+	summary, err := os.ReadFile(filepath.Join(tmp, "tight-report.md"))
+	if err != nil {
+		t.Fatalf("unexpected spec summary read error: %v", err)
+	}
+	if !strings.Contains(string(summary), "latency_budget_unmet") {
+		t.Fatalf("expected latency finding in summary, got %s", summary)
+	}
+}
+
+func TestWriteBindingsReport(t *testing.T) {
+	t.Setenv("RSPP_STT_AZURE_ENABLE", "")
+	t.Setenv("RSPP_TTS_AZURE_ENABLE", "")
+
+	tmp := t.TempDir()
+	outputPath := filepath.Join(tmp, "bindings-report.json")
+	if err := writeBindingsReport(outputPath, filepath.Join("test", "spec", "fixtures", "budgeted-pipeline.json")); err != nil {
+		t.Fatalf("expected repo spec fixture bindings to resolve, got %v", err)
+	}
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected binding report read error: %v", err)
+	}
+	var artifact bindingsReportArtifact
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		t.Fatalf("unexpected binding report decode error: %v", err)
+	}
+	if !artifact.Report.Passed || len(artifact.Report.Bindings) != 3 || len(artifact.DisabledProviders) != 2 {
+		t.Fatalf("unexpected binding report content: %+v", artifact)
+	}
+
+	tests := []struct {
+		name        string
+		node        string
+		wantFinding string
+	}{
+		{name: "disabled provider", node: `{"node_id":"tts","modality":"tts","provider_id":"tts-azure-speech"}`, wantFinding: "provider_disabled"},
+		{name: "unregistered provider", node: `{"node_id":"llm","modality":"llm","provider_id":"llm-unknown"}`, wantFinding: "provider_unavailable"},
+		{name: "streaming unsupported", node: `{"node_id":"stt","modality":"stt","provider_id":"stt-deepgram","requires_streaming":true}`, wantFinding: "streaming_unsupported"},
+	}
+	for _, tc := range tests {
+		name := strings.ReplaceAll(tc.name, " ", "-")
+		specPath := filepath.Join(tmp, name+"-spec.json")
+		if err := os.WriteFile(specPath, []byte(`{"pipeline_version":"pipeline-v1","graph_definition_ref":"graph/default","execution_profile":"simple","nodes":[`+tc.node+`]}`), 0o644); err != nil {
+			t.Fatalf("%s: unexpected spec write error: %v", tc.name, err)
+		}
+		failPath := filepath.Join(tmp, name+"-report.json")
+		if err := writeBindingsReport(failPath, specPath); err == nil {
+			t.Fatalf("%s: expected binding validation to fail", tc.name)
+		}
+		summary, err := os.ReadFile(filepath.Join(tmp, name+"-report.md"))
+		if err != nil {
+			t.Fatalf("%s: unexpected binding summary read error: %v", tc.name, err)
+		}
+		if !strings.Contains(string(summary), tc.wantFinding) {
+			t.Fatalf("%s: expected %s in summary, got %s", tc.name, tc.wantFinding, summary)
+		}
+	}
+}
+
+func TestWriteScrubbedArtifactTokenizesAndKeepsInput(t *testing.T) {
+	t.Setenv(scrub.EnvSalt, "pinned-salt")
+
+	tmp := t.TempDir()
+	inputPath := filepath.Join(tmp, "debug-bundle.json")
+	input := []byte(`{"session_id":"sess-acme-1","turn_id":"turn-2","baseline":[{"SessionID":"sess-acme-1","EventID":"evt-sess-acme-1","TurnOpenAtMS":120}]}`)
+	if err := os.WriteFile(inputPath, input, 0o644); err != nil {
+		t.Fatalf("unexpected bundle write error: %v", err)
+	}
+	if _, err := writeScrubbedArtifact(inputPath, inputPath); err == nil {
+		t.Fatalf("expected scrub to refuse overwriting its input")
+	}
+
+	outputPath := shareablePathFor(inputPath)
+	if outputPath != filepath.Join(tmp, "debug-bundle.shareable.json") {
+		t.Fatalf("unexpected default scrub output path: %s", outputPath)
+	}
+	report, err := writeScrubbedArtifact(inputPath, outputPath)
+	if err != nil {
+		t.Fatalf("unexpected scrub error: %v", err)
+	}
+	if report.Identifiers != 2 || report.TokenizedFields != 3 || report.PreservedNumerics != 1 {
+		t.Fatalf("unexpected scrub report: %+v", report)
+	}
+	scrubbed, err := os.ReadFile(outputPath)
+	if err != nil || strings.Contains(string(scrubbed), "sess-acme-1") || !strings.Contains(string(scrubbed), `"TurnOpenAtMS": 120`) {
+		t.Fatalf("expected scrubbed identifiers with preserved timing, got %s (%v)", scrubbed, err)
+	}
+	again, err := os.ReadFile(inputPath)
+	if err != nil || string(again) != string(input) {
+		t.Fatalf("expected input artifact to be left untouched, got %s (%v)", again, err)
+	}
+
+	repeatPath := filepath.Join(tmp, "repeat.json")
+	if _, err := writeScrubbedArtifact(inputPath, repeatPath); err != nil {
+		t.Fatalf("unexpected scrub error: %v", err)
+	}
+	if repeated, err := os.ReadFile(repeatPath); err != nil || string(repeated) != string(scrubbed) {
+		t.Fatalf("expected a pinned salt to reproduce tokens, got %s (%v)", repeated, err)
+	}
+}
+
+func TestWriteSpecLintReportAppliesSafeFixes(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	specPath := filepath.Join(tmp, "spec.json")
+	if err := os.WriteFile(specPath, []byte(`{"pipeline_version":"pipeline-v1","graph_definition_ref":"graph/default","execution_profile":"simple","nodes":[{"node_id":"stt","modality":"stt","provider_id":"stt-deepgram"},{"node_id":"llm","modality":"llm","provider_id":"llm-gemini"},{"node_id":"tts","modality":"tts","provider_id":"tts-amazon-polly"}],"edges":[{"from":"stt","to":"llm"}]}`), 0o644); err != nil {
+		t.Fatalf("unexpected spec write error: %v", err)
+	}
+	if args, lint, fix := parseSpecLintFlags([]string{specPath, "--fix", "out.json"}); len(args) != 2 || !lint || !fix {
+		t.Fatalf("unexpected flag parse: %v lint=%v fix=%v", args, lint, fix)
+	}
+
+	suggestPath := filepath.Join(tmp, "suggest.json")
+	if err := writeSpecLintReport(suggestPath, specPath, false); err != nil {
+		t.Fatalf("unexpected lint error: %v", err)
+	}
+	var suggested specLintReportArtifact
+	raw, err := os.ReadFile(suggestPath)
+	if err != nil || json.Unmarshal(raw, &suggested) != nil {
+		t.Fatalf("unexpected lint report read error: %v", err)
+	}
+	if suggested.Report.Applied != 0 || suggested.Report.Skipped != 3 {
+		t.Fatalf("expected three skipped suggestions without --fix, got %+v", suggested.Report)
+	}
+
+	fixPath := filepath.Join(tmp, "fix.json")
+	if err := writeSpecLintReport(fixPath, specPath, true); err != nil {
+		t.Fatalf("unexpected lint error: %v", err)
+	}
+	var fixed specLintReportArtifact
+	raw, err = os.ReadFile(fixPath)
+	if err != nil || json.Unmarshal(raw, &fixed) != nil {
+		t.Fatalf("unexpected lint report read error: %v", err)
+	}
+	if fixed.Report.Applied != 2 || fixed.Report.Skipped != 1 {
+		t.Fatalf("expected two applied and one skipped suggestion, got %+v", fixed.Report)
+	}
+	spec, err := validation.LoadPipelineSpec(specPath)
+	if err != nil {
+		t.Fatalf("expected fixed spec to stay loadable: %v", err)
+	}
+	if spec.Edges[0].Lane != "DataLane" || spec.EdgeBufferPolicies["default"].DefaultingSource != "execution_profile_default" {
+		t.Fatalf("expected safe fixes written back to the spec, got %+v", spec)
+	}
+	summary, err := os.ReadFile(filepath.Join(tmp, "fix.md"))
+	if err != nil || !strings.Contains(string(summary), "unreferenced_node") {
+		t.Fatalf("expected skipped unreferenced node in summary, got %s (%v)", summary, err)
+	}
+	if err := writeSpecReport(filepath.Join(tmp, "spec-report.json"), specPath); err != nil {
+		t.Fatalf("expected fixed spec to pass validation, got %v", err)
+	}
+}
+
 func TestWriteReleaseManifest(t *testing.T) {
 	t.Parallel()
 
@@ -647,6 +1127,7 @@ func TestWriteReleaseManifest(t *testing.T) {
 		contractsPath,
 		replayPath,
 		sloPath,
+		"",
 		now,
 	)
 	if err != nil {
@@ -672,6 +1153,119 @@ func TestWriteReleaseManifest(t *testing.T) {
 	}
 }
 
+func TestWriteReleaseManifestBreakGlassBypassesStaleSLOReport(t *testing.T) {
+	tmp := t.TempDir()
+	now := time.Date(2026, 2, 11, 12, 0, 0, 0, time.UTC)
+	outputPath := filepath.Join(tmp, "release-manifest.json")
+	auditPath := filepath.Join(tmp, "audit", "break-glass.jsonl")
+	rolloutCfgPath := filepath.Join(tmp, "rollout.json")
+	contractsPath := filepath.Join(tmp, "contracts.json")
+	replayPath := filepath.Join(tmp, "replay.json")
+	sloPath := filepath.Join(tmp, "slo.json")
+	for path, content := range map[string][]byte{
+		rolloutCfgPath: []byte(`{"pipeline_version":"pipeline-v2","strategy":"canary","rollback_posture":{"mode":"automatic","trigger":"replay_or_slo_failure"}}`),
+		contractsPath:  []byte(`{"generated_at_utc":"2026-02-11T11:00:00Z","passed":true}`),
+		replayPath:     []byte(`{"generated_at_utc":"2026-02-11T11:10:00Z","failing_count":0}`),
+		sloPath:        []byte(`{"generated_at_utc":"2026-02-08T11:20:00Z","report":{"passed":true}}`),
+	} {
+		if err := osWriteFile(path, content); err != nil {
+			t.Fatalf("unexpected fixture write error: %v", err)
+		}
+	}
+	digest := sha256.Sum256([]byte("oncall-secret"))
+	t.Setenv(toolingrelease.EnvBreakGlassOperators, "alice="+hex.EncodeToString(digest[:]))
+	t.Setenv(toolingrelease.EnvBreakGlassOperator, "alice")
+	t.Setenv(toolingrelease.EnvBreakGlassReason, "SEV1 hotfix while SLO pipeline is down")
+	t.Setenv(toolingrelease.EnvBreakGlassGates, "slo_gates_report")
+	t.Setenv(toolingrelease.EnvBreakGlassAuditLog, auditPath)
+
+	t.Setenv(toolingrelease.EnvBreakGlassToken, "guessed-secret")
+	if _, err := writeReleaseManifest(outputPath, "specs/pipeline-v2.json", rolloutCfgPath, contractsPath, replayPath, sloPath, "", now); err == nil {
+		t.Fatalf("expected break-glass with the wrong token to fail")
+	}
+	if _, err := os.Stat(auditPath); !os.IsNotExist(err) {
+		t.Fatalf("expected no audit entry for an unauthorized attempt, stat err=%v", err)
+	}
+
+	t.Setenv(toolingrelease.EnvBreakGlassToken, "oncall-secret")
+	manifest, err := writeReleaseManifest(outputPath, "specs/pipeline-v2.json", rolloutCfgPath, contractsPath, replayPath, sloPath, "", now)
+	if err != nil {
+		t.Fatalf("expected break-glass publish to pass, got %v", err)
+	}
+	bg := manifest.BreakGlass
+	if bg == nil || bg.Operator != "alice" || strings.Join(bg.BypassedGates, ",") != "slo_gates_report" || bg.AuditSequence != 1 || bg.AuditLogPath != auditPath {
+		t.Fatalf("expected break-glass record in manifest, got %+v", bg)
+	}
+	entries, err := toolingrelease.VerifyBreakGlassAuditChain(auditPath)
+	if err != nil || len(entries) != 1 || entries[0].EntryHash != bg.AuditEntryHash || entries[0].ReleaseID != manifest.ReleaseID {
+		t.Fatalf("expected one audit chain entry matching the manifest, got %+v err=%v", entries, err)
+	}
+	summary, err := os.ReadFile(filepath.Join(tmp, "release-manifest.md"))
+	if err != nil {
+		t.Fatalf("unexpected summary read error: %v", err)
+	}
+	if !strings.Contains(string(summary), "## BREAK-GLASS RELEASE") || !strings.Contains(string(summary), "slo_gates_report: BYPASSED (break-glass)") {
+		t.Fatalf("expected break-glass banner in release summary, got:\n%s", summary)
+	}
+}
+
+func TestWriteReleaseManifestStoresSpecForGetSpec(t *testing.T) {
+	tmp := t.TempDir()
+	storeDir := filepath.Join(tmp, "specs")
+	t.Setenv("RSPP_CP_SPEC_STORE_DIR", storeDir)
+	now := time.Date(2026, 2, 11, 12, 0, 0, 0, time.UTC)
+	specPath := filepath.Join(tmp, "pipeline-v2.json")
+	rolloutCfgPath := filepath.Join(tmp, "rollout.json")
+	contractsPath := filepath.Join(tmp, "contracts.json")
+	replayPath := filepath.Join(tmp, "replay.json")
+	sloPath := filepath.Join(tmp, "slo.json")
+	spec := []byte(`{"pipeline_version":"pipeline-v2","graph_definition_ref":"graph/v2","execution_profile":"simple"}`)
+
+	for path, content := range map[string][]byte{
+		specPath:       spec,
+		rolloutCfgPath: []byte(`{"pipeline_version":"pipeline-v2","strategy":"canary","rollback_posture":{"mode":"automatic","trigger":"replay_or_slo_failure"}}`),
+		contractsPath:  []byte(`{"generated_at_utc":"2026-02-11T11:00:00Z","passed":true}`),
+		replayPath:     []byte(`{"generated_at_utc":"2026-02-11T11:10:00Z","failing_count":0}`),
+		sloPath:        []byte(`{"generated_at_utc":"2026-02-11T11:20:00Z","report":{"passed":true}}`),
+	} {
+		if err := osWriteFile(path, content); err != nil {
+			t.Fatalf("unexpected fixture write error: %v", err)
+		}
+	}
+
+	manifest, err := writeReleaseManifest(filepath.Join(tmp, "release-manifest.json"), specPath, rolloutCfgPath, contractsPath, replayPath, sloPath, "", now)
+	if err != nil {
+		t.Fatalf("expected release manifest generation to pass, got %v", err)
+	}
+	if manifest.SpecHash != specstore.Hash(spec) {
+		t.Fatalf("expected manifest spec_hash %s, got %+v", specstore.Hash(spec), manifest)
+	}
+
+	var stdout strings.Builder
+	if err := writePublishedSpec(manifest.SpecHash, "", "", &stdout); err != nil {
+		t.Fatalf("unexpected get-spec error: %v", err)
+	}
+	if stdout.String() != string(spec) {
+		t.Fatalf("expected published spec content, got %s", stdout.String())
+	}
+
+	outputPath := filepath.Join(tmp, "fetched", "spec.json")
+	if err := writePublishedSpec(manifest.SpecHash, outputPath, "", &stdout); err != nil {
+		t.Fatalf("unexpected get-spec file error: %v", err)
+	}
+	if fetched, err := os.ReadFile(outputPath); err != nil || string(fetched) != string(spec) {
+		t.Fatalf("expected fetched spec file, got %s (%v)", fetched, err)
+	}
+
+	blobPath := filepath.Join(storeDir, strings.TrimPrefix(manifest.SpecHash, "sha256:")+".json")
+	if err := osWriteFile(blobPath, []byte(`{"pipeline_version":"pipeline-tampered"}`)); err != nil {
+		t.Fatalf("unexpected tamper write error: %v", err)
+	}
+	if err := writePublishedSpec(manifest.SpecHash, "", "", &stdout); err == nil {
+		t.Fatalf("expected get-spec to reject tampered spec content")
+	}
+}
+
 func TestWriteReleaseManifestFailsWhenReadinessFails(t *testing.T) {
 	t.Parallel()
 
@@ -710,6 +1304,7 @@ func TestWriteReleaseManifestFailsWhenReadinessFails(t *testing.T) {
 		contractsPath,
 		replayPath,
 		sloPath,
+		"",
 		now,
 	); err == nil {
 		t.Fatalf("expected release manifest generation to fail when readiness fails")
@@ -723,3 +1318,953 @@ func int64Ptr(v int64) *int64 {
 func osWriteFile(path string, data []byte) error {
 	return os.WriteFile(path, data, 0o644)
 }
+
+func TestWriteFailureDomainReportFromRuntimeArtifact(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	artifactPath := filepath.Join(tmp, "runtime-baseline.json")
+	outputPath := filepath.Join(tmp, "failure-domains.json")
+
+	if err := writeRuntimeBaselineArtifact(artifactPath); err != nil {
+		t.Fatalf("unexpected runtime baseline generation error: %v", err)
+	}
+	if err := writeFailureDomainReport(outputPath, artifactPath, ops.DefaultFailureDomainBucketMS); err != nil {
+		t.Fatalf("unexpected failure domain report error: %v", err)
+	}
+
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected failure domain report read error: %v", err)
+	}
+	var artifact failureDomainReportArtifact
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		t.Fatalf("unexpected failure domain report decode error: %v", err)
+	}
+	if artifact.Report.TotalTurns == 0 || artifact.Report.FailedTurns != len(artifact.Report.Attributions) {
+		t.Fatalf("expected attributed failed turns over baseline entries, got %+v", artifact.Report)
+	}
+	if _, ok := artifact.Report.ByDomain["provider:llm"]; !ok {
+		t.Fatalf("expected every failure domain in by_domain, got %+v", artifact.Report.ByDomain)
+	}
+	summary, err := os.ReadFile(filepath.Join(tmp, "failure-domains.md"))
+	if err != nil {
+		t.Fatalf("unexpected failure domain summary read error: %v", err)
+	}
+	if !strings.Contains(string(summary), "# Failure Domain Report") {
+		t.Fatalf("expected failure domain summary heading, got %s", summary)
+	}
+}
+
+func TestWriteSLAReportFromTaggedBaselineArtifact(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	artifactPath := filepath.Join(tmp, "runtime-baseline.json")
+	outputPath := filepath.Join(tmp, "sla.json")
+	if err := writeRuntimeBaselineArtifact(artifactPath); err != nil {
+		t.Fatalf("unexpected runtime baseline generation error: %v", err)
+	}
+	baseline, err := timeline.ReadBaselineArtifact(artifactPath)
+	if err != nil {
+		t.Fatalf("unexpected runtime baseline read error: %v", err)
+	}
+	entries := baseline.Entries
+	strict := &controlplane.TurnSLA{TenantID: "tenant-strict", SLATier: controlplane.SLATier{Tier: "gold", MaxFirstOutputLatencyMS: 1, MinAvailability: 0.999}}
+	for i := range entries[:len(entries)-1] {
+		entries[i].SLA = strict
+	}
+	if err := timeline.WriteBaselineArtifact(artifactPath, entries); err != nil {
+		t.Fatalf("unexpected runtime baseline write error: %v", err)
+	}
+
+	if err := writeSLAReport(outputPath, artifactPath, ops.DefaultSLAReportPeriodMS); err != nil {
+		t.Fatalf("expected a missed tenant sla not to fail the report, got %v", err)
+	}
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected sla report read error: %v", err)
+	}
+	var artifact slaReportArtifact
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		t.Fatalf("unexpected sla report decode error: %v", err)
+	}
+	report := artifact.Report
+	if report.UntaggedTurns != 1 || len(report.Tenants) != 1 || report.Tenants[0].Overall.Turns != len(entries)-1 {
+		t.Fatalf("expected tagged turns under one tenant and one untagged turn, got %+v", report)
+	}
+	if report.Tenants[0].Overall.Met || report.Tenants[0].Overall.LatencyBreaches == 0 || len(report.Misses) != 1 {
+		t.Fatalf("expected the 1ms tier to be missed on latency, got %+v", report)
+	}
+	summary, err := os.ReadFile(filepath.Join(tmp, "sla.md"))
+	if err != nil {
+		t.Fatalf("unexpected sla summary read error: %v", err)
+	}
+	if !strings.Contains(string(summary), "# Tenant SLA Attainment Report") || !strings.Contains(string(summary), "tenant-strict (gold") {
+		t.Fatalf("expected tenant sla summary, got %s", summary)
+	}
+	if err := writeSLAReport(outputPath, artifactPath, 0); err == nil {
+		t.Fatalf("expected zero period to be rejected")
+	}
+}
+
+func TestWriteCostReportRollsUpTurnCosts(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	artifactPath := filepath.Join(tmp, "runtime-baseline.json")
+	outputPath := filepath.Join(tmp, "cost.json")
+	if err := writeRuntimeBaselineArtifact(artifactPath); err != nil {
+		t.Fatalf("unexpected runtime baseline generation error: %v", err)
+	}
+	baseline, err := timeline.ReadBaselineArtifact(artifactPath)
+	if err != nil {
+		t.Fatalf("unexpected runtime baseline read error: %v", err)
+	}
+	entries := baseline.Entries
+	table := cost.DefaultPriceTable()
+	for i := range entries[:len(entries)-1] {
+		entries[i].Cost = cost.TurnFromAttempts("tenant-cost", table, []timeline.ProviderAttemptEvidence{
+			{Modality: "stt", ProviderID: "stt-deepgram", AudioInputMS: 60_000},
+			{Modality: "llm", ProviderID: "llm-anthropic", InputTokens: 1000, OutputTokens: 100},
+		})
+	}
+	if err := timeline.WriteBaselineArtifact(artifactPath, entries); err != nil {
+		t.Fatalf("unexpected runtime baseline write error: %v", err)
+	}
+
+	if err := writeCostReport(outputPath, artifactPath); err != nil {
+		t.Fatalf("unexpected cost report error: %v", err)
+	}
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected cost report read error: %v", err)
+	}
+	var artifact costReportArtifact
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		t.Fatalf("unexpected cost report decode error: %v", err)
+	}
+	report := artifact.Report
+	accounted := len(entries) - 1
+	if report.UnaccountedTurns != 1 || len(report.Tenants) != 1 || report.Tenants[0].Turns != accounted || len(report.Providers) != 2 {
+		t.Fatalf("expected accounted turns under one tenant and one unaccounted turn, got %+v", report)
+	}
+	if want := 0.0105 * float64(accounted); math.Abs(report.Total.EstimatedCostUSD-want) > 1e-9 {
+		t.Fatalf("expected total cost %f, got %+v", want, report.Total)
+	}
+	summary, err := os.ReadFile(filepath.Join(tmp, "cost.md"))
+	if err != nil {
+		t.Fatalf("unexpected cost summary read error: %v", err)
+	}
+	if !strings.Contains(string(summary), "# Cost Report") || !strings.Contains(string(summary), "- tenant-cost sessions=") {
+		t.Fatalf("expected tenant cost summary, got %s", summary)
+	}
+}
+
+func TestWriteDebugBundleQueriesIndexedDecisions(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	artifactPath := filepath.Join(tmp, "runtime-baseline.json")
+	outputPath := filepath.Join(tmp, "debug-bundle.json")
+	if err := writeRuntimeBaselineArtifact(artifactPath); err != nil {
+		t.Fatalf("unexpected runtime baseline generation error: %v", err)
+	}
+
+	query := decisionindex.Query{SessionID: "sess-slo-runtime", TurnID: "turn-slo-1"}
+	if err := writeDebugBundle(outputPath, artifactPath, "", query); err != nil {
+		t.Fatalf("unexpected debug bundle error: %v", err)
+	}
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected debug bundle read error: %v", err)
+	}
+	var artifact debugBundleArtifact
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		t.Fatalf("unexpected debug bundle decode error: %v", err)
+	}
+	if len(artifact.Decisions) == 0 || len(artifact.Baseline) != 1 || artifact.DecisionWindow.Indexed == 0 || artifact.DecisionSource != debugBundleDecisionSourceBaseline {
+		t.Fatalf("expected decisions and baseline for one turn, got %+v", artifact)
+	}
+	for _, decision := range artifact.Decisions {
+		if decision.SessionID != query.SessionID || decision.TurnID != query.TurnID {
+			t.Fatalf("expected only decisions for the queried turn, got %+v", decision)
+		}
+	}
+	summary, err := os.ReadFile(filepath.Join(tmp, "debug-bundle.md"))
+	if err != nil {
+		t.Fatalf("unexpected debug bundle summary read error: %v", err)
+	}
+	if !strings.Contains(string(summary), "# Debug Bundle") {
+		t.Fatalf("expected debug bundle summary heading, got %s", summary)
+	}
+
+	if err := writeDebugBundle(filepath.Join(tmp, "missing.json"), artifactPath, "", decisionindex.Query{SessionID: "sess-unknown"}); err == nil {
+		t.Fatalf("expected unknown session error")
+	}
+}
+
+func TestWriteDebugBundleQueriesLiveRuntimeDecisions(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	artifactPath := filepath.Join(tmp, "runtime-baseline.json")
+	outputPath := filepath.Join(tmp, "debug-bundle.json")
+	if err := writeRuntimeBaselineArtifact(artifactPath); err != nil {
+		t.Fatalf("unexpected runtime baseline generation error: %v", err)
+	}
+	index := decisionindex.New(decisionindex.Config{Capacity: 8})
+	if err := index.Record(controlplane.DecisionOutcome{
+		OutcomeKind:        controlplane.OutcomeAdmit,
+		Phase:              controlplane.PhasePreTurn,
+		Scope:              controlplane.ScopeTurn,
+		SessionID:          "sess-slo-runtime",
+		TurnID:             "turn-slo-1",
+		EventID:            "evt-live-1",
+		RuntimeTimestampMS: 10,
+		WallClockMS:        10,
+		EmittedBy:          controlplane.EmitterRK25,
+		Reason:             "admission_capacity_allow",
+	}); err != nil {
+		t.Fatalf("unexpected decision record error: %v", err)
+	}
+	socketPath := filepath.Join(tmp, "admin.sock")
+	admin, err := diagnostics.ListenAdmin(socketPath, diagnostics.Sources{Decisions: index})
+	if err != nil {
+		t.Fatalf("unexpected admin socket error: %v", err)
+	}
+	defer admin.Close()
+
+	if err := writeDebugBundle(outputPath, artifactPath, socketPath, decisionindex.Query{SessionID: "sess-slo-runtime", TurnID: "turn-slo-1"}); err != nil {
+		t.Fatalf("unexpected debug bundle error: %v", err)
+	}
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected debug bundle read error: %v", err)
+	}
+	var artifact debugBundleArtifact
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		t.Fatalf("unexpected debug bundle decode error: %v", err)
+	}
+	if artifact.DecisionSource != debugBundleDecisionSourceAdminSocket || len(artifact.Decisions) != 1 || artifact.Decisions[0].EventID != "evt-live-1" || artifact.DecisionWindow.Indexed != 1 {
+		t.Fatalf("expected decisions from the live runtime index, got %+v", artifact)
+	}
+}
+
+func TestWriteExplainDecisionRendersExplainPayload(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	artifactPath := filepath.Join(tmp, "runtime-baseline.json")
+	outputPath := filepath.Join(tmp, "explain-decision.json")
+	if err := writeRuntimeBaselineArtifact(artifactPath); err != nil {
+		t.Fatalf("unexpected runtime baseline generation error: %v", err)
+	}
+	artifact, err := timeline.ReadBaselineArtifact(artifactPath)
+	if err != nil {
+		t.Fatalf("unexpected runtime baseline read error: %v", err)
+	}
+	var explainedEventID, plainEventID string
+	for i := range artifact.Entries {
+		for j := range artifact.Entries[i].DecisionOutcomes {
+			decision := &artifact.Entries[i].DecisionOutcomes[j]
+			if explainedEventID == "" {
+				explainedEventID = decision.EventID
+				decision.Explain = &controlplane.DecisionExplain{
+					PolicyRule:    localadmission.RuleCapacityDefer,
+					QuotaCounters: []controlplane.QuotaCounter{{Name: "tenant_concurrent_turns", Used: 8, Limit: 8}},
+				}
+			} else if plainEventID == "" {
+				plainEventID = decision.EventID
+			}
+		}
+	}
+	if explainedEventID == "" || plainEventID == "" {
+		t.Fatalf("expected at least two decisions in runtime baseline")
+	}
+	if err := timeline.WriteBaselineArtifact(artifactPath, artifact.Entries); err != nil {
+		t.Fatalf("unexpected runtime baseline write error: %v", err)
+	}
+
+	summary, err := writeExplainDecision(outputPath, artifactPath, explainedEventID)
+	if err != nil {
+		t.Fatalf("unexpected explain decision error: %v", err)
+	}
+	if !strings.Contains(summary, "Policy rule: "+localadmission.RuleCapacityDefer) || !strings.Contains(summary, "tenant_concurrent_turns: 8/8") {
+		t.Fatalf("expected explain payload in summary, got %s", summary)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "explain-decision.md")); err != nil {
+		t.Fatalf("expected explain summary written: %v", err)
+	}
+
+	if _, err := writeExplainDecision(outputPath, artifactPath, plainEventID); err == nil || !strings.Contains(err.Error(), localadmission.EnvDecisionExplain) {
+		t.Fatalf("expected missing explain payload error, got %v", err)
+	}
+	if _, err := writeExplainDecision(outputPath, artifactPath, "evt-unknown"); err == nil {
+		t.Fatalf("expected unknown event error")
+	}
+}
+
+func TestWriteDecisionReplayReportRecomputesRecordedSession(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	session, err := demo.NewSession(demo.SessionConfig{SessionID: "sess-decision-replay", PipelineVersion: "pipeline-v1", ArtifactsDir: tmp}, demo.SandboxProviders())
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	for _, text := range []string{"hello", "what time is it"} {
+		if _, err := session.SubmitText(text); err != nil {
+			t.Fatalf("unexpected turn error: %v", err)
+		}
+	}
+	artifacts, err := session.WriteArtifacts()
+	if err != nil {
+		t.Fatalf("unexpected session artifacts error: %v", err)
+	}
+	candidatePath := filepath.Join(tmp, "candidate-distribution.json")
+	if err := os.WriteFile(candidatePath, []byte(`{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "routing_view": {
+    "default": {
+      "routing_view_snapshot": "routing-view/candidate",
+      "admission_policy_snapshot": "admission-policy/candidate",
+      "abi_compatibility_snapshot": "abi-compat/candidate"
+    }
+  }
+}`), 0o644); err != nil {
+		t.Fatalf("unexpected candidate write error: %v", err)
+	}
+
+	cases := []struct {
+		name        string
+		candidate   string
+		divergences bool
+	}{
+		{name: "unchanged control plane", divergences: false},
+		{name: "candidate routing view", candidate: candidatePath, divergences: true},
+	}
+	for _, tc := range cases {
+		outputPath := filepath.Join(tmp, strings.ReplaceAll(tc.name, " ", "-")+".json")
+		summary, err := writeDecisionReplayReport(outputPath, artifacts.BaselinePath, tc.candidate, nil, nil)
+		if tc.divergences != (err != nil) {
+			t.Fatalf("%s: expected divergence failure=%v, got %v", tc.name, tc.divergences, err)
+		}
+		if err != nil && exitcode.For(err) != exitcode.GateFailure {
+			t.Fatalf("%s: expected gate failure exit code, got %v", tc.name, err)
+		}
+		raw, readErr := os.ReadFile(outputPath)
+		if readErr != nil {
+			t.Fatalf("%s: unexpected report read error: %v", tc.name, readErr)
+		}
+		var artifact decisionReplayArtifact
+		if err := json.Unmarshal(raw, &artifact); err != nil {
+			t.Fatalf("%s: unexpected report decode error: %v", tc.name, err)
+		}
+		if artifact.RecordedInputs != 2 || len(artifact.Result.EvaluatedIDs) != 2 {
+			t.Fatalf("%s: expected both recorded turn opens evaluated, got %+v", tc.name, artifact)
+		}
+		if tc.divergences != (len(artifact.Result.Divergences) > 0) {
+			t.Fatalf("%s: expected divergences=%v, got %+v", tc.name, tc.divergences, artifact.Result.Divergences)
+		}
+		for _, divergence := range artifact.Result.Divergences {
+			if divergence.Class != obs.PlanDivergence {
+				t.Fatalf("%s: expected plan divergences only, got %+v", tc.name, divergence)
+			}
+		}
+		if !strings.Contains(summary, "# Decision Replay") {
+			t.Fatalf("%s: expected decision replay summary, got %s", tc.name, summary)
+		}
+	}
+
+	args, filter, cursor, err := parseReplayDecisionFlags([]string{artifacts.BaselinePath, candidatePath, "--session=sess-decision-replay", "--class=PLAN_DIVERGENCE", "--limit=1"})
+	if err != nil || len(args) != 2 || filter == nil || filter.SessionID != "sess-decision-replay" || cursor == nil || cursor.Limit != 1 {
+		t.Fatalf("expected filter and cursor flags parsed, got args=%v filter=%+v cursor=%+v err=%v", args, filter, cursor, err)
+	}
+	pagedPath := filepath.Join(tmp, "paged.json")
+	summary, err := writeDecisionReplayReport(pagedPath, args[0], args[1], filter, cursor)
+	if exitcode.For(err) != exitcode.GateFailure || !strings.Contains(summary, "Next page: --after-event=") {
+		t.Fatalf("expected first page with divergences and a next-page cursor, got err=%v summary=%s", err, summary)
+	}
+	if _, err := writeDecisionReplayReport(pagedPath, args[0], args[1], nil, &obs.ReplayCursor{AfterEventID: "evt-unrecorded"}); !errors.Is(err, replaycmp.ErrReplayCursorEventNotFound) || exitcode.For(err) != exitcode.UsageError {
+		t.Fatalf("expected usage error for a cursor after an unrecorded event, got %v", err)
+	}
+	for _, flags := range [][]string{{"--limit=-1"}, {"--class=BOGUS"}, {"--lane=SideLane"}, {"--after-event"}, {"--bogus=1"}} {
+		if _, _, _, err := parseReplayDecisionFlags(flags); exitcode.For(err) != exitcode.UsageError {
+			t.Fatalf("expected usage error for %v, got %v", flags, err)
+		}
+	}
+}
+
+func TestRunReplayShellBreaksOnCandidateDivergence(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	baselinePath := filepath.Join(tmp, "runtime-baseline.json")
+	candidatePath := filepath.Join(tmp, "candidate-baseline.json")
+	if err := writeRuntimeBaselineArtifact(baselinePath); err != nil {
+		t.Fatalf("unexpected runtime baseline generation error: %v", err)
+	}
+	artifact, err := timeline.ReadBaselineArtifact(baselinePath)
+	if err != nil {
+		t.Fatalf("unexpected runtime baseline read error: %v", err)
+	}
+	last := len(artifact.Entries) - 1
+	artifact.Entries[last].AuthorityEpoch++
+	if err := timeline.WriteBaselineArtifact(candidatePath, artifact.Entries); err != nil {
+		t.Fatalf("unexpected candidate write error: %v", err)
+	}
+	commandsPath := filepath.Join(tmp, "commands.txt")
+	if err := os.WriteFile(commandsPath, []byte("break authority\ncontinue\nstate\nquit\n"), 0o644); err != nil {
+		t.Fatalf("write commands: %v", err)
+	}
+	commands, err := os.Open(commandsPath)
+	if err != nil {
+		t.Fatalf("open commands: %v", err)
+	}
+	defer commands.Close()
+
+	var out strings.Builder
+	if err := runReplayShell(baselinePath, candidatePath, commands, &out); err != nil {
+		t.Fatalf("unexpected replay shell error: %v", err)
+	}
+	if !strings.Contains(out.String(), "breakpoint AUTHORITY_DIVERGENCE") || !strings.Contains(out.String(), "turn="+artifact.Entries[last].TurnID) {
+		t.Fatalf("expected authority breakpoint in last turn, got %s", out.String())
+	}
+	if strings.Contains(out.String(), replayshell.Prompt) {
+		t.Fatalf("expected no prompt for non-interactive input, got %s", out.String())
+	}
+	if err := runReplayShell(filepath.Join(tmp, "missing.json"), "", commands, &out); err == nil {
+		t.Fatalf("expected missing baseline error")
+	}
+}
+
+func TestSynthesizeReplayFixtureFeedsRegressionGate(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	baselinePath := filepath.Join(tmp, "runtime-baseline.json")
+	candidatePath := filepath.Join(tmp, "candidate-baseline.json")
+	if err := writeRuntimeBaselineArtifact(baselinePath); err != nil {
+		t.Fatalf("unexpected runtime baseline generation error: %v", err)
+	}
+	artifact, err := timeline.ReadBaselineArtifact(baselinePath)
+	if err != nil {
+		t.Fatalf("unexpected runtime baseline read error: %v", err)
+	}
+	last := len(artifact.Entries) - 1
+	artifact.Entries[last].PlanHash = "plan-drifted"
+	if err := timeline.WriteBaselineArtifact(candidatePath, artifact.Entries); err != nil {
+		t.Fatalf("unexpected candidate write error: %v", err)
+	}
+	metadataPath := filepath.Join(tmp, "fixtures", "metadata.json")
+	if err := os.MkdirAll(filepath.Dir(metadataPath), 0o755); err != nil {
+		t.Fatalf("mkdir metadata: %v", err)
+	}
+	if err := os.WriteFile(metadataPath, []byte(`{"fixtures":{}}`), 0o644); err != nil {
+		t.Fatalf("write metadata: %v", err)
+	}
+
+	summary, err := synthesizeReplayFixture("inc-001-plan-drift", baselinePath, candidatePath, metadataPath, "shadow run")
+	if err != nil {
+		t.Fatalf("unexpected synthesize error: %v", err)
+	}
+	if !strings.Contains(summary, "turn:"+artifact.Entries[last].TurnID) || strings.Contains(summary, "needs triage") {
+		t.Fatalf("expected plan drift scope without triage notes, got %s", summary)
+	}
+
+	reportPath := filepath.Join(tmp, "regression-report.json")
+	if err := writeReplayRegressionReport(reportPath, metadataPath, "full"); err != nil {
+		t.Fatalf("expected synthesized fixture to pass the full gate, got %v", err)
+	}
+	raw, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("read regression report: %v", err)
+	}
+	var report replayRegressionReport
+	if err := json.Unmarshal(raw, &report); err != nil {
+		t.Fatalf("decode regression report: %v", err)
+	}
+	if report.FixtureCount != 1 || report.TotalDivergences != 1 || report.ByClass[string(obs.PlanDivergence)] != 1 {
+		t.Fatalf("expected synthesized fixture plan divergence in report, got %+v", report)
+	}
+}
+
+func TestWriteProviderBenchmark(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	baselinePath := filepath.Join(tmp, "runtime-baseline.json")
+	if err := writeRuntimeBaselineArtifact(baselinePath); err != nil {
+		t.Fatalf("unexpected runtime baseline generation error: %v", err)
+	}
+	outputPath := filepath.Join(tmp, "provider-benchmark.json")
+
+	report, err := writeProviderBenchmark(outputPath, []string{baselinePath}, "1970-01-01", "2100-01-01T00:00:00Z", "dev", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected provider benchmark error: %v", err)
+	}
+	if report.TurnsInWindow == 0 || len(report.Modalities) == 0 || report.Modalities[0].Providers[0].Rank != 1 {
+		t.Fatalf("expected ranked providers from baseline invocation evidence, got %+v", report)
+	}
+	summary, err := os.ReadFile(filepath.Join(tmp, "provider-benchmark.md"))
+	if err != nil || !strings.Contains(string(summary), "# Provider Benchmark") || !strings.Contains(string(summary), "| 1 | "+report.Modalities[0].Providers[0].ProviderID+" |") {
+		t.Fatalf("expected provider benchmark summary, got %s (%v)", summary, err)
+	}
+
+	if _, err := writeProviderBenchmark(outputPath, []string{baselinePath}, "last week", "2100-01-01", "dev", time.Now()); err == nil {
+		t.Fatalf("expected invalid window error")
+	}
+}
+
+func TestWritersClassifyExitCodes(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	fixtureRoot := filepath.Join("test", "contract", "fixtures")
+	missingBaseline := filepath.Join(tmp, "missing-baseline.json")
+	tests := []struct {
+		name string
+		run  func() error
+		want exitcode.Code
+	}{
+		{
+			name: "coverage below threshold",
+			run: func() error {
+				return writeContractCoverageReport(filepath.Join(tmp, "strict.json"), fixtureRoot, 1.0)
+			},
+			want: exitcode.GateFailure,
+		},
+		{
+			name: "invalid benchmark window",
+			run: func() error {
+				_, err := writeProviderBenchmark(filepath.Join(tmp, "bench.json"), nil, "last week", "2100-01-01", "dev", time.Now())
+				return err
+			},
+			want: exitcode.UsageError,
+		},
+		{
+			name: "missing baseline artifact",
+			run: func() error {
+				_, err := writeProviderBenchmark(filepath.Join(tmp, "bench.json"), []string{missingBaseline}, "1970-01-01", "2100-01-01", "dev", time.Now())
+				return err
+			},
+			want: exitcode.InfrastructureError,
+		},
+		{
+			name: "coverage at default threshold",
+			run: func() error {
+				return writeContractCoverageReport(filepath.Join(tmp, "coverage.json"), fixtureRoot, 0)
+			},
+			want: exitcode.Success,
+		},
+	}
+	for _, tc := range tests {
+		if got := exitcode.For(tc.run()); got != tc.want {
+			t.Fatalf("%s: expected exit code %s, got %s", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestWriteAlertsReport(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	baselinePath := filepath.Join(tmp, "runtime-baseline.json")
+	if err := writeRuntimeBaselineArtifact(baselinePath); err != nil {
+		t.Fatalf("unexpected runtime baseline generation error: %v", err)
+	}
+	benchmarkPath := filepath.Join(tmp, "provider-benchmark.json")
+	if _, err := writeProviderBenchmark(benchmarkPath, []string{baselinePath}, "1970-01-01", "2100-01-01", "", time.Now()); err != nil {
+		t.Fatalf("unexpected provider benchmark error: %v", err)
+	}
+	rulesPath := filepath.Join(tmp, "rules.yaml")
+	rules := "rules:\n" +
+		"  - id: benchmark-has-turns\n    artifact: " + benchmarkPath + "\n    metric: turns_in_window\n    comparator: lt\n    threshold: 1\n    severity: critical\n" +
+		"  - id: benchmark-modalities\n    artifact: " + benchmarkPath + "\n    metric: modalities\n    comparator: gte\n    threshold: 1\n    severity: warning\n"
+	if err := os.WriteFile(rulesPath, []byte(rules), 0o644); err != nil {
+		t.Fatalf("write rules: %v", err)
+	}
+	outputPath := filepath.Join(tmp, "alerts.json")
+
+	report, err := writeAlertsReport(outputPath, rulesPath, "", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected alerts error: %v", err)
+	}
+	if !report.Passed || report.Firing[alerting.SeverityWarning] != 1 || report.Evaluated != 2 {
+		t.Fatalf("expected only the warning rule to fire, got %+v", report)
+	}
+	summary, err := os.ReadFile(filepath.Join(tmp, "alerts.md"))
+	if err != nil || !strings.Contains(string(summary), "Status: PASS") || !strings.Contains(string(summary), "[warning] benchmark-modalities") {
+		t.Fatalf("expected alerts summary, got %s (%v)", summary, err)
+	}
+
+	critical := strings.Replace(rules, "comparator: lt", "comparator: gte", 1)
+	if err := os.WriteFile(rulesPath, []byte(critical), 0o644); err != nil {
+		t.Fatalf("write rules: %v", err)
+	}
+	if report, err := writeAlertsReport(outputPath, rulesPath, "", time.Now()); err != nil || report.Passed {
+		t.Fatalf("expected firing critical rule to fail the report, got %+v (%v)", report, err)
+	}
+}
+
+func TestReportWritersPairSummaryPaths(t *testing.T) {
+	tmp := t.TempDir()
+	baselinePath := filepath.Join(tmp, "runtime-baseline.json")
+	if err := writeRuntimeBaselineArtifact(baselinePath); err != nil {
+		t.Fatalf("unexpected runtime baseline generation error: %v", err)
+	}
+	unrelated := filepath.Join(tmp, "failure-domain.md")
+	if err := os.WriteFile(unrelated, []byte("notes"), 0o644); err != nil {
+		t.Fatalf("write unrelated: %v", err)
+	}
+
+	extensionless := filepath.Join(tmp, "failure-domain.v2")
+	if err := writeFailureDomainReport(extensionless, baselinePath, ops.DefaultFailureDomainBucketMS); err != nil {
+		t.Fatalf("unexpected failure domain error: %v", err)
+	}
+	if raw, _ := os.ReadFile(unrelated); string(raw) != "notes" {
+		t.Fatalf("expected unrelated summary untouched, got %q", raw)
+	}
+	if _, err := os.Stat(extensionless + ".md"); err != nil {
+		t.Fatalf("expected appended summary path: %v", err)
+	}
+
+	explicit := filepath.Join(tmp, "summaries", "failure-domain-summary.md")
+	t.Setenv(artifactwriter.EnvSummaryPath, explicit)
+	if err := writeFailureDomainReport(filepath.Join(tmp, "failure-domain.json"), baselinePath, ops.DefaultFailureDomainBucketMS); err != nil {
+		t.Fatalf("unexpected failure domain error: %v", err)
+	}
+	if _, err := os.Stat(explicit); err != nil || summaryPathFor(filepath.Join(tmp, "failure-domain.json")) != explicit {
+		t.Fatalf("expected summary at explicit path: %v", err)
+	}
+	if report := newGateReport("failure-domain-report", "dev", filepath.Join(tmp, "failure-domain.json"), nil, time.Now()); report.SummaryPath != explicit {
+		t.Fatalf("expected gate report to publish the explicit summary, got %+v", report)
+	}
+
+	t.Setenv(artifactwriter.EnvSummaryPath, filepath.Join(tmp, "collide.json"))
+	if err := writeFailureDomainReport(filepath.Join(tmp, "collide.json"), baselinePath, ops.DefaultFailureDomainBucketMS); err == nil {
+		t.Fatalf("expected summary path colliding with the json artifact to be rejected")
+	}
+}
+
+func TestWriteComplianceReport(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	baselinePath := filepath.Join(tmp, "runtime-baseline.json")
+	if err := writeRuntimeBaselineArtifact(baselinePath); err != nil {
+		t.Fatalf("unexpected runtime baseline generation error: %v", err)
+	}
+	inputsPath := filepath.Join(tmp, "compliance-inputs.json")
+	inputs, _ := json.Marshal(compliance.Inputs{BaselineArtifacts: []string{baselinePath}})
+	if err := os.WriteFile(inputsPath, inputs, 0o644); err != nil {
+		t.Fatalf("write inputs: %v", err)
+	}
+	outputPath := filepath.Join(tmp, "compliance-report.json")
+
+	report, err := writeComplianceReport(outputPath, inputsPath, "1970-01-01T00:00:00Z", "2100-01-01T00:00:00Z", "dev", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected compliance report error: %v", err)
+	}
+	if len(report.Tenants) != 1 || report.Tenants[0].TenantID != compliance.UnattributedTenant || report.Tenants[0].RedactedTurns == 0 {
+		t.Fatalf("expected unattributed redaction evidence from baseline, got %+v", report.Tenants)
+	}
+	summary, err := os.ReadFile(filepath.Join(tmp, "compliance-report.md"))
+	if err != nil || !strings.Contains(string(summary), "# Compliance Report") || !strings.Contains(string(summary), "| "+compliance.UnattributedTenant+" |") {
+		t.Fatalf("expected compliance summary, got %s (%v)", summary, err)
+	}
+
+	if _, err := writeComplianceReport(outputPath, inputsPath, "yesterday", "2100-01-01T00:00:00Z", "dev", time.Now()); err == nil {
+		t.Fatalf("expected invalid window error")
+	}
+}
+
+func TestWriteRunbookDecisionsSuggestsMatchedActions(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	sloPath := filepath.Join(tmp, "slo.json")
+	runbookPath := filepath.Join(tmp, "runbook.json")
+	outputPath := filepath.Join(tmp, "runbook-decisions.json")
+
+	slo := sloGateArtifact{
+		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
+		Report:         ops.MVPSLOGateReport{Violations: []string{"first-output p95=1800ms exceeds threshold=1500ms"}},
+	}
+	raw, err := json.Marshal(slo)
+	if err != nil {
+		t.Fatalf("unexpected slo encode error: %v", err)
+	}
+	if err := osWriteFile(sloPath, raw); err != nil {
+		t.Fatalf("unexpected slo write error: %v", err)
+	}
+	if err := osWriteFile(runbookPath, []byte(`{"mode":"suggest","actions":[
+  {"action_id":"open-llm-a","kind":"open_breaker","match":"first-output","provider_id":"llm-a"},
+  {"action_id":"scale","kind":"scale_hint_webhook","match":"turn-open","webhook_url":"https://ops.example/scale"}
+]}`)); err != nil {
+		t.Fatalf("unexpected runbook write error: %v", err)
+	}
+
+	if err := writeRunbookDecisions(outputPath, runbookPath, sloPath, filepath.Join(tmp, "absent.sock"), time.Now()); err != nil {
+		t.Fatalf("unexpected runbook evaluation error: %v", err)
+	}
+	out, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected runbook decisions read error: %v", err)
+	}
+	var artifact runbookDecisionsArtifact
+	if err := json.Unmarshal(out, &artifact); err != nil {
+		t.Fatalf("unexpected runbook decisions decode error: %v", err)
+	}
+	if len(artifact.Decisions) != 1 || artifact.Decisions[0].ActionID != "open-llm-a" || artifact.Decisions[0].Status != runbook.StatusSuggested {
+		t.Fatalf("expected one suggested breaker decision, got %+v", artifact.Decisions)
+	}
+
+	if err := osWriteFile(runbookPath, []byte(`{"mode":"auto","max_auto_actions":2,"actions":[
+  {"action_id":"open-llm-a","kind":"open_breaker","match":"first-output","provider_id":"llm-a","allow_auto":true},
+  {"action_id":"shift-llm","kind":"shift_provider_weights","match":"first-output","weights":{"llm-a":1,"llm-b":3},"allow_auto":true}
+]}`)); err != nil {
+		t.Fatalf("unexpected runbook write error: %v", err)
+	}
+	opened := ""
+	var shifted map[string]int
+	socketPath := filepath.Join(tmp, "admin.sock")
+	admin, err := diagnostics.ListenAdmin(socketPath, diagnostics.Sources{Remediation: diagnostics.Remediation{
+		OpenBreaker: func(providerID string) bool {
+			opened = providerID
+			return true
+		},
+		ShiftWeights: func(weights map[string]int) (bool, error) {
+			shifted = weights
+			return true, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected admin socket error: %v", err)
+	}
+	defer admin.Close()
+	if err := writeRunbookDecisions(outputPath, runbookPath, sloPath, socketPath, time.Now()); err != nil {
+		t.Fatalf("unexpected auto runbook evaluation error: %v", err)
+	}
+	out, err = os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected runbook decisions read error: %v", err)
+	}
+	artifact = runbookDecisionsArtifact{}
+	if err := json.Unmarshal(out, &artifact); err != nil {
+		t.Fatalf("unexpected runbook decisions decode error: %v", err)
+	}
+	if len(artifact.Decisions) != 2 || artifact.Decisions[0].Status != runbook.StatusExecuted || artifact.Decisions[1].Status != runbook.StatusExecuted {
+		t.Fatalf("expected both admin actions executed, got %+v", artifact.Decisions)
+	}
+	if opened != "llm-a" || shifted["llm-a"] != 25 || shifted["llm-b"] != 75 {
+		t.Fatalf("expected the runtime breaker opened and weights shifted, got opened=%q weights=%v", opened, shifted)
+	}
+
+	if err := writeRunbookDecisions(outputPath, runbookPath, sloPath, filepath.Join(tmp, "absent.sock"), time.Now()); err != nil {
+		t.Fatalf("unexpected runbook evaluation error: %v", err)
+	}
+	out, err = os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected runbook decisions read error: %v", err)
+	}
+	artifact = runbookDecisionsArtifact{}
+	if err := json.Unmarshal(out, &artifact); err != nil {
+		t.Fatalf("unexpected runbook decisions decode error: %v", err)
+	}
+	if len(artifact.Decisions) != 2 || artifact.Decisions[0].Status != runbook.StatusFailed || artifact.Decisions[0].Error == "" {
+		t.Fatalf("expected actions against an unreachable runtime recorded as failed, got %+v", artifact.Decisions)
+	}
+}
+
+func TestWriteRollbackReportFlipsActiveVersionAndRunsSmoke(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	manifestPath := filepath.Join(tmp, "release-manifest.json")
+	distributionPath := filepath.Join(tmp, "cp-distribution.json")
+	outputPath := filepath.Join(tmp, "rollback-report.json")
+
+	manifest := toolingrelease.ReleaseManifest{
+		ReleaseID:      "rel-20260211120000-abc",
+		GeneratedAtUTC: "2026-02-11T12:00:00Z",
+		SpecRef:        "specs/pipeline-v2.json",
+		RolloutConfig: toolingrelease.RolloutConfig{
+			PipelineVersion: "pipeline-v2",
+			Strategy:        "canary",
+			RollbackPosture: toolingrelease.RollbackPosture{Mode: "manual", Trigger: "slo_breach", TargetPipelineVersion: "pipeline-v1"},
+		},
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("unexpected manifest encode error: %v", err)
+	}
+	if err := osWriteFile(manifestPath, data); err != nil {
+		t.Fatalf("unexpected manifest write error: %v", err)
+	}
+	if err := osWriteFile(distributionPath, []byte(`{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "registry": {
+    "default_pipeline_version": "pipeline-v2",
+    "records": {
+      "pipeline-v1": {"pipeline_version": "pipeline-v1", "graph_definition_ref": "graph/v1", "execution_profile": "simple"},
+      "pipeline-v2": {"pipeline_version": "pipeline-v2", "graph_definition_ref": "graph/v2", "execution_profile": "simple"}
+    }
+  },
+  "rollout": {"default_pipeline_version": "pipeline-v2"},
+  "routing_view": {
+    "default": {"routing_view_snapshot": "routing-view/file", "admission_policy_snapshot": "admission-policy/file", "abi_compatibility_snapshot": "abi-compat/file"}
+  }
+}`)); err != nil {
+		t.Fatalf("unexpected distribution write error: %v", err)
+	}
+
+	report, err := writeRollbackReport(outputPath, manifestPath, distributionPath, filepath.Join("..", "..", defaultReplayMetadataPath), time.Date(2026, 2, 11, 13, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("expected rollback to pass, got %v (report=%+v)", err, report)
+	}
+	if report.PreviousActiveVersion != "pipeline-v2" || report.ResolvedPipelineVersion != "pipeline-v1" || report.SmokeReport == nil {
+		t.Fatalf("unexpected rollback report: %+v", report)
+	}
+
+	raw, err := os.ReadFile(report.SmokeReport.Path)
+	if err != nil {
+		t.Fatalf("unexpected smoke report read error: %v", err)
+	}
+	var smoke replaySmokeReport
+	if err := json.Unmarshal(raw, &smoke); err != nil {
+		t.Fatalf("unexpected smoke report decode error: %v", err)
+	}
+	if smoke.PipelineVersion != "pipeline-v1" {
+		t.Fatalf("expected smoke gate against rolled-back version, got %+v", smoke)
+	}
+	summary, err := os.ReadFile(filepath.Join(tmp, "rollback-report.md"))
+	if err != nil {
+		t.Fatalf("unexpected rollback summary read error: %v", err)
+	}
+	if !strings.Contains(string(summary), "Status: PASS") {
+		t.Fatalf("expected passing rollback summary, got %s", summary)
+	}
+
+	if _, err := writeRollbackReport(outputPath, manifestPath, distributionPath, filepath.Join("..", "..", defaultReplayMetadataPath), time.Now()); err != nil {
+		t.Fatalf("expected repeated rollback to be idempotent, got %v", err)
+	}
+}
+
+func TestWriteRecordingExportUsesReviewerIdentityFromEnv(t *testing.T) {
+	tmp := t.TempDir()
+	audioPath := filepath.Join(tmp, "session-audio.json")
+	if err := osWriteFile(audioPath, []byte(`{
+  "session_id": "sess-cli-export",
+  "tenant_id": "tenant-a",
+  "pipeline_version": "pipeline-v1",
+  "consent_granted": true,
+  "segments": [{"track": "user", "event_id": "evt-1", "pts_ms": 0, "sample_rate_hz": 8000, "channels": 1, "pcm": [1, 2, 3, 4]}],
+  "transcript": [{"track": "user", "start_ms": 0, "end_ms": 1, "text": "hi", "payload_class": "text_raw"}]
+}`)); err != nil {
+		t.Fatalf("unexpected session audio write error: %v", err)
+	}
+
+	t.Setenv(envRecordingExportPrincipal, "")
+	t.Setenv(envRecordingExportRole, "")
+	if _, err := writeRecordingExport(audioPath, filepath.Join(tmp, "denied"), recording.FormatWAV, nil); err == nil {
+		t.Fatalf("expected export without reviewer identity to be denied")
+	}
+
+	t.Setenv(envRecordingExportPrincipal, "qa-reviewer")
+	t.Setenv(envRecordingExportRole, "operator")
+	outputDir := filepath.Join(tmp, "export")
+	sidecar, err := writeRecordingExport(audioPath, outputDir, recording.FormatWAV, []string{recording.CaptionFormatWebVTT})
+	if err != nil {
+		t.Fatalf("unexpected recording export error: %v", err)
+	}
+	if len(sidecar.Tracks) != 1 || sidecar.Tracks[0].FileName != "sess-cli-export-user.wav" {
+		t.Fatalf("expected one user track, got %+v", sidecar.Tracks)
+	}
+	for _, name := range []string{"sess-cli-export-user.wav", recording.SidecarFileName("sess-cli-export"), recording.CaptionFileName("sess-cli-export", recording.CaptionFormatWebVTT)} {
+		if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+			t.Fatalf("expected export file %s: %v", name, err)
+		}
+	}
+
+	oggDir := filepath.Join(tmp, "export-ogg")
+	sidecar, err = writeRecordingExport(audioPath, oggDir, recording.FormatOGG, nil)
+	if err != nil || len(sidecar.Tracks) != 1 || sidecar.Tracks[0].FileName != "sess-cli-export-user.ogg" {
+		t.Fatalf("expected ogg user track, got %+v err=%v", sidecar.Tracks, err)
+	}
+	raw, err := os.ReadFile(filepath.Join(oggDir, "sess-cli-export-user.ogg"))
+	if err != nil || !strings.HasPrefix(string(raw), "OggS") {
+		t.Fatalf("expected ogg export file, got %d bytes err=%v", len(raw), err)
+	}
+}
+
+func TestNewGateReportCarriesSummaryAndGateError(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	outputPath := filepath.Join(tmp, "slo-gates-report.json")
+	if err := os.WriteFile(outputPath, []byte(`{}`), 0o644); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "slo-gates-report.md"), []byte("# MVP SLO Gates Report\n"), 0o644); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+
+	report := newGateReport("slo-gates-report", "staging", outputPath, errors.New("slo gates failed"), time.Unix(0, 0))
+	if report.Passed || report.Detail != "slo gates failed" || report.Environment != "staging" {
+		t.Fatalf("expected failing staging report, got %+v", report)
+	}
+	if report.SummaryPath != filepath.Join(tmp, "slo-gates-report.md") || report.Summary != "# MVP SLO Gates Report\n" {
+		t.Fatalf("expected markdown summary attached, got %+v", report)
+	}
+	if report.CompletedAtUTC != "1970-01-01T00:00:00Z" {
+		t.Fatalf("unexpected completion time: %s", report.CompletedAtUTC)
+	}
+}
+
+func TestCompletionSpecCoversDispatchedCommands(t *testing.T) {
+	t.Parallel()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "main.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parse main.go: %v", err)
+	}
+	dispatched := map[string]bool{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != "main" {
+			continue
+		}
+		ast.Inspect(fn.Body, func(node ast.Node) bool {
+			clause, ok := node.(*ast.CaseClause)
+			if !ok {
+				return true
+			}
+			for _, expr := range clause.List {
+				if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					dispatched[strings.Trim(lit.Value, `"`)] = true
+				}
+			}
+			return true
+		})
+	}
+
+	completed := map[string]bool{}
+	for _, command := range cliCompletionSpec().Commands {
+		completed[command.Name] = true
+		if !dispatched[command.Name] {
+			t.Fatalf("completion lists %s but main does not dispatch it", command.Name)
+		}
+	}
+	for name := range dispatched {
+		if !completed[name] {
+			t.Fatalf("main dispatches %s but completion does not list it", name)
+		}
+	}
+	for _, shell := range completion.Shells {
+		if _, err := completion.Script(shell, cliCompletionSpec()); err != nil {
+			t.Fatalf("%s: unexpected completion error: %v", shell, err)
+		}
+	}
+}
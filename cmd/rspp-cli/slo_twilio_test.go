@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/demo"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/transport"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/wscodec"
+	"github.com/tiger/realtime-speech-pipeline/transports/twilio"
+	"github.com/tiger/realtime-speech-pipeline/transports/websocket"
+)
+
+// twilioRunPipeline runs a sandbox demo session behind the Twilio transport and, on close,
+// appends its baseline to the merged runtime baseline, as rspp-runtime twilio does.
+type twilioRunPipeline struct {
+	session      *demo.Session
+	baselinePath string
+}
+
+func (p twilioRunPipeline) StartCapture() error { return p.session.StartCapture() }
+
+func (p twilioRunPipeline) AppendAudio(pcm []int16) (*websocket.Turn, error) {
+	return twilioRunTurn(p.session.AppendAudio(pcm))
+}
+
+func (p twilioRunPipeline) EndCapture() (*websocket.Turn, error) {
+	return twilioRunTurn(p.session.EndCapture())
+}
+
+func (p twilioRunPipeline) RecordReplyEgress(turnID string) error {
+	return p.session.RecordReplyEgress(turnID)
+}
+
+func (p twilioRunPipeline) AcknowledgePlayback(ack transport.PlaybackAck) error {
+	_, err := p.session.AcknowledgePlayback(ack)
+	return err
+}
+
+func (p twilioRunPipeline) CancelPlayback(turnID string, reason string) error {
+	return p.session.CancelPlayback(turnID, reason)
+}
+
+func (p twilioRunPipeline) Close() error {
+	artifacts, err := p.session.WriteArtifacts()
+	if err != nil {
+		return err
+	}
+	baseline, err := timeline.ReadBaselineArtifact(artifacts.BaselinePath)
+	if err != nil {
+		return err
+	}
+	return timeline.AppendBaselineArtifact(p.baselinePath, baseline.Entries)
+}
+
+func twilioRunTurn(result *demo.TurnResult, err error) (*websocket.Turn, error) {
+	if result == nil || err != nil {
+		return nil, err
+	}
+	return &websocket.Turn{TurnID: result.TurnID, Reply: result.Reply, ReplyPCM: result.ReplyPCM, Committed: result.Committed, FallbackReason: result.FallbackReason}, nil
+}
+
+// twilioCall is a Media Streams client placing one call against the transport.
+type twilioCall struct {
+	t        *testing.T
+	conn     *wscodec.Conn
+	sequence int
+	atMS     int64
+}
+
+func (c *twilioCall) send(msg map[string]any) {
+	c.t.Helper()
+	c.sequence++
+	msg["sequenceNumber"] = strconv.Itoa(c.sequence)
+	msg["streamSid"] = "MZ-slo"
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatalf("encode stream message: %v", err)
+	}
+	if err := c.conn.WriteMessage(wscodec.OpText, payload); err != nil {
+		c.t.Fatalf("write stream message: %v", err)
+	}
+}
+
+// audio streams durationMS of 20 ms mulaw chunks at the given amplitude.
+func (c *twilioCall) audio(amplitude int16, durationMS int64) {
+	c.t.Helper()
+	pcm := make([]int16, twilio.SampleRateHz/50)
+	for i := range pcm {
+		pcm[i] = amplitude
+		if i%2 == 1 {
+			pcm[i] = -amplitude
+		}
+	}
+	payload := base64.StdEncoding.EncodeToString(transport.EncodeMuLaw(pcm))
+	for end := c.atMS + durationMS; c.atMS < end; c.atMS += 20 {
+		c.send(map[string]any{"event": twilio.EventMedia, "media": map[string]any{"track": "inbound", "timestamp": strconv.FormatInt(c.atMS, 10), "payload": payload}})
+	}
+}
+
+// next reads stream messages until one of event arrives and returns its mark name, if any.
+func (c *twilioCall) next(event string) string {
+	c.t.Helper()
+	for {
+		_, payload, err := c.conn.ReadMessage()
+		if err != nil {
+			c.t.Fatalf("read stream message: %v", err)
+		}
+		var msg struct {
+			Event string `json:"event"`
+			Mark  struct {
+				Name string `json:"name"`
+			} `json:"mark"`
+		}
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			c.t.Fatalf("decode stream message: %v", err)
+		}
+		if msg.Event == event {
+			return msg.Mark.Name
+		}
+	}
+}
+
+func TestWriteSLOGatesReportFromTwilioRun(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	baselinePath := filepath.Join(tmp, "runtime-baseline.json")
+	reports := make(chan transport.Report, 1)
+	handler, err := twilio.NewHandler(twilio.Config{
+		PipelineVersion: "pipeline-twilio",
+		NewPipeline: func(sessionID, _ string) (websocket.Pipeline, error) {
+			session, err := demo.NewSession(demo.SessionConfig{SessionID: sessionID, PipelineVersion: "pipeline-twilio", SampleRateHz: twilio.SampleRateHz, ArtifactsDir: tmp}, demo.SandboxProviders())
+			if err != nil {
+				return nil, err
+			}
+			return twilioRunPipeline{session: session, baselinePath: baselinePath}, nil
+		},
+		OnReport: func(report transport.Report) { reports <- report },
+		Logger:   log.New(io.Discard, "", 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := wscodec.Dial(ctx, nil, "ws"+strings.TrimPrefix(server.URL, "http")+"/media", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	call := &twilioCall{t: t, conn: conn}
+
+	call.send(map[string]any{"event": twilio.EventConnected})
+	call.send(map[string]any{"event": twilio.EventStart, "start": map[string]any{"streamSid": "MZ-slo", "callSid": "CA-slo", "mediaFormat": map[string]any{"encoding": twilio.MediaEncoding, "sampleRate": twilio.SampleRateHz, "channels": 1}}})
+	call.audio(0, 200)
+	call.audio(4000, 400)
+	call.audio(0, 600)
+	heard := call.next(twilio.EventMark)
+	call.send(map[string]any{"event": twilio.EventMark, "mark": map[string]any{"name": heard}})
+	// The caller talks over the second reply.
+	call.audio(4000, 400)
+	call.audio(0, 600)
+	interrupted := call.next(twilio.EventMark)
+	call.audio(4000, 20)
+	call.next(twilio.EventClear)
+	call.send(map[string]any{"event": twilio.EventMark, "mark": map[string]any{"name": interrupted}})
+	call.audio(4000, 380)
+	call.audio(0, 600)
+	call.next(twilio.EventMark)
+	call.send(map[string]any{"event": twilio.EventStop})
+	select {
+	case <-reports:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out waiting for the call to end")
+	}
+
+	outputPath := filepath.Join(tmp, "slo.json")
+	if err := writeSLOGatesReportWithQuality(outputPath, baselinePath, "", ""); err != nil {
+		t.Fatalf("expected slo-gates-report on the twilio run to pass, got %v", err)
+	}
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected slo report read error: %v", err)
+	}
+	var artifact sloGateArtifact
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		t.Fatalf("unexpected slo report decode error: %v", err)
+	}
+	report := artifact.Report
+	if report.Samples != 3 || report.PlaybackAckedTurns != 1 || report.PerceivedFirstAudioP95MS == nil || report.BargeInTurns != 1 {
+		t.Fatalf("expected three twilio turns with one heard and one barged in, got %+v", report)
+	}
+	if _, ok := report.ByPipelineVersion["pipeline-twilio"]; !ok {
+		t.Fatalf("expected the twilio pipeline version sliced, got %+v", report.ByPipelineVersion)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/exitcode"
+)
+
+// runInteractive guides an operator through publishing or rolling back the active pipeline
+// version. Every change is previewed with its change hash and only applied after the operator
+// types that hash back; the loop ends on "quit" or end of input.
+func runInteractive(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("interactive", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	statePath := statePathFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitcode.Usage(err)
+	}
+	if err := requireStatePath("interactive", *statePath); err != nil {
+		return err
+	}
+
+	session := interactiveSession{in: bufio.NewScanner(stdin), out: stdout, statePath: *statePath}
+	for {
+		state, err := distribution.DescribeActiveVersion(session.statePath)
+		if err != nil {
+			return err
+		}
+		session.printState(state)
+		action, ok := session.prompt("action [publish|rollback|quit]: ")
+		if !ok {
+			return nil
+		}
+		switch strings.ToLower(action) {
+		case "publish":
+			session.change(state, "pipeline version to publish", "")
+		case "rollback":
+			session.change(state, "pipeline version to roll back to", state.PreviousPipelineVersion)
+		case "quit", "exit", "q":
+			return nil
+		default:
+			session.printf("unknown action %q\n", action)
+		}
+	}
+}
+
+type interactiveSession struct {
+	in        *bufio.Scanner
+	out       io.Writer
+	statePath string
+}
+
+func (s interactiveSession) printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(s.out, format, args...)
+}
+
+func (s interactiveSession) prompt(label string) (string, bool) {
+	s.printf("%s", label)
+	if !s.in.Scan() {
+		s.printf("\n")
+		return "", false
+	}
+	return strings.TrimSpace(s.in.Text()), true
+}
+
+func (s interactiveSession) printState(state distribution.ActiveVersionState) {
+	s.printf("\nstate: %s\n", state.Path)
+	s.printf("active pipeline version: %s\n", valueOrNone(state.ActivePipelineVersion))
+	s.printf("registered pipeline versions: %s\n", strings.Join(state.PipelineVersions, ", "))
+	s.printf("rollback target (newest backup): %s\n", valueOrNone(state.PreviousPipelineVersion))
+}
+
+// change previews one switch of the active version and applies it once the operator confirms
+// its change hash. Failures are reported and return the operator to the action prompt.
+func (s interactiveSession) change(state distribution.ActiveVersionState, label string, defaultVersion string) {
+	if defaultVersion != "" {
+		label += " [" + defaultVersion + "]"
+	}
+	version, ok := s.prompt(label + ": ")
+	if !ok {
+		return
+	}
+	if version == "" {
+		version = defaultVersion
+	}
+	if version == "" {
+		s.printf("no pipeline version given; nothing changed\n")
+		return
+	}
+
+	change, err := distribution.PlanActivePipelineVersion(s.statePath, version)
+	if err != nil {
+		s.printf("cannot plan change: %v\n", err)
+		return
+	}
+	s.printf("change: %s -> %s\n", valueOrNone(change.FromPipelineVersion), change.ToPipelineVersion)
+	s.printf("state sha256: %s\n", change.StateHash)
+	s.printf("change hash: %s\n", change.ChangeHash)
+	typed, ok := s.prompt(fmt.Sprintf("type the change hash (or its first %d characters) to apply: ", distribution.ShortChangeHashLength))
+	if !ok {
+		return
+	}
+	if !change.Confirms(typed) {
+		s.printf("change hash mismatch; nothing changed\n")
+		return
+	}
+	previous, err := distribution.ApplyActivePipelineVersionChange(change)
+	if err != nil {
+		s.printf("apply failed: %v\n", err)
+		return
+	}
+	s.printf("applied %s: active pipeline version %s (was %s)\n", change.ShortChangeHash(), change.ToPipelineVersion, valueOrNone(previous))
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
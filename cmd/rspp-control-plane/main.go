@@ -1,7 +1,119 @@
 package main
 
-import "fmt"
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sessionroute"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/exitcode"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/httpproblem"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/completion"
+)
 
 func main() {
-	fmt.Println("rspp-control-plane: scaffold initialized")
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "rspp-control-plane: %v\n", err)
+		os.Exit(int(exitcode.For(err)))
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		printUsage(stdout)
+		return nil
+	}
+
+	switch args[0] {
+	case "repair":
+		return runRepair(args[1:], stdout)
+	case "interactive":
+		return runInteractive(args[1:], stdin, stdout)
+	case "serve":
+		return runServe(args[1:], stdout)
+	case "completion":
+		return runCompletion(args[1:], stdout)
+	case "help", "-h", "--help":
+		printUsage(stdout)
+		return nil
+	default:
+		printUsage(stdout)
+		return exitcode.Usagef("unsupported command %q", args[0])
+	}
+}
+
+func statePathFlag(fs *flag.FlagSet) *string {
+	return fs.String("state", os.Getenv(distribution.EnvFileAdapterPath), "distribution state file")
+}
+
+func requireStatePath(command string, statePath string) error {
+	if statePath == "" {
+		return exitcode.Usagef("%s requires -state <path> or %s", command, distribution.EnvFileAdapterPath)
+	}
+	return nil
+}
+
+func runRepair(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("repair", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	statePath := statePathFlag(fs)
+	backups := fs.Int("backups", distribution.DefaultStateBackups, "backup generations to search, newest first")
+	if err := fs.Parse(args); err != nil {
+		return exitcode.Usage(err)
+	}
+	if err := requireStatePath("repair", *statePath); err != nil {
+		return err
+	}
+
+	result, err := distribution.RepairStateFile(*statePath, *backups)
+	if err != nil {
+		return err
+	}
+	if result.Healthy {
+		_, _ = fmt.Fprintf(stdout, "rspp-control-plane repair: state=%s healthy; nothing to restore\n", result.Path)
+		return nil
+	}
+	_, _ = fmt.Fprintf(stdout, "rspp-control-plane repair: state=%s restored_from=%s quarantined=%s reason=%s\n",
+		result.Path, result.RestoredFrom, result.QuarantinedPath, result.CurrentError)
+	return nil
+}
+
+func runCompletion(args []string, stdout io.Writer) error {
+	if len(args) != 1 {
+		return exitcode.Usagef("completion requires bash|zsh|fish")
+	}
+	script, err := completion.Script(args[0], completion.Spec{
+		Program: "rspp-control-plane",
+		Commands: []completion.Command{
+			{Name: "repair", Flags: []string{"state", "backups"}},
+			{Name: "interactive", Flags: []string{"state"}},
+			{Name: "serve", Flags: []string{"state", "addr", "backups"}},
+			completion.CompletionCommand(),
+			{Name: "help"},
+		},
+	})
+	if err != nil {
+		return exitcode.Usage(err)
+	}
+	_, err = io.WriteString(stdout, script)
+	return err
+}
+
+func printUsage(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "rspp-control-plane usage:")
+	_, _ = fmt.Fprintln(w, "  rspp-control-plane repair [-state <distribution.json>] [-backups <n>]")
+	_, _ = fmt.Fprintln(w, "  rspp-control-plane interactive [-state <distribution.json>]")
+	_, _ = fmt.Fprintln(w, "  rspp-control-plane serve [-state <distribution.json>] [-addr <host:port>] [-backups <n>]")
+	_, _ = fmt.Fprintln(w, "  rspp-control-plane completion <bash|zsh|fish>")
+	_, _ = fmt.Fprintf(w, "  %s=<path> sets the default -state\n", distribution.EnvFileAdapterPath)
+	_, _ = fmt.Fprintf(w, "  serve reads %s (session tokens), %s (route endpoint), and %s (API bearer auth)\n",
+		sessionroute.EnvTokenSecret, sessionroute.EnvRuntimeEndpoint, controlplaneclient.EnvAuthBearerToken)
+	_, _ = fmt.Fprintf(w, "  %s=file|sqlite|memory selects where serve keeps session statuses and the audit log (sqlite needs -tags sqlite; %s sets its path)\n",
+		envStateBackend, envStateSQLitePath)
+	_, _ = fmt.Fprintf(w, "  %s=<dir> enables serve's blue/green cutover gate over recent runtime baselines (%s, %s tune it)\n",
+		envRolloutBaselineDir, envRolloutGateWindow, envRolloutGateMinTurns)
+	_, _ = fmt.Fprintf(w, "  %s=<path> overrides serve's error-code and decision-outcome HTTP status mapping\n", httpproblem.EnvStatusMap)
 }
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/exitcode"
+)
+
+func TestRunRepairRestoresFromBackup(t *testing.T) {
+	t.Parallel()
+
+	path := writeState(t)
+	if _, err := distribution.SetActivePipelineVersion(path, "pipeline-v2"); err != nil {
+		t.Fatalf("unexpected set active version error: %v", err)
+	}
+
+	var healthy bytes.Buffer
+	if err := run([]string{"repair", "-state", path}, nil, &healthy); err != nil || !strings.Contains(healthy.String(), "healthy") {
+		t.Fatalf("expected healthy state report, got %q err=%v", healthy.String(), err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"registry":`), 0o644); err != nil {
+		t.Fatalf("corrupt state: %v", err)
+	}
+	var out bytes.Buffer
+	if err := run([]string{"repair", "-state", path}, nil, &out); err != nil {
+		t.Fatalf("unexpected repair error: %v", err)
+	}
+	if !strings.Contains(out.String(), "restored_from="+distribution.BackupPath(path, 1)) {
+		t.Fatalf("expected restore from newest backup, got %q", out.String())
+	}
+	if _, err := distribution.NewFileBackends(distribution.FileAdapterConfig{Path: path}); err != nil {
+		t.Fatalf("expected restored state to load, got %v", err)
+	}
+}
+
+func writeState(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "distribution.json")
+	valid := `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "registry": {"default_pipeline_version": "pipeline-v1", "records": {"pipeline-v1": {"pipeline_version": "pipeline-v1"}, "pipeline-v2": {"pipeline_version": "pipeline-v2"}}},
+  "rollout": {"default_pipeline_version": "pipeline-v1"}
+}`
+	if err := os.WriteFile(path, []byte(valid), 0o644); err != nil {
+		t.Fatalf("write state: %v", err)
+	}
+	return path
+}
+
+func TestRunInteractiveConfirmsChangeHashBeforeApplying(t *testing.T) {
+	t.Parallel()
+
+	path := writeState(t)
+	publish, err := distribution.PlanActivePipelineVersion(path, "pipeline-v2")
+	if err != nil {
+		t.Fatalf("unexpected plan error: %v", err)
+	}
+	input := strings.Join([]string{"publish", "pipeline-v2", "0000deadbeef", "publish", "pipeline-v2", publish.ShortChangeHash(), "quit"}, "\n")
+	var out bytes.Buffer
+	if err := run([]string{"interactive", "-state", path}, strings.NewReader(input), &out); err != nil {
+		t.Fatalf("unexpected interactive error: %v", err)
+	}
+	transcript := out.String()
+	if !strings.Contains(transcript, "change hash mismatch; nothing changed") || !strings.Contains(transcript, "change hash: "+publish.ChangeHash) {
+		t.Fatalf("expected a refused then previewed change, got:\n%s", transcript)
+	}
+	if !strings.Contains(transcript, "active pipeline version pipeline-v2 (was pipeline-v1)") {
+		t.Fatalf("expected confirmed publish to apply, got:\n%s", transcript)
+	}
+
+	rollback, err := distribution.PlanActivePipelineVersion(path, "pipeline-v1")
+	if err != nil {
+		t.Fatalf("unexpected plan error: %v", err)
+	}
+	out.Reset()
+	input = strings.Join([]string{"rollback", "", rollback.ChangeHash}, "\n")
+	if err := run([]string{"interactive", "-state", path}, strings.NewReader(input), &out); err != nil {
+		t.Fatalf("unexpected interactive error: %v", err)
+	}
+	if !strings.Contains(out.String(), "pipeline version to roll back to [pipeline-v1]") || !strings.Contains(out.String(), "active pipeline version pipeline-v1 (was pipeline-v2)") {
+		t.Fatalf("expected rollback to default to the newest backup version, got:\n%s", out.String())
+	}
+}
+
+func TestRunCompletionPrintsShellScripts(t *testing.T) {
+	t.Parallel()
+
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		var out bytes.Buffer
+		if err := run([]string{"completion", shell}, nil, &out); err != nil {
+			t.Fatalf("%s: unexpected completion error: %v", shell, err)
+		}
+		if !strings.Contains(out.String(), "interactive") || !strings.Contains(out.String(), "backups") {
+			t.Fatalf("%s: expected control-plane commands and flags, got:\n%s", shell, out.String())
+		}
+	}
+}
+
+func TestRunRejectsInvalidInvocations(t *testing.T) {
+	t.Parallel()
+
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	tests := []struct {
+		name string
+		args []string
+		want exitcode.Code
+	}{
+		{name: "unknown command", args: []string{"serve-everything"}, want: exitcode.UsageError},
+		{name: "unknown flag", args: []string{"repair", "-bogus"}, want: exitcode.UsageError},
+		{name: "no valid backup", args: []string{"repair", "-state", missing}, want: exitcode.InfrastructureError},
+		{name: "interactive without state", args: []string{"interactive", "-state", ""}, want: exitcode.UsageError},
+		{name: "interactive missing state", args: []string{"interactive", "-state", missing}, want: exitcode.InfrastructureError},
+		{name: "serve without state", args: []string{"serve", "-state", ""}, want: exitcode.UsageError},
+		{name: "serve missing state", args: []string{"serve", "-state", missing}, want: exitcode.InfrastructureError},
+		{name: "completion without shell", args: []string{"completion"}, want: exitcode.UsageError},
+		{name: "completion unsupported shell", args: []string{"completion", "csh"}, want: exitcode.UsageError},
+	}
+	for _, tc := range tests {
+		err := run(tc.args, nil, &bytes.Buffer{})
+		if err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+		if got := exitcode.For(err); got != tc.want {
+			t.Fatalf("%s: expected exit code %s, got %s (%v)", tc.name, tc.want, got, err)
+		}
+	}
+
+	var usage bytes.Buffer
+	if err := run(nil, nil, &usage); err != nil || !strings.Contains(usage.String(), "rspp-control-plane repair") {
+		t.Fatalf("expected usage without arguments, got %q err=%v", usage.String(), err)
+	}
+}
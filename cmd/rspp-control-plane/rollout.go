@@ -0,0 +1,298 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/rollout"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sessionroute"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/ops"
+)
+
+const (
+	// envRolloutBaselineDir is the directory runtimes write session baseline artifacts to; serve
+	// gates blue/green promotion on the recent ones. Evaluation is unavailable when it is unset.
+	envRolloutBaselineDir = "RSPP_CP_ROLLOUT_BASELINE_DIR"
+	// envRolloutGateWindow bounds how old a baseline artifact may be to count toward the gate.
+	envRolloutGateWindow = "RSPP_CP_ROLLOUT_GATE_WINDOW"
+	// envRolloutGateMinTurns is the fewest target-version turns the gate passes on.
+	envRolloutGateMinTurns = "RSPP_CP_ROLLOUT_GATE_MIN_TURNS"
+
+	defaultRolloutGateWindow   = 15 * time.Minute
+	defaultRolloutGateMinTurns = 20
+
+	baselineArtifactSuffix = "-baseline.json"
+)
+
+// registerRolloutPools registers an environment's blue and green pools. Both pipeline versions
+// must be published, and an environment mid-cutover cannot be re-registered.
+func (s *processState) registerRolloutPools(environment string, blue rollout.RuntimePool, green rollout.RuntimePool) (rollout.BlueGreenStatus, error) {
+	backends, err := distribution.NewFileBackends(distribution.FileAdapterConfig{Path: s.distributionPath})
+	if err != nil {
+		return rollout.BlueGreenStatus{}, err
+	}
+	for _, pool := range []rollout.RuntimePool{blue, green} {
+		if _, err := backends.Registry.ResolvePipelineRecord(pool.PipelineVersion); err != nil {
+			return rollout.BlueGreenStatus{}, fmt.Errorf("%s pool: %w", pool.Color, err)
+		}
+	}
+	// Sessions routed to a pool request its version; pin both so they resolve it while the
+	// environment's active version is still the other pool's.
+	s.mu.Lock()
+	err = distribution.PinPipelineVersions(s.distributionPath, blue.PipelineVersion, green.PipelineVersion)
+	s.mu.Unlock()
+	if err != nil {
+		return rollout.BlueGreenStatus{}, err
+	}
+	return s.changeRollout(environment, true, "", func(c *rollout.BlueGreenCoordinator) error {
+		if current, err := c.Status(environment); err == nil && current.Phase == rollout.CutoverPhaseShifting {
+			return fmt.Errorf("%w: cutover to %s in progress for environment %s", sessionroute.ErrConflict, current.Target, environment)
+		}
+		if err := c.RegisterPools(environment, blue, green); err != nil {
+			return fmt.Errorf("%w: %v", sessionroute.ErrInvalidRequest, err)
+		}
+		return nil
+	})
+}
+
+func (s *processState) shiftRollout(environment string, target rollout.PoolColor, weight int) (rollout.BlueGreenStatus, error) {
+	return s.changeRollout(environment, false, "", func(c *rollout.BlueGreenCoordinator) error {
+		return rolloutConflict(c.ShiftWeight(environment, target, weight))
+	})
+}
+
+func (s *processState) abortRollout(environment string, reason string) (rollout.BlueGreenStatus, error) {
+	return s.changeRollout(environment, false, "", func(c *rollout.BlueGreenCoordinator) error {
+		return rolloutConflict(c.Abort(environment, reason))
+	})
+}
+
+func (s *processState) reportPoolSessions(environment string, color rollout.PoolColor, activeSessions int) (rollout.BlueGreenStatus, error) {
+	return s.changeRollout(environment, false, "", func(c *rollout.BlueGreenCoordinator) error {
+		_, err := c.ReportActiveSessions(environment, color, activeSessions)
+		return rolloutConflict(err)
+	})
+}
+
+// evaluateRollout runs gate against the environment's shifting pool. A passing gate promotes
+// the pool and activates its pipeline version for the environment in the distribution state,
+// so the environment's runtimes resolve new sessions to it; a pending gate leaves the cutover
+// shifting; a failing or erroring gate aborts it. The gate result is returned with the
+// resulting status.
+func (s *processState) evaluateRollout(environment string, gate rollout.SLOGate) (rollout.BlueGreenStatus, rollout.GateResult, error) {
+	coordinator, err := s.restoreRollout(environment)
+	if err != nil {
+		return rollout.BlueGreenStatus{}, rollout.GateResult{}, err
+	}
+	target, err := coordinator.CutoverTarget(environment)
+	if err != nil {
+		return rollout.BlueGreenStatus{}, rollout.GateResult{}, rolloutConflict(err)
+	}
+	result, err := gate.Evaluate(environment, target)
+	if err != nil {
+		result = rollout.GateResult{Passed: false, Reason: fmt.Sprintf("slo gate error: %v", err)}
+	}
+	if result.Pending {
+		status, err := coordinator.Status(environment)
+		return status, result, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous := ""
+	if result.Passed {
+		active, err := distribution.EnvironmentPipelineVersion(s.distributionPath, environment)
+		if err != nil {
+			return rollout.BlueGreenStatus{}, result, err
+		}
+		if active != target.PipelineVersion {
+			if previous, err = distribution.SetEnvironmentPipelineVersion(s.distributionPath, environment, target.PipelineVersion); err != nil {
+				return rollout.BlueGreenStatus{}, result, err
+			}
+		}
+	}
+	status, err := s.changeRollout(environment, false, previous, func(c *rollout.BlueGreenCoordinator) error {
+		_, err := c.CompleteCutover(environment, target.PoolID, result)
+		return rolloutConflict(err)
+	})
+	if err != nil && previous != "" {
+		return rollout.BlueGreenStatus{}, result, fmt.Errorf("distribution state changed but the rollout promotion was not persisted: %w", err)
+	}
+	return status, result, err
+}
+
+func (s *processState) rolloutStatus(environment string) (rollout.BlueGreenStatus, error) {
+	coordinator, err := s.restoreRollout(environment)
+	if err != nil {
+		return rollout.BlueGreenStatus{}, err
+	}
+	return coordinator.Status(environment)
+}
+
+func (s *processState) routeRolloutSession(environment string, sessionID string) (rollout.RuntimePool, error) {
+	coordinator, err := s.restoreRollout(environment)
+	if err != nil {
+		return rollout.RuntimePool{}, err
+	}
+	pool, err := coordinator.RouteSession(environment, sessionID)
+	if err != nil {
+		return rollout.RuntimePool{}, fmt.Errorf("%w: %v", sessionroute.ErrInvalidRequest, err)
+	}
+	return pool, nil
+}
+
+func (s *processState) restoreRollout(environment string) (*rollout.BlueGreenCoordinator, error) {
+	doc, err := s.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	coordinator := rollout.NewBlueGreenCoordinator(s.now)
+	return coordinator, restoreRolloutEnvironment(coordinator, doc, strings.TrimSpace(environment))
+}
+
+// changeRollout applies change to the environment's stored blue/green state and appends every
+// transition it audits to the process audit log in the same save. previousActive, when set, is
+// the pipeline version a promotion replaced as the rollout default.
+func (s *processState) changeRollout(environment string, register bool, previousActive string, change func(*rollout.BlueGreenCoordinator) error) (rollout.BlueGreenStatus, error) {
+	environment = strings.TrimSpace(environment)
+	var status rollout.BlueGreenStatus
+	err := s.update(func(doc *processDocument) error {
+		coordinator := rollout.NewBlueGreenCoordinator(s.now)
+		if err := restoreRolloutEnvironment(coordinator, *doc, environment); err != nil && (!register || !errors.Is(err, sessionroute.ErrNotFound)) {
+			return err
+		}
+		if err := change(coordinator); err != nil {
+			return err
+		}
+		next, err := coordinator.Status(environment)
+		if err != nil {
+			return err
+		}
+		if doc.Rollouts == nil {
+			doc.Rollouts = map[string]rollout.BlueGreenStatus{}
+		}
+		doc.Rollouts[environment] = next
+		for _, record := range coordinator.AuditLog() {
+			doc.Audit = append(doc.Audit, rolloutAuditEntry(record, next, previousActive))
+		}
+		status = next
+		return nil
+	})
+	return status, err
+}
+
+func restoreRolloutEnvironment(coordinator *rollout.BlueGreenCoordinator, doc processDocument, environment string) error {
+	current, ok := doc.Rollouts[environment]
+	if !ok {
+		return fmt.Errorf("%w: no runtime pools registered for environment %q", sessionroute.ErrNotFound, environment)
+	}
+	if err := coordinator.Restore(current); err != nil {
+		return fmt.Errorf("restore rollout state for environment %s: %w", environment, err)
+	}
+	return nil
+}
+
+func rolloutAuditEntry(record rollout.AuditRecord, status rollout.BlueGreenStatus, previousActive string) auditEntry {
+	entry := auditEntry{
+		AtMS:        record.AtMS,
+		Action:      record.Action,
+		Environment: record.Environment,
+		PoolID:      record.PoolID,
+		Color:       string(record.Color),
+		Detail:      record.Detail,
+	}
+	for _, pool := range status.Pools {
+		if pool.PoolID == record.PoolID {
+			entry.PipelineVersion = pool.PipelineVersion
+		}
+	}
+	if record.Action == rollout.AuditActionPromote && previousActive != "" {
+		entry.PreviousPipelineVersion, entry.Activated = previousActive, true
+	}
+	return entry
+}
+
+func rolloutConflict(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %v", sessionroute.ErrConflict, err)
+}
+
+// baselineSLOGate gates a pool on the MVP SLOs of the turns its pipeline version served, read
+// from the session baseline artifacts runtimes wrote within window. Fewer than minTurns turns
+// is a pending result: too little traffic is neither a pass nor a regression.
+type baselineSLOGate struct {
+	dir        string
+	window     time.Duration
+	minTurns   int
+	thresholds ops.MVPSLOThresholds
+	now        func() time.Time
+}
+
+// rolloutGateFromEnv returns the live SLO gate serve evaluates cutovers with, or nil when
+// envRolloutBaselineDir is unset.
+func rolloutGateFromEnv() (rollout.SLOGate, error) {
+	dir := strings.TrimSpace(os.Getenv(envRolloutBaselineDir))
+	if dir == "" {
+		return nil, nil
+	}
+	gate := &baselineSLOGate{dir: dir, window: defaultRolloutGateWindow, minTurns: defaultRolloutGateMinTurns, thresholds: ops.DefaultMVPSLOThresholds(), now: time.Now}
+	if raw := strings.TrimSpace(os.Getenv(envRolloutGateWindow)); raw != "" {
+		window, err := time.ParseDuration(raw)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("%s must be a positive duration, got %q", envRolloutGateWindow, raw)
+		}
+		gate.window = window
+	}
+	if raw := strings.TrimSpace(os.Getenv(envRolloutGateMinTurns)); raw != "" {
+		minTurns, err := strconv.Atoi(raw)
+		if err != nil || minTurns < 1 {
+			return nil, fmt.Errorf("%s must be a positive integer, got %q", envRolloutGateMinTurns, raw)
+		}
+		gate.minTurns = minTurns
+	}
+	return gate, nil
+}
+
+func (g *baselineSLOGate) Evaluate(_ string, pool rollout.RuntimePool) (rollout.GateResult, error) {
+	dirEntries, err := os.ReadDir(g.dir)
+	if err != nil {
+		return rollout.GateResult{}, fmt.Errorf("read baseline dir: %w", err)
+	}
+	cutoff := g.now().Add(-g.window)
+	var entries []timeline.BaselineEvidence
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !strings.HasSuffix(dirEntry.Name(), baselineArtifactSuffix) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil || info.ModTime().Before(cutoff) {
+			continue
+		}
+		artifact, err := timeline.ReadBaselineArtifact(filepath.Join(g.dir, dirEntry.Name()))
+		if err != nil {
+			return rollout.GateResult{}, fmt.Errorf("read baseline %s: %w", dirEntry.Name(), err)
+		}
+		for _, entry := range artifact.Entries {
+			if entry.PipelineVersion == pool.PipelineVersion {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	if len(entries) < g.minTurns {
+		return rollout.GateResult{Pending: true, Reason: fmt.Sprintf("%d turns on %s within %s, need %d", len(entries), pool.PipelineVersion, g.window, g.minTurns)}, nil
+	}
+	report := ops.EvaluateMVPSLOGates(ops.TurnMetricsFromBaseline(entries), g.thresholds)
+	if !report.Passed {
+		return rollout.GateResult{Reason: strings.Join(report.Violations, "; ")}, nil
+	}
+	return rollout.GateResult{Passed: true, Reason: fmt.Sprintf("%d turns on %s passed mvp slo gates", report.Samples, pool.PipelineVersion)}, nil
+}
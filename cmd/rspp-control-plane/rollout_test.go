@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/rollout"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/ops"
+)
+
+func writeGateBaseline(t *testing.T, path string, versions ...string) {
+	t.Helper()
+	entries := make([]timeline.BaselineEvidence, 0, len(versions))
+	for i, version := range versions {
+		entries = append(entries, timeline.BaselineEvidence{SessionID: "sess-1", TurnID: "turn-" + string(rune('a'+i)), PipelineVersion: version, TerminalOutcome: "commit"})
+	}
+	if err := timeline.WriteBaselineArtifact(path, entries); err != nil {
+		t.Fatalf("write baseline: %v", err)
+	}
+}
+
+func TestBaselineSLOGateReadsRecentTargetVersionTurns(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	now := time.Now()
+	writeGateBaseline(t, filepath.Join(dir, "sess-1-baseline.json"), "pipeline-v2", "pipeline-v1")
+	writeGateBaseline(t, filepath.Join(dir, "sess-2-baseline.json"), "pipeline-v2")
+	stale := filepath.Join(dir, "sess-0-baseline.json")
+	writeGateBaseline(t, stale, "pipeline-v2", "pipeline-v2")
+	if err := os.Chtimes(stale, now.Add(-time.Hour), now.Add(-time.Hour)); err != nil {
+		t.Fatalf("age baseline: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sess-1-report.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatalf("write report: %v", err)
+	}
+	pool := rollout.RuntimePool{PoolID: "rt-green", Color: rollout.PoolGreen, PipelineVersion: "pipeline-v2", Weight: 10}
+
+	tests := []struct {
+		name        string
+		minTurns    int
+		wantPending bool
+		wantReason  string
+	}{
+		{name: "too few recent turns", minTurns: 3, wantPending: true, wantReason: "2 turns on pipeline-v2 within 15m0s, need 3"},
+		{name: "slo violations", minTurns: 2, wantReason: "baseline completeness"},
+	}
+	for _, tc := range tests {
+		gate := &baselineSLOGate{dir: dir, window: defaultRolloutGateWindow, minTurns: tc.minTurns, thresholds: ops.DefaultMVPSLOThresholds(), now: func() time.Time { return now }}
+		result, err := gate.Evaluate("prod", pool)
+		if err != nil || result.Passed || result.Pending != tc.wantPending || !strings.Contains(result.Reason, tc.wantReason) {
+			t.Fatalf("%s: expected unpassed gate (pending=%t) with %q, got %+v err=%v", tc.name, tc.wantPending, tc.wantReason, result, err)
+		}
+	}
+}
+
+func TestRolloutGateFromEnv(t *testing.T) {
+	t.Setenv(envRolloutBaselineDir, "")
+	if gate, err := rolloutGateFromEnv(); gate != nil || err != nil {
+		t.Fatalf("expected no gate without a baseline dir, got %v err=%v", gate, err)
+	}
+	t.Setenv(envRolloutBaselineDir, t.TempDir())
+	t.Setenv(envRolloutGateWindow, "5m")
+	t.Setenv(envRolloutGateMinTurns, "4")
+	gate, err := rolloutGateFromEnv()
+	baseline, ok := gate.(*baselineSLOGate)
+	if err != nil || !ok || baseline.window != 5*time.Minute || baseline.minTurns != 4 {
+		t.Fatalf("expected configured baseline gate, got %+v err=%v", gate, err)
+	}
+	t.Setenv(envRolloutGateMinTurns, "0")
+	if _, err := rolloutGateFromEnv(); err == nil {
+		t.Fatalf("expected invalid min turns to fail")
+	}
+}
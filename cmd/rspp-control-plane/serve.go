@@ -0,0 +1,433 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/rollout"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sessionroute"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/specstore"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/exitcode"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/httpproblem"
+)
+
+const maxRequestBytes = 1 << 20
+
+// runServe serves the control-plane REST and session-route gRPC APIs over the distribution state
+// until interrupted.
+func runServe(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	statePath := statePathFlag(fs)
+	addr := fs.String("addr", "127.0.0.1:8790", "listen address for the control-plane API")
+	backups := fs.Int("backups", distribution.DefaultStateBackups, "backup generations kept for each state write")
+	if err := fs.Parse(args); err != nil {
+		return exitcode.Usage(err)
+	}
+	if err := requireStatePath("serve", *statePath); err != nil {
+		return err
+	}
+
+	store, err := stateStoreFromEnv(*statePath, *backups)
+	if err != nil {
+		return err
+	}
+	state, err := newProcessState(*statePath, store)
+	if err != nil {
+		return err
+	}
+	problems, err := httpproblem.MappingFromEnv()
+	if err != nil {
+		return err
+	}
+	gate, err := rolloutGateFromEnv()
+	if err != nil {
+		return err
+	}
+	handler := newAPIHandler(state, sessionroute.NewFileService(*statePath, state), gate, os.Getenv(controlplaneclient.EnvAuthBearerToken), problems)
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("serve: %w", err)
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second, Protocols: new(http.Protocols)}
+	// The session-route gRPC service needs HTTP/2; cleartext h2c lets runtimes reach it without TLS
+	// termination in front of the control plane.
+	server.Protocols.SetHTTP1(true)
+	server.Protocols.SetUnencryptedHTTP2(true)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	_, _ = fmt.Fprintf(stdout, "rspp-control-plane serve: listening on http://%s (state=%s process_state=%s)\n", listener.Addr(), state.distributionPath, state.store.Location())
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve: %w", err)
+	}
+	return nil
+}
+
+type apiHandler struct {
+	state     *processState
+	sessions  sessionroute.Service
+	gate      rollout.SLOGate
+	authToken string
+	problems  httpproblem.Mapping
+}
+
+// newAPIHandler routes the control-plane API and the session-route gRPC service. Blue/green
+// cutovers are gated on gate; without one, rollout evaluation is unavailable. When authToken
+// is set every API call must carry it as a bearer token; /healthz stays open for probes. REST
+// failures are answered as problem+json with statuses from problems.
+func newAPIHandler(state *processState, sessions sessionroute.Service, gate rollout.SLOGate, authToken string, problems httpproblem.Mapping) http.Handler {
+	h := apiHandler{state: state, sessions: sessions, gate: gate, authToken: strings.TrimSpace(authToken), problems: problems}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeAPIJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("POST "+controlplaneclient.PathPublishPipeline, h.authorized(h.publish))
+	mux.HandleFunc("POST "+controlplaneclient.PathRollbackPipeline, h.authorized(h.rollback))
+	mux.HandleFunc("GET "+controlplaneclient.PathPipelines, h.authorized(h.listPipelines))
+	mux.HandleFunc("GET "+controlplaneclient.PathPipelines+"/{version}", h.authorized(h.getPipeline))
+	mux.HandleFunc("POST "+controlplaneclient.PathResolveSessionRoute, h.authorized(h.resolveSessionRoute))
+	mux.HandleFunc("POST "+controlplaneclient.PathIssueSessionToken, h.authorized(h.issueSessionToken))
+	mux.HandleFunc("POST "+controlplaneclient.PathSetSessionStatus, h.authorized(h.setSessionStatus))
+	mux.HandleFunc("GET "+controlplaneclient.PathSetSessionStatus, h.authorized(h.sessionStatus))
+	mux.HandleFunc("POST "+controlplaneclient.PathRegisterRolloutPools, h.authorized(h.registerRolloutPools))
+	mux.HandleFunc("POST "+controlplaneclient.PathShiftRollout, h.authorized(h.shiftRollout))
+	mux.HandleFunc("POST "+controlplaneclient.PathEvaluateRollout, h.authorized(h.evaluateRollout))
+	mux.HandleFunc("POST "+controlplaneclient.PathAbortRollout, h.authorized(h.abortRollout))
+	mux.HandleFunc("POST "+controlplaneclient.PathRouteRolloutSession, h.authorized(h.routeRolloutSessionRequest))
+	mux.HandleFunc("POST "+controlplaneclient.PathReportPoolSessions, h.authorized(h.reportPoolSessions))
+	mux.HandleFunc("GET "+controlplaneclient.PathRollouts+"/{environment}", h.authorized(h.rolloutStatus))
+	mux.HandleFunc("GET "+controlplaneclient.PathRollouts+"/{environment}/route", h.authorized(h.routeRolloutSession))
+	mux.HandleFunc("POST "+controlplaneclient.PathPublishShardMap, h.authorized(h.publishShardMap))
+	mux.HandleFunc("GET "+controlplaneclient.PathShardMap, h.authorized(h.shardMap))
+	mux.HandleFunc("GET "+controlplaneclient.PathDistribution, h.authorized(h.distributionState))
+	mux.Handle(sessionroute.GRPCPathPrefix, sessionroute.NewGRPCServer(sessions, h.authToken))
+	return mux
+}
+
+func (h apiHandler) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.authToken != "" {
+			presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(h.authToken)) != 1 {
+				h.writeAPIError(w, controlplaneclient.CodeUnauthorized, "missing or invalid bearer token")
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+func (h apiHandler) publish(w http.ResponseWriter, r *http.Request) {
+	var req controlplaneclient.PublishPipelineRequest
+	if !h.decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.writeAPIError(w, controlplaneclient.CodeInvalidRequest, err.Error())
+		return
+	}
+	update := distribution.PipelineRecordUpdate{
+		PipelineVersion:    strings.TrimSpace(req.PipelineVersion),
+		GraphDefinitionRef: strings.TrimSpace(req.GraphDefinitionRef),
+		ExecutionProfile:   strings.TrimSpace(req.ExecutionProfile),
+	}
+	if len(req.Spec) > 0 {
+		store, err := specstore.NewFileStoreFromEnv()
+		if err != nil {
+			h.writeAPIFailure(w, err)
+			return
+		}
+		if update.SpecHash, err = store.Put(req.Spec); err != nil {
+			h.writeAPIFailure(w, err)
+			return
+		}
+	}
+	previous, publishedAtMS, err := h.state.publish(update, req.Activate)
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, controlplaneclient.PublishPipelineResponse{
+		PipelineVersion: update.PipelineVersion,
+		SpecHash:        update.SpecHash,
+		Active:          req.Activate || previous == update.PipelineVersion,
+		PublishedAtMS:   publishedAtMS,
+	})
+}
+
+func (h apiHandler) rollback(w http.ResponseWriter, r *http.Request) {
+	var req controlplaneclient.RollbackPipelineRequest
+	if !h.decodeAPIRequest(w, r, &req) {
+		return
+	}
+	active, previous, err := h.state.rollback(strings.TrimSpace(req.PipelineVersion))
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, controlplaneclient.RollbackPipelineResponse{ActivePipelineVersion: active, PreviousPipelineVersion: previous})
+}
+
+func (h apiHandler) listPipelines(w http.ResponseWriter, _ *http.Request) {
+	state, err := distribution.DescribeActiveVersion(h.state.distributionPath)
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, controlplaneclient.PipelineList{
+		ActivePipelineVersion:   state.ActivePipelineVersion,
+		PipelineVersions:        state.PipelineVersions,
+		RollbackPipelineVersion: state.PreviousPipelineVersion,
+		StateSHA256:             state.StateHash,
+	})
+}
+
+func (h apiHandler) getPipeline(w http.ResponseWriter, r *http.Request) {
+	version := strings.TrimSpace(r.PathValue("version"))
+	backends, err := distribution.NewFileBackends(distribution.FileAdapterConfig{Path: h.state.distributionPath})
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	record, err := backends.Registry.ResolvePipelineRecord(version)
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	active, err := distribution.DescribeActiveVersion(h.state.distributionPath)
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, controlplaneclient.Pipeline{
+		PipelineVersion:    record.PipelineVersion,
+		GraphDefinitionRef: record.GraphDefinitionRef,
+		ExecutionProfile:   record.ExecutionProfile,
+		SpecHash:           record.SpecHash,
+		Active:             active.ActivePipelineVersion == record.PipelineVersion,
+	})
+}
+
+func (h apiHandler) resolveSessionRoute(w http.ResponseWriter, r *http.Request) {
+	var req controlplaneclient.ResolveSessionRouteRequest
+	if !h.decodeAPIRequest(w, r, &req) {
+		return
+	}
+	route, err := h.sessions.ResolveSessionRoute(req)
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, route)
+}
+
+func (h apiHandler) issueSessionToken(w http.ResponseWriter, r *http.Request) {
+	var req controlplaneclient.IssueSessionTokenRequest
+	if !h.decodeAPIRequest(w, r, &req) {
+		return
+	}
+	token, err := h.sessions.IssueSessionToken(req)
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, token)
+}
+
+func (h apiHandler) setSessionStatus(w http.ResponseWriter, r *http.Request) {
+	var req controlplaneclient.SetSessionStatusRequest
+	if !h.decodeAPIRequest(w, r, &req) {
+		return
+	}
+	status, err := h.sessions.SetSessionStatus(req)
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, status)
+}
+
+func (h apiHandler) sessionStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.sessions.SessionStatus(r.URL.Query().Get("session_id"))
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, status)
+}
+
+func (h apiHandler) registerRolloutPools(w http.ResponseWriter, r *http.Request) {
+	var req controlplaneclient.RegisterRolloutPoolsRequest
+	if !h.decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.writeAPIError(w, controlplaneclient.CodeInvalidRequest, err.Error())
+		return
+	}
+	status, err := h.state.registerRolloutPools(req.Environment, runtimePool(req.Blue, rollout.PoolBlue), runtimePool(req.Green, rollout.PoolGreen))
+	h.writeRolloutStatus(w, status, nil, err)
+}
+
+func (h apiHandler) shiftRollout(w http.ResponseWriter, r *http.Request) {
+	var req controlplaneclient.ShiftRolloutRequest
+	if !h.decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.writeAPIError(w, controlplaneclient.CodeInvalidRequest, err.Error())
+		return
+	}
+	status, err := h.state.shiftRollout(req.Environment, rollout.PoolColor(strings.TrimSpace(req.Target)), req.Weight)
+	h.writeRolloutStatus(w, status, nil, err)
+}
+
+func (h apiHandler) evaluateRollout(w http.ResponseWriter, r *http.Request) {
+	var req controlplaneclient.EvaluateRolloutRequest
+	if !h.decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if h.gate == nil {
+		h.writeAPIError(w, controlplaneclient.CodeUnavailable, fmt.Sprintf("no live slo gate configured; set %s", envRolloutBaselineDir))
+		return
+	}
+	status, result, err := h.state.evaluateRollout(req.Environment, h.gate)
+	h.writeRolloutStatus(w, status, &controlplaneclient.RolloutGate{Passed: result.Passed, Pending: result.Pending, Reason: result.Reason}, err)
+}
+
+func (h apiHandler) abortRollout(w http.ResponseWriter, r *http.Request) {
+	var req controlplaneclient.AbortRolloutRequest
+	if !h.decodeAPIRequest(w, r, &req) {
+		return
+	}
+	status, err := h.state.abortRollout(req.Environment, strings.TrimSpace(req.Reason))
+	h.writeRolloutStatus(w, status, nil, err)
+}
+
+func (h apiHandler) reportPoolSessions(w http.ResponseWriter, r *http.Request) {
+	var req controlplaneclient.ReportPoolSessionsRequest
+	if !h.decodeAPIRequest(w, r, &req) {
+		return
+	}
+	status, err := h.state.reportPoolSessions(req.Environment, rollout.PoolColor(strings.TrimSpace(req.Color)), req.ActiveSessions)
+	h.writeRolloutStatus(w, status, nil, err)
+}
+
+func (h apiHandler) rolloutStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.state.rolloutStatus(r.PathValue("environment"))
+	h.writeRolloutStatus(w, status, nil, err)
+}
+
+func (h apiHandler) routeRolloutSession(w http.ResponseWriter, r *http.Request) {
+	h.writeRoutedPool(w, r.PathValue("environment"), r.URL.Query().Get("session_id"))
+}
+
+func (h apiHandler) routeRolloutSessionRequest(w http.ResponseWriter, r *http.Request) {
+	var req controlplaneclient.RouteRolloutSessionRequest
+	if !h.decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.writeAPIError(w, controlplaneclient.CodeInvalidRequest, err.Error())
+		return
+	}
+	h.writeRoutedPool(w, req.Environment, req.SessionID)
+}
+
+func (h apiHandler) writeRoutedPool(w http.ResponseWriter, environment string, sessionID string) {
+	pool, err := h.state.routeRolloutSession(environment, sessionID)
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, rolloutPool(pool))
+}
+
+func (h apiHandler) writeRolloutStatus(w http.ResponseWriter, status rollout.BlueGreenStatus, gate *controlplaneclient.RolloutGate, err error) {
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	active, err := distribution.EnvironmentPipelineVersion(h.state.distributionPath, status.Environment)
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	out := controlplaneclient.RolloutStatus{
+		Environment:           status.Environment,
+		Phase:                 string(status.Phase),
+		Target:                string(status.Target),
+		ActivePipelineVersion: active,
+		Gate:                  gate,
+	}
+	for _, pool := range status.Pools {
+		out.Pools = append(out.Pools, rolloutPool(pool))
+	}
+	writeAPIJSON(w, http.StatusOK, out)
+}
+
+func runtimePool(pool controlplaneclient.RolloutPool, color rollout.PoolColor) rollout.RuntimePool {
+	return rollout.RuntimePool{
+		PoolID:          strings.TrimSpace(pool.PoolID),
+		Color:           color,
+		PipelineVersion: strings.TrimSpace(pool.PipelineVersion),
+		Weight:          pool.Weight,
+	}
+}
+
+func rolloutPool(pool rollout.RuntimePool) controlplaneclient.RolloutPool {
+	return controlplaneclient.RolloutPool{
+		PoolID:          pool.PoolID,
+		Color:           string(pool.Color),
+		PipelineVersion: pool.PipelineVersion,
+		Weight:          pool.Weight,
+		State:           string(pool.State),
+	}
+}
+
+func (h apiHandler) decodeAPIRequest(w http.ResponseWriter, r *http.Request, out any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(out); err != nil {
+		h.writeAPIError(w, controlplaneclient.CodeInvalidRequest, fmt.Sprintf("decode request: %v", err))
+		return false
+	}
+	return true
+}
+
+// writeAPIFailure maps err onto the problem+json error body controlplaneclient decodes.
+func (h apiHandler) writeAPIFailure(w http.ResponseWriter, err error) {
+	h.writeAPIError(w, sessionroute.Code(err), err.Error())
+}
+
+func (h apiHandler) writeAPIError(w http.ResponseWriter, code string, message string) {
+	httpproblem.Write(w, h.problems.Problem(code, message))
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
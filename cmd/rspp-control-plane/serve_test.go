@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/rollout"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sessionroute"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/httpproblem"
+)
+
+func writeServeState(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "distribution.json")
+	valid := `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "registry": {"default_pipeline_version": "pipeline-v1", "records": {"pipeline-v1": {"pipeline_version": "pipeline-v1", "graph_definition_ref": "graph/v1"}}},
+  "rollout": {"default_pipeline_version": "pipeline-v1"},
+  "routing_view": {"default": {"routing_view_snapshot": "routing-view/file", "admission_policy_snapshot": "admission-policy/file", "abi_compatibility_snapshot": "abi-compat/file"}},
+  "lease": {"default": {"lease_resolution_snapshot": "lease/file", "authority_epoch": 3}}
+}`
+	if err := os.WriteFile(path, []byte(valid), 0o644); err != nil {
+		t.Fatalf("write state: %v", err)
+	}
+	return path
+}
+
+func newServeTestServer(t *testing.T, path string, authToken string, gate rollout.SLOGate) (*httptest.Server, *processState) {
+	t.Helper()
+	state, err := loadProcessState(path, 2)
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	state.now = func() time.Time { return now }
+	svc := sessionroute.NewFileService(path, state)
+	svc.TokenSecret = []byte("test-secret")
+	svc.RuntimeEndpoint = "wss://runtime.example/ws"
+	svc.Now = state.now
+	server := httptest.NewUnstartedServer(newAPIHandler(state, svc, gate, authToken, httpproblem.DefaultMapping()))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	t.Cleanup(server.Close)
+	return server, state
+}
+
+func newServeTestClient(t *testing.T, baseURL string, token string) *controlplaneclient.Client {
+	t.Helper()
+	client, err := controlplaneclient.New(controlplaneclient.Config{BaseURL: baseURL, AuthBearerToken: token, RetryMaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("unexpected client error: %v", err)
+	}
+	return client
+}
+
+func getServeJSON(t *testing.T, url string, token string, out any) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("unexpected request error: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected get error: %v", err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		t.Fatalf("decode %s: %v", url, err)
+	}
+	return resp.StatusCode
+}
+
+func TestServePublishRouteAndRollback(t *testing.T) {
+	t.Parallel()
+
+	path := writeServeState(t)
+	server, _ := newServeTestServer(t, path, "", nil)
+	client := newServeTestClient(t, server.URL, "")
+	ctx := context.Background()
+
+	published, err := client.PublishPipeline(ctx, controlplaneclient.PublishPipelineRequest{PipelineVersion: "pipeline-v2", GraphDefinitionRef: "graph/v2", Activate: true})
+	if err != nil || !published.Active || published.PublishedAtMS == 0 {
+		t.Fatalf("expected activated publish, got %+v err=%v", published, err)
+	}
+
+	var list controlplaneclient.PipelineList
+	if status := getServeJSON(t, server.URL+controlplaneclient.PathPipelines, "", &list); status != http.StatusOK || list.ActivePipelineVersion != "pipeline-v2" || list.RollbackPipelineVersion != "pipeline-v1" || len(list.PipelineVersions) != 2 {
+		t.Fatalf("unexpected pipeline list status=%d %+v", status, list)
+	}
+	var pipeline controlplaneclient.Pipeline
+	if status := getServeJSON(t, server.URL+controlplaneclient.PathPipelines+"/pipeline-v2", "", &pipeline); status != http.StatusOK || !pipeline.Active || pipeline.GraphDefinitionRef != "graph/v2" {
+		t.Fatalf("unexpected pipeline status=%d %+v", status, pipeline)
+	}
+	var missing httpproblem.Problem
+	if status := getServeJSON(t, server.URL+controlplaneclient.PathPipelines+"/pipeline-v9", "", &missing); status != http.StatusNotFound || missing.Code != controlplaneclient.CodeNotFound || missing.Status != http.StatusNotFound {
+		t.Fatalf("expected unknown pipeline to be not found, got status=%d %+v", status, missing)
+	}
+
+	route, err := client.ResolveSessionRoute(ctx, controlplaneclient.ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: "sess-1"})
+	if err != nil || route.PipelineVersion != "pipeline-v2" || route.AuthorityEpoch != 3 || route.RuntimeEndpoint != "wss://runtime.example/ws" {
+		t.Fatalf("expected route on the published version, got %+v err=%v", route, err)
+	}
+	token, err := client.IssueSessionToken(ctx, controlplaneclient.IssueSessionTokenRequest{TenantID: "tenant-a", SessionID: "sess-1"})
+	if err != nil || token.Token == "" {
+		t.Fatalf("expected session token, got %+v err=%v", token, err)
+	}
+	grpcClient, err := controlplaneclient.NewGRPC(controlplaneclient.Config{BaseURL: server.URL, RetryMaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("unexpected grpc client error: %v", err)
+	}
+	t.Cleanup(func() { _ = grpcClient.Close() })
+	if grpcRoute, err := grpcClient.ResolveSessionRoute(ctx, controlplaneclient.ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: "sess-1"}); err != nil || grpcRoute != route {
+		t.Fatalf("expected grpc route to match rest route %+v, got %+v err=%v", route, grpcRoute, err)
+	}
+
+	if _, err := client.SetSessionStatus(ctx, controlplaneclient.SetSessionStatusRequest{SessionID: "sess-1", Status: controlplaneclient.SessionStatusEnded}); err != nil {
+		t.Fatalf("unexpected set status error: %v", err)
+	}
+	var status controlplaneclient.SessionStatus
+	if code := getServeJSON(t, server.URL+controlplaneclient.PathSetSessionStatus+"?session_id=sess-1", "", &status); code != http.StatusOK || status.Status != controlplaneclient.SessionStatusEnded {
+		t.Fatalf("unexpected session status code=%d %+v", code, status)
+	}
+	_, err = client.SetSessionStatus(ctx, controlplaneclient.SetSessionStatusRequest{SessionID: "sess-1", Status: controlplaneclient.SessionStatusActive})
+	var apiErr *controlplaneclient.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != controlplaneclient.CodeConflict {
+		t.Fatalf("expected reactivating an ended session to conflict, got %v", err)
+	}
+
+	rolledBack, err := client.RollbackPipeline(ctx, controlplaneclient.RollbackPipelineRequest{})
+	if err != nil || rolledBack.ActivePipelineVersion != "pipeline-v1" || rolledBack.PreviousPipelineVersion != "pipeline-v2" {
+		t.Fatalf("expected rollback to pipeline-v1, got %+v err=%v", rolledBack, err)
+	}
+
+	reloaded, err := loadProcessState(path, 2)
+	if err != nil {
+		t.Fatalf("unexpected reload error: %v", err)
+	}
+	audit, err := reloaded.auditLog()
+	if err != nil || len(audit) != 2 || audit[0].Action != auditActionPublish || audit[1].Action != auditActionRollback {
+		t.Fatalf("expected persisted publish and rollback audit entries, got %+v", audit)
+	}
+	shared, err := distribution.ReadAuditLog(path)
+	if err != nil || len(shared) != 1 || shared[0].Source != auditSource || shared[0].PipelineVersion != "pipeline-v1" {
+		t.Fatalf("expected rollback in the shared distribution audit log, got %+v err=%v", shared, err)
+	}
+	if status, ok, _ := reloaded.GetSessionStatus("sess-1"); !ok || status.Status != controlplaneclient.SessionStatusEnded {
+		t.Fatalf("expected persisted session status, got %+v ok=%v", status, ok)
+	}
+}
+
+func TestServeRequiresBearerToken(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newServeTestServer(t, writeServeState(t), "cp-secret", nil)
+	ctx := context.Background()
+
+	_, err := newServeTestClient(t, server.URL, "wrong").ResolveSessionRoute(ctx, controlplaneclient.ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: "sess-1"})
+	var apiErr *controlplaneclient.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != controlplaneclient.CodeUnauthorized || apiErr.Status != http.StatusUnauthorized {
+		t.Fatalf("expected wrong token to be unauthorized, got %v", err)
+	}
+	if _, err := newServeTestClient(t, server.URL, "cp-secret").ResolveSessionRoute(ctx, controlplaneclient.ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: "sess-1"}); err != nil {
+		t.Fatalf("unexpected authorized resolve error: %v", err)
+	}
+	grpcClient, err := controlplaneclient.NewGRPC(controlplaneclient.Config{BaseURL: server.URL, AuthBearerToken: "wrong", RetryMaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("unexpected grpc client error: %v", err)
+	}
+	t.Cleanup(func() { _ = grpcClient.Close() })
+	if _, err := grpcClient.ResolveSessionRoute(ctx, controlplaneclient.ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: "sess-1"}); !errors.Is(err, controlplaneclient.ErrUnauthorized) {
+		t.Fatalf("expected wrong token to be unauthenticated over grpc, got %v", err)
+	}
+	var health map[string]string
+	if status := getServeJSON(t, server.URL+"/healthz", "", &health); status != http.StatusOK || health["status"] != "ok" {
+		t.Fatalf("expected unauthenticated health probe, got status=%d %+v", status, health)
+	}
+}
+
+type serveTestGate struct {
+	result rollout.GateResult
+	pools  []string
+}
+
+func (g *serveTestGate) Evaluate(_ string, pool rollout.RuntimePool) (rollout.GateResult, error) {
+	g.pools = append(g.pools, pool.PoolID)
+	return g.result, nil
+}
+
+func TestServeBlueGreenRollout(t *testing.T) {
+	t.Parallel()
+
+	path := writeServeState(t)
+	gate := &serveTestGate{result: rollout.GateResult{Passed: true, Reason: "slo ok"}}
+	server, _ := newServeTestServer(t, path, "", gate)
+	client := newServeTestClient(t, server.URL, "")
+	ctx := context.Background()
+
+	register := controlplaneclient.RegisterRolloutPoolsRequest{
+		Environment: "prod",
+		Blue:        controlplaneclient.RolloutPool{PoolID: "rt-blue", PipelineVersion: "pipeline-v1", Weight: 100},
+		Green:       controlplaneclient.RolloutPool{PoolID: "rt-green", PipelineVersion: "pipeline-v2", Weight: 0},
+	}
+	var apiErr *controlplaneclient.APIError
+	if _, err := client.RegisterRolloutPools(ctx, register); !errors.As(err, &apiErr) || apiErr.Code != controlplaneclient.CodeNotFound {
+		t.Fatalf("expected unpublished green version to be not found, got %v", err)
+	}
+	if _, err := client.PublishPipeline(ctx, controlplaneclient.PublishPipelineRequest{PipelineVersion: "pipeline-v2", GraphDefinitionRef: "graph/v2"}); err != nil {
+		t.Fatalf("unexpected publish error: %v", err)
+	}
+	if _, err := client.EvaluateRollout(ctx, controlplaneclient.EvaluateRolloutRequest{Environment: "prod"}); !errors.As(err, &apiErr) || apiErr.Code != controlplaneclient.CodeNotFound {
+		t.Fatalf("expected evaluating an unregistered environment to be not found, got %v", err)
+	}
+	if status, err := client.RegisterRolloutPools(ctx, register); err != nil || status.Phase != string(rollout.CutoverPhaseStable) || len(status.Pools) != 2 {
+		t.Fatalf("expected stable registration, got %+v err=%v", status, err)
+	}
+	if _, err := client.EvaluateRollout(ctx, controlplaneclient.EvaluateRolloutRequest{Environment: "prod"}); !errors.As(err, &apiErr) || apiErr.Code != controlplaneclient.CodeConflict {
+		t.Fatalf("expected evaluating without a cutover to conflict, got %v", err)
+	}
+	shifted, err := client.ShiftRollout(ctx, controlplaneclient.ShiftRolloutRequest{Environment: "prod", Target: "green", Weight: 25})
+	if err != nil || shifted.Phase != string(rollout.CutoverPhaseShifting) || shifted.Target != "green" || shifted.ActivePipelineVersion != "pipeline-v1" {
+		t.Fatalf("expected shifting toward green, got %+v err=%v", shifted, err)
+	}
+	if _, err := client.RegisterRolloutPools(ctx, register); !errors.As(err, &apiErr) || apiErr.Code != controlplaneclient.CodeConflict {
+		t.Fatalf("expected re-registering mid-cutover to conflict, got %v", err)
+	}
+	var routed controlplaneclient.RolloutPool
+	if code := getServeJSON(t, server.URL+controlplaneclient.PathRollouts+"/prod/route?session_id=sess-1", "", &routed); code != http.StatusOK || routed.PoolID == "" {
+		t.Fatalf("expected a routed pool, got code=%d %+v", code, routed)
+	}
+	if pool, err := client.RouteRolloutSession(ctx, controlplaneclient.RouteRolloutSessionRequest{Environment: "prod", SessionID: "sess-1"}); err != nil || pool.PoolID != routed.PoolID {
+		t.Fatalf("expected the client route to match %s, got %+v err=%v", routed.PoolID, pool, err)
+	}
+	if _, err := client.RouteRolloutSession(ctx, controlplaneclient.RouteRolloutSessionRequest{Environment: "staging", SessionID: "sess-1"}); !errors.As(err, &apiErr) || apiErr.Code != controlplaneclient.CodeNotFound {
+		t.Fatalf("expected routing an unregistered environment to be not found, got %v", err)
+	}
+
+	gate.result = rollout.GateResult{Pending: true, Reason: "1 recent turns, need 5"}
+	held, err := client.EvaluateRollout(ctx, controlplaneclient.EvaluateRolloutRequest{Environment: "prod"})
+	if err != nil || held.Phase != string(rollout.CutoverPhaseShifting) || held.Gate == nil || !held.Gate.Pending || held.ActivePipelineVersion != "pipeline-v1" {
+		t.Fatalf("expected a pending gate to keep shifting, got %+v err=%v", held, err)
+	}
+	gate.result = rollout.GateResult{Passed: true, Reason: "slo ok"}
+
+	promoted, err := client.EvaluateRollout(ctx, controlplaneclient.EvaluateRolloutRequest{Environment: "prod"})
+	if err != nil || promoted.Phase != string(rollout.CutoverPhasePromoted) || promoted.Gate == nil || !promoted.Gate.Passed || promoted.ActivePipelineVersion != "pipeline-v2" {
+		t.Fatalf("expected promotion to activate pipeline-v2, got %+v err=%v", promoted, err)
+	}
+	if fmt.Sprint(gate.pools) != "[rt-green rt-green]" {
+		t.Fatalf("expected the gate to evaluate the green pool twice, got %v", gate.pools)
+	}
+	if version, err := distribution.EnvironmentPipelineVersion(path, "prod"); err != nil || version != "pipeline-v2" {
+		t.Fatalf("expected prod to activate pipeline-v2, got %q err=%v", version, err)
+	}
+	if active, err := distribution.DescribeActiveVersion(path); err != nil || active.ActivePipelineVersion != "pipeline-v1" {
+		t.Fatalf("expected other environments to keep pipeline-v1, got %+v err=%v", active, err)
+	}
+	drained, err := client.ReportPoolSessions(ctx, controlplaneclient.ReportPoolSessionsRequest{Environment: "prod", Color: "blue", ActiveSessions: 0})
+	if err != nil || drained.Pools[0].Color != "blue" || drained.Pools[0].State != string(rollout.PoolStateDrained) {
+		t.Fatalf("expected blue pool drained, got %+v err=%v", drained, err)
+	}
+
+	reloaded, err := loadProcessState(path, 2)
+	if err != nil {
+		t.Fatalf("unexpected reload error: %v", err)
+	}
+	if status, err := reloaded.rolloutStatus("prod"); err != nil || status.Phase != rollout.CutoverPhasePromoted {
+		t.Fatalf("expected persisted promoted rollout, got %+v err=%v", status, err)
+	}
+	audit, err := reloaded.auditLog()
+	if err != nil {
+		t.Fatalf("unexpected audit error: %v", err)
+	}
+	var actions []string
+	for _, entry := range audit {
+		actions = append(actions, entry.Action)
+	}
+	wantActions := []string{auditActionPublish, rollout.AuditActionRegister, rollout.AuditActionShift, rollout.AuditActionPromote, rollout.AuditActionDrained}
+	if fmt.Sprint(actions) != fmt.Sprint(wantActions) {
+		t.Fatalf("expected audit actions %v, got %v", wantActions, actions)
+	}
+	promote := audit[3]
+	if promote.Environment != "prod" || promote.PoolID != "rt-green" || promote.PipelineVersion != "pipeline-v2" || promote.PreviousPipelineVersion != "pipeline-v1" || !promote.Activated || !strings.Contains(promote.Detail, "reason=slo ok") {
+		t.Fatalf("expected promote audit to record the activation, got %+v", promote)
+	}
+}
+
+func TestServeRolloutEvaluationRequiresGate(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newServeTestServer(t, writeServeState(t), "", nil)
+	_, err := newServeTestClient(t, server.URL, "").EvaluateRollout(context.Background(), controlplaneclient.EvaluateRolloutRequest{Environment: "prod"})
+	var apiErr *controlplaneclient.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != controlplaneclient.CodeUnavailable || !strings.Contains(apiErr.Message, envRolloutBaselineDir) {
+		t.Fatalf("expected evaluation without a gate to be unavailable, got %v", err)
+	}
+}
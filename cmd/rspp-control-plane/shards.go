@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sharding"
+)
+
+// publishShardMap replaces the runtime shard map in the distribution state and audits the
+// change. Publishing advances the authority epoch, so runtimes reading the new map fence turns
+// of sessions whose owner moved.
+func (s *processState) publishShardMap(req controlplaneclient.PublishShardMapRequest) (sharding.ShardMap, error) {
+	workers := make([]string, 0, len(req.Workers))
+	endpoints := make(map[string]string, len(req.Workers))
+	for _, worker := range req.Workers {
+		id := strings.TrimSpace(worker.WorkerID)
+		workers = append(workers, id)
+		endpoints[id] = strings.TrimSpace(worker.Endpoint)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	shardMap, err := distribution.PublishShardMap(s.distributionPath, workers, endpoints, req.VirtualNodes)
+	if err != nil {
+		return sharding.ShardMap{}, err
+	}
+	entry := auditEntry{
+		AtMS:   s.now().UnixMilli(),
+		Action: auditActionShardMap,
+		Detail: fmt.Sprintf("snapshot=%s authority_epoch=%d workers=%s", shardMap.ShardMapSnapshot, shardMap.AuthorityEpoch, strings.Join(workers, ",")),
+	}
+	return shardMap, s.appendAudit(entry)
+}
+
+func (h apiHandler) publishShardMap(w http.ResponseWriter, r *http.Request) {
+	var req controlplaneclient.PublishShardMapRequest
+	if !h.decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.writeAPIError(w, controlplaneclient.CodeInvalidRequest, err.Error())
+		return
+	}
+	shardMap, err := h.state.publishShardMap(req)
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, apiShardMap(shardMap))
+}
+
+func (h apiHandler) shardMap(w http.ResponseWriter, _ *http.Request) {
+	shardMap, err := distribution.DescribeShardMap(h.state.distributionPath)
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, apiShardMap(shardMap))
+}
+
+// distributionState serves the distribution state file, so runtimes using the HTTP
+// distribution adapter resolve turn starts from what this control plane publishes.
+func (h apiHandler) distributionState(w http.ResponseWriter, _ *http.Request) {
+	raw, err := os.ReadFile(h.state.distributionPath)
+	if err != nil {
+		h.writeAPIFailure(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(raw)
+}
+
+func apiShardMap(shardMap sharding.ShardMap) controlplaneclient.ShardMap {
+	out := controlplaneclient.ShardMap{
+		ShardMapSnapshot: shardMap.ShardMapSnapshot,
+		AuthorityEpoch:   shardMap.AuthorityEpoch,
+		Workers:          make([]controlplaneclient.ShardWorker, 0, len(shardMap.Workers)),
+		VirtualNodes:     shardMap.VirtualNodes,
+	}
+	for _, worker := range shardMap.Workers {
+		out.Workers = append(out.Workers, controlplaneclient.ShardWorker{WorkerID: worker, Endpoint: shardMap.Endpoints[worker]})
+	}
+	return out
+}
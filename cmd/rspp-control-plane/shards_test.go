@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sharding"
+)
+
+func TestServeShardMapRoutesSessionsToOwners(t *testing.T) {
+	t.Parallel()
+
+	path := writeServeState(t)
+	server, state := newServeTestServer(t, path, "", nil)
+	client := newServeTestClient(t, server.URL, "")
+	ctx := context.Background()
+
+	var current controlplaneclient.ShardMap
+	if code := getServeJSON(t, server.URL+controlplaneclient.PathShardMap, "", &current); code != http.StatusOK || len(current.Workers) != 0 {
+		t.Fatalf("expected no shard map before publishing, got code=%d %+v", code, current)
+	}
+	var apiErr *controlplaneclient.APIError
+	if _, err := client.PublishShardMap(ctx, controlplaneclient.PublishShardMapRequest{Workers: []controlplaneclient.ShardWorker{{WorkerID: "runtime-a"}}}); !errors.As(err, &apiErr) || apiErr.Code != controlplaneclient.CodeInvalidRequest {
+		t.Fatalf("expected a worker without an endpoint to be rejected, got %v", err)
+	}
+	published, err := client.PublishShardMap(ctx, controlplaneclient.PublishShardMapRequest{Workers: []controlplaneclient.ShardWorker{
+		{WorkerID: "runtime-a", Endpoint: "wss://runtime-a/ws"},
+		{WorkerID: "runtime-b", Endpoint: "wss://runtime-b/ws"},
+	}})
+	if err != nil || published.AuthorityEpoch != 4 || len(published.Workers) != 2 {
+		t.Fatalf("expected the shard map published past lease epoch 3, got %+v err=%v", published, err)
+	}
+	if code := getServeJSON(t, server.URL+controlplaneclient.PathShardMap, "", &current); code != http.StatusOK || current.ShardMapSnapshot != published.ShardMapSnapshot {
+		t.Fatalf("expected the published shard map, got code=%d %+v", code, current)
+	}
+
+	// Runtimes reading the distribution from this control plane resolve the same owners the
+	// session routes point clients at.
+	backends, err := distribution.NewHTTPBackends(distribution.HTTPAdapterConfig{URL: server.URL + controlplaneclient.PathDistribution})
+	if err != nil {
+		t.Fatalf("unexpected http backends error: %v", err)
+	}
+	for _, sessionID := range []string{"sess-1", "sess-2", "sess-3", "sess-4"} {
+		route, err := client.ResolveSessionRoute(ctx, controlplaneclient.ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: sessionID})
+		if err != nil {
+			t.Fatalf("%s: unexpected route error: %v", sessionID, err)
+		}
+		if route.ShardOwner == "" || route.RuntimeEndpoint != "wss://"+route.ShardOwner+"/ws" || route.AuthorityEpoch != 4 {
+			t.Fatalf("%s: expected a route to the shard owner, got %+v", sessionID, route)
+		}
+		owner := sharding.Service{WorkerID: route.ShardOwner, Backend: backends.Sharding}
+		out, err := owner.Resolve(sharding.Input{SessionID: sessionID, RequestedAuthorityEpoch: route.AuthorityEpoch})
+		if err != nil || !out.Owned || !out.EpochValid {
+			t.Fatalf("%s: expected the routed worker to own the session, got %+v err=%v", sessionID, out, err)
+		}
+	}
+
+	audit, err := state.auditLog()
+	if err != nil || len(audit) != 1 || audit[0].Action != auditActionShardMap {
+		t.Fatalf("expected the shard map change audited, got %+v err=%v", audit, err)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build sqlite
+
+package main
+
+import (
+	"database/sql"
+	"net/url"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func init() {
+	openSQLiteDB = func(path string) (*sql.DB, error) {
+		// WAL lets readers proceed during a save; the busy timeout makes concurrent writers wait
+		// for the write lock instead of failing with SQLITE_BUSY.
+		query := url.Values{"_busy_timeout": {"5000"}, "_journal_mode": {"WAL"}}
+		return sql.Open("sqlite3", "file:"+path+"?"+query.Encode())
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/rollout"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sessionroute"
+)
+
+const processStateSchemaVersion = "cp-process-state/v1"
+
+// Audit actions recorded by a serving control plane.
+const (
+	auditActionPublish  = "publish"
+	auditActionRollback = distribution.AuditActionRollback
+	auditActionShardMap = "publish_shard_map"
+)
+
+// auditSource names a serving control plane in the distribution audit log.
+const auditSource = "rspp-control-plane"
+
+// processStatePath names the process state kept beside a distribution state file.
+func processStatePath(distributionPath string) string {
+	return distributionPath + ".process.json"
+}
+
+// auditEntry records one change a serving control plane applied to the distribution state.
+type auditEntry struct {
+	AtMS                    int64  `json:"at_ms"`
+	Action                  string `json:"action"`
+	PipelineVersion         string `json:"pipeline_version"`
+	PreviousPipelineVersion string `json:"previous_pipeline_version,omitempty"`
+	SpecHash                string `json:"spec_hash,omitempty"`
+	Activated               bool   `json:"activated,omitempty"`
+	// Environment, PoolID, Color, and Detail describe blue/green rollout transitions, whose
+	// actions are the rollout.AuditAction* values.
+	Environment string `json:"environment,omitempty"`
+	PoolID      string `json:"pool_id,omitempty"`
+	Color       string `json:"color,omitempty"`
+	Detail      string `json:"detail,omitempty"`
+}
+
+type processDocument struct {
+	SchemaVersion string `json:"schema_version"`
+	// Revision increments on every save; stores refuse a save based on an older revision.
+	Revision int64                                       `json:"revision"`
+	Sessions map[string]controlplaneclient.SessionStatus `json:"sessions"`
+	Audit    []auditEntry                                `json:"audit"`
+	// Rollouts holds each environment's blue/green deployment state.
+	Rollouts map[string]rollout.BlueGreenStatus `json:"rollouts,omitempty"`
+}
+
+// maxStateConflictRetries bounds how often a change is re-applied after losing a save race.
+const maxStateConflictRetries = 8
+
+// processState is what a serving control plane persists. Pipeline records and the active
+// version live in the distribution state that runtimes read; session statuses, blue/green
+// rollout state, and the audit log of applied changes live in a stateStore. Every change
+// re-reads the stored document, applies itself, and saves against the revision it read, so
+// control planes sharing a store never drop each other's audit entries or session statuses.
+// Distribution writes re-read and rewrite the state under distribution.LockStateFile, so
+// control planes sharing a distribution state never drop each other's changes either; mu
+// keeps this process's distribution write and its audit entry together.
+type processState struct {
+	distributionPath string
+	store            stateStore
+	now              func() time.Time
+
+	mu sync.Mutex
+}
+
+// loadProcessState opens the file-backed process state beside the distribution state.
+func loadProcessState(distributionPath string, backups int) (*processState, error) {
+	return newProcessState(distributionPath, newFileStateStore(processStatePath(distributionPath), backups))
+}
+
+func newProcessState(distributionPath string, store stateStore) (*processState, error) {
+	if _, err := distribution.DescribeActiveVersion(distributionPath); err != nil {
+		return nil, err
+	}
+	if _, err := store.Load(); err != nil {
+		return nil, err
+	}
+	return &processState{distributionPath: distributionPath, store: store, now: time.Now}, nil
+}
+
+// publish upserts a pipeline record, optionally activating it, and audits the change.
+func (s *processState) publish(update distribution.PipelineRecordUpdate, activate bool) (string, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, err := distribution.PublishPipelineRecord(s.distributionPath, update, activate)
+	if err != nil {
+		return "", 0, err
+	}
+	entry := auditEntry{
+		AtMS:                    s.now().UnixMilli(),
+		Action:                  auditActionPublish,
+		PipelineVersion:         update.PipelineVersion,
+		PreviousPipelineVersion: previous,
+		SpecHash:                update.SpecHash,
+		Activated:               activate,
+	}
+	return previous, entry.AtMS, s.appendAudit(entry)
+}
+
+// rollback activates target, or the newest backup's active version when target is empty.
+func (s *processState) rollback(target string) (string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, err := distribution.DescribeActiveVersion(s.distributionPath)
+	if err != nil {
+		return "", "", err
+	}
+	if target == "" {
+		target = state.PreviousPipelineVersion
+	}
+	if target == "" {
+		return "", "", fmt.Errorf("%w: no rollback target; no backup records a previous active version", sessionroute.ErrConflict)
+	}
+	if target == state.ActivePipelineVersion {
+		return "", "", fmt.Errorf("%w: pipeline version %s is already active", sessionroute.ErrConflict, target)
+	}
+	applied, err := distribution.RollbackActivePipelineVersion(s.distributionPath, target, auditSource, s.now())
+	if err != nil {
+		return "", "", err
+	}
+	entry := auditEntry{
+		AtMS:                    applied.AtMS,
+		Action:                  applied.Action,
+		PipelineVersion:         applied.PipelineVersion,
+		PreviousPipelineVersion: applied.PreviousPipelineVersion,
+		Activated:               applied.Activated,
+	}
+	return target, applied.PreviousPipelineVersion, s.appendAudit(entry)
+}
+
+func (s *processState) auditLog() ([]auditEntry, error) {
+	doc, err := s.store.Load()
+	return doc.Audit, err
+}
+
+// PutSessionStatus implements sessionroute.SessionStore.
+func (s *processState) PutSessionStatus(status controlplaneclient.SessionStatus) error {
+	return s.update(func(doc *processDocument) error {
+		doc.Sessions[status.SessionID] = status
+		return nil
+	})
+}
+
+// GetSessionStatus implements sessionroute.SessionStore.
+func (s *processState) GetSessionStatus(sessionID string) (controlplaneclient.SessionStatus, bool, error) {
+	doc, err := s.store.Load()
+	if err != nil {
+		return controlplaneclient.SessionStatus{}, false, err
+	}
+	status, ok := doc.Sessions[sessionID]
+	return status, ok, nil
+}
+
+func (s *processState) appendAudit(entry auditEntry) error {
+	if err := s.update(func(doc *processDocument) error {
+		doc.Audit = append(doc.Audit, entry)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("distribution state changed but the audit entry was not persisted: %w", err)
+	}
+	return nil
+}
+
+// update applies change to the latest stored document and saves it, starting over from a fresh
+// load whenever another writer saved first. A change that fails leaves the store untouched.
+func (s *processState) update(change func(*processDocument) error) error {
+	for attempt := 0; ; attempt++ {
+		doc, err := s.store.Load()
+		if err != nil {
+			return err
+		}
+		if err := change(&doc); err != nil {
+			return err
+		}
+		err = s.store.Save(doc)
+		if !errors.Is(err, errStateConflict) || attempt == maxStateConflictRetries {
+			return err
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sessionroute"
+)
+
+const (
+	// envStateBackend selects the process state backend: file (default), sqlite, or memory.
+	envStateBackend = "RSPP_CP_STATE_BACKEND"
+	// envStateSQLitePath overrides the SQLite database path; it defaults beside the distribution state.
+	envStateSQLitePath = "RSPP_CP_STATE_SQLITE_PATH"
+
+	stateBackendFile   = "file"
+	stateBackendSQLite = "sqlite"
+	stateBackendMemory = "memory"
+)
+
+// errStateConflict reports a save based on a revision another writer already replaced.
+var errStateConflict = fmt.Errorf("%w: process state was changed by another writer", sessionroute.ErrConflict)
+
+// stateStore persists the process document with optimistic locking.
+type stateStore interface {
+	// Load returns the stored document, or an empty document at revision 0 when none is stored.
+	Load() (processDocument, error)
+	// Save stores doc as revision doc.Revision+1 if the stored revision is still doc.Revision,
+	// and otherwise fails with errStateConflict.
+	Save(doc processDocument) error
+	// Location describes where the store keeps state, for operator output.
+	Location() string
+}
+
+// stateStoreFromEnv opens the backend named by envStateBackend for the distribution state at
+// distributionPath.
+func stateStoreFromEnv(distributionPath string, backups int) (stateStore, error) {
+	switch backend := strings.TrimSpace(os.Getenv(envStateBackend)); backend {
+	case "", stateBackendFile:
+		return newFileStateStore(processStatePath(distributionPath), backups), nil
+	case stateBackendSQLite:
+		path := strings.TrimSpace(os.Getenv(envStateSQLitePath))
+		if path == "" {
+			path = distributionPath + ".process.db"
+		}
+		return openSQLiteStateStore(path)
+	case stateBackendMemory:
+		return newMemoryStateStore(), nil
+	default:
+		return nil, fmt.Errorf("%s must be one of %s, %s, %s; got %q", envStateBackend, stateBackendFile, stateBackendSQLite, stateBackendMemory, backend)
+	}
+}
+
+func emptyProcessDocument() processDocument {
+	return processDocument{SchemaVersion: processStateSchemaVersion, Sessions: map[string]controlplaneclient.SessionStatus{}}
+}
+
+func decodeProcessDocument(raw []byte, location string) (processDocument, error) {
+	doc := emptyProcessDocument()
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return processDocument{}, fmt.Errorf("decode process state %s: %w", location, err)
+	}
+	if doc.SchemaVersion != processStateSchemaVersion {
+		return processDocument{}, fmt.Errorf("process state %s: unsupported schema_version %q", location, doc.SchemaVersion)
+	}
+	if doc.Sessions == nil {
+		doc.Sessions = map[string]controlplaneclient.SessionStatus{}
+	}
+	return doc, nil
+}
+
+// fileStateStore keeps the process document in a JSON file written through
+// distribution.WriteStateFile, so it keeps rotated backups and survives a crash mid-write. A
+// save holds the file's distribution.LockStateFile lock while it compares revisions and
+// writes, which makes the revision check atomic across processes sharing the file.
+type fileStateStore struct {
+	path    string
+	backups int
+}
+
+func newFileStateStore(path string, backups int) *fileStateStore {
+	return &fileStateStore{path: path, backups: backups}
+}
+
+func (f *fileStateStore) Location() string {
+	return stateBackendFile + ":" + f.path
+}
+
+func (f *fileStateStore) Load() (processDocument, error) {
+	raw, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return emptyProcessDocument(), nil
+	}
+	if err != nil {
+		return processDocument{}, fmt.Errorf("read process state %s: %w", f.path, err)
+	}
+	return decodeProcessDocument(raw, f.path)
+}
+
+func (f *fileStateStore) Save(doc processDocument) error {
+	unlock, err := distribution.LockStateFile(f.path)
+	if err != nil {
+		return fmt.Errorf("lock process state: %w", err)
+	}
+	defer unlock()
+	current, err := f.Load()
+	if err != nil {
+		return err
+	}
+	if current.Revision != doc.Revision {
+		return errStateConflict
+	}
+	doc.Revision++
+	raw, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return distribution.WriteStateFile(f.path, raw, f.backups)
+}
+
+// memoryStateStore keeps the process document in memory, for tests and throwaway control planes.
+type memoryStateStore struct {
+	mu       sync.Mutex
+	raw      []byte
+	revision int64
+}
+
+func newMemoryStateStore() *memoryStateStore {
+	return &memoryStateStore{}
+}
+
+func (m *memoryStateStore) Location() string {
+	return stateBackendMemory
+}
+
+// Load decodes a fresh copy so callers never share maps or slices with the stored document.
+func (m *memoryStateStore) Load() (processDocument, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.raw == nil {
+		return emptyProcessDocument(), nil
+	}
+	return decodeProcessDocument(m.raw, stateBackendMemory)
+}
+
+func (m *memoryStateStore) Save(doc processDocument) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.revision != doc.Revision {
+		return errStateConflict
+	}
+	doc.Revision++
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	m.raw, m.revision = raw, doc.Revision
+	return nil
+}
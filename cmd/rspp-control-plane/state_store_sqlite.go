@@ -0,0 +1,89 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// openSQLiteDB opens the SQLite database at path. It is set by sqlite_driver.go, which links
+// the cgo SQLite driver only into builds tagged sqlite.
+var openSQLiteDB func(path string) (*sql.DB, error)
+
+const sqliteStateSchema = `CREATE TABLE IF NOT EXISTS cp_process_state (
+	id INTEGER PRIMARY KEY CHECK (id = 1),
+	revision INTEGER NOT NULL,
+	document TEXT NOT NULL
+)`
+
+// sqliteStateStore keeps the process document in a single-row SQLite table. Saves are
+// conditional on the stored revision, so SQLite's own write locking makes the compare-and-swap
+// atomic across every control plane sharing the database.
+type sqliteStateStore struct {
+	path string
+	db   *sql.DB
+}
+
+func openSQLiteStateStore(path string) (*sqliteStateStore, error) {
+	if openSQLiteDB == nil {
+		return nil, fmt.Errorf("%s=%s requires rspp-control-plane built with -tags sqlite", envStateBackend, stateBackendSQLite)
+	}
+	db, err := openSQLiteDB(path)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite process state %s: %w", path, err)
+	}
+	if _, err := db.Exec(sqliteStateSchema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create sqlite process state %s: %w", path, err)
+	}
+	return &sqliteStateStore{path: path, db: db}, nil
+}
+
+func (s *sqliteStateStore) Location() string {
+	return stateBackendSQLite + ":" + s.path
+}
+
+func (s *sqliteStateStore) Load() (processDocument, error) {
+	var revision int64
+	var raw string
+	err := s.db.QueryRow(`SELECT revision, document FROM cp_process_state WHERE id = 1`).Scan(&revision, &raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return emptyProcessDocument(), nil
+	}
+	if err != nil {
+		return processDocument{}, fmt.Errorf("read sqlite process state %s: %w", s.path, err)
+	}
+	doc, err := decodeProcessDocument([]byte(raw), s.path)
+	if err != nil {
+		return processDocument{}, err
+	}
+	doc.Revision = revision
+	return doc, nil
+}
+
+func (s *sqliteStateStore) Save(doc processDocument) error {
+	expected := doc.Revision
+	doc.Revision++
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	var result sql.Result
+	if expected == 0 {
+		result, err = s.db.Exec(`INSERT INTO cp_process_state (id, revision, document) VALUES (1, ?, ?) ON CONFLICT (id) DO NOTHING`, doc.Revision, string(raw))
+	} else {
+		result, err = s.db.Exec(`UPDATE cp_process_state SET revision = ?, document = ? WHERE id = 1 AND revision = ?`, doc.Revision, string(raw), expected)
+	}
+	if err != nil {
+		return fmt.Errorf("write sqlite process state %s: %w", s.path, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("write sqlite process state %s: %w", s.path, err)
+	}
+	if rows == 0 {
+		return errStateConflict
+	}
+	return nil
+}
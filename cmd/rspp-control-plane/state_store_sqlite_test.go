@@ -0,0 +1,22 @@
+//go:build sqlite
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteStateStoreKeepsConcurrentAuditEntries(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "process.db")
+	exerciseStateStore(t, "sqlite", func() stateStore {
+		store, err := openSQLiteStateStore(path)
+		if err != nil {
+			t.Fatalf("unexpected sqlite open error: %v", err)
+		}
+		t.Cleanup(func() { _ = store.db.Close() })
+		return store
+	})
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sessionroute"
+)
+
+// exerciseStateStore checks optimistic locking on one store, then has concurrent writers, each
+// with its own processState and store handle from open, append audit entries to shared state.
+func exerciseStateStore(t *testing.T, name string, open func() stateStore) {
+	t.Helper()
+	store := open()
+	doc, err := store.Load()
+	if err != nil || doc.Revision != 0 || len(doc.Audit) != 0 {
+		t.Fatalf("%s: expected empty state, got %+v err=%v", name, doc, err)
+	}
+	doc.Audit = append(doc.Audit, auditEntry{Action: auditActionPublish, PipelineVersion: "pipeline-v0"})
+	if err := store.Save(doc); err != nil {
+		t.Fatalf("%s: unexpected first save error: %v", name, err)
+	}
+	if err := store.Save(doc); !errors.Is(err, errStateConflict) || !errors.Is(err, sessionroute.ErrConflict) {
+		t.Fatalf("%s: expected stale save to conflict, got %v", name, err)
+	}
+
+	distributionPath := writeServeState(t)
+	const writers, entriesPerWriter = 6, 5
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		state, err := newProcessState(distributionPath, open())
+		if err != nil {
+			t.Fatalf("%s: unexpected open error: %v", name, err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < entriesPerWriter; i++ {
+				if err := state.appendAudit(auditEntry{Action: auditActionPublish, PipelineVersion: fmt.Sprintf("pipeline-w%d-%d", w, i)}); err != nil {
+					t.Errorf("%s: unexpected append error: %v", name, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	doc, err = open().Load()
+	if err != nil {
+		t.Fatalf("%s: unexpected load error: %v", name, err)
+	}
+	seen := map[string]bool{}
+	for _, entry := range doc.Audit {
+		seen[entry.PipelineVersion] = true
+	}
+	if len(doc.Audit) != 1+writers*entriesPerWriter || len(seen) != len(doc.Audit) || doc.Revision != int64(len(doc.Audit)) {
+		t.Fatalf("%s: expected every concurrent audit entry kept once, got %d entries at revision %d", name, len(doc.Audit), doc.Revision)
+	}
+}
+
+func TestStateStoresKeepConcurrentAuditEntries(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "distribution.json.process.json")
+	exerciseStateStore(t, "file", func() stateStore { return newFileStateStore(path, 1) })
+
+	memory := newMemoryStateStore()
+	exerciseStateStore(t, "memory", func() stateStore { return memory })
+}
+
+// Environment of one control-plane process spawned by
+// TestControlPlaneProcessesKeepEveryPublish.
+const (
+	envPublishWorker = "RSPP_TEST_PUBLISH_WORKER"
+	envPublishPath   = "RSPP_TEST_PUBLISH_PATH"
+)
+
+const publishesPerProcess = 6
+
+func TestControlPlaneProcessesKeepEveryPublish(t *testing.T) {
+	if worker := os.Getenv(envPublishWorker); worker != "" {
+		path := os.Getenv(envPublishPath)
+		state, err := newProcessState(path, newFileStateStore(processStatePath(path), 1))
+		if err != nil {
+			t.Fatalf("unexpected open error: %v", err)
+		}
+		for i := range publishesPerProcess {
+			version := fmt.Sprintf("pipeline-w%s-%d", worker, i)
+			if _, _, err := state.publish(distribution.PipelineRecordUpdate{PipelineVersion: version, GraphDefinitionRef: "graph/" + version}, false); err != nil {
+				t.Fatalf("unexpected publish error: %v", err)
+			}
+		}
+		return
+	}
+	t.Parallel()
+
+	path := writeServeState(t)
+	const processes = 4
+	cmds := make([]*exec.Cmd, 0, processes)
+	outputs := make([]*bytes.Buffer, 0, processes)
+	for i := range processes {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestControlPlaneProcessesKeepEveryPublish$")
+		cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", envPublishWorker, i), envPublishPath+"="+path)
+		output := &bytes.Buffer{}
+		cmd.Stdout, cmd.Stderr = output, output
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("unexpected start error for process %d: %v", i, err)
+		}
+		cmds, outputs = append(cmds, cmd), append(outputs, output)
+	}
+	for i, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("control plane %d failed: %v\n%s", i, err, outputs[i].String())
+		}
+	}
+
+	active, err := distribution.DescribeActiveVersion(path)
+	if err != nil || len(active.PipelineVersions) != 1+processes*publishesPerProcess {
+		t.Fatalf("expected every process's records kept, got %v err=%v", active.PipelineVersions, err)
+	}
+	state, err := newProcessState(path, newFileStateStore(processStatePath(path), 1))
+	if err != nil {
+		t.Fatalf("unexpected open error: %v", err)
+	}
+	if audit, err := state.auditLog(); err != nil || len(audit) != processes*publishesPerProcess {
+		t.Fatalf("expected every publish audited, got %d entries err=%v", len(audit), err)
+	}
+}
+
+func TestStateStoreFromEnv(t *testing.T) {
+	distributionPath := filepath.Join(t.TempDir(), "distribution.json")
+	tests := []struct {
+		name         string
+		backend      string
+		wantLocation string
+		wantErr      bool
+	}{
+		{name: "default", backend: "", wantLocation: "file:" + processStatePath(distributionPath)},
+		{name: "file", backend: "file", wantLocation: "file:" + processStatePath(distributionPath)},
+		{name: "memory", backend: "memory", wantLocation: "memory"},
+		{name: "unknown", backend: "postgres", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Setenv(envStateBackend, tc.backend)
+		store, err := stateStoreFromEnv(distributionPath, 1)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected error", tc.name)
+			}
+			continue
+		}
+		if err != nil || store.Location() != tc.wantLocation {
+			t.Fatalf("%s: expected %s, got %v err=%v", tc.name, tc.wantLocation, store, err)
+		}
+	}
+	if openSQLiteDB == nil {
+		t.Setenv(envStateBackend, "sqlite")
+		if _, err := stateStoreFromEnv(distributionPath, 1); err == nil {
+			t.Fatalf("expected sqlite backend to require the sqlite build tag")
+		}
+	}
+}
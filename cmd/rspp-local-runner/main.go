@@ -1,7 +1,238 @@
 package main
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/recording"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/callanalysis"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/demo"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/bootstrap"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/exitcode"
+)
+
+// profileSandbox selects the deterministic demo providers; bootstrap.ProfileLocal selects the
+// offline whisper.cpp/llama.cpp/piper chain.
+const profileSandbox = "sandbox"
 
 func main() {
-	fmt.Println("rspp-local-runner: scaffold initialized")
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "rspp-local-runner: %v\n", err)
+		os.Exit(int(exitcode.For(err)))
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		printUsage(stdout)
+		return nil
+	}
+	switch args[0] {
+	case "demo":
+		return runDemo(args[1:], stdout)
+	case "loopback":
+		return runLoopback(args[1:], stdout)
+	case "help", "-h", "--help":
+		printUsage(stdout)
+		return nil
+	default:
+		printUsage(stdout)
+		return exitcode.Usagef("unsupported command %q", args[0])
+	}
+}
+
+func runDemo(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("demo", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	addr := fs.String("addr", "127.0.0.1:8787", "listen address for the embedded web client")
+	artifactsDir := fs.String("artifacts-dir", filepath.Join(".codex", "demo"), "directory for session audio and timeline baseline artifacts")
+	callAnalysis := fs.Bool("call-analysis", false, "write sandbox end-of-call analysis artifacts after each session")
+	profile := fs.String("profile", profileSandbox, "provider profile: sandbox or local")
+	if err := fs.Parse(args); err != nil {
+		return exitcode.Usage(err)
+	}
+
+	providers, err := chainProviders(*profile)
+	if err != nil {
+		return fmt.Errorf("demo: %w", err)
+	}
+	serverCfg := demo.ServerConfig{ArtifactsDir: *artifactsDir, Providers: providers}
+	if *callAnalysis {
+		stage, err := callanalysis.NewStage(callanalysis.Config{ArtifactsDir: *artifactsDir}, demo.SandboxAnalyzers())
+		if err != nil {
+			return fmt.Errorf("demo: %w", err)
+		}
+		serverCfg.Analysis = stage
+		defer func() {
+			drainCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = stage.Drain(drainCtx)
+		}()
+	}
+	handler, err := demo.NewHandler(serverCfg)
+	if err != nil {
+		return fmt.Errorf("demo: %w", err)
+	}
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("demo: %w", err)
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdown := make(chan struct{})
+	go func() {
+		defer close(shutdown)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+		// Sessions run on hijacked connections that server.Shutdown leaves open; close them and
+		// wait for their end-of-session artifact writes before the process exits.
+		_ = handler.Shutdown(shutdownCtx)
+	}()
+
+	_, _ = fmt.Fprintf(stdout, "rspp-local-runner demo: open http://%s (%s providers, artifacts in %s)\n", listener.Addr(), *profile, *artifactsDir)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("demo: %w", err)
+	}
+	<-shutdown
+	return nil
+}
+
+// runLoopback runs one recorded utterance through STT, LLM, and TTS and writes the spoken reply,
+// exercising a full provider chain without a browser.
+func runLoopback(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("loopback", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	input := fs.String("input", "", "WAV file with the user utterance (16-bit PCM)")
+	output := fs.String("output", filepath.Join(".codex", "loopback", "reply.wav"), "WAV file for the synthesized reply")
+	profile := fs.String("profile", bootstrap.ProfileLocal, "provider profile: local or sandbox")
+	if err := fs.Parse(args); err != nil {
+		return exitcode.Usage(err)
+	}
+	if *input == "" {
+		return exitcode.Usagef("loopback: -input is required")
+	}
+
+	providers, err := chainProviders(*profile)
+	if err != nil {
+		return fmt.Errorf("loopback: %w", err)
+	}
+	in, err := os.Open(*input)
+	if err != nil {
+		return fmt.Errorf("loopback: %w", err)
+	}
+	pcm, sampleRateHz, channels, err := recording.DecodeWAV(in)
+	_ = in.Close()
+	if err != nil {
+		return fmt.Errorf("loopback: %w", err)
+	}
+	pcm = downmix(pcm, channels)
+
+	transcript, err := providers.STT.Transcribe(pcm, sampleRateHz)
+	if err != nil {
+		return fmt.Errorf("loopback stt: %w", err)
+	}
+	reply, err := providers.LLM.Respond(transcript)
+	if err != nil {
+		return fmt.Errorf("loopback llm: %w", err)
+	}
+	replyPCM, err := providers.TTS.Synthesize(reply, sampleRateHz)
+	if err != nil {
+		return fmt.Errorf("loopback tts: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(*output), 0o755); err != nil {
+		return fmt.Errorf("loopback: %w", err)
+	}
+	out, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("loopback: %w", err)
+	}
+	if err := (recording.WAVEncoder{}).Encode(out, replyPCM, sampleRateHz, 1); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("loopback: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("loopback: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(stdout, "rspp-local-runner loopback (%s providers)\n", *profile)
+	_, _ = fmt.Fprintf(stdout, "  transcript: %s\n", transcript)
+	_, _ = fmt.Fprintf(stdout, "  reply: %s\n", reply)
+	_, _ = fmt.Fprintf(stdout, "  audio: %s (%d ms)\n", *output, int64(len(replyPCM))*1000/int64(sampleRateHz))
+	return nil
+}
+
+// chainProviders resolves a profile to the three demo provider stages. The local profile takes
+// its stages from the bootstrap catalog, so it exercises the same adapters the runtime registers.
+func chainProviders(profile string) (demo.Providers, error) {
+	switch profile {
+	case profileSandbox:
+		return demo.SandboxProviders(), nil
+	case bootstrap.ProfileLocal:
+		runtimeProviders, err := bootstrap.BuildLocalProviders()
+		if err != nil {
+			return demo.Providers{}, err
+		}
+		var providers demo.Providers
+		for _, modality := range []contracts.Modality{contracts.ModalitySTT, contracts.ModalityLLM, contracts.ModalityTTS} {
+			ids, err := runtimeProviders.Catalog.ProviderIDs(modality)
+			if err != nil {
+				return demo.Providers{}, err
+			}
+			adapter, _ := runtimeProviders.Catalog.Adapter(modality, ids[0])
+			switch stage := adapter.(type) {
+			case demo.Transcriber:
+				providers.STT = stage
+			case demo.Responder:
+				providers.LLM = stage
+			case demo.Synthesizer:
+				providers.TTS = stage
+			}
+		}
+		if providers.STT == nil || providers.LLM == nil || providers.TTS == nil {
+			return demo.Providers{}, fmt.Errorf("local profile does not cover the stt/llm/tts chain")
+		}
+		return providers, nil
+	default:
+		return demo.Providers{}, exitcode.Usagef("unknown provider profile %q (want %s or %s)", profile, profileSandbox, bootstrap.ProfileLocal)
+	}
+}
+
+// downmix averages interleaved channels into mono.
+func downmix(pcm []int16, channels int) []int16 {
+	if channels <= 1 {
+		return pcm
+	}
+	mono := make([]int16, len(pcm)/channels)
+	for i := range mono {
+		sum := 0
+		for c := 0; c < channels; c++ {
+			sum += int(pcm[i*channels+c])
+		}
+		mono[i] = int16(sum / channels)
+	}
+	return mono
+}
+
+func printUsage(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "rspp-local-runner usage:")
+	_, _ = fmt.Fprintln(w, "  rspp-local-runner demo [-addr <host:port>] [-artifacts-dir <dir>] [-call-analysis] [-profile sandbox|local]")
+	_, _ = fmt.Fprintln(w, "  rspp-local-runner loopback -input <wav> [-output <wav>] [-profile local|sandbox]")
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/recording"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/exitcode"
+)
+
+func TestRunLoopbackWritesReply(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "utterance.wav")
+	output := filepath.Join(dir, "out", "reply.wav")
+	// One second of stereo tone, which the sandbox STT hears as speech.
+	pcm := make([]int16, 2*16000)
+	for i := 0; i < 16000; i++ {
+		sample := int16(8000 * math.Sin(2*math.Pi*220*float64(i)/16000))
+		pcm[2*i], pcm[2*i+1] = sample, sample
+	}
+	var encoded bytes.Buffer
+	if err := (recording.WAVEncoder{}).Encode(&encoded, pcm, 16000, 2); err != nil {
+		t.Fatalf("encode input: %v", err)
+	}
+	if err := os.WriteFile(input, encoded.Bytes(), 0o644); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	if err := run([]string{"loopback", "-profile", "sandbox", "-input", input, "-output", output}, &stdout); err != nil {
+		t.Fatalf("unexpected loopback error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "transcript: (sandbox transcript: 0.9 seconds of speech)") || !strings.Contains(stdout.String(), "reply: ") {
+		t.Fatalf("expected transcript and reply in output, got %s", stdout.String())
+	}
+	reply, err := os.Open(output)
+	if err != nil {
+		t.Fatalf("open reply: %v", err)
+	}
+	defer reply.Close()
+	replyPCM, rate, channels, err := recording.DecodeWAV(reply)
+	if err != nil {
+		t.Fatalf("decode reply: %v", err)
+	}
+	if len(replyPCM) == 0 || rate != 16000 || channels != 1 {
+		t.Fatalf("expected mono 16 kHz reply audio, got %d samples at %d Hz x%d", len(replyPCM), rate, channels)
+	}
+}
+
+func TestRunLoopbackRejectsInvalidInvocations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+		want exitcode.Code
+	}{
+		{name: "unknown command", args: []string{"replay"}, want: exitcode.UsageError},
+		{name: "unknown flag", args: []string{"loopback", "-bogus"}, want: exitcode.UsageError},
+		{name: "missing input", args: []string{"loopback", "-profile", "sandbox"}, want: exitcode.UsageError},
+		{name: "unknown profile", args: []string{"loopback", "-profile", "edge", "-input", "utterance.wav"}, want: exitcode.UsageError},
+		{name: "missing file", args: []string{"loopback", "-profile", "sandbox", "-input", filepath.Join(t.TempDir(), "absent.wav")}, want: exitcode.InfrastructureError},
+	}
+	for _, tc := range tests {
+		err := run(tc.args, &bytes.Buffer{})
+		if err == nil {
+			t.Fatalf("%s: expected loopback error", tc.name)
+		}
+		if got := exitcode.For(err); got != tc.want {
+			t.Fatalf("%s: expected exit code %s, got %s (%v)", tc.name, tc.want, got, err)
+		}
+	}
+}
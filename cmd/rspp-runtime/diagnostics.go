@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/demo"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/diagnostics"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/exitcode"
+)
+
+// runDiagnostics dumps a point-in-time snapshot of a running transport instance, read from its
+// admin socket, as a JSON artifact to attach alongside rspp-cli debug bundles.
+func runDiagnostics(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("diagnostics", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	socketPath := fs.String("socket", diagnostics.AdminSocketPathFromEnv(), "admin socket of the running rspp-runtime instance")
+	outputPath := fs.String("out", filepath.Join(".codex", "ops", "runtime-diagnostics.json"), "path to write the diagnostics snapshot json")
+	timeoutMS := fs.Int64("timeout-ms", 5000, "admin socket request timeout in milliseconds")
+	if err := fs.Parse(args); err != nil {
+		return exitcode.Usage(err)
+	}
+	if *timeoutMS < 1 {
+		return exitcode.Usagef("diagnostics: timeout-ms must be >=1")
+	}
+
+	snapshot, err := diagnostics.Fetch(strings.TrimSpace(*socketPath), time.Duration(*timeoutMS)*time.Millisecond)
+	if err != nil {
+		return fmt.Errorf("diagnostics: %w", err)
+	}
+	if err := writeJSONArtifact(*outputPath, snapshot); err != nil {
+		return fmt.Errorf("diagnostics: %w", err)
+	}
+	_, _ = fmt.Fprintf(stdout, "rspp-runtime diagnostics: report=%s active_sessions=%d turns=%d providers=%d\n", *outputPath, snapshot.Sessions.Active, snapshot.Sessions.Turns, len(snapshot.Providers))
+	return nil
+}
+
+// startAdminSocket serves diagnostics snapshots for a transport instance on its admin socket;
+// an empty path disables it. The socket also answers decision queries from the serving
+// runtime's decision index, and runbook remediations: opening a session provider's circuit
+// breaker, and shifting provider weights when a provider catalog file is configured.
+// Provider health and snapshot freshness come from the serving runtime's warm-up tracker and
+// freshness monitor when those are running.
+func startAdminSocket(path string, serving *servingRuntime) (func(), error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return func() {}, nil
+	}
+	now := serving.now
+	sources := diagnostics.Sources{Now: now, StartedAt: now(), Sessions: serving.sessions, Decisions: serving.decisions}
+	if pipeline, ok := telemetry.DefaultEmitter().(*telemetry.Pipeline); ok {
+		sources.Telemetry = pipeline.Stats
+	}
+	if serving.warmup != nil {
+		sources.Providers = serving.warmup
+	}
+	if serving.freshness != nil {
+		sources.Freshness = serving.freshness.Report
+	}
+	if breaker := serving.sessionProviders().CircuitBreaker; breaker != nil {
+		sources.Remediation.OpenBreaker = breaker.Open
+	}
+	if serving.catalog != nil {
+		sources.Remediation.ShiftWeights = serving.shiftProviderWeights
+	}
+
+	admin, err := diagnostics.ListenAdmin(path, sources)
+	if err != nil {
+		return nil, err
+	}
+	return func() { _ = admin.Close() }, nil
+}
+
+// trackSession registers a demo session with the admin socket until the returned func
+// is called.
+func trackSession(sessions *diagnostics.SessionTracker, session *demo.Session) func() {
+	status := session.Status()
+	return sessions.Track(status.SessionID, func() diagnostics.SessionStatus {
+		status := session.Status()
+		return diagnostics.SessionStatus{
+			SessionID:       status.SessionID,
+			TenantID:        status.TenantID,
+			PipelineVersion: status.PipelineVersion,
+			StartedAtUTC:    status.StartedAt.UTC().Format(time.RFC3339Nano),
+			Turns:           status.Turns,
+			Recorder:        status.Recorder,
+		}
+	})
+}
+
+// distributionConfigured reports whether any CP distribution adapter is configured.
+func distributionConfigured() bool {
+	for _, name := range []string{distribution.EnvFileAdapterPath, distribution.EnvHTTPAdapterURL, distribution.EnvHTTPAdapterURLs} {
+		if strings.TrimSpace(os.Getenv(name)) != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -2,28 +2,44 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/cost"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/batch"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/demo"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/bootstrap"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/standby"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/warmup"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/snapshotfreshness"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/synthetic"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/exitcode"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/artifactwriter"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/completion"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/ops"
 )
 
 func main() {
 	if err := run(os.Args[1:], os.Stdout, os.Stderr, time.Now); err != nil {
 		fmt.Fprintf(os.Stderr, "rspp-runtime: %v\n", err)
-		os.Exit(1)
+		os.Exit(int(exitcode.For(err)))
 	}
 }
 
@@ -41,12 +57,28 @@ func run(args []string, stdout io.Writer, _ io.Writer, now func() time.Time) err
 	switch args[0] {
 	case "retention-sweep":
 		return runRetentionSweep(args[1:], stdout, now)
+	case "synthetic-monitor":
+		return runSyntheticMonitor(args[1:], stdout, now)
+	case "snapshot-freshness":
+		return runSnapshotFreshness(args[1:], stdout, now)
+	case "batch":
+		return runBatch(args[1:], stdout, now)
+	case "websocket":
+		return runWebSocket(args[1:], stdout, now)
+	case "webrtc":
+		return runWebRTC(args[1:], stdout, now)
+	case "twilio":
+		return runTwilio(args[1:], stdout, now)
+	case "diagnostics":
+		return runDiagnostics(args[1:], stdout)
+	case "completion":
+		return runCompletion(args[1:], stdout)
 	case "help", "-h", "--help":
 		printUsage(stdout)
 		return nil
 	default:
 		printUsage(stdout)
-		return fmt.Errorf("unsupported command %q", args[0])
+		return exitcode.Usagef("unsupported command %q", args[0])
 	}
 }
 
@@ -71,7 +103,7 @@ func setupRuntimeTelemetry() (func(), error) {
 }
 
 func runProviderBootstrap(stdout io.Writer) error {
-	runtimeProviders, err := bootstrap.BuildMVPProviders()
+	runtimeProviders, _, err := buildRuntimeProviders()
 	if err != nil {
 		return fmt.Errorf("provider bootstrap failed: %w", err)
 	}
@@ -80,6 +112,247 @@ func runProviderBootstrap(stdout io.Writer) error {
 		return fmt.Errorf("provider summary failed: %w", err)
 	}
 	_, _ = fmt.Fprintf(stdout, "rspp-runtime: %s\n", summary)
+
+	if providerWarmupEnabled() {
+		report, err := warmup.NewTracker(runtimeProviders.Catalog).WarmRuntime()
+		if err != nil {
+			return fmt.Errorf("provider warmup failed: %w", err)
+		}
+		_, _ = fmt.Fprintf(stdout, "rspp-runtime: %s\n", report.Summary())
+	}
+	if standby.EnabledFromEnv() {
+		_, _ = fmt.Fprintf(stdout, "rspp-runtime: %s\n", holdProviderStandby(standby.NewManager(runtimeProviders.Catalog, cost.DefaultPriceTable())))
+	}
+	return nil
+}
+
+// holdProviderStandby holds a warm standby on each modality's secondary provider and
+// summarizes the outcome; modalities with a single provider have no failover to speed up.
+func holdProviderStandby(manager *standby.Manager) string {
+	held, unsupported, failed := 0, 0, 0
+	for _, modality := range []contracts.Modality{contracts.ModalitySTT, contracts.ModalityLLM, contracts.ModalityTTS} {
+		result, err := manager.Hold(modality, "")
+		switch {
+		case err != nil:
+		case result.Held:
+			held++
+		case result.Reason == "standby_unsupported":
+			unsupported++
+		default:
+			failed++
+		}
+	}
+	return fmt.Sprintf("provider warm standby held=%d unsupported=%d failed=%d", held, unsupported, failed)
+}
+
+// buildRuntimeProviders builds from the declarative provider catalog file when
+// RSPP_PROVIDER_CATALOG_FILE is set, and from the RSPP_PROVIDER_PROFILE profile otherwise.
+// The returned manager reloads the catalog file; it is nil for a profile.
+func buildRuntimeProviders() (bootstrap.RuntimeProviders, *bootstrap.CatalogManager, error) {
+	path := bootstrap.CatalogFileFromEnv()
+	if path == "" {
+		runtimeProviders, err := bootstrap.BuildProfile(bootstrap.ProfileFromEnv())
+		return runtimeProviders, nil, err
+	}
+	manager, err := bootstrap.NewCatalogManager(path, bootstrap.Options{})
+	if err != nil {
+		return bootstrap.RuntimeProviders{}, nil, err
+	}
+	return manager.RuntimeProviders(), manager, nil
+}
+
+func providerWarmupEnabled() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("RSPP_PROVIDER_WARMUP"))) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+// syntheticMonitorArtifact is the rolling availability plus the canary error budget over the
+// SLO trend store.
+type syntheticMonitorArtifact struct {
+	synthetic.Availability
+	TrendStorePath string                 `json:"trend_store_path"`
+	ErrorBudget    *ops.ErrorBudgetReport `json:"error_budget,omitempty"`
+}
+
+func runSyntheticMonitor(args []string, stdout io.Writer, now func() time.Time) error {
+	fs := flag.NewFlagSet("synthetic-monitor", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	mode := fs.String("mode", string(synthetic.ModeLoopback), "canary transport mode: loopback|live")
+	target := fs.String("target", "", "deployed runtime websocket transport endpoint, e.g. wss://host/ws (live mode)")
+	intervalMS := fs.Int64("interval-ms", 30000, "interval between canary sessions in milliseconds")
+	timeoutMS := fs.Int64("timeout-ms", synthetic.DefaultProbeTimeout.Milliseconds(), "timeout for one canary session in milliseconds")
+	runs := fs.Int("runs", 0, "number of canary sessions (0 runs until interrupted)")
+	window := fs.Int("window", 60, "rolling availability window in samples")
+	reportPath := fs.String("report", filepath.Join(".codex", "ops", "synthetic-availability.json"), "path to write rolling availability json")
+	trendPath := fs.String("trend-store", filepath.Join(".codex", "ops", "slo-trend.jsonl"), "SLO trend store each canary result is appended to")
+	artifactsDir := fs.String("artifacts-dir", filepath.Join(".codex", "synthetic"), "directory for loopback canary session artifacts")
+
+	if err := fs.Parse(args); err != nil {
+		return exitcode.Usage(err)
+	}
+
+	cfg := synthetic.Config{
+		Mode:     synthetic.Mode(strings.TrimSpace(*mode)),
+		Target:   strings.TrimSpace(*target),
+		Interval: time.Duration(*intervalMS) * time.Millisecond,
+		Runs:     *runs,
+		Window:   *window,
+	}
+	if err := cfg.Validate(); err != nil {
+		return exitcode.Usage(fmt.Errorf("synthetic-monitor: %w", err))
+	}
+	trend, err := ops.NewSLOTrendStore(*trendPath)
+	if err != nil {
+		return exitcode.Usage(fmt.Errorf("synthetic-monitor: %w", err))
+	}
+	probe := synthetic.SessionProbe{Endpoint: cfg.Target, Timeout: time.Duration(*timeoutMS) * time.Millisecond}
+	if cfg.Mode == synthetic.ModeLoopback {
+		endpoint, stopTransport, err := startLoopbackWebSocket(*artifactsDir, now)
+		if err != nil {
+			return fmt.Errorf("synthetic-monitor: %w", err)
+		}
+		defer stopTransport()
+		probe.Endpoint = endpoint
+	}
+	monitor, err := synthetic.NewMonitor(cfg, probe, clock.WithNow(now))
+	if err != nil {
+		return exitcode.Usage(fmt.Errorf("synthetic-monitor: %w", err))
+	}
+	monitor.Record = func(result synthetic.ProbeResult) error {
+		return trend.Append(ops.SLOTrendPoint{
+			Series:    synthetic.TrendSeries,
+			AtMS:      result.StartedMS,
+			Good:      result.Success,
+			LatencyMS: result.LatencyMS,
+			Target:    cfg.Target,
+			Reason:    result.Reason,
+		})
+	}
+	var budget *ops.ErrorBudgetReport
+	monitor.Publish = func(availability synthetic.Availability) error {
+		policy := ops.DefaultErrorBudgetPolicy()
+		nowMS := now().UnixMilli()
+		points, err := trend.Points(synthetic.TrendSeries, nowMS-policy.BudgetWindowMS)
+		if err != nil {
+			return err
+		}
+		report, err := ops.EvaluateErrorBudget(ops.SLOEventsFromTrend(points), policy, nowMS)
+		if err != nil {
+			return err
+		}
+		ops.EmitBurnRateAlerts(report, synthetic.TrendSeries)
+		budget = &report
+		return writeJSONArtifact(*reportPath, syntheticMonitorArtifact{Availability: availability, TrendStorePath: trend.Path(), ErrorBudget: budget})
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	availability, err := monitor.Run(ctx)
+	if err != nil {
+		return err
+	}
+	remaining := 1.0
+	if budget != nil {
+		remaining = budget.BudgetRemainingRatio
+	}
+	_, _ = fmt.Fprintf(stdout, "rspp-runtime synthetic-monitor: report=%s trend=%s runs=%d availability=%.4f p95_ms=%d budget_remaining=%.4f\n", *reportPath, trend.Path(), availability.TotalRuns, availability.Availability, availability.LatencyP95MS, remaining)
+	return nil
+}
+
+// runBatch processes pre-recorded WAV files through the pipeline turn path with the sandbox
+// providers and writes the merged baseline plus a batch report into the output dir.
+func runBatch(args []string, stdout io.Writer, now func() time.Time) error {
+	fs := flag.NewFlagSet("batch", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	jobID := fs.String("job", "", "batch job id (names the session ids and job artifacts)")
+	outputDir := fs.String("out", filepath.Join(".codex", "batch"), "directory for session, baseline, and report artifacts")
+	tenantID := fs.String("tenant", "", "tenant id recorded on batch sessions")
+	pipelineVersion := fs.String("pipeline-version", "", "pipeline version recorded on batch turns")
+	maxCaptureMS := fs.Int64("max-capture-ms", demo.DefaultMaxCaptureMS, "split recordings into turns of at most this many milliseconds")
+
+	if err := fs.Parse(args); err != nil {
+		return exitcode.Usage(err)
+	}
+	if strings.TrimSpace(*jobID) == "" || fs.NArg() == 0 {
+		return exitcode.Usagef("batch requires -job and at least one audio file")
+	}
+
+	report, err := batch.Run(batch.Job{
+		JobID:           strings.TrimSpace(*jobID),
+		Inputs:          fs.Args(),
+		ArtifactsDir:    *outputDir,
+		TenantID:        strings.TrimSpace(*tenantID),
+		PipelineVersion: strings.TrimSpace(*pipelineVersion),
+		MaxCaptureMS:    *maxCaptureMS,
+		Providers:       demo.SandboxProviders(),
+		Clock:           now,
+	})
+	if err != nil {
+		return fmt.Errorf("batch: %w", err)
+	}
+	_, _ = fmt.Fprintf(stdout, "rspp-runtime batch: job=%s inputs=%d failed=%d turns=%d baseline=%s\n", report.JobID, len(report.Inputs), report.FailedInputs, report.Turns, report.BaselinePath)
+	if report.FailedInputs > 0 {
+		return exitcode.PartialResult(fmt.Errorf("batch: %d of %d inputs failed", report.FailedInputs, len(report.Inputs)))
+	}
+	return nil
+}
+
+func runSnapshotFreshness(args []string, stdout io.Writer, now func() time.Time) error {
+	fs := flag.NewFlagSet("snapshot-freshness", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	defaults := snapshotfreshness.DefaultThresholds()
+	intervalMS := fs.Int64("interval-ms", 5000, "interval between snapshot observations in milliseconds")
+	runs := fs.Int("runs", 0, "number of observations (0 runs until interrupted)")
+	reportPath := fs.String("report", filepath.Join(".codex", "ops", "snapshot-freshness.json"), "path to write snapshot freshness json")
+	maxAgeMS := map[distribution.SnapshotKind]*int64{}
+	fallbacks := map[distribution.SnapshotKind]*string{}
+	for _, kind := range distribution.TrackedSnapshotKinds() {
+		flagPrefix := strings.ReplaceAll(string(kind), "_", "-")
+		maxAgeMS[kind] = fs.Int64(flagPrefix+"-max-age-ms", defaults[kind].MaxAge.Milliseconds(), "max "+string(kind)+" snapshot age in milliseconds")
+		fallbacks[kind] = fs.String(flagPrefix+"-fallback", string(defaults[kind].Fallback), "fallback while the "+string(kind)+" snapshot is stale")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return exitcode.Usage(err)
+	}
+
+	cfg := snapshotfreshness.Config{
+		Interval:   time.Duration(*intervalMS) * time.Millisecond,
+		Runs:       *runs,
+		Thresholds: map[distribution.SnapshotKind]snapshotfreshness.Threshold{},
+	}
+	for _, kind := range distribution.TrackedSnapshotKinds() {
+		cfg.Thresholds[kind] = snapshotfreshness.Threshold{
+			MaxAge:   time.Duration(*maxAgeMS[kind]) * time.Millisecond,
+			Fallback: snapshotfreshness.Fallback(strings.TrimSpace(*fallbacks[kind])),
+		}
+	}
+	observer, err := distribution.NewSnapshotObserverFromEnv()
+	if err != nil {
+		return fmt.Errorf("snapshot-freshness: %w", err)
+	}
+	monitor, err := snapshotfreshness.NewMonitor(cfg, observer, clock.WithNow(now))
+	if err != nil {
+		return exitcode.Usage(fmt.Errorf("snapshot-freshness: %w", err))
+	}
+	monitor.Publish = func(report snapshotfreshness.Report) error {
+		return writeJSONArtifact(*reportPath, report)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	report, err := monitor.Run(ctx)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(stdout, "rspp-runtime snapshot-freshness: report=%s runs=%d degraded=%t evidence=%d\n", *reportPath, report.TotalRuns, report.Degraded, len(report.Evidence))
 	return nil
 }
 
@@ -203,24 +476,24 @@ func runRetentionSweep(args []string, stdout io.Writer, now func() time.Time) er
 	runs := fs.Int("runs", 1, "number of scheduled runs to execute (must be >=1)")
 
 	if err := fs.Parse(args); err != nil {
-		return err
+		return exitcode.Usage(err)
 	}
 	if strings.TrimSpace(*storePath) == "" {
-		return fmt.Errorf("retention-sweep requires -store")
+		return exitcode.Usagef("retention-sweep requires -store")
 	}
 	if strings.TrimSpace(*tenantsRaw) == "" {
-		return fmt.Errorf("retention-sweep requires -tenants")
+		return exitcode.Usagef("retention-sweep requires -tenants")
 	}
 	if *runs < 1 {
-		return fmt.Errorf("retention-sweep requires runs >=1")
+		return exitcode.Usagef("retention-sweep requires runs >=1")
 	}
 	if *intervalMS < 0 {
-		return fmt.Errorf("retention-sweep requires interval-ms >=0")
+		return exitcode.Usagef("retention-sweep requires interval-ms >=0")
 	}
 
 	tenants := parseTenantList(*tenantsRaw)
 	if len(tenants) == 0 {
-		return fmt.Errorf("retention-sweep requires at least one tenant")
+		return exitcode.Usagef("retention-sweep requires at least one tenant")
 	}
 
 	store, err := loadRetentionStore(*storePath)
@@ -228,6 +501,16 @@ func runRetentionSweep(args []string, stdout io.Writer, now func() time.Time) er
 		return err
 	}
 
+	// CP-distributed policies are only enforced while the retention snapshot is fresh; a
+	// stale snapshot falls back to the default retention policy.
+	var freshness *snapshotfreshness.Monitor
+	if strings.TrimSpace(*policyPath) == "" && distributionConfigured() {
+		freshness, err = newSnapshotFreshnessMonitor(now)
+		if err != nil {
+			return fmt.Errorf("retention sweep snapshot freshness: %w", err)
+		}
+	}
+
 	runResults := make([]retentionSweepRunResult, 0, *runs)
 	for runIndex := 1; runIndex <= *runs; runIndex++ {
 		runAtMS := computeRunAtMS(*nowMSFlag, *intervalMS, runIndex, now)
@@ -235,6 +518,13 @@ func runRetentionSweep(args []string, stdout io.Writer, now func() time.Time) er
 		if err != nil {
 			return err
 		}
+		if freshness != nil && policySource != retentionPolicySourceDefaultFallback {
+			// Observation failures are carried in the freshness report.
+			_, _ = freshness.RunOnce()
+			if freshness.UseDefaultRetentionPolicy() {
+				resolver, policySource, fallbackReason = defaultRetentionPolicyResolver(), retentionPolicySourceDefaultFallback, retentionPolicyFallbackReasonCPDistributionStale
+			}
+		}
 		runResult := retentionSweepRunResult{
 			RunIndex:             runIndex,
 			RunAtMS:              runAtMS,
@@ -379,10 +669,15 @@ func writeRetentionStore(path string, records []replay.ReplayArtifactRecord, now
 	return writeJSONArtifact(path, artifact)
 }
 
-func loadRetentionResolver(policyPath string) (replay.RetentionPolicyResolver, retentionPolicySource, string, error) {
-	fallback := replay.BackendRetentionPolicyResolver{
+// defaultRetentionPolicyResolver resolves the built-in default retention policy.
+func defaultRetentionPolicyResolver() replay.RetentionPolicyResolver {
+	return replay.BackendRetentionPolicyResolver{
 		Fallback: replay.StaticRetentionPolicyResolver{},
 	}
+}
+
+func loadRetentionResolver(policyPath string) (replay.RetentionPolicyResolver, retentionPolicySource, string, error) {
+	fallback := defaultRetentionPolicyResolver()
 
 	trimmedPath := strings.TrimSpace(policyPath)
 	if trimmedPath != "" {
@@ -648,21 +943,55 @@ func normalizeRetentionPolicyArtifact(path string, artifact retentionPolicyArtif
 }
 
 func writeJSONArtifact(path string, payload any) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return fmt.Errorf("create artifact directory for %s: %w", path, err)
+	return artifactwriter.WriteJSON(path, payload)
+}
+
+func runCompletion(args []string, stdout io.Writer) error {
+	if len(args) != 1 {
+		return exitcode.Usagef("completion requires bash|zsh|fish")
 	}
-	data, err := json.MarshalIndent(payload, "", "  ")
+	script, err := completion.Script(args[0], runtimeCompletionSpec())
 	if err != nil {
-		return fmt.Errorf("encode artifact %s: %w", path, err)
+		return exitcode.Usage(err)
+	}
+	_, err = io.WriteString(stdout, script)
+	return err
+}
+
+func runtimeCompletionSpec() completion.Spec {
+	freshnessFlags := []string{"interval-ms", "runs", "report"}
+	for _, kind := range distribution.TrackedSnapshotKinds() {
+		flagPrefix := strings.ReplaceAll(string(kind), "_", "-")
+		freshnessFlags = append(freshnessFlags, flagPrefix+"-max-age-ms", flagPrefix+"-fallback")
 	}
-	if err := os.WriteFile(path, data, 0o644); err != nil {
-		return fmt.Errorf("write artifact %s: %w", path, err)
+	return completion.Spec{
+		Program: "rspp-runtime",
+		Commands: []completion.Command{
+			{Name: "bootstrap-providers"},
+			{Name: "retention-sweep", Flags: []string{"store", "report", "policy", "tenants", "now-ms", "interval-ms", "runs"}},
+			{Name: "synthetic-monitor", Flags: []string{"mode", "target", "interval-ms", "timeout-ms", "runs", "window", "report", "trend-store", "artifacts-dir"}},
+			{Name: "snapshot-freshness", Flags: freshnessFlags},
+			{Name: "batch", Flags: []string{"job", "out", "tenant", "pipeline-version", "max-capture-ms"}},
+			{Name: "websocket", Flags: []string{"addr", "artifacts-dir", "pipeline-version", "authority-epoch", "tenant-id", "admin-socket"}},
+			{Name: "webrtc", Flags: []string{"addr", "artifacts-dir", "pipeline-version", "authority-epoch", "tenant-id", "ice-servers", "admin-socket"}},
+			{Name: "twilio", Flags: []string{"addr", "artifacts-dir", "pipeline-version", "authority-epoch", "tenant-id", "admin-socket"}},
+			{Name: "diagnostics", Flags: []string{"socket", "out", "timeout-ms"}},
+			completion.CompletionCommand(),
+			{Name: "help"},
+		},
 	}
-	return nil
 }
 
 func printUsage(w io.Writer) {
 	_, _ = fmt.Fprintln(w, "rspp-runtime usage:")
 	_, _ = fmt.Fprintln(w, "  rspp-runtime [bootstrap-providers]")
+	_, _ = fmt.Fprintln(w, "  rspp-runtime synthetic-monitor [-mode loopback|live] [-target <ws-url>] [-interval-ms <ms>] [-timeout-ms <ms>] [-runs <n>] [-window <n>] [-report <path>] [-trend-store <path>] [-artifacts-dir <dir>]")
+	_, _ = fmt.Fprintln(w, "  rspp-runtime snapshot-freshness [-interval-ms <ms>] [-runs <n>] [-report <path>] [-{routing-view|admission|retention}-max-age-ms <ms>] [-{routing-view|admission|retention}-fallback <fallback>]")
+	_, _ = fmt.Fprintln(w, "  rspp-runtime batch -job <id> [-out <dir>] [-tenant <id>] [-pipeline-version <v>] [-max-capture-ms <ms>] <audio.wav>...")
+	_, _ = fmt.Fprintln(w, "  rspp-runtime websocket [-addr <host:port>] [-artifacts-dir <dir>] [-pipeline-version <v>] [-authority-epoch <n>] [-tenant-id <id>] [-admin-socket <path>]")
+	_, _ = fmt.Fprintln(w, "  rspp-runtime webrtc [-addr <host:port>] [-artifacts-dir <dir>] [-pipeline-version <v>] [-authority-epoch <n>] [-tenant-id <id>] [-ice-servers <url,url>] [-admin-socket <path>]")
+	_, _ = fmt.Fprintln(w, "  rspp-runtime twilio [-addr <host:port>] [-artifacts-dir <dir>] [-pipeline-version <v>] [-authority-epoch <n>] [-tenant-id <id>] [-admin-socket <path>]")
+	_, _ = fmt.Fprintln(w, "  rspp-runtime diagnostics [-socket <path>] [-out <path>] [-timeout-ms <ms>]")
+	_, _ = fmt.Fprintln(w, "  rspp-runtime completion <bash|zsh|fish>")
 	_, _ = fmt.Fprintln(w, "  rspp-runtime retention-sweep -store <path> -tenants <tenant_a,tenant_b> [-policy <path>] [-report <path>] [-now-ms <ms>] [-interval-ms <ms>] [-runs <n>]")
 }
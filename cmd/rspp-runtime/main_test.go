@@ -2,19 +2,58 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient/sessionroutepb"
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sharding"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/decisionindex"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/recording"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/batch"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/demo"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/diagnostics"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/bootstrap"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/invocation"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/standby"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/warmup"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/snapshotfreshness"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/synthetic"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/exitcode"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/ops"
+	llmanthropic "github.com/tiger/realtime-speech-pipeline/providers/llm/anthropic"
+	llmcohere "github.com/tiger/realtime-speech-pipeline/providers/llm/cohere"
+	llmgemini "github.com/tiger/realtime-speech-pipeline/providers/llm/gemini"
+	sttassemblyai "github.com/tiger/realtime-speech-pipeline/providers/stt/assemblyai"
+	sttazure "github.com/tiger/realtime-speech-pipeline/providers/stt/azure"
+	sttdeepgram "github.com/tiger/realtime-speech-pipeline/providers/stt/deepgram"
+	sttgoogle "github.com/tiger/realtime-speech-pipeline/providers/stt/google"
+	ttsazure "github.com/tiger/realtime-speech-pipeline/providers/tts/azure"
+	ttselevenlabs "github.com/tiger/realtime-speech-pipeline/providers/tts/elevenlabs"
+	ttsgoogle "github.com/tiger/realtime-speech-pipeline/providers/tts/google"
+	"github.com/tiger/realtime-speech-pipeline/transports/twilio"
+	"github.com/tiger/realtime-speech-pipeline/transports/webrtc"
+	"github.com/tiger/realtime-speech-pipeline/transports/websocket"
 )
 
 func TestRunRetentionSweepUsesBackendPolicyResolver(t *testing.T) {
@@ -309,6 +348,70 @@ func TestRunRetentionSweepUsesCPDistributionFilePolicySnapshot(t *testing.T) {
 	}
 }
 
+func TestRunRetentionSweepFallsBackToDefaultPolicyOnStaleSnapshot(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+	tmp := t.TempDir()
+	storePath := filepath.Join(tmp, "store.json")
+	reportPath := filepath.Join(tmp, "report.json")
+	distributionPath := filepath.Join(tmp, "cp-distribution.json")
+
+	mustWriteJSON(t, storePath, retentionStoreArtifact{
+		Records: []replay.ReplayArtifactRecord{
+			{
+				ArtifactID:   "metadata-recent",
+				TenantID:     "tenant-a",
+				SessionID:    "session-1",
+				TurnID:       "turn-1",
+				PayloadClass: eventabi.PayloadMetadata,
+				RecordedAtMS: 100,
+			},
+		},
+	})
+	mustWriteJSON(t, distributionPath, map[string]any{
+		"schema_version": "cp-snapshot-distribution/v1",
+		"retention": map[string]any{
+			"published_at_ms": fixedNow()().Add(-48 * time.Hour).UnixMilli(),
+			"tenant_policies": map[string]any{
+				"tenant-a": map[string]any{
+					"tenant_id":              "tenant-a",
+					"default_retention_ms":   10,
+					"pii_retention_limit_ms": 10,
+					"phi_retention_limit_ms": 10,
+					"max_retention_by_class_ms": map[string]any{
+						"audio_raw":       10,
+						"text_raw":        10,
+						"PII":             10,
+						"PHI":             10,
+						"derived_summary": 10,
+						"metadata":        10,
+					},
+				},
+			},
+		},
+	})
+	t.Setenv(distribution.EnvFileAdapterPath, distributionPath)
+	t.Setenv(distribution.EnvHTTPAdapterURL, "")
+	t.Setenv(distribution.EnvHTTPAdapterURLs, "")
+
+	if err := run([]string{
+		"retention-sweep",
+		"-store", storePath,
+		"-report", reportPath,
+		"-tenants", "tenant-a",
+		"-now-ms", "1000",
+	}, &bytes.Buffer{}, &bytes.Buffer{}, fixedNow()); err != nil {
+		t.Fatalf("unexpected retention sweep error: %v", err)
+	}
+
+	if storeArtifact := mustReadStoreArtifact(t, storePath); len(storeArtifact.Records) != 1 {
+		t.Fatalf("expected stale cp policy not enforced, got %+v", storeArtifact.Records)
+	}
+	report := mustReadSweepReport(t, reportPath)
+	if report.PolicySource != string(retentionPolicySourceDefaultFallback) || report.PolicyFallbackReason != retentionPolicyFallbackReasonCPDistributionStale {
+		t.Fatalf("expected stale snapshot default policy fallback, got source=%q fallback=%q", report.PolicySource, report.PolicyFallbackReason)
+	}
+}
+
 func TestRunRetentionSweepUsesCPDistributionHTTPPolicySnapshot(t *testing.T) {
 	tmp := t.TempDir()
 	storePath := filepath.Join(tmp, "store.json")
@@ -401,6 +504,31 @@ func TestRunBootstrapProvidersWithTelemetryDisabled(t *testing.T) {
 	}
 }
 
+func TestRunCompletionPrintsShellScripts(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+
+	tests := []struct {
+		shell string
+		want  string
+	}{
+		{shell: "bash", want: "complete -F _rspp_runtime rspp-runtime"},
+		{shell: "zsh", want: "-routing-view-max-age-ms"},
+		{shell: "fish", want: `"__fish_seen_subcommand_from batch" -o max-capture-ms`},
+	}
+	for _, tc := range tests {
+		var stdout bytes.Buffer
+		if err := run([]string{"completion", tc.shell}, &stdout, &bytes.Buffer{}, fixedNow()); err != nil {
+			t.Fatalf("%s: unexpected completion error: %v", tc.shell, err)
+		}
+		if !strings.Contains(stdout.String(), tc.want) {
+			t.Fatalf("%s: expected script to contain %q, got:\n%s", tc.shell, tc.want, stdout.String())
+		}
+	}
+	if err := run([]string{"completion", "tcsh"}, &bytes.Buffer{}, &bytes.Buffer{}, fixedNow()); err == nil {
+		t.Fatalf("expected unsupported shell error")
+	}
+}
+
 func TestRunRetentionSweepCPDistributionOutageRecoveryAcrossRuns(t *testing.T) {
 	tmp := t.TempDir()
 	storePath := filepath.Join(tmp, "store.json")
@@ -703,3 +831,879 @@ func containsArtifact(records []replay.ReplayArtifactRecord, artifactID string)
 	}
 	return false
 }
+
+func TestRunSyntheticMonitorLoopbackWritesAvailability(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+	tmp := t.TempDir()
+	reportPath := filepath.Join(tmp, "synthetic.json")
+	trendPath := filepath.Join(tmp, "slo-trend.jsonl")
+	artifactsDir := filepath.Join(tmp, "sessions")
+
+	var stdout bytes.Buffer
+	if err := run([]string{
+		"synthetic-monitor",
+		"-mode", "loopback",
+		"-interval-ms", "1",
+		"-runs", "3",
+		"-window", "2",
+		"-report", reportPath,
+		"-trend-store", trendPath,
+		"-artifacts-dir", artifactsDir,
+	}, &stdout, &bytes.Buffer{}, fixedNow()); err != nil {
+		t.Fatalf("unexpected synthetic monitor error: %v", err)
+	}
+
+	raw, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("unexpected availability read error: %v", err)
+	}
+	var artifact syntheticMonitorArtifact
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		t.Fatalf("unexpected availability decode error: %v", err)
+	}
+	availability := artifact.Availability
+	if availability.TotalRuns != 3 || len(availability.Samples) != 2 || availability.Availability != 1 {
+		t.Fatalf("expected 3 runs with full availability over a 2-sample window, got %+v", availability)
+	}
+	if artifact.ErrorBudget == nil || artifact.ErrorBudget.TotalEvents != 3 || artifact.ErrorBudget.BadEvents != 0 || artifact.TrendStorePath != trendPath {
+		t.Fatalf("expected the error budget over 3 trend store events, got %+v", artifact)
+	}
+	// Each canary ran a real transport session that wrote its baseline artifact.
+	for _, sample := range availability.Samples {
+		if _, err := os.Stat(filepath.Join(artifactsDir, sample.SessionID+"-baseline.json")); err != nil {
+			t.Fatalf("expected baseline artifact for canary session %q: %v", sample.SessionID, err)
+		}
+	}
+	trend, _ := ops.NewSLOTrendStore(trendPath)
+	if points, err := trend.Points(synthetic.TrendSeries, 0); err != nil || len(points) != 3 || !points[2].Good {
+		t.Fatalf("expected 3 good canary points in the trend store, got %+v err=%v", points, err)
+	}
+}
+
+func TestRunBatchWritesBaselineForRecordings(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+	tmp := t.TempDir()
+	audioPath := filepath.Join(tmp, "call.wav")
+	pcm := make([]int16, 8000)
+	for idx := range pcm {
+		pcm[idx] = 3000
+	}
+	var wav bytes.Buffer
+	if err := (recording.WAVEncoder{}).Encode(&wav, pcm, 16000, 1); err != nil {
+		t.Fatalf("unexpected wav encode error: %v", err)
+	}
+	if err := os.WriteFile(audioPath, wav.Bytes(), 0o644); err != nil {
+		t.Fatalf("unexpected wav write error: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	outputDir := filepath.Join(tmp, "batch")
+	if err := run([]string{"batch", "-job", "offline", "-out", outputDir, audioPath}, &stdout, &bytes.Buffer{}, fixedNow()); err != nil {
+		t.Fatalf("unexpected batch error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "turns=1") {
+		t.Fatalf("expected one batch turn, got %q", stdout.String())
+	}
+	baseline, err := timeline.ReadBaselineArtifact(filepath.Join(outputDir, batch.BaselineFileName("offline")))
+	if err != nil || len(baseline.Entries) != 1 {
+		t.Fatalf("expected merged batch baseline, got %+v err=%v", baseline, err)
+	}
+
+	err = run([]string{"batch", "-job", "partial", "-out", outputDir, audioPath, filepath.Join(tmp, "absent.wav")}, &bytes.Buffer{}, &bytes.Buffer{}, fixedNow())
+	if exitcode.For(err) != exitcode.Partial {
+		t.Fatalf("expected a batch with a failed input to exit partial, got %v", err)
+	}
+	err = run([]string{"batch", "-out", outputDir, audioPath}, &bytes.Buffer{}, &bytes.Buffer{}, fixedNow())
+	if exitcode.For(err) != exitcode.UsageError {
+		t.Fatalf("expected batch without -job to be a usage error, got %v", err)
+	}
+}
+
+func TestWebSocketHandlerServesClientAndRequiresPipelineVersion(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+
+	handler, err := newWebSocketHandler(websocket.Config{
+		PipelineVersion: defaultWebSocketPipelineVersion,
+		NewPipeline:     func(string, string) (websocket.Pipeline, error) { return nil, errors.New("unused") },
+	})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "/ws") {
+		t.Fatalf("expected browser client at /, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ws", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected plain GET /ws to require an upgrade, got %d", rec.Code)
+	}
+
+	if err := run([]string{"websocket", "-pipeline-version", " "}, &bytes.Buffer{}, &bytes.Buffer{}, fixedNow()); err == nil {
+		t.Fatalf("expected websocket without a pipeline version to fail")
+	}
+}
+
+func TestWebRTCHandlerServesOfferEndpointAndRequiresPipelineVersion(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+
+	handler, err := newWebRTCHandler(webrtc.Config{
+		PipelineVersion: defaultWebRTCPipelineVersion,
+		NewPipeline:     func(string, string) (websocket.Pipeline, error) { return nil, errors.New("unused") },
+	})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/offer", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected GET /offer to require POST, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/offer", strings.NewReader("{")))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a malformed offer to be rejected, got %d", rec.Code)
+	}
+	if servers := parseICEServers(" stun:a:3478, ,turn:b:3478 "); len(servers) != 2 || servers[0] != "stun:a:3478" || servers[1] != "turn:b:3478" {
+		t.Fatalf("unexpected ice servers: %v", servers)
+	}
+
+	if err := run([]string{"webrtc", "-pipeline-version", " "}, &bytes.Buffer{}, &bytes.Buffer{}, fixedNow()); err == nil {
+		t.Fatalf("expected webrtc without a pipeline version to fail")
+	}
+}
+
+func TestTwilioHandlerServesMediaEndpointAndRequiresPipelineVersion(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+
+	handler, err := newTwilioHandler(twilio.Config{
+		PipelineVersion: defaultTwilioPipelineVersion,
+		NewPipeline:     func(string, string) (websocket.Pipeline, error) { return nil, errors.New("unused") },
+	})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/media", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a plain GET /media to require a websocket upgrade, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/offer", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected only /media to be served, got %d", rec.Code)
+	}
+
+	if err := run([]string{"twilio", "-pipeline-version", " "}, &bytes.Buffer{}, &bytes.Buffer{}, fixedNow()); err == nil {
+		t.Fatalf("expected twilio without a pipeline version to fail")
+	}
+}
+
+func TestResolveTenantSLATierFromDistribution(t *testing.T) {
+	distributionPath := filepath.Join(t.TempDir(), "distribution.json")
+	if err := os.WriteFile(distributionPath, []byte(`{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "sla": {"tenant_tiers": {"tenant-a": {"tier": "gold", "max_first_output_latency_ms": 800, "min_availability": 0.999}}}
+}`), 0o600); err != nil {
+		t.Fatalf("write distribution artifact: %v", err)
+	}
+	t.Setenv(distribution.EnvFileAdapterPath, "")
+	t.Setenv(distribution.EnvHTTPAdapterURL, "")
+	t.Setenv(distribution.EnvHTTPAdapterURLs, "")
+	if tier, err := resolveTenantSLATier("tenant-a"); err != nil || tier != nil {
+		t.Fatalf("expected no tier without a configured distribution, got %+v err=%v", tier, err)
+	}
+
+	t.Setenv(distribution.EnvFileAdapterPath, distributionPath)
+	tier, err := resolveTenantSLATier("tenant-a")
+	if err != nil || tier == nil || tier.Tier != "gold" || tier.MaxFirstOutputLatencyMS != 800 {
+		t.Fatalf("expected tenant-a gold tier, got %+v err=%v", tier, err)
+	}
+	if tier, err := resolveTenantSLATier("tenant-b"); err != nil || tier != nil {
+		t.Fatalf("expected no tier for an undeclared tenant, got %+v err=%v", tier, err)
+	}
+	if tier, err := resolveTenantSLATier(""); err != nil || tier != nil {
+		t.Fatalf("expected no tier without a tenant, got %+v err=%v", tier, err)
+	}
+}
+
+func TestRunSyntheticMonitorRejectsLiveWithoutTarget(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+
+	err := run([]string{"synthetic-monitor", "-mode", "live", "-runs", "1"}, &bytes.Buffer{}, &bytes.Buffer{}, fixedNow())
+	if exitcode.For(err) != exitcode.UsageError {
+		t.Fatalf("expected live mode without target to be a usage error, got %v", err)
+	}
+}
+
+func TestRunMapsFailureClassesToExitCodes(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+
+	tmp := t.TempDir()
+	reportPath := filepath.Join(tmp, "retention-sweep-report.json")
+	corruptStore := filepath.Join(tmp, "store.json")
+	if err := os.WriteFile(corruptStore, []byte("{not json"), 0o644); err != nil {
+		t.Fatalf("unexpected store write error: %v", err)
+	}
+	tests := []struct {
+		name string
+		args []string
+		want exitcode.Code
+	}{
+		{name: "unknown command", args: []string{"serve-everything"}, want: exitcode.UsageError},
+		{name: "unknown flag", args: []string{"retention-sweep", "-bogus"}, want: exitcode.UsageError},
+		{name: "missing required flag", args: []string{"retention-sweep", "-tenants", "tenant-a", "-report", reportPath}, want: exitcode.UsageError},
+		{name: "unsupported completion shell", args: []string{"completion", "csh"}, want: exitcode.UsageError},
+		{name: "unreadable input", args: []string{"retention-sweep", "-store", corruptStore, "-tenants", "tenant-a", "-report", reportPath}, want: exitcode.InfrastructureError},
+	}
+	for _, tc := range tests {
+		err := run(tc.args, &bytes.Buffer{}, &bytes.Buffer{}, fixedNow())
+		if got := exitcode.For(err); got != tc.want {
+			t.Fatalf("%s: expected exit code %s, got %s (%v)", tc.name, tc.want, got, err)
+		}
+	}
+}
+
+func TestRunSnapshotFreshnessReportsAgedSnapshots(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+	publishedAtMS := fixedNow()().Add(-time.Minute).UnixMilli()
+	distributionPath := filepath.Join(t.TempDir(), "cp-distribution.json")
+	artifact := map[string]any{
+		"schema_version":  "cp-snapshot-distribution/v1",
+		"published_at_ms": publishedAtMS,
+		"retention":       map[string]any{"published_at_ms": fixedNow()().UnixMilli()},
+	}
+	raw, err := json.Marshal(artifact)
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	if err := os.WriteFile(distributionPath, raw, 0o644); err != nil {
+		t.Fatalf("unexpected distribution write error: %v", err)
+	}
+	t.Setenv(distribution.EnvFileAdapterPath, distributionPath)
+	t.Setenv(distribution.EnvHTTPAdapterURL, "")
+	t.Setenv(distribution.EnvHTTPAdapterURLs, "")
+
+	reportPath := filepath.Join(t.TempDir(), "snapshot-freshness.json")
+	var stdout bytes.Buffer
+	if err := run([]string{
+		"snapshot-freshness",
+		"-interval-ms", "1",
+		"-runs", "1",
+		"-admission-fallback", "reject_turns",
+		"-report", reportPath,
+	}, &stdout, &bytes.Buffer{}, fixedNow()); err != nil {
+		t.Fatalf("unexpected snapshot freshness error: %v", err)
+	}
+
+	raw, err = os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("unexpected report read error: %v", err)
+	}
+	var report snapshotfreshness.Report
+	if err := json.Unmarshal(raw, &report); err != nil {
+		t.Fatalf("unexpected report decode error: %v", err)
+	}
+	if !report.Degraded || report.TotalRuns != 1 || len(report.Evidence) != 2 {
+		t.Fatalf("expected routing and admission staleness evidence, got %+v", report)
+	}
+	for _, status := range report.Snapshots {
+		if status.Kind == distribution.SnapshotAdmission && status.Fallback != snapshotfreshness.FallbackRejectTurns {
+			t.Fatalf("expected admission reject fallback, got %+v", status)
+		}
+		if status.Kind == distribution.SnapshotRetention && status.Stale {
+			t.Fatalf("expected fresh retention snapshot, got %+v", status)
+		}
+	}
+}
+
+func TestRunDiagnosticsDumpsAdminSocketSnapshot(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+	t.Setenv("RSPP_PROVIDER_WARMUP", "")
+	t.Setenv(distribution.EnvFileAdapterPath, "")
+
+	dir := t.TempDir()
+	socketPath := filepath.Join(dir, "admin.sock")
+	serving, err := newServingRuntime("", "", dir, fixedNow())
+	if err != nil {
+		t.Fatalf("unexpected serving runtime error: %v", err)
+	}
+	defer serving.close()
+	sessions := serving.sessions
+	stopAdmin, err := startAdminSocket(socketPath, serving)
+	if err != nil {
+		t.Fatalf("unexpected admin socket error: %v", err)
+	}
+	defer stopAdmin()
+	pipeline, err := serving.newPipeline("sess-diag-1", "", 0)
+	if err != nil {
+		t.Fatalf("unexpected pipeline error: %v", err)
+	}
+	if err := pipeline.StartCapture(); err != nil {
+		t.Fatalf("unexpected capture error: %v", err)
+	}
+	if _, err := pipeline.EndCapture(); err != nil {
+		t.Fatalf("unexpected turn error: %v", err)
+	}
+	if statuses := serving.healthScorer.Statuses(); len(statuses) == 0 {
+		t.Fatalf("expected the turn's provider attempts scored by the shared health scorer")
+	}
+	if got := serving.preferredProvider(contracts.ModalityTTS); got != demo.SandboxTTSProviderID {
+		t.Fatalf("expected turn plans to prefer the catalog's first tts provider, got %q", got)
+	}
+
+	outputPath := filepath.Join(dir, "runtime-diagnostics.json")
+	var stdout bytes.Buffer
+	if err := run([]string{"diagnostics", "-socket", socketPath, "-out", outputPath}, &stdout, &bytes.Buffer{}, fixedNow()); err != nil {
+		t.Fatalf("unexpected diagnostics error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "active_sessions=1") {
+		t.Fatalf("unexpected diagnostics output %q", stdout.String())
+	}
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("read diagnostics artifact: %v", err)
+	}
+	var snapshot diagnostics.Snapshot
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		t.Fatalf("decode diagnostics artifact: %v", err)
+	}
+	if snapshot.SchemaVersion != diagnostics.SchemaVersion || len(snapshot.Sessions.Sessions) != 1 || snapshot.Sessions.Sessions[0].SessionID != "sess-diag-1" {
+		t.Fatalf("unexpected diagnostics snapshot %+v", snapshot)
+	}
+	if snapshot.Recorder.Baseline.Capacity == 0 {
+		t.Fatalf("expected recorder occupancy of the live session, got %+v", snapshot.Recorder)
+	}
+
+	decisions, err := diagnostics.FetchDecisions(socketPath, time.Second, decisionindex.Query{SessionID: "sess-diag-1"})
+	if err != nil {
+		t.Fatalf("unexpected decision query error: %v", err)
+	}
+	if len(decisions.Decisions) == 0 || decisions.Decisions[0].SessionID != "sess-diag-1" {
+		t.Fatalf("expected the live session's turn decisions on the admin socket, got %+v", decisions)
+	}
+
+	if err := pipeline.Close(); err != nil {
+		t.Fatalf("unexpected pipeline close error: %v", err)
+	}
+	if got := sessions.Statuses(); len(got) != 0 {
+		t.Fatalf("expected closed session untracked, got %+v", got)
+	}
+	if err := run([]string{"diagnostics", "-socket", filepath.Join(dir, "absent.sock"), "-out", outputPath}, &bytes.Buffer{}, &bytes.Buffer{}, fixedNow()); err == nil {
+		t.Fatalf("expected diagnostics without a running instance to fail")
+	}
+}
+
+func TestServingRuntimeCancelTurnsCoversOpenSessions(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+	t.Setenv("RSPP_PROVIDER_WARMUP", "")
+	t.Setenv(distribution.EnvFileAdapterPath, "")
+
+	serving, err := newServingRuntime("", "", t.TempDir(), fixedNow())
+	if err != nil {
+		t.Fatalf("unexpected serving runtime error: %v", err)
+	}
+	defer serving.close()
+	pipeline, err := serving.newPipeline("sess-cancel-1", "", 0)
+	if err != nil {
+		t.Fatalf("unexpected pipeline error: %v", err)
+	}
+	if _, ok := serving.live["sess-cancel-1"]; !ok {
+		t.Fatalf("expected the open session registered for turn cancels")
+	}
+	if cancelled := serving.cancelTurns(); len(cancelled) != 0 {
+		t.Fatalf("expected no turn cancelled without one executing, got %v", cancelled)
+	}
+	if err := pipeline.Close(); err != nil {
+		t.Fatalf("unexpected pipeline close error: %v", err)
+	}
+	if len(serving.live) != 0 {
+		t.Fatalf("expected the closed session dropped from turn cancels, got %v", serving.live)
+	}
+}
+
+func TestServingRuntimeWarmsProvidersPerSession(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+	t.Setenv(distribution.EnvFileAdapterPath, "")
+	t.Setenv("RSPP_PROVIDER_WARMUP", "on")
+	dir := t.TempDir()
+	writeWarmableCatalog(t, dir)
+
+	serving, err := newServingRuntime("", "", dir, fixedNow())
+	if err != nil {
+		t.Fatalf("unexpected serving runtime error: %v", err)
+	}
+	defer serving.close()
+	if serving.warmup == nil || serving.turnStart == nil {
+		t.Fatalf("expected warm-up tracker and turn-start resolver with warm-up on")
+	}
+
+	pipeline, err := serving.newPipeline("sess-warm-1", "", 0)
+	if err != nil {
+		t.Fatalf("unexpected pipeline error: %v", err)
+	}
+	defer pipeline.Close()
+	var sessionWarmed bool
+	for _, status := range serving.warmup.WarmupStatuses() {
+		if status.ProviderID == llmanthropic.ProviderID && status.Phase == string(warmup.PhaseSessionStart) && status.Warmed {
+			sessionWarmed = true
+		}
+	}
+	if !sessionWarmed {
+		t.Fatalf("expected a session-start warm-up of the catalog llm, got %+v", serving.warmup.WarmupStatuses())
+	}
+	for _, status := range serving.warmup.WarmupStatuses() {
+		if _, ok := serving.sessionProviders().Catalog.Adapter(contracts.Modality(status.Modality), status.ProviderID); !ok {
+			t.Fatalf("expected warm-up limited to the providers sessions invoke, got %+v", status)
+		}
+	}
+
+	serving.close()
+	serving.close()
+}
+
+func TestServingRuntimeMergesClosedSessionBaselines(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+	t.Setenv(distribution.EnvFileAdapterPath, "")
+	t.Setenv("RSPP_PROVIDER_WARMUP", "")
+	dir := t.TempDir()
+
+	serving, err := newServingRuntime("", "pipeline-merged", dir, fixedNow())
+	if err != nil {
+		t.Fatalf("unexpected serving runtime error: %v", err)
+	}
+	defer serving.close()
+	speech := make([]int16, 1600)
+	for i := range speech {
+		speech[i] = int16(4000 * (1 - 2*(i%2)))
+	}
+	for _, sessionID := range []string{"sess-merge-1", "sess-merge-2", "sess-merge-idle"} {
+		pipeline, err := serving.newPipeline(sessionID, "", 0)
+		if err != nil {
+			t.Fatalf("%s: unexpected pipeline error: %v", sessionID, err)
+		}
+		if sessionID != "sess-merge-idle" {
+			if err := pipeline.StartCapture(); err != nil {
+				t.Fatalf("%s: unexpected capture error: %v", sessionID, err)
+			}
+			if _, err := pipeline.AppendAudio(speech); err != nil {
+				t.Fatalf("%s: unexpected audio error: %v", sessionID, err)
+			}
+			turn, err := pipeline.EndCapture()
+			if err != nil || turn == nil {
+				t.Fatalf("%s: expected a turn, got %+v err=%v", sessionID, turn, err)
+			}
+			recorder, ok := pipeline.(websocket.PlaybackRecorder)
+			if !ok {
+				t.Fatalf("%s: expected the session pipeline to record playback", sessionID)
+			}
+			if err := recorder.RecordReplyEgress(turn.TurnID); err != nil {
+				t.Fatalf("%s: unexpected egress error: %v", sessionID, err)
+			}
+		}
+		if err := pipeline.Close(); err != nil {
+			t.Fatalf("%s: unexpected close error: %v", sessionID, err)
+		}
+	}
+
+	baseline, err := timeline.ReadBaselineArtifact(filepath.Join(dir, runtimeBaselineFileName))
+	if err != nil {
+		t.Fatalf("expected a merged runtime baseline, got %v", err)
+	}
+	if len(baseline.Entries) != 2 || baseline.Entries[0].SessionID != "sess-merge-1" || baseline.Entries[1].SessionID != "sess-merge-2" {
+		t.Fatalf("expected both turn-completing sessions merged in close order, got %+v", baseline.Entries)
+	}
+}
+
+func TestServingRuntimeRoutesSessionsThroughRolloutPool(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+	t.Setenv(distribution.EnvFileAdapterPath, "")
+	t.Setenv("RSPP_PROVIDER_WARMUP", "")
+
+	var reports []controlplaneclient.ReportPoolSessionsRequest
+	controlPlane := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case controlplaneclient.PathRouteRolloutSession:
+			var req controlplaneclient.RouteRolloutSessionRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			pool := controlplaneclient.RolloutPool{PoolID: "rt-green", Color: "green", PipelineVersion: "pipeline-v2", Weight: 50}
+			if req.SessionID == "sess-blue" {
+				pool = controlplaneclient.RolloutPool{PoolID: "rt-blue", Color: "blue", PipelineVersion: "pipeline-v1", Weight: 50}
+			}
+			_ = json.NewEncoder(w).Encode(pool)
+		case controlplaneclient.PathReportPoolSessions:
+			var req controlplaneclient.ReportPoolSessionsRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			reports = append(reports, req)
+			_ = json.NewEncoder(w).Encode(controlplaneclient.RolloutStatus{Environment: req.Environment})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer controlPlane.Close()
+
+	t.Setenv(envRuntimePoolID, "rt-green")
+	t.Setenv(distribution.EnvEnvironment, "")
+	if _, err := newServingRuntime("", "pipeline-v1", t.TempDir(), fixedNow()); err == nil || !strings.Contains(err.Error(), distribution.EnvEnvironment) {
+		t.Fatalf("expected a pool runtime without an environment to fail, got %v", err)
+	}
+	t.Setenv(distribution.EnvEnvironment, "prod")
+	t.Setenv(controlplaneclient.EnvURL, controlPlane.URL)
+	serving, err := newServingRuntime("", "pipeline-v1", t.TempDir(), fixedNow())
+	if err != nil {
+		t.Fatalf("unexpected serving runtime error: %v", err)
+	}
+	defer serving.close()
+
+	if _, err := serving.newPipeline("sess-blue", "", 0); err == nil || !strings.Contains(err.Error(), "rollout pool rt-blue") {
+		t.Fatalf("expected a session routed to the blue pool to be refused, got %v", err)
+	}
+	pipeline, err := serving.newPipeline("sess-green", "", 0)
+	if err != nil {
+		t.Fatalf("unexpected pipeline error: %v", err)
+	}
+	if err := pipeline.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if len(reports) != 1 || reports[0].Environment != "prod" || reports[0].Color != "green" || reports[0].ActiveSessions != 0 {
+		t.Fatalf("expected the closed session reported for the green pool, got %+v", reports)
+	}
+}
+
+// routeSessionsOverGRPC is a control plane's session-route gRPC service that pins one session
+// to a canary pipeline version.
+type routeSessionsOverGRPC struct {
+	sessionroutepb.UnimplementedSessionRouteServiceServer
+}
+
+func (routeSessionsOverGRPC) ResolveSessionRoute(_ context.Context, req *sessionroutepb.ResolveSessionRouteRequest) (*sessionroutepb.SessionRoute, error) {
+	if req.GetEnvironment() != "prod" {
+		return nil, status.Errorf(codes.InvalidArgument, "unexpected environment %q", req.GetEnvironment())
+	}
+	if req.GetSessionId() == "sess-missing" {
+		return nil, status.Error(codes.NotFound, "no active pipeline version")
+	}
+	version := "pipeline-v1"
+	if req.GetSessionId() == "sess-canary" {
+		version = "pipeline-v2"
+	}
+	return &sessionroutepb.SessionRoute{TenantId: req.GetTenantId(), SessionId: req.GetSessionId(), PipelineVersion: version}, nil
+}
+
+func TestServingRuntimeResolvesSessionRoutesOverGRPC(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+	t.Setenv(distribution.EnvFileAdapterPath, "")
+	t.Setenv("RSPP_PROVIDER_WARMUP", "")
+	t.Setenv(envRuntimePoolID, "")
+	t.Setenv(sharding.EnvWorkerID, "")
+	t.Setenv(distribution.EnvEnvironment, "prod")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %v", err)
+	}
+	controlPlane := grpc.NewServer()
+	sessionroutepb.RegisterSessionRouteServiceServer(controlPlane, routeSessionsOverGRPC{})
+	go func() { _ = controlPlane.Serve(listener) }()
+	defer controlPlane.Stop()
+
+	t.Setenv(envSessionRoutes, "grpc")
+	t.Setenv(controlplaneclient.EnvURL, "http://"+listener.Addr().String())
+	if _, err := newServingRuntime("", "pipeline-v1", t.TempDir(), fixedNow()); err == nil || !strings.Contains(err.Error(), "requires a tenant") {
+		t.Fatalf("expected session routes without a tenant to fail, got %v", err)
+	}
+	serving, err := newServingRuntime("tenant-a", "pipeline-v1", t.TempDir(), fixedNow())
+	if err != nil {
+		t.Fatalf("unexpected serving runtime error: %v", err)
+	}
+	defer serving.close()
+
+	if _, err := serving.newPipeline("sess-missing", "", 0); !errors.Is(err, controlplaneclient.ErrNotFound) {
+		t.Fatalf("expected an unroutable session to be refused, got %v", err)
+	}
+	pipeline, err := serving.newPipeline("sess-canary", "", 0)
+	if err != nil {
+		t.Fatalf("unexpected pipeline error: %v", err)
+	}
+	defer pipeline.Close()
+	serving.liveMu.Lock()
+	version := serving.live["sess-canary"].Status().PipelineVersion
+	serving.liveMu.Unlock()
+	if version != "pipeline-v2" {
+		t.Fatalf("expected the session to run at its routed pipeline version, got %s", version)
+	}
+}
+
+func TestServingRuntimeAdmitsOnlyOwnedShardSessions(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+	t.Setenv("RSPP_PROVIDER_WARMUP", "")
+	t.Setenv(envRuntimePoolID, "")
+	dir := t.TempDir()
+	distributionPath := filepath.Join(dir, "cp-distribution.json")
+	mustWriteJSON(t, distributionPath, map[string]any{
+		"schema_version":  "cp-snapshot-distribution/v1",
+		"published_at_ms": fixedNow()().UnixMilli(),
+	})
+	t.Setenv(distribution.EnvFileAdapterPath, distributionPath)
+	t.Setenv(distribution.EnvHTTPAdapterURL, "")
+	t.Setenv(distribution.EnvHTTPAdapterURLs, "")
+	t.Setenv(sharding.EnvWorkerID, "runtime-a")
+
+	serving, err := newServingRuntime("", "", dir, fixedNow())
+	if err != nil {
+		t.Fatalf("unexpected serving runtime error: %v", err)
+	}
+	defer serving.close()
+	if pipeline, err := serving.newPipeline("sess-unsharded", "", 0); err != nil {
+		t.Fatalf("expected sessions admitted before a shard map is published, got %v", err)
+	} else {
+		_ = pipeline.Close()
+	}
+
+	shardMap, err := distribution.PublishShardMap(distributionPath, []string{"runtime-a", "runtime-b"}, map[string]string{"runtime-a": "wss://runtime-a/ws", "runtime-b": "wss://runtime-b/ws"}, 0)
+	if err != nil {
+		t.Fatalf("unexpected publish error: %v", err)
+	}
+	owned, foreign := "", ""
+	for idx := 0; owned == "" || foreign == ""; idx++ {
+		sessionID := fmt.Sprintf("sess-shard-%d", idx)
+		if owner, _, _ := shardMap.Owner(sessionID); owner == "runtime-a" {
+			owned = sessionID
+		} else {
+			foreign = sessionID
+		}
+	}
+	if _, err := serving.newPipeline(foreign, "", 0); err == nil || !strings.Contains(err.Error(), "reconnect to wss://runtime-b/ws") {
+		t.Fatalf("expected a session owned by runtime-b to be sent to its endpoint, got %v", err)
+	}
+	pipeline, err := serving.newPipeline(owned, "", 0)
+	if err != nil {
+		t.Fatalf("expected the owned session admitted, got %v", err)
+	}
+	_ = pipeline.Close()
+
+	t.Setenv(distribution.EnvFileAdapterPath, "")
+	if _, err := newServingRuntime("", "", dir, fixedNow()); err == nil || !strings.Contains(err.Error(), sharding.EnvWorkerID) {
+		t.Fatalf("expected a worker id without a distribution to fail, got %v", err)
+	}
+}
+
+func TestServingRuntimeGatesTurnsOnStaleSnapshots(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+	t.Setenv("RSPP_PROVIDER_WARMUP", "")
+	dir := t.TempDir()
+	distributionPath := filepath.Join(dir, "cp-distribution.json")
+	mustWriteJSON(t, distributionPath, map[string]any{
+		"schema_version":  "cp-snapshot-distribution/v1",
+		"published_at_ms": fixedNow()().Add(-time.Minute).UnixMilli(),
+	})
+	t.Setenv(distribution.EnvFileAdapterPath, distributionPath)
+	t.Setenv(distribution.EnvHTTPAdapterURL, "")
+	t.Setenv(distribution.EnvHTTPAdapterURLs, "")
+
+	serving, err := newServingRuntime("", "", dir, fixedNow())
+	if err != nil {
+		t.Fatalf("unexpected serving runtime error: %v", err)
+	}
+	defer serving.close()
+	if serving.freshness == nil || !serving.freshness.Report().Degraded {
+		t.Fatalf("expected a degraded freshness monitor in the serving runtime")
+	}
+
+	pipeline, err := serving.newPipeline("sess-stale-1", "", 0)
+	if err != nil {
+		t.Fatalf("unexpected pipeline error: %v", err)
+	}
+	defer pipeline.Close()
+	if err := pipeline.StartCapture(); err != nil {
+		t.Fatalf("unexpected capture error: %v", err)
+	}
+	if _, err := pipeline.AppendAudio(make([]int16, 1600)); err != nil {
+		t.Fatalf("unexpected audio error: %v", err)
+	}
+	if _, err := pipeline.EndCapture(); err == nil || !strings.Contains(err.Error(), "turn not active") {
+		t.Fatalf("expected stale routing snapshot to defer the turn, got %v", err)
+	}
+}
+
+func TestServingRuntimeReloadsProviderCatalog(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+	t.Setenv(distribution.EnvFileAdapterPath, "")
+	t.Setenv("RSPP_PROVIDER_WARMUP", "on")
+	dir := t.TempDir()
+	file, catalogPath := writeWarmableCatalog(t, dir)
+
+	serving, err := newServingRuntime("", "", dir, fixedNow())
+	if err != nil {
+		t.Fatalf("unexpected serving runtime error: %v", err)
+	}
+	defer serving.close()
+	if serving.catalog == nil {
+		t.Fatalf("expected the serving runtime to keep the provider catalog manager")
+	}
+	if changed, err := serving.reloadCatalog(); err != nil || changed {
+		t.Fatalf("expected an unchanged catalog reload to be a no-op, got %t err=%v", changed, err)
+	}
+
+	for i := range file.Providers {
+		if file.Providers[i].ProviderID == sttdeepgram.ProviderID {
+			file.Providers[i].Enabled = false
+		}
+	}
+	if err := writeJSONArtifact(catalogPath, file); err != nil {
+		t.Fatalf("write catalog: %v", err)
+	}
+	if changed, err := serving.reloadCatalog(); err != nil || !changed {
+		t.Fatalf("expected the edited catalog to reload, got %t err=%v", changed, err)
+	}
+	var disabled bool
+	for _, record := range serving.catalog.AuditLog() {
+		if record.Action == bootstrap.CatalogActionDisabled && record.ProviderID == sttdeepgram.ProviderID {
+			disabled = true
+		}
+	}
+	if !disabled {
+		t.Fatalf("expected the disabled provider audited, got %+v", serving.catalog.AuditLog())
+	}
+	statuses := serving.warmup.WarmupStatuses()
+	for _, status := range statuses {
+		if status.ProviderID == sttdeepgram.ProviderID {
+			t.Fatalf("expected the disabled provider dropped from warm-up, got %+v", statuses)
+		}
+	}
+	if len(statuses) == 0 {
+		t.Fatalf("expected the reloaded providers warmed")
+	}
+}
+
+func TestAdminSocketAppliesRunbookRemediations(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+	t.Setenv(distribution.EnvFileAdapterPath, "")
+	t.Setenv("RSPP_PROVIDER_WARMUP", "")
+	dir := t.TempDir()
+	_, catalogPath := writeWarmableCatalog(t, dir)
+
+	serving, err := newServingRuntime("", "", dir, fixedNow())
+	if err != nil {
+		t.Fatalf("unexpected serving runtime error: %v", err)
+	}
+	defer serving.close()
+	socketPath := filepath.Join(dir, "admin.sock")
+	stopAdmin, err := startAdminSocket(socketPath, serving)
+	if err != nil {
+		t.Fatalf("unexpected admin socket error: %v", err)
+	}
+	defer stopAdmin()
+
+	if changed, err := diagnostics.OpenBreaker(socketPath, 2*time.Second, llmgemini.ProviderID); err != nil || !changed {
+		t.Fatalf("expected the breaker opened, got changed=%t err=%v", changed, err)
+	}
+	if state, ok := serving.sessionProviders().CircuitBreaker.Allow(llmgemini.ProviderID); ok || state != invocation.CircuitOpen {
+		t.Fatalf("expected session invocations to skip the opened provider, got %s %t", state, ok)
+	}
+
+	if changed, err := diagnostics.ShiftProviderWeights(socketPath, 2*time.Second, map[string]int{llmcohere.ProviderID: 90, llmanthropic.ProviderID: 10}); err != nil || !changed {
+		t.Fatalf("expected the weight shift applied, got changed=%t err=%v", changed, err)
+	}
+	ids, err := serving.sessionProviders().Catalog.ProviderIDs(contracts.ModalityLLM)
+	if err != nil || ids[0] != llmcohere.ProviderID {
+		t.Fatalf("expected new sessions to prefer the reweighted llm, got %v err=%v", ids, err)
+	}
+	raw, err := os.ReadFile(catalogPath)
+	if err != nil || !strings.Contains(string(raw), `"weight": 90`) {
+		t.Fatalf("expected the shift persisted to the catalog file, got %s err=%v", raw, err)
+	}
+}
+
+func TestServingRuntimeHoldsProviderStandby(t *testing.T) {
+	t.Setenv(telemetry.EnvTelemetryEnabled, "false")
+	t.Setenv(distribution.EnvFileAdapterPath, "")
+	t.Setenv("RSPP_PROVIDER_WARMUP", "")
+	t.Setenv(standby.EnvEnabled, "1")
+	dir := t.TempDir()
+	file, catalogPath := writeWarmableCatalog(t, dir)
+
+	serving, err := newServingRuntime("", "", dir, fixedNow())
+	if err != nil {
+		t.Fatalf("unexpected serving runtime error: %v", err)
+	}
+	defer serving.close()
+	if serving.standby == nil {
+		t.Fatalf("expected a warm-standby manager with warm standby on")
+	}
+	held := map[string]bool{}
+	for _, entry := range serving.standby.Upkeep() {
+		if entry.Refreshes > 0 {
+			held[entry.Modality] = true
+		}
+	}
+	for _, modality := range []contracts.Modality{contracts.ModalityLLM, contracts.ModalityTTS} {
+		if !held[string(modality)] {
+			t.Fatalf("expected a %s standby held at startup, got %+v", modality, held)
+		}
+	}
+
+	for i := range file.Providers {
+		if file.Providers[i].ProviderID == sttgoogle.ProviderID {
+			file.Providers[i].Enabled = false
+		}
+	}
+	if err := writeJSONArtifact(catalogPath, file); err != nil {
+		t.Fatalf("write catalog: %v", err)
+	}
+	if changed, err := serving.reloadCatalog(); err != nil || !changed {
+		t.Fatalf("expected the edited catalog to reload, got %t err=%v", changed, err)
+	}
+	var reheld bool
+	for _, entry := range serving.standby.Upkeep() {
+		if entry.ProviderID == sttgoogle.ProviderID && entry.Refreshes > 0 {
+			t.Fatalf("expected the disabled provider never re-held, got %+v", entry)
+		}
+		if entry.Modality == string(contracts.ModalityLLM) && entry.Refreshes > 0 {
+			reheld = true
+		}
+	}
+	if !reheld {
+		t.Fatalf("expected an llm standby re-held on the reloaded catalog")
+	}
+	serving.close()
+	serving.close()
+}
+
+// writeWarmableCatalog writes a provider catalog file into dir whose providers all warm
+// against a local server and points the runtime at it.
+func writeWarmableCatalog(t *testing.T, dir string) (bootstrap.CatalogFile, string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	file := bootstrap.CatalogFile{SchemaVersion: bootstrap.CatalogFileSchemaVersion}
+	for _, provider := range []struct {
+		id          string
+		modality    contracts.Modality
+		endpointEnv string
+	}{
+		{id: sttdeepgram.ProviderID, modality: contracts.ModalitySTT, endpointEnv: "RSPP_STT_DEEPGRAM_ENDPOINT"},
+		{id: sttgoogle.ProviderID, modality: contracts.ModalitySTT, endpointEnv: "RSPP_STT_GOOGLE_ENDPOINT"},
+		{id: sttassemblyai.ProviderID, modality: contracts.ModalitySTT, endpointEnv: "RSPP_STT_ASSEMBLYAI_ENDPOINT"},
+		{id: sttazure.ProviderID, modality: contracts.ModalitySTT, endpointEnv: "RSPP_STT_AZURE_ENDPOINT"},
+		{id: llmanthropic.ProviderID, modality: contracts.ModalityLLM, endpointEnv: "RSPP_LLM_ANTHROPIC_ENDPOINT"},
+		{id: llmgemini.ProviderID, modality: contracts.ModalityLLM, endpointEnv: "RSPP_LLM_GEMINI_ENDPOINT"},
+		{id: llmcohere.ProviderID, modality: contracts.ModalityLLM, endpointEnv: "RSPP_LLM_COHERE_ENDPOINT"},
+		{id: ttselevenlabs.ProviderID, modality: contracts.ModalityTTS, endpointEnv: "RSPP_TTS_ELEVENLABS_ENDPOINT"},
+		{id: ttsgoogle.ProviderID, modality: contracts.ModalityTTS, endpointEnv: "RSPP_TTS_GOOGLE_ENDPOINT"},
+		{id: ttsazure.ProviderID, modality: contracts.ModalityTTS, endpointEnv: "RSPP_TTS_AZURE_ENDPOINT"},
+	} {
+		t.Setenv(provider.endpointEnv, server.URL+"/v1")
+		file.Providers = append(file.Providers, bootstrap.CatalogEntry{ProviderID: provider.id, Modality: provider.modality, Enabled: true})
+	}
+	catalogPath := filepath.Join(dir, "catalog.json")
+	if err := writeJSONArtifact(catalogPath, file); err != nil {
+		t.Fatalf("write catalog: %v", err)
+	}
+	t.Setenv(bootstrap.CatalogFileEnv, catalogPath)
+	return file, catalogPath
+}
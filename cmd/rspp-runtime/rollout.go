@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+)
+
+// envRuntimePoolID names the blue/green pool a serve mode runs in. With it set, every new
+// session is routed through the control-plane API (controlplaneclient.EnvURL) for the
+// RSPP_ENVIRONMENT deployment, and sessions the control plane routes to the other pool are
+// refused.
+const envRuntimePoolID = "RSPP_RUNTIME_POOL_ID"
+
+// poolRouter routes new sessions of one blue/green pool through the control plane and
+// reports the pool's open sessions so a draining pool can be marked drained.
+type poolRouter struct {
+	client      *controlplaneclient.Client
+	environment string
+	poolID      string
+}
+
+// poolRouterFromEnv returns the pool router of a serve mode, or nil when envRuntimePoolID is
+// unset.
+func poolRouterFromEnv() (*poolRouter, error) {
+	poolID := strings.TrimSpace(os.Getenv(envRuntimePoolID))
+	if poolID == "" {
+		return nil, nil
+	}
+	environment := strings.ToLower(strings.TrimSpace(os.Getenv(distribution.EnvEnvironment)))
+	if environment == "" {
+		return nil, fmt.Errorf("%s requires %s", envRuntimePoolID, distribution.EnvEnvironment)
+	}
+	client, err := controlplaneclient.NewFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", envRuntimePoolID, err)
+	}
+	return &poolRouter{client: client, environment: environment, poolID: poolID}, nil
+}
+
+// route returns this runtime's pool when the control plane routes sessionID to it.
+func (p *poolRouter) route(sessionID string) (controlplaneclient.RolloutPool, error) {
+	pool, err := p.client.RouteRolloutSession(context.Background(), controlplaneclient.RouteRolloutSessionRequest{
+		Environment: p.environment,
+		SessionID:   sessionID,
+	})
+	if err != nil {
+		return controlplaneclient.RolloutPool{}, fmt.Errorf("route rollout session: %w", err)
+	}
+	if pool.PoolID != p.poolID {
+		return controlplaneclient.RolloutPool{}, fmt.Errorf("control plane routes the session to rollout pool %s, not this runtime's pool %s", pool.PoolID, p.poolID)
+	}
+	return pool, nil
+}
+
+// reportSessions reports the pool's remaining open sessions.
+func (p *poolRouter) reportSessions(color string, activeSessions int) error {
+	_, err := p.client.ReportPoolSessions(context.Background(), controlplaneclient.ReportPoolSessionsRequest{
+		Environment:    p.environment,
+		Color:          color,
+		ActiveSessions: activeSessions,
+	})
+	if err != nil {
+		return fmt.Errorf("report pool sessions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,476 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/cost"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/decisionindex"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/cancellation"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/demo"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/diagnostics"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/executor"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/nodecache"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/bootstrap"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/healthscore"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/standby"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/warmup"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/snapshotfreshness"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/transport"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/turnarbiter"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
+	"github.com/tiger/realtime-speech-pipeline/transports/websocket"
+)
+
+const (
+	// profileSandbox selects the offline sandbox adapters for serve-mode sessions.
+	profileSandbox = "sandbox"
+	// providerKeepAliveInterval paces keep-alive warm-up passes while a serve mode runs.
+	providerKeepAliveInterval = 30 * time.Second
+	// providerStandbyUpkeepInterval paces warm-standby refreshes, inside the provider HTTP
+	// pool's default 90 s idle timeout.
+	providerStandbyUpkeepInterval = 30 * time.Second
+	// runtimeBaselineFileName names the merged baseline of every session a serve mode closed,
+	// in the artifacts dir; rspp-cli replay and SLO commands load it as a runtime baseline.
+	runtimeBaselineFileName = "runtime-baseline.json"
+	// snapshotFreshnessInterval paces CP snapshot freshness observations while a serve mode runs.
+	snapshotFreshnessInterval = 5 * time.Second
+)
+
+// servingRuntime is the state a transport serve mode shares across its sessions.
+type servingRuntime struct {
+	tenantID        string
+	pipelineVersion string
+	artifactsDir    string
+	sla             *controlplane.SLATier
+	now             func() time.Time
+	sessions        *diagnostics.SessionTracker
+	// nodeCache memoizes deterministic session stages across sessions of this instance.
+	nodeCache *nodecache.Cache[executor.SchedulingDecision]
+	// turnLoad is the flow-control pressure shared by every session's turn plans.
+	turnLoad *demo.TurnLoad
+	// cancelFence carries turn cancels to the provider invocations of every session's plans.
+	cancelFence *cancellation.Fence
+	// healthScorer scores the provider attempts of every session's turn plans and routes plans
+	// allowing provider_switch off clearly unhealthy providers.
+	healthScorer *healthscore.Scorer
+	// live holds the open sessions by id, for cancelTurns.
+	liveMu sync.Mutex
+	live   map[string]*demo.Session
+	// baselineMu serializes closed sessions' appends to the merged runtime baseline.
+	baselineMu sync.Mutex
+	// providers are the runtime providers sessions invoke when no catalog file is configured.
+	providers bootstrap.RuntimeProviders
+	// catalog reloads the declarative provider catalog when a catalog file is configured.
+	catalog *bootstrap.CatalogManager
+	// warmup tracks provider warm-up when RSPP_PROVIDER_WARMUP is on; nil otherwise.
+	warmup *warmup.Tracker
+	// standby holds each modality's secondary provider warm when RSPP_PROVIDER_WARM_STANDBY
+	// is on; nil otherwise.
+	standby   *standby.Manager
+	turnStart turnarbiter.TurnStartBundleResolver
+	// freshness tracks CP snapshot ages when a CP distribution is configured; nil otherwise.
+	freshness *snapshotfreshness.Monitor
+	// decisions indexes turn decisions of every session for admin socket queries.
+	decisions *decisionindex.Index
+	// pool routes new sessions through the control plane when the runtime serves a blue/green
+	// pool; nil otherwise.
+	pool *poolRouter
+	// shards refuses sessions another runtime worker owns when the runtime has a worker id;
+	// nil otherwise.
+	shards *shardGuard
+	// routes resolves each new session's route over the control plane's gRPC service when
+	// envSessionRoutes selects it; nil otherwise.
+	routes *sessionRouter
+	// stops halts background loops in reverse start order on close.
+	stops []func()
+}
+
+// newServingRuntime resolves the tenant's SLA tier and builds the shared session state. With
+// a CP distribution configured it monitors snapshot freshness until close and gates turn-open
+// requests on it; with envRuntimePoolID set it routes new sessions through the control plane,
+// with envSessionRoutes set it resolves each session's route over gRPC, and with a runtime
+// worker id it admits only the sessions the published shard map assigns it.
+// It starts the runtime providers sessions invoke (see startProviders).
+func newServingRuntime(tenantID, pipelineVersion, artifactsDir string, now func() time.Time) (*servingRuntime, error) {
+	tenantID = strings.TrimSpace(tenantID)
+	sla, err := resolveTenantSLATier(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	r := &servingRuntime{
+		tenantID:        tenantID,
+		pipelineVersion: strings.TrimSpace(pipelineVersion),
+		artifactsDir:    artifactsDir,
+		sla:             sla,
+		now:             now,
+		sessions:        diagnostics.NewSessionTracker(),
+		nodeCache:       nodecache.New[executor.SchedulingDecision](0),
+		turnLoad:        &demo.TurnLoad{},
+		cancelFence:     cancellation.NewFence(),
+		healthScorer:    healthscore.NewScorer(healthscore.Config{}),
+		live:            map[string]*demo.Session{},
+		decisions:       decisionindex.New(decisionindex.Config{}),
+	}
+	if r.pool, err = poolRouterFromEnv(); err != nil {
+		return nil, err
+	}
+	if r.shards, err = shardGuardFromEnv(); err != nil {
+		return nil, err
+	}
+	if r.routes, err = sessionRouterFromEnv(tenantID); err != nil {
+		return nil, err
+	}
+	if r.routes != nil {
+		r.stops = append(r.stops, func() { _ = r.routes.client.Close() })
+	}
+	if distributionConfigured() {
+		monitor, err := newSnapshotFreshnessMonitor(now)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot freshness: %w", err)
+		}
+		// Classify once up front so the first turn is gated; observation failures are
+		// carried in the report.
+		_, _ = monitor.RunOnce()
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = monitor.Run(ctx)
+		}()
+		r.freshness = monitor
+		r.stops = append(r.stops, func() {
+			cancel()
+			<-done
+		})
+	}
+	if err := r.startProviders(); err != nil {
+		r.close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// startProviders builds the runtime providers sessions invoke (see buildSessionProviders),
+// holds their failover providers on warm standby with upkeep when RSPP_PROVIDER_WARM_STANDBY
+// is on, and feeds turn-start provider health: warm-up with keep-alive when
+// RSPP_PROVIDER_WARMUP is on, and the declarative catalog, reloaded on SIGHUP, when a catalog
+// file is configured.
+func (r *servingRuntime) startProviders() error {
+	runtimeProviders, catalog, err := buildSessionProviders()
+	if err != nil {
+		return fmt.Errorf("provider bootstrap failed: %w", err)
+	}
+	r.providers = runtimeProviders
+	if standby.EnabledFromEnv() {
+		manager := standby.NewManagerWithClock(runtimeProviders.Catalog, cost.DefaultPriceTable(), clock.WithNow(r.now))
+		log.Printf("rspp-runtime: %s", holdProviderStandby(manager))
+		stop, err := manager.StartUpkeep(providerStandbyUpkeepInterval)
+		if err != nil {
+			return fmt.Errorf("provider standby upkeep failed: %w", err)
+		}
+		r.standby = manager
+		r.stops = append(r.stops, func() {
+			stop()
+			manager.Release()
+		})
+	}
+	if !providerWarmupEnabled() && catalog == nil {
+		return nil
+	}
+	var backends turnarbiter.ControlPlaneBackends
+	if providerWarmupEnabled() {
+		// Warm only the session providers' modalities turns invoke; the catalog reload swaps
+		// the same providers for both.
+		tracker := warmup.NewTrackerWithClock(runtimeProviders.Catalog, clock.WithNow(r.now))
+		tracker.ScopeModalities(demo.TurnModalities()...)
+		if _, err := tracker.WarmRuntime(); err != nil {
+			return fmt.Errorf("provider warmup failed: %w", err)
+		}
+		stop, err := tracker.StartKeepAlive(providerKeepAliveInterval)
+		if err != nil {
+			return fmt.Errorf("provider keepalive failed: %w", err)
+		}
+		r.warmup = tracker
+		r.stops = append(r.stops, stop)
+		backends.ProviderWarmup = tracker
+	}
+	if catalog != nil {
+		r.catalog = catalog
+		backends.ProviderCatalog = catalog
+		r.watchCatalogReload()
+	}
+	r.turnStart = turnarbiter.NewControlPlaneBundleResolverWithBackends(backends)
+	return nil
+}
+
+// reloadCatalog re-reads the provider catalog file, reporting whether it changed, moves warm
+// standbys onto the swapped-in provider set, and warms it.
+func (r *servingRuntime) reloadCatalog() (bool, error) {
+	if r.catalog == nil {
+		return false, fmt.Errorf("no provider catalog file is configured")
+	}
+	changed, err := r.catalog.Reload()
+	if err != nil || !changed {
+		return changed, err
+	}
+	return true, r.followCatalog()
+}
+
+// shiftProviderWeights applies a runbook weight shift to the provider catalog file, reporting
+// whether it changed, and moves warm standbys and warm-up onto the reweighted provider set.
+func (r *servingRuntime) shiftProviderWeights(weights map[string]int) (bool, error) {
+	if r.catalog == nil {
+		return false, fmt.Errorf("no provider catalog file is configured")
+	}
+	changed, err := r.catalog.ShiftWeights(weights)
+	if err != nil || !changed {
+		return changed, err
+	}
+	return true, r.followCatalog()
+}
+
+// followCatalog moves warm standbys onto the catalog's current provider set and warms it.
+func (r *servingRuntime) followCatalog() error {
+	if r.standby != nil {
+		r.standby.UseCatalog(r.catalog.RuntimeProviders().Catalog)
+		log.Printf("rspp-runtime: %s", holdProviderStandby(r.standby))
+	}
+	if r.warmup != nil {
+		r.warmup.UseCatalog(r.catalog.RuntimeProviders().Catalog)
+		if _, err := r.warmup.WarmRuntime(); err != nil {
+			return fmt.Errorf("provider warmup failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// watchCatalogReload reloads the provider catalog on every SIGHUP until close.
+func (r *servingRuntime) watchCatalogReload() {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case <-hangup:
+				changed, err := r.reloadCatalog()
+				if err != nil {
+					log.Printf("rspp-runtime: provider catalog reload: %v", err)
+					continue
+				}
+				log.Printf("rspp-runtime: provider catalog reload changed=%t", changed)
+			}
+		}
+	}()
+	r.stops = append(r.stops, func() {
+		signal.Stop(hangup)
+		close(done)
+		<-stopped
+	})
+}
+
+// close stops the runtime's background loops; it is safe to call more than once.
+func (r *servingRuntime) close() {
+	for i := len(r.stops) - 1; i >= 0; i-- {
+		r.stops[i]()
+	}
+	r.stops = nil
+}
+
+// newSnapshotFreshnessMonitor builds a freshness monitor with default thresholds over the
+// env-configured CP distribution.
+func newSnapshotFreshnessMonitor(now func() time.Time) (*snapshotfreshness.Monitor, error) {
+	observer, err := distribution.NewSnapshotObserverFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return snapshotfreshness.NewMonitor(snapshotfreshness.Config{Interval: snapshotFreshnessInterval}, observer, clock.WithNow(now))
+}
+
+// sessionProviders returns the runtime providers a new session invokes; a catalog file reload
+// swaps them for later sessions.
+func (r *servingRuntime) sessionProviders() bootstrap.RuntimeProviders {
+	if r.catalog != nil {
+		return r.catalog.RuntimeProviders()
+	}
+	return r.providers
+}
+
+// buildSessionProviders builds the providers serve-mode sessions invoke: the declarative
+// catalog when a catalog file is configured, the RSPP_PROVIDER_PROFILE profile when one other
+// than sandbox is named, and the offline sandbox adapters otherwise.
+func buildSessionProviders() (bootstrap.RuntimeProviders, *bootstrap.CatalogManager, error) {
+	profile := strings.ToLower(strings.TrimSpace(os.Getenv(bootstrap.ProfileEnv)))
+	if bootstrap.CatalogFileFromEnv() == "" && (profile == "" || profile == profileSandbox) {
+		runtimeProviders, err := bootstrap.BuildWithAdapters(demo.SandboxAdapters(), bootstrap.Options{MinProvidersPerModality: 1, MaxProvidersPerModality: 1})
+		return runtimeProviders, nil, err
+	}
+	return buildRuntimeProviders()
+}
+
+// newPipeline starts a session at sampleRateHz (0 selects the demo default) whose turns run
+// through the invocation controller of the session providers under the handshake's
+// traceparent, and tracks it on the admin socket until the pipeline closes. A sharded runtime
+// refuses sessions another worker owns, naming the owner's endpoint. A runtime resolving
+// session routes runs each session at its route's pipeline version. A blue/green pool runtime
+// only starts sessions the control plane routes to its pool, at the pool's pipeline version,
+// and reports its open sessions as each closes.
+func (r *servingRuntime) newPipeline(sessionID string, traceparent string, sampleRateHz int) (websocket.Pipeline, error) {
+	if r.shards != nil {
+		if err := r.shards.admit(sessionID); err != nil {
+			return nil, fmt.Errorf("session %s: %w", sessionID, err)
+		}
+	}
+	pipelineVersion := r.pipelineVersion
+	if r.routes != nil {
+		route, err := r.routes.resolve(sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("session %s: %w", sessionID, err)
+		}
+		pipelineVersion = route.PipelineVersion
+	}
+	poolColor := ""
+	if r.pool != nil {
+		pool, err := r.pool.route(sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("session %s: %w", sessionID, err)
+		}
+		pipelineVersion, poolColor = pool.PipelineVersion, pool.Color
+	}
+	if r.warmup != nil {
+		if _, err := r.warmup.WarmSession(sessionID); err != nil {
+			return nil, fmt.Errorf("session %s: provider warmup: %w", sessionID, err)
+		}
+	}
+	providers := r.sessionProviders()
+	session, err := demo.NewSession(demo.SessionConfig{
+		SessionID:         sessionID,
+		TenantID:          r.tenantID,
+		PipelineVersion:   pipelineVersion,
+		SampleRateHz:      sampleRateHz,
+		ArtifactsDir:      r.artifactsDir,
+		Clock:             r.now,
+		SLA:               r.sla,
+		NodeCache:         r.nodeCache,
+		TurnLoad:          r.turnLoad,
+		TurnStartResolver: r.turnStart,
+		GateTurnOpen:      r.gateTurnOpen(),
+		DecisionIndex:     r.decisions,
+		Invoker:           providers.Controller,
+		CancelFence:       r.cancelFence,
+		PreferredProvider: r.preferredProvider,
+		HealthScorer:      r.healthScorer,
+		Traceparent:       traceparent,
+	}, demo.Providers{})
+	if err != nil {
+		return nil, fmt.Errorf("session %s: %w", sessionID, err)
+	}
+	untrack := trackSession(r.sessions, session)
+	r.liveMu.Lock()
+	r.live[sessionID] = session
+	r.liveMu.Unlock()
+	return sessionPipeline{session: session, untrack: func() {
+		untrack()
+		r.liveMu.Lock()
+		delete(r.live, sessionID)
+		open := len(r.live)
+		r.liveMu.Unlock()
+		if r.pool != nil {
+			if err := r.pool.reportSessions(poolColor, open); err != nil {
+				log.Printf("serving runtime: session %s: %v", sessionID, err)
+			}
+		}
+	}, closed: r.appendRuntimeBaseline}, nil
+}
+
+// appendRuntimeBaseline appends a closed session's baseline to the serve mode's merged
+// runtime baseline (runtimeBaselineFileName). A session that completed no turn wrote none.
+func (r *servingRuntime) appendRuntimeBaseline(artifacts demo.Artifacts) error {
+	baseline, err := timeline.ReadBaselineArtifact(artifacts.BaselinePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("runtime baseline: %w", err)
+	}
+	r.baselineMu.Lock()
+	defer r.baselineMu.Unlock()
+	if err := timeline.AppendBaselineArtifact(filepath.Join(r.artifactsDir, runtimeBaselineFileName), baseline.Entries); err != nil {
+		return fmt.Errorf("runtime baseline: %w", err)
+	}
+	return nil
+}
+
+// preferredProvider names the modality's first provider in the current session catalog's
+// order, the provider turn plans prefer until health scoring routes them elsewhere.
+func (r *servingRuntime) preferredProvider(modality contracts.Modality) string {
+	ids, err := r.sessionProviders().Catalog.ProviderIDs(modality)
+	if err != nil {
+		return ""
+	}
+	return ids[0]
+}
+
+// cancelTurns cancels the turn plan each open session is executing, so a shutting-down serve
+// mode aborts in-flight provider work (streaming LLMs report the usage consumed so far)
+// instead of waiting it out. It returns the cancelled turn ids.
+func (r *servingRuntime) cancelTurns() []string {
+	r.liveMu.Lock()
+	sessions := make([]*demo.Session, 0, len(r.live))
+	for _, session := range r.live {
+		sessions = append(sessions, session)
+	}
+	r.liveMu.Unlock()
+	var cancelled []string
+	for _, session := range sessions {
+		turnID, err := session.CancelTurn()
+		if err != nil {
+			log.Printf("serving runtime: cancel turn: %v", err)
+			continue
+		}
+		if turnID != "" {
+			cancelled = append(cancelled, turnID)
+		}
+	}
+	sort.Strings(cancelled)
+	return cancelled
+}
+
+// gateTurnOpen applies the snapshot freshness fallback to turn-open requests; nil without a
+// freshness monitor.
+func (r *servingRuntime) gateTurnOpen() func(turnarbiter.OpenRequest) turnarbiter.OpenRequest {
+	if r.freshness == nil {
+		return nil
+	}
+	return r.freshness.ApplyToOpenRequest
+}
+
+// transportReportWriter writes each connection's transport report to
+// <artifactsDir>/<session>-transport.json, logging failures under the transport name.
+func transportReportWriter(artifactsDir string, name string, logger *log.Logger) func(transport.Report) {
+	return func(report transport.Report) {
+		path := filepath.Join(artifactsDir, report.SessionID+"-transport.json")
+		if err := writeJSONArtifact(path, report); err != nil {
+			logger.Printf("%s: session %s transport report: %v", name, report.SessionID, err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+)
+
+// envSessionRoutes selects how a serve mode resolves the route of each new session. "grpc"
+// resolves it through the control plane's session-route gRPC service at
+// controlplaneclient.EnvURL for the RSPP_ENVIRONMENT deployment; unset runs every session at
+// the runtime's pipeline version.
+const envSessionRoutes = "RSPP_RUNTIME_SESSION_ROUTES"
+
+// sessionRouter resolves the route of each new session of one tenant over gRPC.
+type sessionRouter struct {
+	client      *controlplaneclient.GRPCClient
+	tenantID    string
+	environment string
+}
+
+// sessionRouterFromEnv returns the session router of a serve mode, or nil when
+// envSessionRoutes is unset.
+func sessionRouterFromEnv(tenantID string) (*sessionRouter, error) {
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv(envSessionRoutes))); mode {
+	case "":
+		return nil, nil
+	case "grpc":
+	default:
+		return nil, fmt.Errorf("%s must be grpc, got %q", envSessionRoutes, mode)
+	}
+	if tenantID == "" {
+		return nil, fmt.Errorf("%s requires a tenant", envSessionRoutes)
+	}
+	client, err := controlplaneclient.NewGRPCFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", envSessionRoutes, err)
+	}
+	return &sessionRouter{
+		client:      client,
+		tenantID:    tenantID,
+		environment: strings.ToLower(strings.TrimSpace(os.Getenv(distribution.EnvEnvironment))),
+	}, nil
+}
+
+// resolve returns the control plane's route for sessionID.
+func (s *sessionRouter) resolve(sessionID string) (controlplaneclient.SessionRoute, error) {
+	route, err := s.client.ResolveSessionRoute(context.Background(), controlplaneclient.ResolveSessionRouteRequest{
+		TenantID:    s.tenantID,
+		SessionID:   sessionID,
+		Environment: s.environment,
+	})
+	if err != nil {
+		return controlplaneclient.SessionRoute{}, fmt.Errorf("resolve session route: %w", err)
+	}
+	return route, nil
+}
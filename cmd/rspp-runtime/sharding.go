@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sharding"
+)
+
+// shardGuard admits only the sessions this runtime worker (sharding.EnvWorkerID) owns under the
+// shard map the control plane publishes in the distribution state. Sessions owned by another
+// worker are refused with the owner's endpoint, which is where route resolution sends them.
+type shardGuard struct {
+	workerID string
+	// backend returns the current shard map source: the HTTP distribution refreshes on its own
+	// cache TTL, and a distribution file is re-read per session.
+	backend func() (sharding.Backend, error)
+}
+
+// shardGuardFromEnv returns the shard guard of a serve mode, or nil when no worker id is set.
+func shardGuardFromEnv() (*shardGuard, error) {
+	workerID := sharding.WorkerIDFromEnv()
+	if workerID == "" {
+		return nil, nil
+	}
+	if !distributionConfigured() {
+		return nil, fmt.Errorf("%s requires a control-plane distribution (%s or %s)", sharding.EnvWorkerID, distribution.EnvHTTPAdapterURL, distribution.EnvFileAdapterPath)
+	}
+	if strings.TrimSpace(os.Getenv(distribution.EnvFileAdapterPath)) != "" {
+		return &shardGuard{workerID: workerID, backend: func() (sharding.Backend, error) {
+			backends, err := distribution.NewFileBackendsFromEnv()
+			return backends.Sharding, err
+		}}, nil
+	}
+	backends, err := distribution.NewHTTPBackendsFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", sharding.EnvWorkerID, err)
+	}
+	return &shardGuard{workerID: workerID, backend: func() (sharding.Backend, error) {
+		return backends.Sharding, nil
+	}}, nil
+}
+
+// admit returns an error naming the owning worker when sessionID belongs to another worker.
+func (g *shardGuard) admit(sessionID string) error {
+	backend, err := g.backend()
+	if err != nil {
+		return fmt.Errorf("load shard map: %w", err)
+	}
+	out, err := sharding.Service{WorkerID: g.workerID, Backend: backend}.Resolve(sharding.Input{SessionID: sessionID})
+	var backendErr distribution.BackendError
+	if errors.As(err, &backendErr) && backendErr.Code == distribution.ErrorCodeSnapshotMissing {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if out.Owned {
+		return nil
+	}
+	if out.OwnerEndpoint == "" {
+		return fmt.Errorf("%s: session is owned by runtime worker %s", sharding.ReasonShardNotOwned, out.OwnerWorkerID)
+	}
+	return fmt.Errorf("%s: session is owned by runtime worker %s; reconnect to %s", sharding.ReasonShardNotOwned, out.OwnerWorkerID, out.OwnerEndpoint)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/diagnostics"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/exitcode"
+	"github.com/tiger/realtime-speech-pipeline/transports/twilio"
+	"github.com/tiger/realtime-speech-pipeline/transports/websocket"
+)
+
+const defaultTwilioPipelineVersion = "pipeline-twilio"
+
+// runTwilio serves the session pipeline as a Twilio Media Streams endpoint at /media, for
+// <Connect><Stream> calls streaming 8 kHz mulaw audio.
+func runTwilio(args []string, stdout io.Writer, now func() time.Time) error {
+	fs := flag.NewFlagSet("twilio", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	addr := fs.String("addr", "127.0.0.1:8790", "listen address for the media stream WebSocket endpoint")
+	artifactsDir := fs.String("artifacts-dir", filepath.Join(".codex", "twilio"), "directory for session audio, baseline, and transport report artifacts")
+	pipelineVersion := fs.String("pipeline-version", defaultTwilioPipelineVersion, "pipeline version recorded on sessions and transport signals")
+	authorityEpoch := fs.Int64("authority-epoch", 0, "authority epoch carried on transport control signals")
+	tenantID := fs.String("tenant-id", "", "tenant recorded on sessions; its SLA tier is resolved from the CP distribution snapshot when one is configured")
+	adminSocket := fs.String("admin-socket", diagnostics.AdminSocketPathFromEnv(), "unix admin socket serving rspp-runtime diagnostics snapshots (empty disables)")
+	if err := fs.Parse(args); err != nil {
+		return exitcode.Usage(err)
+	}
+	serving, err := newServingRuntime(*tenantID, *pipelineVersion, *artifactsDir, now)
+	if err != nil {
+		return fmt.Errorf("twilio: %w", err)
+	}
+	defer serving.close()
+
+	logger := log.Default()
+	handler, err := newTwilioHandler(twilio.Config{
+		PipelineVersion: serving.pipelineVersion,
+		AuthorityEpoch:  *authorityEpoch,
+		Clock:           now,
+		Logger:          logger,
+		NewPipeline: func(sessionID, traceparent string) (websocket.Pipeline, error) {
+			return serving.newPipeline(sessionID, traceparent, twilio.SampleRateHz)
+		},
+		OnReport: transportReportWriter(*artifactsDir, "twilio", logger),
+	})
+	if err != nil {
+		return fmt.Errorf("twilio: %w", err)
+	}
+	stopAdmin, err := startAdminSocket(*adminSocket, serving)
+	if err != nil {
+		return fmt.Errorf("twilio: %w", err)
+	}
+	defer stopAdmin()
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("twilio: %w", err)
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		// Abort in-flight turn plans instead of waiting out their provider calls.
+		serving.cancelTurns()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	_, _ = fmt.Fprintf(stdout, "rspp-runtime twilio: stream calls to ws://%s/media (RSPP_PROVIDER_PROFILE providers, artifacts in %s)\n", listener.Addr(), *artifactsDir)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("twilio: %w", err)
+	}
+	return nil
+}
+
+// newTwilioHandler mounts the Media Streams WebSocket endpoint at /media.
+func newTwilioHandler(cfg twilio.Config) (http.Handler, error) {
+	transportHandler, err := twilio.NewHandler(cfg)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/media", transportHandler)
+	return mux, nil
+}
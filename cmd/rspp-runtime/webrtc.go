@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/diagnostics"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/exitcode"
+	"github.com/tiger/realtime-speech-pipeline/transports/webrtc"
+	"github.com/tiger/realtime-speech-pipeline/transports/websocket"
+)
+
+const defaultWebRTCPipelineVersion = "pipeline-webrtc"
+
+// runWebRTC serves the session pipeline over the Pion WebRTC transport: clients POST an SDP
+// offer to /offer and stream PCMU audio, without a LiveKit SFU.
+func runWebRTC(args []string, stdout io.Writer, now func() time.Time) error {
+	fs := flag.NewFlagSet("webrtc", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	addr := fs.String("addr", "127.0.0.1:8789", "listen address for SDP offer signaling")
+	artifactsDir := fs.String("artifacts-dir", filepath.Join(".codex", "webrtc"), "directory for session audio, baseline, and transport report artifacts")
+	pipelineVersion := fs.String("pipeline-version", defaultWebRTCPipelineVersion, "pipeline version recorded on sessions and transport signals")
+	authorityEpoch := fs.Int64("authority-epoch", 0, "authority epoch carried on transport control signals")
+	tenantID := fs.String("tenant-id", "", "tenant recorded on sessions; its SLA tier is resolved from the CP distribution snapshot when one is configured")
+	adminSocket := fs.String("admin-socket", diagnostics.AdminSocketPathFromEnv(), "unix admin socket serving rspp-runtime diagnostics snapshots (empty disables)")
+	iceServers := fs.String("ice-servers", "", "comma-separated STUN/TURN URLs offered to peer connections")
+	if err := fs.Parse(args); err != nil {
+		return exitcode.Usage(err)
+	}
+	serving, err := newServingRuntime(*tenantID, *pipelineVersion, *artifactsDir, now)
+	if err != nil {
+		return fmt.Errorf("webrtc: %w", err)
+	}
+	defer serving.close()
+
+	logger := log.Default()
+	handler, err := newWebRTCHandler(webrtc.Config{
+		PipelineVersion: serving.pipelineVersion,
+		AuthorityEpoch:  *authorityEpoch,
+		ICEServers:      parseICEServers(*iceServers),
+		Clock:           now,
+		Logger:          logger,
+		NewPipeline: func(sessionID, traceparent string) (websocket.Pipeline, error) {
+			return serving.newPipeline(sessionID, traceparent, webrtc.SampleRateHz)
+		},
+		OnReport: transportReportWriter(*artifactsDir, "webrtc", logger),
+	})
+	if err != nil {
+		return fmt.Errorf("webrtc: %w", err)
+	}
+	stopAdmin, err := startAdminSocket(*adminSocket, serving)
+	if err != nil {
+		return fmt.Errorf("webrtc: %w", err)
+	}
+	defer stopAdmin()
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("webrtc: %w", err)
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		// Abort in-flight turn plans instead of waiting out their provider calls.
+		serving.cancelTurns()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	_, _ = fmt.Fprintf(stdout, "rspp-runtime webrtc: POST SDP offers to http://%s/offer (RSPP_PROVIDER_PROFILE providers, artifacts in %s)\n", listener.Addr(), *artifactsDir)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("webrtc: %w", err)
+	}
+	return nil
+}
+
+// newWebRTCHandler mounts the offer/answer signaling endpoint at /offer.
+func newWebRTCHandler(cfg webrtc.Config) (http.Handler, error) {
+	transportHandler, err := webrtc.NewHandler(cfg)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/offer", transportHandler)
+	return mux, nil
+}
+
+// parseICEServers splits the comma-separated -ice-servers flag, keeping its order.
+func parseICEServers(raw string) []string {
+	servers := make([]string, 0)
+	for _, part := range strings.Split(raw, ",") {
+		if server := strings.TrimSpace(part); server != "" {
+			servers = append(servers, server)
+		}
+	}
+	return servers
+}
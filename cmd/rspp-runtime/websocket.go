@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/demo"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/diagnostics"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/transport"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/exitcode"
+	"github.com/tiger/realtime-speech-pipeline/transports/websocket"
+)
+
+const defaultWebSocketPipelineVersion = "pipeline-websocket"
+
+// runWebSocket serves the session pipeline over the plain WebSocket transport: the browser
+// client at / and one session per connection at /ws, without a LiveKit deployment.
+func runWebSocket(args []string, stdout io.Writer, now func() time.Time) error {
+	fs := flag.NewFlagSet("websocket", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	addr := fs.String("addr", "127.0.0.1:8788", "listen address for the websocket transport")
+	artifactsDir := fs.String("artifacts-dir", filepath.Join(".codex", "websocket"), "directory for session audio, baseline, and transport report artifacts")
+	pipelineVersion := fs.String("pipeline-version", defaultWebSocketPipelineVersion, "pipeline version recorded on sessions and transport signals")
+	authorityEpoch := fs.Int64("authority-epoch", 0, "authority epoch carried on transport control signals")
+	tenantID := fs.String("tenant-id", "", "tenant recorded on sessions; its SLA tier is resolved from the CP distribution snapshot when one is configured")
+	adminSocket := fs.String("admin-socket", diagnostics.AdminSocketPathFromEnv(), "unix admin socket serving rspp-runtime diagnostics snapshots (empty disables)")
+	if err := fs.Parse(args); err != nil {
+		return exitcode.Usage(err)
+	}
+	serving, err := newServingRuntime(*tenantID, *pipelineVersion, *artifactsDir, now)
+	if err != nil {
+		return fmt.Errorf("websocket: %w", err)
+	}
+	defer serving.close()
+
+	logger := log.Default()
+	handler, err := newWebSocketHandler(websocket.Config{
+		PipelineVersion: serving.pipelineVersion,
+		AuthorityEpoch:  *authorityEpoch,
+		Clock:           now,
+		Logger:          logger,
+		NewPipeline: func(sessionID, traceparent string) (websocket.Pipeline, error) {
+			return serving.newPipeline(sessionID, traceparent, 0)
+		},
+		OnReport: transportReportWriter(*artifactsDir, "websocket", logger),
+	})
+	if err != nil {
+		return fmt.Errorf("websocket: %w", err)
+	}
+	stopAdmin, err := startAdminSocket(*adminSocket, serving)
+	if err != nil {
+		return fmt.Errorf("websocket: %w", err)
+	}
+	defer stopAdmin()
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("websocket: %w", err)
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		// Abort in-flight turn plans instead of waiting out their provider calls.
+		serving.cancelTurns()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	_, _ = fmt.Fprintf(stdout, "rspp-runtime websocket: open http://%s or connect ws://%s/ws (RSPP_PROVIDER_PROFILE providers, artifacts in %s)\n", listener.Addr(), listener.Addr(), *artifactsDir)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("websocket: %w", err)
+	}
+	return nil
+}
+
+// newWebSocketHandler mounts the browser client at / and the transport at /ws.
+func newWebSocketHandler(cfg websocket.Config) (http.Handler, error) {
+	transportHandler, err := websocket.NewHandler(cfg)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", demo.WebClientHandler())
+	mux.Handle("/ws", transportHandler)
+	return mux, nil
+}
+
+// startLoopbackWebSocket serves the session pipeline over the WebSocket transport on a
+// loopback listener, for in-process canary sessions. It returns the transport endpoint and a
+// stop function that waits for in-flight sessions to write their artifacts.
+func startLoopbackWebSocket(artifactsDir string, now func() time.Time) (string, func(), error) {
+	serving, err := newServingRuntime("", defaultWebSocketPipelineVersion, artifactsDir, now)
+	if err != nil {
+		return "", nil, err
+	}
+	handler, err := websocket.NewHandler(websocket.Config{
+		PipelineVersion: serving.pipelineVersion,
+		Clock:           now,
+		NewPipeline: func(sessionID, traceparent string) (websocket.Pipeline, error) {
+			return serving.newPipeline(sessionID, traceparent, 0)
+		},
+	})
+	if err != nil {
+		serving.close()
+		return "", nil, err
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		serving.close()
+		return "", nil, err
+	}
+	// Sessions run on hijacked connections that server.Close neither closes nor waits for.
+	var sessions sync.WaitGroup
+	server := &http.Server{ReadHeaderTimeout: 10 * time.Second, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessions.Add(1)
+		defer sessions.Done()
+		handler.ServeHTTP(w, r)
+	})}
+	go func() { _ = server.Serve(listener) }()
+	stop := func() {
+		_ = server.Close()
+		sessions.Wait()
+		serving.close()
+	}
+	return "ws://" + listener.Addr().String() + "/ws", stop, nil
+}
+
+// resolveTenantSLATier looks up the tenant's SLA tier in the env-configured CP distribution
+// snapshot. Sessions carry no tier when no tenant or distribution is configured, or when the
+// distribution declares no SLA for the tenant.
+func resolveTenantSLATier(tenantID string) (*controlplane.SLATier, error) {
+	if tenantID == "" {
+		return nil, nil
+	}
+	if !distributionConfigured() {
+		return nil, nil
+	}
+	snapshot, _, err := distribution.LoadSLASnapshotFromEnv()
+	var backendErr distribution.BackendError
+	if errors.As(err, &backendErr) && backendErr.Code == distribution.ErrorCodeSnapshotMissing {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resolve tenant sla: %w", err)
+	}
+	sla, ok := snapshot.Resolve(tenantID)
+	if !ok {
+		return nil, nil
+	}
+	return &sla.SLATier, nil
+}
+
+// sessionPipeline runs a demo session behind the websocket transport.
+type sessionPipeline struct {
+	session *demo.Session
+	// untrack drops the session from the admin socket's live sessions.
+	untrack func()
+	// closed receives the written session artifacts; nil ignores them.
+	closed func(demo.Artifacts) error
+}
+
+func (p sessionPipeline) StartCapture() error {
+	return p.session.StartCapture()
+}
+
+func (p sessionPipeline) AppendAudio(pcm []int16) (*websocket.Turn, error) {
+	return sessionTurn(p.session.AppendAudio(pcm))
+}
+
+func (p sessionPipeline) EndCapture() (*websocket.Turn, error) {
+	return sessionTurn(p.session.EndCapture())
+}
+
+func (p sessionPipeline) RecordReplyEgress(turnID string) error {
+	return p.session.RecordReplyEgress(turnID)
+}
+
+func (p sessionPipeline) AcknowledgePlayback(ack transport.PlaybackAck) error {
+	_, err := p.session.AcknowledgePlayback(ack)
+	return err
+}
+
+func (p sessionPipeline) CancelPlayback(turnID string, reason string) error {
+	return p.session.CancelPlayback(turnID, reason)
+}
+
+// Close writes the session audio and timeline baseline.
+func (p sessionPipeline) Close() error {
+	if p.untrack != nil {
+		p.untrack()
+	}
+	artifacts, err := p.session.WriteArtifacts()
+	if err != nil || p.closed == nil {
+		return err
+	}
+	return p.closed(artifacts)
+}
+
+func sessionTurn(result *demo.TurnResult, err error) (*websocket.Turn, error) {
+	if result == nil || err != nil {
+		return nil, err
+	}
+	return &websocket.Turn{
+		TurnID:         result.TurnID,
+		Transcript:     result.Transcript,
+		Reply:          result.Reply,
+		ReplyPCM:       result.ReplyPCM,
+		Committed:      result.Committed,
+		FallbackReason: result.FallbackReason,
+	}, nil
+}
@@ -0,0 +1,197 @@
+package distribution
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SetActivePipelineVersion rewrites the registry and rollout default pipeline version in a
+// file-backed distribution artifact and returns the previously active rollout version.
+// Sections other than registry/rollout are preserved byte-for-byte, and the previous state is
+// kept as a rotated backup (see WriteStateFile). The change is made under LockStateFile.
+func SetActivePipelineVersion(path string, pipelineVersion string) (string, error) {
+	path = strings.TrimSpace(path)
+	version := strings.TrimSpace(pipelineVersion)
+	if path == "" {
+		return "", BackendError{Service: "distribution", Code: ErrorCodeInvalidConfig, Path: "path"}
+	}
+	if version == "" {
+		return "", BackendError{Service: "distribution", Code: ErrorCodeInvalidConfig, Path: path, Cause: fmt.Errorf("pipeline_version is required")}
+	}
+	unlock, err := LockStateFile(path)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	return setActivePipelineVersion(path, version)
+}
+
+// setActivePipelineVersion is SetActivePipelineVersion for a caller holding the state lock.
+func setActivePipelineVersion(path string, version string) (string, error) {
+	adapter, err := newFileAdapter(FileAdapterConfig{Path: path})
+	if err != nil {
+		return "", err
+	}
+	if _, ok := adapter.artifact.Registry.Records[version]; !ok {
+		return "", BackendError{Service: "registry", Code: ErrorCodeSnapshotMissing, Path: path, Cause: fmt.Errorf("missing record for pipeline_version=%s", version)}
+	}
+	previous := strings.TrimSpace(adapter.artifact.Rollout.DefaultPipelineVersion)
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", BackendError{Service: "distribution", Code: ErrorCodeReadArtifact, Path: path, Cause: err}
+	}
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return "", BackendError{Service: "distribution", Code: ErrorCodeDecodeArtifact, Path: path, Cause: err}
+	}
+	for _, section := range []string{"registry", "rollout"} {
+		patched, err := setSectionDefaultVersion(document[section], version)
+		if err != nil {
+			return "", BackendError{Service: section, Code: ErrorCodeDecodeArtifact, Path: path, Cause: err}
+		}
+		document[section] = patched
+	}
+
+	out, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := WriteStateFile(path, out, DefaultStateBackups); err != nil {
+		return "", err
+	}
+	return previous, nil
+}
+
+func setSectionDefaultVersion(raw json.RawMessage, version string) (json.RawMessage, error) {
+	section := map[string]json.RawMessage{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &section); err != nil {
+			return nil, err
+		}
+	}
+	encoded, err := json.Marshal(version)
+	if err != nil {
+		return nil, err
+	}
+	section["default_pipeline_version"] = encoded
+	return json.Marshal(section)
+}
+
+// SetEnvironmentPipelineVersion activates pipelineVersion for one deployment environment in a
+// file-backed distribution artifact and returns the environment's previously active version.
+// Only rollout.by_environment changes, so other environments keep resolving their own or the
+// default version. The change is made under LockStateFile.
+func SetEnvironmentPipelineVersion(path string, environment string, pipelineVersion string) (string, error) {
+	path = strings.TrimSpace(path)
+	environment = strings.ToLower(strings.TrimSpace(environment))
+	version := strings.TrimSpace(pipelineVersion)
+	if path == "" {
+		return "", BackendError{Service: "distribution", Code: ErrorCodeInvalidConfig, Path: "path"}
+	}
+	if environment == "" || version == "" {
+		return "", BackendError{Service: "distribution", Code: ErrorCodeInvalidConfig, Path: path, Cause: fmt.Errorf("environment and pipeline_version are required")}
+	}
+	unlock, err := LockStateFile(path)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	adapter, err := newFileAdapter(FileAdapterConfig{Path: path})
+	if err != nil {
+		return "", err
+	}
+	if _, ok := adapter.artifact.Registry.Records[version]; !ok {
+		return "", BackendError{Service: "registry", Code: ErrorCodeSnapshotMissing, Path: path, Cause: fmt.Errorf("missing record for pipeline_version=%s", version)}
+	}
+	previous := adapter.artifact.Rollout.activeVersion(environment)
+	err = patchRolloutMap(path, "by_environment", func(entries map[string]string) bool {
+		entries[environment] = version
+		return true
+	})
+	return previous, err
+}
+
+// EnvironmentPipelineVersion returns the pipeline version new sessions in environment resolve
+// to: the environment's own active version, or the default.
+func EnvironmentPipelineVersion(path string, environment string) (string, error) {
+	adapter, err := newFileAdapter(FileAdapterConfig{Path: path})
+	if err != nil {
+		return "", err
+	}
+	return adapter.artifact.Rollout.activeVersion(environment), nil
+}
+
+// PinPipelineVersions maps each published version to itself in rollout.by_requested_version,
+// so sessions that request one of them (such as sessions routed to a blue/green pool) resolve
+// it whatever the active version is. Versions already mapped are left alone. The change is
+// made under LockStateFile.
+func PinPipelineVersions(path string, versions ...string) error {
+	path = strings.TrimSpace(path)
+	unlock, err := LockStateFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	adapter, err := newFileAdapter(FileAdapterConfig{Path: path})
+	if err != nil {
+		return err
+	}
+	for _, version := range versions {
+		if _, ok := adapter.artifact.Registry.Records[strings.TrimSpace(version)]; !ok {
+			return BackendError{Service: "registry", Code: ErrorCodeSnapshotMissing, Path: path, Cause: fmt.Errorf("missing record for pipeline_version=%s", version)}
+		}
+	}
+	return patchRolloutMap(path, "by_requested_version", func(entries map[string]string) bool {
+		changed := false
+		for _, version := range versions {
+			version = strings.TrimSpace(version)
+			if strings.TrimSpace(entries[version]) == "" {
+				entries[version] = version
+				changed = true
+			}
+		}
+		return changed
+	})
+}
+
+// patchRolloutMap rewrites one string map of the rollout section when change reports a change,
+// preserving every other field and section. The caller holds the state lock.
+func patchRolloutMap(path string, field string, change func(map[string]string) bool) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return BackendError{Service: "distribution", Code: ErrorCodeReadArtifact, Path: path, Cause: err}
+	}
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return BackendError{Service: "distribution", Code: ErrorCodeDecodeArtifact, Path: path, Cause: err}
+	}
+	section := map[string]json.RawMessage{}
+	if len(document["rollout"]) > 0 {
+		if err := json.Unmarshal(document["rollout"], &section); err != nil {
+			return BackendError{Service: "rollout", Code: ErrorCodeDecodeArtifact, Path: path, Cause: err}
+		}
+	}
+	entries := map[string]string{}
+	if len(section[field]) > 0 {
+		if err := json.Unmarshal(section[field], &entries); err != nil {
+			return BackendError{Service: "rollout", Code: ErrorCodeDecodeArtifact, Path: path, Cause: err}
+		}
+	}
+	if !change(entries) {
+		return nil
+	}
+	if section[field], err = json.Marshal(entries); err != nil {
+		return err
+	}
+	if document["rollout"], err = json.Marshal(section); err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteStateFile(path, out, DefaultStateBackups)
+}
@@ -0,0 +1,146 @@
+package distribution
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/rollout"
+)
+
+func TestSetActivePipelineVersionFlipsRegistryAndRollout(t *testing.T) {
+	t.Parallel()
+
+	path := writeDistributionArtifact(t, `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "registry": {
+    "default_pipeline_version": "pipeline-v2",
+    "records": {
+      "pipeline-v1": {"pipeline_version": "pipeline-v1", "graph_definition_ref": "graph/v1", "execution_profile": "simple"},
+      "pipeline-v2": {"pipeline_version": "pipeline-v2", "graph_definition_ref": "graph/v2", "execution_profile": "simple"}
+    }
+  },
+  "rollout": {
+    "default_pipeline_version": "pipeline-v2",
+    "version_resolution_snapshot": "version-resolution/file"
+  },
+  "routing_view": {
+    "default": {"routing_view_snapshot": "routing-view/file", "admission_policy_snapshot": "admission-policy/file", "abi_compatibility_snapshot": "abi-compat/file"}
+  }
+}`)
+
+	previous, err := SetActivePipelineVersion(path, "pipeline-v1")
+	if err != nil {
+		t.Fatalf("unexpected set active version error: %v", err)
+	}
+	if previous != "pipeline-v2" {
+		t.Fatalf("expected previous version pipeline-v2, got %q", previous)
+	}
+
+	backends, err := NewFileBackends(FileAdapterConfig{Path: path})
+	if err != nil {
+		t.Fatalf("unexpected backend reload error: %v", err)
+	}
+	out, err := rollout.Service{Backend: backends.Rollout}.ResolvePipelineVersion(rollout.ResolveVersionInput{SessionID: "sess-rollback"})
+	if err != nil {
+		t.Fatalf("unexpected rollout resolve error: %v", err)
+	}
+	if out.PipelineVersion != "pipeline-v1" || out.VersionResolutionSnapshot != "version-resolution/file" {
+		t.Fatalf("expected rolled-back rollout resolution, got %+v", out)
+	}
+	record, err := backends.Registry.ResolvePipelineRecord("")
+	if err != nil || record.GraphDefinitionRef != "graph/v1" {
+		t.Fatalf("expected registry default to follow rollback, got %+v err=%v", record, err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if !strings.Contains(string(raw), "abi-compat/file") {
+		t.Fatalf("expected unrelated sections preserved, got %s", raw)
+	}
+}
+
+func TestSetActivePipelineVersionRejectsUnknownRecord(t *testing.T) {
+	t.Parallel()
+
+	path := writeDistributionArtifact(t, `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "registry": {"records": {"pipeline-v2": {"pipeline_version": "pipeline-v2"}}},
+  "rollout": {"default_pipeline_version": "pipeline-v2"}
+}`)
+
+	if _, err := SetActivePipelineVersion(path, "pipeline-missing"); err == nil {
+		t.Fatalf("expected unknown registry record error")
+	}
+	backends, err := NewFileBackends(FileAdapterConfig{Path: path})
+	if err != nil {
+		t.Fatalf("unexpected backend reload error: %v", err)
+	}
+	out, err := backends.Rollout.ResolvePipelineVersion(rollout.ResolveVersionInput{SessionID: "sess"})
+	if err != nil || out.PipelineVersion != "pipeline-v2" {
+		t.Fatalf("expected artifact untouched after rejected flip, got %+v err=%v", out, err)
+	}
+}
+
+func TestSetEnvironmentPipelineVersionScopesResolution(t *testing.T) {
+	t.Parallel()
+
+	path := writeDistributionArtifact(t, `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "registry": {
+    "default_pipeline_version": "pipeline-v1",
+    "records": {
+      "pipeline-v1": {"pipeline_version": "pipeline-v1", "graph_definition_ref": "graph/v1"},
+      "pipeline-v2": {"pipeline_version": "pipeline-v2", "graph_definition_ref": "graph/v2"}
+    }
+  },
+  "rollout": {"default_pipeline_version": "pipeline-v1"}
+}`)
+
+	if _, err := SetEnvironmentPipelineVersion(path, "prod", "pipeline-missing"); err == nil {
+		t.Fatalf("expected unknown registry record error")
+	}
+	if err := PinPipelineVersions(path, "pipeline-v1", "pipeline-v2"); err != nil {
+		t.Fatalf("unexpected pin error: %v", err)
+	}
+	previous, err := SetEnvironmentPipelineVersion(path, "Prod", "pipeline-v2")
+	if err != nil || previous != "pipeline-v1" {
+		t.Fatalf("expected previous prod version pipeline-v1, got %q err=%v", previous, err)
+	}
+	if version, err := EnvironmentPipelineVersion(path, "prod"); err != nil || version != "pipeline-v2" {
+		t.Fatalf("expected prod to resolve pipeline-v2, got %q err=%v", version, err)
+	}
+	if version, err := EnvironmentPipelineVersion(path, "staging"); err != nil || version != "pipeline-v1" {
+		t.Fatalf("expected staging to keep the default, got %q err=%v", version, err)
+	}
+
+	cases := []struct {
+		name        string
+		configEnv   string
+		input       rollout.ResolveVersionInput
+		wantVersion string
+	}{
+		{name: "input environment", input: rollout.ResolveVersionInput{SessionID: "sess-1", Environment: "prod"}, wantVersion: "pipeline-v2"},
+		{name: "backend environment", configEnv: "prod", input: rollout.ResolveVersionInput{SessionID: "sess-1"}, wantVersion: "pipeline-v2"},
+		{name: "other environment", configEnv: "prod", input: rollout.ResolveVersionInput{SessionID: "sess-1", Environment: "staging"}, wantVersion: "pipeline-v1"},
+		{name: "no environment", input: rollout.ResolveVersionInput{SessionID: "sess-1"}, wantVersion: "pipeline-v1"},
+		{name: "pinned request", input: rollout.ResolveVersionInput{SessionID: "sess-1", Environment: "prod", RequestedPipelineVersion: "pipeline-v1"}, wantVersion: "pipeline-v1"},
+	}
+	for _, tc := range cases {
+		backends, err := NewFileBackends(FileAdapterConfig{Path: path, Environment: tc.configEnv})
+		if err != nil {
+			t.Fatalf("%s: unexpected backend error: %v", tc.name, err)
+		}
+		out, err := rollout.Service{Backend: backends.Rollout}.ResolvePipelineVersion(tc.input)
+		if err != nil || out.PipelineVersion != tc.wantVersion {
+			t.Fatalf("%s: expected %s, got %+v err=%v", tc.name, tc.wantVersion, out, err)
+		}
+	}
+
+	state, err := DescribeActiveVersion(path)
+	if err != nil || state.ActivePipelineVersion != "pipeline-v1" {
+		t.Fatalf("expected the default active version untouched, got %+v err=%v", state, err)
+	}
+}
@@ -0,0 +1,104 @@
+package distribution
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+)
+
+// AuditActionRollback is the audit action of a rollback to a previous active version.
+const AuditActionRollback = "rollback"
+
+// AuditEntry records one change applied to a distribution state. Source names what applied
+// it (a serving control plane or an rspp-cli command).
+type AuditEntry struct {
+	AtMS                    int64  `json:"at_ms"`
+	Action                  string `json:"action"`
+	PipelineVersion         string `json:"pipeline_version"`
+	PreviousPipelineVersion string `json:"previous_pipeline_version,omitempty"`
+	Activated               bool   `json:"activated,omitempty"`
+	Source                  string `json:"source,omitempty"`
+}
+
+// AuditLogPath names the append-only audit log kept beside a distribution state file.
+func AuditLogPath(path string) string {
+	return path + ".audit.jsonl"
+}
+
+// AppendAuditEntry appends entry as one JSON line to the audit log of the distribution state
+// at path. Each entry is a single append write, so concurrent appliers never interleave.
+func AppendAuditEntry(path string, entry AuditEntry) error {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encode audit entry: %w", err)
+	}
+	file, err := os.OpenFile(AuditLogPath(path), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	if _, err := file.Write(append(raw, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("append audit log: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("sync audit log: %w", err)
+	}
+	return file.Close()
+}
+
+// ReadAuditLog returns the audit entries of the distribution state at path in append order;
+// a state that was never changed through an audited path has none.
+func ReadAuditLog(path string) ([]AuditEntry, error) {
+	file, err := os.Open(AuditLogPath(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	defer file.Close()
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("decode audit log line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// RollbackActivePipelineVersion activates pipelineVersion as a rollback and appends the
+// rollback to the audit log, so a rollback applied by a serving control plane and one applied
+// by rspp-cli execute-rollback leave the same audit entry. source names the applier.
+func RollbackActivePipelineVersion(path string, pipelineVersion string, source string, now time.Time) (AuditEntry, error) {
+	previous, err := SetActivePipelineVersion(path, pipelineVersion)
+	if err != nil {
+		return AuditEntry{}, err
+	}
+	entry := AuditEntry{
+		AtMS:                    now.UnixMilli(),
+		Action:                  AuditActionRollback,
+		PipelineVersion:         strings.TrimSpace(pipelineVersion),
+		PreviousPipelineVersion: previous,
+		Activated:               true,
+		Source:                  source,
+	}
+	if err := AppendAuditEntry(strings.TrimSpace(path), entry); err != nil {
+		return entry, fmt.Errorf("active pipeline version changed but the audit entry was not persisted: %w", err)
+	}
+	return entry, nil
+}
@@ -0,0 +1,72 @@
+package distribution
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRollbackActivePipelineVersionAppendsAuditEntry(t *testing.T) {
+	t.Parallel()
+
+	path := writeDistributionArtifact(t, `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "registry": {
+    "default_pipeline_version": "pipeline-v2",
+    "records": {
+      "pipeline-v1": {"pipeline_version": "pipeline-v1"},
+      "pipeline-v2": {"pipeline_version": "pipeline-v2"},
+      "pipeline-v3": {"pipeline_version": "pipeline-v3"}
+    }
+  },
+  "rollout": {"default_pipeline_version": "pipeline-v2"}
+}`)
+
+	entries, err := ReadAuditLog(path)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("expected empty audit log before any rollback, got %+v err=%v", entries, err)
+	}
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		target       string
+		source       string
+		wantPrevious string
+		shouldErr    bool
+	}{
+		{name: "cli rollback", target: "pipeline-v1", source: "rspp-cli execute-rollback", wantPrevious: "pipeline-v2"},
+		{name: "control plane rollback", target: "pipeline-v3", source: "rspp-control-plane", wantPrevious: "pipeline-v1"},
+		{name: "unknown record", target: "pipeline-missing", source: "rspp-cli execute-rollback", shouldErr: true},
+	}
+	for _, tc := range tests {
+		entry, err := RollbackActivePipelineVersion(path, tc.target, tc.source, now)
+		if tc.shouldErr {
+			if err == nil {
+				t.Fatalf("%s: expected rollback error", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected rollback error: %v", tc.name, err)
+		}
+		want := AuditEntry{
+			AtMS:                    now.UnixMilli(),
+			Action:                  AuditActionRollback,
+			PipelineVersion:         tc.target,
+			PreviousPipelineVersion: tc.wantPrevious,
+			Activated:               true,
+			Source:                  tc.source,
+		}
+		if entry != want {
+			t.Fatalf("%s: expected entry %+v, got %+v", tc.name, want, entry)
+		}
+	}
+
+	entries, err = ReadAuditLog(path)
+	if err != nil {
+		t.Fatalf("unexpected audit log read error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Source != "rspp-cli execute-rollback" || entries[1].Source != "rspp-control-plane" || entries[1].PreviousPipelineVersion != "pipeline-v1" {
+		t.Fatalf("expected one audit entry per applied rollback in order, got %+v", entries)
+	}
+}
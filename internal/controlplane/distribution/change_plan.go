@@ -0,0 +1,132 @@
+package distribution
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ShortChangeHashLength is how many leading change hash characters an operator confirms.
+const ShortChangeHashLength = 12
+
+// ActiveVersionState summarizes a file-backed distribution state for operators.
+type ActiveVersionState struct {
+	Path                  string
+	ActivePipelineVersion string
+	// PipelineVersions lists registered records, sorted.
+	PipelineVersions []string
+	// PreviousPipelineVersion is the active version of the newest valid backup, the default
+	// rollback target; empty when no backup exists.
+	PreviousPipelineVersion string
+	// StateHash is the sha256 of the state file content.
+	StateHash string
+}
+
+// DescribeActiveVersion loads the distribution state at path.
+func DescribeActiveVersion(path string) (ActiveVersionState, error) {
+	path = strings.TrimSpace(path)
+	adapter, stateHash, err := loadStateWithHash(path)
+	if err != nil {
+		return ActiveVersionState{}, err
+	}
+	versions := make([]string, 0, len(adapter.artifact.Registry.Records))
+	for version := range adapter.artifact.Registry.Records {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	state := ActiveVersionState{
+		Path:                  path,
+		ActivePipelineVersion: strings.TrimSpace(adapter.artifact.Rollout.DefaultPipelineVersion),
+		PipelineVersions:      versions,
+		StateHash:             stateHash,
+	}
+	if backup, err := newFileAdapter(FileAdapterConfig{Path: BackupPath(path, 1)}); err == nil {
+		state.PreviousPipelineVersion = strings.TrimSpace(backup.artifact.Rollout.DefaultPipelineVersion)
+	}
+	return state, nil
+}
+
+// ActiveVersionChange is a previewed switch of the active pipeline version.
+type ActiveVersionChange struct {
+	Path                string
+	FromPipelineVersion string
+	ToPipelineVersion   string
+	// StateHash is the hash of the state the change was planned against.
+	StateHash string
+	// ChangeHash binds the path, both versions, and StateHash; operators confirm it before the
+	// change is applied.
+	ChangeHash string
+}
+
+// ShortChangeHash returns the leading ShortChangeHashLength characters of ChangeHash.
+func (c ActiveVersionChange) ShortChangeHash() string {
+	return c.ChangeHash[:min(len(c.ChangeHash), ShortChangeHashLength)]
+}
+
+// Confirms reports whether an operator-typed hash matches the full or short change hash.
+func (c ActiveVersionChange) Confirms(typed string) bool {
+	typed = strings.ToLower(strings.TrimSpace(typed))
+	return c.ChangeHash != "" && (typed == c.ChangeHash || typed == c.ShortChangeHash())
+}
+
+// PlanActivePipelineVersion previews switching the active pipeline version at path without
+// writing anything.
+func PlanActivePipelineVersion(path string, pipelineVersion string) (ActiveVersionChange, error) {
+	path = strings.TrimSpace(path)
+	version := strings.TrimSpace(pipelineVersion)
+	adapter, stateHash, err := loadStateWithHash(path)
+	if err != nil {
+		return ActiveVersionChange{}, err
+	}
+	if _, ok := adapter.artifact.Registry.Records[version]; !ok {
+		return ActiveVersionChange{}, BackendError{Service: "registry", Code: ErrorCodeSnapshotMissing, Path: path, Cause: fmt.Errorf("missing record for pipeline_version=%s", version)}
+	}
+	from := strings.TrimSpace(adapter.artifact.Rollout.DefaultPipelineVersion)
+	if from == version {
+		return ActiveVersionChange{}, BackendError{Service: "rollout", Code: ErrorCodeInvalidConfig, Path: path, Cause: fmt.Errorf("pipeline_version=%s is already active", version)}
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{path, from, version, stateHash}, "\n")))
+	return ActiveVersionChange{
+		Path:                path,
+		FromPipelineVersion: from,
+		ToPipelineVersion:   version,
+		StateHash:           stateHash,
+		ChangeHash:          hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// ApplyActivePipelineVersionChange applies a previewed change and returns the previously
+// active version. It refuses with a snapshot_stale error when the state changed after the
+// preview, so an operator never confirms one change and applies another. The check and the
+// change are made under one LockStateFile.
+func ApplyActivePipelineVersionChange(change ActiveVersionChange) (string, error) {
+	unlock, err := LockStateFile(change.Path)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	_, stateHash, err := loadStateWithHash(change.Path)
+	if err != nil {
+		return "", err
+	}
+	if stateHash != change.StateHash {
+		return "", BackendError{Service: "distribution", Code: ErrorCodeSnapshotStale, Path: change.Path, Cause: fmt.Errorf("state changed since the change was planned; plan it again")}
+	}
+	return setActivePipelineVersion(change.Path, change.ToPipelineVersion)
+}
+
+func loadStateWithHash(path string) (fileAdapter, string, error) {
+	adapter, err := newFileAdapter(FileAdapterConfig{Path: path})
+	if err != nil {
+		return fileAdapter{}, "", err
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fileAdapter{}, "", BackendError{Service: "distribution", Code: ErrorCodeReadArtifact, Path: path, Cause: err}
+	}
+	sum := sha256.Sum256(raw)
+	return adapter, hex.EncodeToString(sum[:]), nil
+}
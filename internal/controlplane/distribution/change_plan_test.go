@@ -0,0 +1,74 @@
+package distribution
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPlanAndApplyActivePipelineVersionChange(t *testing.T) {
+	t.Parallel()
+
+	path := writeDistributionArtifact(t, stateFixture)
+	state, err := DescribeActiveVersion(path)
+	if err != nil {
+		t.Fatalf("unexpected describe error: %v", err)
+	}
+	if state.ActivePipelineVersion != "pipeline-v1" || strings.Join(state.PipelineVersions, ",") != "pipeline-v1,pipeline-v2,pipeline-v3" || state.PreviousPipelineVersion != "" {
+		t.Fatalf("unexpected initial state %+v", state)
+	}
+
+	change, err := PlanActivePipelineVersion(path, "pipeline-v3")
+	if err != nil {
+		t.Fatalf("unexpected plan error: %v", err)
+	}
+	if change.FromPipelineVersion != "pipeline-v1" || change.ToPipelineVersion != "pipeline-v3" || change.StateHash != state.StateHash || len(change.ChangeHash) != 64 {
+		t.Fatalf("unexpected change %+v", change)
+	}
+	again, err := PlanActivePipelineVersion(path, "pipeline-v3")
+	if err != nil || again.ChangeHash != change.ChangeHash {
+		t.Fatalf("expected deterministic change hash, got %+v err=%v", again, err)
+	}
+	if !change.Confirms(" "+strings.ToUpper(change.ShortChangeHash())+" ") || !change.Confirms(change.ChangeHash) || change.Confirms(change.ChangeHash[:6]) {
+		t.Fatalf("unexpected confirmation semantics for %s", change.ChangeHash)
+	}
+
+	previous, err := ApplyActivePipelineVersionChange(change)
+	if err != nil || previous != "pipeline-v1" {
+		t.Fatalf("expected apply to report pipeline-v1 as previous, got %q err=%v", previous, err)
+	}
+	state, err = DescribeActiveVersion(path)
+	if err != nil || state.ActivePipelineVersion != "pipeline-v3" || state.PreviousPipelineVersion != "pipeline-v1" {
+		t.Fatalf("expected pipeline-v3 active with pipeline-v1 rollback target, got %+v err=%v", state, err)
+	}
+
+	// Replaying the confirmed change against the new state is refused.
+	var backendErr BackendError
+	if _, err := ApplyActivePipelineVersionChange(change); !errors.As(err, &backendErr) || !backendErr.StaleSnapshot() {
+		t.Fatalf("expected stale state error, got %v", err)
+	}
+}
+
+func TestPlanActivePipelineVersionRejectsInvalidTargets(t *testing.T) {
+	t.Parallel()
+
+	path := writeDistributionArtifact(t, stateFixture)
+	tests := []struct {
+		name    string
+		path    string
+		version string
+	}{
+		{name: "missing state", path: path + ".missing", version: "pipeline-v2"},
+		{name: "unknown version", path: path, version: "pipeline-v9"},
+		{name: "already active", path: path, version: "pipeline-v1"},
+	}
+	for _, tc := range tests {
+		if _, err := PlanActivePipelineVersion(tc.path, tc.version); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+	if _, err := os.Stat(BackupPath(path, 1)); !os.IsNotExist(err) {
+		t.Fatalf("expected planning to write nothing, stat err=%v", err)
+	}
+}
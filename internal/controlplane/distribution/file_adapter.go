@@ -3,6 +3,7 @@ package distribution
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"strings"
 
@@ -16,11 +17,15 @@ import (
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/registry"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/rollout"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/routingview"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sharding"
 )
 
 const (
 	// EnvFileAdapterPath configures the file-backed CP distribution artifact path.
 	EnvFileAdapterPath = "RSPP_CP_DISTRIBUTION_PATH"
+	// EnvEnvironment names the deployment environment whose active pipeline version rollout
+	// resolution reads (see SetEnvironmentPipelineVersion).
+	EnvEnvironment = "RSPP_ENVIRONMENT"
 	// SchemaVersionV1 is the expected schema version for file-backed CP distribution artifacts.
 	SchemaVersionV1 = "cp-snapshot-distribution/v1"
 )
@@ -35,6 +40,7 @@ const (
 	ErrorCodeInvalidArtifact ErrorCode = "artifact_invalid"
 	ErrorCodeSnapshotMissing ErrorCode = "snapshot_missing"
 	ErrorCodeSnapshotStale   ErrorCode = "snapshot_stale"
+	ErrorCodeStateLocked     ErrorCode = "state_locked"
 )
 
 // BackendError is a deterministic file-backed CP distribution backend error.
@@ -74,6 +80,8 @@ func (e BackendError) StaleSnapshot() bool {
 // FileAdapterConfig configures a file-backed CP snapshot distribution adapter.
 type FileAdapterConfig struct {
 	Path string
+	// Environment scopes rollout resolution to the environment's active pipeline version.
+	Environment string
 }
 
 // FileAdapterConfigFromEnv resolves adapter config from environment.
@@ -82,7 +90,11 @@ func FileAdapterConfigFromEnv() (FileAdapterConfig, error) {
 	if path == "" {
 		return FileAdapterConfig{}, BackendError{Service: "distribution", Code: ErrorCodeInvalidConfig, Path: EnvFileAdapterPath}
 	}
-	return FileAdapterConfig{Path: path}, nil
+	return FileAdapterConfig{Path: path, Environment: environmentFromEnv()}, nil
+}
+
+func environmentFromEnv() string {
+	return strings.ToLower(strings.TrimSpace(os.Getenv(EnvEnvironment)))
 }
 
 // ServiceBackends groups concrete CP backends loaded from distribution artifacts.
@@ -95,6 +107,7 @@ type ServiceBackends struct {
 	GraphCompiler  graphcompiler.Backend
 	Admission      admission.Backend
 	Lease          lease.Backend
+	Sharding       sharding.Backend
 }
 
 // NewFileBackendsFromEnv builds CP service backends from an env-configured artifact.
@@ -112,7 +125,9 @@ func NewFileBackends(cfg FileAdapterConfig) (ServiceBackends, error) {
 	if err != nil {
 		return ServiceBackends{}, err
 	}
-	return serviceBackendsFromAdapter(adapter), nil
+	backends := serviceBackendsFromAdapter(adapter)
+	backends.Rollout = fileRolloutBackend{adapter: adapter, environment: strings.TrimSpace(cfg.Environment)}
+	return backends, nil
 }
 
 type fileAdapter struct {
@@ -152,12 +167,14 @@ func serviceBackendsFromAdapter(adapter fileAdapter) ServiceBackends {
 		GraphCompiler:  fileGraphCompilerBackend{adapter: adapter},
 		Admission:      fileAdmissionBackend{adapter: adapter},
 		Lease:          fileLeaseBackend{adapter: adapter},
+		Sharding:       fileShardingBackend{adapter: adapter},
 	}
 }
 
 type fileArtifact struct {
 	SchemaVersion  string                    `json:"schema_version"`
 	Stale          bool                      `json:"stale,omitempty"`
+	PublishedAtMS  int64                     `json:"published_at_ms,omitempty"`
 	Registry       fileRegistrySection       `json:"registry"`
 	Rollout        fileRolloutSection        `json:"rollout"`
 	RoutingView    fileRoutingSection        `json:"routing_view"`
@@ -166,7 +183,9 @@ type fileArtifact struct {
 	GraphCompiler  fileGraphCompilerSection  `json:"graph_compiler"`
 	Admission      fileAdmissionSection      `json:"admission"`
 	Lease          fileLeaseSection          `json:"lease"`
+	Sharding       fileShardingSection       `json:"sharding,omitempty"`
 	Retention      fileRetentionSection      `json:"retention,omitempty"`
+	SLA            fileSLASection            `json:"sla,omitempty"`
 }
 
 func (a fileArtifact) validate(path string) error {
@@ -187,9 +206,13 @@ type fileRegistrySection struct {
 }
 
 type filePipelineRecord struct {
-	PipelineVersion    string `json:"pipeline_version,omitempty"`
-	GraphDefinitionRef string `json:"graph_definition_ref,omitempty"`
-	ExecutionProfile   string `json:"execution_profile,omitempty"`
+	PipelineVersion    string                         `json:"pipeline_version,omitempty"`
+	GraphDefinitionRef string                         `json:"graph_definition_ref,omitempty"`
+	ExecutionProfile   string                         `json:"execution_profile,omitempty"`
+	STTEndpointing     *controlplane.STTEndpointing   `json:"stt_endpointing,omitempty"`
+	AudioEnhancement   *controlplane.AudioEnhancement `json:"audio_enhancement,omitempty"`
+	Summarization      *controlplane.Summarization    `json:"summarization,omitempty"`
+	SpecHash           string                         `json:"spec_hash,omitempty"`
 }
 
 type fileRolloutSection struct {
@@ -197,12 +220,15 @@ type fileRolloutSection struct {
 	DefaultPipelineVersion    string            `json:"default_pipeline_version,omitempty"`
 	VersionResolutionSnapshot string            `json:"version_resolution_snapshot,omitempty"`
 	ByRequestedVersion        map[string]string `json:"by_requested_version,omitempty"`
+	// ByEnvironment overrides DefaultPipelineVersion per deployment environment.
+	ByEnvironment map[string]string `json:"by_environment,omitempty"`
 }
 
 type fileRoutingSection struct {
-	Stale      bool                           `json:"stale,omitempty"`
-	Default    fileRoutingSnapshot            `json:"default,omitempty"`
-	ByPipeline map[string]fileRoutingSnapshot `json:"by_pipeline,omitempty"`
+	Stale         bool                           `json:"stale,omitempty"`
+	PublishedAtMS int64                          `json:"published_at_ms,omitempty"`
+	Default       fileRoutingSnapshot            `json:"default,omitempty"`
+	ByPipeline    map[string]fileRoutingSnapshot `json:"by_pipeline,omitempty"`
 }
 
 type fileRoutingSnapshot struct {
@@ -218,8 +244,9 @@ type filePolicySection struct {
 }
 
 type filePolicyOutput struct {
-	PolicyResolutionSnapshot string   `json:"policy_resolution_snapshot,omitempty"`
-	AllowedAdaptiveActions   []string `json:"allowed_adaptive_actions,omitempty"`
+	PolicyResolutionSnapshot string                    `json:"policy_resolution_snapshot,omitempty"`
+	AllowedAdaptiveActions   []string                  `json:"allowed_adaptive_actions,omitempty"`
+	Budgets                  controlplane.BudgetPolicy `json:"budgets,omitempty"`
 }
 
 type fileProviderHealthSection struct {
@@ -245,9 +272,10 @@ type fileGraphCompilerOutput struct {
 }
 
 type fileAdmissionSection struct {
-	Stale      bool                           `json:"stale,omitempty"`
-	Default    fileAdmissionOutput            `json:"default,omitempty"`
-	ByPipeline map[string]fileAdmissionOutput `json:"by_pipeline,omitempty"`
+	Stale         bool                           `json:"stale,omitempty"`
+	PublishedAtMS int64                          `json:"published_at_ms,omitempty"`
+	Default       fileAdmissionOutput            `json:"default,omitempty"`
+	ByPipeline    map[string]fileAdmissionOutput `json:"by_pipeline,omitempty"`
 }
 
 type fileAdmissionOutput struct {
@@ -271,8 +299,19 @@ type fileLeaseOutput struct {
 	Reason                  string `json:"reason,omitempty"`
 }
 
+type fileShardingSection struct {
+	Stale            bool     `json:"stale,omitempty"`
+	ShardMapSnapshot string   `json:"shard_map_snapshot,omitempty"`
+	AuthorityEpoch   int64    `json:"authority_epoch,omitempty"`
+	Workers          []string `json:"workers,omitempty"`
+	VirtualNodes     int      `json:"virtual_nodes,omitempty"`
+	// Endpoints maps worker ids to the runtime endpoints route resolution returns.
+	Endpoints map[string]string `json:"endpoints,omitempty"`
+}
+
 type fileRetentionSection struct {
 	Stale          bool                           `json:"stale,omitempty"`
+	PublishedAtMS  int64                          `json:"published_at_ms,omitempty"`
 	DefaultPolicy  *fileRetentionPolicy           `json:"default_policy,omitempty"`
 	TenantPolicies map[string]fileRetentionPolicy `json:"tenant_policies,omitempty"`
 }
@@ -285,6 +324,13 @@ type fileRetentionPolicy struct {
 	MaxRetentionByClassMS map[eventabi.PayloadClass]int64 `json:"max_retention_by_class_ms,omitempty"`
 }
 
+type fileSLASection struct {
+	Stale         bool                            `json:"stale,omitempty"`
+	PublishedAtMS int64                           `json:"published_at_ms,omitempty"`
+	DefaultTier   *controlplane.SLATier           `json:"default_tier,omitempty"`
+	TenantTiers   map[string]controlplane.SLATier `json:"tenant_tiers,omitempty"`
+}
+
 type fileRegistryBackend struct {
 	adapter fileAdapter
 }
@@ -311,11 +357,17 @@ func (b fileRegistryBackend) ResolvePipelineRecord(pipelineVersion string) (regi
 		PipelineVersion:    record.PipelineVersion,
 		GraphDefinitionRef: record.GraphDefinitionRef,
 		ExecutionProfile:   record.ExecutionProfile,
+		STTEndpointing:     record.STTEndpointing,
+		AudioEnhancement:   record.AudioEnhancement,
+		Summarization:      record.Summarization,
+		SpecHash:           record.SpecHash,
 	}, nil
 }
 
 type fileRolloutBackend struct {
 	adapter fileAdapter
+	// environment is resolved when the input names none.
+	environment string
 }
 
 func (b fileRolloutBackend) ResolvePipelineVersion(in rollout.ResolveVersionInput) (rollout.ResolveVersionOutput, error) {
@@ -328,7 +380,11 @@ func (b fileRolloutBackend) ResolvePipelineVersion(in rollout.ResolveVersionInpu
 		version = strings.TrimSpace(b.adapter.artifact.Rollout.ByRequestedVersion[req])
 	}
 	if version == "" {
-		version = strings.TrimSpace(b.adapter.artifact.Rollout.DefaultPipelineVersion)
+		environment := in.Environment
+		if strings.TrimSpace(environment) == "" {
+			environment = b.environment
+		}
+		version = b.adapter.artifact.Rollout.activeVersion(environment)
 	}
 	if version == "" {
 		return rollout.ResolveVersionOutput{}, BackendError{Service: "rollout", Code: ErrorCodeSnapshotMissing, Path: b.adapter.path, Cause: fmt.Errorf("missing rollout pipeline version")}
@@ -340,6 +396,14 @@ func (b fileRolloutBackend) ResolvePipelineVersion(in rollout.ResolveVersionInpu
 	}, nil
 }
 
+// activeVersion returns the environment's active pipeline version, falling back to the default.
+func (s fileRolloutSection) activeVersion(environment string) string {
+	if version := strings.TrimSpace(s.ByEnvironment[strings.ToLower(strings.TrimSpace(environment))]); version != "" {
+		return version
+	}
+	return strings.TrimSpace(s.DefaultPipelineVersion)
+}
+
 type fileRoutingBackend struct {
 	adapter fileAdapter
 }
@@ -388,6 +452,7 @@ func (b filePolicyBackend) Evaluate(in policy.Input) (policy.Output, error) {
 	return policy.Output{
 		PolicyResolutionSnapshot: out.PolicyResolutionSnapshot,
 		AllowedAdaptiveActions:   append([]string(nil), out.AllowedAdaptiveActions...),
+		Budgets:                  out.Budgets,
 	}, nil
 }
 
@@ -499,6 +564,36 @@ func (b fileLeaseBackend) Resolve(in lease.Input) (lease.Output, error) {
 	}, nil
 }
 
+type fileShardingBackend struct {
+	adapter fileAdapter
+}
+
+func (b fileShardingBackend) GetShardMap(_ sharding.Input) (sharding.ShardMap, error) {
+	if b.adapter.artifact.Stale || b.adapter.artifact.Sharding.Stale {
+		return sharding.ShardMap{}, BackendError{Service: "sharding", Code: ErrorCodeSnapshotStale, Path: b.adapter.path}
+	}
+
+	section := b.adapter.artifact.Sharding
+	if len(section.Workers) == 0 {
+		return sharding.ShardMap{}, BackendError{Service: "sharding", Code: ErrorCodeSnapshotMissing, Path: b.adapter.path, Cause: fmt.Errorf("missing shard map")}
+	}
+	shardMap := section.shardMap()
+	if err := shardMap.Validate(); err != nil {
+		return sharding.ShardMap{}, BackendError{Service: "sharding", Code: ErrorCodeInvalidArtifact, Path: b.adapter.path, Cause: err}
+	}
+	return shardMap, nil
+}
+
+func (s fileShardingSection) shardMap() sharding.ShardMap {
+	return sharding.ShardMap{
+		ShardMapSnapshot: s.ShardMapSnapshot,
+		AuthorityEpoch:   s.AuthorityEpoch,
+		Workers:          append([]string(nil), s.Workers...),
+		VirtualNodes:     s.VirtualNodes,
+		Endpoints:        maps.Clone(s.Endpoints),
+	}
+}
+
 func cloneBoolPointer(v *bool) *bool {
 	if v == nil {
 		return nil
@@ -14,6 +14,7 @@ import (
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/providerhealth"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/rollout"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/routingview"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sharding"
 )
 
 func TestFileAdapterConfigFromEnvRequiresPath(t *testing.T) {
@@ -43,7 +44,10 @@ func TestNewFileBackendsResolvesAllServices(t *testing.T) {
       "pipeline-file": {
         "pipeline_version": "pipeline-file",
         "graph_definition_ref": "graph/file",
-        "execution_profile": "simple"
+        "execution_profile": "simple",
+        "stt_endpointing": {"silence_duration_ms": 700, "min_utterance_ms": 150, "max_utterance_ms": 12000, "no_speech_timeout_ms": 6000},
+        "audio_enhancement": {"enabled": true, "engine": "rnnoise", "quality": "low"},
+        "summarization": {"interval_turns": 4, "provider_id": "llm-file", "max_summary_tokens": 128}
       }
     }
   },
@@ -64,7 +68,8 @@ func TestNewFileBackendsResolvesAllServices(t *testing.T) {
   "policy": {
     "default": {
       "policy_resolution_snapshot": "policy-resolution/file",
-      "allowed_adaptive_actions": ["fallback", "retry"]
+      "allowed_adaptive_actions": ["fallback", "retry"],
+      "budgets": {"default": {"max_tokens_per_turn": 4000}, "by_tenant": {"tenant-a": {"max_session_spend_usd": 0.25}}}
     }
   },
   "provider_health": {
@@ -94,6 +99,11 @@ func TestNewFileBackendsResolvesAllServices(t *testing.T) {
       "authority_authorized": true,
       "reason": "lease_authorized"
     }
+  },
+  "sharding": {
+    "shard_map_snapshot": "shard-map/file",
+    "authority_epoch": 9,
+    "workers": ["runtime-a", "runtime-b"]
   }
 }`)
 
@@ -115,6 +125,15 @@ func TestNewFileBackendsResolvesAllServices(t *testing.T) {
 	if record.GraphDefinitionRef != "graph/file" || record.ExecutionProfile != "simple" {
 		t.Fatalf("unexpected registry record: %+v", record)
 	}
+	if record.STTEndpointing == nil || record.STTEndpointing.SilenceDurationMS != 700 || record.STTEndpointing.NoSpeechTimeoutMS != 6000 {
+		t.Fatalf("expected per-version stt endpointing, got %+v", record.STTEndpointing)
+	}
+	if record.AudioEnhancement == nil || !record.AudioEnhancement.Enabled || record.AudioEnhancement.Engine != "rnnoise" {
+		t.Fatalf("expected per-version audio enhancement, got %+v", record.AudioEnhancement)
+	}
+	if record.Summarization == nil || record.Summarization.IntervalTurns != 4 || record.Summarization.ProviderID != "llm-file" {
+		t.Fatalf("expected per-version summarization, got %+v", record.Summarization)
+	}
 
 	versionOut, err := backends.Rollout.ResolvePipelineVersion(rollout.ResolveVersionInput{
 		SessionID:                "sess-file-1",
@@ -142,6 +161,9 @@ func TestNewFileBackendsResolvesAllServices(t *testing.T) {
 	if policyOut.PolicyResolutionSnapshot != "policy-resolution/file" || len(policyOut.AllowedAdaptiveActions) != 2 {
 		t.Fatalf("unexpected policy output: %+v", policyOut)
 	}
+	if policyOut.Budgets.For("tenant-b").MaxTokensPerTurn != 4000 || policyOut.Budgets.For("tenant-a").MaxSessionSpendUSD != 0.25 {
+		t.Fatalf("expected policy tenant budgets, got %+v", policyOut.Budgets)
+	}
 
 	providerHealthOut, err := backends.ProviderHealth.GetSnapshot(providerhealth.Input{Scope: "sess-file-1", PipelineVersion: "pipeline-file"})
 	if err != nil {
@@ -189,6 +211,14 @@ func TestNewFileBackendsResolvesAllServices(t *testing.T) {
 	if leaseOut.AuthorityEpochValid == nil || !*leaseOut.AuthorityEpochValid || leaseOut.AuthorityAuthorized == nil || !*leaseOut.AuthorityAuthorized {
 		t.Fatalf("expected lease authority booleans to be true, got %+v", leaseOut)
 	}
+
+	shardMap, err := backends.Sharding.GetShardMap(sharding.Input{SessionID: "sess-file-1", RequestedAuthorityEpoch: 9})
+	if err != nil {
+		t.Fatalf("sharding resolve: %v", err)
+	}
+	if shardMap.ShardMapSnapshot != "shard-map/file" || shardMap.AuthorityEpoch != 9 || len(shardMap.Workers) != 2 {
+		t.Fatalf("unexpected shard map: %+v", shardMap)
+	}
 }
 
 func TestNewFileBackendsFromEnv(t *testing.T) {
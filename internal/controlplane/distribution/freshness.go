@@ -0,0 +1,131 @@
+package distribution
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// SnapshotKind identifies a CP snapshot tracked for runtime freshness.
+type SnapshotKind string
+
+const (
+	SnapshotRoutingView SnapshotKind = "routing_view"
+	SnapshotAdmission   SnapshotKind = "admission"
+	SnapshotRetention   SnapshotKind = "retention"
+)
+
+// TrackedSnapshotKinds lists snapshot kinds in deterministic observation order.
+func TrackedSnapshotKinds() []SnapshotKind {
+	return []SnapshotKind{SnapshotRoutingView, SnapshotAdmission, SnapshotRetention}
+}
+
+// SnapshotObservation captures the publish time of one CP snapshot as seen by the runtime.
+// PublishedAtMS prefers the section published_at_ms, then the artifact published_at_ms, then
+// the time the runtime loaded the artifact (file mtime or HTTP fetch time).
+type SnapshotObservation struct {
+	Kind          SnapshotKind `json:"kind"`
+	Source        string       `json:"source"`
+	PublishedAtMS int64        `json:"published_at_ms"`
+	MarkedStale   bool         `json:"marked_stale,omitempty"`
+}
+
+// SnapshotObserver reads the tracked CP snapshots.
+type SnapshotObserver interface {
+	ObserveSnapshots() ([]SnapshotObservation, error)
+}
+
+// NewSnapshotObserverFromEnv resolves an observer over the env-configured CP distribution.
+// HTTP observers keep one cached provider, so repeated observations honor cache ttl and
+// bounded stale-serving like runtime backends do.
+func NewSnapshotObserverFromEnv() (SnapshotObserver, error) {
+	if strings.TrimSpace(os.Getenv(EnvHTTPAdapterURLs)) != "" || strings.TrimSpace(os.Getenv(EnvHTTPAdapterURL)) != "" {
+		cfg, err := HTTPAdapterConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		return NewHTTPSnapshotObserver(cfg)
+	}
+	cfg, err := FileAdapterConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return FileSnapshotObserver{Path: cfg.Path}, nil
+}
+
+// FileSnapshotObserver re-reads a file-backed CP distribution artifact on every observation.
+type FileSnapshotObserver struct {
+	Path string
+}
+
+// ObserveSnapshots reads the artifact and reports tracked snapshot publish times.
+func (o FileSnapshotObserver) ObserveSnapshots() ([]SnapshotObservation, error) {
+	adapter, err := newFileAdapter(FileAdapterConfig{Path: o.Path})
+	if err != nil {
+		return nil, err
+	}
+	loadedAtMS := time.Now().UnixMilli()
+	if info, err := os.Stat(adapter.path); err == nil {
+		loadedAtMS = info.ModTime().UnixMilli()
+	}
+	return observeArtifact(adapter.path, adapter.artifact, loadedAtMS), nil
+}
+
+// HTTPSnapshotObserver observes snapshots through a cached HTTP snapshot provider.
+type HTTPSnapshotObserver struct {
+	provider *httpSnapshotProvider
+}
+
+// NewHTTPSnapshotObserver builds an observer over HTTP snapshot distribution endpoints.
+func NewHTTPSnapshotObserver(cfg HTTPAdapterConfig) (*HTTPSnapshotObserver, error) {
+	provider, err := newHTTPSnapshotProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &HTTPSnapshotObserver{provider: provider}, nil
+}
+
+// ObserveSnapshots fetches (or reuses) the snapshot and reports tracked publish times.
+func (o *HTTPSnapshotObserver) ObserveSnapshots() ([]SnapshotObservation, error) {
+	adapter, err := o.provider.current()
+	if err != nil {
+		return nil, err
+	}
+	o.provider.mu.Lock()
+	fetchedAtMS := o.provider.cache.fetchedAt.UnixMilli()
+	o.provider.mu.Unlock()
+	return observeArtifact(adapter.path, adapter.artifact, fetchedAtMS), nil
+}
+
+func observeArtifact(source string, artifact fileArtifact, loadedAtMS int64) []SnapshotObservation {
+	publishedAt := func(sectionMS int64) int64 {
+		switch {
+		case sectionMS > 0:
+			return sectionMS
+		case artifact.PublishedAtMS > 0:
+			return artifact.PublishedAtMS
+		default:
+			return loadedAtMS
+		}
+	}
+	return []SnapshotObservation{
+		{
+			Kind:          SnapshotRoutingView,
+			Source:        source,
+			PublishedAtMS: publishedAt(artifact.RoutingView.PublishedAtMS),
+			MarkedStale:   artifact.Stale || artifact.RoutingView.Stale,
+		},
+		{
+			Kind:          SnapshotAdmission,
+			Source:        source,
+			PublishedAtMS: publishedAt(artifact.Admission.PublishedAtMS),
+			MarkedStale:   artifact.Stale || artifact.Admission.Stale,
+		},
+		{
+			Kind:          SnapshotRetention,
+			Source:        source,
+			PublishedAtMS: publishedAt(artifact.Retention.PublishedAtMS),
+			MarkedStale:   artifact.Stale || artifact.Retention.Stale,
+		},
+	}
+}
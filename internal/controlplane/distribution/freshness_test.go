@@ -0,0 +1,101 @@
+package distribution
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestFileSnapshotObserverPublishTimePrecedence(t *testing.T) {
+	t.Parallel()
+
+	path := writeDistributionArtifact(t, `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "published_at_ms": 1000,
+  "routing_view": {"published_at_ms": 2000},
+  "admission": {"stale": true},
+  "retention": {}
+}`)
+	modTime := time.UnixMilli(5000)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("unexpected chtimes error: %v", err)
+	}
+
+	observations, err := FileSnapshotObserver{Path: path}.ObserveSnapshots()
+	if err != nil {
+		t.Fatalf("unexpected observe error: %v", err)
+	}
+	expected := map[SnapshotKind]SnapshotObservation{
+		SnapshotRoutingView: {Kind: SnapshotRoutingView, Source: path, PublishedAtMS: 2000},
+		SnapshotAdmission:   {Kind: SnapshotAdmission, Source: path, PublishedAtMS: 1000, MarkedStale: true},
+		SnapshotRetention:   {Kind: SnapshotRetention, Source: path, PublishedAtMS: 1000},
+	}
+	if len(observations) != len(expected) {
+		t.Fatalf("expected %d observations, got %+v", len(expected), observations)
+	}
+	for _, observation := range observations {
+		if observation != expected[observation.Kind] {
+			t.Fatalf("expected %+v, got %+v", expected[observation.Kind], observation)
+		}
+	}
+
+	unstamped := writeDistributionArtifact(t, `{"schema_version": "cp-snapshot-distribution/v1"}`)
+	if err := os.Chtimes(unstamped, modTime, modTime); err != nil {
+		t.Fatalf("unexpected chtimes error: %v", err)
+	}
+	observations, err = FileSnapshotObserver{Path: unstamped}.ObserveSnapshots()
+	if err != nil {
+		t.Fatalf("unexpected observe error: %v", err)
+	}
+	if observations[0].PublishedAtMS != 5000 {
+		t.Fatalf("expected file mtime fallback, got %+v", observations[0])
+	}
+}
+
+func TestHTTPSnapshotObserverReusesCachedFetchTime(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock(time.UnixMilli(10_000).UTC())
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		if requests > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"schema_version":"cp-snapshot-distribution/v1","retention":{"published_at_ms":4000}}`))
+	}))
+	defer server.Close()
+
+	observer, err := NewHTTPSnapshotObserver(HTTPAdapterConfig{
+		URLs:             []string{server.URL},
+		RetryMaxAttempts: 1,
+		CacheTTL:         time.Second,
+		MaxStaleness:     5 * time.Second,
+		Now:              clock.Now,
+		Sleep:            func(time.Duration) {},
+	})
+	if err != nil {
+		t.Fatalf("unexpected observer error: %v", err)
+	}
+	if _, err := observer.ObserveSnapshots(); err != nil {
+		t.Fatalf("unexpected observe error: %v", err)
+	}
+
+	clock.Advance(3 * time.Second)
+	observations, err := observer.ObserveSnapshots()
+	if err != nil {
+		t.Fatalf("expected stale-serving observation, got %v", err)
+	}
+	if observations[0].PublishedAtMS != 10_000 || observations[2].PublishedAtMS != 4000 {
+		t.Fatalf("expected original fetch time and retention publish time, got %+v", observations)
+	}
+
+	clock.Advance(10 * time.Second)
+	if _, err := observer.ObserveSnapshots(); err == nil {
+		t.Fatalf("expected observe failure beyond stale-serving bound")
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/registry"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/rollout"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/routingview"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sharding"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/backoff"
 )
 
 const (
@@ -40,6 +42,8 @@ const (
 	EnvHTTPAdapterRetryBackoffMS = "RSPP_CP_DISTRIBUTION_HTTP_RETRY_BACKOFF_MS"
 	// EnvHTTPAdapterRetryMaxBackoffMS configures maximum retry backoff in milliseconds.
 	EnvHTTPAdapterRetryMaxBackoffMS = "RSPP_CP_DISTRIBUTION_HTTP_RETRY_MAX_BACKOFF_MS"
+	// EnvHTTPAdapterRetryJitter selects retry jitter mode (none|decorrelated).
+	EnvHTTPAdapterRetryJitter = "RSPP_CP_DISTRIBUTION_HTTP_RETRY_JITTER"
 	// EnvHTTPAdapterCacheTTLMS configures cache ttl in milliseconds.
 	EnvHTTPAdapterCacheTTLMS = "RSPP_CP_DISTRIBUTION_HTTP_CACHE_TTL_MS"
 	// EnvHTTPAdapterMaxStalenessMS configures max stale-serving window beyond ttl in milliseconds.
@@ -61,8 +65,10 @@ const (
 
 // HTTPAdapterConfig configures a service-client HTTP CP snapshot distribution adapter.
 type HTTPAdapterConfig struct {
-	URL             string
-	URLs            []string
+	URL  string
+	URLs []string
+	// Environment scopes rollout resolution to the environment's active pipeline version.
+	Environment     string
 	Timeout         time.Duration
 	Client          *http.Client
 	AuthBearerToken string
@@ -71,6 +77,7 @@ type HTTPAdapterConfig struct {
 	RetryMaxAttempts int
 	RetryBackoff     time.Duration
 	RetryMaxBackoff  time.Duration
+	RetryJitter      backoff.Jitter
 	CacheTTL         time.Duration
 	MaxStaleness     time.Duration
 
@@ -108,6 +115,10 @@ func HTTPAdapterConfigFromEnv() (HTTPAdapterConfig, error) {
 	if err != nil {
 		return HTTPAdapterConfig{}, err
 	}
+	retryJitter := backoff.Jitter(strings.TrimSpace(os.Getenv(EnvHTTPAdapterRetryJitter)))
+	if err := (backoff.Policy{Base: retryBackoff, Max: max(retryMaxBackoff, retryBackoff), Jitter: retryJitter}).Validate(); err != nil {
+		return HTTPAdapterConfig{}, BackendError{Service: "distribution", Code: ErrorCodeInvalidConfig, Path: EnvHTTPAdapterRetryJitter, Cause: err}
+	}
 	cacheTTL, err := parsePositiveDurationEnvMS(EnvHTTPAdapterCacheTTLMS, defaultHTTPAdapterCacheTTLMS)
 	if err != nil {
 		return HTTPAdapterConfig{}, err
@@ -126,8 +137,10 @@ func HTTPAdapterConfigFromEnv() (HTTPAdapterConfig, error) {
 		RetryMaxAttempts: retryMaxAttempts,
 		RetryBackoff:     retryBackoff,
 		RetryMaxBackoff:  retryMaxBackoff,
+		RetryJitter:      retryJitter,
 		CacheTTL:         cacheTTL,
 		MaxStaleness:     maxStaleness,
+		Environment:      environmentFromEnv(),
 	}, nil
 }
 
@@ -160,6 +173,7 @@ func NewHTTPBackends(cfg HTTPAdapterConfig) (ServiceBackends, error) {
 		GraphCompiler:  httpGraphCompilerBackend{provider: provider},
 		Admission:      httpAdmissionBackend{provider: provider},
 		Lease:          httpLeaseBackend{provider: provider},
+		Sharding:       httpShardingBackend{provider: provider},
 	}, nil
 }
 
@@ -233,6 +247,7 @@ func (p *httpSnapshotProvider) fetchSnapshot() (fileAdapter, error) {
 
 func (p *httpSnapshotProvider) fetchFromEndpoint(endpoint string) (fileAdapter, error) {
 	var lastErr error
+	delays := p.retryPolicy().NewSequence(endpoint)
 	for attempt := 1; attempt <= p.cfg.RetryMaxAttempts; attempt++ {
 		adapter, err := p.fetchOnce(endpoint)
 		if err == nil {
@@ -242,7 +257,11 @@ func (p *httpSnapshotProvider) fetchFromEndpoint(endpoint string) (fileAdapter,
 		if attempt == p.cfg.RetryMaxAttempts || !shouldRetryEndpointError(err) {
 			break
 		}
-		p.cfg.Sleep(retryBackoffDuration(p.cfg.RetryBackoff, p.cfg.RetryMaxBackoff, attempt))
+		delay, ok := delays.Next()
+		if !ok {
+			break
+		}
+		p.cfg.Sleep(delay)
 	}
 	if lastErr == nil {
 		return fileAdapter{}, BackendError{Service: "distribution", Code: ErrorCodeReadArtifact, Path: endpoint, Cause: fmt.Errorf("fetch failed")}
@@ -370,28 +389,12 @@ func shouldRetryEndpointError(err error) bool {
 	return true
 }
 
-func retryBackoffDuration(base time.Duration, max time.Duration, attempt int) time.Duration {
-	if base <= 0 {
-		base = time.Duration(defaultHTTPAdapterRetryBackoffMS) * time.Millisecond
-	}
-	if max < base {
-		max = base
-	}
-	if attempt < 1 {
-		return base
-	}
-
-	delay := base
-	for i := 1; i < attempt; i++ {
-		if delay >= max/2 {
-			return max
-		}
-		delay *= 2
-	}
-	if delay > max {
-		return max
-	}
-	return delay
+func (p *httpSnapshotProvider) retryPolicy() backoff.Policy {
+	return backoff.Policy{
+		Base:   p.cfg.RetryBackoff,
+		Max:    p.cfg.RetryMaxBackoff,
+		Jitter: p.cfg.RetryJitter,
+	}.Normalize(time.Duration(defaultHTTPAdapterRetryBackoffMS) * time.Millisecond)
 }
 
 func normalizeHTTPAdapterConfig(cfg HTTPAdapterConfig) (HTTPAdapterConfig, error) {
@@ -468,6 +471,7 @@ func normalizeHTTPAdapterConfig(cfg HTTPAdapterConfig) (HTTPAdapterConfig, error
 		RetryMaxAttempts: retryMaxAttempts,
 		RetryBackoff:     retryBackoff,
 		RetryMaxBackoff:  retryMaxBackoff,
+		RetryJitter:      cfg.RetryJitter,
 		CacheTTL:         cacheTTL,
 		MaxStaleness:     maxStaleness,
 		Now:              nowFn,
@@ -557,7 +561,8 @@ func artifactHasAnyStaleSection(artifact fileArtifact) bool {
 		artifact.ProviderHealth.Stale ||
 		artifact.GraphCompiler.Stale ||
 		artifact.Admission.Stale ||
-		artifact.Lease.Stale
+		artifact.Lease.Stale ||
+		artifact.Sharding.Stale
 }
 
 type httpStatusError struct {
@@ -600,7 +605,7 @@ func (b httpRolloutBackend) ResolvePipelineVersion(in rollout.ResolveVersionInpu
 	if err != nil {
 		return rollout.ResolveVersionOutput{}, err
 	}
-	return fileRolloutBackend{adapter: adapter}.ResolvePipelineVersion(in)
+	return fileRolloutBackend{adapter: adapter, environment: strings.TrimSpace(b.provider.cfg.Environment)}.ResolvePipelineVersion(in)
 }
 
 type httpRoutingBackend struct {
@@ -674,3 +679,15 @@ func (b httpLeaseBackend) Resolve(in lease.Input) (lease.Output, error) {
 	}
 	return fileLeaseBackend{adapter: adapter}.Resolve(in)
 }
+
+type httpShardingBackend struct {
+	provider *httpSnapshotProvider
+}
+
+func (b httpShardingBackend) GetShardMap(in sharding.Input) (sharding.ShardMap, error) {
+	adapter, err := b.provider.current()
+	if err != nil {
+		return sharding.ShardMap{}, err
+	}
+	return fileShardingBackend{adapter: adapter}.GetShardMap(in)
+}
@@ -0,0 +1,122 @@
+package distribution
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PipelineRecordUpdate is a pipeline record published into a file-backed distribution state.
+type PipelineRecordUpdate struct {
+	PipelineVersion    string
+	GraphDefinitionRef string
+	ExecutionProfile   string
+	SpecHash           string
+}
+
+// PublishPipelineRecord upserts a registry record in the distribution state at path and, when
+// activate is set, makes it the registry and rollout default. It returns the previously active
+// rollout version. Record fields the update does not carry (for example stt_endpointing) and
+// all other sections are preserved, and the previous state is kept as a rotated backup. The
+// change is made under LockStateFile.
+func PublishPipelineRecord(path string, update PipelineRecordUpdate, activate bool) (string, error) {
+	path = strings.TrimSpace(path)
+	version := strings.TrimSpace(update.PipelineVersion)
+	if path == "" {
+		return "", BackendError{Service: "distribution", Code: ErrorCodeInvalidConfig, Path: "path"}
+	}
+	if version == "" {
+		return "", BackendError{Service: "registry", Code: ErrorCodeInvalidConfig, Path: path, Cause: fmt.Errorf("pipeline_version is required")}
+	}
+	if strings.TrimSpace(update.GraphDefinitionRef) == "" {
+		return "", BackendError{Service: "registry", Code: ErrorCodeInvalidConfig, Path: path, Cause: fmt.Errorf("graph_definition_ref is required")}
+	}
+
+	unlock, err := LockStateFile(path)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	adapter, err := newFileAdapter(FileAdapterConfig{Path: path})
+	if err != nil {
+		return "", err
+	}
+	previous := strings.TrimSpace(adapter.artifact.Rollout.DefaultPipelineVersion)
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", BackendError{Service: "distribution", Code: ErrorCodeReadArtifact, Path: path, Cause: err}
+	}
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return "", BackendError{Service: "distribution", Code: ErrorCodeDecodeArtifact, Path: path, Cause: err}
+	}
+	patched, err := upsertRegistryRecord(document["registry"], version, update)
+	if err != nil {
+		return "", BackendError{Service: "registry", Code: ErrorCodeDecodeArtifact, Path: path, Cause: err}
+	}
+	document["registry"] = patched
+	if activate {
+		for _, section := range []string{"registry", "rollout"} {
+			patched, err := setSectionDefaultVersion(document[section], version)
+			if err != nil {
+				return "", BackendError{Service: section, Code: ErrorCodeDecodeArtifact, Path: path, Cause: err}
+			}
+			document[section] = patched
+		}
+	}
+
+	out, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := WriteStateFile(path, out, DefaultStateBackups); err != nil {
+		return "", err
+	}
+	return previous, nil
+}
+
+func upsertRegistryRecord(raw json.RawMessage, version string, update PipelineRecordUpdate) (json.RawMessage, error) {
+	section := map[string]json.RawMessage{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &section); err != nil {
+			return nil, err
+		}
+	}
+	records := map[string]map[string]json.RawMessage{}
+	if existing := section["records"]; len(existing) > 0 {
+		if err := json.Unmarshal(existing, &records); err != nil {
+			return nil, err
+		}
+	}
+	record := records[version]
+	if record == nil {
+		record = map[string]json.RawMessage{}
+	}
+	fields := map[string]string{
+		"pipeline_version":     version,
+		"graph_definition_ref": strings.TrimSpace(update.GraphDefinitionRef),
+		"execution_profile":    strings.TrimSpace(update.ExecutionProfile),
+		"spec_hash":            strings.TrimSpace(update.SpecHash),
+	}
+	for key, value := range fields {
+		if value == "" {
+			// A republish without a spec or profile must not keep a stale one.
+			delete(record, key)
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		record[key] = encoded
+	}
+	records[version] = record
+	encoded, err := json.Marshal(records)
+	if err != nil {
+		return nil, err
+	}
+	section["records"] = encoded
+	return json.Marshal(section)
+}
@@ -0,0 +1,65 @@
+package distribution
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/routingview"
+)
+
+func TestPublishPipelineRecordUpsertsAndActivates(t *testing.T) {
+	t.Parallel()
+
+	path := writeDistributionArtifact(t, `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "registry": {
+    "default_pipeline_version": "pipeline-v1",
+    "records": {
+      "pipeline-v1": {"pipeline_version": "pipeline-v1", "graph_definition_ref": "graph/v1", "stt_endpointing": {"silence_ms": 700}}
+    }
+  },
+  "rollout": {"default_pipeline_version": "pipeline-v1"},
+  "routing_view": {"default": {"routing_view_snapshot": "routing-view/file"}}
+}`)
+
+	previous, err := PublishPipelineRecord(path, PipelineRecordUpdate{PipelineVersion: "pipeline-v2", GraphDefinitionRef: "graph/v2", ExecutionProfile: "simple", SpecHash: "sha256:" + strings.Repeat("a", 64)}, false)
+	if err != nil || previous != "pipeline-v1" {
+		t.Fatalf("expected inactive publish to report pipeline-v1 active, got %q err=%v", previous, err)
+	}
+	state, err := DescribeActiveVersion(path)
+	if err != nil || state.ActivePipelineVersion != "pipeline-v1" || strings.Join(state.PipelineVersions, ",") != "pipeline-v1,pipeline-v2" {
+		t.Fatalf("expected pipeline-v2 registered but not active, got %+v err=%v", state, err)
+	}
+
+	if _, err := PublishPipelineRecord(path, PipelineRecordUpdate{PipelineVersion: "pipeline-v1", GraphDefinitionRef: "graph/v1b"}, true); err != nil {
+		t.Fatalf("unexpected republish error: %v", err)
+	}
+	backends, err := NewFileBackends(FileAdapterConfig{Path: path})
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	record, err := backends.Registry.ResolvePipelineRecord("pipeline-v1")
+	if err != nil || record.GraphDefinitionRef != "graph/v1b" || record.STTEndpointing == nil {
+		t.Fatalf("expected republish to update the graph and keep stt_endpointing, got %+v err=%v", record, err)
+	}
+	record, err = backends.Registry.ResolvePipelineRecord("pipeline-v2")
+	if err != nil || record.SpecHash == "" || record.ExecutionProfile != "simple" {
+		t.Fatalf("expected pipeline-v2 record with spec hash, got %+v err=%v", record, err)
+	}
+	if snapshot, err := backends.RoutingView.GetSnapshot(routingview.Input{SessionID: "sess-1", PipelineVersion: "pipeline-v1"}); err != nil || snapshot.RoutingViewSnapshot != "routing-view/file" {
+		t.Fatalf("expected routing section preserved, got %+v err=%v", snapshot, err)
+	}
+
+	tests := []struct {
+		name   string
+		update PipelineRecordUpdate
+	}{
+		{name: "missing version", update: PipelineRecordUpdate{GraphDefinitionRef: "graph/v3"}},
+		{name: "missing graph", update: PipelineRecordUpdate{PipelineVersion: "pipeline-v3"}},
+	}
+	for _, tc := range tests {
+		if _, err := PublishPipelineRecord(path, tc.update, false); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package distribution
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sharding"
+)
+
+// DescribeShardMap returns the shard map published in the distribution state at path, or a
+// disabled map when none is published.
+func DescribeShardMap(path string) (sharding.ShardMap, error) {
+	adapter, err := newFileAdapter(FileAdapterConfig{Path: path})
+	if err != nil {
+		return sharding.ShardMap{}, err
+	}
+	return adapter.artifact.Sharding.shardMap(), nil
+}
+
+// PublishShardMap replaces the shard map in the distribution state at path with workers and
+// their endpoints; no workers disables sharding. The map is published at an authority epoch
+// past both the previous map's and every lease epoch, so runtimes fence turns that started
+// under the old ownership and re-resolve them. Other sections are preserved, the previous
+// state is kept as a rotated backup, and the change is made under LockStateFile.
+func PublishShardMap(path string, workers []string, endpoints map[string]string, virtualNodes int) (sharding.ShardMap, error) {
+	path = strings.TrimSpace(path)
+	unlock, err := LockStateFile(path)
+	if err != nil {
+		return sharding.ShardMap{}, err
+	}
+	defer unlock()
+	adapter, err := newFileAdapter(FileAdapterConfig{Path: path})
+	if err != nil {
+		return sharding.ShardMap{}, err
+	}
+	epoch := adapter.artifact.Sharding.AuthorityEpoch
+	leases := append([]fileLeaseOutput{adapter.artifact.Lease.Default}, slices.Collect(maps.Values(adapter.artifact.Lease.ByPipeline))...)
+	for _, lease := range leases {
+		if lease.AuthorityEpoch != nil {
+			epoch = max(epoch, *lease.AuthorityEpoch)
+		}
+	}
+	epoch++
+	shardMap := sharding.ShardMap{
+		ShardMapSnapshot: fmt.Sprintf("shard-map/epoch-%d", epoch),
+		AuthorityEpoch:   epoch,
+		Workers:          append([]string(nil), workers...),
+		VirtualNodes:     virtualNodes,
+		Endpoints:        maps.Clone(endpoints),
+	}
+	if err := shardMap.Validate(); err != nil {
+		return sharding.ShardMap{}, BackendError{Service: "sharding", Code: ErrorCodeInvalidConfig, Path: path, Cause: err}
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return sharding.ShardMap{}, BackendError{Service: "distribution", Code: ErrorCodeReadArtifact, Path: path, Cause: err}
+	}
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return sharding.ShardMap{}, BackendError{Service: "distribution", Code: ErrorCodeDecodeArtifact, Path: path, Cause: err}
+	}
+	section := fileShardingSection{
+		ShardMapSnapshot: shardMap.ShardMapSnapshot,
+		AuthorityEpoch:   shardMap.AuthorityEpoch,
+		Workers:          shardMap.Workers,
+		VirtualNodes:     shardMap.VirtualNodes,
+		Endpoints:        shardMap.Endpoints,
+	}
+	if document["sharding"], err = json.Marshal(section); err != nil {
+		return sharding.ShardMap{}, err
+	}
+	out, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return sharding.ShardMap{}, err
+	}
+	if err := WriteStateFile(path, out, DefaultStateBackups); err != nil {
+		return sharding.ShardMap{}, err
+	}
+	return shardMap, nil
+}
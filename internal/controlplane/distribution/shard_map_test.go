@@ -0,0 +1,54 @@
+package distribution
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sharding"
+)
+
+func TestPublishShardMapAdvancesAuthorityEpoch(t *testing.T) {
+	t.Parallel()
+
+	path := writeDistributionArtifact(t, `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "registry": {"records": {"pipeline-v1": {"pipeline_version": "pipeline-v1"}}},
+  "rollout": {"default_pipeline_version": "pipeline-v1"},
+  "routing_view": {"default": {"routing_view_snapshot": "routing-view/file", "admission_policy_snapshot": "admission-policy/file", "abi_compatibility_snapshot": "abi-compat/file"}},
+  "lease": {"default": {"lease_resolution_snapshot": "lease/file", "authority_epoch": 3}, "by_pipeline": {"pipeline-v1": {"authority_epoch": 5}}}
+}`)
+
+	if shardMap, err := DescribeShardMap(path); err != nil || shardMap.Enabled() {
+		t.Fatalf("expected no shard map before publishing, got %+v err=%v", shardMap, err)
+	}
+	endpoints := map[string]string{"runtime-a": "wss://runtime-a/ws", "runtime-b": "wss://runtime-b/ws"}
+	first, err := PublishShardMap(path, []string{"runtime-a", "runtime-b"}, endpoints, 16)
+	if err != nil || first.AuthorityEpoch != 6 || first.ShardMapSnapshot != "shard-map/epoch-6" {
+		t.Fatalf("expected the shard map published past lease epoch 5, got %+v err=%v", first, err)
+	}
+	second, err := PublishShardMap(path, []string{"runtime-a"}, map[string]string{"runtime-a": "wss://runtime-a/ws"}, 0)
+	if err != nil || second.AuthorityEpoch != 7 {
+		t.Fatalf("expected a shard map change to advance the epoch, got %+v err=%v", second, err)
+	}
+
+	backends, err := NewFileBackends(FileAdapterConfig{Path: path})
+	if err != nil {
+		t.Fatalf("unexpected backend error: %v", err)
+	}
+	resolved, err := backends.Sharding.GetShardMap(sharding.Input{SessionID: "sess-1"})
+	if err != nil || resolved.AuthorityEpoch != 7 || len(resolved.Workers) != 1 || resolved.Endpoints["runtime-a"] != "wss://runtime-a/ws" {
+		t.Fatalf("expected runtimes to resolve the republished map, got %+v err=%v", resolved, err)
+	}
+
+	if _, err := PublishShardMap(path, []string{"runtime-a", "runtime-a"}, nil, 0); err == nil {
+		t.Fatalf("expected duplicate workers to be rejected")
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if !strings.Contains(string(raw), "abi-compat/file") || !strings.Contains(string(raw), "shard-map/epoch-7") {
+		t.Fatalf("expected other sections preserved and the rejected map not written, got %s", raw)
+	}
+}
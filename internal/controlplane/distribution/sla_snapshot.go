@@ -0,0 +1,129 @@
+package distribution
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+)
+
+// SLASnapshotSource describes the CP distribution backend used for tenant SLA tiers.
+type SLASnapshotSource string
+
+const (
+	SLASnapshotSourceFile SLASnapshotSource = "cp_distribution_file"
+	SLASnapshotSourceHTTP SLASnapshotSource = "cp_distribution_http"
+)
+
+// SLASnapshot captures the distributed default and per-tenant SLA tiers.
+type SLASnapshot struct {
+	DefaultTier *controlplane.SLATier
+	TenantTiers map[string]controlplane.SLATier
+}
+
+// Resolve returns the SLA annotation for a tenant's turns: the tenant's declared tier, else
+// the default tier. It reports false when neither applies.
+func (s SLASnapshot) Resolve(tenantID string) (controlplane.TurnSLA, bool) {
+	tenantID = strings.TrimSpace(tenantID)
+	if tenantID == "" {
+		return controlplane.TurnSLA{}, false
+	}
+	if tier, ok := s.TenantTiers[tenantID]; ok {
+		return controlplane.TurnSLA{TenantID: tenantID, SLATier: tier}, true
+	}
+	if s.DefaultTier != nil {
+		return controlplane.TurnSLA{TenantID: tenantID, SLATier: *s.DefaultTier}, true
+	}
+	return controlplane.TurnSLA{}, false
+}
+
+// LoadSLASnapshotFromEnv resolves tenant SLA tiers from env-configured CP distribution.
+func LoadSLASnapshotFromEnv() (SLASnapshot, SLASnapshotSource, error) {
+	if strings.TrimSpace(os.Getenv(EnvHTTPAdapterURLs)) != "" || strings.TrimSpace(os.Getenv(EnvHTTPAdapterURL)) != "" {
+		cfg, err := HTTPAdapterConfigFromEnv()
+		if err != nil {
+			return SLASnapshot{}, SLASnapshotSourceHTTP, err
+		}
+		snapshot, err := LoadSLASnapshotFromHTTP(cfg)
+		if err != nil {
+			return SLASnapshot{}, SLASnapshotSourceHTTP, err
+		}
+		return snapshot, SLASnapshotSourceHTTP, nil
+	}
+
+	cfg, err := FileAdapterConfigFromEnv()
+	if err != nil {
+		return SLASnapshot{}, SLASnapshotSourceFile, err
+	}
+	snapshot, err := LoadSLASnapshotFromFile(cfg.Path)
+	if err != nil {
+		return SLASnapshot{}, SLASnapshotSourceFile, err
+	}
+	return snapshot, SLASnapshotSourceFile, nil
+}
+
+// LoadSLASnapshotFromFile resolves tenant SLA tiers from a file-backed CP distribution artifact.
+func LoadSLASnapshotFromFile(path string) (SLASnapshot, error) {
+	adapter, err := newFileAdapter(FileAdapterConfig{Path: path})
+	if err != nil {
+		return SLASnapshot{}, err
+	}
+	return slaSnapshotFromArtifact(adapter.path, adapter.artifact)
+}
+
+// LoadSLASnapshotFromHTTP resolves tenant SLA tiers from HTTP-backed CP distribution endpoints.
+func LoadSLASnapshotFromHTTP(cfg HTTPAdapterConfig) (SLASnapshot, error) {
+	provider, err := newHTTPSnapshotProvider(cfg)
+	if err != nil {
+		return SLASnapshot{}, err
+	}
+	adapter, err := provider.current()
+	if err != nil {
+		return SLASnapshot{}, err
+	}
+	return slaSnapshotFromArtifact(adapter.path, adapter.artifact)
+}
+
+func slaSnapshotFromArtifact(path string, artifact fileArtifact) (SLASnapshot, error) {
+	if artifact.Stale || artifact.SLA.Stale {
+		return SLASnapshot{}, BackendError{
+			Service: "sla",
+			Code:    ErrorCodeSnapshotStale,
+			Path:    path,
+			Cause:   fmt.Errorf("snapshot marked stale"),
+		}
+	}
+
+	section := artifact.SLA
+	if section.DefaultTier == nil && len(section.TenantTiers) == 0 {
+		return SLASnapshot{}, BackendError{
+			Service: "sla",
+			Code:    ErrorCodeSnapshotMissing,
+			Path:    path,
+			Cause:   fmt.Errorf("missing sla snapshot"),
+		}
+	}
+
+	snapshot := SLASnapshot{}
+	if section.DefaultTier != nil {
+		if err := section.DefaultTier.Validate(); err != nil {
+			return SLASnapshot{}, BackendError{Service: "sla", Code: ErrorCodeInvalidArtifact, Path: path, Cause: fmt.Errorf("default_tier: %w", err)}
+		}
+		tier := *section.DefaultTier
+		snapshot.DefaultTier = &tier
+	}
+	if len(section.TenantTiers) > 0 {
+		snapshot.TenantTiers = make(map[string]controlplane.SLATier, len(section.TenantTiers))
+	}
+	for tenantID, tier := range section.TenantTiers {
+		if strings.TrimSpace(tenantID) == "" {
+			return SLASnapshot{}, BackendError{Service: "sla", Code: ErrorCodeInvalidArtifact, Path: path, Cause: fmt.Errorf("tenant_tiers key must be non-empty")}
+		}
+		if err := tier.Validate(); err != nil {
+			return SLASnapshot{}, BackendError{Service: "sla", Code: ErrorCodeInvalidArtifact, Path: path, Cause: fmt.Errorf("tenant_tiers[%s]: %w", tenantID, err)}
+		}
+		snapshot.TenantTiers[tenantID] = tier
+	}
+	return snapshot, nil
+}
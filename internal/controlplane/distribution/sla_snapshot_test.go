@@ -0,0 +1,113 @@
+package distribution
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoadSLASnapshotFromFileResolvesTenantTiers(t *testing.T) {
+	t.Parallel()
+
+	path := writeDistributionArtifact(t, `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "sla": {
+    "default_tier": {"tier": "standard", "max_first_output_latency_ms": 1500, "min_availability": 0.99},
+    "tenant_tiers": {
+      "tenant-a": {"tier": "gold", "max_first_output_latency_ms": 800, "min_availability": 0.999}
+    }
+  }
+}`)
+
+	snapshot, err := LoadSLASnapshotFromFile(path)
+	if err != nil {
+		t.Fatalf("expected sla snapshot from file, got %v", err)
+	}
+	sla, ok := snapshot.Resolve("tenant-a")
+	if !ok || sla.TenantID != "tenant-a" || sla.Tier != "gold" || sla.MaxFirstOutputLatencyMS != 800 {
+		t.Fatalf("unexpected tenant-a sla: %+v (%t)", sla, ok)
+	}
+	sla, ok = snapshot.Resolve("tenant-b")
+	if !ok || sla.TenantID != "tenant-b" || sla.Tier != "standard" || sla.MinAvailability != 0.99 {
+		t.Fatalf("expected tenant-b to fall back to the default tier, got %+v (%t)", sla, ok)
+	}
+	if _, ok := snapshot.Resolve(" "); ok {
+		t.Fatalf("expected blank tenant to resolve no sla")
+	}
+	if _, ok := (SLASnapshot{TenantTiers: snapshot.TenantTiers}).Resolve("tenant-b"); ok {
+		t.Fatalf("expected no sla without a tenant tier or default tier")
+	}
+}
+
+func TestLoadSLASnapshotFromFileClassifiesErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload string
+		code    ErrorCode
+	}{
+		{
+			name:    "stale section",
+			payload: `{"schema_version": "cp-snapshot-distribution/v1", "sla": {"stale": true}}`,
+			code:    ErrorCodeSnapshotStale,
+		},
+		{
+			name:    "missing section",
+			payload: `{"schema_version": "cp-snapshot-distribution/v1"}`,
+			code:    ErrorCodeSnapshotMissing,
+		},
+		{
+			name:    "invalid default tier",
+			payload: `{"schema_version": "cp-snapshot-distribution/v1", "sla": {"default_tier": {"tier": "standard", "min_availability": 0.99}}}`,
+			code:    ErrorCodeInvalidArtifact,
+		},
+		{
+			name:    "invalid tenant tier",
+			payload: `{"schema_version": "cp-snapshot-distribution/v1", "sla": {"tenant_tiers": {"tenant-a": {"tier": "gold", "max_first_output_latency_ms": 800, "min_availability": 2}}}}`,
+			code:    ErrorCodeInvalidArtifact,
+		},
+	}
+	for _, tc := range tests {
+		_, err := LoadSLASnapshotFromFile(writeDistributionArtifact(t, tc.payload))
+		var backendErr BackendError
+		if !errors.As(err, &backendErr) {
+			t.Fatalf("%s: expected backend error, got %v", tc.name, err)
+		}
+		if backendErr.Code != tc.code {
+			t.Fatalf("%s: expected %s code, got %s", tc.name, tc.code, backendErr.Code)
+		}
+	}
+}
+
+func TestLoadSLASnapshotFromEnvUsesHTTPPrecedence(t *testing.T) {
+	filePath := writeDistributionArtifact(t, `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "sla": {"default_tier": {"tier": "standard", "max_first_output_latency_ms": 1500, "min_availability": 0.99}}
+}`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "sla": {"tenant_tiers": {"tenant-a": {"tier": "gold", "max_first_output_latency_ms": 800, "min_availability": 0.999}}}
+}`))
+	}))
+	defer server.Close()
+
+	t.Setenv(EnvFileAdapterPath, filePath)
+	t.Setenv(EnvHTTPAdapterURL, "")
+	t.Setenv(EnvHTTPAdapterURLs, server.URL)
+
+	snapshot, source, err := LoadSLASnapshotFromEnv()
+	if err != nil {
+		t.Fatalf("expected env sla snapshot from http source, got %v", err)
+	}
+	if source != SLASnapshotSourceHTTP {
+		t.Fatalf("expected http source, got %s", source)
+	}
+	if _, ok := snapshot.TenantTiers["tenant-a"]; !ok || snapshot.DefaultTier != nil {
+		t.Fatalf("expected the http snapshot to take precedence over the file source, got %+v", snapshot)
+	}
+}
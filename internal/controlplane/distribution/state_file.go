@@ -0,0 +1,166 @@
+package distribution
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultStateBackups is how many previous distribution states are kept beside the artifact.
+const DefaultStateBackups = 3
+
+// BackupPath names a rotated distribution state; generation 1 is the most recent.
+func BackupPath(path string, generation int) string {
+	return fmt.Sprintf("%s.bak.%d", path, generation)
+}
+
+// QuarantinePath names where repair moves an unreadable distribution state aside.
+func QuarantinePath(path string) string {
+	return path + ".corrupt"
+}
+
+// WriteStateFile durably replaces the distribution state at path. The current state is first
+// rotated into BackupPath(path, 1) (older generations shift up, keeping at most backups), then
+// data is written to a temp file, fsynced, and renamed over path, so a crash at any point leaves
+// either the previous or the new state readable.
+func WriteStateFile(path string, data []byte, backups int) error {
+	if backups < 0 {
+		return fmt.Errorf("distribution state backups must be >=0")
+	}
+	if err := rotateStateBackups(path, backups); err != nil {
+		return err
+	}
+	return writeDurable(path, data)
+}
+
+func rotateStateBackups(path string, backups int) error {
+	if backups == 0 {
+		return nil
+	}
+	current, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(BackupPath(path, backups)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	for generation := backups - 1; generation >= 1; generation-- {
+		if err := os.Rename(BackupPath(path, generation), BackupPath(path, generation+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return writeDurable(BackupPath(path, 1), current)
+}
+
+func writeDurable(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	mode := fs.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	committed = true
+	// Persist the rename itself; directories cannot be fsynced on every platform, so this is
+	// best-effort.
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
+}
+
+// RepairResult reports a distribution state repair.
+type RepairResult struct {
+	Path string
+	// Healthy is true when the current state was already valid and nothing changed.
+	Healthy bool
+	// RestoredFrom is the backup copied over path.
+	RestoredFrom string
+	// QuarantinedPath keeps the unreadable state for inspection, when one existed.
+	QuarantinedPath string
+	// CurrentError explains why the current state was rejected.
+	CurrentError string
+}
+
+// RepairStateFile restores path from the most recent backup that loads as a valid distribution
+// artifact. A valid current state is left untouched; an invalid one is moved to QuarantinePath
+// before the backup is restored. Backups themselves are never rotated by repair.
+func RepairStateFile(path string, backups int) (RepairResult, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return RepairResult{}, BackendError{Service: "distribution", Code: ErrorCodeInvalidConfig, Path: "path"}
+	}
+	if backups < 1 {
+		return RepairResult{}, BackendError{Service: "distribution", Code: ErrorCodeInvalidConfig, Path: path, Cause: fmt.Errorf("backups must be >=1")}
+	}
+	currentErr := validateStateFile(path)
+	if currentErr == nil {
+		return RepairResult{Path: path, Healthy: true}, nil
+	}
+
+	for generation := 1; generation <= backups; generation++ {
+		candidate := BackupPath(path, generation)
+		if validateStateFile(candidate) != nil {
+			continue
+		}
+		raw, err := os.ReadFile(candidate)
+		if err != nil {
+			return RepairResult{}, BackendError{Service: "distribution", Code: ErrorCodeReadArtifact, Path: candidate, Cause: err}
+		}
+		result := RepairResult{Path: path, RestoredFrom: candidate, CurrentError: currentErr.Error()}
+		if _, err := os.Stat(path); err == nil {
+			if err := os.Rename(path, QuarantinePath(path)); err != nil {
+				return RepairResult{}, err
+			}
+			result.QuarantinedPath = QuarantinePath(path)
+		}
+		if err := writeDurable(path, raw); err != nil {
+			return RepairResult{}, err
+		}
+		return result, nil
+	}
+	return RepairResult{}, BackendError{
+		Service: "distribution",
+		Code:    ErrorCodeInvalidArtifact,
+		Path:    path,
+		Cause:   fmt.Errorf("no valid backup among %d generations: %w", backups, currentErr),
+	}
+}
+
+func validateStateFile(path string) error {
+	_, err := newFileAdapter(FileAdapterConfig{Path: path})
+	return err
+}
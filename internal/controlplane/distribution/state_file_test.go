@@ -0,0 +1,125 @@
+package distribution
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const stateFixture = `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "registry": {
+    "default_pipeline_version": "pipeline-v1",
+    "records": {
+      "pipeline-v1": {"pipeline_version": "pipeline-v1"},
+      "pipeline-v2": {"pipeline_version": "pipeline-v2"},
+      "pipeline-v3": {"pipeline_version": "pipeline-v3"}
+    }
+  },
+  "rollout": {"default_pipeline_version": "pipeline-v1"}
+}`
+
+func TestSetActivePipelineVersionRotatesBackups(t *testing.T) {
+	t.Parallel()
+
+	path := writeDistributionArtifact(t, stateFixture)
+	for _, version := range []string{"pipeline-v2", "pipeline-v3", "pipeline-v1", "pipeline-v2"} {
+		if _, err := SetActivePipelineVersion(path, version); err != nil {
+			t.Fatalf("unexpected set active version error: %v", err)
+		}
+	}
+
+	// Newest backup first: each holds the state active before the corresponding write.
+	for generation, want := range []string{"pipeline-v1", "pipeline-v3", "pipeline-v2"} {
+		backend, err := NewFileBackends(FileAdapterConfig{Path: BackupPath(path, generation+1)})
+		if err != nil {
+			t.Fatalf("generation %d: unexpected backup load error: %v", generation+1, err)
+		}
+		record, err := backend.Registry.ResolvePipelineRecord("")
+		if err != nil || record.PipelineVersion != want {
+			t.Fatalf("generation %d: expected %s, got %+v err=%v", generation+1, want, record, err)
+		}
+	}
+	if _, err := os.Stat(BackupPath(path, DefaultStateBackups+1)); !os.IsNotExist(err) {
+		t.Fatalf("expected at most %d backups, stat err=%v", DefaultStateBackups, err)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Fatalf("unexpected leftover temp file %s", entry.Name())
+		}
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected state file mode preserved, got %v err=%v", info, err)
+	}
+}
+
+func TestRepairStateFileRestoresLatestValidBackup(t *testing.T) {
+	t.Parallel()
+
+	path := writeDistributionArtifact(t, stateFixture)
+	for _, version := range []string{"pipeline-v2", "pipeline-v3"} {
+		if _, err := SetActivePipelineVersion(path, version); err != nil {
+			t.Fatalf("unexpected set active version error: %v", err)
+		}
+	}
+
+	healthy, err := RepairStateFile(path, DefaultStateBackups)
+	if err != nil || !healthy.Healthy || healthy.RestoredFrom != "" {
+		t.Fatalf("expected valid state left untouched, got %+v err=%v", healthy, err)
+	}
+
+	// Simulate a torn write of the live state and a corrupt newest backup.
+	if err := os.WriteFile(path, []byte(`{"schema_version": "cp-snapshot-distri`), 0o600); err != nil {
+		t.Fatalf("corrupt state: %v", err)
+	}
+	if err := os.WriteFile(BackupPath(path, 1), []byte(`not json`), 0o600); err != nil {
+		t.Fatalf("corrupt backup: %v", err)
+	}
+
+	result, err := RepairStateFile(path, DefaultStateBackups)
+	if err != nil {
+		t.Fatalf("unexpected repair error: %v", err)
+	}
+	if result.Healthy || result.RestoredFrom != BackupPath(path, 2) || result.QuarantinedPath != QuarantinePath(path) || result.CurrentError == "" {
+		t.Fatalf("unexpected repair result %+v", result)
+	}
+	backends, err := NewFileBackends(FileAdapterConfig{Path: path})
+	if err != nil {
+		t.Fatalf("unexpected restored state load error: %v", err)
+	}
+	if record, err := backends.Registry.ResolvePipelineRecord(""); err != nil || record.PipelineVersion != "pipeline-v1" {
+		t.Fatalf("expected restore of the pipeline-v1 generation, got %+v err=%v", record, err)
+	}
+	if raw, _ := os.ReadFile(QuarantinePath(path)); !strings.Contains(string(raw), "cp-snapshot-distri") {
+		t.Fatalf("expected corrupt state quarantined, got %q", raw)
+	}
+}
+
+func TestRepairStateFileFailsWithoutValidBackup(t *testing.T) {
+	t.Parallel()
+
+	path := writeDistributionArtifact(t, `{"schema_version": `)
+	tests := []struct {
+		name    string
+		path    string
+		backups int
+	}{
+		{name: "empty path", path: " ", backups: 1},
+		{name: "no backups allowed", path: path, backups: 0},
+		{name: "no backups present", path: path, backups: DefaultStateBackups},
+	}
+	for _, tc := range tests {
+		if _, err := RepairStateFile(tc.path, tc.backups); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+	if raw, _ := os.ReadFile(path); string(raw) != `{"schema_version": ` {
+		t.Fatalf("expected failed repair to leave state untouched, got %q", raw)
+	}
+}
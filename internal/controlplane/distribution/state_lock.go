@@ -0,0 +1,49 @@
+package distribution
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+const (
+	stateLockWait  = 5 * time.Second
+	stateLockStale = 30 * time.Second
+	stateLockPoll  = 10 * time.Millisecond
+)
+
+// StateLockPath names the lock file guarding changes to the state file at path.
+func StateLockPath(path string) string {
+	return path + ".lock"
+}
+
+// LockStateFile takes the exclusive lock guarding a read-modify-write of the state file at
+// path and returns its release, so concurrent writers apply their changes one after another
+// instead of the last write dropping the others. The lock is a file created with O_EXCL, so it holds across
+// processes sharing the state (control planes, rspp-cli); a holder is waited for up to
+// stateLockWait, and a lock older than stateLockStale was left by a crashed writer and is
+// broken. A timeout fails with ErrorCodeStateLocked.
+func LockStateFile(path string) (func(), error) {
+	lockPath := StateLockPath(path)
+	deadline := time.Now().Add(stateLockWait)
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = file.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, BackendError{Service: "distribution", Code: ErrorCodeStateLocked, Path: path, Cause: err}
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > stateLockStale {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, BackendError{Service: "distribution", Code: ErrorCodeStateLocked, Path: path, Cause: fmt.Errorf("locked by another writer for over %s", stateLockWait)}
+		}
+		time.Sleep(stateLockPoll)
+	}
+}
@@ -0,0 +1,95 @@
+package distribution
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+// Environment of one writer process spawned by TestStateWritersSerializeAcrossProcesses.
+const (
+	envStateLockWorker = "RSPP_TEST_STATE_LOCK_WORKER"
+	envStateLockPath   = "RSPP_TEST_STATE_LOCK_PATH"
+)
+
+const stateLockPublishes = 8
+
+func TestStateWritersSerializeAcrossProcesses(t *testing.T) {
+	if worker := os.Getenv(envStateLockWorker); worker != "" {
+		runStateLockWorker(t, os.Getenv(envStateLockPath), worker)
+		return
+	}
+	t.Parallel()
+
+	path := writeDistributionArtifact(t, `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "registry": {"records": {"pipeline-v1": {"pipeline_version": "pipeline-v1"}}},
+  "rollout": {"default_pipeline_version": "pipeline-v1"},
+  "routing_view": {"default": {"routing_view_snapshot": "routing-view/file", "admission_policy_snapshot": "admission-policy/file", "abi_compatibility_snapshot": "abi-compat/file"}},
+  "lease": {"default": {"lease_resolution_snapshot": "lease/file", "authority_epoch": 3}}
+}`)
+	const workers = 4
+	cmds := make([]*exec.Cmd, 0, workers)
+	outputs := make([]*bytes.Buffer, 0, workers)
+	for i := range workers {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestStateWritersSerializeAcrossProcesses$")
+		cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", envStateLockWorker, i), envStateLockPath+"="+path)
+		output := &bytes.Buffer{}
+		cmd.Stdout, cmd.Stderr = output, output
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("unexpected start error for writer %d: %v", i, err)
+		}
+		cmds, outputs = append(cmds, cmd), append(outputs, output)
+	}
+	for i, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("writer %d failed: %v\n%s", i, err, outputs[i].String())
+		}
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	var state struct {
+		Registry struct {
+			Records map[string]json.RawMessage `json:"records"`
+		} `json:"registry"`
+		Rollout struct {
+			ByEnvironment map[string]string `json:"by_environment"`
+		} `json:"rollout"`
+	}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+	if len(state.Registry.Records) != 1+workers*stateLockPublishes {
+		t.Fatalf("expected every writer's records kept, got %d records", len(state.Registry.Records))
+	}
+	for i := range workers {
+		want := fmt.Sprintf("pipeline-w%d-%d", i, stateLockPublishes-1)
+		if got := state.Rollout.ByEnvironment[fmt.Sprintf("env-%d", i)]; got != want {
+			t.Fatalf("expected env-%d activated at %s, got %q", i, want, got)
+		}
+	}
+	if _, err := os.Stat(StateLockPath(path)); !os.IsNotExist(err) {
+		t.Fatalf("expected the state lock released, got %v", err)
+	}
+}
+
+// runStateLockWorker publishes records and activates the last one for the worker's own
+// environment, interleaving with the other writer processes.
+func runStateLockWorker(t *testing.T, path string, worker string) {
+	version := ""
+	for i := range stateLockPublishes {
+		version = fmt.Sprintf("pipeline-w%s-%d", worker, i)
+		if _, err := PublishPipelineRecord(path, PipelineRecordUpdate{PipelineVersion: version, GraphDefinitionRef: "graph/" + version}, false); err != nil {
+			t.Fatalf("unexpected publish error: %v", err)
+		}
+	}
+	if _, err := SetEnvironmentPipelineVersion(path, "env-"+worker, version); err != nil {
+		t.Fatalf("unexpected activate error: %v", err)
+	}
+}
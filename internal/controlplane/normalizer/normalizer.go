@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/registry"
 )
 
@@ -17,6 +18,14 @@ type Output struct {
 	PipelineVersion    string
 	GraphDefinitionRef string
 	ExecutionProfile   string
+	// STTEndpointing is the pipeline-version endpointing override, nil for profile defaults.
+	STTEndpointing *controlplane.STTEndpointing
+	// AudioEnhancement is the pipeline-version pre-STT enhancement setting, nil when unset.
+	AudioEnhancement *controlplane.AudioEnhancement
+	// Summarization is the pipeline-version session summarization setting, nil when unset.
+	Summarization *controlplane.Summarization
+	// SpecHash content-addresses the published pipeline spec, empty when unpublished.
+	SpecHash string
 }
 
 // Service applies deterministic CP-02 defaulting.
@@ -47,5 +56,9 @@ func (Service) Normalize(in Input) (Output, error) {
 		PipelineVersion:    record.PipelineVersion,
 		GraphDefinitionRef: record.GraphDefinitionRef,
 		ExecutionProfile:   record.ExecutionProfile,
+		STTEndpointing:     record.STTEndpointing,
+		AudioEnhancement:   record.AudioEnhancement,
+		Summarization:      record.Summarization,
+		SpecHash:           record.SpecHash,
 	}, nil
 }
@@ -1,6 +1,11 @@
 package policy
 
-import "fmt"
+import (
+	"fmt"
+	"maps"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+)
 
 const defaultPolicyResolutionSnapshot = "policy-resolution/v1"
 
@@ -18,6 +23,8 @@ type Input struct {
 type Output struct {
 	PolicyResolutionSnapshot string
 	AllowedAdaptiveActions   []string
+	// Budgets are the tenant budgets RK-25 enforces at scheduling points; empty is unlimited.
+	Budgets controlplane.BudgetPolicy
 }
 
 // Backend evaluates policy from a snapshot-fed control-plane source.
@@ -51,6 +58,9 @@ func (s Service) Evaluate(in Input) (Output, error) {
 		if err != nil {
 			return Output{}, fmt.Errorf("evaluate policy backend: %w", err)
 		}
+		if err := out.Budgets.Validate(); err != nil {
+			return Output{}, fmt.Errorf("invalid policy budgets: %w", err)
+		}
 		return s.normalizeOutput(out), nil
 	}
 
@@ -74,6 +84,10 @@ func (s Service) normalizeOutput(out Output) Output {
 	return Output{
 		PolicyResolutionSnapshot: snapshot,
 		AllowedAdaptiveActions:   normalizedActions,
+		Budgets: controlplane.BudgetPolicy{
+			Default:  out.Budgets.Default,
+			ByTenant: maps.Clone(out.Budgets.ByTenant),
+		},
 	}
 }
 
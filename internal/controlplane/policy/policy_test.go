@@ -4,6 +4,8 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 )
 
 func TestEvaluate(t *testing.T) {
@@ -56,6 +58,37 @@ func TestEvaluateUsesBackendWhenConfigured(t *testing.T) {
 	}
 }
 
+func TestEvaluatePassesThroughTenantBudgets(t *testing.T) {
+	t.Parallel()
+
+	budgets := controlplane.BudgetPolicy{
+		Default:  controlplane.TenantBudget{MaxTokensPerTurn: 2000},
+		ByTenant: map[string]controlplane.TenantBudget{"tenant-a": {MaxSessionSpendUSD: 1.5}},
+	}
+	service := NewService()
+	service.Backend = stubPolicyBackend{
+		evalFn: func(Input) (Output, error) {
+			return Output{PolicyResolutionSnapshot: "policy-resolution/budgets", Budgets: budgets}, nil
+		},
+	}
+	out, err := service.Evaluate(Input{SessionID: "sess-1"})
+	if err != nil {
+		t.Fatalf("unexpected budget policy evaluation error: %v", err)
+	}
+	if !reflect.DeepEqual(out.Budgets, budgets) {
+		t.Fatalf("expected tenant budgets %+v, got %+v", budgets, out.Budgets)
+	}
+
+	service.Backend = stubPolicyBackend{
+		evalFn: func(Input) (Output, error) {
+			return Output{Budgets: controlplane.BudgetPolicy{Default: controlplane.TenantBudget{MaxTokensPerTurn: -1}}}, nil
+		},
+	}
+	if _, err := service.Evaluate(Input{SessionID: "sess-1"}); err == nil {
+		t.Fatalf("expected invalid tenant budgets to be rejected")
+	}
+}
+
 func TestEvaluateBackendError(t *testing.T) {
 	t.Parallel()
 
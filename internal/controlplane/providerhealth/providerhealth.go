@@ -10,9 +10,36 @@ type Input struct {
 	PipelineVersion string
 }
 
+// WarmupStatus is the latest connection warm-up result reported for one provider.
+type WarmupStatus struct {
+	ProviderID       string
+	Modality         string
+	Phase            string
+	Warmed           bool
+	LatencyMS        int64
+	ConnectionReused bool
+	Reason           string
+	WarmedAtMS       int64
+}
+
+// CatalogStatus is one provider entry of the runtime's declarative provider catalog.
+// CredentialsResolved reports whether the credentials reference currently resolves; the
+// secret itself is never carried.
+type CatalogStatus struct {
+	ProviderID          string
+	Modality            string
+	Enabled             bool
+	Weight              int
+	Region              string
+	CredentialsRef      string
+	CredentialsResolved bool
+}
+
 // Output returns deterministic provider health snapshot reference.
 type Output struct {
 	ProviderHealthSnapshot string
+	ProviderWarmup         []WarmupStatus
+	ProviderCatalog        []CatalogStatus
 }
 
 // Backend resolves provider-health snapshots from a snapshot-fed control-plane source.
@@ -20,10 +47,22 @@ type Backend interface {
 	GetSnapshot(in Input) (Output, error)
 }
 
+// WarmupSource reports runtime provider warm-up results for inclusion in snapshots.
+type WarmupSource interface {
+	WarmupStatuses() []WarmupStatus
+}
+
+// CatalogSource reports the runtime's current provider catalog for inclusion in snapshots.
+type CatalogSource interface {
+	CatalogStatuses() []CatalogStatus
+}
+
 // Service resolves CP-10 provider health snapshots for runtime turn-start freeze.
 type Service struct {
 	DefaultProviderHealthSnapshot string
 	Backend                       Backend
+	Warmup                        WarmupSource
+	Catalog                       CatalogSource
 }
 
 // NewService returns the baseline CP-10 provider health resolver.
@@ -56,5 +95,13 @@ func (s Service) normalizeOutput(out Output) Output {
 	if out.ProviderHealthSnapshot != "" {
 		snapshot = out.ProviderHealthSnapshot
 	}
-	return Output{ProviderHealthSnapshot: snapshot}
+	warmup := out.ProviderWarmup
+	if len(warmup) == 0 && s.Warmup != nil {
+		warmup = s.Warmup.WarmupStatuses()
+	}
+	catalog := out.ProviderCatalog
+	if len(catalog) == 0 && s.Catalog != nil {
+		catalog = s.Catalog.CatalogStatuses()
+	}
+	return Output{ProviderHealthSnapshot: snapshot, ProviderWarmup: warmup, ProviderCatalog: catalog}
 }
@@ -1,6 +1,11 @@
 package registry
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/specstore"
+)
 
 const (
 	// DefaultPipelineVersion is the baseline pipeline version resolved by CP-01.
@@ -16,6 +21,14 @@ type PipelineRecord struct {
 	PipelineVersion    string
 	GraphDefinitionRef string
 	ExecutionProfile   string
+	// STTEndpointing optionally overrides the execution-profile endpointing defaults.
+	STTEndpointing *controlplane.STTEndpointing
+	// AudioEnhancement optionally enables the pre-STT noise-suppression node.
+	AudioEnhancement *controlplane.AudioEnhancement
+	// Summarization optionally enables the session summarization node.
+	Summarization *controlplane.Summarization
+	// SpecHash optionally content-addresses the published pipeline spec in the spec store.
+	SpecHash string
 }
 
 // Validate enforces baseline CP-01 contract requirements.
@@ -29,9 +42,34 @@ func (r PipelineRecord) Validate() error {
 	if r.ExecutionProfile == "" {
 		return fmt.Errorf("execution_profile is required")
 	}
+	if r.STTEndpointing != nil {
+		if err := r.STTEndpointing.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.AudioEnhancement != nil {
+		if err := r.AudioEnhancement.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.Summarization != nil {
+		if err := r.Summarization.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.SpecHash != "" {
+		if err := specstore.ValidateHash(r.SpecHash); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// SpecStore fetches published pipeline spec blobs by spec_hash.
+type SpecStore interface {
+	Get(specHash string) ([]byte, error)
+}
+
 // Backend resolves pipeline records from a snapshot-fed control-plane source.
 type Backend interface {
 	ResolvePipelineRecord(pipelineVersion string) (PipelineRecord, error)
@@ -42,6 +80,8 @@ type Service struct {
 	DefaultRecord PipelineRecord
 	Records       map[string]PipelineRecord
 	Backend       Backend
+	// Specs, when set, verifies that a record's spec_hash resolves to intact published content.
+	Specs SpecStore
 }
 
 // NewService returns the deterministic CP-01 baseline resolver.
@@ -81,16 +121,31 @@ func (s Service) ResolvePipelineRecord(pipelineVersion string) (PipelineRecord,
 		if err != nil {
 			return PipelineRecord{}, fmt.Errorf("resolve pipeline record backend: %w", err)
 		}
-		return applyDefaults(record, version, defaultRecord)
+		return s.verifySpec(applyDefaults(record, version, defaultRecord))
 	}
 
 	if record, ok := s.Records[version]; ok {
-		return applyDefaults(record, version, defaultRecord)
+		return s.verifySpec(applyDefaults(record, version, defaultRecord))
 	}
 
 	return applyDefaults(PipelineRecord{}, version, defaultRecord)
 }
 
+func (s Service) verifySpec(record PipelineRecord, err error) (PipelineRecord, error) {
+	if err != nil || record.SpecHash == "" || s.Specs == nil {
+		return record, err
+	}
+	content, err := s.Specs.Get(record.SpecHash)
+	if err != nil {
+		return PipelineRecord{}, fmt.Errorf("fetch pipeline spec %s: %w", record.SpecHash, err)
+	}
+	// Re-verify here so a store that skips integrity checks cannot hand back a different spec.
+	if err := specstore.Verify(record.SpecHash, content); err != nil {
+		return PipelineRecord{}, fmt.Errorf("verify pipeline spec for pipeline_version=%s: %w", record.PipelineVersion, err)
+	}
+	return record, nil
+}
+
 func applyDefaults(record PipelineRecord, version string, defaults PipelineRecord) (PipelineRecord, error) {
 	if record.PipelineVersion == "" {
 		record.PipelineVersion = version
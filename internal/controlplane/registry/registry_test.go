@@ -3,6 +3,9 @@ package registry
 import (
 	"errors"
 	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/specstore"
 )
 
 func TestResolvePipelineRecord(t *testing.T) {
@@ -60,6 +63,22 @@ func TestResolvePipelineRecordAppliesBaselineDefaults(t *testing.T) {
 	}
 }
 
+func TestResolvePipelineRecordValidatesSTTEndpointing(t *testing.T) {
+	t.Parallel()
+
+	service := Service{Records: map[string]PipelineRecord{
+		"pipeline-valid":   {STTEndpointing: &controlplane.STTEndpointing{SilenceDurationMS: 600, MinUtteranceMS: 100, MaxUtteranceMS: 10000, NoSpeechTimeoutMS: 4000}},
+		"pipeline-invalid": {STTEndpointing: &controlplane.STTEndpointing{SilenceDurationMS: 600, MinUtteranceMS: 10000, MaxUtteranceMS: 100, NoSpeechTimeoutMS: 4000}},
+	}}
+	record, err := service.ResolvePipelineRecord("pipeline-valid")
+	if err != nil || record.STTEndpointing == nil || record.STTEndpointing.SilenceDurationMS != 600 {
+		t.Fatalf("expected per-version stt endpointing, got record=%+v err=%v", record, err)
+	}
+	if _, err := service.ResolvePipelineRecord("pipeline-invalid"); err == nil {
+		t.Fatalf("expected invalid stt endpointing to fail record validation")
+	}
+}
+
 func TestResolvePipelineRecordUsesBackendWhenConfigured(t *testing.T) {
 	t.Parallel()
 
@@ -98,6 +117,57 @@ func TestResolvePipelineRecordBackendError(t *testing.T) {
 	}
 }
 
+func TestResolvePipelineRecordVerifiesSpecIntegrity(t *testing.T) {
+	t.Parallel()
+
+	published := []byte(`{"pipeline_version":"pipeline-v2"}`)
+	specHash := specstore.Hash(published)
+
+	cases := []struct {
+		name    string
+		blobs   map[string][]byte
+		hash    string
+		wantErr bool
+	}{
+		{name: "intact spec", blobs: map[string][]byte{specHash: published}, hash: specHash},
+		{name: "tampered spec", blobs: map[string][]byte{specHash: []byte(`{"pipeline_version":"pipeline-v3"}`)}, hash: specHash, wantErr: true},
+		{name: "missing spec", blobs: map[string][]byte{}, hash: specHash, wantErr: true},
+		{name: "malformed hash", blobs: map[string][]byte{}, hash: "md5:abc", wantErr: true},
+		{name: "no spec hash", blobs: map[string][]byte{}},
+	}
+	for _, tc := range cases {
+		service := NewService()
+		service.Records = map[string]PipelineRecord{
+			"pipeline-v2": {GraphDefinitionRef: "graph/v2", SpecHash: tc.hash},
+		}
+		service.Specs = stubSpecStore(tc.blobs)
+
+		record, err := service.ResolvePipelineRecord("pipeline-v2")
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected spec verification error, got %+v", tc.name, record)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected resolve error: %v", tc.name, err)
+		}
+		if record.SpecHash != tc.hash {
+			t.Fatalf("%s: expected spec_hash %q, got %+v", tc.name, tc.hash, record)
+		}
+	}
+}
+
+type stubSpecStore map[string][]byte
+
+func (s stubSpecStore) Get(specHash string) ([]byte, error) {
+	content, ok := s[specHash]
+	if !ok {
+		return nil, specstore.ErrSpecNotFound
+	}
+	return content, nil
+}
+
 type stubRegistryBackend struct {
 	resolveFn func(version string) (PipelineRecord, error)
 }
@@ -0,0 +1,435 @@
+package rollout
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PoolColor identifies one side of a blue/green runtime deployment.
+type PoolColor string
+
+const (
+	PoolBlue  PoolColor = "blue"
+	PoolGreen PoolColor = "green"
+)
+
+// PoolState is the lifecycle state of a runtime pool.
+type PoolState string
+
+const (
+	// PoolStateActive pools receive new sessions in proportion to their weight.
+	PoolStateActive PoolState = "active"
+	// PoolStateDraining pools receive no new sessions but still serve existing ones.
+	PoolStateDraining PoolState = "draining"
+	// PoolStateDrained pools have no remaining sessions and can be replaced.
+	PoolStateDrained PoolState = "drained"
+)
+
+// CutoverPhase is the blue/green coordination phase for one environment.
+type CutoverPhase string
+
+const (
+	CutoverPhaseStable   CutoverPhase = "stable"
+	CutoverPhaseShifting CutoverPhase = "shifting"
+	CutoverPhasePromoted CutoverPhase = "promoted"
+	CutoverPhaseAborted  CutoverPhase = "aborted"
+)
+
+// Audit actions recorded for blue/green transitions.
+const (
+	AuditActionRegister = "register_pools"
+	AuditActionShift    = "shift_weight"
+	AuditActionPromote  = "promote"
+	AuditActionAbort    = "abort"
+	AuditActionDrained  = "pool_drained"
+)
+
+// RuntimePool is one registered runtime pool.
+type RuntimePool struct {
+	PoolID          string    `json:"pool_id"`
+	Color           PoolColor `json:"color"`
+	PipelineVersion string    `json:"pipeline_version"`
+	// Weight is the 0-100 share of new sessions routed to the pool.
+	Weight int       `json:"weight"`
+	State  PoolState `json:"state"`
+}
+
+// Validate enforces pool registration requirements.
+func (p RuntimePool) Validate() error {
+	if strings.TrimSpace(p.PoolID) == "" {
+		return fmt.Errorf("pool_id is required")
+	}
+	if p.Color != PoolBlue && p.Color != PoolGreen {
+		return fmt.Errorf("pool color must be one of blue|green")
+	}
+	if strings.TrimSpace(p.PipelineVersion) == "" {
+		return fmt.Errorf("pool pipeline_version is required")
+	}
+	if p.Weight < 0 || p.Weight > 100 {
+		return fmt.Errorf("pool weight must be within [0,100]")
+	}
+	return nil
+}
+
+// BlueGreenStatus is the per-environment deployment view.
+type BlueGreenStatus struct {
+	Environment string        `json:"environment"`
+	Phase       CutoverPhase  `json:"phase"`
+	Target      PoolColor     `json:"target,omitempty"`
+	Pools       []RuntimePool `json:"pools"`
+}
+
+// GateResult is one live SLO gate evaluation of a target pool. Pending reports the gate had
+// too little live evidence to decide; the cutover keeps shifting until a later evaluation.
+type GateResult struct {
+	Passed  bool
+	Pending bool
+	Reason  string
+}
+
+// SLOGate evaluates live SLOs for the pool receiving shifted traffic.
+type SLOGate interface {
+	Evaluate(environment string, pool RuntimePool) (GateResult, error)
+}
+
+// AuditRecord captures one blue/green transition.
+type AuditRecord struct {
+	AtMS        int64     `json:"at_ms"`
+	Environment string    `json:"environment"`
+	Action      string    `json:"action"`
+	PoolID      string    `json:"pool_id,omitempty"`
+	Color       PoolColor `json:"color,omitempty"`
+	Detail      string    `json:"detail"`
+}
+
+// BlueGreenCoordinator tracks blue/green runtime pools per environment, routes new sessions
+// by pool weight, and gates promotion on live SLO evaluation. All transitions are audited.
+type BlueGreenCoordinator struct {
+	mu    sync.Mutex
+	now   func() time.Time
+	envs  map[string]*blueGreenEnv
+	audit []AuditRecord
+	// AuditSink optionally receives every audit record as it is appended.
+	AuditSink func(AuditRecord)
+}
+
+type blueGreenEnv struct {
+	phase  CutoverPhase
+	target PoolColor
+	pools  map[PoolColor]RuntimePool
+}
+
+// NewBlueGreenCoordinator returns an empty coordinator.
+func NewBlueGreenCoordinator(now func() time.Time) *BlueGreenCoordinator {
+	if now == nil {
+		now = time.Now
+	}
+	return &BlueGreenCoordinator{now: now, envs: map[string]*blueGreenEnv{}}
+}
+
+// Restore loads a previously reported status, replacing any state held for its environment.
+// Transitions are not audited; the status is assumed to come from an audited store.
+func (c *BlueGreenCoordinator) Restore(status BlueGreenStatus) error {
+	environment := strings.TrimSpace(status.Environment)
+	if environment == "" {
+		return fmt.Errorf("environment is required")
+	}
+	switch status.Phase {
+	case CutoverPhaseStable, CutoverPhaseShifting, CutoverPhasePromoted, CutoverPhaseAborted:
+	default:
+		return fmt.Errorf("unsupported cutover phase %q", status.Phase)
+	}
+	if status.Phase != CutoverPhaseStable && status.Target != PoolBlue && status.Target != PoolGreen {
+		return fmt.Errorf("cutover phase %s requires a blue|green target", status.Phase)
+	}
+	pools := make(map[PoolColor]RuntimePool, 2)
+	for _, pool := range status.Pools {
+		if err := pool.Validate(); err != nil {
+			return fmt.Errorf("restore %s pool: %w", pool.Color, err)
+		}
+		switch pool.State {
+		case PoolStateActive, PoolStateDraining, PoolStateDrained:
+		default:
+			return fmt.Errorf("restore %s pool: unsupported pool state %q", pool.Color, pool.State)
+		}
+		pools[pool.Color] = pool
+	}
+	if len(pools) != 2 || len(status.Pools) != 2 {
+		return fmt.Errorf("restore requires one blue and one green pool")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.envs[environment] = &blueGreenEnv{phase: status.Phase, target: status.Target, pools: pools}
+	return nil
+}
+
+// RegisterPools registers the blue and green pools for an environment. Weights must sum to
+// 100 and the environment starts stable.
+func (c *BlueGreenCoordinator) RegisterPools(environment string, blue RuntimePool, green RuntimePool) error {
+	environment = strings.TrimSpace(environment)
+	if environment == "" {
+		return fmt.Errorf("environment is required")
+	}
+	if blue.Color != PoolBlue || green.Color != PoolGreen {
+		return fmt.Errorf("register pools requires one blue and one green pool")
+	}
+	for _, pool := range []RuntimePool{blue, green} {
+		if err := pool.Validate(); err != nil {
+			return fmt.Errorf("register %s pool: %w", pool.Color, err)
+		}
+	}
+	if blue.PoolID == green.PoolID {
+		return fmt.Errorf("blue and green pool_id must differ")
+	}
+	if blue.Weight+green.Weight != 100 {
+		return fmt.Errorf("pool weights must sum to 100, got %d", blue.Weight+green.Weight)
+	}
+	blue.State = PoolStateActive
+	green.State = PoolStateActive
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.envs[environment] = &blueGreenEnv{
+		phase: CutoverPhaseStable,
+		pools: map[PoolColor]RuntimePool{PoolBlue: blue, PoolGreen: green},
+	}
+	c.appendAuditLocked(environment, AuditActionRegister, "", "", fmt.Sprintf("blue=%s@%d green=%s@%d", blue.PoolID, blue.Weight, green.PoolID, green.Weight))
+	return nil
+}
+
+// ShiftWeight routes targetWeight percent of new sessions to target and the rest to the
+// other pool, entering the shifting phase.
+func (c *BlueGreenCoordinator) ShiftWeight(environment string, target PoolColor, targetWeight int) error {
+	if targetWeight < 0 || targetWeight > 100 {
+		return fmt.Errorf("target weight must be within [0,100]")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	env, err := c.envLocked(environment)
+	if err != nil {
+		return err
+	}
+	if env.phase == CutoverPhaseShifting && env.target != target {
+		return fmt.Errorf("cutover to %s already in progress", env.target)
+	}
+	other := otherColor(target)
+	if _, ok := env.pools[target]; !ok {
+		return fmt.Errorf("unknown pool color %q", target)
+	}
+	targetPool := env.pools[target]
+	otherPool := env.pools[other]
+	targetPool.State, targetPool.Weight = PoolStateActive, targetWeight
+	otherPool.State, otherPool.Weight = PoolStateActive, 100-targetWeight
+	env.pools[target], env.pools[other] = targetPool, otherPool
+	env.phase, env.target = CutoverPhaseShifting, target
+	c.appendAuditLocked(environment, AuditActionShift, targetPool.PoolID, target, fmt.Sprintf("%s=%d %s=%d", target, targetWeight, other, 100-targetWeight))
+	return nil
+}
+
+// EvaluateCutover runs the live SLO gate against the shifting target pool and promotes it on
+// pass or aborts back to the other pool on failure; a pending result changes nothing. Gate
+// errors abort fail-closed.
+func (c *BlueGreenCoordinator) EvaluateCutover(environment string, gate SLOGate) (BlueGreenStatus, error) {
+	if gate == nil {
+		return BlueGreenStatus{}, fmt.Errorf("slo gate is required")
+	}
+	targetPool, err := c.CutoverTarget(environment)
+	if err != nil {
+		return BlueGreenStatus{}, err
+	}
+	// Evaluate outside the lock; gates may query live telemetry.
+	result, gateErr := gate.Evaluate(environment, targetPool)
+	if gateErr != nil {
+		result = GateResult{Passed: false, Reason: fmt.Sprintf("slo gate error: %v", gateErr)}
+	}
+	return c.CompleteCutover(environment, targetPool.PoolID, result)
+}
+
+// CutoverTarget returns the pool receiving shifted traffic in an environment's cutover.
+func (c *BlueGreenCoordinator) CutoverTarget(environment string) (RuntimePool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	env, err := c.envLocked(environment)
+	if err != nil {
+		return RuntimePool{}, err
+	}
+	if env.phase != CutoverPhaseShifting {
+		return RuntimePool{}, fmt.Errorf("no cutover in progress for environment %s", environment)
+	}
+	return env.pools[env.target], nil
+}
+
+// CompleteCutover applies a gate result evaluated against targetPoolID: it promotes the target
+// on pass, leaves the cutover shifting when the result is pending, and aborts back to the other
+// pool otherwise. It fails when the cutover moved to a different pool since the gate was
+// evaluated.
+func (c *BlueGreenCoordinator) CompleteCutover(environment string, targetPoolID string, result GateResult) (BlueGreenStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	env, err := c.envLocked(environment)
+	if err != nil {
+		return BlueGreenStatus{}, err
+	}
+	if env.phase != CutoverPhaseShifting || env.pools[env.target].PoolID != targetPoolID {
+		return BlueGreenStatus{}, fmt.Errorf("cutover for environment %s changed during gate evaluation", environment)
+	}
+	switch {
+	case result.Pending:
+	case result.Passed:
+		c.finishCutoverLocked(environment, env, env.target, CutoverPhasePromoted, AuditActionPromote, result.Reason)
+	default:
+		c.finishCutoverLocked(environment, env, otherColor(env.target), CutoverPhaseAborted, AuditActionAbort, result.Reason)
+	}
+	return statusFrom(environment, env), nil
+}
+
+// Abort rolls new-session routing back to the non-target pool without a gate evaluation.
+func (c *BlueGreenCoordinator) Abort(environment string, reason string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	env, err := c.envLocked(environment)
+	if err != nil {
+		return err
+	}
+	if env.phase != CutoverPhaseShifting {
+		return fmt.Errorf("no cutover in progress for environment %s", environment)
+	}
+	c.finishCutoverLocked(environment, env, otherColor(env.target), CutoverPhaseAborted, AuditActionAbort, reason)
+	return nil
+}
+
+// ReportActiveSessions records a draining pool's remaining session count and marks it drained
+// once it reaches zero. It returns the resulting pool state.
+func (c *BlueGreenCoordinator) ReportActiveSessions(environment string, color PoolColor, activeSessions int) (PoolState, error) {
+	if activeSessions < 0 {
+		return "", fmt.Errorf("active sessions must be >=0")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	env, err := c.envLocked(environment)
+	if err != nil {
+		return "", err
+	}
+	pool, ok := env.pools[color]
+	if !ok {
+		return "", fmt.Errorf("unknown pool color %q", color)
+	}
+	if pool.State == PoolStateDraining && activeSessions == 0 {
+		pool.State = PoolStateDrained
+		env.pools[color] = pool
+		c.appendAuditLocked(environment, AuditActionDrained, pool.PoolID, color, "no active sessions remain")
+	}
+	return pool.State, nil
+}
+
+// RouteSession deterministically selects the pool for a new session by weight.
+func (c *BlueGreenCoordinator) RouteSession(environment string, sessionID string) (RuntimePool, error) {
+	if strings.TrimSpace(sessionID) == "" {
+		return RuntimePool{}, fmt.Errorf("session_id is required")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	env, err := c.envLocked(environment)
+	if err != nil {
+		return RuntimePool{}, err
+	}
+	blue := env.pools[PoolBlue]
+	green := env.pools[PoolGreen]
+	blueWeight := routableWeight(blue)
+	if blueWeight+routableWeight(green) == 0 {
+		return RuntimePool{}, fmt.Errorf("no routable pool for environment %s", environment)
+	}
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(sessionID))
+	if int(hasher.Sum32()%100) < blueWeight {
+		return blue, nil
+	}
+	return green, nil
+}
+
+// Status returns the current blue/green view for an environment.
+func (c *BlueGreenCoordinator) Status(environment string) (BlueGreenStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	env, err := c.envLocked(environment)
+	if err != nil {
+		return BlueGreenStatus{}, err
+	}
+	return statusFrom(environment, env), nil
+}
+
+// AuditLog returns a copy of all recorded transitions in order.
+func (c *BlueGreenCoordinator) AuditLog() []AuditRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]AuditRecord(nil), c.audit...)
+}
+
+func (c *BlueGreenCoordinator) finishCutoverLocked(environment string, env *blueGreenEnv, serving PoolColor, phase CutoverPhase, action string, reason string) {
+	drain := otherColor(serving)
+	servingPool := env.pools[serving]
+	drainPool := env.pools[drain]
+	servingPool.State, servingPool.Weight = PoolStateActive, 100
+	if drainPool.State != PoolStateDrained {
+		drainPool.State = PoolStateDraining
+	}
+	drainPool.Weight = 0
+	env.pools[serving], env.pools[drain] = servingPool, drainPool
+	env.phase = phase
+	detail := fmt.Sprintf("serving=%s draining=%s", servingPool.PoolID, drainPool.PoolID)
+	if reason != "" {
+		detail += " reason=" + reason
+	}
+	c.appendAuditLocked(environment, action, env.pools[env.target].PoolID, env.target, detail)
+}
+
+func (c *BlueGreenCoordinator) envLocked(environment string) (*blueGreenEnv, error) {
+	env, ok := c.envs[strings.TrimSpace(environment)]
+	if !ok {
+		return nil, fmt.Errorf("no runtime pools registered for environment %q", environment)
+	}
+	return env, nil
+}
+
+func (c *BlueGreenCoordinator) appendAuditLocked(environment string, action string, poolID string, color PoolColor, detail string) {
+	record := AuditRecord{
+		AtMS:        c.now().UnixMilli(),
+		Environment: strings.TrimSpace(environment),
+		Action:      action,
+		PoolID:      poolID,
+		Color:       color,
+		Detail:      detail,
+	}
+	c.audit = append(c.audit, record)
+	if c.AuditSink != nil {
+		c.AuditSink(record)
+	}
+}
+
+func statusFrom(environment string, env *blueGreenEnv) BlueGreenStatus {
+	pools := make([]RuntimePool, 0, len(env.pools))
+	for _, pool := range env.pools {
+		pools = append(pools, pool)
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Color < pools[j].Color })
+	return BlueGreenStatus{Environment: strings.TrimSpace(environment), Phase: env.phase, Target: env.target, Pools: pools}
+}
+
+func routableWeight(pool RuntimePool) int {
+	if pool.State != PoolStateActive {
+		return 0
+	}
+	return pool.Weight
+}
+
+func otherColor(color PoolColor) PoolColor {
+	if color == PoolBlue {
+		return PoolGreen
+	}
+	return PoolBlue
+}
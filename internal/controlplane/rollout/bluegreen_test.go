@@ -0,0 +1,205 @@
+package rollout
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type staticGate struct {
+	result GateResult
+	err    error
+}
+
+func (g staticGate) Evaluate(string, RuntimePool) (GateResult, error) {
+	return g.result, g.err
+}
+
+func newTestCoordinator(t *testing.T) *BlueGreenCoordinator {
+	t.Helper()
+	clock := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	coordinator := NewBlueGreenCoordinator(func() time.Time { return clock })
+	err := coordinator.RegisterPools("prod",
+		RuntimePool{PoolID: "rt-blue", Color: PoolBlue, PipelineVersion: "pipeline-v1", Weight: 100},
+		RuntimePool{PoolID: "rt-green", Color: PoolGreen, PipelineVersion: "pipeline-v2", Weight: 0},
+	)
+	if err != nil {
+		t.Fatalf("unexpected register error: %v", err)
+	}
+	return coordinator
+}
+
+func routedShare(t *testing.T, coordinator *BlueGreenCoordinator, color PoolColor) int {
+	t.Helper()
+	count := 0
+	for i := 0; i < 1000; i++ {
+		pool, err := coordinator.RouteSession("prod", fmt.Sprintf("sess-%d", i))
+		if err != nil {
+			t.Fatalf("unexpected route error: %v", err)
+		}
+		if pool.Color == color {
+			count++
+		}
+	}
+	return count
+}
+
+func TestBlueGreenCutoverPendingGateKeepsShifting(t *testing.T) {
+	t.Parallel()
+
+	coordinator := newTestCoordinator(t)
+	if err := coordinator.ShiftWeight("prod", PoolGreen, 25); err != nil {
+		t.Fatalf("unexpected shift error: %v", err)
+	}
+	status, err := coordinator.EvaluateCutover("prod", staticGate{result: GateResult{Pending: true, Reason: "3 turns, need 20"}})
+	if err != nil || status.Phase != CutoverPhaseShifting || status.Target != PoolGreen {
+		t.Fatalf("expected pending gate to keep the cutover shifting, got %+v err=%v", status, err)
+	}
+	if share := routedShare(t, coordinator, PoolGreen); share < 180 || share > 320 {
+		t.Fatalf("expected ~25%% green sessions after a pending gate, got %d/1000", share)
+	}
+	if audit := coordinator.AuditLog(); len(audit) != 2 || audit[1].Action != AuditActionShift {
+		t.Fatalf("expected no transition audited for a pending gate, got %+v", audit)
+	}
+	if status, err = coordinator.EvaluateCutover("prod", staticGate{result: GateResult{Passed: true}}); err != nil || status.Phase != CutoverPhasePromoted {
+		t.Fatalf("expected a later passing gate to promote, got %+v err=%v", status, err)
+	}
+}
+
+func TestBlueGreenCutover(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		gate         staticGate
+		wantPhase    CutoverPhase
+		wantServing  PoolColor
+		wantDraining PoolColor
+		wantAction   string
+	}{
+		{name: "promote on passing gate", gate: staticGate{result: GateResult{Passed: true}}, wantPhase: CutoverPhasePromoted, wantServing: PoolGreen, wantDraining: PoolBlue, wantAction: AuditActionPromote},
+		{name: "abort on failing gate", gate: staticGate{result: GateResult{Reason: "p95 regression"}}, wantPhase: CutoverPhaseAborted, wantServing: PoolBlue, wantDraining: PoolGreen, wantAction: AuditActionAbort},
+		{name: "abort on gate error", gate: staticGate{err: errors.New("telemetry unavailable")}, wantPhase: CutoverPhaseAborted, wantServing: PoolBlue, wantDraining: PoolGreen, wantAction: AuditActionAbort},
+	}
+	for _, tc := range tests {
+		coordinator := newTestCoordinator(t)
+		if share := routedShare(t, coordinator, PoolGreen); share != 0 {
+			t.Fatalf("%s: expected no green sessions before shift, got %d", tc.name, share)
+		}
+		if err := coordinator.ShiftWeight("prod", PoolGreen, 25); err != nil {
+			t.Fatalf("%s: unexpected shift error: %v", tc.name, err)
+		}
+		if share := routedShare(t, coordinator, PoolGreen); share < 180 || share > 320 {
+			t.Fatalf("%s: expected ~25%% green sessions, got %d/1000", tc.name, share)
+		}
+
+		status, err := coordinator.EvaluateCutover("prod", tc.gate)
+		if err != nil {
+			t.Fatalf("%s: unexpected evaluate error: %v", tc.name, err)
+		}
+		if status.Phase != tc.wantPhase {
+			t.Fatalf("%s: expected phase %s, got %+v", tc.name, tc.wantPhase, status)
+		}
+		if share := routedShare(t, coordinator, tc.wantServing); share != 1000 {
+			t.Fatalf("%s: expected all new sessions on %s, got %d", tc.name, tc.wantServing, share)
+		}
+
+		state, err := coordinator.ReportActiveSessions("prod", tc.wantDraining, 3)
+		if err != nil || state != PoolStateDraining {
+			t.Fatalf("%s: expected %s draining, got %s err=%v", tc.name, tc.wantDraining, state, err)
+		}
+		state, err = coordinator.ReportActiveSessions("prod", tc.wantDraining, 0)
+		if err != nil || state != PoolStateDrained {
+			t.Fatalf("%s: expected %s drained, got %s err=%v", tc.name, tc.wantDraining, state, err)
+		}
+
+		audit := coordinator.AuditLog()
+		actions := make([]string, 0, len(audit))
+		for _, record := range audit {
+			actions = append(actions, record.Action)
+		}
+		want := []string{AuditActionRegister, AuditActionShift, tc.wantAction, AuditActionDrained}
+		if fmt.Sprint(actions) != fmt.Sprint(want) {
+			t.Fatalf("%s: expected audit actions %v, got %+v", tc.name, want, audit)
+		}
+	}
+}
+
+func TestBlueGreenCoordinatorRejectsInvalidTransitions(t *testing.T) {
+	t.Parallel()
+
+	coordinator := newTestCoordinator(t)
+	if _, err := coordinator.EvaluateCutover("prod", staticGate{}); err == nil {
+		t.Fatalf("expected evaluate without cutover to fail")
+	}
+	if err := coordinator.Abort("prod", "manual"); err == nil {
+		t.Fatalf("expected abort without cutover to fail")
+	}
+	if err := coordinator.ShiftWeight("prod", PoolGreen, 101); err == nil {
+		t.Fatalf("expected out-of-range weight to fail")
+	}
+	if err := coordinator.ShiftWeight("prod", PoolGreen, 10); err != nil {
+		t.Fatalf("unexpected shift error: %v", err)
+	}
+	if err := coordinator.ShiftWeight("prod", PoolBlue, 50); err == nil {
+		t.Fatalf("expected concurrent opposite cutover to fail")
+	}
+	if _, err := coordinator.RouteSession("staging", "sess-1"); err == nil {
+		t.Fatalf("expected unregistered environment to fail routing")
+	}
+	err := coordinator.RegisterPools("staging",
+		RuntimePool{PoolID: "rt-blue", Color: PoolBlue, PipelineVersion: "pipeline-v1", Weight: 60},
+		RuntimePool{PoolID: "rt-green", Color: PoolGreen, PipelineVersion: "pipeline-v2", Weight: 60},
+	)
+	if err == nil {
+		t.Fatalf("expected weights not summing to 100 to fail")
+	}
+}
+
+func TestBlueGreenRestoreResumesCutover(t *testing.T) {
+	t.Parallel()
+
+	source := newTestCoordinator(t)
+	if err := source.ShiftWeight("prod", PoolGreen, 20); err != nil {
+		t.Fatalf("unexpected shift error: %v", err)
+	}
+	status, err := source.Status("prod")
+	if err != nil {
+		t.Fatalf("unexpected status error: %v", err)
+	}
+
+	restored := NewBlueGreenCoordinator(nil)
+	if err := restored.Restore(status); err != nil {
+		t.Fatalf("unexpected restore error: %v", err)
+	}
+	if len(restored.AuditLog()) != 0 {
+		t.Fatalf("expected restore not to audit, got %+v", restored.AuditLog())
+	}
+	target, err := restored.CutoverTarget("prod")
+	if err != nil || target.PoolID != "rt-green" || target.Weight != 20 {
+		t.Fatalf("expected restored green cutover target, got %+v err=%v", target, err)
+	}
+	if _, err := restored.CompleteCutover("prod", "rt-blue", GateResult{Passed: true}); err == nil {
+		t.Fatalf("expected completing a different pool's cutover to fail")
+	}
+	promoted, err := restored.CompleteCutover("prod", "rt-green", GateResult{Passed: true})
+	if err != nil || promoted.Phase != CutoverPhasePromoted {
+		t.Fatalf("expected restored cutover to promote, got %+v err=%v", promoted, err)
+	}
+
+	invalid := []struct {
+		name   string
+		status BlueGreenStatus
+	}{
+		{name: "missing environment", status: BlueGreenStatus{Phase: CutoverPhaseStable, Pools: status.Pools}},
+		{name: "unknown phase", status: BlueGreenStatus{Environment: "prod", Phase: "paused", Pools: status.Pools}},
+		{name: "shifting without target", status: BlueGreenStatus{Environment: "prod", Phase: CutoverPhaseShifting, Pools: status.Pools}},
+		{name: "single pool", status: BlueGreenStatus{Environment: "prod", Phase: CutoverPhaseStable, Pools: status.Pools[:1]}},
+	}
+	for _, tc := range invalid {
+		if err := restored.Restore(tc.status); err == nil {
+			t.Fatalf("%s: expected restore error", tc.name)
+		}
+	}
+}
@@ -9,6 +9,9 @@ type ResolveVersionInput struct {
 	SessionID                string
 	RequestedPipelineVersion string
 	RegistryPipelineVersion  string
+	// Environment selects the environment's active pipeline version when the backend scopes
+	// versions per environment; empty resolves the backend's configured environment.
+	Environment string
 }
 
 // ResolveVersionOutput is the immutable turn-start pipeline version decision.
@@ -0,0 +1,95 @@
+package sessionroute
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient/sessionroutepb"
+)
+
+// GRPCPathPrefix is the path prefix every method of the session-route gRPC service is served under.
+var GRPCPathPrefix = "/" + sessionroutepb.SessionRouteService_ServiceDesc.ServiceName + "/"
+
+// NewGRPCServer serves svc as the rspp.controlplane.v1.SessionRouteService gRPC service (see
+// api/controlplaneclient/sessionroutepb/sessionroute.proto). When authToken is set every call
+// must carry it as a bearer token. The server can own a listener (Serve) or share an HTTP/2
+// one through ServeHTTP.
+func NewGRPCServer(svc Service, authToken string) *grpc.Server {
+	server := grpc.NewServer(grpc.UnaryInterceptor(grpcAuth(strings.TrimSpace(authToken))))
+	sessionroutepb.RegisterSessionRouteServiceServer(server, grpcService{svc: svc})
+	return server
+}
+
+func grpcAuth(authToken string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if authToken != "" {
+			md, _ := metadata.FromIncomingContext(ctx)
+			values := md.Get("authorization")
+			presented, ok := "", false
+			if len(values) == 1 {
+				presented, ok = strings.CutPrefix(values[0], "Bearer ")
+			}
+			if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(authToken)) != 1 {
+				return nil, status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+type grpcService struct {
+	sessionroutepb.UnimplementedSessionRouteServiceServer
+
+	svc Service
+}
+
+func (s grpcService) ResolveSessionRoute(ctx context.Context, req *sessionroutepb.ResolveSessionRouteRequest) (*sessionroutepb.SessionRoute, error) {
+	route, err := s.svc.ResolveSessionRoute(controlplaneclient.ResolveSessionRouteRequestFromProto(req))
+	if err = grpcStatus(ctx, err); err != nil {
+		return nil, err
+	}
+	return route.Proto(), nil
+}
+
+func (s grpcService) IssueSessionToken(ctx context.Context, req *sessionroutepb.IssueSessionTokenRequest) (*sessionroutepb.SessionToken, error) {
+	token, err := s.svc.IssueSessionToken(controlplaneclient.IssueSessionTokenRequestFromProto(req))
+	if err = grpcStatus(ctx, err); err != nil {
+		return nil, err
+	}
+	return token.Proto(), nil
+}
+
+func (s grpcService) SessionStatus(ctx context.Context, req *sessionroutepb.SessionStatusRequest) (*sessionroutepb.SessionStatus, error) {
+	sessionStatus, err := s.svc.SessionStatus(req.GetSessionId())
+	if err = grpcStatus(ctx, err); err != nil {
+		return nil, err
+	}
+	return sessionStatus.Proto(), nil
+}
+
+// grpcStatus maps a service error onto its gRPC status. Service calls do not block on the
+// network, so the deadline is checked after them rather than threaded through: a caller that
+// has already given up gets DEADLINE_EXCEEDED.
+func grpcStatus(ctx context.Context, err error) error {
+	if err == nil {
+		err = ctx.Err()
+	}
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "call canceled")
+	default:
+		return status.Error(controlplaneclient.GRPCCode(Code(err)), err.Error())
+	}
+}
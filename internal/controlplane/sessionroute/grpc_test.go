@@ -0,0 +1,200 @@
+package sessionroute
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient/sessionroutepb"
+)
+
+// newGRPCTestServer serves the gRPC service through ServeHTTP on an h2c listener, as the
+// control-plane serve mode does.
+func newGRPCTestServer(t *testing.T, svc Service, authToken string) string {
+	t.Helper()
+	server := httptest.NewUnstartedServer(NewGRPCServer(svc, authToken))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestGRPCServerServesSessionRoutes(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestService(t)
+	url := newGRPCTestServer(t, svc, "grpc-token")
+	client, err := controlplaneclient.NewGRPC(controlplaneclient.Config{BaseURL: url, AuthBearerToken: "grpc-token"})
+	if err != nil {
+		t.Fatalf("unexpected client error: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	route, err := client.ResolveSessionRoute(ctx, controlplaneclient.ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: "sess-1", RequestedPipelineVersion: "canary"})
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	if route.PipelineVersion != "pipeline-v2" || route.GraphDefinitionRef != "graph/v2" || route.AuthorityEpoch != 7 || route.RuntimeEndpoint != "wss://runtime.example/ws" {
+		t.Fatalf("unexpected route %+v", route)
+	}
+
+	token, err := client.IssueSessionToken(ctx, controlplaneclient.IssueSessionTokenRequest{TenantID: "tenant-a", SessionID: "sess-1"})
+	if err != nil {
+		t.Fatalf("unexpected token error: %v", err)
+	}
+	if claims, err := svc.VerifySessionToken(token.Token); err != nil || claims.SessionID != "sess-1" || token.ExpiresAtMS == 0 {
+		t.Fatalf("expected verifiable token, got %+v claims=%+v (%v)", token, claims, err)
+	}
+
+	if _, err := svc.SetSessionStatus(controlplaneclient.SetSessionStatusRequest{SessionID: "sess-1", Status: controlplaneclient.SessionStatusEnded, Reason: "client_hangup"}); err != nil {
+		t.Fatalf("unexpected set status error: %v", err)
+	}
+	status, err := client.SessionStatus(ctx, controlplaneclient.SessionStatusRequest{SessionID: "sess-1"})
+	if err != nil || status.Status != controlplaneclient.SessionStatusEnded || status.Reason != "client_hangup" {
+		t.Fatalf("unexpected status %+v (%v)", status, err)
+	}
+}
+
+func TestGRPCServerMapsErrorsToStatuses(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestService(t)
+	url := newGRPCTestServer(t, svc, "grpc-token")
+	unauthenticated, err := controlplaneclient.NewGRPC(controlplaneclient.Config{BaseURL: url, AuthBearerToken: "wrong", RetryMaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("unexpected client error: %v", err)
+	}
+	t.Cleanup(func() { _ = unauthenticated.Close() })
+	client, err := controlplaneclient.NewGRPC(controlplaneclient.Config{BaseURL: url, AuthBearerToken: "grpc-token", RetryMaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("unexpected client error: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	tests := []struct {
+		name     string
+		call     func() error
+		wantErr  error
+		wantCode string
+	}{
+		{
+			name: "bad token",
+			call: func() error {
+				_, err := unauthenticated.SessionStatus(context.Background(), controlplaneclient.SessionStatusRequest{SessionID: "sess-1"})
+				return err
+			},
+			wantErr:  controlplaneclient.ErrUnauthorized,
+			wantCode: controlplaneclient.CodeUnauthorized,
+		},
+		{
+			name: "unknown session",
+			call: func() error {
+				_, err := client.SessionStatus(context.Background(), controlplaneclient.SessionStatusRequest{SessionID: "sess-unknown"})
+				return err
+			},
+			wantErr:  controlplaneclient.ErrNotFound,
+			wantCode: controlplaneclient.CodeNotFound,
+		},
+		{
+			name: "token ttl over max",
+			call: func() error {
+				_, err := client.IssueSessionToken(context.Background(), controlplaneclient.IssueSessionTokenRequest{TenantID: "tenant-a", SessionID: "sess-1", TTLMS: 2 * MaxTokenTTL.Milliseconds()})
+				return err
+			},
+			wantErr:  controlplaneclient.ErrInvalidRequest,
+			wantCode: controlplaneclient.CodeInvalidRequest,
+		},
+	}
+	for _, tc := range tests {
+		err := tc.call()
+		var apiErr *controlplaneclient.APIError
+		if !errors.Is(err, tc.wantErr) || !errors.As(err, &apiErr) || apiErr.Code != tc.wantCode {
+			t.Fatalf("%s: expected %s, got %v", tc.name, tc.wantCode, err)
+		}
+	}
+}
+
+// TestGRPCServerInteropsWithGeneratedClient calls the service through the stock generated
+// stub on a listener the gRPC server owns, so the contract does not depend on this repo's client.
+func TestGRPCServerInteropsWithGeneratedClient(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestService(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %v", err)
+	}
+	server := NewGRPCServer(svc, "grpc-token")
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("unexpected dial error: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	stub := sessionroutepb.NewSessionRouteServiceClient(conn)
+	authorized := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer grpc-token")
+
+	route, err := stub.ResolveSessionRoute(authorized, &sessionroutepb.ResolveSessionRouteRequest{TenantId: "tenant-a", SessionId: "sess-1", RequestedPipelineVersion: "canary"})
+	if err != nil || route.GetPipelineVersion() != "pipeline-v2" || route.GetAuthorityEpoch() != 7 || route.GetRuntimeEndpoint() != "wss://runtime.example/ws" {
+		t.Fatalf("expected the canary route, got %v err=%v", route, err)
+	}
+
+	expired, cancel := context.WithDeadline(authorized, time.Now().Add(-time.Second))
+	defer cancel()
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{
+			name: "no token",
+			call: func() error {
+				_, err := stub.SessionStatus(context.Background(), &sessionroutepb.SessionStatusRequest{SessionId: "sess-1"})
+				return err
+			},
+			want: codes.Unauthenticated,
+		},
+		{
+			name: "missing session id",
+			call: func() error {
+				_, err := stub.ResolveSessionRoute(authorized, &sessionroutepb.ResolveSessionRouteRequest{TenantId: "tenant-a"})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "expired deadline",
+			call: func() error {
+				_, err := stub.SessionStatus(expired, &sessionroutepb.SessionStatusRequest{SessionId: "sess-1"})
+				return err
+			},
+			want: codes.DeadlineExceeded,
+		},
+		{
+			name: "unknown method",
+			call: func() error {
+				return conn.Invoke(authorized, GRPCPathPrefix+"DeleteSession", &sessionroutepb.SessionStatusRequest{}, &sessionroutepb.SessionStatus{})
+			},
+			want: codes.Unimplemented,
+		},
+	}
+	for _, tc := range tests {
+		if got := status.Code(tc.call()); got != tc.want {
+			t.Fatalf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+}
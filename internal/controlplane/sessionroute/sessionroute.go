@@ -0,0 +1,304 @@
+package sessionroute
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/lease"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/registry"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/rollout"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/routingview"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sharding"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/identifiers"
+)
+
+const (
+	// EnvTokenSecret configures the HMAC secret session tokens are signed with.
+	EnvTokenSecret = "RSPP_CP_SESSION_TOKEN_SECRET"
+	// EnvRuntimeEndpoint configures the runtime endpoint returned with resolved routes.
+	EnvRuntimeEndpoint = "RSPP_CP_RUNTIME_ENDPOINT"
+
+	DefaultTokenTTL = 5 * time.Minute
+	MaxTokenTTL     = time.Hour
+)
+
+// Sentinel errors classify failures for API error mapping; see Code.
+var (
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrNotFound       = errors.New("not found")
+	ErrConflict       = errors.New("conflict")
+	ErrUnavailable    = errors.New("unavailable")
+)
+
+// SessionStore persists session lifecycle statuses.
+type SessionStore interface {
+	PutSessionStatus(status controlplaneclient.SessionStatus) error
+	// GetSessionStatus returns false when the session has no recorded status.
+	GetSessionStatus(sessionID string) (controlplaneclient.SessionStatus, bool, error)
+}
+
+// Service resolves session routes, issues session tokens, and records session statuses for a
+// live control plane.
+type Service struct {
+	// Backends loads the CP service backends per call, so a publish or rollback applies to the
+	// next resolved session without a restart.
+	Backends        func() (distribution.ServiceBackends, error)
+	Sessions        SessionStore
+	TokenSecret     []byte
+	DefaultTokenTTL time.Duration
+	RuntimeEndpoint string
+	Now             func() time.Time
+}
+
+// NewFileService returns a service over the file-backed distribution state at path, with the
+// token secret and runtime endpoint read from environment.
+func NewFileService(path string, sessions SessionStore) Service {
+	return Service{
+		Backends: func() (distribution.ServiceBackends, error) {
+			return distribution.NewFileBackends(distribution.FileAdapterConfig{Path: path})
+		},
+		Sessions:        sessions,
+		TokenSecret:     []byte(os.Getenv(EnvTokenSecret)),
+		RuntimeEndpoint: strings.TrimSpace(os.Getenv(EnvRuntimeEndpoint)),
+	}
+}
+
+// ResolveSessionRoute resolves the pipeline version, routing snapshot, and authority epoch a
+// new session runs with, the same way runtime turn-start resolution reads the state.
+func (s Service) ResolveSessionRoute(req controlplaneclient.ResolveSessionRouteRequest) (controlplaneclient.SessionRoute, error) {
+	if err := req.Validate(); err != nil {
+		return controlplaneclient.SessionRoute{}, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	if s.Backends == nil {
+		return controlplaneclient.SessionRoute{}, fmt.Errorf("%w: distribution backends are not configured", ErrUnavailable)
+	}
+	backends, err := s.Backends()
+	if err != nil {
+		return controlplaneclient.SessionRoute{}, err
+	}
+	resolved, err := rollout.Service{Backend: backends.Rollout}.ResolvePipelineVersion(rollout.ResolveVersionInput{
+		SessionID:                req.SessionID,
+		RequestedPipelineVersion: req.RequestedPipelineVersion,
+		Environment:              req.Environment,
+	})
+	if err != nil {
+		return controlplaneclient.SessionRoute{}, fmt.Errorf("resolve pipeline version: %w", err)
+	}
+	record, err := registry.Service{Backend: backends.Registry}.ResolvePipelineRecord(resolved.PipelineVersion)
+	if err != nil {
+		return controlplaneclient.SessionRoute{}, fmt.Errorf("resolve pipeline record: %w", err)
+	}
+	snapshot, err := routingview.Service{Backend: backends.RoutingView}.GetSnapshot(routingview.Input{SessionID: req.SessionID, PipelineVersion: resolved.PipelineVersion})
+	if err != nil {
+		return controlplaneclient.SessionRoute{}, fmt.Errorf("resolve routing snapshot: %w", err)
+	}
+	authority, err := lease.Service{Backend: backends.Lease}.Resolve(lease.Input{SessionID: req.SessionID, PipelineVersion: resolved.PipelineVersion})
+	if err != nil {
+		return controlplaneclient.SessionRoute{}, fmt.Errorf("resolve lease authority: %w", err)
+	}
+	route := controlplaneclient.SessionRoute{
+		TenantID:            req.TenantID,
+		SessionID:           req.SessionID,
+		PipelineVersion:     resolved.PipelineVersion,
+		GraphDefinitionRef:  record.GraphDefinitionRef,
+		ExecutionProfile:    record.ExecutionProfile,
+		SpecHash:            record.SpecHash,
+		RoutingViewSnapshot: snapshot.RoutingViewSnapshot,
+		AuthorityEpoch:      authority.AuthorityEpoch,
+		RuntimeEndpoint:     s.RuntimeEndpoint,
+	}
+	if err := s.routeToShardOwner(backends, &route); err != nil {
+		return controlplaneclient.SessionRoute{}, err
+	}
+	return route, nil
+}
+
+// routeToShardOwner points route at the runtime worker that owns the session under the
+// published shard map, at the shard map's authority epoch when it is ahead of the lease's, so
+// the owner admits the session's turns. Without a shard map the route is left unchanged.
+func (s Service) routeToShardOwner(backends distribution.ServiceBackends, route *controlplaneclient.SessionRoute) error {
+	if backends.Sharding == nil {
+		return nil
+	}
+	shardMap, err := backends.Sharding.GetShardMap(sharding.Input{SessionID: route.SessionID, RequestedAuthorityEpoch: route.AuthorityEpoch})
+	var backendErr distribution.BackendError
+	if errors.As(err, &backendErr) && backendErr.Code == distribution.ErrorCodeSnapshotMissing {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("resolve shard map: %w", err)
+	}
+	owner, endpoint, err := shardMap.Owner(route.SessionID)
+	if err != nil || owner == "" {
+		return err
+	}
+	route.ShardMapSnapshot, route.ShardOwner = shardMap.ShardMapSnapshot, owner
+	route.AuthorityEpoch = max(route.AuthorityEpoch, shardMap.AuthorityEpoch)
+	if endpoint != "" {
+		route.RuntimeEndpoint = endpoint
+	}
+	return nil
+}
+
+// TokenClaims are the signed contents of a session token.
+type TokenClaims struct {
+	TenantID    string `json:"tenant_id"`
+	SessionID   string `json:"session_id"`
+	IssuedAtMS  int64  `json:"issued_at_ms"`
+	ExpiresAtMS int64  `json:"expires_at_ms"`
+}
+
+// IssueSessionToken issues an HMAC-signed token binding the tenant and session until expiry.
+func (s Service) IssueSessionToken(req controlplaneclient.IssueSessionTokenRequest) (controlplaneclient.SessionToken, error) {
+	if err := req.Validate(); err != nil {
+		return controlplaneclient.SessionToken{}, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	if len(s.TokenSecret) == 0 {
+		return controlplaneclient.SessionToken{}, fmt.Errorf("%w: %s is not configured", ErrUnavailable, EnvTokenSecret)
+	}
+	ttl := time.Duration(req.TTLMS) * time.Millisecond
+	if ttl == 0 {
+		ttl = s.DefaultTokenTTL
+	}
+	if ttl <= 0 {
+		ttl = DefaultTokenTTL
+	}
+	if ttl > MaxTokenTTL {
+		return controlplaneclient.SessionToken{}, fmt.Errorf("%w: ttl_ms must be <=%d", ErrInvalidRequest, MaxTokenTTL.Milliseconds())
+	}
+	now := s.now()
+	claims := TokenClaims{
+		TenantID:    req.TenantID,
+		SessionID:   req.SessionID,
+		IssuedAtMS:  now.UnixMilli(),
+		ExpiresAtMS: now.Add(ttl).UnixMilli(),
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return controlplaneclient.SessionToken{}, err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return controlplaneclient.SessionToken{
+		SessionID:   req.SessionID,
+		Token:       encoded + "." + s.sign(encoded),
+		ExpiresAtMS: claims.ExpiresAtMS,
+	}, nil
+}
+
+// VerifySessionToken checks a token's signature and expiry and returns its claims.
+func (s Service) VerifySessionToken(token string) (TokenClaims, error) {
+	if len(s.TokenSecret) == 0 {
+		return TokenClaims{}, fmt.Errorf("%w: %s is not configured", ErrUnavailable, EnvTokenSecret)
+	}
+	encoded, signature, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.sign(encoded))) {
+		return TokenClaims{}, fmt.Errorf("%w: session token signature is invalid", ErrInvalidRequest)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return TokenClaims{}, fmt.Errorf("%w: session token payload is invalid", ErrInvalidRequest)
+	}
+	claims := TokenClaims{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return TokenClaims{}, fmt.Errorf("%w: session token payload is invalid", ErrInvalidRequest)
+	}
+	if s.now().UnixMilli() >= claims.ExpiresAtMS {
+		return TokenClaims{}, fmt.Errorf("%w: session token expired", ErrInvalidRequest)
+	}
+	return claims, nil
+}
+
+// SetSessionStatus records a session status. Ended and failed are terminal: a session cannot
+// become active again.
+func (s Service) SetSessionStatus(req controlplaneclient.SetSessionStatusRequest) (controlplaneclient.SessionStatus, error) {
+	if err := req.Validate(); err != nil {
+		return controlplaneclient.SessionStatus{}, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	if s.Sessions == nil {
+		return controlplaneclient.SessionStatus{}, fmt.Errorf("%w: session store is not configured", ErrUnavailable)
+	}
+	current, ok, err := s.Sessions.GetSessionStatus(req.SessionID)
+	if err != nil {
+		return controlplaneclient.SessionStatus{}, err
+	}
+	if ok && current.Status != controlplaneclient.SessionStatusActive && current.Status != req.Status {
+		return controlplaneclient.SessionStatus{}, fmt.Errorf("%w: session %s is already %s", ErrConflict, req.SessionID, current.Status)
+	}
+	status := controlplaneclient.SessionStatus{
+		SessionID:   req.SessionID,
+		Status:      req.Status,
+		Reason:      req.Reason,
+		UpdatedAtMS: s.now().UnixMilli(),
+	}
+	if err := s.Sessions.PutSessionStatus(status); err != nil {
+		return controlplaneclient.SessionStatus{}, err
+	}
+	return status, nil
+}
+
+// SessionStatus returns the recorded status of a session.
+func (s Service) SessionStatus(sessionID string) (controlplaneclient.SessionStatus, error) {
+	if err := identifiers.ValidateSessionID(sessionID); err != nil {
+		return controlplaneclient.SessionStatus{}, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	if s.Sessions == nil {
+		return controlplaneclient.SessionStatus{}, fmt.Errorf("%w: session store is not configured", ErrUnavailable)
+	}
+	status, ok, err := s.Sessions.GetSessionStatus(sessionID)
+	if err != nil {
+		return controlplaneclient.SessionStatus{}, err
+	}
+	if !ok {
+		return controlplaneclient.SessionStatus{}, fmt.Errorf("%w: session %s has no recorded status", ErrNotFound, sessionID)
+	}
+	return status, nil
+}
+
+// Code classifies err with the control-plane API error codes.
+func Code(err error) string {
+	var backendErr distribution.BackendError
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrInvalidRequest):
+		return controlplaneclient.CodeInvalidRequest
+	case errors.Is(err, ErrNotFound):
+		return controlplaneclient.CodeNotFound
+	case errors.Is(err, ErrConflict):
+		return controlplaneclient.CodeConflict
+	case errors.Is(err, ErrUnavailable):
+		return controlplaneclient.CodeUnavailable
+	case errors.As(err, &backendErr):
+		switch backendErr.Code {
+		case distribution.ErrorCodeSnapshotMissing:
+			return controlplaneclient.CodeNotFound
+		case distribution.ErrorCodeSnapshotStale, distribution.ErrorCodeStateLocked:
+			return controlplaneclient.CodeUnavailable
+		case distribution.ErrorCodeInvalidConfig:
+			return controlplaneclient.CodeInvalidRequest
+		}
+	}
+	return controlplaneclient.CodeInternal
+}
+
+func (s Service) sign(encoded string) string {
+	mac := hmac.New(sha256.New, s.TokenSecret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,198 @@
+package sessionroute
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+)
+
+const routeFixture = `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "registry": {
+    "default_pipeline_version": "pipeline-v1",
+    "records": {
+      "pipeline-v1": {"pipeline_version": "pipeline-v1", "graph_definition_ref": "graph/v1", "execution_profile": "simple"},
+      "pipeline-v2": {"pipeline_version": "pipeline-v2", "graph_definition_ref": "graph/v2", "execution_profile": "simple"}
+    }
+  },
+  "rollout": {"default_pipeline_version": "pipeline-v1", "by_requested_version": {"canary": "pipeline-v2"}},
+  "routing_view": {"default": {"routing_view_snapshot": "routing-view/file", "admission_policy_snapshot": "admission-policy/file", "abi_compatibility_snapshot": "abi-compat/file"}},
+  "lease": {"default": {"lease_resolution_snapshot": "lease/file", "authority_epoch": 7}}
+}`
+
+type memorySessions struct {
+	mu       sync.Mutex
+	statuses map[string]controlplaneclient.SessionStatus
+}
+
+func (m *memorySessions) PutSessionStatus(status controlplaneclient.SessionStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.statuses == nil {
+		m.statuses = map[string]controlplaneclient.SessionStatus{}
+	}
+	m.statuses[status.SessionID] = status
+	return nil
+}
+
+func (m *memorySessions) GetSessionStatus(sessionID string) (controlplaneclient.SessionStatus, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status, ok := m.statuses[sessionID]
+	return status, ok, nil
+}
+
+func newTestService(t *testing.T) (Service, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "distribution.json")
+	if err := os.WriteFile(path, []byte(routeFixture), 0o600); err != nil {
+		t.Fatalf("write state: %v", err)
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc := Service{
+		Backends: func() (distribution.ServiceBackends, error) {
+			return distribution.NewFileBackends(distribution.FileAdapterConfig{Path: path})
+		},
+		Sessions:        &memorySessions{},
+		TokenSecret:     []byte("test-secret"),
+		RuntimeEndpoint: "wss://runtime.example/ws",
+		Now:             func() time.Time { return now },
+	}
+	return svc, path
+}
+
+func TestResolveSessionRouteFollowsPublishedState(t *testing.T) {
+	t.Parallel()
+
+	svc, path := newTestService(t)
+	route, err := svc.ResolveSessionRoute(controlplaneclient.ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: "sess-1"})
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	if route.PipelineVersion != "pipeline-v1" || route.GraphDefinitionRef != "graph/v1" || route.RoutingViewSnapshot != "routing-view/file" || route.AuthorityEpoch != 7 || route.RuntimeEndpoint != "wss://runtime.example/ws" {
+		t.Fatalf("unexpected route %+v", route)
+	}
+	if route, err := svc.ResolveSessionRoute(controlplaneclient.ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: "sess-2", RequestedPipelineVersion: "canary"}); err != nil || route.PipelineVersion != "pipeline-v2" {
+		t.Fatalf("expected requested canary to resolve pipeline-v2, got %+v err=%v", route, err)
+	}
+
+	if _, err := distribution.SetActivePipelineVersion(path, "pipeline-v2"); err != nil {
+		t.Fatalf("unexpected set active version error: %v", err)
+	}
+	if route, err := svc.ResolveSessionRoute(controlplaneclient.ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: "sess-3"}); err != nil || route.PipelineVersion != "pipeline-v2" {
+		t.Fatalf("expected the next session to follow the new active version, got %+v err=%v", route, err)
+	}
+
+	if _, err := svc.ResolveSessionRoute(controlplaneclient.ResolveSessionRouteRequest{SessionID: "sess-4"}); Code(err) != controlplaneclient.CodeInvalidRequest {
+		t.Fatalf("expected invalid request without tenant, got %v", err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(routeFixture, `"lease": {"default"`, `"lease": {"stale": true, "default"`, 1)), 0o600); err != nil {
+		t.Fatalf("write stale state: %v", err)
+	}
+	if _, err := svc.ResolveSessionRoute(controlplaneclient.ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: "sess-5"}); Code(err) != controlplaneclient.CodeUnavailable {
+		t.Fatalf("expected stale lease snapshot to be unavailable, got %v", err)
+	}
+}
+
+func TestResolveSessionRouteRoutesToShardOwner(t *testing.T) {
+	t.Parallel()
+
+	svc, path := newTestService(t)
+	endpoints := map[string]string{"runtime-a": "wss://runtime-a/ws", "runtime-b": "wss://runtime-b/ws"}
+	shardMap, err := distribution.PublishShardMap(path, []string{"runtime-a", "runtime-b"}, endpoints, 0)
+	if err != nil || shardMap.AuthorityEpoch != 8 {
+		t.Fatalf("expected the shard map published past lease epoch 7, got %+v err=%v", shardMap, err)
+	}
+
+	owners := map[string]bool{}
+	for _, sessionID := range []string{"sess-1", "sess-2", "sess-3", "sess-4", "sess-5", "sess-6", "sess-7", "sess-8"} {
+		route, err := svc.ResolveSessionRoute(controlplaneclient.ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: sessionID})
+		if err != nil {
+			t.Fatalf("%s: unexpected resolve error: %v", sessionID, err)
+		}
+		owner, _, _ := shardMap.Owner(sessionID)
+		if route.ShardOwner != owner || route.RuntimeEndpoint != endpoints[owner] || route.ShardMapSnapshot != shardMap.ShardMapSnapshot || route.AuthorityEpoch != 8 {
+			t.Fatalf("%s: expected routing to shard owner %s, got %+v", sessionID, owner, route)
+		}
+		owners[owner] = true
+	}
+	if len(owners) != 2 {
+		t.Fatalf("expected sessions spread across both workers, got %v", owners)
+	}
+
+	if _, err := distribution.PublishShardMap(path, nil, nil, 0); err != nil {
+		t.Fatalf("unexpected disable error: %v", err)
+	}
+	route, err := svc.ResolveSessionRoute(controlplaneclient.ResolveSessionRouteRequest{TenantID: "tenant-a", SessionID: "sess-1"})
+	if err != nil || route.ShardOwner != "" || route.RuntimeEndpoint != "wss://runtime.example/ws" || route.AuthorityEpoch != 7 {
+		t.Fatalf("expected an unsharded route after disabling the shard map, got %+v err=%v", route, err)
+	}
+}
+
+func TestIssueAndVerifySessionToken(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestService(t)
+	token, err := svc.IssueSessionToken(controlplaneclient.IssueSessionTokenRequest{TenantID: "tenant-a", SessionID: "sess-1"})
+	if err != nil {
+		t.Fatalf("unexpected issue error: %v", err)
+	}
+	if token.ExpiresAtMS != svc.Now().Add(DefaultTokenTTL).UnixMilli() {
+		t.Fatalf("expected default ttl, got %+v", token)
+	}
+	claims, err := svc.VerifySessionToken(token.Token)
+	if err != nil || claims.TenantID != "tenant-a" || claims.SessionID != "sess-1" {
+		t.Fatalf("expected verified claims, got %+v err=%v", claims, err)
+	}
+
+	other := svc
+	other.TokenSecret = []byte("other-secret")
+	if _, err := other.VerifySessionToken(token.Token); Code(err) != controlplaneclient.CodeInvalidRequest {
+		t.Fatalf("expected token signed with another secret to be rejected, got %v", err)
+	}
+	later := svc
+	later.Now = func() time.Time { return svc.Now().Add(DefaultTokenTTL) }
+	if _, err := later.VerifySessionToken(token.Token); err == nil {
+		t.Fatalf("expected expired token to be rejected")
+	}
+	if _, err := svc.IssueSessionToken(controlplaneclient.IssueSessionTokenRequest{TenantID: "tenant-a", SessionID: "sess-1", TTLMS: 2 * MaxTokenTTL.Milliseconds()}); Code(err) != controlplaneclient.CodeInvalidRequest {
+		t.Fatalf("expected ttl above the maximum to be rejected, got %v", err)
+	}
+	unsigned := svc
+	unsigned.TokenSecret = nil
+	if _, err := unsigned.IssueSessionToken(controlplaneclient.IssueSessionTokenRequest{TenantID: "tenant-a", SessionID: "sess-1"}); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("expected missing secret to be unavailable, got %v", err)
+	}
+}
+
+func TestSessionStatusLifecycle(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestService(t)
+	if _, err := svc.SessionStatus("sess-1"); Code(err) != controlplaneclient.CodeNotFound {
+		t.Fatalf("expected unknown session to be not found, got %v", err)
+	}
+	if _, err := svc.SetSessionStatus(controlplaneclient.SetSessionStatusRequest{SessionID: "sess-1", Status: controlplaneclient.SessionStatusActive}); err != nil {
+		t.Fatalf("unexpected active error: %v", err)
+	}
+	ended, err := svc.SetSessionStatus(controlplaneclient.SetSessionStatusRequest{SessionID: "sess-1", Status: controlplaneclient.SessionStatusEnded, Reason: "caller hung up"})
+	if err != nil || ended.UpdatedAtMS != svc.Now().UnixMilli() {
+		t.Fatalf("unexpected ended status %+v err=%v", ended, err)
+	}
+	if status, err := svc.SessionStatus("sess-1"); err != nil || status.Status != controlplaneclient.SessionStatusEnded || status.Reason != "caller hung up" {
+		t.Fatalf("expected recorded ended status, got %+v err=%v", status, err)
+	}
+	if _, err := svc.SetSessionStatus(controlplaneclient.SetSessionStatusRequest{SessionID: "sess-1", Status: controlplaneclient.SessionStatusActive}); Code(err) != controlplaneclient.CodeConflict {
+		t.Fatalf("expected reactivating an ended session to conflict, got %v", err)
+	}
+	if _, err := svc.SetSessionStatus(controlplaneclient.SetSessionStatusRequest{SessionID: "sess-1", Status: "paused"}); Code(err) != controlplaneclient.CodeInvalidRequest {
+		t.Fatalf("expected unknown status to be invalid, got %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package sharding
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	// EnvWorkerID identifies this rspp-runtime instance in the shard map.
+	EnvWorkerID = "RSPP_RUNTIME_WORKER_ID"
+	// DefaultVirtualNodes is the ring points per worker when a shard map does not set one.
+	DefaultVirtualNodes = 64
+)
+
+const (
+	// ReasonShardingDisabled marks resolution without a published shard map.
+	ReasonShardingDisabled = "sharding_disabled"
+	// ReasonShardOwned marks sessions owned by the resolving worker.
+	ReasonShardOwned = "shard_owned"
+	// ReasonShardNotOwned marks sessions hashed to another worker.
+	ReasonShardNotOwned = "shard_not_owned"
+	// ReasonShardMapEpochAdvanced marks authority that predates the current shard map.
+	ReasonShardMapEpochAdvanced = "shard_map_epoch_advanced"
+	// ReasonShardingInvalidInput marks shard ownership input validation failures.
+	ReasonShardingInvalidInput = "sharding_invalid_input"
+)
+
+// ShardMap is the CP-published set of runtime workers sharing session load.
+// Every shard map change must advance AuthorityEpoch so sessions re-resolve ownership.
+type ShardMap struct {
+	ShardMapSnapshot string
+	AuthorityEpoch   int64
+	Workers          []string
+	VirtualNodes     int
+	// Endpoints maps worker ids to the runtime endpoint sessions they own connect to.
+	Endpoints map[string]string
+}
+
+// Enabled reports whether the map assigns sessions to workers.
+func (m ShardMap) Enabled() bool {
+	return len(m.Workers) > 0
+}
+
+// Validate enforces shard map invariants for enabled maps.
+func (m ShardMap) Validate() error {
+	if !m.Enabled() {
+		return nil
+	}
+	if m.ShardMapSnapshot == "" {
+		return fmt.Errorf("shard_map_snapshot is required")
+	}
+	if m.AuthorityEpoch < 0 {
+		return fmt.Errorf("shard map authority_epoch must be >=0")
+	}
+	if m.VirtualNodes < 0 {
+		return fmt.Errorf("shard map virtual_nodes must be >=0")
+	}
+	seen := make(map[string]struct{}, len(m.Workers))
+	for _, worker := range m.Workers {
+		if strings.TrimSpace(worker) == "" {
+			return fmt.Errorf("shard map worker ids must be non-empty")
+		}
+		if _, ok := seen[worker]; ok {
+			return fmt.Errorf("duplicate shard map worker: %s", worker)
+		}
+		seen[worker] = struct{}{}
+	}
+	for worker := range m.Endpoints {
+		if _, ok := seen[worker]; !ok {
+			return fmt.Errorf("shard map endpoint for unknown worker: %s", worker)
+		}
+	}
+	return nil
+}
+
+// Owner returns the worker that owns sessionID and its endpoint, or empty strings when the map
+// is disabled.
+func (m ShardMap) Owner(sessionID string) (string, string, error) {
+	if !m.Enabled() {
+		return "", "", nil
+	}
+	ring, err := NewRing(m)
+	if err != nil {
+		return "", "", fmt.Errorf("build shard ring: %w", err)
+	}
+	owner := ring.Owner(sessionID)
+	return owner, m.Endpoints[owner], nil
+}
+
+// Ring is a consistent-hash ring over shard map workers.
+type Ring struct {
+	points []ringPoint
+}
+
+type ringPoint struct {
+	hash   uint64
+	worker string
+}
+
+// NewRing builds the consistent-hash ring for an enabled shard map.
+func NewRing(m ShardMap) (Ring, error) {
+	if err := m.Validate(); err != nil {
+		return Ring{}, err
+	}
+	if !m.Enabled() {
+		return Ring{}, fmt.Errorf("shard map has no workers")
+	}
+	vnodes := m.VirtualNodes
+	if vnodes == 0 {
+		vnodes = DefaultVirtualNodes
+	}
+	points := make([]ringPoint, 0, len(m.Workers)*vnodes)
+	for _, worker := range m.Workers {
+		for idx := 0; idx < vnodes; idx++ {
+			points = append(points, ringPoint{hash: hashKey(fmt.Sprintf("%s#%d", worker, idx)), worker: worker})
+		}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].hash != points[j].hash {
+			return points[i].hash < points[j].hash
+		}
+		return points[i].worker < points[j].worker
+	})
+	return Ring{points: points}, nil
+}
+
+// Owner returns the worker that owns sessionID.
+func (r Ring) Owner(sessionID string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	h := hashKey(sessionID)
+	idx := sort.Search(len(r.points), func(i int) bool { return r.points[i].hash >= h })
+	if idx == len(r.points) {
+		idx = 0
+	}
+	return r.points[idx].worker
+}
+
+func hashKey(key string) uint64 {
+	sum := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// Input models shard ownership resolution for one session on this worker.
+type Input struct {
+	SessionID               string
+	RequestedAuthorityEpoch int64
+}
+
+// Output is the deterministic shard ownership artifact recorded in routing evidence.
+type Output struct {
+	ShardMapSnapshot string
+	OwnerWorkerID    string
+	// OwnerEndpoint is where a session owned by another worker should reconnect.
+	OwnerEndpoint  string
+	AuthorityEpoch int64
+	EpochValid     bool
+	Owned          bool
+	Reason         string
+}
+
+// Backend resolves the current shard map from a snapshot-fed control-plane source.
+type Backend interface {
+	GetShardMap(in Input) (ShardMap, error)
+}
+
+// Service resolves session shard ownership for the local WorkerID.
+type Service struct {
+	WorkerID   string
+	DefaultMap ShardMap
+	Backend    Backend
+}
+
+// NewService returns a sharding service for the env-configured runtime worker.
+func NewService() Service {
+	return Service{WorkerID: WorkerIDFromEnv()}
+}
+
+// WorkerIDFromEnv returns the configured runtime worker id.
+func WorkerIDFromEnv() string {
+	return strings.TrimSpace(os.Getenv(EnvWorkerID))
+}
+
+// Resolve reports whether WorkerID owns the session under the current shard map. A shard map
+// epoch ahead of the requested authority epoch invalidates the request so the session is
+// re-resolved under the new map.
+func (s Service) Resolve(in Input) (Output, error) {
+	if in.SessionID == "" {
+		return Output{}, fmt.Errorf("%s: session_id is required", ReasonShardingInvalidInput)
+	}
+	if in.RequestedAuthorityEpoch < 0 {
+		return Output{}, fmt.Errorf("%s: requested_authority_epoch must be >=0", ReasonShardingInvalidInput)
+	}
+
+	shardMap := s.DefaultMap
+	if s.Backend != nil {
+		resolved, err := s.Backend.GetShardMap(in)
+		if err != nil {
+			return Output{}, fmt.Errorf("resolve shard map backend: %w", err)
+		}
+		if resolved.Enabled() {
+			shardMap = resolved
+		}
+	}
+	if !shardMap.Enabled() {
+		return Output{AuthorityEpoch: in.RequestedAuthorityEpoch, EpochValid: true, Owned: true, Reason: ReasonShardingDisabled}, nil
+	}
+	if s.WorkerID == "" {
+		return Output{}, fmt.Errorf("%s: worker_id is required when a shard map is published", ReasonShardingInvalidInput)
+	}
+
+	owner, endpoint, err := shardMap.Owner(in.SessionID)
+	if err != nil {
+		return Output{}, err
+	}
+	out := Output{
+		ShardMapSnapshot: shardMap.ShardMapSnapshot,
+		OwnerWorkerID:    owner,
+		OwnerEndpoint:    endpoint,
+		AuthorityEpoch:   in.RequestedAuthorityEpoch,
+		EpochValid:       true,
+	}
+	if shardMap.AuthorityEpoch > out.AuthorityEpoch {
+		out.AuthorityEpoch = shardMap.AuthorityEpoch
+		out.EpochValid = in.RequestedAuthorityEpoch == 0
+	}
+	out.Owned = out.OwnerWorkerID == s.WorkerID
+	switch {
+	case !out.EpochValid:
+		out.Reason = ReasonShardMapEpochAdvanced
+	case !out.Owned:
+		out.Reason = ReasonShardNotOwned
+	default:
+		out.Reason = ReasonShardOwned
+	}
+	return out, nil
+}
@@ -0,0 +1,181 @@
+package sharding
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRingOwnerIsDeterministicAndBalanced(t *testing.T) {
+	t.Parallel()
+
+	shardMap := ShardMap{ShardMapSnapshot: "shard-map/v1", Workers: []string{"runtime-a", "runtime-b", "runtime-c"}}
+	ring, err := NewRing(shardMap)
+	if err != nil {
+		t.Fatalf("new ring: %v", err)
+	}
+	reordered, err := NewRing(ShardMap{ShardMapSnapshot: "shard-map/v1", Workers: []string{"runtime-c", "runtime-a", "runtime-b"}})
+	if err != nil {
+		t.Fatalf("new reordered ring: %v", err)
+	}
+
+	counts := map[string]int{}
+	for idx := 0; idx < 3000; idx++ {
+		sessionID := fmt.Sprintf("sess-%d", idx)
+		owner := ring.Owner(sessionID)
+		if owner != reordered.Owner(sessionID) {
+			t.Fatalf("expected worker order to not affect ownership of %s", sessionID)
+		}
+		counts[owner]++
+	}
+	for _, worker := range shardMap.Workers {
+		if counts[worker] < 500 {
+			t.Fatalf("expected balanced ownership, got %+v", counts)
+		}
+	}
+}
+
+func TestRingRemapsOnlyRemovedWorkerSessions(t *testing.T) {
+	t.Parallel()
+
+	before, err := NewRing(ShardMap{ShardMapSnapshot: "shard-map/v1", Workers: []string{"runtime-a", "runtime-b", "runtime-c"}})
+	if err != nil {
+		t.Fatalf("new ring: %v", err)
+	}
+	after, err := NewRing(ShardMap{ShardMapSnapshot: "shard-map/v2", Workers: []string{"runtime-a", "runtime-b"}})
+	if err != nil {
+		t.Fatalf("new ring: %v", err)
+	}
+	for idx := 0; idx < 1000; idx++ {
+		sessionID := fmt.Sprintf("sess-%d", idx)
+		owner := before.Owner(sessionID)
+		if owner != "runtime-c" && after.Owner(sessionID) != owner {
+			t.Fatalf("expected %s to stay on %s after removing runtime-c, got %s", sessionID, owner, after.Owner(sessionID))
+		}
+	}
+}
+
+func TestShardMapValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		in      ShardMap
+		wantErr bool
+	}{
+		{name: "disabled", in: ShardMap{}},
+		{name: "valid", in: ShardMap{ShardMapSnapshot: "shard-map/v1", AuthorityEpoch: 3, Workers: []string{"runtime-a"}}},
+		{name: "missing snapshot", in: ShardMap{Workers: []string{"runtime-a"}}, wantErr: true},
+		{name: "negative epoch", in: ShardMap{ShardMapSnapshot: "shard-map/v1", AuthorityEpoch: -1, Workers: []string{"runtime-a"}}, wantErr: true},
+		{name: "blank worker", in: ShardMap{ShardMapSnapshot: "shard-map/v1", Workers: []string{" "}}, wantErr: true},
+		{name: "duplicate worker", in: ShardMap{ShardMapSnapshot: "shard-map/v1", Workers: []string{"runtime-a", "runtime-a"}}, wantErr: true},
+		{name: "endpoint for unknown worker", in: ShardMap{ShardMapSnapshot: "shard-map/v1", Workers: []string{"runtime-a"}, Endpoints: map[string]string{"runtime-b": "wss://b/ws"}}, wantErr: true},
+	}
+	for _, tc := range tests {
+		err := tc.in.Validate()
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("%s: expected wantErr=%v, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
+
+func TestResolveOwnershipAndEpochGate(t *testing.T) {
+	t.Parallel()
+
+	shardMap := ShardMap{
+		ShardMapSnapshot: "shard-map/v2",
+		AuthorityEpoch:   5,
+		Workers:          []string{"runtime-a", "runtime-b"},
+		Endpoints:        map[string]string{"runtime-a": "wss://runtime-a/ws", "runtime-b": "wss://runtime-b/ws"},
+	}
+	owner, endpoint, err := shardMap.Owner("sess-1")
+	if err != nil || endpoint != "wss://"+owner+"/ws" {
+		t.Fatalf("expected the owner's endpoint, got owner=%q endpoint=%q err=%v", owner, endpoint, err)
+	}
+	other := "runtime-a"
+	if owner == other {
+		other = "runtime-b"
+	}
+
+	tests := []struct {
+		name       string
+		workerID   string
+		epoch      int64
+		wantOwned  bool
+		wantValid  bool
+		wantEpoch  int64
+		wantReason string
+	}{
+		{name: "owner at current epoch", workerID: owner, epoch: 5, wantOwned: true, wantValid: true, wantEpoch: 5, wantReason: ReasonShardOwned},
+		{name: "owner adopts epoch on first turn", workerID: owner, epoch: 0, wantOwned: true, wantValid: true, wantEpoch: 5, wantReason: ReasonShardOwned},
+		{name: "non-owner", workerID: other, epoch: 5, wantOwned: false, wantValid: true, wantEpoch: 5, wantReason: ReasonShardNotOwned},
+		{name: "stale epoch after shard map change", workerID: owner, epoch: 4, wantOwned: true, wantValid: false, wantEpoch: 5, wantReason: ReasonShardMapEpochAdvanced},
+		{name: "lease epoch ahead of shard map", workerID: owner, epoch: 8, wantOwned: true, wantValid: true, wantEpoch: 8, wantReason: ReasonShardOwned},
+	}
+	for _, tc := range tests {
+		service := Service{WorkerID: tc.workerID, Backend: stubBackend{shardMap: shardMap}}
+		out, err := service.Resolve(Input{SessionID: "sess-1", RequestedAuthorityEpoch: tc.epoch})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if out.Owned != tc.wantOwned || out.EpochValid != tc.wantValid || out.AuthorityEpoch != tc.wantEpoch || out.Reason != tc.wantReason {
+			t.Fatalf("%s: expected owned=%v valid=%v epoch=%d reason=%s, got %+v", tc.name, tc.wantOwned, tc.wantValid, tc.wantEpoch, tc.wantReason, out)
+		}
+		if out.ShardMapSnapshot != "shard-map/v2" || out.OwnerWorkerID != owner || out.OwnerEndpoint != endpoint {
+			t.Fatalf("%s: expected shard ownership evidence, got %+v", tc.name, out)
+		}
+	}
+}
+
+func TestResolveWithoutShardMapIsDisabled(t *testing.T) {
+	t.Parallel()
+
+	out, err := Service{Backend: stubBackend{}}.Resolve(Input{SessionID: "sess-1", RequestedAuthorityEpoch: 3})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if !out.Owned || !out.EpochValid || out.AuthorityEpoch != 3 || out.Reason != ReasonShardingDisabled || out.ShardMapSnapshot != "" {
+		t.Fatalf("expected sharding disabled output, got %+v", out)
+	}
+	if owner, endpoint, err := (ShardMap{}).Owner("sess-1"); owner != "" || endpoint != "" || err != nil {
+		t.Fatalf("expected a disabled map to own nothing, got owner=%q endpoint=%q err=%v", owner, endpoint, err)
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	t.Parallel()
+
+	shardMap := ShardMap{ShardMapSnapshot: "shard-map/v1", Workers: []string{"runtime-a"}}
+	backendErr := errors.New("backend unavailable")
+	tests := []struct {
+		name    string
+		service Service
+		in      Input
+	}{
+		{name: "missing session", service: Service{WorkerID: "runtime-a"}, in: Input{}},
+		{name: "negative epoch", service: Service{WorkerID: "runtime-a"}, in: Input{SessionID: "sess-1", RequestedAuthorityEpoch: -1}},
+		{name: "missing worker id", service: Service{DefaultMap: shardMap}, in: Input{SessionID: "sess-1"}},
+		{name: "backend error", service: Service{WorkerID: "runtime-a", Backend: stubBackend{err: backendErr}}, in: Input{SessionID: "sess-1"}},
+	}
+	for _, tc := range tests {
+		if _, err := tc.service.Resolve(tc.in); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
+
+func TestNewServiceUsesWorkerIDFromEnv(t *testing.T) {
+	t.Setenv(EnvWorkerID, " runtime-b ")
+	if got := NewService().WorkerID; got != "runtime-b" {
+		t.Fatalf("expected env worker id, got %q", got)
+	}
+}
+
+type stubBackend struct {
+	shardMap ShardMap
+	err      error
+}
+
+func (s stubBackend) GetShardMap(Input) (ShardMap, error) {
+	return s.shardMap, s.err
+}
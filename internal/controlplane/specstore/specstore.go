@@ -0,0 +1,144 @@
+package specstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// EnvStoreDir configures the directory backing the file spec store.
+	EnvStoreDir = "RSPP_CP_SPEC_STORE_DIR"
+	// DefaultStoreDir is the repo-relative spec store used when EnvStoreDir is unset.
+	DefaultStoreDir = ".codex/controlplane/specs"
+
+	hashPrefix = "sha256:"
+)
+
+// ErrSpecNotFound reports that no blob is stored for a spec_hash.
+var ErrSpecNotFound = errors.New("spec not found")
+
+// IntegrityError reports stored spec content that no longer matches its spec_hash.
+type IntegrityError struct {
+	SpecHash   string
+	ActualHash string
+}
+
+func (e IntegrityError) Error() string {
+	return fmt.Sprintf("spec integrity mismatch: expected %s, got %s", e.SpecHash, e.ActualHash)
+}
+
+// Hash returns the content address of a spec blob.
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hashPrefix + hex.EncodeToString(sum[:])
+}
+
+// ValidateHash enforces the sha256:<64 hex> spec_hash form.
+func ValidateHash(specHash string) error {
+	digest, ok := strings.CutPrefix(specHash, hashPrefix)
+	if !ok {
+		return fmt.Errorf("spec_hash %q must start with %q", specHash, hashPrefix)
+	}
+	if len(digest) != sha256.Size*2 {
+		return fmt.Errorf("spec_hash %q must carry a %d-character hex digest", specHash, sha256.Size*2)
+	}
+	if _, err := hex.DecodeString(digest); err != nil || strings.ToLower(digest) != digest {
+		return fmt.Errorf("spec_hash %q must carry a lowercase hex digest", specHash)
+	}
+	return nil
+}
+
+// Verify checks that content hashes to specHash.
+func Verify(specHash string, content []byte) error {
+	if err := ValidateHash(specHash); err != nil {
+		return err
+	}
+	if actual := Hash(content); actual != specHash {
+		return IntegrityError{SpecHash: specHash, ActualHash: actual}
+	}
+	return nil
+}
+
+// FileStore keeps spec blobs on disk, one file per spec_hash.
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore returns a file store rooted at dir.
+func NewFileStore(dir string) (FileStore, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return FileStore{}, fmt.Errorf("spec store dir is required")
+	}
+	return FileStore{Dir: dir}, nil
+}
+
+// NewFileStoreFromEnv returns a file store rooted at EnvStoreDir, or DefaultStoreDir when unset.
+func NewFileStoreFromEnv() (FileStore, error) {
+	dir := strings.TrimSpace(os.Getenv(EnvStoreDir))
+	if dir == "" {
+		dir = DefaultStoreDir
+	}
+	return NewFileStore(dir)
+}
+
+// Put stores content under its spec_hash and returns the hash. Storing identical content is a no-op.
+func (s FileStore) Put(content []byte) (string, error) {
+	if len(content) == 0 {
+		return "", fmt.Errorf("spec content is required")
+	}
+	specHash := Hash(content)
+	path := s.blobPath(specHash)
+	if existing, err := os.ReadFile(path); err == nil {
+		if verifyErr := Verify(specHash, existing); verifyErr != nil {
+			return "", fmt.Errorf("existing spec blob %s: %w", path, verifyErr)
+		}
+		return specHash, nil
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return "", fmt.Errorf("create spec store dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.Dir, ".spec-*")
+	if err != nil {
+		return "", fmt.Errorf("create spec blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("write spec blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("write spec blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("commit spec blob: %w", err)
+	}
+	return specHash, nil
+}
+
+// Get returns the blob stored for specHash after verifying its integrity.
+func (s FileStore) Get(specHash string) ([]byte, error) {
+	if err := ValidateHash(specHash); err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(s.blobPath(specHash))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrSpecNotFound, specHash)
+		}
+		return nil, fmt.Errorf("read spec blob: %w", err)
+	}
+	if err := Verify(specHash, content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func (s FileStore) blobPath(specHash string) string {
+	return filepath.Join(s.Dir, strings.TrimPrefix(specHash, hashPrefix)+".json")
+}
@@ -0,0 +1,87 @@
+package specstore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileStoreRoundTripIsContentAddressed(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected store error: %v", err)
+	}
+	content := []byte(`{"pipeline_version":"pipeline-v2"}`)
+
+	first, err := store.Put(content)
+	if err != nil {
+		t.Fatalf("unexpected put error: %v", err)
+	}
+	second, err := store.Put(content)
+	if err != nil {
+		t.Fatalf("unexpected repeated put error: %v", err)
+	}
+	if first != second || first != Hash(content) {
+		t.Fatalf("expected stable content address, got %s and %s", first, second)
+	}
+
+	got, err := store.Get(first)
+	if err != nil {
+		t.Fatalf("unexpected get error: %v", err)
+	}
+	if string(got) != string(content) {
+		t.Fatalf("expected stored content, got %s", got)
+	}
+}
+
+func TestFileStoreGetFailures(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := FileStore{Dir: dir}
+	specHash, err := store.Put([]byte(`{"pipeline_version":"pipeline-v2"}`))
+	if err != nil {
+		t.Fatalf("unexpected put error: %v", err)
+	}
+	tampered := Hash([]byte("tampered"))
+	if err := os.WriteFile(filepath.Join(dir, strings.TrimPrefix(tampered, "sha256:")+".json"), []byte("other"), 0o644); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+
+	if _, err := store.Get(Hash([]byte("missing"))); !errors.Is(err, ErrSpecNotFound) {
+		t.Fatalf("expected not-found error, got %v", err)
+	}
+	var integrityErr IntegrityError
+	if _, err := store.Get(tampered); !errors.As(err, &integrityErr) {
+		t.Fatalf("expected integrity error, got %v", err)
+	}
+	if _, err := store.Get(strings.TrimPrefix(specHash, "sha256:")); err == nil {
+		t.Fatalf("expected malformed spec_hash to be rejected")
+	}
+}
+
+func TestValidateHash(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		hash    string
+		wantErr bool
+	}{
+		{name: "valid", hash: Hash([]byte("spec"))},
+		{name: "missing prefix", hash: strings.TrimPrefix(Hash([]byte("spec")), "sha256:"), wantErr: true},
+		{name: "short digest", hash: "sha256:abc", wantErr: true},
+		{name: "uppercase digest", hash: strings.ToUpper(Hash([]byte("spec"))), wantErr: true},
+		{name: "path traversal", hash: "sha256:../../etc/passwd", wantErr: true},
+	}
+	for _, tc := range cases {
+		err := ValidateHash(tc.hash)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("%s: expected error=%v, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package cost
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+// Rates are a provider's list prices in USD.
+type Rates struct {
+	InputTokensPerMillionUSD  float64 `json:"input_tokens_per_million_usd,omitempty"`
+	OutputTokensPerMillionUSD float64 `json:"output_tokens_per_million_usd,omitempty"`
+	AudioInputPerMinuteUSD    float64 `json:"audio_input_per_minute_usd,omitempty"`
+	AudioOutputPerMinuteUSD   float64 `json:"audio_output_per_minute_usd,omitempty"`
+	// StandbyPerHourUSD prices holding a warm-standby connection open, for providers that
+	// bill connected time or reserved capacity; unset for the built-in providers.
+	StandbyPerHourUSD float64 `json:"standby_per_hour_usd,omitempty"`
+}
+
+// PriceTable maps provider ids to their rates.
+type PriceTable map[string]Rates
+
+// DefaultPriceTable returns estimated list rates for the built-in providers, in line with the
+// typical per-turn costs in registry.DefaultCapabilities. Local providers are free. The rates
+// are estimates for budgeting, not invoices.
+func DefaultPriceTable() PriceTable {
+	return PriceTable{
+		"stt-deepgram":        {AudioInputPerMinuteUSD: 0.0060},
+		"stt-google":          {AudioInputPerMinuteUSD: 0.0120},
+		"stt-assemblyai":      {AudioInputPerMinuteUSD: 0.0075},
+		"stt-azure-speech":    {AudioInputPerMinuteUSD: 0.0128},
+		"stt-whisper-cpp":     {},
+		"llm-anthropic":       {InputTokensPerMillionUSD: 3, OutputTokensPerMillionUSD: 15},
+		"llm-gemini":          {InputTokensPerMillionUSD: 0.10, OutputTokensPerMillionUSD: 0.40},
+		"llm-cohere":          {InputTokensPerMillionUSD: 2.50, OutputTokensPerMillionUSD: 10},
+		"llm-llama-cpp":       {},
+		"tts-elevenlabs":      {AudioOutputPerMinuteUSD: 0.0720},
+		"tts-google":          {AudioOutputPerMinuteUSD: 0.0192},
+		"tts-amazon-polly":    {AudioOutputPerMinuteUSD: 0.0192},
+		"tts-azure-speech":    {AudioOutputPerMinuteUSD: 0.0192},
+		"tts-piper":           {},
+		"s2s-openai-realtime": {AudioInputPerMinuteUSD: 0.06, AudioOutputPerMinuteUSD: 0.24},
+	}
+}
+
+// Price returns usage with EstimatedCostUSD set from providerID's rates; usage from a
+// provider without rates is marked unpriced at zero cost.
+func (t PriceTable) Price(providerID string, usage timeline.UsageCostEvidence) timeline.UsageCostEvidence {
+	rates, ok := t[providerID]
+	if !ok {
+		usage.EstimatedCostUSD = 0
+		usage.Unpriced = true
+		return usage
+	}
+	usage.EstimatedCostUSD = float64(usage.InputTokens)*rates.InputTokensPerMillionUSD/1e6 +
+		float64(usage.OutputTokens)*rates.OutputTokensPerMillionUSD/1e6 +
+		float64(usage.AudioInputMS)*rates.AudioInputPerMinuteUSD/60000 +
+		float64(usage.AudioOutputMS)*rates.AudioOutputPerMinuteUSD/60000
+	usage.Unpriced = false
+	return usage
+}
+
+// PriceStandby returns the estimated cost of holding providerID's warm standby for heldMS;
+// zero for a provider without rates.
+func (t PriceTable) PriceStandby(providerID string, heldMS int64) float64 {
+	if heldMS <= 0 {
+		return 0
+	}
+	return float64(heldMS) * t[providerID].StandbyPerHourUSD / 3.6e6
+}
+
+// EstimateTokens approximates the token count of text at four characters per token, for
+// providers that do not report usage.
+func EstimateTokens(text string) int64 {
+	chars := utf8.RuneCountInString(text)
+	if chars == 0 {
+		return 0
+	}
+	return int64((chars + 3) / 4)
+}
+
+// AudioMS returns the duration of samples mono PCM samples at sampleRateHz.
+func AudioMS(samples int, sampleRateHz int) int64 {
+	if samples <= 0 || sampleRateHz <= 0 {
+		return 0
+	}
+	return int64(samples) * 1000 / int64(sampleRateHz)
+}
+
+// TurnFromInvocations rolls up the priced invocations of one turn; nil when none carries cost.
+func TurnFromInvocations(tenantID string, invocations []timeline.InvocationOutcomeEvidence) *timeline.TurnCostEvidence {
+	var rollup turnRollup
+	for _, invocation := range invocations {
+		if invocation.Cost != nil {
+			rollup.add(invocation.ProviderID, invocation.Modality, max(1, invocation.AttemptCount), *invocation.Cost)
+		}
+	}
+	return rollup.turn(tenantID)
+}
+
+// TurnFromAttempts rolls up one turn's provider attempts priced with table; nil when there
+// are no attempts.
+func TurnFromAttempts(tenantID string, table PriceTable, attempts []timeline.ProviderAttemptEvidence) *timeline.TurnCostEvidence {
+	var rollup turnRollup
+	for _, attempt := range attempts {
+		usage := table.Price(attempt.ProviderID, timeline.UsageCostEvidence{
+			InputTokens:   attempt.InputTokens,
+			OutputTokens:  attempt.OutputTokens,
+			AudioInputMS:  attempt.AudioInputMS,
+			AudioOutputMS: attempt.AudioOutputMS,
+		})
+		rollup.add(attempt.ProviderID, attempt.Modality, 1, usage)
+	}
+	return rollup.turn(tenantID)
+}
+
+type turnRollup struct {
+	providers []timeline.ProviderCostEvidence
+}
+
+func (r *turnRollup) add(providerID string, modality string, attempts int, usage timeline.UsageCostEvidence) {
+	for idx := range r.providers {
+		if r.providers[idx].ProviderID == providerID && r.providers[idx].Modality == modality {
+			r.providers[idx].Attempts += attempts
+			r.providers[idx].Add(usage)
+			return
+		}
+	}
+	r.providers = append(r.providers, timeline.ProviderCostEvidence{ProviderID: providerID, Modality: modality, Attempts: attempts, UsageCostEvidence: usage})
+}
+
+func (r *turnRollup) turn(tenantID string) *timeline.TurnCostEvidence {
+	if len(r.providers) == 0 {
+		return nil
+	}
+	sort.Slice(r.providers, func(i, j int) bool {
+		if r.providers[i].Modality != r.providers[j].Modality {
+			return r.providers[i].Modality < r.providers[j].Modality
+		}
+		return r.providers[i].ProviderID < r.providers[j].ProviderID
+	})
+	turn := &timeline.TurnCostEvidence{TenantID: tenantID, Providers: r.providers}
+	for _, provider := range r.providers {
+		turn.Add(provider.UsageCostEvidence)
+	}
+	return turn
+}
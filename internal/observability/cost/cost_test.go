@@ -0,0 +1,93 @@
+package cost
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+func TestPriceTablePricesUsageAndFlagsUnknownProviders(t *testing.T) {
+	t.Parallel()
+
+	table := DefaultPriceTable()
+	tests := []struct {
+		name         string
+		providerID   string
+		usage        timeline.UsageCostEvidence
+		wantCostUSD  float64
+		wantUnpriced bool
+	}{
+		{name: "stt audio minutes", providerID: "stt-deepgram", usage: timeline.UsageCostEvidence{AudioInputMS: 120_000}, wantCostUSD: 0.012},
+		{name: "llm tokens", providerID: "llm-anthropic", usage: timeline.UsageCostEvidence{InputTokens: 1000, OutputTokens: 100}, wantCostUSD: 0.0045},
+		{name: "s2s audio both ways", providerID: "s2s-openai-realtime", usage: timeline.UsageCostEvidence{AudioInputMS: 60_000, AudioOutputMS: 30_000}, wantCostUSD: 0.18},
+		{name: "local provider is free", providerID: "tts-piper", usage: timeline.UsageCostEvidence{AudioOutputMS: 60_000}},
+		{name: "unknown provider", providerID: "llm-unknown", usage: timeline.UsageCostEvidence{InputTokens: 1000, EstimatedCostUSD: 1}, wantUnpriced: true},
+	}
+	for _, tc := range tests {
+		priced := table.Price(tc.providerID, tc.usage)
+		if math.Abs(priced.EstimatedCostUSD-tc.wantCostUSD) > 1e-9 || priced.Unpriced != tc.wantUnpriced {
+			t.Fatalf("%s: expected cost=%f unpriced=%t, got %+v", tc.name, tc.wantCostUSD, tc.wantUnpriced, priced)
+		}
+	}
+	if got := EstimateTokens("hello world!"); got != 3 {
+		t.Fatalf("expected 12 characters to estimate 3 tokens, got %d", got)
+	}
+	if got := AudioMS(8000, 16000); got != 500 {
+		t.Fatalf("expected 8000 samples at 16kHz to be 500ms, got %d", got)
+	}
+}
+
+func TestTurnRollupsBuildTenantSessionAndProviderReport(t *testing.T) {
+	t.Parallel()
+
+	table := DefaultPriceTable()
+	turn := TurnFromInvocations("tenant-a", []timeline.InvocationOutcomeEvidence{
+		{Modality: "stt", ProviderID: "stt-deepgram", AttemptCount: 2, Cost: ptr(table.Price("stt-deepgram", timeline.UsageCostEvidence{AudioInputMS: 60_000}))},
+		{Modality: "llm", ProviderID: "llm-anthropic", AttemptCount: 1, Cost: ptr(table.Price("llm-anthropic", timeline.UsageCostEvidence{InputTokens: 1000, OutputTokens: 100, TokensEstimated: true}))},
+		{Modality: "tts", ProviderID: "tts-sandbox", AttemptCount: 1},
+	})
+	if turn == nil || len(turn.Providers) != 2 || turn.Providers[0].Modality != "llm" || turn.Providers[1].Attempts != 2 {
+		t.Fatalf("expected two priced providers ordered by modality, got %+v", turn)
+	}
+	if err := turn.Validate(); err != nil {
+		t.Fatalf("expected a valid turn rollup, got %v", err)
+	}
+	if math.Abs(turn.EstimatedCostUSD-0.0105) > 1e-9 || !turn.TokensEstimated {
+		t.Fatalf("expected turn total 0.0105 with estimated tokens, got %+v", turn.UsageCostEvidence)
+	}
+	if TurnFromInvocations("tenant-a", []timeline.InvocationOutcomeEvidence{{ProviderID: "stt-deepgram"}}) != nil {
+		t.Fatalf("expected no rollup when no invocation carries cost")
+	}
+
+	attemptTurn := TurnFromAttempts("tenant-b", table, []timeline.ProviderAttemptEvidence{
+		{Modality: "llm", ProviderID: "llm-gemini", InputTokens: 2_000_000},
+		{Modality: "llm", ProviderID: "llm-private", InputTokens: 10},
+	})
+	if attemptTurn == nil || !attemptTurn.Unpriced || math.Abs(attemptTurn.EstimatedCostUSD-0.2) > 1e-9 {
+		t.Fatalf("expected priced gemini usage plus an unpriced provider, got %+v", attemptTurn)
+	}
+
+	report := BuildReport([]timeline.BaselineEvidence{
+		{SessionID: "sess-1", TurnID: "turn-1", Cost: turn},
+		{SessionID: "sess-1", TurnID: "turn-2", Cost: turn},
+		{SessionID: "sess-2", TurnID: "turn-1", Cost: attemptTurn},
+		{SessionID: "sess-2", TurnID: "turn-2"},
+	})
+	if report.TotalTurns != 4 || report.UnaccountedTurns != 1 || len(report.Turns) != 3 {
+		t.Fatalf("expected three accounted turns and one unaccounted, got %+v", report)
+	}
+	if len(report.Tenants) != 2 || report.Tenants[0].TenantID != "tenant-a" || report.Tenants[0].Sessions != 1 || report.Tenants[0].Turns != 2 {
+		t.Fatalf("expected tenant-a with one session of two turns, got %+v", report.Tenants)
+	}
+	if math.Abs(report.Tenants[0].EstimatedCostUSD-0.021) > 1e-9 || math.Abs(report.Total.EstimatedCostUSD-0.221) > 1e-9 {
+		t.Fatalf("expected tenant and total costs to sum turns, got %+v / %+v", report.Tenants[0].Usage, report.Total)
+	}
+	if len(report.Sessions) != 2 || len(report.Providers) != 4 || report.Providers[3].ProviderID != "stt-deepgram" || report.Providers[3].Attempts != 4 {
+		t.Fatalf("expected per-session and per-provider rollups, got %+v / %+v", report.Sessions, report.Providers)
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
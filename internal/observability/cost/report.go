@@ -0,0 +1,158 @@
+package cost
+
+import (
+	"sort"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+// Usage is rolled-up usage and estimated cost in report form.
+type Usage struct {
+	InputTokens      int64   `json:"input_tokens"`
+	OutputTokens     int64   `json:"output_tokens"`
+	AudioInputMS     int64   `json:"audio_input_ms"`
+	AudioOutputMS    int64   `json:"audio_output_ms"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+	Unpriced         bool    `json:"unpriced,omitempty"`
+	TokensEstimated  bool    `json:"tokens_estimated,omitempty"`
+}
+
+func (u *Usage) add(other timeline.UsageCostEvidence) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.AudioInputMS += other.AudioInputMS
+	u.AudioOutputMS += other.AudioOutputMS
+	u.EstimatedCostUSD += other.EstimatedCostUSD
+	u.Unpriced = u.Unpriced || other.Unpriced
+	u.TokensEstimated = u.TokensEstimated || other.TokensEstimated
+}
+
+// TenantUsage rolls up a tenant's sessions.
+type TenantUsage struct {
+	TenantID string `json:"tenant_id"`
+	Sessions int    `json:"sessions"`
+	Turns    int    `json:"turns"`
+	Usage
+}
+
+// SessionUsage rolls up a session's turns.
+type SessionUsage struct {
+	TenantID  string `json:"tenant_id"`
+	SessionID string `json:"session_id"`
+	Turns     int    `json:"turns"`
+	Usage
+}
+
+// TurnUsage is one turn's usage.
+type TurnUsage struct {
+	TenantID  string `json:"tenant_id"`
+	SessionID string `json:"session_id"`
+	TurnID    string `json:"turn_id"`
+	Usage
+}
+
+// ProviderUsage rolls up a provider's attempts across turns.
+type ProviderUsage struct {
+	ProviderID string `json:"provider_id"`
+	Modality   string `json:"modality"`
+	Attempts   int    `json:"attempts"`
+	Usage
+}
+
+// Report is a cost breakdown over baseline evidence.
+type Report struct {
+	TotalTurns int `json:"total_turns"`
+	// UnaccountedTurns counts turns without cost evidence; they are excluded from every rollup.
+	UnaccountedTurns int             `json:"unaccounted_turns"`
+	Total            Usage           `json:"total"`
+	Tenants          []TenantUsage   `json:"tenants"`
+	Sessions         []SessionUsage  `json:"sessions"`
+	Turns            []TurnUsage     `json:"turns"`
+	Providers        []ProviderUsage `json:"providers"`
+}
+
+// BuildReport rolls turn cost evidence up per turn, session, tenant and provider. Rollups are
+// ordered by id; turns keep entry order within their session.
+func BuildReport(entries []timeline.BaselineEvidence) Report {
+	report := Report{TotalTurns: len(entries)}
+	tenants := map[string]*TenantUsage{}
+	tenantSessions := map[string]map[string]struct{}{}
+	sessions := map[string]*SessionUsage{}
+	providers := map[string]*ProviderUsage{}
+	for _, entry := range entries {
+		if entry.Cost == nil {
+			report.UnaccountedTurns++
+			continue
+		}
+		turnCost := *entry.Cost
+		report.Total.add(turnCost.UsageCostEvidence)
+		report.Turns = append(report.Turns, TurnUsage{TenantID: turnCost.TenantID, SessionID: entry.SessionID, TurnID: entry.TurnID, Usage: usageFrom(turnCost.UsageCostEvidence)})
+
+		tenant, ok := tenants[turnCost.TenantID]
+		if !ok {
+			tenant = &TenantUsage{TenantID: turnCost.TenantID}
+			tenants[turnCost.TenantID] = tenant
+			tenantSessions[turnCost.TenantID] = map[string]struct{}{}
+		}
+		tenant.Turns++
+		tenant.add(turnCost.UsageCostEvidence)
+		tenantSessions[turnCost.TenantID][entry.SessionID] = struct{}{}
+		tenant.Sessions = len(tenantSessions[turnCost.TenantID])
+
+		sessionKey := turnCost.TenantID + "\x00" + entry.SessionID
+		session, ok := sessions[sessionKey]
+		if !ok {
+			session = &SessionUsage{TenantID: turnCost.TenantID, SessionID: entry.SessionID}
+			sessions[sessionKey] = session
+		}
+		session.Turns++
+		session.add(turnCost.UsageCostEvidence)
+
+		for _, providerCost := range turnCost.Providers {
+			providerKey := providerCost.Modality + "\x00" + providerCost.ProviderID
+			provider, ok := providers[providerKey]
+			if !ok {
+				provider = &ProviderUsage{ProviderID: providerCost.ProviderID, Modality: providerCost.Modality}
+				providers[providerKey] = provider
+			}
+			provider.Attempts += providerCost.Attempts
+			provider.add(providerCost.UsageCostEvidence)
+		}
+	}
+
+	for _, tenant := range tenants {
+		report.Tenants = append(report.Tenants, *tenant)
+	}
+	sort.Slice(report.Tenants, func(i, j int) bool { return report.Tenants[i].TenantID < report.Tenants[j].TenantID })
+	for _, session := range sessions {
+		report.Sessions = append(report.Sessions, *session)
+	}
+	sort.Slice(report.Sessions, func(i, j int) bool {
+		if report.Sessions[i].TenantID != report.Sessions[j].TenantID {
+			return report.Sessions[i].TenantID < report.Sessions[j].TenantID
+		}
+		return report.Sessions[i].SessionID < report.Sessions[j].SessionID
+	})
+	sort.SliceStable(report.Turns, func(i, j int) bool {
+		if report.Turns[i].TenantID != report.Turns[j].TenantID {
+			return report.Turns[i].TenantID < report.Turns[j].TenantID
+		}
+		return report.Turns[i].SessionID < report.Turns[j].SessionID
+	})
+	for _, provider := range providers {
+		report.Providers = append(report.Providers, *provider)
+	}
+	sort.Slice(report.Providers, func(i, j int) bool {
+		if report.Providers[i].Modality != report.Providers[j].Modality {
+			return report.Providers[i].Modality < report.Providers[j].Modality
+		}
+		return report.Providers[i].ProviderID < report.Providers[j].ProviderID
+	})
+	return report
+}
+
+func usageFrom(evidence timeline.UsageCostEvidence) Usage {
+	var usage Usage
+	usage.add(evidence)
+	return usage
+}
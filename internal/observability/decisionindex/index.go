@@ -0,0 +1,183 @@
+package decisionindex
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+)
+
+const (
+	// DefaultCapacity bounds the decision window when no capacity is configured.
+	DefaultCapacity = 4096
+	// AdminPath is the admin endpoint path the query handler is mounted at.
+	AdminPath = "/admin/decisions"
+)
+
+// Config configures the decision window.
+type Config struct {
+	// Capacity is the number of most recent decisions kept; older decisions are evicted.
+	Capacity int
+}
+
+// Query filters indexed decisions. Empty fields match everything.
+type Query struct {
+	SessionID   string
+	TurnID      string
+	OutcomeKind controlplane.OutcomeKind
+	Reason      string
+	// Limit keeps only the most recent matches when >0.
+	Limit int
+}
+
+// Stats captures decision window counters.
+type Stats struct {
+	Size     int    `json:"size"`
+	Capacity int    `json:"capacity"`
+	Indexed  uint64 `json:"indexed"`
+	Evicted  uint64 `json:"evicted"`
+}
+
+// Index is a bounded, runtime-resident ring of recent decision outcomes so operators can
+// query admission/authority decisions without unpacking baseline artifacts.
+type Index struct {
+	mu      sync.Mutex
+	ring    []controlplane.DecisionOutcome
+	next    int
+	size    int
+	indexed uint64
+	evicted uint64
+}
+
+// New constructs an empty decision index.
+func New(cfg Config) *Index {
+	if cfg.Capacity < 1 {
+		cfg.Capacity = DefaultCapacity
+	}
+	return &Index{ring: make([]controlplane.DecisionOutcome, cfg.Capacity)}
+}
+
+// Record validates and appends decisions, evicting the oldest once the window is full.
+func (x *Index) Record(outcomes ...controlplane.DecisionOutcome) error {
+	for _, outcome := range outcomes {
+		if err := outcome.Validate(); err != nil {
+			return fmt.Errorf("index decision outcome %s: %w", outcome.EventID, err)
+		}
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for _, outcome := range outcomes {
+		if outcome.AuthorityEpoch != nil {
+			epoch := *outcome.AuthorityEpoch
+			outcome.AuthorityEpoch = &epoch
+		}
+		if x.size == len(x.ring) {
+			x.evicted++
+		} else {
+			x.size++
+		}
+		x.ring[x.next] = outcome
+		x.next = (x.next + 1) % len(x.ring)
+		x.indexed++
+	}
+	return nil
+}
+
+// Query returns matching decisions oldest-first.
+func (x *Index) Query(q Query) []controlplane.DecisionOutcome {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	out := make([]controlplane.DecisionOutcome, 0)
+	start := (x.next - x.size + len(x.ring)) % len(x.ring)
+	for i := 0; i < x.size; i++ {
+		outcome := x.ring[(start+i)%len(x.ring)]
+		if q.matches(outcome) {
+			out = append(out, outcome)
+		}
+	}
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[len(out)-q.Limit:]
+	}
+	return out
+}
+
+// Stats returns a snapshot of the window counters.
+func (x *Index) Stats() Stats {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return Stats{Size: x.size, Capacity: len(x.ring), Indexed: x.indexed, Evicted: x.evicted}
+}
+
+func (q Query) matches(outcome controlplane.DecisionOutcome) bool {
+	if q.SessionID != "" && outcome.SessionID != q.SessionID {
+		return false
+	}
+	if q.TurnID != "" && outcome.TurnID != q.TurnID {
+		return false
+	}
+	if q.OutcomeKind != "" && outcome.OutcomeKind != q.OutcomeKind {
+		return false
+	}
+	if q.Reason != "" && outcome.Reason != q.Reason {
+		return false
+	}
+	return true
+}
+
+// QueryResponse is the admin endpoint response body.
+type QueryResponse struct {
+	Decisions []controlplane.DecisionOutcome `json:"decisions"`
+	Window    Stats                          `json:"window"`
+}
+
+// QueryFromValues parses session_id, turn_id, outcome_kind, reason, and limit parameters.
+func QueryFromValues(get func(string) string) (Query, error) {
+	q := Query{
+		SessionID:   strings.TrimSpace(get("session_id")),
+		TurnID:      strings.TrimSpace(get("turn_id")),
+		OutcomeKind: controlplane.OutcomeKind(strings.TrimSpace(get("outcome_kind"))),
+		Reason:      strings.TrimSpace(get("reason")),
+	}
+	if raw := strings.TrimSpace(get("limit")); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return Query{}, fmt.Errorf("limit must be integer >=1")
+		}
+		q.Limit = limit
+	}
+	return q, nil
+}
+
+// Handler serves read-only decision queries for the admin endpoint.
+type Handler struct {
+	Index *Index
+}
+
+// ServeHTTP answers GET queries with matching decisions and the window stats.
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.Index == nil {
+		http.Error(w, "decision index unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	q, err := QueryFromValues(r.URL.Query().Get)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(QueryResponse{
+		Decisions: h.Index.Query(q),
+		Window:    h.Index.Stats(),
+	})
+}
@@ -0,0 +1,147 @@
+package decisionindex
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+)
+
+func decision(sessionID, turnID string, kind controlplane.OutcomeKind, reason string, seq int) controlplane.DecisionOutcome {
+	out := controlplane.DecisionOutcome{
+		OutcomeKind:        kind,
+		Phase:              controlplane.PhasePreTurn,
+		Scope:              controlplane.ScopeTurn,
+		SessionID:          sessionID,
+		TurnID:             turnID,
+		EventID:            fmt.Sprintf("evt-%d", seq),
+		RuntimeTimestampMS: int64(seq),
+		WallClockMS:        int64(seq),
+		EmittedBy:          controlplane.EmitterRK25,
+		Reason:             reason,
+	}
+	if kind == controlplane.OutcomeStaleEpochReject {
+		epoch := int64(seq)
+		out.EmittedBy = controlplane.EmitterRK24
+		out.AuthorityEpoch = &epoch
+	}
+	return out
+}
+
+func TestIndexQueryFilters(t *testing.T) {
+	t.Parallel()
+
+	index := New(Config{Capacity: 16})
+	if err := index.Record(
+		decision("sess-1", "turn-1", controlplane.OutcomeAdmit, "admission_capacity_allow", 1),
+		decision("sess-1", "turn-2", controlplane.OutcomeReject, "admission_capacity_reject", 2),
+		decision("sess-2", "turn-1", controlplane.OutcomeStaleEpochReject, "authority_epoch_mismatch", 3),
+		decision("sess-1", "turn-3", controlplane.OutcomeAdmit, "admission_capacity_allow", 4),
+	); err != nil {
+		t.Fatalf("unexpected record error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		query    Query
+		expected []string
+	}{
+		{name: "all", query: Query{}, expected: []string{"evt-1", "evt-2", "evt-3", "evt-4"}},
+		{name: "by session", query: Query{SessionID: "sess-1"}, expected: []string{"evt-1", "evt-2", "evt-4"}},
+		{name: "by session and turn", query: Query{SessionID: "sess-2", TurnID: "turn-1"}, expected: []string{"evt-3"}},
+		{name: "by outcome kind", query: Query{OutcomeKind: controlplane.OutcomeAdmit}, expected: []string{"evt-1", "evt-4"}},
+		{name: "by reason", query: Query{Reason: "admission_capacity_reject"}, expected: []string{"evt-2"}},
+		{name: "limit keeps most recent", query: Query{SessionID: "sess-1", Limit: 2}, expected: []string{"evt-2", "evt-4"}},
+	}
+	for _, tc := range tests {
+		got := eventIDs(index.Query(tc.query))
+		if fmt.Sprint(got) != fmt.Sprint(tc.expected) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestIndexEvictsOldestBeyondCapacity(t *testing.T) {
+	t.Parallel()
+
+	index := New(Config{Capacity: 3})
+	for i := 1; i <= 5; i++ {
+		if err := index.Record(decision("sess-1", "turn-1", controlplane.OutcomeAdmit, "admission_capacity_allow", i)); err != nil {
+			t.Fatalf("unexpected record error: %v", err)
+		}
+	}
+	if got := eventIDs(index.Query(Query{})); fmt.Sprint(got) != "[evt-3 evt-4 evt-5]" {
+		t.Fatalf("expected window of newest 3 decisions, got %v", got)
+	}
+	if stats := index.Stats(); stats.Size != 3 || stats.Indexed != 5 || stats.Evicted != 2 {
+		t.Fatalf("unexpected window stats: %+v", stats)
+	}
+}
+
+func TestIndexRejectsInvalidDecision(t *testing.T) {
+	t.Parallel()
+
+	index := New(Config{})
+	invalid := decision("", "turn-1", controlplane.OutcomeAdmit, "admission_capacity_allow", 1)
+	if err := index.Record(invalid); err == nil {
+		t.Fatalf("expected invalid decision error")
+	}
+	if stats := index.Stats(); stats.Size != 0 || stats.Capacity != DefaultCapacity {
+		t.Fatalf("expected empty default window, got %+v", stats)
+	}
+}
+
+func TestHandlerServesDecisionQueries(t *testing.T) {
+	t.Parallel()
+
+	index := New(Config{Capacity: 8})
+	if err := index.Record(
+		decision("sess-1", "turn-1", controlplane.OutcomeAdmit, "admission_capacity_allow", 1),
+		decision("sess-2", "turn-1", controlplane.OutcomeReject, "admission_capacity_reject", 2),
+	); err != nil {
+		t.Fatalf("unexpected record error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		handler    Handler
+		method     string
+		target     string
+		wantStatus int
+		wantEvents []string
+	}{
+		{name: "query by session", handler: Handler{Index: index}, method: http.MethodGet, target: AdminPath + "?session_id=sess-2", wantStatus: http.StatusOK, wantEvents: []string{"evt-2"}},
+		{name: "query by outcome kind", handler: Handler{Index: index}, method: http.MethodGet, target: AdminPath + "?outcome_kind=admit", wantStatus: http.StatusOK, wantEvents: []string{"evt-1"}},
+		{name: "invalid limit", handler: Handler{Index: index}, method: http.MethodGet, target: AdminPath + "?limit=0", wantStatus: http.StatusBadRequest},
+		{name: "write rejected", handler: Handler{Index: index}, method: http.MethodPost, target: AdminPath, wantStatus: http.StatusMethodNotAllowed},
+		{name: "index unavailable", handler: Handler{}, method: http.MethodGet, target: AdminPath, wantStatus: http.StatusServiceUnavailable},
+	}
+	for _, tc := range tests {
+		rec := httptest.NewRecorder()
+		tc.handler.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.target, nil))
+		if rec.Code != tc.wantStatus {
+			t.Fatalf("%s: expected status %d, got %d", tc.name, tc.wantStatus, rec.Code)
+		}
+		if tc.wantStatus != http.StatusOK {
+			continue
+		}
+		var resp QueryResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: unexpected decode error: %v", tc.name, err)
+		}
+		if got := eventIDs(resp.Decisions); fmt.Sprint(got) != fmt.Sprint(tc.wantEvents) || resp.Window.Size != 2 {
+			t.Fatalf("%s: expected %v in a window of 2, got %+v", tc.name, tc.wantEvents, resp)
+		}
+	}
+}
+
+func eventIDs(decisions []controlplane.DecisionOutcome) []string {
+	out := make([]string, 0, len(decisions))
+	for _, d := range decisions {
+		out = append(out, d.EventID)
+	}
+	return out
+}
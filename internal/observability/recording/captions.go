@@ -0,0 +1,116 @@
+package recording
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	// CaptionFormatWebVTT writes WebVTT (.vtt) captions with speaker voice tags.
+	CaptionFormatWebVTT = "vtt"
+	// CaptionFormatSRT writes SubRip (.srt) captions with bracketed speaker labels.
+	CaptionFormatSRT = "srt"
+
+	// maxCueChars and maxCueMS bound cues built from word timings so each stays readable.
+	maxCueChars       = 42
+	maxCueMS    int64 = 5000
+)
+
+var webVTTEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// CaptionCue is one caption on the reconstructed audio timeline.
+type CaptionCue struct {
+	Track   Track
+	StartMS int64
+	EndMS   int64
+	Text    string
+}
+
+// CaptionFileName returns the caption file name for a session export.
+func CaptionFileName(sessionID string, format string) string {
+	return sessionID + "-captions." + format
+}
+
+func validateCaptionFormat(format string) error {
+	switch format {
+	case CaptionFormatWebVTT, CaptionFormatSRT:
+		return nil
+	default:
+		return fmt.Errorf("unsupported caption format %q", format)
+	}
+}
+
+// BuildCaptionCues converts redacted transcript segments into cues ordered by start time.
+// Segments with word timings are split into cues of at most maxCueChars and maxCueMS;
+// redacted segments carry no words and become one cue holding the redacted text.
+func BuildCaptionCues(transcript []SidecarSegment) []CaptionCue {
+	cues := make([]CaptionCue, 0, len(transcript))
+	for _, segment := range transcript {
+		if len(segment.Words) == 0 {
+			if text := strings.TrimSpace(segment.Text); text != "" {
+				cues = append(cues, CaptionCue{Track: segment.Track, StartMS: segment.StartMS, EndMS: segment.EndMS, Text: text})
+			}
+			continue
+		}
+		var current *CaptionCue
+		for _, word := range segment.Words {
+			if current != nil && (len(current.Text)+1+len(word.Text) > maxCueChars || word.EndMS-current.StartMS > maxCueMS) {
+				cues = append(cues, *current)
+				current = nil
+			}
+			if current == nil {
+				current = &CaptionCue{Track: segment.Track, StartMS: word.StartMS, EndMS: word.EndMS, Text: word.Text}
+				continue
+			}
+			current.Text += " " + word.Text
+			current.EndMS = word.EndMS
+		}
+		cues = append(cues, *current)
+	}
+	sort.SliceStable(cues, func(i, j int) bool { return cues[i].StartMS < cues[j].StartMS })
+	for idx := range cues {
+		// Caption formats require a cue to end after it starts.
+		if cues[idx].EndMS <= cues[idx].StartMS {
+			cues[idx].EndMS = cues[idx].StartMS + 1
+		}
+	}
+	return cues
+}
+
+// WriteCaptions writes cues in the requested caption format.
+func WriteCaptions(w io.Writer, cues []CaptionCue, format string) error {
+	if err := validateCaptionFormat(format); err != nil {
+		return err
+	}
+	var b strings.Builder
+	if format == CaptionFormatWebVTT {
+		b.WriteString("WEBVTT\n")
+	}
+	for idx, cue := range cues {
+		if format == CaptionFormatWebVTT {
+			fmt.Fprintf(&b, "\n%s --> %s\n<v %s>%s\n", captionTimestamp(cue.StartMS, "."), captionTimestamp(cue.EndMS, "."), speakerLabel(cue.Track), webVTTEscaper.Replace(cue.Text))
+			continue
+		}
+		if idx > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%d\n%s --> %s\n[%s] %s\n", idx+1, captionTimestamp(cue.StartMS, ","), captionTimestamp(cue.EndMS, ","), speakerLabel(cue.Track), cue.Text)
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("write %s captions: %w", format, err)
+	}
+	return nil
+}
+
+func captionTimestamp(ms int64, fractionSeparator string) string {
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3_600_000, ms/60_000%60, ms/1000%60, fractionSeparator, ms%1000)
+}
+
+func speakerLabel(track Track) string {
+	if track == TrackAssistant {
+		return "Assistant"
+	}
+	return "User"
+}
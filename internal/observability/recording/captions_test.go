@@ -0,0 +1,138 @@
+package recording
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
+)
+
+func TestBuildCaptionCuesSplitsWordTimings(t *testing.T) {
+	t.Parallel()
+
+	words := make([]TranscriptWord, 0)
+	for idx, word := range strings.Fields("the quick brown fox jumps over the lazy dog and keeps running far away") {
+		start := int64(1000 + idx*400)
+		words = append(words, TranscriptWord{Text: word, StartMS: start, EndMS: start + 300})
+	}
+	cues := BuildCaptionCues([]SidecarSegment{
+		{Track: TrackAssistant, StartMS: 500, EndMS: 900, Text: "[masked]", Redaction: eventabi.RedactionMask},
+		{Track: TrackUser, StartMS: 1000, EndMS: 6700, Text: "unused when words exist", Words: words},
+		{Track: TrackUser, StartMS: 7000, EndMS: 7000, Text: "ok"},
+	})
+
+	if len(cues) != 4 || cues[0].Text != "[masked]" || cues[0].Track != TrackAssistant {
+		t.Fatalf("expected redacted segment cue first, got %+v", cues)
+	}
+	for _, cue := range cues[1:3] {
+		if len(cue.Text) > maxCueChars || cue.EndMS-cue.StartMS > maxCueMS {
+			t.Fatalf("expected word cues within char and duration bounds, got %+v", cue)
+		}
+	}
+	if cues[1].StartMS != 1000 || cues[1].Text != "the quick brown fox jumps over the lazy" || cues[2].StartMS != 4200 || cues[2].EndMS != 6500 {
+		t.Fatalf("expected word cues aligned to word timings, got %+v", cues[1:3])
+	}
+	if last := cues[3]; last.StartMS != 7000 || last.EndMS != 7001 {
+		t.Fatalf("expected zero-length cue extended to end after start, got %+v", last)
+	}
+}
+
+func TestWriteCaptionsFormats(t *testing.T) {
+	t.Parallel()
+
+	cues := []CaptionCue{
+		{Track: TrackUser, StartMS: 0, EndMS: 1500, Text: "hi <there>"},
+		{Track: TrackAssistant, StartMS: 3_723_004, EndMS: 3_725_000, Text: "hello"},
+	}
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "webvtt",
+			format: CaptionFormatWebVTT,
+			want:   "WEBVTT\n\n00:00:00.000 --> 00:00:01.500\n<v User>hi &lt;there&gt;\n\n01:02:03.004 --> 01:02:05.000\n<v Assistant>hello\n",
+		},
+		{
+			name:   "srt",
+			format: CaptionFormatSRT,
+			want:   "1\n00:00:00,000 --> 00:00:01,500\n[User] hi <there>\n\n2\n01:02:03,004 --> 01:02:05,000\n[Assistant] hello\n",
+		},
+	}
+	for _, tc := range tests {
+		var b strings.Builder
+		if err := WriteCaptions(&b, cues, tc.format); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if b.String() != tc.want {
+			t.Fatalf("%s: expected %q, got %q", tc.name, tc.want, b.String())
+		}
+	}
+	if err := WriteCaptions(&strings.Builder{}, cues, "ass"); err == nil {
+		t.Fatalf("expected unsupported caption format error")
+	}
+}
+
+func TestExportWritesRedactedCaptions(t *testing.T) {
+	t.Parallel()
+
+	audio := testSessionAudio()
+	audio.Transcript[0].Words = []TranscriptWord{{Text: "hello", StartMS: 0, EndMS: 2}, {Text: "there", StartMS: 2, EndMS: 4}}
+	audio.Transcript[2].Words = []TranscriptWord{{Text: "greeting", StartMS: 2, EndMS: 4}}
+	request := testExportRequest(FormatWAV)
+	request.CaptionFormats = []string{CaptionFormatWebVTT, CaptionFormatSRT}
+
+	dir := t.TempDir()
+	sidecar, err := NewExporter(replay.DefaultAccessPolicy("tenant-a")).Export(audio, request, dir)
+	if err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+	if len(sidecar.CaptionFiles) != 2 || sidecar.CaptionFiles[0] != "sess-export-1-captions.vtt" {
+		t.Fatalf("expected vtt and srt caption files, got %+v", sidecar.CaptionFiles)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, sidecar.CaptionFiles[0]))
+	if err != nil {
+		t.Fatalf("unexpected caption read error: %v", err)
+	}
+	vtt := string(raw)
+	if strings.Contains(vtt, "hello") || strings.Contains(vtt, "555-0100") || !strings.Contains(vtt, "<v User>[masked]") || !strings.Contains(vtt, "<v Assistant>greeting") {
+		t.Fatalf("expected captions to follow transcript redaction, got %q", vtt)
+	}
+	for _, segment := range sidecar.Transcript {
+		if segment.Redaction != eventabi.RedactionAllow && len(segment.Words) != 0 {
+			t.Fatalf("expected word timings dropped from redacted segments, got %+v", segment)
+		}
+	}
+
+	request.CaptionFormats = []string{"ass"}
+	rejected := t.TempDir()
+	if _, err := NewExporter(replay.DefaultAccessPolicy("tenant-a")).Export(audio, request, rejected); err == nil {
+		t.Fatalf("expected unsupported caption format to be rejected")
+	}
+	if entries, _ := os.ReadDir(rejected); len(entries) != 0 {
+		t.Fatalf("expected no files written for rejected caption format, got %d", len(entries))
+	}
+}
+
+func TestTranscriptSegmentValidateWords(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		words []TranscriptWord
+	}{
+		{name: "empty text", words: []TranscriptWord{{StartMS: 0, EndMS: 1}}},
+		{name: "past segment end", words: []TranscriptWord{{Text: "a", StartMS: 0, EndMS: 5}}},
+		{name: "out of order", words: []TranscriptWord{{Text: "a", StartMS: 2, EndMS: 3}, {Text: "b", StartMS: 1, EndMS: 2}}},
+	}
+	for _, tc := range tests {
+		segment := TranscriptSegment{Track: TrackUser, StartMS: 0, EndMS: 4, Text: "a b", PayloadClass: eventabi.PayloadTextRaw, Words: tc.words}
+		if err := segment.Validate(); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,404 @@
+package recording
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
+)
+
+var (
+	// ErrConsentNotGranted indicates the session has no recording consent on file.
+	ErrConsentNotGranted = fmt.Errorf("recording export requires session consent")
+	// ErrExportDenied indicates replay access policy denied the export request.
+	ErrExportDenied = fmt.Errorf("recording export denied")
+)
+
+const (
+	// FormatWAV is the built-in 16-bit PCM RIFF/WAVE export format.
+	FormatWAV = "wav"
+	// FormatOGG is the built-in lossless Ogg FLAC export format.
+	FormatOGG = "ogg"
+
+	consentedAudioReason = "consented_recording_export"
+	maskedTranscriptText = "[masked]"
+)
+
+// Encoder writes interleaved 16-bit PCM in one container format.
+type Encoder interface {
+	Encode(w io.Writer, pcm []int16, sampleRateHz int, channels int) error
+}
+
+// WAVEncoder writes canonical 16-bit little-endian PCM WAV files.
+type WAVEncoder struct{}
+
+// Encode writes a RIFF/WAVE header followed by pcm.
+func (WAVEncoder) Encode(w io.Writer, pcm []int16, sampleRateHz int, channels int) error {
+	if sampleRateHz < 1 || channels < 1 {
+		return fmt.Errorf("wav encode requires sample_rate_hz and channels >=1")
+	}
+	dataBytes := uint32(len(pcm) * 2)
+	blockAlign := uint16(channels * 2)
+	header := []any{
+		[]byte("RIFF"), 36 + dataBytes, []byte("WAVE"),
+		[]byte("fmt "), uint32(16), uint16(1), uint16(channels), uint32(sampleRateHz),
+		uint32(sampleRateHz) * uint32(blockAlign), blockAlign, uint16(16),
+		[]byte("data"), dataBytes,
+	}
+	for _, field := range header {
+		if err := binary.Write(w, binary.LittleEndian, field); err != nil {
+			return fmt.Errorf("write wav header: %w", err)
+		}
+	}
+	if err := binary.Write(w, binary.LittleEndian, pcm); err != nil {
+		return fmt.Errorf("write wav data: %w", err)
+	}
+	return nil
+}
+
+// DecodeWAV reads a 16-bit PCM RIFF/WAVE file and returns its interleaved samples, skipping
+// chunks other than fmt and data.
+func DecodeWAV(r io.Reader) ([]int16, int, int, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("read wav: %w", err)
+	}
+	if len(raw) < 12 || string(raw[0:4]) != "RIFF" || string(raw[8:12]) != "WAVE" {
+		return nil, 0, 0, fmt.Errorf("wav decode: missing RIFF/WAVE header")
+	}
+	sampleRateHz, channels := 0, 0
+	for offset := 12; offset+8 <= len(raw); {
+		id := string(raw[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(raw[offset+4 : offset+8]))
+		body := offset + 8
+		if size < 0 || body+size > len(raw) {
+			return nil, 0, 0, fmt.Errorf("wav decode: truncated %q chunk", id)
+		}
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, 0, 0, fmt.Errorf("wav decode: short fmt chunk")
+			}
+			if audioFormat := binary.LittleEndian.Uint16(raw[body:]); audioFormat != 1 {
+				return nil, 0, 0, fmt.Errorf("wav decode: unsupported audio format %d (PCM required)", audioFormat)
+			}
+			if bits := binary.LittleEndian.Uint16(raw[body+14:]); bits != 16 {
+				return nil, 0, 0, fmt.Errorf("wav decode: unsupported %d-bit samples (16-bit required)", bits)
+			}
+			channels = int(binary.LittleEndian.Uint16(raw[body+2:]))
+			sampleRateHz = int(binary.LittleEndian.Uint32(raw[body+4:]))
+		case "data":
+			if sampleRateHz < 1 || channels < 1 {
+				return nil, 0, 0, fmt.Errorf("wav decode: data chunk before fmt chunk")
+			}
+			pcm := make([]int16, size/2)
+			for idx := range pcm {
+				pcm[idx] = int16(binary.LittleEndian.Uint16(raw[body+2*idx:]))
+			}
+			return pcm, sampleRateHz, channels, nil
+		}
+		// Chunks are word-aligned.
+		offset = body + size + size%2
+	}
+	return nil, 0, 0, fmt.Errorf("wav decode: missing data chunk")
+}
+
+// Request is one QA export of a session recording. CaptionFormats optionally adds caption
+// files (CaptionFormatWebVTT, CaptionFormatSRT) built from the redacted transcript.
+type Request struct {
+	Access         obs.ReplayAccessRequest
+	Format         string
+	CaptionFormats []string
+}
+
+// TrackFile describes one reconstructed track written by an export.
+type TrackFile struct {
+	Track        Track  `json:"track"`
+	FileName     string `json:"file_name"`
+	SampleRateHz int    `json:"sample_rate_hz"`
+	Channels     int    `json:"channels"`
+	DurationMS   int64  `json:"duration_ms"`
+	MutedMS      int64  `json:"muted_ms"`
+}
+
+// SidecarSegment is one transcript segment after redaction. Word timings are kept only for
+// segments released unredacted.
+type SidecarSegment struct {
+	Track        Track                    `json:"track"`
+	StartMS      int64                    `json:"start_ms"`
+	EndMS        int64                    `json:"end_ms"`
+	Text         string                   `json:"text"`
+	PayloadClass eventabi.PayloadClass    `json:"payload_class"`
+	Redaction    eventabi.RedactionAction `json:"redaction"`
+	Words        []TranscriptWord         `json:"words,omitempty"`
+}
+
+// Sidecar is the transcript and provenance file written next to the exported audio.
+type Sidecar struct {
+	SessionID        string                      `json:"session_id"`
+	PipelineVersion  string                      `json:"pipeline_version"`
+	Format           string                      `json:"format"`
+	Tracks           []TrackFile                 `json:"tracks"`
+	Transcript       []SidecarSegment            `json:"transcript"`
+	DroppedSegments  int                         `json:"dropped_segments"`
+	RedactionMarkers []obs.ReplayRedactionMarker `json:"redaction_markers"`
+	CaptionFiles     []string                    `json:"caption_files,omitempty"`
+}
+
+// Exporter reconstructs session tracks from stored audio evidence under replay access policy.
+type Exporter struct {
+	Policy   replay.AccessPolicy
+	Encoders map[string]Encoder
+}
+
+// NewExporter returns an exporter with the built-in WAV and Ogg FLAC encoders.
+func NewExporter(policy replay.AccessPolicy) Exporter {
+	return Exporter{Policy: policy, Encoders: map[string]Encoder{FormatWAV: WAVEncoder{}, FormatOGG: OGGEncoder{}}}
+}
+
+// SidecarFileName returns the transcript sidecar name for a session export.
+func SidecarFileName(sessionID string) string {
+	return sessionID + "-transcript.json"
+}
+
+// Export writes one audio file per track plus a transcript sidecar into outputDir.
+// Consent is required; transcript text follows the OR-03 redaction markers, and audio
+// under PII/PHI transcript spans that are not allowed through is muted.
+func (e Exporter) Export(audio SessionAudio, req Request, outputDir string) (Sidecar, error) {
+	if err := audio.Validate(); err != nil {
+		return Sidecar{}, err
+	}
+	encoder, ok := e.Encoders[req.Format]
+	if !ok {
+		return Sidecar{}, fmt.Errorf("unsupported recording export format %q: no encoder configured", req.Format)
+	}
+	for _, format := range req.CaptionFormats {
+		if err := validateCaptionFormat(format); err != nil {
+			return Sidecar{}, err
+		}
+	}
+	if !audio.ConsentGranted {
+		return Sidecar{}, ErrConsentNotGranted
+	}
+	if req.Access.TenantID != audio.TenantID {
+		return Sidecar{}, fmt.Errorf("%w: cross_tenant_denied", ErrExportDenied)
+	}
+
+	access := req.Access
+	access.RequestedClasses = requestedClasses(audio)
+	decision := replay.AuthorizeReplayAccess(access, e.Policy)
+	if !decision.Allowed {
+		return Sidecar{}, fmt.Errorf("%w: %s", ErrExportDenied, decision.DenyReason)
+	}
+	actions := make(map[eventabi.PayloadClass]eventabi.RedactionAction, len(decision.RedactionMarkers))
+	markers := make([]obs.ReplayRedactionMarker, 0, len(decision.RedactionMarkers))
+	for _, marker := range decision.RedactionMarkers {
+		if marker.PayloadClass == eventabi.PayloadAudioRaw {
+			// Recorded consent is what releases raw audio to the QA export surface.
+			marker.Action = eventabi.RedactionAllow
+			marker.Reason = consentedAudioReason
+		}
+		actions[marker.PayloadClass] = marker.Action
+		markers = append(markers, marker)
+	}
+
+	sidecar := Sidecar{
+		SessionID:        audio.SessionID,
+		PipelineVersion:  audio.PipelineVersion,
+		Format:           req.Format,
+		Tracks:           make([]TrackFile, 0, 2),
+		Transcript:       make([]SidecarSegment, 0, len(audio.Transcript)),
+		RedactionMarkers: markers,
+	}
+	for _, segment := range audio.Transcript {
+		action := actions[segment.PayloadClass]
+		if action == eventabi.RedactionDrop {
+			sidecar.DroppedSegments++
+			continue
+		}
+		exported := SidecarSegment{
+			Track:        segment.Track,
+			StartMS:      segment.StartMS,
+			EndMS:        segment.EndMS,
+			Text:         redactText(segment.Text, action),
+			PayloadClass: segment.PayloadClass,
+			Redaction:    action,
+		}
+		if action == eventabi.RedactionAllow {
+			exported.Words = append([]TranscriptWord(nil), segment.Words...)
+		}
+		sidecar.Transcript = append(sidecar.Transcript, exported)
+	}
+
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return Sidecar{}, fmt.Errorf("create recording export dir: %w", err)
+	}
+	for _, track := range []Track{TrackUser, TrackAssistant} {
+		segments := segmentsForTrack(audio.Segments, track)
+		if len(segments) == 0 {
+			continue
+		}
+		pcm, mutedMS := reconstructTrack(segments, mutedSpans(audio.Transcript, actions, track))
+		file := TrackFile{
+			Track:        track,
+			FileName:     fmt.Sprintf("%s-%s.%s", audio.SessionID, track, req.Format),
+			SampleRateHz: segments[0].SampleRateHz,
+			Channels:     segments[0].Channels,
+			DurationMS:   int64(len(pcm)/segments[0].Channels) * 1000 / int64(segments[0].SampleRateHz),
+			MutedMS:      mutedMS,
+		}
+		var buf bytes.Buffer
+		if err := encoder.Encode(&buf, pcm, file.SampleRateHz, file.Channels); err != nil {
+			return Sidecar{}, fmt.Errorf("encode %s track: %w", track, err)
+		}
+		if err := os.WriteFile(filepath.Join(outputDir, file.FileName), buf.Bytes(), 0o644); err != nil {
+			return Sidecar{}, fmt.Errorf("write %s track: %w", track, err)
+		}
+		sidecar.Tracks = append(sidecar.Tracks, file)
+	}
+	cues := BuildCaptionCues(sidecar.Transcript)
+	for _, format := range req.CaptionFormats {
+		name := CaptionFileName(audio.SessionID, format)
+		var buf bytes.Buffer
+		if err := WriteCaptions(&buf, cues, format); err != nil {
+			return Sidecar{}, err
+		}
+		if err := os.WriteFile(filepath.Join(outputDir, name), buf.Bytes(), 0o644); err != nil {
+			return Sidecar{}, fmt.Errorf("write %s captions: %w", format, err)
+		}
+		sidecar.CaptionFiles = append(sidecar.CaptionFiles, name)
+	}
+
+	raw, err := json.MarshalIndent(sidecar, "", "  ")
+	if err != nil {
+		return Sidecar{}, fmt.Errorf("encode transcript sidecar: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, SidecarFileName(audio.SessionID)), append(raw, '\n'), 0o644); err != nil {
+		return Sidecar{}, fmt.Errorf("write transcript sidecar: %w", err)
+	}
+	return sidecar, nil
+}
+
+func requestedClasses(audio SessionAudio) []eventabi.PayloadClass {
+	seen := map[eventabi.PayloadClass]struct{}{eventabi.PayloadAudioRaw: {}}
+	classes := []eventabi.PayloadClass{eventabi.PayloadAudioRaw}
+	for _, segment := range audio.Transcript {
+		if _, ok := seen[segment.PayloadClass]; ok {
+			continue
+		}
+		seen[segment.PayloadClass] = struct{}{}
+		classes = append(classes, segment.PayloadClass)
+	}
+	return classes
+}
+
+func redactText(text string, action eventabi.RedactionAction) string {
+	switch action {
+	case eventabi.RedactionAllow:
+		return text
+	case eventabi.RedactionHash:
+		sum := sha256.Sum256([]byte(text))
+		return hex.EncodeToString(sum[:])
+	case eventabi.RedactionTokenize:
+		sum := sha256.Sum256([]byte(text))
+		return "tok_" + hex.EncodeToString(sum[:6])
+	default:
+		return maskedTranscriptText
+	}
+}
+
+type span struct {
+	startMS int64
+	endMS   int64
+}
+
+// mutedSpans returns track spans whose PII/PHI transcript was not allowed through.
+func mutedSpans(transcript []TranscriptSegment, actions map[eventabi.PayloadClass]eventabi.RedactionAction, track Track) []span {
+	spans := make([]span, 0)
+	for _, segment := range transcript {
+		if segment.Track != track {
+			continue
+		}
+		if segment.PayloadClass != eventabi.PayloadPII && segment.PayloadClass != eventabi.PayloadPHI {
+			continue
+		}
+		if actions[segment.PayloadClass] == eventabi.RedactionAllow {
+			continue
+		}
+		spans = append(spans, span{startMS: segment.StartMS, endMS: segment.EndMS})
+	}
+	return spans
+}
+
+func segmentsForTrack(segments []AudioSegment, track Track) []AudioSegment {
+	out := make([]AudioSegment, 0)
+	for _, segment := range segments {
+		if segment.Track == track {
+			out = append(out, segment)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].PTSMS < out[j].PTSMS })
+	return out
+}
+
+// reconstructTrack places segments at their PTS, fills gaps with silence, mixes overlaps with
+// saturation, and mutes redacted spans. It returns the PCM and the muted duration.
+func reconstructTrack(segments []AudioSegment, muted []span) ([]int16, int64) {
+	rate := int64(segments[0].SampleRateHz)
+	channels := int64(segments[0].Channels)
+	offset := func(ms int64) int64 { return ms * rate / 1000 * channels }
+
+	total := int64(0)
+	for _, segment := range segments {
+		if end := offset(segment.PTSMS) + int64(len(segment.PCM)); end > total {
+			total = end
+		}
+	}
+	mix := make([]int32, total)
+	for _, segment := range segments {
+		start := offset(segment.PTSMS)
+		for idx, sample := range segment.PCM {
+			mix[start+int64(idx)] += int32(sample)
+		}
+	}
+
+	mutedSamples := int64(0)
+	silenced := make([]bool, total)
+	for _, s := range muted {
+		end := offset(s.endMS)
+		if end > total {
+			end = total
+		}
+		for idx := offset(s.startMS); idx < end; idx++ {
+			if !silenced[idx] {
+				silenced[idx] = true
+				mutedSamples++
+			}
+			mix[idx] = 0
+		}
+	}
+
+	pcm := make([]int16, total)
+	for idx, sample := range mix {
+		switch {
+		case sample > math.MaxInt16:
+			pcm[idx] = math.MaxInt16
+		case sample < math.MinInt16:
+			pcm[idx] = math.MinInt16
+		default:
+			pcm[idx] = int16(sample)
+		}
+	}
+	return pcm, mutedSamples / channels * 1000 / rate
+}
@@ -0,0 +1,184 @@
+package recording
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
+)
+
+func testSessionAudio() SessionAudio {
+	return SessionAudio{
+		SessionID:       "sess-export-1",
+		TenantID:        "tenant-a",
+		PipelineVersion: "pipeline-v1",
+		ConsentGranted:  true,
+		Segments: []AudioSegment{
+			{Track: TrackUser, EventID: "evt-user-1", PTSMS: 0, SampleRateHz: 1000, Channels: 1, PCM: []int16{1, 1, 1, 1}},
+			{Track: TrackUser, EventID: "evt-user-2", PTSMS: 6, SampleRateHz: 1000, Channels: 1, PCM: []int16{2, 2, 2, 2}},
+			{Track: TrackAssistant, EventID: "evt-assistant-1", PTSMS: 2, SampleRateHz: 1000, Channels: 1, PCM: []int16{30000, 30000}},
+			{Track: TrackAssistant, EventID: "evt-assistant-2", PTSMS: 3, SampleRateHz: 1000, Channels: 1, PCM: []int16{30000}},
+		},
+		Transcript: []TranscriptSegment{
+			{Track: TrackUser, StartMS: 0, EndMS: 4, Text: "hello there", PayloadClass: eventabi.PayloadTextRaw},
+			{Track: TrackUser, StartMS: 6, EndMS: 8, Text: "555-0100", PayloadClass: eventabi.PayloadPII},
+			{Track: TrackAssistant, StartMS: 2, EndMS: 4, Text: "greeting", PayloadClass: eventabi.PayloadDerivedSummary},
+		},
+	}
+}
+
+func testExportRequest(format string) Request {
+	return Request{
+		Format: format,
+		Access: obs.ReplayAccessRequest{
+			TenantID:          "tenant-a",
+			PrincipalID:       "qa-reviewer",
+			Role:              "operator",
+			Purpose:           "eval",
+			RequestedScope:    "sess-export-1",
+			RequestedFidelity: obs.ReplayFidelityL0,
+		},
+	}
+}
+
+func readWAVData(t *testing.T, path string) []int16 {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if len(raw) < 44 || string(raw[0:4]) != "RIFF" || string(raw[8:12]) != "WAVE" || string(raw[36:40]) != "data" {
+		t.Fatalf("expected canonical wav header in %s", path)
+	}
+	samples := make([]int16, (len(raw)-44)/2)
+	for idx := range samples {
+		samples[idx] = int16(binary.LittleEndian.Uint16(raw[44+2*idx:]))
+	}
+	return samples
+}
+
+func TestExportReconstructsTracksWithRedaction(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sidecar, err := NewExporter(replay.DefaultAccessPolicy("tenant-a")).Export(testSessionAudio(), testExportRequest(FormatWAV), dir)
+	if err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+
+	user := readWAVData(t, filepath.Join(dir, "sess-export-1-user.wav"))
+	wantUser := []int16{1, 1, 1, 1, 0, 0, 0, 0, 2, 2}
+	if len(user) != len(wantUser) {
+		t.Fatalf("expected user track %v, got %v", wantUser, user)
+	}
+	for idx := range wantUser {
+		if user[idx] != wantUser[idx] {
+			t.Fatalf("expected gap-filled user track with muted PII span %v, got %v", wantUser, user)
+		}
+	}
+	assistant := readWAVData(t, filepath.Join(dir, "sess-export-1-assistant.wav"))
+	if len(assistant) != 4 || assistant[2] != 30000 || assistant[3] != 32767 {
+		t.Fatalf("expected overlapping assistant audio mixed with saturation, got %v", assistant)
+	}
+
+	if len(sidecar.Tracks) != 2 || sidecar.Tracks[0].MutedMS != 2 || sidecar.Tracks[0].DurationMS != 10 {
+		t.Fatalf("unexpected sidecar tracks: %+v", sidecar.Tracks)
+	}
+	byClass := map[eventabi.PayloadClass]SidecarSegment{}
+	for _, segment := range sidecar.Transcript {
+		byClass[segment.PayloadClass] = segment
+	}
+	if byClass[eventabi.PayloadTextRaw].Text != maskedTranscriptText || byClass[eventabi.PayloadDerivedSummary].Text != "greeting" {
+		t.Fatalf("expected OR-03 transcript redaction, got %+v", sidecar.Transcript)
+	}
+	if pii := byClass[eventabi.PayloadPII]; pii.Redaction != eventabi.RedactionTokenize || pii.Text == "555-0100" {
+		t.Fatalf("expected tokenized PII transcript, got %+v", pii)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, SidecarFileName("sess-export-1")))
+	if err != nil {
+		t.Fatalf("unexpected sidecar read error: %v", err)
+	}
+	var written Sidecar
+	if err := json.Unmarshal(raw, &written); err != nil || written.SessionID != "sess-export-1" || len(written.RedactionMarkers) != 4 {
+		t.Fatalf("expected sidecar file with redaction markers, got %+v err=%v", written, err)
+	}
+}
+
+func TestExportEnforcesConsentAccessAndFormat(t *testing.T) {
+	t.Parallel()
+
+	noConsent := testSessionAudio()
+	noConsent.ConsentGranted = false
+	wrongRole := testExportRequest(FormatWAV)
+	wrongRole.Access.Role = "guest"
+	crossTenant := testExportRequest(FormatWAV)
+	crossTenant.Access.TenantID = "tenant-b"
+
+	tests := []struct {
+		name    string
+		audio   SessionAudio
+		request Request
+		wantErr error
+	}{
+		{name: "consent missing", audio: noConsent, request: testExportRequest(FormatWAV), wantErr: ErrConsentNotGranted},
+		{name: "role denied", audio: testSessionAudio(), request: wrongRole, wantErr: ErrExportDenied},
+		{name: "cross tenant denied", audio: testSessionAudio(), request: crossTenant, wantErr: ErrExportDenied},
+		{name: "format without encoder", audio: testSessionAudio(), request: testExportRequest("mp3")},
+	}
+	for _, tc := range tests {
+		dir := t.TempDir()
+		_, err := NewExporter(replay.DefaultAccessPolicy("tenant-a")).Export(tc.audio, tc.request, dir)
+		if err == nil || (tc.wantErr != nil && !errors.Is(err, tc.wantErr)) {
+			t.Fatalf("%s: expected error %v, got %v", tc.name, tc.wantErr, err)
+		}
+		if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+			t.Fatalf("%s: expected no files written on rejected export, got %d", tc.name, len(entries))
+		}
+	}
+}
+
+func TestSessionAudioValidateRejectsMixedTrackFormats(t *testing.T) {
+	t.Parallel()
+
+	audio := testSessionAudio()
+	audio.Segments[1].SampleRateHz = 16000
+	if err := audio.Validate(); err == nil {
+		t.Fatalf("expected mixed sample rates on one track to be rejected")
+	}
+}
+
+func TestDecodeWAVRoundTripsEncoder(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	pcm := []int16{1, -2, 300, -32768, 32767, 0}
+	if err := (WAVEncoder{}).Encode(&buf, pcm, 8000, 2); err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	}
+	decoded, rate, channels, err := DecodeWAV(bytes.NewReader(buf.Bytes()))
+	if err != nil || rate != 8000 || channels != 2 || len(decoded) != len(pcm) {
+		t.Fatalf("expected round-tripped stereo 8kHz wav, got %v rate=%d channels=%d err=%v", decoded, rate, channels, err)
+	}
+	for idx := range pcm {
+		if decoded[idx] != pcm[idx] {
+			t.Fatalf("expected samples %v, got %v", pcm, decoded)
+		}
+	}
+
+	for name, raw := range map[string][]byte{
+		"not riff":  []byte("OggS0000WAVE"),
+		"truncated": buf.Bytes()[:40],
+	} {
+		if _, _, _, err := DecodeWAV(bytes.NewReader(raw)); err == nil {
+			t.Fatalf("%s: expected decode error", name)
+		}
+	}
+}
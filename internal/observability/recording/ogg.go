@@ -0,0 +1,305 @@
+package recording
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	// oggFLACBlockSize is the per-channel sample count of each FLAC frame.
+	oggFLACBlockSize = 4096
+	// oggStreamSerial is fixed so an export of the same session is byte-for-byte reproducible.
+	oggStreamSerial = 0x52535050
+	oggFLACVendor   = "rspp recording export"
+
+	oggFlagContinued = 0x01
+	oggFlagBOS       = 0x02
+	oggFlagEOS       = 0x04
+	// oggNoGranule marks a page on which no packet completes.
+	oggNoGranule = ^uint64(0)
+	oggMaxLacing = 255
+
+	flacMaxChannels   = 8
+	flacMaxSampleRate = 1<<20 - 1
+)
+
+// OGGEncoder writes lossless Ogg FLAC files (the Ogg mapping of FLAC 1.0): one header page
+// with STREAMINFO, one with a Vorbis comment, then one FLAC frame per audio page. Silent
+// blocks are coded as constant subframes and the rest verbatim, so no codec library is needed.
+type OGGEncoder struct{}
+
+// Encode writes pcm as an Ogg FLAC stream.
+func (OGGEncoder) Encode(w io.Writer, pcm []int16, sampleRateHz int, channels int) error {
+	if sampleRateHz < 1 || sampleRateHz > flacMaxSampleRate || channels < 1 || channels > flacMaxChannels {
+		return fmt.Errorf("ogg encode requires sample_rate_hz in [1,%d] and channels in [1,%d]", flacMaxSampleRate, flacMaxChannels)
+	}
+	if len(pcm)%channels != 0 {
+		return fmt.Errorf("ogg encode: %d samples is not a whole number of %d-channel frames", len(pcm), channels)
+	}
+	frames := len(pcm) / channels
+
+	var frameData [][]byte
+	minFrame, maxFrame := 0, 0
+	for start, number := 0, uint64(0); start < frames; start, number = start+oggFLACBlockSize, number+1 {
+		end := min(start+oggFLACBlockSize, frames)
+		frame := flacFrame(pcm[start*channels:end*channels], sampleRateHz, channels, number)
+		if minFrame == 0 || len(frame) < minFrame {
+			minFrame = len(frame)
+		}
+		maxFrame = max(maxFrame, len(frame))
+		frameData = append(frameData, frame)
+	}
+
+	sum := md5.New()
+	if err := binary.Write(sum, binary.LittleEndian, pcm); err != nil {
+		return fmt.Errorf("hash ogg pcm: %w", err)
+	}
+	streamInfo := flacStreamInfo(sampleRateHz, channels, frames, minFrame, maxFrame, sum.Sum(nil))
+
+	first := bytes.NewBuffer([]byte{0x7F, 'F', 'L', 'A', 'C', 1, 0, 0, 1})
+	first.WriteString("fLaC")
+	first.Write(flacMetadataBlock(0, false, streamInfo))
+
+	stream := oggStream{w: w}
+	if err := stream.writePacket(first.Bytes(), 0, oggFlagBOS); err != nil {
+		return err
+	}
+	eos := 0
+	if len(frameData) == 0 {
+		eos = oggFlagEOS
+	}
+	if err := stream.writePacket(flacMetadataBlock(4, true, flacVorbisComment()), 0, eos); err != nil {
+		return err
+	}
+	for idx, frame := range frameData {
+		granule := uint64(min((idx+1)*oggFLACBlockSize, frames))
+		flags := 0
+		if idx == len(frameData)-1 {
+			flags = oggFlagEOS
+		}
+		if err := stream.writePacket(frame, granule, flags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func flacStreamInfo(sampleRateHz int, channels int, frames int, minFrame int, maxFrame int, digest []byte) []byte {
+	blockSize := uint16(oggFLACBlockSize)
+	if frames < oggFLACBlockSize {
+		// Minimum block size must be at least 16 unless the stream has a single short block.
+		blockSize = uint16(max(frames, 16))
+	}
+	info := make([]byte, 34)
+	binary.BigEndian.PutUint16(info[0:], blockSize)
+	binary.BigEndian.PutUint16(info[2:], blockSize)
+	putUint24(info[4:], uint32(minFrame))
+	putUint24(info[7:], uint32(maxFrame))
+	// 20-bit sample rate, 3-bit channels-1, 5-bit bits-per-sample-1, 36-bit total samples.
+	packed := uint64(sampleRateHz)<<44 | uint64(channels-1)<<41 | uint64(15)<<36 | uint64(frames)&(1<<36-1)
+	binary.BigEndian.PutUint64(info[10:], packed)
+	copy(info[18:], digest)
+	return info
+}
+
+func flacVorbisComment() []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(oggFLACVendor)))
+	buf.WriteString(oggFLACVendor)
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(0))
+	return buf.Bytes()
+}
+
+func flacMetadataBlock(blockType byte, last bool, body []byte) []byte {
+	header := make([]byte, 4, 4+len(body))
+	header[0] = blockType
+	if last {
+		header[0] |= 0x80
+	}
+	putUint24(header[1:], uint32(len(body)))
+	return append(header, body...)
+}
+
+// flacFrame codes one fixed-blocksize FLAC frame of interleaved 16-bit pcm.
+func flacFrame(pcm []int16, sampleRateHz int, channels int, number uint64) []byte {
+	blockSize := len(pcm) / channels
+	rateCode, rateExtra := flacSampleRateCode(sampleRateHz)
+	frame := []byte{0xFF, 0xF8, 0x70 | rateCode, byte(channels-1)<<4 | 0x08}
+	frame = append(frame, flacFrameNumber(number)...)
+	frame = binary.BigEndian.AppendUint16(frame, uint16(blockSize-1))
+	frame = append(frame, rateExtra...)
+	frame = append(frame, flacCRC8(frame))
+
+	for ch := 0; ch < channels; ch++ {
+		constant := true
+		for idx := 1; idx < blockSize && constant; idx++ {
+			constant = pcm[idx*channels+ch] == pcm[ch]
+		}
+		if constant {
+			frame = append(frame, 0x00)
+			frame = binary.BigEndian.AppendUint16(frame, uint16(pcm[ch]))
+			continue
+		}
+		frame = append(frame, 0x02)
+		for idx := 0; idx < blockSize; idx++ {
+			frame = binary.BigEndian.AppendUint16(frame, uint16(pcm[idx*channels+ch]))
+		}
+	}
+	return binary.BigEndian.AppendUint16(frame, flacCRC16(frame))
+}
+
+// flacSampleRateCode returns the frame header sample rate code and any trailing rate bytes.
+func flacSampleRateCode(sampleRateHz int) (byte, []byte) {
+	switch sampleRateHz {
+	case 8000:
+		return 0x4, nil
+	case 16000:
+		return 0x5, nil
+	case 22050:
+		return 0x6, nil
+	case 24000:
+		return 0x7, nil
+	case 32000:
+		return 0x8, nil
+	case 44100:
+		return 0x9, nil
+	case 48000:
+		return 0xA, nil
+	case 96000:
+		return 0xB, nil
+	}
+	if sampleRateHz <= 0xFFFF {
+		return 0xD, binary.BigEndian.AppendUint16(nil, uint16(sampleRateHz))
+	}
+	// Fall back to the STREAMINFO rate.
+	return 0x0, nil
+}
+
+// flacFrameNumber codes a frame number with FLAC's extended UTF-8 scheme.
+func flacFrameNumber(number uint64) []byte {
+	if number < 0x80 {
+		return []byte{byte(number)}
+	}
+	tail := make([]byte, 0, 6)
+	for limit := uint64(0x20); ; limit >>= 1 {
+		tail = append([]byte{0x80 | byte(number&0x3F)}, tail...)
+		number >>= 6
+		if number < limit {
+			lead := byte(0xFF << (7 - len(tail)))
+			return append([]byte{lead | byte(number)}, tail...)
+		}
+	}
+}
+
+func putUint24(b []byte, v uint32) {
+	b[0], b[1], b[2] = byte(v>>16), byte(v>>8), byte(v)
+}
+
+func flacCRC8(data []byte) byte {
+	crc := byte(0)
+	for _, b := range data {
+		crc ^= b
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func flacCRC16(data []byte) uint16 {
+	crc := uint16(0)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x8005
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// oggStream writes packets of one logical Ogg bitstream, each packet starting a fresh page.
+type oggStream struct {
+	w        io.Writer
+	sequence uint32
+}
+
+// writePacket pages packet, continuing it across pages past 255 lacing values. granule and
+// the EOS flag go on the page the packet completes on.
+func (s *oggStream) writePacket(packet []byte, granule uint64, flags int) error {
+	lacing := make([]byte, 0, len(packet)/oggMaxLacing+1)
+	for remaining := len(packet); ; remaining -= oggMaxLacing {
+		if remaining < oggMaxLacing {
+			lacing = append(lacing, byte(remaining))
+			break
+		}
+		lacing = append(lacing, oggMaxLacing)
+	}
+	headerType := byte(flags & oggFlagBOS)
+	for len(lacing) > 0 {
+		count := min(len(lacing), oggMaxLacing)
+		segments := lacing[:count]
+		size := 0
+		for _, value := range segments {
+			size += int(value)
+		}
+		pageGranule := oggNoGranule
+		if count == len(lacing) {
+			pageGranule = granule
+			headerType |= byte(flags & oggFlagEOS)
+		}
+		page := make([]byte, 27, 27+count+size)
+		copy(page, "OggS")
+		page[5] = headerType
+		binary.LittleEndian.PutUint64(page[6:], pageGranule)
+		binary.LittleEndian.PutUint32(page[14:], oggStreamSerial)
+		binary.LittleEndian.PutUint32(page[18:], s.sequence)
+		page[26] = byte(count)
+		page = append(page, segments...)
+		page = append(page, packet[:size]...)
+		binary.LittleEndian.PutUint32(page[22:], oggCRC32(page))
+		if _, err := s.w.Write(page); err != nil {
+			return fmt.Errorf("write ogg page: %w", err)
+		}
+		s.sequence++
+		packet = packet[size:]
+		lacing = lacing[count:]
+		headerType = oggFlagContinued
+	}
+	return nil
+}
+
+var oggCRCTable = func() [256]uint32 {
+	var table [256]uint32
+	for idx := range table {
+		crc := uint32(idx) << 24
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+		table[idx] = crc
+	}
+	return table
+}()
+
+// oggCRC32 is the Ogg page checksum: CRC-32 polynomial 0x04C11DB7, unreflected, zero init.
+func oggCRC32(page []byte) uint32 {
+	crc := uint32(0)
+	for _, b := range page {
+		crc = crc<<8 ^ oggCRCTable[byte(crc>>24)^b]
+	}
+	return crc
+}
@@ -0,0 +1,279 @@
+package recording
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
+)
+
+func TestOGGEncoderRoundTripsFLACFrames(t *testing.T) {
+	t.Parallel()
+
+	tone := func(frames int, channels int) []int16 {
+		pcm := make([]int16, frames*channels)
+		for idx := range pcm {
+			pcm[idx] = int16((idx*37)%2000 - 1000)
+		}
+		return pcm
+	}
+	silenceThenTone := append(make([]int16, oggFLACBlockSize), tone(100, 1)...)
+
+	tests := []struct {
+		name         string
+		pcm          []int16
+		sampleRateHz int
+		channels     int
+		wantFrames   int
+	}{
+		{name: "empty", pcm: nil, sampleRateHz: 16000, channels: 1},
+		{name: "short mono", pcm: tone(10, 1), sampleRateHz: 16000, channels: 1, wantFrames: 1},
+		{name: "silent block then tone", pcm: silenceThenTone, sampleRateHz: 24000, channels: 1, wantFrames: 2},
+		{name: "stereo uncommon rate", pcm: tone(5000, 2), sampleRateHz: 11025, channels: 2, wantFrames: 2},
+		{name: "packet spans pages", pcm: tone(oggFLACBlockSize, 8), sampleRateHz: 48000, channels: 8, wantFrames: 1},
+		{name: "rate beyond frame header", pcm: tone(20, 1), sampleRateHz: 96001, channels: 1, wantFrames: 1},
+	}
+	for _, tc := range tests {
+		var buf bytes.Buffer
+		if err := (OGGEncoder{}).Encode(&buf, tc.pcm, tc.sampleRateHz, tc.channels); err != nil {
+			t.Fatalf("%s: unexpected encode error: %v", tc.name, err)
+		}
+		pcm, sampleRateHz, channels, frames, err := decodeOGGFLAC(buf.Bytes())
+		if err != nil {
+			t.Fatalf("%s: decode: %v", tc.name, err)
+		}
+		if sampleRateHz != tc.sampleRateHz || channels != tc.channels || frames != tc.wantFrames {
+			t.Fatalf("%s: expected %d Hz %d ch %d frames, got %d Hz %d ch %d frames", tc.name, tc.sampleRateHz, tc.channels, tc.wantFrames, sampleRateHz, channels, frames)
+		}
+		if len(pcm) != len(tc.pcm) || (len(pcm) > 0 && !reflect.DeepEqual(pcm, tc.pcm)) {
+			t.Fatalf("%s: expected lossless round trip of %d samples, got %d", tc.name, len(tc.pcm), len(pcm))
+		}
+	}
+}
+
+func TestExportWritesOGGTracks(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sidecar, err := NewExporter(replay.DefaultAccessPolicy("tenant-a")).Export(testSessionAudio(), testExportRequest(FormatOGG), dir)
+	if err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+	if len(sidecar.Tracks) != 2 || sidecar.Tracks[0].FileName != "sess-export-1-user.ogg" {
+		t.Fatalf("expected ogg track files, got %+v", sidecar.Tracks)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, "sess-export-1-user.ogg"))
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	pcm, sampleRateHz, _, _, err := decodeOGGFLAC(raw)
+	wantUser := []int16{1, 1, 1, 1, 0, 0, 0, 0, 2, 2}
+	if err != nil || sampleRateHz != 1000 || !reflect.DeepEqual(pcm, wantUser) {
+		t.Fatalf("expected redacted user track %v in ogg, got %v rate=%d err=%v", wantUser, pcm, sampleRateHz, err)
+	}
+}
+
+func TestOGGEncoderRejectsInvalidLayout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		pcm          []int16
+		sampleRateHz int
+		channels     int
+	}{
+		{name: "zero rate", pcm: []int16{1}, sampleRateHz: 0, channels: 1},
+		{name: "too many channels", pcm: make([]int16, 9), sampleRateHz: 16000, channels: 9},
+		{name: "partial frame", pcm: []int16{1, 2, 3}, sampleRateHz: 16000, channels: 2},
+	}
+	for _, tc := range tests {
+		if err := (OGGEncoder{}).Encode(&bytes.Buffer{}, tc.pcm, tc.sampleRateHz, tc.channels); err == nil {
+			t.Fatalf("%s: expected encode error", tc.name)
+		}
+	}
+}
+
+func TestFLACFrameNumberEncoding(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		number uint64
+		want   []byte
+	}{
+		{number: 0, want: []byte{0x00}},
+		{number: 0x7F, want: []byte{0x7F}},
+		{number: 0x80, want: []byte{0xC2, 0x80}},
+		{number: 0x7FF, want: []byte{0xDF, 0xBF}},
+		{number: 0x800, want: []byte{0xE0, 0xA0, 0x80}},
+		{number: 0x10000, want: []byte{0xF0, 0x90, 0x80, 0x80}},
+	}
+	for _, tc := range tests {
+		if got := flacFrameNumber(tc.number); !bytes.Equal(got, tc.want) {
+			t.Fatalf("frame %d: expected % X, got % X", tc.number, tc.want, got)
+		}
+	}
+}
+
+// decodeOGGFLAC parses an Ogg FLAC stream written by OGGEncoder, checking page and frame
+// checksums, granule positions, and the STREAMINFO MD5.
+func decodeOGGFLAC(raw []byte) ([]int16, int, int, int, error) {
+	var packets [][]byte
+	var granules []uint64
+	var pending []byte
+	sequence := uint32(0)
+	sawEOS := false
+	for offset := 0; offset < len(raw); {
+		if sawEOS {
+			return nil, 0, 0, 0, fmt.Errorf("page after EOS")
+		}
+		if offset+27 > len(raw) || string(raw[offset:offset+4]) != "OggS" {
+			return nil, 0, 0, 0, fmt.Errorf("missing capture pattern at %d", offset)
+		}
+		headerType := raw[offset+5]
+		granule := binary.LittleEndian.Uint64(raw[offset+6:])
+		if got := binary.LittleEndian.Uint32(raw[offset+18:]); got != sequence {
+			return nil, 0, 0, 0, fmt.Errorf("page sequence %d, expected %d", got, sequence)
+		}
+		if (headerType&oggFlagBOS != 0) != (sequence == 0) {
+			return nil, 0, 0, 0, fmt.Errorf("BOS flag on page %d", sequence)
+		}
+		if (headerType&oggFlagContinued != 0) != (len(pending) > 0) {
+			return nil, 0, 0, 0, fmt.Errorf("continuation flag mismatch on page %d", sequence)
+		}
+		count := int(raw[offset+26])
+		lacing := raw[offset+27 : offset+27+count]
+		size := 0
+		for _, value := range lacing {
+			size += int(value)
+		}
+		end := offset + 27 + count + size
+		page := append([]byte(nil), raw[offset:end]...)
+		want := binary.LittleEndian.Uint32(page[22:])
+		binary.LittleEndian.PutUint32(page[22:], 0)
+		if got := oggCRC32(page); got != want {
+			return nil, 0, 0, 0, fmt.Errorf("page %d crc %08x, expected %08x", sequence, got, want)
+		}
+		body := raw[offset+27+count : end]
+		completed := false
+		for _, value := range lacing {
+			pending = append(pending, body[:value]...)
+			body = body[value:]
+			if value < oggMaxLacing {
+				packets = append(packets, pending)
+				pending = nil
+				completed = true
+			}
+		}
+		if completed {
+			granules = append(granules, granule)
+		} else if granule != oggNoGranule {
+			return nil, 0, 0, 0, fmt.Errorf("granule on page %d without a completed packet", sequence)
+		}
+		sawEOS = headerType&oggFlagEOS != 0
+		offset = end
+		sequence++
+	}
+	if !sawEOS || len(pending) > 0 || len(packets) < 2 {
+		return nil, 0, 0, 0, fmt.Errorf("stream not terminated: eos=%v pending=%d packets=%d", sawEOS, len(pending), len(packets))
+	}
+
+	first := packets[0]
+	if len(first) != 51 || !bytes.Equal(first[:5], []byte{0x7F, 'F', 'L', 'A', 'C'}) || string(first[9:13]) != "fLaC" || first[13] != 0 {
+		return nil, 0, 0, 0, fmt.Errorf("bad first header packet % X", first)
+	}
+	if packets[1][0] != 0x84 {
+		return nil, 0, 0, 0, fmt.Errorf("expected last-block vorbis comment, got type %#x", packets[1][0])
+	}
+	info := first[17:]
+	packed := binary.BigEndian.Uint64(info[10:])
+	sampleRateHz := int(packed >> 44)
+	channels := int(packed>>41&0x7) + 1
+	if bps := int(packed>>36&0x1F) + 1; bps != 16 {
+		return nil, 0, 0, 0, fmt.Errorf("bits per sample %d", bps)
+	}
+	total := int(packed & (1<<36 - 1))
+
+	pcm := make([]int16, 0, total*channels)
+	for idx, frame := range packets[2:] {
+		if binary.BigEndian.Uint16(frame[len(frame)-2:]) != flacCRC16(frame[:len(frame)-2]) {
+			return nil, 0, 0, 0, fmt.Errorf("frame %d crc16 mismatch", idx)
+		}
+		if frame[0] != 0xFF || frame[1] != 0xF8 || frame[2]>>4 != 0x7 || int(frame[3]>>4)+1 != channels || frame[3]&0x0F != 0x08 {
+			return nil, 0, 0, 0, fmt.Errorf("frame %d bad header % X", idx, frame[:4])
+		}
+		number := flacFrameNumber(uint64(idx))
+		if !bytes.Equal(frame[4:4+len(number)], number) {
+			return nil, 0, 0, 0, fmt.Errorf("frame %d bad frame number", idx)
+		}
+		pos := 4 + len(number)
+		blockSize := int(binary.BigEndian.Uint16(frame[pos:])) + 1
+		pos += 2
+		if frame[2]&0x0F == 0xD {
+			if rate := int(binary.BigEndian.Uint16(frame[pos:])); rate != sampleRateHz {
+				return nil, 0, 0, 0, fmt.Errorf("frame %d rate %d", idx, rate)
+			}
+			pos += 2
+		}
+		if frame[pos] != flacCRC8(frame[:pos]) {
+			return nil, 0, 0, 0, fmt.Errorf("frame %d crc8 mismatch", idx)
+		}
+		pos++
+		block := make([]int16, blockSize*channels)
+		for ch := 0; ch < channels; ch++ {
+			kind := frame[pos]
+			pos++
+			for sample := 0; sample < blockSize; sample++ {
+				switch kind {
+				case 0x00:
+					block[sample*channels+ch] = int16(binary.BigEndian.Uint16(frame[pos:]))
+				case 0x02:
+					block[sample*channels+ch] = int16(binary.BigEndian.Uint16(frame[pos+2*sample:]))
+				default:
+					return nil, 0, 0, 0, fmt.Errorf("frame %d unexpected subframe %#x", idx, kind)
+				}
+			}
+			if kind == 0x00 {
+				pos += 2
+			} else {
+				pos += 2 * blockSize
+			}
+		}
+		if pos != len(frame)-2 {
+			return nil, 0, 0, 0, fmt.Errorf("frame %d has %d trailing bytes", idx, len(frame)-2-pos)
+		}
+		pcm = append(pcm, block...)
+		if granules[idx+2] != uint64(len(pcm)/channels) {
+			return nil, 0, 0, 0, fmt.Errorf("frame %d granule %d, expected %d", idx, granules[idx+2], len(pcm)/channels)
+		}
+	}
+	if len(pcm) != total*channels {
+		return nil, 0, 0, 0, fmt.Errorf("decoded %d samples, streaminfo says %d", len(pcm)/channels, total)
+	}
+	sum := md5.New()
+	_ = binary.Write(sum, binary.LittleEndian, pcm)
+	if !bytes.Equal(sum.Sum(nil), info[18:34]) {
+		return nil, 0, 0, 0, fmt.Errorf("streaminfo md5 mismatch")
+	}
+	return pcm, sampleRateHz, channels, len(packets) - 2, nil
+}
+
+func TestOGGAndFLACChecksums(t *testing.T) {
+	t.Parallel()
+
+	check := []byte("123456789")
+	if got := oggCRC32(check); got != 0x89A1897F {
+		t.Fatalf("expected ogg crc32 0x89A1897F, got %#08x", got)
+	}
+	if got := flacCRC8(check); got != 0xF4 {
+		t.Fatalf("expected flac crc8 0xF4, got %#02x", got)
+	}
+	if got := flacCRC16(check); got != 0xFEE8 {
+		t.Fatalf("expected flac crc16 0xFEE8, got %#04x", got)
+	}
+}
@@ -0,0 +1,149 @@
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+// Track identifies one side of a session conversation.
+type Track string
+
+const (
+	TrackUser      Track = "user"
+	TrackAssistant Track = "assistant"
+)
+
+// Validate enforces supported track values.
+func (t Track) Validate() error {
+	switch t {
+	case TrackUser, TrackAssistant:
+		return nil
+	default:
+		return fmt.Errorf("invalid recording track: %q", t)
+	}
+}
+
+// AudioSegment is one stored chunk of 16-bit PCM audio evidence placed at PTSMS on its track.
+type AudioSegment struct {
+	Track        Track   `json:"track"`
+	EventID      string  `json:"event_id"`
+	PTSMS        int64   `json:"pts_ms"`
+	SampleRateHz int     `json:"sample_rate_hz"`
+	Channels     int     `json:"channels"`
+	PCM          []int16 `json:"pcm"`
+}
+
+// Validate enforces audio segment invariants.
+func (s AudioSegment) Validate() error {
+	if err := s.Track.Validate(); err != nil {
+		return err
+	}
+	if s.EventID == "" {
+		return fmt.Errorf("audio segment event_id is required")
+	}
+	if s.PTSMS < 0 {
+		return fmt.Errorf("audio segment pts_ms must be >=0")
+	}
+	if s.SampleRateHz < 1 || s.Channels < 1 {
+		return fmt.Errorf("audio segment sample_rate_hz and channels must be >=1")
+	}
+	if len(s.PCM)%s.Channels != 0 {
+		return fmt.Errorf("audio segment %s pcm length must be a multiple of channels", s.EventID)
+	}
+	return nil
+}
+
+// TranscriptSegment is one transcript span with the payload class it was tagged with.
+// Words carries word-level timings when the STT provider reported them.
+type TranscriptSegment struct {
+	Track        Track                 `json:"track"`
+	StartMS      int64                 `json:"start_ms"`
+	EndMS        int64                 `json:"end_ms"`
+	Text         string                `json:"text"`
+	PayloadClass eventabi.PayloadClass `json:"payload_class"`
+	Words        []TranscriptWord      `json:"words,omitempty"`
+}
+
+// TranscriptWord is one recognized word placed on the session audio timeline.
+type TranscriptWord struct {
+	Text    string `json:"text"`
+	StartMS int64  `json:"start_ms"`
+	EndMS   int64  `json:"end_ms"`
+}
+
+// Validate enforces transcript segment invariants.
+func (s TranscriptSegment) Validate() error {
+	if err := s.Track.Validate(); err != nil {
+		return err
+	}
+	if s.StartMS < 0 || s.EndMS < s.StartMS {
+		return fmt.Errorf("transcript segment requires 0<=start_ms<=end_ms")
+	}
+	if err := (eventabi.RedactionDecision{PayloadClass: s.PayloadClass, Action: eventabi.RedactionAllow}).Validate(); err != nil {
+		return err
+	}
+	previousStartMS := s.StartMS
+	for _, word := range s.Words {
+		if word.Text == "" {
+			return fmt.Errorf("transcript word text is required")
+		}
+		if word.StartMS < previousStartMS || word.EndMS < word.StartMS || word.EndMS > s.EndMS {
+			return fmt.Errorf("transcript words must be ordered within the segment span")
+		}
+		previousStartMS = word.StartMS
+	}
+	return nil
+}
+
+// SessionAudio is the stored audio evidence for one session.
+type SessionAudio struct {
+	SessionID       string              `json:"session_id"`
+	TenantID        string              `json:"tenant_id"`
+	PipelineVersion string              `json:"pipeline_version"`
+	ConsentGranted  bool                `json:"consent_granted"`
+	Segments        []AudioSegment      `json:"segments"`
+	Transcript      []TranscriptSegment `json:"transcript,omitempty"`
+}
+
+// Validate enforces session audio invariants, including one audio format per track.
+func (s SessionAudio) Validate() error {
+	if s.SessionID == "" || s.TenantID == "" || s.PipelineVersion == "" {
+		return fmt.Errorf("session_id, tenant_id, and pipeline_version are required")
+	}
+	formats := map[Track][2]int{}
+	for _, segment := range s.Segments {
+		if err := segment.Validate(); err != nil {
+			return err
+		}
+		format := [2]int{segment.SampleRateHz, segment.Channels}
+		if existing, ok := formats[segment.Track]; ok && existing != format {
+			return fmt.Errorf("audio segments on track %s must share sample_rate_hz and channels", segment.Track)
+		}
+		formats[segment.Track] = format
+	}
+	for _, segment := range s.Transcript {
+		if err := segment.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadSessionAudio reads and validates stored session audio evidence from a JSON file.
+func LoadSessionAudio(path string) (SessionAudio, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return SessionAudio{}, fmt.Errorf("read session audio %s: %w", path, err)
+	}
+	var audio SessionAudio
+	if err := json.Unmarshal(raw, &audio); err != nil {
+		return SessionAudio{}, fmt.Errorf("decode session audio %s: %w", path, err)
+	}
+	if err := audio.Validate(); err != nil {
+		return SessionAudio{}, fmt.Errorf("invalid session audio %s: %w", path, err)
+	}
+	return audio, nil
+}
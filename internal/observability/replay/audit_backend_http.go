@@ -13,6 +13,7 @@ import (
 	"time"
 
 	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/backoff"
 )
 
 const (
@@ -32,6 +33,8 @@ const (
 	EnvReplayAuditHTTPRetryBackoffMS = "RSPP_REPLAY_AUDIT_HTTP_RETRY_BACKOFF_MS"
 	// EnvReplayAuditHTTPRetryMaxBackoffMS configures max retry backoff in milliseconds.
 	EnvReplayAuditHTTPRetryMaxBackoffMS = "RSPP_REPLAY_AUDIT_HTTP_RETRY_MAX_BACKOFF_MS"
+	// EnvReplayAuditHTTPRetryJitter selects retry jitter mode (none|decorrelated).
+	EnvReplayAuditHTTPRetryJitter = "RSPP_REPLAY_AUDIT_HTTP_RETRY_JITTER"
 	// EnvReplayAuditJSONLFallbackRootDir configures JSONL fallback root directory when HTTP backend is enabled.
 	EnvReplayAuditJSONLFallbackRootDir = "RSPP_REPLAY_AUDIT_JSONL_FALLBACK_ROOT"
 
@@ -54,6 +57,7 @@ type HTTPAuditBackendConfig struct {
 	RetryMaxAttempts int
 	RetryBackoff     time.Duration
 	RetryMaxBackoff  time.Duration
+	RetryJitter      backoff.Jitter
 	Sleep            func(time.Duration)
 }
 
@@ -106,6 +110,10 @@ func HTTPAuditBackendConfigFromEnv() (HTTPAuditBackendConfig, error) {
 	if err != nil {
 		return HTTPAuditBackendConfig{}, err
 	}
+	retryJitter := backoff.Jitter(strings.TrimSpace(os.Getenv(EnvReplayAuditHTTPRetryJitter)))
+	if err := (backoff.Policy{Base: retryBackoff, Max: max(retryMaxBackoff, retryBackoff), Jitter: retryJitter}).Validate(); err != nil {
+		return HTTPAuditBackendConfig{}, fmt.Errorf("%s: %w", EnvReplayAuditHTTPRetryJitter, err)
+	}
 
 	return HTTPAuditBackendConfig{
 		URL:              urls[0],
@@ -116,6 +124,7 @@ func HTTPAuditBackendConfigFromEnv() (HTTPAuditBackendConfig, error) {
 		RetryMaxAttempts: retryMaxAttempts,
 		RetryBackoff:     retryBackoff,
 		RetryMaxBackoff:  retryMaxBackoff,
+		RetryJitter:      retryJitter,
 	}, nil
 }
 
@@ -251,6 +260,11 @@ func (b *HTTPAuditBackend) AppendReplayAuditEvent(event obs.ReplayAuditEvent) er
 
 func (b *HTTPAuditBackend) appendWithRetry(cfg HTTPAuditBackendConfig, endpoint string, payload []byte, tenantID string) error {
 	var lastErr error
+	delays := backoff.Policy{
+		Base:   cfg.RetryBackoff,
+		Max:    cfg.RetryMaxBackoff,
+		Jitter: cfg.RetryJitter,
+	}.Normalize(time.Duration(defaultReplayAuditRetryBackoffMS) * time.Millisecond).NewSequence(endpoint + "|" + tenantID)
 	for attempt := 1; attempt <= cfg.RetryMaxAttempts; attempt++ {
 		err := appendReplayAuditEventOnce(cfg, endpoint, payload, tenantID)
 		if err == nil {
@@ -260,7 +274,11 @@ func (b *HTTPAuditBackend) appendWithRetry(cfg HTTPAuditBackendConfig, endpoint
 		if attempt == cfg.RetryMaxAttempts || !shouldRetryReplayAuditError(err) {
 			break
 		}
-		cfg.Sleep(replayAuditRetryBackoffDuration(cfg.RetryBackoff, cfg.RetryMaxBackoff, attempt))
+		delay, ok := delays.Next()
+		if !ok {
+			break
+		}
+		cfg.Sleep(delay)
 	}
 	if lastErr == nil {
 		return fmt.Errorf("append replay audit event: endpoint %s failed", endpoint)
@@ -357,6 +375,7 @@ func normalizeHTTPAuditBackendConfig(cfg HTTPAuditBackendConfig) (HTTPAuditBacke
 		RetryMaxAttempts: retryMaxAttempts,
 		RetryBackoff:     retryBackoff,
 		RetryMaxBackoff:  retryMaxBackoff,
+		RetryJitter:      cfg.RetryJitter,
 		Sleep:            sleep,
 	}, nil
 }
@@ -429,30 +448,6 @@ func normalizeReplayAuditURL(raw string) (string, error) {
 	return trimmed, nil
 }
 
-func replayAuditRetryBackoffDuration(base time.Duration, max time.Duration, attempt int) time.Duration {
-	if base <= 0 {
-		base = time.Duration(defaultReplayAuditRetryBackoffMS) * time.Millisecond
-	}
-	if max < base {
-		max = base
-	}
-	if attempt < 1 {
-		return base
-	}
-
-	delay := base
-	for i := 1; i < attempt; i++ {
-		if delay >= max/2 {
-			return max
-		}
-		delay *= 2
-	}
-	if delay > max {
-		return max
-	}
-	return delay
-}
-
 func shouldRetryReplayAuditError(err error) bool {
 	var statusErr replayAuditHTTPStatusError
 	if errors.As(err, &statusErr) {
@@ -2,6 +2,9 @@ package replay
 
 import (
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 	"github.com/tiger/realtime-speech-pipeline/api/observability"
@@ -15,6 +18,13 @@ type TraceArtifact struct {
 	OrderingMarker        string
 	AuthorityEpoch        int64
 	RuntimeTimestampMS    int64
+	// Locale is the session locale tag recorded in baseline evidence, empty when unset.
+	Locale string
+	// SessionSummaryHash identifies the derived_summary bound into the turn's LLM context.
+	SessionSummaryHash string
+	// UnseededSampling lists provider_id=status for LLM invocations whose provider sampled
+	// without the deterministic sampling seed (status ignored or unsupported), sorted.
+	UnseededSampling []string
 }
 
 // LineageRecord captures replay explainability context for merged/dropped outputs.
@@ -27,6 +37,8 @@ type LineageRecord struct {
 // CompareConfig allows deterministic tolerance configuration.
 type CompareConfig struct {
 	TimingToleranceMS int64
+	// ScopeTimingTolerancesMS overrides TimingToleranceMS for matching divergence scopes.
+	ScopeTimingTolerancesMS ScopeTolerances
 }
 
 // CompareDecisionOutcomes performs deterministic replay/outcome comparison.
@@ -83,6 +95,7 @@ func CompareTraceArtifacts(baseline, replay []TraceArtifact, cfg CompareConfig)
 
 	for i := 0; i < limit; i++ {
 		scope := divergenceScope(baseline[i].Decision)
+		indexStart := len(divergences)
 
 		if baseline[i].PlanHash != replay[i].PlanHash {
 			divergences = append(divergences, observability.ReplayDivergence{
@@ -99,6 +112,22 @@ func CompareTraceArtifacts(baseline, replay []TraceArtifact, cfg CompareConfig)
 			})
 		}
 
+		if baseline[i].Locale != replay[i].Locale {
+			divergences = append(divergences, observability.ReplayDivergence{
+				Class:   observability.PlanDivergence,
+				Scope:   scope,
+				Message: fmt.Sprintf("locale mismatch at index=%d baseline=%s replay=%s (provider language and voice choices follow locale)", i, localeLabel(baseline[i].Locale), localeLabel(replay[i].Locale)),
+			})
+		}
+
+		if baseline[i].SessionSummaryHash != replay[i].SessionSummaryHash {
+			divergences = append(divergences, observability.ReplayDivergence{
+				Class:   observability.PlanDivergence,
+				Scope:   scope,
+				Message: fmt.Sprintf("session summary mismatch at index=%d baseline=%s replay=%s (llm context follows the bound summary)", i, summaryLabel(baseline[i].SessionSummaryHash), summaryLabel(replay[i].SessionSummaryHash)),
+			})
+		}
+
 		if !equivalentDecisionOutcome(baseline[i].Decision, replay[i].Decision) {
 			divergences = append(divergences, observability.ReplayDivergence{
 				Class:   observability.OutcomeDivergence,
@@ -123,10 +152,7 @@ func CompareTraceArtifacts(baseline, replay []TraceArtifact, cfg CompareConfig)
 			})
 		}
 
-		tolerance := cfg.TimingToleranceMS
-		if tolerance < 0 {
-			tolerance = 0
-		}
+		tolerance := cfg.TimingToleranceFor(scope)
 		diff := absDiff(baseline[i].RuntimeTimestampMS, replay[i].RuntimeTimestampMS)
 		if diff > tolerance {
 			diffCopy := diff
@@ -137,11 +163,31 @@ func CompareTraceArtifacts(baseline, replay []TraceArtifact, cfg CompareConfig)
 				DiffMS:  &diffCopy,
 			})
 		}
+
+		if note := unseededSamplingNote(baseline[i], replay[i]); note != "" {
+			for j := indexStart; j < len(divergences); j++ {
+				if divergences[j].Class == observability.OutcomeDivergence {
+					divergences[j].Message += note
+				}
+			}
+		}
 	}
 
 	return divergences
 }
 
+// unseededSamplingNote explains outcome divergences at an index where an LLM provider sampled
+// without the deterministic seed on either side: replay cannot reproduce that sampling.
+func unseededSamplingNote(baseline, replay TraceArtifact) string {
+	unseeded := append(append([]string(nil), baseline.UnseededSampling...), replay.UnseededSampling...)
+	if len(unseeded) == 0 {
+		return ""
+	}
+	sort.Strings(unseeded)
+	unseeded = slices.Compact(unseeded)
+	return fmt.Sprintf(" (llm sampling not seeded: %s; provider output is not replay-deterministic)", strings.Join(unseeded, ","))
+}
+
 // CompareLineageRecords verifies merged/dropped explainability against baseline lineage.
 func CompareLineageRecords(baseline, replay []LineageRecord) []observability.ReplayDivergence {
 	divergences := make([]observability.ReplayDivergence, 0)
@@ -179,6 +225,20 @@ func CompareLineageRecords(baseline, replay []LineageRecord) []observability.Rep
 	return divergences
 }
 
+func localeLabel(locale string) string {
+	if locale == "" {
+		return "unset"
+	}
+	return locale
+}
+
+func summaryLabel(hash string) string {
+	if hash == "" {
+		return "none"
+	}
+	return hash
+}
+
 func equivalentDecisionOutcome(a, b controlplane.DecisionOutcome) bool {
 	if a.OutcomeKind != b.OutcomeKind ||
 		a.Phase != b.Phase ||
@@ -0,0 +1,88 @@
+package replay
+
+import (
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+// Pre-turn denial reasons that identify which runtime-side input closed the turn open.
+const (
+	reasonSnapshotInvalid        = "snapshot_invalid_or_missing"
+	reasonCapacityReject         = "admission_capacity_reject"
+	reasonCapacityDefer          = "admission_capacity_defer"
+	reasonAuthorityEpochMismatch = "authority_epoch_mismatch"
+	reasonAuthorityRevoked       = "authority_revoked_before_open"
+)
+
+// DecisionInputsFromBaseline rebuilds recorded turn-open inputs from baseline timeline entries.
+// Entries that never proposed a turn open are skipped. The baseline decision is the turn's
+// first pre-turn denial, and nil for turns that opened. Runtime-side inputs are not recorded
+// directly, so they are inferred from that denial's reason: a turn that opened, or was denied
+// by the control plane, replays with a valid snapshot, allowed capacity, and granted authority.
+// The session locale is rebuilt from the recorded locale tag and TTS voice only.
+func DecisionInputsFromBaseline(entries []timeline.BaselineEvidence) []RecordedDecisionInput {
+	inputs := make([]RecordedDecisionInput, 0, len(entries))
+	for _, entry := range entries {
+		denial := preTurnDenial(entry)
+		if entry.TurnOpenProposedAtMS == nil && denial == nil {
+			continue
+		}
+		in := RecordedDecisionInput{
+			SessionID:                  entry.SessionID,
+			TurnID:                     entry.TurnID,
+			EventID:                    entry.EventID,
+			Lane:                       eventabi.LaneControl,
+			PipelineVersion:            entry.PipelineVersion,
+			AuthorityEpoch:             entry.AuthorityEpoch,
+			SnapshotValid:              true,
+			CapacityDisposition:        "allow",
+			AuthorityEpochValid:        true,
+			AuthorityAuthorized:        true,
+			BaselinePlanHash:           entry.PlanHash,
+			BaselineSnapshotProvenance: entry.SnapshotProvenance,
+		}
+		if entry.Locale != "" {
+			in.Locale = &controlplane.SessionLocale{Locale: entry.Locale, TTSVoice: entry.TTSVoice}
+		}
+		if entry.TurnOpenProposedAtMS != nil {
+			in.RuntimeTimestampMS = *entry.TurnOpenProposedAtMS
+			in.WallClockTimestampMS = *entry.TurnOpenProposedAtMS
+		}
+		if denial != nil {
+			decision := *denial
+			in.BaselineDecision = &decision
+			in.EventID = decision.EventID
+			in.RuntimeTimestampMS = decision.RuntimeTimestampMS
+			in.WallClockTimestampMS = decision.WallClockMS
+			switch decision.Reason {
+			case reasonSnapshotInvalid:
+				in.SnapshotValid = false
+			case reasonCapacityReject:
+				in.CapacityDisposition = "reject"
+			case reasonCapacityDefer:
+				in.CapacityDisposition = "defer"
+			case reasonAuthorityEpochMismatch:
+				in.AuthorityEpochValid = false
+			case reasonAuthorityRevoked:
+				in.AuthorityAuthorized = false
+			}
+		}
+		inputs = append(inputs, in)
+	}
+	return inputs
+}
+
+// preTurnDenial returns the turn's first pre-turn decision that kept it from opening.
+func preTurnDenial(entry timeline.BaselineEvidence) *controlplane.DecisionOutcome {
+	if entry.TurnOpenAtMS != nil {
+		return nil
+	}
+	for i := range entry.DecisionOutcomes {
+		decision := entry.DecisionOutcomes[i]
+		if decision.Phase == controlplane.PhasePreTurn && decision.OutcomeKind != controlplane.OutcomeAdmit {
+			return &entry.DecisionOutcomes[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package replay
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+func TestDecisionInputsFromBaseline(t *testing.T) {
+	t.Parallel()
+
+	openedAt := int64(100)
+	denial := func(kind controlplane.OutcomeKind, reason string) controlplane.DecisionOutcome {
+		return controlplane.DecisionOutcome{OutcomeKind: kind, Phase: controlplane.PhasePreTurn, EventID: "evt-" + reason, RuntimeTimestampMS: 40, WallClockMS: 41, Reason: reason}
+	}
+	cases := []struct {
+		name     string
+		entry    timeline.BaselineEvidence
+		skipped  bool
+		check    func(RecordedDecisionInput) bool
+		expected string
+	}{
+		{
+			name:  "opened turn replays with recorded plan and no decision",
+			entry: timeline.BaselineEvidence{TurnID: "turn-1", PlanHash: "hash-1", TurnOpenProposedAtMS: &openedAt, TurnOpenAtMS: &openedAt, Locale: "pt-BR", TTSVoice: "voice-a"},
+			check: func(in RecordedDecisionInput) bool {
+				return in.BaselineDecision == nil && in.BaselinePlanHash == "hash-1" && in.RuntimeTimestampMS == 100 && in.Locale != nil && in.Locale.TTSVoice == "voice-a"
+			},
+			expected: "recorded plan hash, open timestamp, and locale",
+		},
+		{
+			name:  "capacity defer infers defer disposition",
+			entry: timeline.BaselineEvidence{TurnID: "turn-2", DecisionOutcomes: []controlplane.DecisionOutcome{denial(controlplane.OutcomeDefer, reasonCapacityDefer)}},
+			check: func(in RecordedDecisionInput) bool {
+				return in.CapacityDisposition == "defer" && in.BaselineDecision != nil && in.EventID == "evt-"+reasonCapacityDefer && in.WallClockTimestampMS == 41
+			},
+			expected: "defer disposition with the denial's event and timestamps",
+		},
+		{
+			name:  "stale epoch infers invalid authority epoch",
+			entry: timeline.BaselineEvidence{TurnID: "turn-3", DecisionOutcomes: []controlplane.DecisionOutcome{denial(controlplane.OutcomeStaleEpochReject, reasonAuthorityEpochMismatch)}},
+			check: func(in RecordedDecisionInput) bool {
+				return !in.AuthorityEpochValid && in.AuthorityAuthorized && in.SnapshotValid
+			},
+			expected: "only the authority epoch marked invalid",
+		},
+		{
+			name:    "entry without a turn open is skipped",
+			entry:   timeline.BaselineEvidence{TurnID: "turn-4"},
+			skipped: true,
+		},
+	}
+	for _, tc := range cases {
+		inputs := DecisionInputsFromBaseline([]timeline.BaselineEvidence{tc.entry})
+		if tc.skipped {
+			if len(inputs) != 0 {
+				t.Fatalf("%s: expected entry skipped, got %+v", tc.name, inputs)
+			}
+			continue
+		}
+		if len(inputs) != 1 || !tc.check(inputs[0]) {
+			t.Fatalf("%s: expected %s, got %+v", tc.name, tc.expected, inputs)
+		}
+	}
+}
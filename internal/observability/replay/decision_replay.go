@@ -0,0 +1,138 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+)
+
+var (
+	ErrDecisionRecomputerNil      = errors.New("decision recomputer is required")
+	ErrDecisionReplayModeMismatch = errors.New("decision replay requires mode=recompute_decisions")
+)
+
+// RecordedDecisionInput captures fixed turn-open inputs and the recorded control-plane outputs.
+// Runtime-side inputs (snapshot validity, capacity, authority flags) are replayed as recorded.
+type RecordedDecisionInput struct {
+	SessionID                  string
+	TurnID                     string
+	EventID                    string
+	Lane                       eventabi.Lane
+	PipelineVersion            string
+	Locale                     *controlplane.SessionLocale
+	AuthorityEpoch             int64
+	RuntimeTimestampMS         int64
+	WallClockTimestampMS       int64
+	SnapshotValid              bool
+	CapacityDisposition        string
+	AuthorityEpochValid        bool
+	AuthorityAuthorized        bool
+	BaselinePlanHash           string
+	BaselineSnapshotProvenance controlplane.SnapshotProvenance
+	BaselineDecision           *controlplane.DecisionOutcome
+}
+
+// RecomputedDecision is the control-plane output produced by re-executing decision logic.
+type RecomputedDecision struct {
+	PlanHash           string
+	SnapshotProvenance controlplane.SnapshotProvenance
+	Decision           *controlplane.DecisionOutcome
+}
+
+// DecisionRecomputer re-executes admission, routing, and plan resolution for one recorded input.
+type DecisionRecomputer interface {
+	RecomputeDecision(in RecordedDecisionInput) (RecomputedDecision, error)
+}
+
+// RunDecisionReplay recomputes control-plane decisions against recorded inputs and reports
+// decision-only divergences. Runtime and provider events are not re-simulated.
+// Request filter and cursor bounds select a subset of inputs and divergence classes.
+func RunDecisionReplay(req obs.ReplayRunRequest, inputs []RecordedDecisionInput, recomputer DecisionRecomputer) (obs.ReplayRunResult, error) {
+	if err := req.Validate(); err != nil {
+		return obs.ReplayRunResult{}, err
+	}
+	if req.Mode != obs.ReplayModeRecomputeDecisions {
+		return obs.ReplayRunResult{}, ErrDecisionReplayModeMismatch
+	}
+	if recomputer == nil {
+		return obs.ReplayRunResult{}, ErrDecisionRecomputerNil
+	}
+
+	selected, next, err := selectDecisionInputIndexes(inputs, req.Filter, req.Cursor)
+	if err != nil {
+		return obs.ReplayRunResult{}, err
+	}
+	result := obs.ReplayRunResult{
+		BaselineRef:   req.BaselineRef,
+		CandidateRef:  req.CandidateRef,
+		Mode:          req.Mode,
+		AppliedFilter: cloneReplayFilter(req.Filter),
+		AppliedCursor: cloneReplayCursor(req.Cursor),
+		NextCursor:    next,
+		EvaluatedIDs:  make([]string, 0, len(selected)),
+		Divergences:   make([]obs.ReplayDivergence, 0),
+	}
+	// Divergences and errors report the index of the input among all recorded inputs, not its
+	// position in the filtered selection.
+	for _, index := range selected {
+		in := inputs[index]
+		recomputed, err := recomputer.RecomputeDecision(in)
+		if err != nil {
+			return obs.ReplayRunResult{}, fmt.Errorf("recompute decision at index=%d event=%s: %w", index, in.EventID, err)
+		}
+		result.EvaluatedIDs = append(result.EvaluatedIDs, in.EventID)
+		result.Divergences = append(result.Divergences, compareRecomputedDecision(index, in, recomputed)...)
+	}
+	result.Divergences = filterDivergences(result.Divergences, req.Filter, recordedTurnOrder(inputs))
+	return result, nil
+}
+
+func compareRecomputedDecision(index int, in RecordedDecisionInput, recomputed RecomputedDecision) []obs.ReplayDivergence {
+	scope := "session:" + in.SessionID
+	if in.TurnID != "" {
+		scope = "turn:" + in.TurnID
+	}
+
+	divergences := make([]obs.ReplayDivergence, 0)
+	if in.BaselinePlanHash != recomputed.PlanHash {
+		divergences = append(divergences, obs.ReplayDivergence{
+			Class:   obs.PlanDivergence,
+			Scope:   scope,
+			Message: fmt.Sprintf("recomputed plan hash mismatch at index=%d baseline=%s replay=%s", index, in.BaselinePlanHash, recomputed.PlanHash),
+		})
+	}
+	if in.BaselineSnapshotProvenance != recomputed.SnapshotProvenance {
+		divergences = append(divergences, obs.ReplayDivergence{
+			Class:   obs.PlanDivergence,
+			Scope:   scope,
+			Message: fmt.Sprintf("recomputed snapshot provenance mismatch at index=%d baseline=%+v replay=%+v", index, in.BaselineSnapshotProvenance, recomputed.SnapshotProvenance),
+		})
+	}
+
+	switch {
+	case in.BaselineDecision == nil && recomputed.Decision == nil:
+	case in.BaselineDecision == nil || recomputed.Decision == nil:
+		divergences = append(divergences, obs.ReplayDivergence{
+			Class:   obs.OutcomeDivergence,
+			Scope:   scope,
+			Message: fmt.Sprintf("recomputed decision presence mismatch at index=%d baseline=%s replay=%s", index, decisionLabel(in.BaselineDecision), decisionLabel(recomputed.Decision)),
+		})
+	case !equivalentDecisionOutcome(*in.BaselineDecision, *recomputed.Decision):
+		divergences = append(divergences, obs.ReplayDivergence{
+			Class:   obs.OutcomeDivergence,
+			Scope:   scope,
+			Message: fmt.Sprintf("recomputed decision mismatch at index=%d baseline=%s replay=%s", index, decisionLabel(in.BaselineDecision), decisionLabel(recomputed.Decision)),
+		})
+	}
+	return divergences
+}
+
+func decisionLabel(out *controlplane.DecisionOutcome) string {
+	if out == nil {
+		return "turn_open"
+	}
+	return fmt.Sprintf("%s/%s/%s", out.OutcomeKind, out.EmittedBy, out.Reason)
+}
@@ -0,0 +1,117 @@
+package replay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ErasureCertificate is the auditor-facing proof that a deletion request was applied. Its
+// CertificateID is a content hash, so any edit to the recorded fields invalidates it.
+type ErasureCertificate struct {
+	CertificateID                          string       `json:"certificate_id"`
+	TenantID                               string       `json:"tenant_id"`
+	SessionID                              string       `json:"session_id,omitempty"`
+	TurnID                                 string       `json:"turn_id,omitempty"`
+	Mode                                   DeletionMode `json:"mode"`
+	RequestedBy                            string       `json:"requested_by"`
+	Reason                                 string       `json:"reason,omitempty"`
+	RequestedAtMS                          int64        `json:"requested_at_ms"`
+	CompletedAtMS                          int64        `json:"completed_at_ms"`
+	MatchedArtifacts                       int          `json:"matched_artifacts"`
+	DeletedArtifacts                       int          `json:"deleted_artifacts"`
+	CryptographicallyInaccessibleArtifacts int          `json:"cryptographically_inaccessible_artifacts"`
+}
+
+// IssueErasureCertificate records the outcome of an applied deletion request.
+func IssueErasureCertificate(req DeletionRequest, result DeletionResult, completedAtMS int64) (ErasureCertificate, error) {
+	if err := req.Validate(); err != nil {
+		return ErasureCertificate{}, err
+	}
+	if result.Scope != req.Scope || result.Mode != req.Mode {
+		return ErasureCertificate{}, fmt.Errorf("deletion result does not match request scope and mode")
+	}
+	if completedAtMS < req.RequestedAtMS {
+		return ErasureCertificate{}, fmt.Errorf("completed_at_ms must be >= requested_at_ms")
+	}
+	cert := ErasureCertificate{
+		TenantID:                               req.Scope.TenantID,
+		SessionID:                              req.Scope.SessionID,
+		TurnID:                                 req.Scope.TurnID,
+		Mode:                                   req.Mode,
+		RequestedBy:                            req.RequestedBy,
+		Reason:                                 req.Reason,
+		RequestedAtMS:                          req.RequestedAtMS,
+		CompletedAtMS:                          completedAtMS,
+		MatchedArtifacts:                       result.MatchedArtifacts,
+		DeletedArtifacts:                       result.DeletedArtifacts,
+		CryptographicallyInaccessibleArtifacts: result.CryptographicallyInaccessibleArtifacts,
+	}
+	id, err := cert.contentID()
+	if err != nil {
+		return ErasureCertificate{}, err
+	}
+	cert.CertificateID = id
+	return cert, nil
+}
+
+// Validate enforces certificate invariants and checks CertificateID against the content.
+func (c ErasureCertificate) Validate() error {
+	scope := DeletionScope{TenantID: c.TenantID, SessionID: c.SessionID, TurnID: c.TurnID}
+	if err := scope.Validate(); err != nil {
+		return err
+	}
+	if err := c.Mode.Validate(); err != nil {
+		return err
+	}
+	if c.RequestedBy == "" {
+		return fmt.Errorf("requested_by is required")
+	}
+	if c.RequestedAtMS < 0 || c.CompletedAtMS < c.RequestedAtMS {
+		return fmt.Errorf("erasure certificate requires 0<=requested_at_ms<=completed_at_ms")
+	}
+	id, err := c.contentID()
+	if err != nil {
+		return err
+	}
+	if c.CertificateID != id {
+		return fmt.Errorf("erasure certificate %s does not match its content (expected %s)", c.CertificateID, id)
+	}
+	return nil
+}
+
+// Complete reports whether every matched artifact was deleted or made cryptographically
+// inaccessible.
+func (c ErasureCertificate) Complete() bool {
+	return c.DeletedArtifacts+c.CryptographicallyInaccessibleArtifacts >= c.MatchedArtifacts
+}
+
+func (c ErasureCertificate) contentID() (string, error) {
+	c.CertificateID = ""
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return "erasure-" + hex.EncodeToString(sum[:16]), nil
+}
+
+// ReadErasureCertificates reads and validates a JSON array of erasure certificates.
+func ReadErasureCertificates(path string) ([]ErasureCertificate, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read erasure certificates %s: %w", path, err)
+	}
+	var certs []ErasureCertificate
+	if err := json.Unmarshal(raw, &certs); err != nil {
+		return nil, fmt.Errorf("decode erasure certificates %s: %w", path, err)
+	}
+	for i, cert := range certs {
+		if err := cert.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid erasure certificate %d in %s: %w", i, path, err)
+		}
+	}
+	return certs, nil
+}
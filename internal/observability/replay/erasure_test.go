@@ -0,0 +1,110 @@
+package replay
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+func TestIssueErasureCertificate(t *testing.T) {
+	t.Parallel()
+
+	store := NewInMemoryArtifactStore()
+	mustAddArtifact(t, store, ReplayArtifactRecord{
+		ArtifactID:   "artifact-session-1",
+		TenantID:     "tenant-a",
+		SessionID:    "session-1",
+		TurnID:       "turn-1",
+		PayloadClass: eventabi.PayloadAudioRaw,
+		RecordedAtMS: 100,
+	})
+	req := DeletionRequest{
+		Scope:         DeletionScope{TenantID: "tenant-a", SessionID: "session-1"},
+		Mode:          DeletionModeCryptoInaccessible,
+		RequestedBy:   "dpo-1",
+		Reason:        "data_subject_request",
+		RequestedAtMS: 1_000,
+	}
+	result, err := store.Delete(req)
+	if err != nil {
+		t.Fatalf("unexpected delete error: %v", err)
+	}
+
+	cert, err := IssueErasureCertificate(req, result, 1_500)
+	if err != nil {
+		t.Fatalf("unexpected certificate error: %v", err)
+	}
+	if err := cert.Validate(); err != nil || !cert.Complete() || cert.CryptographicallyInaccessibleArtifacts != 1 {
+		t.Fatalf("expected valid complete certificate, got %+v (%v)", cert, err)
+	}
+	again, _ := IssueErasureCertificate(req, result, 1_500)
+	if again.CertificateID != cert.CertificateID {
+		t.Fatalf("expected deterministic certificate id, got %s vs %s", cert.CertificateID, again.CertificateID)
+	}
+
+	tampered := cert
+	tampered.MatchedArtifacts = 5
+	if err := tampered.Validate(); err == nil {
+		t.Fatalf("expected tampered certificate to fail validation")
+	}
+	if tampered.Complete() {
+		t.Fatalf("expected certificate with unerased matches to be incomplete")
+	}
+
+	tests := []struct {
+		name          string
+		req           DeletionRequest
+		result        DeletionResult
+		completedAtMS int64
+	}{
+		{name: "invalid request", req: DeletionRequest{}, result: result, completedAtMS: 1_500},
+		{name: "mismatched result", req: req, result: DeletionResult{Scope: DeletionScope{TenantID: "tenant-b", SessionID: "session-1"}, Mode: req.Mode}, completedAtMS: 1_500},
+		{name: "completed before requested", req: req, result: result, completedAtMS: 999},
+	}
+	for _, tc := range tests {
+		if _, err := IssueErasureCertificate(tc.req, tc.result, tc.completedAtMS); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
+
+func TestReadErasureCertificates(t *testing.T) {
+	t.Parallel()
+
+	cert, err := IssueErasureCertificate(DeletionRequest{
+		Scope:         DeletionScope{TenantID: "tenant-a", TurnID: "turn-1"},
+		Mode:          DeletionModeHardDelete,
+		RequestedBy:   "dpo-1",
+		RequestedAtMS: 10,
+	}, DeletionResult{Scope: DeletionScope{TenantID: "tenant-a", TurnID: "turn-1"}, Mode: DeletionModeHardDelete, MatchedArtifacts: 2, DeletedArtifacts: 2}, 20)
+	if err != nil {
+		t.Fatalf("unexpected certificate error: %v", err)
+	}
+	tmp := t.TempDir()
+	validPath := filepath.Join(tmp, "valid.json")
+	tamperedPath := filepath.Join(tmp, "tampered.json")
+	writeCerts := func(path string, certs []ErasureCertificate) {
+		raw, _ := json.Marshal(certs)
+		if err := os.WriteFile(path, raw, 0o644); err != nil {
+			t.Fatalf("write certificates: %v", err)
+		}
+	}
+	writeCerts(validPath, []ErasureCertificate{cert})
+	tampered := cert
+	tampered.RequestedBy = "someone-else"
+	writeCerts(tamperedPath, []ErasureCertificate{tampered})
+
+	certs, err := ReadErasureCertificates(validPath)
+	if err != nil || len(certs) != 1 || certs[0] != cert {
+		t.Fatalf("expected round-tripped certificate, got %+v (%v)", certs, err)
+	}
+	if _, err := ReadErasureCertificates(tamperedPath); err == nil {
+		t.Fatalf("expected tampered certificate file to be rejected")
+	}
+	if _, err := ReadErasureCertificates(filepath.Join(tmp, "missing.json")); err == nil {
+		t.Fatalf("expected missing file error")
+	}
+}
@@ -0,0 +1,180 @@
+package replay
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+)
+
+// ErrReplayCursorEventNotFound reports a cursor resuming after an event that was not recorded.
+var ErrReplayCursorEventNotFound = errors.New("replay cursor after_event_id not found in recorded inputs")
+
+// SelectDecisionInputs applies replay filter and cursor bounds to recorded decision inputs.
+// It returns the selected inputs and a continuation cursor when the limit truncated selection.
+// A cursor whose AfterEventID was not recorded fails with ErrReplayCursorEventNotFound rather
+// than selecting nothing.
+func SelectDecisionInputs(inputs []RecordedDecisionInput, filter *obs.ReplayRunFilter, cursor *obs.ReplayCursor) ([]RecordedDecisionInput, *obs.ReplayCursor, error) {
+	indexes, next, err := selectDecisionInputIndexes(inputs, filter, cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	selected := make([]RecordedDecisionInput, 0, len(indexes))
+	for _, index := range indexes {
+		selected = append(selected, inputs[index])
+	}
+	return selected, next, nil
+}
+
+// selectDecisionInputIndexes selects like SelectDecisionInputs and returns the indexes of the
+// selected inputs in the recorded inputs.
+func selectDecisionInputIndexes(inputs []RecordedDecisionInput, filter *obs.ReplayRunFilter, cursor *obs.ReplayCursor) ([]int, *obs.ReplayCursor, error) {
+	start := 0
+	if cursor != nil && cursor.AfterEventID != "" {
+		start = -1
+		for i, in := range inputs {
+			if in.EventID == cursor.AfterEventID {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			return nil, nil, fmt.Errorf("%w: %s", ErrReplayCursorEventNotFound, cursor.AfterEventID)
+		}
+	}
+
+	limit := 0
+	if cursor != nil {
+		limit = cursor.Limit
+	}
+
+	order := recordedTurnOrder(inputs)
+	selected := make([]int, 0, len(inputs)-start)
+	for i := start; i < len(inputs); i++ {
+		if !matchesDecisionInput(inputs[i], filter, order) {
+			continue
+		}
+		if limit > 0 && len(selected) == limit {
+			next := &obs.ReplayCursor{AfterEventID: inputs[selected[len(selected)-1]].EventID, Limit: limit}
+			return selected, next, nil
+		}
+		selected = append(selected, i)
+	}
+	return selected, nil, nil
+}
+
+// FilterDivergences keeps divergences matching the filter's classes and session/turn scope.
+// Without the recorded inputs, turn bounds are placed by obs.CompareTurnIDs.
+func FilterDivergences(divergences []obs.ReplayDivergence, filter *obs.ReplayRunFilter) []obs.ReplayDivergence {
+	return filterDivergences(divergences, filter, nil)
+}
+
+func filterDivergences(divergences []obs.ReplayDivergence, filter *obs.ReplayRunFilter, order turnOrder) []obs.ReplayDivergence {
+	if filter == nil {
+		return divergences
+	}
+	classes := make(map[obs.DivergenceClass]struct{}, len(filter.DivergenceClasses))
+	for _, class := range filter.DivergenceClasses {
+		classes[class] = struct{}{}
+	}
+
+	filtered := make([]obs.ReplayDivergence, 0, len(divergences))
+	for _, entry := range divergences {
+		if len(classes) > 0 {
+			if _, ok := classes[entry.Class]; !ok {
+				continue
+			}
+		}
+		if turnID, ok := strings.CutPrefix(entry.Scope, "turn:"); ok && !inTurnRange(turnID, filter, order) {
+			continue
+		}
+		if sessionID, ok := strings.CutPrefix(entry.Scope, "session:"); ok && filter.SessionID != "" && sessionID != filter.SessionID {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
+
+func matchesDecisionInput(in RecordedDecisionInput, filter *obs.ReplayRunFilter, order turnOrder) bool {
+	if filter == nil {
+		return true
+	}
+	if filter.SessionID != "" && in.SessionID != filter.SessionID {
+		return false
+	}
+	if !inTurnRange(in.TurnID, filter, order) {
+		return false
+	}
+	if filter.Lane != "" && decisionInputLane(in) != filter.Lane {
+		return false
+	}
+	return true
+}
+
+// turnOrder ranks each turn by its first recorded input, which is runtime sequence order.
+type turnOrder map[string]int
+
+func recordedTurnOrder(inputs []RecordedDecisionInput) turnOrder {
+	order := make(turnOrder, len(inputs))
+	for i, in := range inputs {
+		if _, seen := order[in.TurnID]; !seen && in.TurnID != "" {
+			order[in.TurnID] = i
+		}
+	}
+	return order
+}
+
+// compare orders two turns by where they were recorded, and by obs.CompareTurnIDs when either
+// was not recorded. ok is false when neither order places them.
+func (o turnOrder) compare(a, b string) (int, bool) {
+	rankA, recordedA := o[a]
+	rankB, recordedB := o[b]
+	if recordedA && recordedB {
+		return cmp.Compare(rankA, rankB), true
+	}
+	return obs.CompareTurnIDs(a, b)
+}
+
+// inTurnRange reports whether turnID falls inside the filter's inclusive turn range; a turn
+// that cannot be ordered against a bound is outside it.
+func inTurnRange(turnID string, filter *obs.ReplayRunFilter, order turnOrder) bool {
+	if filter.TurnIDFrom != "" {
+		if c, ok := order.compare(turnID, filter.TurnIDFrom); !ok || c < 0 {
+			return false
+		}
+	}
+	if filter.TurnIDTo != "" {
+		if c, ok := order.compare(turnID, filter.TurnIDTo); !ok || c > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func decisionInputLane(in RecordedDecisionInput) eventabi.Lane {
+	if in.Lane == "" {
+		return eventabi.LaneControl
+	}
+	return in.Lane
+}
+
+func cloneReplayFilter(filter *obs.ReplayRunFilter) *obs.ReplayRunFilter {
+	if filter == nil {
+		return nil
+	}
+	cloned := *filter
+	cloned.DivergenceClasses = append([]obs.DivergenceClass(nil), filter.DivergenceClasses...)
+	return &cloned
+}
+
+func cloneReplayCursor(cursor *obs.ReplayCursor) *obs.ReplayCursor {
+	if cursor == nil {
+		return nil
+	}
+	cloned := *cursor
+	return &cloned
+}
@@ -0,0 +1,171 @@
+package replay
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+)
+
+func TestSelectDecisionInputsAppliesScopeFilter(t *testing.T) {
+	t.Parallel()
+
+	inputs := []RecordedDecisionInput{
+		{SessionID: "sess-a", TurnID: "turn-001", EventID: "evt-1"},
+		{SessionID: "sess-a", TurnID: "turn-005", EventID: "evt-2"},
+		{SessionID: "sess-b", TurnID: "turn-003", EventID: "evt-3"},
+		{SessionID: "sess-a", TurnID: "turn-009", EventID: "evt-4", Lane: eventabi.LaneData},
+	}
+
+	selected, next, err := SelectDecisionInputs(inputs, &obs.ReplayRunFilter{
+		SessionID:  "sess-a",
+		TurnIDFrom: "turn-002",
+		TurnIDTo:   "turn-009",
+		Lane:       eventabi.LaneControl,
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected selection error: %v", err)
+	}
+	if next != nil {
+		t.Fatalf("expected no continuation cursor without limit, got %+v", next)
+	}
+	if len(selected) != 1 || selected[0].EventID != "evt-2" {
+		t.Fatalf("expected only evt-2 to match filter, got %+v", selected)
+	}
+}
+
+func TestSelectDecisionInputsCursorPagination(t *testing.T) {
+	t.Parallel()
+
+	inputs := []RecordedDecisionInput{
+		{SessionID: "sess-a", TurnID: "turn-1", EventID: "evt-1"},
+		{SessionID: "sess-a", TurnID: "turn-2", EventID: "evt-2"},
+		{SessionID: "sess-a", TurnID: "turn-3", EventID: "evt-3"},
+		{SessionID: "sess-a", TurnID: "turn-4", EventID: "evt-4"},
+	}
+
+	page, next, err := SelectDecisionInputs(inputs, nil, &obs.ReplayCursor{AfterEventID: "evt-1", Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected selection error: %v", err)
+	}
+	if len(page) != 2 || page[0].EventID != "evt-2" || page[1].EventID != "evt-3" {
+		t.Fatalf("expected second page evt-2..evt-3, got %+v", page)
+	}
+	if next == nil || next.AfterEventID != "evt-3" || next.Limit != 2 {
+		t.Fatalf("expected continuation cursor after evt-3, got %+v", next)
+	}
+
+	page, next, err = SelectDecisionInputs(inputs, nil, next)
+	if err != nil || len(page) != 1 || page[0].EventID != "evt-4" || next != nil {
+		t.Fatalf("expected final page evt-4 without continuation, got %+v next=%+v err=%v", page, next, err)
+	}
+}
+
+func TestSelectDecisionInputsRejectsUnknownCursorEvent(t *testing.T) {
+	t.Parallel()
+
+	inputs := []RecordedDecisionInput{{SessionID: "sess-a", TurnID: "turn-1", EventID: "evt-1"}}
+	if _, _, err := SelectDecisionInputs(inputs, nil, &obs.ReplayCursor{AfterEventID: "evt-missing"}); !errors.Is(err, ErrReplayCursorEventNotFound) {
+		t.Fatalf("expected unknown cursor event error, got %v", err)
+	}
+	_, err := RunDecisionReplay(obs.ReplayRunRequest{
+		BaselineRef: "baseline/cursor",
+		Mode:        obs.ReplayModeRecomputeDecisions,
+		Cursor:      &obs.ReplayCursor{AfterEventID: "evt-missing"},
+	}, inputs, fixedDecisionRecomputer{})
+	if !errors.Is(err, ErrReplayCursorEventNotFound) {
+		t.Fatalf("expected decision replay to reject unknown cursor event, got %v", err)
+	}
+}
+
+func TestRunDecisionReplayRecordsAppliedFilter(t *testing.T) {
+	t.Parallel()
+
+	inputs := []RecordedDecisionInput{
+		{SessionID: "sess-a", TurnID: "turn-1", EventID: "evt-1", BaselinePlanHash: "plan-a"},
+		{SessionID: "sess-b", TurnID: "turn-2", EventID: "evt-2", BaselinePlanHash: "plan-a"},
+	}
+	filter := &obs.ReplayRunFilter{
+		SessionID:         "sess-a",
+		DivergenceClasses: []obs.DivergenceClass{obs.OutcomeDivergence},
+	}
+	result, err := RunDecisionReplay(obs.ReplayRunRequest{
+		BaselineRef: "baseline/filtered",
+		Mode:        obs.ReplayModeRecomputeDecisions,
+		Filter:      filter,
+	}, inputs, fixedDecisionRecomputer{planHash: "plan-b"})
+	if err != nil {
+		t.Fatalf("unexpected filtered replay error: %v", err)
+	}
+	if len(result.EvaluatedIDs) != 1 || result.EvaluatedIDs[0] != "evt-1" {
+		t.Fatalf("expected only sess-a inputs evaluated, got %+v", result.EvaluatedIDs)
+	}
+	if len(result.Divergences) != 0 {
+		t.Fatalf("expected plan divergence to be excluded by class filter, got %+v", result.Divergences)
+	}
+	if result.AppliedFilter == nil || result.AppliedFilter.SessionID != "sess-a" {
+		t.Fatalf("expected applied filter recorded in result, got %+v", result.AppliedFilter)
+	}
+
+	filter.SessionID = "mutated"
+	if result.AppliedFilter.SessionID != "sess-a" {
+		t.Fatalf("expected applied filter to be copied, got %+v", result.AppliedFilter)
+	}
+}
+
+func TestFilterDivergencesByClassAndScope(t *testing.T) {
+	t.Parallel()
+
+	divergences := []obs.ReplayDivergence{
+		{Class: obs.PlanDivergence, Scope: "turn:turn-1"},
+		{Class: obs.TimingDivergence, Scope: "turn:turn-7"},
+		{Class: obs.TimingDivergence, Scope: "session:sess-b"},
+		{Class: obs.TimingDivergence, Scope: "trace"},
+	}
+	filtered := FilterDivergences(divergences, &obs.ReplayRunFilter{
+		SessionID:         "sess-a",
+		TurnIDTo:          "turn-5",
+		DivergenceClasses: []obs.DivergenceClass{obs.TimingDivergence},
+	})
+	if len(filtered) != 1 || filtered[0].Scope != "trace" {
+		t.Fatalf("expected only unscoped timing divergence to remain, got %+v", filtered)
+	}
+	if len(FilterDivergences(divergences, nil)) != len(divergences) {
+		t.Fatalf("expected nil filter to keep all divergences")
+	}
+}
+
+type fixedDecisionRecomputer struct {
+	planHash string
+}
+
+func (r fixedDecisionRecomputer) RecomputeDecision(_ RecordedDecisionInput) (RecomputedDecision, error) {
+	return RecomputedDecision{PlanHash: r.planHash}, nil
+}
+
+func TestRunDecisionReplayOrdersTurnRangeByRecordedOrder(t *testing.T) {
+	t.Parallel()
+
+	inputs := []RecordedDecisionInput{
+		{SessionID: "sess-a", TurnID: "turn-9", EventID: "evt-1", BaselinePlanHash: "plan-b"},
+		{SessionID: "sess-a", TurnID: "turn-2", EventID: "evt-2", BaselinePlanHash: "plan-a"},
+		{SessionID: "sess-a", TurnID: "turn-10", EventID: "evt-3", BaselinePlanHash: "plan-a"},
+		{SessionID: "sess-a", TurnID: "turn-11", EventID: "evt-4", BaselinePlanHash: "plan-b"},
+	}
+	result, err := RunDecisionReplay(obs.ReplayRunRequest{
+		BaselineRef: "baseline/ordered",
+		Mode:        obs.ReplayModeRecomputeDecisions,
+		Filter:      &obs.ReplayRunFilter{TurnIDFrom: "turn-2", TurnIDTo: "turn-10"},
+	}, inputs, fixedDecisionRecomputer{planHash: "plan-b"})
+	if err != nil {
+		t.Fatalf("unexpected replay error: %v", err)
+	}
+	if len(result.EvaluatedIDs) != 2 || result.EvaluatedIDs[0] != "evt-2" || result.EvaluatedIDs[1] != "evt-3" {
+		t.Fatalf("expected turn-2..turn-10 in recorded order, got %+v", result.EvaluatedIDs)
+	}
+	if len(result.Divergences) != 2 || !strings.Contains(result.Divergences[0].Message, "index=1 ") || !strings.Contains(result.Divergences[1].Message, "index=2 ") {
+		t.Fatalf("expected divergences at recorded input indexes 1 and 2, got %+v", result.Divergences)
+	}
+}
@@ -178,7 +178,8 @@ func (p RetentionPolicy) Validate() error {
 	return nil
 }
 
-func (p RetentionPolicy) retentionWindowFor(class eventabi.PayloadClass) int64 {
+// RetentionWindowFor returns the retention window for class, falling back to the default window.
+func (p RetentionPolicy) RetentionWindowFor(class eventabi.PayloadClass) int64 {
 	if windowMS, ok := p.MaxRetentionByClassMS[class]; ok {
 		return windowMS
 	}
@@ -369,7 +370,7 @@ func (s *InMemoryArtifactStore) EnforceRetention(policy RetentionPolicy, nowMS i
 			retained = append(retained, record)
 			continue
 		}
-		windowMS := policy.retentionWindowFor(record.PayloadClass)
+		windowMS := policy.RetentionWindowFor(record.PayloadClass)
 		if isExpired(record.RecordedAtMS, nowMS, windowMS) {
 			result.ExpiredArtifacts++
 			result.DeletedArtifacts++
@@ -0,0 +1,59 @@
+package replay
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ScopeTolerances maps divergence scope patterns to timing tolerances in milliseconds, so noisy
+// scopes can be loosened without a blanket fixture tolerance. A pattern is either an exact scope
+// ("turn:turn-1") or a prefix ending in "*" ("invocation_latency_final:*").
+type ScopeTolerances map[string]int64
+
+// Validate rejects empty patterns, wildcards other than a single trailing "*", and negative
+// tolerances.
+func (t ScopeTolerances) Validate() error {
+	for pattern, toleranceMS := range t {
+		if strings.TrimSpace(pattern) == "" {
+			return fmt.Errorf("scope timing tolerance pattern is required")
+		}
+		if strings.Contains(strings.TrimSuffix(pattern, "*"), "*") {
+			return fmt.Errorf("scope timing tolerance pattern %q may only end in a single *", pattern)
+		}
+		if toleranceMS < 0 {
+			return fmt.Errorf("scope timing tolerance for %q must be >=0", pattern)
+		}
+	}
+	return nil
+}
+
+// Resolve returns the tolerance of the most specific pattern matching scope: an exact pattern
+// wins over prefixes, and a longer prefix wins over a shorter one.
+func (t ScopeTolerances) Resolve(scope string) (int64, bool) {
+	if toleranceMS, ok := t[scope]; ok {
+		return max(toleranceMS, 0), true
+	}
+	best := -1
+	var resolved int64
+	for pattern, toleranceMS := range t {
+		prefix, wildcard := strings.CutSuffix(pattern, "*")
+		if !wildcard || !strings.HasPrefix(scope, prefix) || len(prefix) <= best {
+			continue
+		}
+		best = len(prefix)
+		resolved = toleranceMS
+	}
+	if best < 0 {
+		return 0, false
+	}
+	return max(resolved, 0), true
+}
+
+// TimingToleranceFor returns the timing tolerance applied to scope: the most specific scope
+// pattern when one matches, otherwise TimingToleranceMS.
+func (c CompareConfig) TimingToleranceFor(scope string) int64 {
+	if toleranceMS, ok := c.ScopeTimingTolerancesMS.Resolve(scope); ok {
+		return toleranceMS
+	}
+	return max(c.TimingToleranceMS, 0)
+}
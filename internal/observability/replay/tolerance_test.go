@@ -0,0 +1,69 @@
+package replay
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/observability"
+)
+
+func TestScopeTolerancesResolvePrefersMostSpecificPattern(t *testing.T) {
+	t.Parallel()
+
+	tolerances := ScopeTolerances{
+		"turn:*":                     20,
+		"turn:turn-noisy*":           80,
+		"turn:turn-noisy-1":          5,
+		"invocation_latency_final:*": 50,
+	}
+	if err := tolerances.Validate(); err != nil {
+		t.Fatalf("unexpected validate error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		scope   string
+		want    int64
+		matched bool
+	}{
+		{name: "exact pattern wins", scope: "turn:turn-noisy-1", want: 5, matched: true},
+		{name: "longer prefix wins", scope: "turn:turn-noisy-2", want: 80, matched: true},
+		{name: "short prefix", scope: "turn:turn-quiet", want: 20, matched: true},
+		{name: "latency prefix", scope: "invocation_latency_final:turn:turn-1", want: 50, matched: true},
+		{name: "no match", scope: "session:sess-1", matched: false},
+	}
+	for _, tc := range tests {
+		got, ok := tolerances.Resolve(tc.scope)
+		if ok != tc.matched || got != tc.want {
+			t.Fatalf("%s: expected (%d, %t), got (%d, %t)", tc.name, tc.want, tc.matched, got, ok)
+		}
+	}
+
+	for _, invalid := range []ScopeTolerances{{" ": 1}, {"turn:*:open": 1}, {"turn:**": 1}, {"turn:*": -1}} {
+		if err := invalid.Validate(); err == nil {
+			t.Fatalf("expected invalid scope tolerances %v to fail validation", invalid)
+		}
+	}
+}
+
+func TestCompareTraceArtifactsAppliesScopeTimingTolerance(t *testing.T) {
+	t.Parallel()
+
+	artifact := func(turnID string, timestampMS int64) TraceArtifact {
+		return TraceArtifact{
+			PlanHash:           "plan-1",
+			Decision:           controlplane.DecisionOutcome{SessionID: "sess-1", TurnID: turnID},
+			OrderingMarker:     "runtime_sequence:1",
+			RuntimeTimestampMS: timestampMS,
+		}
+	}
+	baseline := []TraceArtifact{artifact("turn-noisy", 100), artifact("turn-steady", 200)}
+	replayed := []TraceArtifact{artifact("turn-noisy", 140), artifact("turn-steady", 212)}
+
+	divergences := CompareTraceArtifacts(baseline, replayed, CompareConfig{
+		TimingToleranceMS:       15,
+		ScopeTimingTolerancesMS: ScopeTolerances{"turn:turn-noisy": 50, "turn:turn-steady*": 10},
+	})
+	if len(divergences) != 1 || divergences[0].Class != observability.TimingDivergence || divergences[0].Scope != "turn:turn-steady" {
+		t.Fatalf("expected only the tightened scope to diverge, got %+v", divergences)
+	}
+}
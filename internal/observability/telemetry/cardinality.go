@@ -0,0 +1,143 @@
+package telemetry
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// MetricCardinalityBreaches counts metric samples collapsed by the cardinality guardrail.
+	MetricCardinalityBreaches = "telemetry_cardinality_breaches_total"
+	// MetricCardinalitySeries reports the tracked series count of a metric when it first breaches.
+	MetricCardinalitySeries = "telemetry_cardinality_series"
+
+	// CardinalityOverflowAttribute marks a sample whose label set was collapsed after the
+	// per-metric series limit was reached.
+	CardinalityOverflowAttribute = "cardinality_overflow"
+
+	// DefaultMaxSeriesPerMetric bounds unique label combinations per metric name.
+	DefaultMaxSeriesPerMetric = 1000
+	// DefaultAttributeBuckets is the bucket count used for hashed high-cardinality labels.
+	DefaultAttributeBuckets = 64
+)
+
+// DefaultBucketedAttributeKeys are label keys whose values are per-session or per-event
+// identifiers and are replaced with a stable hash bucket before export.
+var DefaultBucketedAttributeKeys = []string{"session_id", "turn_id", "event_id", "trace_id", "span_id"}
+
+// CardinalityConfig configures metric label cardinality protection.
+type CardinalityConfig struct {
+	// MaxSeriesPerMetric bounds unique label combinations per metric name. Samples for new
+	// combinations beyond the limit keep only the overflow marker label.
+	MaxSeriesPerMetric int
+	// BucketedAttributeKeys lists label keys whose values are hashed into AttributeBuckets.
+	BucketedAttributeKeys []string
+	// AttributeBuckets is the number of hash buckets for bucketed label values.
+	AttributeBuckets int
+}
+
+func (c CardinalityConfig) withDefaults() CardinalityConfig {
+	if c.MaxSeriesPerMetric < 1 {
+		c.MaxSeriesPerMetric = DefaultMaxSeriesPerMetric
+	}
+	if c.BucketedAttributeKeys == nil {
+		c.BucketedAttributeKeys = DefaultBucketedAttributeKeys
+	}
+	if c.AttributeBuckets < 1 {
+		c.AttributeBuckets = DefaultAttributeBuckets
+	}
+	return c
+}
+
+// cardinalityGuard tracks series per metric and rewrites label sets that would exceed limits.
+type cardinalityGuard struct {
+	cfg      CardinalityConfig
+	bucketed map[string]struct{}
+
+	mu       sync.Mutex
+	series   map[string]map[string]struct{}
+	breached map[string]struct{}
+}
+
+func newCardinalityGuard(cfg CardinalityConfig) *cardinalityGuard {
+	cfg = cfg.withDefaults()
+	bucketed := make(map[string]struct{}, len(cfg.BucketedAttributeKeys))
+	for _, key := range cfg.BucketedAttributeKeys {
+		if key = strings.TrimSpace(key); key != "" {
+			bucketed[key] = struct{}{}
+		}
+	}
+	return &cardinalityGuard{
+		cfg:      cfg,
+		bucketed: bucketed,
+		series:   make(map[string]map[string]struct{}),
+		breached: make(map[string]struct{}),
+	}
+}
+
+// cardinalityVerdict reports how a metric sample was admitted by the guard.
+type cardinalityVerdict struct {
+	attributes  map[string]string
+	breached    bool
+	firstBreach bool
+	seriesCount int
+}
+
+// admit buckets high-cardinality labels and enforces the per-metric series limit. attributes
+// must already be a private copy; it is rewritten in place.
+func (g *cardinalityGuard) admit(name string, attributes map[string]string) cardinalityVerdict {
+	for key, value := range attributes {
+		if _, ok := g.bucketed[key]; ok {
+			attributes[key] = g.bucket(value)
+		}
+	}
+	seriesKey := attributeSeriesKey(attributes)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	known, ok := g.series[name]
+	if !ok {
+		known = make(map[string]struct{})
+		g.series[name] = known
+	}
+	if _, ok := known[seriesKey]; ok || len(known) < g.cfg.MaxSeriesPerMetric {
+		known[seriesKey] = struct{}{}
+		return cardinalityVerdict{attributes: attributes, seriesCount: len(known)}
+	}
+	_, already := g.breached[name]
+	g.breached[name] = struct{}{}
+	return cardinalityVerdict{
+		attributes:  map[string]string{CardinalityOverflowAttribute: "true"},
+		breached:    true,
+		firstBreach: !already,
+		seriesCount: len(known),
+	}
+}
+
+func (g *cardinalityGuard) bucket(value string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(value))
+	return "bucket_" + strconv.Itoa(int(h.Sum32()%uint32(g.cfg.AttributeBuckets)))
+}
+
+func attributeSeriesKey(attributes map[string]string) string {
+	if len(attributes) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(attributes[key])
+		b.WriteByte(0)
+	}
+	return b.String()
+}
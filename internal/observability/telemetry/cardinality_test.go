@@ -0,0 +1,98 @@
+package telemetry
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPipelineCardinalityGuardrailCollapsesSeriesBeyondLimit(t *testing.T) {
+	t.Parallel()
+
+	sink := NewMemorySink()
+	pipeline := NewPipeline(sink, Config{QueueCapacity: 64, Cardinality: CardinalityConfig{MaxSeriesPerMetric: 2}})
+	for i := 0; i < 5; i++ {
+		pipeline.EmitMetric(MetricProviderRTTMS, 1, "ms", map[string]string{"provider_id": fmt.Sprintf("p-%d", i)}, Correlation{RuntimeTimestampMS: 1})
+	}
+	pipeline.EmitMetric(MetricProviderRTTMS, 1, "ms", map[string]string{"provider_id": "p-0"}, Correlation{RuntimeTimestampMS: 2})
+	if err := pipeline.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	var admitted, collapsed, breaches, series int
+	for _, event := range sink.Events() {
+		switch event.Metric.Name {
+		case MetricProviderRTTMS:
+			if event.Metric.Attributes[CardinalityOverflowAttribute] == "true" {
+				collapsed++
+				if len(event.Metric.Attributes) != 1 {
+					t.Fatalf("expected collapsed sample to keep only the overflow label, got %+v", event.Metric.Attributes)
+				}
+				continue
+			}
+			admitted++
+		case MetricCardinalityBreaches:
+			breaches++
+			if event.Metric.Attributes["metric"] != MetricProviderRTTMS {
+				t.Fatalf("expected breach meta-metric labelled with metric name, got %+v", event.Metric.Attributes)
+			}
+		case MetricCardinalitySeries:
+			series++
+			if event.Metric.Value != 2 {
+				t.Fatalf("expected series meta-metric at the limit, got %v", event.Metric.Value)
+			}
+		}
+	}
+	if admitted != 3 || collapsed != 3 || breaches != 3 || series != 1 {
+		t.Fatalf("expected 3 admitted, 3 collapsed, 3 breaches, 1 series report, got %d/%d/%d/%d", admitted, collapsed, breaches, series)
+	}
+	if got := pipeline.Stats().CardinalityBreaches; got != 3 {
+		t.Fatalf("expected 3 cardinality breaches in stats, got %d", got)
+	}
+}
+
+func TestPipelineCardinalityGuardrailBucketsSessionLabels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     CardinalityConfig
+		label   string
+		buckets int
+	}{
+		{name: "default session bucketing", cfg: CardinalityConfig{AttributeBuckets: 4}, label: "session_id", buckets: 4},
+		{name: "custom bucketed key", cfg: CardinalityConfig{BucketedAttributeKeys: []string{"caller"}, AttributeBuckets: 2}, label: "caller", buckets: 2},
+	}
+	for _, tc := range tests {
+		sink := NewMemorySink()
+		pipeline := NewPipeline(sink, Config{QueueCapacity: 256, Cardinality: tc.cfg})
+		for i := 0; i < 100; i++ {
+			pipeline.EmitMetric(MetricShedRate, 0, "ratio", map[string]string{tc.label: fmt.Sprintf("sess-%d", i)}, Correlation{RuntimeTimestampMS: 1})
+		}
+		if err := pipeline.Close(); err != nil {
+			t.Fatalf("%s: close: %v", tc.name, err)
+		}
+		values := map[string]struct{}{}
+		for _, event := range sink.Events() {
+			if event.Metric.Name != MetricShedRate {
+				t.Fatalf("%s: expected no guardrail breach, got %+v", tc.name, event.Metric)
+			}
+			values[event.Metric.Attributes[tc.label]] = struct{}{}
+		}
+		if len(values) > tc.buckets {
+			t.Fatalf("%s: expected at most %d label values, got %d", tc.name, tc.buckets, len(values))
+		}
+		if pipeline.Stats().CardinalityBreaches != 0 {
+			t.Fatalf("%s: expected no breaches, got %+v", tc.name, pipeline.Stats())
+		}
+	}
+}
+
+func TestCardinalityBucketIsStable(t *testing.T) {
+	t.Parallel()
+
+	first := newCardinalityGuard(CardinalityConfig{})
+	second := newCardinalityGuard(CardinalityConfig{})
+	if first.bucket("sess-42") != second.bucket("sess-42") {
+		t.Fatalf("expected deterministic bucket across guards")
+	}
+}
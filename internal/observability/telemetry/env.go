@@ -20,25 +20,37 @@ const (
 	EnvTelemetryDropSampleRate = "RSPP_TELEMETRY_DROP_SAMPLE_RATE"
 	// EnvTelemetryExportTimeoutMS sets export timeout in milliseconds.
 	EnvTelemetryExportTimeoutMS = "RSPP_TELEMETRY_EXPORT_TIMEOUT_MS"
+	// EnvTelemetryOverflowDir enables the disk-backed overflow spool in the given directory.
+	EnvTelemetryOverflowDir = "RSPP_TELEMETRY_OVERFLOW_DIR"
+	// EnvTelemetryOverflowMaxBytes bounds the overflow spool size in bytes.
+	EnvTelemetryOverflowMaxBytes = "RSPP_TELEMETRY_OVERFLOW_MAX_BYTES"
+	// EnvTelemetryMaxSeriesPerMetric bounds unique label combinations per metric name.
+	EnvTelemetryMaxSeriesPerMetric = "RSPP_TELEMETRY_MAX_SERIES_PER_METRIC"
 )
 
 // RuntimeConfig captures env-configured telemetry settings.
 type RuntimeConfig struct {
-	Enabled          bool
-	OTLPHTTPEndpoint string
-	QueueCapacity    int
-	LogSampleRate    int
-	ExportTimeoutMS  int
+	Enabled            bool
+	OTLPHTTPEndpoint   string
+	QueueCapacity      int
+	LogSampleRate      int
+	ExportTimeoutMS    int
+	OverflowDir        string
+	OverflowMaxBytes   int64
+	MaxSeriesPerMetric int
 }
 
 // RuntimeConfigFromEnv parses telemetry config from environment.
 func RuntimeConfigFromEnv() (RuntimeConfig, error) {
 	cfg := RuntimeConfig{
-		Enabled:          true,
-		OTLPHTTPEndpoint: strings.TrimSpace(os.Getenv(EnvTelemetryOTLPHTTPEndpoint)),
-		QueueCapacity:    256,
-		LogSampleRate:    1,
-		ExportTimeoutMS:  200,
+		Enabled:            true,
+		OTLPHTTPEndpoint:   strings.TrimSpace(os.Getenv(EnvTelemetryOTLPHTTPEndpoint)),
+		QueueCapacity:      256,
+		LogSampleRate:      1,
+		ExportTimeoutMS:    200,
+		OverflowDir:        strings.TrimSpace(os.Getenv(EnvTelemetryOverflowDir)),
+		OverflowMaxBytes:   DefaultOverflowMaxBytes,
+		MaxSeriesPerMetric: DefaultMaxSeriesPerMetric,
 	}
 
 	if raw := strings.TrimSpace(os.Getenv(EnvTelemetryEnabled)); raw != "" {
@@ -69,6 +81,20 @@ func RuntimeConfigFromEnv() (RuntimeConfig, error) {
 		}
 		cfg.ExportTimeoutMS = v
 	}
+	if raw := strings.TrimSpace(os.Getenv(EnvTelemetryOverflowMaxBytes)); raw != "" {
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || v < 1 {
+			return RuntimeConfig{}, fmt.Errorf("%s must be integer >=1", EnvTelemetryOverflowMaxBytes)
+		}
+		cfg.OverflowMaxBytes = v
+	}
+	if raw := strings.TrimSpace(os.Getenv(EnvTelemetryMaxSeriesPerMetric)); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 {
+			return RuntimeConfig{}, fmt.Errorf("%s must be integer >=1", EnvTelemetryMaxSeriesPerMetric)
+		}
+		cfg.MaxSeriesPerMetric = v
+	}
 
 	return cfg, nil
 }
@@ -95,9 +121,19 @@ func NewPipelineFromEnv() (*Pipeline, error) {
 		sink = httpSink
 	}
 
+	var overflow *OverflowQueue
+	if cfg.OverflowDir != "" {
+		overflow, err = NewOverflowQueue(OverflowConfig{Dir: cfg.OverflowDir, MaxBytes: cfg.OverflowMaxBytes})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return NewPipeline(sink, Config{
 		QueueCapacity: cfg.QueueCapacity,
 		LogSampleRate: cfg.LogSampleRate,
 		ExportTimeout: time.Duration(cfg.ExportTimeoutMS) * time.Millisecond,
+		Overflow:      overflow,
+		Cardinality:   CardinalityConfig{MaxSeriesPerMetric: cfg.MaxSeriesPerMetric},
 	}), nil
 }
@@ -40,6 +40,20 @@ func TestRuntimeConfigFromEnvRejectsInvalidValues(t *testing.T) {
 			t.Fatalf("expected timeout validation error")
 		}
 	})
+
+	t.Run("invalid_overflow_max_bytes", func(t *testing.T) {
+		t.Setenv(EnvTelemetryOverflowMaxBytes, "0")
+		if _, err := RuntimeConfigFromEnv(); err == nil {
+			t.Fatalf("expected overflow max bytes validation error")
+		}
+	})
+
+	t.Run("invalid_max_series_per_metric", func(t *testing.T) {
+		t.Setenv(EnvTelemetryMaxSeriesPerMetric, "none")
+		if _, err := RuntimeConfigFromEnv(); err == nil {
+			t.Fatalf("expected max series per metric validation error")
+		}
+	})
 }
 
 func TestNewPipelineFromEnv(t *testing.T) {
@@ -0,0 +1,204 @@
+package telemetry
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// OverflowFileName is the spool file written inside OverflowConfig.Dir.
+	OverflowFileName = "telemetry-overflow.jsonl"
+	// DefaultOverflowMaxBytes bounds the overflow spool when no limit is configured.
+	DefaultOverflowMaxBytes int64 = 64 << 20
+	// DefaultOverflowRetryInterval paces spool drains after exporter failures.
+	DefaultOverflowRetryInterval = time.Second
+	// DefaultOverflowBufferEvents bounds events waiting to be written to the spool.
+	DefaultOverflowBufferEvents = 1024
+)
+
+// errOverflowFull reports that an event does not fit in the bounded spool.
+var errOverflowFull = fmt.Errorf("telemetry overflow spool is full")
+
+// OverflowConfig configures the disk-backed overflow spool.
+type OverflowConfig struct {
+	Dir string
+	// MaxBytes bounds the undrained spool and the file itself; events that do not fit are
+	// dropped and counted.
+	MaxBytes int64
+	// RetryInterval is how often the pipeline drains spooled events to the sink.
+	RetryInterval time.Duration
+	// BufferEvents bounds events handed off by emitters and waiting for the pipeline's spool
+	// writer, so emit never touches disk. Zero means DefaultOverflowBufferEvents.
+	BufferEvents int
+}
+
+// OverflowQueue is a bounded disk spool that holds events the in-memory queue could not
+// accept and events whose export failed. Spooled events left at shutdown are re-exported
+// by the next pipeline opened on the same directory.
+type OverflowQueue struct {
+	mu            sync.Mutex
+	path          string
+	maxBytes      int64
+	retryInterval time.Duration
+	bufferEvents  int
+	file          *os.File
+	size          int64
+	readOffset    int64
+}
+
+// NewOverflowQueue opens (or resumes) the overflow spool in cfg.Dir.
+func NewOverflowQueue(cfg OverflowConfig) (*OverflowQueue, error) {
+	dir := strings.TrimSpace(cfg.Dir)
+	if dir == "" {
+		return nil, fmt.Errorf("telemetry overflow dir is required")
+	}
+	if cfg.MaxBytes < 0 {
+		return nil, fmt.Errorf("telemetry overflow max bytes must be >=0")
+	}
+	if cfg.MaxBytes == 0 {
+		cfg.MaxBytes = DefaultOverflowMaxBytes
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = DefaultOverflowRetryInterval
+	}
+	if cfg.BufferEvents < 0 {
+		return nil, fmt.Errorf("telemetry overflow buffer events must be >=0")
+	}
+	if cfg.BufferEvents == 0 {
+		cfg.BufferEvents = DefaultOverflowBufferEvents
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create telemetry overflow dir: %w", err)
+	}
+	path := filepath.Join(dir, OverflowFileName)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open telemetry overflow spool: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("stat telemetry overflow spool: %w", err)
+	}
+	return &OverflowQueue{
+		path:          path,
+		maxBytes:      cfg.MaxBytes,
+		retryInterval: cfg.RetryInterval,
+		bufferEvents:  cfg.BufferEvents,
+		file:          file,
+		size:          info.Size(),
+	}, nil
+}
+
+// Path returns the spool file path.
+func (q *OverflowQueue) Path() string {
+	return q.path
+}
+
+// PendingBytes returns the spooled bytes not yet drained.
+func (q *OverflowQueue) PendingBytes() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size - q.readOffset
+}
+
+func (q *OverflowQueue) append(event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.size-q.readOffset+int64(len(line)) > q.maxBytes {
+		return errOverflowFull
+	}
+	// The event fits the undrained spool; reclaim the drained prefix when it is what keeps the
+	// file from taking it.
+	if q.size+int64(len(line)) > q.maxBytes {
+		if err := q.compactLocked(); err != nil {
+			return err
+		}
+	}
+	if _, err := q.file.WriteAt(line, q.size); err != nil {
+		return err
+	}
+	q.size += int64(len(line))
+	return nil
+}
+
+// peek returns the oldest spooled event and the offset that commits it. ok is false when
+// the spool is empty. A record that cannot be decoded is returned with decodeErr set so the
+// caller can skip it.
+func (q *OverflowQueue) peek() (event Event, next int64, ok bool, decodeErr error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.readOffset >= q.size {
+		return Event{}, 0, false, nil
+	}
+	reader := bufio.NewReader(io.NewSectionReader(q.file, q.readOffset, q.size-q.readOffset))
+	line, err := reader.ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return Event{}, 0, false, err
+	}
+	next = q.readOffset + int64(len(line))
+	if err := json.Unmarshal(line, &event); err != nil {
+		return Event{}, next, true, err
+	}
+	return event, next, true, nil
+}
+
+// commit marks spooled records before next as drained and reclaims the file once empty.
+func (q *OverflowQueue) commit(next int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.readOffset = next
+	if q.readOffset < q.size {
+		return nil
+	}
+	if err := q.file.Truncate(0); err != nil {
+		return err
+	}
+	q.size = 0
+	q.readOffset = 0
+	return nil
+}
+
+// compactLocked moves the undrained records to the start of the file and truncates the
+// drained prefix.
+func (q *OverflowQueue) compactLocked() error {
+	if q.readOffset == 0 {
+		return nil
+	}
+	pending := make([]byte, q.size-q.readOffset)
+	if _, err := q.file.ReadAt(pending, q.readOffset); err != nil {
+		return err
+	}
+	if _, err := q.file.WriteAt(pending, 0); err != nil {
+		return err
+	}
+	if err := q.file.Truncate(int64(len(pending))); err != nil {
+		return err
+	}
+	q.size = int64(len(pending))
+	q.readOffset = 0
+	return nil
+}
+
+func (q *OverflowQueue) close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	// Compact so the next pipeline resumes at the first undrained record.
+	if err := q.compactLocked(); err != nil {
+		return err
+	}
+	return q.file.Close()
+}
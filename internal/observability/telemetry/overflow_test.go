@@ -0,0 +1,227 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// outageSink fails every export while down is set and otherwise records into a MemorySink.
+type outageSink struct {
+	down   atomic.Bool
+	memory *MemorySink
+}
+
+func (s *outageSink) Export(ctx context.Context, event Event) error {
+	if s.down.Load() {
+		return fmt.Errorf("exporter unavailable")
+	}
+	return s.memory.Export(ctx, event)
+}
+
+func emitTestMetrics(p *Pipeline, count int) {
+	for i := 0; i < count; i++ {
+		p.EmitMetric(MetricShedRate, 1, "ratio", nil, Correlation{SessionID: "sess-overflow", RuntimeTimestampMS: int64(i + 1)})
+	}
+}
+
+func TestPipelineOverflowSpillsQueuePressureInsteadOfDropping(t *testing.T) {
+	t.Parallel()
+
+	overflow, err := NewOverflowQueue(OverflowConfig{Dir: t.TempDir(), RetryInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("new overflow queue: %v", err)
+	}
+	block := make(chan struct{})
+	sink := NewMemorySink()
+	gate := blockingSink{block: block}
+	pipeline := NewPipeline(sinkFunc(func(ctx context.Context, event Event) error {
+		if err := gate.Export(ctx, event); err != nil {
+			return err
+		}
+		return sink.Export(ctx, event)
+	}), Config{QueueCapacity: 1, ExportTimeout: time.Second, Overflow: overflow})
+
+	emitTestMetrics(pipeline, 50)
+	if stats := pipeline.Stats(); stats.Dropped != 0 || stats.Enqueued != 50 {
+		t.Fatalf("expected queue pressure to hand events to the spool without drops, got %+v", stats)
+	}
+	waitFor(t, func() bool {
+		stats := pipeline.Stats()
+		return stats.Spilled > 0 && stats.OverflowPendingBytes > 0
+	})
+
+	close(block)
+	if err := pipeline.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	stats := pipeline.Stats()
+	if len(sink.Events()) != 50 || stats.Replayed != stats.Spilled {
+		t.Fatalf("expected every spilled event exported on close, got %d events and %+v", len(sink.Events()), stats)
+	}
+}
+
+func TestPipelineOverflowReplaysEventsAfterExporterOutage(t *testing.T) {
+	t.Parallel()
+
+	overflow, err := NewOverflowQueue(OverflowConfig{Dir: t.TempDir(), RetryInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("new overflow queue: %v", err)
+	}
+	sink := &outageSink{memory: NewMemorySink()}
+	sink.down.Store(true)
+	pipeline := NewPipeline(sink, Config{QueueCapacity: 8, Overflow: overflow})
+	defer func() { _ = pipeline.Close() }()
+
+	emitTestMetrics(pipeline, 5)
+	waitFor(t, func() bool { return pipeline.Stats().Spilled == 5 })
+	sink.down.Store(false)
+	waitFor(t, func() bool { return len(sink.memory.Events()) == 5 })
+
+	stats := pipeline.Stats()
+	if stats.Replayed != 5 || stats.OverflowPendingBytes != 0 || stats.SpillDropped != 0 {
+		t.Fatalf("expected spooled events replayed after outage, got %+v", stats)
+	}
+}
+
+func TestPipelineOverflowIsBoundedAndCountsDrops(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	overflow, err := NewOverflowQueue(OverflowConfig{Dir: dir, MaxBytes: 600, RetryInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("new overflow queue: %v", err)
+	}
+	sink := &outageSink{memory: NewMemorySink()}
+	sink.down.Store(true)
+	pipeline := NewPipeline(sink, Config{QueueCapacity: 1, Overflow: overflow})
+
+	emitTestMetrics(pipeline, 40)
+	if err := pipeline.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	stats := pipeline.Stats()
+	if stats.SpillDropped == 0 || stats.Spilled == 0 {
+		t.Fatalf("expected bounded spool to spill some and drop the rest, got %+v", stats)
+	}
+	info, err := os.Stat(filepath.Join(dir, OverflowFileName))
+	if err != nil {
+		t.Fatalf("stat spool: %v", err)
+	}
+	if info.Size() > 600 {
+		t.Fatalf("expected spool bounded to 600 bytes, got %d", info.Size())
+	}
+}
+
+func TestPipelineOverflowResumesSpoolAcrossRestart(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	first, err := NewOverflowQueue(OverflowConfig{Dir: dir, RetryInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("new overflow queue: %v", err)
+	}
+	down := &outageSink{memory: NewMemorySink()}
+	down.down.Store(true)
+	pipeline := NewPipeline(down, Config{QueueCapacity: 8, Overflow: first})
+	emitTestMetrics(pipeline, 3)
+	if err := pipeline.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if spilled := pipeline.Stats().Spilled; spilled != 3 {
+		t.Fatalf("expected 3 spilled events, got %d", spilled)
+	}
+
+	second, err := NewOverflowQueue(OverflowConfig{Dir: dir, RetryInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("reopen overflow queue: %v", err)
+	}
+	if second.PendingBytes() == 0 {
+		t.Fatalf("expected spooled events to survive restart")
+	}
+	sink := NewMemorySink()
+	restarted := NewPipeline(sink, Config{Overflow: second})
+	if err := restarted.Close(); err != nil {
+		t.Fatalf("close restarted: %v", err)
+	}
+	if len(sink.Events()) != 3 || restarted.Stats().Replayed != 3 {
+		t.Fatalf("expected spooled events replayed after restart, got %d events and %+v", len(sink.Events()), restarted.Stats())
+	}
+}
+
+func TestOverflowQueueReclaimsDrainedPrefix(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	queue, err := NewOverflowQueue(OverflowConfig{Dir: dir, MaxBytes: 600})
+	if err != nil {
+		t.Fatalf("new overflow queue: %v", err)
+	}
+	defer func() { _ = queue.close() }()
+
+	// Keep two events pending while pushing far more than MaxBytes through the spool.
+	event := Event{Kind: EventKindMetric, Metric: &MetricEvent{Name: MetricShedRate, Value: 1}}
+	for i := 0; i < 2; i++ {
+		if err := queue.append(event); err != nil {
+			t.Fatalf("seed append %d: %v", i, err)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if err := queue.append(event); err != nil {
+			t.Fatalf("append %d with little pending: %v", i, err)
+		}
+		_, next, ok, err := queue.peek()
+		if !ok || err != nil {
+			t.Fatalf("peek %d: ok=%t err=%v", i, ok, err)
+		}
+		if err := queue.commit(next); err != nil {
+			t.Fatalf("commit %d: %v", i, err)
+		}
+	}
+	info, err := os.Stat(filepath.Join(dir, OverflowFileName))
+	if err != nil {
+		t.Fatalf("stat spool: %v", err)
+	}
+	if info.Size() > 600 || queue.PendingBytes() == 0 || queue.PendingBytes() > info.Size() {
+		t.Fatalf("expected the drained prefix reclaimed within 600 bytes, got file=%d pending=%d", info.Size(), queue.PendingBytes())
+	}
+}
+
+func TestNewOverflowQueueRejectsInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  OverflowConfig
+	}{
+		{name: "missing dir", cfg: OverflowConfig{}},
+		{name: "negative max bytes", cfg: OverflowConfig{Dir: t.TempDir(), MaxBytes: -1}},
+		{name: "negative buffer events", cfg: OverflowConfig{Dir: t.TempDir(), BufferEvents: -1}},
+	}
+	for _, tc := range tests {
+		if _, err := NewOverflowQueue(tc.cfg); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
+
+type sinkFunc func(context.Context, Event) error
+
+func (f sinkFunc) Export(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met before deadline")
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+}
@@ -19,8 +19,36 @@ const (
 	MetricProviderRTTMS = "provider_rtt_ms"
 	// MetricShedRate captures scheduling-point shed outcomes.
 	MetricShedRate = "shed_rate"
+	// MetricProviderDNSLatencyMS captures provider HTTP DNS resolution latency observations.
+	MetricProviderDNSLatencyMS = "provider_dns_latency_ms"
+	// MetricProviderConnReused captures provider HTTP connection reuse (1) or new dial (0).
+	MetricProviderConnReused = "provider_conn_reused"
+	// MetricProviderCacheHit captures provider response cache hit (1) or miss (0) per cacheable attempt.
+	MetricProviderCacheHit = "provider_cache_hit"
+	// MetricSyntheticCanarySuccess captures synthetic canary session success (1) or failure (0).
+	MetricSyntheticCanarySuccess = "synthetic_canary_success"
+	// MetricSyntheticCanaryLatencyMS captures synthetic canary session latency observations.
+	MetricSyntheticCanaryLatencyMS = "synthetic_canary_latency_ms"
+	// MetricCPSnapshotAgeMS captures the age of each tracked control-plane snapshot.
+	MetricCPSnapshotAgeMS = "cp_snapshot_age_ms"
+	// MetricCPSnapshotStale captures whether a tracked control-plane snapshot exceeds its freshness threshold (1) or not (0).
+	MetricCPSnapshotStale = "cp_snapshot_stale"
+	// MetricPlanCacheHit captures resolved-plan cache hit (1) or miss (0) per turn; its mean is the hit rate.
+	MetricPlanCacheHit = "plan_cache_hit"
+	// MetricPlanCacheInvalidations captures plan cache entries dropped by a control-plane publish or rollback.
+	MetricPlanCacheInvalidations = "plan_cache_invalidations"
+	// MetricTransportBytes captures bytes crossing the transport boundary per direction, lane, and payload class.
+	MetricTransportBytes = "transport_bytes"
+	// MetricTransportOversizedMetadata captures metadata payloads above the transport alert size.
+	MetricTransportOversizedMetadata = "transport_oversized_metadata"
+	// MetricPlaybackStartDelayMS captures the delay between reply audio egress and the client
+	// acknowledging that playback began.
+	MetricPlaybackStartDelayMS = "playback_start_delay_ms"
 )
 
+// AttributePipelineVersion is the metric/span/log attribute carrying the emitting pipeline version.
+const AttributePipelineVersion = "pipeline_version"
+
 // EventKind defines telemetry payload kind.
 type EventKind string
 
@@ -41,6 +69,9 @@ type Correlation struct {
 	EmittedBy            string `json:"emitted_by,omitempty"`
 	RuntimeTimestampMS   int64  `json:"runtime_timestamp_ms,omitempty"`
 	WallClockTimestampMS int64  `json:"wall_clock_timestamp_ms,omitempty"`
+	TraceID              string `json:"trace_id,omitempty"`
+	SpanID               string `json:"span_id,omitempty"`
+	ParentSpanID         string `json:"parent_span_id,omitempty"`
 }
 
 // MetricEvent captures a metric sample payload.
@@ -134,6 +165,13 @@ type Config struct {
 	// LogSampleRate drops deterministic debug log events when >1.
 	// With N, only every Nth debug log event is accepted.
 	LogSampleRate int
+	// Overflow optionally spools events the in-memory queue cannot accept and events whose
+	// export failed, so short exporter outages do not lose evidence. The pipeline owns and
+	// closes it.
+	Overflow *OverflowQueue
+	// Cardinality bounds unique metric label combinations so per-session labels cannot
+	// explode downstream metric systems.
+	Cardinality CardinalityConfig
 }
 
 func (c Config) withDefaults() Config {
@@ -157,6 +195,16 @@ type Stats struct {
 	Exported       uint64
 	ExportFailures uint64
 	QueueDepth     int
+	// Spilled counts events written to the overflow spool.
+	Spilled uint64
+	// SpillDropped counts events lost because the overflow spool was full or unwritable.
+	SpillDropped uint64
+	// Replayed counts spooled events exported after the fact.
+	Replayed uint64
+	// OverflowPendingBytes is the undrained overflow spool size.
+	OverflowPendingBytes int64
+	// CardinalityBreaches counts metric samples collapsed by the cardinality guardrail.
+	CardinalityBreaches uint64
 }
 
 // Pipeline is a bounded non-blocking telemetry pipeline.
@@ -164,11 +212,17 @@ type Pipeline struct {
 	sink Sink
 	cfg  Config
 
-	queue chan Event
-	stop  chan struct{}
+	queue    chan Event
+	stop     chan struct{}
+	overflow *OverflowQueue
+	guard    *cardinalityGuard
+	// spillQueue hands events the in-memory queue rejected to the spool writer goroutine.
+	spillQueue chan Event
+	spillStop  chan struct{}
 
 	closeOnce sync.Once
 	wg        sync.WaitGroup
+	spillWG   sync.WaitGroup
 
 	enqueued       atomic.Uint64
 	dropped        atomic.Uint64
@@ -176,6 +230,10 @@ type Pipeline struct {
 	exported       atomic.Uint64
 	exportFailures atomic.Uint64
 	logCounter     atomic.Uint64
+	spilled        atomic.Uint64
+	spillDropped   atomic.Uint64
+	replayed       atomic.Uint64
+	cardinality    atomic.Uint64
 }
 
 type discardSink struct{}
@@ -189,48 +247,91 @@ func NewPipeline(sink Sink, cfg Config) *Pipeline {
 		sink = discardSink{}
 	}
 	p := &Pipeline{
-		sink:  sink,
-		cfg:   cfg,
-		queue: make(chan Event, cfg.QueueCapacity),
-		stop:  make(chan struct{}),
+		sink:     sink,
+		cfg:      cfg,
+		queue:    make(chan Event, cfg.QueueCapacity),
+		stop:     make(chan struct{}),
+		overflow: cfg.Overflow,
+		guard:    newCardinalityGuard(cfg.Cardinality),
+	}
+	if p.overflow != nil {
+		p.spillQueue = make(chan Event, p.overflow.bufferEvents)
+		p.spillStop = make(chan struct{})
+		p.spillWG.Add(1)
+		go p.runSpill()
 	}
 	p.wg.Add(1)
 	go p.run()
 	return p
 }
 
-// Close drains pending events and stops background export.
+// Close drains pending events and stops background export. Events still spooled after a
+// final drain attempt stay on disk for the next pipeline.
 func (p *Pipeline) Close() error {
+	var err error
 	p.closeOnce.Do(func() {
+		if p.spillStop != nil {
+			close(p.spillStop)
+			p.spillWG.Wait()
+		}
 		close(p.stop)
 		p.wg.Wait()
+		if p.overflow != nil {
+			err = p.overflow.close()
+		}
 	})
-	return nil
+	return err
 }
 
 // Stats returns current queue/counter snapshots.
 func (p *Pipeline) Stats() Stats {
-	return Stats{
-		Enqueued:       p.enqueued.Load(),
-		Dropped:        p.dropped.Load(),
-		SampledDropped: p.sampledDropped.Load(),
-		Exported:       p.exported.Load(),
-		ExportFailures: p.exportFailures.Load(),
-		QueueDepth:     len(p.queue),
+	stats := Stats{
+		Enqueued:            p.enqueued.Load(),
+		Dropped:             p.dropped.Load(),
+		SampledDropped:      p.sampledDropped.Load(),
+		Exported:            p.exported.Load(),
+		ExportFailures:      p.exportFailures.Load(),
+		QueueDepth:          len(p.queue),
+		Spilled:             p.spilled.Load(),
+		SpillDropped:        p.spillDropped.Load(),
+		Replayed:            p.replayed.Load(),
+		CardinalityBreaches: p.cardinality.Load(),
 	}
+	if p.overflow != nil {
+		stats.OverflowPendingBytes = p.overflow.PendingBytes()
+	}
+	return stats
 }
 
 // EmitMetric enqueues a metric sample without blocking.
+// Label sets are passed through the cardinality guardrail; breaches are reported as
+// telemetry_cardinality_* meta-metrics.
 func (p *Pipeline) EmitMetric(name string, value float64, unit string, attributes map[string]string, correlation Correlation) {
+	name = strings.TrimSpace(name)
+	correlation = normalizeCorrelation(correlation)
+	verdict := p.guard.admit(name, withPipelineVersion(cloneAttributes(attributes), correlation.PipelineVersion))
+	p.enqueueMetric(name, value, unit, verdict.attributes, correlation)
+	if !verdict.breached {
+		return
+	}
+	p.cardinality.Add(1)
+	meta := map[string]string{"metric": name}
+	p.enqueueMetric(MetricCardinalityBreaches, 1, "1", meta, correlation)
+	if verdict.firstBreach {
+		p.enqueueMetric(MetricCardinalitySeries, float64(verdict.seriesCount), "1", cloneAttributes(meta), correlation)
+	}
+}
+
+func (p *Pipeline) enqueueMetric(name string, value float64, unit string, attributes map[string]string, correlation Correlation) {
 	p.enqueue(Event{
 		Kind:        EventKindMetric,
 		TimestampMS: eventTimestampMS(correlation),
-		Correlation: normalizeCorrelation(correlation),
+		Correlation: correlation,
 		Metric: &MetricEvent{
-			Name:       strings.TrimSpace(name),
+			Name:       name,
 			Value:      value,
 			Unit:       strings.TrimSpace(unit),
-			Attributes: cloneAttributes(attributes),
+			Attributes: attributes,
 		},
 	}, true)
 }
@@ -242,11 +343,14 @@ func (p *Pipeline) EmitSpan(name, kind string, startMS, endMS int64, attributes
 		TimestampMS: eventTimestampMS(correlation),
 		Correlation: normalizeCorrelation(correlation),
 		Span: &SpanEvent{
-			Name:       strings.TrimSpace(name),
-			Kind:       strings.TrimSpace(kind),
-			StartMS:    nonNegative(startMS),
-			EndMS:      nonNegative(endMS),
-			Attributes: cloneAttributes(attributes),
+			Name:         strings.TrimSpace(name),
+			Kind:         strings.TrimSpace(kind),
+			StartMS:      nonNegative(startMS),
+			EndMS:        nonNegative(endMS),
+			TraceID:      strings.TrimSpace(correlation.TraceID),
+			SpanID:       strings.TrimSpace(correlation.SpanID),
+			ParentSpanID: strings.TrimSpace(correlation.ParentSpanID),
+			Attributes:   withPipelineVersion(cloneAttributes(attributes), correlation.PipelineVersion),
 		},
 	}, true)
 }
@@ -261,7 +365,7 @@ func (p *Pipeline) EmitLog(name, severity, message string, attributes map[string
 			Name:       strings.TrimSpace(name),
 			Severity:   strings.TrimSpace(severity),
 			Message:    message,
-			Attributes: cloneAttributes(attributes),
+			Attributes: withPipelineVersion(cloneAttributes(attributes), correlation.PipelineVersion),
 		},
 	}
 	sampled := p.shouldSampleLog(event)
@@ -288,14 +392,70 @@ func (p *Pipeline) enqueue(event Event, sampled bool) {
 	select {
 	case p.queue <- event:
 		p.enqueued.Add(1)
+		return
 	default:
-		p.dropped.Add(1)
+	}
+	if p.spillQueue != nil {
+		select {
+		case p.spillQueue <- event:
+			p.enqueued.Add(1)
+			return
+		default:
+			p.spillDropped.Add(1)
+		}
+	}
+	p.dropped.Add(1)
+}
+
+// runSpill writes events handed off by enqueue to the overflow spool, keeping disk I/O off
+// the emit path. On close it flushes what is already buffered.
+func (p *Pipeline) runSpill() {
+	defer p.spillWG.Done()
+	for {
+		select {
+		case event := <-p.spillQueue:
+			if !p.spill(event) {
+				p.dropped.Add(1)
+			}
+		case <-p.spillStop:
+			for {
+				select {
+				case event := <-p.spillQueue:
+					if !p.spill(event) {
+						p.dropped.Add(1)
+					}
+				default:
+					return
+				}
+			}
+		}
 	}
 }
 
+// spill writes an event to the overflow spool, reporting whether it was kept. It runs only on
+// the pipeline's background goroutines.
+func (p *Pipeline) spill(event Event) bool {
+	if p.overflow == nil {
+		return false
+	}
+	if err := p.overflow.append(event); err != nil {
+		p.spillDropped.Add(1)
+		return false
+	}
+	p.spilled.Add(1)
+	return true
+}
+
 func (p *Pipeline) run() {
 	defer p.wg.Done()
 
+	var drainTick <-chan time.Time
+	if p.overflow != nil {
+		ticker := time.NewTicker(p.overflow.retryInterval)
+		defer ticker.Stop()
+		drainTick = ticker.C
+	}
+
 	for {
 		select {
 		case <-p.stop:
@@ -304,25 +464,67 @@ func (p *Pipeline) run() {
 				case event := <-p.queue:
 					p.export(event)
 				default:
+					p.drainOverflow(0)
 					return
 				}
 			}
 		case event := <-p.queue:
 			p.export(event)
+		case <-drainTick:
+			p.drainOverflow(p.cfg.QueueCapacity)
 		}
 	}
 }
 
 func (p *Pipeline) export(event Event) {
-	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.ExportTimeout)
-	defer cancel()
-	if err := p.sink.Export(ctx, event); err != nil {
+	if err := p.exportToSink(event); err != nil {
 		p.exportFailures.Add(1)
+		p.spill(event)
 		return
 	}
 	p.exported.Add(1)
 }
 
+func (p *Pipeline) exportToSink(event Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.ExportTimeout)
+	defer cancel()
+	return p.sink.Export(ctx, event)
+}
+
+// drainOverflow exports spooled events oldest-first until the spool is empty, an export
+// fails (the event stays spooled for the next retry tick), or limit events were exported
+// (limit <=0 means no limit). Live events preempt a bounded drain.
+func (p *Pipeline) drainOverflow(limit int) {
+	if p.overflow == nil {
+		return
+	}
+	for drained := 0; limit <= 0 || drained < limit; drained++ {
+		if limit > 0 && len(p.queue) > 0 {
+			return
+		}
+		event, next, ok, err := p.overflow.peek()
+		if !ok {
+			return
+		}
+		if err != nil {
+			p.spillDropped.Add(1)
+			if commitErr := p.overflow.commit(next); commitErr != nil {
+				return
+			}
+			continue
+		}
+		if err := p.exportToSink(event); err != nil {
+			p.exportFailures.Add(1)
+			return
+		}
+		if err := p.overflow.commit(next); err != nil {
+			return
+		}
+		p.exported.Add(1)
+		p.replayed.Add(1)
+	}
+}
+
 func eventTimestampMS(correlation Correlation) int64 {
 	if correlation.RuntimeTimestampMS > 0 {
 		return correlation.RuntimeTimestampMS
@@ -340,6 +542,9 @@ func normalizeCorrelation(c Correlation) Correlation {
 	c.PipelineVersion = strings.TrimSpace(c.PipelineVersion)
 	c.Lane = strings.TrimSpace(c.Lane)
 	c.EmittedBy = strings.TrimSpace(c.EmittedBy)
+	c.TraceID = strings.TrimSpace(c.TraceID)
+	c.SpanID = strings.TrimSpace(c.SpanID)
+	c.ParentSpanID = strings.TrimSpace(c.ParentSpanID)
 	return c
 }
 
@@ -350,6 +555,24 @@ func nonNegative(v int64) int64 {
 	return v
 }
 
+// withPipelineVersion tags attributes with the correlation pipeline version so metrics, spans,
+// and logs can be sliced by published version during mixed-version rollouts. An explicit
+// pipeline_version attribute wins.
+func withPipelineVersion(attributes map[string]string, pipelineVersion string) map[string]string {
+	pipelineVersion = strings.TrimSpace(pipelineVersion)
+	if pipelineVersion == "" {
+		return attributes
+	}
+	if _, ok := attributes[AttributePipelineVersion]; ok {
+		return attributes
+	}
+	if attributes == nil {
+		attributes = make(map[string]string, 1)
+	}
+	attributes[AttributePipelineVersion] = pipelineVersion
+	return attributes
+}
+
 func cloneAttributes(in map[string]string) map[string]string {
 	if len(in) == 0 {
 		return nil
@@ -129,6 +129,41 @@ func TestPipelineExportsMetricSpanAndLogEvents(t *testing.T) {
 	}
 }
 
+func TestPipelineTagsEventsWithPipelineVersion(t *testing.T) {
+	t.Parallel()
+
+	sink := NewMemorySink()
+	pipeline := NewPipeline(sink, Config{QueueCapacity: 16})
+	versioned := Correlation{PipelineVersion: "pipeline-v2", RuntimeTimestampMS: 100}
+	pipeline.EmitMetric(MetricShedRate, 0, "ratio", nil, versioned)
+	pipeline.EmitSpan("turn_span", "turn_span", 100, 105, map[string]string{"result": "active"}, versioned)
+	pipeline.EmitLog("runtime_event", "info", "turn opened", map[string]string{AttributePipelineVersion: "pipeline-v3"}, versioned)
+	pipeline.EmitMetric(MetricShedRate, 0, "ratio", nil, Correlation{RuntimeTimestampMS: 100})
+	if err := pipeline.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	events := sink.Events()
+	if len(events) != 4 {
+		t.Fatalf("expected 4 exported events, got %d", len(events))
+	}
+	tests := []struct {
+		name       string
+		attributes map[string]string
+		expected   string
+	}{
+		{name: "metric", attributes: events[0].Metric.Attributes, expected: "pipeline-v2"},
+		{name: "span", attributes: events[1].Span.Attributes, expected: "pipeline-v2"},
+		{name: "explicit attribute wins", attributes: events[2].Log.Attributes, expected: "pipeline-v3"},
+		{name: "unversioned", attributes: events[3].Metric.Attributes, expected: ""},
+	}
+	for _, tc := range tests {
+		if got := tc.attributes[AttributePipelineVersion]; got != tc.expected {
+			t.Fatalf("%s: expected pipeline_version %q, got %+v", tc.name, tc.expected, tc.attributes)
+		}
+	}
+}
+
 func TestDefaultEmitterCanBeOverridden(t *testing.T) {
 	sink := NewMemorySink()
 	pipeline := NewPipeline(sink, Config{QueueCapacity: 8})
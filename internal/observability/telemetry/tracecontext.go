@@ -0,0 +1,127 @@
+package telemetry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// TraceparentHeader is the W3C Trace Context propagation header.
+const TraceparentHeader = "traceparent"
+
+const (
+	traceparentVersion = "00"
+	traceFlagSampled   = "01"
+	traceFlagNone      = "00"
+)
+
+// TraceContext is a W3C trace context position: the trace, the current span, and its parent.
+// Child span ids are derived deterministically so replays reproduce the same span tree.
+type TraceContext struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Sampled      bool
+}
+
+// ParseTraceparent decodes a W3C traceparent header value. The parsed span becomes the
+// ParentSpanID of the returned context; callers open their own span with ChildSpan.
+func ParseTraceparent(value string) (TraceContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return TraceContext{}, fmt.Errorf("traceparent must have version-trace_id-parent_id-flags")
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isLowerHex(version, 2) || version == "ff" {
+		return TraceContext{}, fmt.Errorf("invalid traceparent version: %q", version)
+	}
+	if version == traceparentVersion && len(parts) != 4 {
+		return TraceContext{}, fmt.Errorf("traceparent version 00 must have exactly 4 fields")
+	}
+	if !isLowerHex(traceID, 32) || isAllZero(traceID) {
+		return TraceContext{}, fmt.Errorf("invalid traceparent trace_id: %q", traceID)
+	}
+	if !isLowerHex(spanID, 16) || isAllZero(spanID) {
+		return TraceContext{}, fmt.Errorf("invalid traceparent parent_id: %q", spanID)
+	}
+	if !isLowerHex(flags, 2) {
+		return TraceContext{}, fmt.Errorf("invalid traceparent flags: %q", flags)
+	}
+	flagBits, _ := hex.DecodeString(flags)
+	return TraceContext{
+		TraceID:      traceID,
+		ParentSpanID: spanID,
+		Sampled:      flagBits[0]&0x01 == 0x01,
+	}, nil
+}
+
+// NewRootTraceContext starts a sampled trace whose id is derived from key (for example a
+// session and turn id) so a turn without an inbound traceparent still has a stable trace.
+func NewRootTraceContext(key string) TraceContext {
+	sum := sha256.Sum256([]byte("trace|" + key))
+	return TraceContext{TraceID: hex.EncodeToString(sum[:16]), Sampled: true}
+}
+
+// Valid reports whether the context carries a trace id.
+func (t TraceContext) Valid() bool {
+	return isLowerHex(t.TraceID, 32) && !isAllZero(t.TraceID)
+}
+
+// ChildSpan opens a span under the current span (or the inbound parent when no span is
+// open yet). The span id is derived from the trace, the parent, and key.
+func (t TraceContext) ChildSpan(key string) TraceContext {
+	if !t.Valid() {
+		return TraceContext{}
+	}
+	parent := t.SpanID
+	if parent == "" {
+		parent = t.ParentSpanID
+	}
+	sum := sha256.Sum256([]byte(t.TraceID + "|" + parent + "|" + key))
+	spanID := hex.EncodeToString(sum[:8])
+	if isAllZero(spanID) {
+		spanID = "0000000000000001"
+	}
+	return TraceContext{TraceID: t.TraceID, SpanID: spanID, ParentSpanID: parent, Sampled: t.Sampled}
+}
+
+// Traceparent encodes the current span as a W3C traceparent header value, or "" when the
+// context has no open span.
+func (t TraceContext) Traceparent() string {
+	if !t.Valid() || !isLowerHex(t.SpanID, 16) {
+		return ""
+	}
+	flags := traceFlagNone
+	if t.Sampled {
+		flags = traceFlagSampled
+	}
+	return traceparentVersion + "-" + t.TraceID + "-" + t.SpanID + "-" + flags
+}
+
+// WithTrace returns c correlated to the span of t.
+func (c Correlation) WithTrace(t TraceContext) Correlation {
+	if !t.Valid() {
+		return c
+	}
+	c.TraceID = t.TraceID
+	c.SpanID = t.SpanID
+	c.ParentSpanID = t.ParentSpanID
+	return c
+}
+
+func isLowerHex(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for _, r := range value {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAllZero(value string) bool {
+	return strings.Trim(value, "0") == ""
+}
@@ -0,0 +1,98 @@
+package telemetry
+
+import (
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		value       string
+		wantErr     bool
+		wantSampled bool
+	}{
+		{name: "sampled", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantSampled: true},
+		{name: "not sampled", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"},
+		{name: "future version with extra field", value: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", wantSampled: true},
+		{name: "empty", value: "", wantErr: true},
+		{name: "version ff", value: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantErr: true},
+		{name: "version 00 extra field", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", wantErr: true},
+		{name: "zero trace id", value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", wantErr: true},
+		{name: "zero parent id", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", wantErr: true},
+		{name: "uppercase trace id", value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", wantErr: true},
+		{name: "short parent id", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa-01", wantErr: true},
+	}
+	for _, tc := range tests {
+		got, err := ParseTraceparent(tc.value)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("%s: expected wantErr=%v, got %v", tc.name, tc.wantErr, err)
+		}
+		if tc.wantErr {
+			continue
+		}
+		if got.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || got.ParentSpanID != "00f067aa0ba902b7" || got.SpanID != "" || got.Sampled != tc.wantSampled {
+			t.Fatalf("%s: unexpected trace context %+v", tc.name, got)
+		}
+	}
+}
+
+func TestTraceContextChildSpanRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	inbound, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	span := inbound.ChildSpan("ingress")
+	if span.ParentSpanID != "00f067aa0ba902b7" || span.TraceID != inbound.TraceID || len(span.SpanID) != 16 {
+		t.Fatalf("expected child of inbound parent, got %+v", span)
+	}
+	if again := inbound.ChildSpan("ingress"); again != span {
+		t.Fatalf("expected deterministic child span, got %+v and %+v", span, again)
+	}
+	child := span.ChildSpan("node")
+	if child.ParentSpanID != span.SpanID || child.SpanID == span.SpanID {
+		t.Fatalf("expected nested child span, got %+v", child)
+	}
+
+	roundTrip, err := ParseTraceparent(child.Traceparent())
+	if err != nil {
+		t.Fatalf("parse encoded traceparent: %v", err)
+	}
+	if roundTrip.TraceID != child.TraceID || roundTrip.ParentSpanID != child.SpanID || !roundTrip.Sampled {
+		t.Fatalf("expected encoded span to be the downstream parent, got %+v", roundTrip)
+	}
+
+	if (TraceContext{}).ChildSpan("node").Traceparent() != "" {
+		t.Fatalf("expected empty trace context to propagate nothing")
+	}
+	if inbound.Traceparent() != "" {
+		t.Fatalf("expected no traceparent before a span is opened")
+	}
+	root := NewRootTraceContext("sess-1|turn-1")
+	if !root.Valid() || root != NewRootTraceContext("sess-1|turn-1") || root == NewRootTraceContext("sess-1|turn-2") {
+		t.Fatalf("expected deterministic per-key root trace, got %+v", root)
+	}
+}
+
+func TestPipelineSpanCarriesTraceCorrelation(t *testing.T) {
+	t.Parallel()
+
+	sink := NewMemorySink()
+	pipeline := NewPipeline(sink, Config{QueueCapacity: 4})
+	span := NewRootTraceContext("sess-1|turn-1").ChildSpan("turn")
+	pipeline.EmitSpan("turn_span", "turn_span", 1, 2, nil, Correlation{SessionID: "sess-1"}.WithTrace(span))
+	if err := pipeline.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	events := sink.Events()
+	if len(events) != 1 || events[0].Span == nil {
+		t.Fatalf("expected one span event, got %+v", events)
+	}
+	got := events[0].Span
+	if got.TraceID != span.TraceID || got.SpanID != span.SpanID || got.ParentSpanID != span.ParentSpanID {
+		t.Fatalf("expected span trace ids from correlation, got %+v", got)
+	}
+}
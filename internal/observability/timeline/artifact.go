@@ -2,6 +2,7 @@ package timeline
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -59,3 +60,20 @@ func ReadBaselineArtifact(path string) (BaselineArtifact, error) {
 	}
 	return artifact, nil
 }
+
+// AppendBaselineArtifact appends entries to the baseline artifact at path, creating it when
+// absent, so a serve mode grows one artifact across its sessions. Callers serialize appends to
+// the same path.
+func AppendBaselineArtifact(path string, entries []BaselineEvidence) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	existing, err := ReadBaselineArtifact(path)
+	switch {
+	case err == nil:
+		entries = append(existing.Entries, entries...)
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+	return WriteBaselineArtifact(path, entries)
+}
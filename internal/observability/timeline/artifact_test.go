@@ -47,3 +47,30 @@ func TestReadBaselineArtifactRejectsEmptyEntries(t *testing.T) {
 		t.Fatalf("expected empty-entry artifact read to fail")
 	}
 }
+
+func TestAppendBaselineArtifactGrowsAcrossSessions(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "runtime-baseline.json")
+	for _, turnID := range []string{"turn-append-1", "turn-append-2"} {
+		if err := AppendBaselineArtifact(path, []BaselineEvidence{minimalBaseline(turnID)}); err != nil {
+			t.Fatalf("unexpected append error: %v", err)
+		}
+	}
+	if err := AppendBaselineArtifact(path, nil); err != nil {
+		t.Fatalf("unexpected empty append error: %v", err)
+	}
+	artifact, err := ReadBaselineArtifact(path)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if len(artifact.Entries) != 2 || artifact.Entries[0].TurnID != "turn-append-1" || artifact.Entries[1].TurnID != "turn-append-2" {
+		t.Fatalf("expected both sessions' entries in order, got %+v", artifact.Entries)
+	}
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if err := AppendBaselineArtifact(path, []BaselineEvidence{minimalBaseline("turn-append-3")}); err == nil {
+		t.Fatalf("expected a corrupt artifact to fail the append instead of being overwritten")
+	}
+}
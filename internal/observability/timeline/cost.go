@@ -0,0 +1,105 @@
+package timeline
+
+import "fmt"
+
+// UsageCostEvidence is provider usage and its estimated dollar cost.
+type UsageCostEvidence struct {
+	InputTokens   int64
+	OutputTokens  int64
+	AudioInputMS  int64
+	AudioOutputMS int64
+	// EstimatedCostUSD prices the usage with the runtime price table. Unpriced marks usage
+	// from a provider the table has no rates for, which is counted at zero cost.
+	EstimatedCostUSD float64
+	Unpriced         bool
+	// TokensEstimated marks token counts estimated from text length because the provider
+	// did not report usage.
+	TokensEstimated bool
+}
+
+// Validate enforces non-negative usage and cost.
+func (u UsageCostEvidence) Validate() error {
+	if u.InputTokens < 0 || u.OutputTokens < 0 || u.AudioInputMS < 0 || u.AudioOutputMS < 0 {
+		return fmt.Errorf("usage token and audio counts must be >=0")
+	}
+	if u.EstimatedCostUSD < 0 {
+		return fmt.Errorf("usage estimated_cost_usd must be >=0")
+	}
+	return nil
+}
+
+// Add accumulates other into u; the flags stick once any part is unpriced or estimated.
+func (u *UsageCostEvidence) Add(other UsageCostEvidence) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.AudioInputMS += other.AudioInputMS
+	u.AudioOutputMS += other.AudioOutputMS
+	u.EstimatedCostUSD += other.EstimatedCostUSD
+	u.Unpriced = u.Unpriced || other.Unpriced
+	u.TokensEstimated = u.TokensEstimated || other.TokensEstimated
+}
+
+// ProviderCostEvidence is one provider's share of a turn's usage and cost.
+type ProviderCostEvidence struct {
+	ProviderID string
+	Modality   string
+	Attempts   int
+	UsageCostEvidence
+}
+
+// TurnCostEvidence rolls up a turn's provider usage and cost under the tenant it is billed
+// to; session and tenant totals are sums over turns.
+type TurnCostEvidence struct {
+	TenantID string
+	UsageCostEvidence
+	// Providers is ordered by modality then provider id.
+	Providers []ProviderCostEvidence
+}
+
+// Validate enforces turn cost rollup invariants.
+func (e TurnCostEvidence) Validate() error {
+	if e.TenantID == "" {
+		return fmt.Errorf("turn cost tenant_id is required")
+	}
+	if err := e.UsageCostEvidence.Validate(); err != nil {
+		return err
+	}
+	var sum UsageCostEvidence
+	for _, provider := range e.Providers {
+		if provider.ProviderID == "" || provider.Attempts < 1 {
+			return fmt.Errorf("turn cost providers require provider_id and attempts>=1")
+		}
+		if err := provider.UsageCostEvidence.Validate(); err != nil {
+			return err
+		}
+		sum.Add(provider.UsageCostEvidence)
+	}
+	if sum.InputTokens != e.InputTokens || sum.OutputTokens != e.OutputTokens || sum.AudioInputMS != e.AudioInputMS || sum.AudioOutputMS != e.AudioOutputMS {
+		return fmt.Errorf("turn cost totals must equal the sum of provider usage")
+	}
+	return nil
+}
+
+// StandbyUpkeepEvidence is the cost of keeping one provider's warm-standby connection open
+// over an accounting interval.
+type StandbyUpkeepEvidence struct {
+	ProviderID string
+	Modality   string
+	// HeldMS is how long the standby was held; Refreshes and Failures count keep-alive holds
+	// that succeeded and failed.
+	HeldMS           int64
+	Refreshes        int
+	Failures         int
+	EstimatedCostUSD float64
+}
+
+// Validate enforces standby upkeep invariants.
+func (e StandbyUpkeepEvidence) Validate() error {
+	if e.ProviderID == "" || e.Modality == "" {
+		return fmt.Errorf("standby upkeep provider_id and modality are required")
+	}
+	if e.HeldMS < 0 || e.Refreshes < 0 || e.Failures < 0 || e.EstimatedCostUSD < 0 {
+		return fmt.Errorf("standby upkeep held_ms, counts, and estimated cost must be >=0")
+	}
+	return nil
+}
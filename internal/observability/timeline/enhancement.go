@@ -0,0 +1,111 @@
+package timeline
+
+import (
+	"fmt"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+)
+
+// AudioEnhancementEvidence records whether the pre-STT enhancement node ran for a turn and
+// the STT confidence observed downstream, so enhanced and bypassed turns can be compared.
+type AudioEnhancementEvidence struct {
+	SessionID       string
+	TurnID          string
+	PipelineVersion string
+	EventID         string
+	NodeID          string
+	Engine          string
+	Quality         string
+	// Bypassed is true when the degrade ladder skipped enhancement and STT consumed raw audio.
+	Bypassed             bool
+	BypassedBy           controlplane.DegradeStep
+	STTConfidence        float64
+	RuntimeTimestampMS   int64
+	WallClockTimestampMS int64
+}
+
+// Validate enforces audio enhancement evidence invariants.
+func (e AudioEnhancementEvidence) Validate() error {
+	if e.SessionID == "" || e.PipelineVersion == "" || e.EventID == "" {
+		return fmt.Errorf("session_id, pipeline_version, and event_id are required")
+	}
+	if e.NodeID == "" {
+		return fmt.Errorf("node_id is required")
+	}
+	if err := (controlplane.AudioEnhancement{Enabled: true, Engine: e.Engine, Quality: e.Quality}).Validate(); err != nil {
+		return err
+	}
+	if e.Bypassed != (e.BypassedBy != "") {
+		return fmt.Errorf("bypassed_by is required exactly when bypassed")
+	}
+	if e.STTConfidence < 0 || e.STTConfidence > 1 {
+		return fmt.Errorf("stt_confidence must be within [0,1]")
+	}
+	if e.RuntimeTimestampMS < 0 || e.WallClockTimestampMS < 0 {
+		return fmt.Errorf("audio enhancement timestamps must be >=0")
+	}
+	return nil
+}
+
+// AppendAudioEnhancementEvidence appends pre-STT enhancement evidence.
+func (r *Recorder) AppendAudioEnhancementEvidence(evidence AudioEnhancementEvidence) error {
+	if err := evidence.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.enhanceEntries) >= r.cfg.AudioEnhancementCapacity {
+		return ErrAudioEnhancementCapacityExhausted
+	}
+	r.enhanceEntries = append(r.enhanceEntries, evidence)
+	return nil
+}
+
+// AudioEnhancementEntries returns a stable copy of audio enhancement evidence.
+func (r *Recorder) AudioEnhancementEntries() []AudioEnhancementEvidence {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]AudioEnhancementEvidence, len(r.enhanceEntries))
+	copy(out, r.enhanceEntries)
+	return out
+}
+
+// AudioEnhancementImpact compares mean STT confidence of enhanced turns against bypassed turns.
+// ConfidenceDelta is only meaningful when both EnhancedTurns and BypassedTurns are non-zero.
+type AudioEnhancementImpact struct {
+	EnhancedTurns          int
+	BypassedTurns          int
+	MeanEnhancedConfidence float64
+	MeanBypassedConfidence float64
+	ConfidenceDelta        float64
+}
+
+// MeasureAudioEnhancementImpact summarizes STT confidence impact for one pipeline version.
+func MeasureAudioEnhancementImpact(entries []AudioEnhancementEvidence, pipelineVersion string) AudioEnhancementImpact {
+	impact := AudioEnhancementImpact{}
+	var enhancedSum, bypassedSum float64
+	for _, entry := range entries {
+		if entry.PipelineVersion != pipelineVersion {
+			continue
+		}
+		if entry.Bypassed {
+			impact.BypassedTurns++
+			bypassedSum += entry.STTConfidence
+			continue
+		}
+		impact.EnhancedTurns++
+		enhancedSum += entry.STTConfidence
+	}
+	if impact.EnhancedTurns > 0 {
+		impact.MeanEnhancedConfidence = enhancedSum / float64(impact.EnhancedTurns)
+	}
+	if impact.BypassedTurns > 0 {
+		impact.MeanBypassedConfidence = bypassedSum / float64(impact.BypassedTurns)
+	}
+	if impact.EnhancedTurns > 0 && impact.BypassedTurns > 0 {
+		impact.ConfidenceDelta = impact.MeanEnhancedConfidence - impact.MeanBypassedConfidence
+	}
+	return impact
+}
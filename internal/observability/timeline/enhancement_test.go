@@ -0,0 +1,79 @@
+package timeline
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+)
+
+func testAudioEnhancementEvidence(eventID string, bypassed bool, confidence float64) AudioEnhancementEvidence {
+	evidence := AudioEnhancementEvidence{
+		SessionID:       "sess-enhance-1",
+		TurnID:          "turn-" + eventID,
+		PipelineVersion: "pipeline-v1",
+		EventID:         eventID,
+		NodeID:          "enhance",
+		Engine:          "rnnoise",
+		Quality:         "balanced",
+		STTConfidence:   confidence,
+	}
+	if bypassed {
+		evidence.Bypassed = true
+		evidence.BypassedBy = controlplane.DegradeBypassAudioEnhancement
+	}
+	return evidence
+}
+
+func TestAudioEnhancementEvidenceValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		mutate    func(*AudioEnhancementEvidence)
+		shouldErr bool
+	}{
+		{name: "valid", mutate: func(*AudioEnhancementEvidence) {}},
+		{name: "missing node", mutate: func(e *AudioEnhancementEvidence) { e.NodeID = "" }, shouldErr: true},
+		{name: "unknown engine", mutate: func(e *AudioEnhancementEvidence) { e.Engine = "speex" }, shouldErr: true},
+		{name: "bypass without step", mutate: func(e *AudioEnhancementEvidence) { e.Bypassed = true }, shouldErr: true},
+		{name: "confidence out of range", mutate: func(e *AudioEnhancementEvidence) { e.STTConfidence = 1.2 }, shouldErr: true},
+	}
+	for _, tc := range tests {
+		evidence := testAudioEnhancementEvidence("evt-enhance-1", false, 0.8)
+		tc.mutate(&evidence)
+		err := evidence.Validate()
+		if tc.shouldErr && err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestAudioEnhancementEvidenceCapacityAndImpact(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewRecorder(StageAConfig{AudioEnhancementCapacity: 3})
+	for _, evidence := range []AudioEnhancementEvidence{
+		testAudioEnhancementEvidence("evt-enhance-1", false, 0.9),
+		testAudioEnhancementEvidence("evt-enhance-2", false, 0.8),
+		testAudioEnhancementEvidence("evt-enhance-3", true, 0.6),
+	} {
+		if err := recorder.AppendAudioEnhancementEvidence(evidence); err != nil {
+			t.Fatalf("unexpected append error: %v", err)
+		}
+	}
+	if err := recorder.AppendAudioEnhancementEvidence(testAudioEnhancementEvidence("evt-enhance-4", true, 0.5)); err != ErrAudioEnhancementCapacityExhausted {
+		t.Fatalf("expected capacity exhaustion, got %v", err)
+	}
+
+	impact := MeasureAudioEnhancementImpact(recorder.AudioEnhancementEntries(), "pipeline-v1")
+	if impact.EnhancedTurns != 2 || impact.BypassedTurns != 1 || math.Abs(impact.ConfidenceDelta-0.25) > 1e-9 {
+		t.Fatalf("expected enhanced confidence delta 0.25 over 2/1 turns, got %+v", impact)
+	}
+	if other := MeasureAudioEnhancementImpact(recorder.AudioEnhancementEntries(), "pipeline-v2"); other != (AudioEnhancementImpact{}) {
+		t.Fatalf("expected no impact for unrelated pipeline version, got %+v", other)
+	}
+}
@@ -0,0 +1,84 @@
+package timeline
+
+import (
+	"fmt"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+const (
+	// EnrichmentOutcomeOK marks an enricher whose metadata was attached to the session context.
+	EnrichmentOutcomeOK = "ok"
+	// EnrichmentOutcomeError marks an enricher that failed; the session starts without its metadata.
+	EnrichmentOutcomeError = "error"
+	// EnrichmentOutcomeBudgetExceeded marks an enricher still running when the session-start
+	// enrichment budget elapsed; its late result is discarded.
+	EnrichmentOutcomeBudgetExceeded = "budget_exceeded"
+)
+
+// SessionEnrichmentEvidence records one session-start enricher run. Only metadata keys are
+// recorded: enrichment values may carry tenant CRM data and stay out of the timeline.
+type SessionEnrichmentEvidence struct {
+	SessionID       string
+	PipelineVersion string
+	EventID         string
+	Enricher        string
+	PayloadClass    eventabi.PayloadClass
+	Outcome         string
+	StartedAtMS     int64
+	LatencyMS       int64
+	BudgetMS        int64
+	MetadataKeys    []string
+}
+
+// Validate enforces session enrichment evidence invariants.
+func (e SessionEnrichmentEvidence) Validate() error {
+	if e.SessionID == "" || e.PipelineVersion == "" || e.EventID == "" {
+		return fmt.Errorf("session_id, pipeline_version, and event_id are required")
+	}
+	if e.Enricher == "" {
+		return fmt.Errorf("session enrichment enricher is required")
+	}
+	if e.PayloadClass != eventabi.PayloadMetadata {
+		return fmt.Errorf("session enrichment payload_class must be %s", eventabi.PayloadMetadata)
+	}
+	switch e.Outcome {
+	case EnrichmentOutcomeOK:
+	case EnrichmentOutcomeError, EnrichmentOutcomeBudgetExceeded:
+		if len(e.MetadataKeys) != 0 {
+			return fmt.Errorf("session enrichment %s outcome must not attach metadata keys", e.Outcome)
+		}
+	default:
+		return fmt.Errorf("unsupported session enrichment outcome: %q", e.Outcome)
+	}
+	if e.StartedAtMS < 0 || e.LatencyMS < 0 || e.BudgetMS < 1 {
+		return fmt.Errorf("session enrichment requires started_at_ms, latency_ms >=0 and budget_ms >=1")
+	}
+	return nil
+}
+
+// AppendSessionEnrichmentEvidence appends session-start enrichment evidence.
+func (r *Recorder) AppendSessionEnrichmentEvidence(evidence SessionEnrichmentEvidence) error {
+	if err := evidence.Validate(); err != nil {
+		return err
+	}
+	evidence.MetadataKeys = append([]string(nil), evidence.MetadataKeys...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.enrichEntries) >= r.cfg.SessionEnrichmentCap {
+		return ErrSessionEnrichmentCapacityExhausted
+	}
+	r.enrichEntries = append(r.enrichEntries, evidence)
+	return nil
+}
+
+// SessionEnrichmentEntries returns a stable copy of session enrichment evidence.
+func (r *Recorder) SessionEnrichmentEntries() []SessionEnrichmentEvidence {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]SessionEnrichmentEvidence, len(r.enrichEntries))
+	copy(out, r.enrichEntries)
+	return out
+}
@@ -0,0 +1,72 @@
+package timeline
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+func testSessionEnrichmentEvidence(enricher string) SessionEnrichmentEvidence {
+	return SessionEnrichmentEvidence{
+		SessionID:       "sess-enrich-1",
+		PipelineVersion: "pipeline-v1",
+		EventID:         "evt-enrich-" + enricher,
+		Enricher:        enricher,
+		PayloadClass:    eventabi.PayloadMetadata,
+		Outcome:         EnrichmentOutcomeOK,
+		StartedAtMS:     1_000,
+		LatencyMS:       35,
+		BudgetMS:        200,
+		MetadataKeys:    []string{enricher + ".tier"},
+	}
+}
+
+func TestSessionEnrichmentEvidenceValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		mutate    func(*SessionEnrichmentEvidence)
+		shouldErr bool
+	}{
+		{name: "valid", mutate: func(*SessionEnrichmentEvidence) {}},
+		{name: "budget exceeded without keys", mutate: func(e *SessionEnrichmentEvidence) {
+			e.Outcome = EnrichmentOutcomeBudgetExceeded
+			e.MetadataKeys = nil
+		}},
+		{name: "missing enricher", mutate: func(e *SessionEnrichmentEvidence) { e.Enricher = "" }, shouldErr: true},
+		{name: "wrong payload class", mutate: func(e *SessionEnrichmentEvidence) { e.PayloadClass = eventabi.PayloadPII }, shouldErr: true},
+		{name: "unknown outcome", mutate: func(e *SessionEnrichmentEvidence) { e.Outcome = "partial" }, shouldErr: true},
+		{name: "error with keys", mutate: func(e *SessionEnrichmentEvidence) { e.Outcome = EnrichmentOutcomeError }, shouldErr: true},
+		{name: "zero budget", mutate: func(e *SessionEnrichmentEvidence) { e.BudgetMS = 0 }, shouldErr: true},
+	}
+	for _, tc := range tests {
+		evidence := testSessionEnrichmentEvidence("crm")
+		tc.mutate(&evidence)
+		err := evidence.Validate()
+		if tc.shouldErr && err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestSessionEnrichmentEvidenceCapacity(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewRecorder(StageAConfig{SessionEnrichmentCap: 1})
+	evidence := testSessionEnrichmentEvidence("crm")
+	if err := recorder.AppendSessionEnrichmentEvidence(evidence); err != nil {
+		t.Fatalf("unexpected append error: %v", err)
+	}
+	evidence.MetadataKeys[0] = "mutated"
+	if err := recorder.AppendSessionEnrichmentEvidence(testSessionEnrichmentEvidence("prefs")); err != ErrSessionEnrichmentCapacityExhausted {
+		t.Fatalf("expected capacity exhaustion, got %v", err)
+	}
+	entries := recorder.SessionEnrichmentEntries()
+	if len(entries) != 1 || entries[0].MetadataKeys[0] != "crm.tier" {
+		t.Fatalf("expected one isolated enrichment entry, got %+v", entries)
+	}
+}
@@ -0,0 +1,45 @@
+package timeline
+
+import "fmt"
+
+// Fallback utterance audio sources.
+const (
+	FallbackSourcePresynthesized = "presynthesized"
+	FallbackSourceTTS            = "tts"
+)
+
+// FallbackUtteranceEvidence records the fallback utterance spoken in place of a generated
+// response when a turn degraded. A turn carrying it terminates with terminal outcome
+// "fallback" rather than commit or abort, so degraded turns stay distinguishable in replay.
+type FallbackUtteranceEvidence struct {
+	EventID string
+	// Reason is the degrade reason that selected the template; TemplateReason is the template
+	// actually used, which differs when the default template covered an unconfigured reason.
+	Reason         string
+	TemplateReason string
+	Text           string
+	Source         string
+	SampleRateHz   int
+	AudioSamples   int
+	EmittedAtMS    int64
+}
+
+// Validate enforces fallback utterance evidence invariants.
+func (e FallbackUtteranceEvidence) Validate() error {
+	if e.EventID == "" || e.Reason == "" || e.TemplateReason == "" {
+		return fmt.Errorf("fallback utterance event_id, reason, and template_reason are required")
+	}
+	if e.Text == "" {
+		return fmt.Errorf("fallback utterance text is required")
+	}
+	if e.Source != FallbackSourcePresynthesized && e.Source != FallbackSourceTTS {
+		return fmt.Errorf("fallback utterance source must be %s or %s", FallbackSourcePresynthesized, FallbackSourceTTS)
+	}
+	if e.SampleRateHz < 1 || e.AudioSamples < 1 {
+		return fmt.Errorf("fallback utterance requires sample_rate_hz>=1 and audio_samples>=1")
+	}
+	if e.EmittedAtMS < 0 {
+		return fmt.Errorf("fallback utterance emitted_at_ms must be >=0")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package timeline
+
+import "testing"
+
+func TestBaselineFallbackTerminalOutcome(t *testing.T) {
+	t.Parallel()
+
+	utterance := FallbackUtteranceEvidence{
+		EventID:        "evt-fallback-1",
+		Reason:         "provider_failure",
+		TemplateReason: "default",
+		Text:           "Sorry, I can't answer right now.",
+		Source:         FallbackSourceTTS,
+		SampleRateHz:   16000,
+		AudioSamples:   3200,
+		EmittedAtMS:    20,
+	}
+	tests := []struct {
+		name      string
+		mutate    func(*BaselineEvidence)
+		shouldErr bool
+	}{
+		{name: "fallback with utterance", mutate: func(b *BaselineEvidence) {}},
+		{name: "fallback without utterance", mutate: func(b *BaselineEvidence) { b.FallbackUtterance = nil }, shouldErr: true},
+		{name: "fallback without reason", mutate: func(b *BaselineEvidence) { b.TerminalReason = "" }, shouldErr: true},
+		{name: "utterance reason mismatch", mutate: func(b *BaselineEvidence) { b.TerminalReason = "budget_exhausted" }, shouldErr: true},
+		{name: "commit with utterance", mutate: func(b *BaselineEvidence) { b.TerminalOutcome, b.TerminalReason = "commit", "" }, shouldErr: true},
+		{name: "invalid source", mutate: func(b *BaselineEvidence) {
+			bad := *b.FallbackUtterance
+			bad.Source = "cache"
+			b.FallbackUtterance = &bad
+		}, shouldErr: true},
+	}
+	for _, tc := range tests {
+		evidence := minimalBaseline("turn-fallback-1")
+		evidence.TerminalOutcome, evidence.TerminalReason = "fallback", "provider_failure"
+		copied := utterance
+		evidence.FallbackUtterance = &copied
+		tc.mutate(&evidence)
+		err := evidence.ValidateCompleteness()
+		if tc.shouldErr && err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package timeline
+
+import "fmt"
+
+// QueueEstimateEvidence records a queue position/ETA sent to a client for a deferred turn, the
+// inputs it was derived from, and (once the turn opens) the actual wait, so estimate accuracy
+// can be analyzed offline.
+type QueueEstimateEvidence struct {
+	SessionID       string
+	TurnID          string
+	PipelineVersion string
+	EventID         string
+	EstimatedAtMS   int64
+	QueuePosition   int64
+	EstimatedWaitMS int64
+	// Method names the derivation; TurnsAhead, ActiveSlots, and MeanTurnServiceMS are its inputs.
+	Method            string
+	TurnsAhead        int64
+	ActiveSlots       int64
+	MeanTurnServiceMS int64
+	// Resolved is set once the turn opens; ActualWaitMS is measured from EstimatedAtMS.
+	Resolved     bool
+	AdmittedAtMS int64
+	ActualWaitMS int64
+}
+
+// Validate enforces queue estimate evidence invariants.
+func (e QueueEstimateEvidence) Validate() error {
+	if e.SessionID == "" || e.TurnID == "" || e.PipelineVersion == "" || e.EventID == "" {
+		return fmt.Errorf("session_id, turn_id, pipeline_version, and event_id are required")
+	}
+	if e.Method == "" {
+		return fmt.Errorf("queue estimate method is required")
+	}
+	if e.QueuePosition < 1 || e.EstimatedWaitMS < 0 || e.EstimatedAtMS < 0 {
+		return fmt.Errorf("queue estimate requires queue_position>=1 and estimated_wait_ms, estimated_at_ms >=0")
+	}
+	if e.TurnsAhead < 0 || e.ActiveSlots < 1 || e.MeanTurnServiceMS < 0 {
+		return fmt.Errorf("queue estimate inputs require turns_ahead>=0, active_slots>=1, and mean_turn_service_ms>=0")
+	}
+	if e.Resolved && (e.AdmittedAtMS < e.EstimatedAtMS || e.ActualWaitMS != e.AdmittedAtMS-e.EstimatedAtMS) {
+		return fmt.Errorf("resolved queue estimate requires admitted_at_ms>=estimated_at_ms and matching actual_wait_ms")
+	}
+	return nil
+}
+
+// AppendQueueEstimateEvidence appends an unresolved or resolved queue estimate.
+func (r *Recorder) AppendQueueEstimateEvidence(evidence QueueEstimateEvidence) error {
+	if err := evidence.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.queueEntries) >= r.cfg.QueueEstimateCapacity {
+		return ErrQueueEstimateCapacityExhausted
+	}
+	r.queueEntries = append(r.queueEntries, evidence)
+	return nil
+}
+
+// ResolveQueueEstimates records admittedAtMS as the actual admission time for every unresolved
+// estimate of the turn and returns how many were resolved. Estimates made after admittedAtMS
+// are left unresolved.
+func (r *Recorder) ResolveQueueEstimates(sessionID string, turnID string, admittedAtMS int64) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	resolved := 0
+	for i := range r.queueEntries {
+		entry := &r.queueEntries[i]
+		if entry.Resolved || entry.SessionID != sessionID || entry.TurnID != turnID || admittedAtMS < entry.EstimatedAtMS {
+			continue
+		}
+		entry.Resolved = true
+		entry.AdmittedAtMS = admittedAtMS
+		entry.ActualWaitMS = admittedAtMS - entry.EstimatedAtMS
+		resolved++
+	}
+	return resolved
+}
+
+// QueueEstimateEntries returns a stable copy of queue estimate evidence.
+func (r *Recorder) QueueEstimateEntries() []QueueEstimateEvidence {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]QueueEstimateEvidence, len(r.queueEntries))
+	copy(out, r.queueEntries)
+	return out
+}
@@ -0,0 +1,94 @@
+package timeline
+
+import (
+	"errors"
+	"testing"
+)
+
+func testQueueEstimateEvidence(turnID string) QueueEstimateEvidence {
+	return QueueEstimateEvidence{
+		SessionID:         "sess-queue-1",
+		TurnID:            turnID,
+		PipelineVersion:   "pipeline-v1",
+		EventID:           "evt-" + turnID + "-queue-eta",
+		EstimatedAtMS:     1_000,
+		QueuePosition:     3,
+		EstimatedWaitMS:   2_400,
+		Method:            "fifo_slots_mean_service/v1",
+		TurnsAhead:        2,
+		ActiveSlots:       2,
+		MeanTurnServiceMS: 1_200,
+	}
+}
+
+func TestQueueEstimateEvidenceValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		mutate    func(*QueueEstimateEvidence)
+		shouldErr bool
+	}{
+		{name: "valid", mutate: func(*QueueEstimateEvidence) {}},
+		{name: "valid resolved", mutate: func(e *QueueEstimateEvidence) {
+			e.Resolved, e.AdmittedAtMS, e.ActualWaitMS = true, 3_000, 2_000
+		}},
+		{name: "missing turn", mutate: func(e *QueueEstimateEvidence) { e.TurnID = "" }, shouldErr: true},
+		{name: "missing method", mutate: func(e *QueueEstimateEvidence) { e.Method = "" }, shouldErr: true},
+		{name: "zero position", mutate: func(e *QueueEstimateEvidence) { e.QueuePosition = 0 }, shouldErr: true},
+		{name: "no slots", mutate: func(e *QueueEstimateEvidence) { e.ActiveSlots = 0 }, shouldErr: true},
+		{name: "resolved before estimate", mutate: func(e *QueueEstimateEvidence) {
+			e.Resolved, e.AdmittedAtMS, e.ActualWaitMS = true, 500, -500
+		}, shouldErr: true},
+		{name: "resolved wait mismatch", mutate: func(e *QueueEstimateEvidence) {
+			e.Resolved, e.AdmittedAtMS, e.ActualWaitMS = true, 3_000, 1
+		}, shouldErr: true},
+	}
+	for _, tc := range tests {
+		evidence := testQueueEstimateEvidence("turn-1")
+		tc.mutate(&evidence)
+		err := evidence.Validate()
+		if tc.shouldErr && err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestQueueEstimateEvidenceResolveAndCapacity(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewRecorder(StageAConfig{QueueEstimateCapacity: 2})
+	first := testQueueEstimateEvidence("turn-1")
+	second := first
+	second.EventID, second.EstimatedAtMS, second.QueuePosition, second.EstimatedWaitMS = "evt-turn-1-queue-eta-2", 1_500, 2, 1_200
+	for _, evidence := range []QueueEstimateEvidence{first, second} {
+		if err := recorder.AppendQueueEstimateEvidence(evidence); err != nil {
+			t.Fatalf("unexpected append error: %v", err)
+		}
+	}
+	if err := recorder.AppendQueueEstimateEvidence(testQueueEstimateEvidence("turn-2")); !errors.Is(err, ErrQueueEstimateCapacityExhausted) {
+		t.Fatalf("expected capacity exhaustion, got %v", err)
+	}
+
+	if resolved := recorder.ResolveQueueEstimates("sess-queue-1", "turn-other", 4_000); resolved != 0 {
+		t.Fatalf("expected no estimates resolved for another turn, got %d", resolved)
+	}
+	if resolved := recorder.ResolveQueueEstimates("sess-queue-1", "turn-1", 4_000); resolved != 2 {
+		t.Fatalf("expected both estimates resolved, got %d", resolved)
+	}
+	entries := recorder.QueueEstimateEntries()
+	if len(entries) != 2 || !entries[0].Resolved || entries[0].ActualWaitMS != 3_000 || entries[1].ActualWaitMS != 2_500 {
+		t.Fatalf("unexpected resolved entries: %+v", entries)
+	}
+	for _, entry := range entries {
+		if err := entry.Validate(); err != nil {
+			t.Fatalf("resolved entry should validate: %v", err)
+		}
+	}
+	if resolved := recorder.ResolveQueueEstimates("sess-queue-1", "turn-1", 5_000); resolved != 0 {
+		t.Fatalf("expected resolution to be idempotent, got %d", resolved)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/decisionindex"
 )
 
 var (
@@ -16,6 +17,16 @@ var (
 	ErrProviderAttemptCapacityExhausted = fmt.Errorf("timeline provider attempt stage-a capacity exhausted")
 	// ErrInvocationSnapshotCapacityExhausted indicates Stage-A invocation snapshot capacity is depleted.
 	ErrInvocationSnapshotCapacityExhausted = fmt.Errorf("timeline invocation snapshot stage-a capacity exhausted")
+	// ErrNodeCacheCapacityExhausted indicates Stage-A node cache evidence capacity is depleted.
+	ErrNodeCacheCapacityExhausted = fmt.Errorf("timeline node cache stage-a capacity exhausted")
+	// ErrAudioEnhancementCapacityExhausted indicates Stage-A audio enhancement evidence capacity is depleted.
+	ErrAudioEnhancementCapacityExhausted = fmt.Errorf("timeline audio enhancement stage-a capacity exhausted")
+	// ErrSessionSummaryCapacityExhausted indicates Stage-A session summary evidence capacity is depleted.
+	ErrSessionSummaryCapacityExhausted = fmt.Errorf("timeline session summary stage-a capacity exhausted")
+	// ErrQueueEstimateCapacityExhausted indicates Stage-A queue estimate evidence capacity is depleted.
+	ErrQueueEstimateCapacityExhausted = fmt.Errorf("timeline queue estimate stage-a capacity exhausted")
+	// ErrSessionEnrichmentCapacityExhausted indicates Stage-A session enrichment evidence capacity is depleted.
+	ErrSessionEnrichmentCapacityExhausted = fmt.Errorf("timeline session enrichment stage-a capacity exhausted")
 )
 
 // StageAConfig defines bounded Stage-A append capacities.
@@ -25,38 +36,79 @@ type StageAConfig struct {
 	AttemptCapacity          int
 	InvocationSnapshotCap    int
 	EnableInvocationSnapshot bool
+	NodeCacheCapacity        int
+	AudioEnhancementCapacity int
+	SessionSummaryCapacity   int
+	QueueEstimateCapacity    int
+	SessionEnrichmentCap     int
+	// DecisionIndex optionally receives the decision outcomes of every appended baseline entry.
+	DecisionIndex *decisionindex.Index
 }
 
 // BaselineEvidence holds replay-critical OR-02 Stage-A evidence.
 type BaselineEvidence struct {
-	SessionID                string
-	TurnID                   string
-	PipelineVersion          string
-	EventID                  string
-	EnvelopeSnapshot         string
-	PayloadTags              []eventabi.PayloadClass
-	RedactionDecisions       []eventabi.RedactionDecision
-	PlanHash                 string
-	SnapshotProvenance       controlplane.SnapshotProvenance
-	DecisionOutcomes         []controlplane.DecisionOutcome
-	InvocationOutcomes       []InvocationOutcomeEvidence
-	DeterminismSeed          int64
-	OrderingMarkers          []string
-	MergeRuleID              string
-	MergeRuleVersion         string
-	AuthorityEpoch           int64
-	MigrationMarkers         []string
-	TerminalOutcome          string
-	TerminalReason           string
-	CloseEmitted             bool
-	TurnOpenProposedAtMS     *int64
-	TurnOpenAtMS             *int64
+	SessionID            string
+	TurnID               string
+	PipelineVersion      string
+	EventID              string
+	EnvelopeSnapshot     string
+	PayloadTags          []eventabi.PayloadClass
+	RedactionDecisions   []eventabi.RedactionDecision
+	PlanHash             string
+	SnapshotProvenance   controlplane.SnapshotProvenance
+	DecisionOutcomes     []controlplane.DecisionOutcome
+	InvocationOutcomes   []InvocationOutcomeEvidence
+	DeterminismSeed      int64
+	OrderingMarkers      []string
+	MergeRuleID          string
+	MergeRuleVersion     string
+	AuthorityEpoch       int64
+	MigrationMarkers     []string
+	TerminalOutcome      string
+	TerminalReason       string
+	CloseEmitted         bool
+	TurnOpenProposedAtMS *int64
+	TurnOpenAtMS         *int64
+	// Trigger records the session trigger mode and capture timestamps that opened the turn;
+	// nil when the turn was not opened through a session trigger.
+	Trigger                  *controlplane.TurnTrigger
 	FirstOutputAtMS          *int64
 	CancelAcceptedAtMS       *int64
 	CancelFenceAppliedAtMS   *int64
 	CancelSentAtMS           *int64
 	CancelAckAtMS            *int64
 	AcceptedStaleEpochOutput bool
+	// FirstAudioPlayedAtMS is when the client acknowledged that reply audio began playing, on
+	// the runtime clock; nil when no playback acknowledgment arrived.
+	FirstAudioPlayedAtMS *int64
+	// PlaybackCancelledAtMS is when the transport cut off the turn's reply playback, on the
+	// runtime clock, and PlaybackCancelReason why (barge_in); nil when playback was not cut off.
+	PlaybackCancelledAtMS *int64
+	PlaybackCancelReason  string
+	// TraceID is the W3C trace id of the turn, for cross-referencing provider-side spans.
+	TraceID string
+	// Locale and TTSVoice record the session locale selection that drove provider language
+	// choices; empty when the session did not select a locale.
+	Locale   string
+	TTSVoice string
+	// SessionSummaryID and SessionSummaryHash identify the derived_summary bound into the turn's
+	// LLM context; empty when no summary was bound.
+	SessionSummaryID   string
+	SessionSummaryHash string
+	// FallbackUtterance is set when the turn degraded to a fallback utterance; TerminalOutcome
+	// is then "fallback" and TerminalReason the degrade reason.
+	FallbackUtterance *FallbackUtteranceEvidence
+	// SLA is the tenant SLA tier that applied to the turn; nil when the tenant declared none.
+	SLA *controlplane.TurnSLA
+	// Cost rolls up the turn's provider usage and estimated cost; nil when the runtime did
+	// not account cost.
+	Cost *TurnCostEvidence
+	// StandbyUpkeep is the warm-standby upkeep accrued since the previous turn's record; empty
+	// when warm standby is off.
+	StandbyUpkeep []StandbyUpkeepEvidence
+	// ControlSignals are the turn-scope control-lane signals that drove the terminal outcome
+	// (disconnect, stall, deauthorized drain), kept for failure-domain attribution.
+	ControlSignals []eventabi.ControlSignal
 }
 
 // InvocationOutcomeEvidence records normalized provider/external invocation outcomes.
@@ -70,6 +122,34 @@ type InvocationOutcomeEvidence struct {
 	AttemptCount             int
 	FinalAttemptLatencyMS    int64
 	TotalInvocationLatencyMS int64
+	// SessionAffinity and ProviderSessionIDHash record the final LLM attempt's provider-session
+	// affinity decision; the raw provider session id is never recorded.
+	SessionAffinity       string
+	ProviderSessionIDHash string
+	// LengthCapped marks successful output truncated at a plan or degrade output length cap.
+	LengthCapped bool
+	// FirstChunkLatencyMS is the final attempt's streaming first-chunk latency; zero when the
+	// provider did not report one.
+	FirstChunkLatencyMS int64
+	// SamplingSeed is the deterministic LLM sampling seed requested for the invocation and
+	// SamplingSeedStatus how the final attempt's provider handled it (applied, ignored, or
+	// unsupported); both are empty when no seed was requested.
+	SamplingSeed       int64
+	SamplingSeedStatus string
+	// CircuitState is the final attempt's circuit breaker state (closed or half_open), or open
+	// when every candidate provider was skipped; empty when circuit breaking is off.
+	CircuitState string
+	// WarmStandby marks a final attempt issued over the provider's held warm-standby
+	// connection, which skips connection setup on provider_switch failover.
+	WarmStandby bool
+	// EndpointingEnforcement records who applied STT endpointing: provider, or vad_emulated
+	// for the runtime endpointer on captured audio, whose decision is EndpointKind. Both are
+	// empty when no endpointing was applied.
+	EndpointingEnforcement string
+	EndpointKind           string
+	// Cost is the invocation's provider usage and estimated cost across its attempts; nil
+	// when the runtime did not account cost.
+	Cost *UsageCostEvidence
 }
 
 // Validate enforces invocation evidence normalization fields.
@@ -80,7 +160,7 @@ func (e InvocationOutcomeEvidence) Validate() error {
 	if !inStringSet(e.Modality, []string{"stt", "llm", "tts", "external"}) {
 		return fmt.Errorf("invalid invocation modality: %s", e.Modality)
 	}
-	if !inStringSet(e.OutcomeClass, []string{"success", "timeout", "overload", "blocked", "infrastructure_failure", "cancelled"}) {
+	if !inStringSet(e.OutcomeClass, []string{"success", "timeout", "overload", "blocked", "infrastructure_failure", "cancelled", "quality_failure"}) {
 		return fmt.Errorf("invalid invocation outcome_class: %s", e.OutcomeClass)
 	}
 	if !inStringSet(e.RetryDecision, []string{"none", "retry", "provider_switch", "fallback"}) {
@@ -98,6 +178,64 @@ func (e InvocationOutcomeEvidence) Validate() error {
 	if e.TotalInvocationLatencyMS < e.FinalAttemptLatencyMS {
 		return fmt.Errorf("invocation total_invocation_latency_ms must be >= final_attempt_latency_ms")
 	}
+	if e.LengthCapped && e.OutcomeClass != "success" {
+		return fmt.Errorf("invocation length_capped requires outcome_class=success")
+	}
+	if e.FirstChunkLatencyMS < 0 {
+		return fmt.Errorf("invocation first_chunk_latency_ms must be >=0")
+	}
+	if e.CircuitState != "" && !inStringSet(e.CircuitState, []string{"closed", "open", "half_open"}) {
+		return fmt.Errorf("invalid invocation circuit_state: %s", e.CircuitState)
+	}
+	if e.EndpointingEnforcement != "" && !inStringSet(e.EndpointingEnforcement, []string{"provider", "vad_emulated"}) {
+		return fmt.Errorf("invalid invocation endpointing_enforcement: %s", e.EndpointingEnforcement)
+	}
+	if e.EndpointKind != "" && e.EndpointingEnforcement != "vad_emulated" {
+		return fmt.Errorf("invocation endpoint_kind requires endpointing_enforcement=vad_emulated")
+	}
+	if e.Cost != nil {
+		if err := e.Cost.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := validateSessionAffinity(e.Modality, e.SessionAffinity, e.ProviderSessionIDHash); err != nil {
+		return err
+	}
+	return validateSamplingSeed(e.Modality, e.SamplingSeed, e.SamplingSeedStatus)
+}
+
+func validateSessionAffinity(modality string, affinity string, providerSessionIDHash string) error {
+	if affinity == "" {
+		if providerSessionIDHash != "" {
+			return fmt.Errorf("provider_session_id_hash requires session_affinity")
+		}
+		return nil
+	}
+	if modality != "llm" {
+		return fmt.Errorf("session_affinity is only valid for llm invocations")
+	}
+	if !inStringSet(affinity, []string{"reused", "established", "stateless", "stateless_fallback"}) {
+		return fmt.Errorf("invalid session_affinity: %s", affinity)
+	}
+	if (affinity == "reused" || affinity == "established") && providerSessionIDHash == "" {
+		return fmt.Errorf("session_affinity=%s requires provider_session_id_hash", affinity)
+	}
+	return nil
+}
+
+func validateSamplingSeed(modality string, seed int64, status string) error {
+	if status == "" {
+		if seed != 0 {
+			return fmt.Errorf("sampling_seed requires sampling_seed_status")
+		}
+		return nil
+	}
+	if modality != "llm" {
+		return fmt.Errorf("sampling_seed_status is only valid for llm invocations")
+	}
+	if !inStringSet(status, []string{"applied", "ignored", "unsupported"}) {
+		return fmt.Errorf("invalid sampling_seed_status: %s", status)
+	}
 	return nil
 }
 
@@ -115,11 +253,47 @@ type ProviderAttemptEvidence struct {
 	Retryable            bool
 	RetryDecision        string
 	AttemptLatencyMS     int64
+	BackoffMS            int64
 	TransportSequence    int64
 	RuntimeSequence      int64
 	AuthorityEpoch       int64
 	RuntimeTimestampMS   int64
 	WallClockTimestampMS int64
+	// InputTokens/OutputTokens record provider token usage when the adapter reports it;
+	// UsageTruncated marks usage counted up to a cancel-driven stream abort.
+	InputTokens    int64
+	OutputTokens   int64
+	UsageTruncated bool
+	// AudioInputMS/AudioOutputMS record audio the provider consumed or produced when the
+	// adapter reports it; EstimatedCostUSD prices the attempt's usage when the runtime
+	// accounts cost.
+	AudioInputMS     int64
+	AudioOutputMS    int64
+	EstimatedCostUSD float64
+	// SessionAffinity and ProviderSessionIDHash record the LLM provider-session decision.
+	SessionAffinity       string
+	ProviderSessionIDHash string
+	// LengthCapped marks the successful final attempt whose output stopped at a length cap.
+	LengthCapped bool
+	// FirstChunkLatencyMS is the attempt's streaming first-chunk latency when reported.
+	FirstChunkLatencyMS int64
+	// SamplingSeed and SamplingSeedStatus record the attempt's LLM sampling seed handling.
+	SamplingSeed       int64
+	SamplingSeedStatus string
+	// TurnBudgetRemainingMS, BudgetElapsedMS, and DeadlineMS record the attempt deadline
+	// derived from the turn latency budget; BudgetExhausted marks the attempt after which a
+	// futile retry or provider switch was skipped. All zero when the turn is unbudgeted.
+	TurnBudgetRemainingMS int64
+	BudgetElapsedMS       int64
+	DeadlineMS            int64
+	BudgetExhausted       bool
+	// CircuitState is the circuit breaker state the attempt was admitted under (closed or
+	// half_open); CircuitTripped marks the failed attempt that opened the circuit. Empty when
+	// circuit breaking is off.
+	CircuitState   string
+	CircuitTripped bool
+	// WarmStandby marks an attempt issued over the provider's held warm-standby connection.
+	WarmStandby bool
 }
 
 // Validate enforces per-attempt evidence invariants.
@@ -133,7 +307,7 @@ func (e ProviderAttemptEvidence) Validate() error {
 	if !inStringSet(e.Modality, []string{"stt", "llm", "tts", "external"}) {
 		return fmt.Errorf("invalid provider attempt modality: %s", e.Modality)
 	}
-	if !inStringSet(e.OutcomeClass, []string{"success", "timeout", "overload", "blocked", "infrastructure_failure", "cancelled"}) {
+	if !inStringSet(e.OutcomeClass, []string{"success", "timeout", "overload", "blocked", "infrastructure_failure", "cancelled", "quality_failure"}) {
 		return fmt.Errorf("invalid provider attempt outcome_class: %s", e.OutcomeClass)
 	}
 	if !inStringSet(e.RetryDecision, []string{"none", "retry", "provider_switch", "fallback"}) {
@@ -142,6 +316,42 @@ func (e ProviderAttemptEvidence) Validate() error {
 	if e.AttemptLatencyMS < 0 {
 		return fmt.Errorf("provider attempt latency_ms must be >=0")
 	}
+	if e.BackoffMS < 0 {
+		return fmt.Errorf("provider attempt backoff_ms must be >=0")
+	}
+	if e.InputTokens < 0 || e.OutputTokens < 0 {
+		return fmt.Errorf("provider attempt token usage must be >=0")
+	}
+	if e.UsageTruncated && e.OutcomeClass != "cancelled" {
+		return fmt.Errorf("provider attempt truncated usage requires outcome_class=cancelled")
+	}
+	if e.AudioInputMS < 0 || e.AudioOutputMS < 0 || e.EstimatedCostUSD < 0 {
+		return fmt.Errorf("provider attempt audio usage and estimated cost must be >=0")
+	}
+	if e.LengthCapped && e.OutcomeClass != "success" {
+		return fmt.Errorf("provider attempt length_capped requires outcome_class=success")
+	}
+	if e.FirstChunkLatencyMS < 0 {
+		return fmt.Errorf("provider attempt first_chunk_latency_ms must be >=0")
+	}
+	if e.TurnBudgetRemainingMS < 0 || e.BudgetElapsedMS < 0 || e.DeadlineMS < 0 {
+		return fmt.Errorf("provider attempt budget fields must be >=0")
+	}
+	if e.DeadlineMS > e.TurnBudgetRemainingMS {
+		return fmt.Errorf("provider attempt deadline_ms must not exceed turn_budget_remaining_ms")
+	}
+	if e.CircuitState != "" && !inStringSet(e.CircuitState, []string{"closed", "half_open"}) {
+		return fmt.Errorf("invalid provider attempt circuit_state: %s", e.CircuitState)
+	}
+	if e.CircuitTripped && (e.CircuitState == "" || inStringSet(e.OutcomeClass, []string{"success", "blocked", "cancelled"})) {
+		return fmt.Errorf("provider attempt circuit_tripped requires a circuit_state and a failed outcome_class")
+	}
+	if err := validateSessionAffinity(e.Modality, e.SessionAffinity, e.ProviderSessionIDHash); err != nil {
+		return err
+	}
+	if err := validateSamplingSeed(e.Modality, e.SamplingSeed, e.SamplingSeedStatus); err != nil {
+		return err
+	}
 	if e.Attempt < 1 {
 		return fmt.Errorf("provider attempt must be >=1")
 	}
@@ -270,14 +480,25 @@ func (b BaselineEvidence) ValidateCompleteness() error {
 	if b.AuthorityEpoch < 0 {
 		return fmt.Errorf("authority epoch must be >= 0")
 	}
-	if b.TerminalOutcome != "commit" && b.TerminalOutcome != "abort" {
-		return fmt.Errorf("terminal outcome must be commit or abort")
+	if b.TerminalOutcome != "commit" && b.TerminalOutcome != "abort" && b.TerminalOutcome != "fallback" {
+		return fmt.Errorf("terminal outcome must be commit, abort, or fallback")
 	}
 	if !b.CloseEmitted {
 		return fmt.Errorf("close marker is required")
 	}
-	if b.TerminalOutcome == "abort" && b.TerminalReason == "" {
-		return fmt.Errorf("terminal reason is required for abort outcomes")
+	if (b.TerminalOutcome == "abort" || b.TerminalOutcome == "fallback") && b.TerminalReason == "" {
+		return fmt.Errorf("terminal reason is required for %s outcomes", b.TerminalOutcome)
+	}
+	if (b.TerminalOutcome == "fallback") != (b.FallbackUtterance != nil) {
+		return fmt.Errorf("fallback utterance evidence is required exactly when terminal outcome is fallback")
+	}
+	if b.FallbackUtterance != nil {
+		if err := b.FallbackUtterance.Validate(); err != nil {
+			return err
+		}
+		if b.FallbackUtterance.Reason != b.TerminalReason {
+			return fmt.Errorf("fallback utterance reason must match terminal reason")
+		}
 	}
 	if b.CancelAcceptedAtMS != nil {
 		if b.CancelFenceAppliedAtMS == nil {
@@ -287,6 +508,34 @@ func (b BaselineEvidence) ValidateCompleteness() error {
 			return fmt.Errorf("cancel_sent_at is required when cancel was accepted")
 		}
 	}
+	if b.Trigger != nil {
+		if err := b.Trigger.Validate(); err != nil {
+			return err
+		}
+	}
+	if b.SLA != nil {
+		if err := b.SLA.Validate(); err != nil {
+			return err
+		}
+	}
+	if b.Cost != nil {
+		if err := b.Cost.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, upkeep := range b.StandbyUpkeep {
+		if err := upkeep.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, signal := range b.ControlSignals {
+		if err := signal.Validate(); err != nil {
+			return err
+		}
+	}
+	if b.FirstAudioPlayedAtMS != nil && (b.FirstOutputAtMS == nil || *b.FirstAudioPlayedAtMS < *b.FirstOutputAtMS) {
+		return fmt.Errorf("first_audio_played_at requires first_output_at and cannot precede it")
+	}
 	return nil
 }
 
@@ -336,6 +585,42 @@ type DetailAppendResult struct {
 	DroppedDetailCount int
 }
 
+// NodeCacheEvidence records a deterministic node cache lookup so replay can explain
+// why a node (and any provider behind it) was not executed.
+type NodeCacheEvidence struct {
+	SessionID       string
+	TurnID          string
+	PipelineVersion string
+	EventID         string
+	NodeID          string
+	// PlanHash is the resolved turn plan the lookup ran under; it is context only and not part
+	// of the cache key, which InputHash and CacheKey record.
+	PlanHash             string
+	InputHash            string
+	CacheKey             string
+	Hit                  bool
+	SourceEventID        string
+	RuntimeTimestampMS   int64
+	WallClockTimestampMS int64
+}
+
+// Validate enforces node cache evidence invariants.
+func (e NodeCacheEvidence) Validate() error {
+	if e.SessionID == "" || e.PipelineVersion == "" || e.EventID == "" {
+		return fmt.Errorf("session_id, pipeline_version, and event_id are required")
+	}
+	if e.NodeID == "" || e.InputHash == "" || e.CacheKey == "" {
+		return fmt.Errorf("node_id, input_hash, and cache_key are required")
+	}
+	if e.Hit && e.SourceEventID == "" {
+		return fmt.Errorf("source_event_id is required for cache hits")
+	}
+	if e.RuntimeTimestampMS < 0 || e.WallClockTimestampMS < 0 {
+		return fmt.Errorf("node cache timestamps must be >=0")
+	}
+	return nil
+}
+
 // CompletenessReport summarizes accepted-turn baseline coverage.
 type CompletenessReport struct {
 	TotalAcceptedTurns        int
@@ -352,6 +637,11 @@ type Recorder struct {
 	detailEntries   []DetailEvent
 	attemptEntries  []ProviderAttemptEvidence
 	snapshotEntries []InvocationSnapshotEvidence
+	cacheEntries    []NodeCacheEvidence
+	enhanceEntries  []AudioEnhancementEvidence
+	summaryEntries  []SessionSummaryEvidence
+	queueEntries    []QueueEstimateEvidence
+	enrichEntries   []SessionEnrichmentEvidence
 	droppedDetails  int
 	downgradeByTurn map[string]bool
 }
@@ -370,6 +660,21 @@ func NewRecorder(cfg StageAConfig) Recorder {
 	if cfg.InvocationSnapshotCap < 1 {
 		cfg.InvocationSnapshotCap = 1024
 	}
+	if cfg.NodeCacheCapacity < 1 {
+		cfg.NodeCacheCapacity = 1024
+	}
+	if cfg.AudioEnhancementCapacity < 1 {
+		cfg.AudioEnhancementCapacity = 1024
+	}
+	if cfg.SessionSummaryCapacity < 1 {
+		cfg.SessionSummaryCapacity = 256
+	}
+	if cfg.QueueEstimateCapacity < 1 {
+		cfg.QueueEstimateCapacity = 256
+	}
+	if cfg.SessionEnrichmentCap < 1 {
+		cfg.SessionEnrichmentCap = 256
+	}
 	return Recorder{
 		cfg:             cfg,
 		downgradeByTurn: make(map[string]bool),
@@ -389,6 +694,9 @@ func (r *Recorder) AppendBaseline(evidence BaselineEvidence) error {
 		return ErrBaselineCapacityExhausted
 	}
 	r.baselineEntries = append(r.baselineEntries, evidence)
+	if r.cfg.DecisionIndex != nil {
+		return r.cfg.DecisionIndex.Record(evidence.DecisionOutcomes...)
+	}
 	return nil
 }
 
@@ -433,6 +741,22 @@ func (r *Recorder) AppendInvocationSnapshot(snapshot InvocationSnapshotEvidence)
 	return nil
 }
 
+// AppendNodeCacheEvidence appends deterministic node cache lookup evidence.
+func (r *Recorder) AppendNodeCacheEvidence(evidence NodeCacheEvidence) error {
+	if err := evidence.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.cacheEntries) >= r.cfg.NodeCacheCapacity {
+		return ErrNodeCacheCapacityExhausted
+	}
+	r.cacheEntries = append(r.cacheEntries, evidence)
+	return nil
+}
+
 // AppendDetail appends best-effort Stage-A detail and deterministically drops on overflow.
 func (r *Recorder) AppendDetail(detail DetailEvent) (DetailAppendResult, error) {
 	if detail.SessionID == "" || detail.PipelineVersion == "" || detail.EventID == "" {
@@ -521,6 +845,57 @@ func fallbackReason(reason string) string {
 	return reason
 }
 
+// RecordFirstAudioPlayed sets the acknowledged first-audio playback time on the baseline entry
+// for a turn. Playback acknowledgments arrive after the turn's baseline was appended; the first
+// recorded time is kept.
+func (r *Recorder) RecordFirstAudioPlayed(sessionID string, turnID string, playedAtMS int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.baselineEntries) - 1; i >= 0; i-- {
+		entry := &r.baselineEntries[i]
+		if entry.SessionID != sessionID || entry.TurnID != turnID {
+			continue
+		}
+		if entry.FirstAudioPlayedAtMS != nil {
+			return nil
+		}
+		if entry.FirstOutputAtMS == nil || playedAtMS < *entry.FirstOutputAtMS {
+			return fmt.Errorf("first audio for turn %s cannot precede its first output", turnID)
+		}
+		playedAt := playedAtMS
+		entry.FirstAudioPlayedAtMS = &playedAt
+		return nil
+	}
+	return fmt.Errorf("no baseline entry for session %s turn %s", sessionID, turnID)
+}
+
+// RecordPlaybackCancelled sets when and why the transport cut off a turn's reply playback on
+// its baseline entry. The first recorded cancel is kept.
+func (r *Recorder) RecordPlaybackCancelled(sessionID string, turnID string, cancelledAtMS int64, reason string) error {
+	if reason == "" {
+		return fmt.Errorf("playback cancel reason is required")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.baselineEntries) - 1; i >= 0; i-- {
+		entry := &r.baselineEntries[i]
+		if entry.SessionID != sessionID || entry.TurnID != turnID {
+			continue
+		}
+		if entry.PlaybackCancelledAtMS != nil {
+			return nil
+		}
+		if entry.FirstOutputAtMS == nil || cancelledAtMS < *entry.FirstOutputAtMS {
+			return fmt.Errorf("playback cancel for turn %s cannot precede its first output", turnID)
+		}
+		cancelledAt := cancelledAtMS
+		entry.PlaybackCancelledAtMS = &cancelledAt
+		entry.PlaybackCancelReason = reason
+		return nil
+	}
+	return fmt.Errorf("no baseline entry for session %s turn %s", sessionID, turnID)
+}
+
 // BaselineEntries returns a stable copy of baseline entries.
 func (r *Recorder) BaselineEntries() []BaselineEvidence {
 	r.mu.Lock()
@@ -601,6 +976,15 @@ func (r *Recorder) InvocationSnapshotEntriesForTurn(sessionID, turnID string) []
 	return filtered
 }
 
+// NodeCacheEntries returns a stable copy of node cache evidence.
+func (r *Recorder) NodeCacheEntries() []NodeCacheEvidence {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]NodeCacheEvidence, len(r.cacheEntries))
+	copy(out, r.cacheEntries)
+	return out
+}
+
 // DroppedDetailCount reports deterministic detail drop count.
 func (r *Recorder) DroppedDetailCount() int {
 	r.mu.Lock()
@@ -608,6 +992,64 @@ func (r *Recorder) DroppedDetailCount() int {
 	return r.droppedDetails
 }
 
+// StreamOccupancy is one Stage-A stream's appended entries against its capacity.
+type StreamOccupancy struct {
+	Used     int `json:"used"`
+	Capacity int `json:"capacity"`
+}
+
+func (o StreamOccupancy) merge(other StreamOccupancy) StreamOccupancy {
+	return StreamOccupancy{Used: o.Used + other.Used, Capacity: o.Capacity + other.Capacity}
+}
+
+// Occupancy reports how full each bounded Stage-A stream is.
+type Occupancy struct {
+	Baseline            StreamOccupancy `json:"baseline"`
+	Detail              StreamOccupancy `json:"detail"`
+	ProviderAttempts    StreamOccupancy `json:"provider_attempts"`
+	InvocationSnapshots StreamOccupancy `json:"invocation_snapshots"`
+	NodeCache           StreamOccupancy `json:"node_cache"`
+	AudioEnhancement    StreamOccupancy `json:"audio_enhancement"`
+	SessionSummary      StreamOccupancy `json:"session_summary"`
+	QueueEstimate       StreamOccupancy `json:"queue_estimate"`
+	SessionEnrichment   StreamOccupancy `json:"session_enrichment"`
+	DroppedDetails      int             `json:"dropped_details"`
+}
+
+// Merge sums two occupancy reports, for example across the recorders of live sessions.
+func (o Occupancy) Merge(other Occupancy) Occupancy {
+	return Occupancy{
+		Baseline:            o.Baseline.merge(other.Baseline),
+		Detail:              o.Detail.merge(other.Detail),
+		ProviderAttempts:    o.ProviderAttempts.merge(other.ProviderAttempts),
+		InvocationSnapshots: o.InvocationSnapshots.merge(other.InvocationSnapshots),
+		NodeCache:           o.NodeCache.merge(other.NodeCache),
+		AudioEnhancement:    o.AudioEnhancement.merge(other.AudioEnhancement),
+		SessionSummary:      o.SessionSummary.merge(other.SessionSummary),
+		QueueEstimate:       o.QueueEstimate.merge(other.QueueEstimate),
+		SessionEnrichment:   o.SessionEnrichment.merge(other.SessionEnrichment),
+		DroppedDetails:      o.DroppedDetails + other.DroppedDetails,
+	}
+}
+
+// Occupancy returns a point-in-time view of Stage-A stream usage.
+func (r *Recorder) Occupancy() Occupancy {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Occupancy{
+		Baseline:            StreamOccupancy{Used: len(r.baselineEntries), Capacity: r.cfg.BaselineCapacity},
+		Detail:              StreamOccupancy{Used: len(r.detailEntries), Capacity: r.cfg.DetailCapacity},
+		ProviderAttempts:    StreamOccupancy{Used: len(r.attemptEntries), Capacity: r.cfg.AttemptCapacity},
+		InvocationSnapshots: StreamOccupancy{Used: len(r.snapshotEntries), Capacity: r.cfg.InvocationSnapshotCap},
+		NodeCache:           StreamOccupancy{Used: len(r.cacheEntries), Capacity: r.cfg.NodeCacheCapacity},
+		AudioEnhancement:    StreamOccupancy{Used: len(r.enhanceEntries), Capacity: r.cfg.AudioEnhancementCapacity},
+		SessionSummary:      StreamOccupancy{Used: len(r.summaryEntries), Capacity: r.cfg.SessionSummaryCapacity},
+		QueueEstimate:       StreamOccupancy{Used: len(r.queueEntries), Capacity: r.cfg.QueueEstimateCapacity},
+		SessionEnrichment:   StreamOccupancy{Used: len(r.enrichEntries), Capacity: r.cfg.SessionEnrichmentCap},
+		DroppedDetails:      r.droppedDetails,
+	}
+}
+
 // BaselineCompleteness computes accepted-turn L0 completeness ratio.
 func BaselineCompleteness(entries []BaselineEvidence) CompletenessReport {
 	report := CompletenessReport{}
@@ -673,6 +1115,14 @@ func InvocationOutcomesFromProviderAttempts(attempts []ProviderAttemptEvidence)
 			AttemptCount:             len(group),
 			FinalAttemptLatencyMS:    deriveFinalAttemptLatencyMS(group),
 			TotalInvocationLatencyMS: deriveTotalInvocationLatencyMS(group),
+			SessionAffinity:          final.SessionAffinity,
+			ProviderSessionIDHash:    final.ProviderSessionIDHash,
+			LengthCapped:             final.LengthCapped,
+			FirstChunkLatencyMS:      final.FirstChunkLatencyMS,
+			SamplingSeed:             final.SamplingSeed,
+			SamplingSeedStatus:       final.SamplingSeedStatus,
+			CircuitState:             final.CircuitState,
+			WarmStandby:              final.WarmStandby,
 		}
 		if err := outcome.Validate(); err != nil {
 			return nil, err
@@ -6,6 +6,7 @@ import (
 
 	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/decisionindex"
 )
 
 func TestAppendBaselineStageACapacity(t *testing.T) {
@@ -20,6 +21,116 @@ func TestAppendBaselineStageACapacity(t *testing.T) {
 	}
 }
 
+func TestAppendBaselineFeedsDecisionIndex(t *testing.T) {
+	t.Parallel()
+
+	index := decisionindex.New(decisionindex.Config{Capacity: 8})
+	recorder := NewRecorder(StageAConfig{BaselineCapacity: 4, DecisionIndex: index})
+	for _, turnID := range []string{"turn-a", "turn-b"} {
+		if err := recorder.AppendBaseline(minimalBaseline(turnID)); err != nil {
+			t.Fatalf("unexpected append error: %v", err)
+		}
+	}
+	decisions := index.Query(decisionindex.Query{TurnID: "turn-b"})
+	if len(decisions) != 1 || decisions[0].TurnID != "turn-b" {
+		t.Fatalf("expected indexed decision for turn-b, got %+v", decisions)
+	}
+}
+
+func TestRecorderOccupancy(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewRecorder(StageAConfig{BaselineCapacity: 4, DetailCapacity: 1})
+	if err := recorder.AppendBaseline(minimalBaseline("turn-a")); err != nil {
+		t.Fatalf("unexpected append error: %v", err)
+	}
+	for _, eventID := range []string{"evt-1", "evt-2"} {
+		if _, err := recorder.AppendDetail(DetailEvent{SessionID: "sess-1", TurnID: "turn-a", PipelineVersion: "pipeline-v1", EventID: eventID}); err != nil {
+			t.Fatalf("unexpected detail append error: %v", err)
+		}
+	}
+	occupancy := recorder.Occupancy()
+	if occupancy.Baseline != (StreamOccupancy{Used: 1, Capacity: 4}) || occupancy.Detail != (StreamOccupancy{Used: 1, Capacity: 1}) || occupancy.DroppedDetails != 1 {
+		t.Fatalf("unexpected occupancy %+v", occupancy)
+	}
+	if occupancy.ProviderAttempts.Capacity != 1024 {
+		t.Fatalf("expected default attempt capacity, got %+v", occupancy.ProviderAttempts)
+	}
+	merged := occupancy.Merge(occupancy)
+	if merged.Baseline != (StreamOccupancy{Used: 2, Capacity: 8}) || merged.DroppedDetails != 2 {
+		t.Fatalf("unexpected merged occupancy %+v", merged)
+	}
+}
+
+func TestRecordFirstAudioPlayedAnnotatesBaseline(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewRecorder(StageAConfig{BaselineCapacity: 4})
+	if err := recorder.AppendBaseline(minimalBaseline("turn-a")); err != nil {
+		t.Fatalf("unexpected append error: %v", err)
+	}
+	tests := []struct {
+		name       string
+		sessionID  string
+		turnID     string
+		playedAtMS int64
+		wantErr    bool
+	}{
+		{name: "unknown turn", sessionID: "sess-1", turnID: "turn-z", playedAtMS: 400, wantErr: true},
+		{name: "before first output", sessionID: "sess-1", turnID: "turn-a", playedAtMS: 250, wantErr: true},
+		{name: "first ack", sessionID: "sess-1", turnID: "turn-a", playedAtMS: 420},
+		{name: "later ack keeps first", sessionID: "sess-1", turnID: "turn-a", playedAtMS: 900},
+	}
+	for _, tc := range tests {
+		if err := recorder.RecordFirstAudioPlayed(tc.sessionID, tc.turnID, tc.playedAtMS); (err != nil) != tc.wantErr {
+			t.Fatalf("%s: expected error=%v, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+	entry := recorder.BaselineEntries()[0]
+	if entry.FirstAudioPlayedAtMS == nil || *entry.FirstAudioPlayedAtMS != 420 {
+		t.Fatalf("expected first audio at 420, got %v", entry.FirstAudioPlayedAtMS)
+	}
+	if err := entry.ValidateCompleteness(); err != nil {
+		t.Fatalf("expected annotated baseline to stay complete, got %v", err)
+	}
+	early := int64(10)
+	entry.FirstAudioPlayedAtMS = &early
+	if err := entry.ValidateCompleteness(); err == nil {
+		t.Fatalf("expected first audio before first output to fail completeness")
+	}
+}
+
+func TestRecordPlaybackCancelledAnnotatesBaseline(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewRecorder(StageAConfig{BaselineCapacity: 4})
+	if err := recorder.AppendBaseline(minimalBaseline("turn-a")); err != nil {
+		t.Fatalf("unexpected append error: %v", err)
+	}
+	tests := []struct {
+		name          string
+		turnID        string
+		cancelledAtMS int64
+		reason        string
+		wantErr       bool
+	}{
+		{name: "unknown turn", turnID: "turn-z", cancelledAtMS: 500, reason: "barge_in", wantErr: true},
+		{name: "missing reason", turnID: "turn-a", cancelledAtMS: 500, wantErr: true},
+		{name: "before first output", turnID: "turn-a", cancelledAtMS: 250, reason: "barge_in", wantErr: true},
+		{name: "barge-in", turnID: "turn-a", cancelledAtMS: 500, reason: "barge_in"},
+		{name: "later cancel keeps first", turnID: "turn-a", cancelledAtMS: 900, reason: "hangup"},
+	}
+	for _, tc := range tests {
+		if err := recorder.RecordPlaybackCancelled("sess-1", tc.turnID, tc.cancelledAtMS, tc.reason); (err != nil) != tc.wantErr {
+			t.Fatalf("%s: expected error=%v, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+	entry := recorder.BaselineEntries()[0]
+	if entry.PlaybackCancelledAtMS == nil || *entry.PlaybackCancelledAtMS != 500 || entry.PlaybackCancelReason != "barge_in" {
+		t.Fatalf("expected barge-in cancel at 500, got %v %q", entry.PlaybackCancelledAtMS, entry.PlaybackCancelReason)
+	}
+}
+
 func TestAppendDetailOverflowEmitsDowngradeOncePerTurn(t *testing.T) {
 	t.Parallel()
 
@@ -188,6 +299,32 @@ func TestValidateCompletenessInvocationOutcomeEvidence(t *testing.T) {
 	if err := invalidLatency.ValidateCompleteness(); err == nil {
 		t.Fatalf("expected negative invocation latency evidence to fail completeness")
 	}
+
+	endpointingCases := []struct {
+		name        string
+		enforcement string
+		kind        string
+		wantErr     bool
+	}{
+		{name: "provider", enforcement: "provider"},
+		{name: "vad emulated", enforcement: "vad_emulated", kind: "utterance_end"},
+		{name: "unknown enforcement", enforcement: "client", wantErr: true},
+		{name: "kind without emulation", enforcement: "provider", kind: "utterance_end", wantErr: true},
+	}
+	for _, tc := range endpointingCases {
+		endpointing := baseline
+		evidence := baseline.InvocationOutcomes[0]
+		evidence.EndpointingEnforcement = tc.enforcement
+		evidence.EndpointKind = tc.kind
+		endpointing.InvocationOutcomes = []InvocationOutcomeEvidence{evidence}
+		err := endpointing.ValidateCompleteness()
+		if tc.wantErr && err == nil {
+			t.Fatalf("%s: expected endpointing evidence to fail completeness", tc.name)
+		}
+		if !tc.wantErr && err != nil {
+			t.Fatalf("%s: expected valid endpointing evidence, got %v", tc.name, err)
+		}
+	}
 }
 
 func TestAppendProviderInvocationAttempts(t *testing.T) {
@@ -285,6 +422,128 @@ func TestProviderAttemptEvidenceValidate(t *testing.T) {
 	if err := invalidLatency.Validate(); err == nil {
 		t.Fatalf("expected negative provider attempt latency to fail")
 	}
+
+	truncatedSuccess := valid
+	truncatedSuccess.OutputTokens = 4
+	truncatedSuccess.UsageTruncated = true
+	if err := truncatedSuccess.Validate(); err == nil {
+		t.Fatalf("expected truncated usage on non-cancelled attempt to fail")
+	}
+
+	truncatedCancel := truncatedSuccess
+	truncatedCancel.OutcomeClass = "cancelled"
+	if err := truncatedCancel.Validate(); err != nil {
+		t.Fatalf("expected cancelled attempt with truncated usage to be valid, got %v", err)
+	}
+
+	lengthCapped := valid
+	lengthCapped.LengthCapped = true
+	if err := lengthCapped.Validate(); err != nil {
+		t.Fatalf("expected length-capped successful attempt to be valid, got %v", err)
+	}
+	lengthCapped.OutcomeClass = "timeout"
+	if err := lengthCapped.Validate(); err == nil {
+		t.Fatalf("expected length_capped on non-success attempt to fail")
+	}
+
+	negativeFirstChunk := valid
+	negativeFirstChunk.FirstChunkLatencyMS = -1
+	if err := negativeFirstChunk.Validate(); err == nil {
+		t.Fatalf("expected negative first_chunk_latency_ms to fail")
+	}
+
+	affinityCases := []struct {
+		name     string
+		modality string
+		affinity string
+		hash     string
+		wantErr  bool
+	}{
+		{name: "reused with hash", modality: "llm", affinity: "reused", hash: "sha256:abc"},
+		{name: "stateless without hash", modality: "llm", affinity: "stateless"},
+		{name: "reused without hash", modality: "llm", affinity: "reused", wantErr: true},
+		{name: "hash without affinity", modality: "llm", hash: "sha256:abc", wantErr: true},
+		{name: "affinity on tts", modality: "tts", affinity: "stateless", wantErr: true},
+		{name: "unknown affinity", modality: "llm", affinity: "sticky", wantErr: true},
+	}
+	for _, tc := range affinityCases {
+		evidence := valid
+		evidence.Modality = tc.modality
+		evidence.SessionAffinity = tc.affinity
+		evidence.ProviderSessionIDHash = tc.hash
+		if err := evidence.Validate(); (err != nil) != tc.wantErr {
+			t.Fatalf("%s: expected error=%v, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+
+	seedCases := []struct {
+		name     string
+		modality string
+		seed     int64
+		status   string
+		wantErr  bool
+	}{
+		{name: "applied seed", modality: "llm", seed: 42, status: "applied"},
+		{name: "ignored seed", modality: "llm", seed: 42, status: "ignored"},
+		{name: "seed without status", modality: "llm", seed: 42, wantErr: true},
+		{name: "status on stt", modality: "stt", seed: 42, status: "applied", wantErr: true},
+		{name: "unknown status", modality: "llm", seed: 42, status: "partial", wantErr: true},
+	}
+	for _, tc := range seedCases {
+		evidence := valid
+		evidence.Modality = tc.modality
+		evidence.SamplingSeed = tc.seed
+		evidence.SamplingSeedStatus = tc.status
+		if err := evidence.Validate(); (err != nil) != tc.wantErr {
+			t.Fatalf("%s: expected error=%v, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+
+	budgetCases := []struct {
+		name        string
+		remainingMS int64
+		elapsedMS   int64
+		deadlineMS  int64
+		wantErr     bool
+	}{
+		{name: "unbudgeted attempt"},
+		{name: "budgeted attempt", remainingMS: 1000, elapsedMS: 400, deadlineMS: 600},
+		{name: "negative elapsed", remainingMS: 1000, elapsedMS: -1, deadlineMS: 600, wantErr: true},
+		{name: "deadline beyond budget", remainingMS: 500, deadlineMS: 600, wantErr: true},
+	}
+	for _, tc := range budgetCases {
+		evidence := valid
+		evidence.TurnBudgetRemainingMS = tc.remainingMS
+		evidence.BudgetElapsedMS = tc.elapsedMS
+		evidence.DeadlineMS = tc.deadlineMS
+		if err := evidence.Validate(); (err != nil) != tc.wantErr {
+			t.Fatalf("%s: expected error=%v, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+
+	circuitCases := []struct {
+		name    string
+		state   string
+		tripped bool
+		outcome string
+		wantErr bool
+	}{
+		{name: "breaker off", outcome: "success"},
+		{name: "half-open probe", state: "half_open", outcome: "success"},
+		{name: "tripping failure", state: "closed", tripped: true, outcome: "timeout"},
+		{name: "attempt under open circuit", state: "open", outcome: "success", wantErr: true},
+		{name: "tripped without state", tripped: true, outcome: "timeout", wantErr: true},
+		{name: "tripped by success", state: "closed", tripped: true, outcome: "success", wantErr: true},
+	}
+	for _, tc := range circuitCases {
+		evidence := valid
+		evidence.CircuitState = tc.state
+		evidence.CircuitTripped = tc.tripped
+		evidence.OutcomeClass = tc.outcome
+		if err := evidence.Validate(); (err != nil) != tc.wantErr {
+			t.Fatalf("%s: expected error=%v, got %v", tc.name, tc.wantErr, err)
+		}
+	}
 }
 
 func TestProviderAttemptEntriesForTurn(t *testing.T) {
@@ -443,6 +702,7 @@ func TestInvocationOutcomesFromProviderAttempts(t *testing.T) {
 			AuthorityEpoch:       2,
 			RuntimeTimestampMS:   101,
 			WallClockTimestampMS: 101,
+			WarmStandby:          true,
 		},
 		{
 			SessionID:            "sess-1",
@@ -461,6 +721,8 @@ func TestInvocationOutcomesFromProviderAttempts(t *testing.T) {
 			AuthorityEpoch:       2,
 			RuntimeTimestampMS:   102,
 			WallClockTimestampMS: 102,
+			LengthCapped:         true,
+			FirstChunkLatencyMS:  45,
 		},
 	}
 
@@ -478,6 +740,15 @@ func TestInvocationOutcomesFromProviderAttempts(t *testing.T) {
 	if outcomes[1].ProviderID != "stt-b" || outcomes[1].OutcomeClass != "success" || outcomes[1].AttemptCount != 2 {
 		t.Fatalf("expected final-attempt synthesis for pvi-b, got %+v", outcomes[1])
 	}
+	if !outcomes[0].LengthCapped || outcomes[1].LengthCapped {
+		t.Fatalf("expected length_capped carried from the final attempt, got %+v", outcomes)
+	}
+	if outcomes[0].WarmStandby || !outcomes[1].WarmStandby {
+		t.Fatalf("expected warm standby carried from the final attempt, got %+v", outcomes)
+	}
+	if outcomes[0].FirstChunkLatencyMS != 45 || outcomes[1].FirstChunkLatencyMS != 0 {
+		t.Fatalf("expected first-chunk latency carried from the final attempt, got %+v", outcomes)
+	}
 	if outcomes[0].FinalAttemptLatencyMS != 0 || outcomes[0].TotalInvocationLatencyMS != 0 {
 		t.Fatalf("expected single-attempt latency fields to be zero, got %+v", outcomes[0])
 	}
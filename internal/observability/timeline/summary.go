@@ -0,0 +1,76 @@
+package timeline
+
+import (
+	"fmt"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+// SessionSummaryEvidence records the provenance of one derived_summary produced by the session
+// summarization node, so replay can tell which turns were condensed and by which provider.
+type SessionSummaryEvidence struct {
+	SessionID       string
+	TurnID          string
+	PipelineVersion string
+	EventID         string
+	SummaryID       string
+	PayloadClass    eventabi.PayloadClass
+	ProviderID      string
+	// SourceTurnIDs lists the turns condensed into this summary in completion order.
+	SourceTurnIDs []string
+	// PriorSummaryID is the earlier summary folded into this one, empty for the first summary.
+	PriorSummaryID string
+	InputHash      string
+	SummaryHash    string
+	CreatedAtMS    int64
+	ExpiresAtMS    int64
+}
+
+// Validate enforces session summary evidence invariants.
+func (e SessionSummaryEvidence) Validate() error {
+	if e.SessionID == "" || e.PipelineVersion == "" || e.EventID == "" {
+		return fmt.Errorf("session_id, pipeline_version, and event_id are required")
+	}
+	if e.SummaryID == "" || e.ProviderID == "" {
+		return fmt.Errorf("summary_id and provider_id are required")
+	}
+	if e.PayloadClass != eventabi.PayloadDerivedSummary {
+		return fmt.Errorf("session summary payload_class must be %s", eventabi.PayloadDerivedSummary)
+	}
+	if len(e.SourceTurnIDs) == 0 {
+		return fmt.Errorf("session summary source_turn_ids must be non-empty")
+	}
+	if e.InputHash == "" || e.SummaryHash == "" {
+		return fmt.Errorf("session summary input_hash and summary_hash are required")
+	}
+	if e.CreatedAtMS < 0 || e.ExpiresAtMS <= e.CreatedAtMS {
+		return fmt.Errorf("session summary expires_at_ms must be > created_at_ms >= 0")
+	}
+	return nil
+}
+
+// AppendSessionSummaryEvidence appends session summary provenance evidence.
+func (r *Recorder) AppendSessionSummaryEvidence(evidence SessionSummaryEvidence) error {
+	if err := evidence.Validate(); err != nil {
+		return err
+	}
+	evidence.SourceTurnIDs = append([]string(nil), evidence.SourceTurnIDs...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.summaryEntries) >= r.cfg.SessionSummaryCapacity {
+		return ErrSessionSummaryCapacityExhausted
+	}
+	r.summaryEntries = append(r.summaryEntries, evidence)
+	return nil
+}
+
+// SessionSummaryEntries returns a stable copy of session summary evidence.
+func (r *Recorder) SessionSummaryEntries() []SessionSummaryEvidence {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]SessionSummaryEvidence, len(r.summaryEntries))
+	copy(out, r.summaryEntries)
+	return out
+}
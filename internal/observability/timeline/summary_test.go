@@ -0,0 +1,70 @@
+package timeline
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+func testSessionSummaryEvidence(summaryID string) SessionSummaryEvidence {
+	return SessionSummaryEvidence{
+		SessionID:       "sess-summary-1",
+		TurnID:          "turn-4",
+		PipelineVersion: "pipeline-v1",
+		EventID:         "evt-" + summaryID,
+		SummaryID:       summaryID,
+		PayloadClass:    eventabi.PayloadDerivedSummary,
+		ProviderID:      "llm-anthropic",
+		SourceTurnIDs:   []string{"turn-3", "turn-4"},
+		InputHash:       "input-hash",
+		SummaryHash:     "summary-hash",
+		CreatedAtMS:     1_000,
+		ExpiresAtMS:     61_000,
+	}
+}
+
+func TestSessionSummaryEvidenceValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		mutate    func(*SessionSummaryEvidence)
+		shouldErr bool
+	}{
+		{name: "valid", mutate: func(*SessionSummaryEvidence) {}},
+		{name: "missing provider", mutate: func(e *SessionSummaryEvidence) { e.ProviderID = "" }, shouldErr: true},
+		{name: "wrong payload class", mutate: func(e *SessionSummaryEvidence) { e.PayloadClass = eventabi.PayloadTextRaw }, shouldErr: true},
+		{name: "no source turns", mutate: func(e *SessionSummaryEvidence) { e.SourceTurnIDs = nil }, shouldErr: true},
+		{name: "missing summary hash", mutate: func(e *SessionSummaryEvidence) { e.SummaryHash = "" }, shouldErr: true},
+		{name: "expires before creation", mutate: func(e *SessionSummaryEvidence) { e.ExpiresAtMS = e.CreatedAtMS }, shouldErr: true},
+	}
+	for _, tc := range tests {
+		evidence := testSessionSummaryEvidence("summary-1")
+		tc.mutate(&evidence)
+		err := evidence.Validate()
+		if tc.shouldErr && err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestSessionSummaryEvidenceCapacity(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewRecorder(StageAConfig{SessionSummaryCapacity: 1})
+	evidence := testSessionSummaryEvidence("summary-1")
+	if err := recorder.AppendSessionSummaryEvidence(evidence); err != nil {
+		t.Fatalf("unexpected append error: %v", err)
+	}
+	evidence.SourceTurnIDs[0] = "mutated"
+	if err := recorder.AppendSessionSummaryEvidence(testSessionSummaryEvidence("summary-2")); err != ErrSessionSummaryCapacityExhausted {
+		t.Fatalf("expected capacity exhaustion, got %v", err)
+	}
+	entries := recorder.SessionSummaryEntries()
+	if len(entries) != 1 || entries[0].SourceTurnIDs[0] != "turn-3" {
+		t.Fatalf("expected one isolated summary entry, got %+v", entries)
+	}
+}
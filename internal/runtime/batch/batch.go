@@ -0,0 +1,226 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/recording"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/demo"
+)
+
+// SchemaVersion identifies the batch job report artifact layout.
+const SchemaVersion = "rspp-batch-report/v1"
+
+// frameMS is the capture frame size pre-recorded audio is fed in, matching live clients.
+const frameMS = 20
+
+// Job is one scheduled offline run of pre-recorded audio files through the pipeline.
+type Job struct {
+	JobID           string
+	Inputs          []string
+	ArtifactsDir    string
+	TenantID        string
+	PipelineVersion string
+	// MaxCaptureMS splits recordings longer than one capture into consecutive turns.
+	MaxCaptureMS int64
+	Providers    demo.Providers
+	// Clock defaults to time.Now; session time is the clock plus the audio fed so far, so
+	// recordings keep their own duration while provider latency stays measured.
+	Clock func() time.Time
+}
+
+// TurnSummary is one turn produced from an input.
+type TurnSummary struct {
+	TurnID         string `json:"turn_id"`
+	Transcript     string `json:"transcript"`
+	Reply          string `json:"reply"`
+	Committed      bool   `json:"committed"`
+	FallbackReason string `json:"fallback_reason,omitempty"`
+}
+
+// InputResult is the outcome of one input file; Error is set when it could not be processed.
+type InputResult struct {
+	Path             string        `json:"path"`
+	SessionID        string        `json:"session_id"`
+	DurationMS       int64         `json:"duration_ms"`
+	Turns            []TurnSummary `json:"turns"`
+	SessionAudioPath string        `json:"session_audio_path,omitempty"`
+	Error            string        `json:"error,omitempty"`
+}
+
+// Report is the batch job artifact written next to the job baseline.
+type Report struct {
+	SchemaVersion   string        `json:"schema_version"`
+	JobID           string        `json:"job_id"`
+	GeneratedAtUTC  string        `json:"generated_at_utc"`
+	PipelineVersion string        `json:"pipeline_version,omitempty"`
+	BaselinePath    string        `json:"baseline_path"`
+	Turns           int           `json:"turns"`
+	FailedInputs    int           `json:"failed_inputs"`
+	Inputs          []InputResult `json:"inputs"`
+}
+
+// BaselineFileName names the merged job baseline in the artifacts dir.
+func BaselineFileName(jobID string) string {
+	return jobID + "-baseline.json"
+}
+
+// ReportFileName names the batch job report in the artifacts dir.
+func ReportFileName(jobID string) string {
+	return jobID + "-batch-report.json"
+}
+
+// Run processes every input as its own session through the push-to-talk turn path (turn
+// arbiter, provider stages, fallback speech, OR-02 recording) without a live transport.
+// Per-session artifacts go under <artifacts dir>/sessions; the merged baseline of all inputs
+// feeds the same rspp-cli reports and gates as live runs. Unreadable inputs are reported and
+// skipped.
+func Run(job Job) (Report, error) {
+	if job.JobID == "" || job.ArtifactsDir == "" {
+		return Report{}, fmt.Errorf("batch job_id and artifacts_dir are required")
+	}
+	if len(job.Inputs) == 0 {
+		return Report{}, fmt.Errorf("batch job %s has no inputs", job.JobID)
+	}
+	clock := job.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	var fed time.Duration
+	sessionClock := func() time.Time { return clock().Add(fed) }
+
+	report := Report{
+		SchemaVersion:   SchemaVersion,
+		JobID:           job.JobID,
+		PipelineVersion: job.PipelineVersion,
+		BaselinePath:    filepath.Join(job.ArtifactsDir, BaselineFileName(job.JobID)),
+		Inputs:          make([]InputResult, 0, len(job.Inputs)),
+	}
+	entries := make([]timeline.BaselineEvidence, 0)
+	for idx, path := range job.Inputs {
+		result := InputResult{Path: path, SessionID: fmt.Sprintf("%s-%03d", job.JobID, idx+1), Turns: []TurnSummary{}}
+		sessionEntries, err := runInput(job, &result, sessionClock, &fed)
+		if err != nil {
+			result.Error = err.Error()
+			report.FailedInputs++
+		}
+		entries = append(entries, sessionEntries...)
+		report.Turns += len(result.Turns)
+		report.Inputs = append(report.Inputs, result)
+	}
+	if len(entries) == 0 {
+		return Report{}, fmt.Errorf("batch job %s produced no turns", job.JobID)
+	}
+	if err := timeline.WriteBaselineArtifact(report.BaselinePath, entries); err != nil {
+		return Report{}, err
+	}
+	report.GeneratedAtUTC = clock().UTC().Format(time.RFC3339)
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return Report{}, err
+	}
+	if err := os.WriteFile(filepath.Join(job.ArtifactsDir, ReportFileName(job.JobID)), append(data, '\n'), 0o644); err != nil {
+		return Report{}, err
+	}
+	return report, nil
+}
+
+func runInput(job Job, result *InputResult, clock func() time.Time, fed *time.Duration) ([]timeline.BaselineEvidence, error) {
+	file, err := os.Open(result.Path)
+	if err != nil {
+		return nil, err
+	}
+	pcm, sampleRateHz, channels, err := recording.DecodeWAV(file)
+	_ = file.Close()
+	if err != nil {
+		return nil, err
+	}
+	mono := downmix(pcm, channels)
+	result.DurationMS = int64(len(mono)) * 1000 / int64(sampleRateHz)
+
+	session, err := demo.NewSession(demo.SessionConfig{
+		SessionID:       result.SessionID,
+		TenantID:        job.TenantID,
+		PipelineVersion: job.PipelineVersion,
+		SampleRateHz:    sampleRateHz,
+		MaxCaptureMS:    job.MaxCaptureMS,
+		ArtifactsDir:    filepath.Join(job.ArtifactsDir, "sessions"),
+		Clock:           clock,
+	}, job.Providers)
+	if err != nil {
+		return nil, err
+	}
+	record := func(turn *demo.TurnResult) {
+		result.Turns = append(result.Turns, TurnSummary{
+			TurnID:         turn.TurnID,
+			Transcript:     turn.Transcript,
+			Reply:          turn.Reply,
+			Committed:      turn.Committed,
+			FallbackReason: turn.FallbackReason,
+		})
+	}
+
+	if err := session.StartCapture(); err != nil {
+		return nil, err
+	}
+	frame := max(sampleRateHz*frameMS/1000, 1)
+	for offset := 0; offset < len(mono); offset += frame {
+		chunk := mono[offset:min(offset+frame, len(mono))]
+		*fed += time.Duration(len(chunk)) * time.Second / time.Duration(sampleRateHz)
+		turn, err := session.AppendAudio(chunk)
+		if err != nil {
+			return nil, err
+		}
+		if turn != nil {
+			record(turn)
+			if offset+frame >= len(mono) {
+				return finishInput(session, result)
+			}
+			// The capture hit MaxCaptureMS; continue the recording as the next turn.
+			if err := session.StartCapture(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	turn, err := session.EndCapture()
+	if err != nil {
+		return nil, err
+	}
+	if turn != nil {
+		record(turn)
+	}
+	return finishInput(session, result)
+}
+
+func finishInput(session *demo.Session, result *InputResult) ([]timeline.BaselineEvidence, error) {
+	artifacts, err := session.WriteArtifacts()
+	if err != nil {
+		return nil, err
+	}
+	result.SessionAudioPath = artifacts.SessionAudioPath
+	baseline, err := timeline.ReadBaselineArtifact(artifacts.BaselinePath)
+	if err != nil {
+		return nil, err
+	}
+	return baseline.Entries, nil
+}
+
+// downmix averages interleaved channels into mono.
+func downmix(pcm []int16, channels int) []int16 {
+	if channels <= 1 {
+		return pcm
+	}
+	mono := make([]int16, len(pcm)/channels)
+	for idx := range mono {
+		sum := 0
+		for ch := 0; ch < channels; ch++ {
+			sum += int(pcm[idx*channels+ch])
+		}
+		mono[idx] = int16(sum / channels)
+	}
+	return mono
+}
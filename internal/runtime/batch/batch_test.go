@@ -0,0 +1,117 @@
+package batch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/recording"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/demo"
+)
+
+func fixedClock() func() time.Time {
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+}
+
+func writeVoicedWAV(t *testing.T, path string, sampleRateHz int, channels int, durationMS int) {
+	t.Helper()
+	pcm := make([]int16, sampleRateHz*durationMS/1000*channels)
+	for idx := range pcm {
+		pcm[idx] = 4000
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create wav: %v", err)
+	}
+	defer file.Close()
+	if err := (recording.WAVEncoder{}).Encode(file, pcm, sampleRateHz, channels); err != nil {
+		t.Fatalf("encode wav: %v", err)
+	}
+}
+
+func TestRunProcessesRecordingsIntoBaselineEvidence(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	short := filepath.Join(tmp, "short.wav")
+	long := filepath.Join(tmp, "long-stereo.wav")
+	corrupt := filepath.Join(tmp, "corrupt.wav")
+	writeVoicedWAV(t, short, 16000, 1, 500)
+	writeVoicedWAV(t, long, 8000, 2, 2500)
+	if err := os.WriteFile(corrupt, []byte("not audio"), 0o644); err != nil {
+		t.Fatalf("write corrupt: %v", err)
+	}
+
+	report, err := Run(Job{
+		JobID:           "nightly",
+		Inputs:          []string{short, long, corrupt},
+		ArtifactsDir:    filepath.Join(tmp, "out"),
+		PipelineVersion: "pipeline-batch",
+		MaxCaptureMS:    1000,
+		Providers:       demo.SandboxProviders(),
+		Clock:           fixedClock(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected batch error: %v", err)
+	}
+	if len(report.Inputs) != 3 || report.FailedInputs != 1 || report.Inputs[2].Error == "" {
+		t.Fatalf("expected corrupt input reported and skipped, got %+v", report.Inputs)
+	}
+	first := report.Inputs[0]
+	if first.SessionID != "nightly-001" || first.DurationMS != 500 || len(first.Turns) != 1 || !first.Turns[0].Committed || !strings.Contains(first.Turns[0].Transcript, "0.5 seconds") {
+		t.Fatalf("expected one committed turn for the short recording, got %+v", first)
+	}
+	second := report.Inputs[1]
+	if second.DurationMS != 2500 || len(second.Turns) < 2 {
+		t.Fatalf("expected long recording split at max capture into several turns, got %+v", second)
+	}
+
+	baseline, err := timeline.ReadBaselineArtifact(report.BaselinePath)
+	if err != nil {
+		t.Fatalf("unexpected baseline read error: %v", err)
+	}
+	if len(baseline.Entries) != report.Turns || report.Turns != len(first.Turns)+len(second.Turns) {
+		t.Fatalf("expected one baseline entry per turn, got %d entries for %d turns", len(baseline.Entries), report.Turns)
+	}
+	for _, entry := range baseline.Entries {
+		if entry.PipelineVersion != "pipeline-batch" || len(entry.InvocationOutcomes) != 3 || entry.TurnOpenAtMS == nil || entry.FirstOutputAtMS == nil {
+			t.Fatalf("expected live-shaped baseline evidence for batch turns, got %+v", entry)
+		}
+	}
+	if _, err := recording.LoadSessionAudio(first.SessionAudioPath); err != nil {
+		t.Fatalf("expected per-session audio artifact, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "out", ReportFileName("nightly"))); err != nil {
+		t.Fatalf("expected batch report artifact: %v", err)
+	}
+}
+
+func TestRunRejectsInvalidJobs(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	corrupt := filepath.Join(tmp, "corrupt.wav")
+	if err := os.WriteFile(corrupt, []byte("not audio"), 0o644); err != nil {
+		t.Fatalf("write corrupt: %v", err)
+	}
+	tests := []struct {
+		name string
+		job  Job
+	}{
+		{name: "missing job id", job: Job{ArtifactsDir: tmp, Inputs: []string{corrupt}, Providers: demo.SandboxProviders()}},
+		{name: "no inputs", job: Job{JobID: "job", ArtifactsDir: tmp, Providers: demo.SandboxProviders()}},
+		{name: "no processable inputs", job: Job{JobID: "job", ArtifactsDir: tmp, Inputs: []string{corrupt}, Providers: demo.SandboxProviders()}},
+	}
+	for _, tc := range tests {
+		if _, err := Run(tc.job); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
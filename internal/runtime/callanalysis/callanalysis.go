@@ -0,0 +1,265 @@
+package callanalysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/executionpool"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/lanes"
+)
+
+const (
+	// NodeType is the execution node type of the end-of-call analysis stage.
+	NodeType = "end_of_call_analysis"
+	// SchemaVersion identifies the per-session analysis artifact layout.
+	SchemaVersion = "rspp-call-analysis/v1"
+
+	// StageSummary, StageSentiment, and StageDisposition name the analyses in artifact results.
+	StageSummary     = "summary"
+	StageSentiment   = "sentiment"
+	StageDisposition = "disposition"
+
+	// StatusOK and StatusError report per-analysis outcomes; one failed analysis does not
+	// discard the others.
+	StatusOK    = "ok"
+	StatusError = "error"
+
+	defaultQueueCapacity = 64
+)
+
+// Turn is one completed turn of the ended session.
+type Turn struct {
+	TurnID        string `json:"turn_id"`
+	UserText      string `json:"user_text"`
+	AssistantText string `json:"assistant_text"`
+}
+
+// Transcript is the ended session handed to the analysis stage.
+type Transcript struct {
+	SessionID       string
+	TenantID        string
+	PipelineVersion string
+	EndedAtMS       int64
+	Turns           []Turn
+}
+
+func (t Transcript) validate() error {
+	if t.SessionID == "" || t.PipelineVersion == "" {
+		return fmt.Errorf("call analysis session_id and pipeline_version are required")
+	}
+	if t.EndedAtMS < 0 {
+		return fmt.Errorf("call analysis ended_at_ms must be >=0")
+	}
+	return nil
+}
+
+// Sentiment scores the caller's overall sentiment in [-1, 1].
+type Sentiment struct {
+	Label string  `json:"label"`
+	Score float64 `json:"score"`
+}
+
+// Disposition classifies how the call ended (for example resolved, escalated, abandoned).
+type Disposition struct {
+	Label      string  `json:"label"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Analyzers are the configured post-call analyses; a nil analyzer is not run.
+type Analyzers struct {
+	Summarize           func(Transcript) (string, error)
+	ScoreSentiment      func(Transcript) (Sentiment, error)
+	ClassifyDisposition func(Transcript) (Disposition, error)
+}
+
+func (a Analyzers) empty() bool {
+	return a.Summarize == nil && a.ScoreSentiment == nil && a.ClassifyDisposition == nil
+}
+
+// StageResult reports one analysis run.
+type StageResult struct {
+	Stage     string `json:"stage"`
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Artifact is the per-session analysis written after the session ends.
+type Artifact struct {
+	SchemaVersion   string                `json:"schema_version"`
+	SessionID       string                `json:"session_id"`
+	TenantID        string                `json:"tenant_id,omitempty"`
+	PipelineVersion string                `json:"pipeline_version"`
+	Lane            eventabi.Lane         `json:"lane"`
+	QueueKey        string                `json:"queue_key"`
+	PayloadClass    eventabi.PayloadClass `json:"payload_class"`
+	EndedAtMS       int64                 `json:"ended_at_ms"`
+	CompletedAtUTC  string                `json:"completed_at_utc"`
+	TurnCount       int                   `json:"turn_count"`
+	Summary         string                `json:"summary,omitempty"`
+	Sentiment       *Sentiment            `json:"sentiment,omitempty"`
+	Disposition     *Disposition          `json:"disposition,omitempty"`
+	Stages          []StageResult         `json:"stages"`
+}
+
+// ArtifactFileName names the analysis artifact of a session.
+func ArtifactFileName(sessionID string) string {
+	return sessionID + "-call-analysis.json"
+}
+
+// Config scopes the analysis stage.
+type Config struct {
+	ArtifactsDir string
+	// QueueCapacity bounds sessions waiting for analysis; 0 uses 64.
+	QueueCapacity int
+	// Clock defaults to time.Now.
+	Clock func() time.Time
+	// Router resolves the telemetry-lane dispatch target; nil uses the default router.
+	Router lanes.Router
+}
+
+// Stage runs end-of-call analysis asynchronously on its own telemetry-lane execution pool,
+// so post-call work never queues behind or ahead of live turns.
+type Stage struct {
+	cfg       Config
+	analyzers Analyzers
+	target    lanes.DispatchTarget
+	pool      *executionpool.Manager
+}
+
+// NewStage constructs the analysis stage; at least one analyzer is required.
+func NewStage(cfg Config, analyzers Analyzers) (*Stage, error) {
+	if cfg.ArtifactsDir == "" {
+		return nil, fmt.Errorf("call analysis artifacts_dir is required")
+	}
+	if analyzers.empty() {
+		return nil, fmt.Errorf("call analysis requires at least one analyzer")
+	}
+	if cfg.QueueCapacity < 0 {
+		return nil, fmt.Errorf("call analysis queue_capacity must be >=0")
+	}
+	if cfg.QueueCapacity == 0 {
+		cfg.QueueCapacity = defaultQueueCapacity
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+	router := cfg.Router
+	if router == nil {
+		router = lanes.NewDefaultRouter()
+	}
+	target, err := router.Resolve(NodeType, eventabi.LaneTelemetry)
+	if err != nil {
+		return nil, err
+	}
+	return &Stage{
+		cfg:       cfg,
+		analyzers: analyzers,
+		target:    target,
+		pool:      executionpool.NewManagerWithConfig(executionpool.Config{Capacity: cfg.QueueCapacity, Now: cfg.Clock}),
+	}, nil
+}
+
+// Submit enqueues an ended session for analysis and returns without waiting. A full queue
+// rejects the session rather than blocking the caller: like other telemetry-lane work, post-call
+// analysis is droppable.
+func (s *Stage) Submit(transcript Transcript) error {
+	if err := transcript.validate(); err != nil {
+		return err
+	}
+	transcript.Turns = append([]Turn(nil), transcript.Turns...)
+	return s.pool.Submit(executionpool.Task{
+		ID:          s.target.QueueKey + "/" + transcript.SessionID,
+		FairnessKey: transcript.SessionID,
+		Run: func() error {
+			_, err := s.Analyze(transcript)
+			return err
+		},
+	})
+}
+
+// Drain waits for queued analyses to finish and stops the stage.
+func (s *Stage) Drain(ctx context.Context) error {
+	return s.pool.Drain(ctx)
+}
+
+// Analyze runs every configured analysis for transcript and writes its artifact.
+func (s *Stage) Analyze(transcript Transcript) (Artifact, error) {
+	if err := transcript.validate(); err != nil {
+		return Artifact{}, err
+	}
+	artifact := Artifact{
+		SchemaVersion:   SchemaVersion,
+		SessionID:       transcript.SessionID,
+		TenantID:        transcript.TenantID,
+		PipelineVersion: transcript.PipelineVersion,
+		Lane:            s.target.Lane,
+		QueueKey:        s.target.QueueKey,
+		PayloadClass:    eventabi.PayloadDerivedSummary,
+		EndedAtMS:       transcript.EndedAtMS,
+		TurnCount:       len(transcript.Turns),
+		Stages:          []StageResult{},
+	}
+	if s.analyzers.Summarize != nil {
+		s.run(&artifact, StageSummary, func() error {
+			summary, err := s.analyzers.Summarize(transcript)
+			if err == nil {
+				artifact.Summary = summary
+			}
+			return err
+		})
+	}
+	if s.analyzers.ScoreSentiment != nil {
+		s.run(&artifact, StageSentiment, func() error {
+			sentiment, err := s.analyzers.ScoreSentiment(transcript)
+			if err == nil && (sentiment.Label == "" || sentiment.Score < -1 || sentiment.Score > 1) {
+				err = fmt.Errorf("sentiment requires a label and score in [-1, 1]")
+			}
+			if err == nil {
+				artifact.Sentiment = &sentiment
+			}
+			return err
+		})
+	}
+	if s.analyzers.ClassifyDisposition != nil {
+		s.run(&artifact, StageDisposition, func() error {
+			disposition, err := s.analyzers.ClassifyDisposition(transcript)
+			if err == nil && (disposition.Label == "" || disposition.Confidence < 0 || disposition.Confidence > 1) {
+				err = fmt.Errorf("disposition requires a label and confidence in [0, 1]")
+			}
+			if err == nil {
+				artifact.Disposition = &disposition
+			}
+			return err
+		})
+	}
+	artifact.CompletedAtUTC = s.cfg.Clock().UTC().Format(time.RFC3339)
+
+	if err := os.MkdirAll(s.cfg.ArtifactsDir, 0o755); err != nil {
+		return Artifact{}, err
+	}
+	data, err := json.MarshalIndent(artifact, "", "  ")
+	if err != nil {
+		return Artifact{}, err
+	}
+	if err := os.WriteFile(filepath.Join(s.cfg.ArtifactsDir, ArtifactFileName(transcript.SessionID)), append(data, '\n'), 0o644); err != nil {
+		return Artifact{}, err
+	}
+	return artifact, nil
+}
+
+func (s *Stage) run(artifact *Artifact, stage string, analyze func() error) {
+	started := s.cfg.Clock()
+	result := StageResult{Stage: stage, Status: StatusOK}
+	if err := analyze(); err != nil {
+		result.Status = StatusError
+		result.Error = err.Error()
+	}
+	result.LatencyMS = max(s.cfg.Clock().Sub(started).Milliseconds(), 0)
+	artifact.Stages = append(artifact.Stages, result)
+}
@@ -0,0 +1,133 @@
+package callanalysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+func testTranscript(sessionID string) Transcript {
+	return Transcript{
+		SessionID:       sessionID,
+		TenantID:        "tenant-a",
+		PipelineVersion: "pipeline-v1",
+		EndedAtMS:       42_000,
+		Turns: []Turn{
+			{TurnID: "turn-1", UserText: "I need to rebook my flight", AssistantText: "Which date works?"},
+			{TurnID: "turn-2", UserText: "Friday, thanks", AssistantText: "Done, you are rebooked."},
+		},
+	}
+}
+
+func TestAnalyzeWritesArtifactAndIsolatesFailures(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	stage, err := NewStage(Config{ArtifactsDir: dir}, Analyzers{
+		Summarize: func(t Transcript) (string, error) {
+			return fmt.Sprintf("%d turns: %s", len(t.Turns), t.Turns[0].UserText), nil
+		},
+		ScoreSentiment: func(Transcript) (Sentiment, error) {
+			return Sentiment{Label: "positive", Score: 1.5}, nil
+		},
+		ClassifyDisposition: func(Transcript) (Disposition, error) {
+			return Disposition{Label: "resolved", Confidence: 0.9}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected stage error: %v", err)
+	}
+	defer stage.Drain(context.Background())
+
+	artifact, err := stage.Analyze(testTranscript("sess-analysis-1"))
+	if err != nil {
+		t.Fatalf("unexpected analyze error: %v", err)
+	}
+	if artifact.Summary != "2 turns: I need to rebook my flight" || artifact.Disposition == nil || artifact.Disposition.Label != "resolved" || artifact.Sentiment != nil {
+		t.Fatalf("expected summary and disposition with invalid sentiment dropped, got %+v", artifact)
+	}
+	if len(artifact.Stages) != 3 || artifact.Stages[1].Stage != StageSentiment || artifact.Stages[1].Status != StatusError || artifact.Stages[2].Status != StatusOK {
+		t.Fatalf("expected per-stage statuses, got %+v", artifact.Stages)
+	}
+	if artifact.Lane != eventabi.LaneTelemetry || artifact.QueueKey != "runtime/telemetry/end_of_call_analysis" || artifact.PayloadClass != eventabi.PayloadDerivedSummary {
+		t.Fatalf("expected telemetry-lane derived_summary artifact, got %+v", artifact)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, ArtifactFileName("sess-analysis-1")))
+	if err != nil {
+		t.Fatalf("unexpected artifact read error: %v", err)
+	}
+	var decoded Artifact
+	if err := json.Unmarshal(raw, &decoded); err != nil || decoded.SchemaVersion != SchemaVersion || decoded.TurnCount != 2 {
+		t.Fatalf("expected persisted analysis artifact, got %+v err=%v", decoded, err)
+	}
+}
+
+func TestSubmitRunsAsynchronouslyAndDrains(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	release := make(chan struct{})
+	stage, err := NewStage(Config{ArtifactsDir: dir, QueueCapacity: 1}, Analyzers{
+		Summarize: func(Transcript) (string, error) {
+			<-release
+			return "summary", nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected stage error: %v", err)
+	}
+
+	started := time.Now()
+	if err := stage.Submit(testTranscript("sess-async-1")); err != nil {
+		t.Fatalf("unexpected submit error: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("expected submit to return without waiting for analysis, took %s", elapsed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ArtifactFileName("sess-async-1"))); !os.IsNotExist(err) {
+		t.Fatalf("expected no artifact before analysis completes, got %v", err)
+	}
+	if err := stage.Submit(Transcript{SessionID: "sess-async-2"}); err == nil {
+		t.Fatalf("expected transcript without pipeline_version to be rejected")
+	}
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := stage.Drain(ctx); err != nil {
+		t.Fatalf("unexpected drain error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ArtifactFileName("sess-async-1"))); err != nil {
+		t.Fatalf("expected artifact after drain: %v", err)
+	}
+	if err := stage.Submit(testTranscript("sess-async-3")); err == nil {
+		t.Fatalf("expected submit after drain to be rejected")
+	}
+}
+
+func TestNewStageRejectsInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	summarize := func(Transcript) (string, error) { return "", nil }
+	tests := []struct {
+		name      string
+		cfg       Config
+		analyzers Analyzers
+	}{
+		{name: "missing artifacts dir", analyzers: Analyzers{Summarize: summarize}},
+		{name: "no analyzers", cfg: Config{ArtifactsDir: "out"}},
+		{name: "negative queue capacity", cfg: Config{ArtifactsDir: "out", QueueCapacity: -1}, analyzers: Analyzers{Summarize: summarize}},
+	}
+	for _, tc := range tests {
+		if _, err := NewStage(tc.cfg, tc.analyzers); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
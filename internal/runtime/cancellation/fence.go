@@ -10,11 +10,20 @@ import (
 type Fence struct {
 	mu       sync.Mutex
 	canceled map[string]bool
+	// signals holds per-turn channels closed when the turn's cancel is accepted.
+	signals map[string]chan struct{}
 }
 
+// closedSignal is returned for turns already fenced.
+var closedSignal = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
 // NewFence returns an empty cancellation fence.
 func NewFence() *Fence {
-	return &Fence{canceled: map[string]bool{}}
+	return &Fence{canceled: map[string]bool{}, signals: map[string]chan struct{}{}}
 }
 
 // Accept marks a turn as cancellation-fenced.
@@ -26,9 +35,45 @@ func (f *Fence) Accept(sessionID, turnID string) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	f.canceled[key] = true
+	if signal, ok := f.signals[key]; ok {
+		close(signal)
+		delete(f.signals, key)
+	}
 	return nil
 }
 
+// Signal returns a channel closed when cancellation is accepted for the turn, for provider
+// invocations to abort in-flight work. Invalid keys get a channel that never closes.
+func (f *Fence) Signal(sessionID, turnID string) <-chan struct{} {
+	key, err := turnKey(sessionID, turnID)
+	if err != nil {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.canceled[key] {
+		return closedSignal
+	}
+	signal, ok := f.signals[key]
+	if !ok {
+		signal = make(chan struct{})
+		f.signals[key] = signal
+	}
+	return signal
+}
+
+// Release drops the turn's pending cancel signal once its work has finished. Fence state is
+// kept so late output is still dropped.
+func (f *Fence) Release(sessionID, turnID string) {
+	key, err := turnKey(sessionID, turnID)
+	if err != nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.signals, key)
+}
+
 // IsFenced returns true when cancellation has been accepted for the turn.
 func (f *Fence) IsFenced(sessionID, turnID string) bool {
 	key, err := turnKey(sessionID, turnID)
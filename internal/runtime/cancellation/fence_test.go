@@ -28,3 +28,45 @@ func TestFenceRejectsInvalidKeys(t *testing.T) {
 		t.Fatalf("expected empty turn to fail")
 	}
 }
+
+func TestFenceSignalClosesOnAccept(t *testing.T) {
+	t.Parallel()
+
+	fence := NewFence()
+	signal := fence.Signal("sess-1", "turn-1")
+	other := fence.Signal("sess-1", "turn-2")
+	if fence.Signal("sess-1", "turn-1") != signal {
+		t.Fatalf("expected one signal per turn")
+	}
+	select {
+	case <-signal:
+		t.Fatalf("expected signal open before accept")
+	default:
+	}
+	if err := fence.Accept("sess-1", "turn-1"); err != nil {
+		t.Fatalf("unexpected accept error: %v", err)
+	}
+	select {
+	case <-signal:
+	default:
+		t.Fatalf("expected signal closed after accept")
+	}
+	select {
+	case <-fence.Signal("sess-1", "turn-1"):
+	default:
+		t.Fatalf("expected a fenced turn to get a closed signal")
+	}
+	select {
+	case <-other:
+		t.Fatalf("expected other turns unaffected")
+	default:
+	}
+
+	fence.Release("sess-1", "turn-2")
+	if fence.Signal("sess-1", "turn-2") == other {
+		t.Fatalf("expected release to drop the pending signal")
+	}
+	if fence.Signal("", "turn-1") != nil {
+		t.Fatalf("expected no signal for an invalid key")
+	}
+}
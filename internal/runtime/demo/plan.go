@@ -0,0 +1,234 @@
+package demo
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/executor"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/summarization"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/localproc"
+)
+
+// Node ids of the cascaded turn execution plan.
+const (
+	enhanceNodeID = "audio_enhancement"
+	sttNodeID     = "stt"
+	llmNodeID     = "llm"
+	ttsNodeID     = "tts"
+)
+
+// TurnLoad counts the turn plans executing across the sessions sharing it. The plans already
+// executing when a turn plan is materialized are its queue depth, the flow-control pressure
+// the plan's degrade ladder is evaluated against.
+type TurnLoad struct {
+	executing atomic.Int64
+}
+
+// Executing reports the turn plans executing now.
+func (l *TurnLoad) Executing() int64 {
+	if l == nil {
+		return 0
+	}
+	return l.executing.Load()
+}
+
+// enter counts one more executing plan and returns the depth ahead of it; leave undoes it.
+func (l *TurnLoad) enter() int64 {
+	if l == nil {
+		return 0
+	}
+	return l.executing.Add(1) - 1
+}
+
+func (l *TurnLoad) leave() {
+	if l != nil {
+		l.executing.Add(-1)
+	}
+}
+
+// TurnModalities are the provider modalities a session's turn plans invoke.
+func TurnModalities() []contracts.Modality {
+	return []contracts.Modality{contracts.ModalitySTT, contracts.ModalityLLM, contracts.ModalityTTS}
+}
+
+// runPlanLocked runs the turn's provider stages as one STT->LLM->TTS execution plan through
+// the scheduler, so every stage goes through the invocation controller and its retry, switch,
+// and circuit policy. providerErr reports a stage that produced no output, which the turn
+// answers with a fallback utterance; err is fatal.
+func (s *Session) runPlanLocked(result *TurnResult, plan *controlplane.ResolvedTurnPlan, turnTrace telemetry.TraceContext, bound summarization.Context, captured []int16, text *string) (outcomes []timeline.InvocationOutcomeEvidence, providerErr error, err error) {
+	turnID := result.TurnID
+	execution := s.turnExecutionPlan(turnID, plan, bound, captured, text)
+	firstSeq, err := s.sequence.Reserve(s.cfg.SessionID, planSequenceSpan(execution))
+	if err != nil {
+		return nil, nil, err
+	}
+	depth := s.cfg.TurnLoad.enter()
+	defer s.cfg.TurnLoad.leave()
+	s.setInFlight(turnID)
+	defer s.setInFlight("")
+	nowMS := s.nowMS()
+	in := executor.SchedulingInput{
+		SessionID:            s.cfg.SessionID,
+		TurnID:               turnID,
+		EventID:              turnID + "-plan",
+		PipelineVersion:      s.cfg.PipelineVersion,
+		RuntimeSequence:      firstSeq,
+		AuthorityEpoch:       1,
+		RuntimeTimestampMS:   nowMS,
+		WallClockTimestampMS: s.wallClockMS(nowMS),
+		QueueDepth:           depth,
+		Trace:                turnTrace,
+	}
+	if plan != nil {
+		in.PlanHash = plan.PlanHash
+	}
+	trace, err := s.scheduler.ExecutePlan(in, execution)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if text != nil {
+		result.Transcript = *text
+	}
+	for _, node := range trace.Nodes {
+		decision := node.Decision.Provider
+		if decision == nil {
+			continue
+		}
+		if !node.CacheHit {
+			// A cache hit invoked no provider; its node cache evidence records the lookup.
+			outcomes = append(outcomes, decision.ToInvocationOutcomeEvidence())
+		}
+		if decision.OutcomeClass != contracts.OutcomeSuccess {
+			providerErr = fmt.Errorf("%s %s: %s", decision.Modality, decision.SelectedProvider, decision.OutcomeClass)
+			continue
+		}
+		switch node.NodeID {
+		case sttNodeID:
+			result.Transcript = decision.OutputText
+		case llmNodeID:
+			result.Reply = decision.OutputText
+		case ttsNodeID:
+			if decision.OutputAudio == nil || decision.OutputAudio.SampleRateHz < 1 {
+				providerErr = fmt.Errorf("tts %s: no audio output", decision.SelectedProvider)
+				continue
+			}
+			result.ReplyPCM = localproc.Resample(decision.OutputAudio.PCM, decision.OutputAudio.SampleRateHz, s.cfg.SampleRateHz)
+		}
+	}
+	if providerErr == nil && !trace.Completed {
+		providerErr = fmt.Errorf("execution plan stopped: %s", trace.TerminalReason)
+	}
+	return outcomes, providerErr, nil
+}
+
+// turnExecutionPlan builds the cascaded plan for one turn. A text turn has no STT node and
+// prompts the LLM with the text directly; otherwise the LLM is prompted with the transcript.
+// STT and TTS are deterministic in their input and memoized when a node cache is wired; the
+// sampled LLM is not.
+// The bound summary travels to the LLM as the session summary and the turns since it as
+// prompt context. Output limits, the turn budget, and the flow-control degrade ladder come
+// from the resolved plan, as do the STT endpointing bounds the captured audio is cut to and
+// the audio enhancement node that, when the plan enables it, cleans that audio ahead of STT.
+func (s *Session) turnExecutionPlan(turnID string, plan *controlplane.ResolvedTurnPlan, bound summarization.Context, captured []int16, text *string) executor.ExecutionPlan {
+	var actions []string
+	if plan != nil {
+		actions = plan.AllowedAdaptiveActions
+	}
+	provider := func(modality contracts.Modality) *executor.ProviderInvocationInput {
+		preferred := ""
+		if s.cfg.PreferredProvider != nil {
+			preferred = s.cfg.PreferredProvider(modality)
+		}
+		return &executor.ProviderInvocationInput{
+			Modality:               modality,
+			PreferredProvider:      preferred,
+			AllowedAdaptiveActions: actions,
+			ProviderInvocationID:   fmt.Sprintf("%s-%s", turnID, modality),
+		}
+	}
+	var recent []string
+	for _, turn := range bound.RecentTurns {
+		recent = append(recent, "User: "+turn.UserText, "Assistant: "+turn.AssistantText)
+	}
+
+	llm := provider(contracts.ModalityLLM)
+	llm.SessionSummary = bound.InvocationSummary()
+	llm.PromptContext = recent
+	tts := provider(contracts.ModalityTTS)
+	execution := executor.ExecutionPlan{
+		Nodes: []executor.NodeSpec{
+			{NodeID: llmNodeID, NodeType: "provider", Lane: eventabi.LaneData, Provider: llm},
+			{NodeID: ttsNodeID, NodeType: "provider", Lane: eventabi.LaneData, Provider: tts, Deterministic: true},
+		},
+		Edges: []executor.EdgeSpec{{From: llmNodeID, To: ttsNodeID}},
+	}
+	if plan != nil {
+		execution.OutputLimits = plan.OutputLimits
+		execution.TurnBudgetMS = int64(plan.Budgets.TurnBudgetMS)
+		execution.DegradeLadder = plan.FlowControl.DegradeLadder
+	}
+	if text != nil {
+		llm.Prompt = contextPrompt(summarization.Context{RecentTurns: bound.RecentTurns}, *text)
+		return execution
+	}
+	stt := provider(contracts.ModalitySTT)
+	stt.Audio = &contracts.AudioInput{PCM: captured, SampleRateHz: s.cfg.SampleRateHz}
+	if plan != nil && plan.STTEndpointing != nil {
+		endpointing := plan.STTEndpointing.STTEndpointing
+		stt.Endpointing = &endpointing
+	}
+	execution.Nodes = append([]executor.NodeSpec{
+		{NodeID: sttNodeID, NodeType: "provider", Lane: eventabi.LaneData, Provider: stt, Deterministic: true},
+	}, execution.Nodes...)
+	execution.Edges = append(execution.Edges, executor.EdgeSpec{From: sttNodeID, To: llmNodeID})
+	if plan != nil && plan.AudioEnhancement != nil && plan.AudioEnhancement.Enabled {
+		enhancement := *plan.AudioEnhancement
+		execution.Nodes = append([]executor.NodeSpec{
+			{NodeID: enhanceNodeID, NodeType: "audio_enhancement", Lane: eventabi.LaneData, AudioEnhancement: true, Enhancement: &enhancement},
+		}, execution.Nodes...)
+		execution.Edges = append(execution.Edges, executor.EdgeSpec{From: enhanceNodeID, To: sttNodeID})
+	}
+	return execution
+}
+
+// planSequenceSpan bounds the runtime sequences one plan execution may use: its degrade
+// signals plus, per node, the dispatch, every provider attempt, and node failure signals.
+func planSequenceSpan(plan executor.ExecutionPlan) int {
+	return 8 + 16*len(plan.Nodes)
+}
+
+// planResponder prompts the LLM through a one-node plan, as end-of-turn summarization does.
+type planResponder struct {
+	scheduler executor.Scheduler
+	sessionID string
+	version   string
+}
+
+func (r planResponder) Respond(prompt string) (string, error) {
+	trace, err := r.scheduler.ExecutePlan(executor.SchedulingInput{
+		SessionID:       r.sessionID,
+		PipelineVersion: r.version,
+	}, executor.ExecutionPlan{Nodes: []executor.NodeSpec{{
+		NodeID:   llmNodeID,
+		NodeType: "provider",
+		Lane:     eventabi.LaneData,
+		Provider: &executor.ProviderInvocationInput{Modality: contracts.ModalityLLM, Prompt: prompt},
+	}}})
+	if err != nil {
+		return "", err
+	}
+	if len(trace.Nodes) == 0 || trace.Nodes[0].Decision.Provider == nil {
+		return "", fmt.Errorf("llm node was not dispatched")
+	}
+	decision := trace.Nodes[0].Decision.Provider
+	if decision.OutcomeClass != contracts.OutcomeSuccess {
+		return "", fmt.Errorf("llm %s: %s", decision.SelectedProvider, decision.OutcomeClass)
+	}
+	return decision.OutputText, nil
+}
@@ -0,0 +1,258 @@
+package demo
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/cost"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/callanalysis"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/summarization"
+)
+
+// Sandbox provider ids recorded in demo session evidence.
+const (
+	SandboxSTTProviderID = "stt-sandbox"
+	SandboxLLMProviderID = "llm-sandbox"
+	SandboxTTSProviderID = "tts-sandbox"
+)
+
+// Transcriber turns captured user audio into text.
+type Transcriber interface {
+	Transcribe(pcm []int16, sampleRateHz int) (string, error)
+}
+
+// Responder produces the assistant reply for a transcript.
+type Responder interface {
+	Respond(transcript string) (string, error)
+}
+
+// ContextResponder is a Responder that takes the bound session context separately from the
+// transcript. Responders that do not implement it receive the context folded into the prompt.
+type ContextResponder interface {
+	Responder
+	RespondInContext(transcript string, bound summarization.Context) (string, error)
+}
+
+// Synthesizer renders reply text as mono 16-bit PCM.
+type Synthesizer interface {
+	Synthesize(text string, sampleRateHz int) ([]int16, error)
+}
+
+// Providers binds the three provider stages of a demo session.
+type Providers struct {
+	STT Transcriber
+	LLM Responder
+	TTS Synthesizer
+}
+
+// SandboxProviders returns deterministic offline providers: no network calls or credentials,
+// so the demo runs on a fresh checkout.
+func SandboxProviders() Providers {
+	return Providers{STT: SandboxSTT{}, LLM: SandboxLLM{}, TTS: SandboxTTS{}}
+}
+
+// SandboxAdapters returns the sandbox stages as provider adapters, so a runtime without
+// configured providers still runs its turns through the invocation controller.
+func SandboxAdapters() []contracts.Adapter {
+	return []contracts.Adapter{sandboxSTTAdapter{}, sandboxLLMAdapter{}, sandboxTTSAdapter{}}
+}
+
+// sandboxSTTAdapter transcribes request audio with SandboxSTT.
+type sandboxSTTAdapter struct{}
+
+func (sandboxSTTAdapter) ProviderID() string           { return SandboxSTTProviderID }
+func (sandboxSTTAdapter) Modality() contracts.Modality { return contracts.ModalitySTT }
+
+func (sandboxSTTAdapter) Invoke(req contracts.InvocationRequest) (contracts.Outcome, error) {
+	if err := req.Validate(); err != nil {
+		return contracts.Outcome{}, err
+	}
+	if req.Audio == nil {
+		return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+	}
+	transcript, err := SandboxSTT{}.Transcribe(req.Audio.PCM, req.Audio.SampleRateHz)
+	if err != nil {
+		return contracts.Outcome{}, err
+	}
+	outcome := contracts.Outcome{
+		Class: contracts.OutcomeSuccess,
+		Text:  transcript,
+		Usage: &contracts.TokenUsage{AudioInputMS: req.Audio.DurationMS()},
+	}
+	// The sandbox's confidence is the voiced share of the audio it was sent.
+	if len(req.Audio.PCM) > 0 {
+		outcome.Confidence = float64(voicedSamples(req.Audio.PCM)) / float64(len(req.Audio.PCM))
+	}
+	return outcome, nil
+}
+
+// sandboxLLMAdapter answers the latest user line of the request prompt with SandboxLLM and
+// recalls the request session summary, if any.
+type sandboxLLMAdapter struct{}
+
+func (sandboxLLMAdapter) ProviderID() string           { return SandboxLLMProviderID }
+func (sandboxLLMAdapter) Modality() contracts.Modality { return contracts.ModalityLLM }
+
+func (sandboxLLMAdapter) Invoke(req contracts.InvocationRequest) (contracts.Outcome, error) {
+	if err := req.Validate(); err != nil {
+		return contracts.Outcome{}, err
+	}
+	transcript := req.Prompt
+	if idx := strings.LastIndex(transcript, "User: "); idx >= 0 {
+		transcript = transcript[idx+len("User: "):]
+	}
+	reply, err := SandboxLLM{}.Respond(transcript)
+	if err != nil {
+		return contracts.Outcome{}, err
+	}
+	if req.SessionSummary != nil && req.SessionSummary.Text != "" {
+		reply += " Earlier in this call: " + req.SessionSummary.Text
+	}
+	return contracts.Outcome{
+		Class: contracts.OutcomeSuccess,
+		Text:  reply,
+		Usage: &contracts.TokenUsage{
+			InputTokens:  int(cost.EstimateTokens(req.Prompt)),
+			OutputTokens: int(cost.EstimateTokens(reply)),
+		},
+	}, nil
+}
+
+// sandboxTTSAdapter renders the request text with SandboxTTS at DefaultSampleRateHz.
+type sandboxTTSAdapter struct{}
+
+func (sandboxTTSAdapter) ProviderID() string           { return SandboxTTSProviderID }
+func (sandboxTTSAdapter) Modality() contracts.Modality { return contracts.ModalityTTS }
+
+func (sandboxTTSAdapter) Invoke(req contracts.InvocationRequest) (contracts.Outcome, error) {
+	if err := req.Validate(); err != nil {
+		return contracts.Outcome{}, err
+	}
+	pcm, err := SandboxTTS{}.Synthesize(req.TTSText(""), DefaultSampleRateHz)
+	if err != nil {
+		return contracts.Outcome{}, err
+	}
+	if limit := req.MaxAudioOutputMS * DefaultSampleRateHz / 1000; limit > 0 && int64(len(pcm)) > limit {
+		pcm = pcm[:limit]
+	}
+	return contracts.Outcome{
+		Class: contracts.OutcomeSuccess,
+		Audio: &contracts.AudioOutput{PCM: pcm, SampleRateHz: DefaultSampleRateHz},
+		Usage: &contracts.TokenUsage{AudioOutputMS: cost.AudioMS(len(pcm), DefaultSampleRateHz)},
+	}, nil
+}
+
+// SandboxAnalyzers returns deterministic offline end-of-call analyzers: a turn-count summary,
+// neutral sentiment, and a completed/abandoned disposition.
+func SandboxAnalyzers() callanalysis.Analyzers {
+	return callanalysis.Analyzers{
+		Summarize: func(t callanalysis.Transcript) (string, error) {
+			if len(t.Turns) == 0 {
+				return "Session ended before any turn completed.", nil
+			}
+			return fmt.Sprintf("Sandbox call of %d turns; last request: %s", len(t.Turns), t.Turns[len(t.Turns)-1].UserText), nil
+		},
+		ScoreSentiment: func(callanalysis.Transcript) (callanalysis.Sentiment, error) {
+			return callanalysis.Sentiment{Label: "neutral", Score: 0}, nil
+		},
+		ClassifyDisposition: func(t callanalysis.Transcript) (callanalysis.Disposition, error) {
+			if len(t.Turns) == 0 {
+				return callanalysis.Disposition{Label: "abandoned", Confidence: 1}, nil
+			}
+			return callanalysis.Disposition{Label: "completed", Confidence: 1}, nil
+		},
+	}
+}
+
+// SandboxSTT reports how much speech it heard instead of recognizing words.
+type SandboxSTT struct{}
+
+// speechAmplitude is the per-sample magnitude treated as speech by SandboxSTT.
+const speechAmplitude = 1000
+
+// Transcribe describes the voiced duration of pcm.
+func (SandboxSTT) Transcribe(pcm []int16, sampleRateHz int) (string, error) {
+	if sampleRateHz < 1 {
+		return "", fmt.Errorf("sample_rate_hz must be >=1")
+	}
+	voiced := voicedSamples(pcm)
+	if voiced == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("(sandbox transcript: %.1f seconds of speech)", float64(voiced)/float64(sampleRateHz)), nil
+}
+
+func voicedSamples(pcm []int16) int {
+	voiced := 0
+	for _, sample := range pcm {
+		if sample > speechAmplitude || sample < -speechAmplitude {
+			voiced++
+		}
+	}
+	return voiced
+}
+
+// SandboxLLM echoes the transcript back in a fixed reply.
+type SandboxLLM struct{}
+
+// Respond returns a canned reply that references the transcript.
+func (SandboxLLM) Respond(transcript string) (string, error) {
+	if strings.TrimSpace(transcript) == "" {
+		return "I did not hear anything. Hold the button and speak, then release it.", nil
+	}
+	return "Sandbox assistant here. I received " + transcript + ". Configure real providers to hear a real answer.", nil
+}
+
+// RespondInContext answers like Respond and recalls the bound session summary, if any.
+func (l SandboxLLM) RespondInContext(transcript string, bound summarization.Context) (string, error) {
+	reply, err := l.Respond(transcript)
+	if err != nil || bound.Summary == nil {
+		return reply, err
+	}
+	return reply + " Earlier in this call: " + bound.Summary.Text, nil
+}
+
+// Summarize condenses turns into a fixed-form summary without calling a model.
+func (SandboxLLM) Summarize(req summarization.Request) (string, error) {
+	if len(req.Turns) == 0 {
+		return "", fmt.Errorf("summarize requires at least one turn")
+	}
+	last := req.Turns[len(req.Turns)-1]
+	summary := fmt.Sprintf("%d turns; last request: %s", len(req.Turns), last.UserText)
+	if req.PriorSummary != "" {
+		summary = req.PriorSummary + " Then " + summary
+	}
+	return summary, nil
+}
+
+// SandboxTTS renders one short tone per word so the round trip is audible.
+type SandboxTTS struct{}
+
+const (
+	sandboxToneMS  = 120
+	sandboxGapMS   = 60
+	sandboxToneHz  = 440.0
+	sandboxToneAmp = 6000.0
+)
+
+// Synthesize returns a tone/gap pattern sized to the word count of text.
+func (SandboxTTS) Synthesize(text string, sampleRateHz int) ([]int16, error) {
+	if sampleRateHz < 1 {
+		return nil, fmt.Errorf("sample_rate_hz must be >=1")
+	}
+	words := len(strings.Fields(text))
+	toneSamples := sampleRateHz * sandboxToneMS / 1000
+	gapSamples := sampleRateHz * sandboxGapMS / 1000
+	pcm := make([]int16, 0, words*(toneSamples+gapSamples))
+	for w := 0; w < words; w++ {
+		// Alternate pitch so consecutive words are distinguishable.
+		hz := sandboxToneHz * (1 + 0.25*float64(w%3))
+		for i := 0; i < toneSamples; i++ {
+			pcm = append(pcm, int16(sandboxToneAmp*math.Sin(2*math.Pi*hz*float64(i)/float64(sampleRateHz))))
+		}
+		pcm = append(pcm, make([]int16, gapSamples)...)
+	}
+	return pcm, nil
+}
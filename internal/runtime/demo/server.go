@@ -0,0 +1,306 @@
+package demo
+
+import (
+	"context"
+	_ "embed"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/callanalysis"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/transport"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/identifiers"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/wscodec"
+)
+
+//go:embed web/index.html
+var indexHTML []byte
+
+// ServerConfig configures the demo HTTP handler.
+type ServerConfig struct {
+	ArtifactsDir string
+	Providers    Providers
+	// Clock defaults to time.Now.
+	Clock func() time.Time
+	// Logger defaults to log.Default().
+	Logger *log.Logger
+	// Analysis, when set, receives each session's transcript after the connection closes.
+	Analysis *callanalysis.Stage
+}
+
+// clientMessage is a control message from the web client.
+type clientMessage struct {
+	Type string `json:"type"`
+	// TurnID and PlayedUntilMS carry a playback_ack: the client has played the turn's reply
+	// audio up to PlayedUntilMS.
+	TurnID        string `json:"turn_id,omitempty"`
+	PlayedUntilMS int64  `json:"played_until_ms,omitempty"`
+}
+
+// serverMessage is a JSON status message to the web client; reply audio follows a "turn"
+// message as one binary PCM16LE frame.
+type serverMessage struct {
+	Type                 string     `json:"type"`
+	SessionID            string     `json:"session_id,omitempty"`
+	SampleRateHz         int        `json:"sample_rate_hz,omitempty"`
+	TurnID               string     `json:"turn_id,omitempty"`
+	Transcript           string     `json:"transcript,omitempty"`
+	Reply                string     `json:"reply,omitempty"`
+	Committed            bool       `json:"committed,omitempty"`
+	FallbackReason       string     `json:"fallback_reason,omitempty"`
+	FirstOutputLatencyMS int64      `json:"first_output_latency_ms,omitempty"`
+	Artifacts            *Artifacts `json:"artifacts,omitempty"`
+	Error                string     `json:"error,omitempty"`
+	// PerceivedFirstAudioLatencyMS answers the first playback_ack of a turn: capture end to
+	// the acknowledged start of reply playback.
+	PerceivedFirstAudioLatencyMS int64 `json:"perceived_first_audio_latency_ms,omitempty"`
+}
+
+// Handler serves the demo web client and its sessions. Session connections are hijacked, so
+// http.Server.Shutdown neither closes nor waits for them; Shutdown does.
+type Handler struct {
+	mux *http.ServeMux
+
+	mu       sync.Mutex
+	closing  bool
+	conns    map[*wscodec.Conn]struct{}
+	sessions sync.WaitGroup
+}
+
+// NewHandler serves the embedded web client at / and one demo session per WebSocket
+// connection at /ws.
+func NewHandler(cfg ServerConfig) (*Handler, error) {
+	if cfg.ArtifactsDir == "" {
+		return nil, fmt.Errorf("artifacts_dir is required")
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.Default()
+	}
+	h := &Handler{mux: http.NewServeMux(), conns: map[*wscodec.Conn]struct{}{}}
+	ids := identifiers.NewGenerator(cfg.Clock, nil)
+	h.mux.Handle("/", WebClientHandler())
+	h.mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wscodec.Upgrade(w, r)
+		if err != nil {
+			cfg.Logger.Printf("demo: websocket upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		if !h.track(conn) {
+			return
+		}
+		defer h.untrack(conn)
+		sessionID, err := ids.New(identifiers.KindSession)
+		if err != nil {
+			cfg.Logger.Printf("demo: session id: %v", err)
+			return
+		}
+		if err := serveSession(conn, cfg, sessionID); err != nil {
+			cfg.Logger.Printf("demo: session %s ended: %v", sessionID, err)
+		}
+	})
+	return h, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// Shutdown closes every open session connection and waits until the sessions have written
+// their end-of-session artifacts or ctx ends. Connections upgraded afterwards are closed
+// immediately.
+func (h *Handler) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closing = true
+	for conn := range h.conns {
+		_ = conn.Close()
+	}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.sessions.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// track registers a session connection; it reports false once Shutdown has started.
+func (h *Handler) track(conn *wscodec.Conn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closing {
+		return false
+	}
+	h.conns[conn] = struct{}{}
+	h.sessions.Add(1)
+	return true
+}
+
+func (h *Handler) untrack(conn *wscodec.Conn) {
+	h.mu.Lock()
+	delete(h.conns, conn)
+	h.mu.Unlock()
+	h.sessions.Done()
+}
+
+// WebClientHandler serves the embedded browser client at /. The client captures the microphone
+// and connects to /ws on the same host, so it also drives any transport speaking the demo
+// protocol there.
+func WebClientHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(indexHTML)
+	})
+}
+
+func serveSession(conn *wscodec.Conn, cfg ServerConfig, sessionID string) error {
+	session, err := NewSession(SessionConfig{SessionID: sessionID, ArtifactsDir: cfg.ArtifactsDir, Clock: cfg.Clock}, cfg.Providers)
+	if err != nil {
+		return err
+	}
+	meter := transport.NewBandwidthMeter(transport.BandwidthConfig{SessionID: sessionID, PipelineVersion: session.cfg.PipelineVersion})
+	defer func() {
+		bandwidth := meter.Report()
+		cfg.Logger.Printf("demo: session %s bandwidth: ingress_bytes=%d egress_bytes=%d oversized_metadata=%d",
+			sessionID, bandwidth.IngressBytes, bandwidth.EgressBytes, bandwidth.OversizedMetadata)
+		if artifacts, err := session.WriteArtifacts(); err == nil {
+			cfg.Logger.Printf("demo: session %s artifacts: %s %s", sessionID, artifacts.SessionAudioPath, artifacts.BaselinePath)
+		}
+		if cfg.Analysis != nil {
+			if err := cfg.Analysis.Submit(session.CallTranscript()); err != nil {
+				cfg.Logger.Printf("demo: session %s call analysis dropped: %v", sessionID, err)
+			}
+		}
+	}()
+	if err := writeJSON(conn, meter, serverMessage{Type: "session", SessionID: sessionID, SampleRateHz: DefaultSampleRateHz}); err != nil {
+		return err
+	}
+
+	for {
+		op, payload, err := conn.ReadMessage()
+		// A connection closed by Handler.Shutdown ends the session like a client close.
+		if errors.Is(err, wscodec.ErrClosed) || errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// Client audio is raw data-lane payload; text frames are control messages.
+		if op == wscodec.OpBinary {
+			_ = meter.Record(transport.DirectionIngress, eventabi.LaneData, eventabi.PayloadAudioRaw, len(payload))
+		} else {
+			_ = meter.Record(transport.DirectionIngress, eventabi.LaneControl, eventabi.PayloadMetadata, len(payload))
+		}
+
+		var turn *TurnResult
+		var playback *PlaybackResult
+		switch op {
+		case wscodec.OpBinary:
+			turn, err = session.AppendAudio(decodePCM16(payload))
+		case wscodec.OpText:
+			var msg clientMessage
+			if err = json.Unmarshal(payload, &msg); err != nil {
+				break
+			}
+			switch msg.Type {
+			case "capture_start":
+				err = session.StartCapture()
+			case "capture_end":
+				turn, err = session.EndCapture()
+			case "playback_ack":
+				playback, err = session.AcknowledgePlayback(transport.PlaybackAck{TurnID: msg.TurnID, PlayedUntilMS: msg.PlayedUntilMS})
+			default:
+				err = fmt.Errorf("unsupported client message type %q", msg.Type)
+			}
+		}
+		if err != nil {
+			if werr := writeJSON(conn, meter, serverMessage{Type: "error", Error: err.Error()}); werr != nil {
+				return werr
+			}
+			continue
+		}
+		if playback != nil {
+			if err := writeJSON(conn, meter, serverMessage{Type: "playback", TurnID: playback.TurnID, PerceivedFirstAudioLatencyMS: playback.PerceivedFirstAudioLatencyMS}); err != nil {
+				return err
+			}
+			continue
+		}
+		if turn == nil {
+			continue
+		}
+		artifacts := turn.Artifacts
+		if err := writeJSON(conn, meter, serverMessage{
+			Type:                 "turn",
+			TurnID:               turn.TurnID,
+			Transcript:           turn.Transcript,
+			Reply:                turn.Reply,
+			Committed:            turn.Committed,
+			FallbackReason:       turn.FallbackReason,
+			FirstOutputLatencyMS: turn.FirstOutputAtMS - turn.CaptureEndAtMS,
+			Artifacts:            &artifacts,
+		}); err != nil {
+			return err
+		}
+		reply := encodePCM16(turn.ReplyPCM)
+		_ = meter.Record(transport.DirectionEgress, eventabi.LaneData, eventabi.PayloadAudioRaw, len(reply))
+		if err := conn.WriteMessage(wscodec.OpBinary, reply); err != nil {
+			return err
+		}
+		if err := session.RecordReplyEgress(turn.TurnID); err != nil {
+			return err
+		}
+	}
+}
+
+// writeJSON sends msg and accounts its bytes; turn messages carry transcript and reply text, so
+// they count as raw text on the data lane rather than control metadata.
+func writeJSON(conn *wscodec.Conn, meter *transport.BandwidthMeter, msg serverMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if msg.Type == "turn" {
+		_ = meter.Record(transport.DirectionEgress, eventabi.LaneData, eventabi.PayloadTextRaw, len(data))
+	} else {
+		_ = meter.Record(transport.DirectionEgress, eventabi.LaneControl, eventabi.PayloadMetadata, len(data))
+	}
+	return conn.WriteMessage(wscodec.OpText, data)
+}
+
+func decodePCM16(payload []byte) []int16 {
+	pcm := make([]int16, len(payload)/2)
+	for i := range pcm {
+		pcm[i] = int16(binary.LittleEndian.Uint16(payload[2*i:]))
+	}
+	return pcm
+}
+
+func encodePCM16(pcm []int16) []byte {
+	out := make([]byte, 2*len(pcm))
+	for i, sample := range pcm {
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(sample))
+	}
+	return out
+}
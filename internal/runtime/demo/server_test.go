@@ -0,0 +1,263 @@
+package demo
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/wscodec"
+)
+
+// WebSocket control opcodes the test client writes directly.
+const (
+	opCont  byte = 0x0
+	opClose byte = 0x8
+)
+
+// testClient is a minimal masking WebSocket client for exercising the demo handler.
+type testClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dialTestClient(t *testing.T, serverURL string) *testClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(serverURL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	request := "GET /ws HTTP/1.1\r\nHost: demo\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Key: " + key + "\r\n\r\n"
+	if _, err := conn.Write([]byte(request)); err != nil {
+		t.Fatalf("handshake write: %v", err)
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("handshake read: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != wscodec.AcceptKey(key) {
+		t.Fatalf("unexpected handshake response: %d %v", resp.StatusCode, resp.Header)
+	}
+	return &testClient{t: t, conn: conn, reader: reader}
+}
+
+// send writes payload split into two masked frames to exercise continuation reassembly.
+func (c *testClient) send(op byte, payload []byte) {
+	c.t.Helper()
+	half := len(payload) / 2
+	c.writeFrame(false, op, payload[:half])
+	c.writeFrame(true, opCont, payload[half:])
+}
+
+func (c *testClient) writeFrame(fin bool, op byte, payload []byte) {
+	c.t.Helper()
+	first := op
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first}
+	if len(payload) < 126 {
+		frame = append(frame, 0x80|byte(len(payload)))
+	} else {
+		frame = append(frame, 0x80|126, byte(len(payload)>>8), byte(len(payload)))
+	}
+	mask := [4]byte{1, 2, 3, 4}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		c.t.Fatalf("frame write: %v", err)
+	}
+}
+
+func (c *testClient) read() (byte, []byte) {
+	c.t.Helper()
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		c.t.Fatalf("frame read: %v", err)
+	}
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		io.ReadFull(c.reader, ext[:])
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		io.ReadFull(c.reader, ext[:])
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		c.t.Fatalf("payload read: %v", err)
+	}
+	return header[0] & 0x0F, payload
+}
+
+func (c *testClient) readJSON() serverMessage {
+	c.t.Helper()
+	op, payload := c.read()
+	if op != wscodec.OpText {
+		c.t.Fatalf("expected text frame, got opcode 0x%x", op)
+	}
+	var msg serverMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		c.t.Fatalf("decode server message: %v", err)
+	}
+	return msg
+}
+
+// logLines forwards handler log lines so tests can wait for end-of-session artifact writes. It
+// never blocks the handler: lines beyond the buffer are dropped.
+type logLines chan string
+
+func (l logLines) Write(p []byte) (int, error) {
+	select {
+	case l <- string(p):
+	default:
+	}
+	return len(p), nil
+}
+
+func TestHandlerServesWebClientAndRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	logs := make(logLines, 32)
+	handler, err := NewHandler(ServerConfig{ArtifactsDir: t.TempDir(), Providers: SandboxProviders(), Logger: log.New(logs, "", 0)})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/")
+	if err != nil {
+		t.Fatalf("unexpected index error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `new WebSocket(`) {
+		t.Fatalf("expected embedded web client, got %d", resp.StatusCode)
+	}
+
+	client := dialTestClient(t, server.URL)
+	hello := client.readJSON()
+	if hello.Type != "session" || hello.SampleRateHz != DefaultSampleRateHz || !strings.HasPrefix(hello.SessionID, "sess_") {
+		t.Fatalf("unexpected session message: %+v", hello)
+	}
+
+	client.send(wscodec.OpText, []byte(`{"type":"capture_start"}`))
+	client.send(wscodec.OpBinary, encodePCM16(voicedPCM(1600)))
+	client.send(wscodec.OpText, []byte(`{"type":"capture_end"}`))
+	turn := client.readJSON()
+	if turn.Type != "turn" || !turn.Committed || !strings.Contains(turn.Transcript, "0.1 seconds") || turn.Artifacts == nil || turn.Artifacts.BaselinePath == "" {
+		t.Fatalf("unexpected turn message: %+v", turn)
+	}
+	op, audio := client.read()
+	if op != wscodec.OpBinary || len(audio) == 0 || len(audio)%2 != 0 {
+		t.Fatalf("expected PCM16 reply audio, got opcode 0x%x len=%d", op, len(audio))
+	}
+
+	ack := []byte(fmt.Sprintf(`{"type":"playback_ack","turn_id":%q,"played_until_ms":20}`, turn.TurnID))
+	client.send(wscodec.OpText, ack)
+	if msg := client.readJSON(); msg.Type != "playback" || msg.TurnID != turn.TurnID || msg.PerceivedFirstAudioLatencyMS < 0 {
+		t.Fatalf("expected perceived first-audio answer to the playback ack, got %+v", msg)
+	}
+	client.send(wscodec.OpText, []byte(`{"type":"playback_ack","turn_id":"unknown-turn","played_until_ms":20}`))
+	if msg := client.readJSON(); msg.Type != "error" || !strings.Contains(msg.Error, "without reply egress") {
+		t.Fatalf("expected playback ack for an unknown turn to fail, got %+v", msg)
+	}
+
+	client.send(wscodec.OpText, []byte(`{"type":"hang_up"}`))
+	if msg := client.readJSON(); msg.Type != "error" || !strings.Contains(msg.Error, "hang_up") {
+		t.Fatalf("expected unsupported message error, got %+v", msg)
+	}
+
+	// Hijacked connections outlive server.Close, so wait for the session's final artifact write.
+	client.conn.Close()
+	timeout := time.After(5 * time.Second)
+	sawBandwidth := false
+	for {
+		select {
+		case line := <-logs:
+			if strings.Contains(line, "bandwidth:") {
+				ingress := 3264 + len(ack) + len(`{"type":"playback_ack","turn_id":"unknown-turn","played_until_ms":20}`)
+				sawBandwidth = strings.Contains(line, fmt.Sprintf("ingress_bytes=%d ", ingress)) && !strings.Contains(line, "egress_bytes=0 ")
+			}
+			if strings.Contains(line, "artifacts:") {
+				if !sawBandwidth {
+					t.Fatalf("expected 3200 audio bytes plus control message ingress reported before artifacts")
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for session artifacts to be written")
+		}
+	}
+}
+
+func TestHandlerShutdownWaitsForSessionArtifacts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	handler, err := NewHandler(ServerConfig{ArtifactsDir: dir, Providers: SandboxProviders(), Logger: log.New(io.Discard, "", 0)})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := dialTestClient(t, server.URL)
+	hello := client.readJSON()
+	client.send(wscodec.OpText, []byte(`{"type":"capture_start"}`))
+	client.send(wscodec.OpBinary, encodePCM16(voicedPCM(1600)))
+	client.send(wscodec.OpText, []byte(`{"type":"capture_end"}`))
+	if turn := client.readJSON(); turn.Type != "turn" {
+		t.Fatalf("unexpected turn message: %+v", turn)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := handler.Shutdown(ctx); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, hello.SessionID+"-session-audio.json")); err != nil {
+		t.Fatalf("expected session artifacts written before shutdown returned: %v", err)
+	}
+	late := dialTestClient(t, server.URL)
+	if op, _ := late.read(); op != opClose {
+		t.Fatalf("expected connections upgraded after shutdown to be closed, got opcode 0x%x", op)
+	}
+}
+
+func TestUpgradeRejectsPlainRequests(t *testing.T) {
+	t.Parallel()
+
+	handler, err := NewHandler(ServerConfig{ArtifactsDir: t.TempDir(), Providers: SandboxProviders(), Logger: log.New(io.Discard, "", 0)})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ws", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected plain /ws request to be rejected, got %d", recorder.Code)
+	}
+	if _, err := NewHandler(ServerConfig{Providers: SandboxProviders()}); err == nil {
+		t.Fatalf("expected missing artifacts dir to fail")
+	}
+}
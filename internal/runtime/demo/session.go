@@ -0,0 +1,804 @@
+package demo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/cost"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/decisionindex"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/recording"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/callanalysis"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/cancellation"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/executor"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/fallbackspeech"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/localadmission"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/nodecache"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/prelude"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/healthscore"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/sequence"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/summarization"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/transport"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/turnarbiter"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/identifiers"
+)
+
+const (
+	// DefaultSampleRateHz is the PCM16 mono rate the embedded web client captures and plays.
+	DefaultSampleRateHz = 16000
+	// DefaultMaxCaptureMS force-ends a push-to-talk capture the client never released.
+	DefaultMaxCaptureMS    int64 = 30_000
+	defaultTenantID              = "tenant-local-demo"
+	defaultPipelineVersion       = "pipeline-demo"
+)
+
+// SessionConfig scopes one demo conversation.
+type SessionConfig struct {
+	SessionID       string
+	TenantID        string
+	PipelineVersion string
+	SampleRateHz    int
+	MaxCaptureMS    int64
+	// ArtifactsDir receives the session audio and timeline baseline after every turn.
+	ArtifactsDir string
+	// Clock defaults to time.Now; session timestamps are milliseconds since NewSession.
+	Clock func() time.Time
+	// FallbackSpeech overrides the utterances spoken when a provider stage fails; they are
+	// pre-synthesized with the sandbox TTS so they play even when the TTS provider is down.
+	FallbackSpeech *fallbackspeech.Config
+	// SLA is the SLA tier the tenant declared in the control plane; every turn's baseline
+	// evidence is tagged with it.
+	SLA *controlplane.SLATier
+	// PriceTable prices each turn's provider usage into the baseline cost rollup. Defaults to
+	// cost.DefaultPriceTable with the free sandbox providers added.
+	PriceTable cost.PriceTable
+	// NodeCache memoizes the deterministic STT and TTS nodes of Invoker-run turn plans: under
+	// one pipeline version the same node input yields the same output, so a hit skips the
+	// provider call and records node cache evidence instead. The serving runtime shares one
+	// cache across sessions; nil disables memoization.
+	NodeCache *nodecache.Cache[executor.SchedulingDecision]
+	// TurnLoad counts turn plans executing across the sessions sharing it, as the serving
+	// runtime's sessions do; each plan's degrade ladder is evaluated at the depth ahead of it.
+	// nil evaluates every plan at depth 0.
+	TurnLoad *TurnLoad
+	// TurnStartResolver resolves CP turn-start bundles for the session's turns; nil uses the
+	// arbiter's default control-plane services.
+	TurnStartResolver turnarbiter.TurnStartBundleResolver
+	// GateTurnOpen adjusts each turn-open request before the arbiter sees it, as the serving
+	// runtime's snapshot freshness monitor does to defer or reject turns on stale CP snapshots;
+	// nil leaves requests unchanged.
+	GateTurnOpen func(turnarbiter.OpenRequest) turnarbiter.OpenRequest
+	// DecisionIndex receives the session's turn decision outcomes as they are recorded; the
+	// serving runtime shares one index across sessions. Nil disables indexing.
+	DecisionIndex *decisionindex.Index
+	// Invoker, when set, runs every turn as an STT->LLM->TTS execution plan through the RK-07
+	// scheduler and this RK-11 provider invoker (the serving runtime passes the invocation
+	// controller of its resolved provider catalog) instead of the bound Providers. Attempts
+	// are recorded in the session timeline and priced with PriceTable.
+	Invoker executor.ProviderInvoker
+	// CancelFence receives CancelTurn's cancel of the in-flight turn plan; the scheduler hands
+	// each provider invocation the turn's fence signal, so streaming providers abort and report
+	// the usage consumed so far. The serving runtime shares one fence across sessions; nil
+	// disables CancelTurn.
+	CancelFence *cancellation.Fence
+	// PreferredProvider names the provider a turn plan node of the modality tries first; it is
+	// asked per turn so a reloaded catalog takes effect. Nil or an empty name follows the
+	// invoker's catalog order.
+	PreferredProvider func(contracts.Modality) string
+	// HealthScorer scores every provider attempt of the session's turn plans; when a plan
+	// allows provider_switch, a clearly healthier provider is tried before the preferred one.
+	// The serving runtime shares one scorer across sessions; nil routes on preference alone.
+	HealthScorer *healthscore.Scorer
+	// Traceparent is the W3C traceparent header of the transport handshake; each turn opens
+	// its transport ingress span under it, so turn, scheduler, and provider spans join the
+	// caller's trace. Empty or malformed starts a trace per turn.
+	Traceparent string
+}
+
+func (c SessionConfig) withDefaults() SessionConfig {
+	if c.TenantID == "" {
+		c.TenantID = defaultTenantID
+	}
+	if c.PipelineVersion == "" {
+		c.PipelineVersion = defaultPipelineVersion
+	}
+	if c.SampleRateHz == 0 {
+		c.SampleRateHz = DefaultSampleRateHz
+	}
+	if c.MaxCaptureMS == 0 {
+		c.MaxCaptureMS = DefaultMaxCaptureMS
+	}
+	if c.Clock == nil {
+		c.Clock = time.Now
+	}
+	if c.PriceTable == nil {
+		c.PriceTable = cost.DefaultPriceTable()
+		for _, providerID := range []string{SandboxSTTProviderID, SandboxLLMProviderID, SandboxTTSProviderID} {
+			c.PriceTable[providerID] = cost.Rates{}
+		}
+	}
+	return c
+}
+
+// TurnResult is one completed push-to-talk round trip.
+type TurnResult struct {
+	TurnID          string
+	Transcript      string
+	Reply           string
+	ReplyPCM        []int16
+	TriggeredAtMS   int64
+	CaptureEndAtMS  int64
+	FirstOutputAtMS int64
+	Committed       bool
+	// FallbackReason is the degrade reason when Reply is a fallback utterance spoken because a
+	// provider stage failed.
+	FallbackReason string
+	Artifacts      Artifacts
+}
+
+// PlaybackResult is the user-perceived first-audio latency of a turn, from the end of capture
+// to the client-acknowledged start of reply playback.
+type PlaybackResult struct {
+	TurnID                       string
+	FirstAudioPlayedAtMS         int64
+	PerceivedFirstAudioLatencyMS int64
+}
+
+// Artifacts are the inspectable files a demo session writes.
+type Artifacts struct {
+	SessionAudioPath string `json:"session_audio_path"`
+	BaselinePath     string `json:"baseline_path"`
+}
+
+// Session drives push-to-talk turns through the turn arbiter and the bound providers, and
+// records the conversation as session audio plus OR-02 baseline evidence.
+type Session struct {
+	cfg       SessionConfig
+	providers Providers
+	startedAt time.Time
+
+	mu       sync.Mutex
+	trigger  *prelude.Trigger
+	fallback *fallbackspeech.Speaker
+	arbiter  turnarbiter.Arbiter
+	recorder *timeline.Recorder
+	playback *transport.PlaybackTracker
+	sequence *sequence.Allocator
+	audio    recording.SessionAudio
+	capture  []int16
+	turns    int
+	history  []callanalysis.Turn
+	// scheduler runs turn execution plans when an Invoker is configured; nil otherwise.
+	scheduler *executor.Scheduler
+	// summary condenses session context once a turn plan enables summarization; nil until then.
+	summary *summarization.Node
+	// captureEnds maps turn ids to capture end, the origin of perceived first-audio latency.
+	captureEnds map[string]int64
+
+	// inFlightMu guards inFlight apart from mu, which a running turn holds, so CancelTurn can
+	// reach the turn while it executes.
+	inFlightMu sync.Mutex
+	// inFlight is the turn whose execution plan is running; empty otherwise.
+	inFlight string
+}
+
+// NewSession constructs a demo session; every provider stage is required unless the config
+// names an Invoker.
+func NewSession(cfg SessionConfig, providers Providers) (*Session, error) {
+	cfg = cfg.withDefaults()
+	if err := identifiers.ValidateSessionID(cfg.SessionID); err != nil {
+		return nil, err
+	}
+	if cfg.ArtifactsDir == "" {
+		return nil, fmt.Errorf("artifacts_dir is required")
+	}
+	if cfg.SampleRateHz < 1 {
+		return nil, fmt.Errorf("sample_rate_hz must be >=1")
+	}
+	if cfg.Invoker == nil && (providers.STT == nil || providers.LLM == nil || providers.TTS == nil) {
+		return nil, fmt.Errorf("stt, llm, and tts providers are required")
+	}
+	if cfg.SLA != nil {
+		if err := cfg.SLA.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	trigger, err := prelude.NewTrigger(controlplane.SessionTrigger{Mode: controlplane.TriggerPushToTalk, MaxCaptureMS: cfg.MaxCaptureMS}, nil)
+	if err != nil {
+		return nil, err
+	}
+	fallbackCfg := fallbackspeech.DefaultConfig(cfg.SampleRateHz)
+	if cfg.FallbackSpeech != nil {
+		fallbackCfg = *cfg.FallbackSpeech
+		fallbackCfg.SampleRateHz = cfg.SampleRateHz
+		fallbackCfg.Presynthesize = true
+	}
+	speaker, err := fallbackspeech.NewSpeaker(fallbackCfg, SandboxTTS{})
+	if err != nil {
+		return nil, err
+	}
+	recorder := timeline.NewRecorder(timeline.StageAConfig{DecisionIndex: cfg.DecisionIndex})
+	var scheduler *executor.Scheduler
+	if cfg.Invoker != nil {
+		planned := executor.NewSchedulerWithNodeCache(localadmission.NewEvaluatorFromEnv(), cfg.Invoker, &recorder, cfg.NodeCache).
+			WithPriceTable(cfg.PriceTable).
+			WithClock(clock.WithNow(cfg.Clock))
+		if cfg.CancelFence != nil {
+			planned = planned.WithCancelFence(cfg.CancelFence)
+		}
+		if cfg.HealthScorer != nil {
+			planned = planned.WithHealthScorer(cfg.HealthScorer)
+		}
+		scheduler = &planned
+	}
+	return &Session{
+		cfg:       cfg,
+		providers: providers,
+		scheduler: scheduler,
+		startedAt: cfg.Clock(),
+		trigger:   trigger,
+		fallback:  speaker,
+		arbiter:   turnarbiter.NewWithDependencies(&recorder, cfg.TurnStartResolver),
+		recorder:  &recorder,
+		playback:  transport.NewPlaybackTracker(transport.PlaybackConfig{SessionID: cfg.SessionID, PipelineVersion: cfg.PipelineVersion}),
+		sequence:  sequence.NewAllocator(),
+		audio: recording.SessionAudio{
+			SessionID:       cfg.SessionID,
+			TenantID:        cfg.TenantID,
+			PipelineVersion: cfg.PipelineVersion,
+			// The local user is both the speaker and the reviewer of their own demo session.
+			ConsentGranted: true,
+		},
+		captureEnds: map[string]int64{},
+	}, nil
+}
+
+// StartCapture handles the client capture_start message.
+func (s *Session) StartCapture() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, action, err := s.trigger.ObserveControlSignal(s.captureSignal("capture_start"))
+	if err != nil {
+		return err
+	}
+	if action == prelude.TriggerOpenTurn {
+		s.capture = s.capture[:0]
+	}
+	return nil
+}
+
+// AppendAudio buffers captured PCM. Audio outside a capture is dropped. It returns a turn
+// when the capture reaches MaxCaptureMS.
+func (s *Session) AppendAudio(pcm []int16) (*TurnResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.trigger.Capturing() {
+		return nil, nil
+	}
+	s.capture = append(s.capture, pcm...)
+	ended, action, err := s.trigger.ObserveAudio(prelude.AudioFrame{TimestampMS: s.nowMS(), Speech: true})
+	if err != nil || action != prelude.TriggerCaptureEnded {
+		return nil, err
+	}
+	return s.completeTurnLocked(ended, nil)
+}
+
+// EndCapture handles the client capture_end message and runs the turn. It returns nil when
+// no capture is open.
+func (s *Session) EndCapture() (*TurnResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ended, action, err := s.trigger.ObserveControlSignal(s.captureSignal("capture_end"))
+	if err != nil || action != prelude.TriggerCaptureEnded {
+		return nil, err
+	}
+	return s.completeTurnLocked(ended, nil)
+}
+
+// SubmitText runs a turn on typed user text instead of captured audio; the STT stage is
+// skipped and text is the turn transcript. It fails while a capture is open.
+func (s *Session) SubmitText(text string) (*TurnResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("text turn requires non-empty text")
+	}
+	if s.trigger.Capturing() {
+		return nil, fmt.Errorf("text turn while a capture is open")
+	}
+	nowMS := s.nowMS()
+	return s.completeTurnLocked(controlplane.TurnTrigger{
+		Mode:             controlplane.TriggerPushToTalk,
+		TriggeredAtMS:    nowMS,
+		CaptureEndAtMS:   &nowMS,
+		CaptureEndReason: "capture_end",
+	}, &text)
+}
+
+// CancelCapture discards an open capture without running a turn and reports whether one was
+// open.
+func (s *Session) CancelCapture() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, action, err := s.trigger.ObserveControlSignal(s.captureSignal("capture_end"))
+	if err != nil || action != prelude.TriggerCaptureEnded {
+		return false, err
+	}
+	s.capture = s.capture[:0]
+	return true, nil
+}
+
+// CancelTurn accepts a cancel of the turn plan executing now on the session's cancel fence,
+// aborting its in-flight provider invocations, and returns the cancelled turn id; it returns
+// an empty id when no plan is executing or no fence is configured. It does not wait for the
+// turn, which completes with the cancelled stages recorded.
+func (s *Session) CancelTurn() (string, error) {
+	s.inFlightMu.Lock()
+	defer s.inFlightMu.Unlock()
+	if s.cfg.CancelFence == nil || s.inFlight == "" {
+		return "", nil
+	}
+	if err := s.cfg.CancelFence.Accept(s.cfg.SessionID, s.inFlight); err != nil {
+		return "", err
+	}
+	return s.inFlight, nil
+}
+
+// setInFlight records the turn whose plan is executing; an empty id clears it.
+func (s *Session) setInFlight(turnID string) {
+	s.inFlightMu.Lock()
+	defer s.inFlightMu.Unlock()
+	s.inFlight = turnID
+}
+
+// RecordReplyEgress marks that turnID's reply audio was written to the client.
+func (s *Session) RecordReplyEgress(turnID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.playback.RecordEgress(turnID, s.nowMS())
+}
+
+// AcknowledgePlayback handles the client playback_ack message. The first ack reporting played
+// audio records the turn's first-audio time in its baseline evidence and returns the perceived
+// latency; other acks return nil.
+func (s *Session) AcknowledgePlayback(ack transport.PlaybackAck) (*PlaybackResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playback, first, err := s.playback.Acknowledge(ack, s.nowMS())
+	if err != nil || !first {
+		return nil, err
+	}
+	if err := s.recorder.RecordFirstAudioPlayed(s.cfg.SessionID, ack.TurnID, playback.PlayedAtMS); err != nil {
+		return nil, err
+	}
+	return &PlaybackResult{
+		TurnID:                       ack.TurnID,
+		FirstAudioPlayedAtMS:         playback.PlayedAtMS,
+		PerceivedFirstAudioLatencyMS: playback.PlayedAtMS - s.captureEnds[ack.TurnID],
+	}, nil
+}
+
+// CancelPlayback records that the transport cut off turnID's reply playback, for example on
+// barge-in, in the turn's baseline evidence.
+func (s *Session) CancelPlayback(turnID string, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.recorder.RecordPlaybackCancelled(s.cfg.SessionID, turnID, s.nowMS(), reason)
+}
+
+// WriteArtifacts writes the session audio (readable by rspp-cli export-recording) and the
+// timeline baseline (readable by rspp-cli debug-bundle and explain-decision).
+func (s *Session) WriteArtifacts() (Artifacts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writeArtifactsLocked()
+}
+
+// CallTranscript returns the completed turns for end-of-call analysis once the session ends.
+func (s *Session) CallTranscript() callanalysis.Transcript {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return callanalysis.Transcript{
+		SessionID:       s.cfg.SessionID,
+		TenantID:        s.cfg.TenantID,
+		PipelineVersion: s.cfg.PipelineVersion,
+		EndedAtMS:       s.nowMS(),
+		Turns:           append([]callanalysis.Turn(nil), s.history...),
+	}
+}
+
+// Status is a point-in-time view of a live session for runtime diagnostics.
+type Status struct {
+	SessionID       string
+	TenantID        string
+	PipelineVersion string
+	StartedAt       time.Time
+	Turns           int
+	Recorder        timeline.Occupancy
+}
+
+// Status reports the session identity, completed turn count, and recorder occupancy.
+func (s *Session) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Status{
+		SessionID:       s.cfg.SessionID,
+		TenantID:        s.cfg.TenantID,
+		PipelineVersion: s.cfg.PipelineVersion,
+		StartedAt:       s.startedAt,
+		Turns:           s.turns,
+		Recorder:        s.recorder.Occupancy(),
+	}
+}
+
+// completeTurnLocked runs one turn on the captured audio, or on text when it is non-nil.
+func (s *Session) completeTurnLocked(trigger controlplane.TurnTrigger, text *string) (*TurnResult, error) {
+	s.turns++
+	turnID := fmt.Sprintf("%s-turn-%d", s.cfg.SessionID, s.turns)
+	captured := append([]int16(nil), s.capture...)
+	s.capture = s.capture[:0]
+	trace := transport.IngressTraceContext(transport.IngressTraceInput{Traceparent: s.cfg.Traceparent, SessionID: s.cfg.SessionID, TurnID: turnID})
+
+	openRequest := turnarbiter.OpenRequest{
+		SessionID:             s.cfg.SessionID,
+		TurnID:                turnID,
+		EventID:               turnID + "-open",
+		RuntimeTimestampMS:    trigger.TriggeredAtMS,
+		WallClockTimestampMS:  s.wallClockMS(trigger.TriggeredAtMS),
+		PipelineVersion:       s.cfg.PipelineVersion,
+		AuthorityEpoch:        1,
+		SnapshotValid:         true,
+		AuthorityEpochValid:   true,
+		AuthorityAuthorized:   true,
+		SnapshotFailurePolicy: controlplane.OutcomeDefer,
+		PlanFailurePolicy:     controlplane.OutcomeReject,
+		Trace:                 trace,
+	}
+	if s.cfg.GateTurnOpen != nil {
+		openRequest = s.cfg.GateTurnOpen(openRequest)
+	}
+	open, err := s.arbiter.HandleTurnOpenProposed(openRequest)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", turnID, err)
+	}
+	if open.State != controlplane.TurnActive {
+		return nil, fmt.Errorf("open %s: turn not active (state %s)", turnID, open.State)
+	}
+
+	bound, err := s.bindSummaryLocked(open.Plan)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", turnID, err)
+	}
+
+	result := &TurnResult{TurnID: turnID, TriggeredAtMS: trigger.TriggeredAtMS, CaptureEndAtMS: *trigger.CaptureEndAtMS}
+	var (
+		outcomes    []timeline.InvocationOutcomeEvidence
+		providerErr error
+	)
+	if s.scheduler != nil {
+		outcomes, providerErr, err = s.runPlanLocked(result, open.Plan, trace, bound, captured, text)
+	} else {
+		outcomes, providerErr, err = s.runProvidersLocked(result, open.Plan, bound, captured, text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("complete %s: %w", turnID, err)
+	}
+	result.FirstOutputAtMS = s.nowMS()
+
+	var utterance *timeline.FallbackUtteranceEvidence
+	if providerErr != nil {
+		// Speak a fallback utterance rather than leaving the user in silence.
+		fallbackSeq, err := s.sequence.Next(s.cfg.SessionID)
+		if err != nil {
+			return nil, errors.Join(providerErr, err)
+		}
+		spoken, err := s.fallback.Emit(fallbackspeech.EmitInput{
+			SessionID:            s.cfg.SessionID,
+			TurnID:               turnID,
+			PipelineVersion:      s.cfg.PipelineVersion,
+			EventID:              turnID + "-fallback",
+			Reason:               "provider_failure",
+			RuntimeSequence:      fallbackSeq,
+			AuthorityEpoch:       1,
+			RuntimeTimestampMS:   result.FirstOutputAtMS,
+			WallClockTimestampMS: s.wallClockMS(result.FirstOutputAtMS),
+		})
+		if err != nil {
+			return nil, errors.Join(providerErr, err)
+		}
+		result.Reply, result.ReplyPCM = spoken.Evidence.Text, spoken.PCM
+		result.FallbackReason = spoken.Evidence.Reason
+		utterance = &spoken.Evidence
+	}
+
+	openedAt := trigger.TriggeredAtMS
+	firstOutputAt := result.FirstOutputAtMS
+	// The arbiter may emit a follow-on control signal at RuntimeSequence+1.
+	commitSeq, err := s.sequence.Reserve(s.cfg.SessionID, 2)
+	if err != nil {
+		return nil, fmt.Errorf("complete %s: %w", turnID, err)
+	}
+	var summaryID, summaryHash string
+	if bound.Summary != nil {
+		summaryID, summaryHash = bound.Summary.SummaryID, bound.Summary.SummaryHash
+	}
+	active, err := s.arbiter.HandleActive(turnarbiter.ActiveInput{
+		SessionID:            s.cfg.SessionID,
+		TurnID:               turnID,
+		EventID:              turnID + "-commit",
+		PipelineVersion:      s.cfg.PipelineVersion,
+		RuntimeSequence:      commitSeq,
+		RuntimeTimestampMS:   firstOutputAt,
+		WallClockTimestampMS: s.wallClockMS(firstOutputAt),
+		AuthorityEpoch:       1,
+		ProviderFailure:      providerErr != nil,
+		FallbackUtterance:    utterance,
+		TerminalSuccessReady: providerErr == nil,
+		Trigger:              &trigger,
+		SLA:                  s.turnSLA(),
+		SessionSummaryID:     summaryID,
+		SessionSummaryHash:   summaryHash,
+		Trace:                trace,
+		BaselineEvidence: &timeline.BaselineEvidence{
+			PayloadTags:          []eventabi.PayloadClass{eventabi.PayloadAudioRaw, eventabi.PayloadTextRaw},
+			PlanHash:             open.Plan.PlanHash,
+			SnapshotProvenance:   open.Plan.SnapshotProvenance,
+			InvocationOutcomes:   outcomes,
+			Cost:                 cost.TurnFromInvocations(s.cfg.TenantID, outcomes),
+			TurnOpenProposedAtMS: &openedAt,
+			TurnOpenAtMS:         &openedAt,
+			FirstOutputAtMS:      &firstOutputAt,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("complete %s: %w", turnID, err)
+	}
+	for _, event := range active.Events {
+		if event.Name == "commit" {
+			result.Committed = true
+		}
+	}
+
+	s.recordTurnLocked(result, captured)
+	s.captureEnds[turnID] = result.CaptureEndAtMS
+	s.history = append(s.history, callanalysis.Turn{TurnID: turnID, UserText: result.Transcript, AssistantText: result.Reply})
+	if s.summary != nil {
+		// The reply already went out; a failed summary leaves the turn pending and the node
+		// retries with the next completed turn.
+		_, _ = s.summary.CompleteTurn(summarization.Turn{TurnID: turnID, UserText: result.Transcript, AssistantText: result.Reply}, turnID+"-summary", s.wallClockMS(s.nowMS()))
+	}
+	artifacts, err := s.writeArtifactsLocked()
+	if err != nil {
+		return nil, err
+	}
+	result.Artifacts = artifacts
+	return result, nil
+}
+
+// bindSummaryLocked returns the session context for a turn. The summarization node starts with
+// the first turn whose plan enables summarization and summarizes with the session's LLM.
+// runProvidersLocked runs the turn's provider stages on the bound Providers. providerErr
+// reports a failed stage, which the turn answers with a fallback utterance; err is fatal.
+func (s *Session) runProvidersLocked(result *TurnResult, plan *controlplane.ResolvedTurnPlan, bound summarization.Context, captured []int16, text *string) (outcomes []timeline.InvocationOutcomeEvidence, providerErr error, err error) {
+	turnID := result.TurnID
+	// invoke records one provider call; usage reports what the call consumed and produced,
+	// with LLM tokens estimated from text since the sandbox interfaces do not report usage.
+	invoke := func(modality string, providerID string, call func() error, usage func() timeline.UsageCostEvidence) error {
+		startedMS := s.nowMS()
+		err := call()
+		latencyMS := s.nowMS() - startedMS
+		priced := s.cfg.PriceTable.Price(providerID, usage())
+		outcome := timeline.InvocationOutcomeEvidence{
+			ProviderInvocationID:     fmt.Sprintf("%s-%s", turnID, modality),
+			Modality:                 modality,
+			ProviderID:               providerID,
+			OutcomeClass:             "success",
+			RetryDecision:            "none",
+			AttemptCount:             1,
+			FinalAttemptLatencyMS:    latencyMS,
+			TotalInvocationLatencyMS: latencyMS,
+			Cost:                     &priced,
+		}
+		if err != nil {
+			outcome.OutcomeClass, outcome.RetryDecision = "infrastructure_failure", "fallback"
+		}
+		outcomes = append(outcomes, outcome)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", modality, providerID, err)
+		}
+		return nil
+	}
+	if text != nil {
+		result.Transcript = *text
+	} else {
+		providerErr = invoke("stt", SandboxSTTProviderID, func() (err error) {
+			result.Transcript, err = s.providers.STT.Transcribe(captured, s.cfg.SampleRateHz)
+			return err
+		}, func() timeline.UsageCostEvidence {
+			return timeline.UsageCostEvidence{AudioInputMS: cost.AudioMS(len(captured), s.cfg.SampleRateHz)}
+		})
+	}
+	if providerErr == nil {
+		providerErr = invoke("llm", SandboxLLMProviderID, func() (err error) {
+			result.Reply, err = s.respond(result.Transcript, bound)
+			return err
+		}, func() timeline.UsageCostEvidence {
+			return timeline.UsageCostEvidence{
+				InputTokens:     cost.EstimateTokens(result.Transcript),
+				OutputTokens:    cost.EstimateTokens(result.Reply),
+				TokensEstimated: true,
+			}
+		})
+	}
+	if providerErr == nil {
+		providerErr = invoke("tts", SandboxTTSProviderID, func() (err error) {
+			result.ReplyPCM, err = s.providers.TTS.Synthesize(result.Reply, s.cfg.SampleRateHz)
+			return err
+		}, func() timeline.UsageCostEvidence {
+			return timeline.UsageCostEvidence{AudioOutputMS: cost.AudioMS(len(result.ReplyPCM), s.cfg.SampleRateHz)}
+		})
+	}
+	return outcomes, providerErr, nil
+}
+
+func (s *Session) bindSummaryLocked(plan *controlplane.ResolvedTurnPlan) (summarization.Context, error) {
+	if s.summary == nil && plan != nil && plan.Summarization != nil {
+		summarizer := s.summarizer()
+		node, err := summarization.NewNode(summarization.Config{
+			SessionID:       s.cfg.SessionID,
+			PipelineVersion: s.cfg.PipelineVersion,
+			Settings:        *plan.Summarization,
+			RetentionPolicy: replay.DefaultRetentionPolicy(s.cfg.TenantID),
+		}, summarizer, s.recorder)
+		if err != nil {
+			return summarization.Context{}, fmt.Errorf("summarization: %w", err)
+		}
+		s.summary = node
+	}
+	if s.summary == nil {
+		return summarization.Context{}, nil
+	}
+	return s.summary.Bind(s.wallClockMS(s.nowMS())), nil
+}
+
+// summarizer condenses turns with the bound LLM, or through one-node plans when the session
+// runs execution plans. Summary attempts are not turn evidence and are not recorded.
+func (s *Session) summarizer() summarization.Summarizer {
+	if s.scheduler != nil {
+		scheduler := executor.NewSchedulerWithProviderInvoker(localadmission.NewEvaluatorFromEnv(), s.cfg.Invoker)
+		return responderSummarizer{responder: planResponder{scheduler: scheduler, sessionID: s.cfg.SessionID, version: s.cfg.PipelineVersion}}
+	}
+	if summarizer, ok := s.providers.LLM.(summarization.Summarizer); ok {
+		return summarizer
+	}
+	return responderSummarizer{responder: s.providers.LLM}
+}
+
+// respond asks the LLM for the reply with the bound session context.
+func (s *Session) respond(transcript string, bound summarization.Context) (string, error) {
+	if responder, ok := s.providers.LLM.(ContextResponder); ok {
+		return responder.RespondInContext(transcript, bound)
+	}
+	return s.providers.LLM.Respond(contextPrompt(bound, transcript))
+}
+
+// contextPrompt folds the bound summary and the turns since it into the LLM prompt; without
+// bound context the prompt is the transcript alone.
+func contextPrompt(bound summarization.Context, transcript string) string {
+	var lines []string
+	if summary := (contracts.InvocationRequest{SessionSummary: bound.InvocationSummary()}).SessionSummaryContext(); summary != "" {
+		lines = append(lines, summary)
+	}
+	for _, turn := range bound.RecentTurns {
+		lines = append(lines, "User: "+turn.UserText, "Assistant: "+turn.AssistantText)
+	}
+	if len(lines) == 0 {
+		return transcript
+	}
+	return strings.Join(append(lines, "User: "+transcript), "\n")
+}
+
+// responderSummarizer summarizes with a plain Responder by prompting it for a summary.
+type responderSummarizer struct {
+	responder Responder
+}
+
+func (r responderSummarizer) Summarize(req summarization.Request) (string, error) {
+	lines := []string{fmt.Sprintf("Summarize this conversation in at most %d tokens.", req.MaxSummaryTokens)}
+	if req.PriorSummary != "" {
+		lines = append(lines, "Summary so far: "+req.PriorSummary)
+	}
+	for _, turn := range req.Turns {
+		lines = append(lines, "User: "+turn.UserText, "Assistant: "+turn.AssistantText)
+	}
+	return r.responder.Respond(strings.Join(lines, "\n"))
+}
+
+func (s *Session) recordTurnLocked(result *TurnResult, captured []int16) {
+	replyEndMS := result.FirstOutputAtMS + int64(len(result.ReplyPCM))*1000/int64(s.cfg.SampleRateHz)
+	if len(captured) > 0 {
+		s.audio.Segments = append(s.audio.Segments, recording.AudioSegment{
+			Track: recording.TrackUser, EventID: result.TurnID + "-user", PTSMS: result.TriggeredAtMS,
+			SampleRateHz: s.cfg.SampleRateHz, Channels: 1, PCM: captured,
+		})
+	}
+	if len(result.ReplyPCM) > 0 {
+		s.audio.Segments = append(s.audio.Segments, recording.AudioSegment{
+			Track: recording.TrackAssistant, EventID: result.TurnID + "-assistant", PTSMS: result.FirstOutputAtMS,
+			SampleRateHz: s.cfg.SampleRateHz, Channels: 1, PCM: result.ReplyPCM,
+		})
+	}
+	if result.Transcript != "" {
+		s.audio.Transcript = append(s.audio.Transcript, recording.TranscriptSegment{
+			Track: recording.TrackUser, StartMS: result.TriggeredAtMS, EndMS: result.CaptureEndAtMS,
+			Text: result.Transcript, PayloadClass: eventabi.PayloadTextRaw,
+		})
+	}
+	s.audio.Transcript = append(s.audio.Transcript, recording.TranscriptSegment{
+		Track: recording.TrackAssistant, StartMS: result.FirstOutputAtMS, EndMS: replyEndMS,
+		Text: result.Reply, PayloadClass: eventabi.PayloadTextRaw,
+	})
+}
+
+func (s *Session) writeArtifactsLocked() (Artifacts, error) {
+	artifacts := Artifacts{
+		SessionAudioPath: filepath.Join(s.cfg.ArtifactsDir, s.cfg.SessionID+"-session-audio.json"),
+		BaselinePath:     filepath.Join(s.cfg.ArtifactsDir, s.cfg.SessionID+"-baseline.json"),
+	}
+	if err := s.audio.Validate(); err != nil {
+		return Artifacts{}, err
+	}
+	if err := os.MkdirAll(s.cfg.ArtifactsDir, 0o755); err != nil {
+		return Artifacts{}, err
+	}
+	data, err := json.MarshalIndent(s.audio, "", "  ")
+	if err != nil {
+		return Artifacts{}, err
+	}
+	if err := os.WriteFile(artifacts.SessionAudioPath, data, 0o644); err != nil {
+		return Artifacts{}, err
+	}
+	if entries := s.recorder.BaselineEntries(); len(entries) > 0 {
+		if err := timeline.WriteBaselineArtifact(artifacts.BaselinePath, entries); err != nil {
+			return Artifacts{}, err
+		}
+	}
+	return artifacts, nil
+}
+
+func (s *Session) captureSignal(name string) eventabi.ControlSignal {
+	return eventabi.ControlSignal{Signal: name, EmittedBy: "RK-22", RuntimeTimestampMS: s.nowMS()}
+}
+
+func (s *Session) nowMS() int64 {
+	return s.cfg.Clock().Sub(s.startedAt).Milliseconds()
+}
+
+func (s *Session) wallClockMS(sessionMS int64) int64 {
+	return s.startedAt.UnixMilli() + sessionMS
+}
+
+// turnSLA annotates a turn with the tenant's SLA tier; nil when the tenant declared none.
+func (s *Session) turnSLA() *controlplane.TurnSLA {
+	if s.cfg.SLA == nil {
+		return nil
+	}
+	return &controlplane.TurnSLA{TenantID: s.cfg.TenantID, SLATier: *s.cfg.SLA}
+}
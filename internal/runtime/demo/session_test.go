@@ -0,0 +1,866 @@
+package demo
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/recording"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/cancellation"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/executor"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/nodecache"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/healthscore"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/invocation"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/transport"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/turnarbiter"
+)
+
+// steppingClock advances by stepMS on every read so session timestamps are deterministic.
+func steppingClock(stepMS int64) func() time.Time {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(time.Duration(stepMS) * time.Millisecond)
+		return now
+	}
+}
+
+func voicedPCM(samples int) []int16 {
+	pcm := make([]int16, samples)
+	for i := range pcm {
+		pcm[i] = 4000
+	}
+	return pcm
+}
+
+func TestSessionRoundTripWritesArtifacts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	session, err := NewSession(SessionConfig{SessionID: "demo-1", ArtifactsDir: dir, Clock: steppingClock(10)}, SandboxProviders())
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	if turn, err := session.AppendAudio(voicedPCM(160)); err != nil || turn != nil {
+		t.Fatalf("expected audio before capture_start to be dropped, got %+v err=%v", turn, err)
+	}
+	if turn, err := session.EndCapture(); err != nil || turn != nil {
+		t.Fatalf("expected capture_end without capture to be ignored, got %+v err=%v", turn, err)
+	}
+
+	if err := session.StartCapture(); err != nil {
+		t.Fatalf("unexpected capture_start error: %v", err)
+	}
+	session.AppendAudio(voicedPCM(DefaultSampleRateHz / 2))
+	turn, err := session.EndCapture()
+	if err != nil || turn == nil {
+		t.Fatalf("expected completed turn, got %+v err=%v", turn, err)
+	}
+	if !turn.Committed || turn.TurnID != "demo-1-turn-1" || !strings.Contains(turn.Transcript, "0.5 seconds") || len(turn.ReplyPCM) == 0 {
+		t.Fatalf("unexpected turn result: %+v", turn)
+	}
+	if turn.FirstOutputAtMS <= turn.CaptureEndAtMS || turn.CaptureEndAtMS <= turn.TriggeredAtMS {
+		t.Fatalf("expected ordered turn timestamps, got %+v", turn)
+	}
+
+	audio, err := recording.LoadSessionAudio(turn.Artifacts.SessionAudioPath)
+	if err != nil {
+		t.Fatalf("unexpected session audio error: %v", err)
+	}
+	if len(audio.Segments) != 2 || len(audio.Transcript) != 2 || audio.Segments[1].Track != recording.TrackAssistant {
+		t.Fatalf("expected user and assistant tracks, got %+v", audio)
+	}
+	baseline, err := timeline.ReadBaselineArtifact(turn.Artifacts.BaselinePath)
+	if err != nil {
+		t.Fatalf("unexpected baseline error: %v", err)
+	}
+	entry := baseline.Entries[0]
+	if entry.Trigger == nil || entry.Trigger.Mode != controlplane.TriggerPushToTalk || entry.Trigger.CaptureEndReason != "capture_end" || len(entry.InvocationOutcomes) != 3 {
+		t.Fatalf("expected push-to-talk trigger and sandbox invocations in baseline, got %+v", entry)
+	}
+	if entry.Cost == nil || entry.Cost.TenantID != defaultTenantID || len(entry.Cost.Providers) != 3 || entry.Cost.AudioInputMS != 500 ||
+		entry.Cost.AudioOutputMS == 0 || entry.Cost.OutputTokens == 0 || !entry.Cost.TokensEstimated || entry.Cost.Unpriced || entry.Cost.EstimatedCostUSD != 0 {
+		t.Fatalf("expected free sandbox usage rolled up in baseline cost, got %+v", entry.Cost)
+	}
+	if filepath.Dir(turn.Artifacts.BaselinePath) != dir {
+		t.Fatalf("expected artifacts under %s, got %+v", dir, turn.Artifacts)
+	}
+	transcript := session.CallTranscript()
+	if transcript.SessionID != "demo-1" || len(transcript.Turns) != 1 || transcript.Turns[0].UserText != turn.Transcript || transcript.Turns[0].AssistantText != turn.Reply {
+		t.Fatalf("expected call transcript of the completed turn, got %+v", transcript)
+	}
+	status := session.Status()
+	if status.SessionID != "demo-1" || status.Turns != 1 || status.Recorder.Baseline.Used != 1 || status.StartedAt.IsZero() {
+		t.Fatalf("expected status of one recorded turn, got %+v", status)
+	}
+}
+
+func TestSessionMaxCaptureEndsTurn(t *testing.T) {
+	t.Parallel()
+
+	session, err := NewSession(SessionConfig{SessionID: "demo-2", ArtifactsDir: t.TempDir(), MaxCaptureMS: 25, Clock: steppingClock(10)}, SandboxProviders())
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	session.StartCapture()
+	var turn *TurnResult
+	for i := 0; i < 5 && turn == nil; i++ {
+		if turn, err = session.AppendAudio(voicedPCM(160)); err != nil {
+			t.Fatalf("unexpected append error: %v", err)
+		}
+	}
+	if turn == nil || !turn.Committed {
+		t.Fatalf("expected max capture to complete the turn, got %+v", turn)
+	}
+	if turn, err := session.EndCapture(); err != nil || turn != nil {
+		t.Fatalf("expected late capture_end to be ignored, got %+v err=%v", turn, err)
+	}
+}
+
+func TestSessionTagsTurnsWithTenantSLA(t *testing.T) {
+	t.Parallel()
+
+	tier := controlplane.SLATier{Tier: "gold", MaxFirstOutputLatencyMS: 800, MinAvailability: 0.999}
+	session, err := NewSession(SessionConfig{SessionID: "demo-sla", TenantID: "tenant-a", ArtifactsDir: t.TempDir(), Clock: steppingClock(10), SLA: &tier}, SandboxProviders())
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	session.StartCapture()
+	session.AppendAudio(voicedPCM(160))
+	turn, err := session.EndCapture()
+	if err != nil || turn == nil {
+		t.Fatalf("expected completed turn, got %+v err=%v", turn, err)
+	}
+	baseline, err := timeline.ReadBaselineArtifact(turn.Artifacts.BaselinePath)
+	if err != nil {
+		t.Fatalf("unexpected baseline error: %v", err)
+	}
+	sla := baseline.Entries[0].SLA
+	if sla == nil || sla.TenantID != "tenant-a" || sla.SLATier != tier {
+		t.Fatalf("expected tenant sla tag in baseline evidence, got %+v", sla)
+	}
+}
+
+func TestSessionPlaybackAckRecordsPerceivedFirstAudio(t *testing.T) {
+	t.Parallel()
+
+	session, err := NewSession(SessionConfig{SessionID: "demo-4", ArtifactsDir: t.TempDir(), Clock: steppingClock(10)}, SandboxProviders())
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	session.StartCapture()
+	session.AppendAudio(voicedPCM(DefaultSampleRateHz / 4))
+	turn, err := session.EndCapture()
+	if err != nil || turn == nil {
+		t.Fatalf("expected completed turn, got %+v err=%v", turn, err)
+	}
+	if _, err := session.AcknowledgePlayback(transport.PlaybackAck{TurnID: turn.TurnID, PlayedUntilMS: 5}); err == nil {
+		t.Fatalf("expected playback ack before reply egress to fail")
+	}
+	if err := session.RecordReplyEgress(turn.TurnID); err != nil {
+		t.Fatalf("unexpected egress error: %v", err)
+	}
+	if result, err := session.AcknowledgePlayback(transport.PlaybackAck{TurnID: turn.TurnID}); err != nil || result != nil {
+		t.Fatalf("expected ack with nothing played to yield no result, got %+v err=%v", result, err)
+	}
+	result, err := session.AcknowledgePlayback(transport.PlaybackAck{TurnID: turn.TurnID, PlayedUntilMS: 5})
+	if err != nil || result == nil {
+		t.Fatalf("expected perceived first-audio result, got %+v err=%v", result, err)
+	}
+	if result.FirstAudioPlayedAtMS < turn.FirstOutputAtMS || result.PerceivedFirstAudioLatencyMS != result.FirstAudioPlayedAtMS-turn.CaptureEndAtMS {
+		t.Fatalf("expected playback after first output measured from capture end, got %+v turn=%+v", result, turn)
+	}
+	if err := session.CancelPlayback(turn.TurnID, "barge_in"); err != nil {
+		t.Fatalf("unexpected playback cancel error: %v", err)
+	}
+
+	artifacts, err := session.WriteArtifacts()
+	if err != nil {
+		t.Fatalf("unexpected artifacts error: %v", err)
+	}
+	baseline, err := timeline.ReadBaselineArtifact(artifacts.BaselinePath)
+	if err != nil {
+		t.Fatalf("unexpected baseline error: %v", err)
+	}
+	if played := baseline.Entries[0].FirstAudioPlayedAtMS; played == nil || *played != result.FirstAudioPlayedAtMS {
+		t.Fatalf("expected baseline annotated with first audio played, got %v", played)
+	}
+	if entry := baseline.Entries[0]; entry.PlaybackCancelledAtMS == nil || entry.PlaybackCancelReason != "barge_in" {
+		t.Fatalf("expected baseline annotated with the barge-in cancel, got %v %q", entry.PlaybackCancelledAtMS, entry.PlaybackCancelReason)
+	}
+}
+
+type failingLLM struct{}
+
+func (failingLLM) Respond(string) (string, error) { return "", errors.New("llm unavailable") }
+
+func TestSessionProviderFailureSpeaksFallbackUtterance(t *testing.T) {
+	t.Parallel()
+
+	providers := SandboxProviders()
+	providers.LLM = failingLLM{}
+	session, err := NewSession(SessionConfig{SessionID: "demo-3", ArtifactsDir: t.TempDir(), Clock: steppingClock(10)}, providers)
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	session.StartCapture()
+	session.AppendAudio(voicedPCM(DefaultSampleRateHz / 4))
+	turn, err := session.EndCapture()
+	if err != nil || turn == nil {
+		t.Fatalf("expected fallback turn instead of error, got %+v err=%v", turn, err)
+	}
+	if turn.FallbackReason != "provider_failure" || !turn.Committed || !strings.HasPrefix(turn.Reply, "Sorry") || len(turn.ReplyPCM) == 0 {
+		t.Fatalf("unexpected fallback turn: %+v", turn)
+	}
+
+	baseline, err := timeline.ReadBaselineArtifact(turn.Artifacts.BaselinePath)
+	if err != nil {
+		t.Fatalf("unexpected baseline error: %v", err)
+	}
+	entry := baseline.Entries[0]
+	if entry.TerminalOutcome != "fallback" || entry.TerminalReason != "provider_failure" || entry.FallbackUtterance == nil || entry.FallbackUtterance.Source != timeline.FallbackSourcePresynthesized {
+		t.Fatalf("expected fallback terminal outcome with utterance evidence, got %+v", entry)
+	}
+	if len(entry.InvocationOutcomes) != 2 || entry.InvocationOutcomes[1].OutcomeClass != "infrastructure_failure" {
+		t.Fatalf("expected failed llm invocation recorded, got %+v", entry.InvocationOutcomes)
+	}
+}
+
+func TestSessionGateTurnOpenDefersTurns(t *testing.T) {
+	t.Parallel()
+
+	var gated []string
+	session, err := NewSession(SessionConfig{
+		SessionID:    "demo-gate-1",
+		ArtifactsDir: t.TempDir(),
+		Clock:        steppingClock(10),
+		GateTurnOpen: func(req turnarbiter.OpenRequest) turnarbiter.OpenRequest {
+			gated = append(gated, req.TurnID)
+			req.SnapshotValid = false
+			req.SnapshotFailurePolicy = controlplane.OutcomeDefer
+			return req
+		},
+	}, SandboxProviders())
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	if _, err := session.SubmitText("hello"); err == nil || !strings.Contains(err.Error(), "turn not active") {
+		t.Fatalf("expected gated turn to stay closed, got %v", err)
+	}
+	if len(gated) != 1 || gated[0] != "demo-gate-1-turn-1" {
+		t.Fatalf("expected the turn-open request gated once, got %+v", gated)
+	}
+}
+
+func TestSessionTextTurnAndCancelledCapture(t *testing.T) {
+	t.Parallel()
+
+	session, err := NewSession(SessionConfig{SessionID: "demo-4", ArtifactsDir: t.TempDir(), Clock: steppingClock(10)}, SandboxProviders())
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	if cancelled, err := session.CancelCapture(); err != nil || cancelled {
+		t.Fatalf("expected cancel without capture to be a no-op, got %t err=%v", cancelled, err)
+	}
+	session.StartCapture()
+	session.AppendAudio(voicedPCM(DefaultSampleRateHz / 4))
+	if _, err := session.SubmitText("hello"); err == nil {
+		t.Fatalf("expected text turn during an open capture to fail")
+	}
+	if cancelled, err := session.CancelCapture(); err != nil || !cancelled {
+		t.Fatalf("expected open capture to be cancelled, got %t err=%v", cancelled, err)
+	}
+	if turn, err := session.EndCapture(); err != nil || turn != nil {
+		t.Fatalf("expected no turn from a cancelled capture, got %+v err=%v", turn, err)
+	}
+	if _, err := session.SubmitText("  "); err == nil {
+		t.Fatalf("expected empty text turn to fail")
+	}
+
+	turn, err := session.SubmitText("what time is it")
+	if err != nil || turn == nil {
+		t.Fatalf("expected text turn, got %+v err=%v", turn, err)
+	}
+	if turn.TurnID != "demo-4-turn-1" || turn.Transcript != "what time is it" || !strings.Contains(turn.Reply, "what time is it") || !turn.Committed {
+		t.Fatalf("unexpected text turn: %+v", turn)
+	}
+	baseline, err := timeline.ReadBaselineArtifact(turn.Artifacts.BaselinePath)
+	if err != nil {
+		t.Fatalf("unexpected baseline error: %v", err)
+	}
+	if outcomes := baseline.Entries[0].InvocationOutcomes; len(outcomes) != 2 || outcomes[0].Modality != "llm" {
+		t.Fatalf("expected stt skipped for a text turn, got %+v", outcomes)
+	}
+}
+
+func TestNewSessionValidatesConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		cfg       SessionConfig
+		providers Providers
+		shouldErr bool
+	}{
+		{name: "valid", cfg: SessionConfig{SessionID: "demo", ArtifactsDir: "out"}, providers: SandboxProviders()},
+		{name: "missing session", cfg: SessionConfig{ArtifactsDir: "out"}, providers: SandboxProviders(), shouldErr: true},
+		{name: "missing artifacts dir", cfg: SessionConfig{SessionID: "demo"}, providers: SandboxProviders(), shouldErr: true},
+		{name: "missing provider", cfg: SessionConfig{SessionID: "demo", ArtifactsDir: "out"}, providers: Providers{STT: SandboxSTT{}, LLM: SandboxLLM{}}, shouldErr: true},
+		{name: "invalid sla tier", cfg: SessionConfig{SessionID: "demo", ArtifactsDir: "out", SLA: &controlplane.SLATier{Tier: "gold"}}, providers: SandboxProviders(), shouldErr: true},
+	}
+	for _, tc := range tests {
+		_, err := NewSession(tc.cfg, tc.providers)
+		if tc.shouldErr != (err != nil) {
+			t.Fatalf("%s: expected error=%t, got %v", tc.name, tc.shouldErr, err)
+		}
+	}
+}
+
+func TestSessionMemoizesTTSAcrossSessionsWithSharedNodeCache(t *testing.T) {
+	t.Parallel()
+
+	catalog, err := registry.NewCatalog(SandboxAdapters())
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	cache := nodecache.New[executor.SchedulingDecision](8)
+	var replies [][]int16
+	for i, sessionID := range []string{"demo-cache-1", "demo-cache-2"} {
+		session, err := NewSession(SessionConfig{
+			SessionID:    sessionID,
+			ArtifactsDir: t.TempDir(),
+			Clock:        steppingClock(10),
+			Invoker:      invocation.NewController(catalog),
+			NodeCache:    cache,
+		}, Providers{})
+		if err != nil {
+			t.Fatalf("unexpected session error: %v", err)
+		}
+		turn, err := session.SubmitText("what time is it")
+		if err != nil || turn == nil || !turn.Committed {
+			t.Fatalf("expected committed text turn, got %+v err=%v", turn, err)
+		}
+		replies = append(replies, turn.ReplyPCM)
+
+		evidence := session.recorder.NodeCacheEntries()
+		if len(evidence) != 1 || evidence[0].NodeID != "tts" || evidence[0].Hit != (i == 1) {
+			t.Fatalf("expected tts cache hit=%v evidence, got %+v", i == 1, evidence)
+		}
+		if evidence[0].CacheKey == "" || evidence[0].PlanHash == "" || evidence[0].CacheKey == evidence[0].PlanHash {
+			t.Fatalf("expected the node input cache key recorded beside the turn plan hash, got %+v", evidence[0])
+		}
+		baseline, err := timeline.ReadBaselineArtifact(turn.Artifacts.BaselinePath)
+		if err != nil {
+			t.Fatalf("unexpected baseline error: %v", err)
+		}
+		if invocations := len(baseline.Entries[0].InvocationOutcomes); invocations != 2-i {
+			t.Fatalf("expected %d provider invocations with the llm and a tts miss only, got %d", 2-i, invocations)
+		}
+	}
+	if len(replies[0]) == 0 || len(replies[0]) != len(replies[1]) || cache.Len() != 1 {
+		t.Fatalf("expected the cached reply audio served to the second session, got %d and %d samples, cache len %d", len(replies[0]), len(replies[1]), cache.Len())
+	}
+}
+
+// summarizingResolver resolves default CP turn-start bundles with summarization enabled.
+type summarizingResolver struct {
+	settings controlplane.Summarization
+}
+
+func (r summarizingResolver) ResolveTurnStartBundle(in turnarbiter.TurnStartBundleInput) (turnarbiter.TurnStartBundle, error) {
+	bundle, err := turnarbiter.NewControlPlaneBundleResolverWithBackends(turnarbiter.ControlPlaneBackends{}).ResolveTurnStartBundle(in)
+	settings := r.settings
+	bundle.Summarization = &settings
+	return bundle, err
+}
+
+// promptRecordingLLM records every prompt it is asked to answer.
+type promptRecordingLLM struct {
+	prompts *[]string
+}
+
+func (l promptRecordingLLM) Respond(prompt string) (string, error) {
+	*l.prompts = append(*l.prompts, prompt)
+	return fmt.Sprintf("reply %d", len(*l.prompts)), nil
+}
+
+func TestSessionSummarizesContextAndBindsItIntoLaterTurns(t *testing.T) {
+	t.Parallel()
+
+	session, err := NewSession(SessionConfig{
+		SessionID:         "demo-summary-1",
+		ArtifactsDir:      t.TempDir(),
+		Clock:             steppingClock(10),
+		TurnStartResolver: summarizingResolver{settings: controlplane.Summarization{IntervalTurns: 2, ProviderID: SandboxLLMProviderID, MaxSummaryTokens: 64}},
+	}, SandboxProviders())
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	var turns []*TurnResult
+	for _, text := range []string{"book a table", "for four people", "at seven"} {
+		turn, err := session.SubmitText(text)
+		if err != nil {
+			t.Fatalf("unexpected turn error: %v", err)
+		}
+		turns = append(turns, turn)
+	}
+
+	summaries := session.recorder.SessionSummaryEntries()
+	if len(summaries) != 1 || summaries[0].SummaryID != "demo-summary-1-summary-1" || summaries[0].TurnID != "demo-summary-1-turn-2" || len(summaries[0].SourceTurnIDs) != 2 {
+		t.Fatalf("expected one summary over the first two turns, got %+v", summaries)
+	}
+	if strings.Contains(turns[1].Reply, "Earlier in this call") {
+		t.Fatalf("expected no summary bound before one was produced, got %q", turns[1].Reply)
+	}
+	if !strings.Contains(turns[2].Reply, "Earlier in this call: 2 turns; last request: for four people") {
+		t.Fatalf("expected the summary bound into the third turn, got %q", turns[2].Reply)
+	}
+	baseline, err := timeline.ReadBaselineArtifact(turns[2].Artifacts.BaselinePath)
+	if err != nil {
+		t.Fatalf("unexpected baseline error: %v", err)
+	}
+	if len(baseline.Entries) != 3 || baseline.Entries[1].SessionSummaryID != "" || baseline.Entries[2].SessionSummaryID != summaries[0].SummaryID || baseline.Entries[2].SessionSummaryHash != summaries[0].SummaryHash {
+		t.Fatalf("expected the third turn's baseline to carry the bound summary, got %+v", baseline.Entries)
+	}
+}
+
+func TestSessionFoldsContextIntoPlainResponderPrompts(t *testing.T) {
+	t.Parallel()
+
+	var prompts []string
+	providers := SandboxProviders()
+	providers.LLM = promptRecordingLLM{prompts: &prompts}
+	session, err := NewSession(SessionConfig{
+		SessionID:         "demo-summary-2",
+		ArtifactsDir:      t.TempDir(),
+		Clock:             steppingClock(10),
+		TurnStartResolver: summarizingResolver{settings: controlplane.Summarization{IntervalTurns: 2, ProviderID: "llm-plain", MaxSummaryTokens: 32}},
+	}, providers)
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	for _, text := range []string{"hello", "what time is it", "thanks"} {
+		if _, err := session.SubmitText(text); err != nil {
+			t.Fatalf("unexpected turn error: %v", err)
+		}
+	}
+
+	// Prompts: turn 1, turn 2, the summary after turn 2, then turn 3 with the summary bound.
+	if len(prompts) != 4 {
+		t.Fatalf("expected three replies and one summary prompt, got %q", prompts)
+	}
+	if prompts[0] != "hello" || prompts[1] != "User: hello\nAssistant: reply 1\nUser: what time is it" {
+		t.Fatalf("expected recent turns folded into the second prompt, got %q", prompts[:2])
+	}
+	if !strings.HasPrefix(prompts[2], "Summarize this conversation in at most 32 tokens.") {
+		t.Fatalf("expected a summary prompt after the second turn, got %q", prompts[2])
+	}
+	if prompts[3] != "Summary of the conversation so far: reply 3\nUser: thanks" {
+		t.Fatalf("expected the summary bound into the third prompt, got %q", prompts[3])
+	}
+}
+
+func TestSessionRunsTurnsThroughExecutionPlan(t *testing.T) {
+	t.Parallel()
+
+	failingTTS := contracts.StaticAdapter{
+		ID:   "tts-down",
+		Mode: contracts.ModalityTTS,
+		InvokeFn: func(contracts.InvocationRequest) (contracts.Outcome, error) {
+			return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Reason: "provider_unavailable"}, nil
+		},
+	}
+	sandbox := SandboxAdapters()
+	tests := []struct {
+		name         string
+		adapters     []contracts.Adapter
+		wantFallback bool
+		wantTTS      string
+	}{
+		{name: "sandbox catalog", adapters: sandbox, wantTTS: SandboxTTSProviderID},
+		{name: "failed tts", adapters: []contracts.Adapter{sandbox[0], sandbox[1], failingTTS}, wantFallback: true, wantTTS: "tts-down"},
+	}
+	for idx, tt := range tests {
+		catalog, err := registry.NewCatalog(tt.adapters)
+		if err != nil {
+			t.Fatalf("%s: unexpected catalog error: %v", tt.name, err)
+		}
+		session, err := NewSession(SessionConfig{
+			SessionID:    fmt.Sprintf("demo-plan-%d", idx),
+			ArtifactsDir: t.TempDir(),
+			Clock:        steppingClock(10),
+			Invoker:      invocation.NewController(catalog),
+		}, Providers{})
+		if err != nil {
+			t.Fatalf("%s: unexpected session error: %v", tt.name, err)
+		}
+		session.StartCapture()
+		session.AppendAudio(voicedPCM(DefaultSampleRateHz / 2))
+		turn, err := session.EndCapture()
+		if err != nil || turn == nil {
+			t.Fatalf("%s: expected completed turn, got %+v err=%v", tt.name, turn, err)
+		}
+		if !strings.Contains(turn.Transcript, "0.5 seconds") || len(turn.ReplyPCM) == 0 || (turn.FallbackReason != "") != tt.wantFallback {
+			t.Fatalf("%s: unexpected turn result: %+v", tt.name, turn)
+		}
+		if !tt.wantFallback && !strings.Contains(turn.Reply, turn.Transcript) {
+			t.Fatalf("%s: expected the llm prompted with the stt transcript, got reply %q", tt.name, turn.Reply)
+		}
+
+		baseline, err := timeline.ReadBaselineArtifact(turn.Artifacts.BaselinePath)
+		if err != nil {
+			t.Fatalf("%s: unexpected baseline error: %v", tt.name, err)
+		}
+		outcomes := baseline.Entries[0].InvocationOutcomes
+		if len(outcomes) != 3 || outcomes[0].ProviderID != SandboxSTTProviderID || outcomes[1].ProviderID != SandboxLLMProviderID || outcomes[2].ProviderID != tt.wantTTS {
+			t.Fatalf("%s: expected invocation outcomes of the catalog providers, got %+v", tt.name, outcomes)
+		}
+		if outcomes[0].EndpointingEnforcement != "vad_emulated" || outcomes[1].EndpointingEnforcement != "" {
+			t.Fatalf("%s: expected the plan endpointing enforced by the runtime endpointer, got %+v", tt.name, outcomes)
+		}
+		if cost := baseline.Entries[0].Cost; cost == nil || cost.AudioInputMS != 500 || cost.OutputTokens == 0 {
+			t.Fatalf("%s: expected priced attempt usage in baseline cost, got %+v", tt.name, cost)
+		}
+	}
+}
+
+func TestSessionEvaluatesPlanDegradeLadderAtTurnLoad(t *testing.T) {
+	t.Parallel()
+
+	// The default resolved plan shortens LLM output to 64 tokens from 85 plans deep and sheds
+	// at 100.
+	tests := []struct {
+		name      string
+		executing int
+		wantCap   int
+		wantShed  bool
+	}{
+		{name: "idle runtime", executing: 0, wantCap: 0},
+		{name: "loaded runtime", executing: 85, wantCap: 64},
+		{name: "saturated runtime", executing: 100, wantShed: true},
+	}
+	for idx, tt := range tests {
+		gotCap, invoked := 0, false
+		llm := contracts.StaticAdapter{
+			ID:   "llm-capture",
+			Mode: contracts.ModalityLLM,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				gotCap, invoked = req.MaxOutputTokens, true
+				return contracts.Outcome{Class: contracts.OutcomeSuccess, Text: "ok"}, nil
+			},
+		}
+		sandbox := SandboxAdapters()
+		catalog, err := registry.NewCatalog([]contracts.Adapter{sandbox[0], llm, sandbox[2]})
+		if err != nil {
+			t.Fatalf("%s: unexpected catalog error: %v", tt.name, err)
+		}
+		load := &TurnLoad{}
+		for range tt.executing {
+			load.enter()
+		}
+		session, err := NewSession(SessionConfig{
+			SessionID:    fmt.Sprintf("demo-degrade-%d", idx),
+			ArtifactsDir: t.TempDir(),
+			Clock:        steppingClock(10),
+			Invoker:      invocation.NewController(catalog),
+			TurnLoad:     load,
+		}, Providers{})
+		if err != nil {
+			t.Fatalf("%s: unexpected session error: %v", tt.name, err)
+		}
+		turn, err := session.SubmitText("hello")
+		if err != nil || turn == nil {
+			t.Fatalf("%s: expected completed text turn, got %+v err=%v", tt.name, turn, err)
+		}
+		if tt.wantShed {
+			if invoked || turn.FallbackReason == "" {
+				t.Fatalf("%s: expected the shed plan to skip the llm and fall back, got invoked=%t turn=%+v", tt.name, invoked, turn)
+			}
+		} else if !invoked || gotCap != tt.wantCap {
+			t.Fatalf("%s: expected llm output cap %d at depth %d, got invoked=%t cap=%d", tt.name, tt.wantCap, tt.executing, invoked, gotCap)
+		}
+		if load.Executing() != int64(tt.executing) {
+			t.Fatalf("%s: expected the turn to leave the load at %d, got %d", tt.name, tt.executing, load.Executing())
+		}
+	}
+}
+
+func TestSessionJoinsHandshakeTrace(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		traceparent string
+		wantTraceID string
+	}{
+		{name: "inbound trace joined", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "malformed trace replaced", traceparent: "not-a-traceparent"},
+	}
+	for idx, tt := range tests {
+		catalog, err := registry.NewCatalog(SandboxAdapters())
+		if err != nil {
+			t.Fatalf("%s: unexpected catalog error: %v", tt.name, err)
+		}
+		session, err := NewSession(SessionConfig{
+			SessionID:    fmt.Sprintf("demo-trace-%d", idx),
+			ArtifactsDir: t.TempDir(),
+			Clock:        steppingClock(10),
+			Invoker:      invocation.NewController(catalog),
+			Traceparent:  tt.traceparent,
+		}, Providers{})
+		if err != nil {
+			t.Fatalf("%s: unexpected session error: %v", tt.name, err)
+		}
+		turn, err := session.SubmitText("hello")
+		if err != nil || turn == nil {
+			t.Fatalf("%s: expected completed text turn, got %+v err=%v", tt.name, turn, err)
+		}
+		baseline, err := timeline.ReadBaselineArtifact(turn.Artifacts.BaselinePath)
+		if err != nil {
+			t.Fatalf("%s: unexpected baseline error: %v", tt.name, err)
+		}
+		traceID := baseline.Entries[0].TraceID
+		if len(traceID) != 32 || (tt.wantTraceID != "" && traceID != tt.wantTraceID) {
+			t.Fatalf("%s: expected turn trace id %q, got %q", tt.name, tt.wantTraceID, traceID)
+		}
+	}
+}
+
+// enhancingResolver resolves the default turn-start bundle with audio enhancement enabled.
+type enhancingResolver struct{}
+
+func (enhancingResolver) ResolveTurnStartBundle(in turnarbiter.TurnStartBundleInput) (turnarbiter.TurnStartBundle, error) {
+	bundle, err := turnarbiter.NewControlPlaneBundleResolverWithBackends(turnarbiter.ControlPlaneBackends{}).ResolveTurnStartBundle(in)
+	bundle.AudioEnhancement = &controlplane.AudioEnhancement{Enabled: true, Engine: "rnnoise", Quality: "high"}
+	return bundle, err
+}
+
+func TestSessionEnhancesAudioAheadOfSTT(t *testing.T) {
+	t.Parallel()
+
+	// A noisy capture with a DC offset: low-level noise, then a voiced tone.
+	var captured []int16
+	for idx := range DefaultSampleRateHz {
+		amplitude := int16(50)
+		if idx >= DefaultSampleRateHz/5 && idx < DefaultSampleRateHz*3/5 {
+			amplitude = 4000
+		}
+		if idx%2 == 1 {
+			amplitude = -amplitude
+		}
+		captured = append(captured, 500+amplitude)
+	}
+	// The default resolved plan bypasses audio enhancement from 60 plans deep.
+	tests := []struct {
+		name         string
+		executing    int
+		wantEnhanced bool
+	}{
+		{name: "idle runtime", executing: 0, wantEnhanced: true},
+		{name: "loaded runtime", executing: 60},
+	}
+	for idx, tt := range tests {
+		var heard []int16
+		stt := contracts.StaticAdapter{
+			ID:   "stt-capture",
+			Mode: contracts.ModalitySTT,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				heard = append([]int16(nil), req.Audio.PCM...)
+				return contracts.Outcome{Class: contracts.OutcomeSuccess, Text: "hello", Confidence: 0.9}, nil
+			},
+		}
+		sandbox := SandboxAdapters()
+		catalog, err := registry.NewCatalog([]contracts.Adapter{stt, sandbox[1], sandbox[2]})
+		if err != nil {
+			t.Fatalf("%s: unexpected catalog error: %v", tt.name, err)
+		}
+		load := &TurnLoad{}
+		for range tt.executing {
+			load.enter()
+		}
+		session, err := NewSession(SessionConfig{
+			SessionID:         fmt.Sprintf("demo-enhance-%d", idx),
+			ArtifactsDir:      t.TempDir(),
+			Clock:             steppingClock(10),
+			Invoker:           invocation.NewController(catalog),
+			TurnLoad:          load,
+			TurnStartResolver: enhancingResolver{},
+		}, Providers{})
+		if err != nil {
+			t.Fatalf("%s: unexpected session error: %v", tt.name, err)
+		}
+		session.StartCapture()
+		session.AppendAudio(captured)
+		turn, err := session.EndCapture()
+		if err != nil || turn == nil || turn.Transcript != "hello" {
+			t.Fatalf("%s: expected a transcribed turn, got %+v err=%v", tt.name, turn, err)
+		}
+		if len(heard) == 0 {
+			t.Fatalf("%s: expected stt invoked with audio", tt.name)
+		}
+		var sum float64
+		for _, sample := range heard {
+			sum += float64(sample)
+		}
+		mean := sum / float64(len(heard))
+		if tt.wantEnhanced && (mean > 100 || mean < -100) {
+			t.Fatalf("%s: expected stt to transcribe enhanced audio without the dc offset, got mean %.1f", tt.name, mean)
+		}
+		if !tt.wantEnhanced && mean < 400 {
+			t.Fatalf("%s: expected stt to transcribe the raw capture while enhancement is bypassed, got mean %.1f", tt.name, mean)
+		}
+		if used := session.Status().Recorder.AudioEnhancement.Used; used != 1 {
+			t.Fatalf("%s: expected one audio enhancement evidence entry, got %d", tt.name, used)
+		}
+	}
+}
+
+func TestSessionCancelTurnAbortsInFlightProviders(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	llm := contracts.StaticAdapter{
+		ID:   "llm-streaming",
+		Mode: contracts.ModalityLLM,
+		InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+			close(started)
+			<-req.CancelSignal
+			return contracts.Outcome{Class: contracts.OutcomeCancelled, Reason: "provider_stream_cancelled", Usage: &contracts.TokenUsage{InputTokens: 4, OutputTokens: 2, Truncated: true}}, nil
+		},
+	}
+	sandbox := SandboxAdapters()
+	catalog, err := registry.NewCatalog([]contracts.Adapter{sandbox[0], llm, sandbox[2]})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	session, err := NewSession(SessionConfig{
+		SessionID:    "demo-cancel",
+		ArtifactsDir: t.TempDir(),
+		Clock:        steppingClock(10),
+		Invoker:      invocation.NewController(catalog),
+		CancelFence:  cancellation.NewFence(),
+	}, Providers{})
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	if turnID, err := session.CancelTurn(); err != nil || turnID != "" {
+		t.Fatalf("expected no turn to cancel while idle, got %q err=%v", turnID, err)
+	}
+
+	type submitted struct {
+		turn *TurnResult
+		err  error
+	}
+	done := make(chan submitted, 1)
+	go func() {
+		turn, err := session.SubmitText("hello")
+		done <- submitted{turn: turn, err: err}
+	}()
+	<-started
+	turnID, err := session.CancelTurn()
+	if err != nil || turnID != "demo-cancel-turn-1" {
+		t.Fatalf("expected the in-flight turn cancelled, got %q err=%v", turnID, err)
+	}
+	result := <-done
+	if result.err != nil || result.turn == nil || result.turn.FallbackReason == "" {
+		t.Fatalf("expected the cancelled turn to complete with a fallback, got %+v err=%v", result.turn, result.err)
+	}
+
+	baseline, err := timeline.ReadBaselineArtifact(result.turn.Artifacts.BaselinePath)
+	if err != nil {
+		t.Fatalf("unexpected baseline error: %v", err)
+	}
+	outcomes := baseline.Entries[0].InvocationOutcomes
+	if len(outcomes) == 0 || outcomes[0].ProviderID != "llm-streaming" || outcomes[0].OutcomeClass != string(contracts.OutcomeCancelled) {
+		t.Fatalf("expected a cancelled llm outcome, got %+v", outcomes)
+	}
+	if cost := baseline.Entries[0].Cost; cost == nil || cost.OutputTokens != 2 {
+		t.Fatalf("expected the partial llm usage priced into the turn cost, got %+v", cost)
+	}
+	if turnID, _ := session.CancelTurn(); turnID != "" {
+		t.Fatalf("expected no in-flight turn after completion, got %q", turnID)
+	}
+}
+
+func TestSessionRoutesTurnPlansOnProviderHealth(t *testing.T) {
+	t.Parallel()
+
+	llm := func(id string, class contracts.OutcomeClass) contracts.StaticAdapter {
+		return contracts.StaticAdapter{
+			ID:   id,
+			Mode: contracts.ModalityLLM,
+			InvokeFn: func(contracts.InvocationRequest) (contracts.Outcome, error) {
+				return contracts.Outcome{Class: class, Text: "ok"}, nil
+			},
+		}
+	}
+	sandbox := SandboxAdapters()
+	catalog, err := registry.NewCatalog([]contracts.Adapter{sandbox[0], llm("llm-a", contracts.OutcomeSuccess), llm("llm-b", contracts.OutcomeSuccess), sandbox[2]})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	tests := []struct {
+		name     string
+		failures int
+		wantLLM  string
+	}{
+		{name: "healthy preferred provider", failures: 0, wantLLM: "llm-b"},
+		{name: "failing preferred provider", failures: healthscore.DefaultMinSamples, wantLLM: "llm-a"},
+	}
+	for idx, tt := range tests {
+		scorer := healthscore.NewScorer(healthscore.Config{})
+		for range healthscore.DefaultMinSamples {
+			scorer.Record(contracts.ModalityLLM, "llm-a", contracts.OutcomeSuccess, 100)
+		}
+		for idx := range healthscore.DefaultMinSamples {
+			class := contracts.OutcomeSuccess
+			if idx < tt.failures {
+				class = contracts.OutcomeInfrastructureFailure
+			}
+			scorer.Record(contracts.ModalityLLM, "llm-b", class, 100)
+		}
+		session, err := NewSession(SessionConfig{
+			SessionID:    fmt.Sprintf("demo-health-%d", idx),
+			ArtifactsDir: t.TempDir(),
+			Clock:        steppingClock(10),
+			Invoker:      invocation.NewController(catalog),
+			PreferredProvider: func(modality contracts.Modality) string {
+				if modality == contracts.ModalityLLM {
+					return "llm-b"
+				}
+				return ""
+			},
+			HealthScorer: scorer,
+		}, Providers{})
+		if err != nil {
+			t.Fatalf("%s: unexpected session error: %v", tt.name, err)
+		}
+		turn, err := session.SubmitText("hello")
+		if err != nil || turn == nil {
+			t.Fatalf("%s: expected completed text turn, got %+v err=%v", tt.name, turn, err)
+		}
+		baseline, err := timeline.ReadBaselineArtifact(turn.Artifacts.BaselinePath)
+		if err != nil {
+			t.Fatalf("%s: unexpected baseline error: %v", tt.name, err)
+		}
+		outcomes := baseline.Entries[0].InvocationOutcomes
+		if len(outcomes) == 0 || outcomes[0].ProviderID != tt.wantLLM {
+			t.Fatalf("%s: expected the llm node routed to %s, got %+v", tt.name, tt.wantLLM, outcomes)
+		}
+		var scored bool
+		for _, status := range scorer.Statuses() {
+			if status.ProviderID == SandboxTTSProviderID && status.Samples == 1 {
+				scored = true
+			}
+		}
+		if !scored {
+			t.Fatalf("%s: expected the turn's tts attempt scored, got %+v", tt.name, scorer.Statuses())
+		}
+	}
+}
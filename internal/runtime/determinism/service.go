@@ -54,3 +54,9 @@ func deriveSeed(planHash string, runtimeSequence int64) int64 {
 	}
 	return value
 }
+
+// SamplingSeed derives the provider sampling seed for one node of a turn from the turn's
+// determinism seed, so replays of the same turn request identical LLM sampling.
+func SamplingSeed(determinismSeed int64, turnID string, nodeID string) int64 {
+	return deriveSeed(fmt.Sprintf("sampling|%s|%s", turnID, nodeID), determinismSeed)
+}
@@ -30,3 +30,24 @@ func TestIssueContextRejectsEmptyPlanHash(t *testing.T) {
 		t.Fatalf("expected empty plan hash to fail")
 	}
 }
+
+func TestSamplingSeedIsStablePerTurnAndNode(t *testing.T) {
+	t.Parallel()
+
+	seed := SamplingSeed(42, "turn-1", "llm")
+	if seed != SamplingSeed(42, "turn-1", "llm") {
+		t.Fatalf("expected deterministic sampling seed")
+	}
+	if seed < 0 {
+		t.Fatalf("expected non-negative sampling seed, got %d", seed)
+	}
+	for name, other := range map[string]int64{
+		"determinism seed": SamplingSeed(43, "turn-1", "llm"),
+		"turn":             SamplingSeed(42, "turn-2", "llm"),
+		"node":             SamplingSeed(42, "turn-1", "llm-summary"),
+	} {
+		if other == seed {
+			t.Fatalf("%s: expected a distinct sampling seed", name)
+		}
+	}
+}
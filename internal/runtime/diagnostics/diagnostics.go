@@ -0,0 +1,407 @@
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/providerhealth"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/decisionindex"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/executionpool"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/snapshotfreshness"
+)
+
+const (
+	// SchemaVersion identifies the runtime diagnostics snapshot artifact.
+	SchemaVersion = "rspp-runtime-diagnostics/v1"
+	// AdminPath is the admin endpoint path the snapshot handler is mounted at.
+	AdminPath = "/admin/diagnostics"
+	// AdminSocketEnv overrides the admin socket path of running runtime instances.
+	AdminSocketEnv = "RSPP_ADMIN_SOCKET"
+	// DefaultAdminSocketPath is the admin socket path when AdminSocketEnv is unset.
+	DefaultAdminSocketPath = ".codex/runtime/admin.sock"
+)
+
+// AdminSocketPathFromEnv returns the admin socket path from AdminSocketEnv, or the default.
+func AdminSocketPathFromEnv() string {
+	if path := os.Getenv(AdminSocketEnv); path != "" {
+		return path
+	}
+	return DefaultAdminSocketPath
+}
+
+// Snapshot is a point-in-time view of one runtime instance for support diagnostics. Sections
+// the instance does not run (for example an execution pool) are omitted.
+type Snapshot struct {
+	SchemaVersion     string                    `json:"schema_version"`
+	CapturedAtUTC     string                    `json:"captured_at_utc"`
+	UptimeMS          int64                     `json:"uptime_ms"`
+	Pool              *PoolStatus               `json:"pool,omitempty"`
+	Queues            QueueStatus               `json:"queues"`
+	Recorder          timeline.Occupancy        `json:"recorder"`
+	Providers         []ProviderStatus          `json:"providers"`
+	SnapshotFreshness *snapshotfreshness.Report `json:"snapshot_freshness,omitempty"`
+	Sessions          SessionSummary            `json:"sessions"`
+}
+
+// PoolStatus reports execution pool counters.
+type PoolStatus struct {
+	Submitted        int64 `json:"submitted"`
+	Completed        int64 `json:"completed"`
+	Rejected         int64 `json:"rejected"`
+	ResourceRejected int64 `json:"resource_rejected"`
+	InFlight         int64 `json:"in_flight"`
+	QueueDepth       int64 `json:"queue_depth"`
+}
+
+// QueueStatus reports runtime queue depths.
+type QueueStatus struct {
+	ExecutionPool *int64                `json:"execution_pool,omitempty"`
+	Telemetry     *TelemetryQueueStatus `json:"telemetry,omitempty"`
+}
+
+// TelemetryQueueStatus reports the bounded telemetry queue and its overflow spool.
+type TelemetryQueueStatus struct {
+	Depth                int    `json:"depth"`
+	Enqueued             uint64 `json:"enqueued"`
+	Dropped              uint64 `json:"dropped"`
+	Exported             uint64 `json:"exported"`
+	ExportFailures       uint64 `json:"export_failures"`
+	Spilled              uint64 `json:"spilled"`
+	OverflowPendingBytes int64  `json:"overflow_pending_bytes"`
+}
+
+// ProviderStatus is the latest warm-up health of one provider.
+type ProviderStatus struct {
+	ProviderID       string `json:"provider_id"`
+	Modality         string `json:"modality"`
+	Phase            string `json:"phase"`
+	Warmed           bool   `json:"warmed"`
+	LatencyMS        int64  `json:"latency_ms"`
+	ConnectionReused bool   `json:"connection_reused"`
+	Reason           string `json:"reason,omitempty"`
+	WarmedAtMS       int64  `json:"warmed_at_ms,omitempty"`
+}
+
+// SessionSummary reports the sessions live when the snapshot was captured.
+type SessionSummary struct {
+	Active   int             `json:"active"`
+	Turns    int             `json:"turns"`
+	Sessions []SessionStatus `json:"sessions"`
+}
+
+// SessionStatus is one live session's identity, progress, and recorder occupancy.
+type SessionStatus struct {
+	SessionID       string             `json:"session_id"`
+	TenantID        string             `json:"tenant_id,omitempty"`
+	PipelineVersion string             `json:"pipeline_version,omitempty"`
+	StartedAtUTC    string             `json:"started_at_utc,omitempty"`
+	Turns           int                `json:"turns"`
+	Recorder        timeline.Occupancy `json:"recorder"`
+}
+
+// SessionTracker registers live sessions so snapshots can summarize them. It is safe for
+// concurrent use.
+type SessionTracker struct {
+	mu     sync.Mutex
+	probes map[string]func() SessionStatus
+}
+
+// NewSessionTracker returns an empty tracker.
+func NewSessionTracker() *SessionTracker {
+	return &SessionTracker{probes: make(map[string]func() SessionStatus)}
+}
+
+// Track registers a live session; probe is called on every snapshot. The returned func
+// unregisters the session when it ends.
+func (t *SessionTracker) Track(sessionID string, probe func() SessionStatus) func() {
+	t.mu.Lock()
+	t.probes[sessionID] = probe
+	t.mu.Unlock()
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		delete(t.probes, sessionID)
+	}
+}
+
+// Statuses probes every live session, ordered by session id.
+func (t *SessionTracker) Statuses() []SessionStatus {
+	t.mu.Lock()
+	probes := make([]func() SessionStatus, 0, len(t.probes))
+	for _, probe := range t.probes {
+		probes = append(probes, probe)
+	}
+	t.mu.Unlock()
+
+	statuses := make([]SessionStatus, 0, len(probes))
+	for _, probe := range probes {
+		statuses = append(statuses, probe())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].SessionID < statuses[j].SessionID })
+	return statuses
+}
+
+// Sources are the live runtime components a snapshot reads; nil sources are skipped.
+type Sources struct {
+	// Now stamps the snapshot; nil uses time.Now.
+	Now func() time.Time
+	// StartedAt is the instance start time uptime is measured from.
+	StartedAt time.Time
+	Pool      func() executionpool.Stats
+	Telemetry func() telemetry.Stats
+	Providers providerhealth.WarmupSource
+	// Freshness returns the current control-plane snapshot freshness report.
+	Freshness func() snapshotfreshness.Report
+	Sessions  *SessionTracker
+	// Decisions is the live decision index served at decisionindex.AdminPath.
+	Decisions *decisionindex.Index
+	// Remediation backs the runbook endpoints at BreakerAdminPath and WeightsAdminPath.
+	Remediation Remediation
+}
+
+// Capture reads every configured source into one snapshot.
+func (s Sources) Capture() Snapshot {
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	capturedAt := now().UTC()
+	snapshot := Snapshot{
+		SchemaVersion: SchemaVersion,
+		CapturedAtUTC: capturedAt.Format(time.RFC3339Nano),
+		Providers:     []ProviderStatus{},
+		Sessions:      SessionSummary{Sessions: []SessionStatus{}},
+	}
+	if !s.StartedAt.IsZero() {
+		snapshot.UptimeMS = max(capturedAt.Sub(s.StartedAt).Milliseconds(), 0)
+	}
+	if s.Pool != nil {
+		stats := s.Pool()
+		snapshot.Pool = &PoolStatus{
+			Submitted:        stats.Submitted,
+			Completed:        stats.Completed,
+			Rejected:         stats.Rejected,
+			ResourceRejected: stats.ResourceRejected,
+			InFlight:         stats.InFlight,
+			QueueDepth:       stats.QueueDepth,
+		}
+		depth := stats.QueueDepth
+		snapshot.Queues.ExecutionPool = &depth
+	}
+	if s.Telemetry != nil {
+		stats := s.Telemetry()
+		snapshot.Queues.Telemetry = &TelemetryQueueStatus{
+			Depth:                stats.QueueDepth,
+			Enqueued:             stats.Enqueued,
+			Dropped:              stats.Dropped,
+			Exported:             stats.Exported,
+			ExportFailures:       stats.ExportFailures,
+			Spilled:              stats.Spilled,
+			OverflowPendingBytes: stats.OverflowPendingBytes,
+		}
+	}
+	if s.Providers != nil {
+		for _, status := range s.Providers.WarmupStatuses() {
+			snapshot.Providers = append(snapshot.Providers, ProviderStatus(status))
+		}
+	}
+	if s.Freshness != nil {
+		report := s.Freshness()
+		snapshot.SnapshotFreshness = &report
+	}
+	if s.Sessions != nil {
+		for _, status := range s.Sessions.Statuses() {
+			snapshot.Sessions.Sessions = append(snapshot.Sessions.Sessions, status)
+			snapshot.Sessions.Turns += status.Turns
+			snapshot.Recorder = snapshot.Recorder.Merge(status.Recorder)
+		}
+		snapshot.Sessions.Active = len(snapshot.Sessions.Sessions)
+	}
+	return snapshot
+}
+
+// Handler serves snapshots for the admin endpoint.
+type Handler struct {
+	Sources Sources
+}
+
+// ServeHTTP answers GET requests with a fresh snapshot.
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.Sources.Capture())
+}
+
+// AdminServer serves the diagnostics endpoint on a unix admin socket, which is reachable only
+// from the host running the instance.
+type AdminServer struct {
+	path   string
+	server *http.Server
+	done   chan struct{}
+}
+
+// ListenAdmin starts serving sources at AdminPath, decision queries at
+// decisionindex.AdminPath, and runbook remediations at BreakerAdminPath and WeightsAdminPath,
+// on the unix socket at path. A stale socket
+// left by a crashed instance is replaced; a socket a live instance still answers on is not.
+func ListenAdmin(path string, sources Sources) (*AdminServer, error) {
+	if path == "" {
+		return nil, fmt.Errorf("admin socket path is required")
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("admin socket %s is in use by another instance", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("remove stale admin socket: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create admin socket dir: %w", err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listen on admin socket: %w", err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("restrict admin socket: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(AdminPath, Handler{Sources: sources})
+	mux.Handle(decisionindex.AdminPath, decisionindex.Handler{Index: sources.Decisions})
+	mux.Handle(BreakerAdminPath, BreakerHandler{Remediation: sources.Remediation})
+	mux.Handle(WeightsAdminPath, WeightsHandler{Remediation: sources.Remediation})
+	admin := &AdminServer{
+		path:   path,
+		server: &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second},
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(admin.done)
+		_ = admin.server.Serve(listener)
+	}()
+	return admin, nil
+}
+
+// Path returns the socket path the server listens on.
+func (a *AdminServer) Path() string {
+	return a.path
+}
+
+// Close stops serving and removes the socket.
+func (a *AdminServer) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := a.server.Shutdown(ctx)
+	<-a.done
+	if removeErr := os.Remove(a.path); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) && err == nil {
+		err = removeErr
+	}
+	return err
+}
+
+// Fetch requests a snapshot from the instance listening on the admin socket at path.
+func Fetch(path string, timeout time.Duration) (Snapshot, error) {
+	var snapshot Snapshot
+	if err := getAdmin(path, timeout, AdminPath, &snapshot); err != nil {
+		return Snapshot{}, err
+	}
+	if snapshot.SchemaVersion != SchemaVersion {
+		return Snapshot{}, fmt.Errorf("unsupported diagnostics schema_version %q", snapshot.SchemaVersion)
+	}
+	return snapshot, nil
+}
+
+// FetchDecisions queries the decision index of the instance listening on the admin socket at
+// path.
+func FetchDecisions(path string, timeout time.Duration, q decisionindex.Query) (decisionindex.QueryResponse, error) {
+	values := url.Values{}
+	for key, value := range map[string]string{
+		"session_id":   q.SessionID,
+		"turn_id":      q.TurnID,
+		"outcome_kind": string(q.OutcomeKind),
+		"reason":       q.Reason,
+	} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	if q.Limit > 0 {
+		values.Set("limit", fmt.Sprint(q.Limit))
+	}
+	target := decisionindex.AdminPath
+	if encoded := values.Encode(); encoded != "" {
+		target += "?" + encoded
+	}
+	var response decisionindex.QueryResponse
+	if err := getAdmin(path, timeout, target, &response); err != nil {
+		return decisionindex.QueryResponse{}, err
+	}
+	return response, nil
+}
+
+// getAdmin GETs target from the admin socket at path and decodes the JSON body into out.
+func getAdmin(path string, timeout time.Duration, target string, out any) error {
+	return doAdmin(path, timeout, http.MethodGet, target, nil, out)
+}
+
+// postAdmin POSTs body as JSON to target on the admin socket at path and decodes the JSON
+// response into out.
+func postAdmin(path string, timeout time.Duration, target string, body any, out any) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encode admin request: %w", err)
+	}
+	return doAdmin(path, timeout, http.MethodPost, target, raw, out)
+}
+
+func doAdmin(path string, timeout time.Duration, method string, target string, body []byte, out any) error {
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", path)
+			},
+		},
+	}
+	req, err := http.NewRequest(method, "http://rspp-runtime"+target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build admin request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("query admin socket %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("admin socket %s returned %s: %s", path, resp.Status, body)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode admin socket %s response: %w", path, err)
+	}
+	return nil
+}
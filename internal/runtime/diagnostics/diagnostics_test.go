@@ -0,0 +1,166 @@
+package diagnostics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/providerhealth"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/decisionindex"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/executionpool"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/snapshotfreshness"
+)
+
+type staticWarmup []providerhealth.WarmupStatus
+
+func (s staticWarmup) WarmupStatuses() []providerhealth.WarmupStatus { return s }
+
+func testSources() Sources {
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewSessionTracker()
+	for _, id := range []string{"sess-b", "sess-a"} {
+		status := SessionStatus{SessionID: id, Turns: 2, Recorder: timeline.Occupancy{Baseline: timeline.StreamOccupancy{Used: 2, Capacity: 128}}}
+		tracker.Track(id, func() SessionStatus { return status })
+	}
+	return Sources{
+		Now:       func() time.Time { return started.Add(90 * time.Second) },
+		StartedAt: started,
+		Pool: func() executionpool.Stats {
+			return executionpool.Stats{Submitted: 7, Completed: 5, InFlight: 1, QueueDepth: 1}
+		},
+		Telemetry: func() telemetry.Stats { return telemetry.Stats{QueueDepth: 3, Enqueued: 40, Dropped: 2} },
+		Providers: staticWarmup{{ProviderID: "stt-deepgram", Modality: "stt", Phase: "runtime", Warmed: true, LatencyMS: 80}},
+		Freshness: func() snapshotfreshness.Report { return snapshotfreshness.Report{TotalRuns: 1, Degraded: true} },
+		Sessions:  tracker,
+	}
+}
+
+func TestCaptureReadsEverySource(t *testing.T) {
+	t.Parallel()
+
+	snapshot := testSources().Capture()
+	if snapshot.SchemaVersion != SchemaVersion || snapshot.CapturedAtUTC != "2026-03-01T12:01:30Z" || snapshot.UptimeMS != 90_000 {
+		t.Fatalf("unexpected snapshot header %+v", snapshot)
+	}
+	if snapshot.Pool == nil || snapshot.Pool.Submitted != 7 || snapshot.Queues.ExecutionPool == nil || *snapshot.Queues.ExecutionPool != 1 {
+		t.Fatalf("expected pool stats and depth, got %+v %+v", snapshot.Pool, snapshot.Queues)
+	}
+	if snapshot.Queues.Telemetry == nil || snapshot.Queues.Telemetry.Depth != 3 || snapshot.Queues.Telemetry.Dropped != 2 {
+		t.Fatalf("expected telemetry queue stats, got %+v", snapshot.Queues.Telemetry)
+	}
+	if len(snapshot.Providers) != 1 || !snapshot.Providers[0].Warmed || snapshot.Providers[0].ProviderID != "stt-deepgram" {
+		t.Fatalf("expected provider warm-up health, got %+v", snapshot.Providers)
+	}
+	if snapshot.SnapshotFreshness == nil || !snapshot.SnapshotFreshness.Degraded {
+		t.Fatalf("expected freshness report, got %+v", snapshot.SnapshotFreshness)
+	}
+	if snapshot.Sessions.Active != 2 || snapshot.Sessions.Turns != 4 || snapshot.Sessions.Sessions[0].SessionID != "sess-a" {
+		t.Fatalf("expected ordered session summary, got %+v", snapshot.Sessions)
+	}
+	if snapshot.Recorder.Baseline != (timeline.StreamOccupancy{Used: 4, Capacity: 256}) {
+		t.Fatalf("expected recorder occupancy summed across sessions, got %+v", snapshot.Recorder)
+	}
+}
+
+func TestCaptureOmitsMissingSources(t *testing.T) {
+	t.Parallel()
+
+	snapshot := Sources{}.Capture()
+	if snapshot.Pool != nil || snapshot.Queues.Telemetry != nil || snapshot.SnapshotFreshness != nil || snapshot.UptimeMS != 0 {
+		t.Fatalf("expected absent sections, got %+v", snapshot)
+	}
+	if snapshot.Providers == nil || snapshot.Sessions.Sessions == nil {
+		t.Fatalf("expected empty lists rather than null, got %+v", snapshot)
+	}
+}
+
+func TestSessionTrackerUntracksEndedSessions(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewSessionTracker()
+	untrack := tracker.Track("sess-1", func() SessionStatus { return SessionStatus{SessionID: "sess-1"} })
+	tracker.Track("sess-2", func() SessionStatus { return SessionStatus{SessionID: "sess-2"} })
+	untrack()
+	statuses := tracker.Statuses()
+	if len(statuses) != 1 || statuses[0].SessionID != "sess-2" {
+		t.Fatalf("expected only sess-2 tracked, got %+v", statuses)
+	}
+}
+
+func TestHandlerRejectsNonGet(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	Handler{}.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, AdminPath, nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", recorder.Code)
+	}
+}
+
+func TestAdminSocketRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "admin.sock")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("write stale socket: %v", err)
+	}
+	sources := testSources()
+	sources.Decisions = decisionindex.New(decisionindex.Config{Capacity: 8})
+	for i, sessionID := range []string{"sess-a", "sess-b", "sess-a"} {
+		if err := sources.Decisions.Record(controlplane.DecisionOutcome{
+			OutcomeKind:        controlplane.OutcomeAdmit,
+			Phase:              controlplane.PhasePreTurn,
+			Scope:              controlplane.ScopeTurn,
+			SessionID:          sessionID,
+			TurnID:             "turn-1",
+			EventID:            fmt.Sprintf("evt-%d", i),
+			RuntimeTimestampMS: int64(i),
+			WallClockMS:        int64(i),
+			EmittedBy:          controlplane.EmitterRK25,
+			Reason:             "admission_capacity_allow",
+		}); err != nil {
+			t.Fatalf("unexpected decision record error: %v", err)
+		}
+	}
+	admin, err := ListenAdmin(path, sources)
+	if err != nil {
+		t.Fatalf("unexpected listen error: %v", err)
+	}
+	if _, err := ListenAdmin(path, Sources{}); err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Fatalf("expected a live socket to be refused, got %v", err)
+	}
+
+	snapshot, err := Fetch(path, 2*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected fetch error: %v", err)
+	}
+	if snapshot.Sessions.Active != 2 || snapshot.Pool == nil || snapshot.Pool.Completed != 5 {
+		t.Fatalf("unexpected fetched snapshot %+v", snapshot)
+	}
+
+	decisions, err := FetchDecisions(path, 2*time.Second, decisionindex.Query{SessionID: "sess-a", Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected decision fetch error: %v", err)
+	}
+	if len(decisions.Decisions) != 1 || decisions.Decisions[0].EventID != "evt-2" || decisions.Window.Indexed != 3 {
+		t.Fatalf("unexpected fetched decisions %+v", decisions)
+	}
+
+	if err := admin.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected socket removed on close, got %v", err)
+	}
+	if _, err := Fetch(path, time.Second); err == nil {
+		t.Fatalf("expected fetch from a closed instance to fail")
+	}
+}
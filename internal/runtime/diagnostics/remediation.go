@@ -0,0 +1,125 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// BreakerAdminPath is the admin endpoint that forces a provider circuit breaker open.
+	BreakerAdminPath = "/admin/providers/breaker"
+	// WeightsAdminPath is the admin endpoint that shifts provider weights in the catalog.
+	WeightsAdminPath = "/admin/providers/weights"
+)
+
+// Remediation are the runtime controls operator runbooks act on through the admin socket.
+// A nil control answers 501, so a runbook learns the instance cannot perform the action.
+type Remediation struct {
+	// OpenBreaker forces the provider's circuit open and reports whether it was not already.
+	OpenBreaker func(providerID string) bool
+	// ShiftWeights rewrites provider weights and reports whether the catalog changed.
+	ShiftWeights func(weights map[string]int) (bool, error)
+}
+
+// BreakerRequest asks the instance to open one provider's circuit breaker.
+type BreakerRequest struct {
+	ProviderID string `json:"provider_id"`
+}
+
+// WeightsRequest asks the instance to set the weights of the named providers.
+type WeightsRequest struct {
+	Weights map[string]int `json:"weights"`
+}
+
+// RemediationResponse reports whether a remediation changed runtime state.
+type RemediationResponse struct {
+	Changed bool `json:"changed"`
+}
+
+// BreakerHandler serves BreakerAdminPath.
+type BreakerHandler struct {
+	Remediation Remediation
+}
+
+// ServeHTTP answers POST requests by opening the named provider's breaker.
+func (h BreakerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req BreakerRequest
+	if !decodeRemediation(w, r, h.Remediation.OpenBreaker != nil, &req) {
+		return
+	}
+	if req.ProviderID == "" {
+		http.Error(w, "provider_id is required", http.StatusBadRequest)
+		return
+	}
+	writeRemediation(w, RemediationResponse{Changed: h.Remediation.OpenBreaker(req.ProviderID)})
+}
+
+// WeightsHandler serves WeightsAdminPath.
+type WeightsHandler struct {
+	Remediation Remediation
+}
+
+// ServeHTTP answers POST requests by shifting the named providers' weights.
+func (h WeightsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req WeightsRequest
+	if !decodeRemediation(w, r, h.Remediation.ShiftWeights != nil, &req) {
+		return
+	}
+	if len(req.Weights) == 0 {
+		http.Error(w, "weights are required", http.StatusBadRequest)
+		return
+	}
+	changed, err := h.Remediation.ShiftWeights(req.Weights)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	writeRemediation(w, RemediationResponse{Changed: changed})
+}
+
+// decodeRemediation enforces POST, rejects controls the instance lacks, and decodes the body.
+func decodeRemediation(w http.ResponseWriter, r *http.Request, available bool, out any) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	if !available {
+		http.Error(w, "remediation is not available on this instance", http.StatusNotImplemented)
+		return false
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(out); err != nil {
+		http.Error(w, fmt.Sprintf("decode request: %v", err), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeRemediation(w http.ResponseWriter, response RemediationResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// OpenBreaker forces providerID's circuit breaker open on the instance listening on the admin
+// socket at path, reporting whether it was not already open.
+func OpenBreaker(path string, timeout time.Duration, providerID string) (bool, error) {
+	var response RemediationResponse
+	if err := postAdmin(path, timeout, BreakerAdminPath, BreakerRequest{ProviderID: providerID}, &response); err != nil {
+		return false, err
+	}
+	return response.Changed, nil
+}
+
+// ShiftProviderWeights sets provider weights in the provider catalog of the instance
+// listening on the admin socket at path, reporting whether the catalog changed.
+func ShiftProviderWeights(path string, timeout time.Duration, weights map[string]int) (bool, error) {
+	var response RemediationResponse
+	if err := postAdmin(path, timeout, WeightsAdminPath, WeightsRequest{Weights: weights}, &response); err != nil {
+		return false, err
+	}
+	return response.Changed, nil
+}
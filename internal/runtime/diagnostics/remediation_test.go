@@ -0,0 +1,75 @@
+package diagnostics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAdminSocketAppliesRemediations(t *testing.T) {
+	t.Parallel()
+
+	opened := map[string]bool{}
+	var shifted map[string]int
+	sources := Sources{Remediation: Remediation{
+		OpenBreaker: func(providerID string) bool {
+			changed := !opened[providerID]
+			opened[providerID] = true
+			return changed
+		},
+		ShiftWeights: func(weights map[string]int) (bool, error) {
+			if _, ok := weights["llm-z"]; ok {
+				return false, fmt.Errorf("llm-z is not declared in the provider catalog")
+			}
+			shifted = weights
+			return true, nil
+		},
+	}}
+	path := filepath.Join(t.TempDir(), "admin.sock")
+	admin, err := ListenAdmin(path, sources)
+	if err != nil {
+		t.Fatalf("unexpected listen error: %v", err)
+	}
+	defer admin.Close()
+
+	if changed, err := OpenBreaker(path, 2*time.Second, "llm-a"); err != nil || !changed {
+		t.Fatalf("expected breaker opened, got changed=%t err=%v", changed, err)
+	}
+	if changed, err := OpenBreaker(path, 2*time.Second, "llm-a"); err != nil || changed {
+		t.Fatalf("expected reopening an open breaker to be a no-op, got changed=%t err=%v", changed, err)
+	}
+	if changed, err := ShiftProviderWeights(path, 2*time.Second, map[string]int{"llm-a": 10, "llm-b": 90}); err != nil || !changed || shifted["llm-b"] != 90 {
+		t.Fatalf("expected weights shifted, got changed=%t err=%v weights=%v", changed, err, shifted)
+	}
+	if _, err := ShiftProviderWeights(path, 2*time.Second, map[string]int{"llm-z": 1}); err == nil || !strings.Contains(err.Error(), "not declared") {
+		t.Fatalf("expected a rejected shift to surface the instance error, got %v", err)
+	}
+}
+
+func TestRemediationHandlersRejectUnavailableControls(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		method  string
+		body    string
+		code    int
+	}{
+		{name: "breaker without control", handler: BreakerHandler{}, method: http.MethodPost, body: `{"provider_id":"llm-a"}`, code: http.StatusNotImplemented},
+		{name: "weights without control", handler: WeightsHandler{}, method: http.MethodPost, body: `{"weights":{"llm-a":1}}`, code: http.StatusNotImplemented},
+		{name: "breaker get", handler: BreakerHandler{}, method: http.MethodGet, code: http.StatusMethodNotAllowed},
+		{name: "breaker missing provider", handler: BreakerHandler{Remediation: Remediation{OpenBreaker: func(string) bool { return true }}}, method: http.MethodPost, body: `{}`, code: http.StatusBadRequest},
+	}
+	for _, tc := range tests {
+		recorder := httptest.NewRecorder()
+		tc.handler.ServeHTTP(recorder, httptest.NewRequest(tc.method, BreakerAdminPath, strings.NewReader(tc.body)))
+		if recorder.Code != tc.code {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.code, recorder.Code)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package enrichment
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+// DefaultBudgetMS bounds how long session start waits for enrichers when no budget is set.
+const DefaultBudgetMS int64 = 200
+
+var namePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Request identifies the session being enriched.
+type Request struct {
+	SessionID       string
+	TenantID        string
+	PipelineVersion string
+}
+
+// Enricher looks up session metadata (tenant CRM data, user preferences) at session start.
+// ctx is cancelled when the enrichment budget elapses.
+type Enricher interface {
+	Enrich(ctx context.Context, req Request) (map[string]string, error)
+}
+
+// EnricherFunc adapts a function to Enricher.
+type EnricherFunc func(context.Context, Request) (map[string]string, error)
+
+// Enrich calls f(ctx, req).
+func (f EnricherFunc) Enrich(ctx context.Context, req Request) (map[string]string, error) {
+	return f(ctx, req)
+}
+
+// Hook registers an enricher under a name; its metadata keys are namespaced as "<name>.<key>".
+type Hook struct {
+	Name     string
+	Enricher Enricher
+}
+
+// EvidenceAppender records enrichment runs for replay.
+type EvidenceAppender interface {
+	AppendSessionEnrichmentEvidence(timeline.SessionEnrichmentEvidence) error
+}
+
+// Config scopes enrichment to one session start.
+type Config struct {
+	Request
+	EventID string
+	// BudgetMS bounds the whole enrichment step; 0 uses DefaultBudgetMS.
+	BudgetMS int64
+	// Clock defaults to time.Now and timestamps the recorded latency.
+	Clock func() time.Time
+}
+
+func (c Config) validate() error {
+	if c.SessionID == "" || c.PipelineVersion == "" || c.EventID == "" {
+		return fmt.Errorf("enrichment session_id, pipeline_version, and event_id are required")
+	}
+	if c.BudgetMS < 0 {
+		return fmt.Errorf("enrichment budget_ms must be >=0")
+	}
+	return nil
+}
+
+// HookResult is the outcome of one enricher.
+type HookResult struct {
+	Name      string
+	Outcome   string
+	LatencyMS int64
+	Keys      []string
+	Error     string
+}
+
+// Result is the session context metadata produced at session start.
+type Result struct {
+	// Metadata is bound into the session context as metadata-class payload and exposed to
+	// LLM prompt templates.
+	Metadata map[string]string
+	Hooks    []HookResult
+	BudgetMS int64
+}
+
+type hookOutput struct {
+	idx       int
+	metadata  map[string]string
+	err       error
+	latencyMS int64
+}
+
+// Run invokes every hook concurrently and waits at most the budget. Enrichment fails open: an
+// enricher that errors, returns invalid keys, or is still running when the budget elapses
+// contributes no metadata, and the session starts with whatever finished in time. Every hook
+// outcome and its latency is recorded as evidence when appender is set.
+func Run(cfg Config, hooks []Hook, appender EvidenceAppender) (Result, error) {
+	if err := cfg.validate(); err != nil {
+		return Result{}, err
+	}
+	seen := make(map[string]struct{}, len(hooks))
+	for _, hook := range hooks {
+		if !namePattern.MatchString(hook.Name) || hook.Enricher == nil {
+			return Result{}, fmt.Errorf("enrichment hook %q requires a [a-z0-9_] name and an enricher", hook.Name)
+		}
+		if _, ok := seen[hook.Name]; ok {
+			return Result{}, fmt.Errorf("duplicate enrichment hook %q", hook.Name)
+		}
+		seen[hook.Name] = struct{}{}
+	}
+	clock := cfg.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	budgetMS := cfg.BudgetMS
+	if budgetMS == 0 {
+		budgetMS = DefaultBudgetMS
+	}
+
+	started := clock()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(budgetMS)*time.Millisecond)
+	defer cancel()
+	outputs := make(chan hookOutput, len(hooks))
+	for idx, hook := range hooks {
+		go func() {
+			metadata, err := hook.Enricher.Enrich(ctx, cfg.Request)
+			outputs <- hookOutput{idx: idx, metadata: metadata, err: err, latencyMS: clock().Sub(started).Milliseconds()}
+		}()
+	}
+
+	result := Result{Metadata: map[string]string{}, Hooks: make([]HookResult, len(hooks)), BudgetMS: budgetMS}
+	for idx, hook := range hooks {
+		result.Hooks[idx] = HookResult{Name: hook.Name, Outcome: timeline.EnrichmentOutcomeBudgetExceeded, LatencyMS: budgetMS}
+	}
+collect:
+	for range hooks {
+		var out hookOutput
+		select {
+		case out = <-outputs:
+		case <-ctx.Done():
+			break collect
+		}
+		if ctx.Err() != nil {
+			// Finished after the budget elapsed; the session has already moved on.
+			break
+		}
+		hook := &result.Hooks[out.idx]
+		hook.LatencyMS = max(out.latencyMS, 0)
+		metadata, err := namespace(hook.Name, out.metadata, out.err)
+		if err != nil {
+			hook.Outcome = timeline.EnrichmentOutcomeError
+			hook.Error = err.Error()
+			continue
+		}
+		hook.Outcome = timeline.EnrichmentOutcomeOK
+		for key, value := range metadata {
+			result.Metadata[key] = value
+			hook.Keys = append(hook.Keys, key)
+		}
+		sort.Strings(hook.Keys)
+	}
+
+	if appender != nil {
+		for _, hook := range result.Hooks {
+			if err := appender.AppendSessionEnrichmentEvidence(timeline.SessionEnrichmentEvidence{
+				SessionID:       cfg.SessionID,
+				PipelineVersion: cfg.PipelineVersion,
+				EventID:         cfg.EventID,
+				Enricher:        hook.Name,
+				PayloadClass:    eventabi.PayloadMetadata,
+				Outcome:         hook.Outcome,
+				StartedAtMS:     max(started.UnixMilli(), 0),
+				LatencyMS:       hook.LatencyMS,
+				BudgetMS:        budgetMS,
+				MetadataKeys:    hook.Keys,
+			}); err != nil {
+				return Result{}, err
+			}
+		}
+	}
+	return result, nil
+}
+
+func namespace(name string, metadata map[string]string, err error) (map[string]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		if !namePattern.MatchString(key) {
+			return nil, fmt.Errorf("enricher %s returned invalid metadata key %q", name, key)
+		}
+		out[name+"."+key] = value
+	}
+	return out, nil
+}
@@ -0,0 +1,98 @@
+package enrichment
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+func testConfig(budgetMS int64) Config {
+	return Config{
+		Request:  Request{SessionID: "sess-enrich-1", TenantID: "tenant-a", PipelineVersion: "pipeline-v1"},
+		EventID:  "evt-enrich-1",
+		BudgetMS: budgetMS,
+	}
+}
+
+func TestRunAttachesMetadataWithinBudget(t *testing.T) {
+	t.Parallel()
+
+	recorder := timeline.NewRecorder(timeline.StageAConfig{})
+	hooks := []Hook{
+		{Name: "crm", Enricher: EnricherFunc(func(_ context.Context, req Request) (map[string]string, error) {
+			return map[string]string{"tier": "gold", "account": req.TenantID + "-acct"}, nil
+		})},
+		{Name: "prefs", Enricher: EnricherFunc(func(context.Context, Request) (map[string]string, error) {
+			return nil, fmt.Errorf("preferences store unavailable")
+		})},
+		{Name: "slow", Enricher: EnricherFunc(func(ctx context.Context, _ Request) (map[string]string, error) {
+			<-ctx.Done()
+			return map[string]string{"late": "value"}, nil
+		})},
+		{Name: "bad_keys", Enricher: EnricherFunc(func(context.Context, Request) (map[string]string, error) {
+			return map[string]string{"Not A Key": "x"}, nil
+		})},
+	}
+
+	result, err := Run(testConfig(50), hooks, &recorder)
+	if err != nil {
+		t.Fatalf("unexpected enrichment error: %v", err)
+	}
+	want := map[string]string{"crm.account": "tenant-a-acct", "crm.tier": "gold"}
+	if len(result.Metadata) != len(want) || result.Metadata["crm.tier"] != "gold" || result.Metadata["crm.account"] != "tenant-a-acct" {
+		t.Fatalf("expected only crm metadata attached, got %+v", result.Metadata)
+	}
+	outcomes := map[string]string{}
+	for _, hook := range result.Hooks {
+		outcomes[hook.Name] = hook.Outcome
+	}
+	if outcomes["crm"] != timeline.EnrichmentOutcomeOK || outcomes["prefs"] != timeline.EnrichmentOutcomeError || outcomes["slow"] != timeline.EnrichmentOutcomeBudgetExceeded || outcomes["bad_keys"] != timeline.EnrichmentOutcomeError {
+		t.Fatalf("unexpected hook outcomes %+v", outcomes)
+	}
+
+	evidence := recorder.SessionEnrichmentEntries()
+	if len(evidence) != 4 {
+		t.Fatalf("expected evidence per hook, got %d", len(evidence))
+	}
+	if crm := evidence[0]; crm.BudgetMS != 50 || len(crm.MetadataKeys) != 2 || crm.MetadataKeys[0] != "crm.account" || crm.LatencyMS > crm.BudgetMS {
+		t.Fatalf("expected crm evidence with keys and latency within budget, got %+v", crm)
+	}
+	if slow := evidence[2]; slow.LatencyMS != 50 || len(slow.MetadataKeys) != 0 {
+		t.Fatalf("expected budget-exceeded evidence charged the full budget, got %+v", slow)
+	}
+
+	req := contracts.InvocationRequest{SessionMetadata: result.Metadata}
+	if got := req.RenderPrompt("Greet the {{metadata.crm.tier}} customer."); got != "Greet the gold customer." {
+		t.Fatalf("expected metadata available to prompt templates, got %q", got)
+	}
+}
+
+func TestRunRejectsInvalidHooks(t *testing.T) {
+	t.Parallel()
+
+	noop := EnricherFunc(func(context.Context, Request) (map[string]string, error) { return nil, nil })
+	tests := []struct {
+		name  string
+		cfg   Config
+		hooks []Hook
+	}{
+		{name: "missing event id", cfg: Config{Request: Request{SessionID: "s", PipelineVersion: "p"}}, hooks: []Hook{{Name: "crm", Enricher: noop}}},
+		{name: "negative budget", cfg: testConfig(-1), hooks: []Hook{{Name: "crm", Enricher: noop}}},
+		{name: "invalid name", cfg: testConfig(0), hooks: []Hook{{Name: "CRM.lookup", Enricher: noop}}},
+		{name: "nil enricher", cfg: testConfig(0), hooks: []Hook{{Name: "crm"}}},
+		{name: "duplicate name", cfg: testConfig(0), hooks: []Hook{{Name: "crm", Enricher: noop}, {Name: "crm", Enricher: noop}}},
+	}
+	for _, tc := range tests {
+		if _, err := Run(tc.cfg, tc.hooks, nil); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+
+	result, err := Run(testConfig(0), nil, nil)
+	if err != nil || len(result.Metadata) != 0 || result.BudgetMS != DefaultBudgetMS {
+		t.Fatalf("expected empty enrichment with default budget, got %+v err=%v", result, err)
+	}
+}
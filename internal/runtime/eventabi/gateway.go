@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	apieventabi "github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/sequence"
 )
 
 // ValidateAndNormalizeEventRecords performs runtime-side normalization and validation
@@ -43,6 +44,13 @@ func ValidateAndNormalizeEventRecords(in []apieventabi.EventRecord) ([]apieventa
 	return out, nil
 }
 
+// SequenceOrderingMarkers reports runtime_sequence gaps and duplicates across normalized event
+// records as ordering markers for turn evidence. ValidateAndNormalizeEventRecords rejects
+// regressions but admits gaps and repeats, which must still be visible in replay evidence.
+func SequenceOrderingMarkers(records []apieventabi.EventRecord) []string {
+	return sequence.OrderingMarkers(sequence.AuditEventRecords(records))
+}
+
 // ValidateAndNormalizeControlSignals performs runtime-side normalization and validation
 // for control signals before persistence or egress.
 func ValidateAndNormalizeControlSignals(in []apieventabi.ControlSignal) ([]apieventabi.ControlSignal, error) {
@@ -91,6 +99,11 @@ func normalizeSignal(sig apieventabi.ControlSignal) (apieventabi.ControlSignal,
 			sig.Scope = "session"
 		}
 	}
+	if sig.FailureDomain == "" {
+		if domain, ok := apieventabi.FailureDomainForEmitter(sig.EmittedBy); ok {
+			sig.FailureDomain = domain
+		}
+	}
 	if sig.TransportSequence == nil {
 		zero := int64(0)
 		sig.TransportSequence = &zero
@@ -243,3 +243,33 @@ func TestValidateAndNormalizeEventRecordsRejectMissingPayloadClass(t *testing.T)
 		t.Fatalf("expected payload_class-related error, got %v", err)
 	}
 }
+
+func TestSequenceOrderingMarkersSurfaceGapsAndDuplicates(t *testing.T) {
+	t.Parallel()
+
+	records := make([]apieventabi.EventRecord, 0, 4)
+	for i, seq := range []int64{1, 2, 2, 5} {
+		transport := int64(i)
+		epoch := int64(1)
+		records = append(records, apieventabi.EventRecord{
+			EventScope:        apieventabi.ScopeTurn,
+			SessionID:         "sess-1",
+			TurnID:            "turn-1",
+			PipelineVersion:   "pipeline-v1",
+			EventID:           "evt-" + string(rune('a'+i)),
+			Lane:              apieventabi.LaneData,
+			TransportSequence: &transport,
+			RuntimeSequence:   seq,
+			AuthorityEpoch:    &epoch,
+			PayloadClass:      apieventabi.PayloadTextRaw,
+		})
+	}
+	out, err := ValidateAndNormalizeEventRecords(records)
+	if err != nil {
+		t.Fatalf("unexpected normalize/validate error: %v", err)
+	}
+	markers := SequenceOrderingMarkers(out)
+	if strings.Join(markers, ",") != "runtime_sequence_duplicate:2,runtime_sequence_gap:3-4" {
+		t.Fatalf("unexpected sequence markers %v", markers)
+	}
+}
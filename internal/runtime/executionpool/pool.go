@@ -1,17 +1,62 @@
 package executionpool
 
 import (
+	"container/heap"
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
+// ErrResourceUnavailable indicates a task's resource hints do not fit the declared capacity.
+var ErrResourceUnavailable = fmt.Errorf("execution pool resources unavailable")
+
+const (
+	// DefaultAgingInterval is the wait that earns a queued task one priority level.
+	DefaultAgingInterval = 50 * time.Millisecond
+	// DefaultFairnessKeyTTL is how long a fairness key keeps its wait accounting after its
+	// last dispatch.
+	DefaultFairnessKeyTTL = 5 * time.Minute
+	// DefaultMaxFairnessKeys bounds the fairness keys with wait accounting.
+	DefaultMaxFairnessKeys = 1024
+	// waitSampleWindow bounds the wait-time samples kept per fairness key.
+	waitSampleWindow = 256
+)
+
 // Task is one deterministic execution pool unit.
 type Task struct {
 	ID  string
 	Run func() error
+	// FairnessKey groups tasks for per-key wait-time accounting (for example a session ID).
+	FairnessKey string
+	// Priority orders dispatch; higher runs first. Equal priorities dispatch FIFO.
+	Priority int
+	// Resources is reserved from the declared capacity at submit and released after Run.
+	Resources ResourceRequest
+}
+
+// ResourceRequest is a task's accelerator footprint.
+type ResourceRequest struct {
+	GPUs     int
+	MemoryMB int64
+}
+
+// ResourceCapacity declares the accelerators available to one pool.
+type ResourceCapacity struct {
+	GPUs     int
+	MemoryMB int64
+}
+
+// FairnessKeyWait reports queue wait-time percentiles over a key's recent dispatches.
+type FairnessKeyWait struct {
+	Dispatched int64
+	P50        time.Duration
+	P95        time.Duration
+	P99        time.Duration
+	Max        time.Duration
 }
 
 // Stats reports execution pool counters.
@@ -21,27 +66,85 @@ type Stats struct {
 	Rejected   int64
 	InFlight   int64
 	QueueDepth int64
+	// ResourceRejected counts submits refused with ErrResourceUnavailable.
+	ResourceRejected int64
+	// Reserved is the accelerator footprint held by queued and in-flight tasks.
+	Reserved ResourceRequest
+	// WaitByFairnessKey reports queue wait percentiles per fairness key.
+	WaitByFairnessKey map[string]FairnessKeyWait
+}
+
+// Config controls execution pool capacity and priority aging.
+type Config struct {
+	Capacity int
+	// AgingInterval is the queue wait that boosts a task by one priority level, which bounds
+	// how long a low-priority task can be overtaken by a busy higher-priority key.
+	AgingInterval time.Duration
+	// Now overrides the clock used for enqueue/dispatch timestamps.
+	Now func() time.Time
+	// Resources declares accelerator capacity; nil disables resource accounting.
+	Resources *ResourceCapacity
+	// FairnessKeyTTL drops a key's wait accounting once it has not dispatched for this long,
+	// so ended sessions do not accumulate.
+	FairnessKeyTTL time.Duration
+	// MaxFairnessKeys bounds the keys with wait accounting; the least recently dispatched key
+	// is evicted beyond it.
+	MaxFairnessKeys int
 }
 
-// Manager is a bounded single-worker FIFO execution pool.
+func (c Config) withDefaults() Config {
+	if c.Capacity < 1 {
+		c.Capacity = 64
+	}
+	if c.AgingInterval <= 0 {
+		c.AgingInterval = DefaultAgingInterval
+	}
+	if c.Now == nil {
+		c.Now = time.Now
+	}
+	if c.FairnessKeyTTL <= 0 {
+		c.FairnessKeyTTL = DefaultFairnessKeyTTL
+	}
+	if c.MaxFairnessKeys < 1 {
+		c.MaxFairnessKeys = DefaultMaxFairnessKeys
+	}
+	return c
+}
+
+// Manager is a bounded single-worker execution pool that dispatches by aged priority.
 type Manager struct {
-	queue     chan Task
-	wg        sync.WaitGroup
-	submitted atomic.Int64
-	completed atomic.Int64
-	rejected  atomic.Int64
-	inFlight  atomic.Int64
-	closed    atomic.Bool
+	cfg Config
+
+	mu      sync.Mutex
+	ready   *sync.Cond
+	pending taskQueue
+	seq     uint64
+	closed  bool
+	waits   map[string]*keyWaits
+	// sweptAt is when idle fairness keys were last evicted.
+	sweptAt time.Time
+	held    ResourceRequest
+
+	wg               sync.WaitGroup
+	submitted        atomic.Int64
+	completed        atomic.Int64
+	rejected         atomic.Int64
+	inFlight         atomic.Int64
+	resourceRejected atomic.Int64
 }
 
-// NewManager creates a deterministic FIFO manager.
+// NewManager creates a FIFO manager with default aging.
 func NewManager(capacity int) *Manager {
-	if capacity < 1 {
-		capacity = 64
-	}
+	return NewManagerWithConfig(Config{Capacity: capacity})
+}
+
+// NewManagerWithConfig creates a manager from config.
+func NewManagerWithConfig(cfg Config) *Manager {
 	m := &Manager{
-		queue: make(chan Task, capacity),
+		cfg:   cfg.withDefaults(),
+		waits: make(map[string]*keyWaits),
 	}
+	m.ready = sync.NewCond(&m.mu)
 	m.wg.Add(1)
 	go m.worker()
 	return m
@@ -55,24 +158,60 @@ func (m *Manager) Submit(task Task) error {
 	if task.Run == nil {
 		return fmt.Errorf("task run func is required")
 	}
-	if m.closed.Load() {
+	if task.Resources.GPUs < 0 || task.Resources.MemoryMB < 0 {
+		return fmt.Errorf("task resources must be >=0")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.admitLocked(task.ID, task.Resources); err != nil {
 		m.rejected.Add(1)
+		if errors.Is(err, ErrResourceUnavailable) {
+			m.resourceRejected.Add(1)
+		}
+		return err
+	}
+	m.held.GPUs += task.Resources.GPUs
+	m.held.MemoryMB += task.Resources.MemoryMB
+	enqueuedAt := m.cfg.Now()
+	m.seq++
+	heap.Push(&m.pending, &queuedTask{
+		task:       task,
+		enqueuedAt: enqueuedAt,
+		// Aged priority is static once enqueued: a task that has waited Priority*AgingInterval
+		// longer than another ties with it, so the earliest effective deadline dispatches first.
+		deadline: enqueuedAt.Add(-time.Duration(task.Priority) * m.cfg.AgingInterval),
+		seq:      m.seq,
+	})
+	m.submitted.Add(1)
+	m.ready.Signal()
+	return nil
+}
+
+// CheckSubmit reports the error Submit would return for a task with id and resources right now,
+// without enqueueing, reserving, or counting a rejection.
+func (m *Manager) CheckSubmit(id string, resources ResourceRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.admitLocked(id, resources)
+}
+
+func (m *Manager) admitLocked(id string, resources ResourceRequest) error {
+	if m.closed {
 		return fmt.Errorf("execution pool is closed")
 	}
-	select {
-	case m.queue <- task:
-		m.submitted.Add(1)
-		return nil
-	default:
-		m.rejected.Add(1)
+	if len(m.pending) >= m.cfg.Capacity {
 		return fmt.Errorf("execution pool queue is full")
 	}
+	if !m.fitsLocked(resources) {
+		return fmt.Errorf("%w: task %s requests gpus=%d memory_mb=%d", ErrResourceUnavailable, id, resources.GPUs, resources.MemoryMB)
+	}
+	return nil
 }
 
 // Drain waits until queue/in-flight is empty, then closes worker.
 func (m *Manager) Drain(ctx context.Context) error {
 	for {
-		if len(m.queue) == 0 && m.inFlight.Load() == 0 {
+		if m.queueDepth() == 0 && m.inFlight.Load() == 0 {
 			break
 		}
 		select {
@@ -81,9 +220,10 @@ func (m *Manager) Drain(ctx context.Context) error {
 		case <-time.After(5 * time.Millisecond):
 		}
 	}
-	if m.closed.CompareAndSwap(false, true) {
-		close(m.queue)
-	}
+	m.mu.Lock()
+	m.closed = true
+	m.ready.Broadcast()
+	m.mu.Unlock()
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
@@ -99,21 +239,172 @@ func (m *Manager) Drain(ctx context.Context) error {
 
 // Stats returns a snapshot of pool counters.
 func (m *Manager) Stats() Stats {
-	return Stats{
-		Submitted:  m.submitted.Load(),
-		Completed:  m.completed.Load(),
-		Rejected:   m.rejected.Load(),
-		InFlight:   m.inFlight.Load(),
-		QueueDepth: int64(len(m.queue)),
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := Stats{
+		Submitted:        m.submitted.Load(),
+		Completed:        m.completed.Load(),
+		Rejected:         m.rejected.Load(),
+		InFlight:         m.inFlight.Load(),
+		QueueDepth:       int64(len(m.pending)),
+		ResourceRejected: m.resourceRejected.Load(),
+		Reserved:         m.held,
+	}
+	if len(m.waits) > 0 {
+		stats.WaitByFairnessKey = make(map[string]FairnessKeyWait, len(m.waits))
+		for key, waits := range m.waits {
+			stats.WaitByFairnessKey[key] = waits.summary()
+		}
 	}
+	return stats
+}
+
+func (m *Manager) queueDepth() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.pending)
 }
 
 func (m *Manager) worker() {
 	defer m.wg.Done()
-	for task := range m.queue {
+	for {
+		m.mu.Lock()
+		for len(m.pending) == 0 && !m.closed {
+			m.ready.Wait()
+		}
+		if len(m.pending) == 0 {
+			m.mu.Unlock()
+			return
+		}
+		next := heap.Pop(&m.pending).(*queuedTask)
+		dispatchedAt := m.cfg.Now()
+		m.recordWaitLocked(next.task.FairnessKey, dispatchedAt, dispatchedAt.Sub(next.enqueuedAt))
 		m.inFlight.Add(1)
-		_ = task.Run()
+		m.mu.Unlock()
+
+		_ = next.task.Run()
+		m.mu.Lock()
+		m.held.GPUs -= next.task.Resources.GPUs
+		m.held.MemoryMB -= next.task.Resources.MemoryMB
+		m.mu.Unlock()
 		m.completed.Add(1)
 		m.inFlight.Add(-1)
 	}
 }
+
+// fitsLocked reports whether req fits the declared capacity on top of held reservations.
+// Tasks without resource hints always fit.
+func (m *Manager) fitsLocked(req ResourceRequest) bool {
+	if m.cfg.Resources == nil || (req.GPUs == 0 && req.MemoryMB == 0) {
+		return true
+	}
+	capacity := *m.cfg.Resources
+	return m.held.GPUs+req.GPUs <= capacity.GPUs && m.held.MemoryMB+req.MemoryMB <= capacity.MemoryMB
+}
+
+func (m *Manager) recordWaitLocked(key string, dispatchedAt time.Time, wait time.Duration) {
+	if wait < 0 {
+		wait = 0
+	}
+	m.evictIdleKeysLocked(dispatchedAt)
+	waits, ok := m.waits[key]
+	if !ok {
+		if len(m.waits) >= m.cfg.MaxFairnessKeys {
+			m.evictLeastRecentKeyLocked()
+		}
+		waits = &keyWaits{}
+		m.waits[key] = waits
+	}
+	waits.dispatched++
+	waits.lastDispatchAt = dispatchedAt
+	if len(waits.samples) < waitSampleWindow {
+		waits.samples = append(waits.samples, wait)
+	} else {
+		waits.samples[waits.next] = wait
+	}
+	waits.next = (waits.next + 1) % waitSampleWindow
+}
+
+// evictIdleKeysLocked drops keys idle for FairnessKeyTTL, sweeping at most once per TTL.
+func (m *Manager) evictIdleKeysLocked(now time.Time) {
+	if now.Sub(m.sweptAt) < m.cfg.FairnessKeyTTL {
+		return
+	}
+	m.sweptAt = now
+	for key, waits := range m.waits {
+		if now.Sub(waits.lastDispatchAt) >= m.cfg.FairnessKeyTTL {
+			delete(m.waits, key)
+		}
+	}
+}
+
+func (m *Manager) evictLeastRecentKeyLocked() {
+	oldestKey := ""
+	var oldest time.Time
+	for key, waits := range m.waits {
+		if oldestKey == "" || waits.lastDispatchAt.Before(oldest) {
+			oldestKey, oldest = key, waits.lastDispatchAt
+		}
+	}
+	delete(m.waits, oldestKey)
+}
+
+type keyWaits struct {
+	dispatched     int64
+	lastDispatchAt time.Time
+	samples        []time.Duration
+	next           int
+}
+
+func (w *keyWaits) summary() FairnessKeyWait {
+	sorted := append([]time.Duration(nil), w.samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return FairnessKeyWait{
+		Dispatched: w.dispatched,
+		P50:        percentile(sorted, 50),
+		P95:        percentile(sorted, 95),
+		P99:        percentile(sorted, 99),
+		Max:        percentile(sorted, 100),
+	}
+}
+
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := (len(sorted)*p+99)/100 - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
+
+type queuedTask struct {
+	task       Task
+	enqueuedAt time.Time
+	deadline   time.Time
+	seq        uint64
+}
+
+type taskQueue []*queuedTask
+
+func (q taskQueue) Len() int { return len(q) }
+
+func (q taskQueue) Less(i, j int) bool {
+	if !q[i].deadline.Equal(q[j].deadline) {
+		return q[i].deadline.Before(q[j].deadline)
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q taskQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *taskQueue) Push(x any) { *q = append(*q, x.(*queuedTask)) }
+
+func (q *taskQueue) Pop() any {
+	old := *q
+	last := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return last
+}
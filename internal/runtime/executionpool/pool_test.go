@@ -2,7 +2,9 @@ package executionpool
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -75,3 +77,291 @@ func TestManagerRejectsWhenSaturated(t *testing.T) {
 		t.Fatalf("unexpected drain error: %v", err)
 	}
 }
+
+func TestManagerPriorityAgingBoundsStarvation(t *testing.T) {
+	t.Parallel()
+
+	// Each clock read advances 1ms so enqueue order maps to deterministic timestamps.
+	var ticks atomic.Int64
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return base.Add(time.Duration(ticks.Add(1)) * time.Millisecond) }
+
+	tests := []struct {
+		name          string
+		agingInterval time.Duration
+		maxOvertakes  int
+	}{
+		{name: "short aging interval", agingInterval: 2 * time.Millisecond, maxOvertakes: 20},
+		{name: "long aging interval", agingInterval: 10 * time.Millisecond, maxOvertakes: 100},
+	}
+	for _, tc := range tests {
+		manager := NewManagerWithConfig(Config{Capacity: 64, AgingInterval: tc.agingInterval, Now: clock})
+		gate := make(chan struct{})
+		if err := manager.Submit(Task{ID: "gate", Run: func() error { <-gate; return nil }}); err != nil {
+			t.Fatalf("%s: unexpected gate submit error: %v", tc.name, err)
+		}
+
+		var mu sync.Mutex
+		heavyRuns := 0
+		quietAfter := -1
+		var submitHeavy func() error
+		submitHeavy = func() error {
+			return manager.Submit(Task{ID: "heavy", FairnessKey: "heavy", Priority: 10, Run: func() error {
+				mu.Lock()
+				heavyRuns++
+				keepCycling := quietAfter < 0 && heavyRuns < 1000
+				mu.Unlock()
+				if keepCycling {
+					return submitHeavy()
+				}
+				return nil
+			}})
+		}
+		for i := 0; i < 4; i++ {
+			if err := submitHeavy(); err != nil {
+				t.Fatalf("%s: unexpected heavy submit error: %v", tc.name, err)
+			}
+		}
+		if err := manager.Submit(Task{ID: "quiet", FairnessKey: "quiet", Run: func() error {
+			mu.Lock()
+			quietAfter = heavyRuns
+			mu.Unlock()
+			return nil
+		}}); err != nil {
+			t.Fatalf("%s: unexpected quiet submit error: %v", tc.name, err)
+		}
+		close(gate)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := manager.Drain(ctx); err != nil {
+			cancel()
+			t.Fatalf("%s: unexpected drain error: %v", tc.name, err)
+		}
+		cancel()
+		if quietAfter < 0 || quietAfter > tc.maxOvertakes {
+			t.Fatalf("%s: expected quiet key to run within %d heavy dispatches, got %d", tc.name, tc.maxOvertakes, quietAfter)
+		}
+		stats := manager.Stats()
+		quiet, ok := stats.WaitByFairnessKey["quiet"]
+		if !ok || quiet.Dispatched != 1 || quiet.P99 <= 0 || quiet.P99 != quiet.Max {
+			t.Fatalf("%s: expected quiet key wait percentiles, got %+v", tc.name, stats.WaitByFairnessKey)
+		}
+		heavy := stats.WaitByFairnessKey["heavy"]
+		if heavy.Dispatched < int64(quietAfter) || heavy.P50 > heavy.P95 || heavy.P95 > heavy.P99 {
+			t.Fatalf("%s: expected ordered heavy key percentiles, got %+v", tc.name, heavy)
+		}
+	}
+}
+
+func TestManagerDispatchesHigherPriorityFirst(t *testing.T) {
+	t.Parallel()
+
+	manager := NewManager(8)
+	gate := make(chan struct{})
+	if err := manager.Submit(Task{ID: "gate", Run: func() error { <-gate; return nil }}); err != nil {
+		t.Fatalf("unexpected gate submit error: %v", err)
+	}
+	var mu sync.Mutex
+	order := make([]string, 0, 3)
+	for _, task := range []struct {
+		id       string
+		priority int
+	}{{"low", 0}, {"high", 5}, {"low-2", 0}} {
+		id := task.id
+		if err := manager.Submit(Task{ID: id, Priority: task.priority, Run: func() error {
+			mu.Lock()
+			order = append(order, id)
+			mu.Unlock()
+			return nil
+		}}); err != nil {
+			t.Fatalf("unexpected submit error: %v", err)
+		}
+	}
+	close(gate)
+	if err := manager.Drain(context.Background()); err != nil {
+		t.Fatalf("unexpected drain error: %v", err)
+	}
+	if len(order) != 3 || order[0] != "high" || order[1] != "low" || order[2] != "low-2" {
+		t.Fatalf("expected [high low low-2], got %+v", order)
+	}
+}
+
+func TestManagerRejectsAcceleratorOversubscription(t *testing.T) {
+	t.Parallel()
+
+	manager := NewManagerWithConfig(Config{Capacity: 8, Resources: &ResourceCapacity{GPUs: 1, MemoryMB: 8000}})
+	block := make(chan struct{})
+	started := make(chan struct{})
+	if err := manager.Submit(Task{
+		ID:        "local-llm",
+		Resources: ResourceRequest{GPUs: 1, MemoryMB: 6000},
+		Run: func() error {
+			close(started)
+			<-block
+			return nil
+		},
+	}); err != nil {
+		t.Fatalf("unexpected gpu submit error: %v", err)
+	}
+	<-started
+
+	tests := []struct {
+		name      string
+		resources ResourceRequest
+		wantErr   bool
+	}{
+		{name: "second gpu task", resources: ResourceRequest{GPUs: 1}, wantErr: true},
+		{name: "memory over estimate", resources: ResourceRequest{MemoryMB: 4000}, wantErr: true},
+		{name: "memory within estimate", resources: ResourceRequest{MemoryMB: 2000}},
+		{name: "no hints", resources: ResourceRequest{}},
+	}
+	for _, tc := range tests {
+		err := manager.Submit(Task{ID: tc.name, Resources: tc.resources, Run: func() error { return nil }})
+		if tc.wantErr != (err != nil) || (tc.wantErr && !errors.Is(err, ErrResourceUnavailable)) {
+			t.Fatalf("%s: expected resource error=%v, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+	stats := manager.Stats()
+	if stats.ResourceRejected != 2 || stats.Reserved != (ResourceRequest{GPUs: 1, MemoryMB: 8000}) {
+		t.Fatalf("expected two resource rejects with full reservation, got %+v", stats)
+	}
+
+	close(block)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := manager.Drain(ctx); err != nil {
+		t.Fatalf("unexpected drain error: %v", err)
+	}
+	if stats := manager.Stats(); stats.Reserved != (ResourceRequest{}) {
+		t.Fatalf("expected reservations released after completion, got %+v", stats.Reserved)
+	}
+}
+
+func TestManagerCheckSubmitDoesNotReserve(t *testing.T) {
+	t.Parallel()
+
+	manager := NewManagerWithConfig(Config{Capacity: 4, Resources: &ResourceCapacity{GPUs: 1, MemoryMB: 1000}})
+	tests := []struct {
+		name      string
+		resources ResourceRequest
+		wantErr   bool
+	}{
+		{name: "fits", resources: ResourceRequest{GPUs: 1, MemoryMB: 1000}},
+		{name: "too many gpus", resources: ResourceRequest{GPUs: 2}, wantErr: true},
+		{name: "fits again", resources: ResourceRequest{GPUs: 1}},
+	}
+	for _, tc := range tests {
+		err := manager.CheckSubmit(tc.name, tc.resources)
+		if tc.wantErr != (err != nil) || (tc.wantErr && !errors.Is(err, ErrResourceUnavailable)) {
+			t.Fatalf("%s: expected resource error=%v, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+	if stats := manager.Stats(); stats.Submitted != 0 || stats.Rejected != 0 || stats.ResourceRejected != 0 || stats.Reserved != (ResourceRequest{}) {
+		t.Fatalf("expected check to leave counters and reservations untouched, got %+v", stats)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := manager.Drain(ctx); err != nil {
+		t.Fatalf("unexpected drain error: %v", err)
+	}
+	if err := manager.CheckSubmit("after-drain", ResourceRequest{}); err == nil {
+		t.Fatalf("expected closed pool check to fail")
+	}
+}
+
+func TestManagerDispatchesLowPriorityWithinAgingBoundUnderLoad(t *testing.T) {
+	t.Parallel()
+
+	// The clock only advances when a heavy task runs, so waits are measured in heavy dispatches.
+	var nowMS atomic.Int64
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return base.Add(time.Duration(nowMS.Load()) * time.Millisecond) }
+	const (
+		agingInterval = 5 * time.Millisecond
+		priorityGap   = 10
+		queuedHeavy   = 4
+	)
+	manager := NewManagerWithConfig(Config{Capacity: 64, AgingInterval: agingInterval, Now: clock})
+	gate := make(chan struct{})
+	if err := manager.Submit(Task{ID: "gate", Run: func() error { <-gate; return nil }}); err != nil {
+		t.Fatalf("unexpected gate submit error: %v", err)
+	}
+
+	var quietDone atomic.Bool
+	var heavyRuns atomic.Int64
+	var submitHeavy func() error
+	submitHeavy = func() error {
+		return manager.Submit(Task{ID: "heavy", FairnessKey: "heavy", Priority: priorityGap, Run: func() error {
+			nowMS.Add(1)
+			// Heavy load never lets up until the quiet task has run.
+			if !quietDone.Load() && heavyRuns.Add(1) < 10_000 {
+				return submitHeavy()
+			}
+			return nil
+		}})
+	}
+	for i := 0; i < queuedHeavy; i++ {
+		if err := submitHeavy(); err != nil {
+			t.Fatalf("unexpected heavy submit error: %v", err)
+		}
+	}
+	if err := manager.Submit(Task{ID: "quiet", FairnessKey: "quiet", Run: func() error {
+		quietDone.Store(true)
+		return nil
+	}}); err != nil {
+		t.Fatalf("unexpected quiet submit error: %v", err)
+	}
+	close(gate)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := manager.Drain(ctx); err != nil {
+		t.Fatalf("unexpected drain error: %v", err)
+	}
+	// Heavy tasks enqueued priorityGap*agingInterval after the quiet task no longer overtake it;
+	// only the heavy tasks already queued then can still run first.
+	bound := priorityGap*agingInterval + queuedHeavy*time.Millisecond
+	quiet := manager.Stats().WaitByFairnessKey["quiet"]
+	if !quietDone.Load() || quiet.Dispatched != 1 || quiet.Max > bound {
+		t.Fatalf("expected quiet task dispatched within %s under heavy load, got %+v", bound, quiet)
+	}
+}
+
+func TestManagerEvictsIdleFairnessKeys(t *testing.T) {
+	t.Parallel()
+
+	var nowMS atomic.Int64
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return base.Add(time.Duration(nowMS.Load()) * time.Millisecond) }
+	manager := NewManagerWithConfig(Config{Capacity: 8, Now: clock, FairnessKeyTTL: time.Second, MaxFairnessKeys: 2})
+	run := func(key string) {
+		t.Helper()
+		done := make(chan struct{})
+		if err := manager.Submit(Task{ID: "task-" + key, FairnessKey: key, Run: func() error { close(done); return nil }}); err != nil {
+			t.Fatalf("unexpected submit error: %v", err)
+		}
+		<-done
+		// Wait accounting is recorded at dispatch, before the task runs.
+	}
+
+	run("sess-1")
+	nowMS.Add(10)
+	run("sess-2")
+	nowMS.Add(10)
+	run("sess-3")
+	if waits := manager.Stats().WaitByFairnessKey; len(waits) != 2 || waits["sess-1"].Dispatched != 0 {
+		t.Fatalf("expected least recently dispatched key evicted at the key bound, got %+v", waits)
+	}
+
+	nowMS.Add(2000)
+	run("sess-4")
+	if waits := manager.Stats().WaitByFairnessKey; len(waits) != 1 || waits["sess-4"].Dispatched != 1 {
+		t.Fatalf("expected idle keys evicted after the ttl, got %+v", waits)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := manager.Drain(ctx); err != nil {
+		t.Fatalf("unexpected drain error: %v", err)
+	}
+}
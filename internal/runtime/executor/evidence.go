@@ -0,0 +1,131 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+// Node span statuses recorded as ordering markers by BaselineEvidenceFromTrace.
+const (
+	NodeSpanDispatched = "dispatched"
+	NodeSpanCacheHit   = "cache_hit"
+	NodeSpanDenied     = "denied"
+	NodeSpanFailed     = "failed"
+	NodeSpanDegraded   = "degraded"
+)
+
+// TurnEvidenceState is the arbiter-owned turn state an ExecutionTrace does not carry.
+type TurnEvidenceState struct {
+	SessionID          string
+	TurnID             string
+	PipelineVersion    string
+	EventID            string
+	PlanHash           string
+	SnapshotProvenance controlplane.SnapshotProvenance
+	AuthorityEpoch     int64
+	// RuntimeSequence is the base sequence the plan executed at; it seeds determinism evidence.
+	RuntimeSequence int64
+	// Admission is the pre-turn admit decision that opened the turn.
+	Admission            controlplane.DecisionOutcome
+	TurnOpenProposedAtMS *int64
+	TurnOpenAtMS         *int64
+	FirstOutputAtMS      *int64
+	// TerminalOutcome and TerminalReason override the trace-derived terminal, e.g. when the
+	// arbiter cancelled the turn after the plan completed.
+	TerminalOutcome string
+	TerminalReason  string
+}
+
+// BaselineEvidenceFromTrace derives OR-02 baseline evidence from what a plan execution actually
+// did: node decisions, provider invocation outcomes, one ordering marker per node span, and the
+// terminal outcome. Cache hits contribute their span but no invocation outcome, since no provider
+// was called.
+func BaselineEvidenceFromTrace(trace ExecutionTrace, state TurnEvidenceState) (timeline.BaselineEvidence, error) {
+	if state.SessionID == "" || state.TurnID == "" || state.EventID == "" || state.PlanHash == "" {
+		return timeline.BaselineEvidence{}, fmt.Errorf("session_id, turn_id, event_id, and plan_hash are required")
+	}
+	if len(trace.Nodes) > len(trace.NodeOrder) {
+		return timeline.BaselineEvidence{}, fmt.Errorf("execution trace has %d node results for %d ordered nodes", len(trace.Nodes), len(trace.NodeOrder))
+	}
+
+	payloadTags := []eventabi.PayloadClass{eventabi.PayloadMetadata}
+	redactions, err := timeline.EnsureOR02RedactionDecisions("L0", payloadTags, nil)
+	if err != nil {
+		return timeline.BaselineEvidence{}, err
+	}
+	seed := nonNegative(state.RuntimeSequence)
+	evidence := timeline.BaselineEvidence{
+		SessionID:            state.SessionID,
+		TurnID:               state.TurnID,
+		PipelineVersion:      defaultPipelineVersion(state.PipelineVersion),
+		EventID:              state.EventID,
+		EnvelopeSnapshot:     "eventabi/v1",
+		PayloadTags:          payloadTags,
+		RedactionDecisions:   redactions,
+		PlanHash:             state.PlanHash,
+		SnapshotProvenance:   state.SnapshotProvenance,
+		DecisionOutcomes:     []controlplane.DecisionOutcome{state.Admission},
+		DeterminismSeed:      seed,
+		OrderingMarkers:      []string{fmt.Sprintf("runtime_sequence:%d", seed)},
+		MergeRuleID:          "merge/default",
+		MergeRuleVersion:     "v1.0",
+		AuthorityEpoch:       nonNegative(state.AuthorityEpoch),
+		CloseEmitted:         true,
+		TurnOpenProposedAtMS: state.TurnOpenProposedAtMS,
+		TurnOpenAtMS:         state.TurnOpenAtMS,
+		FirstOutputAtMS:      state.FirstOutputAtMS,
+	}
+
+	for _, step := range trace.DegradeSteps {
+		evidence.OrderingMarkers = append(evidence.OrderingMarkers, fmt.Sprintf("degrade:%s", step))
+	}
+	for idx, node := range trace.Nodes {
+		if node.NodeID != trace.NodeOrder[idx] {
+			return timeline.BaselineEvidence{}, fmt.Errorf("execution trace node %d is %s, expected %s", idx, node.NodeID, trace.NodeOrder[idx])
+		}
+		evidence.OrderingMarkers = append(evidence.OrderingMarkers, fmt.Sprintf("node:%d:%s:%s", idx, node.NodeID, nodeSpanStatus(node)))
+		if node.DegradedBy != "" {
+			continue
+		}
+		if node.Decision.Outcome != nil {
+			evidence.DecisionOutcomes = append(evidence.DecisionOutcomes, *node.Decision.Outcome)
+		}
+		if node.Decision.Provider != nil && !node.CacheHit {
+			evidence.InvocationOutcomes = append(evidence.InvocationOutcomes, node.Decision.Provider.ToInvocationOutcomeEvidence())
+		}
+	}
+
+	switch {
+	case state.TerminalOutcome != "":
+		evidence.TerminalOutcome = state.TerminalOutcome
+		evidence.TerminalReason = state.TerminalReason
+	case trace.Completed:
+		evidence.TerminalOutcome = "commit"
+	default:
+		evidence.TerminalOutcome = "abort"
+		evidence.TerminalReason = trace.TerminalReason
+	}
+
+	if err := evidence.ValidateCompleteness(); err != nil {
+		return timeline.BaselineEvidence{}, err
+	}
+	return evidence, nil
+}
+
+func nodeSpanStatus(node NodeExecutionResult) string {
+	switch {
+	case node.DegradedBy != "":
+		return fmt.Sprintf("%s:%s", NodeSpanDegraded, node.DegradedBy)
+	case node.CacheHit:
+		return NodeSpanCacheHit
+	case node.Failure != nil:
+		return NodeSpanFailed
+	case !node.Decision.Allowed:
+		return NodeSpanDenied
+	default:
+		return NodeSpanDispatched
+	}
+}
@@ -0,0 +1,188 @@
+package executor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/localadmission"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/invocation"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+)
+
+func evidenceState(turnID string) TurnEvidenceState {
+	openAt := int64(90)
+	return TurnEvidenceState{
+		SessionID:       "sess-evidence",
+		TurnID:          turnID,
+		PipelineVersion: "pipeline-v1",
+		EventID:         "evt-" + turnID,
+		PlanHash:        "plan/" + turnID,
+		SnapshotProvenance: controlplane.SnapshotProvenance{
+			RoutingViewSnapshot:       "routing-view/v1",
+			AdmissionPolicySnapshot:   "admission-policy/v1",
+			ABICompatibilitySnapshot:  "abi-compat/v1",
+			VersionResolutionSnapshot: "version-resolution/v1",
+			PolicyResolutionSnapshot:  "policy-resolution/v1",
+			ProviderHealthSnapshot:    "provider-health/v1",
+		},
+		AuthorityEpoch:  3,
+		RuntimeSequence: 11,
+		Admission: controlplane.DecisionOutcome{
+			OutcomeKind:        controlplane.OutcomeAdmit,
+			Phase:              controlplane.PhasePreTurn,
+			Scope:              controlplane.ScopeTurn,
+			SessionID:          "sess-evidence",
+			TurnID:             turnID,
+			EventID:            "evt-" + turnID + "-admit",
+			RuntimeTimestampMS: 90,
+			WallClockMS:        90,
+			EmittedBy:          controlplane.EmitterRK25,
+			Reason:             "admission_capacity_allow",
+		},
+		TurnOpenAtMS: &openAt,
+	}
+}
+
+func TestBaselineEvidenceFromTrace(t *testing.T) {
+	t.Parallel()
+
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{
+			ID:   "stt-a",
+			Mode: contracts.ModalitySTT,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				return contracts.Outcome{Class: contracts.OutcomeOverload, CircuitOpen: true, Reason: "provider_overload"}, nil
+			},
+		},
+		contracts.StaticAdapter{
+			ID:   "stt-b",
+			Mode: contracts.ModalitySTT,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	scheduler := NewSchedulerWithProviderInvoker(localadmission.Evaluator{}, invocation.NewController(catalog))
+	input := SchedulingInput{
+		SessionID:            "sess-evidence",
+		EventID:              "evt-evidence-plan",
+		PipelineVersion:      "pipeline-v1",
+		TransportSequence:    10,
+		RuntimeSequence:      11,
+		AuthorityEpoch:       3,
+		RuntimeTimestampMS:   100,
+		WallClockTimestampMS: 100,
+	}
+	providerNode := NodeSpec{
+		NodeID:   "stt",
+		NodeType: "provider",
+		Lane:     eventabi.LaneData,
+		Provider: &ProviderInvocationInput{
+			Modality:               contracts.ModalitySTT,
+			PreferredProvider:      "stt-a",
+			AllowedAdaptiveActions: []string{"provider_switch"},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		plan            ExecutionPlan
+		override        string
+		wantOutcome     string
+		wantReason      string
+		wantMarkers     []string
+		wantInvocations []string
+	}{
+		{
+			name: "completed provider plan commits",
+			plan: ExecutionPlan{
+				Nodes: []NodeSpec{{NodeID: "admission", NodeType: "admission", Lane: eventabi.LaneControl}, providerNode},
+				Edges: []EdgeSpec{{From: "admission", To: "stt"}},
+			},
+			wantOutcome:     "commit",
+			wantMarkers:     []string{"runtime_sequence:11", "node:0:admission:dispatched", "node:1:stt:dispatched"},
+			wantInvocations: []string{"stt-b"},
+		},
+		{
+			name: "denied node aborts with trace reason",
+			plan: ExecutionPlan{
+				Nodes: []NodeSpec{{NodeID: "admission", NodeType: "admission", Lane: eventabi.LaneControl, Shed: true}, providerNode},
+				Edges: []EdgeSpec{{From: "admission", To: "stt"}},
+			},
+			wantOutcome: "abort",
+			wantReason:  "execution_plan_denied",
+			wantMarkers: []string{"runtime_sequence:11", "node:0:admission:denied"},
+		},
+		{
+			name: "arbiter terminal overrides completed trace",
+			plan: ExecutionPlan{
+				Nodes: []NodeSpec{providerNode},
+			},
+			override:        "cancelled",
+			wantOutcome:     "abort",
+			wantReason:      "cancelled",
+			wantMarkers:     []string{"runtime_sequence:11", "node:0:stt:dispatched"},
+			wantInvocations: []string{"stt-b"},
+		},
+	}
+	for _, tc := range tests {
+		nodeInput := input
+		nodeInput.TurnID = "turn-" + strings.ReplaceAll(tc.name, " ", "-")
+		trace, err := scheduler.ExecutePlan(nodeInput, tc.plan)
+		if err != nil {
+			t.Fatalf("%s: unexpected execute plan error: %v", tc.name, err)
+		}
+		state := evidenceState(nodeInput.TurnID)
+		if tc.override != "" {
+			state.TerminalOutcome = "abort"
+			state.TerminalReason = tc.override
+		}
+		evidence, err := BaselineEvidenceFromTrace(trace, state)
+		if err != nil {
+			t.Fatalf("%s: unexpected evidence error: %v", tc.name, err)
+		}
+		if evidence.TerminalOutcome != tc.wantOutcome || evidence.TerminalReason != tc.wantReason {
+			t.Fatalf("%s: expected terminal %s/%s, got %s/%s", tc.name, tc.wantOutcome, tc.wantReason, evidence.TerminalOutcome, evidence.TerminalReason)
+		}
+		if !reflect.DeepEqual(evidence.OrderingMarkers, tc.wantMarkers) {
+			t.Fatalf("%s: expected markers %v, got %v", tc.name, tc.wantMarkers, evidence.OrderingMarkers)
+		}
+		providers := make([]string, 0, len(evidence.InvocationOutcomes))
+		for _, outcome := range evidence.InvocationOutcomes {
+			providers = append(providers, outcome.ProviderID)
+		}
+		if len(providers) != len(tc.wantInvocations) || (len(providers) > 0 && !reflect.DeepEqual(providers, tc.wantInvocations)) {
+			t.Fatalf("%s: expected invocation providers %v, got %v", tc.name, tc.wantInvocations, providers)
+		}
+		if evidence.DecisionOutcomes[0].OutcomeKind != controlplane.OutcomeAdmit || evidence.DeterminismSeed != 11 || evidence.AuthorityEpoch != 3 {
+			t.Fatalf("%s: expected admission decision and arbiter state bound, got %+v", tc.name, evidence)
+		}
+	}
+}
+
+func TestBaselineEvidenceFromTraceRejectsInconsistentInput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		trace ExecutionTrace
+		state TurnEvidenceState
+	}{
+		{name: "missing plan hash", trace: ExecutionTrace{Completed: true}, state: func() TurnEvidenceState { s := evidenceState("turn-x"); s.PlanHash = ""; return s }()},
+		{name: "node out of order", trace: ExecutionTrace{NodeOrder: []string{"a"}, Nodes: []NodeExecutionResult{{NodeID: "b"}}, Completed: true}, state: evidenceState("turn-y")},
+		{name: "more results than nodes", trace: ExecutionTrace{Nodes: []NodeExecutionResult{{NodeID: "a"}}, Completed: true}, state: evidenceState("turn-z")},
+		{name: "abort without reason", trace: ExecutionTrace{Completed: false}, state: evidenceState("turn-w")},
+	}
+	for _, tc := range tests {
+		if _, err := BaselineEvidenceFromTrace(tc.trace, tc.state); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
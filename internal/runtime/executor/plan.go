@@ -1,17 +1,29 @@
 package executor
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/determinism"
 	runtimeeventabi "github.com/tiger/realtime-speech-pipeline/internal/runtime/eventabi"
 	runtimeexecutionpool "github.com/tiger/realtime-speech-pipeline/internal/runtime/executionpool"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/flowcontrol"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/lanes"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/localadmission"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/nodecache"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/nodehost"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/prelude"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
 )
 
 // NodeSpec defines one deterministic runtime execution node.
+// Deterministic nodes are memoized on their actual input (node type, lane, upstream output,
+// and provider invocation input) when a node cache is wired.
 type NodeSpec struct {
 	NodeID        string
 	NodeType      string
@@ -21,6 +33,20 @@ type NodeSpec struct {
 	Reason        string
 	AllowDegrade  bool
 	AllowFallback bool
+	Deterministic bool
+	// Partial marks a non-final streaming output (for example an STT partial transcript).
+	Partial bool
+	// AudioEnhancement marks the optional pre-STT noise-suppression node; STT consumes raw
+	// audio when the degrade ladder bypasses it.
+	AudioEnhancement bool
+	// Enhancement configures an AudioEnhancement node: while it runs, its downstream STT nodes
+	// transcribe prelude.EnhanceAudio output instead of the raw capture, and each downstream
+	// STT success records audio enhancement evidence with the transcript confidence.
+	Enhancement *controlplane.AudioEnhancement
+	// RequiresGPU and MemoryEstimateMB are resource hints for nodes backed by local models;
+	// the execution pool sheds the node with resource_unavailable rather than oversubscribe.
+	RequiresGPU      bool
+	MemoryEstimateMB int64
 }
 
 // EdgeSpec defines one directed edge between execution nodes.
@@ -30,9 +56,16 @@ type EdgeSpec struct {
 }
 
 // ExecutionPlan defines a runtime execution graph for deterministic dispatch.
+// A non-nil DegradeLadder is evaluated once against SchedulingInput.QueueDepth before dispatch.
+// OutputLimits caps every LLM and TTS invocation; the smaller of it and a ladder cap wins.
+// TurnBudgetMS, when >0, is the plan turn latency budget (Budgets.TurnBudgetMS); each provider
+// node inherits what is left of it when dispatched, bounding its attempt deadlines.
 type ExecutionPlan struct {
-	Nodes []NodeSpec
-	Edges []EdgeSpec
+	Nodes         []NodeSpec
+	Edges         []EdgeSpec
+	DegradeLadder *controlplane.DegradeLadder
+	OutputLimits  *controlplane.OutputLimits
+	TurnBudgetMS  int64
 }
 
 // NodeExecutionResult captures one dispatched node outcome.
@@ -41,6 +74,9 @@ type NodeExecutionResult struct {
 	DispatchTarget lanes.DispatchTarget
 	Decision       SchedulingDecision
 	Failure        *nodehost.NodeFailureResult
+	CacheHit       bool
+	// DegradedBy names the degrade ladder step that skipped this node without dispatch.
+	DegradedBy controlplane.DegradeStep
 }
 
 // ExecutionTrace summarizes deterministic plan execution.
@@ -48,6 +84,8 @@ type ExecutionTrace struct {
 	NodeOrder      []string
 	Nodes          []NodeExecutionResult
 	ControlSignals []eventabi.ControlSignal
+	// DegradeSteps lists the engaged degrade ladder steps in severity order.
+	DegradeSteps   []controlplane.DegradeStep
 	Completed      bool
 	TerminalReason string
 }
@@ -63,6 +101,9 @@ func (s Scheduler) ExecutePlan(in SchedulingInput, plan ExecutionPlan) (Executio
 	if err != nil {
 		return ExecutionTrace{}, err
 	}
+	if s.cancelFence != nil {
+		defer s.cancelFence.Release(in.SessionID, in.TurnID)
+	}
 
 	router := s.router
 	if router == nil {
@@ -81,6 +122,28 @@ func (s Scheduler) ExecutePlan(in SchedulingInput, plan ExecutionPlan) (Executio
 	if baseEventID == "" {
 		baseEventID = "evt-execution-plan"
 	}
+	started := s.now()
+	var budget *localadmission.BudgetUsage
+	if in.Budget != nil {
+		usage := *in.Budget
+		budget = &usage
+	}
+
+	degrade, err := evaluatePlanDegrade(in, plan, baseEventID)
+	if err != nil {
+		return ExecutionTrace{}, err
+	}
+	trace.DegradeSteps = degrade.decision.Steps
+	trace.ControlSignals = append(trace.ControlSignals, degrade.signals...)
+
+	upstream := make(map[string][]string, len(plan.Edges))
+	for _, edge := range plan.Edges {
+		upstream[edge.To] = append(upstream[edge.To], edge.From)
+	}
+	// outputs holds each provider node's text output for its downstream nodes.
+	outputs := make(map[string]string, len(order))
+	// enhancements holds each audio enhancement node's run or bypass for its downstream nodes.
+	enhancements := make(map[string]enhancementRun)
 
 	for idx, nodeID := range order {
 		node := nodeByID[nodeID]
@@ -89,7 +152,8 @@ func (s Scheduler) ExecutePlan(in SchedulingInput, plan ExecutionPlan) (Executio
 			return ExecutionTrace{}, err
 		}
 
-		offset := int64(idx)
+		// Degrade evidence occupies the leading runtime sequences of this execution.
+		offset := int64(len(degrade.signals) + idx)
 		nodeInput := in
 		nodeInput.EventID = fmt.Sprintf("%s-%s", baseEventID, node.NodeID)
 		nodeInput.Shed = node.Shed
@@ -100,16 +164,97 @@ func (s Scheduler) ExecutePlan(in SchedulingInput, plan ExecutionPlan) (Executio
 		nodeInput.RuntimeTimestampMS = nonNegative(in.RuntimeTimestampMS) + offset
 		nodeInput.WallClockTimestampMS = nonNegative(in.WallClockTimestampMS) + offset
 		nodeInput.ProviderInvocation = node.Provider
+		if budget != nil {
+			usage := *budget
+			nodeInput.Budget = &usage
+		}
+
+		if step, skip := degrade.skips(node); skip {
+			if node.Enhancement != nil {
+				enhancements[node.NodeID] = enhancementRun{cfg: *node.Enhancement, bypassedBy: step}
+			}
+			trace.Nodes = append(trace.Nodes, NodeExecutionResult{
+				NodeID:         node.NodeID,
+				DispatchTarget: dispatchTarget,
+				DegradedBy:     step,
+			})
+			continue
+		}
+		if degrade.sheds(node) {
+			nodeInput.Shed = true
+			nodeInput.Reason = degradeShedReason
+		}
+		capped := degrade.capProvider(limitProviderOutput(plan.OutputLimits, node.Provider))
+		nodeInput.ProviderInvocation = capped
 
-		decision, err := s.dispatchNode(node.NodeID, nodeInput)
+		upstreamText := upstreamOutput(upstream[node.NodeID], outputs)
+		nodeInput.ProviderInvocation = withUpstreamOutput(nodeInput.ProviderInvocation, upstreamText)
+		enhancers := upstreamEnhancements(upstream[node.NodeID], enhancements)
+		nodeInput.ProviderInvocation, err = withEnhancedAudio(nodeInput.ProviderInvocation, enhancers)
 		if err != nil {
 			return ExecutionTrace{}, err
 		}
+
+		cacheKey, cacheable, err := s.nodeCacheKey(nodeInput, node, upstreamText)
+		if err != nil {
+			return ExecutionTrace{}, err
+		}
+		if cacheable {
+			if entry, ok := s.nodeCache.Get(cacheKey); ok {
+				if err := s.appendNodeCacheEvidence(nodeInput, cacheKey, true, entry.SourceEventID); err != nil {
+					return ExecutionTrace{}, err
+				}
+				trace.Nodes = append(trace.Nodes, NodeExecutionResult{
+					NodeID:         node.NodeID,
+					DispatchTarget: dispatchTarget,
+					Decision:       entry.Value,
+					CacheHit:       true,
+				})
+				if entry.Value.Provider != nil {
+					outputs[node.NodeID] = entry.Value.Provider.OutputText
+				}
+				if err := s.appendEnhancementEvidence(nodeInput, baseEventID, enhancers, entry.Value); err != nil {
+					return ExecutionTrace{}, err
+				}
+				continue
+			}
+		}
+
+		nodeInput.ProviderInvocation = withSamplingSeed(nodeInput.ProviderInvocation, nonNegative(in.RuntimeSequence), in.TurnID, node.NodeID)
+		nodeInput.ProviderInvocation = withTurnBudget(nodeInput.ProviderInvocation, plan.TurnBudgetMS, s.now().Sub(started).Milliseconds())
+
+		decision, err := s.dispatchNode(node, nodeInput)
+		if err != nil {
+			return ExecutionTrace{}, err
+		}
+		if cacheable && decision.Allowed && decision.ControlSignal == nil {
+			if err := s.nodeCache.Put(cacheKey, nodeInput.EventID, decision); err != nil {
+				return ExecutionTrace{}, err
+			}
+			if err := s.appendNodeCacheEvidence(nodeInput, cacheKey, false, ""); err != nil {
+				return ExecutionTrace{}, err
+			}
+		}
 		trace.Nodes = append(trace.Nodes, NodeExecutionResult{
 			NodeID:         node.NodeID,
 			DispatchTarget: dispatchTarget,
 			Decision:       decision,
 		})
+		if decision.Provider != nil {
+			outputs[node.NodeID] = decision.Provider.OutputText
+		}
+		if node.Enhancement != nil && decision.Allowed {
+			enhancements[node.NodeID] = enhancementRun{cfg: *node.Enhancement}
+		}
+		if err := s.appendEnhancementEvidence(nodeInput, baseEventID, enhancers, decision); err != nil {
+			return ExecutionTrace{}, err
+		}
+		if budget != nil && decision.Provider != nil {
+			if usage := decision.Provider.Usage; usage != nil {
+				budget.TurnTokens += int64(usage.InputTokens + usage.OutputTokens)
+			}
+			budget.SessionSpendUSD += decision.Provider.EstimatedCostUSD
+		}
 
 		if decision.ControlSignal != nil {
 			trace.ControlSignals = append(trace.ControlSignals, *decision.ControlSignal)
@@ -161,6 +306,318 @@ func (s Scheduler) ExecutePlan(in SchedulingInput, plan ExecutionPlan) (Executio
 	return trace, nil
 }
 
+const (
+	// degradeShedReason is the scheduling-point shed reason for nodes shed by the ladder hard limit.
+	degradeShedReason = "degrade_ladder_shed"
+	// resourceUnavailableShedReason is the shed reason for nodes whose resource hints do not fit
+	// the execution pool accelerator capacity.
+	resourceUnavailableShedReason = "resource_unavailable"
+)
+
+// planDegrade is the per-execution degrade ladder outcome shared by every node in the plan.
+type planDegrade struct {
+	decision flowcontrol.DegradeDecision
+	signals  []eventabi.ControlSignal
+}
+
+func evaluatePlanDegrade(in SchedulingInput, plan ExecutionPlan, baseEventID string) (planDegrade, error) {
+	if plan.DegradeLadder == nil {
+		return planDegrade{}, nil
+	}
+	decision, err := flowcontrol.EvaluateDegradeLadder(*plan.DegradeLadder, nonNegative(in.QueueDepth))
+	if err != nil {
+		return planDegrade{}, err
+	}
+	signals, err := flowcontrol.BuildDegradeSignals(flowcontrol.Input{
+		SessionID:            in.SessionID,
+		TurnID:               in.TurnID,
+		PipelineVersion:      defaultPipelineVersion(in.PipelineVersion),
+		EdgeID:               "execution-plan",
+		EventID:              baseEventID + "-degrade",
+		TargetLane:           eventabi.LaneData,
+		TransportSequence:    nonNegative(in.TransportSequence),
+		RuntimeSequence:      nonNegative(in.RuntimeSequence),
+		AuthorityEpoch:       nonNegative(in.AuthorityEpoch),
+		RuntimeTimestampMS:   nonNegative(in.RuntimeTimestampMS),
+		WallClockTimestampMS: nonNegative(in.WallClockTimestampMS),
+	}, decision)
+	if err != nil {
+		return planDegrade{}, err
+	}
+	return planDegrade{decision: decision, signals: signals}, nil
+}
+
+// skips reports the ladder step that removes node from this execution, if any.
+func (d planDegrade) skips(node NodeSpec) (controlplane.DegradeStep, bool) {
+	if node.Lane == eventabi.LaneTelemetry && d.decision.Engaged(controlplane.DegradeReduceTelemetryDetail) {
+		return controlplane.DegradeReduceTelemetryDetail, true
+	}
+	if node.AudioEnhancement && d.decision.Engaged(controlplane.DegradeBypassAudioEnhancement) {
+		return controlplane.DegradeBypassAudioEnhancement, true
+	}
+	if node.Partial && node.Provider != nil && node.Provider.Modality == contracts.ModalitySTT &&
+		d.decision.Engaged(controlplane.DegradeDropSTTPartials) {
+		return controlplane.DegradeDropSTTPartials, true
+	}
+	return "", false
+}
+
+// sheds reports whether the ladder hard limit sheds node; control-lane work is never shed.
+func (d planDegrade) sheds(node NodeSpec) bool {
+	return node.Lane == eventabi.LaneData && d.decision.Engaged(controlplane.DegradeShed)
+}
+
+// capProvider returns provider with the ladder LLM output cap applied, or provider unchanged.
+func (d planDegrade) capProvider(provider *ProviderInvocationInput) *ProviderInvocationInput {
+	if provider == nil || provider.Modality != contracts.ModalityLLM || d.decision.LLMMaxTokens < 1 ||
+		!d.decision.Engaged(controlplane.DegradeShortenLLMMaxTokens) {
+		return provider
+	}
+	if provider.MaxOutputTokens > 0 && provider.MaxOutputTokens <= d.decision.LLMMaxTokens {
+		return provider
+	}
+	capped := *provider
+	capped.MaxOutputTokens = d.decision.LLMMaxTokens
+	return &capped
+}
+
+// limitProviderOutput returns provider with the plan output limits applied, or provider
+// unchanged when no limit is tighter than its own cap.
+func limitProviderOutput(limits *controlplane.OutputLimits, provider *ProviderInvocationInput) *ProviderInvocationInput {
+	if limits == nil || provider == nil {
+		return provider
+	}
+	switch provider.Modality {
+	case contracts.ModalityLLM:
+		limit := limits.MaxLLMOutputTokens
+		if limit < 1 || (provider.MaxOutputTokens > 0 && provider.MaxOutputTokens <= limit) {
+			return provider
+		}
+		limited := *provider
+		limited.MaxOutputTokens = limit
+		return &limited
+	case contracts.ModalityTTS:
+		limit := limits.MaxTTSAudioMS
+		if limit < 1 || (provider.MaxAudioOutputMS > 0 && provider.MaxAudioOutputMS <= limit) {
+			return provider
+		}
+		limited := *provider
+		limited.MaxAudioOutputMS = limit
+		return &limited
+	}
+	return provider
+}
+
+// enhancementRun is one audio enhancement node's outcome in an execution: it ran with cfg, or
+// the degrade ladder step bypassedBy skipped it.
+type enhancementRun struct {
+	nodeID     string
+	cfg        controlplane.AudioEnhancement
+	bypassedBy controlplane.DegradeStep
+}
+
+// upstreamEnhancements returns the listed upstream nodes that are audio enhancement nodes.
+func upstreamEnhancements(from []string, enhancements map[string]enhancementRun) []enhancementRun {
+	var out []enhancementRun
+	for _, nodeID := range from {
+		if run, ok := enhancements[nodeID]; ok {
+			run.nodeID = nodeID
+			out = append(out, run)
+		}
+	}
+	return out
+}
+
+// withEnhancedAudio replaces an STT node's captured audio with the output of the last
+// upstream enhancement node that ran; bypassed enhancement leaves the raw audio.
+func withEnhancedAudio(provider *ProviderInvocationInput, enhancers []enhancementRun) (*ProviderInvocationInput, error) {
+	if provider == nil || provider.Modality != contracts.ModalitySTT || provider.Audio == nil {
+		return provider, nil
+	}
+	for idx := len(enhancers) - 1; idx >= 0; idx-- {
+		if enhancers[idx].bypassedBy != "" {
+			continue
+		}
+		pcm, err := prelude.EnhanceAudio(enhancers[idx].cfg, provider.Audio.PCM, provider.Audio.SampleRateHz)
+		if err != nil {
+			return nil, fmt.Errorf("audio enhancement node %s: %w", enhancers[idx].nodeID, err)
+		}
+		enhanced := *provider
+		audio := *provider.Audio
+		audio.PCM = pcm
+		enhanced.Audio = &audio
+		return &enhanced, nil
+	}
+	return provider, nil
+}
+
+// appendEnhancementEvidence records, for a successful STT decision, whether each upstream
+// enhancement node ran or was bypassed, with the transcript confidence observed downstream.
+func (s Scheduler) appendEnhancementEvidence(in SchedulingInput, baseEventID string, enhancers []enhancementRun, decision SchedulingDecision) error {
+	if s.enhanceAppender == nil || decision.Provider == nil || decision.Provider.OutcomeClass != contracts.OutcomeSuccess ||
+		decision.Provider.Modality != contracts.ModalitySTT {
+		return nil
+	}
+	for _, run := range enhancers {
+		if err := s.enhanceAppender.AppendAudioEnhancementEvidence(timeline.AudioEnhancementEvidence{
+			SessionID:            in.SessionID,
+			TurnID:               in.TurnID,
+			PipelineVersion:      defaultPipelineVersion(in.PipelineVersion),
+			EventID:              fmt.Sprintf("%s-%s", baseEventID, run.nodeID),
+			NodeID:               run.nodeID,
+			Engine:               run.cfg.Engine,
+			Quality:              run.cfg.Quality,
+			Bypassed:             run.bypassedBy != "",
+			BypassedBy:           run.bypassedBy,
+			STTConfidence:        decision.Provider.OutputConfidence,
+			RuntimeTimestampMS:   nonNegative(in.RuntimeTimestampMS),
+			WallClockTimestampMS: nonNegative(in.WallClockTimestampMS),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upstreamOutput returns the text output of the last listed upstream node that produced one.
+func upstreamOutput(from []string, outputs map[string]string) string {
+	for idx := len(from) - 1; idx >= 0; idx-- {
+		if text := outputs[from[idx]]; text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// withUpstreamOutput feeds upstream output text into an unset LLM prompt, after its prompt
+// context, or an unset TTS text, so a cascaded STT->LLM->TTS plan passes each stage's output
+// to the next.
+func withUpstreamOutput(provider *ProviderInvocationInput, text string) *ProviderInvocationInput {
+	if provider == nil || text == "" {
+		return provider
+	}
+	fed := *provider
+	switch provider.Modality {
+	case contracts.ModalityLLM:
+		if provider.Prompt != "" {
+			return provider
+		}
+		fed.Prompt = text
+		if len(provider.PromptContext) > 0 {
+			fed.Prompt = strings.Join(append(append([]string(nil), provider.PromptContext...), "User: "+text), "\n")
+		}
+	case contracts.ModalityTTS:
+		if provider.Text != "" {
+			return provider
+		}
+		fed.Text = text
+	default:
+		return provider
+	}
+	return &fed
+}
+
+// withSamplingSeed derives an LLM node's sampling seed from the turn determinism seed, so a
+// replay of the turn requests the same provider sampling. Explicit seeds are kept.
+func withSamplingSeed(provider *ProviderInvocationInput, determinismSeed int64, turnID string, nodeID string) *ProviderInvocationInput {
+	if provider == nil || provider.Modality != contracts.ModalityLLM || provider.SamplingSeed != nil {
+		return provider
+	}
+	seed := determinism.SamplingSeed(determinismSeed, turnID, nodeID)
+	seeded := *provider
+	seeded.SamplingSeed = &seed
+	return &seeded
+}
+
+// withTurnBudget sets a provider node's remaining turn budget to the plan budget less the time
+// already spent on earlier nodes, never below 1 ms so an exhausted budget stays bounded. An
+// explicit tighter budget is kept.
+func withTurnBudget(provider *ProviderInvocationInput, turnBudgetMS int64, elapsedMS int64) *ProviderInvocationInput {
+	if provider == nil || turnBudgetMS < 1 {
+		return provider
+	}
+	remaining := max(turnBudgetMS-nonNegative(elapsedMS), 1)
+	if provider.TurnBudgetRemainingMS > 0 && provider.TurnBudgetRemainingMS <= remaining {
+		return provider
+	}
+	budgeted := *provider
+	budgeted.TurnBudgetRemainingMS = remaining
+	return &budgeted
+}
+
+// nodeCacheKey keys a deterministic node on what it computes from: its type and lane, the
+// upstream output, and the provider invocation input after plan limits, degrade caps, and
+// upstream threading are applied. Per-turn identifiers, deadlines, and the turn-scoped plan
+// hash are left out so identical work hits across turns and sessions.
+func (s Scheduler) nodeCacheKey(in SchedulingInput, node NodeSpec, upstreamText string) (nodecache.Key, bool, error) {
+	if s.nodeCache == nil || !node.Deterministic {
+		return nodecache.Key{}, false, nil
+	}
+	input := nodeCacheInput{NodeType: node.NodeType, Lane: node.Lane, Upstream: upstreamText}
+	if provider := in.ProviderInvocation; provider != nil {
+		input.Modality = provider.Modality
+		input.PreferredProvider = provider.PreferredProvider
+		input.MaxOutputTokens = provider.MaxOutputTokens
+		input.MaxAudioOutputMS = provider.MaxAudioOutputMS
+		input.Endpointing = provider.Endpointing
+		input.Locale = provider.Locale
+		input.SessionSummary = provider.SessionSummary
+		input.SessionMetadata = provider.SessionMetadata
+		input.Audio = provider.Audio
+		input.Prompt = provider.Prompt
+		input.PromptContext = provider.PromptContext
+		input.Text = provider.Text
+	}
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return nodecache.Key{}, false, fmt.Errorf("node %s cache input: %w", node.NodeID, err)
+	}
+	return nodecache.Key{
+		PipelineVersion: defaultPipelineVersion(in.PipelineVersion),
+		NodeID:          node.NodeID,
+		InputHash:       nodecache.HashInput(string(encoded)),
+	}, true, nil
+}
+
+// nodeCacheInput is the part of a node's input that determines its output.
+type nodeCacheInput struct {
+	NodeType          string                       `json:"node_type"`
+	Lane              eventabi.Lane                `json:"lane"`
+	Upstream          string                       `json:"upstream,omitempty"`
+	Modality          contracts.Modality           `json:"modality,omitempty"`
+	PreferredProvider string                       `json:"preferred_provider,omitempty"`
+	MaxOutputTokens   int                          `json:"max_output_tokens,omitempty"`
+	MaxAudioOutputMS  int64                        `json:"max_audio_output_ms,omitempty"`
+	Endpointing       *controlplane.STTEndpointing `json:"endpointing,omitempty"`
+	Locale            *controlplane.ResolvedLocale `json:"locale,omitempty"`
+	SessionSummary    *contracts.SessionSummary    `json:"session_summary,omitempty"`
+	SessionMetadata   map[string]string            `json:"session_metadata,omitempty"`
+	Audio             *contracts.AudioInput        `json:"audio,omitempty"`
+	Prompt            string                       `json:"prompt,omitempty"`
+	PromptContext     []string                     `json:"prompt_context,omitempty"`
+	Text              string                       `json:"text,omitempty"`
+}
+
+func (s Scheduler) appendNodeCacheEvidence(in SchedulingInput, key nodecache.Key, hit bool, sourceEventID string) error {
+	if s.cacheAppender == nil {
+		return nil
+	}
+	return s.cacheAppender.AppendNodeCacheEvidence(timeline.NodeCacheEvidence{
+		SessionID:            in.SessionID,
+		TurnID:               in.TurnID,
+		PipelineVersion:      key.PipelineVersion,
+		EventID:              in.EventID,
+		NodeID:               key.NodeID,
+		PlanHash:             in.PlanHash,
+		InputHash:            key.InputHash,
+		CacheKey:             key.String(),
+		Hit:                  hit,
+		SourceEventID:        sourceEventID,
+		RuntimeTimestampMS:   nonNegative(in.RuntimeTimestampMS),
+		WallClockTimestampMS: nonNegative(in.WallClockTimestampMS),
+	})
+}
+
 func shouldShapeNodeFailure(decision SchedulingDecision) bool {
 	if decision.Provider == nil {
 		return false
@@ -171,7 +628,7 @@ func shouldShapeNodeFailure(decision SchedulingDecision) bool {
 	return true
 }
 
-func (s Scheduler) dispatchNode(nodeID string, in SchedulingInput) (SchedulingDecision, error) {
+func (s Scheduler) dispatchNode(node NodeSpec, in SchedulingInput) (SchedulingDecision, error) {
 	if s.executionPool == nil {
 		return s.NodeDispatch(in)
 	}
@@ -180,7 +637,10 @@ func (s Scheduler) dispatchNode(nodeID string, in SchedulingInput) (SchedulingDe
 		err      error
 	}, 1)
 	if err := s.executionPool.Submit(runtimeexecutionpool.Task{
-		ID: nodeID,
+		ID:          node.NodeID,
+		FairnessKey: in.SessionID,
+		Priority:    laneDispatchPriority(node.Lane),
+		Resources:   node.resources(),
 		Run: func() error {
 			decision, dispatchErr := s.NodeDispatch(in)
 			resultCh <- struct {
@@ -193,12 +653,38 @@ func (s Scheduler) dispatchNode(nodeID string, in SchedulingInput) (SchedulingDe
 			return dispatchErr
 		},
 	}); err != nil {
+		if errors.Is(err, runtimeexecutionpool.ErrResourceUnavailable) {
+			in.Shed = true
+			in.Reason = resourceUnavailableShedReason
+			return s.NodeDispatch(in)
+		}
 		return SchedulingDecision{}, err
 	}
 	result := <-resultCh
 	return result.decision, result.err
 }
 
+func (n NodeSpec) resources() runtimeexecutionpool.ResourceRequest {
+	req := runtimeexecutionpool.ResourceRequest{MemoryMB: n.MemoryEstimateMB}
+	if n.RequiresGPU {
+		req.GPUs = 1
+	}
+	return req
+}
+
+// laneDispatchPriority keeps ControlLane work ahead of DataLane and TelemetryLane work in the
+// execution pool; aging still bounds how long lower lanes wait.
+func laneDispatchPriority(lane eventabi.Lane) int {
+	switch lane {
+	case eventabi.LaneControl:
+		return 2
+	case eventabi.LaneData:
+		return 1
+	default:
+		return 0
+	}
+}
+
 func (p ExecutionPlan) validate() (map[string]NodeSpec, error) {
 	if len(p.Nodes) == 0 {
 		return nil, fmt.Errorf("execution plan requires at least one node")
@@ -225,6 +711,17 @@ func (p ExecutionPlan) validate() (map[string]NodeSpec, error) {
 				return nil, err
 			}
 		}
+		if node.Enhancement != nil {
+			if !node.AudioEnhancement || !node.Enhancement.Enabled {
+				return nil, fmt.Errorf("execution plan node %s enhancement requires an enabled audio enhancement node", node.NodeID)
+			}
+			if err := node.Enhancement.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		if node.MemoryEstimateMB < 0 {
+			return nil, fmt.Errorf("execution plan node %s memory_estimate_mb must be >=0", node.NodeID)
+		}
 		nodeByID[node.NodeID] = node
 	}
 
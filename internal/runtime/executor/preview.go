@@ -0,0 +1,256 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	runtimeexecutionpool "github.com/tiger/realtime-speech-pipeline/internal/runtime/executionpool"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/flowcontrol"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/lanes"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+// PlanPreviewAdminPath is the admin endpoint path the plan preview handler is mounted at.
+const PlanPreviewAdminPath = "/admin/plan-preview"
+
+// Node preview actions.
+const (
+	PreviewDispatch = "dispatch"
+	// PreviewLimit dispatches with a reduced provider budget (e.g. a capped LLM output).
+	PreviewLimit = "limit"
+	// PreviewSkip removes the node without dispatch under an engaged degrade step.
+	PreviewSkip = "skip"
+	// PreviewShed denies the node at its scheduling point, which stops the plan.
+	PreviewShed = "shed"
+	// PreviewReject means the execution pool would refuse the submit outright.
+	PreviewReject = "reject"
+	// PreviewBlocked marks nodes after a shed or rejected node that would never run.
+	PreviewBlocked = "blocked"
+)
+
+// NodePreview is the what-if outcome of one plan node.
+type NodePreview struct {
+	NodeID       string                   `json:"node_id"`
+	NodeType     string                   `json:"node_type"`
+	Lane         eventabi.Lane            `json:"lane"`
+	QueueKey     string                   `json:"queue_key"`
+	Action       string                   `json:"action"`
+	Reason       string                   `json:"reason,omitempty"`
+	DegradeStep  controlplane.DegradeStep `json:"degrade_step,omitempty"`
+	LLMMaxTokens int                      `json:"llm_max_tokens,omitempty"`
+}
+
+// PoolPreview is the execution pool state a preview was evaluated against.
+type PoolPreview struct {
+	QueueDepth       int64 `json:"queue_depth"`
+	InFlight         int64 `json:"in_flight"`
+	ReservedGPUs     int   `json:"reserved_gpus"`
+	ReservedMemoryMB int64 `json:"reserved_memory_mb"`
+}
+
+// PlanPreview summarizes what ExecutePlan would do for the same input and plan.
+type PlanPreview struct {
+	NodeOrder    []string                   `json:"node_order"`
+	Nodes        []NodePreview              `json:"nodes"`
+	QueueDepth   int64                      `json:"queue_depth"`
+	DegradeSteps []controlplane.DegradeStep `json:"degrade_steps,omitempty"`
+	Pool         *PoolPreview               `json:"pool,omitempty"`
+	// WouldComplete is false when a node would be shed or rejected.
+	WouldComplete bool `json:"would_complete"`
+}
+
+// PlanPreview evaluates plan against current pool state, the degrade ladder at in.QueueDepth,
+// and node policies without dispatching nodes, invoking providers, touching the node cache, or
+// reserving pool resources. Provider outcomes are unknowable ahead of time, so the preview only
+// reports scheduling-side shed/limit decisions.
+func (s Scheduler) PlanPreview(in SchedulingInput, plan ExecutionPlan) (PlanPreview, error) {
+	nodeByID, err := plan.validate()
+	if err != nil {
+		return PlanPreview{}, err
+	}
+	order, err := topologicalOrder(plan, nodeByID)
+	if err != nil {
+		return PlanPreview{}, err
+	}
+	router := s.router
+	if router == nil {
+		router = lanes.NewDefaultRouter()
+	}
+	// Only the ladder decision is needed; degrade signals are emitted by real executions.
+	degrade := planDegrade{}
+	if plan.DegradeLadder != nil {
+		degrade.decision, err = flowcontrol.EvaluateDegradeLadder(*plan.DegradeLadder, nonNegative(in.QueueDepth))
+		if err != nil {
+			return PlanPreview{}, err
+		}
+	}
+
+	preview := PlanPreview{
+		NodeOrder:     append([]string(nil), order...),
+		Nodes:         make([]NodePreview, 0, len(order)),
+		QueueDepth:    nonNegative(in.QueueDepth),
+		DegradeSteps:  degrade.decision.Steps,
+		WouldComplete: true,
+	}
+	if s.executionPool != nil {
+		stats := s.executionPool.Stats()
+		preview.Pool = &PoolPreview{
+			QueueDepth:       stats.QueueDepth,
+			InFlight:         stats.InFlight,
+			ReservedGPUs:     stats.Reserved.GPUs,
+			ReservedMemoryMB: stats.Reserved.MemoryMB,
+		}
+	}
+
+	stoppedBy := ""
+	for _, nodeID := range order {
+		node := nodeByID[nodeID]
+		target, err := router.Resolve(node.NodeType, node.Lane)
+		if err != nil {
+			return PlanPreview{}, err
+		}
+		out := NodePreview{NodeID: node.NodeID, NodeType: node.NodeType, Lane: node.Lane, QueueKey: target.QueueKey, Action: PreviewDispatch}
+		switch {
+		case stoppedBy != "":
+			out.Action, out.Reason = PreviewBlocked, "upstream_stopped:"+stoppedBy
+		default:
+			if step, skip := degrade.skips(node); skip {
+				out.Action, out.Reason, out.DegradeStep = PreviewSkip, "degrade_ladder_skip", step
+				break
+			}
+			s.previewDispatch(node, degrade, &out)
+			if out.Action == PreviewShed || out.Action == PreviewReject {
+				stoppedBy = node.NodeID
+				preview.WouldComplete = false
+			}
+		}
+		preview.Nodes = append(preview.Nodes, out)
+	}
+	return preview, nil
+}
+
+// previewDispatch mirrors ExecutePlan's shed precedence: plan-declared shed, then the ladder
+// hard limit, then pool admission.
+func (s Scheduler) previewDispatch(node NodeSpec, degrade planDegrade, out *NodePreview) {
+	switch {
+	case node.Shed:
+		out.Action, out.Reason = PreviewShed, fallbackReason(node.Reason, "scheduling_point_shed")
+		return
+	case degrade.sheds(node):
+		out.Action, out.Reason, out.DegradeStep = PreviewShed, degradeShedReason, controlplane.DegradeShed
+		return
+	}
+	if s.executionPool != nil {
+		if err := s.executionPool.CheckSubmit(node.NodeID, node.resources()); err != nil {
+			if errors.Is(err, runtimeexecutionpool.ErrResourceUnavailable) {
+				out.Action, out.Reason = PreviewShed, resourceUnavailableShedReason
+			} else {
+				out.Action, out.Reason = PreviewReject, err.Error()
+			}
+			return
+		}
+	}
+	if capped := degrade.capProvider(node.Provider); capped != node.Provider {
+		out.Action, out.Reason, out.DegradeStep = PreviewLimit, "degrade_ladder_llm_max_tokens", controlplane.DegradeShortenLLMMaxTokens
+		out.LLMMaxTokens = capped.MaxOutputTokens
+	}
+}
+
+func fallbackReason(reason string, fallback string) string {
+	if reason == "" {
+		return fallback
+	}
+	return reason
+}
+
+// PlanPreviewNode is the wire form of a NodeSpec for the admin endpoint.
+type PlanPreviewNode struct {
+	NodeID           string             `json:"node_id"`
+	NodeType         string             `json:"node_type"`
+	Lane             eventabi.Lane      `json:"lane"`
+	Modality         contracts.Modality `json:"modality,omitempty"`
+	MaxOutputTokens  int                `json:"max_output_tokens,omitempty"`
+	Shed             bool               `json:"shed,omitempty"`
+	Reason           string             `json:"reason,omitempty"`
+	Partial          bool               `json:"partial,omitempty"`
+	AudioEnhancement bool               `json:"audio_enhancement,omitempty"`
+	RequiresGPU      bool               `json:"requires_gpu,omitempty"`
+	MemoryEstimateMB int64              `json:"memory_estimate_mb,omitempty"`
+}
+
+// PlanPreviewRequest is the admin endpoint request body.
+type PlanPreviewRequest struct {
+	QueueDepth    int64                       `json:"queue_depth"`
+	Nodes         []PlanPreviewNode           `json:"nodes"`
+	Edges         []PlanPreviewEdge           `json:"edges,omitempty"`
+	DegradeLadder *controlplane.DegradeLadder `json:"degrade_ladder,omitempty"`
+}
+
+// PlanPreviewEdge is the wire form of an EdgeSpec.
+type PlanPreviewEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Plan converts the request into a SchedulingInput and ExecutionPlan.
+func (r PlanPreviewRequest) Plan() (SchedulingInput, ExecutionPlan) {
+	plan := ExecutionPlan{
+		Nodes:         make([]NodeSpec, 0, len(r.Nodes)),
+		Edges:         make([]EdgeSpec, 0, len(r.Edges)),
+		DegradeLadder: r.DegradeLadder,
+	}
+	for _, node := range r.Nodes {
+		spec := NodeSpec{
+			NodeID:           node.NodeID,
+			NodeType:         node.NodeType,
+			Lane:             node.Lane,
+			Shed:             node.Shed,
+			Reason:           node.Reason,
+			Partial:          node.Partial,
+			AudioEnhancement: node.AudioEnhancement,
+			RequiresGPU:      node.RequiresGPU,
+			MemoryEstimateMB: node.MemoryEstimateMB,
+		}
+		if node.Modality != "" {
+			spec.Provider = &ProviderInvocationInput{Modality: node.Modality, MaxOutputTokens: node.MaxOutputTokens}
+		}
+		plan.Nodes = append(plan.Nodes, spec)
+	}
+	for _, edge := range r.Edges {
+		plan.Edges = append(plan.Edges, EdgeSpec{From: edge.From, To: edge.To})
+	}
+	return SchedulingInput{SessionID: "plan-preview", QueueDepth: r.QueueDepth}, plan
+}
+
+// PlanPreviewHandler serves what-if plan previews for capacity planning.
+type PlanPreviewHandler struct {
+	Scheduler Scheduler
+}
+
+// ServeHTTP answers POSTed PlanPreviewRequest bodies with a PlanPreview.
+func (h PlanPreviewHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req PlanPreviewRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("decode plan preview request: %v", err), http.StatusBadRequest)
+		return
+	}
+	in, plan := req.Plan()
+	preview, err := h.Scheduler.PlanPreview(in, plan)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(preview)
+}
@@ -0,0 +1,182 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	runtimeexecutionpool "github.com/tiger/realtime-speech-pipeline/internal/runtime/executionpool"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/localadmission"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+func previewLadder() *controlplane.DegradeLadder {
+	return &controlplane.DegradeLadder{
+		Rungs: []controlplane.DegradeRung{
+			{Step: controlplane.DegradeReduceTelemetryDetail, SoftLimit: 4},
+			{Step: controlplane.DegradeShortenLLMMaxTokens, SoftLimit: 8, LLMMaxTokens: 32},
+		},
+		HardLimit: 16,
+	}
+}
+
+func previewPlan() ExecutionPlan {
+	return ExecutionPlan{
+		Nodes: []NodeSpec{
+			{NodeID: "admission", NodeType: "admission", Lane: eventabi.LaneControl},
+			{NodeID: "llm", NodeType: "provider", Lane: eventabi.LaneData, Provider: &ProviderInvocationInput{Modality: contracts.ModalityLLM}},
+			{NodeID: "metrics", NodeType: "metrics", Lane: eventabi.LaneTelemetry},
+		},
+		Edges:         []EdgeSpec{{From: "admission", To: "llm"}, {From: "llm", To: "metrics"}},
+		DegradeLadder: previewLadder(),
+	}
+}
+
+func TestPlanPreview(t *testing.T) {
+	t.Parallel()
+
+	gpuPool := runtimeexecutionpool.NewManagerWithConfig(runtimeexecutionpool.Config{
+		Capacity:  4,
+		Resources: &runtimeexecutionpool.ResourceCapacity{GPUs: 0, MemoryMB: 1024},
+	})
+	t.Cleanup(func() { _ = gpuPool.Drain(context.Background()) })
+
+	gpuPlan := previewPlan()
+	gpuPlan.Nodes[1].RequiresGPU = true
+	shedPlan := previewPlan()
+	shedPlan.Nodes[0].Shed = true
+	shedPlan.Nodes[0].Reason = "tenant_quota"
+
+	tests := []struct {
+		name         string
+		scheduler    Scheduler
+		plan         ExecutionPlan
+		queueDepth   int64
+		wantActions  []string
+		wantReasons  []string
+		wantComplete bool
+	}{
+		{
+			name:         "no pressure dispatches everything",
+			scheduler:    NewScheduler(localadmission.Evaluator{}),
+			plan:         previewPlan(),
+			wantActions:  []string{PreviewDispatch, PreviewDispatch, PreviewDispatch},
+			wantReasons:  []string{"", "", ""},
+			wantComplete: true,
+		},
+		{
+			name:         "soft limits skip telemetry and cap llm",
+			scheduler:    NewScheduler(localadmission.Evaluator{}),
+			plan:         previewPlan(),
+			queueDepth:   9,
+			wantActions:  []string{PreviewDispatch, PreviewLimit, PreviewSkip},
+			wantReasons:  []string{"", "degrade_ladder_llm_max_tokens", "degrade_ladder_skip"},
+			wantComplete: true,
+		},
+		{
+			name:         "hard limit sheds data lane and blocks downstream",
+			scheduler:    NewScheduler(localadmission.Evaluator{}),
+			plan:         previewPlan(),
+			queueDepth:   16,
+			wantActions:  []string{PreviewDispatch, PreviewShed, PreviewBlocked},
+			wantReasons:  []string{"", degradeShedReason, "upstream_stopped:llm"},
+			wantComplete: false,
+		},
+		{
+			name:         "declared shed stops the plan",
+			scheduler:    NewScheduler(localadmission.Evaluator{}),
+			plan:         shedPlan,
+			wantActions:  []string{PreviewShed, PreviewBlocked, PreviewBlocked},
+			wantReasons:  []string{"tenant_quota", "upstream_stopped:admission", "upstream_stopped:admission"},
+			wantComplete: false,
+		},
+		{
+			name:         "gpu node without pool capacity is shed",
+			scheduler:    NewSchedulerWithExecutionPool(localadmission.Evaluator{}, gpuPool),
+			plan:         gpuPlan,
+			wantActions:  []string{PreviewDispatch, PreviewShed, PreviewBlocked},
+			wantReasons:  []string{"", resourceUnavailableShedReason, "upstream_stopped:llm"},
+			wantComplete: false,
+		},
+	}
+	for _, tc := range tests {
+		preview, err := tc.scheduler.PlanPreview(SchedulingInput{SessionID: "sess-preview", QueueDepth: tc.queueDepth}, tc.plan)
+		if err != nil {
+			t.Fatalf("%s: unexpected preview error: %v", tc.name, err)
+		}
+		if preview.WouldComplete != tc.wantComplete || len(preview.Nodes) != len(tc.wantActions) {
+			t.Fatalf("%s: expected complete=%t with %d nodes, got %+v", tc.name, tc.wantComplete, len(tc.wantActions), preview)
+		}
+		for i, node := range preview.Nodes {
+			if node.Action != tc.wantActions[i] || node.Reason != tc.wantReasons[i] {
+				t.Fatalf("%s: node %s expected %s/%q, got %s/%q", tc.name, node.NodeID, tc.wantActions[i], tc.wantReasons[i], node.Action, node.Reason)
+			}
+		}
+	}
+
+	stats := gpuPool.Stats()
+	if stats.Submitted != 0 || stats.Rejected != 0 || stats.Reserved.GPUs != 0 {
+		t.Fatalf("expected preview to leave the pool untouched, got %+v", stats)
+	}
+}
+
+func TestPlanPreviewLimitReportsCap(t *testing.T) {
+	t.Parallel()
+
+	preview, err := NewScheduler(localadmission.Evaluator{}).PlanPreview(SchedulingInput{QueueDepth: 8}, previewPlan())
+	if err != nil {
+		t.Fatalf("unexpected preview error: %v", err)
+	}
+	llm := preview.Nodes[1]
+	if llm.LLMMaxTokens != 32 || llm.DegradeStep != controlplane.DegradeShortenLLMMaxTokens {
+		t.Fatalf("expected llm cap evidence, got %+v", llm)
+	}
+	if len(preview.DegradeSteps) != 2 || preview.QueueDepth != 8 {
+		t.Fatalf("expected engaged degrade steps and queue depth, got %+v", preview)
+	}
+}
+
+func TestPlanPreviewHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := PlanPreviewHandler{Scheduler: NewScheduler(localadmission.Evaluator{})}
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "valid request",
+			method:     http.MethodPost,
+			body:       `{"queue_depth":16,"nodes":[{"node_id":"stt","node_type":"provider","lane":"DataLane","modality":"stt"}],"degrade_ladder":{"rungs":[{"step":"reduce_telemetry_detail","soft_limit":4}],"hard_limit":16}}`,
+			wantStatus: http.StatusOK,
+		},
+		{name: "wrong method", method: http.MethodGet, wantStatus: http.StatusMethodNotAllowed},
+		{name: "unknown field", method: http.MethodPost, body: `{"nodes":[],"bogus":1}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid plan", method: http.MethodPost, body: `{"nodes":[]}`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tc := range tests {
+		req := httptest.NewRequest(tc.method, PlanPreviewAdminPath, strings.NewReader(tc.body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tc.wantStatus {
+			t.Fatalf("%s: expected status %d, got %d (%s)", tc.name, tc.wantStatus, rec.Code, rec.Body.String())
+		}
+		if tc.wantStatus != http.StatusOK {
+			continue
+		}
+		var preview PlanPreview
+		if err := json.Unmarshal(rec.Body.Bytes(), &preview); err != nil {
+			t.Fatalf("%s: decode response: %v", tc.name, err)
+		}
+		if preview.WouldComplete || len(preview.Nodes) != 1 || preview.Nodes[0].Action != PreviewShed {
+			t.Fatalf("%s: expected shed stt node, got %+v", tc.name, preview)
+		}
+	}
+}
@@ -2,19 +2,26 @@ package executor
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/cost"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/cancellation"
 	runtimeeventabi "github.com/tiger/realtime-speech-pipeline/internal/runtime/eventabi"
 	runtimeexecutionpool "github.com/tiger/realtime-speech-pipeline/internal/runtime/executionpool"
 	runtimeidentity "github.com/tiger/realtime-speech-pipeline/internal/runtime/identity"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/lanes"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/localadmission"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/nodecache"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/healthscore"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/invocation"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
 )
 
 // SchedulingInput captures runtime scheduling-point context.
@@ -30,7 +37,19 @@ type SchedulingInput struct {
 	WallClockTimestampMS int64
 	Shed                 bool
 	Reason               string
+	PlanHash             string
 	ProviderInvocation   *ProviderInvocationInput
+	// QueueDepth is the flow-control pressure used to evaluate the plan degrade ladder.
+	QueueDepth int64
+	// Budget, when set, is the turn's tenant budget usage before this scheduling point. A
+	// provider invocation is projected at its MaxOutputTokens cap and dispatched only while the
+	// projection fits the RK-25 tenant budget; ExecutePlan accrues usage node by node.
+	Budget *localadmission.BudgetUsage
+	// QuotaCounters and Thresholds are RK-25 explain evidence for shed decisions.
+	QuotaCounters []controlplane.QuotaCounter
+	Thresholds    []controlplane.ThresholdEvaluation
+	// Trace is the turn trace context; each scheduling point opens a node span under it.
+	Trace telemetry.TraceContext
 }
 
 // ProviderInvocationInput supplies optional RK-11 invocation context.
@@ -40,6 +59,36 @@ type ProviderInvocationInput struct {
 	AllowedAdaptiveActions []string
 	ProviderInvocationID   string
 	CancelRequested        bool
+	// MaxOutputTokens caps LLM output when >0; set by plan output limits or the degrade ladder.
+	MaxOutputTokens int
+	// MaxAudioOutputMS caps TTS audio duration when >0; set by plan output limits.
+	MaxAudioOutputMS int64
+	// CancelSignal is closed when the turn is cancelled while the invocation is in flight.
+	CancelSignal <-chan struct{}
+	// Endpointing is the plan STT endpointing config for STT invocations.
+	Endpointing *controlplane.STTEndpointing
+	// Locale is the plan session locale forwarded to provider adapters.
+	Locale *controlplane.ResolvedLocale
+	// SessionSummary is the bound session summary for LLM invocations.
+	SessionSummary *contracts.SessionSummary
+	// SessionMetadata is the session enrichment metadata for LLM prompt templates.
+	SessionMetadata map[string]string
+	// SamplingSeed is the deterministic LLM sampling seed; ExecutePlan derives it per LLM node
+	// from the turn determinism seed when unset.
+	SamplingSeed *int64
+	// TurnBudgetRemainingMS is the turn latency budget left for the invocation when >0;
+	// ExecutePlan sets it from the plan turn budget. Attempt deadlines are derived from it.
+	TurnBudgetRemainingMS int64
+	// Audio is the captured utterance for STT and S2S invocations.
+	Audio *contracts.AudioInput
+	// Prompt is the LLM input in place of the adapters' prompt templates. ExecutePlan derives
+	// it from the upstream node's output text, after PromptContext, when unset.
+	Prompt string
+	// PromptContext lists conversation lines, such as recent turns, that precede the upstream
+	// output text in a derived LLM prompt.
+	PromptContext []string
+	// Text is the TTS input; ExecutePlan sets it to the upstream node's output text when unset.
+	Text string
 }
 
 // SchedulingDecision reports deterministic allow/shed outcomes at scheduling points.
@@ -60,6 +109,47 @@ type ProviderDecision struct {
 	RetryDecision        string
 	Attempts             int
 	Signals              []eventabi.ControlSignal
+	// Usage is the final attempt token usage; truncated when a cancel aborted the stream.
+	Usage *contracts.TokenUsage
+	// EndpointingEnforcement is provider or vad_emulated when endpointing was applied, and
+	// EndpointKind the runtime endpointer's decision when it endpointed the captured audio.
+	EndpointingEnforcement string
+	EndpointKind           string
+	// SessionAffinity and ProviderSessionIDHash record the LLM provider-session decision.
+	SessionAffinity       string
+	ProviderSessionIDHash string
+	// LengthCapped marks output truncated at an output length cap; a length_capped signal
+	// is in Signals.
+	LengthCapped bool
+	// FirstChunkLatencyMS is the final attempt's streaming first-chunk latency when reported.
+	FirstChunkLatencyMS int64
+	// SamplingSeed and SamplingSeedStatus record the LLM sampling seed and how the final
+	// attempt's provider handled it; the status is empty when no seed was requested.
+	SamplingSeed       int64
+	SamplingSeedStatus string
+	// BudgetExhausted marks an invocation cut short by the turn latency budget.
+	BudgetExhausted bool
+	// CircuitState is the final attempt's circuit breaker state, or open when every candidate
+	// was skipped; CircuitSkippedProviders lists providers skipped with an open circuit.
+	CircuitState            string
+	CircuitSkippedProviders []string
+	// HealthRoutedFrom is the plan-preferred provider that health-scored routing put behind a
+	// healthier one; empty when the plan preference was kept.
+	HealthRoutedFrom string
+	// EstimatedCostUSD is the priced cost of every attempt; zero without a price table.
+	EstimatedCostUSD float64
+	// WarmStandby marks a final attempt issued over a held warm-standby connection.
+	WarmStandby bool
+	// OutputText is a successful invocation's text output: an STT transcript or an LLM reply.
+	OutputText string
+	// OutputConfidence is the provider's confidence in a successful STT transcript, when reported.
+	OutputConfidence float64
+	// OutputAudio is a successful invocation's synthesized audio.
+	OutputAudio *contracts.AudioOutput
+	// LatencyMS is the wall time of the whole invocation, every attempt included.
+	LatencyMS int64
+	// Cost is the priced usage of every attempt; nil without a price table.
+	Cost *timeline.UsageCostEvidence
 }
 
 // ToInvocationOutcomeEvidence maps provider decision output into OR-02 evidence shape.
@@ -76,6 +166,11 @@ func (d ProviderDecision) ToInvocationOutcomeEvidence() timeline.InvocationOutco
 	if attempts < 1 {
 		attempts = 1
 	}
+	// Only a single attempt's latency is known to be the final attempt's.
+	finalAttemptLatencyMS := int64(0)
+	if attempts == 1 {
+		finalAttemptLatencyMS = d.LatencyMS
+	}
 	return timeline.InvocationOutcomeEvidence{
 		ProviderInvocationID:     d.ProviderInvocationID,
 		Modality:                 modality,
@@ -84,8 +179,19 @@ func (d ProviderDecision) ToInvocationOutcomeEvidence() timeline.InvocationOutco
 		Retryable:                d.Retryable,
 		RetryDecision:            retryDecision,
 		AttemptCount:             attempts,
-		FinalAttemptLatencyMS:    0,
-		TotalInvocationLatencyMS: 0,
+		FinalAttemptLatencyMS:    finalAttemptLatencyMS,
+		TotalInvocationLatencyMS: d.LatencyMS,
+		SessionAffinity:          d.SessionAffinity,
+		ProviderSessionIDHash:    d.ProviderSessionIDHash,
+		LengthCapped:             d.LengthCapped,
+		FirstChunkLatencyMS:      d.FirstChunkLatencyMS,
+		SamplingSeed:             d.SamplingSeed,
+		SamplingSeedStatus:       d.SamplingSeedStatus,
+		CircuitState:             d.CircuitState,
+		WarmStandby:              d.WarmStandby,
+		EndpointingEnforcement:   d.EndpointingEnforcement,
+		EndpointKind:             d.EndpointKind,
+		Cost:                     d.Cost,
 	}
 }
 
@@ -99,6 +205,16 @@ type ProviderAttemptAppender interface {
 	AppendProviderInvocationAttempts([]timeline.ProviderAttemptEvidence) error
 }
 
+// NodeCacheEvidenceAppender records deterministic node cache lookups for replay.
+type NodeCacheEvidenceAppender interface {
+	AppendNodeCacheEvidence(timeline.NodeCacheEvidence) error
+}
+
+// AudioEnhancementEvidenceAppender records whether pre-STT enhancement ran for a turn.
+type AudioEnhancementEvidenceAppender interface {
+	AppendAudioEnhancementEvidence(timeline.AudioEnhancementEvidence) error
+}
+
 // ProviderInvocationSnapshotAppender appends optional non-terminal invocation snapshots.
 type ProviderInvocationSnapshotAppender interface {
 	AppendInvocationSnapshot(timeline.InvocationSnapshotEvidence) error
@@ -110,6 +226,8 @@ type eventIdentityService interface {
 
 type dispatchPool interface {
 	Submit(task runtimeexecutionpool.Task) error
+	CheckSubmit(id string, resources runtimeexecutionpool.ResourceRequest) error
+	Stats() runtimeexecutionpool.Stats
 }
 
 // Scheduler is a minimal RK-07 execution-path stub wired to RK-25 local admission.
@@ -121,6 +239,13 @@ type Scheduler struct {
 	router           lanes.Router
 	identity         eventIdentityService
 	executionPool    dispatchPool
+	nodeCache        *nodecache.Cache[SchedulingDecision]
+	cacheAppender    NodeCacheEvidenceAppender
+	enhanceAppender  AudioEnhancementEvidenceAppender
+	clock            clock.Clock
+	healthScorer     *healthscore.Scorer
+	priceTable       cost.PriceTable
+	cancelFence      *cancellation.Fence
 }
 
 func NewScheduler(admission localadmission.Evaluator) Scheduler {
@@ -152,6 +277,7 @@ func NewSchedulerWithProviderInvokerAndAttemptAppender(
 		providerInvoker:  providerInvoker,
 		attemptAppender:  attemptAppender,
 		snapshotAppender: toSnapshotAppender(attemptAppender),
+		enhanceAppender:  toEnhancementAppender(attemptAppender),
 		router:           lanes.NewDefaultRouter(),
 		identity:         runtimeidentity.NewService(),
 	}
@@ -187,11 +313,72 @@ func NewSchedulerWithDependencies(
 		providerInvoker:  providerInvoker,
 		attemptAppender:  attemptAppender,
 		snapshotAppender: toSnapshotAppender(attemptAppender),
+		enhanceAppender:  toEnhancementAppender(attemptAppender),
 		router:           router,
 		identity:         identitySvc,
 	}
 }
 
+// NewSchedulerWithNodeCache wires deterministic node memoization on top of RK-11 invocation.
+// Cache lookups are recorded as OR-02 evidence when attemptAppender also records node cache evidence.
+func NewSchedulerWithNodeCache(
+	admission localadmission.Evaluator,
+	providerInvoker ProviderInvoker,
+	attemptAppender ProviderAttemptAppender,
+	cache *nodecache.Cache[SchedulingDecision],
+) Scheduler {
+	scheduler := NewSchedulerWithProviderInvokerAndAttemptAppender(admission, providerInvoker, attemptAppender)
+	scheduler.nodeCache = cache
+	if cacheAppender, ok := attemptAppender.(NodeCacheEvidenceAppender); ok {
+		scheduler.cacheAppender = cacheAppender
+	}
+	return scheduler
+}
+
+// WithCancelFence returns a copy of the scheduler that gives each turn's provider invocations
+// fence's cancel signal for the turn, so a cancel accepted on the shared fence (an arbiter
+// barge-in, for one) aborts in-flight provider work. An explicit CancelSignal still wins.
+func (s Scheduler) WithCancelFence(fence *cancellation.Fence) Scheduler {
+	s.cancelFence = fence
+	return s
+}
+
+// WithClock returns a copy of the scheduler that measures plan execution time on clk, which
+// turn-budget deadlines are derived from; the default is the real clock.
+func (s Scheduler) WithClock(clk clock.Clock) Scheduler {
+	s.clock = clk
+	return s
+}
+
+// WithHealthScorer returns a copy of the scheduler that feeds every provider attempt into
+// scorer and, when a plan allows provider_switch, tries a clearly healthier provider before
+// the plan-preferred one.
+func (s Scheduler) WithHealthScorer(scorer *healthscore.Scorer) Scheduler {
+	s.healthScorer = scorer
+	return s
+}
+
+// WithBudgets returns a copy of the scheduler whose RK-25 evaluator enforces budgets against
+// SchedulingInput.Budget usage.
+func (s Scheduler) WithBudgets(budgets controlplane.BudgetPolicy) Scheduler {
+	s.admission.Budgets = budgets
+	return s
+}
+
+// WithPriceTable returns a copy of the scheduler that prices every provider attempt's reported
+// token and audio usage into its OR-02 attempt evidence.
+func (s Scheduler) WithPriceTable(table cost.PriceTable) Scheduler {
+	s.priceTable = table
+	return s
+}
+
+func (s Scheduler) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock.Now()
+}
+
 // EdgeEnqueue applies deterministic admission enforcement at edge enqueue.
 func (s Scheduler) EdgeEnqueue(in SchedulingInput) (SchedulingDecision, error) {
 	return s.evaluate(controlplane.ScopeEdgeEnqueue, in)
@@ -228,7 +415,11 @@ func (s Scheduler) evaluate(scope controlplane.OutcomeScope, in SchedulingInput)
 		Scope:                scope,
 		Shed:                 in.Shed,
 		Reason:               in.Reason,
+		Budget:               projectedBudget(in),
+		QuotaCounters:        in.QuotaCounters,
+		Thresholds:           in.Thresholds,
 	})
+	nodeTrace := in.Trace.ChildSpan(string(scope) + "|" + in.EventID)
 	correlation := telemetry.Correlation{
 		SessionID:          in.SessionID,
 		TurnID:             in.TurnID,
@@ -238,7 +429,7 @@ func (s Scheduler) evaluate(scope controlplane.OutcomeScope, in SchedulingInput)
 		Lane:               string(eventabi.LaneTelemetry),
 		EmittedBy:          "OR-01",
 		RuntimeTimestampMS: nonNegative(in.RuntimeTimestampMS),
-	}
+	}.WithTrace(nodeTrace)
 	telemetry.DefaultEmitter().EmitMetric(
 		telemetry.MetricShedRate,
 		boolToFloat(in.Shed),
@@ -255,13 +446,16 @@ func (s Scheduler) evaluate(scope controlplane.OutcomeScope, in SchedulingInput)
 			if s.providerInvoker == nil {
 				return SchedulingDecision{}, fmt.Errorf("provider invocation requested but provider invoker is not configured")
 			}
+			preferredProvider, healthRoutedFrom := s.routePreferredProvider(in.ProviderInvocation)
+			cancelRequested, cancelSignal := s.turnCancel(in)
+			invokedAt := s.now()
 			invocationResult, err := s.providerInvoker.Invoke(invocation.InvocationInput{
 				SessionID:              in.SessionID,
 				TurnID:                 in.TurnID,
 				PipelineVersion:        defaultPipelineVersion(in.PipelineVersion),
 				EventID:                in.EventID,
 				Modality:               in.ProviderInvocation.Modality,
-				PreferredProvider:      in.ProviderInvocation.PreferredProvider,
+				PreferredProvider:      preferredProvider,
 				AllowedAdaptiveActions: in.ProviderInvocation.AllowedAdaptiveActions,
 				ProviderInvocationID:   in.ProviderInvocation.ProviderInvocationID,
 				TransportSequence:      nonNegative(in.TransportSequence),
@@ -269,16 +463,32 @@ func (s Scheduler) evaluate(scope controlplane.OutcomeScope, in SchedulingInput)
 				AuthorityEpoch:         nonNegative(in.AuthorityEpoch),
 				RuntimeTimestampMS:     nonNegative(in.RuntimeTimestampMS),
 				WallClockTimestampMS:   nonNegative(in.WallClockTimestampMS),
-				CancelRequested:        in.ProviderInvocation.CancelRequested,
+				CancelRequested:        cancelRequested,
+				MaxOutputTokens:        in.ProviderInvocation.MaxOutputTokens,
+				MaxAudioOutputMS:       in.ProviderInvocation.MaxAudioOutputMS,
+				CancelSignal:           cancelSignal,
+				Endpointing:            invocationEndpointing(in.ProviderInvocation.Endpointing),
+				Locale:                 invocationLocale(in.ProviderInvocation.Locale),
+				SessionSummary:         in.ProviderInvocation.SessionSummary,
+				SessionMetadata:        in.ProviderInvocation.SessionMetadata,
+				SamplingSeed:           in.ProviderInvocation.SamplingSeed,
+				TurnBudgetRemainingMS:  in.ProviderInvocation.TurnBudgetRemainingMS,
+				Audio:                  in.ProviderInvocation.Audio,
+				Prompt:                 in.ProviderInvocation.Prompt,
+				Text:                   in.ProviderInvocation.Text,
+				Trace:                  nodeTrace,
 			})
 			if err != nil {
 				return SchedulingDecision{}, err
 			}
+			latencyMS := s.now().Sub(invokedAt).Milliseconds()
+			s.recordProviderHealth(in.ProviderInvocation.Modality, invocationResult)
 			normalizedSignals, err := runtimeeventabi.ValidateAndNormalizeControlSignals(invocationResult.Signals)
 			if err != nil {
 				return SchedulingDecision{}, err
 			}
 			attemptEvidence := buildAttemptEvidence(in, in.ProviderInvocation.Modality, invocationResult)
+			s.priceAttempts(attemptEvidence)
 			finalAttemptLatencyMS := int64(0)
 			if len(attemptEvidence) > 0 {
 				finalAttemptLatencyMS = attemptEvidence[len(attemptEvidence)-1].AttemptLatencyMS
@@ -314,14 +524,40 @@ func (s Scheduler) evaluate(scope controlplane.OutcomeScope, in SchedulingInput)
 			}
 
 			decision.Provider = &ProviderDecision{
-				ProviderInvocationID: invocationResult.ProviderInvocationID,
-				Modality:             in.ProviderInvocation.Modality,
-				SelectedProvider:     invocationResult.SelectedProvider,
-				OutcomeClass:         invocationResult.Outcome.Class,
-				Retryable:            invocationResult.Outcome.Retryable,
-				RetryDecision:        invocationResult.RetryDecision,
-				Attempts:             len(invocationResult.Attempts),
-				Signals:              append([]eventabi.ControlSignal(nil), normalizedSignals...),
+				ProviderInvocationID:   invocationResult.ProviderInvocationID,
+				Modality:               in.ProviderInvocation.Modality,
+				SelectedProvider:       invocationResult.SelectedProvider,
+				OutcomeClass:           invocationResult.Outcome.Class,
+				Retryable:              invocationResult.Outcome.Retryable,
+				RetryDecision:          invocationResult.RetryDecision,
+				Attempts:               len(invocationResult.Attempts),
+				Signals:                append([]eventabi.ControlSignal(nil), normalizedSignals...),
+				Usage:                  invocationResult.Outcome.Usage,
+				EndpointingEnforcement: invocationResult.EndpointingEnforcement,
+				EndpointKind:           invocationResult.EndpointKind,
+				SessionAffinity:        invocationResult.SessionAffinity,
+				ProviderSessionIDHash:  invocationResult.ProviderSessionIDHash,
+				LengthCapped:           invocationResult.LengthCapped,
+				FirstChunkLatencyMS:    invocationResult.Outcome.FirstChunkLatencyMS,
+				BudgetExhausted:        invocationResult.BudgetExhausted,
+				CircuitState:           invocationResult.CircuitState,
+				HealthRoutedFrom:       healthRoutedFrom,
+				EstimatedCostUSD:       attemptsCostUSD(attemptEvidence),
+				WarmStandby:            invocationResult.WarmStandby,
+				LatencyMS:              latencyMS,
+				Cost:                   s.attemptsCost(attemptEvidence),
+			}
+			if invocationResult.Outcome.Class == contracts.OutcomeSuccess {
+				decision.Provider.OutputText = invocationResult.Outcome.Text
+				decision.Provider.OutputConfidence = invocationResult.Outcome.Confidence
+				decision.Provider.OutputAudio = invocationResult.Outcome.Audio
+			}
+			if len(invocationResult.CircuitSkipped) > 0 {
+				decision.Provider.CircuitSkippedProviders = append([]string(nil), invocationResult.CircuitSkipped...)
+			}
+			if invocationResult.SamplingSeedStatus != "" {
+				decision.Provider.SamplingSeed = *invocationResult.SamplingSeed
+				decision.Provider.SamplingSeedStatus = invocationResult.SamplingSeedStatus
 			}
 			decision.Allowed = invocationResult.Outcome.Class == contracts.OutcomeSuccess
 		}
@@ -345,7 +581,7 @@ func (s Scheduler) evaluate(scope controlplane.OutcomeScope, in SchedulingInput)
 	if err := result.Outcome.Validate(); err != nil {
 		return SchedulingDecision{}, err
 	}
-	if result.Outcome.OutcomeKind != controlplane.OutcomeShed {
+	if result.Outcome.OutcomeKind != controlplane.OutcomeShed && result.Outcome.OutcomeKind != controlplane.OutcomeBudgetExceeded {
 		return SchedulingDecision{}, fmt.Errorf("unexpected scheduling-point outcome kind: %s", result.Outcome.OutcomeKind)
 	}
 
@@ -380,6 +616,50 @@ func (s Scheduler) evaluate(scope controlplane.OutcomeScope, in SchedulingInput)
 	return SchedulingDecision{Allowed: false, Outcome: result.Outcome, ControlSignal: controlSignal}, nil
 }
 
+func invocationEndpointing(cfg *controlplane.STTEndpointing) *contracts.Endpointing {
+	if cfg == nil {
+		return nil
+	}
+	return &contracts.Endpointing{
+		SilenceDurationMS: cfg.SilenceDurationMS,
+		MinUtteranceMS:    cfg.MinUtteranceMS,
+		MaxUtteranceMS:    cfg.MaxUtteranceMS,
+		NoSpeechTimeoutMS: cfg.NoSpeechTimeoutMS,
+	}
+}
+
+func invocationLocale(locale *controlplane.ResolvedLocale) *contracts.Locale {
+	if locale == nil {
+		return nil
+	}
+	return &contracts.Locale{
+		Tag:               locale.Locale,
+		LLMPromptLanguage: locale.LLMPromptLanguage,
+		TTSVoice:          locale.TTSVoice,
+		TTSVoiceLocale:    locale.TTSVoiceLocale,
+		STTLanguageHints:  append([]string(nil), locale.STTLanguageHints...),
+	}
+}
+
+// projectedBudget returns the budget usage a provider invocation would bring the turn to, or nil
+// when nothing would be consumed.
+func projectedBudget(in SchedulingInput) *localadmission.BudgetUsage {
+	if in.Budget == nil || in.ProviderInvocation == nil {
+		return nil
+	}
+	projected := *in.Budget
+	projected.TurnTokens += int64(max(in.ProviderInvocation.MaxOutputTokens, 0))
+	return &projected
+}
+
+func attemptsCostUSD(attempts []timeline.ProviderAttemptEvidence) float64 {
+	total := 0.0
+	for _, attempt := range attempts {
+		total += attempt.EstimatedCostUSD
+	}
+	return total
+}
+
 func buildShedControlSignal(in SchedulingInput, reason string) (*eventabi.ControlSignal, error) {
 	eventScope := eventabi.ScopeSession
 	scope := "session"
@@ -438,6 +718,87 @@ func boolToFloat(v bool) float64 {
 	return 0
 }
 
+// turnCancel resolves the invocation's cancel state: the explicit request fields, or the
+// turn's cancel fence signal when the scheduler shares one.
+func (s Scheduler) turnCancel(in SchedulingInput) (bool, <-chan struct{}) {
+	requested, signal := in.ProviderInvocation.CancelRequested, in.ProviderInvocation.CancelSignal
+	if s.cancelFence == nil || in.TurnID == "" {
+		return requested, signal
+	}
+	if signal == nil {
+		signal = s.cancelFence.Signal(in.SessionID, in.TurnID)
+	}
+	return requested || s.cancelFence.IsFenced(in.SessionID, in.TurnID), signal
+}
+
+// routePreferredProvider returns the provider to try first and, when health scoring moved it
+// off the plan preference, the plan-preferred provider.
+func (s Scheduler) routePreferredProvider(in *ProviderInvocationInput) (string, string) {
+	if s.healthScorer == nil || in.PreferredProvider == "" {
+		return in.PreferredProvider, ""
+	}
+	actions, err := contracts.NormalizeAdaptiveActions(in.AllowedAdaptiveActions)
+	if err != nil || !slices.Contains(actions, "provider_switch") {
+		// Invalid actions are rejected by the invocation controller.
+		return in.PreferredProvider, ""
+	}
+	routed := s.healthScorer.Preferred(in.Modality, in.PreferredProvider)
+	if routed == in.PreferredProvider {
+		return routed, ""
+	}
+	return routed, in.PreferredProvider
+}
+
+// recordProviderHealth feeds live attempts into the health scorer. Cache hits never reached
+// the provider; streaming attempts are scored on first-chunk latency.
+func (s Scheduler) recordProviderHealth(modality contracts.Modality, result invocation.InvocationResult) {
+	if s.healthScorer == nil {
+		return
+	}
+	for _, attempt := range result.Attempts {
+		if attempt.CacheHit {
+			continue
+		}
+		latencyMS := attempt.LatencyMS
+		if attempt.Outcome.FirstChunkLatencyMS > 0 {
+			latencyMS = attempt.Outcome.FirstChunkLatencyMS
+		}
+		s.healthScorer.Record(modality, attempt.ProviderID, attempt.Outcome.Class, latencyMS)
+	}
+}
+
+func (s Scheduler) priceAttempts(attempts []timeline.ProviderAttemptEvidence) {
+	if s.priceTable == nil {
+		return
+	}
+	for idx := range attempts {
+		priced := s.priceTable.Price(attempts[idx].ProviderID, timeline.UsageCostEvidence{
+			InputTokens:   attempts[idx].InputTokens,
+			OutputTokens:  attempts[idx].OutputTokens,
+			AudioInputMS:  attempts[idx].AudioInputMS,
+			AudioOutputMS: attempts[idx].AudioOutputMS,
+		})
+		attempts[idx].EstimatedCostUSD = priced.EstimatedCostUSD
+	}
+}
+
+// attemptsCost sums the priced usage of attempts; nil without a price table.
+func (s Scheduler) attemptsCost(attempts []timeline.ProviderAttemptEvidence) *timeline.UsageCostEvidence {
+	if s.priceTable == nil {
+		return nil
+	}
+	var total timeline.UsageCostEvidence
+	for _, attempt := range attempts {
+		total.Add(s.priceTable.Price(attempt.ProviderID, timeline.UsageCostEvidence{
+			InputTokens:   attempt.InputTokens,
+			OutputTokens:  attempt.OutputTokens,
+			AudioInputMS:  attempt.AudioInputMS,
+			AudioOutputMS: attempt.AudioOutputMS,
+		}))
+	}
+	return &total
+}
+
 func buildAttemptEvidence(
 	in SchedulingInput,
 	modality contracts.Modality,
@@ -458,24 +819,48 @@ func buildAttemptEvidence(
 			attemptLatencyMS = wallClockMS - previousWallClockMS
 		}
 		attempts = append(attempts, timeline.ProviderAttemptEvidence{
-			SessionID:            in.SessionID,
-			TurnID:               in.TurnID,
-			PipelineVersion:      defaultPipelineVersion(in.PipelineVersion),
-			EventID:              in.EventID,
-			ProviderInvocationID: result.ProviderInvocationID,
-			Modality:             string(modality),
-			ProviderID:           attempt.ProviderID,
-			Attempt:              attempt.Attempt,
-			OutcomeClass:         string(attempt.Outcome.Class),
-			Retryable:            attempt.Outcome.Retryable,
-			RetryDecision:        retryDecision,
-			AttemptLatencyMS:     attemptLatencyMS,
-			TransportSequence:    nonNegative(in.TransportSequence) + offset,
-			RuntimeSequence:      nonNegative(in.RuntimeSequence) + offset,
-			AuthorityEpoch:       nonNegative(in.AuthorityEpoch),
-			RuntimeTimestampMS:   nonNegative(in.RuntimeTimestampMS) + offset,
-			WallClockTimestampMS: wallClockMS,
+			SessionID:             in.SessionID,
+			TurnID:                in.TurnID,
+			PipelineVersion:       defaultPipelineVersion(in.PipelineVersion),
+			EventID:               in.EventID,
+			ProviderInvocationID:  result.ProviderInvocationID,
+			Modality:              string(modality),
+			ProviderID:            attempt.ProviderID,
+			Attempt:               attempt.Attempt,
+			OutcomeClass:          string(attempt.Outcome.Class),
+			Retryable:             attempt.Outcome.Retryable,
+			RetryDecision:         retryDecision,
+			AttemptLatencyMS:      attemptLatencyMS,
+			BackoffMS:             attempt.BackoffMS,
+			TransportSequence:     nonNegative(in.TransportSequence) + offset,
+			RuntimeSequence:       nonNegative(in.RuntimeSequence) + offset,
+			AuthorityEpoch:        nonNegative(in.AuthorityEpoch),
+			RuntimeTimestampMS:    nonNegative(in.RuntimeTimestampMS) + offset,
+			WallClockTimestampMS:  wallClockMS,
+			SessionAffinity:       attempt.SessionAffinity,
+			ProviderSessionIDHash: attempt.ProviderSessionIDHash,
+			LengthCapped:          result.LengthCapped && idx == len(result.Attempts)-1,
+			FirstChunkLatencyMS:   attempt.Outcome.FirstChunkLatencyMS,
+			SamplingSeedStatus:    attempt.SamplingSeedStatus,
+			TurnBudgetRemainingMS: attempt.TurnBudgetRemainingMS,
+			BudgetElapsedMS:       attempt.BudgetElapsedMS,
+			DeadlineMS:            attempt.DeadlineMS,
+			BudgetExhausted:       attempt.BudgetExhausted,
+			CircuitState:          attempt.CircuitState,
+			CircuitTripped:        attempt.CircuitTripped,
+			WarmStandby:           attempt.WarmStandby,
 		})
+		if attempt.SamplingSeedStatus != "" {
+			attempts[len(attempts)-1].SamplingSeed = *result.SamplingSeed
+		}
+		if usage := attempt.Outcome.Usage; usage != nil {
+			last := &attempts[len(attempts)-1]
+			last.InputTokens = int64(usage.InputTokens)
+			last.OutputTokens = int64(usage.OutputTokens)
+			last.AudioInputMS = usage.AudioInputMS
+			last.AudioOutputMS = usage.AudioOutputMS
+			last.UsageTruncated = usage.Truncated
+		}
 		previousWallClockMS = wallClockMS
 		hasPrevious = true
 	}
@@ -546,3 +931,14 @@ func buildInvocationSnapshotEvidence(
 		WallClockTimestampMS:     wallClockTimestampMS,
 	}, true, nil
 }
+
+func toEnhancementAppender(appender ProviderAttemptAppender) AudioEnhancementEvidenceAppender {
+	if appender == nil {
+		return nil
+	}
+	enhanceAppender, ok := appender.(AudioEnhancementEvidenceAppender)
+	if !ok {
+		return nil
+	}
+	return enhanceAppender
+}
@@ -2,18 +2,26 @@ package executor
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/cost"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/cancellation"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/determinism"
 	runtimeexecutionpool "github.com/tiger/realtime-speech-pipeline/internal/runtime/executionpool"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/localadmission"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/nodecache"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/healthscore"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/invocation"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
 )
 
 func TestSchedulerAllowsWhenNoShed(t *testing.T) {
@@ -204,6 +212,49 @@ func TestSchedulerProviderInvocationSuccess(t *testing.T) {
 	}
 }
 
+func TestSchedulerPropagatesTraceToProviderRequests(t *testing.T) {
+	t.Parallel()
+
+	var traceparent string
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{
+			ID:   "llm-a",
+			Mode: contracts.ModalityLLM,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				traceparent = req.Traceparent
+				return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	scheduler := NewSchedulerWithProviderInvoker(localadmission.Evaluator{}, invocation.NewController(catalog))
+
+	turnTrace := telemetry.NewRootTraceContext("sess-trace-1|turn-trace-1").ChildSpan("turn")
+	if _, err := scheduler.NodeDispatch(SchedulingInput{
+		SessionID:          "sess-trace-1",
+		TurnID:             "turn-trace-1",
+		EventID:            "evt-trace-1",
+		PipelineVersion:    "pipeline-v1",
+		RuntimeTimestampMS: 100,
+		Trace:              turnTrace,
+		ProviderInvocation: &ProviderInvocationInput{
+			Modality:          contracts.ModalityLLM,
+			PreferredProvider: "llm-a",
+		},
+	}); err != nil {
+		t.Fatalf("unexpected dispatch error: %v", err)
+	}
+	parsed, err := telemetry.ParseTraceparent(traceparent)
+	if err != nil {
+		t.Fatalf("expected provider request traceparent, got %q: %v", traceparent, err)
+	}
+	if parsed.TraceID != turnTrace.TraceID {
+		t.Fatalf("expected provider span in turn trace %s, got %+v", turnTrace.TraceID, parsed)
+	}
+}
+
 func TestSchedulerProviderInvocationSwitchAfterFailure(t *testing.T) {
 	t.Parallel()
 
@@ -603,6 +654,104 @@ func TestExecutePlanInvocationSnapshotEnabled(t *testing.T) {
 	}
 }
 
+func TestExecutePlanNodeCacheSkipsProviderOnHit(t *testing.T) {
+	t.Parallel()
+
+	invocations := 0
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{
+			ID:   "llm-safety",
+			Mode: contracts.ModalityLLM,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				invocations++
+				return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+
+	recorder := timeline.NewRecorder(timeline.StageAConfig{BaselineCapacity: 4, DetailCapacity: 4, AttemptCapacity: 8})
+	scheduler := NewSchedulerWithNodeCache(
+		localadmission.Evaluator{},
+		invocation.NewController(catalog),
+		&recorder,
+		nodecache.New[SchedulingDecision](8),
+	)
+	plan := ExecutionPlan{
+		Nodes: []NodeSpec{
+			{NodeID: "normalize", NodeType: "normalize", Lane: eventabi.LaneData, Deterministic: true},
+			{
+				NodeID:        "safety",
+				NodeType:      "safety_filter",
+				Lane:          eventabi.LaneData,
+				Deterministic: true,
+				Provider:      &ProviderInvocationInput{Modality: contracts.ModalityLLM, PreferredProvider: "llm-safety", Prompt: "hello world"},
+			},
+		},
+		Edges: []EdgeSpec{{From: "normalize", To: "safety"}},
+	}
+	input := func(eventID string, pipelineVersion string) SchedulingInput {
+		return SchedulingInput{
+			SessionID:       "sess-plan-cache-1",
+			TurnID:          "turn-plan-cache-1",
+			EventID:         eventID,
+			PipelineVersion: pipelineVersion,
+			// Plan hashes are turn-scoped and must not keep identical node input from hitting.
+			PlanHash:             "plan-hash-" + eventID,
+			RuntimeTimestampMS:   100,
+			WallClockTimestampMS: 100,
+		}
+	}
+
+	first, err := scheduler.ExecutePlan(input("evt-cache-1", "pipeline-v1"), plan)
+	if err != nil {
+		t.Fatalf("unexpected first execute plan error: %v", err)
+	}
+	second, err := scheduler.ExecutePlan(input("evt-cache-2", "pipeline-v1"), plan)
+	if err != nil {
+		t.Fatalf("unexpected second execute plan error: %v", err)
+	}
+	if first.Nodes[1].CacheHit || !second.Nodes[0].CacheHit || !second.Nodes[1].CacheHit || !second.Completed {
+		t.Fatalf("expected cache misses on first run and hits on second, got first=%+v second=%+v", first.Nodes, second.Nodes)
+	}
+	if invocations != 1 {
+		t.Fatalf("expected provider invoked once across cached runs, got %d", invocations)
+	}
+
+	evidence := recorder.NodeCacheEntries()
+	if len(evidence) != 4 {
+		t.Fatalf("expected 2 miss + 2 hit cache evidence entries, got %+v", evidence)
+	}
+	hit := evidence[3]
+	if !hit.Hit || hit.NodeID != "safety" || hit.SourceEventID != "evt-cache-1-safety" || hit.PlanHash != "plan-hash-evt-cache-2" {
+		t.Fatalf("expected safety cache hit evidence pointing to source event, got %+v", hit)
+	}
+	if miss := evidence[1]; miss.CacheKey != hit.CacheKey || miss.InputHash != hit.InputHash {
+		t.Fatalf("expected the hit recorded under the miss's node input key, got miss=%+v hit=%+v", miss, hit)
+	}
+
+	changed := plan
+	changed.Nodes = append([]NodeSpec(nil), plan.Nodes...)
+	changedProvider := *plan.Nodes[1].Provider
+	changedProvider.Prompt = "goodbye"
+	changed.Nodes[1].Provider = &changedProvider
+	if _, err := scheduler.ExecutePlan(input("evt-cache-changed", "pipeline-v1"), changed); err != nil {
+		t.Fatalf("unexpected changed-input execute plan error: %v", err)
+	}
+	if invocations != 2 {
+		t.Fatalf("expected changed node input to miss the cache, got invocations=%d", invocations)
+	}
+
+	if _, err := scheduler.ExecutePlan(input("evt-cache-3", "pipeline-v2"), plan); err != nil {
+		t.Fatalf("unexpected rollout execute plan error: %v", err)
+	}
+	if invocations != 3 {
+		t.Fatalf("expected pipeline version change to invalidate cache, got invocations=%d", invocations)
+	}
+}
+
 func TestExecutePlanCycleValidation(t *testing.T) {
 	t.Parallel()
 
@@ -822,6 +971,53 @@ func TestExecutePlanWithExecutionPool(t *testing.T) {
 	if stats.Submitted < 2 || stats.Completed < 2 {
 		t.Fatalf("expected pool submissions/completions, got %+v", stats)
 	}
+	if wait, ok := stats.WaitByFairnessKey["sess-plan-pool-1"]; !ok || wait.Dispatched != 2 {
+		t.Fatalf("expected session fairness key wait stats, got %+v", stats.WaitByFairnessKey)
+	}
+}
+
+func TestExecutePlanShedsNodesThatOversubscribeAccelerators(t *testing.T) {
+	t.Parallel()
+
+	pool := runtimeexecutionpool.NewManagerWithConfig(runtimeexecutionpool.Config{
+		Capacity:  4,
+		Resources: &runtimeexecutionpool.ResourceCapacity{GPUs: 0, MemoryMB: 4000},
+	})
+	scheduler := NewSchedulerWithExecutionPool(localadmission.Evaluator{}, pool)
+	trace, err := scheduler.ExecutePlan(
+		SchedulingInput{
+			SessionID:            "sess-plan-gpu-1",
+			TurnID:               "turn-plan-gpu-1",
+			EventID:              "evt-plan-gpu-1",
+			PipelineVersion:      "pipeline-v1",
+			RuntimeSequence:      1,
+			RuntimeTimestampMS:   100,
+			WallClockTimestampMS: 100,
+		},
+		ExecutionPlan{
+			Nodes: []NodeSpec{
+				{NodeID: "vad", NodeType: "admission", Lane: eventabi.LaneData, MemoryEstimateMB: 1000},
+				{NodeID: "local-llm", NodeType: "admission", Lane: eventabi.LaneData, RequiresGPU: true, MemoryEstimateMB: 2000},
+			},
+			Edges: []EdgeSpec{{From: "vad", To: "local-llm"}},
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected execute plan error: %v", err)
+	}
+	if trace.Completed || len(trace.Nodes) != 2 || !trace.Nodes[0].Decision.Allowed || trace.Nodes[1].Decision.Allowed {
+		t.Fatalf("expected gpu node shed after memory-only node, got %+v", trace)
+	}
+	signal := trace.Nodes[1].Decision.ControlSignal
+	if signal == nil || signal.Signal != "shed" || signal.Reason != "resource_unavailable" {
+		t.Fatalf("expected resource_unavailable shed signal, got %+v", signal)
+	}
+	if err := pool.Drain(context.Background()); err != nil {
+		t.Fatalf("unexpected pool drain error: %v", err)
+	}
+	if stats := pool.Stats(); stats.ResourceRejected != 1 || stats.Completed != 1 {
+		t.Fatalf("expected one resource reject and one completion, got %+v", stats)
+	}
 }
 
 func TestSchedulerGeneratesEventIDFromIdentity(t *testing.T) {
@@ -910,3 +1106,747 @@ func TestSchedulerEmitsTelemetryEvents(t *testing.T) {
 		t.Fatalf("expected scheduler telemetry events, got shed_rate=%v node_span=%v shed_log=%v", shedRateMetric, nodeSpan, shedLog)
 	}
 }
+
+func TestExecutePlanDegradeLadder(t *testing.T) {
+	t.Parallel()
+
+	ladder := &controlplane.DegradeLadder{
+		Rungs: []controlplane.DegradeRung{
+			{Step: controlplane.DegradeReduceTelemetryDetail, SoftLimit: 4},
+			{Step: controlplane.DegradeBypassAudioEnhancement, SoftLimit: 6},
+			{Step: controlplane.DegradeDropSTTPartials, SoftLimit: 8},
+			{Step: controlplane.DegradeShortenLLMMaxTokens, SoftLimit: 12, LLMMaxTokens: 32},
+		},
+		HardLimit: 16,
+	}
+	plan := func() ExecutionPlan {
+		return ExecutionPlan{
+			Nodes: []NodeSpec{
+				{NodeID: "enhance", NodeType: "audio_enhancement", Lane: eventabi.LaneData, AudioEnhancement: true},
+				{NodeID: "stt-partial", NodeType: "provider", Lane: eventabi.LaneData, Partial: true, Provider: &ProviderInvocationInput{Modality: contracts.ModalitySTT, PreferredProvider: "stt-a"}},
+				{NodeID: "telemetry", NodeType: "admission", Lane: eventabi.LaneTelemetry},
+				{NodeID: "llm", NodeType: "provider", Lane: eventabi.LaneData, Provider: &ProviderInvocationInput{Modality: contracts.ModalityLLM, PreferredProvider: "llm-a", MaxOutputTokens: 256}},
+			},
+			Edges: []EdgeSpec{
+				{From: "enhance", To: "stt-partial"},
+				{From: "stt-partial", To: "telemetry"},
+				{From: "telemetry", To: "llm"},
+			},
+			DegradeLadder: ladder,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		queueDepth    int64
+		wantSteps     int
+		wantDegraded  map[string]controlplane.DegradeStep
+		wantMaxTokens int
+		wantCompleted bool
+	}{
+		{name: "below soft limits", queueDepth: 1, wantMaxTokens: 256, wantCompleted: true},
+		{name: "telemetry reduced", queueDepth: 4, wantSteps: 1, wantDegraded: map[string]controlplane.DegradeStep{"telemetry": controlplane.DegradeReduceTelemetryDetail}, wantMaxTokens: 256, wantCompleted: true},
+		{name: "audio enhancement bypassed", queueDepth: 6, wantSteps: 2, wantDegraded: map[string]controlplane.DegradeStep{"enhance": controlplane.DegradeBypassAudioEnhancement, "telemetry": controlplane.DegradeReduceTelemetryDetail}, wantMaxTokens: 256, wantCompleted: true},
+		{name: "llm shortened", queueDepth: 12, wantSteps: 4, wantDegraded: map[string]controlplane.DegradeStep{"enhance": controlplane.DegradeBypassAudioEnhancement, "stt-partial": controlplane.DegradeDropSTTPartials, "telemetry": controlplane.DegradeReduceTelemetryDetail}, wantMaxTokens: 32, wantCompleted: true},
+		{name: "hard limit sheds", queueDepth: 16, wantSteps: 5, wantDegraded: map[string]controlplane.DegradeStep{"enhance": controlplane.DegradeBypassAudioEnhancement, "stt-partial": controlplane.DegradeDropSTTPartials, "telemetry": controlplane.DegradeReduceTelemetryDetail}},
+	}
+	for _, tc := range tests {
+		gotMaxTokens := 0
+		catalog, err := registry.NewCatalog([]contracts.Adapter{
+			contracts.StaticAdapter{ID: "stt-a", Mode: contracts.ModalitySTT},
+			contracts.StaticAdapter{
+				ID:   "llm-a",
+				Mode: contracts.ModalityLLM,
+				InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+					gotMaxTokens = req.MaxOutputTokens
+					return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected catalog error: %v", tc.name, err)
+		}
+		scheduler := NewSchedulerWithProviderInvoker(localadmission.Evaluator{}, invocation.NewController(catalog))
+		trace, err := scheduler.ExecutePlan(SchedulingInput{
+			SessionID:            "sess-degrade-1",
+			TurnID:               "turn-degrade-1",
+			EventID:              "evt-degrade-1",
+			PipelineVersion:      "pipeline-v1",
+			RuntimeSequence:      1,
+			RuntimeTimestampMS:   100,
+			WallClockTimestampMS: 100,
+			QueueDepth:           tc.queueDepth,
+		}, plan())
+		if err != nil {
+			t.Fatalf("%s: unexpected execute plan error: %v", tc.name, err)
+		}
+		if len(trace.DegradeSteps) != tc.wantSteps || trace.Completed != tc.wantCompleted {
+			t.Fatalf("%s: expected %d steps completed=%v, got %+v", tc.name, tc.wantSteps, tc.wantCompleted, trace)
+		}
+		degradeSignals := 0
+		for _, signal := range trace.ControlSignals {
+			if signal.Signal == "degrade" && signal.EmittedBy == "RK-14" && strings.HasPrefix(signal.Reason, "degrade_ladder:") {
+				degradeSignals++
+			}
+		}
+		if degradeSignals != tc.wantSteps {
+			t.Fatalf("%s: expected %d degrade evidence signals, got %+v", tc.name, tc.wantSteps, trace.ControlSignals)
+		}
+		for _, node := range trace.Nodes {
+			if node.DegradedBy != tc.wantDegraded[node.NodeID] {
+				t.Fatalf("%s: expected node %s degraded by %q, got %+v", tc.name, node.NodeID, tc.wantDegraded[node.NodeID], node)
+			}
+		}
+		if tc.wantCompleted && gotMaxTokens != tc.wantMaxTokens {
+			t.Fatalf("%s: expected llm max_output_tokens=%d, got %d", tc.name, tc.wantMaxTokens, gotMaxTokens)
+		}
+		if !tc.wantCompleted {
+			last := trace.Nodes[len(trace.Nodes)-1]
+			if last.NodeID != "llm" || last.Decision.Allowed || last.Decision.Outcome == nil || last.Decision.Outcome.Reason != "degrade_ladder_shed" {
+				t.Fatalf("%s: expected llm shed by degrade ladder, got %+v", tc.name, last)
+			}
+		}
+	}
+}
+
+func TestExecutePlanAudioEnhancement(t *testing.T) {
+	t.Parallel()
+
+	ladder := &controlplane.DegradeLadder{
+		Rungs:     []controlplane.DegradeRung{{Step: controlplane.DegradeBypassAudioEnhancement, SoftLimit: 6}},
+		HardLimit: 16,
+	}
+	raw := make([]int16, 1600)
+	for idx := range raw {
+		raw[idx] = 2000
+	}
+	tests := []struct {
+		name         string
+		queueDepth   int64
+		wantBypassed bool
+	}{
+		{name: "enhanced", queueDepth: 1},
+		{name: "bypassed", queueDepth: 6, wantBypassed: true},
+	}
+	for _, tc := range tests {
+		var heard []int16
+		catalog, err := registry.NewCatalog([]contracts.Adapter{
+			contracts.StaticAdapter{
+				ID:   "stt-a",
+				Mode: contracts.ModalitySTT,
+				InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+					heard = req.Audio.PCM
+					return contracts.Outcome{Class: contracts.OutcomeSuccess, Text: "hi", Confidence: 0.8}, nil
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected catalog error: %v", tc.name, err)
+		}
+		recorder := timeline.NewRecorder(timeline.StageAConfig{BaselineCapacity: 4, DetailCapacity: 4, AttemptCapacity: 8})
+		scheduler := NewSchedulerWithProviderInvokerAndAttemptAppender(localadmission.Evaluator{}, invocation.NewController(catalog), &recorder)
+		_, err = scheduler.ExecutePlan(SchedulingInput{
+			SessionID:            "sess-enhance-1",
+			TurnID:               "turn-enhance-1",
+			EventID:              "evt-enhance-1",
+			PipelineVersion:      "pipeline-v1",
+			RuntimeSequence:      1,
+			RuntimeTimestampMS:   100,
+			WallClockTimestampMS: 100,
+			QueueDepth:           tc.queueDepth,
+		}, ExecutionPlan{
+			Nodes: []NodeSpec{
+				{NodeID: "enhance", NodeType: "audio_enhancement", Lane: eventabi.LaneData, AudioEnhancement: true,
+					Enhancement: &controlplane.AudioEnhancement{Enabled: true, Engine: "rnnoise", Quality: "balanced"}},
+				{NodeID: "stt", NodeType: "provider", Lane: eventabi.LaneData, Provider: &ProviderInvocationInput{
+					Modality: contracts.ModalitySTT, PreferredProvider: "stt-a", Audio: &contracts.AudioInput{PCM: raw, SampleRateHz: 16000}}},
+			},
+			Edges:         []EdgeSpec{{From: "enhance", To: "stt"}},
+			DegradeLadder: ladder,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected execute plan error: %v", tc.name, err)
+		}
+		if len(heard) != len(raw) || (heard[len(heard)-1] == raw[len(raw)-1]) == !tc.wantBypassed {
+			t.Fatalf("%s: expected stt audio enhanced=%t, got last sample %d", tc.name, !tc.wantBypassed, heard[len(heard)-1])
+		}
+		if raw[0] != 2000 {
+			t.Fatalf("%s: expected the plan audio left unmodified", tc.name)
+		}
+		entries := recorder.AudioEnhancementEntries()
+		if len(entries) != 1 || entries[0].NodeID != "enhance" || entries[0].EventID != "evt-enhance-1-enhance" ||
+			entries[0].Bypassed != tc.wantBypassed || entries[0].STTConfidence != 0.8 || entries[0].Quality != "balanced" {
+			t.Fatalf("%s: unexpected audio enhancement evidence: %+v", tc.name, entries)
+		}
+	}
+
+	_, err := NewScheduler(localadmission.Evaluator{}).ExecutePlan(SchedulingInput{SessionID: "sess-enhance-2", TurnID: "turn-enhance-2"}, ExecutionPlan{
+		Nodes: []NodeSpec{{NodeID: "enhance", NodeType: "audio_enhancement", Lane: eventabi.LaneData,
+			Enhancement: &controlplane.AudioEnhancement{Enabled: true, Engine: "rnnoise", Quality: "low"}}},
+	})
+	if err == nil {
+		t.Fatalf("expected an enhancement setting off an audio enhancement node to be rejected")
+	}
+}
+
+func TestExecutePlanOutputLimits(t *testing.T) {
+	t.Parallel()
+
+	limits := &controlplane.OutputLimits{MaxLLMOutputTokens: 128, MaxTTSAudioMS: 20_000}
+	ladder := &controlplane.DegradeLadder{
+		Rungs:     []controlplane.DegradeRung{{Step: controlplane.DegradeShortenLLMMaxTokens, SoftLimit: 8, LLMMaxTokens: 32}},
+		HardLimit: 16,
+	}
+	tests := []struct {
+		name          string
+		queueDepth    int64
+		nodeMaxTokens int
+		wantMaxTokens int
+	}{
+		{name: "plan limit applies", wantMaxTokens: 128},
+		{name: "tighter node cap wins", nodeMaxTokens: 64, wantMaxTokens: 64},
+		{name: "tighter ladder cap wins", queueDepth: 8, wantMaxTokens: 32},
+	}
+	for _, tc := range tests {
+		var gotMaxTokens int
+		var gotAudioMS int64
+		catalog, err := registry.NewCatalog([]contracts.Adapter{
+			contracts.StaticAdapter{
+				ID:   "llm-a",
+				Mode: contracts.ModalityLLM,
+				InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+					gotMaxTokens = req.MaxOutputTokens
+					return contracts.Outcome{Class: contracts.OutcomeSuccess, Usage: &contracts.TokenUsage{OutputTokens: req.MaxOutputTokens}}, nil
+				},
+			},
+			contracts.StaticAdapter{
+				ID:   "tts-a",
+				Mode: contracts.ModalityTTS,
+				InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+					gotAudioMS = req.MaxAudioOutputMS
+					return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected catalog error: %v", tc.name, err)
+		}
+		scheduler := NewSchedulerWithProviderInvoker(localadmission.Evaluator{}, invocation.NewController(catalog))
+		trace, err := scheduler.ExecutePlan(SchedulingInput{
+			SessionID:            "sess-limits-1",
+			TurnID:               "turn-limits-1",
+			EventID:              "evt-limits-1",
+			PipelineVersion:      "pipeline-v1",
+			RuntimeSequence:      1,
+			RuntimeTimestampMS:   100,
+			WallClockTimestampMS: 100,
+			QueueDepth:           tc.queueDepth,
+		}, ExecutionPlan{
+			Nodes: []NodeSpec{
+				{NodeID: "llm", NodeType: "provider", Lane: eventabi.LaneData, Provider: &ProviderInvocationInput{Modality: contracts.ModalityLLM, PreferredProvider: "llm-a", MaxOutputTokens: tc.nodeMaxTokens}},
+				{NodeID: "tts", NodeType: "provider", Lane: eventabi.LaneData, Provider: &ProviderInvocationInput{Modality: contracts.ModalityTTS, PreferredProvider: "tts-a"}},
+			},
+			Edges:         []EdgeSpec{{From: "llm", To: "tts"}},
+			DegradeLadder: ladder,
+			OutputLimits:  limits,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected execute plan error: %v", tc.name, err)
+		}
+		if !trace.Completed || gotMaxTokens != tc.wantMaxTokens || gotAudioMS != limits.MaxTTSAudioMS {
+			t.Fatalf("%s: expected max_output_tokens=%d max_audio_output_ms=%d, got %d/%d (%+v)", tc.name, tc.wantMaxTokens, limits.MaxTTSAudioMS, gotMaxTokens, gotAudioMS, trace)
+		}
+		lengthCapped := 0
+		for _, signal := range trace.ControlSignals {
+			if signal.Signal == "length_capped" {
+				lengthCapped++
+			}
+		}
+		llm := trace.Nodes[0].Decision.Provider
+		if lengthCapped != 1 || llm == nil || !llm.LengthCapped || !llm.ToInvocationOutcomeEvidence().LengthCapped {
+			t.Fatalf("%s: expected one length_capped signal with capped llm evidence, got %+v", tc.name, trace)
+		}
+	}
+}
+
+func TestExecutePlanCancelledStreamRecordsPartialUsage(t *testing.T) {
+	t.Parallel()
+
+	cancelSignal := make(chan struct{})
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{
+			ID:   "llm-a",
+			Mode: contracts.ModalityLLM,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				close(cancelSignal)
+				if !req.CancelSignalled() {
+					return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+				}
+				return contracts.Outcome{
+					Class:  contracts.OutcomeCancelled,
+					Reason: "provider_stream_cancelled",
+					Usage:  &contracts.TokenUsage{InputTokens: 20, OutputTokens: 6, Truncated: true},
+				}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+
+	recorder := timeline.NewRecorder(timeline.StageAConfig{BaselineCapacity: 4, DetailCapacity: 4, AttemptCapacity: 8})
+	scheduler := NewSchedulerWithProviderInvokerAndAttemptAppender(localadmission.Evaluator{}, invocation.NewController(catalog), &recorder).
+		WithPriceTable(cost.PriceTable{"llm-a": {InputTokensPerMillionUSD: 1_000_000, OutputTokensPerMillionUSD: 2_000_000}})
+	trace, err := scheduler.ExecutePlan(
+		SchedulingInput{
+			SessionID:            "sess-plan-cancel-1",
+			TurnID:               "turn-plan-cancel-1",
+			EventID:              "evt-plan-cancel-1",
+			PipelineVersion:      "pipeline-v1",
+			RuntimeTimestampMS:   100,
+			WallClockTimestampMS: 100,
+		},
+		ExecutionPlan{
+			Nodes: []NodeSpec{{
+				NodeID:   "llm-node",
+				NodeType: "provider",
+				Lane:     eventabi.LaneData,
+				Provider: &ProviderInvocationInput{
+					Modality:               contracts.ModalityLLM,
+					PreferredProvider:      "llm-a",
+					AllowedAdaptiveActions: []string{"retry"},
+					CancelSignal:           cancelSignal,
+				},
+			}},
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected execute plan error: %v", err)
+	}
+	provider := trace.Nodes[0].Decision.Provider
+	if provider == nil || provider.OutcomeClass != contracts.OutcomeCancelled || provider.Attempts != 1 {
+		t.Fatalf("expected single cancelled provider attempt, got %+v", provider)
+	}
+	if provider.Usage == nil || provider.Usage.OutputTokens != 6 || !provider.Usage.Truncated {
+		t.Fatalf("expected truncated partial usage on provider decision, got %+v", provider.Usage)
+	}
+	if trace.Nodes[0].Failure != nil {
+		t.Fatalf("expected cancel to bypass node failure shaping, got %+v", trace.Nodes[0].Failure)
+	}
+	attempts := recorder.ProviderAttemptEntries()
+	if len(attempts) != 1 || attempts[0].OutcomeClass != "cancelled" || attempts[0].InputTokens != 20 || attempts[0].OutputTokens != 6 || !attempts[0].UsageTruncated {
+		t.Fatalf("expected persisted cancelled attempt with partial usage, got %+v", attempts)
+	}
+	if attempts[0].EstimatedCostUSD != 32 {
+		t.Fatalf("expected partial usage priced into the attempt, got %+v", attempts[0])
+	}
+}
+
+func TestExecutePlanCancelFenceAbortsInFlightInvocation(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{}, 1)
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{
+			ID:   "llm-a",
+			Mode: contracts.ModalityLLM,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				started <- struct{}{}
+				<-req.CancelSignal
+				return contracts.Outcome{Class: contracts.OutcomeCancelled, Reason: "provider_stream_cancelled"}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	fence := cancellation.NewFence()
+	scheduler := NewSchedulerWithProviderInvoker(localadmission.Evaluator{}, invocation.NewController(catalog)).WithCancelFence(fence)
+	plan := ExecutionPlan{Nodes: []NodeSpec{{
+		NodeID:   "llm-node",
+		NodeType: "provider",
+		Lane:     eventabi.LaneData,
+		Provider: &ProviderInvocationInput{Modality: contracts.ModalityLLM, PreferredProvider: "llm-a"},
+	}}}
+	in := SchedulingInput{SessionID: "sess-plan-fence-1", TurnID: "turn-plan-fence-1", EventID: "evt-plan-fence-1", PipelineVersion: "pipeline-v1"}
+
+	go func() {
+		<-started
+		_ = fence.Accept(in.SessionID, in.TurnID)
+	}()
+	trace, err := scheduler.ExecutePlan(in, plan)
+	if err != nil {
+		t.Fatalf("unexpected execute plan error: %v", err)
+	}
+	if provider := trace.Nodes[0].Decision.Provider; provider == nil || provider.OutcomeClass != contracts.OutcomeCancelled {
+		t.Fatalf("expected the fenced cancel to abort the in-flight invocation, got %+v", provider)
+	}
+
+	in.EventID = "evt-plan-fence-2"
+	trace, err = scheduler.ExecutePlan(in, plan)
+	if err != nil {
+		t.Fatalf("unexpected execute plan error: %v", err)
+	}
+	if provider := trace.Nodes[0].Decision.Provider; provider == nil || provider.OutcomeClass != contracts.OutcomeCancelled || len(started) != 0 {
+		t.Fatalf("expected a fenced turn to be cancelled before invoking, got %+v", provider)
+	}
+}
+
+type conversationLLMAdapter struct {
+	contracts.StaticAdapter
+}
+
+func (conversationLLMAdapter) SupportsConversationSessions() bool { return true }
+
+func TestExecutePlanRecordsLLMSessionAffinityEvidence(t *testing.T) {
+	t.Parallel()
+
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		conversationLLMAdapter{contracts.StaticAdapter{
+			ID:   "llm-conv",
+			Mode: contracts.ModalityLLM,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				return contracts.Outcome{Class: contracts.OutcomeSuccess, ProviderSessionID: "conv-1"}, nil
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+
+	recorder := timeline.NewRecorder(timeline.StageAConfig{BaselineCapacity: 4, DetailCapacity: 4, AttemptCapacity: 8})
+	scheduler := NewSchedulerWithProviderInvokerAndAttemptAppender(localadmission.Evaluator{}, invocation.NewController(catalog), &recorder)
+	wantHash := invocation.ProviderSessionIDHash("conv-1")
+	for i, wantAffinity := range []string{contracts.SessionAffinityEstablished, contracts.SessionAffinityReused} {
+		turnID := fmt.Sprintf("turn-affinity-%d", i+1)
+		decision, err := scheduler.NodeDispatch(SchedulingInput{
+			SessionID:            "sess-affinity-1",
+			TurnID:               turnID,
+			EventID:              "evt-" + turnID,
+			PipelineVersion:      "pipeline-v1",
+			RuntimeTimestampMS:   100,
+			WallClockTimestampMS: 100,
+			ProviderInvocation:   &ProviderInvocationInput{Modality: contracts.ModalityLLM, PreferredProvider: "llm-conv"},
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected dispatch error: %v", turnID, err)
+		}
+		if decision.Provider == nil || decision.Provider.SessionAffinity != wantAffinity || decision.Provider.ProviderSessionIDHash != wantHash {
+			t.Fatalf("%s: expected %s affinity with hash, got %+v", turnID, wantAffinity, decision.Provider)
+		}
+		evidence := decision.Provider.ToInvocationOutcomeEvidence()
+		if err := evidence.Validate(); err != nil || evidence.SessionAffinity != wantAffinity {
+			t.Fatalf("%s: expected valid outcome evidence with affinity, got %+v (%v)", turnID, evidence, err)
+		}
+	}
+	attempts := recorder.ProviderAttemptEntries()
+	if len(attempts) != 2 || attempts[1].SessionAffinity != contracts.SessionAffinityReused || attempts[1].ProviderSessionIDHash != wantHash {
+		t.Fatalf("expected attempt evidence to carry affinity, got %+v", attempts)
+	}
+	if strings.Contains(fmt.Sprintf("%+v", attempts), "conv-1") {
+		t.Fatalf("expected raw provider session id to stay out of evidence, got %+v", attempts)
+	}
+}
+
+type seededLLMAdapter struct {
+	contracts.StaticAdapter
+}
+
+func (seededLLMAdapter) SupportsSamplingSeed() bool { return true }
+
+func TestExecutePlanBoundsProviderDeadlinesByTurnBudget(t *testing.T) {
+	t.Parallel()
+
+	virtual := clock.NewVirtual(time.UnixMilli(10_000))
+	var llmDeadlineMS, ttsDeadlineMS int64
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{
+			ID:   "llm-a",
+			Mode: contracts.ModalityLLM,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				llmDeadlineMS = req.DeadlineMS
+				virtual.Advance(50 * time.Millisecond)
+				return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+			},
+		},
+		contracts.StaticAdapter{
+			ID:   "tts-a",
+			Mode: contracts.ModalityTTS,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				ttsDeadlineMS = req.DeadlineMS
+				return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	controller := invocation.NewControllerWithConfig(catalog, invocation.Config{Now: virtual.Now})
+	scheduler := NewSchedulerWithProviderInvoker(localadmission.Evaluator{}, controller).WithClock(virtual)
+	trace, err := scheduler.ExecutePlan(SchedulingInput{
+		SessionID:       "sess-budget-1",
+		TurnID:          "turn-budget-1",
+		EventID:         "evt-budget-1",
+		PipelineVersion: "pipeline-v1",
+	}, ExecutionPlan{
+		Nodes: []NodeSpec{
+			{NodeID: "llm", NodeType: "provider", Lane: eventabi.LaneData, Provider: &ProviderInvocationInput{Modality: contracts.ModalityLLM, PreferredProvider: "llm-a"}},
+			{NodeID: "tts", NodeType: "provider", Lane: eventabi.LaneData, Provider: &ProviderInvocationInput{Modality: contracts.ModalityTTS, PreferredProvider: "tts-a"}},
+		},
+		Edges:        []EdgeSpec{{From: "llm", To: "tts"}},
+		TurnBudgetMS: 2000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected execute plan error: %v", err)
+	}
+	if !trace.Completed || llmDeadlineMS != 2000 {
+		t.Fatalf("expected the first node to inherit the whole turn budget, got %d (%+v)", llmDeadlineMS, trace)
+	}
+	if ttsDeadlineMS != 1950 {
+		t.Fatalf("expected the second node deadline to exclude the 50ms spent on the first, got %d", ttsDeadlineMS)
+	}
+}
+
+func TestWithTurnBudget(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		explicitMS    int64
+		turnBudgetMS  int64
+		elapsedMS     int64
+		wantRemaining int64
+	}{
+		{name: "unbudgeted plan", turnBudgetMS: 0, elapsedMS: 10, wantRemaining: 0},
+		{name: "remaining budget", turnBudgetMS: 1000, elapsedMS: 300, wantRemaining: 700},
+		{name: "exhausted budget stays bounded", turnBudgetMS: 1000, elapsedMS: 1500, wantRemaining: 1},
+		{name: "tighter explicit budget wins", explicitMS: 200, turnBudgetMS: 1000, elapsedMS: 300, wantRemaining: 200},
+		{name: "looser explicit budget is bounded", explicitMS: 900, turnBudgetMS: 1000, elapsedMS: 300, wantRemaining: 700},
+	}
+	for _, tc := range tests {
+		provider := &ProviderInvocationInput{Modality: contracts.ModalityTTS, TurnBudgetRemainingMS: tc.explicitMS}
+		got := withTurnBudget(provider, tc.turnBudgetMS, tc.elapsedMS)
+		if got.TurnBudgetRemainingMS != tc.wantRemaining {
+			t.Fatalf("%s: expected remaining=%d, got %d", tc.name, tc.wantRemaining, got.TurnBudgetRemainingMS)
+		}
+		if provider.TurnBudgetRemainingMS != tc.explicitMS {
+			t.Fatalf("%s: expected the node provider spec to stay unmodified", tc.name)
+		}
+	}
+}
+
+func TestExecutePlanSeedsLLMSamplingFromTurnDeterminismSeed(t *testing.T) {
+	t.Parallel()
+
+	sent := map[string]int64{}
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{ID: "stt-a", Mode: contracts.ModalitySTT},
+		seededLLMAdapter{contracts.StaticAdapter{
+			ID:   "llm-seeded",
+			Mode: contracts.ModalityLLM,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				if req.SamplingSeed != nil {
+					sent[req.EventID] = *req.SamplingSeed
+				}
+				return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+			},
+		}},
+		contracts.StaticAdapter{ID: "llm-unseeded", Mode: contracts.ModalityLLM},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	recorder := timeline.NewRecorder(timeline.StageAConfig{BaselineCapacity: 4, DetailCapacity: 4, AttemptCapacity: 8})
+	scheduler := NewSchedulerWithProviderInvokerAndAttemptAppender(localadmission.Evaluator{}, invocation.NewController(catalog), &recorder)
+	trace, err := scheduler.ExecutePlan(SchedulingInput{
+		SessionID:            "sess-seed-1",
+		TurnID:               "turn-seed-1",
+		EventID:              "evt-seed",
+		PipelineVersion:      "pipeline-v1",
+		RuntimeSequence:      9,
+		RuntimeTimestampMS:   100,
+		WallClockTimestampMS: 100,
+	}, ExecutionPlan{
+		Nodes: []NodeSpec{
+			{NodeID: "stt", NodeType: "provider", Lane: eventabi.LaneData, Provider: &ProviderInvocationInput{Modality: contracts.ModalitySTT, PreferredProvider: "stt-a"}},
+			{NodeID: "llm", NodeType: "provider", Lane: eventabi.LaneData, Provider: &ProviderInvocationInput{Modality: contracts.ModalityLLM, PreferredProvider: "llm-seeded"}},
+			{NodeID: "llm-fallback", NodeType: "provider", Lane: eventabi.LaneData, Provider: &ProviderInvocationInput{Modality: contracts.ModalityLLM, PreferredProvider: "llm-unseeded"}},
+		},
+		Edges: []EdgeSpec{{From: "stt", To: "llm"}, {From: "llm", To: "llm-fallback"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected execute plan error: %v", err)
+	}
+
+	tests := []struct {
+		nodeID     string
+		wantStatus string
+	}{
+		{nodeID: "stt"},
+		{nodeID: "llm", wantStatus: contracts.SamplingSeedApplied},
+		{nodeID: "llm-fallback", wantStatus: contracts.SamplingSeedUnsupported},
+	}
+	for idx, tc := range tests {
+		provider := trace.Nodes[idx].Decision.Provider
+		if provider == nil || trace.Nodes[idx].NodeID != tc.nodeID || provider.SamplingSeedStatus != tc.wantStatus {
+			t.Fatalf("%s: expected sampling seed status %q, got %+v", tc.nodeID, tc.wantStatus, provider)
+		}
+		wantSeed := int64(0)
+		if tc.wantStatus != "" {
+			wantSeed = determinism.SamplingSeed(9, "turn-seed-1", tc.nodeID)
+		}
+		if provider.SamplingSeed != wantSeed {
+			t.Fatalf("%s: expected sampling seed %d, got %d", tc.nodeID, wantSeed, provider.SamplingSeed)
+		}
+		if err := provider.ToInvocationOutcomeEvidence().Validate(); err != nil {
+			t.Fatalf("%s: expected valid outcome evidence, got %v", tc.nodeID, err)
+		}
+	}
+	if len(sent) != 1 || sent["evt-seed-llm"] != determinism.SamplingSeed(9, "turn-seed-1", "llm") {
+		t.Fatalf("expected only the seed-capable provider to receive the seed, got %v", sent)
+	}
+	attempts := recorder.ProviderAttemptEntries()
+	if len(attempts) != 3 || attempts[2].SamplingSeedStatus != contracts.SamplingSeedUnsupported || attempts[2].SamplingSeed == 0 {
+		t.Fatalf("expected attempt evidence to carry sampling seed status, got %+v", attempts)
+	}
+}
+
+func TestNodeDispatchHealthRoutingPrefersHealthierProviderWhenSwitchAllowed(t *testing.T) {
+	t.Parallel()
+
+	calls := map[string]int{}
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{
+			ID:   "stt-a",
+			Mode: contracts.ModalitySTT,
+			InvokeFn: func(contracts.InvocationRequest) (contracts.Outcome, error) {
+				calls["stt-a"]++
+				return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Reason: "provider_unavailable"}, nil
+			},
+		},
+		contracts.StaticAdapter{
+			ID:   "stt-b",
+			Mode: contracts.ModalitySTT,
+			InvokeFn: func(contracts.InvocationRequest) (contracts.Outcome, error) {
+				calls["stt-b"]++
+				return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	scorer := healthscore.NewScorer(healthscore.Config{MinSamples: 3})
+	scheduler := NewSchedulerWithProviderInvoker(localadmission.Evaluator{}, invocation.NewController(catalog)).WithHealthScorer(scorer)
+	dispatch := func(idx int, actions []string) ProviderDecision {
+		decision, err := scheduler.NodeDispatch(SchedulingInput{
+			SessionID:       "sess-health-1",
+			TurnID:          fmt.Sprintf("turn-health-%d", idx),
+			EventID:         fmt.Sprintf("evt-health-%d", idx),
+			PipelineVersion: "pipeline-v1",
+			ProviderInvocation: &ProviderInvocationInput{
+				Modality:               contracts.ModalitySTT,
+				PreferredProvider:      "stt-a",
+				AllowedAdaptiveActions: actions,
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected dispatch error: %v", err)
+		}
+		return *decision.Provider
+	}
+
+	for idx := 1; idx <= 3; idx++ {
+		if decision := dispatch(idx, []string{"provider_switch"}); decision.SelectedProvider != "stt-b" || decision.HealthRoutedFrom != "" {
+			t.Fatalf("expected unscored providers to keep plan order, got %+v", decision)
+		}
+	}
+	if calls["stt-a"] != 3 {
+		t.Fatalf("expected the failing preferred provider tried first while unscored, got %v", calls)
+	}
+
+	decision := dispatch(4, []string{"provider_switch"})
+	if decision.SelectedProvider != "stt-b" || decision.Attempts != 1 || decision.HealthRoutedFrom != "stt-a" || calls["stt-a"] != 3 {
+		t.Fatalf("expected health routing straight to stt-b, got %+v calls=%v", decision, calls)
+	}
+
+	decision = dispatch(5, []string{"retry"})
+	if decision.SelectedProvider != "stt-a" || decision.HealthRoutedFrom != "" || decision.OutcomeClass != contracts.OutcomeInfrastructureFailure {
+		t.Fatalf("expected the plan preference kept without provider_switch, got %+v", decision)
+	}
+}
+
+func TestExecutePlanEnforcesTenantBudgets(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		budget          controlplane.TenantBudget
+		wantInvocations int
+		wantReason      string
+	}{
+		{name: "within budget", budget: controlplane.TenantBudget{MaxTokensPerTurn: 1000, MaxSessionSpendUSD: 1000}, wantInvocations: 3},
+		{name: "turn token cap", budget: controlplane.TenantBudget{MaxTokensPerTurn: 350}, wantInvocations: 2, wantReason: localadmission.ReasonBudgetTurnTokens},
+		{name: "session spend cap", budget: controlplane.TenantBudget{MaxSessionSpendUSD: 150}, wantInvocations: 1, wantReason: localadmission.ReasonBudgetSessionSpend},
+	}
+	for _, tc := range tests {
+		invocations := 0
+		catalog, err := registry.NewCatalog([]contracts.Adapter{
+			contracts.StaticAdapter{
+				ID:   "llm-a",
+				Mode: contracts.ModalityLLM,
+				InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+					invocations++
+					return contracts.Outcome{Class: contracts.OutcomeSuccess, Usage: &contracts.TokenUsage{InputTokens: 100, OutputTokens: 50}}, nil
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected catalog error: %v", tc.name, err)
+		}
+		scheduler := NewSchedulerWithProviderInvoker(localadmission.Evaluator{}, invocation.NewController(catalog)).
+			WithPriceTable(cost.PriceTable{"llm-a": {InputTokensPerMillionUSD: 1_000_000, OutputTokensPerMillionUSD: 2_000_000}}).
+			WithBudgets(controlplane.BudgetPolicy{ByTenant: map[string]controlplane.TenantBudget{"tenant-a": tc.budget}})
+
+		nodes := make([]NodeSpec, 0, 3)
+		for _, nodeID := range []string{"llm-1", "llm-2", "llm-3"} {
+			nodes = append(nodes, NodeSpec{
+				NodeID:   nodeID,
+				NodeType: "provider",
+				Lane:     eventabi.LaneData,
+				Provider: &ProviderInvocationInput{Modality: contracts.ModalityLLM, PreferredProvider: "llm-a", MaxOutputTokens: 60},
+			})
+		}
+		trace, err := scheduler.ExecutePlan(
+			SchedulingInput{
+				SessionID:            "sess-budget-1",
+				TurnID:               "turn-budget-1",
+				EventID:              "evt-budget-1",
+				PipelineVersion:      "pipeline-v1",
+				RuntimeTimestampMS:   100,
+				WallClockTimestampMS: 100,
+				Budget:               &localadmission.BudgetUsage{TenantID: "tenant-a"},
+			},
+			ExecutionPlan{Nodes: nodes, Edges: []EdgeSpec{{From: "llm-1", To: "llm-2"}, {From: "llm-2", To: "llm-3"}}},
+		)
+		if err != nil {
+			t.Fatalf("%s: unexpected execute plan error: %v", tc.name, err)
+		}
+		if invocations != tc.wantInvocations {
+			t.Fatalf("%s: expected %d provider invocations, got %d", tc.name, tc.wantInvocations, invocations)
+		}
+		if tc.wantReason == "" {
+			if !trace.Completed {
+				t.Fatalf("%s: expected plan to complete within budget, got %+v", tc.name, trace)
+			}
+			continue
+		}
+		denied := trace.Nodes[len(trace.Nodes)-1].Decision
+		if trace.Completed || denied.Outcome == nil || denied.Outcome.OutcomeKind != controlplane.OutcomeBudgetExceeded || denied.Outcome.Reason != tc.wantReason {
+			t.Fatalf("%s: expected budget_exceeded %s, got %+v", tc.name, tc.wantReason, denied.Outcome)
+		}
+		if denied.ControlSignal == nil || denied.ControlSignal.Signal != "shed" || denied.ControlSignal.Reason != tc.wantReason {
+			t.Fatalf("%s: expected shed control signal, got %+v", tc.name, denied.ControlSignal)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package fallbackspeech
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+// DefaultTemplateReason keys the template used for degrade reasons without their own template.
+const DefaultTemplateReason = "default"
+
+// Template is the utterance spoken for one degrade reason. PCM, when set, is pre-synthesized
+// audio at the config sample rate and is used as-is.
+type Template struct {
+	Reason string  `json:"reason"`
+	Text   string  `json:"text"`
+	PCM    []int16 `json:"-"`
+}
+
+// Config maps degrade reasons to fallback utterances.
+type Config struct {
+	SampleRateHz int        `json:"sample_rate_hz"`
+	Templates    []Template `json:"templates"`
+	// Presynthesize renders every text-only template once at construction, so a degraded turn
+	// never depends on the TTS provider that may be the reason it degraded.
+	Presynthesize bool `json:"presynthesize,omitempty"`
+}
+
+// DefaultConfig returns templates for the runtime's built-in degrade reasons.
+func DefaultConfig(sampleRateHz int) Config {
+	return Config{
+		SampleRateHz:  sampleRateHz,
+		Presynthesize: true,
+		Templates: []Template{
+			{Reason: "provider_failure", Text: "Sorry, I'm having trouble reaching my speech services. Please try again in a moment."},
+			{Reason: "budget_exhausted", Text: "Sorry, that took longer than expected. Could you ask again?"},
+			{Reason: "node_timeout_or_failure", Text: "Sorry, something went wrong on my side. Please try again."},
+			{Reason: DefaultTemplateReason, Text: "Sorry, I can't answer right now. Please try again shortly."},
+		},
+	}
+}
+
+// LoadConfig reads a JSON template config from path.
+func LoadConfig(path string) (Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return Config{}, fmt.Errorf("decode fallback speech config %s: %w", path, err)
+	}
+	return cfg, cfg.Validate()
+}
+
+// Validate enforces template invariants.
+func (c Config) Validate() error {
+	if c.SampleRateHz < 1 {
+		return fmt.Errorf("fallback speech sample_rate_hz must be >=1")
+	}
+	if len(c.Templates) == 0 {
+		return fmt.Errorf("fallback speech requires at least one template")
+	}
+	seen := map[string]struct{}{}
+	for _, template := range c.Templates {
+		if template.Reason == "" || template.Text == "" {
+			return fmt.Errorf("fallback speech template reason and text are required")
+		}
+		if _, ok := seen[template.Reason]; ok {
+			return fmt.Errorf("duplicate fallback speech template: %s", template.Reason)
+		}
+		seen[template.Reason] = struct{}{}
+	}
+	return nil
+}
+
+// Synthesizer renders text as mono 16-bit PCM.
+type Synthesizer interface {
+	Synthesize(text string, sampleRateHz int) ([]int16, error)
+}
+
+// EmitInput identifies the degraded turn the utterance is spoken into.
+type EmitInput struct {
+	SessionID            string
+	TurnID               string
+	PipelineVersion      string
+	EventID              string
+	Reason               string
+	TransportSequence    int64
+	RuntimeSequence      int64
+	AuthorityEpoch       int64
+	RuntimeTimestampMS   int64
+	WallClockTimestampMS int64
+}
+
+// Utterance is a fallback utterance ready for the data lane.
+type Utterance struct {
+	Event    eventabi.EventRecord
+	PCM      []int16
+	Evidence timeline.FallbackUtteranceEvidence
+}
+
+// Speaker selects and renders fallback utterances for degraded turns.
+type Speaker struct {
+	sampleRateHz int
+	templates    map[string]Template
+	tts          Synthesizer
+}
+
+// NewSpeaker validates cfg and pre-synthesizes templates when configured. tts may be nil only
+// when every template ends up with pre-synthesized audio.
+func NewSpeaker(cfg Config, tts Synthesizer) (*Speaker, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	speaker := &Speaker{sampleRateHz: cfg.SampleRateHz, templates: make(map[string]Template, len(cfg.Templates)), tts: tts}
+	for _, template := range cfg.Templates {
+		template.PCM = append([]int16(nil), template.PCM...)
+		if len(template.PCM) == 0 && cfg.Presynthesize && tts != nil {
+			pcm, err := tts.Synthesize(template.Text, cfg.SampleRateHz)
+			if err != nil {
+				return nil, fmt.Errorf("presynthesize fallback template %s: %w", template.Reason, err)
+			}
+			template.PCM = pcm
+		}
+		if len(template.PCM) == 0 && tts == nil {
+			return nil, fmt.Errorf("fallback template %s has no audio and no synthesizer is configured", template.Reason)
+		}
+		speaker.templates[template.Reason] = template
+	}
+	return speaker, nil
+}
+
+// Template returns the template spoken for reason, falling back to the default template.
+func (s *Speaker) Template(reason string) (Template, bool) {
+	if template, ok := s.templates[reason]; ok {
+		return template, true
+	}
+	template, ok := s.templates[DefaultTemplateReason]
+	return template, ok
+}
+
+// Emit renders the fallback utterance for in.Reason as a data-lane audio event. It fails when
+// no template covers the reason or synthesis fails; the caller then aborts as before.
+func (s *Speaker) Emit(in EmitInput) (Utterance, error) {
+	if in.SessionID == "" || in.TurnID == "" || in.PipelineVersion == "" || in.EventID == "" || in.Reason == "" {
+		return Utterance{}, fmt.Errorf("session_id, turn_id, pipeline_version, event_id, and reason are required")
+	}
+	template, ok := s.Template(in.Reason)
+	if !ok {
+		return Utterance{}, fmt.Errorf("no fallback template for reason %s", in.Reason)
+	}
+
+	source := timeline.FallbackSourcePresynthesized
+	pcm := template.PCM
+	if len(pcm) == 0 {
+		rendered, err := s.tts.Synthesize(template.Text, s.sampleRateHz)
+		if err != nil {
+			return Utterance{}, fmt.Errorf("synthesize fallback template %s: %w", template.Reason, err)
+		}
+		source, pcm = timeline.FallbackSourceTTS, rendered
+	}
+	if len(pcm) == 0 {
+		return Utterance{}, fmt.Errorf("fallback template %s rendered no audio", template.Reason)
+	}
+
+	transportSequence := max(in.TransportSequence, 0)
+	authorityEpoch := max(in.AuthorityEpoch, 0)
+	sampleIndex := int64(0)
+	event := eventabi.EventRecord{
+		SchemaVersion:      "v1.0",
+		EventScope:         eventabi.ScopeTurn,
+		SessionID:          in.SessionID,
+		TurnID:             in.TurnID,
+		PipelineVersion:    in.PipelineVersion,
+		EventID:            in.EventID,
+		Lane:               eventabi.LaneData,
+		TransportSequence:  &transportSequence,
+		RuntimeSequence:    max(in.RuntimeSequence, 0),
+		AuthorityEpoch:     &authorityEpoch,
+		RuntimeTimestampMS: max(in.RuntimeTimestampMS, 0),
+		WallClockMS:        max(in.WallClockTimestampMS, 0),
+		PayloadClass:       eventabi.PayloadAudioRaw,
+		MediaTime:          &eventabi.MediaTime{SampleIndex: &sampleIndex},
+	}
+	if err := event.Validate(); err != nil {
+		return Utterance{}, err
+	}
+	evidence := timeline.FallbackUtteranceEvidence{
+		EventID:        in.EventID,
+		Reason:         in.Reason,
+		TemplateReason: template.Reason,
+		Text:           template.Text,
+		Source:         source,
+		SampleRateHz:   s.sampleRateHz,
+		AudioSamples:   len(pcm),
+		EmittedAtMS:    event.RuntimeTimestampMS,
+	}
+	if err := evidence.Validate(); err != nil {
+		return Utterance{}, err
+	}
+	return Utterance{Event: event, PCM: append([]int16(nil), pcm...), Evidence: evidence}, nil
+}
@@ -0,0 +1,130 @@
+package fallbackspeech
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+type countingTTS struct {
+	calls int
+	err   error
+}
+
+func (c *countingTTS) Synthesize(text string, sampleRateHz int) ([]int16, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return make([]int16, len(strings.Fields(text))*sampleRateHz/100), nil
+}
+
+func testEmitInput(reason string) EmitInput {
+	return EmitInput{
+		SessionID:          "sess-fallback-1",
+		TurnID:             "turn-1",
+		PipelineVersion:    "pipeline-v1",
+		EventID:            "evt-turn-1-fallback",
+		Reason:             reason,
+		RuntimeTimestampMS: 1_200,
+	}
+}
+
+func TestSpeakerSelectsTemplateAndSource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		presynth     bool
+		reason       string
+		wantTemplate string
+		wantSource   string
+		wantTTSCalls int
+	}{
+		{name: "presynthesized reason template", presynth: true, reason: "provider_failure", wantTemplate: "provider_failure", wantSource: timeline.FallbackSourcePresynthesized, wantTTSCalls: 4},
+		{name: "default covers unknown reason", presynth: true, reason: "circuit_open", wantTemplate: DefaultTemplateReason, wantSource: timeline.FallbackSourcePresynthesized, wantTTSCalls: 4},
+		{name: "live tts when not presynthesized", reason: "budget_exhausted", wantTemplate: "budget_exhausted", wantSource: timeline.FallbackSourceTTS, wantTTSCalls: 1},
+	}
+	for _, tc := range tests {
+		tts := &countingTTS{}
+		cfg := DefaultConfig(16000)
+		cfg.Presynthesize = tc.presynth
+		speaker, err := NewSpeaker(cfg, tts)
+		if err != nil {
+			t.Fatalf("%s: unexpected speaker error: %v", tc.name, err)
+		}
+		utterance, err := speaker.Emit(testEmitInput(tc.reason))
+		if err != nil {
+			t.Fatalf("%s: unexpected emit error: %v", tc.name, err)
+		}
+		if utterance.Evidence.Reason != tc.reason || utterance.Evidence.TemplateReason != tc.wantTemplate || utterance.Evidence.Source != tc.wantSource {
+			t.Fatalf("%s: unexpected evidence %+v", tc.name, utterance.Evidence)
+		}
+		if tts.calls != tc.wantTTSCalls {
+			t.Fatalf("%s: expected %d tts calls, got %d", tc.name, tc.wantTTSCalls, tts.calls)
+		}
+		if utterance.Event.Lane != eventabi.LaneData || utterance.Event.PayloadClass != eventabi.PayloadAudioRaw || len(utterance.PCM) != utterance.Evidence.AudioSamples {
+			t.Fatalf("%s: unexpected data-lane utterance %+v", tc.name, utterance.Event)
+		}
+		if err := utterance.Evidence.Validate(); err != nil {
+			t.Fatalf("%s: evidence should validate: %v", tc.name, err)
+		}
+	}
+}
+
+func TestSpeakerFailures(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewSpeaker(Config{SampleRateHz: 16000, Templates: []Template{{Reason: "provider_failure", Text: "Sorry."}}}, nil); err == nil {
+		t.Fatalf("expected text-only template without synthesizer to fail")
+	}
+	if _, err := NewSpeaker(DefaultConfig(16000), &countingTTS{err: errors.New("tts down")}); err == nil {
+		t.Fatalf("expected presynthesis failure to fail construction")
+	}
+	speaker, err := NewSpeaker(Config{SampleRateHz: 16000, Templates: []Template{{Reason: "provider_failure", Text: "Sorry.", PCM: []int16{1, 2, 3}}}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected pre-synthesized speaker error: %v", err)
+	}
+	if _, err := speaker.Emit(testEmitInput("budget_exhausted")); err == nil {
+		t.Fatalf("expected reason without template or default to fail")
+	}
+	if _, err := speaker.Emit(EmitInput{Reason: "provider_failure"}); err == nil {
+		t.Fatalf("expected missing identity to fail")
+	}
+}
+
+func TestConfigValidateAndLoad(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		cfg       Config
+		shouldErr bool
+	}{
+		{name: "defaults", cfg: DefaultConfig(16000)},
+		{name: "no sample rate", cfg: Config{Templates: []Template{{Reason: "a", Text: "b"}}}, shouldErr: true},
+		{name: "no templates", cfg: Config{SampleRateHz: 16000}, shouldErr: true},
+		{name: "missing text", cfg: Config{SampleRateHz: 16000, Templates: []Template{{Reason: "a"}}}, shouldErr: true},
+		{name: "duplicate reason", cfg: Config{SampleRateHz: 16000, Templates: []Template{{Reason: "a", Text: "b"}, {Reason: "a", Text: "c"}}}, shouldErr: true},
+	}
+	for _, tc := range tests {
+		if err := tc.cfg.Validate(); tc.shouldErr != (err != nil) {
+			t.Fatalf("%s: expected error=%t, got %v", tc.name, tc.shouldErr, err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "fallback.json")
+	raw := `{"sample_rate_hz":16000,"presynthesize":true,"templates":[{"reason":"provider_failure","text":"One moment please."}]}`
+	if err := os.WriteFile(path, []byte(raw), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil || len(cfg.Templates) != 1 || !cfg.Presynthesize || cfg.Templates[0].Text != "One moment please." {
+		t.Fatalf("unexpected loaded config %+v (%v)", cfg, err)
+	}
+}
@@ -0,0 +1,83 @@
+package flowcontrol
+
+import (
+	"fmt"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	runtimeeventabi "github.com/tiger/realtime-speech-pipeline/internal/runtime/eventabi"
+)
+
+// DegradeReasonPrefix prefixes the reason of every degrade ladder control signal.
+const DegradeReasonPrefix = "degrade_ladder:"
+
+// DegradeDecision is the deterministic outcome of evaluating a degrade ladder at one pressure level.
+type DegradeDecision struct {
+	// Steps are the engaged ladder steps in severity order.
+	Steps []controlplane.DegradeStep
+	// LLMMaxTokens is the output cap for LLM invocations, or 0 when uncapped.
+	LLMMaxTokens int
+	// HardLimit reports pressure at or above the ladder hard limit.
+	HardLimit bool
+}
+
+// Engaged reports whether step is active in the decision.
+func (d DegradeDecision) Engaged(step controlplane.DegradeStep) bool {
+	if step == controlplane.DegradeShed && d.HardLimit {
+		return true
+	}
+	for _, engaged := range d.Steps {
+		if engaged == step {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluateDegradeLadder engages every rung whose soft limit is at or below queueDepth.
+func EvaluateDegradeLadder(ladder controlplane.DegradeLadder, queueDepth int64) (DegradeDecision, error) {
+	if err := ladder.Validate(); err != nil {
+		return DegradeDecision{}, err
+	}
+	decision := DegradeDecision{}
+	for _, rung := range ladder.Rungs {
+		if queueDepth < int64(rung.SoftLimit) {
+			break
+		}
+		decision.Steps = append(decision.Steps, rung.Step)
+		if rung.Step == controlplane.DegradeShortenLLMMaxTokens {
+			decision.LLMMaxTokens = rung.LLMMaxTokens
+		}
+	}
+	if queueDepth >= int64(ladder.HardLimit) {
+		decision.HardLimit = true
+		if len(decision.Steps) == 0 || decision.Steps[len(decision.Steps)-1] != controlplane.DegradeShed {
+			decision.Steps = append(decision.Steps, controlplane.DegradeShed)
+		}
+	}
+	return decision, nil
+}
+
+// BuildDegradeSignals emits one RK-14 degrade signal per engaged step as replay evidence.
+func BuildDegradeSignals(in Input, decision DegradeDecision) ([]eventabi.ControlSignal, error) {
+	if in.SessionID == "" || in.PipelineVersion == "" || in.EdgeID == "" || in.EventID == "" {
+		return nil, fmt.Errorf("session_id, pipeline_version, edge_id, and event_id are required")
+	}
+	if in.TargetLane == "" {
+		return nil, fmt.Errorf("target_lane is required")
+	}
+	if len(decision.Steps) == 0 {
+		return nil, nil
+	}
+	signals := make([]eventabi.ControlSignal, 0, len(decision.Steps))
+	for idx, step := range decision.Steps {
+		stepInput := in
+		stepInput.RuntimeSequence = in.RuntimeSequence + int64(idx)
+		signal, err := buildSignal(stepInput, "degrade", DegradeReasonPrefix+string(step), nil)
+		if err != nil {
+			return nil, err
+		}
+		signals = append(signals, signal)
+	}
+	return runtimeeventabi.ValidateAndNormalizeControlSignals(signals)
+}
@@ -0,0 +1,83 @@
+package flowcontrol
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+func testDegradeLadder() controlplane.DegradeLadder {
+	return controlplane.DegradeLadder{
+		Rungs: []controlplane.DegradeRung{
+			{Step: controlplane.DegradeReduceTelemetryDetail, SoftLimit: 4},
+			{Step: controlplane.DegradeDropSTTPartials, SoftLimit: 8},
+			{Step: controlplane.DegradeShortenLLMMaxTokens, SoftLimit: 12, LLMMaxTokens: 64},
+		},
+		HardLimit: 16,
+	}
+}
+
+func TestEvaluateDegradeLadder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		queueDepth int64
+		wantSteps  []controlplane.DegradeStep
+		wantTokens int
+		wantHard   bool
+	}{
+		{name: "below first rung", queueDepth: 3},
+		{name: "first rung", queueDepth: 4, wantSteps: []controlplane.DegradeStep{controlplane.DegradeReduceTelemetryDetail}},
+		{name: "llm rung", queueDepth: 13, wantSteps: []controlplane.DegradeStep{controlplane.DegradeReduceTelemetryDetail, controlplane.DegradeDropSTTPartials, controlplane.DegradeShortenLLMMaxTokens}, wantTokens: 64},
+		{name: "hard limit sheds", queueDepth: 16, wantSteps: []controlplane.DegradeStep{controlplane.DegradeReduceTelemetryDetail, controlplane.DegradeDropSTTPartials, controlplane.DegradeShortenLLMMaxTokens, controlplane.DegradeShed}, wantTokens: 64, wantHard: true},
+	}
+	for _, tc := range tests {
+		decision, err := EvaluateDegradeLadder(testDegradeLadder(), tc.queueDepth)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if len(decision.Steps) != len(tc.wantSteps) || decision.LLMMaxTokens != tc.wantTokens || decision.HardLimit != tc.wantHard {
+			t.Fatalf("%s: expected steps=%v tokens=%d hard=%v, got %+v", tc.name, tc.wantSteps, tc.wantTokens, tc.wantHard, decision)
+		}
+		for idx := range tc.wantSteps {
+			if decision.Steps[idx] != tc.wantSteps[idx] {
+				t.Fatalf("%s: expected steps=%v, got %+v", tc.name, tc.wantSteps, decision.Steps)
+			}
+		}
+	}
+
+	if _, err := EvaluateDegradeLadder(controlplane.DegradeLadder{HardLimit: 2}, 1); err == nil {
+		t.Fatalf("expected invalid ladder to be rejected")
+	}
+}
+
+func TestBuildDegradeSignals(t *testing.T) {
+	t.Parallel()
+
+	decision, err := EvaluateDegradeLadder(testDegradeLadder(), 9)
+	if err != nil {
+		t.Fatalf("unexpected evaluate error: %v", err)
+	}
+	signals, err := BuildDegradeSignals(Input{
+		SessionID:       "sess-degrade-1",
+		TurnID:          "turn-degrade-1",
+		PipelineVersion: "pipeline-v1",
+		EdgeID:          "edge-a",
+		EventID:         "evt-degrade-1",
+		TargetLane:      eventabi.LaneData,
+		RuntimeSequence: 5,
+	}, decision)
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+	if len(signals) != 2 {
+		t.Fatalf("expected one signal per engaged step, got %+v", signals)
+	}
+	for idx, want := range []string{"degrade_ladder:reduce_telemetry_detail", "degrade_ladder:drop_stt_partials"} {
+		if signals[idx].Signal != "degrade" || signals[idx].EmittedBy != "RK-14" || signals[idx].Reason != want || signals[idx].RuntimeSequence != int64(5+idx) {
+			t.Fatalf("expected degrade evidence %s at index %d, got %+v", want, idx, signals[idx])
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package guard
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/identifiers"
+)
 
 // IngressAuthorityInput captures authority metadata available at ingress.
 type IngressAuthorityInput struct {
@@ -19,8 +23,13 @@ type IngressAuthorityResult struct {
 
 // EnrichIngressAuthority deterministically fills missing carrier authority metadata.
 func (Evaluator) EnrichIngressAuthority(in IngressAuthorityInput) (IngressAuthorityResult, error) {
-	if in.SessionID == "" {
-		return IngressAuthorityResult{}, fmt.Errorf("session_id is required")
+	if err := identifiers.ValidateSessionID(in.SessionID); err != nil {
+		return IngressAuthorityResult{}, err
+	}
+	if in.TurnID != "" {
+		if err := identifiers.ValidateTurnID(in.TurnID); err != nil {
+			return IngressAuthorityResult{}, err
+		}
 	}
 	if in.RoutingViewEpoch < 0 {
 		return IngressAuthorityResult{}, fmt.Errorf("routing view epoch must be >= 0")
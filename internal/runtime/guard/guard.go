@@ -1,6 +1,9 @@
 package guard
 
-import "github.com/tiger/realtime-speech-pipeline/api/controlplane"
+import (
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
 
 // PreTurnInput is the deterministic authority gate input before turn_open.
 type PreTurnInput struct {
@@ -45,6 +48,7 @@ func (Evaluator) EvaluatePreTurn(in PreTurnInput) PreTurnResult {
 			RuntimeTimestampMS: in.RuntimeTimestampMS,
 			WallClockMS:        in.WallClockTimestampMS,
 			EmittedBy:          controlplane.EmitterRK24,
+			FailureDomain:      eventabi.FailureDomainControlPlane,
 			AuthorityEpoch:     &epoch,
 			Reason:             "authority_epoch_mismatch",
 		}
@@ -67,6 +71,7 @@ func (Evaluator) EvaluatePreTurn(in PreTurnInput) PreTurnResult {
 			RuntimeTimestampMS: in.RuntimeTimestampMS,
 			WallClockMS:        in.WallClockTimestampMS,
 			EmittedBy:          controlplane.EmitterRK24,
+			FailureDomain:      eventabi.FailureDomainControlPlane,
 			AuthorityEpoch:     &epoch,
 			Reason:             "authority_revoked_before_open",
 		}
@@ -89,6 +94,7 @@ func (Evaluator) ActiveTurnRevokeOutcome(sessionID, turnID, eventID string, runt
 		RuntimeTimestampMS: runtimeTS,
 		WallClockMS:        wallTS,
 		EmittedBy:          controlplane.EmitterRK24,
+		FailureDomain:      eventabi.FailureDomainControlPlane,
 		AuthorityEpoch:     &epoch,
 		Reason:             "authority_revoked_in_turn",
 	}
@@ -70,6 +70,7 @@ func RejectOldPlacementOutput(sessionID, turnID, eventID string, runtimeTS, wall
 		RuntimeTimestampMS: runtimeTS,
 		WallClockMS:        wallTS,
 		EmittedBy:          controlplane.EmitterRK24,
+		FailureDomain:      eventabi.FailureDomainControlPlane,
 		AuthorityEpoch:     &epoch,
 		Reason:             "authority_epoch_mismatch",
 	}
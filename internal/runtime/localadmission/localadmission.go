@@ -1,6 +1,13 @@
 package localadmission
 
-import "github.com/tiger/realtime-speech-pipeline/api/controlplane"
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
 
 // CapacityDisposition controls deterministic RK-25 admission behavior.
 type CapacityDisposition string
@@ -11,6 +18,25 @@ const (
 	CapacityReject CapacityDisposition = "reject"
 )
 
+// EnvDecisionExplain enables explain payloads on RK-25 decision outcomes. Explain payloads
+// are debug evidence and are off by default.
+const EnvDecisionExplain = "RSPP_DECISION_EXPLAIN"
+
+// Policy rules recorded in decision explain payloads.
+const (
+	RuleSnapshotFailurePolicy = "rk25.pre_turn.snapshot_failure_policy"
+	RuleCapacityReject        = "rk25.pre_turn.capacity_reject"
+	RuleCapacityDefer         = "rk25.pre_turn.capacity_defer"
+	RuleSchedulingShed        = "rk25.scheduling_point.shed"
+	RuleBudgetExceeded        = "rk25.scheduling_point.budget_exceeded"
+)
+
+// Budget-exceeded decision reasons.
+const (
+	ReasonBudgetTurnTokens   = "budget_max_tokens_per_turn_exceeded"
+	ReasonBudgetSessionSpend = "budget_max_session_spend_exceeded"
+)
+
 // PreTurnInput contains deterministic admission inputs before authority checks.
 type PreTurnInput struct {
 	SessionID             string
@@ -21,12 +47,19 @@ type PreTurnInput struct {
 	SnapshotValid         bool
 	SnapshotFailurePolicy controlplane.OutcomeKind // reject|defer
 	CapacityDisposition   CapacityDisposition
+	// QuotaCounters and Thresholds are recorded as explain evidence when enabled.
+	QuotaCounters []controlplane.QuotaCounter
+	Thresholds    []controlplane.ThresholdEvaluation
+	// Queue, when set, yields a queue position/ETA on capacity defer.
+	Queue *QueueState
 }
 
 // PreTurnResult includes either allow or deterministic RK-25 outcome.
 type PreTurnResult struct {
 	Allowed bool
 	Outcome *controlplane.DecisionOutcome
+	// QueueEstimate is set on capacity defer when a valid queue snapshot was supplied.
+	QueueEstimate *QueueEstimate
 }
 
 // SchedulingPointInput models RK-25 scheduling-point decisions.
@@ -39,18 +72,60 @@ type SchedulingPointInput struct {
 	Scope                controlplane.OutcomeScope // edge_enqueue|edge_dequeue|node_dispatch
 	Shed                 bool
 	Reason               string
+	// Budget, when set, is checked against the evaluator's tenant budget policy.
+	Budget *BudgetUsage
+	// QuotaCounters and Thresholds are recorded as explain evidence when enabled.
+	QuotaCounters []controlplane.QuotaCounter
+	Thresholds    []controlplane.ThresholdEvaluation
+}
+
+// BudgetUsage is a turn's consumption checked against its tenant budget at a scheduling point.
+type BudgetUsage struct {
+	TenantID string
+	// TurnTokens is the turn's provider token count including the work being scheduled.
+	TurnTokens int64
+	// SessionSpendUSD is the provider spend the session has accrued before the work; once it
+	// reaches the cap there is no headroom left for more provider work.
+	SessionSpendUSD float64
 }
 
-// SchedulingPointResult includes either allow or a shed outcome.
+// SchedulingPointResult includes either allow or a shed/budget_exceeded outcome.
 type SchedulingPointResult struct {
 	Allowed bool
 	Outcome *controlplane.DecisionOutcome
 }
 
 // Evaluator implements RK-25 local admission and scheduling enforcement.
-type Evaluator struct{}
+type Evaluator struct {
+	// Explain attaches a DecisionExplain payload to every produced outcome.
+	Explain bool
+	// Budgets are the policy-bundle tenant budgets enforced at scheduling points.
+	Budgets controlplane.BudgetPolicy
+}
 
-func (Evaluator) EvaluatePreTurn(in PreTurnInput) PreTurnResult {
+// NewEvaluatorFromEnv enables explain payloads when RSPP_DECISION_EXPLAIN is true.
+func NewEvaluatorFromEnv() Evaluator {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(EnvDecisionExplain))) {
+	case "1", "true", "yes", "on":
+		return Evaluator{Explain: true}
+	default:
+		return Evaluator{}
+	}
+}
+
+func (e Evaluator) explain(rule string, inputs map[string]string, counters []controlplane.QuotaCounter, thresholds []controlplane.ThresholdEvaluation) *controlplane.DecisionExplain {
+	if !e.Explain {
+		return nil
+	}
+	return &controlplane.DecisionExplain{
+		PolicyRule:    rule,
+		Inputs:        inputs,
+		QuotaCounters: append([]controlplane.QuotaCounter(nil), counters...),
+		Thresholds:    append([]controlplane.ThresholdEvaluation(nil), thresholds...),
+	}
+}
+
+func (e Evaluator) EvaluatePreTurn(in PreTurnInput) PreTurnResult {
 	if !in.SnapshotValid {
 		kind := in.SnapshotFailurePolicy
 		if kind != controlplane.OutcomeReject {
@@ -70,7 +145,12 @@ func (Evaluator) EvaluatePreTurn(in PreTurnInput) PreTurnResult {
 			RuntimeTimestampMS: in.RuntimeTimestampMS,
 			WallClockMS:        in.WallClockTimestampMS,
 			EmittedBy:          controlplane.EmitterRK25,
+			FailureDomain:      eventabi.FailureDomainAdmission,
 			Reason:             "snapshot_invalid_or_missing",
+			Explain: e.explain(RuleSnapshotFailurePolicy, map[string]string{
+				"snapshot_valid":          "false",
+				"snapshot_failure_policy": string(kind),
+			}, in.QuotaCounters, in.Thresholds),
 		}
 		return PreTurnResult{Allowed: false, Outcome: &outcome}
 	}
@@ -91,7 +171,11 @@ func (Evaluator) EvaluatePreTurn(in PreTurnInput) PreTurnResult {
 			RuntimeTimestampMS: in.RuntimeTimestampMS,
 			WallClockMS:        in.WallClockTimestampMS,
 			EmittedBy:          controlplane.EmitterRK25,
+			FailureDomain:      eventabi.FailureDomainAdmission,
 			Reason:             "admission_capacity_reject",
+			Explain: e.explain(RuleCapacityReject, map[string]string{
+				"capacity_disposition": string(in.CapacityDisposition),
+			}, in.QuotaCounters, in.Thresholds),
 		}
 		return PreTurnResult{Allowed: false, Outcome: &outcome}
 	case CapacityDefer:
@@ -109,27 +193,39 @@ func (Evaluator) EvaluatePreTurn(in PreTurnInput) PreTurnResult {
 			RuntimeTimestampMS: in.RuntimeTimestampMS,
 			WallClockMS:        in.WallClockTimestampMS,
 			EmittedBy:          controlplane.EmitterRK25,
+			FailureDomain:      eventabi.FailureDomainAdmission,
 			Reason:             "admission_capacity_defer",
+			Explain: e.explain(RuleCapacityDefer, map[string]string{
+				"capacity_disposition": string(in.CapacityDisposition),
+			}, in.QuotaCounters, in.Thresholds),
 		}
-		return PreTurnResult{Allowed: false, Outcome: &outcome}
+		result := PreTurnResult{Allowed: false, Outcome: &outcome}
+		if in.Queue != nil {
+			if estimate, err := EstimateQueue(*in.Queue); err == nil {
+				result.QueueEstimate = &estimate
+			}
+		}
+		return result
 	default:
 		return PreTurnResult{Allowed: true}
 	}
 }
 
-func (Evaluator) EvaluateSchedulingPoint(in SchedulingPointInput) SchedulingPointResult {
+// EvaluateSchedulingPoint sheds when requested and otherwise emits budget_exceeded when the
+// input budget usage is over its tenant budget; shed takes precedence.
+func (e Evaluator) EvaluateSchedulingPoint(in SchedulingPointInput) SchedulingPointResult {
+	scope := in.Scope
+	if scope != controlplane.ScopeEdgeEnqueue && scope != controlplane.ScopeEdgeDequeue && scope != controlplane.ScopeNodeDispatch {
+		scope = controlplane.ScopeEdgeEnqueue
+	}
 	if !in.Shed {
-		return SchedulingPointResult{Allowed: true}
+		return e.evaluateBudget(in, scope)
 	}
 
 	reason := in.Reason
 	if reason == "" {
 		reason = "scheduling_point_shed"
 	}
-	scope := in.Scope
-	if scope != controlplane.ScopeEdgeEnqueue && scope != controlplane.ScopeEdgeDequeue && scope != controlplane.ScopeNodeDispatch {
-		scope = controlplane.ScopeEdgeEnqueue
-	}
 
 	outcome := controlplane.DecisionOutcome{
 		OutcomeKind:        controlplane.OutcomeShed,
@@ -141,7 +237,63 @@ func (Evaluator) EvaluateSchedulingPoint(in SchedulingPointInput) SchedulingPoin
 		RuntimeTimestampMS: in.RuntimeTimestampMS,
 		WallClockMS:        in.WallClockTimestampMS,
 		EmittedBy:          controlplane.EmitterRK25,
+		FailureDomain:      eventabi.FailureDomainAdmission,
+		Reason:             reason,
+		Explain: e.explain(RuleSchedulingShed, map[string]string{
+			"scope":  string(scope),
+			"reason": reason,
+		}, in.QuotaCounters, in.Thresholds),
+	}
+	return SchedulingPointResult{Allowed: false, Outcome: &outcome}
+}
+
+// evaluateBudget checks the per-turn token cap before the session spend cap.
+func (e Evaluator) evaluateBudget(in SchedulingPointInput, scope controlplane.OutcomeScope) SchedulingPointResult {
+	if in.Budget == nil {
+		return SchedulingPointResult{Allowed: true}
+	}
+	budget := e.Budgets.For(in.Budget.TenantID)
+	tokensExceeded := budget.MaxTokensPerTurn > 0 && in.Budget.TurnTokens > budget.MaxTokensPerTurn
+	spendExceeded := budget.MaxSessionSpendUSD > 0 && in.Budget.SessionSpendUSD >= budget.MaxSessionSpendUSD
+	if !tokensExceeded && !spendExceeded {
+		return SchedulingPointResult{Allowed: true}
+	}
+
+	reason := ReasonBudgetSessionSpend
+	if tokensExceeded {
+		reason = ReasonBudgetTurnTokens
+	}
+	counters := append([]controlplane.QuotaCounter(nil), in.QuotaCounters...)
+	if budget.MaxTokensPerTurn > 0 {
+		counters = append(counters, controlplane.QuotaCounter{Name: "turn_tokens", Used: in.Budget.TurnTokens, Limit: budget.MaxTokensPerTurn})
+	}
+	thresholds := append([]controlplane.ThresholdEvaluation(nil), in.Thresholds...)
+	if budget.MaxSessionSpendUSD > 0 {
+		thresholds = append(thresholds, controlplane.ThresholdEvaluation{
+			Name:      "session_spend_usd",
+			Observed:  in.Budget.SessionSpendUSD,
+			Threshold: budget.MaxSessionSpendUSD,
+			Breached:  spendExceeded,
+		})
+	}
+	outcome := controlplane.DecisionOutcome{
+		OutcomeKind:        controlplane.OutcomeBudgetExceeded,
+		Phase:              controlplane.PhaseScheduling,
+		Scope:              scope,
+		SessionID:          in.SessionID,
+		TurnID:             in.TurnID,
+		EventID:            in.EventID,
+		RuntimeTimestampMS: in.RuntimeTimestampMS,
+		WallClockMS:        in.WallClockTimestampMS,
+		EmittedBy:          controlplane.EmitterRK25,
+		FailureDomain:      eventabi.FailureDomainAdmission,
 		Reason:             reason,
+		Explain: e.explain(RuleBudgetExceeded, map[string]string{
+			"scope":             string(scope),
+			"tenant_id":         in.Budget.TenantID,
+			"turn_tokens":       strconv.FormatInt(in.Budget.TurnTokens, 10),
+			"session_spend_usd": strconv.FormatFloat(in.Budget.SessionSpendUSD, 'f', -1, 64),
+		}, counters, thresholds),
 	}
 	return SchedulingPointResult{Allowed: false, Outcome: &outcome}
 }
@@ -1,6 +1,7 @@
 package localadmission
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
@@ -80,3 +81,181 @@ func TestEvaluateSchedulingPointShed(t *testing.T) {
 		t.Fatalf("outcome should validate: %v", err)
 	}
 }
+
+func TestEvaluatorExplainPayload(t *testing.T) {
+	t.Parallel()
+
+	counters := []controlplane.QuotaCounter{{Name: "tenant_concurrent_turns", Used: 4, Limit: 4}}
+	thresholds := []controlplane.ThresholdEvaluation{{Name: "edge_queue_depth", Observed: 300, Threshold: 256, Breached: true}}
+	tests := []struct {
+		name     string
+		explain  bool
+		evaluate func(Evaluator) *controlplane.DecisionOutcome
+		wantRule string
+	}{
+		{
+			name: "disabled by default",
+			evaluate: func(e Evaluator) *controlplane.DecisionOutcome {
+				return e.EvaluatePreTurn(PreTurnInput{SessionID: "sess-1", EventID: "evt-1", SnapshotValid: true, CapacityDisposition: CapacityReject, QuotaCounters: counters}).Outcome
+			},
+		},
+		{
+			name:    "capacity reject",
+			explain: true,
+			evaluate: func(e Evaluator) *controlplane.DecisionOutcome {
+				return e.EvaluatePreTurn(PreTurnInput{SessionID: "sess-1", EventID: "evt-1", SnapshotValid: true, CapacityDisposition: CapacityReject, QuotaCounters: counters, Thresholds: thresholds}).Outcome
+			},
+			wantRule: RuleCapacityReject,
+		},
+		{
+			name:    "snapshot failure",
+			explain: true,
+			evaluate: func(e Evaluator) *controlplane.DecisionOutcome {
+				return e.EvaluatePreTurn(PreTurnInput{SessionID: "sess-1", EventID: "evt-1", SnapshotFailurePolicy: controlplane.OutcomeDefer, QuotaCounters: counters, Thresholds: thresholds}).Outcome
+			},
+			wantRule: RuleSnapshotFailurePolicy,
+		},
+		{
+			name:    "scheduling shed",
+			explain: true,
+			evaluate: func(e Evaluator) *controlplane.DecisionOutcome {
+				return e.EvaluateSchedulingPoint(SchedulingPointInput{SessionID: "sess-1", EventID: "evt-1", Scope: controlplane.ScopeNodeDispatch, Shed: true, QuotaCounters: counters, Thresholds: thresholds}).Outcome
+			},
+			wantRule: RuleSchedulingShed,
+		},
+	}
+	for _, tc := range tests {
+		outcome := tc.evaluate(Evaluator{Explain: tc.explain})
+		if outcome == nil {
+			t.Fatalf("%s: expected outcome", tc.name)
+		}
+		if err := outcome.Validate(); err != nil {
+			t.Fatalf("%s: outcome should validate: %v", tc.name, err)
+		}
+		if tc.wantRule == "" {
+			if outcome.Explain != nil {
+				t.Fatalf("%s: expected no explain payload, got %+v", tc.name, outcome.Explain)
+			}
+			continue
+		}
+		if outcome.Explain == nil || outcome.Explain.PolicyRule != tc.wantRule {
+			t.Fatalf("%s: expected policy rule %s, got %+v", tc.name, tc.wantRule, outcome.Explain)
+		}
+		if !reflect.DeepEqual(outcome.Explain.QuotaCounters, counters) || !reflect.DeepEqual(outcome.Explain.Thresholds, thresholds) {
+			t.Fatalf("%s: expected counters and thresholds recorded, got %+v", tc.name, outcome.Explain)
+		}
+	}
+}
+
+func TestNewEvaluatorFromEnv(t *testing.T) {
+	t.Setenv(EnvDecisionExplain, "true")
+	if !NewEvaluatorFromEnv().Explain {
+		t.Fatalf("expected explain enabled")
+	}
+	t.Setenv(EnvDecisionExplain, "")
+	if NewEvaluatorFromEnv().Explain {
+		t.Fatalf("expected explain disabled by default")
+	}
+}
+
+func TestEvaluatePreTurnCapacityDeferQueueEstimate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		queue        QueueState
+		wantPosition int64
+		wantWaitMS   int64
+	}{
+		{name: "empty queue waits one service round", queue: QueueState{TurnsAhead: 0, ActiveSlots: 2, MeanTurnServiceMS: 900}, wantPosition: 1, wantWaitMS: 900},
+		{name: "fills current round", queue: QueueState{TurnsAhead: 1, ActiveSlots: 2, MeanTurnServiceMS: 900}, wantPosition: 2, wantWaitMS: 900},
+		{name: "spills into next round", queue: QueueState{TurnsAhead: 2, ActiveSlots: 2, MeanTurnServiceMS: 900}, wantPosition: 3, wantWaitMS: 1_800},
+		{name: "unknown service time", queue: QueueState{TurnsAhead: 5, ActiveSlots: 1}, wantPosition: 6, wantWaitMS: 0},
+	}
+	for _, tc := range tests {
+		queue := tc.queue
+		result := Evaluator{}.EvaluatePreTurn(PreTurnInput{
+			SessionID:           "sess-1",
+			TurnID:              "turn-2",
+			EventID:             "evt-2",
+			SnapshotValid:       true,
+			CapacityDisposition: CapacityDefer,
+			Queue:               &queue,
+		})
+		if result.Allowed || result.QueueEstimate == nil {
+			t.Fatalf("%s: expected deferred result with queue estimate, got %+v", tc.name, result)
+		}
+		estimate := *result.QueueEstimate
+		if estimate.Position != tc.wantPosition || estimate.EstimatedWaitMS != tc.wantWaitMS || estimate.Method != QueueEstimateMethod || estimate.Inputs != tc.queue {
+			t.Fatalf("%s: unexpected estimate %+v", tc.name, estimate)
+		}
+	}
+
+	queue := QueueState{TurnsAhead: 1, ActiveSlots: 1, MeanTurnServiceMS: 100}
+	rejected := Evaluator{}.EvaluatePreTurn(PreTurnInput{SessionID: "sess-1", SnapshotValid: true, CapacityDisposition: CapacityReject, Queue: &queue})
+	if rejected.QueueEstimate != nil {
+		t.Fatalf("expected no queue estimate on reject, got %+v", rejected.QueueEstimate)
+	}
+	if _, err := EstimateQueue(QueueState{TurnsAhead: -1, ActiveSlots: 1}); err == nil {
+		t.Fatalf("expected negative turns_ahead to fail")
+	}
+}
+
+func TestEvaluateSchedulingPointBudget(t *testing.T) {
+	t.Parallel()
+
+	evaluator := Evaluator{Budgets: controlplane.BudgetPolicy{
+		Default:  controlplane.TenantBudget{MaxTokensPerTurn: 1000},
+		ByTenant: map[string]controlplane.TenantBudget{"tenant-a": {MaxTokensPerTurn: 200, MaxSessionSpendUSD: 0.5}},
+	}}
+	tests := []struct {
+		name       string
+		shed       bool
+		budget     *BudgetUsage
+		wantKind   controlplane.OutcomeKind
+		wantReason string
+	}{
+		{name: "no usage allowed", budget: nil},
+		{name: "within tenant budget", budget: &BudgetUsage{TenantID: "tenant-a", TurnTokens: 200, SessionSpendUSD: 0.49}},
+		{name: "tenant token cap", budget: &BudgetUsage{TenantID: "tenant-a", TurnTokens: 201}, wantKind: controlplane.OutcomeBudgetExceeded, wantReason: ReasonBudgetTurnTokens},
+		{name: "tenant spend cap", budget: &BudgetUsage{TenantID: "tenant-a", TurnTokens: 10, SessionSpendUSD: 0.5}, wantKind: controlplane.OutcomeBudgetExceeded, wantReason: ReasonBudgetSessionSpend},
+		{name: "default token cap", budget: &BudgetUsage{TenantID: "tenant-b", TurnTokens: 1001}, wantKind: controlplane.OutcomeBudgetExceeded, wantReason: ReasonBudgetTurnTokens},
+		{name: "default spend unlimited", budget: &BudgetUsage{TenantID: "tenant-b", SessionSpendUSD: 99}},
+		{name: "shed takes precedence", shed: true, budget: &BudgetUsage{TenantID: "tenant-a", TurnTokens: 5000}, wantKind: controlplane.OutcomeShed, wantReason: "scheduling_point_shed"},
+	}
+	for _, tc := range tests {
+		result := evaluator.EvaluateSchedulingPoint(SchedulingPointInput{
+			SessionID: "sess-1",
+			TurnID:    "turn-1",
+			EventID:   "evt-1",
+			Scope:     controlplane.ScopeNodeDispatch,
+			Shed:      tc.shed,
+			Budget:    tc.budget,
+		})
+		if tc.wantKind == "" {
+			if !result.Allowed || result.Outcome != nil {
+				t.Fatalf("%s: expected scheduling point allowed, got %+v", tc.name, result.Outcome)
+			}
+			continue
+		}
+		if result.Allowed || result.Outcome == nil || result.Outcome.OutcomeKind != tc.wantKind || result.Outcome.Reason != tc.wantReason {
+			t.Fatalf("%s: expected %s/%s, got %+v", tc.name, tc.wantKind, tc.wantReason, result.Outcome)
+		}
+		if err := result.Outcome.Validate(); err != nil {
+			t.Fatalf("%s: outcome should validate: %v", tc.name, err)
+		}
+	}
+
+	explained := Evaluator{Explain: true, Budgets: evaluator.Budgets}.EvaluateSchedulingPoint(SchedulingPointInput{
+		SessionID: "sess-1",
+		EventID:   "evt-2",
+		Scope:     controlplane.ScopeNodeDispatch,
+		Budget:    &BudgetUsage{TenantID: "tenant-a", TurnTokens: 250, SessionSpendUSD: 0.1},
+	}).Outcome
+	wantCounters := []controlplane.QuotaCounter{{Name: "turn_tokens", Used: 250, Limit: 200}}
+	wantThresholds := []controlplane.ThresholdEvaluation{{Name: "session_spend_usd", Observed: 0.1, Threshold: 0.5}}
+	if explained == nil || explained.Explain == nil || explained.Explain.PolicyRule != RuleBudgetExceeded ||
+		!reflect.DeepEqual(explained.Explain.QuotaCounters, wantCounters) || !reflect.DeepEqual(explained.Explain.Thresholds, wantThresholds) {
+		t.Fatalf("expected budget explain counters and thresholds, got %+v", explained)
+	}
+}
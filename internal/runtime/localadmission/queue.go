@@ -0,0 +1,55 @@
+package localadmission
+
+import "fmt"
+
+// QueueEstimateMethod identifies the derivation recorded with every queue estimate so later
+// accuracy analysis can compare methods.
+const QueueEstimateMethod = "fifo_slots_mean_service/v1"
+
+// QueueState is the admission queue snapshot used to estimate a deferred turn's wait.
+type QueueState struct {
+	// TurnsAhead counts turns already queued ahead of this one.
+	TurnsAhead int64
+	// ActiveSlots is the number of turns the runtime serves concurrently.
+	ActiveSlots int64
+	// MeanTurnServiceMS is the recent mean turn service time.
+	MeanTurnServiceMS int64
+}
+
+// Validate enforces queue snapshot invariants.
+func (q QueueState) Validate() error {
+	if q.TurnsAhead < 0 {
+		return fmt.Errorf("turns_ahead must be >=0")
+	}
+	if q.ActiveSlots < 1 {
+		return fmt.Errorf("active_slots must be >=1")
+	}
+	if q.MeanTurnServiceMS < 0 {
+		return fmt.Errorf("mean_turn_service_ms must be >=0")
+	}
+	return nil
+}
+
+// QueueEstimate is the client-facing position/ETA for a deferred turn plus its inputs.
+type QueueEstimate struct {
+	Position        int64
+	EstimatedWaitMS int64
+	Method          string
+	Inputs          QueueState
+}
+
+// EstimateQueue derives a FIFO estimate: the turn is served once every turn ahead of it has
+// drained through the active slots, each taking the mean service time.
+func EstimateQueue(q QueueState) (QueueEstimate, error) {
+	if err := q.Validate(); err != nil {
+		return QueueEstimate{}, err
+	}
+	position := q.TurnsAhead + 1
+	rounds := (position + q.ActiveSlots - 1) / q.ActiveSlots
+	return QueueEstimate{
+		Position:        position,
+		EstimatedWaitMS: rounds * q.MeanTurnServiceMS,
+		Method:          QueueEstimateMethod,
+		Inputs:          q,
+	}, nil
+}
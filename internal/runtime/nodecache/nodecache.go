@@ -0,0 +1,128 @@
+package nodecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const defaultCapacity = 1024
+
+// Key identifies one memoized deterministic node computation. InputHash digests everything
+// the node computes from; the pipeline version scopes the graph the node runs in.
+type Key struct {
+	PipelineVersion string
+	NodeID          string
+	InputHash       string
+}
+
+// Validate enforces required key fields.
+func (k Key) Validate() error {
+	if k.PipelineVersion == "" || k.NodeID == "" || k.InputHash == "" {
+		return fmt.Errorf("pipeline_version, node_id, and input_hash are required")
+	}
+	return nil
+}
+
+// String returns a stable digest suitable for evidence records.
+func (k Key) String() string {
+	return HashInput(k.PipelineVersion, k.NodeID, k.InputHash)
+}
+
+// HashInput returns a deterministic digest of ordered input parts.
+func HashInput(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Entry is one cached node result with the event that produced it.
+type Entry[V any] struct {
+	Key           Key
+	SourceEventID string
+	Value         V
+}
+
+// Cache is a bounded, pipeline-version-scoped memoization store for deterministic nodes.
+// Storing an entry for a new pipeline version drops entries from every other version, so
+// a rollout never serves results computed under a previous graph.
+type Cache[V any] struct {
+	mu              sync.Mutex
+	capacity        int
+	pipelineVersion string
+	entries         map[Key]Entry[V]
+	order           []Key
+}
+
+// New constructs a cache bounded to capacity entries (default 1024).
+func New[V any](capacity int) *Cache[V] {
+	if capacity < 1 {
+		capacity = defaultCapacity
+	}
+	return &Cache[V]{
+		capacity: capacity,
+		entries:  make(map[Key]Entry[V]),
+	}
+}
+
+// Get returns the cached entry for key when present.
+func (c *Cache[V]) Get(key Key) (Entry[V], bool) {
+	if c == nil {
+		return Entry[V]{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+// Put stores value under key, evicting the oldest entry when at capacity.
+func (c *Cache[V]) Put(key Key, sourceEventID string, value V) error {
+	if err := key.Validate(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pipelineVersion != key.PipelineVersion {
+		c.resetLocked()
+		c.pipelineVersion = key.PipelineVersion
+	}
+	if _, exists := c.entries[key]; !exists {
+		if len(c.order) >= c.capacity {
+			oldest := c.order[0]
+			c.order = c.order[1:]
+			delete(c.entries, oldest)
+		}
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = Entry[V]{Key: key, SourceEventID: sourceEventID, Value: value}
+	return nil
+}
+
+// InvalidatePipelineVersion drops all entries for pipelineVersion and returns the count removed.
+func (c *Cache[V]) InvalidatePipelineVersion(pipelineVersion string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pipelineVersion != pipelineVersion {
+		return 0
+	}
+	removed := len(c.entries)
+	c.resetLocked()
+	return removed
+}
+
+// Len reports the number of cached entries.
+func (c *Cache[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func (c *Cache[V]) resetLocked() {
+	c.entries = make(map[Key]Entry[V])
+	c.order = nil
+	c.pipelineVersion = ""
+}
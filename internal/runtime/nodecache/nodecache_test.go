@@ -0,0 +1,67 @@
+package nodecache
+
+import "testing"
+
+func TestCacheGetPutAndEviction(t *testing.T) {
+	t.Parallel()
+
+	cache := New[string](2)
+	keyA := Key{PipelineVersion: "pipeline-v1", NodeID: "normalize", InputHash: HashInput("a")}
+	keyB := Key{PipelineVersion: "pipeline-v1", NodeID: "normalize", InputHash: HashInput("b")}
+	keyC := Key{PipelineVersion: "pipeline-v1", NodeID: "normalize", InputHash: HashInput("c")}
+
+	for i, key := range []Key{keyA, keyB, keyC} {
+		if err := cache.Put(key, "evt-"+string(rune('a'+i)), key.InputHash); err != nil {
+			t.Fatalf("unexpected put error: %v", err)
+		}
+	}
+	if _, ok := cache.Get(keyA); ok {
+		t.Fatalf("expected oldest entry to be evicted at capacity")
+	}
+	entry, ok := cache.Get(keyC)
+	if !ok || entry.SourceEventID != "evt-c" || entry.Value != keyC.InputHash {
+		t.Fatalf("expected cached entry for keyC, got %+v ok=%v", entry, ok)
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("expected bounded cache length 2, got %d", cache.Len())
+	}
+}
+
+func TestCachePipelineVersionInvalidation(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int](0)
+	v1 := Key{PipelineVersion: "pipeline-v1", NodeID: "safety", InputHash: HashInput("x")}
+	v2 := v1
+	v2.PipelineVersion = "pipeline-v2"
+
+	if err := cache.Put(v1, "evt-1", 1); err != nil {
+		t.Fatalf("unexpected put error: %v", err)
+	}
+	if err := cache.Put(v2, "evt-2", 2); err != nil {
+		t.Fatalf("unexpected put error: %v", err)
+	}
+	if _, ok := cache.Get(v1); ok {
+		t.Fatalf("expected new pipeline version to invalidate prior version entries")
+	}
+	if removed := cache.InvalidatePipelineVersion("pipeline-v1"); removed != 0 {
+		t.Fatalf("expected no removal for inactive version, got %d", removed)
+	}
+	if removed := cache.InvalidatePipelineVersion("pipeline-v2"); removed != 1 || cache.Len() != 0 {
+		t.Fatalf("expected explicit invalidation to drop entries, removed=%d len=%d", removed, cache.Len())
+	}
+}
+
+func TestKeyValidate(t *testing.T) {
+	t.Parallel()
+
+	if err := New[int](1).Put(Key{NodeID: "n"}, "evt", 1); err == nil {
+		t.Fatalf("expected incomplete key to be rejected")
+	}
+	a := Key{PipelineVersion: "v", NodeID: "n", InputHash: "i"}
+	b := a
+	b.InputHash = "j"
+	if a.String() == b.String() {
+		t.Fatalf("expected input hash to change key digest")
+	}
+}
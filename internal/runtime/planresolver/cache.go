@@ -0,0 +1,265 @@
+package planresolver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+)
+
+// DefaultCacheMaxEntries bounds the resolved-plan cache when no capacity is configured.
+const DefaultCacheMaxEntries = 256
+
+// NotificationKind classifies control-plane changes that invalidate cached plans.
+type NotificationKind string
+
+const (
+	NotificationPublish  NotificationKind = "publish"
+	NotificationRollback NotificationKind = "rollback"
+)
+
+// Notification reports a control-plane publish or rollback. An empty PipelineVersion
+// invalidates every cached plan.
+type Notification struct {
+	Kind            NotificationKind
+	PipelineVersion string
+}
+
+// Validate enforces notification fields.
+func (n Notification) Validate() error {
+	if n.Kind != NotificationPublish && n.Kind != NotificationRollback {
+		return fmt.Errorf("notification kind must be publish|rollback")
+	}
+	return nil
+}
+
+// CacheKey identifies a turn-invariant plan body: the pipeline version plus a hash of the
+// policy and snapshot inputs it was materialized from.
+type CacheKey struct {
+	PipelineVersion string
+	InputsHash      string
+}
+
+// CacheStats summarizes cache effectiveness.
+type CacheStats struct {
+	Hits          int64
+	Misses        int64
+	Invalidations int64
+	Entries       int
+}
+
+// HitRate returns hits over lookups, or 0 before the first lookup.
+func (s CacheStats) HitRate() float64 {
+	lookups := s.Hits + s.Misses
+	if lookups == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(lookups)
+}
+
+// Cache memoizes turn-invariant plan bodies so per-turn resolution only stamps turn identity.
+// It is safe for concurrent use and is shared by copies of the arbiter that hold it.
+type Cache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[CacheKey]controlplane.ResolvedTurnPlan
+	order      []CacheKey
+	stats      CacheStats
+}
+
+// NewCache returns a plan cache holding at most maxEntries bodies (DefaultCacheMaxEntries when <=0).
+func NewCache(maxEntries int) *Cache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheMaxEntries
+	}
+	return &Cache{
+		maxEntries: maxEntries,
+		entries:    make(map[CacheKey]controlplane.ResolvedTurnPlan),
+	}
+}
+
+// Resolve returns the same plan Resolver.Resolve would, reusing a cached body when the
+// pipeline version and policy snapshot inputs match a previous turn.
+func (c *Cache) Resolve(in Input) (controlplane.ResolvedTurnPlan, error) {
+	if in.FailMaterialization {
+		return controlplane.ResolvedTurnPlan{}, ErrMaterializationFailed
+	}
+	if in.TurnID == "" || in.PipelineVersion == "" {
+		return controlplane.ResolvedTurnPlan{}, fmt.Errorf("turn_id and pipeline_version are required")
+	}
+
+	key, err := cacheKey(in)
+	if err != nil {
+		return controlplane.ResolvedTurnPlan{}, err
+	}
+
+	c.mu.Lock()
+	body, hit := c.entries[key]
+	if hit {
+		c.stats.Hits++
+	} else {
+		c.stats.Misses++
+	}
+	c.mu.Unlock()
+	emitLookup(in, hit)
+
+	if !hit {
+		body, err = materializePlanBody(in)
+		if err != nil {
+			return controlplane.ResolvedTurnPlan{}, err
+		}
+		c.store(key, clonePlan(body))
+	}
+	return stampTurnIdentity(clonePlan(body), in)
+}
+
+// Notify applies a control-plane publish or rollback, dropping affected cached plans.
+func (c *Cache) Notify(n Notification) (int, error) {
+	if err := n.Validate(); err != nil {
+		return 0, err
+	}
+	c.mu.Lock()
+	kept := c.order[:0]
+	dropped := 0
+	for _, key := range c.order {
+		if n.PipelineVersion == "" || key.PipelineVersion == n.PipelineVersion {
+			delete(c.entries, key)
+			dropped++
+			continue
+		}
+		kept = append(kept, key)
+	}
+	c.order = kept
+	c.stats.Invalidations += int64(dropped)
+	c.mu.Unlock()
+
+	telemetry.DefaultEmitter().EmitMetric(
+		telemetry.MetricPlanCacheInvalidations,
+		float64(dropped),
+		"1",
+		map[string]string{"notification": string(n.Kind)},
+		telemetry.Correlation{PipelineVersion: n.PipelineVersion, EmittedBy: "RK-04"},
+	)
+	return dropped, nil
+}
+
+// Stats returns a snapshot of cache counters.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	return stats
+}
+
+func (c *Cache) store(key CacheKey, body controlplane.ResolvedTurnPlan) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	// Evict oldest-first so capacity pressure is deterministic.
+	for len(c.order) >= c.maxEntries {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.entries[key] = body
+	c.order = append(c.order, key)
+}
+
+func emitLookup(in Input, hit bool) {
+	value := 0.0
+	if hit {
+		value = 1
+	}
+	telemetry.DefaultEmitter().EmitMetric(
+		telemetry.MetricPlanCacheHit,
+		value,
+		"1",
+		nil,
+		telemetry.Correlation{TurnID: in.TurnID, PipelineVersion: in.PipelineVersion, AuthorityEpoch: in.AuthorityEpoch, EmittedBy: "RK-04"},
+	)
+}
+
+// cacheKey hashes every turn-invariant input; turn identity and authority epoch are excluded
+// because they are stamped per turn.
+func cacheKey(in Input) (CacheKey, error) {
+	invariant := struct {
+		GraphDefinitionRef     string                          `json:"graph_definition_ref"`
+		ExecutionProfile       string                          `json:"execution_profile"`
+		SnapshotProvenance     controlplane.SnapshotProvenance `json:"snapshot_provenance"`
+		AllowedAdaptiveActions []string                        `json:"allowed_adaptive_actions"`
+		STTEndpointing         *controlplane.STTEndpointing    `json:"stt_endpointing"`
+		AudioEnhancement       *controlplane.AudioEnhancement  `json:"audio_enhancement"`
+		Locale                 *controlplane.SessionLocale     `json:"locale"`
+		Summarization          *controlplane.Summarization     `json:"summarization"`
+		OutputLimits           *controlplane.OutputLimits      `json:"output_limits"`
+	}{
+		GraphDefinitionRef:     in.GraphDefinitionRef,
+		ExecutionProfile:       in.ExecutionProfile,
+		SnapshotProvenance:     in.SnapshotProvenance,
+		AllowedAdaptiveActions: in.AllowedAdaptiveActions,
+		STTEndpointing:         in.STTEndpointing,
+		AudioEnhancement:       in.AudioEnhancement,
+		Locale:                 in.Locale,
+		Summarization:          in.Summarization,
+		OutputLimits:           in.OutputLimits,
+	}
+	raw, err := json.Marshal(invariant)
+	if err != nil {
+		return CacheKey{}, fmt.Errorf("hash plan cache inputs: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	return CacheKey{PipelineVersion: in.PipelineVersion, InputsHash: hex.EncodeToString(sum[:])}, nil
+}
+
+// clonePlan deep-copies the reference fields of a plan so cached bodies stay immutable.
+func clonePlan(in controlplane.ResolvedTurnPlan) controlplane.ResolvedTurnPlan {
+	out := in
+	out.ProviderBindings = make(map[string]string, len(in.ProviderBindings))
+	for k, v := range in.ProviderBindings {
+		out.ProviderBindings[k] = v
+	}
+	out.EdgeBufferPolicies = make(map[string]controlplane.EdgeBufferPolicy, len(in.EdgeBufferPolicies))
+	for k, policy := range in.EdgeBufferPolicies {
+		policy.Watermarks.QueueItems = cloneThreshold(policy.Watermarks.QueueItems)
+		policy.Watermarks.QueueMS = cloneThreshold(policy.Watermarks.QueueMS)
+		if policy.SyncDropPolicy != nil {
+			syncDrop := *policy.SyncDropPolicy
+			policy.SyncDropPolicy = &syncDrop
+		}
+		out.EdgeBufferPolicies[k] = policy
+	}
+	if in.FlowControl.DegradeLadder != nil {
+		ladder := *in.FlowControl.DegradeLadder
+		ladder.Rungs = append([]controlplane.DegradeRung(nil), ladder.Rungs...)
+		out.FlowControl.DegradeLadder = &ladder
+	}
+	out.AllowedAdaptiveActions = append([]string{}, in.AllowedAdaptiveActions...)
+	out.RecordingPolicy.AllowedReplayModes = append([]string(nil), in.RecordingPolicy.AllowedReplayModes...)
+	if in.STTEndpointing != nil {
+		endpointing := *in.STTEndpointing
+		out.STTEndpointing = &endpointing
+	}
+	out.AudioEnhancement = cloneAudioEnhancement(in.AudioEnhancement)
+	if in.Locale != nil {
+		locale := *in.Locale
+		locale.STTLanguageHints = append([]string(nil), in.Locale.STTLanguageHints...)
+		out.Locale = &locale
+	}
+	out.Summarization = cloneSummarization(in.Summarization)
+	out.OutputLimits = cloneOutputLimits(in.OutputLimits)
+	return out
+}
+
+func cloneThreshold(in *controlplane.WatermarkThreshold) *controlplane.WatermarkThreshold {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
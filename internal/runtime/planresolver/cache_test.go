@@ -0,0 +1,186 @@
+package planresolver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+)
+
+func cacheTestInput(turnID string, pipelineVersion string, policySnapshot string) Input {
+	return Input{
+		TurnID:             turnID,
+		PipelineVersion:    pipelineVersion,
+		GraphDefinitionRef: "graph/default",
+		ExecutionProfile:   "simple",
+		AuthorityEpoch:     3,
+		SnapshotProvenance: controlplane.SnapshotProvenance{
+			RoutingViewSnapshot:       "routing-view/v1",
+			AdmissionPolicySnapshot:   "admission-policy/v1",
+			ABICompatibilitySnapshot:  "abi-compat/v1",
+			VersionResolutionSnapshot: "version-resolution/v1",
+			PolicyResolutionSnapshot:  policySnapshot,
+			ProviderHealthSnapshot:    "provider-health/v1",
+		},
+		AllowedAdaptiveActions: []string{"retry"},
+		Locale:                 &controlplane.SessionLocale{Locale: "en-GB"},
+	}
+}
+
+func TestCacheResolveMatchesUncachedResolver(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(0)
+	for _, turnID := range []string{"turn-1", "turn-2", "turn-3"} {
+		in := cacheTestInput(turnID, "pipeline-v1", "policy-resolution/v1")
+		cached, err := cache.Resolve(in)
+		if err != nil {
+			t.Fatalf("%s: unexpected cached resolve error: %v", turnID, err)
+		}
+		uncached, err := Resolver{}.Resolve(in)
+		if err != nil {
+			t.Fatalf("%s: unexpected resolve error: %v", turnID, err)
+		}
+		if !reflect.DeepEqual(cached, uncached) {
+			t.Fatalf("%s: expected cached plan to match uncached plan, got %+v want %+v", turnID, cached, uncached)
+		}
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Fatalf("expected 2 hits and 1 miss, got %+v", stats)
+	}
+	if rate := stats.HitRate(); rate < 0.66 || rate > 0.67 {
+		t.Fatalf("expected 2/3 hit rate, got %v", rate)
+	}
+}
+
+func TestCacheKeysOnPolicySnapshotsAndIsolatesCallers(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(0)
+	first, err := cache.Resolve(cacheTestInput("turn-1", "pipeline-v1", "policy-resolution/v1"))
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	first.ProviderBindings["stt"] = "mutated"
+	first.AllowedAdaptiveActions[0] = "mutated"
+	first.Locale.STTLanguageHints[0] = "mutated"
+	first.FlowControl.DegradeLadder.Rungs[0].SoftLimit = 1
+
+	second, err := cache.Resolve(cacheTestInput("turn-2", "pipeline-v1", "policy-resolution/v1"))
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	if second.ProviderBindings["stt"] != "default-stt" || second.AllowedAdaptiveActions[0] != "retry" ||
+		second.Locale.STTLanguageHints[0] != "en-GB" || second.FlowControl.DegradeLadder.Rungs[0].SoftLimit != 50 {
+		t.Fatalf("expected cached body to be isolated from caller mutation, got %+v", second)
+	}
+
+	if _, err := cache.Resolve(cacheTestInput("turn-3", "pipeline-v1", "policy-resolution/v2")); err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	if stats := cache.Stats(); stats.Misses != 2 || stats.Entries != 2 {
+		t.Fatalf("expected a new policy snapshot to miss, got %+v", stats)
+	}
+}
+
+func TestCacheNotifyInvalidatesByPipelineVersion(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		note        Notification
+		wantDropped int
+		wantErr     bool
+	}{
+		{name: "publish one version", note: Notification{Kind: NotificationPublish, PipelineVersion: "pipeline-v1"}, wantDropped: 1},
+		{name: "rollback all versions", note: Notification{Kind: NotificationRollback}, wantDropped: 2},
+		{name: "unknown version", note: Notification{Kind: NotificationPublish, PipelineVersion: "pipeline-v9"}},
+		{name: "invalid kind", note: Notification{Kind: "drain"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		cache := NewCache(0)
+		for _, version := range []string{"pipeline-v1", "pipeline-v2"} {
+			if _, err := cache.Resolve(cacheTestInput("turn-1", version, "policy-resolution/v1")); err != nil {
+				t.Fatalf("%s: unexpected resolve error: %v", tc.name, err)
+			}
+		}
+		dropped, err := cache.Notify(tc.note)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("%s: expected error=%v, got %v", tc.name, tc.wantErr, err)
+		}
+		if dropped != tc.wantDropped {
+			t.Fatalf("%s: expected %d dropped, got %d", tc.name, tc.wantDropped, dropped)
+		}
+		stats := cache.Stats()
+		if stats.Entries != 2-tc.wantDropped || stats.Invalidations != int64(tc.wantDropped) {
+			t.Fatalf("%s: unexpected stats after notify: %+v", tc.name, stats)
+		}
+	}
+}
+
+func TestCacheEvictsOldestAtCapacity(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(2)
+	for _, version := range []string{"pipeline-v1", "pipeline-v2", "pipeline-v3"} {
+		if _, err := cache.Resolve(cacheTestInput("turn-1", version, "policy-resolution/v1")); err != nil {
+			t.Fatalf("unexpected resolve error: %v", err)
+		}
+	}
+	if _, err := cache.Resolve(cacheTestInput("turn-2", "pipeline-v3", "policy-resolution/v1")); err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	if _, err := cache.Resolve(cacheTestInput("turn-2", "pipeline-v1", "policy-resolution/v1")); err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 4 || stats.Entries != 2 {
+		t.Fatalf("expected oldest entry to be evicted, got %+v", stats)
+	}
+}
+
+func TestPublishWatcherInvalidatesOnNewPublish(t *testing.T) {
+	t.Parallel()
+
+	observer := &stubObserver{publishedAtMS: 1000}
+	cache := NewCache(0)
+	watcher := &PublishWatcher{Observer: observer, Cache: cache}
+	if _, err := cache.Resolve(cacheTestInput("turn-1", "pipeline-v1", "policy-resolution/v1")); err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+
+	steps := []struct {
+		publishedAtMS int64
+		wantNotified  bool
+	}{
+		{publishedAtMS: 1000},
+		{publishedAtMS: 1000},
+		{publishedAtMS: 2000, wantNotified: true},
+	}
+	for i, step := range steps {
+		observer.publishedAtMS = step.publishedAtMS
+		notified, err := watcher.Poll()
+		if err != nil {
+			t.Fatalf("poll %d: unexpected error: %v", i, err)
+		}
+		if notified != step.wantNotified {
+			t.Fatalf("poll %d: expected notified=%v, got %v", i, step.wantNotified, notified)
+		}
+	}
+	if stats := cache.Stats(); stats.Entries != 0 || stats.Invalidations != 1 {
+		t.Fatalf("expected publish to invalidate cached plans, got %+v", stats)
+	}
+}
+
+type stubObserver struct {
+	publishedAtMS int64
+}
+
+func (s *stubObserver) ObserveSnapshots() ([]distribution.SnapshotObservation, error) {
+	return []distribution.SnapshotObservation{
+		{Kind: distribution.SnapshotRoutingView, PublishedAtMS: s.publishedAtMS},
+		{Kind: distribution.SnapshotAdmission, PublishedAtMS: s.publishedAtMS - 10},
+	}, nil
+}
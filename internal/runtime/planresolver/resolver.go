@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 	runtimedeterminism "github.com/tiger/realtime-speech-pipeline/internal/runtime/determinism"
@@ -12,6 +13,10 @@ import (
 
 var ErrMaterializationFailed = errors.New("resolved turn plan materialization failed")
 
+// ErrLocaleInconsistent reports a session locale whose prompt language, TTS voice, and STT
+// hints do not agree, or a locale with no default profile.
+var ErrLocaleInconsistent = errors.New("session locale inconsistent")
+
 // Input contains deterministic fields used to freeze turn execution.
 type Input struct {
 	TurnID                 string
@@ -22,6 +27,58 @@ type Input struct {
 	SnapshotProvenance     controlplane.SnapshotProvenance
 	FailMaterialization    bool
 	AllowedAdaptiveActions []string
+	// STTEndpointing overrides DefaultSTTEndpointing for the pipeline version when set.
+	STTEndpointing *controlplane.STTEndpointing
+	// AudioEnhancement enables the pre-STT enhancement node for the pipeline version when set.
+	AudioEnhancement *controlplane.AudioEnhancement
+	// Locale is the session-level locale selection; nil leaves provider language defaults.
+	Locale *controlplane.SessionLocale
+	// Summarization enables the session summarization node for the pipeline version when set.
+	Summarization *controlplane.Summarization
+	// OutputLimits caps assistant output length for the pipeline version when set.
+	OutputLimits *controlplane.OutputLimits
+}
+
+// DefaultLocaleProfiles are the joint prompt-language/voice/STT-hint defaults per locale.
+var DefaultLocaleProfiles = map[string]controlplane.SessionLocale{
+	"en-US": {LLMPromptLanguage: "en", TTSVoice: "en-US-Chirp3-HD-Achernar", TTSVoiceLocale: "en-US", STTLanguageHints: []string{"en-US"}},
+	"en-GB": {LLMPromptLanguage: "en", TTSVoice: "en-GB-Chirp3-HD-Achernar", TTSVoiceLocale: "en-GB", STTLanguageHints: []string{"en-GB", "en-US"}},
+	"es-ES": {LLMPromptLanguage: "es", TTSVoice: "es-ES-Chirp3-HD-Achernar", TTSVoiceLocale: "es-ES", STTLanguageHints: []string{"es-ES"}},
+	"es-MX": {LLMPromptLanguage: "es", TTSVoice: "es-US-Chirp3-HD-Achernar", TTSVoiceLocale: "es-US", STTLanguageHints: []string{"es-MX", "es-US"}},
+	"fr-FR": {LLMPromptLanguage: "fr", TTSVoice: "fr-FR-Chirp3-HD-Achernar", TTSVoiceLocale: "fr-FR", STTLanguageHints: []string{"fr-FR"}},
+	"de-DE": {LLMPromptLanguage: "de", TTSVoice: "de-DE-Chirp3-HD-Achernar", TTSVoiceLocale: "de-DE", STTLanguageHints: []string{"de-DE"}},
+	"pt-BR": {LLMPromptLanguage: "pt", TTSVoice: "pt-BR-Chirp3-HD-Achernar", TTSVoiceLocale: "pt-BR", STTLanguageHints: []string{"pt-BR"}},
+	"ja-JP": {LLMPromptLanguage: "ja", TTSVoice: "ja-JP-Chirp3-HD-Achernar", TTSVoiceLocale: "ja-JP", STTLanguageHints: []string{"ja-JP"}},
+	"hi-IN": {LLMPromptLanguage: "hi", TTSVoice: "hi-IN-Chirp3-HD-Achernar", TTSVoiceLocale: "hi-IN", STTLanguageHints: []string{"hi-IN", "en-IN"}},
+}
+
+// DefaultSTTEndpointing is the execution-profile endpointing applied without a pipeline override.
+var DefaultSTTEndpointing = controlplane.STTEndpointing{
+	SilenceDurationMS: 500,
+	MinUtteranceMS:    200,
+	MaxUtteranceMS:    15000,
+	NoSpeechTimeoutMS: 5000,
+}
+
+// DefaultEdgeBufferPolicy is the execution-profile buffer policy resolved plans carry under the
+// "default" edge_buffer_policies key.
+func DefaultEdgeBufferPolicy() controlplane.EdgeBufferPolicy {
+	return controlplane.EdgeBufferPolicy{
+		Strategy:                 controlplane.BufferStrategyDrop,
+		MaxQueueItems:            64,
+		MaxQueueMS:               300,
+		MaxQueueBytes:            262144,
+		MaxLatencyContributionMS: 120,
+		Watermarks: controlplane.EdgeWatermarks{
+			QueueItems: &controlplane.WatermarkThreshold{High: 48, Low: 24},
+		},
+		LaneHandling: controlplane.LaneHandling{
+			DataLane:      "drop",
+			ControlLane:   "non_blocking_priority",
+			TelemetryLane: "best_effort_drop",
+		},
+		DefaultingSource: "execution_profile_default",
+	}
 }
 
 // Resolver materializes immutable ResolvedTurnPlan artifacts.
@@ -36,6 +93,16 @@ func (Resolver) Resolve(in Input) (controlplane.ResolvedTurnPlan, error) {
 		return controlplane.ResolvedTurnPlan{}, fmt.Errorf("turn_id and pipeline_version are required")
 	}
 
+	plan, err := materializePlanBody(in)
+	if err != nil {
+		return controlplane.ResolvedTurnPlan{}, err
+	}
+	return stampTurnIdentity(plan, in)
+}
+
+// materializePlanBody builds the turn-invariant part of a plan: everything except the
+// turn identity, authority epoch, and determinism context.
+func materializePlanBody(in Input) (controlplane.ResolvedTurnPlan, error) {
 	if in.GraphDefinitionRef == "" {
 		in.GraphDefinitionRef = "graph/default"
 	}
@@ -47,21 +114,15 @@ func (Resolver) Resolve(in Input) (controlplane.ResolvedTurnPlan, error) {
 		in.AllowedAdaptiveActions = []string{}
 	}
 
-	determinismCtx, err := runtimedeterminism.NewService().IssueContext(
-		hashPlanIdentity(in.TurnID, in.PipelineVersion, in.GraphDefinitionRef, in.ExecutionProfile, in.AuthorityEpoch),
-		in.AuthorityEpoch,
-	)
+	locale, err := resolveLocale(in.Locale)
 	if err != nil {
 		return controlplane.ResolvedTurnPlan{}, err
 	}
 
 	plan := controlplane.ResolvedTurnPlan{
-		TurnID:             in.TurnID,
 		PipelineVersion:    in.PipelineVersion,
-		PlanHash:           hashPlanIdentity(in.TurnID, in.PipelineVersion, in.GraphDefinitionRef, in.ExecutionProfile, in.AuthorityEpoch),
 		GraphDefinitionRef: in.GraphDefinitionRef,
 		ExecutionProfile:   in.ExecutionProfile,
-		AuthorityEpoch:     in.AuthorityEpoch,
 		Budgets: controlplane.Budgets{
 			TurnBudgetMS:        5000,
 			NodeBudgetMSDefault: 1500,
@@ -70,22 +131,7 @@ func (Resolver) Resolve(in Input) (controlplane.ResolvedTurnPlan, error) {
 		},
 		ProviderBindings: map[string]string{"stt": "default-stt", "llm": "default-llm", "tts": "default-tts"},
 		EdgeBufferPolicies: map[string]controlplane.EdgeBufferPolicy{
-			"default": {
-				Strategy:                 controlplane.BufferStrategyDrop,
-				MaxQueueItems:            64,
-				MaxQueueMS:               300,
-				MaxQueueBytes:            262144,
-				MaxLatencyContributionMS: 120,
-				Watermarks: controlplane.EdgeWatermarks{
-					QueueItems: &controlplane.WatermarkThreshold{High: 48, Low: 24},
-				},
-				LaneHandling: controlplane.LaneHandling{
-					DataLane:      "drop",
-					ControlLane:   "non_blocking_priority",
-					TelemetryLane: "best_effort_drop",
-				},
-				DefaultingSource: "execution_profile_default",
-			},
+			"default": DefaultEdgeBufferPolicy(),
 		},
 		FlowControl: controlplane.FlowControl{
 			ModeByLane: controlplane.ModeByLane{
@@ -103,6 +149,16 @@ func (Resolver) Resolve(in Input) (controlplane.ResolvedTurnPlan, error) {
 				ControlLane:   "none",
 				TelemetryLane: "sample",
 			},
+			// The degrade ladder steps down between the data-lane low and high watermarks.
+			DegradeLadder: &controlplane.DegradeLadder{
+				Rungs: []controlplane.DegradeRung{
+					{Step: controlplane.DegradeReduceTelemetryDetail, SoftLimit: 50},
+					{Step: controlplane.DegradeBypassAudioEnhancement, SoftLimit: 60},
+					{Step: controlplane.DegradeDropSTTPartials, SoftLimit: 70},
+					{Step: controlplane.DegradeShortenLLMMaxTokens, SoftLimit: 85, LLMMaxTokens: 64},
+				},
+				HardLimit: 100,
+			},
 		},
 		AllowedAdaptiveActions: in.AllowedAdaptiveActions,
 		SnapshotProvenance:     in.SnapshotProvenance,
@@ -110,8 +166,27 @@ func (Resolver) Resolve(in Input) (controlplane.ResolvedTurnPlan, error) {
 			RecordingLevel:     "L0",
 			AllowedReplayModes: []string{"replay_decisions"},
 		},
-		Determinism: determinismCtx,
+		STTEndpointing:   effectiveSTTEndpointing(in.STTEndpointing),
+		AudioEnhancement: cloneAudioEnhancement(in.AudioEnhancement),
+		Locale:           locale,
+		Summarization:    cloneSummarization(in.Summarization),
+		OutputLimits:     cloneOutputLimits(in.OutputLimits),
+	}
+	return plan, nil
+}
+
+// stampTurnIdentity binds the turn-scoped identity and determinism context onto a
+// turn-invariant plan body and validates the result.
+func stampTurnIdentity(plan controlplane.ResolvedTurnPlan, in Input) (controlplane.ResolvedTurnPlan, error) {
+	planHash := hashPlanIdentity(in.TurnID, plan.PipelineVersion, plan.GraphDefinitionRef, plan.ExecutionProfile, in.AuthorityEpoch)
+	determinismCtx, err := runtimedeterminism.NewService().IssueContext(planHash, in.AuthorityEpoch)
+	if err != nil {
+		return controlplane.ResolvedTurnPlan{}, err
 	}
+	plan.TurnID = in.TurnID
+	plan.PlanHash = planHash
+	plan.AuthorityEpoch = in.AuthorityEpoch
+	plan.Determinism = determinismCtx
 
 	if err := plan.Validate(); err != nil {
 		return controlplane.ResolvedTurnPlan{}, err
@@ -120,6 +195,87 @@ func (Resolver) Resolve(in Input) (controlplane.ResolvedTurnPlan, error) {
 	return plan, nil
 }
 
+func effectiveSTTEndpointing(override *controlplane.STTEndpointing) *controlplane.STTEndpointingProvenance {
+	if override != nil {
+		return &controlplane.STTEndpointingProvenance{STTEndpointing: *override, DefaultingSource: "pipeline_version"}
+	}
+	return &controlplane.STTEndpointingProvenance{STTEndpointing: DefaultSTTEndpointing, DefaultingSource: "execution_profile_default"}
+}
+
+// resolveLocale overlays session overrides on the locale default profile and validates that
+// prompt language, TTS voice, and STT hints agree with the locale language.
+func resolveLocale(in *controlplane.SessionLocale) (*controlplane.ResolvedLocale, error) {
+	if in == nil {
+		return nil, nil
+	}
+	if err := in.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLocaleInconsistent, err)
+	}
+	resolved := controlplane.ResolvedLocale{DefaultingSource: "locale_default"}
+	if profile, ok := DefaultLocaleProfiles[in.Locale]; ok {
+		resolved.SessionLocale = profile
+		resolved.STTLanguageHints = append([]string(nil), profile.STTLanguageHints...)
+	}
+	resolved.Locale = in.Locale
+	if in.LLMPromptLanguage != "" {
+		resolved.LLMPromptLanguage = in.LLMPromptLanguage
+		resolved.DefaultingSource = "session"
+	}
+	if in.TTSVoice != "" {
+		resolved.TTSVoice = in.TTSVoice
+		resolved.TTSVoiceLocale = in.TTSVoiceLocale
+		if resolved.TTSVoiceLocale == "" {
+			resolved.TTSVoiceLocale = voiceLocale(in.TTSVoice)
+		}
+		resolved.DefaultingSource = "session"
+	}
+	if len(in.STTLanguageHints) > 0 {
+		resolved.STTLanguageHints = append([]string(nil), in.STTLanguageHints...)
+		resolved.DefaultingSource = "session"
+	}
+	if err := resolved.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLocaleInconsistent, err)
+	}
+	return &resolved, nil
+}
+
+// voiceLocale derives the locale prefix of voice names such as es-US-Chirp3-HD-Achernar.
+func voiceLocale(voice string) string {
+	parts := strings.SplitN(voice, "-", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	tag := parts[0] + "-" + parts[1]
+	if _, ok := controlplane.LocaleLanguage(tag); !ok {
+		return ""
+	}
+	return tag
+}
+
+func cloneAudioEnhancement(in *controlplane.AudioEnhancement) *controlplane.AudioEnhancement {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
+
+func cloneSummarization(in *controlplane.Summarization) *controlplane.Summarization {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
+
+func cloneOutputLimits(in *controlplane.OutputLimits) *controlplane.OutputLimits {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
+
 func hashPlanIdentity(turnID, pipelineVersion, graphRef, profile string, epoch int64) string {
 	s := fmt.Sprintf("%s|%s|%s|%s|%d", turnID, pipelineVersion, graphRef, profile, epoch)
 	sum := sha256.Sum256([]byte(s))
@@ -1,6 +1,7 @@
 package planresolver
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -92,3 +93,189 @@ func TestCT004ResolvedTurnPlanDeterministicIdentity(t *testing.T) {
 		t.Fatalf("expected plan hash to change when authority epoch changes")
 	}
 }
+
+func TestResolvedTurnPlanRecordsEffectiveSTTEndpointing(t *testing.T) {
+	t.Parallel()
+
+	override := controlplane.STTEndpointing{SilenceDurationMS: 800, MinUtteranceMS: 100, MaxUtteranceMS: 20000, NoSpeechTimeoutMS: 8000}
+	tests := []struct {
+		name       string
+		override   *controlplane.STTEndpointing
+		want       controlplane.STTEndpointing
+		wantSource string
+	}{
+		{name: "profile default", want: DefaultSTTEndpointing, wantSource: "execution_profile_default"},
+		{name: "pipeline version override", override: &override, want: override, wantSource: "pipeline_version"},
+	}
+	for _, tc := range tests {
+		plan, err := Resolver{}.Resolve(Input{
+			TurnID:          "turn-endpointing-1",
+			PipelineVersion: "pipeline-v1",
+			SnapshotProvenance: controlplane.SnapshotProvenance{
+				RoutingViewSnapshot:       "routing-view/v1",
+				AdmissionPolicySnapshot:   "admission-policy/v1",
+				ABICompatibilitySnapshot:  "abi-compat/v1",
+				VersionResolutionSnapshot: "version-resolution/v1",
+				PolicyResolutionSnapshot:  "policy-resolution/v1",
+				ProviderHealthSnapshot:    "provider-health/v1",
+			},
+			STTEndpointing: tc.override,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected resolve error: %v", tc.name, err)
+		}
+		if plan.STTEndpointing == nil || plan.STTEndpointing.STTEndpointing != tc.want || plan.STTEndpointing.DefaultingSource != tc.wantSource {
+			t.Fatalf("%s: expected endpointing %+v from %s, got %+v", tc.name, tc.want, tc.wantSource, plan.STTEndpointing)
+		}
+	}
+
+	invalid := controlplane.STTEndpointing{SilenceDurationMS: 0, MaxUtteranceMS: 1000, NoSpeechTimeoutMS: 1000}
+	if _, err := (Resolver{}).Resolve(Input{TurnID: "turn-endpointing-2", PipelineVersion: "pipeline-v1", SnapshotProvenance: controlplane.SnapshotProvenance{
+		RoutingViewSnapshot:       "routing-view/v1",
+		AdmissionPolicySnapshot:   "admission-policy/v1",
+		ABICompatibilitySnapshot:  "abi-compat/v1",
+		VersionResolutionSnapshot: "version-resolution/v1",
+		PolicyResolutionSnapshot:  "policy-resolution/v1",
+		ProviderHealthSnapshot:    "provider-health/v1",
+	}, STTEndpointing: &invalid}); err == nil {
+		t.Fatalf("expected invalid endpointing override to fail plan validation")
+	}
+}
+
+func TestResolvedTurnPlanResolvesSessionLocale(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		locale    *controlplane.SessionLocale
+		want      *controlplane.ResolvedLocale
+		shouldErr bool
+	}{
+		{name: "no locale", locale: nil, want: nil},
+		{
+			name:   "locale default profile",
+			locale: &controlplane.SessionLocale{Locale: "es-MX"},
+			want: &controlplane.ResolvedLocale{
+				SessionLocale:    controlplane.SessionLocale{Locale: "es-MX", LLMPromptLanguage: "es", TTSVoice: "es-US-Chirp3-HD-Achernar", TTSVoiceLocale: "es-US", STTLanguageHints: []string{"es-MX", "es-US"}},
+				DefaultingSource: "locale_default",
+			},
+		},
+		{
+			name:   "session voice override derives voice locale",
+			locale: &controlplane.SessionLocale{Locale: "fr-FR", TTSVoice: "fr-CA-Neural2-A"},
+			want: &controlplane.ResolvedLocale{
+				SessionLocale:    controlplane.SessionLocale{Locale: "fr-FR", LLMPromptLanguage: "fr", TTSVoice: "fr-CA-Neural2-A", TTSVoiceLocale: "fr-CA", STTLanguageHints: []string{"fr-FR"}},
+				DefaultingSource: "session",
+			},
+		},
+		{
+			name: "unprofiled locale with full overrides",
+			locale: &controlplane.SessionLocale{
+				Locale: "it-IT", LLMPromptLanguage: "it", TTSVoice: "Bianca", TTSVoiceLocale: "it-IT", STTLanguageHints: []string{"it-IT"},
+			},
+			want: &controlplane.ResolvedLocale{
+				SessionLocale:    controlplane.SessionLocale{Locale: "it-IT", LLMPromptLanguage: "it", TTSVoice: "Bianca", TTSVoiceLocale: "it-IT", STTLanguageHints: []string{"it-IT"}},
+				DefaultingSource: "session",
+			},
+		},
+		{name: "voice in another language", locale: &controlplane.SessionLocale{Locale: "de-DE", TTSVoice: "en-US-Neural2-F"}, shouldErr: true},
+		{name: "prompt language mismatch", locale: &controlplane.SessionLocale{Locale: "ja-JP", LLMPromptLanguage: "en"}, shouldErr: true},
+		{name: "stt hint mismatch", locale: &controlplane.SessionLocale{Locale: "pt-BR", STTLanguageHints: []string{"es-ES", "pt-BR"}}, shouldErr: true},
+		{name: "unprofiled locale without overrides", locale: &controlplane.SessionLocale{Locale: "it-IT"}, shouldErr: true},
+		{name: "voice without locale prefix", locale: &controlplane.SessionLocale{Locale: "en-US", TTSVoice: "Joanna"}, shouldErr: true},
+		{name: "malformed locale", locale: &controlplane.SessionLocale{Locale: "english"}, shouldErr: true},
+	}
+	for _, tc := range tests {
+		plan, err := Resolver{}.Resolve(Input{TurnID: "turn-locale", PipelineVersion: "pipeline-v1", SnapshotProvenance: testSnapshotProvenance(), Locale: tc.locale})
+		if tc.shouldErr {
+			if !errors.Is(err, ErrLocaleInconsistent) {
+				t.Fatalf("%s: expected locale inconsistency error, got %v", tc.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected resolve error: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(plan.Locale, tc.want) {
+			t.Fatalf("%s: expected locale %+v, got %+v", tc.name, tc.want, plan.Locale)
+		}
+	}
+}
+
+func testSnapshotProvenance() controlplane.SnapshotProvenance {
+	return controlplane.SnapshotProvenance{
+		RoutingViewSnapshot:       "routing-view/v1",
+		AdmissionPolicySnapshot:   "admission-policy/v1",
+		ABICompatibilitySnapshot:  "abi-compat/v1",
+		VersionResolutionSnapshot: "version-resolution/v1",
+		PolicyResolutionSnapshot:  "policy-resolution/v1",
+		ProviderHealthSnapshot:    "provider-health/v1",
+	}
+}
+
+func TestResolvedTurnPlanCarriesAudioEnhancement(t *testing.T) {
+	t.Parallel()
+
+	provenance := controlplane.SnapshotProvenance{
+		RoutingViewSnapshot:       "routing-view/v1",
+		AdmissionPolicySnapshot:   "admission-policy/v1",
+		ABICompatibilitySnapshot:  "abi-compat/v1",
+		VersionResolutionSnapshot: "version-resolution/v1",
+		PolicyResolutionSnapshot:  "policy-resolution/v1",
+		ProviderHealthSnapshot:    "provider-health/v1",
+	}
+	enhancement := &controlplane.AudioEnhancement{Enabled: true, Engine: "rnnoise", Quality: "high"}
+	plan, err := Resolver{}.Resolve(Input{TurnID: "turn-enhance-1", PipelineVersion: "pipeline-v1", SnapshotProvenance: provenance, AudioEnhancement: enhancement})
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	if plan.AudioEnhancement == nil || *plan.AudioEnhancement != *enhancement || plan.AudioEnhancement == enhancement {
+		t.Fatalf("expected frozen copy of audio enhancement %+v, got %+v", enhancement, plan.AudioEnhancement)
+	}
+
+	unset, err := Resolver{}.Resolve(Input{TurnID: "turn-enhance-2", PipelineVersion: "pipeline-v1", SnapshotProvenance: provenance})
+	if err != nil || unset.AudioEnhancement != nil {
+		t.Fatalf("expected no audio enhancement without pipeline setting, got %+v err=%v", unset.AudioEnhancement, err)
+	}
+
+	invalid := &controlplane.AudioEnhancement{Enabled: true, Engine: "speex", Quality: "low"}
+	if _, err := (Resolver{}).Resolve(Input{TurnID: "turn-enhance-3", PipelineVersion: "pipeline-v1", SnapshotProvenance: provenance, AudioEnhancement: invalid}); err == nil {
+		t.Fatalf("expected invalid audio enhancement to fail plan validation")
+	}
+}
+
+func TestResolvedTurnPlanCarriesSummarization(t *testing.T) {
+	t.Parallel()
+
+	summarization := &controlplane.Summarization{IntervalTurns: 4, ProviderID: "llm-anthropic", MaxSummaryTokens: 200}
+	plan, err := Resolver{}.Resolve(Input{TurnID: "turn-summary-1", PipelineVersion: "pipeline-v1", SnapshotProvenance: testSnapshotProvenance(), Summarization: summarization})
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	if plan.Summarization == nil || *plan.Summarization != *summarization || plan.Summarization == summarization {
+		t.Fatalf("expected frozen copy of summarization %+v, got %+v", summarization, plan.Summarization)
+	}
+
+	invalid := &controlplane.Summarization{IntervalTurns: 0, ProviderID: "llm-anthropic", MaxSummaryTokens: 200}
+	if _, err := (Resolver{}).Resolve(Input{TurnID: "turn-summary-2", PipelineVersion: "pipeline-v1", SnapshotProvenance: testSnapshotProvenance(), Summarization: invalid}); err == nil {
+		t.Fatalf("expected invalid summarization to fail plan validation")
+	}
+}
+
+func TestResolvedTurnPlanCarriesOutputLimits(t *testing.T) {
+	t.Parallel()
+
+	limits := &controlplane.OutputLimits{MaxLLMOutputTokens: 256, MaxTTSAudioMS: 30_000}
+	plan, err := Resolver{}.Resolve(Input{TurnID: "turn-limits-1", PipelineVersion: "pipeline-v1", SnapshotProvenance: testSnapshotProvenance(), OutputLimits: limits})
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	if plan.OutputLimits == nil || *plan.OutputLimits != *limits || plan.OutputLimits == limits {
+		t.Fatalf("expected frozen copy of output limits %+v, got %+v", limits, plan.OutputLimits)
+	}
+
+	invalid := &controlplane.OutputLimits{MaxLLMOutputTokens: -1}
+	if _, err := (Resolver{}).Resolve(Input{TurnID: "turn-limits-2", PipelineVersion: "pipeline-v1", SnapshotProvenance: testSnapshotProvenance(), OutputLimits: invalid}); err == nil {
+		t.Fatalf("expected invalid output limits to fail plan validation")
+	}
+}
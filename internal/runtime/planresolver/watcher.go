@@ -0,0 +1,46 @@
+package planresolver
+
+import (
+	"fmt"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+)
+
+// PublishWatcher turns control-plane distribution publishes into cache invalidations. The CP
+// has no push channel yet, so each poll compares tracked snapshot publish times against the
+// last poll; rollbacks rewrite the distribution artifact and surface the same way.
+type PublishWatcher struct {
+	Observer distribution.SnapshotObserver
+	Cache    *Cache
+
+	lastPublishedAtMS int64
+}
+
+// Poll observes the distribution and invalidates every cached plan when any tracked snapshot
+// was published after the previous poll. The first poll only records the baseline.
+func (w *PublishWatcher) Poll() (bool, error) {
+	if w.Observer == nil || w.Cache == nil {
+		return false, fmt.Errorf("publish watcher requires observer and cache")
+	}
+	observations, err := w.Observer.ObserveSnapshots()
+	if err != nil {
+		return false, fmt.Errorf("observe control-plane snapshots: %w", err)
+	}
+	var latest int64
+	for _, observation := range observations {
+		if observation.PublishedAtMS > latest {
+			latest = observation.PublishedAtMS
+		}
+	}
+	previous := w.lastPublishedAtMS
+	if latest > previous {
+		w.lastPublishedAtMS = latest
+	}
+	if previous == 0 || latest <= previous {
+		return false, nil
+	}
+	if _, err := w.Cache.Notify(Notification{Kind: NotificationPublish}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,181 @@
+package prelude
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+)
+
+// EndpointKind classifies why the VAD endpointer closed a listening window.
+type EndpointKind string
+
+const (
+	// EndpointUtteranceEnd marks trailing silence after a qualifying utterance.
+	EndpointUtteranceEnd EndpointKind = "utterance_end"
+	// EndpointMaxUtterance marks an utterance force-ended at max_utterance_ms.
+	EndpointMaxUtterance EndpointKind = "max_utterance"
+	// EndpointNoSpeechTimeout marks a window where no speech started in time.
+	EndpointNoSpeechTimeout EndpointKind = "no_speech_timeout"
+)
+
+const (
+	// DefaultSpeechRMS is the PCM16 frame RMS at or above which captured audio counts as speech.
+	DefaultSpeechRMS = 500
+	// audioFrameMS is the energy-VAD frame length EndpointAudio classifies captured audio in.
+	audioFrameMS = 20
+)
+
+// VADFrame is one voice-activity observation at a runtime timestamp.
+type VADFrame struct {
+	TimestampMS int64
+	Speech      bool
+}
+
+// Endpoint is one emulated STT endpointing decision.
+type Endpoint struct {
+	Kind          EndpointKind
+	StartMS       int64
+	EndMS         int64
+	UtteranceMS   int64
+	DecidedAtMS   int64
+	DroppedShorts int
+}
+
+// Endpointer emulates STT endpointing from VAD frames for providers without server-side
+// endpointing. Frames must arrive in non-decreasing timestamp order.
+type Endpointer struct {
+	cfg           controlplane.STTEndpointing
+	windowStartMS int64
+	speaking      bool
+	speechStartMS int64
+	lastSpeechMS  int64
+	lastFrameMS   int64
+	droppedShorts int
+}
+
+// NewEndpointer starts a listening window at startMS.
+func NewEndpointer(cfg controlplane.STTEndpointing, startMS int64) (*Endpointer, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Endpointer{cfg: cfg, windowStartMS: startMS, lastFrameMS: startMS}, nil
+}
+
+// Observe folds one frame and returns an endpoint when the frame closes the window.
+// After an endpoint the next window starts at the deciding frame.
+func (e *Endpointer) Observe(frame VADFrame) (Endpoint, bool, error) {
+	if frame.TimestampMS < e.lastFrameMS {
+		return Endpoint{}, false, fmt.Errorf("vad frame timestamp regression: %d < %d", frame.TimestampMS, e.lastFrameMS)
+	}
+	e.lastFrameMS = frame.TimestampMS
+
+	if !e.speaking {
+		if frame.Speech {
+			e.speaking = true
+			e.speechStartMS = frame.TimestampMS
+			e.lastSpeechMS = frame.TimestampMS
+			return Endpoint{}, false, nil
+		}
+		if frame.TimestampMS-e.windowStartMS >= e.cfg.NoSpeechTimeoutMS {
+			return e.close(EndpointNoSpeechTimeout, e.windowStartMS, e.windowStartMS, frame.TimestampMS), true, nil
+		}
+		return Endpoint{}, false, nil
+	}
+
+	if frame.Speech {
+		e.lastSpeechMS = frame.TimestampMS
+		if frame.TimestampMS-e.speechStartMS >= e.cfg.MaxUtteranceMS {
+			return e.close(EndpointMaxUtterance, e.speechStartMS, frame.TimestampMS, frame.TimestampMS), true, nil
+		}
+		return Endpoint{}, false, nil
+	}
+	if frame.TimestampMS-e.lastSpeechMS < e.cfg.SilenceDurationMS {
+		return Endpoint{}, false, nil
+	}
+	if e.lastSpeechMS-e.speechStartMS < e.cfg.MinUtteranceMS {
+		// Too short to be an utterance: treat it as noise and keep listening in the same window.
+		e.speaking = false
+		e.droppedShorts++
+		return Endpoint{}, false, nil
+	}
+	return e.close(EndpointUtteranceEnd, e.speechStartMS, e.lastSpeechMS, frame.TimestampMS), true, nil
+}
+
+func (e *Endpointer) close(kind EndpointKind, startMS int64, endMS int64, decidedAtMS int64) Endpoint {
+	endpoint := Endpoint{
+		Kind:          kind,
+		StartMS:       startMS,
+		EndMS:         endMS,
+		UtteranceMS:   endMS - startMS,
+		DecidedAtMS:   decidedAtMS,
+		DroppedShorts: e.droppedShorts,
+	}
+	e.speaking = false
+	e.windowStartMS = decidedAtMS
+	e.droppedShorts = 0
+	return endpoint
+}
+
+// EndpointAudio applies STT endpointing to captured audio for providers without server-side
+// endpointing. It classifies the audio into 20 ms energy-VAD frames (speech at or above
+// speechRMS), runs the endpointer from the start of the audio, and returns the first endpoint
+// with the utterance audio it bounds: leading noise, speech bursts shorter than
+// min_utterance_ms, and audio after the utterance's trailing silence are dropped, and an
+// utterance longer than max_utterance_ms is cut there. Audio ending mid-utterance closes it at
+// the last speech frame. Without a qualifying utterance the endpoint is a no-speech timeout
+// and the utterance is nil.
+func EndpointAudio(cfg controlplane.STTEndpointing, pcm []int16, sampleRateHz int, speechRMS float64) (Endpoint, []int16, error) {
+	if sampleRateHz < 1 {
+		return Endpoint{}, nil, fmt.Errorf("sample_rate_hz must be >=1")
+	}
+	e, err := NewEndpointer(cfg, 0)
+	if err != nil {
+		return Endpoint{}, nil, err
+	}
+	sampleAt := func(ms int64) int {
+		return min(int(ms*int64(sampleRateHz)/1000), len(pcm))
+	}
+	utterance := func(endpoint Endpoint) []int16 {
+		endMS := endpoint.EndMS + audioFrameMS
+		if endpoint.Kind == EndpointMaxUtterance {
+			endMS = endpoint.EndMS
+		}
+		return pcm[sampleAt(endpoint.StartMS):sampleAt(endMS)]
+	}
+	frameSamples := max(sampleRateHz*audioFrameMS/1000, 1)
+	for offset := 0; offset < len(pcm); offset += frameSamples {
+		frame := pcm[offset:min(offset+frameSamples, len(pcm))]
+		endpoint, ok, err := e.Observe(VADFrame{TimestampMS: int64(offset) * 1000 / int64(sampleRateHz), Speech: rms(frame) >= speechRMS})
+		if err != nil {
+			return Endpoint{}, nil, err
+		}
+		if !ok {
+			continue
+		}
+		if endpoint.Kind == EndpointNoSpeechTimeout {
+			return endpoint, nil, nil
+		}
+		return endpoint, utterance(endpoint), nil
+	}
+	endMS := int64(len(pcm)) * 1000 / int64(sampleRateHz)
+	if e.speaking && e.lastSpeechMS-e.speechStartMS >= cfg.MinUtteranceMS {
+		endpoint := e.close(EndpointUtteranceEnd, e.speechStartMS, e.lastSpeechMS, endMS)
+		return endpoint, utterance(endpoint), nil
+	}
+	if e.speaking {
+		e.droppedShorts++
+	}
+	return e.close(EndpointNoSpeechTimeout, e.windowStartMS, e.windowStartMS, endMS), nil, nil
+}
+
+func rms(pcm []int16) float64 {
+	if len(pcm) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, sample := range pcm {
+		sum += float64(sample) * float64(sample)
+	}
+	return math.Sqrt(sum / float64(len(pcm)))
+}
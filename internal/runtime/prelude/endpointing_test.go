@@ -0,0 +1,144 @@
+package prelude
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+)
+
+func TestEndpointerEmulatesEndpointing(t *testing.T) {
+	t.Parallel()
+
+	cfg := controlplane.STTEndpointing{SilenceDurationMS: 300, MinUtteranceMS: 200, MaxUtteranceMS: 1000, NoSpeechTimeoutMS: 2000}
+	speech := func(fromMS, toMS int64) []VADFrame {
+		frames := make([]VADFrame, 0)
+		for ts := fromMS; ts <= toMS; ts += 100 {
+			frames = append(frames, VADFrame{TimestampMS: ts, Speech: true})
+		}
+		return frames
+	}
+	silence := func(fromMS, toMS int64) []VADFrame {
+		frames := make([]VADFrame, 0)
+		for ts := fromMS; ts <= toMS; ts += 100 {
+			frames = append(frames, VADFrame{TimestampMS: ts})
+		}
+		return frames
+	}
+
+	tests := []struct {
+		name   string
+		frames []VADFrame
+		want   Endpoint
+	}{
+		{
+			name:   "trailing silence ends utterance",
+			frames: append(speech(100, 500), silence(600, 900)...),
+			want:   Endpoint{Kind: EndpointUtteranceEnd, StartMS: 100, EndMS: 500, UtteranceMS: 400, DecidedAtMS: 800},
+		},
+		{
+			name:   "max utterance force-ends",
+			frames: speech(100, 1500),
+			want:   Endpoint{Kind: EndpointMaxUtterance, StartMS: 100, EndMS: 1100, UtteranceMS: 1000, DecidedAtMS: 1100},
+		},
+		{
+			name:   "no speech timeout",
+			frames: silence(100, 2500),
+			want:   Endpoint{Kind: EndpointNoSpeechTimeout, DecidedAtMS: 2000},
+		},
+		{
+			name:   "short burst dropped as noise",
+			frames: append(append(append(speech(100, 200), silence(300, 600)...), speech(700, 1000)...), silence(1100, 1400)...),
+			want:   Endpoint{Kind: EndpointUtteranceEnd, StartMS: 700, EndMS: 1000, UtteranceMS: 300, DecidedAtMS: 1300, DroppedShorts: 1},
+		},
+	}
+	for _, tc := range tests {
+		endpointer, err := NewEndpointer(cfg, 0)
+		if err != nil {
+			t.Fatalf("%s: unexpected endpointer error: %v", tc.name, err)
+		}
+		var got *Endpoint
+		for _, frame := range tc.frames {
+			endpoint, closed, err := endpointer.Observe(frame)
+			if err != nil {
+				t.Fatalf("%s: unexpected observe error: %v", tc.name, err)
+			}
+			if closed {
+				got = &endpoint
+				break
+			}
+		}
+		if got == nil || *got != tc.want {
+			t.Fatalf("%s: expected endpoint %+v, got %+v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestEndpointerRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewEndpointer(controlplane.STTEndpointing{SilenceDurationMS: 300, MaxUtteranceMS: 100, MinUtteranceMS: 200, NoSpeechTimeoutMS: 1000}, 0); err == nil {
+		t.Fatalf("expected invalid endpointing config to fail")
+	}
+	endpointer, err := NewEndpointer(controlplane.STTEndpointing{SilenceDurationMS: 300, MaxUtteranceMS: 1000, NoSpeechTimeoutMS: 1000}, 500)
+	if err != nil {
+		t.Fatalf("unexpected endpointer error: %v", err)
+	}
+	if _, _, err := endpointer.Observe(VADFrame{TimestampMS: 100}); err == nil {
+		t.Fatalf("expected timestamp regression to fail")
+	}
+}
+
+func TestEndpointAudioBoundsTheUtterance(t *testing.T) {
+	t.Parallel()
+
+	cfg := controlplane.STTEndpointing{SilenceDurationMS: 300, MinUtteranceMS: 200, MaxUtteranceMS: 1000, NoSpeechTimeoutMS: 2000}
+	// segment is a run of loud or silent audio at 1 kHz, so one sample is one millisecond.
+	type segment struct {
+		ms     int
+		speech bool
+	}
+	tests := []struct {
+		name          string
+		segments      []segment
+		wantKind      EndpointKind
+		wantStartMS   int64
+		wantSamples   int
+		wantDropped   int
+		wantUtterance bool
+	}{
+		{name: "utterance after noise", segments: []segment{{100, false}, {500, true}, {400, false}, {300, true}}, wantKind: EndpointUtteranceEnd, wantStartMS: 100, wantSamples: 500, wantUtterance: true},
+		{name: "short burst dropped", segments: []segment{{100, true}, {400, false}, {400, true}, {400, false}}, wantKind: EndpointUtteranceEnd, wantStartMS: 500, wantSamples: 400, wantDropped: 1, wantUtterance: true},
+		{name: "max utterance cut", segments: []segment{{1500, true}}, wantKind: EndpointMaxUtterance, wantStartMS: 0, wantSamples: 1000, wantUtterance: true},
+		{name: "audio ends mid-utterance", segments: []segment{{200, false}, {300, true}}, wantKind: EndpointUtteranceEnd, wantStartMS: 200, wantSamples: 300, wantUtterance: true},
+		{name: "silence only", segments: []segment{{600, false}}, wantKind: EndpointNoSpeechTimeout},
+		{name: "burst too short at end", segments: []segment{{300, false}, {100, true}}, wantKind: EndpointNoSpeechTimeout, wantDropped: 1},
+	}
+	for _, tc := range tests {
+		var pcm []int16
+		for _, seg := range tc.segments {
+			for range seg.ms {
+				var sample int16
+				if seg.speech {
+					sample = 4000
+				}
+				pcm = append(pcm, sample)
+			}
+		}
+		endpoint, utterance, err := EndpointAudio(cfg, pcm, 1000, DefaultSpeechRMS)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if endpoint.Kind != tc.wantKind || endpoint.DroppedShorts != tc.wantDropped {
+			t.Fatalf("%s: expected %s endpoint dropping %d bursts, got %+v", tc.name, tc.wantKind, tc.wantDropped, endpoint)
+		}
+		if (utterance != nil) != tc.wantUtterance || len(utterance) != tc.wantSamples {
+			t.Fatalf("%s: expected a %d-sample utterance, got %d samples", tc.name, tc.wantSamples, len(utterance))
+		}
+		if tc.wantUtterance && endpoint.StartMS != tc.wantStartMS {
+			t.Fatalf("%s: expected the utterance to start at %d ms, got %+v", tc.name, tc.wantStartMS, endpoint)
+		}
+	}
+	if _, _, err := EndpointAudio(cfg, []int16{1}, 0, DefaultSpeechRMS); err == nil {
+		t.Fatalf("expected a zero sample rate to fail")
+	}
+}
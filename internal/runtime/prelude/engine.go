@@ -3,6 +3,7 @@ package prelude
 import (
 	"fmt"
 
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
 	runtimeeventabi "github.com/tiger/realtime-speech-pipeline/internal/runtime/eventabi"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/identity"
@@ -20,12 +21,15 @@ type Input struct {
 	RuntimeTimestampMS   int64
 	WallClockTimestampMS int64
 	Reason               string
+	// Trigger is the session trigger that opened the turn; its mode is the default reason.
+	Trigger *controlplane.TurnTrigger
 }
 
 // Proposal is a non-authoritative turn-open intent.
 type Proposal struct {
 	Identity identity.Context
 	Signal   eventabi.ControlSignal
+	Trigger  *controlplane.TurnTrigger
 }
 
 // Engine issues deterministic non-authoritative turn-open intent signals.
@@ -60,6 +64,14 @@ func (e Engine) ProposeTurn(in Input) (Proposal, error) {
 	}
 
 	reason := in.Reason
+	if in.Trigger != nil {
+		if err := in.Trigger.Validate(); err != nil {
+			return Proposal{}, err
+		}
+		if reason == "" {
+			reason = "trigger_" + string(in.Trigger.Mode)
+		}
+	}
 	if reason == "" {
 		reason = "session_turn_intent"
 	}
@@ -88,10 +100,15 @@ func (e Engine) ProposeTurn(in Input) (Proposal, error) {
 		return Proposal{}, err
 	}
 
-	return Proposal{
+	proposal := Proposal{
 		Identity: ctx,
 		Signal:   normalized[0],
-	}, nil
+	}
+	if in.Trigger != nil {
+		trigger := *in.Trigger
+		proposal.Trigger = &trigger
+	}
+	return proposal, nil
 }
 
 func nonNegative(v int64) int64 {
@@ -0,0 +1,82 @@
+package prelude
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+)
+
+const (
+	// dcBlockPole is the pole of the DC-blocking high-pass filter (about 25 Hz at 16 kHz).
+	dcBlockPole = 0.99
+	// noiseFloorPercentile is the frame-energy percentile taken as the stationary noise floor.
+	noiseFloorPercentile = 0.1
+	// noiseGateRatio is how far above the noise floor a frame must be to pass unattenuated.
+	noiseGateRatio = 2.0
+)
+
+// enhancementGain is the gain applied to noise-floor frames per quality setting.
+var enhancementGain = map[string]float64{
+	"low":      0.5,
+	"balanced": 0.25,
+	"high":     0.1,
+}
+
+// EnhanceAudio runs in-process noise suppression over captured PCM ahead of STT: a
+// DC-blocking high-pass filter removes rumble and offset, then 20 ms frames whose energy
+// stays near the estimated noise floor are attenuated by the quality setting's gain. The
+// suppressor is a noise gate, not a learned denoiser; the input slice is not modified.
+func EnhanceAudio(cfg controlplane.AudioEnhancement, pcm []int16, sampleRateHz int) ([]int16, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled {
+		return nil, fmt.Errorf("audio enhancement is not enabled")
+	}
+	if sampleRateHz <= 0 {
+		return nil, fmt.Errorf("sample_rate_hz must be >0")
+	}
+	out := make([]int16, len(pcm))
+	var prevIn, prevOut float64
+	for idx, sample := range pcm {
+		x := float64(sample)
+		y := x - prevIn + dcBlockPole*prevOut
+		prevIn, prevOut = x, y
+		out[idx] = clampSample(y)
+	}
+
+	frame := sampleRateHz * audioFrameMS / 1000
+	if frame < 1 || len(out) < 2*frame {
+		return out, nil
+	}
+	energies := make([]float64, 0, len(out)/frame+1)
+	for start := 0; start < len(out); start += frame {
+		energies = append(energies, rms(out[start:min(start+frame, len(out))]))
+	}
+	sorted := append([]float64(nil), energies...)
+	sort.Float64s(sorted)
+	floor := sorted[int(noiseFloorPercentile*float64(len(sorted)-1))]
+	gain := enhancementGain[cfg.Quality]
+	for idx, energy := range energies {
+		if energy > floor*noiseGateRatio {
+			continue
+		}
+		start := idx * frame
+		for sample := start; sample < min(start+frame, len(out)); sample++ {
+			out[sample] = clampSample(float64(out[sample]) * gain)
+		}
+	}
+	return out, nil
+}
+
+func clampSample(v float64) int16 {
+	switch {
+	case v > 32767:
+		return 32767
+	case v < -32768:
+		return -32768
+	default:
+		return int16(v)
+	}
+}
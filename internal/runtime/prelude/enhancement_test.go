@@ -0,0 +1,76 @@
+package prelude
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+)
+
+func TestEnhanceAudioSuppressesNoiseFloor(t *testing.T) {
+	t.Parallel()
+
+	// tone is an alternating-sign tone at amplitude over a DC offset, at 1 kHz so one
+	// sample is one millisecond.
+	tone := func(ms int, amplitude int16, offset int16) []int16 {
+		out := make([]int16, ms)
+		for idx := range out {
+			out[idx] = offset + amplitude
+			if idx%2 == 1 {
+				out[idx] = offset - amplitude
+			}
+		}
+		return out
+	}
+	var pcm []int16
+	pcm = append(pcm, tone(400, 100, 1000)...)
+	pcm = append(pcm, tone(400, 3000, 1000)...)
+	pcm = append(pcm, tone(400, 100, 1000)...)
+
+	tests := []struct {
+		name        string
+		quality     string
+		maxNoiseRMS float64
+	}{
+		{name: "low", quality: "low", maxNoiseRMS: 55},
+		{name: "balanced", quality: "balanced", maxNoiseRMS: 30},
+		{name: "high", quality: "high", maxNoiseRMS: 12},
+	}
+	for _, tc := range tests {
+		cfg := controlplane.AudioEnhancement{Enabled: true, Engine: "rnnoise", Quality: tc.quality}
+		enhanced, err := EnhanceAudio(cfg, pcm, 1000)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if len(enhanced) != len(pcm) || pcm[0] != 1100 {
+			t.Fatalf("%s: expected same-length output without modifying the input", tc.name)
+		}
+		if noise := rms(enhanced[1000:1200]); noise > tc.maxNoiseRMS {
+			t.Fatalf("%s: expected noise floor suppressed below %.0f rms, got %.1f", tc.name, tc.maxNoiseRMS, noise)
+		}
+		if speech := rms(enhanced[500:700]); speech < 2500 {
+			t.Fatalf("%s: expected speech kept, got %.1f rms", tc.name, speech)
+		}
+		var sum float64
+		for _, sample := range enhanced[500:700] {
+			sum += float64(sample)
+		}
+		if mean := sum / 200; mean > 100 || mean < -100 {
+			t.Fatalf("%s: expected dc offset removed, got mean %.1f", tc.name, mean)
+		}
+	}
+
+	invalid := []struct {
+		name string
+		cfg  controlplane.AudioEnhancement
+		rate int
+	}{
+		{name: "disabled", cfg: controlplane.AudioEnhancement{}, rate: 1000},
+		{name: "unknown quality", cfg: controlplane.AudioEnhancement{Enabled: true, Engine: "rnnoise", Quality: "max"}, rate: 1000},
+		{name: "sample rate", cfg: controlplane.AudioEnhancement{Enabled: true, Engine: "rnnoise", Quality: "low"}, rate: 0},
+	}
+	for _, tc := range invalid {
+		if _, err := EnhanceAudio(tc.cfg, pcm, tc.rate); err == nil {
+			t.Fatalf("%s: expected enhancement error", tc.name)
+		}
+	}
+}
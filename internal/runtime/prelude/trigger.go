@@ -0,0 +1,162 @@
+package prelude
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+// TriggerAction reports what a session trigger observation did to capture.
+type TriggerAction string
+
+const (
+	// TriggerNone leaves capture unchanged.
+	TriggerNone TriggerAction = ""
+	// TriggerOpenTurn starts capture; the caller proposes a turn with the returned trigger.
+	TriggerOpenTurn TriggerAction = "open_turn"
+	// TriggerCaptureEnded ends capture; the returned trigger carries the capture end.
+	TriggerCaptureEnded TriggerAction = "capture_ended"
+)
+
+// AudioFrame is one ingress audio observation offered to the session trigger.
+type AudioFrame struct {
+	TimestampMS int64
+	Speech      bool
+	PCM         []byte
+}
+
+// WakeWordDetection is one local wake-word detector hit.
+type WakeWordDetection struct {
+	WakeWord   string
+	Confidence float64
+}
+
+// WakeWordDetector is the local detector used by wake_word triggers.
+type WakeWordDetector interface {
+	DetectWakeWord(AudioFrame) (WakeWordDetection, bool)
+}
+
+// Trigger gates turn opening upstream of the turn arbiter according to the session trigger
+// mode. Observations must arrive in non-decreasing timestamp order.
+type Trigger struct {
+	cfg       controlplane.SessionTrigger
+	detector  WakeWordDetector
+	capturing bool
+	current   controlplane.TurnTrigger
+}
+
+// NewTrigger returns a session trigger; detector is required for wake_word mode.
+func NewTrigger(cfg controlplane.SessionTrigger, detector WakeWordDetector) (*Trigger, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Mode == controlplane.TriggerWakeWord && detector == nil {
+		return nil, fmt.Errorf("wake_word trigger requires a wake-word detector")
+	}
+	return &Trigger{cfg: cfg, detector: detector}, nil
+}
+
+// Capturing reports whether ingress audio currently belongs to an open capture.
+func (t *Trigger) Capturing() bool {
+	return t.capturing
+}
+
+// ObserveControlSignal folds a transport capture_start/capture_end message. Capture messages
+// are rejected outside push_to_talk mode; other signals are ignored.
+func (t *Trigger) ObserveControlSignal(signal eventabi.ControlSignal) (controlplane.TurnTrigger, TriggerAction, error) {
+	if signal.Signal != "capture_start" && signal.Signal != "capture_end" {
+		return controlplane.TurnTrigger{}, TriggerNone, nil
+	}
+	if t.cfg.Mode != controlplane.TriggerPushToTalk {
+		return controlplane.TurnTrigger{}, TriggerNone, fmt.Errorf("%s requires trigger mode %s, session uses %s", signal.Signal, controlplane.TriggerPushToTalk, t.cfg.Mode)
+	}
+	if signal.Signal == "capture_start" {
+		if t.capturing {
+			return controlplane.TurnTrigger{}, TriggerNone, nil
+		}
+		return t.open(controlplane.TurnTrigger{Mode: t.cfg.Mode, TriggeredAtMS: signal.RuntimeTimestampMS})
+	}
+	if !t.capturing {
+		return controlplane.TurnTrigger{}, TriggerNone, nil
+	}
+	return t.end(signal.RuntimeTimestampMS, "capture_end")
+}
+
+// ObserveAudio folds one ingress audio frame: speech onset opens continuous turns, the local
+// detector opens wake_word turns, and push_to_talk capture is force-ended at max_capture_ms.
+func (t *Trigger) ObserveAudio(frame AudioFrame) (controlplane.TurnTrigger, TriggerAction, error) {
+	if t.capturing && frame.TimestampMS < t.current.TriggeredAtMS {
+		return controlplane.TurnTrigger{}, TriggerNone, fmt.Errorf("audio frame timestamp regression: %d < %d", frame.TimestampMS, t.current.TriggeredAtMS)
+	}
+	if t.capturing {
+		if t.cfg.Mode == controlplane.TriggerPushToTalk && t.cfg.MaxCaptureMS > 0 && frame.TimestampMS-t.current.TriggeredAtMS >= t.cfg.MaxCaptureMS {
+			return t.end(frame.TimestampMS, "max_capture")
+		}
+		return controlplane.TurnTrigger{}, TriggerNone, nil
+	}
+
+	switch t.cfg.Mode {
+	case controlplane.TriggerContinuous:
+		if frame.Speech {
+			return t.open(controlplane.TurnTrigger{Mode: t.cfg.Mode, TriggeredAtMS: frame.TimestampMS})
+		}
+	case controlplane.TriggerWakeWord:
+		detection, ok := t.detector.DetectWakeWord(frame)
+		if ok && t.acceptsWakeWord(detection) {
+			return t.open(controlplane.TurnTrigger{
+				Mode:               t.cfg.Mode,
+				TriggeredAtMS:      frame.TimestampMS,
+				WakeWord:           detection.WakeWord,
+				WakeWordConfidence: detection.Confidence,
+			})
+		}
+	}
+	return controlplane.TurnTrigger{}, TriggerNone, nil
+}
+
+// EndCapture ends continuous and wake_word capture at an utterance endpoint. push_to_talk
+// capture only ends on capture_end or max_capture_ms, so endpoints are ignored there.
+func (t *Trigger) EndCapture(endpoint Endpoint) (controlplane.TurnTrigger, TriggerAction, error) {
+	if !t.capturing || t.cfg.Mode == controlplane.TriggerPushToTalk {
+		return controlplane.TurnTrigger{}, TriggerNone, nil
+	}
+	return t.end(endpoint.DecidedAtMS, "endpoint")
+}
+
+func (t *Trigger) acceptsWakeWord(detection WakeWordDetection) bool {
+	if detection.WakeWord == "" || detection.Confidence < t.cfg.WakeWordMinConfidence {
+		return false
+	}
+	if len(t.cfg.WakeWords) == 0 {
+		return true
+	}
+	for _, word := range t.cfg.WakeWords {
+		if strings.EqualFold(strings.TrimSpace(word), strings.TrimSpace(detection.WakeWord)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Trigger) open(trigger controlplane.TurnTrigger) (controlplane.TurnTrigger, TriggerAction, error) {
+	if err := trigger.Validate(); err != nil {
+		return controlplane.TurnTrigger{}, TriggerNone, err
+	}
+	t.capturing = true
+	t.current = trigger
+	return trigger, TriggerOpenTurn, nil
+}
+
+func (t *Trigger) end(atMS int64, reason string) (controlplane.TurnTrigger, TriggerAction, error) {
+	trigger := t.current
+	trigger.CaptureEndAtMS = &atMS
+	trigger.CaptureEndReason = reason
+	if err := trigger.Validate(); err != nil {
+		return controlplane.TurnTrigger{}, TriggerNone, err
+	}
+	t.capturing = false
+	t.current = controlplane.TurnTrigger{}
+	return trigger, TriggerCaptureEnded, nil
+}
@@ -0,0 +1,141 @@
+package prelude
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+type scriptedDetector map[int64]WakeWordDetection
+
+func (d scriptedDetector) DetectWakeWord(frame AudioFrame) (WakeWordDetection, bool) {
+	detection, ok := d[frame.TimestampMS]
+	return detection, ok
+}
+
+func captureSignal(name string, atMS int64) eventabi.ControlSignal {
+	return eventabi.ControlSignal{Signal: name, EmittedBy: "RK-22", RuntimeTimestampMS: atMS}
+}
+
+func TestTriggerPushToTalkCapturesBetweenTransportMessages(t *testing.T) {
+	t.Parallel()
+
+	trigger, err := NewTrigger(controlplane.SessionTrigger{Mode: controlplane.TriggerPushToTalk}, nil)
+	if err != nil {
+		t.Fatalf("unexpected trigger error: %v", err)
+	}
+	if _, action, _ := trigger.ObserveAudio(AudioFrame{TimestampMS: 50, Speech: true}); action != TriggerNone || trigger.Capturing() {
+		t.Fatalf("expected speech without capture_start to be ignored, got %s", action)
+	}
+
+	opened, action, err := trigger.ObserveControlSignal(captureSignal("capture_start", 100))
+	if err != nil || action != TriggerOpenTurn || opened.Mode != controlplane.TriggerPushToTalk || opened.TriggeredAtMS != 100 || !trigger.Capturing() {
+		t.Fatalf("expected capture_start to open a turn, got %+v action=%s err=%v", opened, action, err)
+	}
+	if _, action, _ := trigger.EndCapture(Endpoint{DecidedAtMS: 300}); action != TriggerNone || !trigger.Capturing() {
+		t.Fatalf("expected endpoints to be ignored during push-to-talk capture, got %s", action)
+	}
+
+	ended, action, err := trigger.ObserveControlSignal(captureSignal("capture_end", 900))
+	if err != nil || action != TriggerCaptureEnded || ended.TriggeredAtMS != 100 || ended.CaptureEndAtMS == nil || *ended.CaptureEndAtMS != 900 || ended.CaptureEndReason != "capture_end" || trigger.Capturing() {
+		t.Fatalf("expected capture_end to close capture, got %+v action=%s err=%v", ended, action, err)
+	}
+	if _, action, _ := trigger.ObserveControlSignal(captureSignal("capture_end", 950)); action != TriggerNone {
+		t.Fatalf("expected duplicate capture_end to be ignored, got %s", action)
+	}
+}
+
+func TestTriggerPushToTalkMaxCapture(t *testing.T) {
+	t.Parallel()
+
+	trigger, err := NewTrigger(controlplane.SessionTrigger{Mode: controlplane.TriggerPushToTalk, MaxCaptureMS: 500}, nil)
+	if err != nil {
+		t.Fatalf("unexpected trigger error: %v", err)
+	}
+	trigger.ObserveControlSignal(captureSignal("capture_start", 100))
+	if _, action, _ := trigger.ObserveAudio(AudioFrame{TimestampMS: 599, Speech: true}); action != TriggerNone {
+		t.Fatalf("expected capture to stay open before max_capture_ms, got %s", action)
+	}
+	ended, action, err := trigger.ObserveAudio(AudioFrame{TimestampMS: 600})
+	if err != nil || action != TriggerCaptureEnded || ended.CaptureEndReason != "max_capture" || *ended.CaptureEndAtMS != 600 {
+		t.Fatalf("expected max_capture to end capture, got %+v action=%s err=%v", ended, action, err)
+	}
+}
+
+func TestTriggerContinuousOpensOnSpeechAndEndsAtEndpoint(t *testing.T) {
+	t.Parallel()
+
+	trigger, err := NewTrigger(controlplane.SessionTrigger{Mode: controlplane.TriggerContinuous}, nil)
+	if err != nil {
+		t.Fatalf("unexpected trigger error: %v", err)
+	}
+	if _, action, _ := trigger.ObserveAudio(AudioFrame{TimestampMS: 10}); action != TriggerNone {
+		t.Fatalf("expected silence not to open a turn, got %s", action)
+	}
+	opened, action, _ := trigger.ObserveAudio(AudioFrame{TimestampMS: 20, Speech: true})
+	if action != TriggerOpenTurn || opened.TriggeredAtMS != 20 {
+		t.Fatalf("expected speech onset to open a turn, got %+v action=%s", opened, action)
+	}
+	if _, _, err := trigger.ObserveControlSignal(captureSignal("capture_start", 30)); err == nil {
+		t.Fatalf("expected capture_start outside push-to-talk to be rejected")
+	}
+	ended, action, _ := trigger.EndCapture(Endpoint{Kind: EndpointUtteranceEnd, DecidedAtMS: 700})
+	if action != TriggerCaptureEnded || ended.CaptureEndReason != "endpoint" || *ended.CaptureEndAtMS != 700 {
+		t.Fatalf("expected endpoint to end continuous capture, got %+v action=%s", ended, action)
+	}
+}
+
+func TestTriggerWakeWordUsesLocalDetector(t *testing.T) {
+	t.Parallel()
+
+	detector := scriptedDetector{
+		100: {WakeWord: "hey rspp", Confidence: 0.4},
+		200: {WakeWord: "ok computer", Confidence: 0.95},
+		300: {WakeWord: "Hey RSPP", Confidence: 0.9},
+		400: {WakeWord: "hey rspp", Confidence: 0.99},
+	}
+	trigger, err := NewTrigger(controlplane.SessionTrigger{Mode: controlplane.TriggerWakeWord, WakeWords: []string{"hey rspp"}, WakeWordMinConfidence: 0.6}, detector)
+	if err != nil {
+		t.Fatalf("unexpected trigger error: %v", err)
+	}
+	for _, at := range []int64{50, 100, 200} {
+		if _, action, _ := trigger.ObserveAudio(AudioFrame{TimestampMS: at, Speech: true}); action != TriggerNone {
+			t.Fatalf("expected frame at %d not to open a turn, got %s", at, action)
+		}
+	}
+	opened, action, err := trigger.ObserveAudio(AudioFrame{TimestampMS: 300, Speech: true})
+	if err != nil || action != TriggerOpenTurn || opened.WakeWord != "Hey RSPP" || opened.WakeWordConfidence != 0.9 || opened.TriggeredAtMS != 300 {
+		t.Fatalf("expected configured wake word to open a turn, got %+v action=%s err=%v", opened, action, err)
+	}
+	if _, action, _ := trigger.ObserveAudio(AudioFrame{TimestampMS: 400, Speech: true}); action != TriggerNone {
+		t.Fatalf("expected detector to be bypassed while capturing, got %s", action)
+	}
+}
+
+func TestNewTriggerRequiresDetectorForWakeWord(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewTrigger(controlplane.SessionTrigger{Mode: controlplane.TriggerWakeWord}, nil); err == nil {
+		t.Fatalf("expected wake_word trigger without detector to fail")
+	}
+	if _, err := NewTrigger(controlplane.SessionTrigger{Mode: "clap"}, nil); err == nil {
+		t.Fatalf("expected unknown trigger mode to fail")
+	}
+}
+
+func TestProposeTurnCarriesTrigger(t *testing.T) {
+	t.Parallel()
+
+	trigger := &controlplane.TurnTrigger{Mode: controlplane.TriggerPushToTalk, TriggeredAtMS: 100}
+	proposal, err := NewEngine().ProposeTurn(Input{SessionID: "sess-ptt-1", TurnID: "turn-ptt-1", RuntimeTimestampMS: 100, Trigger: trigger})
+	if err != nil {
+		t.Fatalf("unexpected proposal error: %v", err)
+	}
+	if proposal.Signal.Reason != "trigger_push_to_talk" || proposal.Trigger == nil || proposal.Trigger == trigger || *proposal.Trigger != *trigger {
+		t.Fatalf("expected trigger-derived proposal, got %+v", proposal)
+	}
+	if _, err := NewEngine().ProposeTurn(Input{SessionID: "sess-ptt-1", TurnID: "turn-ptt-2", Trigger: &controlplane.TurnTrigger{Mode: controlplane.TriggerWakeWord}}); err == nil {
+		t.Fatalf("expected invalid trigger to fail proposal")
+	}
+}
@@ -2,6 +2,8 @@ package bootstrap
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/invocation"
@@ -9,36 +11,63 @@ import (
 	llmanthropic "github.com/tiger/realtime-speech-pipeline/providers/llm/anthropic"
 	llmcohere "github.com/tiger/realtime-speech-pipeline/providers/llm/cohere"
 	llmgemini "github.com/tiger/realtime-speech-pipeline/providers/llm/gemini"
+	llmllamacpp "github.com/tiger/realtime-speech-pipeline/providers/llm/llamacpp"
+	s2sopenai "github.com/tiger/realtime-speech-pipeline/providers/s2s/openai"
 	sttassemblyai "github.com/tiger/realtime-speech-pipeline/providers/stt/assemblyai"
+	sttazure "github.com/tiger/realtime-speech-pipeline/providers/stt/azure"
 	sttdeepgram "github.com/tiger/realtime-speech-pipeline/providers/stt/deepgram"
 	sttgoogle "github.com/tiger/realtime-speech-pipeline/providers/stt/google"
+	sttwhispercpp "github.com/tiger/realtime-speech-pipeline/providers/stt/whispercpp"
+	ttsazure "github.com/tiger/realtime-speech-pipeline/providers/tts/azure"
 	ttselevenlabs "github.com/tiger/realtime-speech-pipeline/providers/tts/elevenlabs"
 	ttsgoogle "github.com/tiger/realtime-speech-pipeline/providers/tts/google"
+	ttspiper "github.com/tiger/realtime-speech-pipeline/providers/tts/piper"
 	ttspolly "github.com/tiger/realtime-speech-pipeline/providers/tts/polly"
 )
 
+const (
+	// ProfileEnv selects the provider profile for BuildProfile; it defaults to ProfileMVP.
+	ProfileEnv = "RSPP_PROVIDER_PROFILE"
+
+	// ProfileMVP is the cloud provider catalog.
+	ProfileMVP = "mvp"
+	// ProfileLocal is the offline whisper.cpp/llama.cpp/piper chain, which needs no cloud
+	// credentials.
+	ProfileLocal = "local"
+)
+
 // Options controls provider bootstrap invariants.
 type Options struct {
 	MinProvidersPerModality int
 	MaxProvidersPerModality int
 	MaxAttemptsPerProvider  int
 	MaxCandidateProviders   int
+	// ResponseCache serves repeated identical LLM/TTS invocations, as in replay and regression
+	// runs, without calling live providers; nil disables response caching.
+	ResponseCache *invocation.ResponseCache
+	// CircuitBreaker keeps per-provider breaker state for the runtime instance; nil builds one
+	// with invocation defaults.
+	CircuitBreaker *invocation.CircuitBreaker
 }
 
 // RuntimeProviders contains initialized provider manager components.
 type RuntimeProviders struct {
 	Catalog    registry.Catalog
 	Controller invocation.Controller
+	// CircuitBreaker is the breaker Controller consults, shared by its copies.
+	CircuitBreaker *invocation.CircuitBreaker
 }
 
-// BuildMVPProviders creates the canonical 3x3x3 provider catalog.
+// BuildMVPProviders creates the canonical 3x3x3 provider catalog plus the optional
+// speech-to-speech providers. Azure Speech STT/TTS join the catalog when their enable flags
+// (RSPP_STT_AZURE_ENABLE, RSPP_TTS_AZURE_ENABLE) are set.
 func BuildMVPProviders() (RuntimeProviders, error) {
 	return BuildMVPProvidersWithOptions(Options{})
 }
 
 // BuildMVPProvidersWithOptions creates providers with explicit options.
 func BuildMVPProvidersWithOptions(opts Options) (RuntimeProviders, error) {
-	adapters := make([]contracts.Adapter, 0, 9)
+	adapters := make([]contracts.Adapter, 0, 12)
 
 	constructors := []func() (contracts.Adapter, error){
 		sttdeepgram.NewAdapterFromEnv,
@@ -50,6 +79,12 @@ func BuildMVPProvidersWithOptions(opts Options) (RuntimeProviders, error) {
 		ttselevenlabs.NewAdapterFromEnv,
 		ttsgoogle.NewAdapterFromEnv,
 		ttspolly.NewAdapterFromEnv,
+		s2sopenai.NewAdapterFromEnv,
+	}
+	for _, optional := range OptionalProviders() {
+		if optional.Enabled {
+			constructors = append(constructors, optional.constructor)
+		}
 	}
 
 	for _, constructor := range constructors {
@@ -63,8 +98,80 @@ func BuildMVPProvidersWithOptions(opts Options) (RuntimeProviders, error) {
 	return BuildWithAdapters(adapters, opts)
 }
 
+// BuildLocalProviders creates the offline one-provider-per-modality catalog: whisper.cpp STT,
+// a local llama.cpp server LLM, and piper TTS.
+func BuildLocalProviders() (RuntimeProviders, error) {
+	constructors := []func() (contracts.Adapter, error){
+		sttwhispercpp.NewAdapterFromEnv,
+		llmllamacpp.NewAdapterFromEnv,
+		ttspiper.NewAdapterFromEnv,
+	}
+	adapters := make([]contracts.Adapter, 0, len(constructors))
+	for _, constructor := range constructors {
+		adapter, err := constructor()
+		if err != nil {
+			return RuntimeProviders{}, err
+		}
+		adapters = append(adapters, adapter)
+	}
+	return BuildWithAdapters(adapters, Options{MinProvidersPerModality: 1, MaxProvidersPerModality: 1})
+}
+
+// ProfileFromEnv returns the profile named by ProfileEnv, defaulting to ProfileMVP.
+func ProfileFromEnv() string {
+	profile := strings.ToLower(strings.TrimSpace(os.Getenv(ProfileEnv)))
+	if profile == "" {
+		return ProfileMVP
+	}
+	return profile
+}
+
+// BuildProfile creates the provider catalog for a named profile.
+func BuildProfile(profile string) (RuntimeProviders, error) {
+	switch profile {
+	case ProfileMVP:
+		return BuildMVPProviders()
+	case ProfileLocal:
+		return BuildLocalProviders()
+	default:
+		return RuntimeProviders{}, fmt.Errorf("unknown provider profile %q (want %s or %s)", profile, ProfileMVP, ProfileLocal)
+	}
+}
+
+// OptionalProvider is a provider that joins the MVP catalog only when its enable flag is set.
+type OptionalProvider struct {
+	ProviderID  string
+	Modality    contracts.Modality
+	EnableEnv   string
+	Enabled     bool
+	constructor func() (contracts.Adapter, error)
+}
+
+// OptionalProviders lists the flag-gated MVP providers with their current enabled state.
+func OptionalProviders() []OptionalProvider {
+	return []OptionalProvider{
+		{ProviderID: sttazure.ProviderID, Modality: contracts.ModalitySTT, EnableEnv: sttazure.EnableEnv, Enabled: sttazure.EnabledFromEnv(), constructor: sttazure.NewAdapterFromEnv},
+		{ProviderID: ttsazure.ProviderID, Modality: contracts.ModalityTTS, EnableEnv: ttsazure.EnableEnv, Enabled: ttsazure.EnabledFromEnv(), constructor: ttsazure.NewAdapterFromEnv},
+	}
+}
+
 // BuildWithAdapters wires registry+controller for a given adapter set.
 func BuildWithAdapters(adapters []contracts.Adapter, opts Options) (RuntimeProviders, error) {
+	opts = normalizeOptions(opts)
+	catalog, err := registry.NewCatalog(adapters)
+	if err != nil {
+		return RuntimeProviders{}, err
+	}
+	if err := catalog.ValidateCoverage(opts.MinProvidersPerModality, opts.MaxProvidersPerModality); err != nil {
+		return RuntimeProviders{}, err
+	}
+
+	controller := invocation.NewControllerWithConfig(catalog, controllerConfig(opts))
+
+	return RuntimeProviders{Catalog: catalog, Controller: controller, CircuitBreaker: opts.CircuitBreaker}, nil
+}
+
+func normalizeOptions(opts Options) Options {
 	if opts.MinProvidersPerModality < 1 {
 		opts.MinProvidersPerModality = 3
 	}
@@ -77,21 +184,19 @@ func BuildWithAdapters(adapters []contracts.Adapter, opts Options) (RuntimeProvi
 	if opts.MaxCandidateProviders < 1 {
 		opts.MaxCandidateProviders = opts.MaxProvidersPerModality
 	}
-
-	catalog, err := registry.NewCatalog(adapters)
-	if err != nil {
-		return RuntimeProviders{}, err
-	}
-	if err := catalog.ValidateCoverage(opts.MinProvidersPerModality, opts.MaxProvidersPerModality); err != nil {
-		return RuntimeProviders{}, err
+	if opts.CircuitBreaker == nil {
+		opts.CircuitBreaker = invocation.NewCircuitBreaker(invocation.CircuitBreakerConfig{})
 	}
+	return opts
+}
 
-	controller := invocation.NewControllerWithConfig(catalog, invocation.Config{
+func controllerConfig(opts Options) invocation.Config {
+	return invocation.Config{
 		MaxAttemptsPerProvider: opts.MaxAttemptsPerProvider,
 		MaxCandidateProviders:  opts.MaxCandidateProviders,
-	})
-
-	return RuntimeProviders{Catalog: catalog, Controller: controller}, nil
+		ResponseCache:          opts.ResponseCache,
+		CircuitBreaker:         opts.CircuitBreaker,
+	}
 }
 
 // Summary returns deterministic provider counts by modality.
@@ -1,6 +1,7 @@
 package bootstrap
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
@@ -96,3 +97,110 @@ func TestBuildWithAdaptersDefaultCoverageRejectsAboveFive(t *testing.T) {
 		t.Fatalf("expected default bootstrap coverage to reject >5 providers per modality")
 	}
 }
+
+func TestBuildMVPProvidersRegistersAzureBehindEnableFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		sttFlag  string
+		ttsFlag  string
+		wantSTT  bool
+		wantTTS  bool
+		sttCount int
+		ttsCount int
+	}{
+		{name: "disabled by default", sttCount: 3, ttsCount: 3},
+		{name: "stt only", sttFlag: "1", wantSTT: true, sttCount: 4, ttsCount: 3},
+		{name: "both enabled", sttFlag: "true", ttsFlag: "1", wantSTT: true, wantTTS: true, sttCount: 4, ttsCount: 4},
+	}
+	for _, tc := range tests {
+		t.Setenv("RSPP_STT_AZURE_ENABLE", tc.sttFlag)
+		t.Setenv("RSPP_TTS_AZURE_ENABLE", tc.ttsFlag)
+		runtimeProviders, err := BuildMVPProviders()
+		if err != nil {
+			t.Fatalf("%s: unexpected bootstrap error: %v", tc.name, err)
+		}
+		stt, err := runtimeProviders.Catalog.ProviderIDs(contracts.ModalitySTT)
+		if err != nil {
+			t.Fatalf("%s: unexpected stt lookup error: %v", tc.name, err)
+		}
+		tts, err := runtimeProviders.Catalog.ProviderIDs(contracts.ModalityTTS)
+		if err != nil {
+			t.Fatalf("%s: unexpected tts lookup error: %v", tc.name, err)
+		}
+		if len(stt) != tc.sttCount || len(tts) != tc.ttsCount {
+			t.Fatalf("%s: expected stt=%d tts=%d providers, got %v %v", tc.name, tc.sttCount, tc.ttsCount, stt, tts)
+		}
+		if _, ok := runtimeProviders.Catalog.Adapter(contracts.ModalitySTT, "stt-azure-speech"); ok != tc.wantSTT {
+			t.Fatalf("%s: expected azure stt registered=%t", tc.name, tc.wantSTT)
+		}
+		if _, ok := runtimeProviders.Catalog.Adapter(contracts.ModalityTTS, "tts-azure-speech"); ok != tc.wantTTS {
+			t.Fatalf("%s: expected azure tts registered=%t", tc.name, tc.wantTTS)
+		}
+		for _, optional := range OptionalProviders() {
+			_, registered := runtimeProviders.Catalog.Adapter(optional.Modality, optional.ProviderID)
+			if optional.Enabled != registered {
+				t.Fatalf("%s: expected optional provider %s enabled=%t to match registration, got %t", tc.name, optional.ProviderID, optional.Enabled, registered)
+			}
+		}
+	}
+}
+
+func TestBuildProfileSelectsCatalog(t *testing.T) {
+	tests := []struct {
+		name      string
+		profile   string
+		wantSTT   []string
+		wantError bool
+	}{
+		{name: "default profile", wantSTT: []string{"stt-assemblyai", "stt-deepgram", "stt-google"}},
+		{name: "local profile", profile: " Local ", wantSTT: []string{"stt-whisper-cpp"}},
+		{name: "unknown profile", profile: "edge", wantError: true},
+	}
+	for _, tc := range tests {
+		t.Setenv(ProfileEnv, tc.profile)
+		t.Setenv("RSPP_STT_AZURE_ENABLE", "")
+		runtimeProviders, err := BuildProfile(ProfileFromEnv())
+		if tc.wantError {
+			if err == nil {
+				t.Fatalf("%s: expected profile error", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected bootstrap error: %v", tc.name, err)
+		}
+		stt, err := runtimeProviders.Catalog.ProviderIDs(contracts.ModalitySTT)
+		if err != nil {
+			t.Fatalf("%s: unexpected stt lookup error: %v", tc.name, err)
+		}
+		if strings.Join(stt, ",") != strings.Join(tc.wantSTT, ",") {
+			t.Fatalf("%s: expected stt providers %v, got %v", tc.name, tc.wantSTT, stt)
+		}
+	}
+}
+
+func TestBuildLocalProvidersCoversChain(t *testing.T) {
+	t.Parallel()
+
+	runtimeProviders, err := BuildLocalProviders()
+	if err != nil {
+		t.Fatalf("unexpected bootstrap error: %v", err)
+	}
+	for modality, providerID := range map[contracts.Modality]string{
+		contracts.ModalitySTT: "stt-whisper-cpp",
+		contracts.ModalityLLM: "llm-llama-cpp",
+		contracts.ModalityTTS: "tts-piper",
+	} {
+		adapter, ok := runtimeProviders.Catalog.Adapter(modality, providerID)
+		if !ok {
+			t.Fatalf("expected %s provider %s", modality, providerID)
+		}
+		if _, ok := adapter.(contracts.StreamingAdapter); !ok {
+			t.Fatalf("expected %s to stream", providerID)
+		}
+	}
+	summary, err := Summary(runtimeProviders.Catalog)
+	if err != nil || summary != "providers initialized: stt=1 llm=1 tts=1" {
+		t.Fatalf("unexpected summary %q (err=%v)", summary, err)
+	}
+}
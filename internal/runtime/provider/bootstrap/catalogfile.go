@@ -0,0 +1,453 @@
+package bootstrap
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/providerhealth"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/invocation"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+	llmanthropic "github.com/tiger/realtime-speech-pipeline/providers/llm/anthropic"
+	llmcohere "github.com/tiger/realtime-speech-pipeline/providers/llm/cohere"
+	llmgemini "github.com/tiger/realtime-speech-pipeline/providers/llm/gemini"
+	llmllamacpp "github.com/tiger/realtime-speech-pipeline/providers/llm/llamacpp"
+	s2sopenai "github.com/tiger/realtime-speech-pipeline/providers/s2s/openai"
+	sttassemblyai "github.com/tiger/realtime-speech-pipeline/providers/stt/assemblyai"
+	sttazure "github.com/tiger/realtime-speech-pipeline/providers/stt/azure"
+	sttdeepgram "github.com/tiger/realtime-speech-pipeline/providers/stt/deepgram"
+	sttgoogle "github.com/tiger/realtime-speech-pipeline/providers/stt/google"
+	sttwhispercpp "github.com/tiger/realtime-speech-pipeline/providers/stt/whispercpp"
+	ttsazure "github.com/tiger/realtime-speech-pipeline/providers/tts/azure"
+	ttselevenlabs "github.com/tiger/realtime-speech-pipeline/providers/tts/elevenlabs"
+	ttsgoogle "github.com/tiger/realtime-speech-pipeline/providers/tts/google"
+	ttspiper "github.com/tiger/realtime-speech-pipeline/providers/tts/piper"
+	ttspolly "github.com/tiger/realtime-speech-pipeline/providers/tts/polly"
+)
+
+const (
+	// CatalogFileEnv points the runtime at a declarative provider catalog file; when set it
+	// replaces the hardcoded provider profile.
+	CatalogFileEnv = "RSPP_PROVIDER_CATALOG_FILE"
+	// CatalogFileSchemaVersion is the supported provider catalog file schema.
+	CatalogFileSchemaVersion = "rspp-provider-catalog/v1"
+)
+
+// Catalog audit actions.
+const (
+	CatalogActionLoaded         = "catalog_loaded"
+	CatalogActionReloaded       = "catalog_reloaded"
+	CatalogActionReloadRejected = "catalog_reload_rejected"
+	CatalogActionAdded          = "provider_added"
+	CatalogActionRemoved        = "provider_removed"
+	CatalogActionEnabled        = "provider_enabled"
+	CatalogActionDisabled       = "provider_disabled"
+	CatalogActionUpdated        = "provider_updated"
+)
+
+// credentialsRefPattern accepts env:<NAME> references; secrets are never written inline.
+var credentialsRefPattern = regexp.MustCompile(`^env:[A-Z_][A-Z0-9_]*$`)
+
+// CatalogFile is the declarative provider catalog.
+type CatalogFile struct {
+	SchemaVersion string         `json:"schema_version"`
+	Providers     []CatalogEntry `json:"providers"`
+}
+
+// CatalogEntry declares one provider. Disabled providers stay declared but take no traffic.
+// Weight orders candidates within a modality (higher first, ties by provider id). Region is
+// a placement label reported in provider health; adapter endpoints keep their own config.
+// CredentialsRef names the env var holding the provider secret as env:<NAME>.
+type CatalogEntry struct {
+	ProviderID     string             `json:"provider_id"`
+	Modality       contracts.Modality `json:"modality"`
+	Enabled        bool               `json:"enabled"`
+	Weight         int                `json:"weight,omitempty"`
+	Region         string             `json:"region,omitempty"`
+	CredentialsRef string             `json:"credentials_ref,omitempty"`
+}
+
+// CatalogFileFromEnv returns the catalog file path named by CatalogFileEnv.
+func CatalogFileFromEnv() string {
+	return strings.TrimSpace(os.Getenv(CatalogFileEnv))
+}
+
+// ParseCatalogFile decodes and validates a provider catalog file.
+func ParseCatalogFile(raw []byte) (CatalogFile, error) {
+	var file CatalogFile
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return CatalogFile{}, fmt.Errorf("decode provider catalog: %w", err)
+	}
+	if file.SchemaVersion != CatalogFileSchemaVersion {
+		return CatalogFile{}, fmt.Errorf("provider catalog schema_version must be %s, got %q", CatalogFileSchemaVersion, file.SchemaVersion)
+	}
+	seen := make(map[string]struct{}, len(file.Providers))
+	for idx, entry := range file.Providers {
+		if strings.TrimSpace(entry.ProviderID) == "" {
+			return CatalogFile{}, fmt.Errorf("provider catalog entry %d: provider_id is required", idx)
+		}
+		if _, ok := seen[entry.ProviderID]; ok {
+			return CatalogFile{}, fmt.Errorf("provider catalog entry %d: duplicate provider_id %q", idx, entry.ProviderID)
+		}
+		seen[entry.ProviderID] = struct{}{}
+		if err := entry.Modality.Validate(); err != nil {
+			return CatalogFile{}, fmt.Errorf("provider catalog entry %s: %w", entry.ProviderID, err)
+		}
+		if entry.Weight < 0 {
+			return CatalogFile{}, fmt.Errorf("provider catalog entry %s: weight must be >=0", entry.ProviderID)
+		}
+		if entry.CredentialsRef != "" && !credentialsRefPattern.MatchString(entry.CredentialsRef) {
+			return CatalogFile{}, fmt.Errorf("provider catalog entry %s: credentials_ref must be env:<NAME>", entry.ProviderID)
+		}
+	}
+	return file, nil
+}
+
+// knownProviderConstructors maps every provider id the runtime can build to its constructor.
+func knownProviderConstructors() map[string]func() (contracts.Adapter, error) {
+	return map[string]func() (contracts.Adapter, error){
+		sttdeepgram.ProviderID:   sttdeepgram.NewAdapterFromEnv,
+		sttgoogle.ProviderID:     sttgoogle.NewAdapterFromEnv,
+		sttassemblyai.ProviderID: sttassemblyai.NewAdapterFromEnv,
+		sttazure.ProviderID:      sttazure.NewAdapterFromEnv,
+		sttwhispercpp.ProviderID: sttwhispercpp.NewAdapterFromEnv,
+		llmanthropic.ProviderID:  llmanthropic.NewAdapterFromEnv,
+		llmgemini.ProviderID:     llmgemini.NewAdapterFromEnv,
+		llmcohere.ProviderID:     llmcohere.NewAdapterFromEnv,
+		llmllamacpp.ProviderID:   llmllamacpp.NewAdapterFromEnv,
+		ttselevenlabs.ProviderID: ttselevenlabs.NewAdapterFromEnv,
+		ttsgoogle.ProviderID:     ttsgoogle.NewAdapterFromEnv,
+		ttspolly.ProviderID:      ttspolly.NewAdapterFromEnv,
+		ttsazure.ProviderID:      ttsazure.NewAdapterFromEnv,
+		ttspiper.ProviderID:      ttspiper.NewAdapterFromEnv,
+		s2sopenai.ProviderID:     s2sopenai.NewAdapterFromEnv,
+	}
+}
+
+func buildKnownAdapter(entry CatalogEntry) (contracts.Adapter, error) {
+	constructor, ok := knownProviderConstructors()[entry.ProviderID]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider_id %q", entry.ProviderID)
+	}
+	return constructor()
+}
+
+// CatalogAuditRecord captures one provider catalog change or rejected reload.
+type CatalogAuditRecord struct {
+	AtMS       int64  `json:"at_ms"`
+	Action     string `json:"action"`
+	ProviderID string `json:"provider_id,omitempty"`
+	Detail     string `json:"detail"`
+}
+
+// CatalogManager serves the runtime provider catalog from a catalog file and reloads it
+// while the runtime runs. A reload that fails to parse, build, or meet coverage is rejected
+// and the serving catalog is kept. Every change is audited. It is safe for concurrent use.
+type CatalogManager struct {
+	mu         sync.Mutex
+	path       string
+	opts       Options
+	now        func() time.Time
+	build      func(CatalogEntry) (contracts.Adapter, error)
+	live       *registry.LiveCatalog
+	controller invocation.Controller
+	file       CatalogFile
+	digest     string
+	adapters   map[string]contracts.Adapter
+	audit      []CatalogAuditRecord
+	// shiftMu serializes weight shifts, which rewrite the catalog file.
+	shiftMu sync.Mutex
+	// AuditSink optionally receives every audit record as it is appended.
+	AuditSink func(CatalogAuditRecord)
+}
+
+// NewCatalogManager loads the catalog file at path and builds its enabled providers.
+func NewCatalogManager(path string, opts Options) (*CatalogManager, error) {
+	return newCatalogManager(path, opts, time.Now, buildKnownAdapter)
+}
+
+func newCatalogManager(path string, opts Options, now func() time.Time, build func(CatalogEntry) (contracts.Adapter, error)) (*CatalogManager, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("provider catalog file path is required")
+	}
+	opts = normalizeOptions(opts)
+	m := &CatalogManager{path: path, opts: opts, now: now, build: build, adapters: map[string]contracts.Adapter{}}
+	file, digest, catalog, adapters, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	m.live = registry.NewLiveCatalog(catalog)
+	m.controller = invocation.NewControllerWithSource(m.live, controllerConfig(opts))
+	m.file, m.digest, m.adapters = file, digest, adapters
+	m.appendAuditLocked(CatalogActionLoaded, "", catalogDetail(file, digest))
+	return m, nil
+}
+
+// RuntimeProviders returns the serving catalog and a controller that follows reloads.
+func (m *CatalogManager) RuntimeProviders() RuntimeProviders {
+	return RuntimeProviders{Catalog: m.live.Catalog(), Controller: m.controller, CircuitBreaker: m.opts.CircuitBreaker}
+}
+
+// Reload re-reads the catalog file and swaps in the new provider set, reporting whether it
+// changed. An unchanged file is a no-op.
+func (m *CatalogManager) Reload() (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	file, digest, catalog, adapters, err := m.load()
+	if err != nil {
+		m.appendAuditLocked(CatalogActionReloadRejected, "", err.Error())
+		return false, err
+	}
+	if digest == m.digest {
+		return false, nil
+	}
+	for _, change := range diffCatalog(m.file, file) {
+		m.appendAuditLocked(change.Action, change.ProviderID, change.Detail)
+	}
+	m.live.Swap(catalog)
+	m.file, m.digest, m.adapters = file, digest, adapters
+	m.appendAuditLocked(CatalogActionReloaded, "", catalogDetail(file, digest))
+	return true, nil
+}
+
+// ShiftWeights rewrites the weights of the named providers in the catalog file and reloads
+// it, so the shift is audited like any catalog edit and survives later reloads. The file is
+// re-read first so pending operator edits are kept. Every provider must be declared in the
+// file; providers not named keep their weight.
+func (m *CatalogManager) ShiftWeights(weights map[string]int) (bool, error) {
+	if len(weights) == 0 {
+		return false, fmt.Errorf("provider weight shift requires weights")
+	}
+	m.shiftMu.Lock()
+	defer m.shiftMu.Unlock()
+	raw, err := os.ReadFile(m.path)
+	if err != nil {
+		return false, fmt.Errorf("read provider catalog: %w", err)
+	}
+	file, err := ParseCatalogFile(raw)
+	if err != nil {
+		return false, err
+	}
+
+	declared := make(map[string]int, len(file.Providers))
+	for idx, entry := range file.Providers {
+		declared[entry.ProviderID] = idx
+	}
+	for providerID, weight := range weights {
+		idx, ok := declared[providerID]
+		if !ok {
+			return false, fmt.Errorf("provider weight shift: %q is not declared in the provider catalog", providerID)
+		}
+		if weight < 0 {
+			return false, fmt.Errorf("provider weight shift: %s weight must be >=0", providerID)
+		}
+		file.Providers[idx].Weight = weight
+	}
+	if err := replaceCatalogFile(m.path, file); err != nil {
+		return false, err
+	}
+	return m.Reload()
+}
+
+// replaceCatalogFile replaces the catalog file through a temp file rename, so a concurrent
+// reload never reads a partial file.
+func replaceCatalogFile(path string, file CatalogFile) error {
+	raw, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode provider catalog: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".catalog-*")
+	if err != nil {
+		return fmt.Errorf("write provider catalog: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(raw, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write provider catalog: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write provider catalog: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("commit provider catalog: %w", err)
+	}
+	return nil
+}
+
+// AuditLog returns a copy of all recorded catalog changes in order.
+func (m *CatalogManager) AuditLog() []CatalogAuditRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]CatalogAuditRecord(nil), m.audit...)
+}
+
+// CatalogStatuses reports every declared provider ordered by provider id. An entry without
+// a credentials reference needs none and counts as resolved.
+func (m *CatalogManager) CatalogStatuses() []providerhealth.CatalogStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	statuses := make([]providerhealth.CatalogStatus, 0, len(m.file.Providers))
+	for _, entry := range m.file.Providers {
+		statuses = append(statuses, providerhealth.CatalogStatus{
+			ProviderID:          entry.ProviderID,
+			Modality:            string(entry.Modality),
+			Enabled:             entry.Enabled,
+			Weight:              entry.Weight,
+			Region:              entry.Region,
+			CredentialsRef:      entry.CredentialsRef,
+			CredentialsResolved: credentialsResolved(entry.CredentialsRef),
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ProviderID < statuses[j].ProviderID })
+	return statuses
+}
+
+// load reads and builds the catalog file without touching serving state. Adapters of
+// providers that stay enabled with the same credentials reference are reused so their
+// warm connections survive the reload.
+func (m *CatalogManager) load() (CatalogFile, string, registry.Catalog, map[string]contracts.Adapter, error) {
+	raw, err := os.ReadFile(m.path)
+	if err != nil {
+		return CatalogFile{}, "", registry.Catalog{}, nil, fmt.Errorf("read provider catalog: %w", err)
+	}
+	file, err := ParseCatalogFile(raw)
+	if err != nil {
+		return CatalogFile{}, "", registry.Catalog{}, nil, err
+	}
+	sum := sha256.Sum256(raw)
+	digest := hex.EncodeToString(sum[:8])
+
+	previous := make(map[string]CatalogEntry, len(m.file.Providers))
+	for _, entry := range m.file.Providers {
+		previous[entry.ProviderID] = entry
+	}
+	adapters := make(map[string]contracts.Adapter)
+	enabled := make([]contracts.Adapter, 0, len(file.Providers))
+	weights := make(map[string]int, len(file.Providers))
+	for _, entry := range file.Providers {
+		if !entry.Enabled {
+			continue
+		}
+		adapter, reuse := m.adapters[entry.ProviderID]
+		if !reuse || previous[entry.ProviderID].CredentialsRef != entry.CredentialsRef {
+			adapter, err = m.build(entry)
+			if err != nil {
+				return CatalogFile{}, "", registry.Catalog{}, nil, fmt.Errorf("provider catalog entry %s: %w", entry.ProviderID, err)
+			}
+		}
+		if adapter.Modality() != entry.Modality {
+			return CatalogFile{}, "", registry.Catalog{}, nil, fmt.Errorf("provider catalog entry %s: declared modality %s, adapter is %s", entry.ProviderID, entry.Modality, adapter.Modality())
+		}
+		adapters[entry.ProviderID] = adapter
+		enabled = append(enabled, adapter)
+		weights[entry.ProviderID] = entry.Weight
+	}
+	catalog, err := registry.NewWeightedCatalog(enabled, weights)
+	if err != nil {
+		return CatalogFile{}, "", registry.Catalog{}, nil, err
+	}
+	if err := catalog.ValidateCoverage(m.opts.MinProvidersPerModality, m.opts.MaxProvidersPerModality); err != nil {
+		return CatalogFile{}, "", registry.Catalog{}, nil, err
+	}
+	return file, digest, catalog, adapters, nil
+}
+
+func (m *CatalogManager) appendAuditLocked(action string, providerID string, detail string) {
+	record := CatalogAuditRecord{AtMS: m.now().UnixMilli(), Action: action, ProviderID: providerID, Detail: detail}
+	m.audit = append(m.audit, record)
+	if m.AuditSink != nil {
+		m.AuditSink(record)
+	}
+}
+
+// diffCatalog lists per-provider changes from old to next ordered by provider id.
+func diffCatalog(old CatalogFile, next CatalogFile) []CatalogAuditRecord {
+	before := make(map[string]CatalogEntry, len(old.Providers))
+	for _, entry := range old.Providers {
+		before[entry.ProviderID] = entry
+	}
+	after := make(map[string]CatalogEntry, len(next.Providers))
+	for _, entry := range next.Providers {
+		after[entry.ProviderID] = entry
+	}
+	ids := make([]string, 0, len(before)+len(after))
+	for id := range before {
+		ids = append(ids, id)
+	}
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var changes []CatalogAuditRecord
+	for _, id := range ids {
+		prev, hadPrev := before[id]
+		cur, hasCur := after[id]
+		switch {
+		case !hadPrev:
+			changes = append(changes, CatalogAuditRecord{Action: CatalogActionAdded, ProviderID: id, Detail: entryDetail(cur)})
+		case !hasCur:
+			changes = append(changes, CatalogAuditRecord{Action: CatalogActionRemoved, ProviderID: id, Detail: entryDetail(prev)})
+		default:
+			if prev.Enabled != cur.Enabled {
+				action := CatalogActionDisabled
+				if cur.Enabled {
+					action = CatalogActionEnabled
+				}
+				changes = append(changes, CatalogAuditRecord{Action: action, ProviderID: id, Detail: entryDetail(cur)})
+			}
+			var updated []string
+			if prev.Modality != cur.Modality {
+				updated = append(updated, fmt.Sprintf("modality %s->%s", prev.Modality, cur.Modality))
+			}
+			if prev.Weight != cur.Weight {
+				updated = append(updated, fmt.Sprintf("weight %d->%d", prev.Weight, cur.Weight))
+			}
+			if prev.Region != cur.Region {
+				updated = append(updated, fmt.Sprintf("region %q->%q", prev.Region, cur.Region))
+			}
+			if prev.CredentialsRef != cur.CredentialsRef {
+				updated = append(updated, fmt.Sprintf("credentials_ref %q->%q", prev.CredentialsRef, cur.CredentialsRef))
+			}
+			if len(updated) > 0 {
+				changes = append(changes, CatalogAuditRecord{Action: CatalogActionUpdated, ProviderID: id, Detail: strings.Join(updated, " ")})
+			}
+		}
+	}
+	return changes
+}
+
+func entryDetail(entry CatalogEntry) string {
+	return fmt.Sprintf("modality=%s enabled=%t weight=%d region=%q", entry.Modality, entry.Enabled, entry.Weight, entry.Region)
+}
+
+func catalogDetail(file CatalogFile, digest string) string {
+	enabled := 0
+	for _, entry := range file.Providers {
+		if entry.Enabled {
+			enabled++
+		}
+	}
+	return fmt.Sprintf("digest=%s providers=%d enabled=%d", digest, len(file.Providers), enabled)
+}
+
+func credentialsResolved(ref string) bool {
+	if ref == "" {
+		return true
+	}
+	return strings.TrimSpace(os.Getenv(strings.TrimPrefix(ref, "env:"))) != ""
+}
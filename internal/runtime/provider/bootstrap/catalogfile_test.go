@@ -0,0 +1,214 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/providerhealth"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/invocation"
+)
+
+func staticCatalogAdapter(entry CatalogEntry) (contracts.Adapter, error) {
+	return contracts.StaticAdapter{ID: entry.ProviderID, Mode: contracts.Modality(strings.SplitN(entry.ProviderID, "-", 2)[0])}, nil
+}
+
+func writeCatalogFile(t *testing.T, path string, entries []CatalogEntry) {
+	t.Helper()
+	raw, err := json.Marshal(CatalogFile{SchemaVersion: CatalogFileSchemaVersion, Providers: entries})
+	if err != nil {
+		t.Fatalf("marshal catalog: %v", err)
+	}
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatalf("write catalog: %v", err)
+	}
+}
+
+func baseCatalogEntries() []CatalogEntry {
+	var entries []CatalogEntry
+	for _, modality := range []contracts.Modality{contracts.ModalitySTT, contracts.ModalityLLM, contracts.ModalityTTS} {
+		for _, suffix := range []string{"a", "b", "c"} {
+			entries = append(entries, CatalogEntry{ProviderID: string(modality) + "-" + suffix, Modality: modality, Enabled: true})
+		}
+	}
+	entries = append(entries, CatalogEntry{ProviderID: "stt-d", Modality: contracts.ModalitySTT, Region: "eu-west-1", CredentialsRef: "env:RSPP_TEST_CATALOG_UNSET_KEY"})
+	return entries
+}
+
+func invokeSTT(t *testing.T, controller invocation.Controller) string {
+	t.Helper()
+	result, err := controller.Invoke(invocation.InvocationInput{
+		SessionID:            "sess-catalog-1",
+		TurnID:               "turn-catalog-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-catalog-1",
+		Modality:             contracts.ModalitySTT,
+		TransportSequence:    1,
+		RuntimeSequence:      1,
+		AuthorityEpoch:       1,
+		RuntimeTimestampMS:   10,
+		WallClockTimestampMS: 10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	return result.SelectedProvider
+}
+
+func TestParseCatalogFileValidatesEntries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		raw       string
+		shouldErr bool
+	}{
+		{name: "valid", raw: `{"schema_version":"rspp-provider-catalog/v1","providers":[{"provider_id":"stt-deepgram","modality":"stt","enabled":true,"weight":3,"region":"us-east-1","credentials_ref":"env:RSPP_STT_DEEPGRAM_API_KEY"}]}`},
+		{name: "wrong schema", raw: `{"schema_version":"v0","providers":[]}`, shouldErr: true},
+		{name: "unknown field", raw: `{"schema_version":"rspp-provider-catalog/v1","providers":[{"provider_id":"stt-deepgram","modality":"stt","api_key":"secret"}]}`, shouldErr: true},
+		{name: "missing provider id", raw: `{"schema_version":"rspp-provider-catalog/v1","providers":[{"modality":"stt"}]}`, shouldErr: true},
+		{name: "duplicate provider", raw: `{"schema_version":"rspp-provider-catalog/v1","providers":[{"provider_id":"stt-a","modality":"stt"},{"provider_id":"stt-a","modality":"stt"}]}`, shouldErr: true},
+		{name: "invalid modality", raw: `{"schema_version":"rspp-provider-catalog/v1","providers":[{"provider_id":"stt-a","modality":"vision"}]}`, shouldErr: true},
+		{name: "negative weight", raw: `{"schema_version":"rspp-provider-catalog/v1","providers":[{"provider_id":"stt-a","modality":"stt","weight":-1}]}`, shouldErr: true},
+		{name: "inline credentials", raw: `{"schema_version":"rspp-provider-catalog/v1","providers":[{"provider_id":"stt-a","modality":"stt","credentials_ref":"sk-live-123"}]}`, shouldErr: true},
+	}
+	for _, tc := range tests {
+		_, err := ParseCatalogFile([]byte(tc.raw))
+		if tc.shouldErr != (err != nil) {
+			t.Fatalf("%s: expected error=%t, got %v", tc.name, tc.shouldErr, err)
+		}
+	}
+}
+
+func TestCatalogManagerReloadsAuditedChangesIntoController(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "providers.json")
+	entries := baseCatalogEntries()
+	writeCatalogFile(t, path, entries)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	manager, err := newCatalogManager(path, Options{}, func() time.Time { return now }, staticCatalogAdapter)
+	if err != nil {
+		t.Fatalf("unexpected catalog manager error: %v", err)
+	}
+	var sunk []CatalogAuditRecord
+	manager.AuditSink = func(record CatalogAuditRecord) { sunk = append(sunk, record) }
+	controller := manager.RuntimeProviders().Controller
+	if selected := invokeSTT(t, controller); selected != "stt-a" {
+		t.Fatalf("expected id-ordered stt-a before reload, got %s", selected)
+	}
+	if changed, err := manager.Reload(); err != nil || changed {
+		t.Fatalf("expected an unchanged file to be a no-op, got changed=%t err=%v", changed, err)
+	}
+
+	entries[0].Enabled = false
+	entries[2].Weight = 10
+	entries[9].Enabled = true
+	entries = append(entries, CatalogEntry{ProviderID: "tts-d", Modality: contracts.ModalityTTS})
+	writeCatalogFile(t, path, entries)
+	if changed, err := manager.Reload(); err != nil || !changed {
+		t.Fatalf("expected reload to apply, got changed=%t err=%v", changed, err)
+	}
+	if selected := invokeSTT(t, controller); selected != "stt-c" {
+		t.Fatalf("expected the existing controller to follow the reload to weighted stt-c, got %s", selected)
+	}
+	ids, _ := manager.RuntimeProviders().Catalog.ProviderIDs(contracts.ModalitySTT)
+	if strings.Join(ids, ",") != "stt-c,stt-b,stt-d" {
+		t.Fatalf("expected stt-a disabled and stt-d enabled, got %v", ids)
+	}
+
+	for idx := range entries {
+		entries[idx].Enabled = entries[idx].Modality != contracts.ModalityLLM
+	}
+	writeCatalogFile(t, path, entries)
+	if _, err := manager.Reload(); err == nil {
+		t.Fatalf("expected a reload without llm coverage to be rejected")
+	}
+	if selected := invokeSTT(t, controller); selected != "stt-c" {
+		t.Fatalf("expected the rejected reload to keep the serving catalog, got %s", selected)
+	}
+
+	var actions []string
+	for _, record := range manager.AuditLog() {
+		actions = append(actions, record.Action+":"+record.ProviderID)
+	}
+	want := "catalog_loaded:,provider_disabled:stt-a,provider_updated:stt-c,provider_enabled:stt-d,provider_added:tts-d,catalog_reloaded:,catalog_reload_rejected:"
+	if strings.Join(actions, ",") != want {
+		t.Fatalf("expected audit %s, got %s", want, strings.Join(actions, ","))
+	}
+	if len(sunk) != len(actions)-1 || sunk[0].AtMS != now.UnixMilli() {
+		t.Fatalf("expected every post-load record sunk, got %+v", sunk)
+	}
+
+	service := providerhealth.NewService()
+	service.Catalog = manager
+	out, err := service.GetSnapshot(providerhealth.Input{Scope: "tenant-a"})
+	if err != nil {
+		t.Fatalf("unexpected provider health error: %v", err)
+	}
+	if len(out.ProviderCatalog) != 11 {
+		t.Fatalf("expected every declared provider in the health snapshot, got %+v", out.ProviderCatalog)
+	}
+	for _, status := range out.ProviderCatalog {
+		if status.ProviderID == "stt-a" && (status.Enabled || !status.CredentialsResolved) {
+			t.Fatalf("expected serving stt-a disabled with no credentials needed, got %+v", status)
+		}
+		if status.ProviderID == "stt-d" && (!status.Enabled || status.Region != "eu-west-1" || status.CredentialsResolved) {
+			t.Fatalf("expected stt-d enabled in eu-west-1 with an unresolved credentials ref, got %+v", status)
+		}
+	}
+}
+
+func TestCatalogManagerRejectsUnknownProviders(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "providers.json")
+	writeCatalogFile(t, path, []CatalogEntry{{ProviderID: "stt-unknown", Modality: contracts.ModalitySTT, Enabled: true}})
+	if _, err := NewCatalogManager(path, Options{MinProvidersPerModality: 1}); err == nil || !strings.Contains(err.Error(), "unknown provider_id") {
+		t.Fatalf("expected unknown provider to fail, got %v", err)
+	}
+	if _, err := NewCatalogManager(filepath.Join(t.TempDir(), "missing.json"), Options{}); err == nil {
+		t.Fatalf("expected a missing catalog file to fail")
+	}
+}
+
+func TestCatalogManagerShiftWeightsRewritesCatalogFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "providers.json")
+	writeCatalogFile(t, path, baseCatalogEntries())
+	manager, err := newCatalogManager(path, Options{}, time.Now, staticCatalogAdapter)
+	if err != nil {
+		t.Fatalf("unexpected catalog manager error: %v", err)
+	}
+	controller := manager.RuntimeProviders().Controller
+
+	if _, err := manager.ShiftWeights(map[string]int{"stt-z": 5}); err == nil {
+		t.Fatalf("expected a shift naming an undeclared provider to be rejected")
+	}
+	if changed, err := manager.ShiftWeights(map[string]int{"stt-b": 80, "stt-a": 20}); err != nil || !changed {
+		t.Fatalf("expected weight shift to apply, got changed=%t err=%v", changed, err)
+	}
+	if selected := invokeSTT(t, controller); selected != "stt-b" {
+		t.Fatalf("expected the controller to follow the shift to stt-b, got %s", selected)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read shifted catalog: %v", err)
+	}
+	file, err := ParseCatalogFile(raw)
+	if err != nil || file.Providers[1].Weight != 80 || file.Providers[0].Weight != 20 || len(file.Providers) != 10 {
+		t.Fatalf("expected shifted weights persisted to the catalog file, got %+v err=%v", file, err)
+	}
+	if changed, err := manager.Reload(); err != nil || changed {
+		t.Fatalf("expected the shift to survive a reload unchanged, got changed=%t err=%v", changed, err)
+	}
+	log := manager.AuditLog()
+	if last := log[len(log)-1]; last.Action != CatalogActionReloaded || log[len(log)-2].Action != CatalogActionUpdated {
+		t.Fatalf("expected the shift audited as a catalog update, got %+v", log)
+	}
+}
@@ -2,7 +2,9 @@ package contracts
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
+	"time"
 )
 
 // Modality defines provider families supported by runtime invocation.
@@ -12,12 +14,14 @@ const (
 	ModalitySTT Modality = "stt"
 	ModalityLLM Modality = "llm"
 	ModalityTTS Modality = "tts"
+	// ModalityS2S is a unified speech-to-speech provider that replaces an STT->LLM->TTS chain.
+	ModalityS2S Modality = "s2s"
 )
 
 // Validate enforces supported provider modality values.
 func (m Modality) Validate() error {
 	switch m {
-	case ModalitySTT, ModalityLLM, ModalityTTS:
+	case ModalitySTT, ModalityLLM, ModalityTTS, ModalityS2S:
 		return nil
 	default:
 		return fmt.Errorf("unsupported modality: %q", m)
@@ -34,12 +38,15 @@ const (
 	OutcomeBlocked               OutcomeClass = "blocked"
 	OutcomeInfrastructureFailure OutcomeClass = "infrastructure_failure"
 	OutcomeCancelled             OutcomeClass = "cancelled"
+	// OutcomeQualityFailure is a provider response whose output failed a quality check, such
+	// as synthesized audio with the wrong duration, long silence, or clipping.
+	OutcomeQualityFailure OutcomeClass = "quality_failure"
 )
 
 // Validate enforces supported outcome classes.
 func (o OutcomeClass) Validate() error {
 	switch o {
-	case OutcomeSuccess, OutcomeTimeout, OutcomeOverload, OutcomeBlocked, OutcomeInfrastructureFailure, OutcomeCancelled:
+	case OutcomeSuccess, OutcomeTimeout, OutcomeOverload, OutcomeBlocked, OutcomeInfrastructureFailure, OutcomeCancelled, OutcomeQualityFailure:
 		return nil
 	default:
 		return fmt.Errorf("unsupported outcome_class: %q", o)
@@ -65,6 +72,244 @@ type InvocationRequest struct {
 	AllowedAdaptiveActions []string
 	RetryBudgetRemaining   int
 	CandidateProviderCount int
+	// MaxOutputTokens caps LLM output length when >0 (for example under a flow-control degrade).
+	MaxOutputTokens int
+	// MaxAudioOutputMS caps TTS audio duration when >0; adapters stop synthesis at the cap.
+	MaxAudioOutputMS int64
+	// DeadlineMS is the attempt's share of the remaining turn latency budget when >0; adapters
+	// bound their own timeout by it.
+	DeadlineMS int64
+	// CancelSignal is closed when the turn is cancelled after the attempt starts; streaming
+	// adapters abort the provider stream and report usage consumed up to the abort.
+	CancelSignal <-chan struct{}
+	// Endpointing is set only for STT adapters that endpoint server-side.
+	Endpointing *Endpointing
+	// Traceparent is the W3C trace context of this attempt's span; HTTP adapters forward it so
+	// provider-side latency joins the turn trace.
+	Traceparent string
+	// Locale is the session locale selection; adapters apply the fields for their modality.
+	Locale *Locale
+	// SessionSummary is the latest derived_summary of earlier turns; LLM adapters bind it into
+	// the prompt context in place of the condensed transcript.
+	SessionSummary *SessionSummary
+	// SessionMetadata is the session-start enrichment metadata bound into the session context;
+	// LLM adapters expose it to prompt templates through RenderPrompt.
+	SessionMetadata map[string]string
+	// ProviderSessionID continues a provider-side conversation, so conversation-capable LLM
+	// adapters send only the new turn input. Empty means stateless: send the full context.
+	ProviderSessionID string
+	// SamplingSeed is the deterministic per-turn, per-node sampling seed; set only for LLM
+	// adapters that support seeded sampling.
+	SamplingSeed *int64
+	// Prompt, when set, is the LLM input in place of the adapter's configured prompt template,
+	// as for eval prompts run through the invocation path.
+	Prompt string
+	// Audio is the captured utterance an STT or S2S adapter recognizes in place of its
+	// configured sample audio.
+	Audio *AudioInput
+	// Text, when set, is the TTS input in place of the adapter's configured sample text.
+	Text string
+}
+
+// AudioInput is mono 16-bit PCM audio passed to an adapter for recognition.
+type AudioInput struct {
+	PCM          []int16
+	SampleRateHz int
+}
+
+// DurationMS returns the audio duration in milliseconds.
+func (a AudioInput) DurationMS() int64 {
+	if a.SampleRateHz < 1 {
+		return 0
+	}
+	return int64(len(a.PCM)) * 1000 / int64(a.SampleRateHz)
+}
+
+// AudioOutput is mono 16-bit PCM audio produced by a TTS or S2S adapter.
+type AudioOutput struct {
+	PCM          []int16
+	SampleRateHz int
+}
+
+// SessionSummary carries a condensed session context produced by the summarization node.
+type SessionSummary struct {
+	SummaryID string
+	Text      string
+}
+
+// Locale carries the resolved session locale to provider adapters.
+type Locale struct {
+	Tag               string
+	LLMPromptLanguage string
+	TTSVoice          string
+	TTSVoiceLocale    string
+	STTLanguageHints  []string
+}
+
+// Endpointing carries STT endpointing parameters in milliseconds.
+type Endpointing struct {
+	SilenceDurationMS int64
+	MinUtteranceMS    int64
+	MaxUtteranceMS    int64
+	NoSpeechTimeoutMS int64
+}
+
+const (
+	// EndpointingEnforcementProvider marks endpointing applied by the STT provider.
+	EndpointingEnforcementProvider = "provider"
+	// EndpointingEnforcementVADEmulated marks endpointing emulated by the session VAD.
+	EndpointingEnforcementVADEmulated = "vad_emulated"
+)
+
+// EndpointingAdapter is implemented by STT adapters that can endpoint server-side.
+type EndpointingAdapter interface {
+	SupportsServerEndpointing() bool
+}
+
+// SupportsServerEndpointing reports whether adapter applies Endpointing itself.
+func SupportsServerEndpointing(adapter Adapter) bool {
+	capable, ok := adapter.(EndpointingAdapter)
+	return ok && adapter.Modality() == ModalitySTT && capable.SupportsServerEndpointing()
+}
+
+const (
+	// SessionAffinityReused marks an attempt that continued a provider-side conversation.
+	SessionAffinityReused = "reused"
+	// SessionAffinityEstablished marks a stateless attempt whose provider returned a new
+	// conversation handle for later turns.
+	SessionAffinityEstablished = "established"
+	// SessionAffinityStateless marks an attempt sent with full context and no handle.
+	SessionAffinityStateless = "stateless"
+	// SessionAffinityStatelessFallback marks a full-context attempt sent after the provider
+	// failed an attempt that reused its conversation handle.
+	SessionAffinityStatelessFallback = "stateless_fallback"
+)
+
+// ConversationSessionAdapter is implemented by LLM adapters whose provider keeps a
+// conversation handle across turns.
+type ConversationSessionAdapter interface {
+	SupportsConversationSessions() bool
+}
+
+// SupportsConversationSessions reports whether adapter can continue a provider-side conversation.
+func SupportsConversationSessions(adapter Adapter) bool {
+	capable, ok := adapter.(ConversationSessionAdapter)
+	return ok && adapter.Modality() == ModalityLLM && capable.SupportsConversationSessions()
+}
+
+const (
+	// SamplingSeedApplied marks an LLM attempt whose provider sampled with SamplingSeed.
+	SamplingSeedApplied = "applied"
+	// SamplingSeedIgnored marks a seed-capable provider that reported not applying the seed.
+	SamplingSeedIgnored = "ignored"
+	// SamplingSeedUnsupported marks an LLM provider that cannot accept a sampling seed.
+	SamplingSeedUnsupported = "unsupported"
+)
+
+// SamplingSeedAdapter is implemented by LLM adapters whose provider accepts a sampling seed.
+type SamplingSeedAdapter interface {
+	SupportsSamplingSeed() bool
+}
+
+// SupportsSamplingSeed reports whether adapter forwards SamplingSeed to its provider.
+func SupportsSamplingSeed(adapter Adapter) bool {
+	capable, ok := adapter.(SamplingSeedAdapter)
+	return ok && adapter.Modality() == ModalityLLM && capable.SupportsSamplingSeed()
+}
+
+// Capability is typical provider performance metadata used to check declared node budgets.
+type Capability struct {
+	TypicalFirstOutputLatencyMS int64
+	TypicalCostPerTurnUSD       float64
+}
+
+// Validate enforces non-negative capability metadata.
+func (c Capability) Validate() error {
+	if c.TypicalFirstOutputLatencyMS < 0 {
+		return fmt.Errorf("typical_first_output_latency_ms must be >=0")
+	}
+	if c.TypicalCostPerTurnUSD < 0 {
+		return fmt.Errorf("typical_cost_per_turn_usd must be >=0")
+	}
+	return nil
+}
+
+// CapabilityAdapter is implemented by adapters that report their own capability metadata.
+type CapabilityAdapter interface {
+	Capability() Capability
+}
+
+// CancelSignalled reports whether the request cancel signal has fired.
+func (r InvocationRequest) CancelSignalled() bool {
+	if r.CancelSignal == nil {
+		return false
+	}
+	select {
+	case <-r.CancelSignal:
+		return true
+	default:
+		return false
+	}
+}
+
+// OutputTokenLimit returns configured capped by MaxOutputTokens when a cap is set.
+func (r InvocationRequest) OutputTokenLimit(configured int) int {
+	if r.MaxOutputTokens > 0 && (configured < 1 || r.MaxOutputTokens < configured) {
+		return r.MaxOutputTokens
+	}
+	return configured
+}
+
+// AudioOutputLimitMS returns configured capped by MaxAudioOutputMS when a cap is set.
+func (r InvocationRequest) AudioOutputLimitMS(configured int64) int64 {
+	if r.MaxAudioOutputMS > 0 && (configured < 1 || r.MaxAudioOutputMS < configured) {
+		return r.MaxAudioOutputMS
+	}
+	return configured
+}
+
+// AttemptTimeout returns configured bounded by DeadlineMS when a deadline is set.
+func (r InvocationRequest) AttemptTimeout(configured time.Duration) time.Duration {
+	deadline := time.Duration(r.DeadlineMS) * time.Millisecond
+	if r.DeadlineMS > 0 && (configured <= 0 || deadline < configured) {
+		return deadline
+	}
+	return configured
+}
+
+// SessionSummaryContext returns the system-prompt text binding the session summary, or "".
+func (r InvocationRequest) SessionSummaryContext() string {
+	if r.SessionSummary == nil || r.SessionSummary.Text == "" {
+		return ""
+	}
+	return "Summary of the conversation so far: " + r.SessionSummary.Text
+}
+
+// LLMPrompt returns Prompt when set and otherwise template rendered with RenderPrompt.
+func (r InvocationRequest) LLMPrompt(template string) string {
+	if r.Prompt != "" {
+		return r.Prompt
+	}
+	return r.RenderPrompt(template)
+}
+
+// TTSText returns Text when set and otherwise the adapter's configured text.
+func (r InvocationRequest) TTSText(configured string) string {
+	if r.Text != "" {
+		return r.Text
+	}
+	return configured
+}
+
+// promptMetadataPattern matches {{metadata.<key>}} placeholders in LLM prompt templates.
+var promptMetadataPattern = regexp.MustCompile(`\{\{\s*metadata\.([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// RenderPrompt substitutes {{metadata.<key>}} placeholders in a prompt template with session
+// metadata values. Keys the session has no metadata for render empty.
+func (r InvocationRequest) RenderPrompt(template string) string {
+	return promptMetadataPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		return r.SessionMetadata[promptMetadataPattern.FindStringSubmatch(placeholder)[1]]
+	})
 }
 
 // Validate enforces deterministic required fields.
@@ -96,6 +341,38 @@ func (r InvocationRequest) Validate() error {
 	if r.CandidateProviderCount < 0 {
 		return fmt.Errorf("candidate_provider_count must be >=0")
 	}
+	if r.MaxOutputTokens < 0 {
+		return fmt.Errorf("max_output_tokens must be >=0")
+	}
+	if r.MaxAudioOutputMS < 0 {
+		return fmt.Errorf("max_audio_output_ms must be >=0")
+	}
+	if r.DeadlineMS < 0 {
+		return fmt.Errorf("deadline_ms must be >=0")
+	}
+	if r.Endpointing != nil && r.Modality != ModalitySTT {
+		return fmt.Errorf("endpointing is only valid for stt invocations")
+	}
+	if r.SessionSummary != nil && r.Modality != ModalityLLM {
+		return fmt.Errorf("session_summary is only valid for llm invocations")
+	}
+	if r.SamplingSeed != nil && r.Modality != ModalityLLM {
+		return fmt.Errorf("sampling_seed is only valid for llm invocations")
+	}
+	if len(r.SessionMetadata) > 0 && r.Modality != ModalityLLM {
+		return fmt.Errorf("session_metadata is only valid for llm invocations")
+	}
+	if r.Audio != nil {
+		if r.Modality != ModalitySTT && r.Modality != ModalityS2S {
+			return fmt.Errorf("audio is only valid for stt and s2s invocations")
+		}
+		if r.Audio.SampleRateHz < 1 {
+			return fmt.Errorf("audio sample_rate_hz must be >=1")
+		}
+	}
+	if r.Text != "" && r.Modality != ModalityTTS {
+		return fmt.Errorf("text is only valid for tts invocations")
+	}
 	return nil
 }
 
@@ -129,6 +406,49 @@ type Outcome struct {
 	Reason      string
 	CircuitOpen bool
 	BackoffMS   int64
+	// Usage reports provider token consumption when the adapter can observe it.
+	Usage *TokenUsage
+	// ProviderSessionID is the conversation handle the provider returned for reuse on later turns.
+	ProviderSessionID string
+	// LengthCapped reports output stopped at the request MaxOutputTokens or MaxAudioOutputMS.
+	LengthCapped bool
+	// FirstChunkLatencyMS is the time from request to the first streamed output chunk; zero
+	// when the adapter does not stream or did not observe it.
+	FirstChunkLatencyMS int64
+	// SamplingSeedIgnored reports the provider accepted the request but did not apply its
+	// SamplingSeed (for example a model that drops the seed parameter).
+	SamplingSeedIgnored bool
+	// Text is the output text of a successful LLM attempt, or the transcript of a successful
+	// STT attempt, when the adapter observes it.
+	Text string
+	// Confidence is the provider's [0,1] confidence in a successful STT transcript; zero when
+	// the adapter does not observe it.
+	Confidence float64
+	// Audio is the synthesized speech of a successful TTS or S2S attempt when the adapter
+	// returns PCM.
+	Audio *AudioOutput
+}
+
+// TokenUsage is token consumption for one attempt; Truncated marks usage counted up to a
+// cancel-driven stream abort rather than a provider-reported final total. AudioInputMS and
+// AudioOutputMS report audio the provider consumed or produced when the adapter observes it.
+type TokenUsage struct {
+	InputTokens   int
+	OutputTokens  int
+	Truncated     bool
+	AudioInputMS  int64
+	AudioOutputMS int64
+}
+
+// Validate enforces non-negative token and audio counts.
+func (u TokenUsage) Validate() error {
+	if u.InputTokens < 0 || u.OutputTokens < 0 {
+		return fmt.Errorf("token usage counts must be >=0")
+	}
+	if u.AudioInputMS < 0 || u.AudioOutputMS < 0 {
+		return fmt.Errorf("audio usage must be >=0")
+	}
+	return nil
 }
 
 // Validate enforces normalized outcome invariants.
@@ -145,6 +465,20 @@ func (o Outcome) Validate() error {
 	if o.CircuitOpen && o.Class == OutcomeSuccess {
 		return fmt.Errorf("circuit_open cannot be true for success")
 	}
+	if o.LengthCapped && o.Class != OutcomeSuccess {
+		return fmt.Errorf("length_capped requires outcome_class=success")
+	}
+	if o.FirstChunkLatencyMS < 0 {
+		return fmt.Errorf("first_chunk_latency_ms must be >=0")
+	}
+	if o.Usage != nil {
+		if err := o.Usage.Validate(); err != nil {
+			return err
+		}
+		if o.Usage.Truncated && o.Class != OutcomeCancelled {
+			return fmt.Errorf("truncated token usage requires outcome_class=cancelled")
+		}
+	}
 	return nil
 }
 
@@ -155,11 +489,27 @@ type Adapter interface {
 	Invoke(InvocationRequest) (Outcome, error)
 }
 
+// StreamChunk is one incremental output of a streaming invocation.
+type StreamChunk struct {
+	// Text is incremental text output; for speech-to-speech providers, the reply transcript.
+	Text string
+	// Audio is incremental reply audio in the adapter's configured output format.
+	Audio []byte
+}
+
+// StreamingAdapter is implemented by adapters that deliver output incrementally. InvokeStream
+// calls onChunk for each chunk in provider order and returns the same normalized outcome
+// Invoke would; an onChunk error aborts the stream and is returned.
+type StreamingAdapter interface {
+	InvokeStream(req InvocationRequest, onChunk func(StreamChunk) error) (Outcome, error)
+}
+
 // StaticAdapter is a small utility adapter for tests and static catalogs.
 type StaticAdapter struct {
-	ID       string
-	Mode     Modality
-	InvokeFn func(InvocationRequest) (Outcome, error)
+	ID             string
+	Mode           Modality
+	InvokeFn       func(InvocationRequest) (Outcome, error)
+	InvokeStreamFn func(InvocationRequest, func(StreamChunk) error) (Outcome, error)
 }
 
 func (a StaticAdapter) ProviderID() string {
@@ -179,3 +529,52 @@ func (a StaticAdapter) Invoke(req InvocationRequest) (Outcome, error) {
 	}
 	return Outcome{Class: OutcomeSuccess}, nil
 }
+
+// InvokeStream uses InvokeStreamFn when set and otherwise falls back to Invoke without chunks.
+func (a StaticAdapter) InvokeStream(req InvocationRequest, onChunk func(StreamChunk) error) (Outcome, error) {
+	if a.InvokeStreamFn != nil {
+		return a.InvokeStreamFn(req, onChunk)
+	}
+	return a.Invoke(req)
+}
+
+// WarmupResult captures one provider connection pre-establishment attempt.
+type WarmupResult struct {
+	ProviderID       string
+	Modality         Modality
+	Warmed           bool
+	LatencyMS        int64
+	ConnectionReused bool
+	Reason           string
+}
+
+// Warmer is implemented by adapters that can pre-establish provider connections/TLS
+// sessions ahead of the first invocation. Warm-up never counts as an invocation attempt.
+type Warmer interface {
+	Warm() WarmupResult
+}
+
+// StandbyResult captures one warm-standby hold or refresh of an authenticated provider
+// connection.
+type StandbyResult struct {
+	ProviderID string
+	Modality   Modality
+	Held       bool
+	LatencyMS  int64
+	Reason     string
+}
+
+// StandbyHolder is implemented by adapters that can keep an authenticated connection open as
+// a warm standby, so a provider_switch onto them skips connection setup. Holding and
+// refreshing never count as invocation attempts.
+type StandbyHolder interface {
+	HoldStandby() StandbyResult
+	ReleaseStandby()
+	StandbyHeld() bool
+}
+
+// HoldsStandby reports whether adapter currently holds a warm-standby connection.
+func HoldsStandby(adapter Adapter) bool {
+	holder, ok := adapter.(StandbyHolder)
+	return ok && holder.StandbyHeld()
+}
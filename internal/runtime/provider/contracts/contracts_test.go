@@ -1,6 +1,9 @@
 package contracts
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestInvocationRequestValidate(t *testing.T) {
 	t.Parallel()
@@ -54,6 +57,89 @@ func TestInvocationRequestValidate(t *testing.T) {
 	if err := req.Validate(); err == nil {
 		t.Fatalf("expected negative candidate provider count to fail validation")
 	}
+
+	req.CandidateProviderCount = 0
+	req.MaxOutputTokens = -1
+	if err := req.Validate(); err == nil {
+		t.Fatalf("expected negative max output tokens to fail validation")
+	}
+
+	req.MaxOutputTokens = 0
+	req.SessionSummary = &SessionSummary{SummaryID: "summary-1", Text: "caller wants to rebook"}
+	if err := req.Validate(); err == nil {
+		t.Fatalf("expected session summary on stt invocation to fail validation")
+	}
+	req.Modality = ModalityLLM
+	if err := req.Validate(); err != nil {
+		t.Fatalf("expected session summary on llm invocation to validate, got %v", err)
+	}
+	if got := req.SessionSummaryContext(); got != "Summary of the conversation so far: caller wants to rebook" {
+		t.Fatalf("unexpected session summary context %q", got)
+	}
+
+	req.Modality = ModalityTTS
+	req.SessionSummary = nil
+	req.SessionMetadata = map[string]string{"crm.tier": "gold"}
+	if err := req.Validate(); err == nil {
+		t.Fatalf("expected session metadata on tts invocation to fail validation")
+	}
+}
+
+func TestInvocationRequestRenderPrompt(t *testing.T) {
+	t.Parallel()
+
+	req := InvocationRequest{SessionMetadata: map[string]string{"crm.tier": "gold", "prefs.language": "de"}}
+	got := req.RenderPrompt("Customer tier {{metadata.crm.tier}}, language {{ metadata.prefs.language }}, plan {{metadata.crm.plan}}, {{other}}.")
+	if want := "Customer tier gold, language de, plan , {{other}}."; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got := (InvocationRequest{}).RenderPrompt("Reply with the word: ok"); got != "Reply with the word: ok" {
+		t.Fatalf("expected template without placeholders unchanged, got %q", got)
+	}
+}
+
+func TestInvocationRequestOutputTokenLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		maxTokens  int
+		configured int
+		want       int
+	}{
+		{name: "uncapped keeps configured", configured: 256, want: 256},
+		{name: "cap below configured", maxTokens: 64, configured: 256, want: 64},
+		{name: "cap above configured", maxTokens: 512, configured: 256, want: 256},
+		{name: "cap without configured", maxTokens: 64, want: 64},
+	}
+	for _, tc := range tests {
+		got := InvocationRequest{MaxOutputTokens: tc.maxTokens}.OutputTokenLimit(tc.configured)
+		if got != tc.want {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestInvocationRequestAttemptTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		deadlineMS int64
+		configured time.Duration
+		want       time.Duration
+	}{
+		{name: "no deadline keeps configured", configured: 10 * time.Second, want: 10 * time.Second},
+		{name: "deadline below configured", deadlineMS: 800, configured: 10 * time.Second, want: 800 * time.Millisecond},
+		{name: "deadline above configured", deadlineMS: 20000, configured: 10 * time.Second, want: 10 * time.Second},
+		{name: "deadline without configured", deadlineMS: 800, want: 800 * time.Millisecond},
+	}
+	for _, tc := range tests {
+		got := InvocationRequest{DeadlineMS: tc.deadlineMS}.AttemptTimeout(tc.configured)
+		if got != tc.want {
+			t.Fatalf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
 }
 
 func TestOutcomeValidate(t *testing.T) {
@@ -79,6 +165,29 @@ func TestOutcomeValidate(t *testing.T) {
 	if err := circuitOnSuccess.Validate(); err == nil {
 		t.Fatalf("expected success outcome with circuit_open=true to fail")
 	}
+
+	truncatedCancel := Outcome{
+		Class:  OutcomeCancelled,
+		Reason: "provider_stream_cancelled",
+		Usage:  &TokenUsage{InputTokens: 12, OutputTokens: 3, Truncated: true},
+	}
+	if err := truncatedCancel.Validate(); err != nil {
+		t.Fatalf("expected cancelled outcome with truncated usage to be valid, got %v", err)
+	}
+
+	truncatedTimeout := Outcome{
+		Class:  OutcomeTimeout,
+		Reason: "provider_timeout",
+		Usage:  &TokenUsage{OutputTokens: 3, Truncated: true},
+	}
+	if err := truncatedTimeout.Validate(); err == nil {
+		t.Fatalf("expected truncated usage on non-cancelled outcome to fail")
+	}
+
+	negativeUsage := Outcome{Class: OutcomeSuccess, Usage: &TokenUsage{OutputTokens: -1}}
+	if err := negativeUsage.Validate(); err == nil {
+		t.Fatalf("expected negative token usage to fail")
+	}
 }
 
 func TestStaticAdapterDefaultInvoke(t *testing.T) {
@@ -113,6 +222,49 @@ func TestStaticAdapterDefaultInvoke(t *testing.T) {
 	}
 }
 
+func TestStaticAdapterInvokeStream(t *testing.T) {
+	t.Parallel()
+
+	req := InvocationRequest{
+		SessionID:            "sess-3",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-3",
+		ProviderInvocationID: "pvi-3",
+		ProviderID:           "s2s-a",
+		Modality:             ModalityS2S,
+		Attempt:              1,
+	}
+	streaming := StaticAdapter{
+		ID:   "s2s-a",
+		Mode: ModalityS2S,
+		InvokeStreamFn: func(_ InvocationRequest, onChunk func(StreamChunk) error) (Outcome, error) {
+			for _, text := range []string{"he", "llo"} {
+				if err := onChunk(StreamChunk{Text: text}); err != nil {
+					return Outcome{}, err
+				}
+			}
+			return Outcome{Class: OutcomeSuccess}, nil
+		},
+	}
+	var text string
+	outcome, err := streaming.InvokeStream(req, func(chunk StreamChunk) error {
+		text += chunk.Text
+		return nil
+	})
+	if err != nil || outcome.Class != OutcomeSuccess || text != "hello" {
+		t.Fatalf("expected streamed chunks in order, got text=%q outcome=%+v err=%v", text, outcome, err)
+	}
+
+	chunks := 0
+	outcome, err = StaticAdapter{ID: "s2s-a", Mode: ModalityS2S}.InvokeStream(req, func(StreamChunk) error {
+		chunks++
+		return nil
+	})
+	if err != nil || outcome.Class != OutcomeSuccess || chunks != 0 {
+		t.Fatalf("expected fallback to Invoke without chunks, got chunks=%d outcome=%+v err=%v", chunks, outcome, err)
+	}
+}
+
 func TestNormalizeAdaptiveActions(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,185 @@
+package healthscore
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+const (
+	// DefaultWindow is how many recent outcomes per provider a score covers.
+	DefaultWindow = 50
+	// DefaultMinSamples is how many outcomes a provider needs before it is scored.
+	DefaultMinSamples = 5
+	// DefaultLatencyTargetMS is the mean success latency at or below which latency does not
+	// lower a score.
+	DefaultLatencyTargetMS int64 = 800
+	// DefaultSwitchMargin is how much higher an alternative must score to displace the
+	// preferred provider.
+	DefaultSwitchMargin = 0.2
+)
+
+// Config configures a Scorer.
+type Config struct {
+	// Window is how many recent outcomes per provider a score covers. Defaults to DefaultWindow.
+	Window int
+	// MinSamples is the fewest windowed outcomes that make a provider's score usable for
+	// routing. Defaults to DefaultMinSamples and is capped at Window.
+	MinSamples int
+	// LatencyTargetMS defaults to DefaultLatencyTargetMS.
+	LatencyTargetMS int64
+	// SwitchMargin, in (0,1], defaults to DefaultSwitchMargin.
+	SwitchMargin float64
+}
+
+// Status is a point-in-time view of one provider's health score.
+type Status struct {
+	ProviderID    string
+	Modality      contracts.Modality
+	Samples       int
+	SuccessRate   float64
+	MeanLatencyMS int64
+	// Score is SuccessRate scaled down by mean success latency above the latency target, in
+	// [0,1]. Scored is false while the provider has fewer than MinSamples outcomes.
+	Score  float64
+	Scored bool
+}
+
+// Scorer aggregates recent outcome classes and latencies per provider into a health score
+// and picks the healthiest provider for a modality. Cancelled and blocked outcomes say
+// nothing about provider health and are not counted. It is safe for concurrent use.
+type Scorer struct {
+	mu        sync.Mutex
+	cfg       Config
+	providers map[string]*window
+}
+
+type window struct {
+	modality contracts.Modality
+	samples  []sample
+}
+
+type sample struct {
+	success   bool
+	latencyMS int64
+}
+
+// NewScorer returns a scorer with cfg defaults applied.
+func NewScorer(cfg Config) *Scorer {
+	if cfg.Window < 1 {
+		cfg.Window = DefaultWindow
+	}
+	if cfg.MinSamples < 1 {
+		cfg.MinSamples = DefaultMinSamples
+	}
+	if cfg.MinSamples > cfg.Window {
+		cfg.MinSamples = cfg.Window
+	}
+	if cfg.LatencyTargetMS < 1 {
+		cfg.LatencyTargetMS = DefaultLatencyTargetMS
+	}
+	if cfg.SwitchMargin <= 0 || cfg.SwitchMargin > 1 {
+		cfg.SwitchMargin = DefaultSwitchMargin
+	}
+	return &Scorer{cfg: cfg, providers: map[string]*window{}}
+}
+
+// Record folds one live attempt outcome into providerID's window.
+func (s *Scorer) Record(modality contracts.Modality, providerID string, class contracts.OutcomeClass, latencyMS int64) {
+	if providerID == "" || class == contracts.OutcomeCancelled || class == contracts.OutcomeBlocked {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, ok := s.providers[providerID]
+	if !ok {
+		w = &window{}
+		s.providers[providerID] = w
+	}
+	w.modality = modality
+	w.samples = append(w.samples, sample{success: class == contracts.OutcomeSuccess, latencyMS: max(0, latencyMS)})
+	if len(w.samples) > s.cfg.Window {
+		w.samples = w.samples[len(w.samples)-s.cfg.Window:]
+	}
+}
+
+// Preferred returns the provider to try first for modality. The preferred provider keeps its
+// place unless it is scored and a scored provider of the same modality beats it by more than
+// the switch margin. An empty or unscored preferred provider is returned unchanged.
+func (s *Scorer) Preferred(modality contracts.Modality, preferred string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if preferred == "" {
+		return preferred
+	}
+	current, ok := s.statusLocked(preferred)
+	if !ok || !current.Scored {
+		return preferred
+	}
+	best := current
+	for _, providerID := range s.sortedIDsLocked() {
+		status, _ := s.statusLocked(providerID)
+		if status.Modality != modality || !status.Scored {
+			continue
+		}
+		if status.Score > best.Score {
+			best = status
+		}
+	}
+	if best.Score-current.Score <= s.cfg.SwitchMargin {
+		return preferred
+	}
+	return best.ProviderID
+}
+
+// Statuses returns every tracked provider ordered by provider id.
+func (s *Scorer) Statuses() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := s.sortedIDsLocked()
+	statuses := make([]Status, 0, len(ids))
+	for _, providerID := range ids {
+		status, _ := s.statusLocked(providerID)
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func (s *Scorer) sortedIDsLocked() []string {
+	ids := make([]string, 0, len(s.providers))
+	for providerID := range s.providers {
+		ids = append(ids, providerID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (s *Scorer) statusLocked(providerID string) (Status, bool) {
+	w, ok := s.providers[providerID]
+	if !ok {
+		return Status{}, false
+	}
+	status := Status{ProviderID: providerID, Modality: w.modality, Samples: len(w.samples)}
+	if len(w.samples) == 0 {
+		return status, true
+	}
+	successes := 0
+	var latencyTotal int64
+	for _, sample := range w.samples {
+		if sample.success {
+			successes++
+			latencyTotal += sample.latencyMS
+		}
+	}
+	status.SuccessRate = float64(successes) / float64(len(w.samples))
+	if successes > 0 {
+		status.MeanLatencyMS = latencyTotal / int64(successes)
+	}
+	status.Score = status.SuccessRate
+	if status.MeanLatencyMS > s.cfg.LatencyTargetMS {
+		status.Score *= float64(s.cfg.LatencyTargetMS) / float64(status.MeanLatencyMS)
+	}
+	status.Scored = len(w.samples) >= s.cfg.MinSamples
+	return status, true
+}
@@ -0,0 +1,96 @@
+package healthscore
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+func recordN(scorer *Scorer, modality contracts.Modality, providerID string, class contracts.OutcomeClass, latencyMS int64, n int) {
+	for i := 0; i < n; i++ {
+		scorer.Record(modality, providerID, class, latencyMS)
+	}
+}
+
+func TestScorerScoresSuccessRateAndLatency(t *testing.T) {
+	t.Parallel()
+
+	scorer := NewScorer(Config{Window: 4, MinSamples: 2, LatencyTargetMS: 500})
+	recordN(scorer, contracts.ModalitySTT, "stt-a", contracts.OutcomeTimeout, 0, 3)
+	recordN(scorer, contracts.ModalitySTT, "stt-a", contracts.OutcomeSuccess, 1000, 2)
+	recordN(scorer, contracts.ModalitySTT, "stt-a", contracts.OutcomeCancelled, 0, 5)
+	scorer.Record(contracts.ModalitySTT, "stt-b", contracts.OutcomeSuccess, 100)
+
+	statuses := scorer.Statuses()
+	if len(statuses) != 2 {
+		t.Fatalf("expected two tracked providers, got %+v", statuses)
+	}
+	a, b := statuses[0], statuses[1]
+	if a.Samples != 4 || a.SuccessRate != 0.5 || a.MeanLatencyMS != 1000 || a.Score != 0.25 || !a.Scored {
+		t.Fatalf("expected a windowed, latency-penalized score for stt-a, got %+v", a)
+	}
+	if b.Samples != 1 || b.Score != 1 || b.Scored {
+		t.Fatalf("expected stt-b unscored below min samples, got %+v", b)
+	}
+}
+
+func TestScorerPreferredSwitchesOnlyPastMargin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		preferred string
+		record    func(*Scorer)
+		want      string
+	}{
+		{
+			name:      "unscored preferred is kept",
+			preferred: "llm-a",
+			record: func(s *Scorer) {
+				recordN(s, contracts.ModalityLLM, "llm-b", contracts.OutcomeSuccess, 100, 5)
+			},
+			want: "llm-a",
+		},
+		{
+			name:      "failing preferred yields to a healthy provider",
+			preferred: "llm-a",
+			record: func(s *Scorer) {
+				recordN(s, contracts.ModalityLLM, "llm-a", contracts.OutcomeInfrastructureFailure, 0, 5)
+				recordN(s, contracts.ModalityLLM, "llm-b", contracts.OutcomeSuccess, 100, 5)
+				recordN(s, contracts.ModalityLLM, "llm-c", contracts.OutcomeSuccess, 2000, 5)
+			},
+			want: "llm-b",
+		},
+		{
+			name:      "small advantage keeps preferred",
+			preferred: "llm-a",
+			record: func(s *Scorer) {
+				recordN(s, contracts.ModalityLLM, "llm-a", contracts.OutcomeSuccess, 100, 9)
+				recordN(s, contracts.ModalityLLM, "llm-a", contracts.OutcomeTimeout, 0, 1)
+				recordN(s, contracts.ModalityLLM, "llm-b", contracts.OutcomeSuccess, 100, 10)
+			},
+			want: "llm-a",
+		},
+		{
+			name:      "other modalities are not candidates",
+			preferred: "llm-a",
+			record: func(s *Scorer) {
+				recordN(s, contracts.ModalityLLM, "llm-a", contracts.OutcomeTimeout, 0, 5)
+				recordN(s, contracts.ModalityTTS, "tts-a", contracts.OutcomeSuccess, 100, 5)
+			},
+			want: "llm-a",
+		},
+		{
+			name:   "empty preferred is kept",
+			record: func(s *Scorer) { recordN(s, contracts.ModalityLLM, "llm-b", contracts.OutcomeSuccess, 100, 5) },
+			want:   "",
+		},
+	}
+	for _, tc := range tests {
+		scorer := NewScorer(Config{})
+		tc.record(scorer)
+		if got := scorer.Preferred(contracts.ModalityLLM, tc.preferred); got != tc.want {
+			t.Fatalf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package invocation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// DefaultSessionAffinityCapacity bounds remembered provider conversations.
+const DefaultSessionAffinityCapacity = 1024
+
+// SessionAffinityStore remembers provider-side conversation handles per session and provider
+// so later turns can continue the conversation instead of resending full context. It is safe
+// for concurrent use; the oldest handle is evicted once capacity is reached.
+type SessionAffinityStore struct {
+	mu       sync.Mutex
+	capacity int
+	handles  map[affinityKey]string
+	order    []affinityKey
+}
+
+type affinityKey struct {
+	sessionID  string
+	providerID string
+}
+
+// NewSessionAffinityStore returns a store holding at most capacity handles
+// (DefaultSessionAffinityCapacity when capacity < 1).
+func NewSessionAffinityStore(capacity int) *SessionAffinityStore {
+	if capacity < 1 {
+		capacity = DefaultSessionAffinityCapacity
+	}
+	return &SessionAffinityStore{capacity: capacity, handles: map[affinityKey]string{}}
+}
+
+// Lookup returns the remembered handle for sessionID on providerID, or "".
+func (s *SessionAffinityStore) Lookup(sessionID string, providerID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.handles[affinityKey{sessionID: sessionID, providerID: providerID}]
+}
+
+// Remember stores handle for sessionID on providerID; an empty handle forgets it.
+func (s *SessionAffinityStore) Remember(sessionID string, providerID string, handle string) {
+	key := affinityKey{sessionID: sessionID, providerID: providerID}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if handle == "" {
+		s.remove(key)
+		return
+	}
+	if _, ok := s.handles[key]; !ok {
+		if len(s.order) >= s.capacity {
+			oldest := s.order[0]
+			s.order = s.order[1:]
+			delete(s.handles, oldest)
+		}
+		s.order = append(s.order, key)
+	}
+	s.handles[key] = handle
+}
+
+// Forget drops every handle remembered for sessionID, for example when the session ends.
+func (s *SessionAffinityStore) Forget(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.handles {
+		if key.sessionID == sessionID {
+			s.remove(key)
+		}
+	}
+}
+
+// Len reports how many handles are remembered.
+func (s *SessionAffinityStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.handles)
+}
+
+func (s *SessionAffinityStore) remove(key affinityKey) {
+	if _, ok := s.handles[key]; !ok {
+		return
+	}
+	delete(s.handles, key)
+	for i, existing := range s.order {
+		if existing == key {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// ProviderSessionIDHash returns the evidence form of a provider conversation handle. Raw
+// handles can grant access to provider-side history, so only the hash is recorded.
+func ProviderSessionIDHash(handle string) string {
+	if handle == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(handle))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,221 @@
+package invocation
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+// Circuit breaker states recorded in attempt and provider decision evidence.
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+const (
+	// DefaultCircuitBreakerWindow is how many recent attempt outcomes the error rate covers.
+	DefaultCircuitBreakerWindow = 20
+	// DefaultCircuitBreakerMinSamples is how many outcomes the window needs before it can trip.
+	DefaultCircuitBreakerMinSamples = 5
+	// DefaultCircuitBreakerErrorRate is the windowed failure rate that opens the circuit.
+	DefaultCircuitBreakerErrorRate = 0.5
+	// DefaultCircuitBreakerCooldown is how long an open circuit rejects before a half-open probe.
+	DefaultCircuitBreakerCooldown = 30 * time.Second
+)
+
+// CircuitBreakerConfig configures a CircuitBreaker.
+type CircuitBreakerConfig struct {
+	// Window is how many recent outcomes per provider the error rate covers. Defaults to
+	// DefaultCircuitBreakerWindow.
+	Window int
+	// MinSamples is the fewest windowed outcomes that can trip the circuit. Defaults to
+	// DefaultCircuitBreakerMinSamples and is capped at Window.
+	MinSamples int
+	// ErrorRateThreshold is the windowed failure rate, in (0,1], that opens the circuit.
+	// Defaults to DefaultCircuitBreakerErrorRate.
+	ErrorRateThreshold float64
+	// Cooldown is how long an open circuit rejects attempts before admitting one half-open
+	// probe. Defaults to DefaultCircuitBreakerCooldown.
+	Cooldown time.Duration
+	// Now is the clock for cooldowns; nil uses time.Now.
+	Now func() time.Time
+}
+
+// CircuitBreakerStatus is a point-in-time view of one provider's circuit.
+type CircuitBreakerStatus struct {
+	ProviderID string
+	State      string
+	// Samples and ErrorRate describe the closed-state outcome window.
+	Samples   int
+	ErrorRate float64
+	// OpenedAt is when the circuit last opened; zero while it has never opened.
+	OpenedAt time.Time
+}
+
+// CircuitBreaker keeps per-provider closed/open/half-open circuit state across invocations so
+// a provider that keeps failing is skipped instead of being retried on every turn. A closed
+// circuit opens when the windowed failure rate reaches the threshold; after the cooldown one
+// half-open probe is admitted, and its outcome closes or reopens the circuit. It is safe for
+// concurrent use and is shared by copies of the controller that hold it.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	cfg       CircuitBreakerConfig
+	providers map[string]*circuit
+}
+
+type circuit struct {
+	state    string
+	failures []bool
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker returns a circuit breaker with cfg defaults applied.
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
+	if cfg.Window < 1 {
+		cfg.Window = DefaultCircuitBreakerWindow
+	}
+	if cfg.MinSamples < 1 {
+		cfg.MinSamples = DefaultCircuitBreakerMinSamples
+	}
+	if cfg.MinSamples > cfg.Window {
+		cfg.MinSamples = cfg.Window
+	}
+	if cfg.ErrorRateThreshold <= 0 || cfg.ErrorRateThreshold > 1 {
+		cfg.ErrorRateThreshold = DefaultCircuitBreakerErrorRate
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = DefaultCircuitBreakerCooldown
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &CircuitBreaker{cfg: cfg, providers: map[string]*circuit{}}
+}
+
+// Allow reports whether an attempt on providerID may start and the circuit state it runs
+// under. An open circuit past its cooldown moves to half-open and admits a single probe;
+// further attempts are rejected until the probe's outcome is recorded.
+func (b *CircuitBreaker) Allow(providerID string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuit(providerID)
+	switch c.state {
+	case CircuitOpen:
+		if b.cfg.Now().Sub(c.openedAt) < b.cfg.Cooldown {
+			return CircuitOpen, false
+		}
+		c.state = CircuitHalfOpen
+		c.probing = true
+		return CircuitHalfOpen, true
+	case CircuitHalfOpen:
+		if c.probing {
+			return CircuitHalfOpen, false
+		}
+		c.probing = true
+		return CircuitHalfOpen, true
+	default:
+		return CircuitClosed, true
+	}
+}
+
+// Record folds a live attempt outcome into providerID's circuit and reports whether the
+// outcome opened it. Cancelled and blocked outcomes say nothing about provider health: they
+// only release a pending half-open probe.
+func (b *CircuitBreaker) Record(providerID string, outcome contracts.Outcome) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuit(providerID)
+	if outcome.Class == contracts.OutcomeCancelled || outcome.Class == contracts.OutcomeBlocked {
+		c.probing = false
+		return false
+	}
+	failed := outcome.Class != contracts.OutcomeSuccess
+	switch c.state {
+	case CircuitHalfOpen:
+		c.probing = false
+		if !failed {
+			c.state = CircuitClosed
+			return false
+		}
+	case CircuitClosed:
+		c.failures = append(c.failures, failed)
+		if len(c.failures) > b.cfg.Window {
+			c.failures = c.failures[len(c.failures)-b.cfg.Window:]
+		}
+		if len(c.failures) < b.cfg.MinSamples || errorRate(c.failures) < b.cfg.ErrorRateThreshold {
+			return false
+		}
+	default:
+		return false
+	}
+	c.state = CircuitOpen
+	c.openedAt = b.cfg.Now()
+	c.failures = c.failures[:0]
+	return true
+}
+
+// Open forces providerID's circuit open, as an operator remediation would, and reports
+// whether it was not already open. The circuit then follows the normal cooldown and
+// half-open probe before it closes again.
+func (b *CircuitBreaker) Open(providerID string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuit(providerID)
+	opened := c.state != CircuitOpen
+	c.state = CircuitOpen
+	c.openedAt = b.cfg.Now()
+	c.failures = c.failures[:0]
+	c.probing = false
+	return opened
+}
+
+// release drops a pending half-open probe whose attempt never reached the provider.
+func (b *CircuitBreaker) release(providerID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.circuit(providerID).probing = false
+}
+
+// Statuses returns every tracked provider circuit ordered by provider id.
+func (b *CircuitBreaker) Statuses() []CircuitBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	statuses := make([]CircuitBreakerStatus, 0, len(b.providers))
+	for providerID, c := range b.providers {
+		statuses = append(statuses, CircuitBreakerStatus{
+			ProviderID: providerID,
+			State:      c.state,
+			Samples:    len(c.failures),
+			ErrorRate:  errorRate(c.failures),
+			OpenedAt:   c.openedAt,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ProviderID < statuses[j].ProviderID })
+	return statuses
+}
+
+func (b *CircuitBreaker) circuit(providerID string) *circuit {
+	c, ok := b.providers[providerID]
+	if !ok {
+		c = &circuit{state: CircuitClosed}
+		b.providers[providerID] = c
+	}
+	return c
+}
+
+func errorRate(failures []bool) float64 {
+	if len(failures) == 0 {
+		return 0
+	}
+	failed := 0
+	for _, f := range failures {
+		if f {
+			failed++
+		}
+	}
+	return float64(failed) / float64(len(failures))
+}
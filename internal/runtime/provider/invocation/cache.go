@@ -0,0 +1,249 @@
+package invocation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+const (
+	// DefaultResponseCacheMaxEntries bounds the response cache when no capacity is configured.
+	DefaultResponseCacheMaxEntries = 512
+	// DefaultResponseCacheTTL is how long a cached response is served when no TTL is configured.
+	DefaultResponseCacheTTL = 10 * time.Minute
+)
+
+// ResponseCacheConfig configures a ResponseCache.
+type ResponseCacheConfig struct {
+	// MaxEntries bounds cached responses; the oldest is evicted first. Defaults to
+	// DefaultResponseCacheMaxEntries.
+	MaxEntries int
+	// TTL is how long a stored response is served. Defaults to DefaultResponseCacheTTL.
+	TTL time.Duration
+	// Modalities lists the cacheable modalities. Defaults to LLM and TTS; STT results depend
+	// on live audio and are never worth caching.
+	Modalities []contracts.Modality
+	// Now is the clock for entry expiry; nil uses time.Now.
+	Now func() time.Time
+}
+
+// ResponseCacheKey identifies one cacheable provider invocation: the modality, the provider,
+// and a hash of the canonicalized request inputs that shape the provider output.
+type ResponseCacheKey struct {
+	Modality    contracts.Modality
+	ProviderID  string
+	RequestHash string
+}
+
+// ResponseCacheStats summarizes response cache effectiveness.
+type ResponseCacheStats struct {
+	Hits        int64
+	Misses      int64
+	Expirations int64
+	Evictions   int64
+	Entries     int
+}
+
+// HitRate returns hits over lookups, or 0 before the first lookup.
+func (s ResponseCacheStats) HitRate() float64 {
+	lookups := s.Hits + s.Misses
+	if lookups == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(lookups)
+}
+
+// ResponseCache memoizes successful provider outcomes so repeated identical invocations,
+// as in replay and regression runs, are served without calling the live provider. It is safe
+// for concurrent use and is shared by copies of the controller that hold it.
+type ResponseCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	modalities []contracts.Modality
+	now        func() time.Time
+	entries    map[ResponseCacheKey]responseCacheEntry
+	order      []ResponseCacheKey
+	stats      ResponseCacheStats
+}
+
+type responseCacheEntry struct {
+	outcome  contracts.Outcome
+	storedAt time.Time
+}
+
+// NewResponseCache returns a response cache with cfg defaults applied.
+func NewResponseCache(cfg ResponseCacheConfig) *ResponseCache {
+	if cfg.MaxEntries < 1 {
+		cfg.MaxEntries = DefaultResponseCacheMaxEntries
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultResponseCacheTTL
+	}
+	if len(cfg.Modalities) == 0 {
+		cfg.Modalities = []contracts.Modality{contracts.ModalityLLM, contracts.ModalityTTS}
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &ResponseCache{
+		maxEntries: cfg.MaxEntries,
+		ttl:        cfg.TTL,
+		modalities: append([]contracts.Modality(nil), cfg.Modalities...),
+		now:        cfg.Now,
+		entries:    make(map[ResponseCacheKey]responseCacheEntry),
+	}
+}
+
+// Caches reports whether invocations of modality are cacheable.
+func (c *ResponseCache) Caches(modality contracts.Modality) bool {
+	return slices.Contains(c.modalities, modality)
+}
+
+// Get returns the cached outcome for key. An entry older than the TTL is dropped and counts
+// as a miss.
+func (c *ResponseCache) Get(key ResponseCacheKey) (contracts.Outcome, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if ok && c.now().Sub(entry.storedAt) >= c.ttl {
+		c.remove(key)
+		c.stats.Expirations++
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		return contracts.Outcome{}, false
+	}
+	c.stats.Hits++
+	return entry.outcome, true
+}
+
+// Put stores a successful outcome under key. Other outcome classes are ignored so failures
+// are always retried against the live provider.
+func (c *ResponseCache) Put(key ResponseCacheKey, outcome contracts.Outcome) {
+	if outcome.Class != contracts.OutcomeSuccess {
+		return
+	}
+	// A served response consumed no provider tokens, latency, or conversation state.
+	outcome.Usage = nil
+	outcome.FirstChunkLatencyMS = 0
+	outcome.ProviderSessionID = ""
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		// Evict oldest-first so capacity pressure is deterministic.
+		for len(c.order) >= c.maxEntries {
+			delete(c.entries, c.order[0])
+			c.order = c.order[1:]
+			c.stats.Evictions++
+		}
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = responseCacheEntry{outcome: outcome, storedAt: c.now()}
+}
+
+// Stats returns a snapshot of cache counters.
+func (c *ResponseCache) Stats() ResponseCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	return stats
+}
+
+func (c *ResponseCache) remove(key ResponseCacheKey) {
+	delete(c.entries, key)
+	if idx := slices.Index(c.order, key); idx >= 0 {
+		c.order = slices.Delete(c.order, idx, idx+1)
+	}
+}
+
+// ResponseKey derives the cache key for req. Only inputs that shape the provider output are
+// hashed: session, turn, and event identity, sequencing, timestamps, attempt, deadline, and
+// trace context differ across replays of the same invocation and are excluded.
+func ResponseKey(req contracts.InvocationRequest) (ResponseCacheKey, error) {
+	canonical := struct {
+		PipelineVersion  string                    `json:"pipeline_version"`
+		MaxOutputTokens  int                       `json:"max_output_tokens"`
+		MaxAudioOutputMS int64                     `json:"max_audio_output_ms"`
+		Endpointing      *contracts.Endpointing    `json:"endpointing"`
+		Locale           *contracts.Locale         `json:"locale"`
+		SessionSummary   *contracts.SessionSummary `json:"session_summary"`
+		SessionMetadata  map[string]string         `json:"session_metadata"`
+		SamplingSeed     *int64                    `json:"sampling_seed"`
+		Prompt           string                    `json:"prompt,omitempty"`
+		Audio            *contracts.AudioInput     `json:"audio,omitempty"`
+		Text             string                    `json:"text,omitempty"`
+	}{
+		PipelineVersion:  req.PipelineVersion,
+		MaxOutputTokens:  req.MaxOutputTokens,
+		MaxAudioOutputMS: req.MaxAudioOutputMS,
+		Endpointing:      req.Endpointing,
+		Locale:           req.Locale,
+		SessionSummary:   req.SessionSummary,
+		SessionMetadata:  req.SessionMetadata,
+		SamplingSeed:     req.SamplingSeed,
+		Prompt:           req.Prompt,
+		Audio:            req.Audio,
+		Text:             req.Text,
+	}
+	if len(canonical.SessionMetadata) == 0 {
+		canonical.SessionMetadata = nil
+	}
+	raw, err := json.Marshal(canonical)
+	if err != nil {
+		return ResponseCacheKey{}, fmt.Errorf("hash provider response cache inputs: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	return ResponseCacheKey{Modality: req.Modality, ProviderID: req.ProviderID, RequestHash: hex.EncodeToString(sum[:])}, nil
+}
+
+// responseCacheKey reports the cache key for an attempt, or false when the attempt bypasses
+// the cache: caching is off, the modality is not cached, or the attempt continues a provider
+// conversation whose output depends on provider-side state.
+func (c Controller) responseCacheKey(req contracts.InvocationRequest) (ResponseCacheKey, bool, error) {
+	cache := c.cfg.ResponseCache
+	if cache == nil || !cache.Caches(req.Modality) || req.ProviderSessionID != "" {
+		return ResponseCacheKey{}, false, nil
+	}
+	key, err := ResponseKey(req)
+	if err != nil {
+		return ResponseCacheKey{}, false, err
+	}
+	return key, true, nil
+}
+
+func emitResponseCacheLookup(in InvocationInput, providerID string, hit bool) {
+	value := 0.0
+	if hit {
+		value = 1
+	}
+	telemetry.DefaultEmitter().EmitMetric(
+		telemetry.MetricProviderCacheHit,
+		value,
+		"1",
+		map[string]string{
+			"provider_id": providerID,
+			"modality":    string(in.Modality),
+		},
+		telemetry.Correlation{
+			SessionID:          in.SessionID,
+			TurnID:             in.TurnID,
+			EventID:            in.EventID,
+			PipelineVersion:    in.PipelineVersion,
+			AuthorityEpoch:     nonNegative(in.AuthorityEpoch),
+			Lane:               string(eventabi.LaneTelemetry),
+			EmittedBy:          "OR-01",
+			RuntimeTimestampMS: nonNegative(in.RuntimeTimestampMS),
+		},
+	)
+}
@@ -2,23 +2,71 @@ package invocation
 
 import (
 	"fmt"
+	"maps"
 	"strconv"
+	"time"
 
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/prelude"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/backoff"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
 )
 
 // Config controls deterministic RK-11 invocation behavior.
 type Config struct {
 	MaxAttemptsPerProvider int
 	MaxCandidateProviders  int
+	// Backoff paces same-provider retries. Each delay is waited on Clock before the retry and
+	// recorded as attempt BackoffMS evidence; jitter is seeded per provider invocation so
+	// replays match.
+	Backoff backoff.Policy
+	// SessionAffinity remembers LLM provider conversation handles across turns. Nil uses a
+	// store with DefaultSessionAffinityCapacity.
+	SessionAffinity *SessionAffinityStore
+	// MinAttemptBudgetMS is the smallest attempt deadline worth starting under a turn budget;
+	// a retry or provider switch that would get less is skipped as futile. Defaults to
+	// DefaultMinAttemptBudgetMS.
+	MinAttemptBudgetMS int64
+	// Now is the wall clock for turn budget accounting; nil uses Clock.Now.
+	Now func() time.Time
+	// Clock waits retry backoff. Nil uses the real clock reading Now, so tests that inject a
+	// virtual clock here see backoff advance the turn budget without sleeping.
+	Clock clock.Clock
+	// ResponseCache serves repeated identical invocations without calling the provider; nil
+	// disables response caching.
+	ResponseCache *ResponseCache
+	// CircuitBreaker keeps per-provider breaker state across invocations; open providers are
+	// skipped. Nil disables circuit breaking.
+	CircuitBreaker *CircuitBreaker
+}
+
+// DefaultMinAttemptBudgetMS is the default smallest attempt deadline under a turn budget.
+const DefaultMinAttemptBudgetMS int64 = 100
+
+// turnBudgetExhaustedReason is the outcome reason when no attempt fits the turn budget.
+const turnBudgetExhaustedReason = "turn_budget_exhausted"
+
+// circuitOpenReason is the outcome reason when every candidate provider's circuit is open.
+const circuitOpenReason = "circuit_open"
+
+// DefaultBackoffPolicy returns the RK-11 retry pacing policy.
+func DefaultBackoffPolicy() backoff.Policy {
+	return backoff.Policy{Base: 50 * time.Millisecond, Max: time.Second, Jitter: backoff.JitterDecorrelated}
+}
+
+// CandidateSource resolves ordered candidate adapters for an invocation, e.g.
+// registry.Catalog or a registry.LiveCatalog that is swapped on provider catalog reload.
+type CandidateSource interface {
+	Candidates(modality contracts.Modality, preferredProvider string, maxProviders int) ([]contracts.Adapter, error)
 }
 
 // Controller executes deterministic provider invocation attempts.
 type Controller struct {
-	catalog registry.Catalog
+	catalog CandidateSource
 	cfg     Config
 }
 
@@ -38,6 +86,34 @@ type InvocationInput struct {
 	RuntimeTimestampMS     int64
 	WallClockTimestampMS   int64
 	CancelRequested        bool
+	MaxOutputTokens        int
+	// MaxAudioOutputMS caps TTS audio duration when >0.
+	MaxAudioOutputMS int64
+	// CancelSignal is closed when the turn is cancelled mid-invocation.
+	CancelSignal <-chan struct{}
+	// Endpointing is forwarded to STT adapters with server-side endpointing; other adapters
+	// leave endpointing to the session VAD.
+	Endpointing *contracts.Endpointing
+	// Locale is the session locale forwarded to every adapter attempt.
+	Locale *contracts.Locale
+	// SessionSummary is the bound session summary forwarded to every adapter attempt.
+	SessionSummary *contracts.SessionSummary
+	// SessionMetadata is the session enrichment metadata forwarded to LLM adapter attempts.
+	SessionMetadata map[string]string
+	// SamplingSeed is the deterministic sampling seed forwarded to seed-capable LLM adapters.
+	SamplingSeed *int64
+	// Prompt replaces the adapters' configured prompt template on LLM attempts when set.
+	Prompt string
+	// Audio is the captured utterance forwarded to STT and S2S adapter attempts.
+	Audio *contracts.AudioInput
+	// Text replaces the adapters' configured sample text on TTS attempts when set.
+	Text string
+	// Trace is the scheduler node span; each attempt opens a child span propagated to the provider.
+	Trace telemetry.TraceContext
+	// TurnBudgetRemainingMS is the turn latency budget left when the invocation starts. When
+	// >0 every attempt's deadline is what remains after earlier attempts and their backoff;
+	// 0 leaves attempts on adapter timeouts.
+	TurnBudgetRemainingMS int64
 }
 
 // InvocationAttempt records one provider attempt with normalized outcome.
@@ -45,6 +121,35 @@ type InvocationAttempt struct {
 	ProviderID string
 	Attempt    int
 	Outcome    contracts.Outcome
+	// BackoffMS is the delay applied before the next same-provider retry (0 when none).
+	BackoffMS int64
+	// SessionAffinity is the LLM provider-session decision for this attempt; empty for other
+	// modalities. ProviderSessionIDHash identifies the handle reused or established.
+	SessionAffinity       string
+	ProviderSessionIDHash string
+	// SamplingSeedStatus is applied, ignored, or unsupported for seeded LLM attempts; empty
+	// when no sampling seed was requested.
+	SamplingSeedStatus string
+	// TurnBudgetRemainingMS, BudgetElapsedMS, and DeadlineMS record the attempt's budget math:
+	// DeadlineMS = TurnBudgetRemainingMS - BudgetElapsedMS, where elapsed counts earlier
+	// attempts and their backoff. All zero when the turn is unbudgeted.
+	TurnBudgetRemainingMS int64
+	BudgetElapsedMS       int64
+	DeadlineMS            int64
+	// BudgetExhausted marks the attempt after which a retry or provider switch was skipped
+	// because the remaining budget was below MinAttemptBudgetMS.
+	BudgetExhausted bool
+	// CacheHit marks an attempt served from the response cache without calling the provider.
+	CacheHit bool
+	// LatencyMS is the measured adapter call duration; 0 for cache hits.
+	LatencyMS int64
+	// WarmStandby marks an attempt issued over the provider's held warm-standby connection.
+	WarmStandby bool
+	// CircuitState is the breaker state the attempt was admitted under (closed or half_open);
+	// CircuitTripped marks the attempt whose failure opened the circuit. Empty when circuit
+	// breaking is off.
+	CircuitState   string
+	CircuitTripped bool
 }
 
 // InvocationResult summarizes deterministic invocation behavior.
@@ -55,6 +160,33 @@ type InvocationResult struct {
 	RetryDecision        string
 	Attempts             []InvocationAttempt
 	Signals              []eventabi.ControlSignal
+	// EndpointingEnforcement records who applied endpointing for the selected provider: the
+	// provider itself, or the runtime endpointer on the captured audio (vad_emulated). It is
+	// empty when endpointing was not requested or there was no captured audio to endpoint.
+	EndpointingEnforcement string
+	// EndpointKind is the runtime endpointer's decision (prelude.EndpointKind) when it
+	// endpointed the audio; a no_speech_timeout skipped the provider call.
+	EndpointKind string
+	// SessionAffinity and ProviderSessionIDHash mirror the final LLM attempt's affinity decision.
+	SessionAffinity       string
+	ProviderSessionIDHash string
+	// LengthCapped is set when the successful output stopped at an output length cap.
+	LengthCapped bool
+	// SamplingSeed is the requested LLM sampling seed; SamplingSeedStatus mirrors the final
+	// attempt's seeding status.
+	SamplingSeed       *int64
+	SamplingSeedStatus string
+	// BudgetExhausted is set when the turn budget cut the invocation short: no attempt fit, or
+	// a retry or provider switch was skipped as futile.
+	BudgetExhausted bool
+	// CacheHit mirrors the final attempt's response cache hit.
+	CacheHit bool
+	// WarmStandby mirrors the final attempt's warm-standby connection use.
+	WarmStandby bool
+	// CircuitState mirrors the final attempt's circuit state, or is open when every candidate
+	// was skipped. CircuitSkipped lists providers skipped because their circuit was open.
+	CircuitState   string
+	CircuitSkipped []string
 }
 
 // NewController returns a controller with defaults suitable for MVP.
@@ -64,13 +196,39 @@ func NewController(catalog registry.Catalog) Controller {
 
 // NewControllerWithConfig builds a controller with explicit limits.
 func NewControllerWithConfig(catalog registry.Catalog, cfg Config) Controller {
+	return NewControllerWithSource(catalog, cfg)
+}
+
+// NewControllerWithSource builds a controller that resolves candidates from source on every
+// invocation, so a live catalog swap takes effect on the next invocation.
+func NewControllerWithSource(source CandidateSource, cfg Config) Controller {
 	if cfg.MaxAttemptsPerProvider < 1 {
 		cfg.MaxAttemptsPerProvider = 2
 	}
 	if cfg.MaxCandidateProviders < 1 {
 		cfg.MaxCandidateProviders = 5
 	}
-	return Controller{catalog: catalog, cfg: cfg}
+	if cfg.Backoff.Base <= 0 {
+		cfg.Backoff = DefaultBackoffPolicy()
+	}
+	cfg.Backoff = cfg.Backoff.Normalize(DefaultBackoffPolicy().Base)
+	if cfg.SessionAffinity == nil {
+		cfg.SessionAffinity = NewSessionAffinityStore(0)
+	}
+	if cfg.MinAttemptBudgetMS < 1 {
+		cfg.MinAttemptBudgetMS = DefaultMinAttemptBudgetMS
+	}
+	if cfg.Clock == nil {
+		if cfg.Now != nil {
+			cfg.Clock = clock.WithNow(cfg.Now)
+		} else {
+			cfg.Clock = clock.Real()
+		}
+	}
+	if cfg.Now == nil {
+		cfg.Now = cfg.Clock.Now
+	}
+	return Controller{catalog: source, cfg: cfg}
 }
 
 // Invoke executes deterministic provider attempt/retry/switch behavior.
@@ -79,6 +237,9 @@ func (c Controller) Invoke(in InvocationInput) (InvocationResult, error) {
 		return InvocationResult{}, err
 	}
 
+	if c.catalog == nil {
+		return InvocationResult{}, fmt.Errorf("provider catalog is not configured")
+	}
 	candidates, err := c.catalog.Candidates(in.Modality, in.PreferredProvider, c.cfg.MaxCandidateProviders)
 	if err != nil {
 		return InvocationResult{}, err
@@ -90,6 +251,10 @@ func (c Controller) Invoke(in InvocationInput) (InvocationResult, error) {
 		Attempts:             make([]InvocationAttempt, 0, c.cfg.MaxAttemptsPerProvider*len(candidates)),
 		Signals:              make([]eventabi.ControlSignal, 0),
 	}
+	if in.SamplingSeed != nil {
+		seed := *in.SamplingSeed
+		result.SamplingSeed = &seed
+	}
 
 	if in.CancelRequested {
 		result.SelectedProvider = candidates[0].ProviderID()
@@ -125,7 +290,35 @@ func (c Controller) Invoke(in InvocationInput) (InvocationResult, error) {
 	if err != nil {
 		return InvocationResult{}, err
 	}
+	budget := newTurnBudget(in.TurnBudgetRemainingMS, c.cfg.Now)
+	if !budget.fits(c.cfg.MinAttemptBudgetMS, 0) {
+		result.SelectedProvider = candidates[0].ProviderID()
+		result.Outcome = contracts.Outcome{Class: contracts.OutcomeTimeout, Retryable: false, Reason: turnBudgetExhaustedReason}
+		result.BudgetExhausted = true
+		return result, nil
+	}
 	for providerIndex, adapter := range candidates {
+		circuitState, allowed := c.allowCircuit(adapter.ProviderID())
+		if !allowed {
+			if err := c.appendSignal(&result, in, "circuit_event", fmt.Sprintf("provider=%s state=%s skipped", adapter.ProviderID(), circuitState)); err != nil {
+				return InvocationResult{}, err
+			}
+			result.CircuitSkipped = append(result.CircuitSkipped, adapter.ProviderID())
+			if providerIndex < len(candidates)-1 && (actions.providerSwitch || actions.fallback) {
+				if err := c.switchProvider(&result, in, actions, adapter.ProviderID(), candidates[providerIndex+1].ProviderID()); err != nil {
+					return InvocationResult{}, err
+				}
+				continue
+			}
+			if len(result.Attempts) == 0 {
+				result.SelectedProvider = adapter.ProviderID()
+				result.Outcome = contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: false, Reason: circuitOpenReason, CircuitOpen: true}
+				result.CircuitState = CircuitOpen
+			}
+			return result, nil
+		}
+		delays := c.cfg.Backoff.NewSequence(result.ProviderInvocationID + "|" + adapter.ProviderID())
+		affinity := c.newAttemptAffinity(in, adapter)
 		for attempt := 1; attempt <= c.cfg.MaxAttemptsPerProvider; attempt++ {
 			req := contracts.InvocationRequest{
 				SessionID:              in.SessionID,
@@ -145,18 +338,100 @@ func (c Controller) Invoke(in InvocationInput) (InvocationResult, error) {
 				AllowedAdaptiveActions: append([]string(nil), actions.normalized...),
 				RetryBudgetRemaining:   max(0, c.cfg.MaxAttemptsPerProvider-attempt),
 				CandidateProviderCount: len(candidates),
+				MaxOutputTokens:        in.MaxOutputTokens,
+				MaxAudioOutputMS:       in.MaxAudioOutputMS,
+				CancelSignal:           in.CancelSignal,
+				ProviderSessionID:      affinity.handle,
+			}
+			elapsedMS := budget.elapsedMS()
+			req.DeadlineMS = budget.deadlineMS()
+			if in.SamplingSeed != nil && contracts.SupportsSamplingSeed(adapter) {
+				seed := *in.SamplingSeed
+				req.SamplingSeed = &seed
+			}
+			attemptTrace := in.Trace.ChildSpan(fmt.Sprintf("%s|%s|%d", result.ProviderInvocationID, adapter.ProviderID(), attempt))
+			req.Traceparent = attemptTrace.Traceparent()
+			result.EndpointingEnforcement, result.EndpointKind = "", ""
+			if in.Endpointing != nil && contracts.SupportsServerEndpointing(adapter) {
+				endpointing := *in.Endpointing
+				req.Endpointing = &endpointing
+				result.EndpointingEnforcement = contracts.EndpointingEnforcementProvider
+			}
+			if in.Locale != nil {
+				locale := *in.Locale
+				locale.STTLanguageHints = append([]string(nil), in.Locale.STTLanguageHints...)
+				req.Locale = &locale
 			}
-			outcome, invokeErr := adapter.Invoke(req)
-			if invokeErr != nil {
-				outcome = contracts.Outcome{
-					Class:     contracts.OutcomeInfrastructureFailure,
-					Retryable: true,
-					Reason:    "adapter_invoke_error",
+			if in.SessionSummary != nil && in.Modality == contracts.ModalityLLM {
+				summary := *in.SessionSummary
+				req.SessionSummary = &summary
+			}
+			if len(in.SessionMetadata) > 0 && in.Modality == contracts.ModalityLLM {
+				req.SessionMetadata = maps.Clone(in.SessionMetadata)
+			}
+			if in.Modality == contracts.ModalityLLM {
+				req.Prompt = in.Prompt
+			}
+			if in.Audio != nil && (in.Modality == contracts.ModalitySTT || in.Modality == contracts.ModalityS2S) {
+				req.Audio = in.Audio
+			}
+			if in.Modality == contracts.ModalityTTS {
+				req.Text = in.Text
+			}
+			noSpeech := false
+			if in.Endpointing != nil && req.Audio != nil && req.Endpointing == nil {
+				endpoint, utterance, err := prelude.EndpointAudio(controlplane.STTEndpointing{
+					SilenceDurationMS: in.Endpointing.SilenceDurationMS,
+					MinUtteranceMS:    in.Endpointing.MinUtteranceMS,
+					MaxUtteranceMS:    in.Endpointing.MaxUtteranceMS,
+					NoSpeechTimeoutMS: in.Endpointing.NoSpeechTimeoutMS,
+				}, req.Audio.PCM, req.Audio.SampleRateHz, prelude.DefaultSpeechRMS)
+				if err != nil {
+					return InvocationResult{}, err
+				}
+				req.Audio = &contracts.AudioInput{PCM: utterance, SampleRateHz: req.Audio.SampleRateHz}
+				result.EndpointingEnforcement = contracts.EndpointingEnforcementVADEmulated
+				result.EndpointKind = string(endpoint.Kind)
+				noSpeech = utterance == nil
+			}
+			cacheKey, cacheable, err := c.responseCacheKey(req)
+			if err != nil {
+				return InvocationResult{}, err
+			}
+			var outcome contracts.Outcome
+			cacheHit := false
+			if cacheable {
+				outcome, cacheHit = c.cfg.ResponseCache.Get(cacheKey)
+				emitResponseCacheLookup(in, adapter.ProviderID(), cacheHit)
+			}
+			var latencyMS int64
+			warmStandby := !cacheHit && !noSpeech && contracts.HoldsStandby(adapter)
+			if noSpeech {
+				// The runtime endpointer found no utterance: the turn has nothing to
+				// transcribe, so the provider is not called.
+				outcome = contracts.Outcome{Class: contracts.OutcomeSuccess}
+			} else if !cacheHit {
+				var invokeErr error
+				invokeStarted := c.cfg.Now()
+				outcome, invokeErr = adapter.Invoke(req)
+				latencyMS = nonNegative(c.cfg.Now().Sub(invokeStarted).Milliseconds())
+				if invokeErr != nil {
+					outcome = contracts.Outcome{
+						Class:     contracts.OutcomeInfrastructureFailure,
+						Retryable: true,
+						Reason:    "adapter_invoke_error",
+					}
 				}
 			}
 			if err := outcome.Validate(); err != nil {
 				return InvocationResult{}, err
 			}
+			if cacheable && !cacheHit && !noSpeech {
+				c.cfg.ResponseCache.Put(cacheKey, outcome)
+			}
+			tripped := c.recordCircuit(adapter.ProviderID(), circuitState, outcome, cacheHit || noSpeech)
+			affinityDecision, affinityHash := affinity.observe(outcome)
+			seedStatus := samplingSeedStatus(in, adapter, outcome)
 			attemptStartMS := nonNegative(in.RuntimeTimestampMS) + int64(attempt-1)
 			attemptLatencyMS := nonNegative(outcome.BackoffMS)
 			attemptEndMS := attemptStartMS + attemptLatencyMS
@@ -201,7 +476,7 @@ func (c Controller) Invoke(in InvocationInput) (InvocationResult, error) {
 					Lane:               string(eventabi.LaneTelemetry),
 					EmittedBy:          "OR-01",
 					RuntimeTimestampMS: attemptStartMS,
-				},
+				}.WithTrace(attemptTrace),
 			)
 			logSeverity := "info"
 			if outcome.Class != contracts.OutcomeSuccess {
@@ -231,14 +506,45 @@ func (c Controller) Invoke(in InvocationInput) (InvocationResult, error) {
 			)
 
 			result.Attempts = append(result.Attempts, InvocationAttempt{
-				ProviderID: adapter.ProviderID(),
-				Attempt:    attempt,
-				Outcome:    outcome,
+				ProviderID:            adapter.ProviderID(),
+				Attempt:               attempt,
+				Outcome:               outcome,
+				SessionAffinity:       affinityDecision,
+				ProviderSessionIDHash: affinityHash,
+				SamplingSeedStatus:    seedStatus,
+				CacheHit:              cacheHit,
+				LatencyMS:             latencyMS,
+				WarmStandby:           warmStandby,
+				CircuitState:          circuitState,
+				CircuitTripped:        tripped,
 			})
+			if budget.bounded() {
+				last := &result.Attempts[len(result.Attempts)-1]
+				last.TurnBudgetRemainingMS = budget.remainingMS
+				last.BudgetElapsedMS = elapsedMS
+				last.DeadlineMS = req.DeadlineMS
+			}
 			result.SelectedProvider = adapter.ProviderID()
 			result.Outcome = outcome
+			result.SessionAffinity = affinityDecision
+			result.ProviderSessionIDHash = affinityHash
+			result.SamplingSeedStatus = seedStatus
+			result.CacheHit = cacheHit
+			result.WarmStandby = warmStandby
+			result.CircuitState = circuitState
 
 			if outcome.Class == contracts.OutcomeSuccess {
+				if reason, capped := lengthCapReason(in, outcome); capped {
+					result.LengthCapped = true
+					if err := c.appendSignal(&result, in, "length_capped", reason); err != nil {
+						return InvocationResult{}, err
+					}
+				}
+				return result, nil
+			}
+			// A cancelled turn is terminal for the invocation: never retried, switched, or
+			// reported as a provider error.
+			if outcome.Class == contracts.OutcomeCancelled {
 				return result, nil
 			}
 
@@ -250,24 +556,44 @@ func (c Controller) Invoke(in InvocationInput) (InvocationResult, error) {
 					return InvocationResult{}, err
 				}
 			}
+			// A provider whose circuit just opened is not retried.
+			if tripped {
+				if err := c.appendSignal(&result, in, "circuit_event", fmt.Sprintf("provider=%s state=%s", adapter.ProviderID(), CircuitOpen)); err != nil {
+					return InvocationResult{}, err
+				}
+				break
+			}
 
 			if outcome.Retryable && actions.retry && attempt < c.cfg.MaxAttemptsPerProvider {
-				result.RetryDecision = "retry"
-				continue
+				if delay, ok := delays.Next(); ok {
+					backoffMS := delay.Milliseconds()
+					if outcome.BackoffMS > backoffMS {
+						backoffMS = outcome.BackoffMS
+					}
+					// A retry that cannot get a useful deadline after its backoff is futile.
+					if !budget.fits(c.cfg.MinAttemptBudgetMS, backoffMS) {
+						markBudgetExhausted(&result)
+						return result, nil
+					}
+					result.Attempts[len(result.Attempts)-1].BackoffMS = backoffMS
+					result.RetryDecision = "retry"
+					if !c.waitBackoff(in.CancelSignal, backoffMS) {
+						result.Outcome = contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "cancel_requested_during_backoff"}
+						return result, nil
+					}
+					continue
+				}
 			}
 			break
 		}
 
 		if providerIndex < len(candidates)-1 && (actions.providerSwitch || actions.fallback) {
-			nextProvider := candidates[providerIndex+1].ProviderID()
-			switchReason := fmt.Sprintf("from=%s to=%s", adapter.ProviderID(), nextProvider)
-			if err := c.appendSignal(&result, in, "provider_switch", switchReason); err != nil {
-				return InvocationResult{}, err
+			if !budget.fits(c.cfg.MinAttemptBudgetMS, 0) {
+				markBudgetExhausted(&result)
+				return result, nil
 			}
-			if actions.providerSwitch {
-				result.RetryDecision = "provider_switch"
-			} else {
-				result.RetryDecision = "fallback"
+			if err := c.switchProvider(&result, in, actions, adapter.ProviderID(), candidates[providerIndex+1].ProviderID()); err != nil {
+				return InvocationResult{}, err
 			}
 			continue
 		}
@@ -277,10 +603,202 @@ func (c Controller) Invoke(in InvocationInput) (InvocationResult, error) {
 	return result, nil
 }
 
+// switchProvider records a move to the next candidate provider.
+func (c Controller) switchProvider(result *InvocationResult, in InvocationInput, actions adaptiveActions, from string, to string) error {
+	if err := c.appendSignal(result, in, "provider_switch", fmt.Sprintf("from=%s to=%s", from, to)); err != nil {
+		return err
+	}
+	if actions.providerSwitch {
+		result.RetryDecision = "provider_switch"
+	} else {
+		result.RetryDecision = "fallback"
+	}
+	return nil
+}
+
+// allowCircuit admits an attempt on providerID; with circuit breaking off every attempt is
+// admitted with no state.
+func (c Controller) allowCircuit(providerID string) (string, bool) {
+	if c.cfg.CircuitBreaker == nil {
+		return "", true
+	}
+	return c.cfg.CircuitBreaker.Allow(providerID)
+}
+
+// recordCircuit folds an attempt outcome into the provider's circuit and reports whether the
+// attempt opened it. Cached responses never reached the provider and only release a probe.
+func (c Controller) recordCircuit(providerID string, admittedState string, outcome contracts.Outcome, cacheHit bool) bool {
+	breaker := c.cfg.CircuitBreaker
+	if breaker == nil {
+		return false
+	}
+	if cacheHit {
+		if admittedState == CircuitHalfOpen {
+			breaker.release(providerID)
+		}
+		return false
+	}
+	return breaker.Record(providerID, outcome)
+}
+
+// waitBackoff waits backoffMS on the controller clock before a retry. It returns false when
+// cancel closes first, so a cancelled turn never starts another attempt.
+func (c Controller) waitBackoff(cancel <-chan struct{}, backoffMS int64) bool {
+	if backoffMS <= 0 {
+		return true
+	}
+	timer := c.cfg.Clock.NewTimer(time.Duration(backoffMS) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return true
+	case <-cancel:
+		return false
+	}
+}
+
+// turnBudget accounts attempt deadlines against the turn latency budget left when the
+// invocation started. Elapsed time is wall time since then, which includes waited backoff.
+type turnBudget struct {
+	remainingMS int64
+	started     time.Time
+	now         func() time.Time
+}
+
+func newTurnBudget(remainingMS int64, now func() time.Time) *turnBudget {
+	return &turnBudget{remainingMS: nonNegative(remainingMS), started: now(), now: now}
+}
+
+// bounded reports whether the invocation runs under a turn budget.
+func (b *turnBudget) bounded() bool {
+	return b.remainingMS > 0
+}
+
+func (b *turnBudget) elapsedMS() int64 {
+	if !b.bounded() {
+		return 0
+	}
+	return nonNegative(b.now().Sub(b.started).Milliseconds())
+}
+
+// deadlineMS returns the next attempt's deadline, or 0 when unbounded.
+func (b *turnBudget) deadlineMS() int64 {
+	if !b.bounded() {
+		return 0
+	}
+	return nonNegative(b.remainingMS - b.elapsedMS())
+}
+
+// fits reports whether an attempt started after waitMS would still get at least minMS.
+func (b *turnBudget) fits(minMS int64, waitMS int64) bool {
+	return !b.bounded() || b.remainingMS-b.elapsedMS()-waitMS >= minMS
+}
+
+func markBudgetExhausted(result *InvocationResult) {
+	result.BudgetExhausted = true
+	if len(result.Attempts) > 0 {
+		result.Attempts[len(result.Attempts)-1].BudgetExhausted = true
+	}
+}
+
+// attemptAffinity tracks one provider's conversation handle across the attempts of an
+// LLM invocation. Adapters without conversation support are always stateless.
+type attemptAffinity struct {
+	store          *SessionAffinityStore
+	sessionID      string
+	providerID     string
+	applies        bool
+	conversational bool
+	handle         string
+	fellBack       bool
+}
+
+func (c Controller) newAttemptAffinity(in InvocationInput, adapter contracts.Adapter) *attemptAffinity {
+	affinity := &attemptAffinity{
+		store:      c.cfg.SessionAffinity,
+		sessionID:  in.SessionID,
+		providerID: adapter.ProviderID(),
+		applies:    in.Modality == contracts.ModalityLLM && c.cfg.SessionAffinity != nil,
+	}
+	if affinity.applies && contracts.SupportsConversationSessions(adapter) {
+		affinity.conversational = true
+		affinity.handle = affinity.store.Lookup(in.SessionID, affinity.providerID)
+	}
+	return affinity
+}
+
+// observe records the attempt outcome and returns its affinity decision and handle hash. A
+// failed attempt on a reused handle drops the handle so retries resend full context.
+func (a *attemptAffinity) observe(outcome contracts.Outcome) (string, string) {
+	if !a.applies {
+		return "", ""
+	}
+	sent := a.handle
+	decision := contracts.SessionAffinityStateless
+	switch {
+	case sent != "":
+		decision = contracts.SessionAffinityReused
+	case a.fellBack:
+		decision = contracts.SessionAffinityStatelessFallback
+	}
+	if !a.conversational {
+		return decision, ""
+	}
+
+	switch outcome.Class {
+	case contracts.OutcomeSuccess:
+		returned := outcome.ProviderSessionID
+		if returned != "" && returned != sent {
+			a.store.Remember(a.sessionID, a.providerID, returned)
+			a.handle = returned
+			if sent == "" {
+				decision = contracts.SessionAffinityEstablished
+			}
+		}
+		return decision, ProviderSessionIDHash(a.handle)
+	case contracts.OutcomeCancelled:
+		return decision, ProviderSessionIDHash(sent)
+	default:
+		if sent != "" {
+			a.store.Remember(a.sessionID, a.providerID, "")
+			a.handle = ""
+			a.fellBack = true
+		}
+		return decision, ProviderSessionIDHash(sent)
+	}
+}
+
+// samplingSeedStatus reports how a seeded LLM attempt handled its sampling seed. Providers that
+// cannot take a seed, or report ignoring it, sample nondeterministically across replays.
+func samplingSeedStatus(in InvocationInput, adapter contracts.Adapter, outcome contracts.Outcome) string {
+	switch {
+	case in.SamplingSeed == nil:
+		return ""
+	case !contracts.SupportsSamplingSeed(adapter):
+		return contracts.SamplingSeedUnsupported
+	case outcome.SamplingSeedIgnored:
+		return contracts.SamplingSeedIgnored
+	default:
+		return contracts.SamplingSeedApplied
+	}
+}
+
 func validateInput(in InvocationInput) error {
 	if in.SessionID == "" || in.PipelineVersion == "" || in.EventID == "" {
 		return fmt.Errorf("session_id, pipeline_version, and event_id are required")
 	}
+	if in.Endpointing != nil && in.Modality != contracts.ModalitySTT {
+		return fmt.Errorf("endpointing is only valid for stt invocations")
+	}
+	if in.SamplingSeed != nil && in.Modality != contracts.ModalityLLM {
+		return fmt.Errorf("sampling_seed is only valid for llm invocations")
+	}
+	if in.MaxOutputTokens < 0 || in.MaxAudioOutputMS < 0 {
+		return fmt.Errorf("max_output_tokens and max_audio_output_ms must be >=0")
+	}
+	if in.TurnBudgetRemainingMS < 0 {
+		return fmt.Errorf("turn_budget_remaining_ms must be >=0")
+	}
 	return in.Modality.Validate()
 }
 
@@ -311,6 +829,25 @@ func parseAdaptiveActions(actions []string) (adaptiveActions, error) {
 	return out, nil
 }
 
+// lengthCapReason reports whether a successful outcome stopped at an output length cap. LLM
+// output that used the whole token cap counts as capped even when the adapter did not flag it.
+func lengthCapReason(in InvocationInput, outcome contracts.Outcome) (string, bool) {
+	switch in.Modality {
+	case contracts.ModalityLLM:
+		if in.MaxOutputTokens < 1 {
+			return "", false
+		}
+		if outcome.LengthCapped || (outcome.Usage != nil && outcome.Usage.OutputTokens >= in.MaxOutputTokens) {
+			return fmt.Sprintf("max_output_tokens=%d", in.MaxOutputTokens), true
+		}
+	case contracts.ModalityTTS:
+		if in.MaxAudioOutputMS > 0 && outcome.LengthCapped {
+			return fmt.Sprintf("max_audio_output_ms=%d", in.MaxAudioOutputMS), true
+		}
+	}
+	return "", false
+}
+
 func providerInvocationID(in InvocationInput) string {
 	if in.ProviderInvocationID != "" {
 		return in.ProviderInvocationID
@@ -348,6 +885,9 @@ func (c Controller) appendSignal(result *InvocationResult, in InvocationInput, s
 		Reason:             reason,
 		Scope:              "provider_invocation",
 	}
+	if domain, ok := eventabi.ProviderFailureDomain(string(in.Modality)); ok {
+		signal.FailureDomain = domain
+	}
 	if err := signal.Validate(); err != nil {
 		return err
 	}
@@ -1,12 +1,17 @@
 package invocation
 
 import (
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/backoff"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
 )
 
 func TestInvokeRetriesThenSucceeds(t *testing.T) {
@@ -39,6 +44,7 @@ func TestInvokeRetriesThenSucceeds(t *testing.T) {
 	controller := NewControllerWithConfig(catalog, Config{
 		MaxAttemptsPerProvider: 2,
 		MaxCandidateProviders:  5,
+		Clock:                  advancingClock{clock.NewVirtual(time.Unix(1700000000, 0))},
 	})
 
 	result, err := controller.Invoke(InvocationInput{
@@ -70,6 +76,159 @@ func TestInvokeRetriesThenSucceeds(t *testing.T) {
 	if len(result.Signals) != 1 || result.Signals[0].Signal != "provider_error" {
 		t.Fatalf("expected one provider_error signal, got %+v", result.Signals)
 	}
+	if result.Attempts[0].BackoffMS < 50 || result.Attempts[1].BackoffMS != 0 {
+		t.Fatalf("expected backoff evidence on retried attempt only, got %+v", result.Attempts)
+	}
+}
+
+func TestInvokeRetryBackoffBudgetStopsRetries(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{
+			ID:   "stt-a",
+			Mode: contracts.ModalitySTT,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				attempts++
+				return contracts.Outcome{Class: contracts.OutcomeOverload, Retryable: true, Reason: "provider_overload", BackoffMS: 700}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+
+	controller := NewControllerWithConfig(catalog, Config{
+		MaxAttemptsPerProvider: 4,
+		Backoff:                backoff.Policy{Base: 100 * time.Millisecond, Max: 100 * time.Millisecond, Budget: 200 * time.Millisecond},
+		Clock:                  advancingClock{clock.NewVirtual(time.Unix(1700000000, 0))},
+	})
+	result, err := controller.Invoke(InvocationInput{
+		SessionID:              "sess-rk11-backoff",
+		PipelineVersion:        "pipeline-v1",
+		EventID:                "evt-rk11-backoff",
+		Modality:               contracts.ModalitySTT,
+		AllowedAdaptiveActions: []string{"retry"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if attempts != 3 || len(result.Attempts) != 3 {
+		t.Fatalf("expected backoff budget to allow two retries, got attempts=%d", attempts)
+	}
+	if result.Attempts[0].BackoffMS != 700 || result.Attempts[1].BackoffMS != 700 || result.Attempts[2].BackoffMS != 0 {
+		t.Fatalf("expected provider retry-after hint to dominate backoff evidence, got %+v", result.Attempts)
+	}
+}
+
+func TestInvokeWaitsRetryBackoffOnClock(t *testing.T) {
+	t.Parallel()
+
+	virtual := clock.NewVirtual(time.Unix(1700000000, 0))
+	var invokedAt []time.Time
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{
+			ID:   "stt-a",
+			Mode: contracts.ModalitySTT,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				invokedAt = append(invokedAt, virtual.Now())
+				if len(invokedAt) == 1 {
+					return contracts.Outcome{Class: contracts.OutcomeOverload, Retryable: true, Reason: "provider_overload", BackoffMS: 250}, nil
+				}
+				return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	controller := NewControllerWithConfig(catalog, Config{
+		MaxAttemptsPerProvider: 2,
+		Backoff:                backoff.Policy{Base: 100 * time.Millisecond, Max: 100 * time.Millisecond},
+		Clock:                  virtual,
+	})
+
+	done := make(chan InvocationResult, 1)
+	go func() {
+		result, err := controller.Invoke(InvocationInput{
+			SessionID:              "sess-rk11-wait",
+			PipelineVersion:        "pipeline-v1",
+			EventID:                "evt-rk11-wait",
+			Modality:               contracts.ModalitySTT,
+			AllowedAdaptiveActions: []string{"retry"},
+		})
+		if err != nil {
+			t.Errorf("unexpected invoke error: %v", err)
+		}
+		done <- result
+	}()
+
+	virtual.BlockUntil(1)
+	select {
+	case result := <-done:
+		t.Fatalf("expected the retry to wait for backoff, got %+v", result)
+	default:
+	}
+	virtual.Advance(250 * time.Millisecond)
+	result := <-done
+	if result.Outcome.Class != contracts.OutcomeSuccess || len(invokedAt) != 2 {
+		t.Fatalf("expected the retry to succeed after backoff, got %+v", result)
+	}
+	if waited := invokedAt[1].Sub(invokedAt[0]); waited != 250*time.Millisecond {
+		t.Fatalf("expected the retry to start 250ms after the first attempt, got %s", waited)
+	}
+}
+
+func TestInvokeCancelDuringBackoffStopsRetry(t *testing.T) {
+	t.Parallel()
+
+	virtual := clock.NewVirtual(time.Unix(1700000000, 0))
+	attempts := 0
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{
+			ID:   "stt-a",
+			Mode: contracts.ModalitySTT,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				attempts++
+				return contracts.Outcome{Class: contracts.OutcomeTimeout, Retryable: true, Reason: "provider_timeout"}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	controller := NewControllerWithConfig(catalog, Config{MaxAttemptsPerProvider: 3, Clock: virtual})
+
+	cancel := make(chan struct{})
+	done := make(chan InvocationResult, 1)
+	go func() {
+		result, err := controller.Invoke(InvocationInput{
+			SessionID:              "sess-rk11-cancel-wait",
+			PipelineVersion:        "pipeline-v1",
+			EventID:                "evt-rk11-cancel-wait",
+			Modality:               contracts.ModalitySTT,
+			AllowedAdaptiveActions: []string{"retry"},
+			CancelSignal:           cancel,
+		})
+		if err != nil {
+			t.Errorf("unexpected invoke error: %v", err)
+		}
+		done <- result
+	}()
+
+	virtual.BlockUntil(1)
+	close(cancel)
+	result := <-done
+	if attempts != 1 || len(result.Attempts) != 1 {
+		t.Fatalf("expected no retry after cancel during backoff, got attempts=%d", attempts)
+	}
+	if result.Outcome.Class != contracts.OutcomeCancelled || result.Outcome.Reason != "cancel_requested_during_backoff" {
+		t.Fatalf("expected a cancelled outcome, got %+v", result.Outcome)
+	}
+	if err := result.Outcome.Validate(); err != nil {
+		t.Fatalf("expected a valid outcome, got %v", err)
+	}
 }
 
 func TestInvokeSwitchesProviderAfterFailure(t *testing.T) {
@@ -229,6 +388,64 @@ func TestInvokeCancelledBeforeAttempt(t *testing.T) {
 	}
 }
 
+func TestInvokeCancelledMidStreamIsTerminal(t *testing.T) {
+	t.Parallel()
+
+	cancelSignal := make(chan struct{})
+	close(cancelSignal)
+	fallbackInvoked := false
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{
+			ID:   "llm-a",
+			Mode: contracts.ModalityLLM,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				if !req.CancelSignalled() {
+					t.Fatalf("expected cancel signal to reach adapter")
+				}
+				return contracts.Outcome{
+					Class:  contracts.OutcomeCancelled,
+					Reason: "provider_stream_cancelled",
+					Usage:  &contracts.TokenUsage{InputTokens: 10, OutputTokens: 4, Truncated: true},
+				}, nil
+			},
+		},
+		contracts.StaticAdapter{
+			ID:   "llm-b",
+			Mode: contracts.ModalityLLM,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				fallbackInvoked = true
+				return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+
+	result, err := NewController(catalog).Invoke(InvocationInput{
+		SessionID:              "sess-rk11-cancel",
+		TurnID:                 "turn-rk11-cancel",
+		PipelineVersion:        "pipeline-v1",
+		EventID:                "evt-rk11-cancel",
+		Modality:               contracts.ModalityLLM,
+		PreferredProvider:      "llm-a",
+		AllowedAdaptiveActions: []string{"retry", "provider_switch"},
+		CancelSignal:           cancelSignal,
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if result.Outcome.Class != contracts.OutcomeCancelled || result.RetryDecision != "none" || fallbackInvoked {
+		t.Fatalf("expected terminal cancelled outcome without retry/switch, got %+v fallback=%v", result, fallbackInvoked)
+	}
+	if len(result.Attempts) != 1 || result.Attempts[0].Outcome.Usage == nil || result.Attempts[0].Outcome.Usage.OutputTokens != 4 {
+		t.Fatalf("expected one attempt with partial usage, got %+v", result.Attempts)
+	}
+	if len(result.Signals) != 0 {
+		t.Fatalf("expected no provider_error signals on cancel, got %+v", result.Signals)
+	}
+}
+
 func TestInvokeRejectsUnsupportedAdaptiveAction(t *testing.T) {
 	t.Parallel()
 
@@ -312,6 +529,57 @@ func TestInvokePolicyEnvelopePassedToAdapter(t *testing.T) {
 	}
 }
 
+func TestInvokePropagatesTraceparentPerAttempt(t *testing.T) {
+	t.Parallel()
+
+	var traceparents []string
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{
+			ID:   "llm-a",
+			Mode: contracts.ModalityLLM,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				traceparents = append(traceparents, req.Traceparent)
+				if len(traceparents) == 1 {
+					return contracts.Outcome{Class: contracts.OutcomeTimeout, Retryable: true, Reason: "provider_timeout"}, nil
+				}
+				return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+
+	nodeTrace := telemetry.NewRootTraceContext("sess-rk11-trace|turn-rk11-trace").ChildSpan("node")
+	_, err = NewController(catalog).Invoke(InvocationInput{
+		SessionID:              "sess-rk11-trace",
+		TurnID:                 "turn-rk11-trace",
+		PipelineVersion:        "pipeline-v1",
+		EventID:                "evt-rk11-trace",
+		Modality:               contracts.ModalityLLM,
+		PreferredProvider:      "llm-a",
+		AllowedAdaptiveActions: []string{"retry"},
+		RuntimeTimestampMS:     10,
+		WallClockTimestampMS:   10,
+		Trace:                  nodeTrace,
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if len(traceparents) != 2 || traceparents[0] == traceparents[1] {
+		t.Fatalf("expected a distinct traceparent per attempt, got %v", traceparents)
+	}
+	for _, value := range traceparents {
+		parsed, err := telemetry.ParseTraceparent(value)
+		if err != nil {
+			t.Fatalf("expected valid traceparent, got %q: %v", value, err)
+		}
+		if parsed.TraceID != nodeTrace.TraceID || parsed.ParentSpanID == nodeTrace.SpanID {
+			t.Fatalf("expected attempt span under node trace %+v, got %+v", nodeTrace, parsed)
+		}
+	}
+}
+
 func TestInvokeEmitsTelemetryEvents(t *testing.T) {
 	catalog, err := registry.NewCatalog([]contracts.Adapter{
 		contracts.StaticAdapter{
@@ -377,3 +645,1074 @@ func TestInvokeEmitsTelemetryEvents(t *testing.T) {
 		t.Fatalf("expected provider invocation telemetry events, got metric=%v span=%v log=%v", metricFound, spanFound, logFound)
 	}
 }
+
+type serverEndpointingAdapter struct {
+	contracts.StaticAdapter
+}
+
+func (serverEndpointingAdapter) SupportsServerEndpointing() bool { return true }
+
+func TestInvokeEndpointingEnforcement(t *testing.T) {
+	t.Parallel()
+
+	endpointing := &contracts.Endpointing{SilenceDurationMS: 500, MinUtteranceMS: 200, MaxUtteranceMS: 15000, NoSpeechTimeoutMS: 5000}
+	// capture is 16 kHz audio: leading silence, speech, then trailing silence.
+	capture := func(leadingMS, speechMS, trailingMS int) *contracts.AudioInput {
+		pcm := make([]int16, (leadingMS+speechMS+trailingMS)*16)
+		for idx := leadingMS * 16; idx < (leadingMS+speechMS)*16; idx++ {
+			pcm[idx] = 4000
+		}
+		return &contracts.AudioInput{PCM: pcm, SampleRateHz: 16000}
+	}
+	tests := []struct {
+		name            string
+		serverSide      bool
+		audio           *contracts.AudioInput
+		wantEnforcement string
+		wantKind        string
+		wantInvoked     bool
+		wantSamples     int
+	}{
+		{name: "server-side endpointing", serverSide: true, audio: capture(300, 500, 700), wantEnforcement: contracts.EndpointingEnforcementProvider, wantInvoked: true, wantSamples: 1500 * 16},
+		{name: "runtime endpointer trims the utterance", audio: capture(300, 500, 700), wantEnforcement: contracts.EndpointingEnforcementVADEmulated, wantKind: "utterance_end", wantInvoked: true, wantSamples: 500 * 16},
+		{name: "runtime endpointer finds no speech", audio: capture(1000, 0, 0), wantEnforcement: contracts.EndpointingEnforcementVADEmulated, wantKind: "no_speech_timeout"},
+		{name: "no captured audio", wantInvoked: true},
+	}
+	for _, tc := range tests {
+		var forwarded *contracts.Endpointing
+		invoked, gotSamples := false, 0
+		static := contracts.StaticAdapter{
+			ID:   "stt-a",
+			Mode: contracts.ModalitySTT,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				invoked, forwarded = true, req.Endpointing
+				if req.Audio != nil {
+					gotSamples = len(req.Audio.PCM)
+				}
+				return contracts.Outcome{Class: contracts.OutcomeSuccess, Text: "heard"}, req.Validate()
+			},
+		}
+		var adapter contracts.Adapter = static
+		if tc.serverSide {
+			adapter = serverEndpointingAdapter{StaticAdapter: static}
+		}
+		catalog, err := registry.NewCatalog([]contracts.Adapter{adapter})
+		if err != nil {
+			t.Fatalf("%s: unexpected catalog error: %v", tc.name, err)
+		}
+		result, err := NewController(catalog).Invoke(InvocationInput{
+			SessionID:       "sess-rk11-endpointing",
+			PipelineVersion: "pipeline-v1",
+			EventID:         "evt-rk11-endpointing",
+			Modality:        contracts.ModalitySTT,
+			Endpointing:     endpointing,
+			Audio:           tc.audio,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		if result.EndpointingEnforcement != tc.wantEnforcement || result.EndpointKind != tc.wantKind {
+			t.Fatalf("%s: expected enforcement %q kind %q, got %+v", tc.name, tc.wantEnforcement, tc.wantKind, result)
+		}
+		if tc.serverSide != (forwarded != nil) || (forwarded != nil && *forwarded != *endpointing) {
+			t.Fatalf("%s: expected endpointing forwarded=%v, got %+v", tc.name, tc.serverSide, forwarded)
+		}
+		if invoked != tc.wantInvoked || gotSamples != tc.wantSamples {
+			t.Fatalf("%s: expected invoked=%v with %d samples, got invoked=%v samples=%d", tc.name, tc.wantInvoked, tc.wantSamples, invoked, gotSamples)
+		}
+		if result.Outcome.Class != contracts.OutcomeSuccess || (result.Outcome.Text == "") == tc.wantInvoked {
+			t.Fatalf("%s: expected a success outcome, transcribed only when invoked, got %+v", tc.name, result.Outcome)
+		}
+	}
+
+	catalog, err := registry.NewCatalog([]contracts.Adapter{contracts.StaticAdapter{ID: "llm-a", Mode: contracts.ModalityLLM}})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	if _, err := NewController(catalog).Invoke(InvocationInput{
+		SessionID:       "sess-rk11-endpointing",
+		PipelineVersion: "pipeline-v1",
+		EventID:         "evt-rk11-endpointing",
+		Modality:        contracts.ModalityLLM,
+		Endpointing:     endpointing,
+	}); err == nil {
+		t.Fatalf("expected endpointing on llm invocation to be rejected")
+	}
+}
+
+func TestInvokeForwardsSessionLocale(t *testing.T) {
+	t.Parallel()
+
+	var forwarded *contracts.Locale
+	catalog, err := registry.NewCatalog([]contracts.Adapter{contracts.StaticAdapter{
+		ID:   "tts-a",
+		Mode: contracts.ModalityTTS,
+		InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+			forwarded = req.Locale
+			return contracts.Outcome{Class: contracts.OutcomeSuccess}, req.Validate()
+		},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	locale := &contracts.Locale{Tag: "es-MX", LLMPromptLanguage: "es", TTSVoice: "es-US-Chirp3-HD-Achernar", TTSVoiceLocale: "es-US", STTLanguageHints: []string{"es-MX", "es-US"}}
+	if _, err := NewController(catalog).Invoke(InvocationInput{
+		SessionID:       "sess-rk11-locale",
+		PipelineVersion: "pipeline-v1",
+		EventID:         "evt-rk11-locale",
+		Modality:        contracts.ModalityTTS,
+		Locale:          locale,
+	}); err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if forwarded == nil || forwarded == locale || !reflect.DeepEqual(*forwarded, *locale) {
+		t.Fatalf("expected per-attempt copy of locale %+v, got %+v", locale, forwarded)
+	}
+}
+
+func TestInvokeForwardsSessionSummaryToLLMOnly(t *testing.T) {
+	t.Parallel()
+
+	forwarded := map[contracts.Modality]*contracts.SessionSummary{}
+	forwardedMetadata := map[contracts.Modality]map[string]string{}
+	forwardedPrompt := map[contracts.Modality]string{}
+	adapterFor := func(id string, mode contracts.Modality) contracts.Adapter {
+		return contracts.StaticAdapter{ID: id, Mode: mode, InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+			forwarded[req.Modality] = req.SessionSummary
+			forwardedMetadata[req.Modality] = req.SessionMetadata
+			forwardedPrompt[req.Modality] = req.Prompt
+			return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+		}}
+	}
+	catalog, err := registry.NewCatalog([]contracts.Adapter{adapterFor("llm-a", contracts.ModalityLLM), adapterFor("tts-a", contracts.ModalityTTS)})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	summary := &contracts.SessionSummary{SummaryID: "summary-1", Text: "caller wants to rebook"}
+	metadata := map[string]string{"crm.tier": "gold"}
+	for _, modality := range []contracts.Modality{contracts.ModalityLLM, contracts.ModalityTTS} {
+		if _, err := NewController(catalog).Invoke(InvocationInput{
+			SessionID:       "sess-rk11-summary",
+			PipelineVersion: "pipeline-v1",
+			EventID:         "evt-rk11-summary-" + string(modality),
+			Modality:        modality,
+			SessionSummary:  summary,
+			SessionMetadata: metadata,
+			Prompt:          "rebook my flight",
+		}); err != nil {
+			t.Fatalf("unexpected invoke error: %v", err)
+		}
+	}
+	if got := forwarded[contracts.ModalityLLM]; got == nil || got == summary || *got != *summary {
+		t.Fatalf("expected per-attempt copy of summary %+v, got %+v", summary, got)
+	}
+	if got := forwarded[contracts.ModalityTTS]; got != nil {
+		t.Fatalf("expected no summary on tts invocation, got %+v", got)
+	}
+	if got := forwardedMetadata[contracts.ModalityLLM]; !reflect.DeepEqual(got, metadata) {
+		t.Fatalf("expected session metadata on llm invocation, got %+v", got)
+	}
+	if got := forwardedMetadata[contracts.ModalityTTS]; got != nil {
+		t.Fatalf("expected no session metadata on tts invocation, got %+v", got)
+	}
+	if forwardedPrompt[contracts.ModalityLLM] != "rebook my flight" || forwardedPrompt[contracts.ModalityTTS] != "" {
+		t.Fatalf("expected prompt on llm invocation only, got %+v", forwardedPrompt)
+	}
+}
+
+func TestInvokeForwardsAudioAndTextByModality(t *testing.T) {
+	t.Parallel()
+
+	forwardedAudio := map[contracts.Modality]*contracts.AudioInput{}
+	forwardedText := map[contracts.Modality]string{}
+	adapterFor := func(id string, mode contracts.Modality) contracts.Adapter {
+		return contracts.StaticAdapter{ID: id, Mode: mode, InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+			forwardedAudio[req.Modality] = req.Audio
+			forwardedText[req.Modality] = req.Text
+			return contracts.Outcome{Class: contracts.OutcomeSuccess}, req.Validate()
+		}}
+	}
+	catalog, err := registry.NewCatalog([]contracts.Adapter{adapterFor("stt-a", contracts.ModalitySTT), adapterFor("llm-a", contracts.ModalityLLM), adapterFor("tts-a", contracts.ModalityTTS)})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	audio := &contracts.AudioInput{PCM: []int16{1, 2, 3}, SampleRateHz: 16000}
+	for _, modality := range []contracts.Modality{contracts.ModalitySTT, contracts.ModalityLLM, contracts.ModalityTTS} {
+		if _, err := NewController(catalog).Invoke(InvocationInput{
+			SessionID:       "sess-rk11-audio",
+			PipelineVersion: "pipeline-v1",
+			EventID:         "evt-rk11-audio-" + string(modality),
+			Modality:        modality,
+			Audio:           audio,
+			Text:            "your table is booked",
+		}); err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", modality, err)
+		}
+	}
+	if forwardedAudio[contracts.ModalitySTT] != audio || forwardedAudio[contracts.ModalityLLM] != nil || forwardedAudio[contracts.ModalityTTS] != nil {
+		t.Fatalf("expected audio on stt invocation only, got %+v", forwardedAudio)
+	}
+	if forwardedText[contracts.ModalityTTS] != "your table is booked" || forwardedText[contracts.ModalitySTT] != "" || forwardedText[contracts.ModalityLLM] != "" {
+		t.Fatalf("expected text on tts invocation only, got %+v", forwardedText)
+	}
+}
+
+func TestInvokeLengthCappedSignal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		modality   contracts.Modality
+		input      InvocationInput
+		outcome    contracts.Outcome
+		wantReason string
+	}{
+		{name: "llm cap reached by usage", modality: contracts.ModalityLLM, input: InvocationInput{MaxOutputTokens: 64}, outcome: contracts.Outcome{Class: contracts.OutcomeSuccess, Usage: &contracts.TokenUsage{OutputTokens: 64}}, wantReason: "max_output_tokens=64"},
+		{name: "llm cap flagged by adapter", modality: contracts.ModalityLLM, input: InvocationInput{MaxOutputTokens: 64}, outcome: contracts.Outcome{Class: contracts.OutcomeSuccess, LengthCapped: true}, wantReason: "max_output_tokens=64"},
+		{name: "llm under cap", modality: contracts.ModalityLLM, input: InvocationInput{MaxOutputTokens: 64}, outcome: contracts.Outcome{Class: contracts.OutcomeSuccess, Usage: &contracts.TokenUsage{OutputTokens: 12}}},
+		{name: "llm uncapped", modality: contracts.ModalityLLM, outcome: contracts.Outcome{Class: contracts.OutcomeSuccess, Usage: &contracts.TokenUsage{OutputTokens: 900}}},
+		{name: "tts cap flagged by adapter", modality: contracts.ModalityTTS, input: InvocationInput{MaxAudioOutputMS: 15_000}, outcome: contracts.Outcome{Class: contracts.OutcomeSuccess, LengthCapped: true}, wantReason: "max_audio_output_ms=15000"},
+		{name: "tts under cap", modality: contracts.ModalityTTS, input: InvocationInput{MaxAudioOutputMS: 15_000}, outcome: contracts.Outcome{Class: contracts.OutcomeSuccess}},
+	}
+	for _, tc := range tests {
+		var gotAudioCap int64
+		catalog, err := registry.NewCatalog([]contracts.Adapter{contracts.StaticAdapter{
+			ID:   "provider-a",
+			Mode: tc.modality,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				gotAudioCap = req.MaxAudioOutputMS
+				return tc.outcome, req.Validate()
+			},
+		}})
+		if err != nil {
+			t.Fatalf("%s: unexpected catalog error: %v", tc.name, err)
+		}
+		in := tc.input
+		in.SessionID = "sess-rk11-length"
+		in.TurnID = "turn-rk11-length"
+		in.PipelineVersion = "pipeline-v1"
+		in.EventID = "evt-rk11-length"
+		in.Modality = tc.modality
+		result, err := NewController(catalog).Invoke(in)
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		if gotAudioCap != tc.input.MaxAudioOutputMS {
+			t.Fatalf("%s: expected max_audio_output_ms=%d forwarded, got %d", tc.name, tc.input.MaxAudioOutputMS, gotAudioCap)
+		}
+		if tc.wantReason == "" {
+			if result.LengthCapped || len(result.Signals) != 0 {
+				t.Fatalf("%s: expected uncapped result, got %+v", tc.name, result)
+			}
+			continue
+		}
+		if !result.LengthCapped || len(result.Signals) != 1 {
+			t.Fatalf("%s: expected one length_capped signal, got %+v", tc.name, result)
+		}
+		signal := result.Signals[0]
+		if signal.Signal != "length_capped" || signal.EmittedBy != "RK-11" || signal.Reason != tc.wantReason {
+			t.Fatalf("%s: expected length_capped reason %s, got %+v", tc.name, tc.wantReason, signal)
+		}
+	}
+}
+
+type conversationAdapter struct {
+	contracts.StaticAdapter
+}
+
+func (conversationAdapter) SupportsConversationSessions() bool { return true }
+
+func TestInvokeLLMSessionAffinityAcrossTurns(t *testing.T) {
+	t.Parallel()
+
+	var sent []string
+	failNextReuse := false
+	handles := 0
+	conversational := conversationAdapter{contracts.StaticAdapter{ID: "llm-conv", Mode: contracts.ModalityLLM, InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+		sent = append(sent, req.ProviderSessionID)
+		if req.ProviderSessionID != "" && failNextReuse {
+			failNextReuse = false
+			return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: true, Reason: "conversation_expired"}, nil
+		}
+		if req.ProviderSessionID != "" {
+			return contracts.Outcome{Class: contracts.OutcomeSuccess, ProviderSessionID: req.ProviderSessionID}, nil
+		}
+		handles++
+		return contracts.Outcome{Class: contracts.OutcomeSuccess, ProviderSessionID: "conv-" + strconv.Itoa(handles)}, nil
+	}}}
+	catalog, err := registry.NewCatalog([]contracts.Adapter{conversational})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	store := NewSessionAffinityStore(0)
+	controller := NewControllerWithConfig(catalog, Config{MaxAttemptsPerProvider: 2, SessionAffinity: store})
+	invoke := func(turnID string) InvocationResult {
+		result, err := controller.Invoke(InvocationInput{
+			SessionID:              "sess-affinity",
+			TurnID:                 turnID,
+			PipelineVersion:        "pipeline-v1",
+			EventID:                "evt-" + turnID,
+			Modality:               contracts.ModalityLLM,
+			AllowedAdaptiveActions: []string{"retry"},
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", turnID, err)
+		}
+		return result
+	}
+
+	first := invoke("turn-1")
+	if first.SessionAffinity != contracts.SessionAffinityEstablished || first.ProviderSessionIDHash != ProviderSessionIDHash("conv-1") {
+		t.Fatalf("expected first turn to establish a provider session, got %+v", first)
+	}
+	second := invoke("turn-2")
+	if second.SessionAffinity != contracts.SessionAffinityReused || second.ProviderSessionIDHash != first.ProviderSessionIDHash {
+		t.Fatalf("expected second turn to reuse the provider session, got %+v", second)
+	}
+
+	failNextReuse = true
+	third := invoke("turn-3")
+	if len(third.Attempts) != 2 || third.Attempts[0].SessionAffinity != contracts.SessionAffinityReused {
+		t.Fatalf("expected failed reuse followed by a retry, got %+v", third.Attempts)
+	}
+	if third.SessionAffinity != contracts.SessionAffinityEstablished || third.ProviderSessionIDHash != ProviderSessionIDHash("conv-2") {
+		t.Fatalf("expected stateless retry to establish a fresh provider session, got %+v", third)
+	}
+	if want := []string{"", "conv-1", "conv-1", ""}; !reflect.DeepEqual(sent, want) {
+		t.Fatalf("expected handles sent %v, got %v", want, sent)
+	}
+	if store.Lookup("sess-affinity", "llm-conv") != "conv-2" {
+		t.Fatalf("expected store to remember the fresh handle")
+	}
+	store.Forget("sess-affinity")
+	if store.Len() != 0 {
+		t.Fatalf("expected forget to drop session handles, got %d", store.Len())
+	}
+}
+
+func TestInvokeSessionAffinityDecisions(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name         string
+		adapter      contracts.Adapter
+		modality     contracts.Modality
+		wantAffinity []string
+	}{
+		{
+			name: "stateless llm provider",
+			adapter: contracts.StaticAdapter{ID: "llm-plain", Mode: contracts.ModalityLLM, InvokeFn: func(contracts.InvocationRequest) (contracts.Outcome, error) {
+				return contracts.Outcome{Class: contracts.OutcomeSuccess, ProviderSessionID: "ignored"}, nil
+			}},
+			modality:     contracts.ModalityLLM,
+			wantAffinity: []string{contracts.SessionAffinityStateless},
+		},
+		{
+			name:         "conversation provider without handle",
+			adapter:      conversationAdapter{contracts.StaticAdapter{ID: "llm-conv", Mode: contracts.ModalityLLM}},
+			modality:     contracts.ModalityLLM,
+			wantAffinity: []string{contracts.SessionAffinityStateless},
+		},
+		{
+			name:         "non llm modality",
+			adapter:      contracts.StaticAdapter{ID: "tts-a", Mode: contracts.ModalityTTS},
+			modality:     contracts.ModalityTTS,
+			wantAffinity: []string{""},
+		},
+	}
+	for _, tc := range cases {
+		catalog, err := registry.NewCatalog([]contracts.Adapter{tc.adapter})
+		if err != nil {
+			t.Fatalf("%s: unexpected catalog error: %v", tc.name, err)
+		}
+		result, err := NewController(catalog).Invoke(InvocationInput{
+			SessionID:       "sess-affinity-" + tc.adapter.ProviderID(),
+			PipelineVersion: "pipeline-v1",
+			EventID:         "evt-affinity",
+			Modality:        tc.modality,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		got := make([]string, 0, len(result.Attempts))
+		for _, attempt := range result.Attempts {
+			got = append(got, attempt.SessionAffinity)
+		}
+		if !reflect.DeepEqual(got, tc.wantAffinity) || result.ProviderSessionIDHash != "" {
+			t.Fatalf("%s: expected affinity %v without hash, got %v (%q)", tc.name, tc.wantAffinity, got, result.ProviderSessionIDHash)
+		}
+	}
+}
+
+func TestSessionAffinityStoreEvictsOldest(t *testing.T) {
+	t.Parallel()
+
+	store := NewSessionAffinityStore(2)
+	store.Remember("sess-1", "llm-a", "conv-1")
+	store.Remember("sess-2", "llm-a", "conv-2")
+	store.Remember("sess-2", "llm-a", "conv-2b")
+	store.Remember("sess-3", "llm-a", "conv-3")
+	if store.Len() != 2 || store.Lookup("sess-1", "llm-a") != "" || store.Lookup("sess-2", "llm-a") != "conv-2b" {
+		t.Fatalf("expected oldest session evicted and updates kept in place, got len=%d", store.Len())
+	}
+}
+
+type seededAdapter struct {
+	contracts.StaticAdapter
+}
+
+func (seededAdapter) SupportsSamplingSeed() bool { return true }
+
+func TestInvokeForwardsSamplingSeedToCapableLLMAdapters(t *testing.T) {
+	t.Parallel()
+
+	seed := int64(7321)
+	cases := []struct {
+		name       string
+		ignoreSeed bool
+		capable    bool
+		wantStatus string
+	}{
+		{name: "seed applied", capable: true, wantStatus: contracts.SamplingSeedApplied},
+		{name: "seed ignored by provider", capable: true, ignoreSeed: true, wantStatus: contracts.SamplingSeedIgnored},
+		{name: "provider without seeding", wantStatus: contracts.SamplingSeedUnsupported},
+	}
+	for _, tc := range cases {
+		var sent *int64
+		static := contracts.StaticAdapter{ID: "llm-seed", Mode: contracts.ModalityLLM, InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+			sent = req.SamplingSeed
+			return contracts.Outcome{Class: contracts.OutcomeSuccess, SamplingSeedIgnored: tc.ignoreSeed}, req.Validate()
+		}}
+		var adapter contracts.Adapter = static
+		if tc.capable {
+			adapter = seededAdapter{static}
+		}
+		catalog, err := registry.NewCatalog([]contracts.Adapter{adapter})
+		if err != nil {
+			t.Fatalf("%s: unexpected catalog error: %v", tc.name, err)
+		}
+		result, err := NewController(catalog).Invoke(InvocationInput{
+			SessionID:       "sess-seed",
+			TurnID:          "turn-seed",
+			PipelineVersion: "pipeline-v1",
+			EventID:         "evt-seed",
+			Modality:        contracts.ModalityLLM,
+			SamplingSeed:    &seed,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		if tc.capable != (sent != nil && *sent == seed) {
+			t.Fatalf("%s: expected seed forwarded=%t, got %v", tc.name, tc.capable, sent)
+		}
+		if result.SamplingSeedStatus != tc.wantStatus || result.Attempts[0].SamplingSeedStatus != tc.wantStatus {
+			t.Fatalf("%s: expected sampling seed status %s, got %+v", tc.name, tc.wantStatus, result)
+		}
+		if result.SamplingSeed == nil || *result.SamplingSeed != seed {
+			t.Fatalf("%s: expected result to record the requested seed, got %v", tc.name, result.SamplingSeed)
+		}
+	}
+
+	catalog, err := registry.NewCatalog([]contracts.Adapter{contracts.StaticAdapter{ID: "tts-a", Mode: contracts.ModalityTTS}})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	if _, err := NewController(catalog).Invoke(InvocationInput{SessionID: "sess-seed", PipelineVersion: "pipeline-v1", EventID: "evt-seed", Modality: contracts.ModalityTTS, SamplingSeed: &seed}); err == nil {
+		t.Fatalf("expected sampling seed on a tts invocation to fail")
+	}
+}
+
+type standbyAdapter struct {
+	contracts.StaticAdapter
+	held bool
+}
+
+func (a *standbyAdapter) HoldStandby() contracts.StandbyResult {
+	a.held = true
+	return contracts.StandbyResult{ProviderID: a.ID, Modality: a.Mode, Held: true}
+}
+
+func (a *standbyAdapter) ReleaseStandby() { a.held = false }
+
+func (a *standbyAdapter) StandbyHeld() bool { return a.held }
+
+func TestInvokeRecordsWarmStandbyFailover(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		hold     bool
+		wantWarm bool
+	}{
+		{name: "held standby", hold: true, wantWarm: true},
+		{name: "cold secondary", hold: false, wantWarm: false},
+	}
+	for _, tc := range tests {
+		secondary := &standbyAdapter{StaticAdapter: contracts.StaticAdapter{ID: "stt-b", Mode: contracts.ModalitySTT}}
+		if tc.hold {
+			secondary.HoldStandby()
+		}
+		catalog, err := registry.NewCatalog([]contracts.Adapter{
+			contracts.StaticAdapter{ID: "stt-a", Mode: contracts.ModalitySTT, InvokeFn: func(contracts.InvocationRequest) (contracts.Outcome, error) {
+				return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: true, Reason: "provider_server_error"}, nil
+			}},
+			secondary,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected catalog error: %v", tc.name, err)
+		}
+		result, err := NewControllerWithConfig(catalog, Config{MaxAttemptsPerProvider: 1}).Invoke(InvocationInput{
+			SessionID:              "sess-standby",
+			TurnID:                 "turn-standby",
+			PipelineVersion:        "pipeline-v1",
+			EventID:                "evt-standby",
+			Modality:               contracts.ModalitySTT,
+			PreferredProvider:      "stt-a",
+			AllowedAdaptiveActions: []string{"provider_switch"},
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		if result.SelectedProvider != "stt-b" || result.RetryDecision != "provider_switch" || len(result.Attempts) != 2 {
+			t.Fatalf("%s: expected failover to stt-b, got %+v", tc.name, result)
+		}
+		if result.Attempts[0].WarmStandby || result.Attempts[1].WarmStandby != tc.wantWarm || result.WarmStandby != tc.wantWarm {
+			t.Fatalf("%s: expected warm standby=%t on the failover attempt only, got %+v", tc.name, tc.wantWarm, result.Attempts)
+		}
+	}
+}
+
+func TestInvokeDerivesAttemptDeadlinesFromTurnBudget(t *testing.T) {
+	t.Parallel()
+
+	virtual := clock.NewVirtual(time.Unix(1700000000, 0))
+	var deadlines []int64
+	fallbackCalls := 0
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{
+			ID:   "stt-a",
+			Mode: contracts.ModalitySTT,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				deadlines = append(deadlines, req.DeadlineMS)
+				virtual.Advance(300 * time.Millisecond)
+				return contracts.Outcome{Class: contracts.OutcomeTimeout, Retryable: true, Reason: "provider_timeout"}, nil
+			},
+		},
+		contracts.StaticAdapter{
+			ID:   "stt-b",
+			Mode: contracts.ModalitySTT,
+			InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+				fallbackCalls++
+				return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+
+	controller := NewControllerWithConfig(catalog, Config{
+		MaxAttemptsPerProvider: 3,
+		MaxCandidateProviders:  2,
+		Backoff:                backoff.Policy{Base: 100 * time.Millisecond, Max: 100 * time.Millisecond},
+		Clock:                  advancingClock{virtual},
+	})
+	result, err := controller.Invoke(InvocationInput{
+		SessionID:              "sess-rk11-budget",
+		PipelineVersion:        "pipeline-v1",
+		EventID:                "evt-rk11-budget",
+		Modality:               contracts.ModalitySTT,
+		PreferredProvider:      "stt-a",
+		AllowedAdaptiveActions: []string{"retry", "provider_switch"},
+		TurnBudgetRemainingMS:  1000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if !reflect.DeepEqual(deadlines, []int64{1000, 600, 200}) {
+		t.Fatalf("expected deadlines to shrink by elapsed time and backoff, got %v", deadlines)
+	}
+	if fallbackCalls != 0 || result.SelectedProvider != "stt-a" || !result.BudgetExhausted {
+		t.Fatalf("expected the futile provider switch to be skipped, got %+v (fallback calls=%d)", result, fallbackCalls)
+	}
+	if len(result.Signals) != 3 {
+		t.Fatalf("expected provider_error signals only, got %+v", result.Signals)
+	}
+	last := result.Attempts[len(result.Attempts)-1]
+	if last.TurnBudgetRemainingMS != 1000 || last.BudgetElapsedMS != 800 || last.DeadlineMS != 200 || !last.BudgetExhausted {
+		t.Fatalf("unexpected budget evidence on the final attempt: %+v", last)
+	}
+	if result.Attempts[0].BudgetExhausted || result.Attempts[1].BudgetElapsedMS != 400 {
+		t.Fatalf("unexpected budget evidence on earlier attempts: %+v", result.Attempts)
+	}
+}
+
+func TestInvokeTurnBudgetSkipsFutileAttempts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		budgetMS      int64
+		backoffHintMS int64
+		wantAttempts  int
+		wantCalls     int
+		wantExhausted bool
+		wantReason    string
+	}{
+		{name: "unbudgeted", budgetMS: 0, wantAttempts: 2, wantCalls: 2, wantReason: "provider_overload"},
+		{name: "budget fits retry", budgetMS: 1000, wantAttempts: 2, wantCalls: 2, wantReason: "provider_overload"},
+		{name: "retry-after exceeds budget", budgetMS: 1000, backoffHintMS: 950, wantAttempts: 1, wantCalls: 1, wantExhausted: true, wantReason: "provider_overload"},
+		{name: "exhausted before first attempt", budgetMS: 50, wantAttempts: 0, wantCalls: 0, wantExhausted: true, wantReason: "turn_budget_exhausted"},
+	}
+	for _, tc := range tests {
+		calls := 0
+		catalog, err := registry.NewCatalog([]contracts.Adapter{
+			contracts.StaticAdapter{
+				ID:   "stt-a",
+				Mode: contracts.ModalitySTT,
+				InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+					calls++
+					return contracts.Outcome{Class: contracts.OutcomeOverload, Retryable: true, Reason: "provider_overload", BackoffMS: tc.backoffHintMS}, nil
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected catalog error: %v", tc.name, err)
+		}
+		controller := NewControllerWithConfig(catalog, Config{
+			MaxAttemptsPerProvider: 2,
+			Backoff:                backoff.Policy{Base: 100 * time.Millisecond, Max: 100 * time.Millisecond},
+			Clock:                  advancingClock{clock.NewVirtual(time.Unix(1700000000, 0))},
+		})
+		result, err := controller.Invoke(InvocationInput{
+			SessionID:              "sess-rk11-futile",
+			PipelineVersion:        "pipeline-v1",
+			EventID:                "evt-rk11-futile",
+			Modality:               contracts.ModalitySTT,
+			AllowedAdaptiveActions: []string{"retry"},
+			TurnBudgetRemainingMS:  tc.budgetMS,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		if calls != tc.wantCalls || len(result.Attempts) != tc.wantAttempts || result.BudgetExhausted != tc.wantExhausted {
+			t.Fatalf("%s: expected calls=%d attempts=%d exhausted=%t, got calls=%d %+v", tc.name, tc.wantCalls, tc.wantAttempts, tc.wantExhausted, calls, result)
+		}
+		if result.Outcome.Reason != tc.wantReason || result.SelectedProvider != "stt-a" {
+			t.Fatalf("%s: expected reason %q from stt-a, got %+v", tc.name, tc.wantReason, result.Outcome)
+		}
+		if err := result.Outcome.Validate(); err != nil {
+			t.Fatalf("%s: expected a valid outcome, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestInvokeRejectsNegativeTurnBudget(t *testing.T) {
+	t.Parallel()
+
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{ID: "stt-a", Mode: contracts.ModalitySTT},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	if _, err := NewController(catalog).Invoke(InvocationInput{SessionID: "sess-rk11-neg", PipelineVersion: "pipeline-v1", EventID: "evt-rk11-neg", Modality: contracts.ModalitySTT, TurnBudgetRemainingMS: -1}); err == nil {
+		t.Fatalf("expected negative turn budget to be rejected")
+	}
+}
+
+func TestInvokeServesRepeatedInvocationsFromResponseCache(t *testing.T) {
+	sink := telemetry.NewMemorySink()
+	pipeline := telemetry.NewPipeline(sink, telemetry.Config{QueueCapacity: 64})
+	previous := telemetry.DefaultEmitter()
+	telemetry.SetDefaultEmitter(pipeline)
+	t.Cleanup(func() {
+		telemetry.SetDefaultEmitter(previous)
+		_ = pipeline.Close()
+	})
+
+	calls := 0
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{ID: "llm-cached", Mode: contracts.ModalityLLM, InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+			calls++
+			return contracts.Outcome{Class: contracts.OutcomeSuccess, Usage: &contracts.TokenUsage{InputTokens: 12, OutputTokens: 30}, FirstChunkLatencyMS: 180}, nil
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	virtual := clock.NewVirtual(time.Unix(1700000000, 0))
+	cache := NewResponseCache(ResponseCacheConfig{TTL: time.Minute, Now: virtual.Now})
+	controller := NewControllerWithConfig(catalog, Config{ResponseCache: cache})
+
+	tests := []struct {
+		name      string
+		turnID    string
+		maxTokens int
+		advance   time.Duration
+		wantHit   bool
+		wantCalls int
+	}{
+		{name: "first invocation", turnID: "turn-1", maxTokens: 64, wantCalls: 1},
+		{name: "replayed turn identity differs", turnID: "turn-2", maxTokens: 64, wantHit: true, wantCalls: 1},
+		{name: "output cap differs", turnID: "turn-3", maxTokens: 32, wantCalls: 2},
+		{name: "entry expired", turnID: "turn-4", maxTokens: 64, advance: time.Minute, wantCalls: 3},
+		{name: "refreshed entry", turnID: "turn-5", maxTokens: 64, wantHit: true, wantCalls: 3},
+	}
+	for _, tc := range tests {
+		virtual.Advance(tc.advance)
+		result, err := controller.Invoke(InvocationInput{
+			SessionID:       "sess-cache-" + tc.turnID,
+			TurnID:          tc.turnID,
+			PipelineVersion: "pipeline-v1",
+			EventID:         "evt-cache-" + tc.turnID,
+			Modality:        contracts.ModalityLLM,
+			MaxOutputTokens: tc.maxTokens,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		if calls != tc.wantCalls || result.CacheHit != tc.wantHit || result.Attempts[0].CacheHit != tc.wantHit {
+			t.Fatalf("%s: expected calls=%d hit=%t, got calls=%d %+v", tc.name, tc.wantCalls, tc.wantHit, calls, result)
+		}
+		if tc.wantHit && (result.Outcome.Class != contracts.OutcomeSuccess || result.Outcome.Usage != nil || result.Outcome.FirstChunkLatencyMS != 0) {
+			t.Fatalf("%s: expected a cached success without provider usage, got %+v", tc.name, result.Outcome)
+		}
+	}
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 3 || stats.Expirations != 1 || stats.Entries != 2 {
+		t.Fatalf("unexpected cache stats %+v", stats)
+	}
+	if err := pipeline.Close(); err != nil {
+		t.Fatalf("unexpected pipeline close error: %v", err)
+	}
+	var lookups []float64
+	for _, event := range sink.Events() {
+		if event.Kind == telemetry.EventKindMetric && event.Metric != nil && event.Metric.Name == telemetry.MetricProviderCacheHit {
+			if event.Metric.Attributes["provider_id"] != "llm-cached" || event.Metric.Attributes["modality"] != "llm" {
+				t.Fatalf("unexpected cache metric attributes %v", event.Metric.Attributes)
+			}
+			lookups = append(lookups, event.Metric.Value)
+		}
+	}
+	if want := []float64{0, 1, 0, 0, 1}; !reflect.DeepEqual(lookups, want) {
+		t.Fatalf("expected cache hit metrics %v, got %v", want, lookups)
+	}
+}
+
+func TestInvokeResponseCacheBypass(t *testing.T) {
+	t.Parallel()
+
+	success := contracts.Outcome{Class: contracts.OutcomeSuccess}
+	tests := []struct {
+		name           string
+		modality       contracts.Modality
+		outcome        contracts.Outcome
+		conversational bool
+		wantCalls      int
+	}{
+		{name: "stt is not cached", modality: contracts.ModalitySTT, outcome: success, wantCalls: 2},
+		{name: "failures are not cached", modality: contracts.ModalityTTS, outcome: contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: false, Reason: "provider_unavailable"}, wantCalls: 2},
+		{name: "successful tts is cached", modality: contracts.ModalityTTS, outcome: success, wantCalls: 1},
+		{name: "continued provider conversation is not cached", modality: contracts.ModalityLLM, outcome: contracts.Outcome{Class: contracts.OutcomeSuccess, ProviderSessionID: "conv-1"}, conversational: true, wantCalls: 2},
+	}
+	for _, tc := range tests {
+		calls := 0
+		static := contracts.StaticAdapter{ID: string(tc.modality) + "-a", Mode: tc.modality, InvokeFn: func(contracts.InvocationRequest) (contracts.Outcome, error) {
+			calls++
+			return tc.outcome, nil
+		}}
+		var adapter contracts.Adapter = static
+		if tc.conversational {
+			adapter = conversationAdapter{static}
+		}
+		catalog, err := registry.NewCatalog([]contracts.Adapter{adapter})
+		if err != nil {
+			t.Fatalf("%s: unexpected catalog error: %v", tc.name, err)
+		}
+		controller := NewControllerWithConfig(catalog, Config{ResponseCache: NewResponseCache(ResponseCacheConfig{})})
+		for _, turnID := range []string{"turn-1", "turn-2"} {
+			if _, err := controller.Invoke(InvocationInput{
+				SessionID:       "sess-cache-bypass",
+				TurnID:          turnID,
+				PipelineVersion: "pipeline-v1",
+				EventID:         "evt-" + turnID,
+				Modality:        tc.modality,
+			}); err != nil {
+				t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+			}
+		}
+		if calls != tc.wantCalls {
+			t.Fatalf("%s: expected %d provider calls, got %d", tc.name, tc.wantCalls, calls)
+		}
+	}
+}
+
+func TestResponseCacheEvictsOldestAndKeysOnOutputInputs(t *testing.T) {
+	t.Parallel()
+
+	base := contracts.InvocationRequest{
+		SessionID:       "sess-1",
+		TurnID:          "turn-1",
+		PipelineVersion: "pipeline-v1",
+		EventID:         "evt-1",
+		ProviderID:      "llm-a",
+		Modality:        contracts.ModalityLLM,
+		Attempt:         1,
+		SessionMetadata: map[string]string{},
+	}
+	seed := int64(7)
+	tests := []struct {
+		name     string
+		mutate   func(*contracts.InvocationRequest)
+		wantSame bool
+	}{
+		{name: "identity and timing", mutate: func(req *contracts.InvocationRequest) {
+			req.SessionID, req.TurnID, req.EventID = "sess-2", "turn-9", "evt-9"
+			req.Attempt, req.RuntimeTimestampMS, req.DeadlineMS, req.Traceparent = 2, 500, 900, "00-trace"
+			req.SessionMetadata = nil
+		}, wantSame: true},
+		{name: "sampling seed", mutate: func(req *contracts.InvocationRequest) { req.SamplingSeed = &seed }},
+		{name: "session summary", mutate: func(req *contracts.InvocationRequest) {
+			req.SessionSummary = &contracts.SessionSummary{SummaryID: "sum-1", Text: "booked"}
+		}},
+		{name: "locale", mutate: func(req *contracts.InvocationRequest) { req.Locale = &contracts.Locale{Tag: "de-DE"} }},
+		{name: "provider", mutate: func(req *contracts.InvocationRequest) { req.ProviderID = "llm-b" }},
+		{name: "pipeline version", mutate: func(req *contracts.InvocationRequest) { req.PipelineVersion = "pipeline-v2" }},
+	}
+	baseKey, err := ResponseKey(base)
+	if err != nil {
+		t.Fatalf("unexpected key error: %v", err)
+	}
+	for _, tc := range tests {
+		req := base
+		tc.mutate(&req)
+		key, err := ResponseKey(req)
+		if err != nil {
+			t.Fatalf("%s: unexpected key error: %v", tc.name, err)
+		}
+		if (key == baseKey) != tc.wantSame {
+			t.Fatalf("%s: expected same key=%t", tc.name, tc.wantSame)
+		}
+	}
+
+	cache := NewResponseCache(ResponseCacheConfig{MaxEntries: 2})
+	keys := []ResponseCacheKey{{ProviderID: "a"}, {ProviderID: "b"}, {ProviderID: "c"}}
+	for _, key := range keys {
+		cache.Put(key, contracts.Outcome{Class: contracts.OutcomeSuccess})
+	}
+	if _, ok := cache.Get(keys[0]); ok {
+		t.Fatalf("expected oldest entry evicted")
+	}
+	if _, ok := cache.Get(keys[2]); !ok {
+		t.Fatalf("expected newest entry cached")
+	}
+	if stats := cache.Stats(); stats.Evictions != 1 || stats.Entries != 2 || stats.HitRate() != 0.5 {
+		t.Fatalf("unexpected cache stats %+v", stats)
+	}
+}
+
+func TestInvokeCircuitBreakerSkipsOpenProviderAndProbesAfterCooldown(t *testing.T) {
+	t.Parallel()
+
+	primaryCalls := 0
+	primaryHealthy := false
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{ID: "stt-a", Mode: contracts.ModalitySTT, InvokeFn: func(contracts.InvocationRequest) (contracts.Outcome, error) {
+			primaryCalls++
+			if primaryHealthy {
+				return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+			}
+			return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: true, Reason: "provider_server_error"}, nil
+		}},
+		contracts.StaticAdapter{ID: "stt-b", Mode: contracts.ModalitySTT},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	virtual := clock.NewVirtual(time.Unix(1700000000, 0))
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{Window: 4, MinSamples: 2, Cooldown: 10 * time.Second, Now: virtual.Now})
+	controller := NewControllerWithConfig(catalog, Config{MaxAttemptsPerProvider: 1, CircuitBreaker: breaker})
+
+	tests := []struct {
+		name         string
+		advance      time.Duration
+		healthy      bool
+		wantCalls    int
+		wantProvider string
+		wantState    string
+		wantTripped  bool
+		wantSkipped  []string
+	}{
+		{name: "closed failure switches", wantCalls: 1, wantProvider: "stt-b", wantState: CircuitClosed},
+		{name: "error rate trips circuit", wantCalls: 2, wantProvider: "stt-b", wantState: CircuitClosed, wantTripped: true},
+		{name: "open circuit skipped", healthy: true, wantCalls: 2, wantProvider: "stt-b", wantState: CircuitClosed, wantSkipped: []string{"stt-a"}},
+		{name: "half-open probe closes circuit", advance: 10 * time.Second, healthy: true, wantCalls: 3, wantProvider: "stt-a", wantState: CircuitHalfOpen},
+		{name: "closed again", healthy: true, wantCalls: 4, wantProvider: "stt-a", wantState: CircuitClosed},
+	}
+	for idx, tc := range tests {
+		virtual.Advance(tc.advance)
+		primaryHealthy = tc.healthy
+		result, err := controller.Invoke(InvocationInput{
+			SessionID:              "sess-breaker",
+			TurnID:                 "turn-" + strconv.Itoa(idx),
+			PipelineVersion:        "pipeline-v1",
+			EventID:                "evt-breaker-" + strconv.Itoa(idx),
+			Modality:               contracts.ModalitySTT,
+			PreferredProvider:      "stt-a",
+			AllowedAdaptiveActions: []string{"provider_switch"},
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		if primaryCalls != tc.wantCalls || result.SelectedProvider != tc.wantProvider || result.Outcome.Class != contracts.OutcomeSuccess {
+			t.Fatalf("%s: expected %d primary calls and success on %s, got calls=%d %+v", tc.name, tc.wantCalls, tc.wantProvider, primaryCalls, result)
+		}
+		if !reflect.DeepEqual(result.CircuitSkipped, tc.wantSkipped) {
+			t.Fatalf("%s: expected skipped providers %v, got %v", tc.name, tc.wantSkipped, result.CircuitSkipped)
+		}
+		first := result.Attempts[0]
+		if len(tc.wantSkipped) == 0 && (first.ProviderID != "stt-a" || first.CircuitState != tc.wantState || first.CircuitTripped != tc.wantTripped) {
+			t.Fatalf("%s: expected stt-a attempt state=%s tripped=%t, got %+v", tc.name, tc.wantState, tc.wantTripped, first)
+		}
+		circuitEvents := 0
+		for _, signal := range result.Signals {
+			if signal.Signal == "circuit_event" {
+				circuitEvents++
+			}
+		}
+		wantEvents := len(tc.wantSkipped)
+		if tc.wantTripped {
+			wantEvents = 1
+		}
+		if circuitEvents != wantEvents {
+			t.Fatalf("%s: unexpected circuit_event signals %+v", tc.name, result.Signals)
+		}
+	}
+	if statuses := breaker.Statuses(); len(statuses) != 2 || statuses[0].ProviderID != "stt-a" || statuses[0].State != CircuitClosed {
+		t.Fatalf("expected stt-a circuit closed after the probe, got %+v", statuses)
+	}
+}
+
+func TestInvokeCircuitOpenWithoutSwitchFailsFast(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{ID: "tts-a", Mode: contracts.ModalityTTS, InvokeFn: func(contracts.InvocationRequest) (contracts.Outcome, error) {
+			calls++
+			return contracts.Outcome{Class: contracts.OutcomeOverload, Retryable: true, Reason: "provider_overload"}, nil
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{Window: 2, MinSamples: 2})
+	controller := NewControllerWithConfig(catalog, Config{MaxAttemptsPerProvider: 3, CircuitBreaker: breaker})
+	in := InvocationInput{
+		SessionID:              "sess-breaker-tts",
+		PipelineVersion:        "pipeline-v1",
+		EventID:                "evt-breaker-tts",
+		Modality:               contracts.ModalityTTS,
+		AllowedAdaptiveActions: []string{"retry"},
+	}
+
+	result, err := controller.Invoke(in)
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if calls != 2 || len(result.Attempts) != 2 || !result.Attempts[1].CircuitTripped {
+		t.Fatalf("expected retries to stop once the circuit opened, got calls=%d %+v", calls, result.Attempts)
+	}
+
+	result, err = controller.Invoke(in)
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if calls != 2 || len(result.Attempts) != 0 {
+		t.Fatalf("expected no attempt on an open circuit, got calls=%d %+v", calls, result.Attempts)
+	}
+	if result.Outcome.Reason != circuitOpenReason || !result.Outcome.CircuitOpen || result.Outcome.Retryable || result.CircuitState != CircuitOpen {
+		t.Fatalf("expected a non-retryable circuit_open outcome, got %+v", result)
+	}
+	if len(result.Signals) != 1 || result.Signals[0].Signal != "circuit_event" || !strings.Contains(result.Signals[0].Reason, "skipped") {
+		t.Fatalf("expected a single circuit_event skip signal, got %+v", result.Signals)
+	}
+}
+
+func TestCircuitBreakerHalfOpenAdmitsSingleProbe(t *testing.T) {
+	t.Parallel()
+
+	virtual := clock.NewVirtual(time.Unix(1700000000, 0))
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{Window: 4, MinSamples: 4, ErrorRateThreshold: 0.75, Cooldown: time.Second, Now: virtual.Now})
+	failure := contracts.Outcome{Class: contracts.OutcomeTimeout, Retryable: true}
+
+	for _, outcome := range []contracts.Outcome{failure, {Class: contracts.OutcomeCancelled}, {Class: contracts.OutcomeBlocked}, {Class: contracts.OutcomeSuccess}, failure} {
+		if breaker.Record("llm-a", outcome) {
+			t.Fatalf("expected %s below the error-rate threshold not to trip", outcome.Class)
+		}
+	}
+	if !breaker.Record("llm-a", failure) {
+		t.Fatalf("expected 3/4 windowed failures to trip")
+	}
+	if state, ok := breaker.Allow("llm-a"); ok || state != CircuitOpen {
+		t.Fatalf("expected open circuit to reject during cooldown, got %s %t", state, ok)
+	}
+
+	virtual.Advance(time.Second)
+	if state, ok := breaker.Allow("llm-a"); !ok || state != CircuitHalfOpen {
+		t.Fatalf("expected a half-open probe after cooldown, got %s %t", state, ok)
+	}
+	if _, ok := breaker.Allow("llm-a"); ok {
+		t.Fatalf("expected a second concurrent probe to be rejected")
+	}
+	breaker.Record("llm-a", contracts.Outcome{Class: contracts.OutcomeCancelled})
+	if state, ok := breaker.Allow("llm-a"); !ok || state != CircuitHalfOpen {
+		t.Fatalf("expected a cancelled probe to release the probe slot, got %s %t", state, ok)
+	}
+	if !breaker.Record("llm-a", failure) {
+		t.Fatalf("expected a failed probe to reopen the circuit")
+	}
+	statuses := breaker.Statuses()
+	if len(statuses) != 1 || statuses[0].State != CircuitOpen || !statuses[0].OpenedAt.Equal(virtual.Now()) {
+		t.Fatalf("expected reopened circuit status, got %+v", statuses)
+	}
+}
+
+func TestCircuitBreakerOpenForcesOpenUntilCooldown(t *testing.T) {
+	t.Parallel()
+
+	virtual := clock.NewVirtual(time.Unix(1700000000, 0))
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{Cooldown: time.Second, Now: virtual.Now})
+	if !breaker.Open("llm-a") {
+		t.Fatalf("expected forcing a closed circuit open to report a change")
+	}
+	if breaker.Open("llm-a") {
+		t.Fatalf("expected forcing an open circuit open to be a no-op")
+	}
+	if state, ok := breaker.Allow("llm-a"); ok || state != CircuitOpen {
+		t.Fatalf("expected forced-open circuit to reject during cooldown, got %s %t", state, ok)
+	}
+	virtual.Advance(time.Second)
+	if state, ok := breaker.Allow("llm-a"); !ok || state != CircuitHalfOpen {
+		t.Fatalf("expected a half-open probe after cooldown, got %s %t", state, ok)
+	}
+	if breaker.Record("llm-a", contracts.Outcome{Class: contracts.OutcomeSuccess}) {
+		t.Fatalf("expected a successful probe to close the circuit")
+	}
+	if state, ok := breaker.Allow("llm-a"); !ok || state != CircuitClosed {
+		t.Fatalf("expected closed circuit after probe, got %s %t", state, ok)
+	}
+}
+
+// advancingClock waits by advancing its virtual clock, so retry backoff moves the turn budget
+// without blocking the test.
+type advancingClock struct {
+	*clock.Virtual
+}
+
+func (c advancingClock) NewTimer(d time.Duration) clock.Timer {
+	timer := c.Virtual.NewTimer(d)
+	c.Advance(d)
+	return timer
+}
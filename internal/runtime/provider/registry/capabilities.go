@@ -0,0 +1,56 @@
+package registry
+
+import "github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+
+// Capabilities maps modality/provider pairs to typical performance metadata.
+type Capabilities map[contracts.Modality]map[string]contracts.Capability
+
+// Capability returns the metadata recorded for a modality/provider pair.
+func (c Capabilities) Capability(modality contracts.Modality, providerID string) (contracts.Capability, bool) {
+	capability, ok := c[modality][providerID]
+	return capability, ok
+}
+
+// DefaultCapabilities returns typical first-output latency and per-turn cost for the MVP
+// providers, measured at the default models and regions used by bootstrap. Local providers
+// cost nothing per turn; their latency assumes base-size models on a laptop CPU.
+func DefaultCapabilities() Capabilities {
+	return Capabilities{
+		contracts.ModalitySTT: {
+			"stt-deepgram":     {TypicalFirstOutputLatencyMS: 300, TypicalCostPerTurnUSD: 0.0008},
+			"stt-google":       {TypicalFirstOutputLatencyMS: 450, TypicalCostPerTurnUSD: 0.0016},
+			"stt-assemblyai":   {TypicalFirstOutputLatencyMS: 500, TypicalCostPerTurnUSD: 0.0010},
+			"stt-azure-speech": {TypicalFirstOutputLatencyMS: 400, TypicalCostPerTurnUSD: 0.0017},
+			"stt-whisper-cpp":  {TypicalFirstOutputLatencyMS: 900},
+		},
+		contracts.ModalityLLM: {
+			"llm-anthropic": {TypicalFirstOutputLatencyMS: 600, TypicalCostPerTurnUSD: 0.0020},
+			"llm-gemini":    {TypicalFirstOutputLatencyMS: 500, TypicalCostPerTurnUSD: 0.0008},
+			"llm-cohere":    {TypicalFirstOutputLatencyMS: 700, TypicalCostPerTurnUSD: 0.0015},
+			"llm-llama-cpp": {TypicalFirstOutputLatencyMS: 400},
+		},
+		contracts.ModalityTTS: {
+			"tts-elevenlabs":   {TypicalFirstOutputLatencyMS: 350, TypicalCostPerTurnUSD: 0.0060},
+			"tts-google":       {TypicalFirstOutputLatencyMS: 400, TypicalCostPerTurnUSD: 0.0016},
+			"tts-amazon-polly": {TypicalFirstOutputLatencyMS: 300, TypicalCostPerTurnUSD: 0.0016},
+			"tts-azure-speech": {TypicalFirstOutputLatencyMS: 300, TypicalCostPerTurnUSD: 0.0016},
+			"tts-piper":        {TypicalFirstOutputLatencyMS: 200},
+		},
+		contracts.ModalityS2S: {
+			"s2s-openai-realtime": {TypicalFirstOutputLatencyMS: 500, TypicalCostPerTurnUSD: 0.0300},
+		},
+	}
+}
+
+// Capability returns capability metadata for a registered adapter, preferring the adapter's
+// own report over DefaultCapabilities.
+func (c Catalog) Capability(modality contracts.Modality, providerID string) (contracts.Capability, bool) {
+	adapter, ok := c.Adapter(modality, providerID)
+	if !ok {
+		return contracts.Capability{}, false
+	}
+	if reporter, ok := adapter.(contracts.CapabilityAdapter); ok {
+		return reporter.Capability(), true
+	}
+	return DefaultCapabilities().Capability(modality, providerID)
+}
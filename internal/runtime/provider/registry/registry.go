@@ -3,6 +3,7 @@ package registry
 import (
 	"fmt"
 	"sort"
+	"sync"
 
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
 )
@@ -15,12 +16,18 @@ type Catalog struct {
 
 // NewCatalog creates a deterministic adapter catalog.
 func NewCatalog(adapters []contracts.Adapter) (Catalog, error) {
+	return NewWeightedCatalog(adapters, nil)
+}
+
+// NewWeightedCatalog creates a catalog whose candidate order within each modality is by
+// descending provider weight, then provider id. Providers missing from weights weigh 0.
+func NewWeightedCatalog(adapters []contracts.Adapter, weights map[string]int) (Catalog, error) {
 	catalog := Catalog{
 		adapters: make(map[contracts.Modality]map[string]contracts.Adapter),
 		ordered:  make(map[contracts.Modality][]string),
 	}
 
-	for _, modality := range []contracts.Modality{contracts.ModalitySTT, contracts.ModalityLLM, contracts.ModalityTTS} {
+	for _, modality := range []contracts.Modality{contracts.ModalitySTT, contracts.ModalityLLM, contracts.ModalityTTS, contracts.ModalityS2S} {
 		catalog.adapters[modality] = make(map[string]contracts.Adapter)
 	}
 
@@ -48,7 +55,12 @@ func NewCatalog(adapters []contracts.Adapter) (Catalog, error) {
 		for providerID := range providers {
 			ids = append(ids, providerID)
 		}
-		sort.Strings(ids)
+		sort.Slice(ids, func(i, j int) bool {
+			if weights[ids[i]] != weights[ids[j]] {
+				return weights[ids[i]] > weights[ids[j]]
+			}
+			return ids[i] < ids[j]
+		})
 		catalog.ordered[modality] = ids
 	}
 
@@ -120,7 +132,8 @@ func (c Catalog) Candidates(modality contracts.Modality, preferredProvider strin
 	return adapters, nil
 }
 
-// ValidateCoverage enforces provider count bounds for each MVP modality.
+// ValidateCoverage enforces provider count bounds for each MVP modality. Speech-to-speech
+// providers are optional and not counted.
 func (c Catalog) ValidateCoverage(minPerModality, maxPerModality int) error {
 	if minPerModality < 1 {
 		return fmt.Errorf("min_per_modality must be >=1")
@@ -137,3 +150,34 @@ func (c Catalog) ValidateCoverage(minPerModality, maxPerModality int) error {
 	}
 	return nil
 }
+
+// LiveCatalog holds the current catalog of a runtime whose provider set can be swapped
+// while invocations are in flight. It is safe for concurrent use.
+type LiveCatalog struct {
+	mu      sync.RWMutex
+	catalog Catalog
+}
+
+// NewLiveCatalog returns a live catalog serving catalog.
+func NewLiveCatalog(catalog Catalog) *LiveCatalog {
+	return &LiveCatalog{catalog: catalog}
+}
+
+// Catalog returns the current catalog.
+func (l *LiveCatalog) Catalog() Catalog {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.catalog
+}
+
+// Swap replaces the current catalog; invocations already holding candidates finish on them.
+func (l *LiveCatalog) Swap(catalog Catalog) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.catalog = catalog
+}
+
+// Candidates resolves candidates against the current catalog.
+func (l *LiveCatalog) Candidates(modality contracts.Modality, preferredProvider string, maxProviders int) ([]contracts.Adapter, error) {
+	return l.Catalog().Candidates(modality, preferredProvider, maxProviders)
+}
@@ -1,6 +1,7 @@
 package registry
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
@@ -74,3 +75,116 @@ func TestValidateCoverageRequiresThreeToFiveProvidersPerModality(t *testing.T) {
 		t.Fatalf("expected coverage check to pass, got %v", err)
 	}
 }
+
+type capabilityAdapter struct {
+	contracts.StaticAdapter
+	capability contracts.Capability
+}
+
+func (a capabilityAdapter) Capability() contracts.Capability {
+	return a.capability
+}
+
+func TestCatalogCapabilityPrefersAdapterReport(t *testing.T) {
+	t.Parallel()
+
+	reported := contracts.Capability{TypicalFirstOutputLatencyMS: 120, TypicalCostPerTurnUSD: 0.01}
+	catalog, err := NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{ID: "stt-deepgram", Mode: contracts.ModalitySTT},
+		contracts.StaticAdapter{ID: "stt-unlisted", Mode: contracts.ModalitySTT},
+		capabilityAdapter{StaticAdapter: contracts.StaticAdapter{ID: "llm-anthropic", Mode: contracts.ModalityLLM}, capability: reported},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog build error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		modality   contracts.Modality
+		providerID string
+		want       contracts.Capability
+		found      bool
+	}{
+		{name: "default table", modality: contracts.ModalitySTT, providerID: "stt-deepgram", want: DefaultCapabilities()[contracts.ModalitySTT]["stt-deepgram"], found: true},
+		{name: "adapter report", modality: contracts.ModalityLLM, providerID: "llm-anthropic", want: reported, found: true},
+		{name: "registered without metadata", modality: contracts.ModalitySTT, providerID: "stt-unlisted"},
+		{name: "unregistered", modality: contracts.ModalityTTS, providerID: "tts-google"},
+	}
+	for _, tc := range tests {
+		got, found := catalog.Capability(tc.modality, tc.providerID)
+		if found != tc.found || got != tc.want {
+			t.Fatalf("%s: expected %+v found=%t, got %+v found=%t", tc.name, tc.want, tc.found, got, found)
+		}
+	}
+}
+
+func TestSpeechToSpeechProvidersAreOptional(t *testing.T) {
+	t.Parallel()
+
+	adapters := []contracts.Adapter{
+		contracts.StaticAdapter{ID: "stt-a", Mode: contracts.ModalitySTT},
+		contracts.StaticAdapter{ID: "stt-b", Mode: contracts.ModalitySTT},
+		contracts.StaticAdapter{ID: "stt-c", Mode: contracts.ModalitySTT},
+		contracts.StaticAdapter{ID: "llm-a", Mode: contracts.ModalityLLM},
+		contracts.StaticAdapter{ID: "llm-b", Mode: contracts.ModalityLLM},
+		contracts.StaticAdapter{ID: "llm-c", Mode: contracts.ModalityLLM},
+		contracts.StaticAdapter{ID: "tts-a", Mode: contracts.ModalityTTS},
+		contracts.StaticAdapter{ID: "tts-b", Mode: contracts.ModalityTTS},
+		contracts.StaticAdapter{ID: "tts-c", Mode: contracts.ModalityTTS},
+	}
+	catalog, err := NewCatalog(append(adapters, contracts.StaticAdapter{ID: "s2s-a", Mode: contracts.ModalityS2S}))
+	if err != nil {
+		t.Fatalf("unexpected catalog build error: %v", err)
+	}
+	if err := catalog.ValidateCoverage(3, 5); err != nil {
+		t.Fatalf("expected a single s2s provider not to affect coverage, got %v", err)
+	}
+	candidates, err := catalog.Candidates(contracts.ModalityS2S, "", 5)
+	if err != nil || len(candidates) != 1 || candidates[0].ProviderID() != "s2s-a" {
+		t.Fatalf("expected the s2s provider as sole candidate, got %v err=%v", candidates, err)
+	}
+
+	withoutS2S, err := NewCatalog(adapters)
+	if err != nil {
+		t.Fatalf("unexpected catalog build error: %v", err)
+	}
+	if err := withoutS2S.ValidateCoverage(3, 5); err != nil {
+		t.Fatalf("expected coverage without s2s providers, got %v", err)
+	}
+	if _, err := withoutS2S.ProviderIDs(contracts.ModalityS2S); err == nil {
+		t.Fatalf("expected no s2s providers to be registered")
+	}
+}
+
+func TestWeightedCatalogOrdersByWeightAndSwapsLive(t *testing.T) {
+	t.Parallel()
+
+	adapters := []contracts.Adapter{
+		contracts.StaticAdapter{ID: "stt-a", Mode: contracts.ModalitySTT},
+		contracts.StaticAdapter{ID: "stt-b", Mode: contracts.ModalitySTT},
+		contracts.StaticAdapter{ID: "stt-c", Mode: contracts.ModalitySTT},
+	}
+	weighted, err := NewWeightedCatalog(adapters, map[string]int{"stt-c": 10, "stt-b": 5})
+	if err != nil {
+		t.Fatalf("unexpected catalog build error: %v", err)
+	}
+	ids, err := weighted.ProviderIDs(contracts.ModalitySTT)
+	if err != nil || strings.Join(ids, ",") != "stt-c,stt-b,stt-a" {
+		t.Fatalf("expected weight-descending order, got %v err=%v", ids, err)
+	}
+
+	plain, err := NewCatalog(adapters)
+	if err != nil {
+		t.Fatalf("unexpected catalog build error: %v", err)
+	}
+	live := NewLiveCatalog(plain)
+	candidates, err := live.Candidates(contracts.ModalitySTT, "", 5)
+	if err != nil || candidates[0].ProviderID() != "stt-a" {
+		t.Fatalf("expected id order before swap, got %v err=%v", candidates, err)
+	}
+	live.Swap(weighted)
+	candidates, err = live.Candidates(contracts.ModalitySTT, "", 5)
+	if err != nil || candidates[0].ProviderID() != "stt-c" {
+		t.Fatalf("expected swapped weighted order, got %v err=%v", candidates, err)
+	}
+}
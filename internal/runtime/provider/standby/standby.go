@@ -0,0 +1,240 @@
+package standby
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/cost"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
+)
+
+// EnvEnabled turns on warm standby for critical deployments.
+const EnvEnabled = "RSPP_PROVIDER_WARM_STANDBY"
+
+// EnabledFromEnv reports whether RSPP_PROVIDER_WARM_STANDBY enables warm standby.
+func EnabledFromEnv() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(EnvEnabled))) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+type holdKey struct {
+	modality   contracts.Modality
+	providerID string
+}
+
+type hold struct {
+	holder contracts.StandbyHolder
+	// heldSinceMS is when held time was last accrued; meaningful only while held.
+	heldSinceMS int64
+	held        bool
+	// released stops Refresh re-holding the standby until Hold opens it again.
+	released bool
+	upkeep   timeline.StandbyUpkeepEvidence
+}
+
+// Manager keeps the secondary provider of each held modality connected, so a provider_switch
+// onto it skips connection setup, and accounts the upkeep of every standby it holds.
+type Manager struct {
+	catalog registry.Catalog
+	prices  cost.PriceTable
+	clock   clock.Clock
+
+	mu    sync.Mutex
+	holds map[holdKey]*hold
+}
+
+// NewManager returns a manager over catalog adapters that prices upkeep with prices.
+func NewManager(catalog registry.Catalog, prices cost.PriceTable) *Manager {
+	return NewManagerWithClock(catalog, prices, clock.Real())
+}
+
+// NewManagerWithClock returns a manager with an injected clock, which measures held time and
+// paces upkeep refreshes.
+func NewManagerWithClock(catalog registry.Catalog, prices cost.PriceTable, clk clock.Clock) *Manager {
+	if clk == nil {
+		clk = clock.Real()
+	}
+	return &Manager{catalog: catalog, prices: prices, clock: clk, holds: make(map[holdKey]*hold)}
+}
+
+// Hold opens a warm standby on the provider a preferredProvider failure would switch to, the
+// second candidate in the modality. A secondary without standby support is reported with
+// reason standby_unsupported.
+func (m *Manager) Hold(modality contracts.Modality, preferredProvider string) (contracts.StandbyResult, error) {
+	m.mu.Lock()
+	catalog := m.catalog
+	m.mu.Unlock()
+	candidates, err := catalog.Candidates(modality, preferredProvider, 2)
+	if err != nil {
+		return contracts.StandbyResult{}, err
+	}
+	if len(candidates) < 2 {
+		return contracts.StandbyResult{}, fmt.Errorf("warm standby requires a secondary %s provider", modality)
+	}
+	secondary := candidates[1]
+	holder, ok := secondary.(contracts.StandbyHolder)
+	if !ok {
+		return contracts.StandbyResult{ProviderID: secondary.ProviderID(), Modality: modality, Reason: "standby_unsupported"}, nil
+	}
+	result := holder.HoldStandby()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := holdKey{modality: modality, providerID: secondary.ProviderID()}
+	h, ok := m.holds[key]
+	if !ok {
+		h = &hold{holder: holder, upkeep: timeline.StandbyUpkeepEvidence{ProviderID: key.providerID, Modality: string(modality)}}
+		m.holds[key] = h
+	}
+	h.released = false
+	m.observeLocked(h, result.Held)
+	return result, nil
+}
+
+// Refresh re-holds every standby not released, keeping pooled connections inside their idle
+// timeout. A failed refresh stops accruing held time until a later refresh succeeds.
+func (m *Manager) Refresh() {
+	m.mu.Lock()
+	holds := make([]*hold, 0, len(m.holds))
+	for _, h := range m.holds {
+		if !h.released {
+			holds = append(holds, h)
+		}
+	}
+	m.mu.Unlock()
+
+	results := make([]contracts.StandbyResult, len(holds))
+	var wg sync.WaitGroup
+	for i, h := range holds {
+		wg.Add(1)
+		go func(i int, holder contracts.StandbyHolder) {
+			defer wg.Done()
+			results[i] = holder.HoldStandby()
+		}(i, h.holder)
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, h := range holds {
+		if h.released {
+			// Released while refreshing; keep the connection from counting as held.
+			h.holder.ReleaseStandby()
+			continue
+		}
+		m.observeLocked(h, results[i].Held)
+	}
+}
+
+// StartUpkeep refreshes standbys every interval until the returned stop function is called.
+func (m *Manager) StartUpkeep(interval time.Duration) (func(), error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("standby upkeep interval must be >0")
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := m.clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C():
+				m.Refresh()
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}, nil
+}
+
+// Release drops every standby. Upkeep accrued up to the release stays reported by Upkeep.
+func (m *Manager) Release() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	nowMS := m.clock.Now().UnixMilli()
+	for _, h := range m.holds {
+		m.accrueLocked(h, nowMS)
+		h.held = false
+		h.released = true
+		h.holder.ReleaseStandby()
+	}
+}
+
+// UseCatalog releases every standby and holds later ones on catalog's providers, as a
+// provider catalog reload does. Upkeep accrued before the swap stays reported by Upkeep.
+func (m *Manager) UseCatalog(catalog registry.Catalog) {
+	m.Release()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.catalog = catalog
+}
+
+// Upkeep returns the upkeep accrued since the previous call, priced with the manager's price
+// table and ordered by modality then provider id, and resets the accrual.
+func (m *Manager) Upkeep() []timeline.StandbyUpkeepEvidence {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	nowMS := m.clock.Now().UnixMilli()
+	upkeep := make([]timeline.StandbyUpkeepEvidence, 0, len(m.holds))
+	for key, h := range m.holds {
+		m.accrueLocked(h, nowMS)
+		entry := h.upkeep
+		if entry.HeldMS == 0 && entry.Refreshes == 0 && entry.Failures == 0 {
+			if !h.held {
+				delete(m.holds, key)
+			}
+			continue
+		}
+		entry.EstimatedCostUSD = m.prices.PriceStandby(entry.ProviderID, entry.HeldMS)
+		upkeep = append(upkeep, entry)
+		h.upkeep = timeline.StandbyUpkeepEvidence{ProviderID: entry.ProviderID, Modality: entry.Modality}
+	}
+	sort.Slice(upkeep, func(i, j int) bool {
+		if upkeep[i].Modality != upkeep[j].Modality {
+			return upkeep[i].Modality < upkeep[j].Modality
+		}
+		return upkeep[i].ProviderID < upkeep[j].ProviderID
+	})
+	return upkeep
+}
+
+func (m *Manager) observeLocked(h *hold, held bool) {
+	nowMS := m.clock.Now().UnixMilli()
+	m.accrueLocked(h, nowMS)
+	if held {
+		h.upkeep.Refreshes++
+		h.heldSinceMS = nowMS
+	} else {
+		h.upkeep.Failures++
+	}
+	h.held = held
+}
+
+func (m *Manager) accrueLocked(h *hold, nowMS int64) {
+	if !h.held {
+		return
+	}
+	if nowMS > h.heldSinceMS {
+		h.upkeep.HeldMS += nowMS - h.heldSinceMS
+	}
+	h.heldSinceMS = nowMS
+}
@@ -0,0 +1,194 @@
+package standby
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/cost"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
+)
+
+type stubStandbyAdapter struct {
+	contracts.StaticAdapter
+
+	mu    sync.Mutex
+	fail  bool
+	held  bool
+	holds int
+}
+
+func (a *stubStandbyAdapter) HoldStandby() contracts.StandbyResult {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.holds++
+	a.held = !a.fail
+	result := contracts.StandbyResult{ProviderID: a.ID, Modality: a.Mode, Held: a.held}
+	if !a.held {
+		result.Reason = "provider_auth_rejected"
+	}
+	return result
+}
+
+func (a *stubStandbyAdapter) ReleaseStandby() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.held = false
+}
+
+func (a *stubStandbyAdapter) StandbyHeld() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.held
+}
+
+func (a *stubStandbyAdapter) setFail(fail bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.fail = fail
+}
+
+func TestManagerHoldsSecondaryAndAccountsUpkeep(t *testing.T) {
+	t.Parallel()
+
+	secondary := &stubStandbyAdapter{StaticAdapter: contracts.StaticAdapter{ID: "stt-b", Mode: contracts.ModalitySTT}}
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{ID: "stt-a", Mode: contracts.ModalitySTT},
+		secondary,
+		contracts.StaticAdapter{ID: "tts-a", Mode: contracts.ModalityTTS},
+		contracts.StaticAdapter{ID: "tts-b", Mode: contracts.ModalityTTS},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	virtual := clock.NewVirtual(time.UnixMilli(1000))
+	manager := NewManagerWithClock(catalog, cost.PriceTable{"stt-b": {StandbyPerHourUSD: 0.36}}, virtual)
+
+	result, err := manager.Hold(contracts.ModalitySTT, "stt-a")
+	if err != nil || !result.Held || result.ProviderID != "stt-b" || !contracts.HoldsStandby(secondary) {
+		t.Fatalf("expected the secondary stt provider held, got %+v err=%v", result, err)
+	}
+	unsupported, err := manager.Hold(contracts.ModalityTTS, "tts-a")
+	if err != nil || unsupported.Held || unsupported.Reason != "standby_unsupported" {
+		t.Fatalf("expected a secondary without standby support to be reported, got %+v err=%v", unsupported, err)
+	}
+	if _, err := manager.Hold(contracts.ModalityLLM, ""); err == nil {
+		t.Fatalf("expected a modality without providers to fail")
+	}
+
+	virtual.Advance(30 * time.Second)
+	secondary.setFail(true)
+	manager.Refresh()
+	virtual.Advance(20 * time.Second)
+	secondary.setFail(false)
+	manager.Refresh()
+	virtual.Advance(10 * time.Second)
+
+	upkeep := manager.Upkeep()
+	if len(upkeep) != 1 {
+		t.Fatalf("expected one upkeep entry, got %+v", upkeep)
+	}
+	entry := upkeep[0]
+	if entry.ProviderID != "stt-b" || entry.HeldMS != 40_000 || entry.Refreshes != 2 || entry.Failures != 1 {
+		t.Fatalf("expected held time to stop while the refresh failed, got %+v", entry)
+	}
+	if math.Abs(entry.EstimatedCostUSD-0.004) > 1e-9 {
+		t.Fatalf("expected 40s at $0.36/h to cost $0.004, got %f", entry.EstimatedCostUSD)
+	}
+
+	virtual.Advance(5 * time.Second)
+	manager.Release()
+	virtual.Advance(time.Minute)
+	if upkeep := manager.Upkeep(); len(upkeep) != 1 || upkeep[0].HeldMS != 5000 || upkeep[0].Refreshes != 0 || contracts.HoldsStandby(secondary) {
+		t.Fatalf("expected upkeep to stop at release, got %+v", upkeep)
+	}
+	if upkeep := manager.Upkeep(); len(upkeep) != 0 {
+		t.Fatalf("expected no upkeep after release was reported, got %+v", upkeep)
+	}
+}
+
+func TestManagerStartUpkeepRefreshesOnInterval(t *testing.T) {
+	t.Parallel()
+
+	secondary := &stubStandbyAdapter{StaticAdapter: contracts.StaticAdapter{ID: "llm-b", Mode: contracts.ModalityLLM}}
+	catalog, err := registry.NewCatalog([]contracts.Adapter{contracts.StaticAdapter{ID: "llm-a", Mode: contracts.ModalityLLM}, secondary})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	virtual := clock.NewVirtual(time.UnixMilli(1000))
+	manager := NewManagerWithClock(catalog, nil, virtual)
+	if _, err := manager.StartUpkeep(0); err == nil {
+		t.Fatalf("expected non-positive upkeep interval to fail")
+	}
+	if _, err := manager.Hold(contracts.ModalityLLM, "llm-a"); err != nil {
+		t.Fatalf("unexpected hold error: %v", err)
+	}
+	stop, err := manager.StartUpkeep(time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected upkeep error: %v", err)
+	}
+	virtual.BlockUntil(1)
+	virtual.Advance(time.Minute)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		secondary.mu.Lock()
+		refreshes := secondary.holds
+		secondary.mu.Unlock()
+		if refreshes >= 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected an upkeep refresh after one interval")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	stop()
+	stop()
+	if upkeep := manager.Upkeep(); len(upkeep) != 1 || upkeep[0].HeldMS != 60_000 || upkeep[0].EstimatedCostUSD != 0 {
+		t.Fatalf("expected unpriced upkeep over one interval, got %+v", upkeep)
+	}
+}
+
+func TestManagerUseCatalogMovesStandbys(t *testing.T) {
+	t.Parallel()
+
+	before := &stubStandbyAdapter{StaticAdapter: contracts.StaticAdapter{ID: "llm-b", Mode: contracts.ModalityLLM}}
+	after := &stubStandbyAdapter{StaticAdapter: contracts.StaticAdapter{ID: "llm-c", Mode: contracts.ModalityLLM}}
+	primary := contracts.StaticAdapter{ID: "llm-a", Mode: contracts.ModalityLLM}
+	oldCatalog, err := registry.NewCatalog([]contracts.Adapter{primary, before})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	newCatalog, err := registry.NewCatalog([]contracts.Adapter{primary, after})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	manager := NewManagerWithClock(oldCatalog, nil, clock.NewVirtual(time.UnixMilli(1000)))
+	if _, err := manager.Hold(contracts.ModalityLLM, ""); err != nil {
+		t.Fatalf("unexpected hold error: %v", err)
+	}
+
+	manager.UseCatalog(newCatalog)
+	manager.Refresh()
+	if contracts.HoldsStandby(before) {
+		t.Fatalf("expected the swapped-out provider's standby released and not refreshed")
+	}
+	result, err := manager.Hold(contracts.ModalityLLM, "")
+	if err != nil || !result.Held || result.ProviderID != "llm-c" || !contracts.HoldsStandby(after) {
+		t.Fatalf("expected the reloaded catalog's secondary held, got %+v err=%v", result, err)
+	}
+}
+
+func TestEnabledFromEnv(t *testing.T) {
+	t.Setenv(EnvEnabled, " On ")
+	if !EnabledFromEnv() {
+		t.Fatalf("expected warm standby enabled")
+	}
+	t.Setenv(EnvEnabled, "")
+	if EnabledFromEnv() {
+		t.Fatalf("expected warm standby disabled by default")
+	}
+}
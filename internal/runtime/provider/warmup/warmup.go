@@ -0,0 +1,263 @@
+package warmup
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/providerhealth"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
+)
+
+// Phase identifies when a warm-up pass ran.
+type Phase string
+
+const (
+	PhaseRuntimeStart Phase = "runtime_start"
+	PhaseSessionStart Phase = "session_start"
+	PhaseKeepAlive    Phase = "keepalive"
+)
+
+// Validate enforces supported warm-up phases.
+func (p Phase) Validate() error {
+	switch p {
+	case PhaseRuntimeStart, PhaseSessionStart, PhaseKeepAlive:
+		return nil
+	default:
+		return fmt.Errorf("unsupported warmup phase: %q", p)
+	}
+}
+
+// ProviderResult is one provider warm-up outcome within a pass.
+type ProviderResult struct {
+	contracts.WarmupResult
+	Phase      Phase
+	Supported  bool
+	WarmedAtMS int64
+}
+
+// Report summarizes one warm-up pass across the catalog.
+type Report struct {
+	Phase     Phase
+	SessionID string
+	Results   []ProviderResult
+	Warmed    int
+	Failed    int
+	Skipped   int
+}
+
+// Summary returns a deterministic one-line warm-up summary.
+func (r Report) Summary() string {
+	return fmt.Sprintf("provider warmup phase=%s warmed=%d failed=%d skipped=%d", r.Phase, r.Warmed, r.Failed, r.Skipped)
+}
+
+// Tracker runs warm-up passes against a catalog and retains the latest result per provider
+// for provider health snapshots.
+type Tracker struct {
+	clock clock.Clock
+
+	mu      sync.Mutex
+	catalog registry.Catalog
+	// modalities are the modalities whose providers passes warm.
+	modalities []contracts.Modality
+	latest     map[string]ProviderResult
+}
+
+// NewTracker returns a tracker over catalog adapters.
+func NewTracker(catalog registry.Catalog) *Tracker {
+	return NewTrackerWithClock(catalog, clock.Real())
+}
+
+// NewTrackerWithClock returns a tracker with an injected clock, which timestamps warm-up
+// results and paces keep-alive passes.
+func NewTrackerWithClock(catalog registry.Catalog, clk clock.Clock) *Tracker {
+	if clk == nil {
+		clk = clock.Real()
+	}
+	return &Tracker{catalog: catalog, clock: clk, modalities: warmModalities, latest: make(map[string]ProviderResult)}
+}
+
+// ScopeModalities limits later warm-up passes to the providers of modalities, as the ones
+// a session's turns invoke, and drops the results of providers outside them.
+func (t *Tracker) ScopeModalities(modalities ...contracts.Modality) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.modalities = append([]contracts.Modality(nil), modalities...)
+	t.pruneLocked()
+}
+
+// WarmRuntime runs the runtime-start warm-up pass.
+func (t *Tracker) WarmRuntime() (Report, error) {
+	return t.run(PhaseRuntimeStart, "")
+}
+
+// WarmSession runs a session-start warm-up pass so session-scoped connections are live
+// before the first turn opens.
+func (t *Tracker) WarmSession(sessionID string) (Report, error) {
+	if sessionID == "" {
+		return Report{}, fmt.Errorf("session_id is required")
+	}
+	return t.run(PhaseSessionStart, sessionID)
+}
+
+// StartKeepAlive re-warms provider connections every interval until the returned stop
+// function is called. Keep-alive passes refresh the tracked snapshot only.
+func (t *Tracker) StartKeepAlive(interval time.Duration) (func(), error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("keepalive interval must be >0")
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := t.clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C():
+				_, _ = t.run(PhaseKeepAlive, "")
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}, nil
+}
+
+// WarmupStatuses returns the latest warm-up result per provider, ordered by provider id.
+func (t *Tracker) WarmupStatuses() []providerhealth.WarmupStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ids := make([]string, 0, len(t.latest))
+	for providerID := range t.latest {
+		ids = append(ids, providerID)
+	}
+	sort.Strings(ids)
+
+	statuses := make([]providerhealth.WarmupStatus, 0, len(ids))
+	for _, providerID := range ids {
+		result := t.latest[providerID]
+		statuses = append(statuses, providerhealth.WarmupStatus{
+			ProviderID:       result.ProviderID,
+			Modality:         string(result.Modality),
+			Phase:            string(result.Phase),
+			Warmed:           result.Warmed,
+			LatencyMS:        result.LatencyMS,
+			ConnectionReused: result.ConnectionReused,
+			Reason:           result.Reason,
+			WarmedAtMS:       result.WarmedAtMS,
+		})
+	}
+	return statuses
+}
+
+func (t *Tracker) run(phase Phase, sessionID string) (Report, error) {
+	if err := phase.Validate(); err != nil {
+		return Report{}, err
+	}
+	adapters, err := t.adapters()
+	if err != nil {
+		return Report{}, err
+	}
+
+	results := make([]ProviderResult, len(adapters))
+	var wg sync.WaitGroup
+	for i, adapter := range adapters {
+		warmer, ok := adapter.(contracts.Warmer)
+		if !ok {
+			results[i] = ProviderResult{
+				WarmupResult: contracts.WarmupResult{ProviderID: adapter.ProviderID(), Modality: adapter.Modality(), Reason: "warmup_unsupported"},
+				Phase:        phase,
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, warmer contracts.Warmer) {
+			defer wg.Done()
+			results[i] = ProviderResult{WarmupResult: warmer.Warm(), Phase: phase, Supported: true}
+		}(i, warmer)
+	}
+	wg.Wait()
+
+	warmedAtMS := t.clock.Now().UnixMilli()
+	report := Report{Phase: phase, SessionID: sessionID, Results: results}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := range report.Results {
+		result := &report.Results[i]
+		result.WarmedAtMS = warmedAtMS
+		switch {
+		case !result.Supported:
+			report.Skipped++
+		case result.Warmed:
+			report.Warmed++
+		default:
+			report.Failed++
+		}
+		if result.Supported {
+			t.latest[result.ProviderID] = *result
+		}
+	}
+	return report, nil
+}
+
+// UseCatalog points later warm-up passes at catalog, as after a provider catalog reload, and
+// drops the results of providers catalog no longer serves.
+func (t *Tracker) UseCatalog(catalog registry.Catalog) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.catalog = catalog
+	t.pruneLocked()
+}
+
+// pruneLocked drops the results of providers the scoped catalog modalities no longer serve.
+func (t *Tracker) pruneLocked() {
+	served := make(map[string]struct{})
+	for _, modality := range t.modalities {
+		ids, _ := t.catalog.ProviderIDs(modality)
+		for _, providerID := range ids {
+			served[providerID] = struct{}{}
+		}
+	}
+	for providerID := range t.latest {
+		if _, ok := served[providerID]; !ok {
+			delete(t.latest, providerID)
+		}
+	}
+}
+
+var warmModalities = []contracts.Modality{contracts.ModalitySTT, contracts.ModalityLLM, contracts.ModalityTTS, contracts.ModalityS2S}
+
+func (t *Tracker) adapters() ([]contracts.Adapter, error) {
+	t.mu.Lock()
+	catalog, modalities := t.catalog, t.modalities
+	t.mu.Unlock()
+
+	adapters := make([]contracts.Adapter, 0)
+	for _, modality := range modalities {
+		ids, err := catalog.ProviderIDs(modality)
+		if err != nil {
+			continue
+		}
+		for _, providerID := range ids {
+			adapter, ok := catalog.Adapter(modality, providerID)
+			if ok {
+				adapters = append(adapters, adapter)
+			}
+		}
+	}
+	if len(adapters) == 0 {
+		return nil, fmt.Errorf("no providers registered for warmup")
+	}
+	return adapters, nil
+}
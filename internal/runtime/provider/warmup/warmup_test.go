@@ -0,0 +1,206 @@
+package warmup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/providerhealth"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
+)
+
+type stubWarmAdapter struct {
+	contracts.StaticAdapter
+	result contracts.WarmupResult
+	// warmed, when set, receives each warm-up call.
+	warmed chan struct{}
+}
+
+func (a stubWarmAdapter) Warm() contracts.WarmupResult {
+	if a.warmed != nil {
+		a.warmed <- struct{}{}
+	}
+	return a.result
+}
+
+func TestTrackerWarmRuntimeAndSnapshot(t *testing.T) {
+	t.Parallel()
+
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		stubWarmAdapter{
+			StaticAdapter: contracts.StaticAdapter{ID: "stt-a", Mode: contracts.ModalitySTT},
+			result:        contracts.WarmupResult{ProviderID: "stt-a", Modality: contracts.ModalitySTT, Warmed: true, LatencyMS: 42},
+		},
+		stubWarmAdapter{
+			StaticAdapter: contracts.StaticAdapter{ID: "llm-a", Mode: contracts.ModalityLLM},
+			result:        contracts.WarmupResult{ProviderID: "llm-a", Modality: contracts.ModalityLLM, Reason: "provider_transport_error"},
+		},
+		contracts.StaticAdapter{ID: "tts-a", Mode: contracts.ModalityTTS},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	tracker := NewTrackerWithClock(catalog, clock.NewVirtual(time.UnixMilli(1000)))
+
+	report, err := tracker.WarmRuntime()
+	if err != nil {
+		t.Fatalf("unexpected warmup error: %v", err)
+	}
+	if report.Phase != PhaseRuntimeStart || report.Warmed != 1 || report.Failed != 1 || report.Skipped != 1 {
+		t.Fatalf("unexpected warmup report: %+v", report)
+	}
+
+	service := providerhealth.NewService()
+	service.Warmup = tracker
+	out, err := service.GetSnapshot(providerhealth.Input{Scope: "tenant-a"})
+	if err != nil {
+		t.Fatalf("unexpected snapshot error: %v", err)
+	}
+	if len(out.ProviderWarmup) != 2 {
+		t.Fatalf("expected warmup statuses for warmable providers only, got %+v", out.ProviderWarmup)
+	}
+	if got := out.ProviderWarmup[1]; got.ProviderID != "stt-a" || !got.Warmed || got.LatencyMS != 42 || got.Phase != string(PhaseRuntimeStart) || got.WarmedAtMS != 1000 {
+		t.Fatalf("unexpected stt warmup status: %+v", got)
+	}
+	if got := out.ProviderWarmup[0]; got.ProviderID != "llm-a" || got.Warmed || got.Reason != "provider_transport_error" {
+		t.Fatalf("unexpected llm warmup status: %+v", got)
+	}
+
+	session, err := tracker.WarmSession("sess-warm-1")
+	if err != nil {
+		t.Fatalf("unexpected session warmup error: %v", err)
+	}
+	if session.Phase != PhaseSessionStart || session.SessionID != "sess-warm-1" {
+		t.Fatalf("unexpected session warmup report: %+v", session)
+	}
+	if statuses := tracker.WarmupStatuses(); statuses[0].Phase != string(PhaseSessionStart) {
+		t.Fatalf("expected session warmup to refresh snapshot, got %+v", statuses)
+	}
+}
+
+func TestTrackerValidation(t *testing.T) {
+	t.Parallel()
+
+	catalog, err := registry.NewCatalog(nil)
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	tracker := NewTracker(catalog)
+	if _, err := tracker.WarmRuntime(); err == nil {
+		t.Fatalf("expected empty catalog warmup error")
+	}
+	if _, err := tracker.WarmSession(""); err == nil {
+		t.Fatalf("expected session_id validation error")
+	}
+	if _, err := tracker.StartKeepAlive(0); err == nil {
+		t.Fatalf("expected keepalive interval validation error")
+	}
+}
+
+func TestTrackerKeepAliveRefreshesSnapshot(t *testing.T) {
+	t.Parallel()
+
+	warmed := make(chan struct{})
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		stubWarmAdapter{
+			StaticAdapter: contracts.StaticAdapter{ID: "llm-a", Mode: contracts.ModalityLLM},
+			result:        contracts.WarmupResult{ProviderID: "llm-a", Modality: contracts.ModalityLLM, Warmed: true, ConnectionReused: true},
+			warmed:        warmed,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	virtual := clock.NewVirtual(time.UnixMilli(5_000))
+	tracker := NewTrackerWithClock(catalog, virtual)
+	stop, err := tracker.StartKeepAlive(time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected keepalive error: %v", err)
+	}
+	virtual.BlockUntil(1)
+	virtual.Advance(59 * time.Second)
+	if statuses := tracker.WarmupStatuses(); len(statuses) != 0 {
+		t.Fatalf("expected no keepalive pass before the interval, got %+v", statuses)
+	}
+	virtual.Advance(time.Second)
+	<-warmed
+	stop()
+	stop()
+
+	statuses := tracker.WarmupStatuses()
+	if len(statuses) != 1 || statuses[0].Phase != string(PhaseKeepAlive) || !statuses[0].ConnectionReused || statuses[0].WarmedAtMS != 65_000 {
+		t.Fatalf("expected one keepalive warmup status after the interval, got %+v", statuses)
+	}
+}
+
+func TestTrackerUseCatalogFollowsReloads(t *testing.T) {
+	t.Parallel()
+
+	warmAdapter := func(id string) contracts.Adapter {
+		return stubWarmAdapter{
+			StaticAdapter: contracts.StaticAdapter{ID: id, Mode: contracts.ModalityLLM},
+			result:        contracts.WarmupResult{ProviderID: id, Modality: contracts.ModalityLLM, Warmed: true},
+		}
+	}
+	initial, err := registry.NewCatalog([]contracts.Adapter{warmAdapter("llm-a"), warmAdapter("llm-b")})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	tracker := NewTrackerWithClock(initial, clock.NewVirtual(time.UnixMilli(1000)))
+	if _, err := tracker.WarmRuntime(); err != nil {
+		t.Fatalf("unexpected warmup error: %v", err)
+	}
+
+	reloaded, err := registry.NewCatalog([]contracts.Adapter{warmAdapter("llm-b"), warmAdapter("llm-c")})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	tracker.UseCatalog(reloaded)
+	if statuses := tracker.WarmupStatuses(); len(statuses) != 1 || statuses[0].ProviderID != "llm-b" {
+		t.Fatalf("expected removed provider dropped on reload, got %+v", statuses)
+	}
+	report, err := tracker.WarmRuntime()
+	if err != nil {
+		t.Fatalf("unexpected warmup error: %v", err)
+	}
+	if report.Warmed != 2 {
+		t.Fatalf("expected reloaded catalog warmed, got %+v", report)
+	}
+	if statuses := tracker.WarmupStatuses(); len(statuses) != 2 || statuses[0].ProviderID != "llm-b" || statuses[1].ProviderID != "llm-c" {
+		t.Fatalf("unexpected statuses after reload warm-up: %+v", statuses)
+	}
+}
+
+func TestTrackerScopeModalitiesWarmsSessionProvidersOnly(t *testing.T) {
+	t.Parallel()
+
+	warmAdapter := func(id string, modality contracts.Modality) contracts.Adapter {
+		return stubWarmAdapter{
+			StaticAdapter: contracts.StaticAdapter{ID: id, Mode: modality},
+			result:        contracts.WarmupResult{ProviderID: id, Modality: modality, Warmed: true},
+		}
+	}
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		warmAdapter("llm-a", contracts.ModalityLLM),
+		warmAdapter("s2s-a", contracts.ModalityS2S),
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	tracker := NewTrackerWithClock(catalog, clock.NewVirtual(time.UnixMilli(1000)))
+	if _, err := tracker.WarmRuntime(); err != nil {
+		t.Fatalf("unexpected warmup error: %v", err)
+	}
+	tracker.ScopeModalities(contracts.ModalitySTT, contracts.ModalityLLM, contracts.ModalityTTS)
+	if statuses := tracker.WarmupStatuses(); len(statuses) != 1 || statuses[0].ProviderID != "llm-a" {
+		t.Fatalf("expected out-of-scope provider dropped, got %+v", statuses)
+	}
+	report, err := tracker.WarmSession("sess-1")
+	if err != nil {
+		t.Fatalf("unexpected warmup error: %v", err)
+	}
+	if len(report.Results) != 1 || report.Results[0].ProviderID != "llm-a" {
+		t.Fatalf("expected only the scoped provider warmed, got %+v", report.Results)
+	}
+}
@@ -0,0 +1,162 @@
+package sequence
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/identifiers"
+)
+
+// Allocator is the runtime's single source of runtime_sequence values: per session, sequences
+// start at 1 and every issued value is unique and strictly increasing.
+type Allocator struct {
+	mu   sync.Mutex
+	last map[string]int64
+}
+
+// NewAllocator returns an allocator with no sessions.
+func NewAllocator() *Allocator {
+	return &Allocator{last: map[string]int64{}}
+}
+
+// Next issues the next runtime sequence for the session.
+func (a *Allocator) Next(sessionID string) (int64, error) {
+	return a.Reserve(sessionID, 1)
+}
+
+// Reserve issues a contiguous block of n sequences and returns its first value, for callers
+// that derive per-node sequences as base+offset.
+func (a *Allocator) Reserve(sessionID string, n int) (int64, error) {
+	if err := identifiers.ValidateSessionID(sessionID); err != nil {
+		return 0, err
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("sequence reservation must be >=1, got %d", n)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	first := a.last[sessionID] + 1
+	a.last[sessionID] += int64(n)
+	return first, nil
+}
+
+// Last returns the highest sequence issued for the session.
+func (a *Allocator) Last(sessionID string) (int64, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	last, ok := a.last[sessionID]
+	return last, ok
+}
+
+// Forget drops a closed session's counter.
+func (a *Allocator) Forget(sessionID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.last, sessionID)
+}
+
+// StampEventRecord assigns the record its runtime sequence. The caller must not have set one:
+// a non-zero runtime_sequence on an unstamped record means some other party, typically a
+// transport, tried to choose its own position in the session order.
+func (a *Allocator) StampEventRecord(record eventabi.EventRecord) (eventabi.EventRecord, error) {
+	if record.RuntimeSequence != 0 {
+		return eventabi.EventRecord{}, fmt.Errorf("event %s carries runtime_sequence %d; runtime sequences are assigned by the runtime", record.EventID, record.RuntimeSequence)
+	}
+	seq, err := a.Next(record.SessionID)
+	if err != nil {
+		return eventabi.EventRecord{}, err
+	}
+	record.RuntimeSequence = seq
+	return record, nil
+}
+
+// Anomaly kinds reported by Detector.
+const (
+	AnomalyGap        = "gap"
+	AnomalyDuplicate  = "duplicate"
+	AnomalyRegression = "regression"
+)
+
+// Anomaly is a break in a session's runtime sequence order.
+type Anomaly struct {
+	SessionID string
+	Kind      string
+	// Expected is the sequence that should have followed the previous one.
+	Expected int64
+	Observed int64
+}
+
+// OrderingMarker renders the anomaly as determinism ordering evidence. A gap lists the missing
+// inclusive range; duplicates and regressions name the offending sequence.
+func (a Anomaly) OrderingMarker() string {
+	if a.Kind == AnomalyGap {
+		return fmt.Sprintf("runtime_sequence_gap:%d-%d", a.Expected, a.Observed-1)
+	}
+	return fmt.Sprintf("runtime_sequence_%s:%d", a.Kind, a.Observed)
+}
+
+// Detector watches observed runtime sequences per session. The first observation of a session
+// sets its baseline, so a detector attached mid-stream reports nothing until order breaks.
+type Detector struct {
+	mu   sync.Mutex
+	last map[string]int64
+}
+
+// NewDetector returns a detector with no sessions.
+func NewDetector() *Detector {
+	return &Detector{last: map[string]int64{}}
+}
+
+// Observe records seq for the session and reports the anomaly it reveals, if any. Gaps advance
+// the baseline; duplicates and regressions leave it unchanged.
+func (d *Detector) Observe(sessionID string, seq int64) (Anomaly, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	last, seen := d.last[sessionID]
+	if !seen {
+		d.last[sessionID] = seq
+		return Anomaly{}, false
+	}
+	anomaly := Anomaly{SessionID: sessionID, Expected: last + 1, Observed: seq}
+	switch {
+	case seq == last+1:
+		d.last[sessionID] = seq
+		return Anomaly{}, false
+	case seq > last+1:
+		d.last[sessionID] = seq
+		anomaly.Kind = AnomalyGap
+	case seq == last:
+		anomaly.Kind = AnomalyDuplicate
+	default:
+		anomaly.Kind = AnomalyRegression
+	}
+	return anomaly, true
+}
+
+// AuditEventRecords reports every sequence anomaly across records in the order given.
+func AuditEventRecords(records []eventabi.EventRecord) []Anomaly {
+	detector := NewDetector()
+	var anomalies []Anomaly
+	for _, record := range records {
+		if anomaly, ok := detector.Observe(record.SessionID, record.RuntimeSequence); ok {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+	return anomalies
+}
+
+// OrderingMarkers renders anomalies as unique ordering markers, in order.
+func OrderingMarkers(anomalies []Anomaly) []string {
+	seen := make(map[string]struct{}, len(anomalies))
+	markers := make([]string, 0, len(anomalies))
+	for _, anomaly := range anomalies {
+		marker := anomaly.OrderingMarker()
+		if _, ok := seen[marker]; ok {
+			continue
+		}
+		seen[marker] = struct{}{}
+		markers = append(markers, marker)
+	}
+	return markers
+}
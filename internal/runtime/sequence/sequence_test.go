@@ -0,0 +1,118 @@
+package sequence
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+func TestAllocatorIssuesUniqueMonotonicSequencesPerSession(t *testing.T) {
+	t.Parallel()
+
+	alloc := NewAllocator()
+	if seq, err := alloc.Next("sess-1"); err != nil || seq != 1 {
+		t.Fatalf("expected first sequence 1, got %d err=%v", seq, err)
+	}
+	if first, err := alloc.Reserve("sess-1", 3); err != nil || first != 2 {
+		t.Fatalf("expected block starting at 2, got %d err=%v", first, err)
+	}
+	if seq, err := alloc.Next("sess-1"); err != nil || seq != 5 {
+		t.Fatalf("expected sequence after the block, got %d err=%v", seq, err)
+	}
+	if seq, err := alloc.Next("sess-2"); err != nil || seq != 1 {
+		t.Fatalf("expected sessions to be independent, got %d err=%v", seq, err)
+	}
+
+	var wg sync.WaitGroup
+	issued := make(chan int64, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			seq, err := alloc.Next("sess-3")
+			if err != nil {
+				t.Errorf("unexpected concurrent next error: %v", err)
+			}
+			issued <- seq
+		}()
+	}
+	wg.Wait()
+	close(issued)
+	seen := map[int64]bool{}
+	for seq := range issued {
+		if seen[seq] {
+			t.Fatalf("sequence %d issued twice", seq)
+		}
+		seen[seq] = true
+	}
+	if last, _ := alloc.Last("sess-3"); last != 100 {
+		t.Fatalf("expected 100 sequences issued, got last=%d", last)
+	}
+
+	alloc.Forget("sess-1")
+	if _, ok := alloc.Last("sess-1"); ok {
+		t.Fatalf("expected forgotten session to reset")
+	}
+
+	tests := []struct {
+		name      string
+		sessionID string
+		n         int
+	}{
+		{name: "empty session", sessionID: "", n: 1},
+		{name: "malformed session", sessionID: "sess 1", n: 1},
+		{name: "empty block", sessionID: "sess-1", n: 0},
+	}
+	for _, tc := range tests {
+		if _, err := alloc.Reserve(tc.sessionID, tc.n); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
+
+func TestStampEventRecordRejectsCallerSequences(t *testing.T) {
+	t.Parallel()
+
+	alloc := NewAllocator()
+	stamped, err := alloc.StampEventRecord(eventabi.EventRecord{SessionID: "sess-1", EventID: "evt-1"})
+	if err != nil || stamped.RuntimeSequence != 1 {
+		t.Fatalf("expected stamped sequence 1, got %+v err=%v", stamped, err)
+	}
+	if _, err := alloc.StampEventRecord(eventabi.EventRecord{SessionID: "sess-1", EventID: "evt-2", RuntimeSequence: 1000}); err == nil {
+		t.Fatalf("expected a caller-chosen sequence to be rejected")
+	}
+}
+
+func TestDetectorReportsGapsDuplicatesAndRegressions(t *testing.T) {
+	t.Parallel()
+
+	detector := NewDetector()
+	tests := []struct {
+		name       string
+		sessionID  string
+		seq        int64
+		wantMarker string
+	}{
+		{name: "baseline", sessionID: "sess-1", seq: 4},
+		{name: "next", sessionID: "sess-1", seq: 5},
+		{name: "other session baseline", sessionID: "sess-2", seq: 1},
+		{name: "duplicate", sessionID: "sess-1", seq: 5, wantMarker: "runtime_sequence_duplicate:5"},
+		{name: "gap", sessionID: "sess-1", seq: 9, wantMarker: "runtime_sequence_gap:6-8"},
+		{name: "after gap", sessionID: "sess-1", seq: 10},
+		{name: "regression", sessionID: "sess-1", seq: 7, wantMarker: "runtime_sequence_regression:7"},
+		{name: "other session next", sessionID: "sess-2", seq: 2},
+	}
+	for _, tc := range tests {
+		anomaly, ok := detector.Observe(tc.sessionID, tc.seq)
+		if tc.wantMarker == "" {
+			if ok {
+				t.Fatalf("%s: unexpected anomaly %+v", tc.name, anomaly)
+			}
+			continue
+		}
+		if !ok || anomaly.OrderingMarker() != tc.wantMarker {
+			t.Fatalf("%s: expected %s, got %+v ok=%v", tc.name, tc.wantMarker, anomaly, ok)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package snapshotfreshness
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/turnarbiter"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
+)
+
+// maxEvidence bounds degradation evidence kept in the report.
+const maxEvidence = 64
+
+// Fallback is the degraded behavior applied while a snapshot is stale.
+type Fallback string
+
+const (
+	// FallbackServeStale keeps serving the last snapshot and only records evidence.
+	FallbackServeStale Fallback = "serve_stale"
+	// FallbackDeferTurns marks turn-open snapshots invalid with a defer failure policy.
+	FallbackDeferTurns Fallback = "defer_turns"
+	// FallbackRejectTurns marks turn-open snapshots invalid with a reject failure policy.
+	FallbackRejectTurns Fallback = "reject_turns"
+	// FallbackDefaultPolicy switches retention enforcement to the default retention policy.
+	FallbackDefaultPolicy Fallback = "default_policy"
+)
+
+// Evidence reasons.
+const (
+	ReasonAgeExceeded   = "age_exceeded"
+	ReasonMarkedStale   = "marked_stale"
+	ReasonObserveFailed = "observe_failed"
+	ReasonRecovered     = "recovered"
+)
+
+// Threshold bounds one snapshot kind's age and names its fallback.
+type Threshold struct {
+	MaxAge   time.Duration
+	Fallback Fallback
+}
+
+// DefaultThresholds keeps turn-path snapshots within 30s and retention within 24h.
+func DefaultThresholds() map[distribution.SnapshotKind]Threshold {
+	return map[distribution.SnapshotKind]Threshold{
+		distribution.SnapshotRoutingView: {MaxAge: 30 * time.Second, Fallback: FallbackDeferTurns},
+		distribution.SnapshotAdmission:   {MaxAge: 30 * time.Second, Fallback: FallbackDeferTurns},
+		distribution.SnapshotRetention:   {MaxAge: 24 * time.Hour, Fallback: FallbackDefaultPolicy},
+	}
+}
+
+// Config controls one freshness monitor loop.
+type Config struct {
+	Interval time.Duration
+	// Runs bounds the loop; zero runs until the context is cancelled.
+	Runs int
+	// Thresholds overrides DefaultThresholds per snapshot kind.
+	Thresholds map[distribution.SnapshotKind]Threshold
+}
+
+// Validate enforces monitor settings.
+func (c Config) Validate() error {
+	if c.Interval <= 0 {
+		return fmt.Errorf("snapshot freshness interval must be >0")
+	}
+	if c.Runs < 0 {
+		return fmt.Errorf("snapshot freshness runs must be >=0")
+	}
+	for kind, threshold := range c.Thresholds {
+		if threshold.MaxAge <= 0 {
+			return fmt.Errorf("snapshot freshness %s max age must be >0", kind)
+		}
+		if !fallbackAllowed(kind, threshold.Fallback) {
+			return fmt.Errorf("snapshot freshness %s fallback %q is unsupported", kind, threshold.Fallback)
+		}
+	}
+	return nil
+}
+
+func fallbackAllowed(kind distribution.SnapshotKind, fallback Fallback) bool {
+	switch kind {
+	case distribution.SnapshotRoutingView, distribution.SnapshotAdmission:
+		return fallback == FallbackServeStale || fallback == FallbackDeferTurns || fallback == FallbackRejectTurns
+	case distribution.SnapshotRetention:
+		return fallback == FallbackServeStale || fallback == FallbackDefaultPolicy
+	default:
+		return false
+	}
+}
+
+// SnapshotStatus is the latest freshness classification of one snapshot.
+type SnapshotStatus struct {
+	Kind          distribution.SnapshotKind `json:"kind"`
+	Source        string                    `json:"source,omitempty"`
+	PublishedAtMS int64                     `json:"published_at_ms,omitempty"`
+	AgeMS         int64                     `json:"age_ms"`
+	MaxAgeMS      int64                     `json:"max_age_ms"`
+	MarkedStale   bool                      `json:"marked_stale,omitempty"`
+	Stale         bool                      `json:"stale"`
+	// Fallback is the active degraded behavior while Stale.
+	Fallback Fallback `json:"fallback,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+}
+
+// Evidence records one transition into or out of a degraded fallback.
+type Evidence struct {
+	Kind         distribution.SnapshotKind `json:"kind"`
+	DetectedAtMS int64                     `json:"detected_at_ms"`
+	AgeMS        int64                     `json:"age_ms"`
+	MaxAgeMS     int64                     `json:"max_age_ms"`
+	Fallback     Fallback                  `json:"fallback,omitempty"`
+	Reason       string                    `json:"reason"`
+	Detail       string                    `json:"detail,omitempty"`
+}
+
+// Report is the freshness artifact written after each observation.
+type Report struct {
+	GeneratedAtUTC string           `json:"generated_at_utc"`
+	IntervalMS     int64            `json:"interval_ms"`
+	TotalRuns      int              `json:"total_runs"`
+	Degraded       bool             `json:"degraded"`
+	ObserveError   string           `json:"observe_error,omitempty"`
+	Snapshots      []SnapshotStatus `json:"snapshots"`
+	Evidence       []Evidence       `json:"evidence,omitempty"`
+}
+
+// Monitor tracks tracked CP snapshot ages continuously and degrades per-kind to the
+// configured fallback when a threshold is exceeded.
+type Monitor struct {
+	cfg      Config
+	observer distribution.SnapshotObserver
+	clock    clock.Clock
+	// Publish receives the report after every observation.
+	Publish func(Report) error
+
+	mu           sync.Mutex
+	totalRuns    int
+	observeError string
+	last         map[distribution.SnapshotKind]distribution.SnapshotObservation
+	statuses     map[distribution.SnapshotKind]SnapshotStatus
+	evidence     []Evidence
+}
+
+// NewMonitor validates config and returns a monitor reading snapshots from observer; clk
+// timestamps observations and paces Run, and nil uses the real clock.
+func NewMonitor(cfg Config, observer distribution.SnapshotObserver, clk clock.Clock) (*Monitor, error) {
+	thresholds := DefaultThresholds()
+	for kind, threshold := range cfg.Thresholds {
+		thresholds[kind] = threshold
+	}
+	cfg.Thresholds = thresholds
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if observer == nil {
+		return nil, fmt.Errorf("snapshot freshness observer is required")
+	}
+	if clk == nil {
+		clk = clock.Real()
+	}
+	return &Monitor{
+		cfg:      cfg,
+		observer: observer,
+		clock:    clk,
+		last:     make(map[distribution.SnapshotKind]distribution.SnapshotObservation),
+		statuses: make(map[distribution.SnapshotKind]SnapshotStatus),
+	}, nil
+}
+
+// Run observes snapshots until Runs is reached or ctx is cancelled.
+func (m *Monitor) Run(ctx context.Context) (Report, error) {
+	ticker := m.clock.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		report, err := m.RunOnce()
+		if err != nil {
+			return report, err
+		}
+		if m.cfg.Runs > 0 && report.TotalRuns >= m.cfg.Runs {
+			return report, nil
+		}
+		select {
+		case <-ctx.Done():
+			return report, nil
+		case <-ticker.C():
+		}
+	}
+}
+
+// RunOnce observes snapshots, classifies freshness, emits telemetry, and publishes the report.
+// Observation failures age the last known snapshots rather than resetting them.
+func (m *Monitor) RunOnce() (Report, error) {
+	observations, observeErr := m.observer.ObserveSnapshots()
+	nowMS := m.clock.Now().UnixMilli()
+
+	m.mu.Lock()
+	m.totalRuns++
+	m.observeError = ""
+	if observeErr != nil {
+		m.observeError = observeErr.Error()
+	}
+	for _, observation := range observations {
+		m.last[observation.Kind] = observation
+	}
+	for _, kind := range distribution.TrackedSnapshotKinds() {
+		m.classify(kind, nowMS)
+	}
+	report := m.reportLocked()
+	m.mu.Unlock()
+
+	for _, status := range report.Snapshots {
+		emit(status, nowMS)
+	}
+	if m.Publish != nil {
+		if err := m.Publish(report); err != nil {
+			return report, fmt.Errorf("publish snapshot freshness: %w", err)
+		}
+	}
+	return report, nil
+}
+
+func (m *Monitor) classify(kind distribution.SnapshotKind, nowMS int64) {
+	threshold := m.cfg.Thresholds[kind]
+	status := SnapshotStatus{Kind: kind, MaxAgeMS: threshold.MaxAge.Milliseconds()}
+	observation, seen := m.last[kind]
+	switch {
+	case !seen:
+		status.Stale = true
+		status.Reason = ReasonObserveFailed
+	default:
+		status.Source = observation.Source
+		status.PublishedAtMS = observation.PublishedAtMS
+		status.AgeMS = max(nowMS-observation.PublishedAtMS, 0)
+		status.MarkedStale = observation.MarkedStale
+		switch {
+		case observation.MarkedStale:
+			status.Stale = true
+			status.Reason = ReasonMarkedStale
+		case status.AgeMS > status.MaxAgeMS:
+			status.Stale = true
+			status.Reason = ReasonAgeExceeded
+		}
+	}
+	if status.Stale {
+		status.Fallback = threshold.Fallback
+	}
+
+	previous, known := m.statuses[kind]
+	switch {
+	case status.Stale && (!known || !previous.Stale || previous.Reason != status.Reason):
+		m.record(Evidence{
+			Kind:         kind,
+			DetectedAtMS: nowMS,
+			AgeMS:        status.AgeMS,
+			MaxAgeMS:     status.MaxAgeMS,
+			Fallback:     status.Fallback,
+			Reason:       status.Reason,
+			Detail:       m.observeError,
+		})
+	case !status.Stale && known && previous.Stale:
+		m.record(Evidence{Kind: kind, DetectedAtMS: nowMS, AgeMS: status.AgeMS, MaxAgeMS: status.MaxAgeMS, Reason: ReasonRecovered})
+	}
+	m.statuses[kind] = status
+}
+
+func (m *Monitor) record(evidence Evidence) {
+	m.evidence = append(m.evidence, evidence)
+	if len(m.evidence) > maxEvidence {
+		m.evidence = append([]Evidence(nil), m.evidence[len(m.evidence)-maxEvidence:]...)
+	}
+	severity := "warn"
+	if evidence.Reason == ReasonRecovered {
+		severity = "info"
+	}
+	attributes := map[string]string{
+		"snapshot": string(evidence.Kind),
+		"reason":   evidence.Reason,
+		"fallback": string(evidence.Fallback),
+	}
+	telemetry.DefaultEmitter().EmitLog(
+		"cp_snapshot_freshness",
+		severity,
+		fmt.Sprintf("cp snapshot %s %s (age_ms=%d max_age_ms=%d)", evidence.Kind, evidence.Reason, evidence.AgeMS, evidence.MaxAgeMS),
+		attributes,
+		telemetry.Correlation{EmittedBy: "OR-01", WallClockTimestampMS: evidence.DetectedAtMS},
+	)
+}
+
+// Report returns the current freshness report.
+func (m *Monitor) Report() Report {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.reportLocked()
+}
+
+func (m *Monitor) reportLocked() Report {
+	report := Report{
+		GeneratedAtUTC: m.clock.Now().UTC().Format(time.RFC3339),
+		IntervalMS:     m.cfg.Interval.Milliseconds(),
+		TotalRuns:      m.totalRuns,
+		ObserveError:   m.observeError,
+		Evidence:       append([]Evidence(nil), m.evidence...),
+	}
+	for _, kind := range distribution.TrackedSnapshotKinds() {
+		status, ok := m.statuses[kind]
+		if !ok {
+			continue
+		}
+		report.Snapshots = append(report.Snapshots, status)
+		if status.Stale {
+			report.Degraded = true
+		}
+	}
+	return report
+}
+
+// Status returns the latest classification for kind.
+func (m *Monitor) Status(kind distribution.SnapshotKind) (SnapshotStatus, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status, ok := m.statuses[kind]
+	return status, ok
+}
+
+// ApplyToOpenRequest degrades a turn-open request while the routing or admission snapshot
+// is stale with a defer/reject fallback: the snapshot is marked invalid and the fallback
+// becomes the snapshot failure policy. Reject wins when both snapshots are degraded.
+func (m *Monitor) ApplyToOpenRequest(req turnarbiter.OpenRequest) turnarbiter.OpenRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, kind := range []distribution.SnapshotKind{distribution.SnapshotRoutingView, distribution.SnapshotAdmission} {
+		status := m.statuses[kind]
+		if !status.Stale {
+			continue
+		}
+		switch status.Fallback {
+		case FallbackRejectTurns:
+			req.SnapshotValid = false
+			req.SnapshotFailurePolicy = controlplane.OutcomeReject
+		case FallbackDeferTurns:
+			req.SnapshotValid = false
+			if req.SnapshotFailurePolicy != controlplane.OutcomeReject {
+				req.SnapshotFailurePolicy = controlplane.OutcomeDefer
+			}
+		}
+	}
+	return req
+}
+
+// UseDefaultRetentionPolicy reports whether retention enforcement should fall back to the
+// default retention policy because the retention snapshot is stale.
+func (m *Monitor) UseDefaultRetentionPolicy() bool {
+	status, _ := m.Status(distribution.SnapshotRetention)
+	return status.Stale && status.Fallback == FallbackDefaultPolicy
+}
+
+func emit(status SnapshotStatus, nowMS int64) {
+	attributes := map[string]string{"snapshot": string(status.Kind)}
+	correlation := telemetry.Correlation{EmittedBy: "OR-01", WallClockTimestampMS: nowMS}
+	staleValue := 0.0
+	if status.Stale {
+		staleValue = 1
+	}
+	telemetry.DefaultEmitter().EmitMetric(telemetry.MetricCPSnapshotAgeMS, float64(status.AgeMS), "ms", attributes, correlation)
+	telemetry.DefaultEmitter().EmitMetric(telemetry.MetricCPSnapshotStale, staleValue, "1", attributes, correlation)
+}
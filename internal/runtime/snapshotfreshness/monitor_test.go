@@ -0,0 +1,190 @@
+package snapshotfreshness
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/turnarbiter"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
+)
+
+type scriptedObserver struct {
+	steps []observeStep
+	calls int
+}
+
+type observeStep struct {
+	publishedAtMS int64
+	markedStale   distribution.SnapshotKind
+	err           error
+}
+
+func (o *scriptedObserver) ObserveSnapshots() ([]distribution.SnapshotObservation, error) {
+	step := o.steps[min(o.calls, len(o.steps)-1)]
+	o.calls++
+	if step.err != nil {
+		return nil, step.err
+	}
+	out := make([]distribution.SnapshotObservation, 0, 3)
+	for _, kind := range distribution.TrackedSnapshotKinds() {
+		out = append(out, distribution.SnapshotObservation{
+			Kind:          kind,
+			Source:        "cp.json",
+			PublishedAtMS: step.publishedAtMS,
+			MarkedStale:   kind == step.markedStale,
+		})
+	}
+	return out, nil
+}
+
+func TestMonitorDegradesAndRecoversWithEvidence(t *testing.T) {
+	t.Parallel()
+
+	virtual := clock.NewVirtual(time.UnixMilli(1_000))
+	observer := &scriptedObserver{steps: []observeStep{
+		{publishedAtMS: 1_000},
+		{publishedAtMS: 1_000},
+		{publishedAtMS: 1_000},
+		{publishedAtMS: 70_000},
+	}}
+	monitor, err := NewMonitor(Config{Interval: time.Second}, observer, virtual)
+	if err != nil {
+		t.Fatalf("unexpected monitor error: %v", err)
+	}
+
+	report, _ := monitor.RunOnce()
+	if report.Degraded || len(report.Evidence) != 0 {
+		t.Fatalf("expected fresh snapshots, got %+v", report)
+	}
+
+	virtual.Advance(39 * time.Second)
+	report, _ = monitor.RunOnce()
+	if !report.Degraded {
+		t.Fatalf("expected routing/admission age breach, got %+v", report)
+	}
+	routing, _ := monitor.Status(distribution.SnapshotRoutingView)
+	if !routing.Stale || routing.Reason != ReasonAgeExceeded || routing.Fallback != FallbackDeferTurns || routing.AgeMS != 39_000 {
+		t.Fatalf("unexpected routing status: %+v", routing)
+	}
+	if retention, _ := monitor.Status(distribution.SnapshotRetention); retention.Stale || monitor.UseDefaultRetentionPolicy() {
+		t.Fatalf("expected retention within its 24h threshold, got %+v", retention)
+	}
+	if len(report.Evidence) != 2 {
+		t.Fatalf("expected evidence for routing and admission, got %+v", report.Evidence)
+	}
+
+	virtual.Advance(5 * time.Second)
+	report, _ = monitor.RunOnce()
+	if len(report.Evidence) != 2 {
+		t.Fatalf("expected no duplicate evidence while still stale, got %+v", report.Evidence)
+	}
+
+	virtual.Advance(26 * time.Second)
+	report, _ = monitor.RunOnce()
+	if report.Degraded || len(report.Evidence) != 4 || report.Evidence[3].Reason != ReasonRecovered {
+		t.Fatalf("expected recovery evidence, got %+v", report)
+	}
+}
+
+func TestMonitorObserveFailureAgesLastKnownSnapshots(t *testing.T) {
+	t.Parallel()
+
+	virtual := clock.NewVirtual(time.UnixMilli(0))
+	observer := &scriptedObserver{steps: []observeStep{
+		{err: errors.New("cp unavailable")},
+		{publishedAtMS: 0},
+		{err: errors.New("cp unavailable")},
+	}}
+	monitor, err := NewMonitor(Config{
+		Interval:   time.Second,
+		Thresholds: map[distribution.SnapshotKind]Threshold{distribution.SnapshotRetention: {MaxAge: time.Minute, Fallback: FallbackDefaultPolicy}},
+	}, observer, virtual)
+	if err != nil {
+		t.Fatalf("unexpected monitor error: %v", err)
+	}
+
+	report, _ := monitor.RunOnce()
+	if !report.Degraded || report.ObserveError == "" || report.Snapshots[0].Reason != ReasonObserveFailed {
+		t.Fatalf("expected never-observed snapshots to degrade, got %+v", report)
+	}
+
+	virtual.Advance(10 * time.Second)
+	if report, _ = monitor.RunOnce(); report.Degraded {
+		t.Fatalf("expected fresh snapshots after successful observation, got %+v", report)
+	}
+
+	virtual.Advance(80 * time.Second)
+	report, _ = monitor.RunOnce()
+	retention, _ := monitor.Status(distribution.SnapshotRetention)
+	if retention.AgeMS != 90_000 || retention.Reason != ReasonAgeExceeded || !monitor.UseDefaultRetentionPolicy() {
+		t.Fatalf("expected last known retention snapshot to age past threshold, got %+v", retention)
+	}
+	if report.ObserveError != "cp unavailable" {
+		t.Fatalf("expected observe error in report, got %q", report.ObserveError)
+	}
+}
+
+func TestApplyToOpenRequestUsesConfiguredFallback(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		routing       Fallback
+		markedStale   distribution.SnapshotKind
+		wantValid     bool
+		wantOnFailure controlplane.OutcomeKind
+	}{
+		{name: "fresh snapshots pass through", routing: FallbackRejectTurns, wantValid: true, wantOnFailure: controlplane.OutcomeDefer},
+		{name: "stale routing rejects", routing: FallbackRejectTurns, markedStale: distribution.SnapshotRoutingView, wantValid: false, wantOnFailure: controlplane.OutcomeReject},
+		{name: "stale admission defers", routing: FallbackRejectTurns, markedStale: distribution.SnapshotAdmission, wantValid: false, wantOnFailure: controlplane.OutcomeDefer},
+		{name: "serve stale keeps snapshot valid", routing: FallbackServeStale, markedStale: distribution.SnapshotRoutingView, wantValid: true, wantOnFailure: controlplane.OutcomeDefer},
+	}
+	for _, tc := range tests {
+		observer := &scriptedObserver{steps: []observeStep{{publishedAtMS: 0, markedStale: tc.markedStale}}}
+		monitor, err := NewMonitor(Config{
+			Interval:   time.Millisecond,
+			Runs:       1,
+			Thresholds: map[distribution.SnapshotKind]Threshold{distribution.SnapshotRoutingView: {MaxAge: time.Minute, Fallback: tc.routing}},
+		}, observer, clock.NewVirtual(time.UnixMilli(0)))
+		if err != nil {
+			t.Fatalf("%s: unexpected monitor error: %v", tc.name, err)
+		}
+		if _, err := monitor.Run(context.Background()); err != nil {
+			t.Fatalf("%s: unexpected run error: %v", tc.name, err)
+		}
+		req := monitor.ApplyToOpenRequest(turnarbiter.OpenRequest{SnapshotValid: true, SnapshotFailurePolicy: controlplane.OutcomeDefer})
+		if req.SnapshotValid != tc.wantValid || req.SnapshotFailurePolicy != tc.wantOnFailure {
+			t.Fatalf("%s: expected valid=%v policy=%s, got %+v", tc.name, tc.wantValid, tc.wantOnFailure, req)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		cfg       Config
+		shouldErr bool
+	}{
+		{name: "defaults", cfg: Config{Interval: time.Second}},
+		{name: "zero interval", cfg: Config{}, shouldErr: true},
+		{name: "negative runs", cfg: Config{Interval: time.Second, Runs: -1}, shouldErr: true},
+		{name: "zero max age", cfg: Config{Interval: time.Second, Thresholds: map[distribution.SnapshotKind]Threshold{distribution.SnapshotAdmission: {Fallback: FallbackDeferTurns}}}, shouldErr: true},
+		{name: "default policy on admission", cfg: Config{Interval: time.Second, Thresholds: map[distribution.SnapshotKind]Threshold{distribution.SnapshotAdmission: {MaxAge: time.Second, Fallback: FallbackDefaultPolicy}}}, shouldErr: true},
+		{name: "reject turns on retention", cfg: Config{Interval: time.Second, Thresholds: map[distribution.SnapshotKind]Threshold{distribution.SnapshotRetention: {MaxAge: time.Second, Fallback: FallbackRejectTurns}}}, shouldErr: true},
+	}
+	for _, tc := range tests {
+		_, err := NewMonitor(tc.cfg, &scriptedObserver{steps: []observeStep{{}}}, nil)
+		if tc.shouldErr && err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package summarization
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+// NodeType is the execution node type of the session summarization node.
+const NodeType = "session_summarization"
+
+// Turn is one completed turn's transcript contribution to session context.
+type Turn struct {
+	TurnID        string
+	UserText      string
+	AssistantText string
+}
+
+// Request asks the configured LLM to condense turns, folding in the prior summary when set.
+type Request struct {
+	SessionID        string
+	TurnID           string
+	PipelineVersion  string
+	ProviderID       string
+	PriorSummary     string
+	Turns            []Turn
+	MaxSummaryTokens int
+}
+
+// Summarizer produces summary text with the configured LLM provider.
+type Summarizer interface {
+	Summarize(Request) (string, error)
+}
+
+// SummarizerFunc adapts a function to Summarizer.
+type SummarizerFunc func(Request) (string, error)
+
+// Summarize calls f(req).
+func (f SummarizerFunc) Summarize(req Request) (string, error) {
+	return f(req)
+}
+
+// EvidenceAppender records summary provenance for replay.
+type EvidenceAppender interface {
+	AppendSessionSummaryEvidence(timeline.SessionSummaryEvidence) error
+}
+
+// Summary is one derived_summary payload with the provenance recorded for replay.
+type Summary struct {
+	SummaryID      string
+	Text           string
+	SourceTurnIDs  []string
+	PriorSummaryID string
+	InputHash      string
+	SummaryHash    string
+	CreatedAtMS    int64
+	ExpiresAtMS    int64
+}
+
+// Context is the session context bound into a turn: the latest live summary plus the turns
+// completed since it was produced.
+type Context struct {
+	Summary     *Summary
+	RecentTurns []Turn
+}
+
+// InvocationSummary returns the bound summary for LLM provider invocation, or nil.
+func (c Context) InvocationSummary() *contracts.SessionSummary {
+	if c.Summary == nil {
+		return nil
+	}
+	return &contracts.SessionSummary{SummaryID: c.Summary.SummaryID, Text: c.Summary.Text}
+}
+
+// Config scopes a summarization node to one session.
+type Config struct {
+	SessionID       string
+	PipelineVersion string
+	Settings        controlplane.Summarization
+	// RetentionPolicy supplies the tenant derived_summary retention window.
+	RetentionPolicy replay.RetentionPolicy
+}
+
+func (c Config) validate() error {
+	if c.SessionID == "" || c.PipelineVersion == "" {
+		return fmt.Errorf("summarization session_id and pipeline_version are required")
+	}
+	if err := c.Settings.Validate(); err != nil {
+		return err
+	}
+	return c.RetentionPolicy.Validate()
+}
+
+// Node periodically condenses accumulated session turns into a derived_summary. Turns stay
+// pending until a summary covering them succeeds, so a failed summarization retries on the
+// next completed turn without losing context.
+type Node struct {
+	cfg         Config
+	summarizer  Summarizer
+	appender    EvidenceAppender
+	retentionMS int64
+
+	mu       sync.Mutex
+	pending  []Turn
+	latest   *Summary
+	produced int
+}
+
+// NewNode constructs a session summarization node. appender may be nil.
+func NewNode(cfg Config, summarizer Summarizer, appender EvidenceAppender) (*Node, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if summarizer == nil {
+		return nil, fmt.Errorf("summarizer is required")
+	}
+	retentionMS := cfg.RetentionPolicy.RetentionWindowFor(eventabi.PayloadDerivedSummary)
+	if cfg.Settings.RetentionMS > 0 && cfg.Settings.RetentionMS < retentionMS {
+		retentionMS = cfg.Settings.RetentionMS
+	}
+	return &Node{cfg: cfg, summarizer: summarizer, appender: appender, retentionMS: retentionMS}, nil
+}
+
+// CompleteTurn folds a completed turn into session context and summarizes once IntervalTurns
+// turns are pending. It returns the new summary, or nil when no summary was produced.
+func (n *Node) CompleteTurn(turn Turn, eventID string, nowMS int64) (*Summary, error) {
+	if turn.TurnID == "" {
+		return nil, fmt.Errorf("turn_id is required")
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.pending = append(n.pending, turn)
+	if len(n.pending) < n.cfg.Settings.IntervalTurns {
+		return nil, nil
+	}
+
+	prior := n.liveSummaryLocked(nowMS)
+	req := Request{
+		SessionID:        n.cfg.SessionID,
+		TurnID:           turn.TurnID,
+		PipelineVersion:  n.cfg.PipelineVersion,
+		ProviderID:       n.cfg.Settings.ProviderID,
+		Turns:            append([]Turn(nil), n.pending...),
+		MaxSummaryTokens: n.cfg.Settings.MaxSummaryTokens,
+	}
+	if prior != nil {
+		req.PriorSummary = prior.Text
+	}
+	text, err := n.summarizer.Summarize(req)
+	if err != nil {
+		return nil, fmt.Errorf("summarize session %s: %w", n.cfg.SessionID, err)
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("summarize session %s: empty summary", n.cfg.SessionID)
+	}
+
+	summary := &Summary{
+		SummaryID:     fmt.Sprintf("%s-summary-%d", n.cfg.SessionID, n.produced+1),
+		Text:          text,
+		SourceTurnIDs: turnIDs(req.Turns),
+		InputHash:     inputHash(req),
+		SummaryHash:   hashParts(text),
+		CreatedAtMS:   nowMS,
+		ExpiresAtMS:   nowMS + n.retentionMS,
+	}
+	if prior != nil {
+		summary.PriorSummaryID = prior.SummaryID
+	}
+	if n.appender != nil {
+		if err := n.appender.AppendSessionSummaryEvidence(timeline.SessionSummaryEvidence{
+			SessionID:       n.cfg.SessionID,
+			TurnID:          turn.TurnID,
+			PipelineVersion: n.cfg.PipelineVersion,
+			EventID:         eventID,
+			SummaryID:       summary.SummaryID,
+			PayloadClass:    eventabi.PayloadDerivedSummary,
+			ProviderID:      n.cfg.Settings.ProviderID,
+			SourceTurnIDs:   summary.SourceTurnIDs,
+			PriorSummaryID:  summary.PriorSummaryID,
+			InputHash:       summary.InputHash,
+			SummaryHash:     summary.SummaryHash,
+			CreatedAtMS:     summary.CreatedAtMS,
+			ExpiresAtMS:     summary.ExpiresAtMS,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	n.produced++
+	n.latest = summary
+	n.pending = nil
+	out := *summary
+	return &out, nil
+}
+
+// Bind returns the context for the next turn. A summary past its derived_summary retention
+// window is no longer bound.
+func (n *Node) Bind(nowMS int64) Context {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	ctx := Context{RecentTurns: append([]Turn(nil), n.pending...)}
+	if live := n.liveSummaryLocked(nowMS); live != nil {
+		summary := *live
+		ctx.Summary = &summary
+	}
+	return ctx
+}
+
+func (n *Node) liveSummaryLocked(nowMS int64) *Summary {
+	if n.latest == nil || nowMS >= n.latest.ExpiresAtMS {
+		return nil
+	}
+	return n.latest
+}
+
+func turnIDs(turns []Turn) []string {
+	out := make([]string, 0, len(turns))
+	for _, turn := range turns {
+		out = append(out, turn.TurnID)
+	}
+	return out
+}
+
+func inputHash(req Request) string {
+	parts := []string{req.ProviderID, req.PriorSummary}
+	for _, turn := range req.Turns {
+		parts = append(parts, turn.TurnID, turn.UserText, turn.AssistantText)
+	}
+	return hashParts(parts...)
+}
+
+func hashParts(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,163 @@
+package summarization
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+func testConfig(settings controlplane.Summarization) Config {
+	return Config{
+		SessionID:       "sess-summary",
+		PipelineVersion: "pipeline-v1",
+		Settings:        settings,
+		RetentionPolicy: replay.DefaultRetentionPolicy("tenant-a"),
+	}
+}
+
+func testTurn(i int) Turn {
+	return Turn{TurnID: fmt.Sprintf("turn-%d", i), UserText: fmt.Sprintf("user %d", i), AssistantText: fmt.Sprintf("assistant %d", i)}
+}
+
+func TestNodeSummarizesEveryIntervalAndBindsLatestSummary(t *testing.T) {
+	t.Parallel()
+
+	requests := make([]Request, 0)
+	summarizer := SummarizerFunc(func(req Request) (string, error) {
+		requests = append(requests, req)
+		return fmt.Sprintf("summary of %d turns", len(req.Turns)), nil
+	})
+	recorder := timeline.NewRecorder(timeline.StageAConfig{})
+	node, err := NewNode(testConfig(controlplane.Summarization{IntervalTurns: 2, ProviderID: "llm-anthropic", MaxSummaryTokens: 128}), summarizer, &recorder)
+	if err != nil {
+		t.Fatalf("unexpected node error: %v", err)
+	}
+
+	if summary, err := node.CompleteTurn(testTurn(1), "evt-1", 100); err != nil || summary != nil {
+		t.Fatalf("expected no summary before interval, got %+v err=%v", summary, err)
+	}
+	if ctx := node.Bind(150); ctx.Summary != nil || len(ctx.RecentTurns) != 1 || ctx.InvocationSummary() != nil {
+		t.Fatalf("expected only recent turns bound before first summary, got %+v", ctx)
+	}
+
+	first, err := node.CompleteTurn(testTurn(2), "evt-2", 200)
+	if err != nil || first == nil {
+		t.Fatalf("expected first summary, got %+v err=%v", first, err)
+	}
+	if first.SummaryID != "sess-summary-summary-1" || !reflect.DeepEqual(first.SourceTurnIDs, []string{"turn-1", "turn-2"}) || first.PriorSummaryID != "" {
+		t.Fatalf("unexpected first summary provenance: %+v", first)
+	}
+
+	node.CompleteTurn(testTurn(3), "evt-3", 300)
+	ctx := node.Bind(350)
+	if ctx.Summary == nil || ctx.Summary.SummaryID != first.SummaryID || len(ctx.RecentTurns) != 1 || ctx.RecentTurns[0].TurnID != "turn-3" {
+		t.Fatalf("expected first summary plus turn-3 bound, got %+v", ctx)
+	}
+	if invocation := ctx.InvocationSummary(); invocation == nil || invocation.Text != "summary of 2 turns" {
+		t.Fatalf("expected invocation summary from bound context, got %+v", invocation)
+	}
+
+	second, err := node.CompleteTurn(testTurn(4), "evt-4", 400)
+	if err != nil || second == nil || second.PriorSummaryID != first.SummaryID {
+		t.Fatalf("expected second summary folding in the first, got %+v err=%v", second, err)
+	}
+	if requests[1].PriorSummary != first.Text || len(requests[1].Turns) != 2 || requests[1].MaxSummaryTokens != 128 || requests[1].ProviderID != "llm-anthropic" {
+		t.Fatalf("unexpected second summarize request: %+v", requests[1])
+	}
+
+	entries := recorder.SessionSummaryEntries()
+	if len(entries) != 2 || entries[1].PayloadClass != eventabi.PayloadDerivedSummary || entries[1].PriorSummaryID != first.SummaryID || entries[1].SummaryHash != second.SummaryHash {
+		t.Fatalf("expected summary provenance evidence, got %+v", entries)
+	}
+}
+
+func TestNodeSummaryRetentionWindow(t *testing.T) {
+	t.Parallel()
+
+	policy := replay.DefaultRetentionPolicy("tenant-a")
+	tenantWindow := policy.RetentionWindowFor(eventabi.PayloadDerivedSummary)
+	tests := []struct {
+		name        string
+		retentionMS int64
+		wantWindow  int64
+	}{
+		{name: "tenant window by default", wantWindow: tenantWindow},
+		{name: "shorter plan retention", retentionMS: 60_000, wantWindow: 60_000},
+		{name: "plan retention capped by tenant", retentionMS: tenantWindow * 2, wantWindow: tenantWindow},
+	}
+	for _, tc := range tests {
+		node, err := NewNode(testConfig(controlplane.Summarization{IntervalTurns: 1, ProviderID: "llm-anthropic", MaxSummaryTokens: 64, RetentionMS: tc.retentionMS}), SummarizerFunc(func(Request) (string, error) {
+			return "summary", nil
+		}), nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected node error: %v", tc.name, err)
+		}
+		summary, err := node.CompleteTurn(testTurn(1), "evt-1", 1_000)
+		if err != nil || summary.ExpiresAtMS != 1_000+tc.wantWindow {
+			t.Fatalf("%s: expected window %d, got %+v err=%v", tc.name, tc.wantWindow, summary, err)
+		}
+		if ctx := node.Bind(summary.ExpiresAtMS - 1); ctx.Summary == nil {
+			t.Fatalf("%s: expected summary bound inside retention window", tc.name)
+		}
+		if ctx := node.Bind(summary.ExpiresAtMS); ctx.Summary != nil {
+			t.Fatalf("%s: expected expired summary to be unbound, got %+v", tc.name, ctx.Summary)
+		}
+	}
+}
+
+func TestNodeKeepsPendingTurnsWhenSummarizationFails(t *testing.T) {
+	t.Parallel()
+
+	fail := true
+	node, err := NewNode(testConfig(controlplane.Summarization{IntervalTurns: 1, ProviderID: "llm-anthropic", MaxSummaryTokens: 64}), SummarizerFunc(func(req Request) (string, error) {
+		if fail {
+			return "", errors.New("provider overloaded")
+		}
+		return strings.Join(turnIDs(req.Turns), ","), nil
+	}), nil)
+	if err != nil {
+		t.Fatalf("unexpected node error: %v", err)
+	}
+	if _, err := node.CompleteTurn(testTurn(1), "evt-1", 100); err == nil || !strings.Contains(err.Error(), "provider overloaded") {
+		t.Fatalf("expected summarization failure, got %v", err)
+	}
+	fail = false
+	summary, err := node.CompleteTurn(testTurn(2), "evt-2", 200)
+	if err != nil || summary.Text != "turn-1,turn-2" {
+		t.Fatalf("expected retry to cover both pending turns, got %+v err=%v", summary, err)
+	}
+}
+
+func TestNewNodeValidatesConfig(t *testing.T) {
+	t.Parallel()
+
+	valid := controlplane.Summarization{IntervalTurns: 3, ProviderID: "llm-anthropic", MaxSummaryTokens: 64}
+	tests := []struct {
+		name       string
+		cfg        Config
+		summarizer Summarizer
+		shouldErr  bool
+	}{
+		{name: "valid", cfg: testConfig(valid), summarizer: SummarizerFunc(func(Request) (string, error) { return "", nil })},
+		{name: "missing summarizer", cfg: testConfig(valid), shouldErr: true},
+		{name: "missing session", cfg: Config{PipelineVersion: "pipeline-v1", Settings: valid, RetentionPolicy: replay.DefaultRetentionPolicy("tenant-a")}, summarizer: SummarizerFunc(func(Request) (string, error) { return "", nil }), shouldErr: true},
+		{name: "zero interval", cfg: testConfig(controlplane.Summarization{ProviderID: "llm-anthropic", MaxSummaryTokens: 64}), summarizer: SummarizerFunc(func(Request) (string, error) { return "", nil }), shouldErr: true},
+		{name: "invalid retention policy", cfg: Config{SessionID: "sess", PipelineVersion: "pipeline-v1", Settings: valid}, summarizer: SummarizerFunc(func(Request) (string, error) { return "", nil }), shouldErr: true},
+	}
+	for _, tc := range tests {
+		_, err := NewNode(tc.cfg, tc.summarizer, nil)
+		if tc.shouldErr && err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+	}
+}
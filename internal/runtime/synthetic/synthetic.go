@@ -0,0 +1,376 @@
+package synthetic
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/wscodec"
+)
+
+// Mode selects how the canary session reaches the runtime.
+type Mode string
+
+const (
+	// ModeLoopback drives canary sessions through a WebSocket transport served in-process.
+	ModeLoopback Mode = "loopback"
+	// ModeLive drives canary sessions through a deployed runtime's WebSocket transport.
+	ModeLive Mode = "live"
+)
+
+// TrendSeries names canary outcomes in the SLO trend store.
+const TrendSeries = "synthetic_canary"
+
+// DefaultProbeTimeout bounds one canary session when SessionProbe.Timeout is unset.
+const DefaultProbeTimeout = 10 * time.Second
+
+// canaryUtteranceMS is the length of the voiced capture a canary session sends.
+const canaryUtteranceMS = 100
+
+// ProbeResult is one canary session outcome.
+type ProbeResult struct {
+	RunIndex int `json:"run_index"`
+	// SessionID is the id the runtime assigned the canary session; empty when none opened.
+	SessionID string `json:"session_id,omitempty"`
+	StartedMS int64  `json:"started_ms"`
+	Success   bool   `json:"success"`
+	LatencyMS int64  `json:"latency_ms"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// ProbeOutcome is what one canary session reported.
+type ProbeOutcome struct {
+	SessionID string
+	Success   bool
+	Reason    string
+}
+
+// Probe runs one canary session.
+type Probe interface {
+	Probe(ctx context.Context) ProbeOutcome
+}
+
+// SessionProbe drives one canary session through a runtime's WebSocket transport the way a
+// client does: it waits for the session announcement, captures a short voiced utterance, waits
+// for the committed turn and its reply audio, and acknowledges playback.
+type SessionProbe struct {
+	// Endpoint is the transport's ws:// or wss:// URL.
+	Endpoint string
+	// Client dials Endpoint; nil uses http.DefaultClient.
+	Client *http.Client
+	// Timeout bounds one canary session; zero uses DefaultProbeTimeout.
+	Timeout time.Duration
+}
+
+// canaryClientMessage and canaryServerMessage are the transport messages a canary session
+// exchanges; see transports/websocket.
+type canaryClientMessage struct {
+	Type          string `json:"type"`
+	TurnID        string `json:"turn_id,omitempty"`
+	PlayedUntilMS int64  `json:"played_until_ms,omitempty"`
+}
+
+type canaryServerMessage struct {
+	Type           string `json:"type"`
+	SessionID      string `json:"session_id,omitempty"`
+	SampleRateHz   int    `json:"sample_rate_hz,omitempty"`
+	TurnID         string `json:"turn_id,omitempty"`
+	Committed      bool   `json:"committed,omitempty"`
+	FallbackReason string `json:"fallback_reason,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// Probe runs one canary session and succeeds once the runtime answers the playback ack of a
+// committed turn.
+func (p SessionProbe) Probe(ctx context.Context) ProbeOutcome {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultProbeTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	conn, err := wscodec.Dial(ctx, p.Client, p.Endpoint, nil)
+	if err != nil {
+		return ProbeOutcome{Reason: fmt.Sprintf("canary_dial_failed: %v", err)}
+	}
+	defer conn.Close()
+	// The connection outlives ctx; closing it on timeout unblocks a pending read.
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	hello, err := readCanaryMessage(conn, "session")
+	if err != nil {
+		return ProbeOutcome{Reason: fmt.Sprintf("canary_session_failed: %v", err)}
+	}
+	outcome := ProbeOutcome{SessionID: hello.SessionID}
+	if hello.SampleRateHz < 1 {
+		outcome.Reason = "canary_sample_rate_missing"
+		return outcome
+	}
+	pcm := make([]byte, 2*hello.SampleRateHz*canaryUtteranceMS/1000)
+	for i := 0; i < len(pcm); i += 2 {
+		binary.LittleEndian.PutUint16(pcm[i:], 4000)
+	}
+	if err := writeCanaryMessage(conn, canaryClientMessage{Type: "capture_start"}); err != nil {
+		outcome.Reason = fmt.Sprintf("canary_capture_failed: %v", err)
+		return outcome
+	}
+	if err := conn.WriteMessage(wscodec.OpBinary, pcm); err != nil {
+		outcome.Reason = fmt.Sprintf("canary_capture_failed: %v", err)
+		return outcome
+	}
+	if err := writeCanaryMessage(conn, canaryClientMessage{Type: "capture_end"}); err != nil {
+		outcome.Reason = fmt.Sprintf("canary_capture_failed: %v", err)
+		return outcome
+	}
+
+	turn, err := readCanaryMessage(conn, "turn")
+	if err != nil {
+		outcome.Reason = fmt.Sprintf("canary_turn_failed: %v", err)
+		return outcome
+	}
+	if !turn.Committed {
+		outcome.Reason = "canary_turn_not_committed"
+		if turn.FallbackReason != "" {
+			outcome.Reason += ": " + turn.FallbackReason
+		}
+		return outcome
+	}
+	if op, audio, err := conn.ReadMessage(); err != nil || op != wscodec.OpBinary || len(audio) == 0 {
+		outcome.Reason = fmt.Sprintf("canary_reply_audio_missing: opcode=0x%x bytes=%d err=%v", op, len(audio), err)
+		return outcome
+	}
+	if err := writeCanaryMessage(conn, canaryClientMessage{Type: "playback_ack", TurnID: turn.TurnID, PlayedUntilMS: 20}); err != nil {
+		outcome.Reason = fmt.Sprintf("canary_playback_failed: %v", err)
+		return outcome
+	}
+	if _, err := readCanaryMessage(conn, "playback"); err != nil {
+		outcome.Reason = fmt.Sprintf("canary_playback_failed: %v", err)
+		return outcome
+	}
+	outcome.Success = true
+	return outcome
+}
+
+// readCanaryMessage reads JSON messages until one of type want arrives, failing on a runtime
+// error message or unexpected audio.
+func readCanaryMessage(conn *wscodec.Conn, want string) (canaryServerMessage, error) {
+	for {
+		op, payload, err := conn.ReadMessage()
+		if err != nil {
+			return canaryServerMessage{}, err
+		}
+		if op != wscodec.OpText {
+			return canaryServerMessage{}, fmt.Errorf("unexpected %d-byte audio frame waiting for %s", len(payload), want)
+		}
+		var msg canaryServerMessage
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			return canaryServerMessage{}, fmt.Errorf("decode %s message: %w", want, err)
+		}
+		switch msg.Type {
+		case want:
+			return msg, nil
+		case "error":
+			return canaryServerMessage{}, fmt.Errorf("runtime error: %s", msg.Error)
+		}
+	}
+}
+
+func writeCanaryMessage(conn *wscodec.Conn, msg canaryClientMessage) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return conn.WriteMessage(wscodec.OpText, payload)
+}
+
+// Config controls one synthetic monitor loop.
+type Config struct {
+	Mode     Mode
+	Target   string
+	Interval time.Duration
+	// Runs bounds the loop; zero runs until the context is cancelled.
+	Runs int
+	// Window is the rolling sample count used for availability.
+	Window int
+}
+
+// Validate enforces monitor settings.
+func (c Config) Validate() error {
+	if c.Mode != ModeLoopback && c.Mode != ModeLive {
+		return fmt.Errorf("synthetic monitor mode must be one of loopback|live")
+	}
+	if c.Mode == ModeLive && c.Target == "" {
+		return fmt.Errorf("synthetic monitor live mode requires a target websocket endpoint")
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("synthetic monitor interval must be >0")
+	}
+	if c.Runs < 0 {
+		return fmt.Errorf("synthetic monitor runs must be >=0")
+	}
+	if c.Window < 1 {
+		return fmt.Errorf("synthetic monitor window must be >=1")
+	}
+	return nil
+}
+
+// Availability is the rolling availability artifact written after each canary run.
+type Availability struct {
+	GeneratedAtUTC string        `json:"generated_at_utc"`
+	Mode           Mode          `json:"mode"`
+	Target         string        `json:"target,omitempty"`
+	IntervalMS     int64         `json:"interval_ms"`
+	Window         int           `json:"window"`
+	TotalRuns      int           `json:"total_runs"`
+	Successes      int           `json:"successes"`
+	Availability   float64       `json:"availability"`
+	LatencyP50MS   int64         `json:"latency_p50_ms"`
+	LatencyP95MS   int64         `json:"latency_p95_ms"`
+	Samples        []ProbeResult `json:"samples"`
+}
+
+// Monitor runs canary sessions on an interval and keeps a rolling window of outcomes.
+type Monitor struct {
+	cfg   Config
+	probe Probe
+	clock clock.Clock
+	// Record, when set, receives every canary result, e.g. to append it to the SLO trend store.
+	Record func(ProbeResult) error
+	// Publish receives the rolling availability after every run.
+	Publish func(Availability) error
+
+	totalRuns int
+	samples   []ProbeResult
+}
+
+// NewMonitor validates config and returns a monitor using probe; clk timestamps canary runs
+// and paces Run, and nil uses the real clock.
+func NewMonitor(cfg Config, probe Probe, clk clock.Clock) (*Monitor, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if probe == nil {
+		return nil, fmt.Errorf("synthetic monitor probe is required")
+	}
+	if clk == nil {
+		clk = clock.Real()
+	}
+	return &Monitor{cfg: cfg, probe: probe, clock: clk}, nil
+}
+
+// Run executes canary runs until Runs is reached or ctx is cancelled.
+func (m *Monitor) Run(ctx context.Context) (Availability, error) {
+	ticker := m.clock.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		availability, err := m.RunOnce(ctx)
+		if err != nil {
+			return availability, err
+		}
+		if m.cfg.Runs > 0 && m.totalRuns >= m.cfg.Runs {
+			return availability, nil
+		}
+		select {
+		case <-ctx.Done():
+			return availability, nil
+		case <-ticker.C():
+		}
+	}
+}
+
+// RunOnce executes one canary session, emits telemetry, records the result, and publishes
+// availability.
+func (m *Monitor) RunOnce(ctx context.Context) (Availability, error) {
+	m.totalRuns++
+	started := m.clock.Now()
+	outcome := m.probe.Probe(ctx)
+	result := ProbeResult{
+		RunIndex:  m.totalRuns,
+		SessionID: outcome.SessionID,
+		StartedMS: started.UnixMilli(),
+		Success:   outcome.Success,
+		LatencyMS: m.clock.Now().Sub(started).Milliseconds(),
+		Reason:    outcome.Reason,
+	}
+
+	m.samples = append(m.samples, result)
+	if len(m.samples) > m.cfg.Window {
+		m.samples = append([]ProbeResult(nil), m.samples[len(m.samples)-m.cfg.Window:]...)
+	}
+	m.emit(result)
+	if m.Record != nil {
+		if err := m.Record(result); err != nil {
+			return m.Availability(), fmt.Errorf("record synthetic canary result: %w", err)
+		}
+	}
+
+	availability := m.Availability()
+	if m.Publish != nil {
+		if err := m.Publish(availability); err != nil {
+			return availability, fmt.Errorf("publish synthetic availability: %w", err)
+		}
+	}
+	return availability, nil
+}
+
+// Availability summarizes the current rolling window.
+func (m *Monitor) Availability() Availability {
+	out := Availability{
+		GeneratedAtUTC: m.clock.Now().UTC().Format(time.RFC3339),
+		Mode:           m.cfg.Mode,
+		Target:         m.cfg.Target,
+		IntervalMS:     m.cfg.Interval.Milliseconds(),
+		Window:         m.cfg.Window,
+		TotalRuns:      m.totalRuns,
+		Samples:        append([]ProbeResult(nil), m.samples...),
+	}
+	latencies := make([]int64, 0, len(m.samples))
+	for _, sample := range m.samples {
+		if sample.Success {
+			out.Successes++
+			latencies = append(latencies, sample.LatencyMS)
+		}
+	}
+	if len(m.samples) > 0 {
+		out.Availability = float64(out.Successes) / float64(len(m.samples))
+	}
+	out.LatencyP50MS = percentile(latencies, 50)
+	out.LatencyP95MS = percentile(latencies, 95)
+	return out
+}
+
+func (m *Monitor) emit(result ProbeResult) {
+	attributes := map[string]string{"mode": string(m.cfg.Mode)}
+	if m.cfg.Target != "" {
+		attributes["target"] = m.cfg.Target
+	}
+	successValue := 0.0
+	if result.Success {
+		successValue = 1
+	}
+	// Canary metrics carry no session id: every run is a new session, and a per-run label
+	// would add a series per run.
+	correlation := telemetry.Correlation{EmittedBy: "OR-01", WallClockTimestampMS: result.StartedMS}
+	telemetry.DefaultEmitter().EmitMetric(telemetry.MetricSyntheticCanarySuccess, successValue, "1", attributes, correlation)
+	telemetry.DefaultEmitter().EmitMetric(telemetry.MetricSyntheticCanaryLatencyMS, float64(result.LatencyMS), "ms", attributes, correlation)
+}
+
+func percentile(values []int64, p int) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := (len(sorted)*p+99)/100 - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,196 @@
+package synthetic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/transport"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
+	"github.com/tiger/realtime-speech-pipeline/transports/websocket"
+)
+
+type scriptedProbe struct {
+	outcomes []bool
+	calls    int
+	// clock and latency, when set, make each probe take latency of virtual time; probed then
+	// receives each call.
+	clock   *clock.Virtual
+	latency time.Duration
+	probed  chan struct{}
+}
+
+func (p *scriptedProbe) Probe(context.Context) ProbeOutcome {
+	outcome := p.outcomes[p.calls%len(p.outcomes)]
+	p.calls++
+	if p.clock != nil {
+		p.clock.Advance(p.latency)
+		p.probed <- struct{}{}
+	}
+	if !outcome {
+		return ProbeOutcome{SessionID: "sess-scripted", Reason: "scripted_failure"}
+	}
+	return ProbeOutcome{SessionID: "sess-scripted", Success: true}
+}
+
+func TestMonitorRollingAvailability(t *testing.T) {
+	t.Parallel()
+
+	virtual := clock.NewVirtual(time.UnixMilli(0))
+	probe := &scriptedProbe{outcomes: []bool{true, false, true, true}, clock: virtual, latency: 120 * time.Millisecond, probed: make(chan struct{})}
+	published, recorded := 0, 0
+	monitor, err := NewMonitor(Config{Mode: ModeLoopback, Interval: 30 * time.Second, Runs: 4, Window: 3}, probe, virtual)
+	if err != nil {
+		t.Fatalf("unexpected monitor error: %v", err)
+	}
+	monitor.Record = func(result ProbeResult) error {
+		if result.SessionID != "sess-scripted" {
+			return fmt.Errorf("unexpected session id %q", result.SessionID)
+		}
+		recorded++
+		return nil
+	}
+	monitor.Publish = func(Availability) error {
+		published++
+		return nil
+	}
+
+	type runResult struct {
+		availability Availability
+		err          error
+	}
+	done := make(chan runResult, 1)
+	go func() {
+		availability, err := monitor.Run(context.Background())
+		done <- runResult{availability: availability, err: err}
+	}()
+	// Each canary run waits for the next interval tick before starting.
+	<-probe.probed
+	for run := 2; run <= 4; run++ {
+		virtual.Advance(30 * time.Second)
+		<-probe.probed
+	}
+	result := <-done
+	availability, err := result.availability, result.err
+	if err != nil {
+		t.Fatalf("unexpected run error: %v", err)
+	}
+	if probe.calls != 4 || published != 4 || recorded != 4 {
+		t.Fatalf("expected 4 runs, records, and publishes, got calls=%d recorded=%d published=%d", probe.calls, recorded, published)
+	}
+	if availability.TotalRuns != 4 || len(availability.Samples) != 3 || availability.Successes != 2 {
+		t.Fatalf("expected rolling window of last 3 samples, got %+v", availability)
+	}
+	if availability.Samples[0].RunIndex != 2 || availability.Samples[0].Reason != "scripted_failure" {
+		t.Fatalf("expected oldest window sample to be run 2 failure, got %+v", availability.Samples[0])
+	}
+	if last := availability.Samples[2]; last.StartedMS != 90_360 || last.LatencyMS != 120 {
+		t.Fatalf("expected deterministic virtual run timing, got %+v", last)
+	}
+}
+
+// canaryPipeline completes one turn per capture; committed selects the turn outcome.
+type canaryPipeline struct {
+	committed bool
+	samples   int
+}
+
+func (p *canaryPipeline) StartCapture() error { return nil }
+
+func (p *canaryPipeline) AppendAudio(pcm []int16) (*websocket.Turn, error) {
+	p.samples += len(pcm)
+	return nil, nil
+}
+
+func (p *canaryPipeline) EndCapture() (*websocket.Turn, error) {
+	if p.samples == 0 {
+		return nil, fmt.Errorf("turn not active: no audio captured")
+	}
+	turn := &websocket.Turn{TurnID: "turn-1", Committed: p.committed, ReplyPCM: []int16{1, 2, 3}}
+	if !p.committed {
+		turn.FallbackReason = "llm_timeout"
+	}
+	return turn, nil
+}
+
+func (p *canaryPipeline) Close() error { return nil }
+
+func TestSessionProbeDrivesWebSocketSession(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		committed   bool
+		wantSuccess bool
+		wantReason  string
+	}{
+		{name: "committed turn", committed: true, wantSuccess: true},
+		{name: "fallback turn", committed: false, wantReason: "canary_turn_not_committed: llm_timeout"},
+	}
+	for _, tc := range tests {
+		reports := make(chan transport.Report, 1)
+		handler, err := websocket.NewHandler(websocket.Config{
+			PipelineVersion: "pipeline-canary",
+			NewPipeline: func(string, string) (websocket.Pipeline, error) {
+				return &canaryPipeline{committed: tc.committed}, nil
+			},
+			OnReport: func(report transport.Report) { reports <- report },
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected handler error: %v", tc.name, err)
+		}
+		server := httptest.NewServer(handler)
+		probe := SessionProbe{Endpoint: "ws" + strings.TrimPrefix(server.URL, "http"), Client: server.Client(), Timeout: 5 * time.Second}
+		outcome := probe.Probe(context.Background())
+		if outcome.Success != tc.wantSuccess || outcome.Reason != tc.wantReason || !strings.HasPrefix(outcome.SessionID, "sess_") {
+			t.Fatalf("%s: unexpected outcome %+v", tc.name, outcome)
+		}
+		report := <-reports
+		server.Close()
+		if report.SessionID != outcome.SessionID || report.Turns != 1 || report.AudioSamples != int64(websocket.DefaultSampleRateHz*canaryUtteranceMS/1000) {
+			t.Fatalf("%s: expected the runtime to carry the canary session, got %+v", tc.name, report)
+		}
+		if tc.wantSuccess && len(report.FirstAudio) != 1 {
+			t.Fatalf("%s: expected the canary playback ack to be measured, got %+v", tc.name, report.FirstAudio)
+		}
+	}
+}
+
+func TestSessionProbeReportsUnreachableTransport(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	outcome := SessionProbe{Endpoint: "ws" + strings.TrimPrefix(server.URL, "http"), Client: server.Client()}.Probe(context.Background())
+	if outcome.Success || outcome.SessionID != "" || !strings.HasPrefix(outcome.Reason, "canary_dial_failed: ") {
+		t.Fatalf("expected dial failure, got %+v", outcome)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		cfg       Config
+		shouldErr bool
+	}{
+		{name: "loopback", cfg: Config{Mode: ModeLoopback, Interval: time.Second, Window: 10}},
+		{name: "live without target", cfg: Config{Mode: ModeLive, Interval: time.Second, Window: 10}, shouldErr: true},
+		{name: "zero interval", cfg: Config{Mode: ModeLoopback, Window: 10}, shouldErr: true},
+		{name: "unknown mode", cfg: Config{Mode: "grpc", Interval: time.Second, Window: 10}, shouldErr: true},
+	}
+	for _, tc := range tests {
+		err := tc.cfg.Validate()
+		if tc.shouldErr && err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+	}
+}
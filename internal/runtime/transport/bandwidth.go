@@ -0,0 +1,174 @@
+package transport
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+)
+
+// DefaultMetadataAlertBytes flags metadata payloads larger than typical control/status messages.
+const DefaultMetadataAlertBytes int64 = 16 << 10
+
+// Direction is the side of the transport boundary a payload crossed.
+type Direction string
+
+const (
+	DirectionIngress Direction = "ingress"
+	DirectionEgress  Direction = "egress"
+)
+
+// BandwidthConfig scopes one transport session's byte accounting.
+type BandwidthConfig struct {
+	SessionID       string
+	PipelineVersion string
+	// MetadataAlertBytes is the size above which one metadata payload counts as oversized;
+	// zero uses DefaultMetadataAlertBytes.
+	MetadataAlertBytes int64
+}
+
+// BandwidthEntry is the byte total for one direction, lane, and payload class.
+type BandwidthEntry struct {
+	Direction    Direction             `json:"direction"`
+	Lane         eventabi.Lane         `json:"lane"`
+	PayloadClass eventabi.PayloadClass `json:"payload_class"`
+	Payloads     int64                 `json:"payloads"`
+	Bytes        int64                 `json:"bytes"`
+}
+
+// BandwidthReport summarizes bytes that crossed the transport boundary for one session.
+type BandwidthReport struct {
+	SessionID    string           `json:"session_id"`
+	IngressBytes int64            `json:"ingress_bytes"`
+	EgressBytes  int64            `json:"egress_bytes"`
+	Entries      []BandwidthEntry `json:"entries"`
+	// OversizedMetadata counts metadata payloads above MetadataAlertBytes.
+	OversizedMetadata    int64 `json:"oversized_metadata"`
+	LargestMetadataBytes int64 `json:"largest_metadata_bytes"`
+	MetadataAlertBytes   int64 `json:"metadata_alert_bytes"`
+}
+
+type bandwidthKey struct {
+	direction Direction
+	lane      eventabi.Lane
+	class     eventabi.PayloadClass
+}
+
+// BandwidthMeter accounts ingress and egress bytes per payload class and lane. Every recorded
+// payload is also emitted as a transport_bytes metric so telemetry can aggregate across sessions.
+type BandwidthMeter struct {
+	cfg BandwidthConfig
+
+	mu              sync.Mutex
+	totals          map[bandwidthKey]*BandwidthEntry
+	oversized       int64
+	largestMetadata int64
+}
+
+// NewBandwidthMeter returns an empty meter for one session.
+func NewBandwidthMeter(cfg BandwidthConfig) *BandwidthMeter {
+	if cfg.MetadataAlertBytes <= 0 {
+		cfg.MetadataAlertBytes = DefaultMetadataAlertBytes
+	}
+	return &BandwidthMeter{cfg: cfg, totals: map[bandwidthKey]*BandwidthEntry{}}
+}
+
+// Record accounts one payload of size bytes crossing the boundary in direction.
+func (m *BandwidthMeter) Record(direction Direction, lane eventabi.Lane, class eventabi.PayloadClass, bytes int) error {
+	if direction != DirectionIngress && direction != DirectionEgress {
+		return fmt.Errorf("invalid bandwidth direction: %q", direction)
+	}
+	if lane != eventabi.LaneData && lane != eventabi.LaneControl && lane != eventabi.LaneTelemetry {
+		return fmt.Errorf("invalid bandwidth lane: %q", lane)
+	}
+	if !isPayloadClass(class) {
+		return fmt.Errorf("invalid bandwidth payload class: %q", class)
+	}
+	if bytes < 0 {
+		return fmt.Errorf("bandwidth bytes must be >=0")
+	}
+	size := int64(bytes)
+	oversized := class == eventabi.PayloadMetadata && size > m.cfg.MetadataAlertBytes
+
+	m.mu.Lock()
+	key := bandwidthKey{direction: direction, lane: lane, class: class}
+	entry := m.totals[key]
+	if entry == nil {
+		entry = &BandwidthEntry{Direction: direction, Lane: lane, PayloadClass: class}
+		m.totals[key] = entry
+	}
+	entry.Payloads++
+	entry.Bytes += size
+	if class == eventabi.PayloadMetadata && size > m.largestMetadata {
+		m.largestMetadata = size
+	}
+	if oversized {
+		m.oversized++
+	}
+	m.mu.Unlock()
+
+	attributes := map[string]string{
+		"direction":     string(direction),
+		"lane":          string(lane),
+		"payload_class": string(class),
+	}
+	correlation := telemetry.Correlation{
+		SessionID:       m.cfg.SessionID,
+		PipelineVersion: m.cfg.PipelineVersion,
+		Lane:            string(eventabi.LaneTelemetry),
+		EmittedBy:       "OR-01",
+	}
+	telemetry.DefaultEmitter().EmitMetric(telemetry.MetricTransportBytes, float64(size), "By", attributes, correlation)
+	if oversized {
+		telemetry.DefaultEmitter().EmitMetric(telemetry.MetricTransportOversizedMetadata, 1, "1", attributes, correlation)
+		telemetry.DefaultEmitter().EmitLog(
+			"transport_oversized_metadata",
+			"warn",
+			"metadata payload exceeds transport alert size",
+			map[string]string{
+				"direction":   string(direction),
+				"lane":        string(lane),
+				"bytes":       strconv.FormatInt(size, 10),
+				"alert_bytes": strconv.FormatInt(m.cfg.MetadataAlertBytes, 10),
+			},
+			correlation,
+		)
+	}
+	return nil
+}
+
+// Report returns the accumulated totals ordered by direction, lane, and payload class.
+func (m *BandwidthMeter) Report() BandwidthReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	report := BandwidthReport{
+		SessionID:            m.cfg.SessionID,
+		Entries:              make([]BandwidthEntry, 0, len(m.totals)),
+		OversizedMetadata:    m.oversized,
+		LargestMetadataBytes: m.largestMetadata,
+		MetadataAlertBytes:   m.cfg.MetadataAlertBytes,
+	}
+	for _, entry := range m.totals {
+		report.Entries = append(report.Entries, *entry)
+		if entry.Direction == DirectionIngress {
+			report.IngressBytes += entry.Bytes
+		} else {
+			report.EgressBytes += entry.Bytes
+		}
+	}
+	sort.Slice(report.Entries, func(i, j int) bool {
+		a, b := report.Entries[i], report.Entries[j]
+		if a.Direction != b.Direction {
+			return a.Direction < b.Direction
+		}
+		if a.Lane != b.Lane {
+			return a.Lane < b.Lane
+		}
+		return a.PayloadClass < b.PayloadClass
+	})
+	return report
+}
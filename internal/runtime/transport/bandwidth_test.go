@@ -0,0 +1,123 @@
+package transport
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+)
+
+func TestBandwidthMeterReport(t *testing.T) {
+	t.Parallel()
+
+	meter := NewBandwidthMeter(BandwidthConfig{SessionID: "sess-bw-1", MetadataAlertBytes: 100})
+	records := []struct {
+		direction Direction
+		lane      eventabi.Lane
+		class     eventabi.PayloadClass
+		bytes     int
+	}{
+		{DirectionIngress, eventabi.LaneData, eventabi.PayloadAudioRaw, 3200},
+		{DirectionIngress, eventabi.LaneData, eventabi.PayloadAudioRaw, 3200},
+		{DirectionIngress, eventabi.LaneControl, eventabi.PayloadMetadata, 24},
+		{DirectionEgress, eventabi.LaneData, eventabi.PayloadTextRaw, 180},
+		{DirectionEgress, eventabi.LaneControl, eventabi.PayloadMetadata, 400},
+	}
+	for _, r := range records {
+		if err := meter.Record(r.direction, r.lane, r.class, r.bytes); err != nil {
+			t.Fatalf("unexpected record error: %v", err)
+		}
+	}
+
+	report := meter.Report()
+	if report.IngressBytes != 6424 || report.EgressBytes != 580 {
+		t.Fatalf("expected ingress=6424 egress=580, got %+v", report)
+	}
+	if report.OversizedMetadata != 1 || report.LargestMetadataBytes != 400 || report.MetadataAlertBytes != 100 {
+		t.Fatalf("expected one oversized metadata payload, got %+v", report)
+	}
+	want := []BandwidthEntry{
+		{Direction: DirectionEgress, Lane: eventabi.LaneControl, PayloadClass: eventabi.PayloadMetadata, Payloads: 1, Bytes: 400},
+		{Direction: DirectionEgress, Lane: eventabi.LaneData, PayloadClass: eventabi.PayloadTextRaw, Payloads: 1, Bytes: 180},
+		{Direction: DirectionIngress, Lane: eventabi.LaneControl, PayloadClass: eventabi.PayloadMetadata, Payloads: 1, Bytes: 24},
+		{Direction: DirectionIngress, Lane: eventabi.LaneData, PayloadClass: eventabi.PayloadAudioRaw, Payloads: 2, Bytes: 6400},
+	}
+	if len(report.Entries) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), report.Entries)
+	}
+	for i := range want {
+		if report.Entries[i] != want[i] {
+			t.Fatalf("entry %d: expected %+v, got %+v", i, want[i], report.Entries[i])
+		}
+	}
+}
+
+func TestBandwidthMeterRejectsInvalidRecords(t *testing.T) {
+	t.Parallel()
+
+	meter := NewBandwidthMeter(BandwidthConfig{SessionID: "sess-bw-2"})
+	tests := []struct {
+		name      string
+		direction Direction
+		lane      eventabi.Lane
+		class     eventabi.PayloadClass
+		bytes     int
+	}{
+		{name: "direction", direction: "sideways", lane: eventabi.LaneData, class: eventabi.PayloadAudioRaw},
+		{name: "lane", direction: DirectionIngress, lane: "BulkLane", class: eventabi.PayloadAudioRaw},
+		{name: "payload class", direction: DirectionIngress, lane: eventabi.LaneData, class: "video"},
+		{name: "negative bytes", direction: DirectionIngress, lane: eventabi.LaneData, class: eventabi.PayloadAudioRaw, bytes: -1},
+	}
+	for _, tc := range tests {
+		if err := meter.Record(tc.direction, tc.lane, tc.class, tc.bytes); err == nil {
+			t.Fatalf("%s: expected invalid record error", tc.name)
+		}
+	}
+	if report := meter.Report(); len(report.Entries) != 0 || report.MetadataAlertBytes != DefaultMetadataAlertBytes {
+		t.Fatalf("expected empty report with default alert size, got %+v", report)
+	}
+}
+
+func TestBandwidthMeterEmitsTelemetry(t *testing.T) {
+	sink := telemetry.NewMemorySink()
+	pipeline := telemetry.NewPipeline(sink, telemetry.Config{QueueCapacity: 32})
+	previous := telemetry.DefaultEmitter()
+	telemetry.SetDefaultEmitter(pipeline)
+	t.Cleanup(func() {
+		telemetry.SetDefaultEmitter(previous)
+		_ = pipeline.Close()
+	})
+
+	meter := NewBandwidthMeter(BandwidthConfig{SessionID: "sess-bw-telemetry", MetadataAlertBytes: 10})
+	if err := meter.Record(DirectionIngress, eventabi.LaneData, eventabi.PayloadAudioRaw, 640); err != nil {
+		t.Fatalf("unexpected record error: %v", err)
+	}
+	if err := meter.Record(DirectionEgress, eventabi.LaneControl, eventabi.PayloadMetadata, 64); err != nil {
+		t.Fatalf("unexpected record error: %v", err)
+	}
+	if err := pipeline.Close(); err != nil {
+		t.Fatalf("unexpected pipeline close error: %v", err)
+	}
+
+	var bytesTotal float64
+	var oversizedMetric, oversizedLog bool
+	for _, event := range sink.Events() {
+		if event.Correlation.SessionID != "sess-bw-telemetry" {
+			continue
+		}
+		if event.Kind == telemetry.EventKindMetric && event.Metric != nil {
+			switch event.Metric.Name {
+			case telemetry.MetricTransportBytes:
+				bytesTotal += event.Metric.Value
+			case telemetry.MetricTransportOversizedMetadata:
+				oversizedMetric = event.Metric.Attributes["direction"] == string(DirectionEgress)
+			}
+		}
+		if event.Kind == telemetry.EventKindLog && event.Log != nil && event.Log.Name == "transport_oversized_metadata" {
+			oversizedLog = true
+		}
+	}
+	if bytesTotal != 704 || !oversizedMetric || !oversizedLog {
+		t.Fatalf("expected transport telemetry, got bytes=%v oversized_metric=%v oversized_log=%v", bytesTotal, oversizedMetric, oversizedLog)
+	}
+}
@@ -4,14 +4,20 @@ import (
 	"fmt"
 
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/sequence"
 )
 
 // IngressClassificationConfig controls deterministic RK-22 ingress tagging defaults.
 type IngressClassificationConfig struct {
 	DefaultDataClass eventabi.PayloadClass
+	// Sequences, when set, stamps each ingress record with the session's next runtime sequence.
+	// Records that arrive with a runtime_sequence already set are rejected, so a transport cannot
+	// place its events anywhere in the session order.
+	Sequences *sequence.Allocator
 }
 
-// TagIngressEventRecord applies required RK-22 payload classification tags before RK-05 validation.
+// TagIngressEventRecord applies required RK-22 payload classification tags before RK-05 validation,
+// and the runtime sequence when cfg.Sequences is set.
 func TagIngressEventRecord(record eventabi.EventRecord, cfg IngressClassificationConfig) (eventabi.EventRecord, error) {
 	if record.Lane == eventabi.LaneControl {
 		return eventabi.EventRecord{}, fmt.Errorf("ingress event records cannot use control lane")
@@ -21,7 +27,7 @@ func TagIngressEventRecord(record eventabi.EventRecord, cfg IngressClassificatio
 		if !isPayloadClass(record.PayloadClass) {
 			return eventabi.EventRecord{}, fmt.Errorf("invalid ingress payload class: %s", record.PayloadClass)
 		}
-		return record, nil
+		return stampIngressSequence(record, cfg)
 	}
 
 	switch record.Lane {
@@ -39,7 +45,14 @@ func TagIngressEventRecord(record eventabi.EventRecord, cfg IngressClassificatio
 		return eventabi.EventRecord{}, fmt.Errorf("invalid ingress lane: %s", record.Lane)
 	}
 
-	return record, nil
+	return stampIngressSequence(record, cfg)
+}
+
+func stampIngressSequence(record eventabi.EventRecord, cfg IngressClassificationConfig) (eventabi.EventRecord, error) {
+	if cfg.Sequences == nil {
+		return record, nil
+	}
+	return cfg.Sequences.StampEventRecord(record)
 }
 
 func isPayloadClass(class eventabi.PayloadClass) bool {
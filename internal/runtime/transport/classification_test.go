@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/sequence"
 )
 
 func TestTagIngressEventRecordDefaults(t *testing.T) {
@@ -68,3 +69,24 @@ func TestTagIngressEventRecordRejectsControlLane(t *testing.T) {
 		t.Fatalf("expected control-lane ingress classification error")
 	}
 }
+
+func TestTagIngressEventRecordStampsRuntimeSequence(t *testing.T) {
+	t.Parallel()
+
+	cfg := IngressClassificationConfig{Sequences: sequence.NewAllocator()}
+	for want := int64(1); want <= 2; want++ {
+		record, err := TagIngressEventRecord(eventabi.EventRecord{SessionID: "sess-1", EventID: "evt-1", Lane: eventabi.LaneData}, cfg)
+		if err != nil {
+			t.Fatalf("unexpected stamp error: %v", err)
+		}
+		if record.RuntimeSequence != want {
+			t.Fatalf("expected runtime_sequence %d, got %d", want, record.RuntimeSequence)
+		}
+	}
+	if _, err := TagIngressEventRecord(eventabi.EventRecord{SessionID: "sess-1", EventID: "evt-3", Lane: eventabi.LaneData, RuntimeSequence: 99}, cfg); err == nil {
+		t.Fatalf("expected transport-supplied runtime_sequence to be rejected")
+	}
+	if last, _ := cfg.Sequences.Last("sess-1"); last != 2 {
+		t.Fatalf("expected rejected record not to consume a sequence, got last=%d", last)
+	}
+}
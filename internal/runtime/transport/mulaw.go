@@ -0,0 +1,55 @@
+package transport
+
+const (
+	muLawBias = 0x84
+	muLawClip = 32635
+)
+
+// EncodeMuLaw encodes PCM16 samples as G.711 µ-law (PCMU), the 8 kHz telephony
+// codec WebRTC and SIP/PSTN transports carry.
+func EncodeMuLaw(pcm []int16) []byte {
+	out := make([]byte, len(pcm))
+	for i, sample := range pcm {
+		out[i] = encodeMuLawSample(sample)
+	}
+	return out
+}
+
+// DecodeMuLaw decodes G.711 µ-law (PCMU) bytes to PCM16 samples.
+func DecodeMuLaw(payload []byte) []int16 {
+	out := make([]int16, len(payload))
+	for i, b := range payload {
+		out[i] = decodeMuLawSample(b)
+	}
+	return out
+}
+
+func encodeMuLawSample(sample int16) byte {
+	value := int32(sample)
+	sign := byte(0)
+	if value < 0 {
+		value = -value
+		sign = 0x80
+	}
+	if value > muLawClip {
+		value = muLawClip
+	}
+	value += muLawBias
+	exponent := byte(7)
+	for mask := int32(0x4000); exponent > 0 && value&mask == 0; mask >>= 1 {
+		exponent--
+	}
+	mantissa := byte(value>>(exponent+3)) & 0x0F
+	return ^(sign | exponent<<4 | mantissa)
+}
+
+func decodeMuLawSample(b byte) int16 {
+	b = ^b
+	exponent := (b >> 4) & 0x07
+	value := (int32(b&0x0F)<<3 + muLawBias) << exponent
+	value -= muLawBias
+	if b&0x80 != 0 {
+		return int16(-value)
+	}
+	return int16(value)
+}
@@ -0,0 +1,33 @@
+package transport
+
+import "testing"
+
+func TestMuLawRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		sample int16
+	}{
+		{name: "silence", sample: 0},
+		{name: "quiet positive", sample: 100},
+		{name: "quiet negative", sample: -100},
+		{name: "loud positive", sample: 20000},
+		{name: "loud negative", sample: -20000},
+		{name: "clipped", sample: 32767},
+	}
+	for _, tc := range tests {
+		decoded := DecodeMuLaw(EncodeMuLaw([]int16{tc.sample}))[0]
+		magnitude := int32(tc.sample)
+		if magnitude < 0 {
+			magnitude = -magnitude
+		}
+		tolerance := magnitude/16 + 8
+		if diff := int32(decoded) - int32(tc.sample); diff > tolerance || diff < -tolerance {
+			t.Fatalf("%s: expected %d to survive within %d, got %d", tc.name, tc.sample, tolerance, decoded)
+		}
+	}
+	if EncodeMuLaw([]int16{0})[0] != 0xFF {
+		t.Fatalf("expected silence to encode as 0xFF")
+	}
+}
@@ -0,0 +1,137 @@
+package transport
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+)
+
+// PlaybackAck is a client report that it has played a turn's reply audio up to PlayedUntilMS,
+// measured in milliseconds of reply audio from its start.
+type PlaybackAck struct {
+	TurnID        string `json:"turn_id"`
+	PlayedUntilMS int64  `json:"played_until_ms"`
+}
+
+// Validate enforces required ack fields.
+func (a PlaybackAck) Validate() error {
+	if a.TurnID == "" {
+		return fmt.Errorf("playback ack turn_id is required")
+	}
+	if a.PlayedUntilMS < 0 {
+		return fmt.Errorf("playback ack played_until_ms must be >=0")
+	}
+	return nil
+}
+
+// PlaybackConfig scopes one transport session's playback tracking.
+type PlaybackConfig struct {
+	SessionID       string
+	PipelineVersion string
+}
+
+// FirstAudioPlayback is the user-perceived first-audio estimate for one turn.
+type FirstAudioPlayback struct {
+	TurnID string
+	// EgressAtMS is when the reply audio left the runtime; PlayedAtMS is when the client began
+	// playing it. Both are on the runtime clock.
+	EgressAtMS int64
+	PlayedAtMS int64
+}
+
+type playbackTurn struct {
+	egressAtMS    int64
+	playedUntilMS int64
+	played        bool
+}
+
+// PlaybackTracker turns client playback acknowledgments into user-perceived first-audio times.
+// Client clocks are never trusted: an ack received at runtime time R reporting P milliseconds
+// played means playback began near R-P, and never before the audio left the runtime.
+type PlaybackTracker struct {
+	cfg PlaybackConfig
+
+	mu    sync.Mutex
+	turns map[string]*playbackTurn
+}
+
+// NewPlaybackTracker returns an empty tracker for one session.
+func NewPlaybackTracker(cfg PlaybackConfig) *PlaybackTracker {
+	return &PlaybackTracker{cfg: cfg, turns: map[string]*playbackTurn{}}
+}
+
+// RecordEgress marks when turnID's reply audio was written to the client. Later egress for the
+// same turn keeps the first timestamp.
+func (t *PlaybackTracker) RecordEgress(turnID string, atMS int64) error {
+	if turnID == "" {
+		return fmt.Errorf("egress turn_id is required")
+	}
+	if atMS < 0 {
+		return fmt.Errorf("egress timestamp must be >=0")
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.turns[turnID]; !ok {
+		t.turns[turnID] = &playbackTurn{egressAtMS: atMS}
+	}
+	return nil
+}
+
+// Acknowledge applies ack received at receivedAtMS on the runtime clock. It returns the
+// turn's first-audio playback the first time an ack reports audio played; acks that report
+// nothing played yet, or arrive after the first, return false.
+func (t *PlaybackTracker) Acknowledge(ack PlaybackAck, receivedAtMS int64) (FirstAudioPlayback, bool, error) {
+	if err := ack.Validate(); err != nil {
+		return FirstAudioPlayback{}, false, err
+	}
+	t.mu.Lock()
+	turn, ok := t.turns[ack.TurnID]
+	if !ok {
+		t.mu.Unlock()
+		return FirstAudioPlayback{}, false, fmt.Errorf("playback ack for turn %s without reply egress", ack.TurnID)
+	}
+	if receivedAtMS < turn.egressAtMS {
+		t.mu.Unlock()
+		return FirstAudioPlayback{}, false, fmt.Errorf("playback ack for turn %s received before reply egress", ack.TurnID)
+	}
+	turn.playedUntilMS = max(turn.playedUntilMS, ack.PlayedUntilMS)
+	if turn.played || ack.PlayedUntilMS == 0 {
+		t.mu.Unlock()
+		return FirstAudioPlayback{}, false, nil
+	}
+	turn.played = true
+	playback := FirstAudioPlayback{
+		TurnID:     ack.TurnID,
+		EgressAtMS: turn.egressAtMS,
+		PlayedAtMS: max(turn.egressAtMS, receivedAtMS-ack.PlayedUntilMS),
+	}
+	t.mu.Unlock()
+
+	telemetry.DefaultEmitter().EmitMetric(
+		telemetry.MetricPlaybackStartDelayMS,
+		float64(playback.PlayedAtMS-playback.EgressAtMS),
+		"ms",
+		nil,
+		telemetry.Correlation{
+			SessionID:          t.cfg.SessionID,
+			TurnID:             ack.TurnID,
+			PipelineVersion:    t.cfg.PipelineVersion,
+			Lane:               string(eventabi.LaneTelemetry),
+			EmittedBy:          "OR-01",
+			RuntimeTimestampMS: receivedAtMS,
+		},
+	)
+	return playback, true, nil
+}
+
+// PlayedUntilMS returns the furthest reply position acknowledged for turnID.
+func (t *PlaybackTracker) PlayedUntilMS(turnID string) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if turn, ok := t.turns[turnID]; ok {
+		return turn.playedUntilMS
+	}
+	return 0
+}
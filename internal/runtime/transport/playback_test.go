@@ -0,0 +1,68 @@
+package transport
+
+import "testing"
+
+func TestPlaybackTrackerEstimatesFirstAudioFromAcks(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewPlaybackTracker(PlaybackConfig{SessionID: "sess-playback"})
+	if err := tracker.RecordEgress("turn-1", 1000); err != nil {
+		t.Fatalf("unexpected egress error: %v", err)
+	}
+	if err := tracker.RecordEgress("turn-1", 1400); err != nil {
+		t.Fatalf("unexpected repeated egress error: %v", err)
+	}
+
+	if _, ok, err := tracker.Acknowledge(PlaybackAck{TurnID: "turn-1"}, 1050); err != nil || ok {
+		t.Fatalf("expected an ack with nothing played to yield no estimate, got ok=%v err=%v", ok, err)
+	}
+	playback, ok, err := tracker.Acknowledge(PlaybackAck{TurnID: "turn-1", PlayedUntilMS: 120}, 1300)
+	if err != nil || !ok {
+		t.Fatalf("expected first-audio estimate, got ok=%v err=%v", ok, err)
+	}
+	if playback.EgressAtMS != 1000 || playback.PlayedAtMS != 1180 {
+		t.Fatalf("expected playback at receipt minus played audio, got %+v", playback)
+	}
+	if _, ok, err := tracker.Acknowledge(PlaybackAck{TurnID: "turn-1", PlayedUntilMS: 900}, 2100); err != nil || ok {
+		t.Fatalf("expected later acks to keep the first estimate, got ok=%v err=%v", ok, err)
+	}
+	if got := tracker.PlayedUntilMS("turn-1"); got != 900 {
+		t.Fatalf("expected furthest played position 900, got %d", got)
+	}
+
+	// A client reporting more audio played than has elapsed since egress is clamped to egress.
+	if err := tracker.RecordEgress("turn-2", 3000); err != nil {
+		t.Fatalf("unexpected egress error: %v", err)
+	}
+	playback, ok, err = tracker.Acknowledge(PlaybackAck{TurnID: "turn-2", PlayedUntilMS: 500}, 3100)
+	if err != nil || !ok || playback.PlayedAtMS != 3000 {
+		t.Fatalf("expected playback clamped to egress, got %+v ok=%v err=%v", playback, ok, err)
+	}
+}
+
+func TestPlaybackTrackerRejectsInvalidAcks(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewPlaybackTracker(PlaybackConfig{SessionID: "sess-playback-invalid"})
+	if err := tracker.RecordEgress("turn-1", 500); err != nil {
+		t.Fatalf("unexpected egress error: %v", err)
+	}
+	tests := []struct {
+		name       string
+		ack        PlaybackAck
+		receivedMS int64
+	}{
+		{name: "missing turn id", ack: PlaybackAck{PlayedUntilMS: 10}, receivedMS: 600},
+		{name: "negative played until", ack: PlaybackAck{TurnID: "turn-1", PlayedUntilMS: -1}, receivedMS: 600},
+		{name: "unknown turn", ack: PlaybackAck{TurnID: "turn-9", PlayedUntilMS: 10}, receivedMS: 600},
+		{name: "before egress", ack: PlaybackAck{TurnID: "turn-1", PlayedUntilMS: 10}, receivedMS: 400},
+	}
+	for _, tc := range tests {
+		if _, _, err := tracker.Acknowledge(tc.ack, tc.receivedMS); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+	if err := tracker.RecordEgress("", 1); err == nil {
+		t.Fatalf("expected egress without turn id to fail")
+	}
+}
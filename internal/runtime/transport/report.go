@@ -0,0 +1,55 @@
+package transport
+
+import "github.com/tiger/realtime-speech-pipeline/api/eventabi"
+
+// Report is what one client connection carried across the RK-22/RK-23 transport boundary, in
+// the same shape for every transport adapter: ingress counts, the connection and capture
+// control signals in emission order, reply playback, and per-lane bandwidth. Fields that only
+// one transport fills are omitted by the others.
+type Report struct {
+	SessionID       string `json:"session_id"`
+	PipelineVersion string `json:"pipeline_version"`
+	// StreamSID and CallSID identify a Twilio Media Stream and its call.
+	StreamSID string `json:"stream_sid,omitempty"`
+	CallSID   string `json:"call_sid,omitempty"`
+	// Traceparent is the W3C traceparent header of the connection handshake, which the
+	// session's turns join; empty when the client sent none.
+	Traceparent string `json:"traceparent,omitempty"`
+	// AudioFrames counts ingress audio units: WebSocket binary frames, RTP packets, or Twilio
+	// media messages.
+	AudioFrames int `json:"audio_frames"`
+	// LostPackets counts RTP sequence gaps; LatePackets counts duplicate or reordered RTP
+	// packets that arrived after later audio and were dropped.
+	LostPackets         int64                    `json:"lost_packets,omitempty"`
+	LatePackets         int                      `json:"late_packets,omitempty"`
+	AudioSamples        int64                    `json:"audio_samples"`
+	ControlMessages     int                      `json:"control_messages"`
+	Turns               int                      `json:"turns"`
+	Errors              int                      `json:"errors"`
+	LastRuntimeSequence int64                    `json:"last_runtime_sequence"`
+	Signals             []eventabi.ControlSignal `json:"signals"`
+	// TurnLifecycle lists each turn as the caller experienced it, for transports that track
+	// barge-in against reply playback.
+	TurnLifecycle []TurnLifecycle `json:"turn_lifecycle,omitempty"`
+	FirstAudio    []FirstAudio    `json:"first_audio,omitempty"`
+	Bandwidth     BandwidthReport `json:"bandwidth"`
+}
+
+// FirstAudio is the user-perceived first-audio latency of one turn, from capture end to the
+// acknowledged start of reply playback, both on the runtime clock.
+type FirstAudio struct {
+	TurnID                       string `json:"turn_id"`
+	PerceivedFirstAudioLatencyMS int64  `json:"perceived_first_audio_latency_ms"`
+	PlaybackStartDelayMS         int64  `json:"playback_start_delay_ms"`
+}
+
+// TurnLifecycle is one turn as the caller experienced it: the capture that opened it, its
+// terminal outcome, and whether barge-in cancelled its reply. Times are on the runtime clock.
+type TurnLifecycle struct {
+	TurnID     string `json:"turn_id"`
+	OpenedAtMS int64  `json:"opened_at_ms"`
+	// TerminalAtMS is when the reply was sent, or when barge-in cancelled its playback.
+	TerminalAtMS int64  `json:"terminal_at_ms"`
+	Outcome      string `json:"outcome"`
+	Reason       string `json:"reason,omitempty"`
+}
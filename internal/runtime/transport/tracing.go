@@ -0,0 +1,26 @@
+package transport
+
+import (
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+)
+
+const ingressSpanKey = "transport_ingress"
+
+// IngressTraceInput carries the inbound trace header and turn identity at transport ingress.
+type IngressTraceInput struct {
+	// Traceparent is the inbound W3C traceparent header value, empty when the client sent none.
+	Traceparent string
+	SessionID   string
+	TurnID      string
+}
+
+// IngressTraceContext opens the transport ingress span. A valid inbound traceparent joins the
+// caller's trace; a missing or malformed one starts a trace derived from the session and turn.
+// Scheduler and provider spans are opened as children of the returned context.
+func IngressTraceContext(in IngressTraceInput) telemetry.TraceContext {
+	parent, err := telemetry.ParseTraceparent(in.Traceparent)
+	if err != nil {
+		parent = telemetry.NewRootTraceContext(in.SessionID + "|" + in.TurnID)
+	}
+	return parent.ChildSpan(ingressSpanKey)
+}
@@ -0,0 +1,29 @@
+package transport
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+)
+
+func TestIngressTraceContext(t *testing.T) {
+	t.Parallel()
+
+	root := telemetry.NewRootTraceContext("sess-1|turn-1")
+	tests := []struct {
+		name        string
+		traceparent string
+		wantTraceID string
+		wantParent  string
+	}{
+		{name: "joins inbound trace", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736", wantParent: "00f067aa0ba902b7"},
+		{name: "starts root without header", wantTraceID: root.TraceID},
+		{name: "starts root on malformed header", traceparent: "not-a-traceparent", wantTraceID: root.TraceID},
+	}
+	for _, tc := range tests {
+		got := IngressTraceContext(IngressTraceInput{Traceparent: tc.traceparent, SessionID: "sess-1", TurnID: "turn-1"})
+		if got.TraceID != tc.wantTraceID || got.ParentSpanID != tc.wantParent || got.Traceparent() == "" {
+			t.Fatalf("%s: expected trace=%s parent=%q, got %+v", tc.name, tc.wantTraceID, tc.wantParent, got)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package turnarbiter
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/cancellation"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/guard"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/localadmission"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/planresolver"
@@ -36,6 +38,15 @@ type OpenRequest struct {
 	SnapshotFailurePolicy controlplane.OutcomeKind
 	PlanFailurePolicy     controlplane.OutcomeKind
 	PlanShouldFail        bool
+	// Locale is the session-level locale selection frozen into the resolved plan.
+	Locale *controlplane.SessionLocale
+	// QuotaCounters and Thresholds are RK-25 explain evidence for admission decisions.
+	QuotaCounters []controlplane.QuotaCounter
+	Thresholds    []controlplane.ThresholdEvaluation
+	// Trace is the transport ingress trace context; the turn span is opened under it.
+	Trace telemetry.TraceContext
+	// Queue is the admission queue snapshot; on capacity defer it yields a queue_eta signal.
+	Queue *localadmission.QueueState
 }
 
 // OpenResult includes deterministic outputs and transitions.
@@ -46,30 +57,52 @@ type OpenResult struct {
 	Plan        *controlplane.ResolvedTurnPlan
 	Events      []LifecycleEvent
 	ControlLane []eventabi.ControlSignal
+	// Budgets are the turn-start tenant budgets for the turn's scheduler, set once Active.
+	Budgets controlplane.BudgetPolicy
 }
 
 // ActiveInput drives Active turn handling with precedence rules.
 type ActiveInput struct {
-	SessionID                    string
-	TurnID                       string
-	EventID                      string
-	PipelineVersion              string
-	SnapshotProvenance           controlplane.SnapshotProvenance
-	TransportSequence            int64
-	RuntimeSequence              int64
-	RuntimeTimestampMS           int64
-	WallClockTimestampMS         int64
-	AuthorityEpoch               int64
-	AuthorityRevoked             bool
-	CancelAccepted               bool
+	SessionID            string
+	TurnID               string
+	EventID              string
+	PipelineVersion      string
+	SnapshotProvenance   controlplane.SnapshotProvenance
+	TransportSequence    int64
+	RuntimeSequence      int64
+	RuntimeTimestampMS   int64
+	WallClockTimestampMS int64
+	AuthorityEpoch       int64
+	AuthorityRevoked     bool
+	CancelAccepted       bool
+	// Cancel carries the cancel timestamps a barge-in recorded; when unset, cancel evidence is
+	// derived from RuntimeTimestampMS.
+	Cancel                       *CancelTiming
 	ProviderFailure              bool
 	ProviderInvocationOutcomes   []timeline.InvocationOutcomeEvidence
 	NodeTimeoutOrFailure         bool
 	TransportDisconnectOrStall   bool
 	BaselineEvidenceAppendFailed bool
 	BaselineEvidence             *timeline.BaselineEvidence
-	NoLegalContinueOrFallback    bool
-	TerminalSuccessReady         bool
+	// Locale is the resolved plan locale; it is recorded in baseline evidence so replay can
+	// attribute provider-choice divergences to locale differences.
+	Locale *controlplane.ResolvedLocale
+	// SessionSummaryID and SessionSummaryHash identify the derived_summary bound into the turn's
+	// LLM context; they are recorded in baseline evidence for replay.
+	SessionSummaryID   string
+	SessionSummaryHash string
+	// Trigger is the session trigger that opened the turn; it is recorded in turn-open evidence.
+	Trigger                   *controlplane.TurnTrigger
+	NoLegalContinueOrFallback bool
+	TerminalSuccessReady      bool
+	// FallbackUtterance is set when a degraded turn spoke a fallback utterance instead of a
+	// generated response; the turn then commits with terminal outcome "fallback".
+	FallbackUtterance *timeline.FallbackUtteranceEvidence
+	// Trace is the transport ingress trace context; its trace id is recorded in baseline evidence.
+	Trace telemetry.TraceContext
+	// SLA is the tenant SLA tier resolved from the CP distribution snapshot; it is recorded in
+	// baseline evidence so per-tenant SLA attainment can be reported from baseline artifacts.
+	SLA *controlplane.TurnSLA
 }
 
 // ActiveResult returns ordered terminal outputs when a terminal path is selected.
@@ -83,23 +116,26 @@ type ActiveResult struct {
 
 // ApplyInput provides a unified dispatch interface for deterministic arbiter operations.
 type ApplyInput struct {
-	Open   *OpenRequest
-	Active *ActiveInput
+	Open    *OpenRequest
+	Active  *ActiveInput
+	BargeIn *BargeInInput
 }
 
 // ApplyResult is the unified result for TurnArbiter.Apply.
 type ApplyResult struct {
-	Open   *OpenResult
-	Active *ActiveResult
+	Open    *OpenResult
+	Active  *ActiveResult
+	BargeIn *BargeInResult
 }
 
 // Arbiter composes RK-24/RK-25 guard checks and RK-04 plan resolution.
 type Arbiter struct {
 	admission         localadmission.Evaluator
 	guard             guard.Evaluator
-	resolver          planresolver.Resolver
+	resolver          planResolver
 	baselineRecorder  *timeline.Recorder
 	turnStartResolver TurnStartBundleResolver
+	cancelFence       cancelFence
 }
 
 func New() Arbiter {
@@ -115,27 +151,75 @@ func NewWithRecorder(recorder *timeline.Recorder) Arbiter {
 	return NewWithDependencies(recorder, nil)
 }
 
+// planResolver materializes turn plans; the plan cache and the uncached resolver both satisfy it.
+type planResolver interface {
+	Resolve(in planresolver.Input) (controlplane.ResolvedTurnPlan, error)
+}
+
+// NewWithPlanCache wires dependencies like NewWithDependencies and resolves plans through
+// cache, so turns sharing a pipeline version and policy snapshots reuse the materialized plan.
+func NewWithPlanCache(recorder *timeline.Recorder, turnStartResolver TurnStartBundleResolver, cache *planresolver.Cache) Arbiter {
+	arbiter := NewWithDependencies(recorder, turnStartResolver)
+	if cache != nil {
+		arbiter.resolver = cache
+	}
+	return arbiter
+}
+
+// NewWithCancelFence wires dependencies like NewWithDependencies and accepts barge-in and
+// active-turn cancels into fence, so a transport output fence sharing it drops the cancelled
+// turn's late output and a scheduler sharing it aborts the turn's in-flight provider calls.
+func NewWithCancelFence(recorder *timeline.Recorder, turnStartResolver TurnStartBundleResolver, fence *cancellation.Fence) Arbiter {
+	arbiter := NewWithDependencies(recorder, turnStartResolver)
+	if fence != nil {
+		arbiter.cancelFence = fence
+	}
+	return arbiter
+}
+
 // NewWithDependencies wires deterministic runtime dependencies for testing and seams.
 func NewWithDependencies(recorder *timeline.Recorder, turnStartResolver TurnStartBundleResolver) Arbiter {
 	if turnStartResolver == nil {
 		turnStartResolver = newControlPlaneBundleResolver()
 	}
 	return Arbiter{
-		admission:         localadmission.Evaluator{},
+		admission:         localadmission.NewEvaluatorFromEnv(),
 		guard:             guard.Evaluator{},
 		resolver:          planresolver.Resolver{},
 		baselineRecorder:  recorder,
 		turnStartResolver: turnStartResolver,
+		cancelFence:       cancellation.NewFence(),
 	}
 }
 
-// Apply dispatches either pre-turn or active-turn handling.
+// Apply dispatches pre-turn, active-turn, or barge-in handling.
 func (a Arbiter) Apply(in ApplyInput) (ApplyResult, error) {
-	if in.Open != nil && in.Active != nil {
-		return ApplyResult{}, fmt.Errorf("apply input must set only one of Open or Active")
+	set := 0
+	for _, present := range []bool{in.Open != nil, in.Active != nil, in.BargeIn != nil} {
+		if present {
+			set++
+		}
 	}
-	if in.Open == nil && in.Active == nil {
-		return ApplyResult{}, fmt.Errorf("apply input must set Open or Active")
+	if set > 1 {
+		return ApplyResult{}, fmt.Errorf("apply input must set only one of Open, Active, or BargeIn")
+	}
+	if set == 0 {
+		return ApplyResult{}, fmt.Errorf("apply input must set Open, Active, or BargeIn")
+	}
+
+	if in.BargeIn != nil {
+		out, err := a.HandleBargeIn(*in.BargeIn)
+		interrupted := in.BargeIn.Interrupted
+		if err == nil {
+			interrupted.CancelAccepted = true
+			interrupted.Cancel = &out.Cancel
+		}
+		emitOpenTelemetry(in.BargeIn.Next, out.Next, err)
+		emitActiveTelemetry(interrupted, out.Interrupted, err)
+		if err != nil {
+			return ApplyResult{}, err
+		}
+		return ApplyResult{BargeIn: &out}, nil
 	}
 
 	if in.Open != nil {
@@ -166,7 +250,7 @@ func emitOpenTelemetry(in OpenRequest, out OpenResult, err error) {
 		EmittedBy:            "OR-01",
 		RuntimeTimestampMS:   nonNegative(in.RuntimeTimestampMS),
 		WallClockTimestampMS: nonNegative(in.WallClockTimestampMS),
-	}
+	}.WithTrace(turnTraceContext(in.Trace, in.TurnID))
 	attrs := map[string]string{
 		"phase":       "pre_turn",
 		"state":       string(out.State),
@@ -211,7 +295,7 @@ func emitActiveTelemetry(in ActiveInput, out ActiveResult, err error) {
 		EmittedBy:            "OR-01",
 		RuntimeTimestampMS:   nonNegative(in.RuntimeTimestampMS),
 		WallClockTimestampMS: nonNegative(in.WallClockTimestampMS),
-	}
+	}.WithTrace(turnTraceContext(in.Trace, in.TurnID))
 	attrs := map[string]string{
 		"phase":       "active_turn",
 		"state":       string(out.State),
@@ -223,9 +307,13 @@ func emitActiveTelemetry(in ActiveInput, out ActiveResult, err error) {
 		attrs["terminal_event"] = terminalEvent
 	}
 	if in.CancelAccepted {
+		cancelLatencyMS := int64(0)
+		if in.Cancel != nil {
+			cancelLatencyMS = nonNegative(in.Cancel.AcceptedAtMS - in.Cancel.SentAtMS)
+		}
 		telemetry.DefaultEmitter().EmitMetric(
 			telemetry.MetricCancelLatencyMS,
-			0,
+			float64(cancelLatencyMS),
 			"ms",
 			map[string]string{
 				"scope": "turn",
@@ -254,6 +342,11 @@ func emitActiveTelemetry(in ActiveInput, out ActiveResult, err error) {
 	)
 }
 
+// turnTraceContext opens the turn span; pre-turn and active handling share one span per turn.
+func turnTraceContext(trace telemetry.TraceContext, turnID string) telemetry.TraceContext {
+	return trace.ChildSpan("turn|" + turnID)
+}
+
 // HandleTurnOpenProposed executes deterministic pre-turn gating and plan freeze.
 func (a Arbiter) HandleTurnOpenProposed(in OpenRequest) (OpenResult, error) {
 	result := OpenResult{State: controlplane.TurnOpening}
@@ -274,6 +367,9 @@ func (a Arbiter) HandleTurnOpenProposed(in OpenRequest) (OpenResult, error) {
 		SnapshotValid:         in.SnapshotValid,
 		CapacityDisposition:   in.CapacityDisposition,
 		SnapshotFailurePolicy: in.SnapshotFailurePolicy,
+		QuotaCounters:         in.QuotaCounters,
+		Thresholds:            in.Thresholds,
+		Queue:                 in.Queue,
 	})
 
 	if !admission.Allowed {
@@ -298,6 +394,13 @@ func (a Arbiter) HandleTurnOpenProposed(in OpenRequest) (OpenResult, error) {
 		result.Decision = admission.Outcome
 		result.State = controlplane.TurnIdle
 		result.Events = append(result.Events, LifecycleEvent{Name: string(admission.Outcome.OutcomeKind), Reason: admission.Outcome.Reason})
+		if admission.QueueEstimate != nil {
+			signal, err := a.queueETA(in, *admission.Outcome, *admission.QueueEstimate)
+			if err != nil {
+				return OpenResult{}, err
+			}
+			result.ControlLane = append(result.ControlLane, signal)
+		}
 		return result, validateOpenTransitions(result.Transitions)
 	}
 
@@ -408,6 +511,7 @@ func (a Arbiter) HandleTurnOpenProposed(in OpenRequest) (OpenResult, error) {
 			RuntimeTimestampMS: in.RuntimeTimestampMS,
 			WallClockMS:        in.WallClockTimestampMS,
 			EmittedBy:          controlplane.EmitterCP05,
+			FailureDomain:      eventabi.FailureDomainAdmission,
 			Reason:             turnStartBundle.CPAdmissionReason,
 		}
 		if err := outcome.Validate(); err != nil {
@@ -429,7 +533,7 @@ func (a Arbiter) HandleTurnOpenProposed(in OpenRequest) (OpenResult, error) {
 		return result, validateOpenTransitions(result.Transitions)
 	}
 
-	plan, err := a.resolver.Resolve(planresolver.Input{
+	plan, err := a.resolvePlan(planresolver.Input{
 		TurnID:                 in.TurnID,
 		PipelineVersion:        turnStartBundle.PipelineVersion,
 		GraphDefinitionRef:     turnStartBundle.GraphDefinitionRef,
@@ -437,8 +541,15 @@ func (a Arbiter) HandleTurnOpenProposed(in OpenRequest) (OpenResult, error) {
 		AuthorityEpoch:         resolvedAuthorityEpoch,
 		SnapshotProvenance:     turnStartBundle.SnapshotProvenance,
 		AllowedAdaptiveActions: append([]string(nil), turnStartBundle.AllowedAdaptiveActions...),
+		STTEndpointing:         turnStartBundle.STTEndpointing,
+		AudioEnhancement:       turnStartBundle.AudioEnhancement,
+		Summarization:          turnStartBundle.Summarization,
+		Locale:                 in.Locale,
 		FailMaterialization:    in.PlanShouldFail,
 	})
+	if errors.Is(err, planresolver.ErrLocaleInconsistent) {
+		return a.planMaterializationFailure(result, in, "session_locale_inconsistent")
+	}
 	if err != nil {
 		return a.planMaterializationFailure(result, in, "plan_materialization_failed")
 	}
@@ -453,8 +564,10 @@ func (a Arbiter) HandleTurnOpenProposed(in OpenRequest) (OpenResult, error) {
 		Deterministic: true,
 	})
 	result.Plan = &plan
+	result.Budgets = turnStartBundle.Budgets
 	result.State = controlplane.TurnActive
 	result.Events = append(result.Events, LifecycleEvent{Name: string(controlplane.TriggerTurnOpen)})
+	a.resolveQueueEstimates(in)
 
 	return result, validateOpenTransitions(result.Transitions)
 }
@@ -467,6 +580,13 @@ func (a Arbiter) resolveTurnStartBundle(in TurnStartBundleInput) (TurnStartBundl
 	return resolver.ResolveTurnStartBundle(in)
 }
 
+func (a Arbiter) resolvePlan(in planresolver.Input) (controlplane.ResolvedTurnPlan, error) {
+	if a.resolver == nil {
+		return planresolver.Resolver{}.Resolve(in)
+	}
+	return a.resolver.Resolve(in)
+}
+
 func (a Arbiter) planMaterializationFailure(result OpenResult, in OpenRequest, reason string) (OpenResult, error) {
 	kind := in.PlanFailurePolicy
 	if kind != controlplane.OutcomeReject {
@@ -482,6 +602,7 @@ func (a Arbiter) planMaterializationFailure(result OpenResult, in OpenRequest, r
 		RuntimeTimestampMS: in.RuntimeTimestampMS,
 		WallClockMS:        in.WallClockTimestampMS,
 		EmittedBy:          controlplane.EmitterRK25,
+		FailureDomain:      eventabi.FailureDomainAdmission,
 		Reason:             reason,
 	}
 	if err := outcome.Validate(); err != nil {
@@ -545,6 +666,12 @@ func (a Arbiter) HandleActive(in ActiveInput) (ActiveResult, error) {
 	}
 
 	if in.CancelAccepted {
+		// Fencing the turn closes its cancel signal, aborting provider work still in flight.
+		if a.cancelFence != nil && in.SessionID != "" && in.TurnID != "" {
+			if err := a.cancelFence.Accept(in.SessionID, in.TurnID); err != nil {
+				return ActiveResult{}, err
+			}
+		}
 		result.Events = append(result.Events,
 			LifecycleEvent{Name: "abort", Reason: "cancelled"},
 			LifecycleEvent{Name: "close"},
@@ -552,6 +679,16 @@ func (a Arbiter) HandleActive(in ActiveInput) (ActiveResult, error) {
 		return a.finalizeTerminal(in, result, "abort", "cancelled", controlplane.TriggerAbort)
 	}
 
+	if in.FallbackUtterance != nil && !in.TransportDisconnectOrStall {
+		reason := in.FallbackUtterance.Reason
+		result.Events = append(result.Events,
+			LifecycleEvent{Name: "fallback_utterance", Reason: reason},
+			LifecycleEvent{Name: "commit"},
+			LifecycleEvent{Name: "close"},
+		)
+		return a.finalizeTerminal(in, result, "fallback", reason, controlplane.TriggerCommit)
+	}
+
 	if in.ProviderFailure {
 		result.Events = append(result.Events,
 			LifecycleEvent{Name: "abort", Reason: "provider_failure"},
@@ -643,7 +780,7 @@ func (a Arbiter) finalizeTerminal(in ActiveInput, result ActiveResult, terminalO
 	result.State = controlplane.TurnClosed
 	appendTerminalTransitions(&result, trigger)
 
-	if err := a.appendBaselineEvidence(in, terminalOutcome, terminalReason); err != nil {
+	if err := a.appendBaselineEvidence(in, result.ControlLane, terminalOutcome, terminalReason); err != nil {
 		result.Decision = nil
 		result.Events = []LifecycleEvent{
 			{Name: "abort", Reason: "recording_evidence_unavailable"},
@@ -656,7 +793,7 @@ func (a Arbiter) finalizeTerminal(in ActiveInput, result ActiveResult, terminalO
 	return result, validateActiveResult(result)
 }
 
-func (a Arbiter) appendBaselineEvidence(in ActiveInput, terminalOutcome string, terminalReason string) error {
+func (a Arbiter) appendBaselineEvidence(in ActiveInput, signals []eventabi.ControlSignal, terminalOutcome string, terminalReason string) error {
 	if in.BaselineEvidenceAppendFailed {
 		return timeline.ErrBaselineCapacityExhausted
 	}
@@ -695,6 +832,9 @@ func (a Arbiter) appendBaselineEvidence(in ActiveInput, terminalOutcome string,
 	if err != nil {
 		return err
 	}
+	if len(evidence.ControlSignals) == 0 && len(signals) > 0 {
+		evidence.ControlSignals = append([]eventabi.ControlSignal(nil), signals...)
+	}
 	return a.baselineRecorder.AppendBaseline(evidence)
 }
 
@@ -722,6 +862,7 @@ func buildBaselineEvidence(in ActiveInput, terminalOutcome string, terminalReaso
 		RuntimeTimestampMS: nonNegative(in.RuntimeTimestampMS),
 		WallClockMS:        nonNegative(in.WallClockTimestampMS),
 		EmittedBy:          controlplane.EmitterRK25,
+		FailureDomain:      eventabi.FailureDomainAdmission,
 		Reason:             "admission_capacity_allow",
 	}
 	evidence := timeline.BaselineEvidence{
@@ -795,6 +936,19 @@ func normalizeBaselineEvidence(evidence *timeline.BaselineEvidence, in ActiveInp
 	}
 	evidence.RedactionDecisions = decisions
 	evidence.PlanHash = fallback(evidence.PlanHash, "plan/"+fallback(in.TurnID, "unknown"))
+	evidence.TraceID = fallback(evidence.TraceID, in.Trace.TraceID)
+	if evidence.Locale == "" && in.Locale != nil {
+		evidence.Locale = in.Locale.Locale
+		evidence.TTSVoice = in.Locale.TTSVoice
+	}
+	if evidence.SessionSummaryID == "" {
+		evidence.SessionSummaryID = in.SessionSummaryID
+		evidence.SessionSummaryHash = in.SessionSummaryHash
+	}
+	if evidence.SLA == nil && in.SLA != nil {
+		sla := *in.SLA
+		evidence.SLA = &sla
+	}
 
 	evidence.SnapshotProvenance.RoutingViewSnapshot = fallback(evidence.SnapshotProvenance.RoutingViewSnapshot, snapshotDefaults.RoutingViewSnapshot)
 	evidence.SnapshotProvenance.AdmissionPolicySnapshot = fallback(evidence.SnapshotProvenance.AdmissionPolicySnapshot, snapshotDefaults.AdmissionPolicySnapshot)
@@ -814,6 +968,7 @@ func normalizeBaselineEvidence(evidence *timeline.BaselineEvidence, in ActiveInp
 			RuntimeTimestampMS: nonNegative(in.RuntimeTimestampMS),
 			WallClockMS:        nonNegative(in.WallClockTimestampMS),
 			EmittedBy:          controlplane.EmitterRK25,
+			FailureDomain:      eventabi.FailureDomainAdmission,
 			Reason:             "admission_capacity_allow",
 		}
 		evidence.DecisionOutcomes = []controlplane.DecisionOutcome{decision}
@@ -843,7 +998,23 @@ func normalizeBaselineEvidence(evidence *timeline.BaselineEvidence, in ActiveInp
 	if evidence.TurnOpenAtMS == nil {
 		evidence.TurnOpenAtMS = &runtimeTs
 	}
+	if evidence.Trigger == nil && in.Trigger != nil {
+		trigger := *in.Trigger
+		if in.Trigger.CaptureEndAtMS != nil {
+			captureEnd := *in.Trigger.CaptureEndAtMS
+			trigger.CaptureEndAtMS = &captureEnd
+		}
+		evidence.Trigger = &trigger
+	}
 
+	if in.Cancel != nil && evidence.CancelAcceptedAtMS == nil {
+		cancelSent := nonNegative(in.Cancel.SentAtMS)
+		cancelAccepted := nonNegative(in.Cancel.AcceptedAtMS)
+		cancelFence := nonNegative(in.Cancel.FenceAppliedAtMS)
+		evidence.CancelSentAtMS = &cancelSent
+		evidence.CancelAcceptedAtMS = &cancelAccepted
+		evidence.CancelFenceAppliedAtMS = &cancelFence
+	}
 	if in.CancelAccepted && evidence.CancelAcceptedAtMS == nil {
 		cancelAccepted := runtimeTs
 		evidence.CancelAcceptedAtMS = &cancelAccepted
@@ -863,6 +1034,11 @@ func normalizeBaselineEvidence(evidence *timeline.BaselineEvidence, in ActiveInp
 
 	evidence.TerminalOutcome = terminalOutcome
 	evidence.TerminalReason = terminalReason
+	evidence.FallbackUtterance = nil
+	if terminalOutcome == "fallback" && in.FallbackUtterance != nil {
+		utterance := *in.FallbackUtterance
+		evidence.FallbackUtterance = &utterance
+	}
 	evidence.CloseEmitted = true
 	return nil
 }
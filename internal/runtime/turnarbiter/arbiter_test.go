@@ -6,8 +6,12 @@ import (
 	"testing"
 
 	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/cancellation"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/localadmission"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/planresolver"
 )
 
 func TestHandleTurnOpenProposedSuccess(t *testing.T) {
@@ -45,6 +49,41 @@ func TestHandleTurnOpenProposedSuccess(t *testing.T) {
 	}
 }
 
+func TestHandleTurnOpenProposedReusesCachedPlanAcrossTurns(t *testing.T) {
+	t.Parallel()
+
+	cache := planresolver.NewCache(0)
+	arbiter := NewWithPlanCache(nil, nil, cache)
+	var plans []*controlplane.ResolvedTurnPlan
+	for _, turnID := range []string{"turn-cache-1", "turn-cache-2"} {
+		result, err := arbiter.HandleTurnOpenProposed(OpenRequest{
+			SessionID:            "sess-cache-1",
+			TurnID:               turnID,
+			EventID:              "evt-" + turnID,
+			RuntimeTimestampMS:   1,
+			WallClockTimestampMS: 1,
+			PipelineVersion:      "pipeline-v1",
+			AuthorityEpoch:       2,
+			SnapshotValid:        true,
+			AuthorityEpochValid:  true,
+			AuthorityAuthorized:  true,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected open error: %v", turnID, err)
+		}
+		if result.State != controlplane.TurnActive || result.Plan == nil || result.Plan.TurnID != turnID {
+			t.Fatalf("%s: expected active turn with its own plan identity, got %+v", turnID, result)
+		}
+		plans = append(plans, result.Plan)
+	}
+	if plans[0].PlanHash == plans[1].PlanHash {
+		t.Fatalf("expected per-turn plan hashes to differ, got %s", plans[0].PlanHash)
+	}
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Fatalf("expected second turn to hit the plan cache, got %+v", stats)
+	}
+}
+
 func TestHandleTurnOpenProposedUsesResolvedTurnStartBundle(t *testing.T) {
 	t.Parallel()
 
@@ -366,11 +405,168 @@ func TestHandleTurnOpenProposedPlanMaterializationFailure(t *testing.T) {
 	if result.Decision == nil || result.Decision.OutcomeKind != controlplane.OutcomeReject {
 		t.Fatalf("expected deterministic reject outcome, got %+v", result.Decision)
 	}
+	if result.Decision.EmittedBy != controlplane.EmitterRK25 || result.Decision.FailureDomain != eventabi.FailureDomainAdmission {
+		t.Fatalf("expected RK-25 plan failure tagged admission, got %+v", result.Decision)
+	}
 	if containsLifecycleEvent(result.Events, "turn_open") {
 		t.Fatalf("plan failure must not emit turn_open")
 	}
 }
 
+func TestHandleTurnOpenProposedInconsistentLocaleDefers(t *testing.T) {
+	t.Parallel()
+
+	arbiter := New()
+	request := OpenRequest{
+		SessionID:            "sess-locale",
+		TurnID:               "turn-locale-1",
+		EventID:              "evt-locale-1",
+		RuntimeTimestampMS:   3,
+		WallClockTimestampMS: 3,
+		PipelineVersion:      "pipeline-v1",
+		AuthorityEpoch:       4,
+		SnapshotValid:        true,
+		AuthorityEpochValid:  true,
+		AuthorityAuthorized:  true,
+		Locale:               &controlplane.SessionLocale{Locale: "es-MX", TTSVoice: "en-US-Neural2-F"},
+	}
+	result, err := arbiter.HandleTurnOpenProposed(request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Decision == nil || result.Decision.OutcomeKind != controlplane.OutcomeDefer || result.Decision.Reason != "session_locale_inconsistent" {
+		t.Fatalf("expected session_locale_inconsistent defer, got %+v", result.Decision)
+	}
+
+	request.TurnID = "turn-locale-2"
+	request.Locale = &controlplane.SessionLocale{Locale: "es-MX"}
+	result, err = arbiter.HandleTurnOpenProposed(request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Plan == nil || result.Plan.Locale == nil || result.Plan.Locale.TTSVoiceLocale != "es-US" {
+		t.Fatalf("expected locale frozen into plan, got %+v", result.Plan)
+	}
+}
+
+func TestHandleActiveRecordsLocaleInBaselineEvidence(t *testing.T) {
+	t.Parallel()
+
+	recorder := timeline.NewRecorder(timeline.StageAConfig{BaselineCapacity: 4, DetailCapacity: 4})
+	arbiter := NewWithRecorder(&recorder)
+	_, err := arbiter.HandleActive(ActiveInput{
+		SessionID:            "sess-locale",
+		TurnID:               "turn-locale-3",
+		EventID:              "evt-locale-3",
+		RuntimeTimestampMS:   10,
+		WallClockTimestampMS: 10,
+		TerminalSuccessReady: true,
+		Locale: &controlplane.ResolvedLocale{
+			SessionLocale:    controlplane.SessionLocale{Locale: "pt-BR", LLMPromptLanguage: "pt", TTSVoice: "pt-BR-Chirp3-HD-Achernar", TTSVoiceLocale: "pt-BR", STTLanguageHints: []string{"pt-BR"}},
+			DefaultingSource: "locale_default",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries := recorder.BaselineEntries()
+	if len(entries) != 1 || entries[0].Locale != "pt-BR" || entries[0].TTSVoice != "pt-BR-Chirp3-HD-Achernar" {
+		t.Fatalf("expected locale recorded in baseline evidence, got %+v", entries)
+	}
+}
+
+func TestHandleActiveRecordsBoundSessionSummaryInBaselineEvidence(t *testing.T) {
+	t.Parallel()
+
+	recorder := timeline.NewRecorder(timeline.StageAConfig{BaselineCapacity: 4, DetailCapacity: 4})
+	arbiter := NewWithRecorder(&recorder)
+	_, err := arbiter.HandleActive(ActiveInput{
+		SessionID:            "sess-summary",
+		TurnID:               "turn-summary-7",
+		EventID:              "evt-summary-7",
+		RuntimeTimestampMS:   10,
+		WallClockTimestampMS: 10,
+		TerminalSuccessReady: true,
+		SessionSummaryID:     "sess-summary-summary-1",
+		SessionSummaryHash:   "summary-hash-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries := recorder.BaselineEntries()
+	if len(entries) != 1 || entries[0].SessionSummaryID != "sess-summary-summary-1" || entries[0].SessionSummaryHash != "summary-hash-1" {
+		t.Fatalf("expected bound session summary recorded in baseline evidence, got %+v", entries)
+	}
+}
+
+func TestHandleActiveRecordsTenantSLAInBaselineEvidence(t *testing.T) {
+	t.Parallel()
+
+	recorder := timeline.NewRecorder(timeline.StageAConfig{BaselineCapacity: 4, DetailCapacity: 4})
+	arbiter := NewWithRecorder(&recorder)
+	sla := &controlplane.TurnSLA{TenantID: "tenant-a", SLATier: controlplane.SLATier{Tier: "gold", MaxFirstOutputLatencyMS: 800, MinAvailability: 0.999}}
+	_, err := arbiter.HandleActive(ActiveInput{
+		SessionID:            "sess-sla",
+		TurnID:               "turn-sla-1",
+		EventID:              "evt-sla-1",
+		RuntimeTimestampMS:   10,
+		WallClockTimestampMS: 10,
+		TerminalSuccessReady: true,
+		SLA:                  sla,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries := recorder.BaselineEntries()
+	if len(entries) != 1 || entries[0].SLA == nil || *entries[0].SLA != *sla {
+		t.Fatalf("expected tenant sla recorded in baseline evidence, got %+v", entries)
+	}
+	if entries[0].SLA == sla {
+		t.Fatalf("expected baseline evidence to hold its own copy of the sla annotation")
+	}
+}
+
+func TestHandleActiveRecordsTriggerInTurnOpenEvidence(t *testing.T) {
+	t.Parallel()
+
+	recorder := timeline.NewRecorder(timeline.StageAConfig{BaselineCapacity: 4, DetailCapacity: 4})
+	arbiter := NewWithRecorder(&recorder)
+	captureEnd := int64(900)
+	trigger := &controlplane.TurnTrigger{Mode: controlplane.TriggerPushToTalk, TriggeredAtMS: 100, CaptureEndAtMS: &captureEnd, CaptureEndReason: "capture_end"}
+	_, err := arbiter.HandleActive(ActiveInput{
+		SessionID:            "sess-trigger",
+		TurnID:               "turn-trigger-1",
+		EventID:              "evt-trigger-1",
+		RuntimeTimestampMS:   1000,
+		WallClockTimestampMS: 1000,
+		TerminalSuccessReady: true,
+		Trigger:              trigger,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	captureEnd = 0
+	entries := recorder.BaselineEntries()
+	if len(entries) != 1 || entries[0].Trigger == nil || entries[0].Trigger.Mode != controlplane.TriggerPushToTalk || entries[0].Trigger.TriggeredAtMS != 100 || *entries[0].Trigger.CaptureEndAtMS != 900 {
+		t.Fatalf("expected push-to-talk trigger recorded in turn-open evidence, got %+v", entries)
+	}
+
+	invalid, err := arbiter.HandleActive(ActiveInput{
+		SessionID:            "sess-trigger",
+		TurnID:               "turn-trigger-2",
+		EventID:              "evt-trigger-2",
+		RuntimeTimestampMS:   1000,
+		TerminalSuccessReady: true,
+		Trigger:              &controlplane.TurnTrigger{Mode: controlplane.TriggerWakeWord, TriggeredAtMS: 100},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(invalid.Events) == 0 || invalid.Events[0].Reason != "recording_evidence_unavailable" || len(recorder.BaselineEntries()) != 1 {
+		t.Fatalf("expected incomplete trigger evidence to abort without recording, got %+v", invalid.Events)
+	}
+}
+
 func TestHandleActiveAuthorityRevokeWinsSamePointCancel(t *testing.T) {
 	t.Parallel()
 
@@ -432,6 +628,33 @@ func TestHandleActiveCancelPath(t *testing.T) {
 	}
 }
 
+func TestHandleActiveCancelClosesSharedFenceSignal(t *testing.T) {
+	t.Parallel()
+
+	fence := cancellation.NewFence()
+	arbiter := NewWithCancelFence(nil, nil, fence)
+	inFlight := fence.Signal("sess-1", "turn-cancel-signal")
+	if _, err := arbiter.HandleActive(ActiveInput{
+		SessionID:            "sess-1",
+		TurnID:               "turn-cancel-signal",
+		EventID:              "evt-cancel-signal",
+		RuntimeTimestampMS:   5,
+		WallClockTimestampMS: 5,
+		AuthorityEpoch:       6,
+		CancelAccepted:       true,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-inFlight:
+	default:
+		t.Fatalf("expected accepted cancel to close the turn's cancel signal")
+	}
+	if !fence.IsFenced("sess-1", "turn-cancel-signal") {
+		t.Fatalf("expected accepted cancel to fence the turn")
+	}
+}
+
 func TestHandleActiveProviderFailurePath(t *testing.T) {
 	t.Parallel()
 
@@ -489,7 +712,8 @@ func TestHandleActiveNodeTimeoutOrFailurePath(t *testing.T) {
 func TestHandleActiveTransportDisconnectOrStallPath(t *testing.T) {
 	t.Parallel()
 
-	arbiter := New()
+	recorder := timeline.NewRecorder(timeline.StageAConfig{BaselineCapacity: 4, DetailCapacity: 4})
+	arbiter := NewWithRecorder(&recorder)
 	result, err := arbiter.HandleActive(ActiveInput{
 		SessionID:                  "sess-1",
 		TurnID:                     "turn-transport-fail-1",
@@ -514,6 +738,13 @@ func TestHandleActiveTransportDisconnectOrStallPath(t *testing.T) {
 	if len(result.Events) != 4 || result.Events[2].Name != "abort" || result.Events[2].Reason != "transport_disconnect_or_stall" || result.Events[3].Name != "close" {
 		t.Fatalf("expected disconnected/stall + abort(transport_disconnect_or_stall)->close, got %+v", result.Events)
 	}
+	entries := recorder.BaselineEntries()
+	if len(entries) != 1 || len(entries[0].ControlSignals) != 2 || entries[0].ControlSignals[0].Signal != "disconnected" {
+		t.Fatalf("expected transport control signals recorded in baseline evidence, got %+v", entries)
+	}
+	if domain, ok := entries[0].ControlSignals[0].ResolvedFailureDomain(); !ok || domain != eventabi.FailureDomainTransport {
+		t.Fatalf("expected recorded signal to resolve to transport domain, got %q", domain)
+	}
 }
 
 func TestApplyDispatchOpen(t *testing.T) {
@@ -582,6 +813,38 @@ func TestHandleActiveAppendsBaselineEvidenceOnTerminalPath(t *testing.T) {
 	}
 }
 
+func TestHandleActiveRecordsIngressTraceIDInBaselineEvidence(t *testing.T) {
+	t.Parallel()
+
+	trace, err := telemetry.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatalf("parse traceparent: %v", err)
+	}
+	recorder := timeline.NewRecorder(timeline.StageAConfig{BaselineCapacity: 2, DetailCapacity: 2})
+	arbiter := NewWithRecorder(&recorder)
+
+	_, err = arbiter.HandleActive(ActiveInput{
+		SessionID:            "sess-or02-trace-1",
+		TurnID:               "turn-or02-trace-1",
+		EventID:              "evt-or02-trace-1",
+		PipelineVersion:      "pipeline-v1",
+		RuntimeSequence:      12,
+		RuntimeTimestampMS:   120,
+		WallClockTimestampMS: 120,
+		AuthorityEpoch:       1,
+		TerminalSuccessReady: true,
+		Trace:                trace.ChildSpan("transport_ingress"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := recorder.BaselineEntries()
+	if len(entries) != 1 || entries[0].TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("expected ingress trace id in OR-02 baseline, got %+v", entries)
+	}
+}
+
 func TestHandleActiveBaselineAppendFailureFallsBackDeterministically(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,158 @@
+package turnarbiter
+
+import (
+	"fmt"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/sequence"
+)
+
+// cancelFence records turns whose output must be dropped after cancel acceptance; the
+// transport output fence reads the same fence when it is shared.
+type cancelFence interface {
+	Accept(sessionID, turnID string) error
+}
+
+// CancelTiming is when a cancel was sent, accepted, and fenced on the runtime clock.
+type CancelTiming struct {
+	SentAtMS         int64
+	AcceptedAtMS     int64
+	FenceAppliedAtMS int64
+}
+
+// BargeInInput interrupts an Active turn with the start of a new user utterance while the
+// turn's assistant output is streaming.
+type BargeInInput struct {
+	// Interrupted is the Active turn being interrupted. Its terminal signals are not evaluated:
+	// the barge-in cancels it.
+	Interrupted ActiveInput
+	// Next proposes the turn that the new utterance opens, in the same session.
+	Next OpenRequest
+	// UtteranceStartAtMS is when the new utterance started; the cancel is sent at this time.
+	UtteranceStartAtMS int64
+	// OutputStreaming reports that assistant output of the interrupted turn was streaming. A
+	// new utterance without streaming output is not a barge-in.
+	OutputStreaming bool
+	// Sequences is the session's runtime sequence allocator. When set, the barge_in and cancel
+	// signals take freshly reserved sequences; otherwise they take the interrupted input's
+	// RuntimeSequence and the one after it.
+	Sequences *sequence.Allocator
+}
+
+// BargeInResult is the interrupted turn's terminal result, the cancel timing recorded in its
+// baseline evidence, and the next turn's open result.
+type BargeInResult struct {
+	Interrupted ActiveResult
+	Cancel      CancelTiming
+	Next        OpenResult
+}
+
+// HandleBargeIn cancels the interrupted turn and opens the next turn in one step. Everything
+// that can fail is checked before any transition: the interrupted turn's terminal result is
+// built and validated and the next turn's open is resolved, which has no side effects. Only
+// then are the signal sequences reserved, the cancel fence accepted, and the interrupted turn
+// closed with abort(barge_in), so a failure leaves both turns as they were. The interrupted
+// turn closes whatever the open outcome.
+func (a Arbiter) HandleBargeIn(in BargeInInput) (BargeInResult, error) {
+	interrupted := in.Interrupted
+	if interrupted.SessionID == "" || interrupted.TurnID == "" || in.Next.TurnID == "" {
+		return BargeInResult{}, fmt.Errorf("barge-in requires session_id and interrupted and next turn_id")
+	}
+	if in.Next.SessionID != interrupted.SessionID {
+		return BargeInResult{}, fmt.Errorf("barge-in next turn must be in session %s, got %s", interrupted.SessionID, in.Next.SessionID)
+	}
+	if in.Next.TurnID == interrupted.TurnID {
+		return BargeInResult{}, fmt.Errorf("barge-in next turn must differ from interrupted turn %s", interrupted.TurnID)
+	}
+	if !in.OutputStreaming {
+		return BargeInResult{}, fmt.Errorf("barge-in requires streaming assistant output on turn %s", interrupted.TurnID)
+	}
+	if in.UtteranceStartAtMS < 0 {
+		return BargeInResult{}, fmt.Errorf("barge-in utterance_start_at_ms must be >=0")
+	}
+	if a.cancelFence == nil {
+		return BargeInResult{}, fmt.Errorf("cancel fence unavailable")
+	}
+
+	acceptedAtMS := max(interrupted.RuntimeTimestampMS, in.UtteranceStartAtMS)
+	interrupted.RuntimeTimestampMS = acceptedAtMS
+	interrupted.CancelAccepted = true
+	cancel := CancelTiming{
+		SentAtMS:         in.UtteranceStartAtMS,
+		AcceptedAtMS:     acceptedAtMS,
+		FenceAppliedAtMS: acceptedAtMS,
+	}
+	interrupted.Cancel = &cancel
+
+	result := bargeInResult(interrupted, interrupted.RuntimeSequence)
+	prospective := result
+	appendTerminalTransitions(&prospective, controlplane.TriggerAbort)
+	if err := validateActiveResult(prospective); err != nil {
+		return BargeInResult{}, err
+	}
+	next, err := a.HandleTurnOpenProposed(in.Next)
+	if err != nil {
+		return BargeInResult{}, err
+	}
+
+	if in.Sequences != nil {
+		first, err := in.Sequences.Reserve(interrupted.SessionID, len(result.ControlLane))
+		if err != nil {
+			return BargeInResult{}, err
+		}
+		result = bargeInResult(interrupted, first)
+	}
+	if err := a.cancelFence.Accept(interrupted.SessionID, interrupted.TurnID); err != nil {
+		return BargeInResult{}, err
+	}
+	closed, err := a.finalizeTerminal(interrupted, result, "abort", "barge_in", controlplane.TriggerAbort)
+	if err != nil {
+		return BargeInResult{}, err
+	}
+	return BargeInResult{Interrupted: closed, Cancel: cancel, Next: next}, nil
+}
+
+// bargeInResult builds the interrupted turn's barge_in and cancel signals at consecutive
+// sequences from firstSequence, and its lifecycle events.
+func bargeInResult(interrupted ActiveInput, firstSequence int64) ActiveResult {
+	result := ActiveResult{State: controlplane.TurnActive}
+	pipelineVersion := defaultPipelineVersion(interrupted.PipelineVersion)
+	eventID := fallback(interrupted.EventID, interrupted.TurnID)
+	for i, signal := range []struct {
+		name      string
+		emittedBy string
+		reason    string
+		scope     string
+	}{
+		{name: "barge_in", emittedBy: "RK-03", reason: "user_utterance_start"},
+		{name: "cancel", emittedBy: "RK-16", reason: "barge_in", scope: "turn"},
+	} {
+		transportSequence := interrupted.TransportSequence + int64(i)
+		result.ControlLane = append(result.ControlLane, eventabi.ControlSignal{
+			SchemaVersion:      "v1.0",
+			EventScope:         eventabi.ScopeTurn,
+			Signal:             signal.name,
+			EmittedBy:          signal.emittedBy,
+			SessionID:          interrupted.SessionID,
+			TurnID:             interrupted.TurnID,
+			PipelineVersion:    pipelineVersion,
+			EventID:            eventID + "-" + signal.name,
+			Lane:               eventabi.LaneControl,
+			TransportSequence:  &transportSequence,
+			RuntimeSequence:    firstSequence + int64(i),
+			AuthorityEpoch:     interrupted.AuthorityEpoch,
+			RuntimeTimestampMS: interrupted.RuntimeTimestampMS,
+			WallClockMS:        interrupted.WallClockTimestampMS,
+			PayloadClass:       eventabi.PayloadMetadata,
+			Reason:             signal.reason,
+			Scope:              signal.scope,
+		})
+	}
+	result.Events = append(result.Events,
+		LifecycleEvent{Name: "barge_in", Reason: "user_utterance_start"},
+		LifecycleEvent{Name: "abort", Reason: "barge_in"},
+		LifecycleEvent{Name: "close"},
+	)
+	return result
+}
@@ -0,0 +1,211 @@
+package turnarbiter
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/cancellation"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/sequence"
+)
+
+func bargeInOpen(turnID string, timestampMS int64) OpenRequest {
+	return OpenRequest{
+		SessionID:             "sess-barge-1",
+		TurnID:                turnID,
+		EventID:               "evt-" + turnID + "-open",
+		RuntimeTimestampMS:    timestampMS,
+		WallClockTimestampMS:  timestampMS,
+		PipelineVersion:       "pipeline-v1",
+		AuthorityEpoch:        3,
+		SnapshotValid:         true,
+		AuthorityEpochValid:   true,
+		AuthorityAuthorized:   true,
+		SnapshotFailurePolicy: controlplane.OutcomeDefer,
+		PlanFailurePolicy:     controlplane.OutcomeReject,
+	}
+}
+
+func bargeInInterrupted(runtimeTimestampMS int64) ActiveInput {
+	return ActiveInput{
+		SessionID:            "sess-barge-1",
+		TurnID:               "turn-barge-1",
+		EventID:              "evt-barge-1",
+		PipelineVersion:      "pipeline-v1",
+		TransportSequence:    7,
+		RuntimeSequence:      7,
+		RuntimeTimestampMS:   runtimeTimestampMS,
+		WallClockTimestampMS: runtimeTimestampMS,
+		AuthorityEpoch:       3,
+		TerminalSuccessReady: true,
+	}
+}
+
+func TestHandleBargeInCancelsInterruptedTurnAndOpensNext(t *testing.T) {
+	t.Parallel()
+
+	recorder := timeline.NewRecorder(timeline.StageAConfig{BaselineCapacity: 4, DetailCapacity: 4})
+	fence := cancellation.NewFence()
+	arbiter := NewWithCancelFence(&recorder, nil, fence)
+	inFlight := fence.Signal("sess-barge-1", "turn-barge-1")
+
+	out, err := arbiter.Apply(ApplyInput{BargeIn: &BargeInInput{
+		Interrupted:        bargeInInterrupted(240),
+		Next:               bargeInOpen("turn-barge-2", 241),
+		UtteranceStartAtMS: 232,
+		OutputStreaming:    true,
+	}})
+	if err != nil {
+		t.Fatalf("unexpected barge-in error: %v", err)
+	}
+	result := out.BargeIn
+	if result == nil || result.Interrupted.State != controlplane.TurnClosed || result.Next.State != controlplane.TurnActive || result.Next.Plan == nil {
+		t.Fatalf("expected interrupted turn closed and next turn active, got %+v", out)
+	}
+	events := result.Interrupted.Events
+	if len(events) != 3 || events[0].Name != "barge_in" || events[1].Name != "abort" || events[1].Reason != "barge_in" || events[2].Name != "close" {
+		t.Fatalf("expected barge_in->abort(barge_in)->close despite terminal success, got %+v", events)
+	}
+	if len(result.Interrupted.ControlLane) != 2 || result.Interrupted.ControlLane[0].Signal != "barge_in" || result.Interrupted.ControlLane[1].Signal != "cancel" || result.Interrupted.ControlLane[1].Scope != "turn" {
+		t.Fatalf("expected barge_in and turn-scoped cancel signals, got %+v", result.Interrupted.ControlLane)
+	}
+	if lane := result.Interrupted.ControlLane; lane[0].RuntimeSequence != 7 || lane[1].RuntimeSequence != 8 || *lane[0].TransportSequence == *lane[1].TransportSequence {
+		t.Fatalf("expected distinct sequences for barge_in and cancel, got %+v", lane)
+	}
+	if !fence.IsFenced("sess-barge-1", "turn-barge-1") || fence.IsFenced("sess-barge-1", "turn-barge-2") {
+		t.Fatalf("expected only the interrupted turn fenced")
+	}
+	select {
+	case <-inFlight:
+	default:
+		t.Fatalf("expected the interrupted turn's cancel signal closed")
+	}
+	if result.Cancel != (CancelTiming{SentAtMS: 232, AcceptedAtMS: 240, FenceAppliedAtMS: 240}) {
+		t.Fatalf("unexpected cancel timing %+v", result.Cancel)
+	}
+
+	entries := recorder.BaselineEntries()
+	if len(entries) != 1 {
+		t.Fatalf("expected one baseline entry for the interrupted turn, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.TurnID != "turn-barge-1" || entry.TerminalOutcome != "abort" || entry.TerminalReason != "barge_in" {
+		t.Fatalf("expected abort(barge_in) baseline evidence, got %+v", entry)
+	}
+	if entry.CancelSentAtMS == nil || *entry.CancelSentAtMS != 232 || entry.CancelAcceptedAtMS == nil || *entry.CancelAcceptedAtMS != 240 || entry.CancelFenceAppliedAtMS == nil || *entry.CancelFenceAppliedAtMS != 240 {
+		t.Fatalf("expected barge-in cancel timestamps in baseline evidence, got sent=%v accepted=%v fence=%v", entry.CancelSentAtMS, entry.CancelAcceptedAtMS, entry.CancelFenceAppliedAtMS)
+	}
+}
+
+func TestHandleBargeInReservesSignalSequencesFromAllocator(t *testing.T) {
+	t.Parallel()
+
+	sequences := sequence.NewAllocator()
+	if _, err := sequences.Reserve("sess-barge-1", 7); err != nil {
+		t.Fatalf("unexpected reserve error: %v", err)
+	}
+	result, err := New().HandleBargeIn(BargeInInput{
+		Interrupted:        bargeInInterrupted(240),
+		Next:               bargeInOpen("turn-barge-2", 241),
+		UtteranceStartAtMS: 232,
+		OutputStreaming:    true,
+		Sequences:          sequences,
+	})
+	if err != nil {
+		t.Fatalf("unexpected barge-in error: %v", err)
+	}
+	detector := sequence.NewDetector()
+	detector.Observe("sess-barge-1", 7)
+	for _, signal := range result.Interrupted.ControlLane {
+		if anomaly, ok := detector.Observe(signal.SessionID, signal.RuntimeSequence); ok {
+			t.Fatalf("expected allocator sequences after 7, got anomaly %+v for %+v", anomaly, result.Interrupted.ControlLane)
+		}
+	}
+	if last, _ := sequences.Last("sess-barge-1"); last != 9 {
+		t.Fatalf("expected two sequences reserved, last=%d", last)
+	}
+}
+
+func TestHandleBargeInAcceptsCancelNoEarlierThanUtteranceStart(t *testing.T) {
+	t.Parallel()
+
+	arbiter := New()
+	result, err := arbiter.HandleBargeIn(BargeInInput{
+		Interrupted:        bargeInInterrupted(0),
+		Next:               bargeInOpen("turn-barge-2", 120),
+		UtteranceStartAtMS: 118,
+		OutputStreaming:    true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected barge-in error: %v", err)
+	}
+	if result.Cancel.AcceptedAtMS != 118 || result.Interrupted.ControlLane[0].RuntimeTimestampMS != 118 {
+		t.Fatalf("expected cancel accepted at utterance start, got %+v", result.Cancel)
+	}
+}
+
+func TestHandleBargeInClosesInterruptedTurnWhenNextOpenIsRejected(t *testing.T) {
+	t.Parallel()
+
+	fence := cancellation.NewFence()
+	arbiter := NewWithCancelFence(nil, nil, fence)
+	next := bargeInOpen("turn-barge-2", 241)
+	next.AuthorityAuthorized = false
+	result, err := arbiter.HandleBargeIn(BargeInInput{
+		Interrupted:        bargeInInterrupted(240),
+		Next:               next,
+		UtteranceStartAtMS: 232,
+		OutputStreaming:    true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected barge-in error: %v", err)
+	}
+	if result.Next.State != controlplane.TurnIdle || result.Next.Decision == nil || result.Next.Decision.OutcomeKind != controlplane.OutcomeDeauthorized {
+		t.Fatalf("expected deauthorized next turn to stay Idle, got %+v", result.Next)
+	}
+	if result.Interrupted.State != controlplane.TurnClosed || !fence.IsFenced("sess-barge-1", "turn-barge-1") {
+		t.Fatalf("expected interrupted turn fenced and closed, got %+v", result.Interrupted)
+	}
+}
+
+func TestHandleBargeInRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	otherSession := bargeInOpen("turn-barge-2", 241)
+	otherSession.SessionID = "sess-barge-2"
+	tests := []struct {
+		name string
+		in   BargeInInput
+	}{
+		{name: "missing next turn", in: BargeInInput{Interrupted: bargeInInterrupted(240), Next: bargeInOpen("", 241), OutputStreaming: true}},
+		{name: "other session", in: BargeInInput{Interrupted: bargeInInterrupted(240), Next: otherSession, OutputStreaming: true}},
+		{name: "same turn", in: BargeInInput{Interrupted: bargeInInterrupted(240), Next: bargeInOpen("turn-barge-1", 241), OutputStreaming: true}},
+		{name: "output not streaming", in: BargeInInput{Interrupted: bargeInInterrupted(240), Next: bargeInOpen("turn-barge-2", 241)}},
+		{name: "invalid interrupted event id", in: BargeInInput{Interrupted: func() ActiveInput {
+			in := bargeInInterrupted(240)
+			in.EventID = "evt barge"
+			return in
+		}(), Next: bargeInOpen("turn-barge-2", 241), OutputStreaming: true}},
+		{name: "negative utterance start", in: BargeInInput{Interrupted: bargeInInterrupted(240), Next: bargeInOpen("turn-barge-2", 241), UtteranceStartAtMS: -1, OutputStreaming: true}},
+	}
+	for _, tc := range tests {
+		fence := cancellation.NewFence()
+		recorder := timeline.NewRecorder(timeline.StageAConfig{BaselineCapacity: 4, DetailCapacity: 4})
+		sequences := sequence.NewAllocator()
+		tc.in.Sequences = sequences
+		arbiter := NewWithCancelFence(&recorder, nil, fence)
+		if _, err := arbiter.HandleBargeIn(tc.in); err == nil {
+			t.Fatalf("%s: expected barge-in error", tc.name)
+		}
+		if fence.IsFenced("sess-barge-1", "turn-barge-1") || len(recorder.BaselineEntries()) != 0 {
+			t.Fatalf("%s: expected a rejected barge-in to leave the interrupted turn unfenced and open", tc.name)
+		}
+		if _, ok := sequences.Last("sess-barge-1"); ok {
+			t.Fatalf("%s: expected no sequences reserved by a rejected barge-in", tc.name)
+		}
+	}
+
+	if _, err := New().Apply(ApplyInput{Active: &ActiveInput{}, BargeIn: &BargeInInput{}}); err == nil {
+		t.Fatalf("expected apply to reject more than one input")
+	}
+}
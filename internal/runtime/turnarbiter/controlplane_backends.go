@@ -15,6 +15,8 @@ import (
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/registry"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/rollout"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/routingview"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sharding"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/specstore"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
 )
 
@@ -25,6 +27,8 @@ const (
 	ControlPlaneDistributionHTTPURLsEnv = distribution.EnvHTTPAdapterURLs
 	// ControlPlaneDistributionHTTPURLEnv configures the HTTP-backed CP distribution endpoint.
 	ControlPlaneDistributionHTTPURLEnv = distribution.EnvHTTPAdapterURL
+	// ControlPlaneSpecStoreDirEnv configures the spec store used to verify published spec integrity.
+	ControlPlaneSpecStoreDirEnv = specstore.EnvStoreDir
 )
 
 // ControlPlaneBackends declares optional backend resolvers used by CP turn-start services.
@@ -37,6 +41,13 @@ type ControlPlaneBackends struct {
 	GraphCompiler  graphcompiler.Backend
 	Admission      admission.Backend
 	Lease          lease.Backend
+	Sharding       sharding.Backend
+	// Specs verifies published spec blobs at pipeline record resolution, nil to skip verification.
+	Specs registry.SpecStore
+	// ProviderWarmup feeds runtime provider warm-up results into provider health snapshots.
+	ProviderWarmup providerhealth.WarmupSource
+	// ProviderCatalog feeds the runtime's declarative provider catalog into provider health snapshots.
+	ProviderCatalog providerhealth.CatalogSource
 }
 
 // NewControlPlaneBackendsFromDistributionFile builds CP backends from a file-backed distribution artifact.
@@ -58,20 +69,31 @@ func NewControlPlaneBackendsFromDistributionHTTP(url string) (ControlPlaneBacken
 }
 
 // NewControlPlaneBackendsFromDistributionEnv builds CP backends from env-configured distribution artifacts.
+// Spec integrity verification is enabled when the spec store dir env var is set.
 func NewControlPlaneBackendsFromDistributionEnv() (ControlPlaneBackends, error) {
+	var serviceBackends distribution.ServiceBackends
+	var err error
 	if strings.TrimSpace(os.Getenv(ControlPlaneDistributionHTTPURLsEnv)) != "" || strings.TrimSpace(os.Getenv(ControlPlaneDistributionHTTPURLEnv)) != "" {
-		serviceBackends, err := distribution.NewHTTPBackendsFromEnv()
+		serviceBackends, err = distribution.NewHTTPBackendsFromEnv()
 		if err != nil {
 			return ControlPlaneBackends{}, fmt.Errorf("load control-plane distribution http backends from env: %w", err)
 		}
-		return newControlPlaneBackends(serviceBackends), nil
+	} else {
+		serviceBackends, err = distribution.NewFileBackendsFromEnv()
+		if err != nil {
+			return ControlPlaneBackends{}, fmt.Errorf("load control-plane distribution backends from env: %w", err)
+		}
 	}
 
-	serviceBackends, err := distribution.NewFileBackendsFromEnv()
-	if err != nil {
-		return ControlPlaneBackends{}, fmt.Errorf("load control-plane distribution backends from env: %w", err)
+	backends := newControlPlaneBackends(serviceBackends)
+	if strings.TrimSpace(os.Getenv(ControlPlaneSpecStoreDirEnv)) != "" {
+		store, err := specstore.NewFileStoreFromEnv()
+		if err != nil {
+			return ControlPlaneBackends{}, fmt.Errorf("load control-plane spec store from env: %w", err)
+		}
+		backends.Specs = store
 	}
-	return newControlPlaneBackends(serviceBackends), nil
+	return backends, nil
 }
 
 // NewWithControlPlaneBackendsFromDistributionFile loads CP backends from file and wires arbiter.
@@ -107,6 +129,7 @@ func NewControlPlaneBundleResolverWithBackends(backends ControlPlaneBackends) Tu
 
 	registryService := registry.NewService()
 	registryService.Backend = wrappedBackends.Registry
+	registryService.Specs = backends.Specs
 
 	rolloutService := rollout.NewService()
 	rolloutService.Backend = wrappedBackends.Rollout
@@ -119,6 +142,8 @@ func NewControlPlaneBundleResolverWithBackends(backends ControlPlaneBackends) Tu
 
 	providerHealthService := providerhealth.NewService()
 	providerHealthService.Backend = wrappedBackends.ProviderHealth
+	providerHealthService.Warmup = backends.ProviderWarmup
+	providerHealthService.Catalog = backends.ProviderCatalog
 
 	graphCompilerService := graphcompiler.NewService()
 	graphCompilerService.Backend = wrappedBackends.GraphCompiler
@@ -129,6 +154,9 @@ func NewControlPlaneBundleResolverWithBackends(backends ControlPlaneBackends) Tu
 	leaseService := lease.NewService()
 	leaseService.Backend = wrappedBackends.Lease
 
+	shardingService := sharding.NewService()
+	shardingService.Backend = wrappedBackends.Sharding
+
 	return NewControlPlaneBundleResolverWithServices(ControlPlaneBundleServices{
 		Registry:       registryService,
 		Normalizer:     normalizer.Service{},
@@ -139,6 +167,7 @@ func NewControlPlaneBundleResolverWithBackends(backends ControlPlaneBackends) Tu
 		GraphCompiler:  graphCompilerService,
 		Admission:      admissionService,
 		Lease:          leaseService,
+		Sharding:       shardingService,
 	})
 }
 
@@ -152,6 +181,7 @@ func newControlPlaneBackends(serviceBackends distribution.ServiceBackends) Contr
 		GraphCompiler:  serviceBackends.GraphCompiler,
 		Admission:      serviceBackends.Admission,
 		Lease:          serviceBackends.Lease,
+		Sharding:       serviceBackends.Sharding,
 	}
 }
 
@@ -181,6 +211,9 @@ func withFallbackBackends(backends ControlPlaneBackends) ControlPlaneBackends {
 	if out.Lease != nil {
 		out.Lease = fallbackLeaseBackend{backend: out.Lease}
 	}
+	if out.Sharding != nil {
+		out.Sharding = fallbackShardingBackend{backend: out.Sharding}
+	}
 	return out
 }
 
@@ -304,6 +337,21 @@ func (b fallbackLeaseBackend) Resolve(in lease.Input) (lease.Output, error) {
 	return out, nil
 }
 
+type fallbackShardingBackend struct {
+	backend sharding.Backend
+}
+
+func (b fallbackShardingBackend) GetShardMap(in sharding.Input) (sharding.ShardMap, error) {
+	out, err := b.backend.GetShardMap(in)
+	if err != nil {
+		if isStaleSnapshotResolutionError(err) {
+			return sharding.ShardMap{}, err
+		}
+		return sharding.ShardMap{}, nil
+	}
+	return out, nil
+}
+
 // NewWithControlPlaneBackends wires arbiter with CP backend resolver integration.
 func NewWithControlPlaneBackends(recorder *timeline.Recorder, backends ControlPlaneBackends) Arbiter {
 	return NewWithDependencies(recorder, NewControlPlaneBundleResolverWithBackends(backends))
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
@@ -19,6 +20,7 @@ import (
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/registry"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/rollout"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/routingview"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/specstore"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
 )
 
@@ -130,6 +132,35 @@ func TestNewControlPlaneBundleResolverWithBackends(t *testing.T) {
 	}
 }
 
+func TestNewControlPlaneBundleResolverWithBackendsFeedsProviderWarmup(t *testing.T) {
+	t.Parallel()
+
+	warmed := []providerhealth.WarmupStatus{{ProviderID: "llm-a", Modality: "llm", Phase: "session_start", Warmed: true}}
+	resolver := NewControlPlaneBundleResolverWithBackends(ControlPlaneBackends{ProviderWarmup: stubWarmupSource{statuses: warmed}})
+	bundleResolver, ok := resolver.(controlPlaneBundleResolver)
+	if !ok {
+		t.Fatalf("expected control plane bundle resolver, got %T", resolver)
+	}
+	out, err := bundleResolver.providerHealth.GetSnapshot(providerhealth.Input{Scope: "sess-warmup-1"})
+	if err != nil {
+		t.Fatalf("unexpected provider health error: %v", err)
+	}
+	if !reflect.DeepEqual(out.ProviderWarmup, warmed) {
+		t.Fatalf("expected provider warm-up fed into provider health, got %+v", out.ProviderWarmup)
+	}
+	if _, err := resolver.ResolveTurnStartBundle(TurnStartBundleInput{SessionID: "sess-warmup-1", TurnID: "turn-warmup-1", RequestedPipelineVersion: "pipeline-v1"}); err != nil {
+		t.Fatalf("unexpected turn-start bundle error: %v", err)
+	}
+}
+
+type stubWarmupSource struct {
+	statuses []providerhealth.WarmupStatus
+}
+
+func (s stubWarmupSource) WarmupStatuses() []providerhealth.WarmupStatus {
+	return s.statuses
+}
+
 func TestNewControlPlaneBundleResolverWithBackendsFallsBackPerService(t *testing.T) {
 	t.Parallel()
 
@@ -348,6 +379,52 @@ func TestNewControlPlaneBackendsFromDistributionEnv(t *testing.T) {
 	}
 }
 
+func TestNewControlPlaneBackendsFromDistributionEnvVerifiesPublishedSpec(t *testing.T) {
+	storeDir := t.TempDir()
+	store, err := specstore.NewFileStore(storeDir)
+	if err != nil {
+		t.Fatalf("unexpected spec store error: %v", err)
+	}
+	specHash, err := store.Put([]byte(`{"pipeline_version":"pipeline-v1","graph_definition_ref":"graph/default"}`))
+	if err != nil {
+		t.Fatalf("unexpected spec put error: %v", err)
+	}
+	artifactPath := writeDistributionFixture(t, `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "registry": {"records": {"pipeline-v1": {"graph_definition_ref": "graph/default", "execution_profile": "simple", "spec_hash": "`+specHash+`"}}},
+  "rollout": {"default_pipeline_version": "pipeline-v1"},
+  "routing_view": {"default": {"routing_view_snapshot": "routing-view/v1", "admission_policy_snapshot": "admission-policy/v1", "abi_compatibility_snapshot": "abi-compat/v1"}},
+  "policy": {"default": {"policy_resolution_snapshot": "policy-resolution/v1", "allowed_adaptive_actions": ["retry"]}},
+  "provider_health": {"default": {"provider_health_snapshot": "provider-health/v1"}}
+}`)
+	t.Setenv(ControlPlaneDistributionPathEnv, artifactPath)
+	t.Setenv(ControlPlaneSpecStoreDirEnv, storeDir)
+
+	backends, err := NewControlPlaneBackendsFromDistributionEnv()
+	if err != nil {
+		t.Fatalf("expected distribution env backends, got %v", err)
+	}
+	if backends.Specs == nil {
+		t.Fatalf("expected spec store to be wired from env")
+	}
+	resolver := NewControlPlaneBundleResolverWithBackends(backends)
+	bundle, err := resolver.ResolveTurnStartBundle(TurnStartBundleInput{SessionID: "sess-spec-1", TurnID: "turn-spec-1", RequestedPipelineVersion: "pipeline-v1"})
+	if err != nil {
+		t.Fatalf("unexpected bundle error with intact spec: %v", err)
+	}
+	if bundle.SpecHash != specHash {
+		t.Fatalf("expected bundle spec_hash %s, got %+v", specHash, bundle)
+	}
+
+	blobPath := filepath.Join(storeDir, strings.TrimPrefix(specHash, "sha256:")+".json")
+	if err := os.WriteFile(blobPath, []byte(`{"pipeline_version":"pipeline-v1","graph_definition_ref":"graph/tampered"}`), 0o644); err != nil {
+		t.Fatalf("unexpected tamper write error: %v", err)
+	}
+	if _, err := resolver.ResolveTurnStartBundle(TurnStartBundleInput{SessionID: "sess-spec-1", TurnID: "turn-spec-2", RequestedPipelineVersion: "pipeline-v1"}); err == nil {
+		t.Fatalf("expected tampered spec to fail turn-start resolution")
+	}
+}
+
 func TestNewWithControlPlaneBackendsFromDistributionHTTP(t *testing.T) {
 	t.Parallel()
 
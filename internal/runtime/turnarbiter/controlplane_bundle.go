@@ -14,6 +14,7 @@ import (
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/registry"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/rollout"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/routingview"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sharding"
 )
 
 // TurnStartBundleInput captures CP context needed to freeze turn-start runtime inputs.
@@ -32,6 +33,16 @@ type TurnStartBundle struct {
 	GraphFingerprint       string
 	AllowedAdaptiveActions []string
 	SnapshotProvenance     controlplane.SnapshotProvenance
+	// STTEndpointing is the pipeline-version endpointing override, nil for profile defaults.
+	STTEndpointing *controlplane.STTEndpointing
+	// AudioEnhancement is the pipeline-version pre-STT enhancement setting, nil when unset.
+	AudioEnhancement *controlplane.AudioEnhancement
+	// Summarization is the pipeline-version session summarization setting, nil when unset.
+	Summarization *controlplane.Summarization
+	// Budgets are the CP policy tenant budgets RK-25 enforces at the turn's scheduling points.
+	Budgets controlplane.BudgetPolicy
+	// SpecHash content-addresses the integrity-verified published spec, empty when unpublished.
+	SpecHash               string
 	HasCPAdmissionDecision bool
 	CPAdmissionOutcomeKind controlplane.OutcomeKind
 	CPAdmissionScope       controlplane.OutcomeScope
@@ -97,6 +108,7 @@ type controlPlaneBundleResolver struct {
 	graphCompiler  graphcompiler.Service
 	admission      admission.Service
 	lease          lease.Service
+	sharding       sharding.Service
 }
 
 // ControlPlaneBundleServices defines CP service dependencies for turn-start resolution.
@@ -110,6 +122,7 @@ type ControlPlaneBundleServices struct {
 	GraphCompiler  graphcompiler.Service
 	Admission      admission.Service
 	Lease          lease.Service
+	Sharding       sharding.Service
 }
 
 func newControlPlaneBundleResolver() TurnStartBundleResolver {
@@ -128,6 +141,7 @@ func NewControlPlaneBundleResolverWithServices(services ControlPlaneBundleServic
 		graphCompiler:  services.GraphCompiler,
 		admission:      services.Admission,
 		lease:          services.Lease,
+		sharding:       services.Sharding,
 	}
 }
 
@@ -200,6 +214,14 @@ func (r controlPlaneBundleResolver) ResolveTurnStartBundle(in TurnStartBundleInp
 		return TurnStartBundle{}, fmt.Errorf("resolve lease authority: %w", err)
 	}
 
+	shardResult, err := r.sharding.Resolve(sharding.Input{
+		SessionID:               in.SessionID,
+		RequestedAuthorityEpoch: in.AuthorityEpoch,
+	})
+	if err != nil {
+		return TurnStartBundle{}, fmt.Errorf("resolve session shard: %w", err)
+	}
+
 	admissionResult, err := r.admission.Evaluate(admission.Input{
 		SessionID:                in.SessionID,
 		TurnID:                   in.TurnID,
@@ -218,6 +240,14 @@ func (r controlPlaneBundleResolver) ResolveTurnStartBundle(in TurnStartBundleInp
 	if leaseResult.AuthorityAuthorized != nil {
 		leaseAuthorityGranted = *leaseResult.AuthorityAuthorized
 	}
+	// Shard map changes advance the authority epoch. Route resolution sends each session to its
+	// owner's endpoint, so a turn that still reaches another worker is fenced here.
+	leaseAuthorityEpoch := leaseResult.AuthorityEpoch
+	if shardResult.AuthorityEpoch > leaseAuthorityEpoch {
+		leaseAuthorityEpoch = shardResult.AuthorityEpoch
+	}
+	leaseAuthorityValid = leaseAuthorityValid && shardResult.EpochValid
+	leaseAuthorityGranted = leaseAuthorityGranted && shardResult.Owned
 
 	cpScope := admissionResult.Scope
 	if cpScope == "" {
@@ -230,6 +260,11 @@ func (r controlPlaneBundleResolver) ResolveTurnStartBundle(in TurnStartBundleInp
 		ExecutionProfile:       normalized.ExecutionProfile,
 		GraphFingerprint:       compiledGraph.GraphFingerprint,
 		AllowedAdaptiveActions: append([]string(nil), policyResult.AllowedAdaptiveActions...),
+		STTEndpointing:         normalized.STTEndpointing,
+		AudioEnhancement:       normalized.AudioEnhancement,
+		Summarization:          normalized.Summarization,
+		SpecHash:               normalized.SpecHash,
+		Budgets:                policyResult.Budgets,
 		SnapshotProvenance: controlplane.SnapshotProvenance{
 			RoutingViewSnapshot:       routingSnapshot.RoutingViewSnapshot,
 			AdmissionPolicySnapshot:   routingSnapshot.AdmissionPolicySnapshot,
@@ -237,13 +272,15 @@ func (r controlPlaneBundleResolver) ResolveTurnStartBundle(in TurnStartBundleInp
 			VersionResolutionSnapshot: rolloutResult.VersionResolutionSnapshot,
 			PolicyResolutionSnapshot:  policyResult.PolicyResolutionSnapshot,
 			ProviderHealthSnapshot:    providerHealthSnapshot.ProviderHealthSnapshot,
+			ShardMapSnapshot:          shardResult.ShardMapSnapshot,
+			ShardOwner:                shardResult.OwnerWorkerID,
 		},
 		HasCPAdmissionDecision: true,
 		CPAdmissionOutcomeKind: admissionResult.OutcomeKind,
 		CPAdmissionScope:       cpScope,
 		CPAdmissionReason:      admissionResult.Reason,
 		HasLeaseDecision:       true,
-		LeaseAuthorityEpoch:    leaseResult.AuthorityEpoch,
+		LeaseAuthorityEpoch:    leaseAuthorityEpoch,
 		LeaseAuthorityValid:    leaseAuthorityValid,
 		LeaseAuthorityGranted:  leaseAuthorityGranted,
 	}
@@ -6,12 +6,14 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/normalizer"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/policy"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/providerhealth"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/registry"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/rollout"
 	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/routingview"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/sharding"
 )
 
 func TestResolveTurnStartBundleDefaults(t *testing.T) {
@@ -105,6 +107,7 @@ func TestResolveTurnStartBundleUsesInjectedServices(t *testing.T) {
 			return policy.Output{
 				PolicyResolutionSnapshot: "policy-resolution/backend",
 				AllowedAdaptiveActions:   []string{"fallback", "retry"},
+				Budgets:                  controlplane.BudgetPolicy{Default: controlplane.TenantBudget{MaxTokensPerTurn: 3000}},
 			}, nil
 		},
 	}
@@ -143,6 +146,9 @@ func TestResolveTurnStartBundleUsesInjectedServices(t *testing.T) {
 	if !reflect.DeepEqual(bundle.AllowedAdaptiveActions, []string{"retry", "fallback"}) {
 		t.Fatalf("expected normalized backend policy actions, got %+v", bundle.AllowedAdaptiveActions)
 	}
+	if bundle.Budgets.For("tenant-any").MaxTokensPerTurn != 3000 {
+		t.Fatalf("expected backend policy tenant budgets, got %+v", bundle.Budgets)
+	}
 	if bundle.SnapshotProvenance.RoutingViewSnapshot != "routing-view/backend" ||
 		bundle.SnapshotProvenance.AdmissionPolicySnapshot != "admission-policy/backend" ||
 		bundle.SnapshotProvenance.ABICompatibilitySnapshot != "abi-compat/backend" ||
@@ -153,6 +159,58 @@ func TestResolveTurnStartBundleUsesInjectedServices(t *testing.T) {
 	}
 }
 
+func TestResolveTurnStartBundleGatesAuthorityOnShardOwnership(t *testing.T) {
+	t.Parallel()
+
+	shardMap := sharding.ShardMap{ShardMapSnapshot: "shard-map/v4", AuthorityEpoch: 4, Workers: []string{"runtime-a", "runtime-b"}}
+	ring, err := sharding.NewRing(shardMap)
+	if err != nil {
+		t.Fatalf("new ring: %v", err)
+	}
+	owner := ring.Owner("sess-shard-1")
+	other := "runtime-a"
+	if owner == other {
+		other = "runtime-b"
+	}
+
+	tests := []struct {
+		name        string
+		workerID    string
+		epoch       int64
+		wantValid   bool
+		wantGranted bool
+	}{
+		{name: "owner", workerID: owner, epoch: 4, wantValid: true, wantGranted: true},
+		{name: "non-owner", workerID: other, epoch: 4, wantValid: true, wantGranted: false},
+		{name: "epoch predates shard map", workerID: owner, epoch: 3, wantValid: false, wantGranted: true},
+	}
+	for _, tc := range tests {
+		resolver := NewControlPlaneBundleResolverWithServices(ControlPlaneBundleServices{
+			Registry:       registry.NewService(),
+			Normalizer:     normalizer.Service{},
+			Rollout:        rollout.NewService(),
+			RoutingView:    routingview.NewService(),
+			Policy:         policy.NewService(),
+			ProviderHealth: providerhealth.NewService(),
+			Sharding:       sharding.Service{WorkerID: tc.workerID, DefaultMap: shardMap},
+		})
+		bundle, err := resolver.ResolveTurnStartBundle(TurnStartBundleInput{
+			SessionID:      "sess-shard-1",
+			TurnID:         "turn-shard-1",
+			AuthorityEpoch: tc.epoch,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected resolve error: %v", tc.name, err)
+		}
+		if bundle.LeaseAuthorityEpoch != 4 || bundle.LeaseAuthorityValid != tc.wantValid || bundle.LeaseAuthorityGranted != tc.wantGranted {
+			t.Fatalf("%s: expected epoch=4 valid=%v granted=%v, got %+v", tc.name, tc.wantValid, tc.wantGranted, bundle)
+		}
+		if bundle.SnapshotProvenance.ShardMapSnapshot != "shard-map/v4" || bundle.SnapshotProvenance.ShardOwner != owner {
+			t.Fatalf("%s: expected shard ownership in provenance, got %+v", tc.name, bundle.SnapshotProvenance)
+		}
+	}
+}
+
 func TestResolveTurnStartBundleRejectsUnsupportedExecutionProfile(t *testing.T) {
 	t.Parallel()
 
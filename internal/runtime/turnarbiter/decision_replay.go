@@ -0,0 +1,53 @@
+package turnarbiter
+
+import (
+	"fmt"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/localadmission"
+)
+
+// DecisionRecomputer re-executes pre-turn admission, routing, and plan resolution
+// against recorded inputs for OR-03 recompute-decisions replay.
+type DecisionRecomputer struct {
+	arbiter Arbiter
+}
+
+// NewDecisionRecomputer wires a recomputer over the supplied turn-start resolver.
+// A nil resolver uses the default control-plane bundle resolver. Pre-turn recompute
+// never appends baseline evidence, so no recorder is attached.
+func NewDecisionRecomputer(turnStartResolver TurnStartBundleResolver) DecisionRecomputer {
+	return DecisionRecomputer{arbiter: NewWithDependencies(nil, turnStartResolver)}
+}
+
+// RecomputeDecision replays one recorded turn-open proposal through pre-turn decision logic.
+func (r DecisionRecomputer) RecomputeDecision(in replay.RecordedDecisionInput) (replay.RecomputedDecision, error) {
+	capacity := localadmission.CapacityDisposition(in.CapacityDisposition)
+	if capacity == "" {
+		capacity = localadmission.CapacityAllow
+	}
+	out, err := r.arbiter.HandleTurnOpenProposed(OpenRequest{
+		SessionID:            in.SessionID,
+		TurnID:               in.TurnID,
+		EventID:              in.EventID,
+		RuntimeTimestampMS:   in.RuntimeTimestampMS,
+		WallClockTimestampMS: in.WallClockTimestampMS,
+		PipelineVersion:      in.PipelineVersion,
+		Locale:               in.Locale,
+		AuthorityEpoch:       in.AuthorityEpoch,
+		SnapshotValid:        in.SnapshotValid,
+		CapacityDisposition:  capacity,
+		AuthorityEpochValid:  in.AuthorityEpochValid,
+		AuthorityAuthorized:  in.AuthorityAuthorized,
+	})
+	if err != nil {
+		return replay.RecomputedDecision{}, fmt.Errorf("recompute turn open decision: %w", err)
+	}
+
+	recomputed := replay.RecomputedDecision{Decision: out.Decision}
+	if out.Plan != nil {
+		recomputed.PlanHash = out.Plan.PlanHash
+		recomputed.SnapshotProvenance = out.Plan.SnapshotProvenance
+	}
+	return recomputed, nil
+}
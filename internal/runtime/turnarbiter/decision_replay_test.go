@@ -0,0 +1,106 @@
+package turnarbiter
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
+)
+
+func TestDecisionRecomputerMatchesRecordedTurnOpen(t *testing.T) {
+	t.Parallel()
+
+	recorded := recordDecisionInput(t, NewDecisionRecomputer(nil))
+	result, err := replay.RunDecisionReplay(obs.ReplayRunRequest{
+		BaselineRef: "baseline/sess-decision-replay",
+		Mode:        obs.ReplayModeRecomputeDecisions,
+	}, []replay.RecordedDecisionInput{recorded}, NewDecisionRecomputer(nil))
+	if err != nil {
+		t.Fatalf("unexpected decision replay error: %v", err)
+	}
+	if len(result.Divergences) != 0 {
+		t.Fatalf("expected no decision divergence for unchanged control plane, got %+v", result.Divergences)
+	}
+	if len(result.EvaluatedIDs) != 1 || result.EvaluatedIDs[0] != recorded.EventID {
+		t.Fatalf("expected evaluated event ids to be recorded, got %+v", result.EvaluatedIDs)
+	}
+}
+
+func TestDecisionRecomputerReportsCandidatePolicyDivergence(t *testing.T) {
+	t.Parallel()
+
+	recorded := recordDecisionInput(t, NewDecisionRecomputer(nil))
+	candidate := NewDecisionRecomputer(stubTurnStartBundleResolver{bundle: TurnStartBundle{
+		PipelineVersion:        "pipeline-v1",
+		GraphDefinitionRef:     "graph/default",
+		ExecutionProfile:       "simple",
+		AllowedAdaptiveActions: []string{"retry"},
+		SnapshotProvenance:     defaultSnapshotProvenance(),
+		HasCPAdmissionDecision: true,
+		CPAdmissionOutcomeKind: controlplane.OutcomeReject,
+		CPAdmissionScope:       controlplane.ScopeSession,
+		CPAdmissionReason:      "cp_admission_reject_policy",
+	}})
+
+	result, err := replay.RunDecisionReplay(obs.ReplayRunRequest{
+		BaselineRef:  "baseline/sess-decision-replay",
+		CandidateRef: "policy-bundle/v2",
+		Mode:         obs.ReplayModeRecomputeDecisions,
+	}, []replay.RecordedDecisionInput{recorded}, candidate)
+	if err != nil {
+		t.Fatalf("unexpected decision replay error: %v", err)
+	}
+
+	classes := map[obs.DivergenceClass]int{}
+	for _, divergence := range result.Divergences {
+		classes[divergence.Class]++
+		if divergence.Scope != "turn:turn-decision-replay" {
+			t.Fatalf("expected turn-scoped divergence, got %+v", divergence)
+		}
+	}
+	if classes[obs.OutcomeDivergence] != 1 || classes[obs.PlanDivergence] == 0 {
+		t.Fatalf("expected outcome and plan divergences for rejecting candidate policy, got %+v", result.Divergences)
+	}
+	if classes[obs.TimingDivergence] != 0 || classes[obs.OrderingDivergence] != 0 {
+		t.Fatalf("expected decision-only divergence classes, got %+v", result.Divergences)
+	}
+}
+
+func TestRunDecisionReplayRejectsOtherModes(t *testing.T) {
+	t.Parallel()
+
+	_, err := replay.RunDecisionReplay(obs.ReplayRunRequest{
+		BaselineRef: "baseline/sess-decision-replay",
+		Mode:        obs.ReplayModeReSimulateNodes,
+	}, nil, NewDecisionRecomputer(nil))
+	if err != replay.ErrDecisionReplayModeMismatch {
+		t.Fatalf("expected mode mismatch error, got %v", err)
+	}
+}
+
+func recordDecisionInput(t *testing.T, recomputer DecisionRecomputer) replay.RecordedDecisionInput {
+	t.Helper()
+
+	in := replay.RecordedDecisionInput{
+		SessionID:            "sess-decision-replay",
+		TurnID:               "turn-decision-replay",
+		EventID:              "evt-decision-replay",
+		PipelineVersion:      "pipeline-v1",
+		AuthorityEpoch:       3,
+		RuntimeTimestampMS:   100,
+		WallClockTimestampMS: 100,
+		SnapshotValid:        true,
+		CapacityDisposition:  "allow",
+		AuthorityEpochValid:  true,
+		AuthorityAuthorized:  true,
+	}
+	baseline, err := recomputer.RecomputeDecision(in)
+	if err != nil {
+		t.Fatalf("unexpected baseline recompute error: %v", err)
+	}
+	in.BaselinePlanHash = baseline.PlanHash
+	in.BaselineSnapshotProvenance = baseline.SnapshotProvenance
+	in.BaselineDecision = baseline.Decision
+	return in
+}
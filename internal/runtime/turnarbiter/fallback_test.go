@@ -0,0 +1,82 @@
+package turnarbiter
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+func testFallbackUtterance() *timeline.FallbackUtteranceEvidence {
+	return &timeline.FallbackUtteranceEvidence{
+		EventID:        "evt-fallback-1",
+		Reason:         "provider_failure",
+		TemplateReason: "provider_failure",
+		Text:           "Sorry, I'm having trouble reaching my speech services.",
+		Source:         timeline.FallbackSourcePresynthesized,
+		SampleRateHz:   16000,
+		AudioSamples:   4800,
+		EmittedAtMS:    9,
+	}
+}
+
+func TestHandleActiveFallbackUtteranceTerminalOutcome(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		in            ActiveInput
+		wantEvents    []string
+		wantOutcome   string
+		wantReason    string
+		wantUtterance bool
+	}{
+		{
+			name:          "provider failure degrades to fallback",
+			in:            ActiveInput{ProviderFailure: true, FallbackUtterance: testFallbackUtterance()},
+			wantEvents:    []string{"fallback_utterance", "commit", "close"},
+			wantOutcome:   "fallback",
+			wantReason:    "provider_failure",
+			wantUtterance: true,
+		},
+		{
+			name:        "cancel wins over fallback",
+			in:          ActiveInput{CancelAccepted: true, FallbackUtterance: testFallbackUtterance()},
+			wantEvents:  []string{"abort", "close"},
+			wantOutcome: "abort",
+			wantReason:  "cancelled",
+		},
+		{
+			name:        "disconnected client cannot hear fallback",
+			in:          ActiveInput{ProviderFailure: true, TransportDisconnectOrStall: true, FallbackUtterance: testFallbackUtterance()},
+			wantEvents:  []string{"abort", "close"},
+			wantOutcome: "abort",
+			wantReason:  "provider_failure",
+		},
+	}
+	for _, tc := range tests {
+		recorder := timeline.NewRecorder(timeline.StageAConfig{})
+		in := tc.in
+		in.SessionID, in.TurnID, in.EventID = "sess-fallback-1", "turn-1", "evt-terminal-1"
+		in.RuntimeTimestampMS, in.WallClockTimestampMS, in.AuthorityEpoch = 9, 9, 3
+		result, err := NewWithRecorder(&recorder).HandleActive(in)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		var events []string
+		for _, event := range result.Events {
+			events = append(events, event.Name)
+		}
+		if !equalFixtureLists(tc.wantEvents, events) || result.State != controlplane.TurnClosed {
+			t.Fatalf("%s: expected events %v, got %+v", tc.name, tc.wantEvents, result.Events)
+		}
+		entries := recorder.BaselineEntries()
+		if len(entries) != 1 {
+			t.Fatalf("%s: expected one baseline entry, got %d", tc.name, len(entries))
+		}
+		entry := entries[0]
+		if entry.TerminalOutcome != tc.wantOutcome || entry.TerminalReason != tc.wantReason || (entry.FallbackUtterance != nil) != tc.wantUtterance {
+			t.Fatalf("%s: unexpected terminal evidence outcome=%s reason=%s utterance=%+v", tc.name, entry.TerminalOutcome, entry.TerminalReason, entry.FallbackUtterance)
+		}
+	}
+}
@@ -0,0 +1,487 @@
+package turnarbiter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/localadmission"
+)
+
+// FixtureSchemaVersion identifies the declarative arbiter fixture format.
+const FixtureSchemaVersion = "turn-arbiter-fixture/v1"
+
+// Fixture is a declarative arbiter scenario: an ordered input sequence and the transitions,
+// lifecycle events, decisions, and terminal outcomes each step must produce.
+type Fixture struct {
+	SchemaVersion string        `json:"schema_version"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description,omitempty"`
+	Steps         []FixtureStep `json:"steps"`
+}
+
+// FixtureStep sets exactly one of Open or Active.
+type FixtureStep struct {
+	Open   *FixtureOpen       `json:"open,omitempty"`
+	Active *FixtureActive     `json:"active,omitempty"`
+	Expect FixtureExpectation `json:"expect"`
+}
+
+// FixtureOpen is a turn-open proposal. Validity flags default to true so fixtures only spell
+// out the failure they exercise.
+type FixtureOpen struct {
+	SessionID             string                   `json:"session_id"`
+	TurnID                string                   `json:"turn_id"`
+	EventID               string                   `json:"event_id,omitempty"`
+	RuntimeTimestampMS    int64                    `json:"runtime_timestamp_ms,omitempty"`
+	PipelineVersion       string                   `json:"pipeline_version,omitempty"`
+	AuthorityEpoch        int64                    `json:"authority_epoch,omitempty"`
+	SnapshotValid         *bool                    `json:"snapshot_valid,omitempty"`
+	AuthorityEpochValid   *bool                    `json:"authority_epoch_valid,omitempty"`
+	AuthorityAuthorized   *bool                    `json:"authority_authorized,omitempty"`
+	CapacityDisposition   string                   `json:"capacity_disposition,omitempty"`
+	SnapshotFailurePolicy controlplane.OutcomeKind `json:"snapshot_failure_policy,omitempty"`
+	PlanShouldFail        bool                     `json:"plan_should_fail,omitempty"`
+	Queue                 *FixtureQueue            `json:"queue,omitempty"`
+}
+
+// FixtureQueue is the admission queue snapshot offered with a capacity-deferred open.
+type FixtureQueue struct {
+	TurnsAhead        int64 `json:"turns_ahead"`
+	ActiveSlots       int64 `json:"active_slots"`
+	MeanTurnServiceMS int64 `json:"mean_turn_service_ms"`
+}
+
+// FixtureActive is one Active-state input. Signals that race in the same input resolve by
+// the arbiter's precedence rules. Unset pipeline version and authority epoch inherit the
+// turn's resolved plan.
+type FixtureActive struct {
+	SessionID                    string `json:"session_id"`
+	TurnID                       string `json:"turn_id"`
+	EventID                      string `json:"event_id,omitempty"`
+	RuntimeTimestampMS           int64  `json:"runtime_timestamp_ms,omitempty"`
+	PipelineVersion              string `json:"pipeline_version,omitempty"`
+	AuthorityEpoch               int64  `json:"authority_epoch,omitempty"`
+	AuthorityRevoked             bool   `json:"authority_revoked,omitempty"`
+	CancelAccepted               bool   `json:"cancel_accepted,omitempty"`
+	ProviderFailure              bool   `json:"provider_failure,omitempty"`
+	NodeTimeoutOrFailure         bool   `json:"node_timeout_or_failure,omitempty"`
+	TransportDisconnectOrStall   bool   `json:"transport_disconnect_or_stall,omitempty"`
+	BaselineEvidenceAppendFailed bool   `json:"baseline_evidence_append_failed,omitempty"`
+	NoLegalContinueOrFallback    bool   `json:"no_legal_continue_or_fallback,omitempty"`
+	TerminalSuccessReady         bool   `json:"terminal_success_ready,omitempty"`
+}
+
+// FixtureExpectation lists what a step must produce. State is always checked; list fields are
+// checked when present, so an empty list asserts that nothing was produced.
+type FixtureExpectation struct {
+	State           controlplane.TurnState   `json:"state"`
+	Transitions     *[]FixtureTransition     `json:"transitions,omitempty"`
+	Events          *[]FixtureEvent          `json:"events,omitempty"`
+	ControlSignals  *[]string                `json:"control_signals,omitempty"`
+	DecisionOutcome controlplane.OutcomeKind `json:"decision_outcome,omitempty"`
+	DecisionReason  string                   `json:"decision_reason,omitempty"`
+	TerminalOutcome string                   `json:"terminal_outcome,omitempty"`
+	TerminalReason  string                   `json:"terminal_reason,omitempty"`
+	PlanPipeline    string                   `json:"plan_pipeline_version,omitempty"`
+	PlanEpoch       int64                    `json:"plan_authority_epoch,omitempty"`
+	Error           bool                     `json:"error,omitempty"`
+}
+
+// FixtureTransition is one expected state transition.
+type FixtureTransition struct {
+	From    controlplane.TurnState         `json:"from"`
+	Trigger controlplane.TransitionTrigger `json:"trigger"`
+	To      controlplane.TurnState         `json:"to"`
+}
+
+// FixtureEvent is one expected lifecycle event.
+type FixtureEvent struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// FixtureResult reports every mismatch found while running one fixture.
+type FixtureResult struct {
+	Name     string
+	Steps    int
+	Failures []string
+}
+
+// Passed reports whether every step matched its expectation.
+func (r FixtureResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// FixtureSummary aggregates fixture results for a fixture directory.
+type FixtureSummary struct {
+	Total   int
+	Failed  int
+	Results []FixtureResult
+}
+
+// LoadFixture reads and validates one fixture file. Unknown fields are rejected so typos in
+// expectation keys cannot silently disable a check.
+func LoadFixture(path string) (Fixture, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Fixture{}, fmt.Errorf("read arbiter fixture %s: %w", path, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	var fixture Fixture
+	if err := decoder.Decode(&fixture); err != nil {
+		return Fixture{}, fmt.Errorf("decode arbiter fixture %s: %w", path, err)
+	}
+	if err := fixture.Validate(); err != nil {
+		return Fixture{}, fmt.Errorf("arbiter fixture %s: %w", path, err)
+	}
+	return fixture, nil
+}
+
+// Validate enforces fixture shape before any step runs.
+func (f Fixture) Validate() error {
+	if f.SchemaVersion != FixtureSchemaVersion {
+		return fmt.Errorf("schema_version must be %s", FixtureSchemaVersion)
+	}
+	if strings.TrimSpace(f.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(f.Steps) == 0 {
+		return fmt.Errorf("steps are required")
+	}
+	for i, step := range f.Steps {
+		if (step.Open == nil) == (step.Active == nil) {
+			return fmt.Errorf("step %d must set exactly one of open or active", i)
+		}
+		if step.Open != nil && (step.Open.SessionID == "" || step.Open.TurnID == "") {
+			return fmt.Errorf("step %d open requires session_id and turn_id", i)
+		}
+		if step.Active != nil && (step.Active.SessionID == "" || step.Active.TurnID == "") {
+			return fmt.Errorf("step %d active requires session_id and turn_id", i)
+		}
+		if step.Expect.State == "" && !step.Expect.Error {
+			return fmt.Errorf("step %d expect.state is required", i)
+		}
+	}
+	return nil
+}
+
+// RunFixture runs the fixture through a fresh arbiter built by newArbiter, or through the
+// default arbiter with an isolated baseline recorder when newArbiter is nil. A step may only
+// drive a turn from the state the previous steps left it in.
+func RunFixture(fixture Fixture, newArbiter func() Arbiter) FixtureResult {
+	result := FixtureResult{Name: fixture.Name, Steps: len(fixture.Steps)}
+	if err := fixture.Validate(); err != nil {
+		result.Failures = append(result.Failures, err.Error())
+		return result
+	}
+	var arbiter Arbiter
+	if newArbiter != nil {
+		arbiter = newArbiter()
+	} else {
+		recorder := timeline.NewRecorder(timeline.StageAConfig{BaselineCapacity: 64, DetailCapacity: 64})
+		arbiter = NewWithRecorder(&recorder)
+	}
+
+	turns := map[string]*fixtureTurn{}
+	for i, step := range fixture.Steps {
+		fail := func(format string, args ...any) {
+			result.Failures = append(result.Failures, fmt.Sprintf("step %d: %s", i, fmt.Sprintf(format, args...)))
+		}
+		var observed fixtureObservation
+		var err error
+		if step.Open != nil {
+			key := step.Open.SessionID + "/" + step.Open.TurnID
+			if turn := turns[key]; turn != nil && turn.state != controlplane.TurnIdle {
+				fail("turn %s cannot reopen from state %s", key, turn.state)
+				continue
+			}
+			observed, err = runFixtureOpen(arbiter, *step.Open, i)
+			if err == nil {
+				turns[key] = &fixtureTurn{state: observed.state, pipelineVersion: observed.planPipeline, authorityEpoch: observed.planEpoch}
+			}
+		} else {
+			key := step.Active.SessionID + "/" + step.Active.TurnID
+			turn := turns[key]
+			if turn == nil || turn.state != controlplane.TurnActive {
+				state := controlplane.TurnIdle
+				if turn != nil {
+					state = turn.state
+				}
+				fail("turn %s is %s, not Active", key, state)
+				continue
+			}
+			observed, err = runFixtureActive(arbiter, *step.Active, *turn, i)
+			if err == nil {
+				turn.state = observed.state
+			}
+		}
+		if err != nil {
+			if !step.Expect.Error {
+				fail("unexpected arbiter error: %v", err)
+			}
+			continue
+		}
+		if step.Expect.Error {
+			fail("expected arbiter error, got state %s", observed.state)
+			continue
+		}
+		for _, mismatch := range step.Expect.compare(observed) {
+			fail("%s", mismatch)
+		}
+	}
+	return result
+}
+
+// ValidateFixtures runs every *.json fixture under root in name order.
+func ValidateFixtures(root string, newArbiter func() Arbiter) (FixtureSummary, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return FixtureSummary{}, fmt.Errorf("read arbiter fixtures %s: %w", root, err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return FixtureSummary{}, fmt.Errorf("no arbiter fixtures in %s", root)
+	}
+
+	summary := FixtureSummary{}
+	for _, name := range names {
+		path := filepath.Join(root, name)
+		summary.Total++
+		fixture, err := LoadFixture(path)
+		var result FixtureResult
+		if err != nil {
+			result = FixtureResult{Name: path, Failures: []string{err.Error()}}
+		} else {
+			result = RunFixture(fixture, newArbiter)
+			result.Name = path + " (" + fixture.Name + ")"
+		}
+		if !result.Passed() {
+			summary.Failed++
+		}
+		summary.Results = append(summary.Results, result)
+	}
+	return summary, nil
+}
+
+// RenderFixtureSummary renders a fixture summary in the validate-contracts style.
+func RenderFixtureSummary(summary FixtureSummary) string {
+	lines := []string{fmt.Sprintf("arbiter fixtures: total=%d failed=%d", summary.Total, summary.Failed)}
+	failed := false
+	for _, result := range summary.Results {
+		if result.Passed() {
+			continue
+		}
+		if !failed {
+			lines = append(lines, "failures:")
+			failed = true
+		}
+		for _, failure := range result.Failures {
+			lines = append(lines, fmt.Sprintf("- %s: %s", result.Name, failure))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+type fixtureTurn struct {
+	state           controlplane.TurnState
+	pipelineVersion string
+	authorityEpoch  int64
+}
+
+type fixtureObservation struct {
+	state          controlplane.TurnState
+	transitions    []controlplane.TurnTransition
+	events         []LifecycleEvent
+	controlSignals []string
+	decision       *controlplane.DecisionOutcome
+	planPipeline   string
+	planEpoch      int64
+}
+
+func runFixtureOpen(arbiter Arbiter, in FixtureOpen, step int) (fixtureObservation, error) {
+	capacity := localadmission.CapacityDisposition(in.CapacityDisposition)
+	if capacity == "" {
+		capacity = localadmission.CapacityAllow
+	}
+	timestamp := fixtureTimestamp(in.RuntimeTimestampMS, step)
+	var queue *localadmission.QueueState
+	if in.Queue != nil {
+		queue = &localadmission.QueueState{TurnsAhead: in.Queue.TurnsAhead, ActiveSlots: in.Queue.ActiveSlots, MeanTurnServiceMS: in.Queue.MeanTurnServiceMS}
+	}
+	out, err := arbiter.Apply(ApplyInput{Open: &OpenRequest{
+		SessionID:             in.SessionID,
+		TurnID:                in.TurnID,
+		EventID:               fallback(in.EventID, fmt.Sprintf("%s-step-%d", in.TurnID, step)),
+		RuntimeTimestampMS:    timestamp,
+		WallClockTimestampMS:  timestamp,
+		PipelineVersion:       in.PipelineVersion,
+		AuthorityEpoch:        in.AuthorityEpoch,
+		SnapshotValid:         fixtureFlag(in.SnapshotValid),
+		CapacityDisposition:   capacity,
+		AuthorityEpochValid:   fixtureFlag(in.AuthorityEpochValid),
+		AuthorityAuthorized:   fixtureFlag(in.AuthorityAuthorized),
+		SnapshotFailurePolicy: in.SnapshotFailurePolicy,
+		PlanShouldFail:        in.PlanShouldFail,
+		Queue:                 queue,
+	}})
+	if err != nil {
+		return fixtureObservation{}, err
+	}
+	observed := fixtureObservation{
+		state:       out.Open.State,
+		transitions: out.Open.Transitions,
+		events:      out.Open.Events,
+		decision:    out.Open.Decision,
+	}
+	for _, signal := range out.Open.ControlLane {
+		observed.controlSignals = append(observed.controlSignals, signal.Signal)
+	}
+	if out.Open.Plan != nil {
+		observed.planPipeline = out.Open.Plan.PipelineVersion
+		observed.planEpoch = out.Open.Plan.AuthorityEpoch
+	}
+	return observed, nil
+}
+
+func runFixtureActive(arbiter Arbiter, in FixtureActive, turn fixtureTurn, step int) (fixtureObservation, error) {
+	pipelineVersion := fallback(in.PipelineVersion, turn.pipelineVersion)
+	authorityEpoch := in.AuthorityEpoch
+	if authorityEpoch == 0 {
+		authorityEpoch = turn.authorityEpoch
+	}
+	timestamp := fixtureTimestamp(in.RuntimeTimestampMS, step)
+	out, err := arbiter.Apply(ApplyInput{Active: &ActiveInput{
+		SessionID:                    in.SessionID,
+		TurnID:                       in.TurnID,
+		EventID:                      fallback(in.EventID, fmt.Sprintf("%s-step-%d", in.TurnID, step)),
+		PipelineVersion:              pipelineVersion,
+		TransportSequence:            int64(step),
+		RuntimeSequence:              int64(step),
+		RuntimeTimestampMS:           timestamp,
+		WallClockTimestampMS:         timestamp,
+		AuthorityEpoch:               authorityEpoch,
+		AuthorityRevoked:             in.AuthorityRevoked,
+		CancelAccepted:               in.CancelAccepted,
+		ProviderFailure:              in.ProviderFailure,
+		NodeTimeoutOrFailure:         in.NodeTimeoutOrFailure,
+		TransportDisconnectOrStall:   in.TransportDisconnectOrStall,
+		BaselineEvidenceAppendFailed: in.BaselineEvidenceAppendFailed,
+		NoLegalContinueOrFallback:    in.NoLegalContinueOrFallback,
+		TerminalSuccessReady:         in.TerminalSuccessReady,
+	}})
+	if err != nil {
+		return fixtureObservation{}, err
+	}
+	observed := fixtureObservation{
+		state:       out.Active.State,
+		transitions: out.Active.Transitions,
+		events:      out.Active.Events,
+		decision:    out.Active.Decision,
+	}
+	for _, signal := range out.Active.ControlLane {
+		observed.controlSignals = append(observed.controlSignals, signal.Signal)
+	}
+	return observed, nil
+}
+
+func (e FixtureExpectation) compare(observed fixtureObservation) []string {
+	var mismatches []string
+	if observed.state != e.State {
+		mismatches = append(mismatches, fmt.Sprintf("state: expected %s, got %s", e.State, observed.state))
+	}
+	if e.Transitions != nil {
+		got := make([]FixtureTransition, 0, len(observed.transitions))
+		for _, tr := range observed.transitions {
+			got = append(got, FixtureTransition{From: tr.FromState, Trigger: tr.Trigger, To: tr.ToState})
+		}
+		if !equalFixtureLists(*e.Transitions, got) {
+			mismatches = append(mismatches, fmt.Sprintf("transitions: expected %v, got %v", *e.Transitions, got))
+		}
+	}
+	if e.Events != nil {
+		got := make([]FixtureEvent, 0, len(observed.events))
+		for _, event := range observed.events {
+			got = append(got, FixtureEvent{Name: event.Name, Reason: event.Reason})
+		}
+		if !equalFixtureLists(*e.Events, got) {
+			mismatches = append(mismatches, fmt.Sprintf("events: expected %v, got %v", *e.Events, got))
+		}
+	}
+	if e.ControlSignals != nil && !equalFixtureLists(*e.ControlSignals, observed.controlSignals) {
+		mismatches = append(mismatches, fmt.Sprintf("control_signals: expected %v, got %v", *e.ControlSignals, observed.controlSignals))
+	}
+	if e.DecisionOutcome != "" || e.DecisionReason != "" {
+		var kind controlplane.OutcomeKind
+		var reason string
+		if observed.decision != nil {
+			kind, reason = observed.decision.OutcomeKind, observed.decision.Reason
+		}
+		if e.DecisionOutcome != "" && kind != e.DecisionOutcome {
+			mismatches = append(mismatches, fmt.Sprintf("decision_outcome: expected %s, got %q", e.DecisionOutcome, kind))
+		}
+		if e.DecisionReason != "" && reason != e.DecisionReason {
+			mismatches = append(mismatches, fmt.Sprintf("decision_reason: expected %s, got %q", e.DecisionReason, reason))
+		}
+	}
+	if e.TerminalOutcome != "" || e.TerminalReason != "" {
+		outcome := terminalLifecycleEvent(observed.events)
+		reason := ""
+		for _, event := range observed.events {
+			if event.Name == outcome {
+				reason = event.Reason
+				break
+			}
+		}
+		if outcome != e.TerminalOutcome {
+			mismatches = append(mismatches, fmt.Sprintf("terminal_outcome: expected %s, got %q", e.TerminalOutcome, outcome))
+		}
+		if reason != e.TerminalReason {
+			mismatches = append(mismatches, fmt.Sprintf("terminal_reason: expected %q, got %q", e.TerminalReason, reason))
+		}
+	}
+	if e.PlanPipeline != "" && observed.planPipeline != e.PlanPipeline {
+		mismatches = append(mismatches, fmt.Sprintf("plan_pipeline_version: expected %s, got %q", e.PlanPipeline, observed.planPipeline))
+	}
+	if e.PlanEpoch != 0 && observed.planEpoch != e.PlanEpoch {
+		mismatches = append(mismatches, fmt.Sprintf("plan_authority_epoch: expected %d, got %d", e.PlanEpoch, observed.planEpoch))
+	}
+	return mismatches
+}
+
+func equalFixtureLists[T comparable](want []T, got []T) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if want[i] != got[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func fixtureFlag(value *bool) bool {
+	return value == nil || *value
+}
+
+// fixtureTimestamp keeps steps strictly ordered when a fixture omits timestamps.
+func fixtureTimestamp(value int64, step int) int64 {
+	if value > 0 {
+		return value
+	}
+	return int64(step+1) * 10
+}
@@ -0,0 +1,94 @@
+package turnarbiter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+)
+
+func TestRunFixtureReportsMismatches(t *testing.T) {
+	t.Parallel()
+
+	open := FixtureStep{Open: &FixtureOpen{SessionID: "sess-fx", TurnID: "turn-1"}, Expect: FixtureExpectation{State: controlplane.TurnActive}}
+	cases := []struct {
+		name        string
+		steps       []FixtureStep
+		wantFailure string
+	}{
+		{
+			name:  "matching sequence",
+			steps: []FixtureStep{open, {Active: &FixtureActive{SessionID: "sess-fx", TurnID: "turn-1", CancelAccepted: true}, Expect: FixtureExpectation{State: controlplane.TurnClosed, TerminalOutcome: "abort", TerminalReason: "cancelled"}}},
+		},
+		{
+			name:        "wrong terminal reason",
+			steps:       []FixtureStep{open, {Active: &FixtureActive{SessionID: "sess-fx", TurnID: "turn-1", CancelAccepted: true, ProviderFailure: true}, Expect: FixtureExpectation{State: controlplane.TurnClosed, TerminalOutcome: "abort", TerminalReason: "provider_failure"}}},
+			wantFailure: `step 1: terminal_reason: expected "provider_failure", got "cancelled"`,
+		},
+		{
+			name:        "unexpected events",
+			steps:       []FixtureStep{open, {Active: &FixtureActive{SessionID: "sess-fx", TurnID: "turn-1"}, Expect: FixtureExpectation{State: controlplane.TurnActive, Events: &[]FixtureEvent{{Name: "commit"}}}}},
+			wantFailure: "step 1: events: expected",
+		},
+		{
+			name:        "active before open",
+			steps:       []FixtureStep{{Active: &FixtureActive{SessionID: "sess-fx", TurnID: "turn-1"}, Expect: FixtureExpectation{State: controlplane.TurnActive}}},
+			wantFailure: "step 0: turn sess-fx/turn-1 is Idle, not Active",
+		},
+		{
+			name:        "reopen closed turn",
+			steps:       []FixtureStep{open, {Active: &FixtureActive{SessionID: "sess-fx", TurnID: "turn-1", TerminalSuccessReady: true}, Expect: FixtureExpectation{State: controlplane.TurnClosed}}, open},
+			wantFailure: "step 2: turn sess-fx/turn-1 cannot reopen from state Closed",
+		},
+	}
+	for _, tc := range cases {
+		result := RunFixture(Fixture{SchemaVersion: FixtureSchemaVersion, Name: tc.name, Steps: tc.steps}, nil)
+		if tc.wantFailure == "" {
+			if !result.Passed() {
+				t.Fatalf("%s: expected fixture to pass, got %+v", tc.name, result.Failures)
+			}
+			continue
+		}
+		if len(result.Failures) != 1 || !strings.HasPrefix(result.Failures[0], tc.wantFailure) {
+			t.Fatalf("%s: expected failure %q, got %+v", tc.name, tc.wantFailure, result.Failures)
+		}
+	}
+}
+
+func TestLoadFixtureRejectsMalformedFiles(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{name: "unknown expectation key", body: `{"schema_version":"turn-arbiter-fixture/v1","name":"x","steps":[{"open":{"session_id":"s","turn_id":"t"},"expect":{"state":"Active","terminal":"commit"}}]}`, wantErr: "unknown field"},
+		{name: "wrong schema", body: `{"schema_version":"v0","name":"x","steps":[]}`, wantErr: "schema_version"},
+		{name: "both inputs", body: `{"schema_version":"turn-arbiter-fixture/v1","name":"x","steps":[{"open":{"session_id":"s","turn_id":"t"},"active":{"session_id":"s","turn_id":"t"},"expect":{"state":"Active"}}]}`, wantErr: "exactly one of open or active"},
+		{name: "missing state", body: `{"schema_version":"turn-arbiter-fixture/v1","name":"x","steps":[{"open":{"session_id":"s","turn_id":"t"},"expect":{}}]}`, wantErr: "expect.state is required"},
+	}
+	dir := t.TempDir()
+	for _, tc := range cases {
+		path := filepath.Join(dir, strings.ReplaceAll(tc.name, " ", "_")+".json")
+		if err := os.WriteFile(path, []byte(tc.body), 0o644); err != nil {
+			t.Fatalf("%s: write fixture: %v", tc.name, err)
+		}
+		if _, err := LoadFixture(path); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+
+	summary, err := ValidateFixtures(dir, nil)
+	if err != nil {
+		t.Fatalf("unexpected validate error: %v", err)
+	}
+	if summary.Total != len(cases) || summary.Failed != len(cases) {
+		t.Fatalf("expected every malformed fixture to fail, got %+v", summary)
+	}
+	if rendered := RenderFixtureSummary(summary); !strings.Contains(rendered, "failed=4") {
+		t.Fatalf("unexpected rendered summary: %s", rendered)
+	}
+}
@@ -0,0 +1,72 @@
+package turnarbiter
+
+import (
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/localadmission"
+)
+
+// queueETA builds the client-facing queue_eta signal for a capacity-deferred turn and records
+// the estimate and its inputs for later accuracy analysis. Recording is best-effort: a full
+// recorder must not turn a defer into a failure.
+func (a Arbiter) queueETA(in OpenRequest, decision controlplane.DecisionOutcome, estimate localadmission.QueueEstimate) (eventabi.ControlSignal, error) {
+	scope := eventabi.ScopeSession
+	if in.TurnID != "" {
+		scope = eventabi.ScopeTurn
+	}
+	pipelineVersion := defaultPipelineVersion(in.PipelineVersion)
+	eventID := in.EventID + "-queue-eta"
+	// The signal is emitted before the turn is sequenced; transport egress assigns ordering.
+	transportSequence := int64(0)
+	position := estimate.Position
+	waitMS := estimate.EstimatedWaitMS
+	signal := eventabi.ControlSignal{
+		SchemaVersion:      "v1.0",
+		EventScope:         scope,
+		Signal:             "queue_eta",
+		EmittedBy:          "RK-25",
+		SessionID:          in.SessionID,
+		TurnID:             in.TurnID,
+		PipelineVersion:    pipelineVersion,
+		EventID:            eventID,
+		Lane:               eventabi.LaneControl,
+		TransportSequence:  &transportSequence,
+		AuthorityEpoch:     nonNegative(in.AuthorityEpoch),
+		RuntimeTimestampMS: nonNegative(in.RuntimeTimestampMS),
+		WallClockMS:        nonNegative(in.WallClockTimestampMS),
+		PayloadClass:       eventabi.PayloadMetadata,
+		Reason:             decision.Reason,
+		Scope:              string(scope),
+		QueuePosition:      &position,
+		EstimatedWaitMS:    &waitMS,
+	}
+	if err := signal.Validate(); err != nil {
+		return eventabi.ControlSignal{}, err
+	}
+
+	if a.baselineRecorder != nil && in.TurnID != "" {
+		_ = a.baselineRecorder.AppendQueueEstimateEvidence(timeline.QueueEstimateEvidence{
+			SessionID:         in.SessionID,
+			TurnID:            in.TurnID,
+			PipelineVersion:   pipelineVersion,
+			EventID:           eventID,
+			EstimatedAtMS:     signal.RuntimeTimestampMS,
+			QueuePosition:     position,
+			EstimatedWaitMS:   waitMS,
+			Method:            estimate.Method,
+			TurnsAhead:        estimate.Inputs.TurnsAhead,
+			ActiveSlots:       estimate.Inputs.ActiveSlots,
+			MeanTurnServiceMS: estimate.Inputs.MeanTurnServiceMS,
+		})
+	}
+	return signal, nil
+}
+
+// resolveQueueEstimates closes out any queue estimates given while this turn was deferred.
+func (a Arbiter) resolveQueueEstimates(in OpenRequest) {
+	if a.baselineRecorder == nil || in.TurnID == "" {
+		return
+	}
+	a.baselineRecorder.ResolveQueueEstimates(in.SessionID, in.TurnID, nonNegative(in.RuntimeTimestampMS))
+}
@@ -0,0 +1,106 @@
+package turnarbiter
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/localadmission"
+)
+
+func TestHandleTurnOpenProposedEmitsQueueETAOnCapacityDefer(t *testing.T) {
+	t.Parallel()
+
+	recorder := timeline.NewRecorder(timeline.StageAConfig{})
+	arbiter := NewWithRecorder(&recorder)
+	open := OpenRequest{
+		SessionID:             "sess-queue-1",
+		TurnID:                "turn-1",
+		EventID:               "evt-open-1",
+		RuntimeTimestampMS:    1_000,
+		WallClockTimestampMS:  1_000,
+		PipelineVersion:       "pipeline-v1",
+		AuthorityEpoch:        2,
+		SnapshotValid:         true,
+		AuthorityEpochValid:   true,
+		AuthorityAuthorized:   true,
+		CapacityDisposition:   localadmission.CapacityDefer,
+		SnapshotFailurePolicy: controlplane.OutcomeDefer,
+		PlanFailurePolicy:     controlplane.OutcomeReject,
+		Queue:                 &localadmission.QueueState{TurnsAhead: 4, ActiveSlots: 2, MeanTurnServiceMS: 800},
+	}
+	deferred, err := arbiter.HandleTurnOpenProposed(open)
+	if err != nil {
+		t.Fatalf("unexpected defer error: %v", err)
+	}
+	if deferred.State != controlplane.TurnIdle || deferred.Decision == nil || deferred.Decision.OutcomeKind != controlplane.OutcomeDefer {
+		t.Fatalf("expected capacity defer, got %+v", deferred)
+	}
+	if len(deferred.ControlLane) != 1 {
+		t.Fatalf("expected one queue_eta signal, got %+v", deferred.ControlLane)
+	}
+	signal := deferred.ControlLane[0]
+	if signal.Signal != "queue_eta" || signal.EmittedBy != "RK-25" || *signal.QueuePosition != 5 || *signal.EstimatedWaitMS != 2_400 {
+		t.Fatalf("unexpected queue_eta signal: %+v", signal)
+	}
+	if err := signal.Validate(); err != nil {
+		t.Fatalf("queue_eta signal should validate: %v", err)
+	}
+
+	entries := recorder.QueueEstimateEntries()
+	if len(entries) != 1 || entries[0].Resolved || entries[0].Method != localadmission.QueueEstimateMethod || entries[0].TurnsAhead != 4 {
+		t.Fatalf("expected one unresolved estimate with inputs, got %+v", entries)
+	}
+
+	open.CapacityDisposition = localadmission.CapacityAllow
+	open.Queue = nil
+	open.RuntimeTimestampMS = 3_100
+	opened, err := arbiter.HandleTurnOpenProposed(open)
+	if err != nil || opened.State != controlplane.TurnActive {
+		t.Fatalf("expected turn to open after defer, got %+v (%v)", opened, err)
+	}
+	if len(opened.ControlLane) != 0 {
+		t.Fatalf("expected no control signals on open, got %+v", opened.ControlLane)
+	}
+	entries = recorder.QueueEstimateEntries()
+	if !entries[0].Resolved || entries[0].AdmittedAtMS != 3_100 || entries[0].ActualWaitMS != 2_100 {
+		t.Fatalf("expected estimate resolved with actual wait, got %+v", entries[0])
+	}
+}
+
+func TestHandleTurnOpenProposedOmitsQueueETAWithoutEstimate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		capacity localadmission.CapacityDisposition
+		snapshot bool
+		queue    *localadmission.QueueState
+	}{
+		{name: "defer without queue snapshot", capacity: localadmission.CapacityDefer, snapshot: true},
+		{name: "reject sheds instead of queueing", capacity: localadmission.CapacityReject, snapshot: true, queue: &localadmission.QueueState{TurnsAhead: 1, ActiveSlots: 1, MeanTurnServiceMS: 500}},
+		{name: "snapshot defer is not a queue", capacity: localadmission.CapacityAllow, queue: &localadmission.QueueState{TurnsAhead: 1, ActiveSlots: 1, MeanTurnServiceMS: 500}},
+		{name: "invalid queue snapshot", capacity: localadmission.CapacityDefer, snapshot: true, queue: &localadmission.QueueState{TurnsAhead: 1}},
+	}
+	for _, tc := range tests {
+		recorder := timeline.NewRecorder(timeline.StageAConfig{})
+		result, err := NewWithRecorder(&recorder).HandleTurnOpenProposed(OpenRequest{
+			SessionID:             "sess-queue-2",
+			TurnID:                "turn-1",
+			EventID:               "evt-open-1",
+			PipelineVersion:       "pipeline-v1",
+			SnapshotValid:         tc.snapshot,
+			AuthorityEpochValid:   true,
+			AuthorityAuthorized:   true,
+			CapacityDisposition:   tc.capacity,
+			SnapshotFailurePolicy: controlplane.OutcomeDefer,
+			Queue:                 tc.queue,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if result.State != controlplane.TurnIdle || len(result.ControlLane) != 0 || len(recorder.QueueEstimateEntries()) != 0 {
+			t.Fatalf("%s: expected no queue_eta, got %+v", tc.name, result)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package piidetect
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+// Rule detects one kind of PII/PHI in transcript text. When Pattern has a capture group that
+// matched, only the group is the finding, so a rule can anchor on context ("my mrn is") without
+// redacting it. Check, when set, rejects pattern matches that fail a structural check.
+type Rule struct {
+	Name    string
+	Class   eventabi.PayloadClass
+	Pattern *regexp.Regexp
+	Check   func(match string) bool
+}
+
+// Finding is one detected span of text, as byte offsets into the input.
+type Finding struct {
+	Rule  string                `json:"rule"`
+	Class eventabi.PayloadClass `json:"class"`
+	Start int                   `json:"start"`
+	End   int                   `json:"end"`
+}
+
+// Detector applies redaction rules to transcript text.
+type Detector struct {
+	rules []Rule
+}
+
+// NewDetector validates rules and returns a detector that applies them in order.
+func NewDetector(rules []Rule) (Detector, error) {
+	seen := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		if rule.Name == "" || rule.Pattern == nil {
+			return Detector{}, fmt.Errorf("pii rule name and pattern are required")
+		}
+		if rule.Class != eventabi.PayloadPII && rule.Class != eventabi.PayloadPHI {
+			return Detector{}, fmt.Errorf("pii rule %s class must be %s or %s", rule.Name, eventabi.PayloadPII, eventabi.PayloadPHI)
+		}
+		if _, ok := seen[rule.Name]; ok {
+			return Detector{}, fmt.Errorf("duplicate pii rule: %s", rule.Name)
+		}
+		seen[rule.Name] = struct{}{}
+	}
+	return Detector{rules: append([]Rule(nil), rules...)}, nil
+}
+
+// NewDefaultDetector returns a detector with DefaultRules.
+func NewDefaultDetector() Detector {
+	detector, err := NewDetector(DefaultRules())
+	if err != nil {
+		panic(err)
+	}
+	return detector
+}
+
+// DefaultRules returns the transcript redaction rules. Changes to them are gated by the
+// redaction quality suite, since over- and under-redaction both carry risk.
+func DefaultRules() []Rule {
+	return []Rule{
+		{Name: "email", Class: eventabi.PayloadPII, Pattern: regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`)},
+		{Name: "us_ssn", Class: eventabi.PayloadPII, Pattern: regexp.MustCompile(`\b\d{3}[- ]\d{2}[- ]\d{4}\b`)},
+		{Name: "payment_card", Class: eventabi.PayloadPII, Pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`), Check: luhnValid},
+		{Name: "phone_number", Class: eventabi.PayloadPII, Pattern: regexp.MustCompile(`(?:\+1[ .-]?)?(?:\(\d{3}\) ?|\b\d{3}[ .-])\d{3}[ .-]\d{4}\b`)},
+		{Name: "street_address", Class: eventabi.PayloadPII, Pattern: regexp.MustCompile(`\b\d{1,5} (?:[A-Z][a-z]+ ){1,3}(?:Street|St|Avenue|Ave|Road|Rd|Lane|Ln|Drive|Dr|Boulevard|Blvd|Court|Ct)\b`)},
+		{Name: "date_of_birth", Class: eventabi.PayloadPII, Pattern: regexp.MustCompile(`(?i)\b(?:born on|date of birth is|dob is)\s+(\d{1,2}/\d{1,2}/\d{2,4}|(?:january|february|march|april|may|june|july|august|september|october|november|december) \d{1,2}(?:st|nd|rd|th)?,? \d{4})`)},
+		{Name: "medical_record_number", Class: eventabi.PayloadPHI, Pattern: regexp.MustCompile(`(?i)\b(?:mrn|medical record number)(?: is)?[:#]?\s*([a-z]{0,3}-?\d{5,10})\b`)},
+		{Name: "health_plan_id", Class: eventabi.PayloadPHI, Pattern: regexp.MustCompile(`(?i)\b(?:member id|insurance id|policy number)(?: is)?[:#]?\s*([a-z]{0,4}\d{6,12})\b`)},
+		{Name: "health_condition", Class: eventabi.PayloadPHI, Pattern: regexp.MustCompile(`(?i)\b(?:type [12] diabetes|diabetes|hypertension|asthma|hiv|depression|anxiety disorder|chemotherapy|pregnancy|pregnant)\b`)},
+		{Name: "medication", Class: eventabi.PayloadPHI, Pattern: regexp.MustCompile(`(?i)\b(?:insulin|metformin|lisinopril|sertraline|atorvastatin|albuterol|prednisone)\b`)},
+	}
+}
+
+// Detect returns the non-overlapping findings in text ordered by offset. Where findings
+// overlap, the earlier one wins and then the longer one.
+func (d Detector) Detect(text string) []Finding {
+	candidates := make([]Finding, 0)
+	for _, rule := range d.rules {
+		for _, match := range rule.Pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := match[0], match[1]
+			if len(match) >= 4 && match[2] >= 0 {
+				start, end = match[2], match[3]
+			}
+			if start == end || (rule.Check != nil && !rule.Check(text[start:end])) {
+				continue
+			}
+			candidates = append(candidates, Finding{Rule: rule.Name, Class: rule.Class, Start: start, End: end})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Start != candidates[j].Start {
+			return candidates[i].Start < candidates[j].Start
+		}
+		return candidates[i].End > candidates[j].End
+	})
+	findings := make([]Finding, 0, len(candidates))
+	for _, candidate := range candidates {
+		if len(findings) > 0 && candidate.Start < findings[len(findings)-1].End {
+			continue
+		}
+		findings = append(findings, candidate)
+	}
+	return findings
+}
+
+// Redact replaces every finding in text with its class marker, such as "[PII]".
+func (d Detector) Redact(text string) (string, []Finding) {
+	findings := d.Detect(text)
+	var redacted strings.Builder
+	last := 0
+	for _, finding := range findings {
+		redacted.WriteString(text[last:finding.Start])
+		redacted.WriteString("[" + string(finding.Class) + "]")
+		last = finding.End
+	}
+	redacted.WriteString(text[last:])
+	return redacted.String(), findings
+}
+
+func luhnValid(match string) bool {
+	sum := 0
+	digits := 0
+	for idx := len(match) - 1; idx >= 0; idx-- {
+		c := match[idx]
+		if c < '0' || c > '9' {
+			continue
+		}
+		value := int(c - '0')
+		if digits%2 == 1 {
+			value *= 2
+			if value > 9 {
+				value -= 9
+			}
+		}
+		sum += value
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
@@ -0,0 +1,50 @@
+package piidetect
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+func TestDefaultDetectorRedactsTranscriptSpans(t *testing.T) {
+	t.Parallel()
+
+	detector := NewDefaultDetector()
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "email and phone", text: "mail jo@example.com or call 415-555-0132", want: "mail [PII] or call [PII]"},
+		{name: "context kept around mrn", text: "my MRN is MR-0048213 today", want: "my MRN is [PHI] today"},
+		{name: "luhn-valid card only", text: "card 4111 1111 1111 1111 not 1234 5678 9012 3456", want: "card [PII] not 1234 5678 9012 3456"},
+		{name: "medication", text: "refill my Metformin please", want: "refill my [PHI] please"},
+		{name: "nothing to redact", text: "table for 4 at 7:30", want: "table for 4 at 7:30"},
+	}
+	for _, tc := range tests {
+		got, _ := detector.Redact(tc.text)
+		if got != tc.want {
+			t.Fatalf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestNewDetectorValidatesRules(t *testing.T) {
+	t.Parallel()
+
+	pattern := regexp.MustCompile(`x`)
+	tests := []struct {
+		name  string
+		rules []Rule
+	}{
+		{name: "missing pattern", rules: []Rule{{Name: "a", Class: eventabi.PayloadPII}}},
+		{name: "non pii class", rules: []Rule{{Name: "a", Class: eventabi.PayloadTextRaw, Pattern: pattern}}},
+		{name: "duplicate name", rules: []Rule{{Name: "a", Class: eventabi.PayloadPII, Pattern: pattern}, {Name: "a", Class: eventabi.PayloadPHI, Pattern: pattern}}},
+	}
+	for _, tc := range tests {
+		if _, err := NewDetector(tc.rules); err == nil {
+			t.Fatalf("%s: expected rule validation error", tc.name)
+		}
+	}
+}
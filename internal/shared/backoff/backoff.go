@@ -0,0 +1,175 @@
+package backoff
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
+)
+
+// Jitter selects how exponential delays are randomized.
+type Jitter string
+
+const (
+	// JitterNone produces plain capped exponential delays (base, 2*base, 4*base, ...).
+	JitterNone Jitter = "none"
+	// JitterDecorrelated draws each delay from [base, 3*previous], capped at Max.
+	JitterDecorrelated Jitter = "decorrelated"
+)
+
+// Policy configures retry pacing shared by runtime, control-plane, and replay clients.
+type Policy struct {
+	Base   time.Duration
+	Max    time.Duration
+	Jitter Jitter
+	// Budget caps cumulative delay across one sequence. Zero means unbounded.
+	Budget time.Duration
+}
+
+// Validate enforces policy bounds.
+func (p Policy) Validate() error {
+	if p.Base <= 0 {
+		return fmt.Errorf("backoff base must be >0")
+	}
+	if p.Max < p.Base {
+		return fmt.Errorf("backoff max must be >= base")
+	}
+	if p.Budget < 0 {
+		return fmt.Errorf("backoff budget must be >=0")
+	}
+	switch p.Jitter {
+	case "", JitterNone, JitterDecorrelated:
+		return nil
+	default:
+		return fmt.Errorf("unsupported backoff jitter: %q", p.Jitter)
+	}
+}
+
+// Normalize returns a policy with defaults applied: a non-positive base falls back to
+// defaultBase and a max below base is raised to base.
+func (p Policy) Normalize(defaultBase time.Duration) Policy {
+	if p.Base <= 0 {
+		p.Base = defaultBase
+	}
+	if p.Max < p.Base {
+		p.Max = p.Base
+	}
+	if p.Jitter == "" {
+		p.Jitter = JitterNone
+	}
+	if p.Budget < 0 {
+		p.Budget = 0
+	}
+	return p
+}
+
+// Exponential returns the capped exponential delay for a 1-based attempt without jitter.
+func (p Policy) Exponential(attempt int) time.Duration {
+	if attempt < 1 {
+		return p.Base
+	}
+	delay := p.Base
+	for i := 1; i < attempt; i++ {
+		if delay >= p.Max/2 {
+			return p.Max
+		}
+		delay *= 2
+	}
+	return min(delay, p.Max)
+}
+
+// Sequence produces successive retry delays for one retried operation. Jitter is derived
+// from the seed so the same operation replays the same delays.
+type Sequence struct {
+	policy  Policy
+	state   uint64
+	attempt int
+	prev    time.Duration
+	spent   time.Duration
+}
+
+// NewSequence starts a delay sequence seeded by a stable operation key.
+func (p Policy) NewSequence(seed string) *Sequence {
+	sum := sha256.Sum256([]byte(seed))
+	return &Sequence{policy: p, state: binary.BigEndian.Uint64(sum[:8]) | 1}
+}
+
+// Next returns the delay before the next retry and false once the budget is exhausted.
+func (s *Sequence) Next() (time.Duration, bool) {
+	s.attempt++
+	var delay time.Duration
+	switch s.policy.Jitter {
+	case JitterDecorrelated:
+		upper := s.policy.Base
+		if s.prev > 0 {
+			upper = min(s.prev*3, s.policy.Max)
+		}
+		delay = s.policy.Base
+		if upper > s.policy.Base {
+			delay += time.Duration(s.nextRand() % uint64(upper-s.policy.Base+1))
+		}
+	default:
+		delay = s.policy.Exponential(s.attempt)
+	}
+	delay = min(delay, s.policy.Max)
+
+	if s.policy.Budget > 0 {
+		remaining := s.policy.Budget - s.spent
+		if remaining <= 0 {
+			return 0, false
+		}
+		delay = min(delay, remaining)
+	}
+	s.prev = delay
+	s.spent += delay
+	return delay, true
+}
+
+// Spent reports cumulative delay issued by the sequence.
+func (s *Sequence) Spent() time.Duration {
+	return s.spent
+}
+
+// Retry runs op up to maxAttempts times, waiting each sequence delay on clk between
+// attempts. It stops at the first success, a non-retryable error, an exhausted budget, or a
+// done ctx, and returns the last op error (or ctx's error if it ended a wait).
+func (p Policy) Retry(ctx context.Context, clk clock.Clock, seed string, maxAttempts int, op func() (retryable bool, err error)) error {
+	if clk == nil {
+		clk = clock.Real()
+	}
+	delays := p.NewSequence(seed)
+	var lastErr error
+	for attempt := 1; attempt <= max(maxAttempts, 1); attempt++ {
+		retryable, err := op()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retryable || attempt >= maxAttempts {
+			break
+		}
+		delay, ok := delays.Next()
+		if !ok {
+			break
+		}
+		timer := clk.NewTimer(delay)
+		select {
+		case <-timer.C():
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (retry abandoned: %v)", lastErr, ctx.Err())
+		}
+	}
+	return lastErr
+}
+
+// nextRand is a xorshift64 step; deterministic and allocation free.
+func (s *Sequence) nextRand() uint64 {
+	s.state ^= s.state << 13
+	s.state ^= s.state >> 7
+	s.state ^= s.state << 17
+	return s.state
+}
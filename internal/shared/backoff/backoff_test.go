@@ -0,0 +1,174 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
+)
+
+func TestSequenceExponentialWithoutJitter(t *testing.T) {
+	t.Parallel()
+
+	seq := Policy{Base: 10 * time.Millisecond, Max: 50 * time.Millisecond}.Normalize(time.Millisecond).NewSequence("op")
+	got := make([]time.Duration, 0, 4)
+	for i := 0; i < 4; i++ {
+		delay, ok := seq.Next()
+		if !ok {
+			t.Fatalf("unexpected budget exhaustion at step %d", i)
+		}
+		got = append(got, delay)
+	}
+	want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected capped exponential delays %v, got %v", want, got)
+	}
+}
+
+func TestSequenceDecorrelatedJitterIsBoundedAndDeterministic(t *testing.T) {
+	t.Parallel()
+
+	policy := Policy{Base: 10 * time.Millisecond, Max: 200 * time.Millisecond, Jitter: JitterDecorrelated}
+	run := func(seed string) []time.Duration {
+		seq := policy.NewSequence(seed)
+		out := make([]time.Duration, 0, 8)
+		prev := policy.Base
+		for i := 0; i < 8; i++ {
+			delay, _ := seq.Next()
+			if delay < policy.Base || delay > policy.Max || delay > prev*3 {
+				t.Fatalf("delay %v outside decorrelated bounds (prev=%v)", delay, prev)
+			}
+			prev = delay
+			out = append(out, delay)
+		}
+		return out
+	}
+	if a, b := run("invocation-1"), run("invocation-1"); !reflect.DeepEqual(a, b) {
+		t.Fatalf("expected same seed to replay identical delays, got %v vs %v", a, b)
+	}
+	if a, b := run("invocation-1"), run("invocation-2"); reflect.DeepEqual(a, b) {
+		t.Fatalf("expected different seeds to diverge, got %v", a)
+	}
+}
+
+func TestSequenceBudgetCapsCumulativeDelay(t *testing.T) {
+	t.Parallel()
+
+	seq := Policy{Base: 40 * time.Millisecond, Max: 40 * time.Millisecond, Budget: 100 * time.Millisecond}.NewSequence("op")
+	var got []time.Duration
+	for {
+		delay, ok := seq.Next()
+		if !ok {
+			break
+		}
+		got = append(got, delay)
+	}
+	want := []time.Duration{40 * time.Millisecond, 40 * time.Millisecond, 20 * time.Millisecond}
+	if !reflect.DeepEqual(got, want) || seq.Spent() != 100*time.Millisecond {
+		t.Fatalf("expected budget-truncated delays %v, got %v spent=%v", want, got, seq.Spent())
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy Policy
+		valid  bool
+	}{
+		{name: "valid", policy: Policy{Base: time.Millisecond, Max: time.Second, Jitter: JitterDecorrelated}, valid: true},
+		{name: "zero base", policy: Policy{Max: time.Second}},
+		{name: "max below base", policy: Policy{Base: time.Second, Max: time.Millisecond}},
+		{name: "negative budget", policy: Policy{Base: time.Millisecond, Max: time.Second, Budget: -1}},
+		{name: "unknown jitter", policy: Policy{Base: time.Millisecond, Max: time.Second, Jitter: "full"}},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tc.policy.Validate(); (err == nil) != tc.valid {
+				t.Fatalf("expected valid=%t, got err=%v", tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestRetryWaitsSequenceDelaysOnClock(t *testing.T) {
+	t.Parallel()
+
+	errTransient := errors.New("transient")
+	errFatal := errors.New("fatal")
+	tests := []struct {
+		name        string
+		results     []error
+		retryable   bool
+		maxAttempts int
+		wantCalls   int
+		wantWaited  time.Duration
+		wantErr     error
+	}{
+		{name: "success after retries", results: []error{errTransient, errTransient, nil}, retryable: true, maxAttempts: 3, wantCalls: 3, wantWaited: 30 * time.Millisecond},
+		{name: "attempts exhausted", results: []error{errTransient, errTransient, errTransient}, retryable: true, maxAttempts: 2, wantCalls: 2, wantWaited: 10 * time.Millisecond, wantErr: errTransient},
+		{name: "non-retryable", results: []error{errFatal}, retryable: false, maxAttempts: 3, wantCalls: 1, wantErr: errFatal},
+	}
+	for _, tc := range tests {
+		virtual := clock.NewVirtual(time.Unix(1700000000, 0))
+		start := virtual.Now()
+		calls := 0
+		done := make(chan error, 1)
+		go func() {
+			done <- Policy{Base: 10 * time.Millisecond, Max: 50 * time.Millisecond}.Retry(context.Background(), virtual, "op", tc.maxAttempts, func() (bool, error) {
+				err := tc.results[calls]
+				calls++
+				return tc.retryable, err
+			})
+		}()
+		var err error
+	wait:
+		for {
+			select {
+			case err = <-done:
+				break wait
+			default:
+				if virtual.Waiters() > 0 {
+					virtual.Advance(10 * time.Millisecond)
+				}
+			}
+		}
+		if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) {
+			t.Fatalf("%s: expected error %v, got %v", tc.name, tc.wantErr, err)
+		}
+		if calls != tc.wantCalls {
+			t.Fatalf("%s: expected %d calls, got %d", tc.name, tc.wantCalls, calls)
+		}
+		if waited := virtual.Now().Sub(start); waited != tc.wantWaited {
+			t.Fatalf("%s: expected %s waited on the clock, got %s", tc.name, tc.wantWaited, waited)
+		}
+	}
+}
+
+func TestRetryStopsWaitingWhenContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	virtual := clock.NewVirtual(time.Unix(1700000000, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	errTransient := errors.New("transient")
+	calls := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- Policy{Base: time.Second, Max: time.Second}.Retry(ctx, virtual, "op", 3, func() (bool, error) {
+			calls++
+			return true, errTransient
+		})
+	}()
+	virtual.BlockUntil(1)
+	cancel()
+	err := <-done
+	if !errors.Is(err, errTransient) || calls != 1 {
+		t.Fatalf("expected the retry wait to end with the last error after one call, got %v (calls=%d)", err, calls)
+	}
+}
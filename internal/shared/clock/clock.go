@@ -0,0 +1,98 @@
+package clock
+
+import "time"
+
+// Clock is the time source for runtime components with deadlines, timeouts, and periodic work.
+// Production code uses Real; tests drive a Virtual clock so timing paths run deterministically
+// without sleeping. Components that only read the time keep a plain `Now func() time.Time` and
+// accept a Clock's Now method.
+type Clock interface {
+	Now() time.Time
+	// NewTimer fires once after d.
+	NewTimer(d time.Duration) Timer
+	// NewTicker fires every d until stopped; d must be >0.
+	NewTicker(d time.Duration) Ticker
+	// After is NewTimer(d).C().
+	After(d time.Duration) <-chan time.Time
+	// Sleep blocks until d has elapsed on this clock.
+	Sleep(d time.Duration)
+}
+
+// Timer mirrors time.Timer behind an interface.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker mirrors time.Ticker behind an interface.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real returns the process wall clock.
+func Real() Clock {
+	return realClock{now: time.Now}
+}
+
+// WithNow returns a clock reading time from now with real timers, for callers that inject a
+// fixed or offset wall clock but still need periodic work to run. A nil now returns Real.
+func WithNow(now func() time.Time) Clock {
+	if now == nil {
+		return Real()
+	}
+	return realClock{now: now}
+}
+
+type realClock struct {
+	now func() time.Time
+}
+
+func (c realClock) Now() time.Time {
+	return c.now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{timer: time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{ticker: time.NewTicker(d)}
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+func (t realTimer) Reset(d time.Duration) bool {
+	return t.timer.Reset(d)
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t realTicker) Stop() {
+	t.ticker.Stop()
+}
@@ -0,0 +1,181 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Virtual is a Clock that only moves when a test advances it. Timers and tickers fire in
+// deadline order as Advance passes them, with Now reading each deadline as it fires, so
+// timeout, backoff, and periodic paths run instantly and deterministically.
+type Virtual struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	seq     uint64
+	waiters map[*virtualWaiter]struct{}
+}
+
+// virtualWaiter is one pending timer, ticker, or sleep.
+type virtualWaiter struct {
+	clock  *Virtual
+	at     time.Time
+	seq    uint64
+	period time.Duration
+	ch     chan time.Time
+}
+
+// NewVirtual returns a virtual clock reading start.
+func NewVirtual(start time.Time) *Virtual {
+	v := &Virtual{now: start, waiters: make(map[*virtualWaiter]struct{})}
+	v.changed = sync.NewCond(&v.mu)
+	return v
+}
+
+func (v *Virtual) Now() time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.now
+}
+
+func (v *Virtual) NewTimer(d time.Duration) Timer {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	w := &virtualWaiter{clock: v, ch: make(chan time.Time, 1)}
+	v.scheduleLocked(w, d)
+	return w
+}
+
+// NewTicker panics on a non-positive interval, like time.NewTicker.
+func (v *Virtual) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	w := &virtualWaiter{clock: v, period: d, ch: make(chan time.Time, 1)}
+	v.scheduleLocked(w, d)
+	return virtualTicker{waiter: w}
+}
+
+func (v *Virtual) After(d time.Duration) <-chan time.Time {
+	return v.NewTimer(d).C()
+}
+
+func (v *Virtual) Sleep(d time.Duration) {
+	<-v.After(d)
+}
+
+// Advance moves the clock forward by d, firing every timer and ticker that comes due on the
+// way. Like their real counterparts, a ticker whose last tick was not received drops ticks.
+func (v *Virtual) Advance(d time.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	target := v.now.Add(d)
+	for {
+		next := v.nextDueLocked(target)
+		if next == nil {
+			break
+		}
+		v.now = next.at
+		select {
+		case next.ch <- next.at:
+		default:
+		}
+		if next.period > 0 {
+			next.at = next.at.Add(next.period)
+			v.seq++
+			next.seq = v.seq
+		} else {
+			delete(v.waiters, next)
+		}
+	}
+	if target.After(v.now) {
+		v.now = target
+	}
+	v.changed.Broadcast()
+}
+
+// Waiters reports the pending timers, tickers, and sleeps.
+func (v *Virtual) Waiters() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.waiters)
+}
+
+// BlockUntil waits until at least n timers, tickers, or sleeps are pending, so a test can
+// advance only after the code under test has armed its deadline.
+func (v *Virtual) BlockUntil(n int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for len(v.waiters) < n {
+		v.changed.Wait()
+	}
+}
+
+// scheduleLocked arms w to fire d from now; a non-positive d fires immediately.
+func (v *Virtual) scheduleLocked(w *virtualWaiter, d time.Duration) {
+	if d <= 0 && w.period == 0 {
+		select {
+		case w.ch <- v.now:
+		default:
+		}
+		delete(v.waiters, w)
+		return
+	}
+	v.seq++
+	w.at = v.now.Add(d)
+	w.seq = v.seq
+	v.waiters[w] = struct{}{}
+	v.changed.Broadcast()
+}
+
+// nextDueLocked returns the earliest waiter due by target, breaking ties in arming order.
+func (v *Virtual) nextDueLocked(target time.Time) *virtualWaiter {
+	var next *virtualWaiter
+	for w := range v.waiters {
+		if w.at.After(target) {
+			continue
+		}
+		if next == nil || w.at.Before(next.at) || (w.at.Equal(next.at) && w.seq < next.seq) {
+			next = w
+		}
+	}
+	return next
+}
+
+func (w *virtualWaiter) C() <-chan time.Time {
+	return w.ch
+}
+
+// Stop disarms the timer or ticker, reporting whether it was pending.
+func (w *virtualWaiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	_, pending := w.clock.waiters[w]
+	delete(w.clock.waiters, w)
+	w.clock.changed.Broadcast()
+	return pending
+}
+
+// Reset re-arms the timer to fire d from now, reporting whether it was pending.
+func (w *virtualWaiter) Reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	_, pending := w.clock.waiters[w]
+	w.clock.scheduleLocked(w, d)
+	return pending
+}
+
+// virtualTicker adapts a periodic waiter to Ticker.
+type virtualTicker struct {
+	waiter *virtualWaiter
+}
+
+func (t virtualTicker) C() <-chan time.Time {
+	return t.waiter.ch
+}
+
+func (t virtualTicker) Stop() {
+	t.waiter.Stop()
+}
@@ -0,0 +1,130 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var virtualStart = time.UnixMilli(1_000_000)
+
+func TestVirtualFiresTimersInDeadlineOrder(t *testing.T) {
+	t.Parallel()
+
+	v := NewVirtual(virtualStart)
+	late := v.NewTimer(300 * time.Millisecond)
+	early := v.NewTimer(100 * time.Millisecond)
+	stopped := v.NewTimer(200 * time.Millisecond)
+	if !stopped.Stop() || stopped.Stop() {
+		t.Fatalf("expected Stop to report the timer pending exactly once")
+	}
+
+	v.Advance(150 * time.Millisecond)
+	select {
+	case fired := <-early.C():
+		if !fired.Equal(virtualStart.Add(100 * time.Millisecond)) {
+			t.Fatalf("expected the early timer to fire at its deadline, got %v", fired)
+		}
+	default:
+		t.Fatalf("expected the early timer to fire")
+	}
+	select {
+	case <-late.C():
+		t.Fatalf("expected the late timer to stay pending")
+	default:
+	}
+	if got := v.Now(); !got.Equal(virtualStart.Add(150 * time.Millisecond)) {
+		t.Fatalf("expected now at the advance target, got %v", got)
+	}
+	if v.Waiters() != 1 {
+		t.Fatalf("expected one pending timer, got %d", v.Waiters())
+	}
+
+	if !late.Reset(50 * time.Millisecond) {
+		t.Fatalf("expected Reset to report the late timer pending")
+	}
+	v.Advance(50 * time.Millisecond)
+	if fired := <-late.C(); !fired.Equal(virtualStart.Add(200 * time.Millisecond)) {
+		t.Fatalf("expected the reset timer to fire 50ms after reset, got %v", fired)
+	}
+}
+
+func TestVirtualTickerDropsUnreceivedTicks(t *testing.T) {
+	t.Parallel()
+
+	v := NewVirtual(virtualStart)
+	ticker := v.NewTicker(time.Second)
+	v.Advance(3500 * time.Millisecond)
+	if tick := <-ticker.C(); !tick.Equal(virtualStart.Add(time.Second)) {
+		t.Fatalf("expected the first undelivered tick to be kept, got %v", tick)
+	}
+	select {
+	case tick := <-ticker.C():
+		t.Fatalf("expected later ticks to be dropped, got %v", tick)
+	default:
+	}
+	v.Advance(500 * time.Millisecond)
+	if tick := <-ticker.C(); !tick.Equal(virtualStart.Add(4 * time.Second)) {
+		t.Fatalf("expected the ticker to stay on its period, got %v", tick)
+	}
+	ticker.Stop()
+	if v.Waiters() != 0 {
+		t.Fatalf("expected a stopped ticker to disarm, got %d waiters", v.Waiters())
+	}
+}
+
+func TestVirtualSleepWakesOnAdvance(t *testing.T) {
+	t.Parallel()
+
+	v := NewVirtual(virtualStart)
+	woke := make(chan time.Time, 1)
+	go func() {
+		v.Sleep(time.Minute)
+		woke <- v.Now()
+	}()
+	v.BlockUntil(1)
+	v.Advance(59 * time.Second)
+	select {
+	case <-woke:
+		t.Fatalf("expected the sleeper to wait for the full minute")
+	default:
+	}
+	v.Advance(time.Second)
+	if at := <-woke; !at.Equal(virtualStart.Add(time.Minute)) {
+		t.Fatalf("expected the sleeper to wake after one virtual minute, got %v", at)
+	}
+}
+
+func TestVirtualNonPositiveTimerFiresImmediately(t *testing.T) {
+	t.Parallel()
+
+	v := NewVirtual(virtualStart)
+	select {
+	case fired := <-v.After(0):
+		if !fired.Equal(virtualStart) {
+			t.Fatalf("expected an immediate fire at now, got %v", fired)
+		}
+	default:
+		t.Fatalf("expected a zero-duration timer to fire without advancing")
+	}
+	if v.Waiters() != 0 {
+		t.Fatalf("expected no pending waiters, got %d", v.Waiters())
+	}
+}
+
+func TestWithNowKeepsRealTimers(t *testing.T) {
+	t.Parallel()
+
+	fixed := virtualStart
+	c := WithNow(func() time.Time { return fixed })
+	if !c.Now().Equal(fixed) {
+		t.Fatalf("expected the injected now, got %v", c.Now())
+	}
+	select {
+	case <-c.After(time.Millisecond):
+	case <-time.After(time.Second):
+		t.Fatalf("expected a real timer to fire")
+	}
+	if _, ok := WithNow(nil).(realClock); !ok {
+		t.Fatalf("expected a nil now to fall back to the real clock")
+	}
+}
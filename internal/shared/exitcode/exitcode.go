@@ -0,0 +1,109 @@
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code is a process exit code from the scheme every rspp binary shares, so CI can branch on
+// the class of a failure without parsing its message.
+type Code int
+
+const (
+	// Success means the command completed and every gate it evaluated passed.
+	Success Code = 0
+	// GateFailure means the command ran to completion and a gate it evaluated failed.
+	GateFailure Code = 1
+	// UsageError means the command line was invalid: an unknown command, a bad flag, or a
+	// missing or malformed argument.
+	UsageError Code = 2
+	// InfrastructureError means the command could not run to completion, for example because
+	// an input could not be read or an artifact could not be written.
+	InfrastructureError Code = 3
+	// Partial means the command completed with some work failed or a gate waived, such as a
+	// batch with failed inputs or a break-glass release.
+	Partial Code = 4
+)
+
+// String returns the code's name as documented in the exit-code scheme.
+func (c Code) String() string {
+	switch c {
+	case Success:
+		return "success"
+	case GateFailure:
+		return "gate_failure"
+	case UsageError:
+		return "usage_error"
+	case InfrastructureError:
+		return "infrastructure_error"
+	case Partial:
+		return "partial"
+	default:
+		return fmt.Sprintf("exit_code_%d", int(c))
+	}
+}
+
+// Error classifies err with an exit code. It wraps err, so errors.Is and errors.As still
+// reach the cause.
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Gate classifies err as a gate failure; nil stays nil.
+func Gate(err error) error {
+	return classify(GateFailure, err)
+}
+
+// Usage classifies err as a usage error; nil stays nil.
+func Usage(err error) error {
+	return classify(UsageError, err)
+}
+
+// Infrastructure classifies err as an infrastructure error; nil stays nil.
+func Infrastructure(err error) error {
+	return classify(InfrastructureError, err)
+}
+
+// PartialResult classifies err as a partial or waived result; nil stays nil.
+func PartialResult(err error) error {
+	return classify(Partial, err)
+}
+
+// Gatef formats a gate failure.
+func Gatef(format string, args ...any) error {
+	return Gate(fmt.Errorf(format, args...))
+}
+
+// Usagef formats a usage error.
+func Usagef(format string, args ...any) error {
+	return Usage(fmt.Errorf(format, args...))
+}
+
+// For returns the exit code for err: Success for nil, the outermost classification in err's
+// chain, and InfrastructureError for an unclassified error.
+func For(err error) Code {
+	if err == nil {
+		return Success
+	}
+	var classified *Error
+	if errors.As(err, &classified) {
+		return classified.Code
+	}
+	return InfrastructureError
+}
+
+func classify(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
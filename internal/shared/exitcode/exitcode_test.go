@@ -0,0 +1,46 @@
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+)
+
+func TestForMapsFailureClasses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{name: "success", err: nil, want: Success},
+		{name: "gate failure", err: Gatef("slo gate failed: %d violations", 2), want: GateFailure},
+		{name: "usage error", err: Usagef("unsupported command %q", "nope"), want: UsageError},
+		{name: "infrastructure error", err: Infrastructure(fs.ErrNotExist), want: InfrastructureError},
+		{name: "partial result", err: PartialResult(errors.New("2 of 5 inputs failed")), want: Partial},
+		{name: "unclassified defaults to infrastructure", err: errors.New("read report: permission denied"), want: InfrastructureError},
+		{name: "classification survives wrapping", err: fmt.Errorf("batch: %w", Usagef("-job is required")), want: UsageError},
+	}
+	for _, tc := range tests {
+		if got := For(tc.err); got != tc.want {
+			t.Fatalf("%s: expected %s (%d), got %s (%d)", tc.name, tc.want, tc.want, got, got)
+		}
+	}
+}
+
+func TestClassifiedErrorsKeepTheirCause(t *testing.T) {
+	t.Parallel()
+
+	err := Infrastructure(fmt.Errorf("open spec: %w", fs.ErrNotExist))
+	if !errors.Is(err, fs.ErrNotExist) || err.Error() != "open spec: file does not exist" {
+		t.Fatalf("expected the cause and message preserved, got %v", err)
+	}
+	if Gate(nil) != nil || Usage(nil) != nil || Infrastructure(nil) != nil || PartialResult(nil) != nil {
+		t.Fatalf("expected nil errors to stay nil")
+	}
+	if Code(9).String() != "exit_code_9" || Partial.String() != "partial" {
+		t.Fatalf("unexpected code names")
+	}
+}
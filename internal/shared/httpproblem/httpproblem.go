@@ -0,0 +1,197 @@
+package httpproblem
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+)
+
+const (
+	// ContentType is the RFC 9457 problem details media type.
+	ContentType = "application/problem+json"
+	// EnvStatusMap names a JSON file of Overrides applied on top of DefaultMapping.
+	EnvStatusMap = "RSPP_HTTP_STATUS_MAP"
+
+	typePrefix = "urn:rspp:problem:"
+)
+
+// Problem is an RFC 9457 problem details body. Code is the machine-readable error code clients
+// branch on; Message repeats Detail for clients of the older {code,message} error body.
+type Problem struct {
+	Type        string                   `json:"type"`
+	Title       string                   `json:"title"`
+	Status      int                      `json:"status"`
+	Detail      string                   `json:"detail,omitempty"`
+	Code        string                   `json:"code"`
+	Message     string                   `json:"message,omitempty"`
+	Retryable   bool                     `json:"retryable,omitempty"`
+	OutcomeKind controlplane.OutcomeKind `json:"outcome_kind,omitempty"`
+}
+
+// Mapping maps error codes to HTTP statuses and DecisionOutcome kinds to error codes, so every
+// HTTP surface answers the same failure with the same status and body.
+type Mapping struct {
+	codes    map[string]int
+	outcomes map[controlplane.OutcomeKind]string
+}
+
+// Overrides replaces entries of a mapping. Codes maps an error code, possibly a new one, to an
+// HTTP status; Outcomes maps a DecisionOutcome kind to an error code, or to "" when the outcome
+// is not a failure.
+type Overrides struct {
+	Codes    map[string]int                      `json:"codes,omitempty"`
+	Outcomes map[controlplane.OutcomeKind]string `json:"outcomes,omitempty"`
+}
+
+// DefaultMapping is the mapping controlplaneclient's status classification expects.
+func DefaultMapping() Mapping {
+	return Mapping{
+		codes: map[string]int{
+			controlplaneclient.CodeInvalidRequest: http.StatusBadRequest,
+			controlplaneclient.CodeUnauthorized:   http.StatusUnauthorized,
+			controlplaneclient.CodeForbidden:      http.StatusForbidden,
+			controlplaneclient.CodeNotFound:       http.StatusNotFound,
+			controlplaneclient.CodeConflict:       http.StatusConflict,
+			controlplaneclient.CodeRateLimited:    http.StatusTooManyRequests,
+			controlplaneclient.CodeUnavailable:    http.StatusServiceUnavailable,
+			controlplaneclient.CodeInternal:       http.StatusInternalServerError,
+		},
+		outcomes: map[controlplane.OutcomeKind]string{
+			controlplane.OutcomeAdmit: "",
+			// Admission policy refused the work outright; retrying unchanged will not help.
+			controlplane.OutcomeReject: controlplaneclient.CodeForbidden,
+			// Deferred work may be admitted later; shed work was dropped under overload.
+			controlplane.OutcomeDefer: controlplaneclient.CodeUnavailable,
+			controlplane.OutcomeShed:  controlplaneclient.CodeRateLimited,
+			// A tenant budget cap stopped the work; the caller is over its allowance, as with shed.
+			controlplane.OutcomeBudgetExceeded: controlplaneclient.CodeRateLimited,
+			// The caller holds a stale or revoked authority epoch and must re-resolve its route.
+			controlplane.OutcomeStaleEpochReject: controlplaneclient.CodeConflict,
+			controlplane.OutcomeDeauthorized:     controlplaneclient.CodeConflict,
+		},
+	}
+}
+
+// MappingFromEnv returns DefaultMapping with the overrides file named by EnvStatusMap applied.
+func MappingFromEnv() (Mapping, error) {
+	path := strings.TrimSpace(os.Getenv(EnvStatusMap))
+	if path == "" {
+		return DefaultMapping(), nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Mapping{}, fmt.Errorf("read %s: %w", EnvStatusMap, err)
+	}
+	var overrides Overrides
+	if err := json.Unmarshal(raw, &overrides); err != nil {
+		return Mapping{}, fmt.Errorf("decode %s %s: %w", EnvStatusMap, path, err)
+	}
+	return DefaultMapping().With(overrides)
+}
+
+// With returns a copy of m with overrides applied. Statuses must be 4xx or 5xx, outcome kinds
+// must be known, and outcomes must map to a code the result defines.
+func (m Mapping) With(overrides Overrides) (Mapping, error) {
+	out := Mapping{
+		codes:    make(map[string]int, len(m.codes)+len(overrides.Codes)),
+		outcomes: make(map[controlplane.OutcomeKind]string, len(m.outcomes)),
+	}
+	for code, status := range m.codes {
+		out.codes[code] = status
+	}
+	for kind, code := range m.outcomes {
+		out.outcomes[kind] = code
+	}
+	for code, status := range overrides.Codes {
+		if strings.TrimSpace(code) == "" {
+			return Mapping{}, fmt.Errorf("status override requires a non-empty code")
+		}
+		if status < 400 || status > 599 {
+			return Mapping{}, fmt.Errorf("code %s: status %d is not a 4xx or 5xx status", code, status)
+		}
+		out.codes[code] = status
+	}
+	for _, kind := range sortedKinds(overrides.Outcomes) {
+		code := overrides.Outcomes[kind]
+		if _, ok := m.outcomes[kind]; !ok {
+			return Mapping{}, fmt.Errorf("unknown outcome_kind %q", kind)
+		}
+		if _, ok := out.codes[code]; code != "" && !ok {
+			return Mapping{}, fmt.Errorf("outcome_kind %s: code %q has no status", kind, code)
+		}
+		out.outcomes[kind] = code
+	}
+	return out, nil
+}
+
+// Status returns the HTTP status for code; unknown codes are internal errors.
+func (m Mapping) Status(code string) int {
+	if status, ok := m.codes[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// OutcomeCode returns the error code a DecisionOutcome kind surfaces as, or false when the
+// outcome is not a failure.
+func (m Mapping) OutcomeCode(kind controlplane.OutcomeKind) (string, bool) {
+	code, ok := m.outcomes[kind]
+	return code, ok && code != ""
+}
+
+// Problem builds the problem details body for code. Unknown codes keep their code string but
+// carry the internal-error status; statuses controlplaneclient retries are marked retryable.
+func (m Mapping) Problem(code string, detail string) Problem {
+	status := m.Status(code)
+	return Problem{
+		Type:      typePrefix + code,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Code:      code,
+		Message:   detail,
+		Retryable: retryableStatus(status),
+	}
+}
+
+// OutcomeProblem builds the problem details body for a failed DecisionOutcome, with its reason
+// as detail. It returns false for outcomes that are not failures.
+func (m Mapping) OutcomeProblem(outcome controlplane.DecisionOutcome) (Problem, bool) {
+	code, ok := m.OutcomeCode(outcome.OutcomeKind)
+	if !ok {
+		return Problem{}, false
+	}
+	problem := m.Problem(code, outcome.Reason)
+	problem.OutcomeKind = outcome.OutcomeKind
+	return problem, true
+}
+
+// Write sends problem as an application/problem+json response.
+func Write(w http.ResponseWriter, problem Problem) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+func sortedKinds(outcomes map[controlplane.OutcomeKind]string) []controlplane.OutcomeKind {
+	kinds := make([]controlplane.OutcomeKind, 0, len(outcomes))
+	for kind := range outcomes {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	return kinds
+}
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
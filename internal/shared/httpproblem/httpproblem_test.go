@@ -0,0 +1,155 @@
+package httpproblem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/controlplaneclient"
+)
+
+func TestDefaultMappingMatchesClientClassification(t *testing.T) {
+	t.Parallel()
+
+	mapping := DefaultMapping()
+	tests := []struct {
+		name      string
+		code      string
+		wantCause error
+	}{
+		{name: "invalid request", code: controlplaneclient.CodeInvalidRequest, wantCause: controlplaneclient.ErrInvalidRequest},
+		{name: "unauthorized", code: controlplaneclient.CodeUnauthorized, wantCause: controlplaneclient.ErrUnauthorized},
+		{name: "forbidden", code: controlplaneclient.CodeForbidden, wantCause: controlplaneclient.ErrUnauthorized},
+		{name: "not found", code: controlplaneclient.CodeNotFound, wantCause: controlplaneclient.ErrNotFound},
+		{name: "conflict", code: controlplaneclient.CodeConflict, wantCause: controlplaneclient.ErrConflict},
+		{name: "rate limited", code: controlplaneclient.CodeRateLimited, wantCause: controlplaneclient.ErrUnavailable},
+		{name: "unavailable", code: controlplaneclient.CodeUnavailable, wantCause: controlplaneclient.ErrUnavailable},
+		{name: "internal", code: controlplaneclient.CodeInternal},
+		{name: "unknown code", code: "quota_exhausted"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				Write(w, mapping.Problem(tc.code, "detail for "+tc.name))
+			}))
+			defer server.Close()
+			client, err := controlplaneclient.New(controlplaneclient.Config{BaseURL: server.URL, RetryMaxAttempts: 1})
+			if err != nil {
+				t.Fatalf("%s: unexpected client error: %v", tc.name, err)
+			}
+			_, err = client.RollbackPipeline(t.Context(), controlplaneclient.RollbackPipelineRequest{})
+			var apiErr *controlplaneclient.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("%s: expected APIError, got %v", tc.name, err)
+			}
+			problem := mapping.Problem(tc.code, "")
+			if apiErr.Code != tc.code || apiErr.Status != problem.Status || apiErr.Message != "detail for "+tc.name || apiErr.Retryable != problem.Retryable {
+				t.Fatalf("%s: expected client to read back %+v, got %+v", tc.name, problem, apiErr)
+			}
+			if tc.wantCause != nil && !errors.Is(err, tc.wantCause) {
+				t.Fatalf("%s: expected %v, got %v", tc.name, tc.wantCause, err)
+			}
+		})
+	}
+}
+
+func TestOutcomeProblems(t *testing.T) {
+	t.Parallel()
+
+	mapping := DefaultMapping()
+	tests := []struct {
+		name       string
+		kind       controlplane.OutcomeKind
+		wantStatus int
+	}{
+		{name: "admit", kind: controlplane.OutcomeAdmit},
+		{name: "reject", kind: controlplane.OutcomeReject, wantStatus: http.StatusForbidden},
+		{name: "defer", kind: controlplane.OutcomeDefer, wantStatus: http.StatusServiceUnavailable},
+		{name: "shed", kind: controlplane.OutcomeShed, wantStatus: http.StatusTooManyRequests},
+		{name: "budget exceeded", kind: controlplane.OutcomeBudgetExceeded, wantStatus: http.StatusTooManyRequests},
+		{name: "stale epoch", kind: controlplane.OutcomeStaleEpochReject, wantStatus: http.StatusConflict},
+		{name: "deauthorized", kind: controlplane.OutcomeDeauthorized, wantStatus: http.StatusConflict},
+	}
+	for _, tc := range tests {
+		problem, ok := mapping.OutcomeProblem(controlplane.DecisionOutcome{OutcomeKind: tc.kind, Reason: "policy said so"})
+		if tc.wantStatus == 0 {
+			if ok {
+				t.Fatalf("%s: expected no problem, got %+v", tc.name, problem)
+			}
+			continue
+		}
+		if !ok || problem.Status != tc.wantStatus || problem.OutcomeKind != tc.kind || problem.Detail != "policy said so" || problem.Type != typePrefix+problem.Code {
+			t.Fatalf("%s: expected status %d, got %+v ok=%v", tc.name, tc.wantStatus, problem, ok)
+		}
+	}
+}
+
+func TestMappingOverrides(t *testing.T) {
+	t.Parallel()
+
+	mapping, err := DefaultMapping().With(Overrides{
+		Codes:    map[string]int{"quota_exhausted": http.StatusPaymentRequired},
+		Outcomes: map[controlplane.OutcomeKind]string{controlplane.OutcomeReject: "quota_exhausted", controlplane.OutcomeDefer: ""},
+	})
+	if err != nil {
+		t.Fatalf("unexpected override error: %v", err)
+	}
+	if problem, ok := mapping.OutcomeProblem(controlplane.DecisionOutcome{OutcomeKind: controlplane.OutcomeReject}); !ok || problem.Status != http.StatusPaymentRequired || problem.Code != "quota_exhausted" {
+		t.Fatalf("expected reject to surface as quota_exhausted, got %+v ok=%v", problem, ok)
+	}
+	if _, ok := mapping.OutcomeCode(controlplane.OutcomeDefer); ok {
+		t.Fatalf("expected defer override to clear its code")
+	}
+	if DefaultMapping().Status("quota_exhausted") != http.StatusInternalServerError {
+		t.Fatalf("expected overrides not to change the default mapping")
+	}
+
+	tests := []struct {
+		name      string
+		overrides Overrides
+	}{
+		{name: "success status", overrides: Overrides{Codes: map[string]int{controlplaneclient.CodeConflict: http.StatusOK}}},
+		{name: "empty code", overrides: Overrides{Codes: map[string]int{" ": http.StatusTeapot}}},
+		{name: "unknown outcome", overrides: Overrides{Outcomes: map[controlplane.OutcomeKind]string{"postpone": controlplaneclient.CodeUnavailable}}},
+		{name: "undefined code", overrides: Overrides{Outcomes: map[controlplane.OutcomeKind]string{controlplane.OutcomeShed: "overloaded"}}},
+	}
+	for _, tc := range tests {
+		if _, err := DefaultMapping().With(tc.overrides); err == nil {
+			t.Fatalf("%s: expected override error", tc.name)
+		}
+	}
+}
+
+func TestMappingFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status-map.json")
+	raw, err := json.Marshal(Overrides{Codes: map[string]int{controlplaneclient.CodeConflict: http.StatusPreconditionFailed}})
+	if err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	}
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+
+	t.Setenv(EnvStatusMap, path)
+	mapping, err := MappingFromEnv()
+	if err != nil || mapping.Status(controlplaneclient.CodeConflict) != http.StatusPreconditionFailed {
+		t.Fatalf("expected conflict override from %s, got %d err=%v", EnvStatusMap, mapping.Status(controlplaneclient.CodeConflict), err)
+	}
+
+	t.Setenv(EnvStatusMap, filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := MappingFromEnv(); err == nil {
+		t.Fatalf("expected missing overrides file to fail")
+	}
+
+	t.Setenv(EnvStatusMap, "")
+	if mapping, err := MappingFromEnv(); err != nil || mapping.Status(controlplaneclient.CodeConflict) != http.StatusConflict {
+		t.Fatalf("expected default mapping without %s, got err=%v", EnvStatusMap, err)
+	}
+}
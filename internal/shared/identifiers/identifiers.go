@@ -0,0 +1,218 @@
+package identifiers
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind names the identifier family an ID belongs to.
+type Kind string
+
+const (
+	KindSession Kind = "session"
+	KindTurn    Kind = "turn"
+	KindEvent   Kind = "event"
+)
+
+// MaxLength bounds every identifier so IDs stay usable as file names and join keys.
+const MaxLength = 128
+
+var kinds = []Kind{KindSession, KindTurn, KindEvent}
+
+// Field returns the contract field name IDs of the kind are carried in.
+func (k Kind) Field() string {
+	return string(k) + "_id"
+}
+
+// Prefix returns the prefix generated IDs of the kind carry before their ULID.
+func (k Kind) Prefix() string {
+	switch k {
+	case KindSession:
+		return "sess_"
+	case KindTurn:
+		return "turn_"
+	case KindEvent:
+		return "evt_"
+	default:
+		return ""
+	}
+}
+
+// Generator issues prefixed ULID identifiers. IDs from one generator sort by issue order:
+// within a millisecond the random component is incremented instead of redrawn.
+type Generator struct {
+	now     func() time.Time
+	entropy io.Reader
+
+	mu     sync.Mutex
+	lastMS int64
+	last   ULID
+}
+
+// NewGenerator returns a generator over clock and entropy; nil selects time.Now and crypto/rand.
+func NewGenerator(clock func() time.Time, entropy io.Reader) *Generator {
+	if clock == nil {
+		clock = time.Now
+	}
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+	return &Generator{now: clock, entropy: entropy, lastMS: -1}
+}
+
+var defaultGenerator = NewGenerator(nil, nil)
+
+// NewSessionID issues a session ID from the process-wide generator.
+func NewSessionID() (string, error) {
+	return defaultGenerator.New(KindSession)
+}
+
+// NewTurnID issues a turn ID from the process-wide generator.
+func NewTurnID() (string, error) {
+	return defaultGenerator.New(KindTurn)
+}
+
+// NewEventID issues an event ID from the process-wide generator.
+func NewEventID() (string, error) {
+	return defaultGenerator.New(KindEvent)
+}
+
+// New issues an ID of kind.
+func (g *Generator) New(kind Kind) (string, error) {
+	if kind.Prefix() == "" {
+		return "", fmt.Errorf("unsupported identifier kind %q", kind)
+	}
+	id, err := g.NewULID()
+	if err != nil {
+		return "", err
+	}
+	return kind.Prefix() + id.String(), nil
+}
+
+// NewULID issues a ULID that sorts after every ULID this generator issued before.
+func (g *Generator) NewULID() (ULID, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := g.now().UnixMilli()
+	if ms < 0 || ms > maxULIDTime {
+		return ULID{}, fmt.Errorf("ulid timestamp %d is out of range", ms)
+	}
+	var id ULID
+	if ms <= g.lastMS {
+		id = g.last
+		if !incrementEntropy(&id) {
+			return ULID{}, fmt.Errorf("ulid entropy exhausted within millisecond %d", g.lastMS)
+		}
+	} else {
+		if _, err := io.ReadFull(g.entropy, id[6:]); err != nil {
+			return ULID{}, fmt.Errorf("read ulid entropy: %w", err)
+		}
+		putTime(&id, ms)
+		g.lastMS = ms
+	}
+	g.last = id
+	return id, nil
+}
+
+// Validate checks that id is a well-formed identifier of kind. Free-form and derived IDs (such as
+// "<session_id>-turn-1") remain valid when they use the portable character set; an ID shaped like
+// a generated one, a kind prefix followed by exactly 26 alphanumerics, must carry the prefix of
+// its own kind and a valid ULID.
+func Validate(kind Kind, id string) error {
+	field := kind.Field()
+	if id == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+	if len(id) > MaxLength {
+		return fmt.Errorf("%s exceeds %d bytes", field, MaxLength)
+	}
+	for i := 0; i < len(id); i++ {
+		if !portable(id[i]) {
+			return fmt.Errorf("%s %q contains %q; allowed characters are A-Z a-z 0-9 . _ : / -", field, id, id[i])
+		}
+	}
+	for _, other := range kinds {
+		suffix, ok := strings.CutPrefix(id, other.Prefix())
+		if !ok || !generatedShape(suffix) {
+			continue
+		}
+		if other != kind {
+			return fmt.Errorf("%s %q carries the %s prefix %q", field, id, other, other.Prefix())
+		}
+		if _, err := ParseULID(suffix); err != nil {
+			return fmt.Errorf("%s %q: %w", field, id, err)
+		}
+	}
+	return nil
+}
+
+// ValidateSessionID validates a session ID.
+func ValidateSessionID(id string) error {
+	return Validate(KindSession, id)
+}
+
+// ValidateTurnID validates a turn ID.
+func ValidateTurnID(id string) error {
+	return Validate(KindTurn, id)
+}
+
+// ValidateEventID validates an event ID.
+func ValidateEventID(id string) error {
+	return Validate(KindEvent, id)
+}
+
+func generatedShape(suffix string) bool {
+	if len(suffix) != ulidLength {
+		return false
+	}
+	for i := 0; i < len(suffix); i++ {
+		if c := suffix[i]; !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func portable(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	case c == '.', c == '_', c == ':', c == '/', c == '-':
+		return true
+	default:
+		return false
+	}
+}
+
+// Index detects identifier collisions: one ID claimed by two different owners, such as two
+// artifacts that each mint the same event_id.
+type Index struct {
+	owners map[Kind]map[string]string
+}
+
+// NewIndex returns an empty collision index.
+func NewIndex() *Index {
+	return &Index{owners: map[Kind]map[string]string{}}
+}
+
+// Claim records owner for id, failing when another owner already claimed it.
+func (x *Index) Claim(kind Kind, id string, owner string) error {
+	if err := Validate(kind, id); err != nil {
+		return err
+	}
+	owners := x.owners[kind]
+	if owners == nil {
+		owners = map[string]string{}
+		x.owners[kind] = owners
+	}
+	if existing, ok := owners[id]; ok && existing != owner {
+		return fmt.Errorf("%s %q collides: already claimed by %s", kind.Field(), id, existing)
+	}
+	owners[id] = owner
+	return nil
+}
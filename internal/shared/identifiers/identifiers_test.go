@@ -0,0 +1,125 @@
+package identifiers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestULIDRoundTripsTheSpecVector(t *testing.T) {
+	t.Parallel()
+
+	id, err := ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if id.Time().UnixMilli() != 1469922850259 {
+		t.Fatalf("expected spec timestamp, got %d", id.Time().UnixMilli())
+	}
+	if id.String() != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Fatalf("expected round trip, got %s", id)
+	}
+	if lower, err := ParseULID("01arz3ndektsv4rrffq69g5fav"); err != nil || lower != id {
+		t.Fatalf("expected lower-case parse to match, got %s err=%v", lower, err)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "short", value: "01ARZ3NDEK"},
+		{name: "invalid character", value: "01ARZ3NDEKTSV4RRFFQ69G5FAU"},
+		{name: "overflow", value: "81ARZ3NDEKTSV4RRFFQ69G5FAV"},
+	}
+	for _, tc := range tests {
+		if _, err := ParseULID(tc.value); err == nil {
+			t.Fatalf("%s: expected parse error", tc.name)
+		}
+	}
+}
+
+func TestGeneratorIssuesMonotonicPrefixedIDs(t *testing.T) {
+	t.Parallel()
+
+	now := time.UnixMilli(1_700_000_000_000)
+	gen := NewGenerator(func() time.Time { return now }, bytes.NewReader(bytes.Repeat([]byte{0xff}, 10)))
+	first, err := gen.New(KindSession)
+	if err != nil || !strings.HasPrefix(first, "sess_") {
+		t.Fatalf("expected session id, got %q err=%v", first, err)
+	}
+	if err := ValidateSessionID(first); err != nil {
+		t.Fatalf("expected generated id to validate, got %v", err)
+	}
+	if _, err := gen.New(KindTurn); err == nil {
+		t.Fatalf("expected exhausted entropy within one millisecond to fail")
+	}
+
+	gen = NewGenerator(func() time.Time { return now }, nil)
+	previous := ""
+	for i := 0; i < 100; i++ {
+		id, err := gen.New(KindEvent)
+		if err != nil {
+			t.Fatalf("unexpected generate error: %v", err)
+		}
+		if id <= previous {
+			t.Fatalf("expected ids to sort by issue order, got %q after %q", id, previous)
+		}
+		previous = id
+	}
+	parsed, err := ParseULID(strings.TrimPrefix(previous, KindEvent.Prefix()))
+	if err != nil || !parsed.Time().Equal(now) {
+		t.Fatalf("expected ulid to carry the issue time, got %v err=%v", parsed.Time(), err)
+	}
+	if _, err := gen.New(Kind("tenant")); err == nil {
+		t.Fatalf("expected unsupported kind to fail")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		kind    Kind
+		id      string
+		wantErr bool
+	}{
+		{name: "free-form session", kind: KindSession, id: "sess-1"},
+		{name: "runtime event path", kind: KindEvent, id: "evt/sess-1/turn-1/000001"},
+		{name: "generated turn", kind: KindTurn, id: "turn_01ARZ3NDEKTSV4RRFFQ69G5FAV"},
+		{name: "empty", kind: KindSession, id: "", wantErr: true},
+		{name: "whitespace", kind: KindSession, id: "sess 1", wantErr: true},
+		{name: "too long", kind: KindEvent, id: strings.Repeat("e", MaxLength+1), wantErr: true},
+		{name: "other kind prefix", kind: KindTurn, id: "sess_01ARZ3NDEKTSV4RRFFQ69G5FAV", wantErr: true},
+		{name: "derived from generated session", kind: KindTurn, id: "sess_01ARZ3NDEKTSV4RRFFQ69G5FAV-turn-1"},
+		{name: "malformed ulid", kind: KindEvent, id: "evt_01ARZ3NDEKTSV4RRFFQ69G5FAU", wantErr: true},
+	}
+	for _, tc := range tests {
+		err := Validate(tc.kind, tc.id)
+		if tc.wantErr && err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+		if !tc.wantErr && err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestIndexDetectsCollisions(t *testing.T) {
+	t.Parallel()
+
+	index := NewIndex()
+	if err := index.Claim(KindEvent, "evt-1", "a.json"); err != nil {
+		t.Fatalf("unexpected claim error: %v", err)
+	}
+	if err := index.Claim(KindEvent, "evt-1", "a.json"); err != nil {
+		t.Fatalf("expected the same owner to reclaim, got %v", err)
+	}
+	if err := index.Claim(KindTurn, "evt-1", "b.json"); err != nil {
+		t.Fatalf("expected kinds to be indexed separately, got %v", err)
+	}
+	if err := index.Claim(KindEvent, "evt-1", "b.json"); err == nil || !strings.Contains(err.Error(), "a.json") {
+		t.Fatalf("expected collision naming the first owner, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package identifiers
+
+import (
+	"fmt"
+	"time"
+)
+
+// ULID is a 128-bit lexicographically sortable identifier: a 48-bit millisecond timestamp
+// followed by 80 random bits, rendered as 26 Crockford base32 characters.
+type ULID [16]byte
+
+const (
+	ulidLength  = 26
+	maxULIDTime = 1<<48 - 1
+	crockford   = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+var crockfordValues = func() [256]byte {
+	var values [256]byte
+	for i := range values {
+		values[i] = 0xff
+	}
+	for i := 0; i < len(crockford); i++ {
+		values[crockford[i]] = byte(i)
+		values[crockford[i]|0x20] = byte(i)
+	}
+	return values
+}()
+
+// String renders the canonical upper-case encoding.
+func (u ULID) String() string {
+	var out [ulidLength]byte
+	// 128 bits encode as 26 five-bit groups; the leading group holds only the top 3 bits.
+	bit := 0
+	for i := ulidLength - 1; i >= 0; i-- {
+		var value byte
+		for b := 0; b < 5; b++ {
+			if bit < 128 && u[15-bit/8]&(1<<(bit%8)) != 0 {
+				value |= 1 << b
+			}
+			bit++
+		}
+		out[i] = crockford[value]
+	}
+	return string(out[:])
+}
+
+// Time returns the millisecond timestamp the ULID was issued at.
+func (u ULID) Time() time.Time {
+	var ms int64
+	for _, b := range u[:6] {
+		ms = ms<<8 | int64(b)
+	}
+	return time.UnixMilli(ms)
+}
+
+// ParseULID decodes a 26-character Crockford base32 ULID, accepting either letter case.
+func ParseULID(s string) (ULID, error) {
+	if len(s) != ulidLength {
+		return ULID{}, fmt.Errorf("ulid must be %d characters, got %d", ulidLength, len(s))
+	}
+	if crockfordValues[s[0]] > 7 {
+		return ULID{}, fmt.Errorf("ulid %q overflows 128 bits", s)
+	}
+	var u ULID
+	bit := 0
+	for i := ulidLength - 1; i >= 0; i-- {
+		value := crockfordValues[s[i]]
+		if value == 0xff {
+			return ULID{}, fmt.Errorf("ulid %q contains invalid character %q", s, s[i])
+		}
+		for b := 0; b < 5 && bit < 128; b++ {
+			if value&(1<<b) != 0 {
+				u[15-bit/8] |= 1 << (bit % 8)
+			}
+			bit++
+		}
+	}
+	return u, nil
+}
+
+func putTime(u *ULID, ms int64) {
+	for i := 5; i >= 0; i-- {
+		u[i] = byte(ms)
+		ms >>= 8
+	}
+}
+
+// incrementEntropy adds one to the 80-bit random component, reporting false on overflow.
+func incrementEntropy(u *ULID) bool {
+	for i := 15; i >= 6; i-- {
+		u[i]++
+		if u[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
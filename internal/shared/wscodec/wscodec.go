@@ -0,0 +1,333 @@
+package wscodec
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// WebSocket opcodes (RFC 6455 section 5.2). OpText and OpBinary are the message types
+// callers read and write; control frames are handled inside Conn.
+const (
+	OpText   byte = 0x1
+	OpBinary byte = 0x2
+	opCont   byte = 0x0
+	opClose  byte = 0x8
+	opPing   byte = 0x9
+	opPong   byte = 0xA
+)
+
+const (
+	websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	// DefaultMaxMessageBytes bounds one reassembled message (about 30s of 16 kHz PCM16).
+	DefaultMaxMessageBytes = 1 << 20
+)
+
+// ErrClosed is returned by ReadMessage after the peer sends a close frame.
+var ErrClosed = errors.New("websocket closed")
+
+// HandshakeError reports a non-101 response to a client upgrade request.
+type HandshakeError struct {
+	StatusCode int
+	RetryAfter string
+}
+
+func (e *HandshakeError) Error() string {
+	return fmt.Sprintf("websocket handshake rejected with status %d", e.StatusCode)
+}
+
+// Conn is a minimal WebSocket connection: it reassembles fragmented messages, answers pings,
+// and writes unfragmented frames. Client connections mask outgoing frames and reject masked
+// incoming ones; server connections do the reverse.
+type Conn struct {
+	rwc    io.ReadWriteCloser
+	reader *bufio.Reader
+	client bool
+
+	maxMessageBytes int
+
+	writeMu sync.Mutex
+}
+
+// Upgrade completes the server side of the WebSocket handshake for r and hijacks the
+// underlying connection.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != http.MethodGet ||
+		!headerContainsToken(r.Header, "Connection", "upgrade") ||
+		!headerContainsToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "websocket upgrade required", http.StatusBadRequest)
+		return nil, fmt.Errorf("request is not a websocket upgrade")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("unsupported websocket version %q", r.Header.Get("Sec-WebSocket-Version"))
+	}
+	key := strings.TrimSpace(r.Header.Get("Sec-WebSocket-Key"))
+	if key == "" {
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, fmt.Errorf("missing Sec-WebSocket-Key")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket unsupported", http.StatusInternalServerError)
+		return nil, fmt.Errorf("response writer cannot be hijacked")
+	}
+	netConn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("hijack websocket connection: %w", err)
+	}
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + AcceptKey(key) + "\r\n\r\n"
+	if _, err := rw.WriteString(response); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	return &Conn{rwc: netConn, reader: rw.Reader, maxMessageBytes: DefaultMaxMessageBytes}, nil
+}
+
+// Dial opens a client WebSocket to a ws:// or wss:// endpoint through client, so the upgrade
+// request uses the client's transport (proxy, dial and TLS settings, connection metrics). The
+// handshake stops when ctx is done; the returned connection outlives ctx and is closed with
+// Close.
+func Dial(ctx context.Context, client *http.Client, endpoint string, header http.Header) (*Conn, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "ws", "http":
+		u.Scheme = "http"
+	case "wss", "https":
+		u.Scheme = "https"
+	default:
+		return nil, fmt.Errorf("unsupported websocket endpoint scheme %q", u.Scheme)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	// Connection: Upgrade with Upgrade: websocket also keeps net/http on HTTP/1.1.
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		_ = resp.Body.Close()
+		return nil, &HandshakeError{StatusCode: resp.StatusCode, RetryAfter: resp.Header.Get("Retry-After")}
+	}
+	rwc, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("websocket upgrade response body is not writable")
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != AcceptKey(key) {
+		_ = rwc.Close()
+		return nil, fmt.Errorf("websocket handshake returned an invalid accept key")
+	}
+	return &Conn{rwc: rwc, reader: bufio.NewReader(rwc), client: true, maxMessageBytes: DefaultMaxMessageBytes}, nil
+}
+
+// AcceptKey derives the Sec-WebSocket-Accept value for a client key.
+func AcceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// SetMaxMessageBytes bounds one reassembled incoming message; n <= 0 restores the default.
+func (c *Conn) SetMaxMessageBytes(n int) {
+	if n <= 0 {
+		n = DefaultMaxMessageBytes
+	}
+	c.maxMessageBytes = n
+}
+
+// ReadMessage returns the next complete text or binary message.
+func (c *Conn) ReadMessage() (byte, []byte, error) {
+	var (
+		messageOp byte
+		message   []byte
+	)
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			_ = c.writeFrame(opClose, nil)
+			return 0, nil, ErrClosed
+		case OpText, OpBinary:
+			if messageOp != 0 {
+				return 0, nil, fmt.Errorf("websocket data frame interrupts fragmented message")
+			}
+			messageOp = op
+		case opCont:
+			if messageOp == 0 {
+				return 0, nil, fmt.Errorf("websocket continuation without message")
+			}
+		default:
+			return 0, nil, fmt.Errorf("unsupported websocket opcode 0x%x", op)
+		}
+		if len(message)+len(payload) > c.maxMessageBytes {
+			return 0, nil, fmt.Errorf("websocket message exceeds %d bytes", c.maxMessageBytes)
+		}
+		message = append(message, payload...)
+		if fin {
+			return messageOp, message, nil
+		}
+	}
+}
+
+// WriteMessage writes one unfragmented text or binary message.
+func (c *Conn) WriteMessage(op byte, payload []byte) error {
+	if op != OpText && op != OpBinary {
+		return fmt.Errorf("unsupported websocket message opcode 0x%x", op)
+	}
+	return c.writeFrame(op, payload)
+}
+
+// Close sends a close frame and closes the connection.
+func (c *Conn) Close() error {
+	_ = c.writeFrame(opClose, nil)
+	return c.rwc.Close()
+}
+
+func (c *Conn) readFrame() (bool, byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := header[0]&0x80 != 0
+	op := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	switch {
+	case c.client && masked:
+		return false, 0, nil, fmt.Errorf("websocket server frames must not be masked")
+	case !c.client && !masked:
+		return false, 0, nil, fmt.Errorf("websocket client frames must be masked")
+	}
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > uint64(c.maxMessageBytes) {
+		return false, 0, nil, fmt.Errorf("websocket frame exceeds %d bytes", c.maxMessageBytes)
+	}
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, op, payload, nil
+}
+
+func (c *Conn) writeFrame(op byte, payload []byte) error {
+	maskBit := byte(0)
+	if c.client {
+		maskBit = 0x80
+	}
+	frame := []byte{0x80 | op}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, maskBit|126, byte(n>>8), byte(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		frame = append(frame, mask[:]...)
+		for i, b := range payload {
+			frame = append(frame, b^mask[i%4])
+		}
+		payload = nil
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if _, err := c.rwc.Write(frame); err != nil {
+		return err
+	}
+	if len(payload) == 0 {
+		return nil
+	}
+	_, err := c.rwc.Write(payload)
+	return err
+}
+
+func headerContainsToken(header http.Header, name string, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package wscodec
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDialRoundTripsWithUpgradedServer(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test" {
+			w.Header().Set("Retry-After", "3")
+			http.Error(w, "unauthorized", http.StatusTooManyRequests)
+			return
+		}
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			op, payload, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(op, append([]byte("echo:"), payload...)); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+	endpoint := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := Dial(ctx, server.Client(), endpoint, nil)
+	var rejected *HandshakeError
+	if !errors.As(err, &rejected) || rejected.StatusCode != http.StatusTooManyRequests || rejected.RetryAfter != "3" {
+		t.Fatalf("expected handshake rejection with retry-after, got %v", err)
+	}
+
+	conn, err := Dial(ctx, server.Client(), endpoint, http.Header{"Authorization": {"Bearer test"}})
+	if err != nil {
+		t.Fatalf("unexpected dial error: %v", err)
+	}
+	defer conn.Close()
+	large := strings.Repeat("a", 70000)
+	tests := []struct {
+		op      byte
+		payload string
+	}{
+		{op: OpText, payload: "hello"},
+		{op: OpBinary, payload: "\x00\x01\x02"},
+		{op: OpText, payload: large},
+	}
+	for _, tc := range tests {
+		if err := conn.WriteMessage(tc.op, []byte(tc.payload)); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+		op, payload, err := conn.ReadMessage()
+		if err != nil || op != tc.op || string(payload) != "echo:"+tc.payload {
+			t.Fatalf("expected echoed %d-byte message with opcode 0x%x, got 0x%x %d bytes err=%v", len(tc.payload), tc.op, op, len(payload), err)
+		}
+	}
+
+	conn.SetMaxMessageBytes(16)
+	if err := conn.WriteMessage(OpText, []byte(large)); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if _, _, err := conn.ReadMessage(); err == nil || !strings.Contains(err.Error(), "exceeds 16 bytes") {
+		t.Fatalf("expected oversized message to be rejected, got %v", err)
+	}
+}
+
+func TestUpgradeRejectsNonWebSocketRequests(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		wantErr string
+		status  int
+	}{
+		{name: "plain get", header: http.Header{}, status: http.StatusBadRequest, wantErr: "not a websocket upgrade"},
+		{name: "old version", header: http.Header{"Connection": {"keep-alive, Upgrade"}, "Upgrade": {"websocket"}, "Sec-Websocket-Version": {"8"}}, status: http.StatusUpgradeRequired, wantErr: "unsupported websocket version"},
+		{name: "missing key", header: http.Header{"Connection": {"Upgrade"}, "Upgrade": {"websocket"}, "Sec-Websocket-Version": {"13"}}, status: http.StatusBadRequest, wantErr: "missing Sec-WebSocket-Key"},
+	}
+	for _, tc := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		req.Header = tc.header
+		recorder := httptest.NewRecorder()
+		if _, err := Upgrade(recorder, req); err == nil || !strings.Contains(err.Error(), tc.wantErr) || recorder.Code != tc.status {
+			t.Fatalf("%s: expected %d %q, got %d %v", tc.name, tc.status, tc.wantErr, recorder.Code, err)
+		}
+	}
+}
+
+func TestAcceptKeyMatchesRFCExample(t *testing.T) {
+	t.Parallel()
+
+	if got := AcceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("expected RFC 6455 accept key, got %s", got)
+	}
+}
@@ -0,0 +1,201 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchemaVersion identifies the alerts artifact layout.
+const SchemaVersion = "rspp-alerts/v1"
+
+// Result is one rule's evaluation. Rules whose artifact or metric cannot be resolved fire
+// with their own severity and carry Error, so a missing report never passes silently.
+type Result struct {
+	RuleID      string `json:"rule_id"`
+	Severity    string `json:"severity"`
+	Description string `json:"description,omitempty"`
+	Artifact    string `json:"artifact"`
+	Metric      string `json:"metric"`
+	Comparator  string `json:"comparator"`
+	Threshold   any    `json:"threshold"`
+	Observed    any    `json:"observed,omitempty"`
+	Firing      bool   `json:"firing"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Report is the alerts artifact.
+type Report struct {
+	SchemaVersion  string         `json:"schema_version"`
+	GeneratedAtUTC string         `json:"generated_at_utc"`
+	Environment    string         `json:"environment,omitempty"`
+	RulesPath      string         `json:"rules_path,omitempty"`
+	Evaluated      int            `json:"evaluated"`
+	Firing         map[string]int `json:"firing"`
+	Results        []Result       `json:"results"`
+	// Passed is false when any critical rule fires.
+	Passed bool `json:"passed"`
+}
+
+// Evaluate runs every rule against its artifact. resolvePath maps a rule artifact path to
+// the file to read (for example, into the active environment's artifact tree); nil keeps
+// paths as written.
+func Evaluate(rules RuleSet, resolvePath func(string) string, environment string, now time.Time) (Report, error) {
+	if err := rules.Validate(); err != nil {
+		return Report{}, err
+	}
+	if resolvePath == nil {
+		resolvePath = func(path string) string { return path }
+	}
+	report := Report{
+		SchemaVersion:  SchemaVersion,
+		GeneratedAtUTC: now.UTC().Format(time.RFC3339),
+		Environment:    environment,
+		Firing:         map[string]int{},
+		Results:        make([]Result, 0, len(rules.Rules)),
+		Passed:         true,
+	}
+	artifacts := map[string]any{}
+	for _, rule := range rules.Rules {
+		result := Result{
+			RuleID:      rule.ID,
+			Severity:    rule.Severity,
+			Description: rule.Description,
+			Artifact:    resolvePath(rule.Artifact),
+			Metric:      rule.Metric,
+			Comparator:  rule.Comparator,
+			Threshold:   rule.Threshold,
+		}
+		observed, artifactPath, err := observe(result.Artifact, rule.Metric, artifacts)
+		result.Artifact = artifactPath
+		if err != nil {
+			result.Firing = true
+			result.Error = err.Error()
+		} else {
+			result.Observed = observed
+			if result.Firing, err = compare(observed, rule.Comparator, rule.Threshold); err != nil {
+				result.Firing = true
+				result.Error = err.Error()
+			}
+		}
+		if result.Firing {
+			report.Firing[rule.Severity]++
+			if rule.Severity == SeverityCritical {
+				report.Passed = false
+			}
+		}
+		report.Evaluated++
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// observe resolves the artifact (newest glob match) and reads the metric from it; decoded
+// artifacts are cached so rules sharing an artifact read it once.
+func observe(pattern string, metric string, artifacts map[string]any) (any, string, error) {
+	path, err := latestArtifact(pattern)
+	if err != nil {
+		return nil, pattern, err
+	}
+	document, ok := artifacts[path]
+	if !ok {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, path, fmt.Errorf("read artifact: %w", err)
+		}
+		if err := json.Unmarshal(raw, &document); err != nil {
+			return nil, path, fmt.Errorf("decode artifact: %w", err)
+		}
+		artifacts[path] = document
+	}
+	value, err := lookup(document, metric)
+	return value, path, err
+}
+
+func latestArtifact(pattern string) (string, error) {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return pattern, nil
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid artifact glob: %w", err)
+	}
+	latest := ""
+	var latestModTime time.Time
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if latest == "" || info.ModTime().After(latestModTime) {
+			latest, latestModTime = match, info.ModTime()
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no artifact matches %s", pattern)
+	}
+	return latest, nil
+}
+
+func lookup(document any, metric string) (any, error) {
+	current := document
+	for _, segment := range strings.Split(metric, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			next, ok := node[segment]
+			if !ok {
+				return nil, fmt.Errorf("metric %s: key %s not found", metric, segment)
+			}
+			current = next
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("metric %s: index %s out of range", metric, segment)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("metric %s: cannot descend into %s", metric, segment)
+		}
+	}
+	if current == nil {
+		return nil, fmt.Errorf("metric %s is null", metric)
+	}
+	return current, nil
+}
+
+func compare(observed any, comparator string, threshold any) (bool, error) {
+	switch value := observed.(type) {
+	case []any:
+		observed = float64(len(value))
+	case map[string]any:
+		observed = float64(len(value))
+	}
+	switch comparator {
+	case ComparatorEQ, ComparatorNE:
+		equal := fmt.Sprint(observed) == fmt.Sprint(threshold)
+		if number, ok := observed.(float64); ok {
+			want, isNumber := threshold.(float64)
+			equal = isNumber && number == want
+		}
+		return equal == (comparator == ComparatorEQ), nil
+	}
+	number, ok := observed.(float64)
+	if !ok {
+		return false, fmt.Errorf("observed value %v is not numeric", observed)
+	}
+	want := threshold.(float64)
+	switch comparator {
+	case ComparatorGT:
+		return number > want, nil
+	case ComparatorGTE:
+		return number >= want, nil
+	case ComparatorLT:
+		return number < want, nil
+	default:
+		return number <= want, nil
+	}
+}
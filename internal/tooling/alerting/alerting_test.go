@@ -0,0 +1,132 @@
+package alerting
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleRulesYAML = `# Team gate extensions.
+rules:
+  - id: slo-turn-open-p95
+    artifact: .codex/ops/slo-gates-report.json
+    metric: report.turn_open_p95_ms
+    comparator: gt
+    threshold: 120 # ms
+    severity: critical
+    description: "Turn-open p95 above budget"
+  - id: slo-violations
+    artifact: .codex/ops/slo-gates-report.json
+    metric: report.violations
+    comparator: gte
+    threshold: 1
+    severity: warning
+  - id: release-passed
+    artifact: '.codex/ops/release-*.json'
+    metric: passed
+    comparator: ne
+    threshold: true
+    severity: critical
+`
+
+func writeAlertFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestLoadRulesParsesYAMLSubsetAndJSON(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	yamlPath := filepath.Join(tmp, "rules.yaml")
+	writeAlertFile(t, yamlPath, sampleRulesYAML)
+	rules, err := LoadRules(yamlPath)
+	if err != nil {
+		t.Fatalf("unexpected yaml rules error: %v", err)
+	}
+	if len(rules.Rules) != 3 || rules.Rules[0].Threshold != 120.0 || rules.Rules[0].Description != "Turn-open p95 above budget" || rules.Rules[2].Threshold != true || rules.Rules[2].Artifact != ".codex/ops/release-*.json" {
+		t.Fatalf("expected typed yaml rules, got %+v", rules.Rules)
+	}
+
+	jsonPath := filepath.Join(tmp, "rules.json")
+	writeAlertFile(t, jsonPath, `{"rules":[{"id":"a","artifact":"a.json","metric":"passed","comparator":"eq","threshold":false,"severity":"info"}]}`)
+	if rules, err := LoadRules(jsonPath); err != nil || len(rules.Rules) != 1 {
+		t.Fatalf("expected json rules to load, got %+v (%v)", rules, err)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "missing rules key", content: "- id: a\n"},
+		{name: "unknown field", content: "rules:\n  - id: a\n    artifact: a.json\n    metric: x\n    comparator: gt\n    threshold: 1\n    severity: critical\n    owner: team\n"},
+		{name: "non-numeric threshold", content: "rules:\n  - id: a\n    artifact: a.json\n    metric: x\n    comparator: gt\n    threshold: high\n    severity: critical\n"},
+		{name: "invalid severity", content: "rules:\n  - id: a\n    artifact: a.json\n    metric: x\n    comparator: eq\n    threshold: 1\n    severity: page\n"},
+		{name: "duplicate id", content: "rules:\n  - id: a\n    artifact: a.json\n    metric: x\n    comparator: eq\n    threshold: 1\n    severity: info\n  - id: a\n    artifact: a.json\n    metric: x\n    comparator: eq\n    threshold: 1\n    severity: info\n"},
+		{name: "no rules", content: "rules:\n"},
+	}
+	for _, tc := range tests {
+		path := filepath.Join(tmp, strings.ReplaceAll(tc.name, " ", "-")+".yaml")
+		writeAlertFile(t, path, tc.content)
+		if _, err := LoadRules(path); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
+
+func TestEvaluateFiresRulesAgainstArtifacts(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	writeAlertFile(t, filepath.Join(tmp, ".codex/ops/slo-gates-report.json"), `{"report":{"turn_open_p95_ms":140,"violations":[]}}`)
+	writeAlertFile(t, filepath.Join(tmp, ".codex/ops/release-old.json"), `{"passed":false}`)
+	latest := filepath.Join(tmp, ".codex/ops/release-new.json")
+	writeAlertFile(t, latest, `{"passed":true}`)
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(latest, future, future); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	rulesPath := filepath.Join(tmp, "rules.yaml")
+	writeAlertFile(t, rulesPath, sampleRulesYAML+`  - id: missing-report
+    artifact: .codex/ops/absent.json
+    metric: passed
+    comparator: eq
+    threshold: false
+    severity: warning
+`)
+	rules, err := LoadRules(rulesPath)
+	if err != nil {
+		t.Fatalf("unexpected rules error: %v", err)
+	}
+
+	report, err := Evaluate(rules, func(path string) string { return filepath.Join(tmp, path) }, "dev", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected evaluate error: %v", err)
+	}
+	if report.Evaluated != 4 || report.Passed || report.Firing[SeverityCritical] != 1 || report.Firing[SeverityWarning] != 1 {
+		t.Fatalf("expected one critical and one warning firing, got %+v", report)
+	}
+	byID := map[string]Result{}
+	for _, result := range report.Results {
+		byID[result.RuleID] = result
+	}
+	if !byID["slo-turn-open-p95"].Firing || byID["slo-turn-open-p95"].Observed != 140.0 {
+		t.Fatalf("expected p95 rule to fire at 140, got %+v", byID["slo-turn-open-p95"])
+	}
+	if byID["slo-violations"].Firing || byID["slo-violations"].Observed == nil {
+		t.Fatalf("expected empty violations list to compare by length and not fire, got %+v", byID["slo-violations"])
+	}
+	if release := byID["release-passed"]; release.Firing || release.Artifact != latest {
+		t.Fatalf("expected newest release artifact to be evaluated, got %+v", release)
+	}
+	if missing := byID["missing-report"]; !missing.Firing || missing.Error == "" {
+		t.Fatalf("expected unresolved artifact to fire with an error, got %+v", missing)
+	}
+}
@@ -0,0 +1,193 @@
+package alerting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Severities a rule can raise; a firing critical rule fails evaluate-alerts.
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Comparators a rule can apply between the observed metric and its threshold.
+const (
+	ComparatorGT  = "gt"
+	ComparatorGTE = "gte"
+	ComparatorLT  = "lt"
+	ComparatorLTE = "lte"
+	ComparatorEQ  = "eq"
+	ComparatorNE  = "ne"
+)
+
+// Rule fires when the metric at Metric in the artifact at Artifact compares true against
+// Threshold. Metric is a dot-separated path of object keys and array indexes; arrays and
+// objects compare by length. Artifact may be a glob, in which case the newest match is used.
+type Rule struct {
+	ID          string `json:"id"`
+	Artifact    string `json:"artifact"`
+	Metric      string `json:"metric"`
+	Comparator  string `json:"comparator"`
+	Threshold   any    `json:"threshold"`
+	Severity    string `json:"severity"`
+	Description string `json:"description,omitempty"`
+}
+
+// RuleSet is the decoded rules file.
+type RuleSet struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Validate enforces rule identity, comparator, severity, and threshold typing.
+func (r Rule) Validate() error {
+	if r.ID == "" || r.Artifact == "" || r.Metric == "" {
+		return fmt.Errorf("alert rule id, artifact, and metric are required")
+	}
+	switch r.Comparator {
+	case ComparatorGT, ComparatorGTE, ComparatorLT, ComparatorLTE:
+		if _, ok := r.Threshold.(float64); !ok {
+			return fmt.Errorf("alert rule %s comparator %s requires a numeric threshold", r.ID, r.Comparator)
+		}
+	case ComparatorEQ, ComparatorNE:
+		switch r.Threshold.(type) {
+		case float64, bool, string:
+		default:
+			return fmt.Errorf("alert rule %s requires a scalar threshold", r.ID)
+		}
+	default:
+		return fmt.Errorf("alert rule %s has invalid comparator %q", r.ID, r.Comparator)
+	}
+	switch r.Severity {
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+	default:
+		return fmt.Errorf("alert rule %s has invalid severity %q", r.ID, r.Severity)
+	}
+	return nil
+}
+
+// Validate requires at least one rule and unique rule ids.
+func (s RuleSet) Validate() error {
+	if len(s.Rules) == 0 {
+		return fmt.Errorf("alert rules file defines no rules")
+	}
+	seen := make(map[string]struct{}, len(s.Rules))
+	for _, rule := range s.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+		if _, dup := seen[rule.ID]; dup {
+			return fmt.Errorf("duplicate alert rule id %s", rule.ID)
+		}
+		seen[rule.ID] = struct{}{}
+	}
+	return nil
+}
+
+// LoadRules reads a rules file. JSON documents are decoded directly; otherwise the file is
+// parsed as the block-style YAML subset rules.yaml uses: a top-level rules key holding a list
+// of flat key/value maps, with full-line comments.
+func LoadRules(path string) (RuleSet, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return RuleSet{}, fmt.Errorf("read alert rules %s: %w", path, err)
+	}
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) == 0 || trimmed[0] != '{' {
+		if raw, err = yamlRulesToJSON(raw); err != nil {
+			return RuleSet{}, fmt.Errorf("parse alert rules %s: %w", path, err)
+		}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	var rules RuleSet
+	if err := decoder.Decode(&rules); err != nil {
+		return RuleSet{}, fmt.Errorf("decode alert rules %s: %w", path, err)
+	}
+	if err := rules.Validate(); err != nil {
+		return RuleSet{}, fmt.Errorf("alert rules %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+func yamlRulesToJSON(raw []byte) ([]byte, error) {
+	items := make([]map[string]any, 0)
+	sawRules := false
+	for number, line := range strings.Split(string(raw), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "-") {
+			if trimmed != "rules:" || sawRules {
+				return nil, fmt.Errorf("line %d: expected a single top-level rules: key", number+1)
+			}
+			sawRules = true
+			continue
+		}
+		if !sawRules {
+			return nil, fmt.Errorf("line %d: entry before rules: key", number+1)
+		}
+		if rest, ok := strings.CutPrefix(trimmed, "- "); ok {
+			items = append(items, map[string]any{})
+			trimmed = strings.TrimSpace(rest)
+		} else if len(items) == 0 {
+			return nil, fmt.Errorf("line %d: rule field outside a list item", number+1)
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected key: value", number+1)
+		}
+		item := items[len(items)-1]
+		if _, dup := item[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %s", number+1, key)
+		}
+		scalar, err := yamlScalar(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number+1, err)
+		}
+		item[key] = scalar
+	}
+	if !sawRules {
+		return nil, fmt.Errorf("missing top-level rules: key")
+	}
+	return json.Marshal(map[string]any{"rules": items})
+}
+
+// yamlScalar decodes a quoted string, boolean, number, or plain string, dropping a trailing
+// comment from unquoted values.
+func yamlScalar(value string) (any, error) {
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted value %s", value)
+		}
+		return unquoted, nil
+	}
+	if strings.HasPrefix(value, "'") {
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return nil, fmt.Errorf("invalid quoted value %s", value)
+		}
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'"), nil
+	}
+	if before, _, found := strings.Cut(value, " #"); found {
+		value = strings.TrimSpace(before)
+	}
+	switch value {
+	case "":
+		return nil, fmt.Errorf("empty value")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		return number, nil
+	}
+	return value, nil
+}
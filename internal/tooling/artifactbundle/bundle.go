@@ -0,0 +1,400 @@
+package artifactbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemaVersion identifies the bundle manifest format.
+const SchemaVersion = "rspp-artifact-bundle/v1"
+
+// ManifestFileName is the first tar entry of every bundle.
+const ManifestFileName = "manifest.json"
+
+// MaxFileBytes bounds every bundled file, on export and import.
+const MaxFileBytes = 64 << 20
+
+// Artifact kinds recorded in the manifest.
+const (
+	KindRuntimeBaseline = "runtime_baseline"
+	KindFixtureMetadata = "fixture_metadata"
+	KindArbiterFixture  = "arbiter_fixture"
+	KindReport          = "report"
+	KindFile            = "file"
+)
+
+// ErrSessionAudio rejects raw session recordings, which leave only through export-recording's
+// consent and principal checks.
+var ErrSessionAudio = errors.New("session audio recordings cannot be bundled; use export-recording")
+
+// Manifest describes a bundle's contents.
+type Manifest struct {
+	SchemaVersion  string  `json:"schema_version"`
+	CreatedAtUTC   string  `json:"created_at_utc"`
+	Environment    string  `json:"environment,omitempty"`
+	Description    string  `json:"description,omitempty"`
+	Entries        []Entry `json:"entries"`
+	TotalSizeBytes int64   `json:"total_size_bytes"`
+}
+
+// Entry is one bundled file.
+type Entry struct {
+	// Path is the slash-separated bundle path, relative to the export root.
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	// ArtifactSchemaVersion is the file's own top-level schema_version, when it has one.
+	ArtifactSchemaVersion string `json:"artifact_schema_version,omitempty"`
+	SHA256                string `json:"sha256"`
+	SizeBytes             int64  `json:"size_bytes"`
+}
+
+// Validate enforces manifest invariants.
+func (m Manifest) Validate() error {
+	if m.SchemaVersion != SchemaVersion {
+		return fmt.Errorf("unsupported artifact bundle schema_version: %q", m.SchemaVersion)
+	}
+	if len(m.Entries) == 0 {
+		return fmt.Errorf("artifact bundle manifest has no entries")
+	}
+	seen := map[string]struct{}{}
+	var total int64
+	for _, entry := range m.Entries {
+		if err := validateBundlePath(entry.Path); err != nil {
+			return err
+		}
+		if _, ok := seen[entry.Path]; ok {
+			return fmt.Errorf("duplicate artifact bundle entry: %s", entry.Path)
+		}
+		seen[entry.Path] = struct{}{}
+		if entry.Kind == "" || len(entry.SHA256) != 64 {
+			return fmt.Errorf("artifact bundle entry %s requires kind and sha256", entry.Path)
+		}
+		if entry.SizeBytes < 0 || entry.SizeBytes > MaxFileBytes {
+			return fmt.Errorf("artifact bundle entry %s size out of range", entry.Path)
+		}
+		total += entry.SizeBytes
+	}
+	if total != m.TotalSizeBytes {
+		return fmt.Errorf("artifact bundle total_size_bytes %d does not match entries (%d)", m.TotalSizeBytes, total)
+	}
+	return nil
+}
+
+// ExportOptions selects what to bundle.
+type ExportOptions struct {
+	// Root anchors bundle paths; every input must live under it. Defaults to the working directory.
+	Root string
+	// Paths are files or directories; directories are bundled recursively.
+	Paths       []string
+	Environment string
+	Description string
+	Now         func() time.Time
+}
+
+// Export writes a gzip-compressed tar bundle of opts.Paths to outputPath and returns its manifest.
+func Export(outputPath string, opts ExportOptions) (Manifest, error) {
+	if len(opts.Paths) == 0 {
+		return Manifest{}, fmt.Errorf("artifact export requires at least one path")
+	}
+	root := opts.Root
+	if root == "" {
+		root = "."
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return Manifest{}, err
+	}
+	files := map[string]string{}
+	for _, input := range opts.Paths {
+		if err := collect(root, input, files); err != nil {
+			return Manifest{}, err
+		}
+	}
+	absOutput, err := filepath.Abs(outputPath)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	bundlePaths := make([]string, 0, len(files))
+	for bundlePath, source := range files {
+		if source == absOutput {
+			continue
+		}
+		bundlePaths = append(bundlePaths, bundlePath)
+	}
+	sort.Strings(bundlePaths)
+	if len(bundlePaths) == 0 {
+		return Manifest{}, fmt.Errorf("artifact export found no files")
+	}
+
+	now := time.Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	manifest := Manifest{
+		SchemaVersion: SchemaVersion,
+		CreatedAtUTC:  now().UTC().Format(time.RFC3339),
+		Environment:   opts.Environment,
+		Description:   opts.Description,
+	}
+	contents := make(map[string][]byte, len(bundlePaths))
+	for _, bundlePath := range bundlePaths {
+		data, err := readBounded(files[bundlePath])
+		if err != nil {
+			return Manifest{}, err
+		}
+		entry, err := describe(bundlePath, data)
+		if err != nil {
+			return Manifest{}, err
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+		manifest.TotalSizeBytes += entry.SizeBytes
+		contents[bundlePath] = data
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	modTime := now().UTC()
+	if err := writeTarFile(tw, ManifestFileName, manifestData, modTime); err != nil {
+		return Manifest{}, err
+	}
+	for _, entry := range manifest.Entries {
+		if err := writeTarFile(tw, entry.Path, contents[entry.Path], modTime); err != nil {
+			return Manifest{}, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return Manifest{}, err
+	}
+	if err := gz.Close(); err != nil {
+		return Manifest{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, os.WriteFile(outputPath, buf.Bytes(), 0o644)
+}
+
+// ReadManifest reads and validates a bundle's manifest without extracting it.
+func ReadManifest(bundlePath string) (Manifest, error) {
+	manifest, _, err := readBundle(bundlePath)
+	return manifest, err
+}
+
+// Import verifies every file of the bundle against its manifest and extracts it under destDir.
+// Nothing is written unless the whole bundle verifies, and existing files are never overwritten.
+func Import(bundlePath string, destDir string) (Manifest, error) {
+	if strings.TrimSpace(destDir) == "" {
+		return Manifest{}, fmt.Errorf("artifact import destination is required")
+	}
+	manifest, contents, err := readBundle(bundlePath)
+	if err != nil {
+		return Manifest{}, err
+	}
+	for _, entry := range manifest.Entries {
+		target := filepath.Join(destDir, filepath.FromSlash(entry.Path))
+		if _, err := os.Stat(target); err == nil {
+			return Manifest{}, fmt.Errorf("artifact import would overwrite %s", target)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return Manifest{}, err
+		}
+	}
+	for _, entry := range manifest.Entries {
+		target := filepath.Join(destDir, filepath.FromSlash(entry.Path))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return Manifest{}, err
+		}
+		if err := os.WriteFile(target, contents[entry.Path], 0o644); err != nil {
+			return Manifest{}, err
+		}
+	}
+	return manifest, nil
+}
+
+func readBundle(bundlePath string) (Manifest, map[string][]byte, error) {
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return Manifest{}, nil, fmt.Errorf("read artifact bundle %s: %w", bundlePath, err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	header, err := tr.Next()
+	if err != nil {
+		return Manifest{}, nil, fmt.Errorf("read artifact bundle manifest: %w", err)
+	}
+	if header.Name != ManifestFileName {
+		return Manifest{}, nil, fmt.Errorf("artifact bundle must start with %s, got %s", ManifestFileName, header.Name)
+	}
+	manifestData, err := io.ReadAll(io.LimitReader(tr, MaxFileBytes))
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+	var manifest Manifest
+	decoder := json.NewDecoder(bytes.NewReader(manifestData))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&manifest); err != nil {
+		return Manifest{}, nil, fmt.Errorf("decode artifact bundle manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return Manifest{}, nil, err
+	}
+	expected := make(map[string]Entry, len(manifest.Entries))
+	for _, entry := range manifest.Entries {
+		expected[entry.Path] = entry
+	}
+
+	contents := make(map[string][]byte, len(manifest.Entries))
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Manifest{}, nil, fmt.Errorf("read artifact bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			return Manifest{}, nil, fmt.Errorf("artifact bundle entry %s is not a regular file", header.Name)
+		}
+		entry, ok := expected[header.Name]
+		if !ok {
+			return Manifest{}, nil, fmt.Errorf("artifact bundle entry %s is not in the manifest", header.Name)
+		}
+		if _, dup := contents[header.Name]; dup {
+			return Manifest{}, nil, fmt.Errorf("artifact bundle entry %s appears twice", header.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, entry.SizeBytes+1))
+		if err != nil {
+			return Manifest{}, nil, err
+		}
+		if int64(len(data)) != entry.SizeBytes || sha256Hex(data) != entry.SHA256 {
+			return Manifest{}, nil, fmt.Errorf("artifact bundle entry %s does not match its manifest hash", header.Name)
+		}
+		contents[header.Name] = data
+	}
+	for _, entry := range manifest.Entries {
+		if _, ok := contents[entry.Path]; !ok {
+			return Manifest{}, nil, fmt.Errorf("artifact bundle is missing %s", entry.Path)
+		}
+	}
+	return manifest, contents, nil
+}
+
+func collect(root string, input string, files map[string]string) error {
+	abs, err := filepath.Abs(input)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return err
+	}
+	add := func(source string) error {
+		rel, err := filepath.Rel(root, source)
+		if err != nil || rel == "." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || rel == ".." {
+			return fmt.Errorf("artifact %s is outside export root %s", source, root)
+		}
+		files[filepath.ToSlash(rel)] = source
+		return nil
+	}
+	if !info.IsDir() {
+		return add(abs)
+	}
+	return filepath.WalkDir(abs, func(walked string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		return add(walked)
+	})
+}
+
+func describe(bundlePath string, data []byte) (Entry, error) {
+	entry := Entry{Path: bundlePath, Kind: KindFile, SHA256: sha256Hex(data), SizeBytes: int64(len(data))}
+	if path.Ext(bundlePath) != ".json" {
+		return entry, nil
+	}
+	var top map[string]json.RawMessage
+	if json.Unmarshal(data, &top) != nil {
+		return entry, nil
+	}
+	var schema string
+	if raw, ok := top["schema_version"]; ok {
+		_ = json.Unmarshal(raw, &schema)
+	}
+	entry.ArtifactSchemaVersion = schema
+	_, hasSegments := top["segments"]
+	_, hasConsent := top["consent_granted"]
+	_, hasEntries := top["entries"]
+	_, hasFixtures := top["fixtures"]
+	switch {
+	case hasSegments && hasConsent:
+		return Entry{}, fmt.Errorf("%s: %w", bundlePath, ErrSessionAudio)
+	case hasEntries && schema != "":
+		entry.Kind = KindRuntimeBaseline
+	case hasFixtures && path.Base(bundlePath) == "metadata.json":
+		entry.Kind = KindFixtureMetadata
+	case strings.HasPrefix(schema, "turn-arbiter-fixture/"):
+		entry.Kind = KindArbiterFixture
+	default:
+		entry.Kind = KindReport
+	}
+	return entry, nil
+}
+
+func validateBundlePath(p string) error {
+	if p == "" || p == ManifestFileName || strings.HasPrefix(p, "/") || strings.Contains(p, `\`) || path.Clean(p) != p || p == ".." || strings.HasPrefix(p, "../") {
+		return fmt.Errorf("invalid artifact bundle path: %q", p)
+	}
+	return nil
+}
+
+func readBounded(source string) ([]byte, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > MaxFileBytes {
+		return nil, fmt.Errorf("artifact %s exceeds %d bytes", source, MaxFileBytes)
+	}
+	return os.ReadFile(source)
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: modTime, Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
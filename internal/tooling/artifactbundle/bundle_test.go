@@ -0,0 +1,191 @@
+package artifactbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path string, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func fixedNow() time.Time {
+	return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "replay", "runtime-baseline.json"), `{"schema_version":"v1","entries":[]}`)
+	writeFile(t, filepath.Join(root, "fixtures", "metadata.json"), `{"fixtures":{}}`)
+	writeFile(t, filepath.Join(root, "fixtures", "arbiter", "defer.json"), `{"schema_version":"turn-arbiter-fixture/v1"}`)
+	writeFile(t, filepath.Join(root, "notes.txt"), "repro notes")
+
+	bundlePath := filepath.Join(root, "out", "bundle.tar.gz")
+	manifest, err := Export(bundlePath, ExportOptions{
+		Root:        root,
+		Paths:       []string{filepath.Join(root, "replay"), filepath.Join(root, "fixtures"), filepath.Join(root, "notes.txt")},
+		Environment: "staging",
+		Now:         fixedNow,
+	})
+	if err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+	kinds := map[string]string{}
+	for _, entry := range manifest.Entries {
+		kinds[entry.Path] = entry.Kind
+	}
+	expected := map[string]string{
+		"replay/runtime-baseline.json": KindRuntimeBaseline,
+		"fixtures/metadata.json":       KindFixtureMetadata,
+		"fixtures/arbiter/defer.json":  KindArbiterFixture,
+		"notes.txt":                    KindFile,
+	}
+	if len(kinds) != len(expected) {
+		t.Fatalf("expected %d entries, got %+v", len(expected), manifest.Entries)
+	}
+	for path, kind := range expected {
+		if kinds[path] != kind {
+			t.Fatalf("expected %s kind %s, got %s", path, kind, kinds[path])
+		}
+	}
+	if manifest.CreatedAtUTC != "2026-01-02T03:04:05Z" || manifest.Environment != "staging" {
+		t.Fatalf("unexpected manifest header: %+v", manifest)
+	}
+
+	dest := filepath.Join(t.TempDir(), "import")
+	imported, err := Import(bundlePath, dest)
+	if err != nil {
+		t.Fatalf("unexpected import error: %v", err)
+	}
+	if len(imported.Entries) != len(expected) {
+		t.Fatalf("expected imported manifest entries, got %+v", imported)
+	}
+	got, err := os.ReadFile(filepath.Join(dest, "notes.txt"))
+	if err != nil || string(got) != "repro notes" {
+		t.Fatalf("expected imported notes, got %q (%v)", got, err)
+	}
+
+	if _, err := Import(bundlePath, dest); err == nil || !strings.Contains(err.Error(), "overwrite") {
+		t.Fatalf("expected overwrite rejection, got %v", err)
+	}
+}
+
+func TestExportRejectsSessionAudioAndOutsideRoot(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	audioPath := filepath.Join(root, "session-audio.json")
+	writeFile(t, audioPath, `{"session_id":"s","consent_granted":true,"segments":[]}`)
+	_, err := Export(filepath.Join(root, "bundle.tar.gz"), ExportOptions{Root: root, Paths: []string{audioPath}})
+	if !errors.Is(err, ErrSessionAudio) {
+		t.Fatalf("expected session audio rejection, got %v", err)
+	}
+
+	outside := filepath.Join(t.TempDir(), "report.json")
+	writeFile(t, outside, `{}`)
+	if _, err := Export(filepath.Join(root, "bundle.tar.gz"), ExportOptions{Root: root, Paths: []string{outside}}); err == nil {
+		t.Fatalf("expected outside-root rejection")
+	}
+}
+
+func TestImportRejectsTamperedBundles(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "report.json"), `{"schema_version":"v1"}`)
+	bundlePath := filepath.Join(root, "bundle.tar.gz")
+	manifest, err := Export(bundlePath, ExportOptions{Root: root, Paths: []string{filepath.Join(root, "report.json")}, Now: fixedNow})
+	if err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+	manifestData, err := readTarEntry(bundlePath, ManifestFileName)
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		entries map[string]string
+		wantErr string
+	}{
+		{name: "modified content", entries: map[string]string{"report.json": `{"schema_version":"v2"}`}, wantErr: "hash"},
+		{name: "extra file", entries: map[string]string{"report.json": `{"schema_version":"v1"}`, "extra.json": `{}`}, wantErr: "not in the manifest"},
+		{name: "missing file", entries: map[string]string{}, wantErr: "missing"},
+	}
+	for _, tc := range tests {
+		tampered := filepath.Join(t.TempDir(), "tampered.tar.gz")
+		writeTarGz(t, tampered, manifestData, tc.entries)
+		if _, err := Import(tampered, filepath.Join(t.TempDir(), "dest")); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+
+	traversal := manifest
+	traversal.Entries = append([]Entry(nil), manifest.Entries...)
+	traversal.Entries[0].Path = "../escape.json"
+	if err := traversal.Validate(); err == nil {
+		t.Fatalf("expected traversal path rejection")
+	}
+}
+
+func readTarEntry(bundlePath string, name string) ([]byte, error) {
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			return nil, err
+		}
+		if header.Name == name {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+func writeTarGz(t *testing.T, path string, manifest []byte, entries map[string]string) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	defer file.Close()
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	if err := writeTarFile(tw, ManifestFileName, manifest, fixedNow()); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	for name, data := range entries {
+		if err := writeTarFile(tw, name, []byte(data), fixedNow()); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("close gzip: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package artifactwriter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// EnvSummaryPath overrides the Markdown summary path of the next report a command writes.
+const EnvSummaryPath = "RSPP_SUMMARY_PATH"
+
+// SummaryPath returns the Markdown summary paired with a JSON artifact: explicit when set,
+// otherwise outputPath with its .json extension swapped for .md. Any other extension (or none)
+// gets .md appended, so report.txt pairs with report.txt.md instead of overwriting report.md.
+func SummaryPath(outputPath string, explicit string) string {
+	if explicit = strings.TrimSpace(explicit); explicit != "" {
+		return explicit
+	}
+	if strings.EqualFold(filepath.Ext(outputPath), ".json") {
+		return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".md"
+	}
+	return outputPath + ".md"
+}
+
+// Pair is a JSON report artifact and its Markdown summary.
+type Pair struct {
+	JSONPath    string
+	SummaryPath string
+}
+
+// NewPair resolves the summary path for outputPath and rejects colliding pairs: the two paths
+// must differ and neither may name an existing directory.
+func NewPair(outputPath string, explicitSummaryPath string) (Pair, error) {
+	if strings.TrimSpace(outputPath) == "" {
+		return Pair{}, fmt.Errorf("artifact output path is required")
+	}
+	pair := Pair{JSONPath: outputPath, SummaryPath: SummaryPath(outputPath, explicitSummaryPath)}
+	if filepath.Clean(pair.JSONPath) == filepath.Clean(pair.SummaryPath) {
+		return Pair{}, fmt.Errorf("artifact summary path %s collides with the json artifact", pair.SummaryPath)
+	}
+	for _, path := range []string{pair.JSONPath, pair.SummaryPath} {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return Pair{}, fmt.Errorf("artifact path %s is an existing directory", path)
+		}
+	}
+	return pair, nil
+}
+
+// Write writes payload as indented JSON and then the summary, each atomically. A failed write
+// leaves any previous file at that path intact.
+func (p Pair) Write(payload any, summary string) error {
+	if err := WriteJSON(p.JSONPath, payload); err != nil {
+		return err
+	}
+	return WriteFile(p.SummaryPath, []byte(summary))
+}
+
+// WriteJSON atomically writes payload as indented JSON.
+func WriteJSON(path string, payload any) error {
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode artifact %s: %w", path, err)
+	}
+	return WriteFile(path, data)
+}
+
+// WriteFile atomically replaces path with data: it writes a temp file in the same directory,
+// syncs it, and renames it over path, so readers never observe a partial artifact. Artifacts
+// under a .codex tree are then recorded in its integrity index (see IndexPath).
+func WriteFile(path string, data []byte) error {
+	if err := writeAtomic(path, data); err != nil {
+		return err
+	}
+	return recordArtifact(path, data, time.Now())
+}
+
+func writeAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("write artifact %s: %w", path, err)
+	}
+	tmpPath := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			_ = os.Remove(tmpPath)
+		}
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write artifact %s: %w", path, err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write artifact %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("sync artifact %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write artifact %s: %w", path, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("replace artifact %s: %w", path, err)
+	}
+	committed = true
+	return nil
+}
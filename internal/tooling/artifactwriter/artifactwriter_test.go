@@ -0,0 +1,96 @@
+package artifactwriter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSummaryPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		output   string
+		explicit string
+		want     string
+	}{
+		{name: "json extension swapped", output: ".codex/ops/slo.json", want: ".codex/ops/slo.md"},
+		{name: "upper-case json extension swapped", output: "report.JSON", want: "report.md"},
+		{name: "no extension appended", output: ".codex/ops/report", want: ".codex/ops/report.md"},
+		{name: "other extension appended", output: "release.v2", want: "release.v2.md"},
+		{name: "explicit path wins", output: "slo.json", explicit: "summaries/slo-summary.md", want: "summaries/slo-summary.md"},
+	}
+	for _, tc := range tests {
+		if got := SummaryPath(tc.output, tc.explicit); got != tc.want {
+			t.Fatalf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestNewPairRejectsCollisions(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		output   string
+		explicit string
+	}{
+		{name: "empty output", output: " "},
+		{name: "summary equals output", output: filepath.Join(dir, "report.md"), explicit: filepath.Join(dir, ".", "report.md")},
+		{name: "output is a directory", output: dir},
+		{name: "summary is a directory", output: filepath.Join(dir, "report.json"), explicit: dir},
+	}
+	for _, tc := range tests {
+		if _, err := NewPair(tc.output, tc.explicit); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
+
+func TestPairWriteIsAtomicAndLeavesNoTempFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "ops")
+	unrelated := filepath.Join(dir, "report.md")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(unrelated, []byte("hand-written notes"), 0o644); err != nil {
+		t.Fatalf("write unrelated: %v", err)
+	}
+
+	pair, err := NewPair(filepath.Join(dir, "report.txt"), "")
+	if err != nil {
+		t.Fatalf("unexpected pair error: %v", err)
+	}
+	if err := pair.Write(map[string]any{"passed": true}, "# Report\n"); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if raw, _ := os.ReadFile(unrelated); string(raw) != "hand-written notes" {
+		t.Fatalf("expected unrelated report.md untouched, got %q", raw)
+	}
+	if raw, _ := os.ReadFile(filepath.Join(dir, "report.txt")); string(raw) != "{\n  \"passed\": true\n}" {
+		t.Fatalf("unexpected json artifact %q", raw)
+	}
+	if raw, _ := os.ReadFile(filepath.Join(dir, "report.txt.md")); string(raw) != "# Report\n" {
+		t.Fatalf("unexpected summary %q", raw)
+	}
+
+	if err := pair.Write(func() {}, "# ignored\n"); err == nil {
+		t.Fatalf("expected unencodable payload to fail")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	for _, entry := range entries {
+		if name := entry.Name(); name != "report.txt" && name != "report.md" && name != "report.txt.md" {
+			t.Fatalf("unexpected leftover file %s", name)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(dir, "report.txt")); err != nil || info.Mode().Perm() != 0o644 {
+		t.Fatalf("expected 0644 artifact, got %v err=%v", info, err)
+	}
+}
@@ -0,0 +1,293 @@
+package artifactwriter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// IndexSchemaVersion identifies the artifact integrity index format.
+	IndexSchemaVersion = "artifact-index/v1"
+	// IndexFileName is the integrity index kept at the root of a .codex tree.
+	IndexFileName = "index.json"
+
+	codexDirName   = ".codex"
+	indexLockWait  = 5 * time.Second
+	indexLockStale = 30 * time.Second
+	indexLockPoll  = 10 * time.Millisecond
+)
+
+// IndexEntry records one artifact as the tooling last wrote it.
+type IndexEntry struct {
+	// Path is slash-separated and relative to the directory holding the .codex tree, e.g.
+	// .codex/ops/slo-gates-report.json.
+	Path           string `json:"path"`
+	SHA256         string `json:"sha256"`
+	SizeBytes      int64  `json:"size_bytes"`
+	SchemaVersion  string `json:"schema_version,omitempty"`
+	GeneratedAtUTC string `json:"generated_at_utc"`
+}
+
+// Index lists every artifact the tooling wrote under one .codex tree, sorted by path.
+type Index struct {
+	SchemaVersion string       `json:"schema_version"`
+	Entries       []IndexEntry `json:"entries"`
+}
+
+// IndexPath returns the integrity index covering artifactPath: index.json at the root of the
+// nearest enclosing .codex directory. Artifacts outside any .codex tree are not indexed.
+func IndexPath(artifactPath string) (indexPath string, entryPath string, ok bool) {
+	abs, err := filepath.Abs(artifactPath)
+	if err != nil {
+		return "", "", false
+	}
+	for dir := filepath.Dir(abs); ; dir = filepath.Dir(dir) {
+		if filepath.Base(dir) == codexDirName {
+			rel, err := filepath.Rel(filepath.Dir(dir), abs)
+			if err != nil {
+				return "", "", false
+			}
+			return filepath.Join(dir, IndexFileName), filepath.ToSlash(rel), true
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return "", "", false
+		}
+	}
+}
+
+// Record indexes the artifact already written at path. WriteFile records every artifact it
+// writes; writers that produce files some other way call Record afterwards.
+func Record(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("index artifact %s: %w", path, err)
+	}
+	return recordArtifact(path, data, time.Now())
+}
+
+func recordArtifact(path string, data []byte, now time.Time) error {
+	indexPath, entryPath, ok := IndexPath(path)
+	if !ok || entryPath == codexDirName+"/"+IndexFileName {
+		return nil
+	}
+	entry := IndexEntry{
+		Path:           entryPath,
+		SHA256:         sha256Hex(data),
+		SizeBytes:      int64(len(data)),
+		SchemaVersion:  artifactSchemaVersion(data),
+		GeneratedAtUTC: now.UTC().Format(time.RFC3339),
+	}
+	unlock, err := lockIndex(indexPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	index, err := ReadIndex(indexPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	index.SchemaVersion = IndexSchemaVersion
+	replaced := false
+	for i := range index.Entries {
+		if index.Entries[i].Path == entry.Path {
+			index.Entries[i], replaced = entry, true
+			break
+		}
+	}
+	if !replaced {
+		index.Entries = append(index.Entries, entry)
+		sort.Slice(index.Entries, func(i, j int) bool { return index.Entries[i].Path < index.Entries[j].Path })
+	}
+	raw, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode artifact index %s: %w", indexPath, err)
+	}
+	if err := writeAtomic(indexPath, raw); err != nil {
+		return fmt.Errorf("artifact %s written but not indexed: %w", path, err)
+	}
+	return nil
+}
+
+// ReadIndex loads the integrity index at path. A missing index reads as an empty index along
+// with an error wrapping fs.ErrNotExist.
+func ReadIndex(path string) (Index, error) {
+	index := Index{SchemaVersion: IndexSchemaVersion}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return index, fmt.Errorf("read artifact index %s: %w", path, err)
+	}
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return Index{}, fmt.Errorf("decode artifact index %s: %w", path, err)
+	}
+	if index.SchemaVersion != IndexSchemaVersion {
+		return Index{}, fmt.Errorf("artifact index %s: unsupported schema_version %q", path, index.SchemaVersion)
+	}
+	return index, nil
+}
+
+// CheckIndexed fails when data, read from path, differs from what the tooling last wrote there.
+// Artifacts outside a .codex tree or absent from its index pass: only evidence of an edit made
+// outside the tooling is an error.
+func CheckIndexed(path string, data []byte) error {
+	indexPath, entryPath, ok := IndexPath(path)
+	if !ok {
+		return nil
+	}
+	index, err := ReadIndex(indexPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range index.Entries {
+		if entry.Path == entryPath {
+			if got := sha256Hex(data); got != entry.SHA256 {
+				return fmt.Errorf("artifact %s was modified outside the tooling: sha256 %s, indexed %s", path, got, entry.SHA256)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// Verification problems reported by VerifyIndex.
+const (
+	ProblemMissing   = "missing"
+	ProblemModified  = "modified"
+	ProblemUnindexed = "unindexed"
+)
+
+// Problem is one integrity finding. Missing and modified entries fail verification; unindexed
+// files were produced by something other than the report writers and are only flagged.
+type Problem struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Verification is the outcome of checking a .codex tree against its index.
+type Verification struct {
+	IndexPath string    `json:"index_path"`
+	Checked   int       `json:"checked"`
+	Problems  []Problem `json:"problems,omitempty"`
+}
+
+// Passed reports whether every indexed artifact is present and unmodified.
+func (v Verification) Passed() bool {
+	for _, problem := range v.Problems {
+		if problem.Kind != ProblemUnindexed {
+			return false
+		}
+	}
+	return true
+}
+
+// VerifyIndex checks every artifact listed in the index at indexPath against its recorded
+// SHA256 and flags files in the same .codex tree the index does not list. The skills and rules
+// directories hold tracked agent configuration, not artifacts, and are skipped.
+func VerifyIndex(indexPath string) (Verification, error) {
+	index, err := ReadIndex(indexPath)
+	if err != nil {
+		return Verification{}, err
+	}
+	codexDir := filepath.Dir(indexPath)
+	root := filepath.Dir(codexDir)
+	result := Verification{IndexPath: indexPath, Checked: len(index.Entries)}
+	indexed := make(map[string]bool, len(index.Entries))
+	for _, entry := range index.Entries {
+		indexed[entry.Path] = true
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(entry.Path)))
+		if errors.Is(err, fs.ErrNotExist) {
+			result.Problems = append(result.Problems, Problem{Path: entry.Path, Kind: ProblemMissing})
+			continue
+		}
+		if err != nil {
+			return Verification{}, fmt.Errorf("read artifact %s: %w", entry.Path, err)
+		}
+		if got := sha256Hex(data); got != entry.SHA256 {
+			result.Problems = append(result.Problems, Problem{Path: entry.Path, Kind: ProblemModified, Detail: fmt.Sprintf("sha256 %s, indexed %s", got, entry.SHA256)})
+		}
+	}
+	err = filepath.WalkDir(codexDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel == codexDirName+"/skills" || rel == codexDirName+"/rules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := d.Name()
+		if path == indexPath || name == IndexFileName+".lock" || strings.Contains(name, ".tmp-") {
+			return nil
+		}
+		if !indexed[rel] {
+			result.Problems = append(result.Problems, Problem{Path: rel, Kind: ProblemUnindexed})
+		}
+		return nil
+	})
+	if err != nil {
+		return Verification{}, fmt.Errorf("scan %s: %w", codexDir, err)
+	}
+	return result, nil
+}
+
+// lockIndex serializes index updates across processes with an exclusive lock file. A lock older
+// than indexLockStale was left by a crashed writer and is broken.
+func lockIndex(indexPath string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0o755); err != nil {
+		return nil, err
+	}
+	lockPath := indexPath + ".lock"
+	deadline := time.Now().Add(indexLockWait)
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = file.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("lock artifact index %s: %w", indexPath, err)
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > indexLockStale {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("artifact index %s is locked by another writer", indexPath)
+		}
+		time.Sleep(indexLockPoll)
+	}
+}
+
+// artifactSchemaVersion reads a JSON artifact's top-level schema_version, if it has one.
+func artifactSchemaVersion(data []byte) string {
+	var header struct {
+		SchemaVersion string `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return ""
+	}
+	return header.SchemaVersion
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
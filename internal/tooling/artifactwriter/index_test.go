@@ -0,0 +1,109 @@
+package artifactwriter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteFileMaintainsIntegrityIndex(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	reportPath := filepath.Join(root, ".codex", "ops", "slo-gates-report.json")
+	summaryPath := filepath.Join(root, ".codex", "ops", "slo-gates-report.md")
+	if err := (Pair{JSONPath: reportPath, SummaryPath: summaryPath}).Write(map[string]any{"schema_version": "slo-gates-report/v2"}, "# SLO\n"); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if err := WriteFile(filepath.Join(root, "outside.json"), []byte(`{}`)); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	// Rewriting an artifact replaces its entry rather than adding one.
+	if err := WriteJSON(reportPath, map[string]any{"schema_version": "slo-gates-report/v2", "passed": true}); err != nil {
+		t.Fatalf("unexpected rewrite error: %v", err)
+	}
+
+	indexPath := filepath.Join(root, ".codex", IndexFileName)
+	index, err := ReadIndex(indexPath)
+	if err != nil {
+		t.Fatalf("unexpected index read error: %v", err)
+	}
+	paths := []string{}
+	for _, entry := range index.Entries {
+		paths = append(paths, entry.Path)
+	}
+	if !reflect.DeepEqual(paths, []string{".codex/ops/slo-gates-report.json", ".codex/ops/slo-gates-report.md"}) {
+		t.Fatalf("expected both .codex artifacts indexed once, got %v", paths)
+	}
+	if index.Entries[0].SchemaVersion != "slo-gates-report/v2" || index.Entries[0].SHA256 == "" || index.Entries[1].SchemaVersion != "" {
+		t.Fatalf("unexpected index entries %+v", index.Entries)
+	}
+	verification, err := VerifyIndex(indexPath)
+	if err != nil || !verification.Passed() || len(verification.Problems) != 0 || verification.Checked != 2 {
+		t.Fatalf("expected clean verification, got %+v err=%v", verification, err)
+	}
+
+	raw, _ := os.ReadFile(reportPath)
+	if err := CheckIndexed(reportPath, raw); err != nil {
+		t.Fatalf("unexpected check error for untouched artifact: %v", err)
+	}
+	if err := os.WriteFile(reportPath, []byte(`{"passed":true}`), 0o644); err != nil {
+		t.Fatalf("unexpected tamper error: %v", err)
+	}
+	if err := CheckIndexed(reportPath, []byte(`{"passed":true}`)); err == nil {
+		t.Fatalf("expected hand-edited artifact to fail the index check")
+	}
+	if err := os.Remove(summaryPath); err != nil {
+		t.Fatalf("unexpected remove error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".codex", "ops", "notes.txt"), []byte("hand-written"), 0o644); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".codex", "skills"), 0o755); err != nil {
+		t.Fatalf("unexpected mkdir error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".codex", "skills", "SKILL.md"), []byte("skill"), 0o644); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+
+	verification, err = VerifyIndex(indexPath)
+	if err != nil {
+		t.Fatalf("unexpected verification error: %v", err)
+	}
+	kinds := map[string]string{}
+	for _, problem := range verification.Problems {
+		kinds[problem.Path] = problem.Kind
+	}
+	want := map[string]string{
+		".codex/ops/slo-gates-report.json": ProblemModified,
+		".codex/ops/slo-gates-report.md":   ProblemMissing,
+		".codex/ops/notes.txt":             ProblemUnindexed,
+	}
+	if verification.Passed() || !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("expected %v, got %v", want, kinds)
+	}
+}
+
+func TestIndexPathFindsNearestCodexRoot(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	tests := []struct {
+		name      string
+		path      string
+		wantIndex string
+		wantEntry string
+		wantOK    bool
+	}{
+		{name: "default report", path: filepath.Join(root, ".codex", "ops", "contracts-report.json"), wantIndex: filepath.Join(root, ".codex", IndexFileName), wantEntry: ".codex/ops/contracts-report.json", wantOK: true},
+		{name: "environment namespaced", path: filepath.Join(root, ".codex", "prod", "replay", "regression-report.json"), wantIndex: filepath.Join(root, ".codex", IndexFileName), wantEntry: ".codex/prod/replay/regression-report.json", wantOK: true},
+		{name: "outside codex", path: filepath.Join(root, "reports", "contracts.json")},
+	}
+	for _, tc := range tests {
+		indexPath, entryPath, ok := IndexPath(tc.path)
+		if ok != tc.wantOK || indexPath != tc.wantIndex || entryPath != tc.wantEntry {
+			t.Fatalf("%s: expected %s %s %v, got %s %s %v", tc.name, tc.wantIndex, tc.wantEntry, tc.wantOK, indexPath, entryPath, ok)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package completion
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Shells lists the shells Script can generate completion for.
+var Shells = []string{"bash", "zsh", "fish"}
+
+var wordPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Command is one top-level command of a binary. Subcommands complete as the second word;
+// Flags are Go flag names (without the leading dash) completed anywhere after the command.
+// Other positions fall back to file completion.
+type Command struct {
+	Name        string
+	Subcommands []string
+	Flags       []string
+}
+
+// Spec describes the command tree of one binary.
+type Spec struct {
+	Program  string
+	Commands []Command
+}
+
+func (s Spec) validate() error {
+	if !wordPattern.MatchString(s.Program) {
+		return fmt.Errorf("completion program %q must match %s", s.Program, wordPattern)
+	}
+	if len(s.Commands) == 0 {
+		return fmt.Errorf("completion spec for %s has no commands", s.Program)
+	}
+	seen := make(map[string]struct{}, len(s.Commands))
+	for _, command := range s.Commands {
+		if _, dup := seen[command.Name]; dup {
+			return fmt.Errorf("duplicate completion command %q", command.Name)
+		}
+		seen[command.Name] = struct{}{}
+		for _, word := range append(append([]string{command.Name}, command.Subcommands...), command.Flags...) {
+			if !wordPattern.MatchString(word) {
+				return fmt.Errorf("completion word %q of %s %s must match %s", word, s.Program, command.Name, wordPattern)
+			}
+		}
+	}
+	return nil
+}
+
+// Script renders the completion script of spec for shell.
+func Script(shell string, spec Spec) (string, error) {
+	if err := spec.validate(); err != nil {
+		return "", err
+	}
+	switch shell {
+	case "bash":
+		return bashScript(spec), nil
+	case "zsh":
+		return zshScript(spec), nil
+	case "fish":
+		return fishScript(spec), nil
+	default:
+		return "", fmt.Errorf("unsupported completion shell %q (expected %s)", shell, strings.Join(Shells, "|"))
+	}
+}
+
+// CompletionCommand is the "completion <shell>" command every binary exposes.
+func CompletionCommand() Command {
+	return Command{Name: "completion", Subcommands: Shells}
+}
+
+func functionName(program string) string {
+	return "_" + strings.ReplaceAll(program, "-", "_")
+}
+
+func commandNames(spec Spec) []string {
+	names := make([]string, 0, len(spec.Commands))
+	for _, command := range spec.Commands {
+		names = append(names, command.Name)
+	}
+	return names
+}
+
+func dashed(flags []string) []string {
+	out := make([]string, 0, len(flags))
+	for _, flag := range flags {
+		out = append(out, "-"+flag)
+	}
+	return out
+}
+
+func bashScript(spec Spec) string {
+	var b strings.Builder
+	fn := functionName(spec.Program)
+	fmt.Fprintf(&b, "# bash completion for %s; load with: source <(%s completion bash)\n", spec.Program, spec.Program)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("\tif [ \"$COMP_CWORD\" -eq 1 ]; then\n")
+	fmt.Fprintf(&b, "\t\tCOMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(commandNames(spec), " "))
+	b.WriteString("\t\treturn\n\tfi\n")
+	b.WriteString("\tlocal words=\"\"\n")
+	b.WriteString("\tcase \"${COMP_WORDS[1]}\" in\n")
+	for _, command := range spec.Commands {
+		if len(command.Subcommands) == 0 && len(command.Flags) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\t%s)\n", command.Name)
+		if len(command.Subcommands) > 0 {
+			fmt.Fprintf(&b, "\t\t[ \"$COMP_CWORD\" -eq 2 ] && words=\"%s\"\n", strings.Join(command.Subcommands, " "))
+		}
+		if len(command.Flags) > 0 {
+			fmt.Fprintf(&b, "\t\twords=\"$words %s\"\n", strings.Join(dashed(command.Flags), " "))
+		}
+		b.WriteString("\t\t;;\n")
+	}
+	b.WriteString("\tesac\n")
+	b.WriteString("\tCOMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	b.WriteString("\tif [ ${#COMPREPLY[@]} -eq 0 ]; then\n")
+	b.WriteString("\t\tCOMPREPLY=($(compgen -f -- \"$cur\"))\n")
+	b.WriteString("\tfi\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -F %s %s\n", fn, spec.Program)
+	return b.String()
+}
+
+func zshScript(spec Spec) string {
+	var b strings.Builder
+	fn := functionName(spec.Program)
+	fmt.Fprintf(&b, "#compdef %s\n", spec.Program)
+	fmt.Fprintf(&b, "# zsh completion for %s; load with: source <(%s completion zsh)\n", spec.Program, spec.Program)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("\tif (( CURRENT == 2 )); then\n")
+	fmt.Fprintf(&b, "\t\tcompadd -- %s\n", strings.Join(commandNames(spec), " "))
+	b.WriteString("\t\treturn\n\tfi\n")
+	b.WriteString("\tcase \"${words[2]}\" in\n")
+	for _, command := range spec.Commands {
+		if len(command.Subcommands) == 0 && len(command.Flags) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\t%s)\n", command.Name)
+		if len(command.Subcommands) > 0 {
+			fmt.Fprintf(&b, "\t\tif (( CURRENT == 3 )); then\n\t\t\tcompadd -- %s\n\t\t\treturn\n\t\tfi\n", strings.Join(command.Subcommands, " "))
+		}
+		if len(command.Flags) > 0 {
+			fmt.Fprintf(&b, "\t\tif [[ \"$PREFIX\" == -* ]]; then\n\t\t\tcompadd -- %s\n\t\t\treturn\n\t\tfi\n", strings.Join(dashed(command.Flags), " "))
+		}
+		b.WriteString("\t\t;;\n")
+	}
+	b.WriteString("\tesac\n")
+	b.WriteString("\t_files\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "compdef %s %s\n", fn, spec.Program)
+	return b.String()
+}
+
+func fishScript(spec Spec) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s; load with: %s completion fish | source\n", spec.Program, spec.Program)
+	fmt.Fprintf(&b, "complete -c %s -f -n __fish_use_subcommand -a \"%s\"\n", spec.Program, strings.Join(commandNames(spec), " "))
+	for _, command := range spec.Commands {
+		if len(command.Subcommands) > 0 {
+			fmt.Fprintf(&b, "complete -c %s -f -n \"__fish_seen_subcommand_from %s; and test (count (commandline -opc)) -eq 2\" -a \"%s\"\n",
+				spec.Program, command.Name, strings.Join(command.Subcommands, " "))
+		}
+		for _, flag := range command.Flags {
+			fmt.Fprintf(&b, "complete -c %s -n \"__fish_seen_subcommand_from %s\" -o %s\n", spec.Program, command.Name, flag)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package completion
+
+import (
+	"strings"
+	"testing"
+)
+
+func testSpec() Spec {
+	return Spec{
+		Program: "rspp-test",
+		Commands: []Command{
+			{Name: "report"},
+			{Name: "artifacts", Subcommands: []string{"export", "import"}},
+			{Name: "batch", Flags: []string{"job", "max-capture-ms"}},
+			CompletionCommand(),
+		},
+	}
+}
+
+func TestScriptRendersCommandTreePerShell(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{shell: "bash", want: []string{
+			`COMPREPLY=($(compgen -W "report artifacts batch completion" -- "$cur"))`,
+			`[ "$COMP_CWORD" -eq 2 ] && words="export import"`,
+			`words="$words -job -max-capture-ms"`,
+			`[ "$COMP_CWORD" -eq 2 ] && words="bash zsh fish"`,
+			"complete -F _rspp_test rspp-test",
+		}},
+		{shell: "zsh", want: []string{
+			"#compdef rspp-test",
+			"compadd -- report artifacts batch completion",
+			"compadd -- export import",
+			"compadd -- -job -max-capture-ms",
+			"compdef _rspp_test rspp-test",
+		}},
+		{shell: "fish", want: []string{
+			`complete -c rspp-test -f -n __fish_use_subcommand -a "report artifacts batch completion"`,
+			`-a "export import"`,
+			`complete -c rspp-test -n "__fish_seen_subcommand_from batch" -o max-capture-ms`,
+		}},
+	}
+	for _, tc := range tests {
+		script, err := Script(tc.shell, testSpec())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.shell, err)
+		}
+		for _, want := range tc.want {
+			if !strings.Contains(script, want) {
+				t.Fatalf("%s: expected script to contain %q, got:\n%s", tc.shell, want, script)
+			}
+		}
+	}
+}
+
+func TestScriptRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		shell string
+		spec  Spec
+	}{
+		{name: "unsupported shell", shell: "powershell", spec: testSpec()},
+		{name: "invalid program", shell: "bash", spec: Spec{Program: "rspp cli", Commands: []Command{{Name: "report"}}}},
+		{name: "no commands", shell: "bash", spec: Spec{Program: "rspp-cli"}},
+		{name: "duplicate command", shell: "bash", spec: Spec{Program: "rspp-cli", Commands: []Command{{Name: "report"}, {Name: "report"}}}},
+		{name: "unsafe word", shell: "zsh", spec: Spec{Program: "rspp-cli", Commands: []Command{{Name: "report", Flags: []string{"out;rm"}}}}},
+	}
+	for _, tc := range tests {
+		if _, err := Script(tc.shell, tc.spec); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package compliance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/recording"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+// SchemaVersion identifies the compliance report artifact layout.
+const SchemaVersion = "rspp-compliance-report/v1"
+
+// UnattributedTenant groups redaction evidence for sessions without a consent record, since
+// baseline evidence does not carry a tenant.
+const UnattributedTenant = "unattributed"
+
+// Exception kinds raised for auditor follow-up.
+const (
+	ExceptionRecordingWithoutConsent = "recording_without_consent"
+	ExceptionSensitivePayloadAllowed = "sensitive_payload_allowed"
+	ExceptionExpiredNotDeleted       = "expired_artifacts_not_deleted"
+	ExceptionRetentionPolicyFallback = "retention_policy_fallback"
+	ExceptionErasureIncomplete       = "erasure_incomplete"
+	ExceptionErasureMatchedNothing   = "erasure_matched_no_artifacts"
+)
+
+// Inputs lists the evidence files one report aggregates.
+type Inputs struct {
+	// RetentionSweepReports are rspp-runtime retention-sweep report JSON files.
+	RetentionSweepReports []string `json:"retention_sweep_reports,omitempty"`
+	// SessionAudio are stored session audio files; their consent flag is the consent record.
+	SessionAudio []string `json:"session_audio,omitempty"`
+	// BaselineArtifacts are OR-02 runtime baseline artifacts carrying redaction decisions.
+	BaselineArtifacts []string `json:"baseline_artifacts,omitempty"`
+	// ErasureCertificates are JSON arrays of replay erasure certificates.
+	ErasureCertificates []string `json:"erasure_certificates,omitempty"`
+}
+
+// LoadInputs reads an inputs config file.
+func LoadInputs(path string) (Inputs, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Inputs{}, fmt.Errorf("read compliance inputs %s: %w", path, err)
+	}
+	var inputs Inputs
+	if err := json.Unmarshal(raw, &inputs); err != nil {
+		return Inputs{}, fmt.Errorf("decode compliance inputs %s: %w", path, err)
+	}
+	if len(inputs.RetentionSweepReports)+len(inputs.SessionAudio)+len(inputs.BaselineArtifacts)+len(inputs.ErasureCertificates) == 0 {
+		return Inputs{}, fmt.Errorf("compliance inputs %s list no evidence files", path)
+	}
+	return inputs, nil
+}
+
+// Window bounds the report to [Start, End).
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (w Window) contains(ms int64) bool {
+	return ms >= w.Start.UnixMilli() && ms < w.End.UnixMilli()
+}
+
+// TenantSummary aggregates one tenant's evidence within the window.
+type TenantSummary struct {
+	TenantID           string         `json:"tenant_id"`
+	RetentionSweepRuns int            `json:"retention_sweep_runs"`
+	ArtifactsEvaluated int            `json:"artifacts_evaluated"`
+	ArtifactsExpired   int            `json:"artifacts_expired"`
+	ArtifactsDeleted   int            `json:"artifacts_deleted"`
+	DeletedByClass     map[string]int `json:"deleted_by_class,omitempty"`
+	ConsentRecords     int            `json:"consent_records"`
+	ConsentGranted     int            `json:"consent_granted"`
+	RedactedTurns      int            `json:"redacted_turns"`
+	RedactionByAction  map[string]int `json:"redaction_by_action,omitempty"`
+	Erasures           int            `json:"erasures"`
+	ErasedArtifacts    int            `json:"erased_artifacts"`
+	Exceptions         int            `json:"exceptions"`
+}
+
+// Exception is one finding an auditor must review.
+type Exception struct {
+	TenantID string `json:"tenant_id"`
+	Kind     string `json:"kind"`
+	Source   string `json:"source"`
+	Detail   string `json:"detail"`
+}
+
+// Report is the auditor-facing compliance artifact.
+type Report struct {
+	SchemaVersion  string          `json:"schema_version"`
+	GeneratedAtUTC string          `json:"generated_at_utc"`
+	Environment    string          `json:"environment,omitempty"`
+	WindowStartUTC string          `json:"window_start_utc"`
+	WindowEndUTC   string          `json:"window_end_utc"`
+	Inputs         Inputs          `json:"inputs"`
+	Tenants        []TenantSummary `json:"tenants"`
+	Exceptions     []Exception     `json:"exceptions"`
+}
+
+// retentionSweepReport is the subset of the rspp-runtime retention-sweep report a compliance
+// report reads.
+type retentionSweepReport struct {
+	RunResults []struct {
+		RunAtMS              int64  `json:"run_at_ms"`
+		PolicySource         string `json:"policy_source"`
+		PolicyFallbackReason string `json:"policy_fallback_reason,omitempty"`
+		TenantResults        []struct {
+			TenantID           string         `json:"tenant_id"`
+			EvaluatedArtifacts int            `json:"evaluated_artifacts"`
+			ExpiredArtifacts   int            `json:"expired_artifacts"`
+			DeletedArtifacts   int            `json:"deleted_artifacts"`
+			DeletedByClass     map[string]int `json:"deleted_by_class"`
+		} `json:"tenant_results"`
+	} `json:"run_results"`
+}
+
+type builder struct {
+	window     Window
+	tenants    map[string]*TenantSummary
+	exceptions []Exception
+}
+
+// Build aggregates inputs into a report. Retention sweep runs, erasure certificates, and turns
+// whose decisions were recorded inside the window are counted; consent records carry no
+// timestamp and are reported as on file.
+func Build(inputs Inputs, window Window, environment string, now time.Time) (Report, error) {
+	if !window.End.After(window.Start) {
+		return Report{}, fmt.Errorf("compliance window end must be after start")
+	}
+	b := &builder{window: window, tenants: map[string]*TenantSummary{}}
+
+	sessionTenants := map[string]string{}
+	for _, path := range inputs.SessionAudio {
+		audio, err := recording.LoadSessionAudio(path)
+		if err != nil {
+			return Report{}, err
+		}
+		sessionTenants[audio.SessionID] = audio.TenantID
+		summary := b.tenant(audio.TenantID)
+		summary.ConsentRecords++
+		if audio.ConsentGranted {
+			summary.ConsentGranted++
+		} else if len(audio.Segments) > 0 {
+			b.raise(audio.TenantID, ExceptionRecordingWithoutConsent, path, fmt.Sprintf("session %s has %d stored audio segments without consent", audio.SessionID, len(audio.Segments)))
+		}
+	}
+	for _, path := range inputs.RetentionSweepReports {
+		if err := b.addRetentionSweep(path); err != nil {
+			return Report{}, err
+		}
+	}
+	for _, path := range inputs.BaselineArtifacts {
+		artifact, err := timeline.ReadBaselineArtifact(path)
+		if err != nil {
+			return Report{}, fmt.Errorf("read baseline artifact %s: %w", path, err)
+		}
+		for _, entry := range artifact.Entries {
+			b.addRedactions(path, entry, sessionTenants)
+		}
+	}
+	for _, path := range inputs.ErasureCertificates {
+		certs, err := replay.ReadErasureCertificates(path)
+		if err != nil {
+			return Report{}, err
+		}
+		for _, cert := range certs {
+			b.addErasure(path, cert)
+		}
+	}
+
+	report := Report{
+		SchemaVersion:  SchemaVersion,
+		GeneratedAtUTC: now.UTC().Format(time.RFC3339),
+		Environment:    environment,
+		WindowStartUTC: window.Start.UTC().Format(time.RFC3339),
+		WindowEndUTC:   window.End.UTC().Format(time.RFC3339),
+		Inputs:         inputs,
+		Tenants:        make([]TenantSummary, 0, len(b.tenants)),
+		Exceptions:     b.exceptions,
+	}
+	if report.Exceptions == nil {
+		report.Exceptions = []Exception{}
+	}
+	for _, summary := range b.tenants {
+		report.Tenants = append(report.Tenants, *summary)
+	}
+	sort.Slice(report.Tenants, func(i, j int) bool { return report.Tenants[i].TenantID < report.Tenants[j].TenantID })
+	sort.SliceStable(report.Exceptions, func(i, j int) bool {
+		if report.Exceptions[i].TenantID != report.Exceptions[j].TenantID {
+			return report.Exceptions[i].TenantID < report.Exceptions[j].TenantID
+		}
+		return report.Exceptions[i].Kind < report.Exceptions[j].Kind
+	})
+	return report, nil
+}
+
+func (b *builder) tenant(tenantID string) *TenantSummary {
+	summary := b.tenants[tenantID]
+	if summary == nil {
+		summary = &TenantSummary{TenantID: tenantID}
+		b.tenants[tenantID] = summary
+	}
+	return summary
+}
+
+func (b *builder) raise(tenantID string, kind string, source string, detail string) {
+	b.exceptions = append(b.exceptions, Exception{TenantID: tenantID, Kind: kind, Source: source, Detail: detail})
+	b.tenant(tenantID).Exceptions++
+}
+
+func (b *builder) addRetentionSweep(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read retention sweep report %s: %w", path, err)
+	}
+	var report retentionSweepReport
+	if err := json.Unmarshal(raw, &report); err != nil {
+		return fmt.Errorf("decode retention sweep report %s: %w", path, err)
+	}
+	for _, run := range report.RunResults {
+		if !b.window.contains(run.RunAtMS) {
+			continue
+		}
+		for _, result := range run.TenantResults {
+			summary := b.tenant(result.TenantID)
+			summary.RetentionSweepRuns++
+			summary.ArtifactsEvaluated += result.EvaluatedArtifacts
+			summary.ArtifactsExpired += result.ExpiredArtifacts
+			summary.ArtifactsDeleted += result.DeletedArtifacts
+			for class, count := range result.DeletedByClass {
+				if summary.DeletedByClass == nil {
+					summary.DeletedByClass = map[string]int{}
+				}
+				summary.DeletedByClass[class] += count
+			}
+			if result.DeletedArtifacts < result.ExpiredArtifacts {
+				b.raise(result.TenantID, ExceptionExpiredNotDeleted, path, fmt.Sprintf("run at %d ms deleted %d of %d expired artifacts", run.RunAtMS, result.DeletedArtifacts, result.ExpiredArtifacts))
+			}
+			if run.PolicyFallbackReason != "" {
+				b.raise(result.TenantID, ExceptionRetentionPolicyFallback, path, fmt.Sprintf("run at %d ms used %s policy (%s)", run.RunAtMS, run.PolicySource, run.PolicyFallbackReason))
+			}
+		}
+	}
+	return nil
+}
+
+// addRedactions counts one turn's redaction decisions when any of its decisions was recorded
+// inside the window; raw PII/PHI persisted with an allow action is an exception.
+func (b *builder) addRedactions(path string, entry timeline.BaselineEvidence, sessionTenants map[string]string) {
+	inWindow := false
+	for _, decision := range entry.DecisionOutcomes {
+		if b.window.contains(decision.WallClockMS) {
+			inWindow = true
+			break
+		}
+	}
+	if !inWindow {
+		return
+	}
+	tenantID := sessionTenants[entry.SessionID]
+	if tenantID == "" {
+		tenantID = UnattributedTenant
+	}
+	summary := b.tenant(tenantID)
+	summary.RedactedTurns++
+	for _, decision := range entry.RedactionDecisions {
+		if summary.RedactionByAction == nil {
+			summary.RedactionByAction = map[string]int{}
+		}
+		summary.RedactionByAction[string(decision.Action)]++
+		if decision.Action == eventabi.RedactionAllow && (decision.PayloadClass == eventabi.PayloadPII || decision.PayloadClass == eventabi.PayloadPHI) {
+			b.raise(tenantID, ExceptionSensitivePayloadAllowed, path, fmt.Sprintf("session %s turn %s persisted %s without redaction", entry.SessionID, entry.TurnID, decision.PayloadClass))
+		}
+	}
+}
+
+func (b *builder) addErasure(path string, cert replay.ErasureCertificate) {
+	if !b.window.contains(cert.CompletedAtMS) {
+		return
+	}
+	summary := b.tenant(cert.TenantID)
+	summary.Erasures++
+	summary.ErasedArtifacts += cert.DeletedArtifacts + cert.CryptographicallyInaccessibleArtifacts
+	switch {
+	case cert.MatchedArtifacts == 0:
+		b.raise(cert.TenantID, ExceptionErasureMatchedNothing, path, fmt.Sprintf("certificate %s matched no artifacts", cert.CertificateID))
+	case !cert.Complete():
+		b.raise(cert.TenantID, ExceptionErasureIncomplete, path, fmt.Sprintf("certificate %s erased %d of %d matched artifacts", cert.CertificateID, cert.DeletedArtifacts+cert.CryptographicallyInaccessibleArtifacts, cert.MatchedArtifacts))
+	}
+}
@@ -0,0 +1,201 @@
+package compliance
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/recording"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+var (
+	windowStart = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	windowEnd   = time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	inWindowMS  = time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC).UnixMilli()
+	afterMS     = time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC).UnixMilli()
+)
+
+func writeJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func sessionAudio(sessionID string, tenantID string, consent bool) recording.SessionAudio {
+	return recording.SessionAudio{
+		SessionID:       sessionID,
+		TenantID:        tenantID,
+		PipelineVersion: "pipeline-v1",
+		ConsentGranted:  consent,
+		Segments:        []recording.AudioSegment{{Track: recording.TrackUser, EventID: "evt-audio", SampleRateHz: 16000, Channels: 1, PCM: []int16{1, 2}}},
+	}
+}
+
+func baselineEntry(sessionID string, turnID string, wallClockMS int64, redactions []eventabi.RedactionDecision) timeline.BaselineEvidence {
+	return timeline.BaselineEvidence{
+		SessionID:          sessionID,
+		TurnID:             turnID,
+		RedactionDecisions: redactions,
+		DecisionOutcomes:   []controlplane.DecisionOutcome{{SessionID: sessionID, TurnID: turnID, EventID: "evt-" + turnID, WallClockMS: wallClockMS}},
+	}
+}
+
+func complianceInputs(t *testing.T) Inputs {
+	t.Helper()
+	tmp := t.TempDir()
+
+	audioA := filepath.Join(tmp, "audio-a.json")
+	audioB := filepath.Join(tmp, "audio-b.json")
+	writeJSON(t, audioA, sessionAudio("sess-a", "tenant-a", true))
+	writeJSON(t, audioB, sessionAudio("sess-b", "tenant-b", false))
+
+	sweep := filepath.Join(tmp, "retention-sweep.json")
+	writeJSON(t, sweep, map[string]any{
+		"run_results": []map[string]any{
+			{
+				"run_at_ms":     inWindowMS,
+				"policy_source": "cp_distribution_file",
+				"tenant_results": []map[string]any{
+					{"tenant_id": "tenant-a", "evaluated_artifacts": 10, "expired_artifacts": 4, "deleted_artifacts": 4, "deleted_by_class": map[string]int{"audio_raw": 4}},
+					{"tenant_id": "tenant-b", "evaluated_artifacts": 5, "expired_artifacts": 2, "deleted_artifacts": 1},
+				},
+			},
+			{
+				"run_at_ms":              afterMS,
+				"policy_source":          "default_fallback",
+				"policy_fallback_reason": "cp_distribution_unconfigured",
+				"tenant_results":         []map[string]any{{"tenant_id": "tenant-a", "evaluated_artifacts": 99}},
+			},
+		},
+	})
+
+	baseline := filepath.Join(tmp, "baseline.json")
+	if err := timeline.WriteBaselineArtifact(baseline, []timeline.BaselineEvidence{
+		baselineEntry("sess-a", "turn-a1", inWindowMS, []eventabi.RedactionDecision{{PayloadClass: eventabi.PayloadPII, Action: eventabi.RedactionMask}}),
+		baselineEntry("sess-b", "turn-b1", inWindowMS, []eventabi.RedactionDecision{{PayloadClass: eventabi.PayloadPHI, Action: eventabi.RedactionAllow}}),
+		baselineEntry("sess-x", "turn-x1", inWindowMS, []eventabi.RedactionDecision{{PayloadClass: eventabi.PayloadMetadata, Action: eventabi.RedactionAllow}}),
+		baselineEntry("sess-a", "turn-a2", afterMS, []eventabi.RedactionDecision{{PayloadClass: eventabi.PayloadPII, Action: eventabi.RedactionAllow}}),
+	}); err != nil {
+		t.Fatalf("write baseline: %v", err)
+	}
+
+	issue := func(tenantID string, sessionID string, matched int, deleted int, completedAtMS int64) replay.ErasureCertificate {
+		scope := replay.DeletionScope{TenantID: tenantID, SessionID: sessionID}
+		cert, err := replay.IssueErasureCertificate(
+			replay.DeletionRequest{Scope: scope, Mode: replay.DeletionModeHardDelete, RequestedBy: "dpo-1", RequestedAtMS: completedAtMS - 1},
+			replay.DeletionResult{Scope: scope, Mode: replay.DeletionModeHardDelete, MatchedArtifacts: matched, DeletedArtifacts: deleted},
+			completedAtMS,
+		)
+		if err != nil {
+			t.Fatalf("issue certificate: %v", err)
+		}
+		return cert
+	}
+	certs := filepath.Join(tmp, "erasures.json")
+	writeJSON(t, certs, []replay.ErasureCertificate{
+		issue("tenant-a", "sess-a-old", 3, 3, inWindowMS),
+		issue("tenant-b", "sess-b-old", 0, 0, inWindowMS),
+		issue("tenant-b", "sess-b-late", 2, 2, afterMS),
+	})
+
+	return Inputs{
+		RetentionSweepReports: []string{sweep},
+		SessionAudio:          []string{audioA, audioB},
+		BaselineArtifacts:     []string{baseline},
+		ErasureCertificates:   []string{certs},
+	}
+}
+
+func TestBuildAggregatesPerTenant(t *testing.T) {
+	t.Parallel()
+
+	report, err := Build(complianceInputs(t), Window{Start: windowStart, End: windowEnd}, "staging", time.Date(2026, 4, 3, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+	if report.SchemaVersion != SchemaVersion || report.Environment != "staging" || report.WindowStartUTC != "2026-03-01T00:00:00Z" {
+		t.Fatalf("unexpected report header: %+v", report)
+	}
+	if len(report.Tenants) != 3 || report.Tenants[2].TenantID != UnattributedTenant {
+		t.Fatalf("expected tenant-a, tenant-b, and unattributed summaries, got %+v", report.Tenants)
+	}
+
+	tenantA, tenantB := report.Tenants[0], report.Tenants[1]
+	if tenantA.RetentionSweepRuns != 1 || tenantA.ArtifactsEvaluated != 10 || tenantA.DeletedByClass["audio_raw"] != 4 {
+		t.Fatalf("expected only the in-window sweep for tenant-a, got %+v", tenantA)
+	}
+	if tenantA.ConsentRecords != 1 || tenantA.ConsentGranted != 1 || tenantA.RedactedTurns != 1 || tenantA.RedactionByAction["mask"] != 1 {
+		t.Fatalf("expected tenant-a consent and in-window redaction evidence, got %+v", tenantA)
+	}
+	if tenantA.Erasures != 1 || tenantA.ErasedArtifacts != 3 || tenantA.Exceptions != 0 {
+		t.Fatalf("expected one clean tenant-a erasure, got %+v", tenantA)
+	}
+	if tenantB.Erasures != 1 || tenantB.Exceptions != 4 {
+		t.Fatalf("expected tenant-b in-window erasure and four exceptions, got %+v", tenantB)
+	}
+
+	wantKinds := []string{ExceptionErasureMatchedNothing, ExceptionExpiredNotDeleted, ExceptionRecordingWithoutConsent, ExceptionSensitivePayloadAllowed}
+	if len(report.Exceptions) != len(wantKinds) {
+		t.Fatalf("expected %d exceptions, got %+v", len(wantKinds), report.Exceptions)
+	}
+	for i, kind := range wantKinds {
+		if report.Exceptions[i].TenantID != "tenant-b" || report.Exceptions[i].Kind != kind || report.Exceptions[i].Source == "" {
+			t.Fatalf("exception %d: expected tenant-b %s, got %+v", i, kind, report.Exceptions[i])
+		}
+	}
+}
+
+func TestBuildRejectsInvalidInputs(t *testing.T) {
+	t.Parallel()
+
+	valid := complianceInputs(t)
+	tmp := t.TempDir()
+	badSweep := filepath.Join(tmp, "bad-sweep.json")
+	if err := os.WriteFile(badSweep, []byte("{"), 0o644); err != nil {
+		t.Fatalf("write bad sweep: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		inputs Inputs
+		window Window
+	}{
+		{name: "empty window", inputs: valid, window: Window{Start: windowEnd, End: windowStart}},
+		{name: "missing audio", inputs: Inputs{SessionAudio: []string{filepath.Join(tmp, "missing.json")}}, window: Window{Start: windowStart, End: windowEnd}},
+		{name: "bad sweep", inputs: Inputs{RetentionSweepReports: []string{badSweep}}, window: Window{Start: windowStart, End: windowEnd}},
+	}
+	for _, tc := range tests {
+		if _, err := Build(tc.inputs, tc.window, "", time.Now()); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
+
+func TestLoadInputs(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "inputs.json")
+	writeJSON(t, path, Inputs{BaselineArtifacts: []string{"baseline.json"}})
+	inputs, err := LoadInputs(path)
+	if err != nil || len(inputs.BaselineArtifacts) != 1 {
+		t.Fatalf("expected loaded inputs, got %+v (%v)", inputs, err)
+	}
+
+	empty := filepath.Join(tmp, "empty.json")
+	writeJSON(t, empty, Inputs{})
+	if _, err := LoadInputs(empty); err == nil {
+		t.Fatalf("expected empty inputs error")
+	}
+}
@@ -0,0 +1,296 @@
+package fixturesynth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+	replaycmp "github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/regression"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/replayshell"
+)
+
+const (
+	// BaselineFile and CandidateFile are the trace slice filenames inside a fixture directory.
+	BaselineFile  = "baseline.json"
+	CandidateFile = "candidate.json"
+	// DefaultTimingToleranceMS matches the tolerance of hand-written replay fixtures.
+	DefaultTimingToleranceMS int64 = 15
+)
+
+// ErrNoDivergence reports a baseline/candidate pair with nothing left to capture.
+var ErrNoDivergence = errors.New("no failing divergences between baseline and candidate")
+
+var fixtureIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// MetadataEntry is the scaffolded replay fixture metadata for an artifact-backed fixture.
+// Artifact paths are relative to the metadata file directory.
+type MetadataEntry struct {
+	Gate                string                          `json:"gate"`
+	TimingToleranceMS   *int64                          `json:"timing_tolerance_ms,omitempty"`
+	BaselineArtifact    string                          `json:"baseline_artifact"`
+	CandidateArtifact   string                          `json:"candidate_artifact"`
+	Source              string                          `json:"source,omitempty"`
+	ExpectedDivergences []regression.ExpectedDivergence `json:"expected_divergences"`
+}
+
+// Request selects the observed run to snapshot into a new fixture.
+type Request struct {
+	FixtureID     string
+	BaselinePath  string
+	CandidatePath string
+	MetadataPath  string
+	// Source is a free-form incident or run reference recorded in the metadata entry.
+	Source            string
+	TimingToleranceMS int64
+}
+
+// Result summarizes a synthesized fixture.
+type Result struct {
+	FixtureID      string
+	FixtureDir     string
+	Scopes         []string
+	BaselineTurns  int
+	CandidateTurns int
+	Entry          MetadataEntry
+	// Failing lists divergences the scaffolded entry cannot explain; the fixture fails its gate
+	// until they are triaged (ordering approval, authority fixes).
+	Failing []obs.ReplayDivergence
+}
+
+// Synthesize compares the candidate run to its baseline, snapshots the entries of every scope
+// with a failing divergence into <metadata dir>/<fixture id>/, and adds a metadata entry that
+// expects the observed plan, outcome, and ordering divergences. Ordering divergences are
+// scaffolded unapproved.
+func Synthesize(req Request) (Result, error) {
+	if !fixtureIDPattern.MatchString(req.FixtureID) {
+		return Result{}, fmt.Errorf("fixture id %q must match %s", req.FixtureID, fixtureIDPattern)
+	}
+	if req.MetadataPath == "" {
+		return Result{}, fmt.Errorf("metadata path is required")
+	}
+	tolerance := req.TimingToleranceMS
+	if tolerance <= 0 {
+		tolerance = DefaultTimingToleranceMS
+	}
+	metadata, fixtures, err := readMetadata(req.MetadataPath)
+	if err != nil {
+		return Result{}, err
+	}
+	if _, exists := fixtures[req.FixtureID]; exists {
+		return Result{}, fmt.Errorf("fixture %s already exists in %s", req.FixtureID, req.MetadataPath)
+	}
+	fixtureDir := filepath.Join(filepath.Dir(req.MetadataPath), req.FixtureID)
+	if _, err := os.Stat(fixtureDir); err == nil {
+		return Result{}, fmt.Errorf("fixture directory %s already exists", fixtureDir)
+	}
+
+	baseline, err := readEntries(req.BaselinePath)
+	if err != nil {
+		return Result{}, err
+	}
+	candidate, err := readEntries(req.CandidatePath)
+	if err != nil {
+		return Result{}, err
+	}
+	observed, err := Divergences(baseline, candidate, tolerance)
+	if err != nil {
+		return Result{}, err
+	}
+	failing := regression.EvaluateDivergences(observed, regression.DivergencePolicy{TimingToleranceMS: tolerance}).Failing
+	if len(failing) == 0 {
+		return Result{}, ErrNoDivergence
+	}
+
+	scopes := divergenceScopes(failing)
+	baselineSlice := sliceEntries(baseline, scopes)
+	candidateSlice := sliceEntries(candidate, scopes)
+	if len(baselineSlice) == 0 {
+		return Result{}, fmt.Errorf("baseline has no entries for divergent scopes %v", scopes)
+	}
+	// Re-derive divergences on the slices so the scaffold matches what the fixture replays.
+	sliced, err := Divergences(baselineSlice, candidateSlice, tolerance)
+	if err != nil {
+		return Result{}, err
+	}
+
+	entry := MetadataEntry{
+		Gate:                "full",
+		TimingToleranceMS:   &tolerance,
+		BaselineArtifact:    filepath.ToSlash(filepath.Join(req.FixtureID, BaselineFile)),
+		CandidateArtifact:   filepath.ToSlash(filepath.Join(req.FixtureID, CandidateFile)),
+		Source:              req.Source,
+		ExpectedDivergences: expectedDivergences(sliced),
+	}
+	evaluation := regression.EvaluateDivergences(sliced, regression.DivergencePolicy{TimingToleranceMS: tolerance, Expected: entry.ExpectedDivergences})
+
+	if err := timeline.WriteBaselineArtifact(filepath.Join(fixtureDir, BaselineFile), baselineSlice); err != nil {
+		return Result{}, fmt.Errorf("write fixture baseline: %w", err)
+	}
+	if err := timeline.WriteBaselineArtifact(filepath.Join(fixtureDir, CandidateFile), candidateSlice); err != nil {
+		return Result{}, fmt.Errorf("write fixture candidate: %w", err)
+	}
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return Result{}, err
+	}
+	fixtures[req.FixtureID] = raw
+	if err := writeMetadata(req.MetadataPath, metadata, fixtures); err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		FixtureID:      req.FixtureID,
+		FixtureDir:     fixtureDir,
+		Scopes:         scopes,
+		BaselineTurns:  len(baselineSlice),
+		CandidateTurns: len(candidateSlice),
+		Entry:          entry,
+		Failing:        evaluation.Failing,
+	}, nil
+}
+
+// Divergences compares candidate to baseline step by step and returns one divergence per
+// class and scope, so each can be matched by a single expected_divergences entry.
+func Divergences(baseline []timeline.BaselineEvidence, candidate []timeline.BaselineEvidence, timingToleranceMS int64) ([]obs.ReplayDivergence, error) {
+	return compareEntries(baseline, candidate, replaycmp.CompareConfig{TimingToleranceMS: timingToleranceMS})
+}
+
+func compareEntries(baseline []timeline.BaselineEvidence, candidate []timeline.BaselineEvidence, cfg replaycmp.CompareConfig) ([]obs.ReplayDivergence, error) {
+	if candidate == nil {
+		candidate = []timeline.BaselineEvidence{}
+	}
+	t, err := replayshell.Build(baseline, candidate, cfg)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]struct{}{}
+	out := make([]obs.ReplayDivergence, 0)
+	for _, step := range t.Steps {
+		for _, divergence := range step.Divergences {
+			key := string(divergence.Class) + "|" + divergence.Scope
+			if _, dup := seen[key]; dup {
+				continue
+			}
+			seen[key] = struct{}{}
+			out = append(out, divergence)
+		}
+	}
+	return out, nil
+}
+
+// ArtifactDivergences loads an artifact-backed fixture's trace slices, resolving paths
+// relative to the metadata file, and returns their divergences under cfg.
+func ArtifactDivergences(metadataPath string, baselineArtifact string, candidateArtifact string, cfg replaycmp.CompareConfig) ([]obs.ReplayDivergence, error) {
+	root := filepath.Dir(metadataPath)
+	baseline, err := readEntries(filepath.Join(root, filepath.FromSlash(baselineArtifact)))
+	if err != nil {
+		return nil, err
+	}
+	candidate, err := readEntries(filepath.Join(root, filepath.FromSlash(candidateArtifact)))
+	if err != nil {
+		return nil, err
+	}
+	return compareEntries(baseline, candidate, cfg)
+}
+
+func readEntries(path string) ([]timeline.BaselineEvidence, error) {
+	artifact, err := timeline.ReadBaselineArtifact(path)
+	if err != nil {
+		return nil, fmt.Errorf("read trace artifact %s: %w", path, err)
+	}
+	return artifact.Entries, nil
+}
+
+func divergenceScopes(divergences []obs.ReplayDivergence) []string {
+	seen := map[string]struct{}{}
+	scopes := make([]string, 0)
+	for _, divergence := range divergences {
+		if _, dup := seen[divergence.Scope]; dup {
+			continue
+		}
+		seen[divergence.Scope] = struct{}{}
+		scopes = append(scopes, divergence.Scope)
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// sliceEntries keeps entries whose turn or session is in scopes; a trace-wide scope keeps all.
+func sliceEntries(entries []timeline.BaselineEvidence, scopes []string) []timeline.BaselineEvidence {
+	wanted := make(map[string]struct{}, len(scopes))
+	for _, scope := range scopes {
+		if scope == "trace" {
+			return append([]timeline.BaselineEvidence(nil), entries...)
+		}
+		wanted[scope] = struct{}{}
+	}
+	out := make([]timeline.BaselineEvidence, 0)
+	for _, entry := range entries {
+		_, turn := wanted["turn:"+entry.TurnID]
+		_, session := wanted["session:"+entry.SessionID]
+		if (entry.TurnID != "" && turn) || session {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+func expectedDivergences(divergences []obs.ReplayDivergence) []regression.ExpectedDivergence {
+	out := make([]regression.ExpectedDivergence, 0)
+	for _, divergence := range divergences {
+		switch divergence.Class {
+		case obs.PlanDivergence, obs.OutcomeDivergence, obs.OrderingDivergence:
+			out = append(out, regression.ExpectedDivergence{Class: divergence.Class, Scope: divergence.Scope})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Scope != out[j].Scope {
+			return out[i].Scope < out[j].Scope
+		}
+		return out[i].Class < out[j].Class
+	})
+	return out
+}
+
+// readMetadata decodes the metadata file keeping unknown top-level keys and each fixture
+// entry verbatim, so hand-written entries survive the rewrite.
+func readMetadata(path string) (map[string]json.RawMessage, map[string]json.RawMessage, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read replay fixture metadata %s: %w", path, err)
+	}
+	var metadata map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &metadata); err != nil {
+		return nil, nil, fmt.Errorf("decode replay fixture metadata %s: %w", path, err)
+	}
+	fixtures := map[string]json.RawMessage{}
+	if existing, ok := metadata["fixtures"]; ok {
+		if err := json.Unmarshal(existing, &fixtures); err != nil {
+			return nil, nil, fmt.Errorf("decode replay fixture metadata %s fixtures: %w", path, err)
+		}
+	}
+	return metadata, fixtures, nil
+}
+
+func writeMetadata(path string, metadata map[string]json.RawMessage, fixtures map[string]json.RawMessage) error {
+	encoded, err := json.Marshal(fixtures)
+	if err != nil {
+		return err
+	}
+	metadata["fixtures"] = encoded
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write replay fixture metadata %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package fixturesynth
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+	replaycmp "github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/regression"
+)
+
+func synthEntry(turnID string, sequence string, atMS int64) timeline.BaselineEvidence {
+	return timeline.BaselineEvidence{
+		SessionID:       "sess-synth",
+		TurnID:          turnID,
+		PlanHash:        "plan-a",
+		AuthorityEpoch:  1,
+		OrderingMarkers: []string{"runtime_sequence:" + sequence},
+		DecisionOutcomes: []controlplane.DecisionOutcome{{
+			OutcomeKind:        controlplane.OutcomeAdmit,
+			Phase:              controlplane.PhasePreTurn,
+			Scope:              controlplane.ScopeTurn,
+			SessionID:          "sess-synth",
+			TurnID:             turnID,
+			EventID:            "evt-" + turnID,
+			RuntimeTimestampMS: atMS,
+			WallClockMS:        atMS,
+			EmittedBy:          controlplane.EmitterRK25,
+			Reason:             "admission_capacity_allow",
+		}},
+		TerminalOutcome: "commit",
+	}
+}
+
+func synthWorkspace(t *testing.T, mutate func([]timeline.BaselineEvidence)) Request {
+	t.Helper()
+	tmp := t.TempDir()
+	baseline := []timeline.BaselineEvidence{synthEntry("turn-1", "10", 100), synthEntry("turn-2", "20", 200), synthEntry("turn-3", "30", 300)}
+	candidate := []timeline.BaselineEvidence{synthEntry("turn-1", "10", 100), synthEntry("turn-2", "20", 200), synthEntry("turn-3", "30", 300)}
+	mutate(candidate)
+
+	req := Request{
+		FixtureID:     "inc-042-plan-drift",
+		BaselinePath:  filepath.Join(tmp, "live-baseline.json"),
+		CandidatePath: filepath.Join(tmp, "shadow-candidate.json"),
+		MetadataPath:  filepath.Join(tmp, "fixtures", "metadata.json"),
+		Source:        "incident INC-042",
+	}
+	if err := timeline.WriteBaselineArtifact(req.BaselinePath, baseline); err != nil {
+		t.Fatalf("write baseline: %v", err)
+	}
+	if err := timeline.WriteBaselineArtifact(req.CandidatePath, candidate); err != nil {
+		t.Fatalf("write candidate: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(req.MetadataPath), 0o755); err != nil {
+		t.Fatalf("mkdir metadata: %v", err)
+	}
+	metadata := `{"fixtures":{"rd-001-smoke":{"gate":"quick","timing_tolerance_ms":15,"expected_divergences":[]}}}`
+	if err := os.WriteFile(req.MetadataPath, []byte(metadata), 0o644); err != nil {
+		t.Fatalf("write metadata: %v", err)
+	}
+	return req
+}
+
+func TestSynthesizeSnapshotsDivergentScopes(t *testing.T) {
+	t.Parallel()
+
+	req := synthWorkspace(t, func(candidate []timeline.BaselineEvidence) {
+		candidate[1].PlanHash = "plan-b"
+	})
+	result, err := Synthesize(req)
+	if err != nil {
+		t.Fatalf("unexpected synthesize error: %v", err)
+	}
+	if len(result.Scopes) != 1 || result.Scopes[0] != "turn:turn-2" || result.BaselineTurns != 1 || result.CandidateTurns != 1 {
+		t.Fatalf("expected only turn-2 snapshotted, got %+v", result)
+	}
+	if len(result.Failing) != 0 {
+		t.Fatalf("expected scaffold to explain the plan divergence, got %+v", result.Failing)
+	}
+	want := regression.ExpectedDivergence{Class: obs.PlanDivergence, Scope: "turn:turn-2"}
+	if len(result.Entry.ExpectedDivergences) != 1 || result.Entry.ExpectedDivergences[0] != want {
+		t.Fatalf("expected scaffolded plan divergence, got %+v", result.Entry.ExpectedDivergences)
+	}
+
+	raw, err := os.ReadFile(req.MetadataPath)
+	if err != nil {
+		t.Fatalf("read metadata: %v", err)
+	}
+	var metadata struct {
+		Fixtures map[string]MetadataEntry `json:"fixtures"`
+	}
+	if err := json.Unmarshal(raw, &metadata); err != nil {
+		t.Fatalf("decode metadata: %v", err)
+	}
+	entry, ok := metadata.Fixtures[req.FixtureID]
+	if !ok || entry.Source != req.Source || entry.BaselineArtifact != "inc-042-plan-drift/baseline.json" || metadata.Fixtures["rd-001-smoke"].Gate != "quick" {
+		t.Fatalf("expected scaffolded entry alongside existing fixtures, got %+v", metadata.Fixtures)
+	}
+
+	divergences, err := ArtifactDivergences(req.MetadataPath, entry.BaselineArtifact, entry.CandidateArtifact, replaycmp.CompareConfig{TimingToleranceMS: *entry.TimingToleranceMS})
+	if err != nil {
+		t.Fatalf("unexpected artifact divergence error: %v", err)
+	}
+	evaluation := regression.EvaluateDivergences(divergences, regression.DivergencePolicy{TimingToleranceMS: *entry.TimingToleranceMS, Expected: entry.ExpectedDivergences})
+	if len(divergences) != 1 || len(evaluation.Failing) != 0 {
+		t.Fatalf("expected synthesized fixture to replay its divergence and pass, got %+v (%+v)", divergences, evaluation)
+	}
+
+	if _, err := Synthesize(req); err == nil {
+		t.Fatalf("expected duplicate fixture id to be rejected")
+	}
+}
+
+func TestSynthesizeReportsUntriagedDivergences(t *testing.T) {
+	t.Parallel()
+
+	req := synthWorkspace(t, func(candidate []timeline.BaselineEvidence) {
+		candidate[2].AuthorityEpoch = 2
+	})
+	result, err := Synthesize(req)
+	if err != nil {
+		t.Fatalf("unexpected synthesize error: %v", err)
+	}
+	if len(result.Failing) != 1 || result.Failing[0].Class != obs.AuthorityDivergence || result.Failing[0].Scope != "turn:turn-3" {
+		t.Fatalf("expected authority divergence left for triage, got %+v", result.Failing)
+	}
+}
+
+func TestSynthesizeRejectsInvalidRequests(t *testing.T) {
+	t.Parallel()
+
+	identical := synthWorkspace(t, func([]timeline.BaselineEvidence) {})
+	if _, err := Synthesize(identical); !errors.Is(err, ErrNoDivergence) {
+		t.Fatalf("expected ErrNoDivergence for identical runs, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*Request)
+	}{
+		{name: "bad fixture id", mutate: func(r *Request) { r.FixtureID = "INC 42" }},
+		{name: "missing metadata", mutate: func(r *Request) { r.MetadataPath = filepath.Join(t.TempDir(), "missing.json") }},
+		{name: "missing candidate", mutate: func(r *Request) { r.CandidatePath = filepath.Join(t.TempDir(), "missing.json") }},
+	}
+	for _, tc := range tests {
+		req := synthWorkspace(t, func(candidate []timeline.BaselineEvidence) { candidate[0].PlanHash = "plan-b" })
+		tc.mutate(&req)
+		if _, err := Synthesize(req); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package livecombo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Selection strategies.
+const (
+	StrategyFullMatrix      = "full_matrix"
+	StrategyPairwise        = "pairwise"
+	StrategyTrafficWeighted = "traffic_weighted"
+)
+
+// Environment knobs read by ConfigFromEnv.
+const (
+	EnvStrategy      = "RSPP_LIVE_COMBO_STRATEGY"
+	EnvSeed          = "RSPP_LIVE_COMBO_SEED"
+	EnvMaxCombos     = "RSPP_LIVE_COMBO_MAX"
+	EnvTrafficShares = "RSPP_LIVE_COMBO_TRAFFIC_SHARES"
+)
+
+// DefaultSeed keeps runs reproducible when no seed is configured.
+const DefaultSeed int64 = 1
+
+// Dimension is one chain stage (e.g. a modality) and its candidate providers.
+type Dimension struct {
+	Name      string   `json:"name"`
+	Providers []string `json:"providers"`
+}
+
+// Config chooses how chain combinations are selected.
+type Config struct {
+	Strategy string `json:"strategy"`
+	Seed     int64  `json:"seed"`
+	// MaxCombos caps the selection; 0 means no cap. full_matrix fails instead of truncating,
+	// pairwise truncates and reports reduced pair coverage, traffic_weighted requires it.
+	MaxCombos int `json:"max_combos,omitempty"`
+	// TrafficShares maps provider ID to its production traffic share; a combination's weight is
+	// the product of its providers' shares. Providers without a share weigh 0.
+	TrafficShares map[string]float64 `json:"traffic_shares,omitempty"`
+}
+
+// Combo is one provider per dimension, keyed by dimension name.
+type Combo map[string]string
+
+// Selection is the chosen combinations plus the coverage they achieve.
+type Selection struct {
+	Strategy   string      `json:"strategy"`
+	Seed       int64       `json:"seed"`
+	MaxCombos  int         `json:"max_combos,omitempty"`
+	Dimensions []Dimension `json:"dimensions"`
+	Combos     []Combo     `json:"combos"`
+	// MatrixSize is the size of the full cartesian product.
+	MatrixSize int `json:"matrix_size"`
+	// PairCoverage is the fraction of cross-dimension provider pairs exercised by Combos.
+	PairCoverage float64 `json:"pair_coverage"`
+	// TrafficCoverage is the share of modeled production traffic covered, when shares are configured.
+	TrafficCoverage *float64 `json:"traffic_coverage,omitempty"`
+}
+
+// Validate enforces config invariants.
+func (c Config) Validate() error {
+	switch c.Strategy {
+	case StrategyFullMatrix, StrategyPairwise:
+	case StrategyTrafficWeighted:
+		if c.MaxCombos < 1 {
+			return fmt.Errorf("%s strategy requires max_combos>=1", c.Strategy)
+		}
+		if len(c.TrafficShares) == 0 {
+			return fmt.Errorf("%s strategy requires traffic_shares", c.Strategy)
+		}
+	default:
+		return fmt.Errorf("unsupported live combo strategy: %q", c.Strategy)
+	}
+	if c.MaxCombos < 0 {
+		return fmt.Errorf("max_combos must be >=0")
+	}
+	for provider, share := range c.TrafficShares {
+		if share < 0 || math.IsNaN(share) || math.IsInf(share, 0) {
+			return fmt.Errorf("traffic share for %s must be a finite value >=0", provider)
+		}
+	}
+	return nil
+}
+
+// ConfigFromEnv reads the selection config, defaulting to an uncapped pairwise selection.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{Strategy: StrategyPairwise, Seed: DefaultSeed}
+	if raw := strings.TrimSpace(os.Getenv(EnvStrategy)); raw != "" {
+		cfg.Strategy = raw
+	}
+	if raw := strings.TrimSpace(os.Getenv(EnvSeed)); raw != "" {
+		seed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", EnvSeed, err)
+		}
+		cfg.Seed = seed
+	}
+	if raw := strings.TrimSpace(os.Getenv(EnvMaxCombos)); raw != "" {
+		maxCombos, err := strconv.Atoi(raw)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", EnvMaxCombos, err)
+		}
+		cfg.MaxCombos = maxCombos
+	}
+	if path := strings.TrimSpace(os.Getenv(EnvTrafficShares)); path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", EnvTrafficShares, err)
+		}
+		if err := json.Unmarshal(raw, &cfg.TrafficShares); err != nil {
+			return Config{}, fmt.Errorf("decode traffic shares %s: %w", path, err)
+		}
+	}
+	return cfg, cfg.Validate()
+}
+
+// Select picks chain combinations across dims according to cfg. The same dims, config, and
+// seed always yield the same selection.
+func Select(dims []Dimension, cfg Config) (Selection, error) {
+	if err := cfg.Validate(); err != nil {
+		return Selection{}, err
+	}
+	if len(dims) == 0 {
+		return Selection{}, fmt.Errorf("live combo selection requires at least one dimension")
+	}
+	normalized := make([]Dimension, len(dims))
+	seen := map[string]struct{}{}
+	for i, dim := range dims {
+		if dim.Name == "" || len(dim.Providers) == 0 {
+			return Selection{}, fmt.Errorf("live combo dimension %d requires a name and providers", i)
+		}
+		if _, ok := seen[dim.Name]; ok {
+			return Selection{}, fmt.Errorf("duplicate live combo dimension: %s", dim.Name)
+		}
+		seen[dim.Name] = struct{}{}
+		providers := append([]string(nil), dim.Providers...)
+		sort.Strings(providers)
+		normalized[i] = Dimension{Name: dim.Name, Providers: providers}
+	}
+
+	matrix := cartesian(normalized)
+	rng := rand.New(rand.NewPCG(uint64(cfg.Seed), uint64(cfg.Seed)^0x9e3779b97f4a7c15))
+	var chosen [][]int
+	switch cfg.Strategy {
+	case StrategyFullMatrix:
+		if cfg.MaxCombos > 0 && len(matrix) > cfg.MaxCombos {
+			return Selection{}, fmt.Errorf("full matrix has %d combinations, exceeding max_combos=%d", len(matrix), cfg.MaxCombos)
+		}
+		chosen = matrix
+	case StrategyPairwise:
+		chosen = pairwise(normalized, matrix, rng, cfg.MaxCombos)
+	case StrategyTrafficWeighted:
+		chosen = trafficWeighted(normalized, matrix, rng, cfg)
+		if len(chosen) == 0 {
+			return Selection{}, fmt.Errorf("no combination has a positive traffic share")
+		}
+	}
+
+	selection := Selection{
+		Strategy:     cfg.Strategy,
+		Seed:         cfg.Seed,
+		MaxCombos:    cfg.MaxCombos,
+		Dimensions:   normalized,
+		Combos:       make([]Combo, 0, len(chosen)),
+		MatrixSize:   len(matrix),
+		PairCoverage: pairCoverage(normalized, chosen),
+	}
+	for _, indices := range chosen {
+		selection.Combos = append(selection.Combos, toCombo(normalized, indices))
+	}
+	if len(cfg.TrafficShares) > 0 {
+		total := 0.0
+		for _, indices := range matrix {
+			total += weight(normalized, indices, cfg.TrafficShares)
+		}
+		covered := 0.0
+		for _, indices := range chosen {
+			covered += weight(normalized, indices, cfg.TrafficShares)
+		}
+		ratio := 0.0
+		if total > 0 {
+			ratio = covered / total
+		}
+		selection.TrafficCoverage = &ratio
+	}
+	return selection, nil
+}
+
+// Key renders a combination in dimension order, e.g. "stt-a|llm-b|tts-c".
+func (s Selection) Key(combo Combo) string {
+	parts := make([]string, 0, len(s.Dimensions))
+	for _, dim := range s.Dimensions {
+		parts = append(parts, combo[dim.Name])
+	}
+	return strings.Join(parts, "|")
+}
+
+func cartesian(dims []Dimension) [][]int {
+	out := [][]int{{}}
+	for _, dim := range dims {
+		next := make([][]int, 0, len(out)*len(dim.Providers))
+		for _, prefix := range out {
+			for i := range dim.Providers {
+				next = append(next, append(append([]int(nil), prefix...), i))
+			}
+		}
+		out = next
+	}
+	return out
+}
+
+type pairKey struct {
+	dimA, optA, dimB, optB int
+}
+
+func pairsOf(indices []int) []pairKey {
+	pairs := make([]pairKey, 0, len(indices)*(len(indices)-1)/2)
+	for a := 0; a < len(indices); a++ {
+		for b := a + 1; b < len(indices); b++ {
+			pairs = append(pairs, pairKey{dimA: a, optA: indices[a], dimB: b, optB: indices[b]})
+		}
+	}
+	return pairs
+}
+
+func totalPairs(dims []Dimension) int {
+	total := 0
+	for a := 0; a < len(dims); a++ {
+		for b := a + 1; b < len(dims); b++ {
+			total += len(dims[a].Providers) * len(dims[b].Providers)
+		}
+	}
+	return total
+}
+
+// pairwise greedily builds a covering array: each round takes the matrix row covering the most
+// uncovered pairs, breaking ties by a seeded shuffle of the matrix.
+func pairwise(dims []Dimension, matrix [][]int, rng *rand.Rand, maxCombos int) [][]int {
+	if len(dims) < 2 {
+		return matrix
+	}
+	order := rng.Perm(len(matrix))
+	uncovered := map[pairKey]struct{}{}
+	for _, indices := range matrix {
+		for _, pair := range pairsOf(indices) {
+			uncovered[pair] = struct{}{}
+		}
+	}
+	used := make([]bool, len(matrix))
+	chosen := make([][]int, 0)
+	for len(uncovered) > 0 && (maxCombos == 0 || len(chosen) < maxCombos) {
+		best, bestGain := -1, 0
+		for _, idx := range order {
+			if used[idx] {
+				continue
+			}
+			gain := 0
+			for _, pair := range pairsOf(matrix[idx]) {
+				if _, ok := uncovered[pair]; ok {
+					gain++
+				}
+			}
+			if gain > bestGain {
+				best, bestGain = idx, gain
+			}
+		}
+		if best < 0 {
+			break
+		}
+		used[best] = true
+		chosen = append(chosen, matrix[best])
+		for _, pair := range pairsOf(matrix[best]) {
+			delete(uncovered, pair)
+		}
+	}
+	return chosen
+}
+
+// trafficWeighted samples combinations without replacement, proportional to traffic weight,
+// using seeded exponential keys (Efraimidis-Spirakis).
+func trafficWeighted(dims []Dimension, matrix [][]int, rng *rand.Rand, cfg Config) [][]int {
+	type keyed struct {
+		indices []int
+		key     float64
+	}
+	candidates := make([]keyed, 0, len(matrix))
+	for _, indices := range matrix {
+		// Draw for every row, even zero-weight ones, so a share change does not reshuffle others.
+		u := rng.Float64()
+		w := weight(dims, indices, cfg.TrafficShares)
+		if w <= 0 {
+			continue
+		}
+		candidates = append(candidates, keyed{indices: indices, key: math.Log(u) / w})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].key > candidates[j].key })
+	if len(candidates) > cfg.MaxCombos {
+		candidates = candidates[:cfg.MaxCombos]
+	}
+	chosen := make([][]int, 0, len(candidates))
+	for _, candidate := range candidates {
+		chosen = append(chosen, candidate.indices)
+	}
+	return chosen
+}
+
+func weight(dims []Dimension, indices []int, shares map[string]float64) float64 {
+	w := 1.0
+	for d, idx := range indices {
+		w *= shares[dims[d].Providers[idx]]
+	}
+	return w
+}
+
+func pairCoverage(dims []Dimension, chosen [][]int) float64 {
+	total := totalPairs(dims)
+	if total == 0 {
+		if len(chosen) > 0 {
+			return 1
+		}
+		return 0
+	}
+	covered := map[pairKey]struct{}{}
+	for _, indices := range chosen {
+		for _, pair := range pairsOf(indices) {
+			covered[pair] = struct{}{}
+		}
+	}
+	return float64(len(covered)) / float64(total)
+}
+
+func toCombo(dims []Dimension, indices []int) Combo {
+	combo := make(Combo, len(dims))
+	for d, idx := range indices {
+		combo[dims[d].Name] = dims[d].Providers[idx]
+	}
+	return combo
+}
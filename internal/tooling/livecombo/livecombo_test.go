@@ -0,0 +1,135 @@
+package livecombo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func chainDimensions() []Dimension {
+	return []Dimension{
+		{Name: "stt", Providers: []string{"stt-deepgram", "stt-google", "stt-assemblyai"}},
+		{Name: "llm", Providers: []string{"llm-anthropic", "llm-gemini", "llm-cohere"}},
+		{Name: "tts", Providers: []string{"tts-elevenlabs", "tts-google", "tts-amazon-polly"}},
+	}
+}
+
+func TestSelectStrategies(t *testing.T) {
+	t.Parallel()
+
+	shares := map[string]float64{
+		"stt-deepgram": 0.7, "stt-google": 0.3,
+		"llm-anthropic": 0.5, "llm-gemini": 0.4, "llm-cohere": 0.1,
+		"tts-elevenlabs": 0.8, "tts-google": 0.2,
+	}
+	tests := []struct {
+		name         string
+		cfg          Config
+		wantCombos   int
+		wantPairs    float64
+		wantErr      bool
+		wantTraffic  bool
+		maxPairCombo int
+	}{
+		{name: "full matrix", cfg: Config{Strategy: StrategyFullMatrix, Seed: 1}, wantCombos: 27, wantPairs: 1},
+		{name: "full matrix over cap", cfg: Config{Strategy: StrategyFullMatrix, Seed: 1, MaxCombos: 10}, wantErr: true},
+		{name: "pairwise covers all pairs", cfg: Config{Strategy: StrategyPairwise, Seed: 7}, wantPairs: 1, maxPairCombo: 12},
+		{name: "pairwise truncated", cfg: Config{Strategy: StrategyPairwise, Seed: 7, MaxCombos: 3}, wantCombos: 3},
+		{name: "traffic weighted", cfg: Config{Strategy: StrategyTrafficWeighted, Seed: 3, MaxCombos: 4, TrafficShares: shares}, wantCombos: 4, wantTraffic: true},
+		{name: "traffic weighted requires cap", cfg: Config{Strategy: StrategyTrafficWeighted, Seed: 3, TrafficShares: shares}, wantErr: true},
+		{name: "unknown strategy", cfg: Config{Strategy: "first_n"}, wantErr: true},
+	}
+	for _, tc := range tests {
+		selection, err := Select(chainDimensions(), tc.cfg)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected error, got %+v", tc.name, selection)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if selection.Strategy != tc.cfg.Strategy || selection.Seed != tc.cfg.Seed || selection.MatrixSize != 27 {
+			t.Fatalf("%s: expected strategy/seed recorded, got %+v", tc.name, selection)
+		}
+		if tc.wantCombos > 0 && len(selection.Combos) != tc.wantCombos {
+			t.Fatalf("%s: expected %d combos, got %d", tc.name, tc.wantCombos, len(selection.Combos))
+		}
+		if tc.maxPairCombo > 0 && len(selection.Combos) > tc.maxPairCombo {
+			t.Fatalf("%s: expected at most %d combos, got %d", tc.name, tc.maxPairCombo, len(selection.Combos))
+		}
+		if tc.wantPairs > 0 && selection.PairCoverage != tc.wantPairs {
+			t.Fatalf("%s: expected pair coverage %v, got %v", tc.name, tc.wantPairs, selection.PairCoverage)
+		}
+		if tc.wantPairs == 0 && selection.PairCoverage >= 1 {
+			t.Fatalf("%s: expected partial pair coverage, got %v", tc.name, selection.PairCoverage)
+		}
+		if tc.wantTraffic {
+			if selection.TrafficCoverage == nil || *selection.TrafficCoverage <= 0 || *selection.TrafficCoverage > 1 {
+				t.Fatalf("%s: expected traffic coverage in (0,1], got %+v", tc.name, selection.TrafficCoverage)
+			}
+			for _, combo := range selection.Combos {
+				if weight(selection.Dimensions, indicesOf(selection, combo), shares) <= 0 {
+					t.Fatalf("%s: selected zero-traffic combo %s", tc.name, selection.Key(combo))
+				}
+			}
+		}
+	}
+}
+
+func TestSelectDeterministicPerSeed(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{Strategy: StrategyPairwise, Seed: 42}
+	first, err := Select(chainDimensions(), cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reordered := chainDimensions()
+	reordered[0].Providers = []string{"stt-google", "stt-assemblyai", "stt-deepgram"}
+	second, err := Select(reordered, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("expected identical selection for identical seed, got %+v vs %+v", first.Combos, second.Combos)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	sharesPath := filepath.Join(t.TempDir(), "shares.json")
+	if err := os.WriteFile(sharesPath, []byte(`{"stt-deepgram":1,"llm-anthropic":1,"tts-google":1}`), 0o644); err != nil {
+		t.Fatalf("write shares: %v", err)
+	}
+	t.Setenv(EnvStrategy, StrategyTrafficWeighted)
+	t.Setenv(EnvSeed, "99")
+	t.Setenv(EnvMaxCombos, "2")
+	t.Setenv(EnvTrafficShares, sharesPath)
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Strategy != StrategyTrafficWeighted || cfg.Seed != 99 || cfg.MaxCombos != 2 || cfg.TrafficShares["tts-google"] != 1 {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+
+	t.Setenv(EnvSeed, "not-a-number")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Fatalf("expected invalid seed error")
+	}
+}
+
+func indicesOf(selection Selection, combo Combo) []int {
+	indices := make([]int, len(selection.Dimensions))
+	for d, dim := range selection.Dimensions {
+		for i, provider := range dim.Providers {
+			if combo[dim.Name] == provider {
+				indices[d] = i
+			}
+		}
+	}
+	return indices
+}
@@ -0,0 +1,463 @@
+package llmeval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/invocation"
+)
+
+// DefaultSuitePath is the repository-relative LLM answer-quality eval suite.
+const DefaultSuitePath = "test/quality/fixtures/llm_eval_suite.json"
+
+const (
+	// ProviderResponderMode runs suite prompts through the configured LLM providers.
+	ProviderResponderMode = "providers"
+	// RecordedResponderMode scores the suite recorded responses without calling a provider.
+	RecordedResponderMode = "recorded"
+)
+
+// AssertionKind enumerates deterministic rubric assertion types.
+type AssertionKind string
+
+const (
+	AssertContains    AssertionKind = "contains"
+	AssertNotContains AssertionKind = "not_contains"
+	AssertRegex       AssertionKind = "regex"
+	AssertNotRegex    AssertionKind = "not_regex"
+)
+
+// Assertion is one weighted rubric criterion evaluated against a response.
+type Assertion struct {
+	Kind   AssertionKind `json:"kind"`
+	Value  string        `json:"value"`
+	Weight float64       `json:"weight,omitempty"`
+}
+
+// Validate enforces assertion shape requirements.
+func (a Assertion) Validate() error {
+	switch a.Kind {
+	case AssertContains, AssertNotContains:
+		if strings.TrimSpace(a.Value) == "" {
+			return fmt.Errorf("%s assertion requires value", a.Kind)
+		}
+	case AssertRegex, AssertNotRegex:
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return fmt.Errorf("%s assertion has invalid pattern: %w", a.Kind, err)
+		}
+	default:
+		return fmt.Errorf("unsupported assertion kind: %q", a.Kind)
+	}
+	if a.Weight < 0 {
+		return fmt.Errorf("assertion weight must be >=0")
+	}
+	return nil
+}
+
+// Fixture is one prompt with its rubric and optional recorded provider responses.
+type Fixture struct {
+	FixtureID         string            `json:"fixture_id"`
+	Prompt            string            `json:"prompt"`
+	Rubric            []Assertion       `json:"rubric"`
+	RecordedResponses map[string]string `json:"recorded_responses,omitempty"`
+}
+
+// Thresholds define pass criteria for one provider.
+type Thresholds struct {
+	MinMeanScore float64 `json:"min_mean_score"`
+	MinPassRate  float64 `json:"min_pass_rate"`
+}
+
+// Suite groups eval fixtures with per-fixture and per-provider pass thresholds.
+type Suite struct {
+	FixturePassScore     float64               `json:"fixture_pass_score"`
+	DefaultThresholds    Thresholds            `json:"default_thresholds"`
+	ThresholdsByProvider map[string]Thresholds `json:"thresholds_by_provider,omitempty"`
+	Fixtures             []Fixture             `json:"fixtures"`
+}
+
+// Validate enforces suite shape requirements.
+func (s Suite) Validate() error {
+	if len(s.Fixtures) == 0 {
+		return fmt.Errorf("llm eval suite requires at least one fixture")
+	}
+	if s.FixturePassScore < 0 || s.FixturePassScore > 1 {
+		return fmt.Errorf("fixture_pass_score must be within [0,1]")
+	}
+	if err := validateThresholds(s.DefaultThresholds); err != nil {
+		return fmt.Errorf("default_thresholds: %w", err)
+	}
+	for providerID, thresholds := range s.ThresholdsByProvider {
+		if providerID == "" {
+			return fmt.Errorf("thresholds_by_provider requires non-empty provider ids")
+		}
+		if err := validateThresholds(thresholds); err != nil {
+			return fmt.Errorf("thresholds_by_provider[%s]: %w", providerID, err)
+		}
+	}
+	seen := make(map[string]struct{}, len(s.Fixtures))
+	for _, fixture := range s.Fixtures {
+		if fixture.FixtureID == "" || strings.TrimSpace(fixture.Prompt) == "" {
+			return fmt.Errorf("llm eval fixture_id and prompt are required")
+		}
+		if _, ok := seen[fixture.FixtureID]; ok {
+			return fmt.Errorf("duplicate llm eval fixture_id: %s", fixture.FixtureID)
+		}
+		seen[fixture.FixtureID] = struct{}{}
+		for _, assertion := range fixture.Rubric {
+			if err := assertion.Validate(); err != nil {
+				return fmt.Errorf("fixture %s: %w", fixture.FixtureID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ThresholdsFor returns the pass thresholds configured for a provider.
+func (s Suite) ThresholdsFor(providerID string) Thresholds {
+	if thresholds, ok := s.ThresholdsByProvider[providerID]; ok {
+		return thresholds
+	}
+	return s.DefaultThresholds
+}
+
+// RecordedProviderIDs returns provider ids that have recorded responses in the suite.
+func (s Suite) RecordedProviderIDs() []string {
+	seen := map[string]struct{}{}
+	for _, fixture := range s.Fixtures {
+		for providerID := range fixture.RecordedResponses {
+			seen[providerID] = struct{}{}
+		}
+	}
+	ids := make([]string, 0, len(seen))
+	for providerID := range seen {
+		ids = append(ids, providerID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// LoadSuite reads an eval suite from disk.
+func LoadSuite(path string) (Suite, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Suite{}, fmt.Errorf("read llm eval suite %s: %w", path, err)
+	}
+	var suite Suite
+	if err := json.Unmarshal(raw, &suite); err != nil {
+		return Suite{}, fmt.Errorf("decode llm eval suite %s: %w", path, err)
+	}
+	if err := suite.Validate(); err != nil {
+		return Suite{}, fmt.Errorf("validate llm eval suite %s: %w", path, err)
+	}
+	return suite, nil
+}
+
+// Responder runs one eval prompt through an LLM node.
+type Responder interface {
+	Respond(providerID string, fixture Fixture) (string, error)
+}
+
+// RecordedResponder plays back responses recorded alongside the suite.
+type RecordedResponder struct{}
+
+// Respond returns the recorded response for the provider and fixture.
+func (RecordedResponder) Respond(providerID string, fixture Fixture) (string, error) {
+	response, ok := fixture.RecordedResponses[providerID]
+	if !ok {
+		return "", fmt.Errorf("no recorded response for provider=%s fixture=%s", providerID, fixture.FixtureID)
+	}
+	return response, nil
+}
+
+// Invoker is the provider invocation path eval prompts run through.
+type Invoker interface {
+	Invoke(in invocation.InvocationInput) (invocation.InvocationResult, error)
+}
+
+// InvocationResponder runs eval prompts through the provider invocation controller, pinned to
+// the provider under evaluation.
+type InvocationResponder struct {
+	Invoker         Invoker
+	PipelineVersion string
+}
+
+// Respond invokes providerID with the fixture prompt and returns the reply text.
+func (r InvocationResponder) Respond(providerID string, fixture Fixture) (string, error) {
+	if r.Invoker == nil {
+		return "", fmt.Errorf("llm eval invoker is required")
+	}
+	pipelineVersion := r.PipelineVersion
+	if pipelineVersion == "" {
+		pipelineVersion = "llm-eval"
+	}
+	result, err := r.Invoker.Invoke(invocation.InvocationInput{
+		SessionID:              "llm-eval",
+		TurnID:                 "llm-eval-" + fixture.FixtureID,
+		PipelineVersion:        pipelineVersion,
+		EventID:                "llm-eval-" + providerID + "-" + fixture.FixtureID,
+		Modality:               contracts.ModalityLLM,
+		PreferredProvider:      providerID,
+		AllowedAdaptiveActions: []string{"retry"},
+		TransportSequence:      1,
+		RuntimeSequence:        1,
+		AuthorityEpoch:         1,
+		RuntimeTimestampMS:     1,
+		WallClockTimestampMS:   1,
+		Prompt:                 fixture.Prompt,
+	})
+	if err != nil {
+		return "", fmt.Errorf("invoke provider %s: %w", providerID, err)
+	}
+	if result.Outcome.Class != contracts.OutcomeSuccess {
+		return "", fmt.Errorf("provider %s outcome %s (%s)", providerID, result.Outcome.Class, result.Outcome.Reason)
+	}
+	if result.SelectedProvider != providerID {
+		return "", fmt.Errorf("provider %s was not invoked; selected %s", providerID, result.SelectedProvider)
+	}
+	response := strings.TrimSpace(result.Outcome.Text)
+	if response == "" {
+		return "", fmt.Errorf("provider %s returned no response text", providerID)
+	}
+	return response, nil
+}
+
+// LLMJudge grades responses by prompting a judge provider through a Responder.
+type LLMJudge struct {
+	Responder  Responder
+	ProviderID string
+}
+
+// judgeInstructions asks for a score on the first line so the reply parses without a schema.
+const judgeInstructions = "You grade a voice assistant's answer. Reply with a score between 0 and 1 on the first line, then one sentence of rationale.\n\nUser request: %s\n\nAssistant answer: %s"
+
+// Grade asks the judge provider to score response against prompt.
+func (j LLMJudge) Grade(prompt string, response string) (float64, string, error) {
+	if j.Responder == nil || j.ProviderID == "" {
+		return 0, "", fmt.Errorf("llm judge responder and provider are required")
+	}
+	reply, err := j.Responder.Respond(j.ProviderID, Fixture{
+		FixtureID: "judge",
+		Prompt:    fmt.Sprintf(judgeInstructions, prompt, response),
+	})
+	if err != nil {
+		return 0, "", fmt.Errorf("llm judge %s: %w", j.ProviderID, err)
+	}
+	return ParseJudgeReply(reply)
+}
+
+// ParseJudgeReply reads a judge reply's first-line score and the remaining rationale.
+func ParseJudgeReply(reply string) (float64, string, error) {
+	first, rest, _ := strings.Cut(strings.TrimSpace(reply), "\n")
+	field := strings.TrimRight(strings.TrimSpace(first), ".")
+	if prefix, value, ok := strings.Cut(field, ":"); ok && strings.EqualFold(strings.TrimSpace(prefix), "score") {
+		field = strings.TrimSpace(value)
+	}
+	value, err := strconv.ParseFloat(field, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("llm judge reply has no score on its first line: %q", first)
+	}
+	return value, strings.TrimSpace(rest), nil
+}
+
+// Score is a rubric evaluation result in [0,1] with the criteria that failed.
+type Score struct {
+	Value  float64  `json:"value"`
+	Failed []string `json:"failed,omitempty"`
+}
+
+// Scorer grades a response against a fixture rubric.
+type Scorer interface {
+	Score(fixture Fixture, response string) (Score, error)
+}
+
+// AssertionScorer grades responses with the fixture's keyword/regex assertions.
+// Contains checks are case-insensitive. Unweighted assertions count as weight 1.
+type AssertionScorer struct{}
+
+// Score returns the weighted fraction of satisfied assertions.
+func (AssertionScorer) Score(fixture Fixture, response string) (Score, error) {
+	if len(fixture.Rubric) == 0 {
+		return Score{Value: 1}, nil
+	}
+	lowered := strings.ToLower(response)
+	total := 0.0
+	earned := 0.0
+	score := Score{}
+	for _, assertion := range fixture.Rubric {
+		weight := assertion.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		total += weight
+
+		var ok bool
+		switch assertion.Kind {
+		case AssertContains:
+			ok = strings.Contains(lowered, strings.ToLower(assertion.Value))
+		case AssertNotContains:
+			ok = !strings.Contains(lowered, strings.ToLower(assertion.Value))
+		case AssertRegex, AssertNotRegex:
+			pattern, err := regexp.Compile(assertion.Value)
+			if err != nil {
+				return Score{}, fmt.Errorf("compile %s assertion: %w", assertion.Kind, err)
+			}
+			ok = pattern.MatchString(response) == (assertion.Kind == AssertRegex)
+		default:
+			return Score{}, fmt.Errorf("unsupported assertion kind: %q", assertion.Kind)
+		}
+		if ok {
+			earned += weight
+		} else {
+			score.Failed = append(score.Failed, fmt.Sprintf("%s:%s", assertion.Kind, assertion.Value))
+		}
+	}
+	score.Value = earned / total
+	return score, nil
+}
+
+// Judge is a secondary LLM that grades a response against a prompt on a [0,1] scale.
+type Judge interface {
+	Grade(prompt string, response string) (float64, string, error)
+}
+
+// JudgeScorer grades responses with a secondary LLM judge.
+type JudgeScorer struct {
+	Judge Judge
+}
+
+// Score delegates grading to the configured judge.
+func (s JudgeScorer) Score(fixture Fixture, response string) (Score, error) {
+	if s.Judge == nil {
+		return Score{}, fmt.Errorf("llm judge is required")
+	}
+	value, rationale, err := s.Judge.Grade(fixture.Prompt, response)
+	if err != nil {
+		return Score{}, err
+	}
+	if value < 0 || value > 1 {
+		return Score{}, fmt.Errorf("llm judge score %.3f outside [0,1]", value)
+	}
+	score := Score{Value: value}
+	if rationale != "" {
+		score.Failed = []string{rationale}
+	}
+	return score, nil
+}
+
+// StrictestScorer grades with every scorer and keeps the lowest score, so a response must
+// satisfy both the rubric assertions and the judge.
+type StrictestScorer struct {
+	Scorers []Scorer
+}
+
+// Score returns the minimum score with the failed criteria of every scorer.
+func (s StrictestScorer) Score(fixture Fixture, response string) (Score, error) {
+	if len(s.Scorers) == 0 {
+		return Score{}, fmt.Errorf("llm eval scorers are required")
+	}
+	combined := Score{Value: 1}
+	for _, scorer := range s.Scorers {
+		score, err := scorer.Score(fixture, response)
+		if err != nil {
+			return Score{}, err
+		}
+		combined.Value = min(combined.Value, score.Value)
+		combined.Failed = append(combined.Failed, score.Failed...)
+	}
+	return combined, nil
+}
+
+// FixtureResult is the per-provider, per-fixture eval outcome.
+type FixtureResult struct {
+	FixtureID string `json:"fixture_id"`
+	Score     Score  `json:"score"`
+	Passed    bool   `json:"passed"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ProviderResult aggregates rubric scores for one provider across the suite.
+type ProviderResult struct {
+	ProviderID string          `json:"provider_id"`
+	Thresholds Thresholds      `json:"thresholds"`
+	MeanScore  float64         `json:"mean_score"`
+	PassRate   float64         `json:"pass_rate"`
+	Fixtures   []FixtureResult `json:"fixtures"`
+	Passed     bool            `json:"passed"`
+}
+
+// Report is the LLM answer-quality eval result attachable to release readiness.
+type Report struct {
+	Providers  []ProviderResult `json:"providers"`
+	Violations []string         `json:"violations,omitempty"`
+	Passed     bool             `json:"passed"`
+}
+
+// Evaluate runs suite prompts through each provider and scores responses against thresholds.
+// Responder and scorer errors count as zero-score, failing fixtures.
+func Evaluate(suite Suite, providerIDs []string, responder Responder, scorer Scorer) Report {
+	report := Report{}
+	if responder == nil || scorer == nil {
+		report.Violations = append(report.Violations, "llm eval responder and scorer are required")
+		return report
+	}
+
+	ids := append([]string(nil), providerIDs...)
+	sort.Strings(ids)
+	if len(ids) == 0 {
+		report.Violations = append(report.Violations, "no llm providers configured for eval")
+	}
+
+	for _, providerID := range ids {
+		provider := ProviderResult{
+			ProviderID: providerID,
+			Thresholds: suite.ThresholdsFor(providerID),
+			Fixtures:   make([]FixtureResult, 0, len(suite.Fixtures)),
+		}
+		scoreTotal := 0.0
+		passed := 0
+		for _, fixture := range suite.Fixtures {
+			result := FixtureResult{FixtureID: fixture.FixtureID}
+			response, err := responder.Respond(providerID, fixture)
+			if err == nil {
+				result.Score, err = scorer.Score(fixture, response)
+			}
+			if err != nil {
+				result.Error = err.Error()
+				report.Violations = append(report.Violations, fmt.Sprintf("provider %s fixture %s eval failed: %v", providerID, fixture.FixtureID, err))
+			} else {
+				result.Passed = result.Score.Value >= suite.FixturePassScore
+			}
+			scoreTotal += result.Score.Value
+			if result.Passed {
+				passed++
+			}
+			provider.Fixtures = append(provider.Fixtures, result)
+		}
+		if len(suite.Fixtures) > 0 {
+			provider.MeanScore = scoreTotal / float64(len(suite.Fixtures))
+			provider.PassRate = float64(passed) / float64(len(suite.Fixtures))
+		}
+		provider.Passed = provider.MeanScore >= provider.Thresholds.MinMeanScore && provider.PassRate >= provider.Thresholds.MinPassRate
+		if !provider.Passed {
+			report.Violations = append(report.Violations, fmt.Sprintf("provider %s mean_score=%.3f pass_rate=%.3f below thresholds min_mean_score=%.3f min_pass_rate=%.3f", providerID, provider.MeanScore, provider.PassRate, provider.Thresholds.MinMeanScore, provider.Thresholds.MinPassRate))
+		}
+		report.Providers = append(report.Providers, provider)
+	}
+
+	report.Passed = len(report.Violations) == 0
+	return report
+}
+
+func validateThresholds(t Thresholds) error {
+	if t.MinMeanScore < 0 || t.MinMeanScore > 1 || t.MinPassRate < 0 || t.MinPassRate > 1 {
+		return fmt.Errorf("min_mean_score and min_pass_rate must be within [0,1]")
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package llmeval
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/invocation"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+)
+
+func TestAssertionScorerWeightsCriteria(t *testing.T) {
+	t.Parallel()
+
+	fixture := Fixture{
+		FixtureID: "fx-1",
+		Prompt:    "confirm booking",
+		Rubric: []Assertion{
+			{Kind: AssertContains, Value: "Two"},
+			{Kind: AssertRegex, Value: `\b7\b`, Weight: 2},
+			{Kind: AssertNotContains, Value: "sorry"},
+			{Kind: AssertNotRegex, Value: `\d{16}`},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		response string
+		want     float64
+		failed   int
+	}{
+		{name: "all criteria", response: "Table for two at 7.", want: 1, failed: 0},
+		{name: "missing weighted regex", response: "Table for two at seven.", want: 0.6, failed: 1},
+		{name: "forbidden content", response: "Sorry, two at 7 with card 4111111111111111", want: 0.6, failed: 2},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			score, err := AssertionScorer{}.Score(fixture, tc.response)
+			if err != nil {
+				t.Fatalf("unexpected score error: %v", err)
+			}
+			if score.Value != tc.want || len(score.Failed) != tc.failed {
+				t.Fatalf("expected score=%.2f failed=%d, got %+v", tc.want, tc.failed, score)
+			}
+		})
+	}
+}
+
+func TestEvaluateAppliesProviderThresholds(t *testing.T) {
+	t.Parallel()
+
+	suite := Suite{
+		FixturePassScore:     0.5,
+		DefaultThresholds:    Thresholds{MinMeanScore: 0.5, MinPassRate: 0.5},
+		ThresholdsByProvider: map[string]Thresholds{"llm-strict": {MinMeanScore: 0.9, MinPassRate: 1}},
+		Fixtures: []Fixture{
+			{
+				FixtureID: "fx-1",
+				Prompt:    "say hello",
+				Rubric:    []Assertion{{Kind: AssertContains, Value: "hello"}},
+				RecordedResponses: map[string]string{
+					"llm-lenient": "hello there",
+					"llm-strict":  "hello there",
+				},
+			},
+			{
+				FixtureID: "fx-2",
+				Prompt:    "say goodbye",
+				Rubric:    []Assertion{{Kind: AssertContains, Value: "goodbye"}},
+				RecordedResponses: map[string]string{
+					"llm-lenient": "see you",
+					"llm-strict":  "see you",
+				},
+			},
+		},
+	}
+
+	report := Evaluate(suite, []string{"llm-strict", "llm-lenient"}, RecordedResponder{}, AssertionScorer{})
+	if report.Passed || len(report.Violations) != 1 {
+		t.Fatalf("expected one strict-provider violation, got %+v", report)
+	}
+	if report.Providers[0].ProviderID != "llm-lenient" || !report.Providers[0].Passed {
+		t.Fatalf("expected lenient provider to pass, got %+v", report.Providers[0])
+	}
+	if strict := report.Providers[1]; strict.Passed || strict.MeanScore != 0.5 || strict.PassRate != 0.5 {
+		t.Fatalf("expected strict provider mean/pass-rate 0.5 failure, got %+v", strict)
+	}
+}
+
+func TestEvaluateWithJudgeScorer(t *testing.T) {
+	t.Parallel()
+
+	suite := Suite{
+		FixturePassScore:  0.7,
+		DefaultThresholds: Thresholds{MinMeanScore: 0.7, MinPassRate: 1},
+		Fixtures:          []Fixture{{FixtureID: "fx-1", Prompt: "explain", RecordedResponses: map[string]string{"llm-a": "answer"}}},
+	}
+
+	report := Evaluate(suite, []string{"llm-a"}, RecordedResponder{}, JudgeScorer{Judge: fixedJudge{score: 0.8}})
+	if !report.Passed {
+		t.Fatalf("expected judge-scored suite to pass, got %+v", report)
+	}
+	report = Evaluate(suite, []string{"llm-a"}, RecordedResponder{}, JudgeScorer{Judge: fixedJudge{err: errors.New("judge offline")}})
+	if report.Passed || report.Providers[0].Fixtures[0].Error == "" {
+		t.Fatalf("expected judge error to fail fixture, got %+v", report)
+	}
+}
+
+func TestInvocationResponderRunsPromptsThroughProviders(t *testing.T) {
+	t.Parallel()
+
+	reply := func(id string, text string) contracts.Adapter {
+		return contracts.StaticAdapter{ID: id, Mode: contracts.ModalityLLM, InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+			if req.ProviderID != id {
+				return contracts.Outcome{}, errors.New("unexpected provider " + req.ProviderID)
+			}
+			if text == "" {
+				return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+			}
+			if strings.HasPrefix(req.Prompt, "You grade") {
+				return contracts.Outcome{Class: contracts.OutcomeSuccess, Text: "Score: 0.4\nToo terse."}, nil
+			}
+			return contracts.Outcome{Class: contracts.OutcomeSuccess, Text: text + " " + req.Prompt}, nil
+		}}
+	}
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		reply("llm-a", "hello:"),
+		reply("llm-b", ""),
+		contracts.StaticAdapter{ID: "llm-down", Mode: contracts.ModalityLLM, InvokeFn: func(contracts.InvocationRequest) (contracts.Outcome, error) {
+			return contracts.Outcome{Class: contracts.OutcomeBlocked, Reason: "provider_api_key_missing"}, nil
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	responder := InvocationResponder{Invoker: invocation.NewController(catalog)}
+
+	suite := Suite{
+		FixturePassScore:  0.5,
+		DefaultThresholds: Thresholds{MinMeanScore: 0.5, MinPassRate: 1},
+		Fixtures:          []Fixture{{FixtureID: "fx-1", Prompt: "greet me", Rubric: []Assertion{{Kind: AssertContains, Value: "hello"}}}},
+	}
+	report := Evaluate(suite, []string{"llm-a", "llm-down"}, responder, AssertionScorer{})
+	if report.Passed || len(report.Providers) != 2 {
+		t.Fatalf("expected blocked provider to fail the report, got %+v", report)
+	}
+	if a := report.Providers[0]; !a.Passed || a.MeanScore != 1 {
+		t.Fatalf("expected llm-a prompt reply to satisfy the rubric, got %+v", a)
+	}
+	if down := report.Providers[1].Fixtures[0]; !strings.Contains(down.Error, "provider_api_key_missing") {
+		t.Fatalf("expected blocked outcome error, got %+v", down)
+	}
+
+	judged := StrictestScorer{Scorers: []Scorer{AssertionScorer{}, JudgeScorer{Judge: LLMJudge{Responder: responder, ProviderID: "llm-a"}}}}
+	score, err := judged.Score(suite.Fixtures[0], "hello there")
+	if err != nil || score.Value != 0.4 || len(score.Failed) != 1 || score.Failed[0] != "Too terse." {
+		t.Fatalf("expected judge score 0.4 with rationale, got %+v err=%v", score, err)
+	}
+	if _, _, err := (LLMJudge{Responder: responder, ProviderID: "llm-b"}).Grade("p", "r"); err == nil {
+		t.Fatalf("expected empty judge reply to be rejected")
+	}
+}
+
+func TestParseJudgeReply(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		reply     string
+		want      float64
+		rationale string
+		wantErr   bool
+	}{
+		{reply: "0.75\nMostly correct.", want: 0.75, rationale: "Mostly correct."},
+		{reply: "  Score: 1.\n", want: 1},
+		{reply: "Great answer, 0.9", wantErr: true},
+	}
+	for _, tc := range tests {
+		value, rationale, err := ParseJudgeReply(tc.reply)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("%q: expected error=%v, got %v", tc.reply, tc.wantErr, err)
+		}
+		if !tc.wantErr && (value != tc.want || rationale != tc.rationale) {
+			t.Fatalf("%q: expected %.2f %q, got %.2f %q", tc.reply, tc.want, tc.rationale, value, rationale)
+		}
+	}
+}
+
+func TestSuiteValidateRejectsBadAssertions(t *testing.T) {
+	t.Parallel()
+
+	suite := Suite{
+		FixturePassScore: 0.5,
+		Fixtures:         []Fixture{{FixtureID: "fx-1", Prompt: "p", Rubric: []Assertion{{Kind: AssertRegex, Value: "("}}}},
+	}
+	if err := suite.Validate(); err == nil {
+		t.Fatalf("expected invalid regex assertion to be rejected")
+	}
+	suite.Fixtures[0].Rubric = []Assertion{{Kind: "semantic", Value: "x"}}
+	if err := suite.Validate(); err == nil {
+		t.Fatalf("expected unsupported assertion kind to be rejected")
+	}
+}
+
+func TestDefaultSuitePasses(t *testing.T) {
+	t.Parallel()
+
+	suite, err := LoadSuite(filepath.Join("..", "..", "..", DefaultSuitePath))
+	if err != nil {
+		t.Fatalf("unexpected suite load error: %v", err)
+	}
+	report := Evaluate(suite, suite.RecordedProviderIDs(), RecordedResponder{}, AssertionScorer{})
+	if !report.Passed {
+		t.Fatalf("expected recorded eval suite to pass, got %+v", report.Violations)
+	}
+}
+
+type fixedJudge struct {
+	score float64
+	err   error
+}
+
+func (j fixedJudge) Grade(string, string) (float64, string, error) {
+	return j.score, "", j.err
+}
@@ -0,0 +1,42 @@
+package ops
+
+import "github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+
+// TurnMetricsFromBaseline converts runtime baseline evidence into SLO gate samples.
+func TurnMetricsFromBaseline(entries []timeline.BaselineEvidence) []TurnMetrics {
+	samples := make([]TurnMetrics, 0, len(entries))
+	for _, entry := range entries {
+		terminalEvents := []string{entry.TerminalOutcome}
+		if entry.CloseEmitted {
+			terminalEvents = append(terminalEvents, "close")
+		}
+		sample := TurnMetrics{
+			TurnID:                   entry.TurnID,
+			PipelineVersion:          entry.PipelineVersion,
+			Accepted:                 entry.IsAcceptedTurn(),
+			HappyPath:                entry.TurnOpenAtMS != nil && entry.FirstOutputAtMS != nil,
+			TurnOpenProposedAtMS:     entry.TurnOpenProposedAtMS,
+			TurnOpenAtMS:             entry.TurnOpenAtMS,
+			FirstOutputAtMS:          entry.FirstOutputAtMS,
+			CancelAcceptedAtMS:       entry.CancelAcceptedAtMS,
+			CancelFenceAppliedAtMS:   entry.CancelFenceAppliedAtMS,
+			BaselineComplete:         entry.ValidateCompleteness() == nil,
+			AcceptedStaleEpochOutput: entry.AcceptedStaleEpochOutput,
+			TerminalEvents:           terminalEvents,
+			LengthCapped:             baselineLengthCapped(entry),
+			FirstAudioPlayedAtMS:     entry.FirstAudioPlayedAtMS,
+			PlaybackCancelled:        entry.PlaybackCancelledAtMS != nil,
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+func baselineLengthCapped(entry timeline.BaselineEvidence) bool {
+	for _, outcome := range entry.InvocationOutcomes {
+		if outcome.LengthCapped {
+			return true
+		}
+	}
+	return false
+}
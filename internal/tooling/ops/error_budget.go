@@ -0,0 +1,156 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+)
+
+// BurnSeverity classifies burn-rate alerts.
+type BurnSeverity string
+
+const (
+	BurnSeverityFast BurnSeverity = "fast_burn"
+	BurnSeveritySlow BurnSeverity = "slow_burn"
+)
+
+// SLOEvent is one good/bad observation counted against an SLO objective.
+type SLOEvent struct {
+	AtMS int64
+	Good bool
+}
+
+// ErrorBudgetPolicy defines the objective, budget window, and multi-window burn thresholds.
+type ErrorBudgetPolicy struct {
+	Objective         float64 `json:"objective"`
+	BudgetWindowMS    int64   `json:"budget_window_ms"`
+	FastBurnWindowMS  int64   `json:"fast_burn_window_ms"`
+	FastBurnThreshold float64 `json:"fast_burn_threshold"`
+	SlowBurnWindowMS  int64   `json:"slow_burn_window_ms"`
+	SlowBurnThreshold float64 `json:"slow_burn_threshold"`
+}
+
+// DefaultErrorBudgetPolicy returns a 99% objective over 30 days with 1h/6h burn windows.
+func DefaultErrorBudgetPolicy() ErrorBudgetPolicy {
+	const hourMS = int64(60 * 60 * 1000)
+	return ErrorBudgetPolicy{
+		Objective:         0.99,
+		BudgetWindowMS:    30 * 24 * hourMS,
+		FastBurnWindowMS:  hourMS,
+		FastBurnThreshold: 14.4,
+		SlowBurnWindowMS:  6 * hourMS,
+		SlowBurnThreshold: 6,
+	}
+}
+
+// Validate enforces policy bounds.
+func (p ErrorBudgetPolicy) Validate() error {
+	if p.Objective <= 0 || p.Objective >= 1 {
+		return fmt.Errorf("error budget objective must be in (0,1)")
+	}
+	if p.BudgetWindowMS < 1 || p.FastBurnWindowMS < 1 || p.SlowBurnWindowMS < 1 {
+		return fmt.Errorf("error budget windows must be >=1ms")
+	}
+	if p.FastBurnWindowMS > p.SlowBurnWindowMS || p.SlowBurnWindowMS > p.BudgetWindowMS {
+		return fmt.Errorf("error budget windows must satisfy fast <= slow <= budget")
+	}
+	if p.FastBurnThreshold <= 0 || p.SlowBurnThreshold <= 0 {
+		return fmt.Errorf("burn thresholds must be >0")
+	}
+	return nil
+}
+
+// BurnRateAlert is raised when a window's burn rate exceeds its threshold.
+type BurnRateAlert struct {
+	Severity  BurnSeverity `json:"severity"`
+	WindowMS  int64        `json:"window_ms"`
+	BurnRate  float64      `json:"burn_rate"`
+	Threshold float64      `json:"threshold"`
+}
+
+// ErrorBudgetReport summarizes budget consumption and burn rates at evaluation time.
+type ErrorBudgetReport struct {
+	Policy               ErrorBudgetPolicy `json:"policy"`
+	EvaluatedAtMS        int64             `json:"evaluated_at_ms"`
+	TotalEvents          int               `json:"total_events"`
+	BadEvents            int               `json:"bad_events"`
+	BudgetConsumedRatio  float64           `json:"budget_consumed_ratio"`
+	BudgetRemainingRatio float64           `json:"budget_remaining_ratio"`
+	FastBurnRate         float64           `json:"fast_burn_rate"`
+	SlowBurnRate         float64           `json:"slow_burn_rate"`
+	Alerts               []BurnRateAlert   `json:"alerts,omitempty"`
+}
+
+// EvaluateErrorBudget accounts budget consumption within the budget window ending at
+// nowMS and computes fast/slow burn rates. A burn rate of 1 spends exactly the budget over
+// the budget window; alerts fire when a window's rate exceeds its threshold.
+func EvaluateErrorBudget(events []SLOEvent, policy ErrorBudgetPolicy, nowMS int64) (ErrorBudgetReport, error) {
+	if err := policy.Validate(); err != nil {
+		return ErrorBudgetReport{}, err
+	}
+	report := ErrorBudgetReport{Policy: policy, EvaluatedAtMS: nowMS}
+	allowedBadRatio := 1 - policy.Objective
+
+	var fastTotal, fastBad, slowTotal, slowBad int
+	for _, event := range events {
+		age := nowMS - event.AtMS
+		if age < 0 || age >= policy.BudgetWindowMS {
+			continue
+		}
+		report.TotalEvents++
+		if !event.Good {
+			report.BadEvents++
+		}
+		if age < policy.SlowBurnWindowMS {
+			slowTotal++
+			if !event.Good {
+				slowBad++
+			}
+		}
+		if age < policy.FastBurnWindowMS {
+			fastTotal++
+			if !event.Good {
+				fastBad++
+			}
+		}
+	}
+
+	if report.TotalEvents > 0 {
+		report.BudgetConsumedRatio = (float64(report.BadEvents) / float64(report.TotalEvents)) / allowedBadRatio
+	}
+	report.BudgetRemainingRatio = 1 - report.BudgetConsumedRatio
+	report.FastBurnRate = burnRate(fastBad, fastTotal, allowedBadRatio)
+	report.SlowBurnRate = burnRate(slowBad, slowTotal, allowedBadRatio)
+
+	if report.FastBurnRate > policy.FastBurnThreshold {
+		report.Alerts = append(report.Alerts, BurnRateAlert{Severity: BurnSeverityFast, WindowMS: policy.FastBurnWindowMS, BurnRate: report.FastBurnRate, Threshold: policy.FastBurnThreshold})
+	}
+	if report.SlowBurnRate > policy.SlowBurnThreshold {
+		report.Alerts = append(report.Alerts, BurnRateAlert{Severity: BurnSeveritySlow, WindowMS: policy.SlowBurnWindowMS, BurnRate: report.SlowBurnRate, Threshold: policy.SlowBurnThreshold})
+	}
+	return report, nil
+}
+
+// EmitBurnRateAlerts publishes burn-rate alerts as telemetry log events.
+func EmitBurnRateAlerts(report ErrorBudgetReport, sloName string) {
+	for _, alert := range report.Alerts {
+		telemetry.DefaultEmitter().EmitLog(
+			"slo_burn_rate_alert",
+			"warn",
+			fmt.Sprintf("%s %s burn_rate=%.2f exceeds threshold=%.2f", sloName, alert.Severity, alert.BurnRate, alert.Threshold),
+			map[string]string{
+				"slo":       sloName,
+				"severity":  string(alert.Severity),
+				"window_ms": fmt.Sprintf("%d", alert.WindowMS),
+			},
+			telemetry.Correlation{EmittedBy: "OR-01", WallClockTimestampMS: report.EvaluatedAtMS},
+		)
+	}
+}
+
+func burnRate(bad int, total int, allowedBadRatio float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return (float64(bad) / float64(total)) / allowedBadRatio
+}
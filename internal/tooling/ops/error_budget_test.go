@@ -0,0 +1,89 @@
+package ops
+
+import "testing"
+
+func TestEvaluateErrorBudgetBurnRates(t *testing.T) {
+	t.Parallel()
+
+	policy := ErrorBudgetPolicy{
+		Objective:         0.9,
+		BudgetWindowMS:    10_000,
+		FastBurnWindowMS:  1_000,
+		FastBurnThreshold: 5,
+		SlowBurnWindowMS:  5_000,
+		SlowBurnThreshold: 2,
+	}
+
+	tests := []struct {
+		name           string
+		events         []SLOEvent
+		wantConsumed   float64
+		wantFast       float64
+		wantSlow       float64
+		wantSeverities []BurnSeverity
+	}{
+		{
+			name:         "old failures consume budget without burn alerts",
+			events:       []SLOEvent{{AtMS: 9_500, Good: true}, {AtMS: 8_000, Good: true}, {AtMS: 2_000, Good: true}, {AtMS: 1_000, Good: false}},
+			wantConsumed: 2.5,
+		},
+		{
+			name:           "fast and slow burn",
+			events:         []SLOEvent{{AtMS: 9_500, Good: false}, {AtMS: 9_400, Good: false}, {AtMS: 7_000, Good: true}, {AtMS: 6_000, Good: true}},
+			wantConsumed:   5,
+			wantFast:       10,
+			wantSlow:       5,
+			wantSeverities: []BurnSeverity{BurnSeverityFast, BurnSeveritySlow},
+		},
+		{
+			name:         "events outside budget window ignored",
+			events:       []SLOEvent{{AtMS: -1, Good: false}, {AtMS: 9_900, Good: true}},
+			wantConsumed: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			report, err := EvaluateErrorBudget(tc.events, policy, 10_000)
+			if err != nil {
+				t.Fatalf("unexpected error budget error: %v", err)
+			}
+			if !almostEqual(report.BudgetConsumedRatio, tc.wantConsumed) || !almostEqual(report.FastBurnRate, tc.wantFast) || !almostEqual(report.SlowBurnRate, tc.wantSlow) {
+				t.Fatalf("expected consumed=%.2f fast=%.2f slow=%.2f, got %+v", tc.wantConsumed, tc.wantFast, tc.wantSlow, report)
+			}
+			if len(report.Alerts) != len(tc.wantSeverities) {
+				t.Fatalf("expected alerts %v, got %+v", tc.wantSeverities, report.Alerts)
+			}
+			for i, severity := range tc.wantSeverities {
+				if report.Alerts[i].Severity != severity {
+					t.Fatalf("expected alert %d severity %s, got %+v", i, severity, report.Alerts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestErrorBudgetPolicyValidate(t *testing.T) {
+	t.Parallel()
+
+	if err := DefaultErrorBudgetPolicy().Validate(); err != nil {
+		t.Fatalf("expected default policy valid, got %v", err)
+	}
+	invalid := DefaultErrorBudgetPolicy()
+	invalid.FastBurnWindowMS = invalid.SlowBurnWindowMS + 1
+	if err := invalid.Validate(); err == nil {
+		t.Fatalf("expected fast window larger than slow window to fail")
+	}
+	invalid = DefaultErrorBudgetPolicy()
+	invalid.Objective = 1
+	if err := invalid.Validate(); err == nil {
+		t.Fatalf("expected objective=1 to fail")
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	diff := a - b
+	return diff < 1e-9 && diff > -1e-9
+}
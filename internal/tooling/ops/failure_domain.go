@@ -0,0 +1,180 @@
+package ops
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+// UnattributedFailureDomain labels failed turns without a tagged or inferable domain.
+const UnattributedFailureDomain = "unattributed"
+
+// DefaultFailureDomainBucketMS is the default blast-radius time bucket width.
+const DefaultFailureDomainBucketMS int64 = 60_000
+
+// FailedInvocation is one non-success provider invocation observed in a turn. RetryDecision
+// records how the runtime moved on from it (none, retry, provider_switch, fallback).
+type FailedInvocation struct {
+	Modality      string
+	ProviderID    string
+	OutcomeClass  string
+	RetryDecision string
+}
+
+// TurnFailureSample captures per-turn evidence used for failure-domain attribution.
+type TurnFailureSample struct {
+	SessionID         string
+	TurnID            string
+	AtMS              int64
+	TerminalOutcome   string
+	TerminalReason    string
+	Decisions         []controlplane.DecisionOutcome
+	FailedInvocations []FailedInvocation
+	Signals           []eventabi.ControlSignal
+}
+
+// FailedTurnAttribution records the domain a failed turn was charged to.
+type FailedTurnAttribution struct {
+	SessionID string `json:"session_id"`
+	TurnID    string `json:"turn_id,omitempty"`
+	AtMS      int64  `json:"at_ms"`
+	Domain    string `json:"failure_domain"`
+	Reason    string `json:"reason"`
+}
+
+// FailureDomainBucket aggregates failed turns per domain within one time window.
+type FailureDomainBucket struct {
+	StartMS     int64          `json:"start_ms"`
+	FailedTurns int            `json:"failed_turns"`
+	ByDomain    map[string]int `json:"by_domain"`
+}
+
+// FailureDomainReport attributes failed turns to failure domains over time.
+type FailureDomainReport struct {
+	BucketMS     int64                   `json:"bucket_ms"`
+	TotalTurns   int                     `json:"total_turns"`
+	FailedTurns  int                     `json:"failed_turns"`
+	ByDomain     map[string]int          `json:"by_domain"`
+	Buckets      []FailureDomainBucket   `json:"buckets"`
+	Attributions []FailedTurnAttribution `json:"attributions"`
+}
+
+// AttributeFailureDomains charges each failed turn to one failure domain and buckets the
+// results by bucketMS. A turn fails when admission/authority did not admit it, when it
+// terminated with abort, or when a provider invocation ended non-success and was not
+// recovered by a retry or provider switch in a turn that went on to finish. Attribution
+// prefers the earliest non-admit decision, then unrecovered provider invocations, then
+// recorded control signals.
+func AttributeFailureDomains(samples []TurnFailureSample, bucketMS int64) (FailureDomainReport, error) {
+	if bucketMS < 1 {
+		return FailureDomainReport{}, fmt.Errorf("failure domain bucket_ms must be >=1")
+	}
+	report := FailureDomainReport{
+		BucketMS:     bucketMS,
+		TotalTurns:   len(samples),
+		ByDomain:     make(map[string]int),
+		Buckets:      make([]FailureDomainBucket, 0),
+		Attributions: make([]FailedTurnAttribution, 0),
+	}
+	for _, domain := range eventabi.FailureDomains() {
+		report.ByDomain[string(domain)] = 0
+	}
+
+	buckets := make(map[int64]*FailureDomainBucket)
+	for _, sample := range samples {
+		attribution, failed := attributeTurn(sample)
+		if !failed {
+			continue
+		}
+		report.FailedTurns++
+		report.ByDomain[attribution.Domain]++
+		report.Attributions = append(report.Attributions, attribution)
+
+		start := floorBucket(sample.AtMS, bucketMS)
+		bucket, ok := buckets[start]
+		if !ok {
+			bucket = &FailureDomainBucket{StartMS: start, ByDomain: make(map[string]int)}
+			buckets[start] = bucket
+		}
+		bucket.FailedTurns++
+		bucket.ByDomain[attribution.Domain]++
+	}
+
+	starts := make([]int64, 0, len(buckets))
+	for start := range buckets {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+	for _, start := range starts {
+		report.Buckets = append(report.Buckets, *buckets[start])
+	}
+	sort.SliceStable(report.Attributions, func(i, j int) bool {
+		return report.Attributions[i].AtMS < report.Attributions[j].AtMS
+	})
+	return report, nil
+}
+
+func attributeTurn(sample TurnFailureSample) (FailedTurnAttribution, bool) {
+	attribution := FailedTurnAttribution{SessionID: sample.SessionID, TurnID: sample.TurnID, AtMS: sample.AtMS}
+
+	for _, decision := range sample.Decisions {
+		if decision.OutcomeKind == controlplane.OutcomeAdmit {
+			continue
+		}
+		attribution.Reason = decision.Reason
+		attribution.Domain = UnattributedFailureDomain
+		if domain, ok := decision.ResolvedFailureDomain(); ok {
+			attribution.Domain = string(domain)
+		}
+		return attribution, true
+	}
+
+	failed := unrecoveredInvocations(sample)
+	for _, invocation := range failed {
+		domain, ok := eventabi.ProviderFailureDomain(invocation.Modality)
+		if !ok {
+			continue
+		}
+		attribution.Domain = string(domain)
+		attribution.Reason = fmt.Sprintf("%s:%s", invocation.ProviderID, invocation.OutcomeClass)
+		return attribution, true
+	}
+
+	if sample.TerminalOutcome != "abort" && len(failed) == 0 {
+		return FailedTurnAttribution{}, false
+	}
+	attribution.Reason = sample.TerminalReason
+	attribution.Domain = UnattributedFailureDomain
+	for _, signal := range sample.Signals {
+		if domain, ok := signal.ResolvedFailureDomain(); ok {
+			attribution.Domain = string(domain)
+			break
+		}
+	}
+	return attribution, true
+}
+
+// unrecoveredInvocations drops failed invocations the runtime recovered from: in a turn that
+// did not abort, an invocation followed by a retry or provider switch did not fail the turn.
+func unrecoveredInvocations(sample TurnFailureSample) []FailedInvocation {
+	if sample.TerminalOutcome == "abort" {
+		return sample.FailedInvocations
+	}
+	failed := make([]FailedInvocation, 0, len(sample.FailedInvocations))
+	for _, invocation := range sample.FailedInvocations {
+		if invocation.RetryDecision == "retry" || invocation.RetryDecision == "provider_switch" {
+			continue
+		}
+		failed = append(failed, invocation)
+	}
+	return failed
+}
+
+func floorBucket(atMS int64, bucketMS int64) int64 {
+	if atMS < 0 {
+		atMS = 0
+	}
+	return atMS - atMS%bucketMS
+}
@@ -0,0 +1,77 @@
+package ops
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+func TestAttributeFailureDomainsBucketsFailedTurns(t *testing.T) {
+	t.Parallel()
+
+	samples := []TurnFailureSample{
+		{SessionID: "s1", TurnID: "t-ok", AtMS: 10, TerminalOutcome: "commit"},
+		{
+			SessionID: "s1", TurnID: "t-switched", AtMS: 15, TerminalOutcome: "commit",
+			FailedInvocations: []FailedInvocation{{Modality: "tts", ProviderID: "tts-a", OutcomeClass: "timeout", RetryDecision: "provider_switch"}},
+		},
+		{
+			SessionID: "s1", TurnID: "t-shed", AtMS: 20,
+			Decisions: []controlplane.DecisionOutcome{{OutcomeKind: controlplane.OutcomeShed, EmittedBy: controlplane.EmitterRK25, Reason: "scheduling_point_shed"}},
+		},
+		{
+			SessionID: "s1", TurnID: "t-plan", AtMS: 30,
+			Decisions: []controlplane.DecisionOutcome{{OutcomeKind: controlplane.OutcomeDefer, EmittedBy: controlplane.EmitterRK25, Reason: "plan_materialization_failed", FailureDomain: eventabi.FailureDomainAdmission}},
+		},
+		{
+			SessionID: "s2", TurnID: "t-llm", AtMS: 61_000, TerminalOutcome: "abort", TerminalReason: "provider_failure",
+			FailedInvocations: []FailedInvocation{{Modality: "llm", ProviderID: "llm-a", OutcomeClass: "timeout"}},
+		},
+		{
+			SessionID: "s2", TurnID: "t-transport", AtMS: 62_000, TerminalOutcome: "abort", TerminalReason: "transport_disconnect",
+			Signals: []eventabi.ControlSignal{{Signal: "disconnected", EmittedBy: "RK-23"}},
+		},
+		{
+			SessionID: "s2", TurnID: "t-deauth", AtMS: 62_500, TerminalOutcome: "abort", TerminalReason: "authority_loss",
+			Signals: []eventabi.ControlSignal{{Signal: "deauthorized_drain", EmittedBy: "RK-24"}},
+		},
+		{SessionID: "s2", TurnID: "t-unknown", AtMS: 63_000, TerminalOutcome: "abort", TerminalReason: "unknown"},
+	}
+
+	report, err := AttributeFailureDomains(samples, DefaultFailureDomainBucketMS)
+	if err != nil {
+		t.Fatalf("unexpected attribution error: %v", err)
+	}
+	if report.TotalTurns != 8 || report.FailedTurns != 6 {
+		t.Fatalf("expected 6 of 8 turns failed, got %+v", report)
+	}
+	expected := map[string]int{
+		"admission":               2,
+		"control-plane":           1,
+		"provider:llm":            1,
+		"transport":               1,
+		UnattributedFailureDomain: 1,
+		"provider:stt":            0,
+		"provider:tts":            0,
+	}
+	for domain, count := range expected {
+		if report.ByDomain[domain] != count {
+			t.Fatalf("expected %s=%d, got %+v", domain, count, report.ByDomain)
+		}
+	}
+	if len(report.Buckets) != 2 || report.Buckets[0].StartMS != 0 || report.Buckets[0].FailedTurns != 2 || report.Buckets[1].StartMS != 60_000 || report.Buckets[1].FailedTurns != 4 {
+		t.Fatalf("expected two time buckets, got %+v", report.Buckets)
+	}
+	if report.Attributions[2].Reason != "llm-a:timeout" {
+		t.Fatalf("expected provider attribution reason, got %+v", report.Attributions[2])
+	}
+}
+
+func TestAttributeFailureDomainsRejectsInvalidBucket(t *testing.T) {
+	t.Parallel()
+
+	if _, err := AttributeFailureDomains(nil, 0); err == nil {
+		t.Fatalf("expected bucket_ms=0 to fail")
+	}
+}
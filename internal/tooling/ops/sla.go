@@ -0,0 +1,192 @@
+package ops
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+)
+
+// DefaultSLAReportPeriodMS is the default SLA attainment reporting period (one day).
+const DefaultSLAReportPeriodMS int64 = 24 * 60 * 60 * 1000
+
+// TenantTurnSample captures per-turn evidence used for tenant SLA attainment.
+type TenantTurnSample struct {
+	SessionID string
+	TurnID    string
+	AtMS      int64
+	// SLA is the tenant SLA tier the turn was tagged with; nil turns are counted as untagged.
+	SLA *controlplane.TurnSLA
+	// Available marks a turn that was admitted and completed without a non-cancel abort.
+	Available bool
+	// FirstOutputLatencyMS is turn open to first output; nil when the turn produced no output.
+	FirstOutputLatencyMS *int64
+}
+
+// SLAAttainment aggregates tenant turns against one SLA tier. A turn attains the SLA when it
+// is available and its first output, if any, arrived within the tier's latency bound; the
+// SLA is met when the attained fraction reaches the tier's minimum availability.
+type SLAAttainment struct {
+	Turns                     int     `json:"turns"`
+	AvailableTurns            int     `json:"available_turns"`
+	LatencyBreaches           int     `json:"latency_breaches"`
+	AttainedTurns             int     `json:"attained_turns"`
+	Availability              float64 `json:"availability"`
+	Attainment                float64 `json:"attainment"`
+	WorstFirstOutputLatencyMS *int64  `json:"worst_first_output_latency_ms,omitempty"`
+	Met                       bool    `json:"met"`
+}
+
+// SLAPeriodAttainment is one reporting period of a tenant's SLA attainment.
+type SLAPeriodAttainment struct {
+	StartMS int64 `json:"start_ms"`
+	SLAAttainment
+}
+
+// TenantSLAReport is a tenant's attainment against one declared SLA tier; a tenant whose
+// tier changed within the report window has one entry per tier.
+type TenantSLAReport struct {
+	TenantID string                `json:"tenant_id"`
+	SLA      controlplane.SLATier  `json:"sla"`
+	Overall  SLAAttainment         `json:"overall"`
+	Periods  []SLAPeriodAttainment `json:"periods"`
+}
+
+// SLAMiss records one tenant period that missed its SLA.
+type SLAMiss struct {
+	TenantID   string  `json:"tenant_id"`
+	Tier       string  `json:"tier"`
+	StartMS    int64   `json:"start_ms"`
+	Attainment float64 `json:"attainment"`
+	Target     float64 `json:"target"`
+}
+
+// SLAReport is the per-tenant SLA attainment report. It is informational and separate from
+// the global MVP SLO gates: a missed tenant SLA never fails a gate.
+type SLAReport struct {
+	PeriodMS      int64             `json:"period_ms"`
+	TotalTurns    int               `json:"total_turns"`
+	UntaggedTurns int               `json:"untagged_turns"`
+	Tenants       []TenantSLAReport `json:"tenants"`
+	Misses        []SLAMiss         `json:"misses"`
+}
+
+type tenantSLAKey struct {
+	tenantID string
+	tier     controlplane.SLATier
+}
+
+// EvaluateTenantSLAs computes per-tenant SLA attainment over periodMS reporting periods.
+func EvaluateTenantSLAs(samples []TenantTurnSample, periodMS int64) (SLAReport, error) {
+	if periodMS < 1 {
+		return SLAReport{}, fmt.Errorf("sla report period_ms must be >=1")
+	}
+	report := SLAReport{
+		PeriodMS:   periodMS,
+		TotalTurns: len(samples),
+		Tenants:    make([]TenantSLAReport, 0),
+		Misses:     make([]SLAMiss, 0),
+	}
+
+	tenants := make(map[tenantSLAKey]map[int64]*SLAAttainment)
+	for _, sample := range samples {
+		if sample.SLA == nil {
+			report.UntaggedTurns++
+			continue
+		}
+		if err := sample.SLA.Validate(); err != nil {
+			return SLAReport{}, fmt.Errorf("turn %s sla: %w", sample.TurnID, err)
+		}
+		key := tenantSLAKey{tenantID: sample.SLA.TenantID, tier: sample.SLA.SLATier}
+		periods, ok := tenants[key]
+		if !ok {
+			periods = make(map[int64]*SLAAttainment)
+			tenants[key] = periods
+		}
+		start := floorBucket(sample.AtMS, periodMS)
+		period, ok := periods[start]
+		if !ok {
+			period = &SLAAttainment{}
+			periods[start] = period
+		}
+		period.add(sample, key.tier)
+	}
+
+	keys := make([]tenantSLAKey, 0, len(tenants))
+	for key := range tenants {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].tenantID != keys[j].tenantID {
+			return keys[i].tenantID < keys[j].tenantID
+		}
+		return keys[i].tier.Tier < keys[j].tier.Tier
+	})
+	for _, key := range keys {
+		tenant := TenantSLAReport{TenantID: key.tenantID, SLA: key.tier, Periods: make([]SLAPeriodAttainment, 0, len(tenants[key]))}
+		starts := make([]int64, 0, len(tenants[key]))
+		for start := range tenants[key] {
+			starts = append(starts, start)
+		}
+		sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+		for _, start := range starts {
+			period := *tenants[key][start]
+			period.finalize(key.tier)
+			tenant.Overall.merge(period)
+			tenant.Periods = append(tenant.Periods, SLAPeriodAttainment{StartMS: start, SLAAttainment: period})
+			if !period.Met {
+				report.Misses = append(report.Misses, SLAMiss{
+					TenantID:   key.tenantID,
+					Tier:       key.tier.Tier,
+					StartMS:    start,
+					Attainment: period.Attainment,
+					Target:     key.tier.MinAvailability,
+				})
+			}
+		}
+		tenant.Overall.finalize(key.tier)
+		report.Tenants = append(report.Tenants, tenant)
+	}
+	return report, nil
+}
+
+func (a *SLAAttainment) add(sample TenantTurnSample, tier controlplane.SLATier) {
+	a.Turns++
+	withinLatency := true
+	if latency := sample.FirstOutputLatencyMS; latency != nil {
+		if a.WorstFirstOutputLatencyMS == nil || *latency > *a.WorstFirstOutputLatencyMS {
+			worst := *latency
+			a.WorstFirstOutputLatencyMS = &worst
+		}
+		if *latency > tier.MaxFirstOutputLatencyMS {
+			a.LatencyBreaches++
+			withinLatency = false
+		}
+	}
+	if sample.Available {
+		a.AvailableTurns++
+		if withinLatency {
+			a.AttainedTurns++
+		}
+	}
+}
+
+func (a *SLAAttainment) merge(other SLAAttainment) {
+	a.Turns += other.Turns
+	a.AvailableTurns += other.AvailableTurns
+	a.LatencyBreaches += other.LatencyBreaches
+	a.AttainedTurns += other.AttainedTurns
+	if other.WorstFirstOutputLatencyMS != nil && (a.WorstFirstOutputLatencyMS == nil || *other.WorstFirstOutputLatencyMS > *a.WorstFirstOutputLatencyMS) {
+		worst := *other.WorstFirstOutputLatencyMS
+		a.WorstFirstOutputLatencyMS = &worst
+	}
+}
+
+func (a *SLAAttainment) finalize(tier controlplane.SLATier) {
+	if a.Turns == 0 {
+		return
+	}
+	a.Availability = float64(a.AvailableTurns) / float64(a.Turns)
+	a.Attainment = float64(a.AttainedTurns) / float64(a.Turns)
+	a.Met = a.Attainment >= tier.MinAvailability
+}
@@ -0,0 +1,99 @@
+package ops
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+)
+
+func TestEvaluateTenantSLAsReportsAttainmentPerTenantAndPeriod(t *testing.T) {
+	t.Parallel()
+
+	gold := &controlplane.TurnSLA{TenantID: "tenant-a", SLATier: controlplane.SLATier{Tier: "gold", MaxFirstOutputLatencyMS: 800, MinAvailability: 0.75}}
+	standard := &controlplane.TurnSLA{TenantID: "tenant-b", SLATier: controlplane.SLATier{Tier: "standard", MaxFirstOutputLatencyMS: 1500, MinAvailability: 0.5}}
+	latency := func(ms int64) *int64 { return &ms }
+	samples := []TenantTurnSample{
+		{TurnID: "a-1", AtMS: 10, SLA: gold, Available: true, FirstOutputLatencyMS: latency(400)},
+		{TurnID: "a-2", AtMS: 20, SLA: gold, Available: true, FirstOutputLatencyMS: latency(700)},
+		{TurnID: "a-3", AtMS: 30, SLA: gold, Available: true, FirstOutputLatencyMS: latency(900)},
+		{TurnID: "a-4", AtMS: 40, SLA: gold, Available: false},
+		{TurnID: "a-5", AtMS: 1_010, SLA: gold, Available: true, FirstOutputLatencyMS: latency(500)},
+		{TurnID: "b-1", AtMS: 15, SLA: standard, Available: true, FirstOutputLatencyMS: latency(1_200)},
+		{TurnID: "b-2", AtMS: 25, SLA: standard, Available: false},
+		{TurnID: "untagged", AtMS: 35, Available: true, FirstOutputLatencyMS: latency(100)},
+	}
+
+	report, err := EvaluateTenantSLAs(samples, 1_000)
+	if err != nil {
+		t.Fatalf("unexpected sla evaluation error: %v", err)
+	}
+	if report.TotalTurns != 8 || report.UntaggedTurns != 1 || len(report.Tenants) != 2 {
+		t.Fatalf("expected two tenants and one untagged turn, got %+v", report)
+	}
+
+	tenantA := report.Tenants[0]
+	if tenantA.TenantID != "tenant-a" || tenantA.SLA.Tier != "gold" || len(tenantA.Periods) != 2 {
+		t.Fatalf("unexpected tenant-a report: %+v", tenantA)
+	}
+	first := tenantA.Periods[0]
+	if first.StartMS != 0 || first.Turns != 4 || first.AvailableTurns != 3 || first.LatencyBreaches != 1 || first.AttainedTurns != 2 || first.Attainment != 0.5 || first.Met {
+		t.Fatalf("expected the first tenant-a period to miss with a latency breach and an unavailable turn, got %+v", first)
+	}
+	if first.WorstFirstOutputLatencyMS == nil || *first.WorstFirstOutputLatencyMS != 900 {
+		t.Fatalf("expected worst first-output latency 900ms, got %v", first.WorstFirstOutputLatencyMS)
+	}
+	if second := tenantA.Periods[1]; second.StartMS != 1_000 || second.Turns != 1 || !second.Met {
+		t.Fatalf("expected the second tenant-a period to meet its sla, got %+v", second)
+	}
+	if overall := tenantA.Overall; overall.Turns != 5 || overall.AttainedTurns != 3 || overall.Availability != 0.8 || overall.Met {
+		t.Fatalf("unexpected tenant-a overall attainment: %+v", overall)
+	}
+
+	tenantB := report.Tenants[1]
+	if tenantB.TenantID != "tenant-b" || !tenantB.Overall.Met || tenantB.Overall.Attainment != 0.5 {
+		t.Fatalf("expected tenant-b to meet its standard tier at 50%%, got %+v", tenantB)
+	}
+	if len(report.Misses) != 1 || report.Misses[0].TenantID != "tenant-a" || report.Misses[0].StartMS != 0 || report.Misses[0].Target != 0.75 {
+		t.Fatalf("expected one tenant-a miss, got %+v", report.Misses)
+	}
+}
+
+func TestEvaluateTenantSLAsSeparatesTierChanges(t *testing.T) {
+	t.Parallel()
+
+	silver := controlplane.SLATier{Tier: "silver", MaxFirstOutputLatencyMS: 1_000, MinAvailability: 0.9}
+	gold := controlplane.SLATier{Tier: "gold", MaxFirstOutputLatencyMS: 800, MinAvailability: 0.99}
+	samples := []TenantTurnSample{
+		{TurnID: "t-1", AtMS: 10, SLA: &controlplane.TurnSLA{TenantID: "tenant-a", SLATier: silver}, Available: true},
+		{TurnID: "t-2", AtMS: 20, SLA: &controlplane.TurnSLA{TenantID: "tenant-a", SLATier: gold}, Available: true},
+	}
+	report, err := EvaluateTenantSLAs(samples, DefaultSLAReportPeriodMS)
+	if err != nil {
+		t.Fatalf("unexpected sla evaluation error: %v", err)
+	}
+	if len(report.Tenants) != 2 || report.Tenants[0].SLA.Tier != "gold" || report.Tenants[1].SLA.Tier != "silver" {
+		t.Fatalf("expected one entry per declared tier, got %+v", report.Tenants)
+	}
+}
+
+func TestEvaluateTenantSLAsRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		samples  []TenantTurnSample
+		periodMS int64
+	}{
+		{name: "zero period", periodMS: 0},
+		{
+			name:     "invalid sla tag",
+			samples:  []TenantTurnSample{{TurnID: "t-1", SLA: &controlplane.TurnSLA{TenantID: "tenant-a"}}},
+			periodMS: DefaultSLAReportPeriodMS,
+		},
+	}
+	for _, tc := range tests {
+		if _, err := EvaluateTenantSLAs(tc.samples, tc.periodMS); err == nil {
+			t.Fatalf("%s: expected sla evaluation error", tc.name)
+		}
+	}
+}
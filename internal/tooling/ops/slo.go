@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 )
 
 // TurnMetrics captures per-turn measurements used for MVP SLO gates.
 type TurnMetrics struct {
 	TurnID                   string
+	PipelineVersion          string
 	Accepted                 bool
 	HappyPath                bool
 	TurnOpenProposedAtMS     *int64
@@ -19,6 +21,15 @@ type TurnMetrics struct {
 	BaselineComplete         bool
 	AcceptedStaleEpochOutput bool
 	TerminalEvents           []string
+	// LengthCapped marks a turn whose assistant output was truncated at an output length cap;
+	// such turns still count toward latency gates but are reported separately.
+	LengthCapped bool
+	// FirstAudioPlayedAtMS is the client-acknowledged start of reply playback; nil when the
+	// client sent no playback acknowledgment.
+	FirstAudioPlayedAtMS *int64
+	// PlaybackCancelled marks a turn whose reply playback the transport cut off, as on
+	// barge-in.
+	PlaybackCancelled bool
 }
 
 // MVPSLOThresholds define normative MVP limits.
@@ -53,15 +64,55 @@ type MVPSLOGateReport struct {
 	BaselineCompletenessRatio float64  `json:"baseline_completeness_ratio"`
 	StaleAcceptedOutputs      int      `json:"stale_epoch_accepted_outputs"`
 	TerminalCorrectnessRatio  float64  `json:"terminal_correctness_ratio"`
+	LengthCappedTurns         int      `json:"length_capped_turns,omitempty"`
 	Violations                []string `json:"violations,omitempty"`
 	Passed                    bool     `json:"passed"`
+	// PerceivedFirstAudioP95MS is turn-open to client-acknowledged playback for happy-path
+	// turns with a playback acknowledgment. It is reported alongside the egress-based
+	// FirstOutputP95MS and is not gated.
+	PerceivedFirstAudioP95MS *int64 `json:"perceived_first_audio_p95_ms,omitempty"`
+	PlaybackAckedTurns       int    `json:"playback_acked_turns,omitempty"`
+	// BargeInTurns counts turns whose reply playback the caller cut off; informational.
+	BargeInTurns int `json:"barge_in_turns,omitempty"`
+	// ByPipelineVersion slices the gates per published pipeline version so a regression in a
+	// mixed-version rollout is attributed to the version that caused it. Slices are
+	// informational; Passed reflects the aggregate.
+	ByPipelineVersion map[string]MVPSLOGateReport `json:"by_pipeline_version,omitempty"`
 }
 
-// EvaluateMVPSLOGates evaluates MVP SLO gates against runtime samples.
+// UnversionedPipelineSlice keys ByPipelineVersion samples that carry no pipeline version.
+const UnversionedPipelineSlice = "unversioned"
+
+// EvaluateMVPSLOGates evaluates MVP SLO gates against runtime samples, sliced per pipeline
+// version when samples carry one.
 func EvaluateMVPSLOGates(samples []TurnMetrics, thresholds MVPSLOThresholds) MVPSLOGateReport {
+	report := evaluateMVPSLOGates(samples, thresholds)
+	byVersion := make(map[string][]TurnMetrics)
+	versioned := false
+	for _, sample := range samples {
+		version := strings.TrimSpace(sample.PipelineVersion)
+		if version == "" {
+			version = UnversionedPipelineSlice
+		} else {
+			versioned = true
+		}
+		byVersion[version] = append(byVersion[version], sample)
+	}
+	if !versioned {
+		return report
+	}
+	report.ByPipelineVersion = make(map[string]MVPSLOGateReport, len(byVersion))
+	for version, slice := range byVersion {
+		report.ByPipelineVersion[version] = evaluateMVPSLOGates(slice, thresholds)
+	}
+	return report
+}
+
+func evaluateMVPSLOGates(samples []TurnMetrics, thresholds MVPSLOThresholds) MVPSLOGateReport {
 	report := MVPSLOGateReport{Samples: len(samples)}
 	turnOpenLatencies := make([]int64, 0)
 	firstOutputLatencies := make([]int64, 0)
+	perceivedFirstAudioLatencies := make([]int64, 0)
 	cancelFenceLatencies := make([]int64, 0)
 
 	completeAccepted := 0
@@ -79,6 +130,9 @@ func EvaluateMVPSLOGates(samples []TurnMetrics, thresholds MVPSLOThresholds) MVP
 			if sample.AcceptedStaleEpochOutput {
 				report.StaleAcceptedOutputs++
 			}
+			if sample.LengthCapped {
+				report.LengthCappedTurns++
+			}
 			if sample.TurnOpenProposedAtMS == nil || sample.TurnOpenAtMS == nil {
 				report.Violations = append(report.Violations, fmt.Sprintf("turn %s missing open-latency markers", sample.TurnID))
 			} else {
@@ -103,6 +157,19 @@ func EvaluateMVPSLOGates(samples []TurnMetrics, thresholds MVPSLOThresholds) MVP
 					firstOutputLatencies = append(firstOutputLatencies, latency)
 				}
 			}
+			if sample.TurnOpenAtMS != nil && sample.FirstAudioPlayedAtMS != nil {
+				report.PlaybackAckedTurns++
+				latency := *sample.FirstAudioPlayedAtMS - *sample.TurnOpenAtMS
+				if latency < 0 {
+					report.Violations = append(report.Violations, fmt.Sprintf("turn %s has negative perceived first-audio latency", sample.TurnID))
+				} else {
+					perceivedFirstAudioLatencies = append(perceivedFirstAudioLatencies, latency)
+				}
+			}
+		}
+
+		if sample.PlaybackCancelled {
+			report.BargeInTurns++
 		}
 
 		if sample.CancelAcceptedAtMS != nil {
@@ -134,6 +201,10 @@ func EvaluateMVPSLOGates(samples []TurnMetrics, thresholds MVPSLOThresholds) MVP
 			report.Violations = append(report.Violations, fmt.Sprintf("first-output p95=%dms exceeds threshold=%dms", p95, thresholds.FirstOutputP95MS))
 		}
 	}
+	if len(perceivedFirstAudioLatencies) > 0 {
+		p95 := percentile95(perceivedFirstAudioLatencies)
+		report.PerceivedFirstAudioP95MS = &p95
+	}
 	if len(cancelFenceLatencies) > 0 {
 		p95 := percentile95(cancelFenceLatencies)
 		report.CancelFenceP95MS = &p95
@@ -170,7 +241,7 @@ func hasValidTerminalSequence(events []string) bool {
 	if events[1] != "close" {
 		return false
 	}
-	return events[0] == "commit" || events[0] == "abort"
+	return events[0] == "commit" || events[0] == "abort" || events[0] == "fallback"
 }
 
 func percentile95(values []int64) int64 {
@@ -9,6 +9,7 @@ func TestEvaluateMVPSLOGatesPass(t *testing.T) {
 		newAcceptedTurn("turn-1", 0, 90, 500, nil, nil, true, false, []string{"commit", "close"}, true),
 		newAcceptedTurn("turn-2", 0, 100, 700, nil, nil, true, false, []string{"abort", "close"}, true),
 		newAcceptedTurn("turn-3", 0, 110, 900, int64Ptr(1200), int64Ptr(1290), true, false, []string{"abort", "close"}, false),
+		newAcceptedTurn("turn-4", 0, 95, 600, nil, nil, true, false, []string{"fallback", "close"}, true),
 	}
 
 	report := EvaluateMVPSLOGates(samples, DefaultMVPSLOThresholds())
@@ -20,6 +21,46 @@ func TestEvaluateMVPSLOGatesPass(t *testing.T) {
 	}
 }
 
+func TestEvaluateMVPSLOGatesCountsLengthCappedTurns(t *testing.T) {
+	t.Parallel()
+
+	capped := newAcceptedTurn("turn-capped", 0, 90, 500, nil, nil, true, false, []string{"commit", "close"}, true)
+	capped.LengthCapped = true
+	samples := []TurnMetrics{
+		capped,
+		newAcceptedTurn("turn-full", 0, 100, 700, nil, nil, true, false, []string{"commit", "close"}, true),
+	}
+
+	report := EvaluateMVPSLOGates(samples, DefaultMVPSLOThresholds())
+	if !report.Passed || report.LengthCappedTurns != 1 {
+		t.Fatalf("expected passing report with one length-capped turn, got %+v", report)
+	}
+}
+
+func TestEvaluateMVPSLOGatesReportsPerceivedFirstAudio(t *testing.T) {
+	t.Parallel()
+
+	acked := newAcceptedTurn("turn-acked", 0, 90, 500, nil, nil, true, false, []string{"commit", "close"}, true)
+	acked.FirstAudioPlayedAtMS = int64Ptr(2100)
+	bargedIn := newAcceptedTurn("turn-barged-in", 0, 100, 700, nil, nil, true, false, []string{"commit", "close"}, true)
+	bargedIn.PlaybackCancelled = true
+	samples := []TurnMetrics{acked, bargedIn}
+
+	report := EvaluateMVPSLOGates(samples, DefaultMVPSLOThresholds())
+	if !report.Passed || report.PlaybackAckedTurns != 1 || report.PerceivedFirstAudioP95MS == nil || *report.PerceivedFirstAudioP95MS != 2010 {
+		t.Fatalf("expected ungated perceived first-audio p95 from the acked turn, got %+v", report)
+	}
+	if report.BargeInTurns != 1 {
+		t.Fatalf("expected the barged-in turn counted without failing the gates, got %+v", report)
+	}
+
+	skewed := acked
+	skewed.FirstAudioPlayedAtMS = int64Ptr(10)
+	if report := EvaluateMVPSLOGates([]TurnMetrics{skewed}, DefaultMVPSLOThresholds()); report.Passed {
+		t.Fatalf("expected negative perceived first-audio latency to be a violation")
+	}
+}
+
 func TestEvaluateMVPSLOGatesFail(t *testing.T) {
 	t.Parallel()
 
@@ -45,6 +86,41 @@ func TestEvaluateMVPSLOGatesFail(t *testing.T) {
 	}
 }
 
+func TestEvaluateMVPSLOGatesSlicesByPipelineVersion(t *testing.T) {
+	t.Parallel()
+
+	stable := newAcceptedTurn("turn-v1", 0, 90, 500, nil, nil, true, false, []string{"commit", "close"}, true)
+	stable.PipelineVersion = "pipeline-v1"
+	regressed := newAcceptedTurn("turn-v2", 0, 400, 900, nil, nil, true, false, []string{"commit", "close"}, true)
+	regressed.PipelineVersion = "pipeline-v2"
+	unversioned := newAcceptedTurn("turn-legacy", 0, 90, 500, nil, nil, true, false, []string{"commit", "close"}, true)
+
+	tests := []struct {
+		name     string
+		samples  []TurnMetrics
+		expected map[string]bool
+	}{
+		{name: "no versions keeps report unsliced", samples: []TurnMetrics{unversioned}, expected: nil},
+		{
+			name:     "mixed versions attribute the regression",
+			samples:  []TurnMetrics{stable, regressed, unversioned},
+			expected: map[string]bool{"pipeline-v1": true, "pipeline-v2": false, UnversionedPipelineSlice: true},
+		},
+	}
+	for _, tc := range tests {
+		report := EvaluateMVPSLOGates(tc.samples, DefaultMVPSLOThresholds())
+		if len(report.ByPipelineVersion) != len(tc.expected) {
+			t.Fatalf("%s: expected %d slices, got %+v", tc.name, len(tc.expected), report.ByPipelineVersion)
+		}
+		for version, passed := range tc.expected {
+			slice, ok := report.ByPipelineVersion[version]
+			if !ok || slice.Passed != passed || slice.Samples != 1 || slice.ByPipelineVersion != nil {
+				t.Fatalf("%s: expected %s slice passed=%t over 1 sample, got %+v", tc.name, version, passed, slice)
+			}
+		}
+	}
+}
+
 func newAcceptedTurn(
 	turnID string,
 	openProposed int64,
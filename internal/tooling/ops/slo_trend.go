@@ -0,0 +1,111 @@
+package ops
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// SLOTrendPoint is one timestamped observation of a named SLO series.
+type SLOTrendPoint struct {
+	Series string `json:"series"`
+	AtMS   int64  `json:"at_ms"`
+	Good   bool   `json:"good"`
+	// LatencyMS is the observed latency; zero when the series measures no latency.
+	LatencyMS int64 `json:"latency_ms,omitempty"`
+	// Target identifies what was observed, such as a deployed endpoint.
+	Target string `json:"target,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// SLOTrendStore keeps SLO observations in an append-only JSON-lines file, so availability and
+// error-budget trends span monitor restarts.
+type SLOTrendStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewSLOTrendStore returns a store backed by the file at path; the file is created on the
+// first append.
+func NewSLOTrendStore(path string) (*SLOTrendStore, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("slo trend store path is required")
+	}
+	return &SLOTrendStore{path: path}, nil
+}
+
+// Path returns the backing file path.
+func (s *SLOTrendStore) Path() string {
+	return s.path
+}
+
+// Append adds one observation.
+func (s *SLOTrendStore) Append(point SLOTrendPoint) error {
+	if strings.TrimSpace(point.Series) == "" {
+		return fmt.Errorf("slo trend point series is required")
+	}
+	line, err := json.Marshal(point)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Points returns the series' observations at or after sinceMS in append order. A missing file
+// holds no observations.
+func (s *SLOTrendStore) Points(series string, sinceMS int64) ([]SLOTrendPoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var points []SLOTrendPoint
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var point SLOTrendPoint
+		if err := json.Unmarshal(scanner.Bytes(), &point); err != nil {
+			return nil, fmt.Errorf("slo trend store %s line %d: %w", s.path, lineNumber, err)
+		}
+		if point.Series == series && point.AtMS >= sinceMS {
+			points = append(points, point)
+		}
+	}
+	return points, scanner.Err()
+}
+
+// SLOEventsFromTrend converts trend observations into error-budget events.
+func SLOEventsFromTrend(points []SLOTrendPoint) []SLOEvent {
+	events := make([]SLOEvent, 0, len(points))
+	for _, point := range points {
+		events = append(events, SLOEvent{AtMS: point.AtMS, Good: point.Good})
+	}
+	return events
+}
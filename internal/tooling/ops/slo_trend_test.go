@@ -0,0 +1,61 @@
+package ops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSLOTrendStoreAppendsAndFiltersSeries(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "ops", "slo-trend.jsonl")
+	store, err := NewSLOTrendStore(path)
+	if err != nil {
+		t.Fatalf("unexpected store error: %v", err)
+	}
+	if points, err := store.Points("synthetic_canary", 0); err != nil || len(points) != 0 {
+		t.Fatalf("expected an empty store before the first append, got %+v err=%v", points, err)
+	}
+	appends := []SLOTrendPoint{
+		{Series: "synthetic_canary", AtMS: 1000, Good: true, LatencyMS: 120},
+		{Series: "turn_success", AtMS: 1500, Good: false},
+		{Series: "synthetic_canary", AtMS: 2000, Good: false, Reason: "canary_dial_failed: refused"},
+		{Series: "synthetic_canary", AtMS: 3000, Good: true, LatencyMS: 90},
+	}
+	for _, point := range appends {
+		if err := store.Append(point); err != nil {
+			t.Fatalf("unexpected append error: %v", err)
+		}
+	}
+
+	reopened, _ := NewSLOTrendStore(path)
+	points, err := reopened.Points("synthetic_canary", 2000)
+	if err != nil || len(points) != 2 || points[0].Reason != "canary_dial_failed: refused" || points[1].LatencyMS != 90 {
+		t.Fatalf("expected the two canary points since 2000ms, got %+v err=%v", points, err)
+	}
+	events := SLOEventsFromTrend(points)
+	if len(events) != 2 || events[0].Good || !events[1].Good || events[1].AtMS != 3000 {
+		t.Fatalf("unexpected error budget events %+v", events)
+	}
+}
+
+func TestSLOTrendStoreRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewSLOTrendStore(" "); err == nil {
+		t.Fatalf("expected empty path to fail")
+	}
+	path := filepath.Join(t.TempDir(), "slo-trend.jsonl")
+	store, _ := NewSLOTrendStore(path)
+	if err := store.Append(SLOTrendPoint{AtMS: 1}); err == nil {
+		t.Fatalf("expected a point without a series to fail")
+	}
+	if err := os.WriteFile(path, []byte("{\"series\":\"x\",\"at_ms\":1}\nnot-json\n"), 0o644); err != nil {
+		t.Fatalf("write trend: %v", err)
+	}
+	if _, err := store.Points("x", 0); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected the malformed line to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,357 @@
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/recording"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/invocation"
+)
+
+// DefaultSTTGoldenCorpusPath is the repository-relative golden transcript corpus.
+const DefaultSTTGoldenCorpusPath = "test/quality/fixtures/stt_golden_corpus.json"
+
+// STTGoldenFixture is one recorded audio fixture with its golden transcript.
+type STTGoldenFixture struct {
+	FixtureID           string            `json:"fixture_id"`
+	AudioRef            string            `json:"audio_ref"`
+	GoldenTranscript    string            `json:"golden_transcript"`
+	RecordedTranscripts map[string]string `json:"recorded_transcripts,omitempty"`
+}
+
+// STTGoldenCorpus groups golden fixtures with per-provider WER thresholds.
+type STTGoldenCorpus struct {
+	WERThresholdsByProvider map[string]float64 `json:"wer_thresholds_by_provider"`
+	DefaultWERThreshold     float64            `json:"default_wer_threshold"`
+	Fixtures                []STTGoldenFixture `json:"fixtures"`
+}
+
+// Validate enforces corpus shape requirements.
+func (c STTGoldenCorpus) Validate() error {
+	if len(c.Fixtures) == 0 {
+		return fmt.Errorf("stt golden corpus requires at least one fixture")
+	}
+	if c.DefaultWERThreshold < 0 {
+		return fmt.Errorf("default_wer_threshold must be >=0")
+	}
+	for providerID, threshold := range c.WERThresholdsByProvider {
+		if providerID == "" || threshold < 0 {
+			return fmt.Errorf("wer_thresholds_by_provider requires non-empty provider ids and thresholds >=0")
+		}
+	}
+	seen := make(map[string]struct{}, len(c.Fixtures))
+	for _, fixture := range c.Fixtures {
+		if fixture.FixtureID == "" || fixture.AudioRef == "" {
+			return fmt.Errorf("stt golden fixture_id and audio_ref are required")
+		}
+		if _, ok := seen[fixture.FixtureID]; ok {
+			return fmt.Errorf("duplicate stt golden fixture_id: %s", fixture.FixtureID)
+		}
+		seen[fixture.FixtureID] = struct{}{}
+		if len(normalizeTranscriptWords(fixture.GoldenTranscript)) == 0 {
+			return fmt.Errorf("stt golden fixture %s requires a non-empty golden_transcript", fixture.FixtureID)
+		}
+	}
+	return nil
+}
+
+// ThresholdFor returns the WER threshold configured for a provider.
+func (c STTGoldenCorpus) ThresholdFor(providerID string) float64 {
+	if threshold, ok := c.WERThresholdsByProvider[providerID]; ok {
+		return threshold
+	}
+	return c.DefaultWERThreshold
+}
+
+// LoadSTTGoldenCorpus reads a golden transcript corpus from disk.
+func LoadSTTGoldenCorpus(path string) (STTGoldenCorpus, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return STTGoldenCorpus{}, fmt.Errorf("read stt golden corpus %s: %w", path, err)
+	}
+	var corpus STTGoldenCorpus
+	if err := json.Unmarshal(raw, &corpus); err != nil {
+		return STTGoldenCorpus{}, fmt.Errorf("decode stt golden corpus %s: %w", path, err)
+	}
+	if err := corpus.Validate(); err != nil {
+		return STTGoldenCorpus{}, fmt.Errorf("validate stt golden corpus %s: %w", path, err)
+	}
+	return corpus, nil
+}
+
+// Transcriber runs one recorded audio fixture through an STT provider.
+type Transcriber interface {
+	Transcribe(providerID string, fixture STTGoldenFixture) (string, error)
+}
+
+// STT quality transcriber modes. ProviderTranscriberMode runs fixture audio through the
+// provider invocation path; RecordedTranscriberMode is the explicit offline mode that scores the
+// transcripts recorded in the corpus.
+const (
+	ProviderTranscriberMode = "providers"
+	RecordedTranscriberMode = "recorded"
+)
+
+// STTInvoker is the provider invocation path fixture audio runs through.
+type STTInvoker interface {
+	Invoke(in invocation.InvocationInput) (invocation.InvocationResult, error)
+}
+
+// InvocationTranscriber runs each fixture's audio_ref WAV through the provider invocation
+// controller, pinned to the STT provider under evaluation.
+type InvocationTranscriber struct {
+	Invoker         STTInvoker
+	PipelineVersion string
+	// ResolveAudioRef maps an audio_ref to a readable path; nil reads audio_ref as given.
+	ResolveAudioRef func(string) (string, error)
+}
+
+// Transcribe decodes the fixture audio, downmixes it to mono, and invokes the provider with it.
+func (t InvocationTranscriber) Transcribe(providerID string, fixture STTGoldenFixture) (string, error) {
+	if t.Invoker == nil {
+		return "", fmt.Errorf("stt quality invoker is required")
+	}
+	path := fixture.AudioRef
+	if t.ResolveAudioRef != nil {
+		resolved, err := t.ResolveAudioRef(fixture.AudioRef)
+		if err != nil {
+			return "", fmt.Errorf("resolve audio_ref %s: %w", fixture.AudioRef, err)
+		}
+		path = resolved
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open audio_ref %s: %w", fixture.AudioRef, err)
+	}
+	defer file.Close()
+	pcm, sampleRateHz, channels, err := recording.DecodeWAV(file)
+	if err != nil {
+		return "", fmt.Errorf("decode audio_ref %s: %w", fixture.AudioRef, err)
+	}
+	pipelineVersion := t.PipelineVersion
+	if pipelineVersion == "" {
+		pipelineVersion = "stt-quality"
+	}
+	result, err := t.Invoker.Invoke(invocation.InvocationInput{
+		SessionID:              "stt-quality",
+		TurnID:                 "stt-quality-" + fixture.FixtureID,
+		PipelineVersion:        pipelineVersion,
+		EventID:                "stt-quality-" + providerID + "-" + fixture.FixtureID,
+		Modality:               contracts.ModalitySTT,
+		PreferredProvider:      providerID,
+		AllowedAdaptiveActions: []string{"retry"},
+		TransportSequence:      1,
+		RuntimeSequence:        1,
+		AuthorityEpoch:         1,
+		RuntimeTimestampMS:     1,
+		WallClockTimestampMS:   1,
+		Audio:                  &contracts.AudioInput{PCM: downmixPCM(pcm, channels), SampleRateHz: sampleRateHz},
+	})
+	if err != nil {
+		return "", fmt.Errorf("invoke provider %s: %w", providerID, err)
+	}
+	if result.Outcome.Class != contracts.OutcomeSuccess {
+		return "", fmt.Errorf("provider %s outcome %s (%s)", providerID, result.Outcome.Class, result.Outcome.Reason)
+	}
+	if result.SelectedProvider != providerID {
+		return "", fmt.Errorf("provider %s was not invoked; selected %s", providerID, result.SelectedProvider)
+	}
+	return result.Outcome.Text, nil
+}
+
+// downmixPCM averages interleaved channels into mono.
+func downmixPCM(pcm []int16, channels int) []int16 {
+	if channels <= 1 {
+		return pcm
+	}
+	mono := make([]int16, len(pcm)/channels)
+	for i := range mono {
+		sum := 0
+		for c := 0; c < channels; c++ {
+			sum += int(pcm[i*channels+c])
+		}
+		mono[i] = int16(sum / channels)
+	}
+	return mono
+}
+
+// RecordedTranscriber plays back provider transcripts recorded alongside the corpus. It is the
+// offline mode of the quality gate: no STT provider is called.
+type RecordedTranscriber struct{}
+
+// Transcribe returns the recorded transcript for the provider and fixture.
+func (RecordedTranscriber) Transcribe(providerID string, fixture STTGoldenFixture) (string, error) {
+	transcript, ok := fixture.RecordedTranscripts[providerID]
+	if !ok {
+		return "", fmt.Errorf("no recorded transcript for provider=%s fixture=%s", providerID, fixture.FixtureID)
+	}
+	return transcript, nil
+}
+
+// WERResult captures word-level edit distance against a golden transcript.
+type WERResult struct {
+	ReferenceWords int     `json:"reference_words"`
+	Substitutions  int     `json:"substitutions"`
+	Deletions      int     `json:"deletions"`
+	Insertions     int     `json:"insertions"`
+	WER            float64 `json:"wer"`
+}
+
+// ComputeWER computes word error rate of a hypothesis against a reference transcript.
+// Both transcripts are lower-cased and stripped of punctuation before alignment.
+func ComputeWER(reference string, hypothesis string) WERResult {
+	ref := normalizeTranscriptWords(reference)
+	hyp := normalizeTranscriptWords(hypothesis)
+
+	type cell struct {
+		cost, sub, del, ins int
+	}
+	prev := make([]cell, len(hyp)+1)
+	for j := range prev {
+		prev[j] = cell{cost: j, ins: j}
+	}
+	for i := 1; i <= len(ref); i++ {
+		curr := make([]cell, len(hyp)+1)
+		curr[0] = cell{cost: i, del: i}
+		for j := 1; j <= len(hyp); j++ {
+			if ref[i-1] == hyp[j-1] {
+				curr[j] = prev[j-1]
+				continue
+			}
+			best := prev[j-1]
+			best.sub++
+			if prev[j].cost < best.cost {
+				best = prev[j]
+				best.del++
+			}
+			if curr[j-1].cost < best.cost {
+				best = curr[j-1]
+				best.ins++
+			}
+			best.cost++
+			curr[j] = best
+		}
+		prev = curr
+	}
+
+	final := prev[len(hyp)]
+	result := WERResult{
+		ReferenceWords: len(ref),
+		Substitutions:  final.sub,
+		Deletions:      final.del,
+		Insertions:     final.ins,
+	}
+	if len(ref) > 0 {
+		result.WER = float64(final.cost) / float64(len(ref))
+	} else if len(hyp) > 0 {
+		result.WER = 1.0
+	}
+	return result
+}
+
+// STTFixtureQuality is the per-provider, per-fixture WER outcome.
+type STTFixtureQuality struct {
+	FixtureID string    `json:"fixture_id"`
+	Result    WERResult `json:"result"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// STTProviderQuality aggregates WER for one provider across the corpus.
+type STTProviderQuality struct {
+	ProviderID   string              `json:"provider_id"`
+	ThresholdWER float64             `json:"threshold_wer"`
+	AggregateWER float64             `json:"aggregate_wer"`
+	Fixtures     []STTFixtureQuality `json:"fixtures"`
+	Passed       bool                `json:"passed"`
+}
+
+// STTQualityReport is the quality section consumed by SLO/MVP gates.
+type STTQualityReport struct {
+	Providers  []STTProviderQuality `json:"providers"`
+	Violations []string             `json:"violations,omitempty"`
+	Passed     bool                 `json:"passed"`
+}
+
+// EvaluateSTTQuality runs corpus fixtures through each provider and gates on per-provider WER.
+// Aggregate WER is corpus-level: total word errors divided by total reference words.
+func EvaluateSTTQuality(corpus STTGoldenCorpus, providerIDs []string, transcriber Transcriber) STTQualityReport {
+	report := STTQualityReport{}
+	if transcriber == nil {
+		report.Violations = append(report.Violations, "stt quality transcriber is required")
+		return report
+	}
+
+	ids := append([]string(nil), providerIDs...)
+	sort.Strings(ids)
+	if len(ids) == 0 {
+		report.Violations = append(report.Violations, "no stt providers configured for quality evaluation")
+	}
+
+	for _, providerID := range ids {
+		provider := STTProviderQuality{
+			ProviderID:   providerID,
+			ThresholdWER: corpus.ThresholdFor(providerID),
+			Fixtures:     make([]STTFixtureQuality, 0, len(corpus.Fixtures)),
+		}
+		errorsTotal := 0
+		wordsTotal := 0
+		failedFixtures := 0
+		for _, fixture := range corpus.Fixtures {
+			transcript, err := transcriber.Transcribe(providerID, fixture)
+			if err != nil {
+				failedFixtures++
+				provider.Fixtures = append(provider.Fixtures, STTFixtureQuality{FixtureID: fixture.FixtureID, Error: err.Error()})
+				report.Violations = append(report.Violations, fmt.Sprintf("provider %s fixture %s transcription failed: %v", providerID, fixture.FixtureID, err))
+				continue
+			}
+			result := ComputeWER(fixture.GoldenTranscript, transcript)
+			errorsTotal += result.Substitutions + result.Deletions + result.Insertions
+			wordsTotal += result.ReferenceWords
+			provider.Fixtures = append(provider.Fixtures, STTFixtureQuality{FixtureID: fixture.FixtureID, Result: result})
+		}
+		if wordsTotal > 0 {
+			provider.AggregateWER = float64(errorsTotal) / float64(wordsTotal)
+		}
+		provider.Passed = failedFixtures == 0 && provider.AggregateWER <= provider.ThresholdWER
+		if failedFixtures == 0 && !provider.Passed {
+			report.Violations = append(report.Violations, fmt.Sprintf("provider %s WER=%.3f exceeds threshold=%.3f", providerID, provider.AggregateWER, provider.ThresholdWER))
+		}
+		report.Providers = append(report.Providers, provider)
+	}
+
+	report.Passed = len(report.Violations) == 0
+	return report
+}
+
+// RecordedProviderIDs returns provider ids that have recorded transcripts in the corpus.
+func (c STTGoldenCorpus) RecordedProviderIDs() []string {
+	seen := map[string]struct{}{}
+	for _, fixture := range c.Fixtures {
+		for providerID := range fixture.RecordedTranscripts {
+			seen[providerID] = struct{}{}
+		}
+	}
+	ids := make([]string, 0, len(seen))
+	for providerID := range seen {
+		ids = append(ids, providerID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func normalizeTranscriptWords(transcript string) []string {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'':
+			return unicode.ToLower(r)
+		default:
+			return ' '
+		}
+	}, transcript)
+	return strings.Fields(cleaned)
+}
@@ -0,0 +1,208 @@
+package ops
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/invocation"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+)
+
+func TestComputeWER(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		reference  string
+		hypothesis string
+		want       WERResult
+	}{
+		{
+			name:       "exact match ignores case and punctuation",
+			reference:  "Book a table, please.",
+			hypothesis: "book a table please",
+			want:       WERResult{ReferenceWords: 4},
+		},
+		{
+			name:       "substitution",
+			reference:  "book a table for two",
+			hypothesis: "book a cable for two",
+			want:       WERResult{ReferenceWords: 5, Substitutions: 1, WER: 0.2},
+		},
+		{
+			name:       "deletion and insertion",
+			reference:  "cancel my order now",
+			hypothesis: "cancel order now please",
+			want:       WERResult{ReferenceWords: 4, Deletions: 1, Insertions: 1, WER: 0.5},
+		},
+		{
+			name:       "empty hypothesis",
+			reference:  "hello there",
+			hypothesis: "",
+			want:       WERResult{ReferenceWords: 2, Deletions: 2, WER: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := ComputeWER(tc.reference, tc.hypothesis)
+			if got != tc.want {
+				t.Fatalf("expected %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestEvaluateSTTQualityAppliesPerProviderThresholds(t *testing.T) {
+	t.Parallel()
+
+	corpus := STTGoldenCorpus{
+		DefaultWERThreshold:     0.5,
+		WERThresholdsByProvider: map[string]float64{"stt-strict": 0.1},
+		Fixtures: []STTGoldenFixture{
+			{
+				FixtureID:        "fx-1",
+				AudioRef:         "audio/fx-1.wav",
+				GoldenTranscript: "turn the lights off",
+				RecordedTranscripts: map[string]string{
+					"stt-strict":  "turn the light off",
+					"stt-lenient": "turn the light off",
+				},
+			},
+		},
+	}
+
+	report := EvaluateSTTQuality(corpus, []string{"stt-strict", "stt-lenient"}, RecordedTranscriber{})
+	if report.Passed {
+		t.Fatalf("expected strict provider threshold violation, got %+v", report)
+	}
+	if len(report.Providers) != 2 || report.Providers[0].ProviderID != "stt-lenient" {
+		t.Fatalf("expected providers sorted by id, got %+v", report.Providers)
+	}
+	if !report.Providers[0].Passed || report.Providers[1].Passed {
+		t.Fatalf("expected lenient pass and strict fail, got %+v", report.Providers)
+	}
+	if report.Providers[1].AggregateWER != 0.25 || report.Providers[1].ThresholdWER != 0.1 {
+		t.Fatalf("expected strict aggregate WER 0.25 against 0.1, got %+v", report.Providers[1])
+	}
+	if len(report.Violations) != 1 {
+		t.Fatalf("expected one violation, got %+v", report.Violations)
+	}
+}
+
+func TestEvaluateSTTQualityFailsOnTranscriptionError(t *testing.T) {
+	t.Parallel()
+
+	corpus := STTGoldenCorpus{
+		DefaultWERThreshold: 1,
+		Fixtures: []STTGoldenFixture{
+			{FixtureID: "fx-1", AudioRef: "audio/fx-1.wav", GoldenTranscript: "hello"},
+		},
+	}
+	report := EvaluateSTTQuality(corpus, []string{"stt-broken"}, failingTranscriber{})
+	if report.Passed || len(report.Violations) != 1 || report.Providers[0].Fixtures[0].Error == "" {
+		t.Fatalf("expected transcription failure to fail quality gate, got %+v", report)
+	}
+}
+
+func TestLoadSTTGoldenCorpusValidates(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "corpus.json")
+	if err := os.WriteFile(path, []byte(`{"default_wer_threshold":0.1,"fixtures":[{"fixture_id":"fx-1","audio_ref":"a.wav","golden_transcript":""}]}`), 0o644); err != nil {
+		t.Fatalf("write corpus: %v", err)
+	}
+	if _, err := LoadSTTGoldenCorpus(path); err == nil {
+		t.Fatalf("expected empty golden transcript to be rejected")
+	}
+}
+
+func TestDefaultSTTGoldenCorpusPasses(t *testing.T) {
+	t.Parallel()
+
+	corpus, err := LoadSTTGoldenCorpus(filepath.Join("..", "..", "..", DefaultSTTGoldenCorpusPath))
+	if err != nil {
+		t.Fatalf("unexpected corpus load error: %v", err)
+	}
+	report := EvaluateSTTQuality(corpus, corpus.RecordedProviderIDs(), RecordedTranscriber{})
+	if !report.Passed {
+		t.Fatalf("expected recorded golden corpus to pass, got %+v", report.Violations)
+	}
+}
+
+func TestInvocationTranscriberRunsCorpusAudioThroughInvocationPath(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join("..", "..", "..")
+	corpus, err := LoadSTTGoldenCorpus(filepath.Join(root, DefaultSTTGoldenCorpusPath))
+	if err != nil {
+		t.Fatalf("unexpected corpus load error: %v", err)
+	}
+	// Each stand-in adapter answers with the golden transcripts in corpus order and records
+	// the audio it was handed.
+	var mu sync.Mutex
+	calls := map[string][]contracts.AudioInput{}
+	adapterFor := func(id string) contracts.Adapter {
+		return contracts.StaticAdapter{ID: id, Mode: contracts.ModalitySTT, InvokeFn: func(req contracts.InvocationRequest) (contracts.Outcome, error) {
+			if err := req.Validate(); err != nil || req.Audio == nil {
+				return contracts.Outcome{Class: contracts.OutcomeBlocked, Reason: "provider_audio_missing"}, err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			calls[id] = append(calls[id], *req.Audio)
+			return contracts.Outcome{Class: contracts.OutcomeSuccess, Text: corpus.Fixtures[len(calls[id])-1].GoldenTranscript}, nil
+		}}
+	}
+	catalog, err := registry.NewCatalog([]contracts.Adapter{adapterFor("stt-a"), adapterFor("stt-b")})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	transcriber := InvocationTranscriber{
+		Invoker: invocation.NewController(catalog),
+		ResolveAudioRef: func(ref string) (string, error) {
+			return filepath.Join(root, ref), nil
+		},
+	}
+	report := EvaluateSTTQuality(corpus, []string{"stt-a", "stt-b"}, transcriber)
+	if !report.Passed {
+		t.Fatalf("expected every fixture transcribed through the invocation path, got %+v", report)
+	}
+	for _, providerID := range []string{"stt-a", "stt-b"} {
+		if len(calls[providerID]) != len(corpus.Fixtures) {
+			t.Fatalf("expected %d invocations of %s, got %d", len(corpus.Fixtures), providerID, len(calls[providerID]))
+		}
+		for _, audio := range calls[providerID] {
+			if audio.SampleRateHz != 16000 || len(audio.PCM) == 0 {
+				t.Fatalf("expected decoded 16 kHz fixture audio for %s, got %d samples at %d Hz", providerID, len(audio.PCM), audio.SampleRateHz)
+			}
+		}
+	}
+
+	tests := []struct {
+		name        string
+		providerID  string
+		fixture     STTGoldenFixture
+		wantErrPart string
+	}{
+		{name: "provider not in catalog", providerID: "stt-missing", fixture: corpus.Fixtures[0], wantErrPart: "is not registered"},
+		{name: "missing audio", providerID: "stt-a", fixture: STTGoldenFixture{FixtureID: "fx-missing", AudioRef: "test/quality/fixtures/audio/missing.wav"}, wantErrPart: "open audio_ref"},
+	}
+	for _, tc := range tests {
+		if _, err := transcriber.Transcribe(tc.providerID, tc.fixture); err == nil || !strings.Contains(err.Error(), tc.wantErrPart) {
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.wantErrPart, err)
+		}
+	}
+}
+
+type failingTranscriber struct{}
+
+func (failingTranscriber) Transcribe(string, STTGoldenFixture) (string, error) {
+	return "", errors.New("provider unavailable")
+}
@@ -0,0 +1,267 @@
+package providerbench
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+)
+
+// SchemaVersion identifies the provider benchmark report artifact layout.
+const SchemaVersion = "rspp-provider-benchmark/v1"
+
+// RankedBy documents the ranking order applied within each modality.
+const RankedBy = "error_rate,latency_p95_ms,estimated_cost_per_turn_usd,provider_id"
+
+// Window bounds the report to turns with a decision recorded in [Start, End).
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (w Window) contains(ms int64) bool {
+	return ms >= w.Start.UnixMilli() && ms < w.End.UnixMilli()
+}
+
+// ProviderStats aggregates one provider's invocations within the window.
+type ProviderStats struct {
+	Rank        int    `json:"rank"`
+	Modality    string `json:"modality"`
+	ProviderID  string `json:"provider_id"`
+	Invocations int    `json:"invocations"`
+	Attempts    int    `json:"attempts"`
+	Turns       int    `json:"turns"`
+	// Errors counts non-success invocations other than cancellations, which are turn-driven.
+	Errors       int     `json:"errors"`
+	Cancelled    int     `json:"cancelled"`
+	ErrorRate    float64 `json:"error_rate"`
+	LatencyP50MS int64   `json:"latency_p50_ms"`
+	LatencyP95MS int64   `json:"latency_p95_ms"`
+	LatencyP99MS int64   `json:"latency_p99_ms"`
+	// FirstChunk* cover streamed invocations that reported a first-chunk latency.
+	FirstChunkSamples int    `json:"first_chunk_samples"`
+	FirstChunkP50MS   *int64 `json:"first_chunk_p50_ms,omitempty"`
+	FirstChunkP95MS   *int64 `json:"first_chunk_p95_ms,omitempty"`
+	// EstimatedCostPerTurnUSD prices every attempt at the provider's typical per-turn cost;
+	// nil when the provider has no capability metadata.
+	EstimatedCostPerTurnUSD *float64 `json:"estimated_cost_per_turn_usd,omitempty"`
+	// Failovers counts provider_switch invocations this provider served. FailoverCold/Warm
+	// split their final-attempt latency by whether a warm standby connection was held, and
+	// FailoverLatencyImprovementMS is cold minus warm p50 when both were observed.
+	Failovers                    int    `json:"failovers,omitempty"`
+	FailoverColdP50MS            *int64 `json:"failover_cold_p50_ms,omitempty"`
+	FailoverWarmP50MS            *int64 `json:"failover_warm_p50_ms,omitempty"`
+	FailoverLatencyImprovementMS *int64 `json:"failover_latency_improvement_ms,omitempty"`
+	// StandbyHeldMS and StandbyUpkeepCostUSD total the warm standby upkeep recorded in the
+	// window; a provider can appear with upkeep and no invocations.
+	StandbyHeldMS        int64   `json:"standby_held_ms,omitempty"`
+	StandbyUpkeepCostUSD float64 `json:"standby_upkeep_cost_usd,omitempty"`
+}
+
+// ModalityRanking lists a modality's providers best first.
+type ModalityRanking struct {
+	Modality  string          `json:"modality"`
+	Providers []ProviderStats `json:"providers"`
+}
+
+// Report is the ranked provider comparison artifact.
+type Report struct {
+	SchemaVersion      string            `json:"schema_version"`
+	GeneratedAtUTC     string            `json:"generated_at_utc"`
+	Environment        string            `json:"environment,omitempty"`
+	WindowStartUTC     string            `json:"window_start_utc"`
+	WindowEndUTC       string            `json:"window_end_utc"`
+	BaselineArtifacts  []string          `json:"baseline_artifacts,omitempty"`
+	RankedBy           string            `json:"ranked_by"`
+	TurnsInWindow      int               `json:"turns_in_window"`
+	TurnsOutsideWindow int               `json:"turns_outside_window"`
+	Modalities         []ModalityRanking `json:"modalities"`
+}
+
+type providerKey struct {
+	modality   string
+	providerID string
+}
+
+type accumulator struct {
+	stats      ProviderStats
+	latencies  []int64
+	firstChunk []int64
+	coldSwitch []int64
+	warmSwitch []int64
+	turns      map[string]struct{}
+}
+
+func accumulatorFor(providers map[providerKey]*accumulator, modality string, providerID string) *accumulator {
+	key := providerKey{modality: modality, providerID: providerID}
+	acc, ok := providers[key]
+	if !ok {
+		acc = &accumulator{
+			stats: ProviderStats{Modality: modality, ProviderID: providerID},
+			turns: map[string]struct{}{},
+		}
+		providers[key] = acc
+	}
+	return acc
+}
+
+// Build aggregates the invocation outcomes of every turn whose decisions were recorded inside
+// the window, per modality and provider, and ranks each modality's providers.
+func Build(entries []timeline.BaselineEvidence, window Window, capabilities registry.Capabilities, environment string, now time.Time) (Report, error) {
+	if !window.End.After(window.Start) {
+		return Report{}, fmt.Errorf("provider benchmark window end must be after start")
+	}
+	report := Report{
+		SchemaVersion:  SchemaVersion,
+		GeneratedAtUTC: now.UTC().Format(time.RFC3339),
+		Environment:    environment,
+		WindowStartUTC: window.Start.UTC().Format(time.RFC3339),
+		WindowEndUTC:   window.End.UTC().Format(time.RFC3339),
+		RankedBy:       RankedBy,
+		Modalities:     []ModalityRanking{},
+	}
+
+	providers := map[providerKey]*accumulator{}
+	for _, entry := range entries {
+		if !inWindow(entry, window) {
+			report.TurnsOutsideWindow++
+			continue
+		}
+		report.TurnsInWindow++
+		for _, outcome := range entry.InvocationOutcomes {
+			if err := outcome.Validate(); err != nil {
+				return Report{}, fmt.Errorf("turn %s/%s invocation %s: %w", entry.SessionID, entry.TurnID, outcome.ProviderInvocationID, err)
+			}
+			acc := accumulatorFor(providers, outcome.Modality, outcome.ProviderID)
+			acc.stats.Invocations++
+			acc.stats.Attempts += outcome.AttemptCount
+			acc.turns[entry.SessionID+"/"+entry.TurnID] = struct{}{}
+			switch outcome.OutcomeClass {
+			case "success":
+			case "cancelled":
+				acc.stats.Cancelled++
+			default:
+				acc.stats.Errors++
+			}
+			acc.latencies = append(acc.latencies, outcome.TotalInvocationLatencyMS)
+			if outcome.FirstChunkLatencyMS > 0 {
+				acc.firstChunk = append(acc.firstChunk, outcome.FirstChunkLatencyMS)
+			}
+			if outcome.RetryDecision == "provider_switch" {
+				acc.stats.Failovers++
+				if outcome.WarmStandby {
+					acc.warmSwitch = append(acc.warmSwitch, outcome.FinalAttemptLatencyMS)
+				} else {
+					acc.coldSwitch = append(acc.coldSwitch, outcome.FinalAttemptLatencyMS)
+				}
+			}
+		}
+		for _, upkeep := range entry.StandbyUpkeep {
+			if err := upkeep.Validate(); err != nil {
+				return Report{}, fmt.Errorf("turn %s/%s standby upkeep: %w", entry.SessionID, entry.TurnID, err)
+			}
+			acc := accumulatorFor(providers, upkeep.Modality, upkeep.ProviderID)
+			acc.stats.StandbyHeldMS += upkeep.HeldMS
+			acc.stats.StandbyUpkeepCostUSD += upkeep.EstimatedCostUSD
+		}
+	}
+
+	byModality := map[string][]ProviderStats{}
+	for key, acc := range providers {
+		stats := acc.stats
+		stats.Turns = len(acc.turns)
+		if stats.Invocations > 0 {
+			stats.ErrorRate = float64(stats.Errors) / float64(stats.Invocations)
+		}
+		stats.LatencyP50MS = percentile(acc.latencies, 0.50)
+		stats.LatencyP95MS = percentile(acc.latencies, 0.95)
+		stats.LatencyP99MS = percentile(acc.latencies, 0.99)
+		stats.FirstChunkSamples = len(acc.firstChunk)
+		if len(acc.firstChunk) > 0 {
+			p50 := percentile(acc.firstChunk, 0.50)
+			p95 := percentile(acc.firstChunk, 0.95)
+			stats.FirstChunkP50MS = &p50
+			stats.FirstChunkP95MS = &p95
+		}
+		if len(acc.coldSwitch) > 0 {
+			cold := percentile(acc.coldSwitch, 0.50)
+			stats.FailoverColdP50MS = &cold
+		}
+		if len(acc.warmSwitch) > 0 {
+			warm := percentile(acc.warmSwitch, 0.50)
+			stats.FailoverWarmP50MS = &warm
+		}
+		if stats.FailoverColdP50MS != nil && stats.FailoverWarmP50MS != nil {
+			improvement := *stats.FailoverColdP50MS - *stats.FailoverWarmP50MS
+			stats.FailoverLatencyImprovementMS = &improvement
+		}
+		if capability, ok := capabilities.Capability(contracts.Modality(key.modality), key.providerID); ok && stats.Turns > 0 {
+			cost := capability.TypicalCostPerTurnUSD * float64(stats.Attempts) / float64(stats.Turns)
+			stats.EstimatedCostPerTurnUSD = &cost
+		}
+		byModality[key.modality] = append(byModality[key.modality], stats)
+	}
+
+	modalities := make([]string, 0, len(byModality))
+	for modality := range byModality {
+		modalities = append(modalities, modality)
+	}
+	sort.Strings(modalities)
+	for _, modality := range modalities {
+		ranked := byModality[modality]
+		sort.Slice(ranked, func(i, j int) bool { return rankLess(ranked[i], ranked[j]) })
+		for i := range ranked {
+			ranked[i].Rank = i + 1
+		}
+		report.Modalities = append(report.Modalities, ModalityRanking{Modality: modality, Providers: ranked})
+	}
+	return report, nil
+}
+
+// inWindow reports whether any of the turn's decisions was recorded inside the window.
+func inWindow(entry timeline.BaselineEvidence, window Window) bool {
+	for _, decision := range entry.DecisionOutcomes {
+		if window.contains(decision.WallClockMS) {
+			return true
+		}
+	}
+	return false
+}
+
+func rankLess(a, b ProviderStats) bool {
+	if a.ErrorRate != b.ErrorRate {
+		return a.ErrorRate < b.ErrorRate
+	}
+	if a.LatencyP95MS != b.LatencyP95MS {
+		return a.LatencyP95MS < b.LatencyP95MS
+	}
+	// Providers without cost metadata rank after priced ones.
+	if (a.EstimatedCostPerTurnUSD == nil) != (b.EstimatedCostPerTurnUSD == nil) {
+		return a.EstimatedCostPerTurnUSD != nil
+	}
+	if a.EstimatedCostPerTurnUSD != nil && *a.EstimatedCostPerTurnUSD != *b.EstimatedCostPerTurnUSD {
+		return *a.EstimatedCostPerTurnUSD < *b.EstimatedCostPerTurnUSD
+	}
+	return a.ProviderID < b.ProviderID
+}
+
+// percentile returns the nearest-rank percentile of values.
+func percentile(values []int64, p float64) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
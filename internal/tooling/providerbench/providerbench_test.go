@@ -0,0 +1,163 @@
+package providerbench
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+)
+
+var benchWindowStart = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+func benchTurn(turnID string, at time.Time, outcomes ...timeline.InvocationOutcomeEvidence) timeline.BaselineEvidence {
+	return timeline.BaselineEvidence{
+		SessionID: "sess-bench",
+		TurnID:    turnID,
+		DecisionOutcomes: []controlplane.DecisionOutcome{{
+			OutcomeKind: controlplane.OutcomeAdmit,
+			SessionID:   "sess-bench",
+			TurnID:      turnID,
+			WallClockMS: at.UnixMilli(),
+		}},
+		InvocationOutcomes: outcomes,
+	}
+}
+
+func benchInvocation(id string, modality string, providerID string, outcomeClass string, attempts int, latencyMS int64, firstChunkMS int64) timeline.InvocationOutcomeEvidence {
+	retryDecision := "none"
+	if attempts > 1 {
+		retryDecision = "retry"
+	}
+	return timeline.InvocationOutcomeEvidence{
+		ProviderInvocationID:     id,
+		Modality:                 modality,
+		ProviderID:               providerID,
+		OutcomeClass:             outcomeClass,
+		RetryDecision:            retryDecision,
+		AttemptCount:             attempts,
+		FinalAttemptLatencyMS:    latencyMS,
+		TotalInvocationLatencyMS: latencyMS,
+		FirstChunkLatencyMS:      firstChunkMS,
+	}
+}
+
+func TestBuildRanksProvidersPerModality(t *testing.T) {
+	t.Parallel()
+
+	day := benchWindowStart.Add(12 * time.Hour)
+	entries := []timeline.BaselineEvidence{
+		benchTurn("turn-1", day,
+			benchInvocation("pvi-1", "llm", "llm-anthropic", "success", 1, 800, 300),
+			benchInvocation("pvi-2", "tts", "tts-google", "success", 1, 400, 0)),
+		benchTurn("turn-2", day,
+			benchInvocation("pvi-3", "llm", "llm-gemini", "success", 1, 500, 200),
+			benchInvocation("pvi-4", "tts", "tts-google", "timeout", 2, 900, 0)),
+		benchTurn("turn-3", day,
+			benchInvocation("pvi-5", "llm", "llm-anthropic", "success", 1, 600, 250),
+			benchInvocation("pvi-6", "llm", "llm-custom", "success", 1, 500, 0)),
+		benchTurn("turn-4", day,
+			benchInvocation("pvi-7", "llm", "llm-gemini", "cancelled", 1, 100, 0)),
+		benchTurn("turn-old", benchWindowStart.Add(-time.Hour),
+			benchInvocation("pvi-8", "llm", "llm-gemini", "timeout", 1, 5000, 0)),
+	}
+
+	report, err := Build(entries, Window{Start: benchWindowStart, End: benchWindowStart.Add(24 * time.Hour)}, registry.DefaultCapabilities(), "staging", day)
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+	if report.TurnsInWindow != 4 || report.TurnsOutsideWindow != 1 || len(report.Modalities) != 2 {
+		t.Fatalf("expected 4 windowed turns across llm and tts, got %+v", report)
+	}
+
+	llm := report.Modalities[0]
+	if llm.Modality != "llm" || len(llm.Providers) != 3 {
+		t.Fatalf("expected three llm providers, got %+v", llm)
+	}
+	order := []string{llm.Providers[0].ProviderID, llm.Providers[1].ProviderID, llm.Providers[2].ProviderID}
+	if order[0] != "llm-gemini" || order[1] != "llm-custom" || order[2] != "llm-anthropic" || llm.Providers[0].Rank != 1 {
+		t.Fatalf("expected gemini, custom (same p95, unpriced), anthropic, got %v", order)
+	}
+	gemini := llm.Providers[0]
+	if gemini.Errors != 0 || gemini.Cancelled != 1 || gemini.LatencyP95MS != 500 || gemini.FirstChunkSamples != 1 || *gemini.FirstChunkP50MS != 200 {
+		t.Fatalf("expected cancellations excluded from errors and first-chunk samples, got %+v", gemini)
+	}
+	if llm.Providers[1].EstimatedCostPerTurnUSD != nil {
+		t.Fatalf("expected no cost estimate without capability metadata, got %+v", llm.Providers[1])
+	}
+	anthropic := llm.Providers[2]
+	if anthropic.Turns != 2 || anthropic.LatencyP50MS != 600 || anthropic.LatencyP95MS != 800 || *anthropic.FirstChunkP95MS != 300 {
+		t.Fatalf("expected anthropic percentiles over two turns, got %+v", anthropic)
+	}
+
+	tts := report.Modalities[1].Providers[0]
+	if tts.ProviderID != "tts-google" || tts.Errors != 1 || tts.ErrorRate != 0.5 || tts.FirstChunkP50MS != nil {
+		t.Fatalf("expected tts-google error rate 0.5 without first-chunk samples, got %+v", tts)
+	}
+	if cost := *tts.EstimatedCostPerTurnUSD; cost < 0.0023 || cost > 0.0025 {
+		t.Fatalf("expected retries priced into cost per turn (3 attempts over 2 turns), got %f", cost)
+	}
+}
+
+func TestBuildRejectsInvalidInputs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		entries []timeline.BaselineEvidence
+		window  Window
+	}{
+		{name: "inverted window", window: Window{Start: benchWindowStart, End: benchWindowStart.Add(-time.Hour)}},
+		{
+			name:    "invalid invocation evidence",
+			entries: []timeline.BaselineEvidence{benchTurn("turn-1", benchWindowStart, benchInvocation("pvi-1", "llm", "llm-gemini", "success", 0, 10, 0))},
+			window:  Window{Start: benchWindowStart, End: benchWindowStart.Add(time.Hour)},
+		},
+	}
+	for _, tc := range tests {
+		if _, err := Build(tc.entries, tc.window, registry.DefaultCapabilities(), "", benchWindowStart); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
+
+func TestBuildReportsWarmStandbyFailover(t *testing.T) {
+	t.Parallel()
+
+	day := benchWindowStart.Add(12 * time.Hour)
+	failover := func(id string, latencyMS int64, warm bool) timeline.InvocationOutcomeEvidence {
+		outcome := benchInvocation(id, "stt", "stt-google", "success", 2, latencyMS, 0)
+		outcome.RetryDecision = "provider_switch"
+		outcome.WarmStandby = warm
+		return outcome
+	}
+	standby := benchTurn("turn-2", day, failover("pvi-2", 120, true), failover("pvi-3", 140, true))
+	standby.StandbyUpkeep = []timeline.StandbyUpkeepEvidence{
+		{ProviderID: "stt-google", Modality: "stt", HeldMS: 60000, Refreshes: 2, EstimatedCostUSD: 0.002},
+		{ProviderID: "tts-google", Modality: "tts", HeldMS: 30000, Refreshes: 1, EstimatedCostUSD: 0.001},
+	}
+	entries := []timeline.BaselineEvidence{
+		benchTurn("turn-1", day, failover("pvi-1", 420, false), benchInvocation("pvi-4", "stt", "stt-google", "success", 1, 90, 0)),
+		standby,
+	}
+
+	report, err := Build(entries, Window{Start: benchWindowStart, End: benchWindowStart.Add(24 * time.Hour)}, registry.DefaultCapabilities(), "", day)
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+	if len(report.Modalities) != 2 {
+		t.Fatalf("expected stt and upkeep-only tts rankings, got %+v", report.Modalities)
+	}
+	stt := report.Modalities[0].Providers[0]
+	if stt.Failovers != 3 || *stt.FailoverColdP50MS != 420 || *stt.FailoverWarmP50MS != 120 || *stt.FailoverLatencyImprovementMS != 300 {
+		t.Fatalf("expected cold 420ms vs warm 120ms failover split, got %+v", stt)
+	}
+	if stt.StandbyHeldMS != 60000 || stt.StandbyUpkeepCostUSD != 0.002 {
+		t.Fatalf("expected standby upkeep totals, got %+v", stt)
+	}
+	tts := report.Modalities[1].Providers[0]
+	if tts.Invocations != 0 || tts.ErrorRate != 0 || tts.EstimatedCostPerTurnUSD != nil || tts.StandbyUpkeepCostUSD != 0.001 {
+		t.Fatalf("expected an upkeep-only tts standby entry, got %+v", tts)
+	}
+}
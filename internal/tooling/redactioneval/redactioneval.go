@@ -0,0 +1,242 @@
+package redactioneval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/security/piidetect"
+)
+
+// DefaultSuitePath is the repository-relative labeled transcript PII/PHI suite.
+const DefaultSuitePath = "test/quality/fixtures/pii_redaction_suite.json"
+
+// evaluatedClasses are the payload classes the redaction rules detect.
+var evaluatedClasses = []eventabi.PayloadClass{eventabi.PayloadPII, eventabi.PayloadPHI}
+
+// Label marks one occurrence of Text in a fixture transcript as Class. Repeated label texts
+// claim successive occurrences.
+type Label struct {
+	Class eventabi.PayloadClass `json:"class"`
+	Text  string                `json:"text"`
+}
+
+// Fixture is one transcript with every PII/PHI span labeled; unlabeled text must stay
+// unredacted.
+type Fixture struct {
+	FixtureID  string  `json:"fixture_id"`
+	Transcript string  `json:"transcript"`
+	Labels     []Label `json:"labels,omitempty"`
+}
+
+// Thresholds define pass criteria for one class.
+type Thresholds struct {
+	MinPrecision float64 `json:"min_precision"`
+	MinRecall    float64 `json:"min_recall"`
+}
+
+// Suite groups labeled fixtures with per-class pass thresholds.
+type Suite struct {
+	DefaultThresholds Thresholds                           `json:"default_thresholds"`
+	ThresholdsByClass map[eventabi.PayloadClass]Thresholds `json:"thresholds_by_class,omitempty"`
+	Fixtures          []Fixture                            `json:"fixtures"`
+}
+
+// Validate enforces suite shape requirements, including that every label occurs in its
+// transcript.
+func (s Suite) Validate() error {
+	if len(s.Fixtures) == 0 {
+		return fmt.Errorf("redaction eval suite requires at least one fixture")
+	}
+	if err := validateThresholds(s.DefaultThresholds); err != nil {
+		return fmt.Errorf("default_thresholds: %w", err)
+	}
+	for class, thresholds := range s.ThresholdsByClass {
+		if class != eventabi.PayloadPII && class != eventabi.PayloadPHI {
+			return fmt.Errorf("thresholds_by_class supports only %s and %s, got %q", eventabi.PayloadPII, eventabi.PayloadPHI, class)
+		}
+		if err := validateThresholds(thresholds); err != nil {
+			return fmt.Errorf("thresholds_by_class[%s]: %w", class, err)
+		}
+	}
+	seen := make(map[string]struct{}, len(s.Fixtures))
+	for _, fixture := range s.Fixtures {
+		if fixture.FixtureID == "" || strings.TrimSpace(fixture.Transcript) == "" {
+			return fmt.Errorf("redaction eval fixture_id and transcript are required")
+		}
+		if _, ok := seen[fixture.FixtureID]; ok {
+			return fmt.Errorf("duplicate redaction eval fixture_id: %s", fixture.FixtureID)
+		}
+		seen[fixture.FixtureID] = struct{}{}
+		for _, label := range fixture.Labels {
+			if label.Class != eventabi.PayloadPII && label.Class != eventabi.PayloadPHI {
+				return fmt.Errorf("fixture %s label class must be %s or %s, got %q", fixture.FixtureID, eventabi.PayloadPII, eventabi.PayloadPHI, label.Class)
+			}
+		}
+		if _, err := locateLabels(fixture); err != nil {
+			return fmt.Errorf("fixture %s: %w", fixture.FixtureID, err)
+		}
+	}
+	return nil
+}
+
+// ThresholdsFor returns the pass thresholds configured for a class.
+func (s Suite) ThresholdsFor(class eventabi.PayloadClass) Thresholds {
+	if thresholds, ok := s.ThresholdsByClass[class]; ok {
+		return thresholds
+	}
+	return s.DefaultThresholds
+}
+
+// LoadSuite reads a redaction eval suite from disk.
+func LoadSuite(path string) (Suite, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Suite{}, fmt.Errorf("read redaction eval suite %s: %w", path, err)
+	}
+	var suite Suite
+	if err := json.Unmarshal(raw, &suite); err != nil {
+		return Suite{}, fmt.Errorf("decode redaction eval suite %s: %w", path, err)
+	}
+	if err := suite.Validate(); err != nil {
+		return Suite{}, fmt.Errorf("validate redaction eval suite %s: %w", path, err)
+	}
+	return suite, nil
+}
+
+// ClassResult aggregates span matches for one class across the suite.
+type ClassResult struct {
+	Class          eventabi.PayloadClass `json:"class"`
+	Thresholds     Thresholds            `json:"thresholds"`
+	Labels         int                   `json:"labels"`
+	Detected       int                   `json:"detected"`
+	TruePositives  int                   `json:"true_positives"`
+	FalsePositives int                   `json:"false_positives"`
+	FalseNegatives int                   `json:"false_negatives"`
+	Precision      float64               `json:"precision"`
+	Recall         float64               `json:"recall"`
+	Passed         bool                  `json:"passed"`
+}
+
+// Miss is one over-redacted (false_positive) or under-redacted (false_negative) span.
+type Miss struct {
+	FixtureID string                `json:"fixture_id"`
+	Kind      string                `json:"kind"`
+	Class     eventabi.PayloadClass `json:"class"`
+	Rule      string                `json:"rule,omitempty"`
+	Text      string                `json:"text"`
+}
+
+// Report is the transcript redaction quality result attachable to release readiness.
+type Report struct {
+	Fixtures   int           `json:"fixtures"`
+	Classes    []ClassResult `json:"classes"`
+	Misses     []Miss        `json:"misses,omitempty"`
+	Violations []string      `json:"violations,omitempty"`
+	Passed     bool          `json:"passed"`
+}
+
+type labelSpan struct {
+	class eventabi.PayloadClass
+	start int
+	end   int
+}
+
+// Evaluate runs the detector over every fixture and scores its findings against the labels.
+// A finding that overlaps an unmatched label of the same class is a true positive; any other
+// finding is a false positive, and every label left unmatched is a false negative. Precision
+// and recall are 1 when a class has nothing detected or nothing labeled respectively.
+func Evaluate(suite Suite, detector piidetect.Detector) Report {
+	report := Report{Fixtures: len(suite.Fixtures)}
+	results := make(map[eventabi.PayloadClass]*ClassResult, len(evaluatedClasses))
+	for _, class := range evaluatedClasses {
+		results[class] = &ClassResult{Class: class, Thresholds: suite.ThresholdsFor(class)}
+	}
+
+	for _, fixture := range suite.Fixtures {
+		labels, err := locateLabels(fixture)
+		if err != nil {
+			report.Violations = append(report.Violations, fmt.Sprintf("fixture %s: %v", fixture.FixtureID, err))
+			continue
+		}
+		matched := make([]bool, len(labels))
+		for _, label := range labels {
+			results[label.class].Labels++
+		}
+		for _, finding := range detector.Detect(fixture.Transcript) {
+			result := results[finding.Class]
+			result.Detected++
+			hit := false
+			for idx, label := range labels {
+				if !matched[idx] && label.class == finding.Class && finding.Start < label.end && label.start < finding.End {
+					matched[idx] = true
+					hit = true
+					break
+				}
+			}
+			if hit {
+				result.TruePositives++
+				continue
+			}
+			result.FalsePositives++
+			report.Misses = append(report.Misses, Miss{FixtureID: fixture.FixtureID, Kind: "false_positive", Class: finding.Class, Rule: finding.Rule, Text: fixture.Transcript[finding.Start:finding.End]})
+		}
+		for idx, label := range labels {
+			if !matched[idx] {
+				results[label.class].FalseNegatives++
+				report.Misses = append(report.Misses, Miss{FixtureID: fixture.FixtureID, Kind: "false_negative", Class: label.class, Text: fixture.Transcript[label.start:label.end]})
+			}
+		}
+	}
+
+	for _, class := range evaluatedClasses {
+		result := results[class]
+		result.Precision = ratio(result.TruePositives, result.TruePositives+result.FalsePositives)
+		result.Recall = ratio(result.TruePositives, result.TruePositives+result.FalseNegatives)
+		result.Passed = result.Precision >= result.Thresholds.MinPrecision && result.Recall >= result.Thresholds.MinRecall
+		if !result.Passed {
+			report.Violations = append(report.Violations, fmt.Sprintf("class %s precision=%.3f recall=%.3f below thresholds min_precision=%.3f min_recall=%.3f", class, result.Precision, result.Recall, result.Thresholds.MinPrecision, result.Thresholds.MinRecall))
+		}
+		report.Classes = append(report.Classes, *result)
+	}
+	report.Passed = len(report.Violations) == 0
+	return report
+}
+
+// locateLabels resolves label texts to spans ordered by offset.
+func locateLabels(fixture Fixture) ([]labelSpan, error) {
+	spans := make([]labelSpan, 0, len(fixture.Labels))
+	claimed := map[string]int{}
+	for _, label := range fixture.Labels {
+		if label.Text == "" {
+			return nil, fmt.Errorf("label text is required")
+		}
+		from := claimed[label.Text]
+		offset := strings.Index(fixture.Transcript[from:], label.Text)
+		if offset < 0 {
+			return nil, fmt.Errorf("label %q does not occur in the transcript", label.Text)
+		}
+		start := from + offset
+		claimed[label.Text] = start + len(label.Text)
+		spans = append(spans, labelSpan{class: label.Class, start: start, end: start + len(label.Text)})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	return spans, nil
+}
+
+func ratio(numerator int, denominator int) float64 {
+	if denominator == 0 {
+		return 1
+	}
+	return float64(numerator) / float64(denominator)
+}
+
+func validateThresholds(t Thresholds) error {
+	if t.MinPrecision < 0 || t.MinPrecision > 1 || t.MinRecall < 0 || t.MinRecall > 1 {
+		return fmt.Errorf("min_precision and min_recall must be within [0,1]")
+	}
+	return nil
+}
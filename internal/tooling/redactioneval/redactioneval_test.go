@@ -0,0 +1,83 @@
+package redactioneval
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/security/piidetect"
+)
+
+func TestDefaultSuiteMeetsThresholdsWithDefaultRules(t *testing.T) {
+	t.Parallel()
+
+	suite, err := LoadSuite(filepath.Join("..", "..", "..", DefaultSuitePath))
+	if err != nil {
+		t.Fatalf("unexpected suite load error: %v", err)
+	}
+	report := Evaluate(suite, piidetect.NewDefaultDetector())
+	if !report.Passed || len(report.Classes) != 2 {
+		t.Fatalf("expected default rules to pass the suite, got %+v", report)
+	}
+	misses := map[string]bool{}
+	for _, miss := range report.Misses {
+		misses[miss.FixtureID+":"+miss.Kind] = true
+	}
+	for _, want := range []string{"pii-011-spelled-out-phone:false_negative", "phi-005-unlisted-condition:false_negative", "phi-007-no-phi-weather-depression:false_positive"} {
+		if !misses[want] {
+			t.Fatalf("expected known miss %s in report, got %+v", want, report.Misses)
+		}
+	}
+}
+
+func TestEvaluateGatesOverAndUnderRedaction(t *testing.T) {
+	t.Parallel()
+
+	suite := Suite{
+		DefaultThresholds: Thresholds{MinPrecision: 0.9, MinRecall: 0.9},
+		Fixtures: []Fixture{
+			{FixtureID: "f1", Transcript: "reach me at a@example.com or b@example.com", Labels: []Label{{Class: eventabi.PayloadPII, Text: "a@example.com"}, {Class: eventabi.PayloadPII, Text: "b@example.com"}}},
+			{FixtureID: "f2", Transcript: "order 12345 ships today"},
+		},
+	}
+	tests := []struct {
+		name          string
+		pattern       string
+		wantPrecision float64
+		wantRecall    float64
+	}{
+		{name: "exact rules", pattern: `\S+@example\.com`, wantPrecision: 1, wantRecall: 1},
+		{name: "under-redaction", pattern: `a@example\.com`, wantPrecision: 1, wantRecall: 0.5},
+		{name: "over-redaction", pattern: `\S+@example\.com|\d{5}`, wantPrecision: 2.0 / 3.0, wantRecall: 1},
+	}
+	for _, tc := range tests {
+		detector, err := piidetect.NewDetector([]piidetect.Rule{{Name: "test", Class: eventabi.PayloadPII, Pattern: regexp.MustCompile(tc.pattern)}})
+		if err != nil {
+			t.Fatalf("%s: unexpected detector error: %v", tc.name, err)
+		}
+		report := Evaluate(suite, detector)
+		pii := report.Classes[0]
+		if pii.Class != eventabi.PayloadPII || pii.Precision != tc.wantPrecision || pii.Recall != tc.wantRecall {
+			t.Fatalf("%s: expected precision=%.3f recall=%.3f, got %+v", tc.name, tc.wantPrecision, tc.wantRecall, pii)
+		}
+		wantPassed := tc.wantPrecision >= 0.9 && tc.wantRecall >= 0.9
+		if report.Passed != wantPassed || (!wantPassed && !strings.Contains(strings.Join(report.Violations, ";"), "class PII")) {
+			t.Fatalf("%s: expected passed=%t with a PII violation on failure, got %+v", tc.name, wantPassed, report)
+		}
+	}
+}
+
+func TestSuiteValidateRejectsMissingLabels(t *testing.T) {
+	t.Parallel()
+
+	suite := Suite{Fixtures: []Fixture{{FixtureID: "f1", Transcript: "call 555-0100", Labels: []Label{{Class: eventabi.PayloadPII, Text: "555-0199"}}}}}
+	if err := suite.Validate(); err == nil || !strings.Contains(err.Error(), "does not occur") {
+		t.Fatalf("expected a label absent from the transcript to be rejected, got %v", err)
+	}
+	suite.Fixtures[0].Labels = []Label{{Class: eventabi.PayloadTextRaw, Text: "555-0100"}}
+	if err := suite.Validate(); err == nil {
+		t.Fatalf("expected a non PII/PHI label class to be rejected")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
 )
 
 // ExpectedDivergence declares fixture-approved divergences by class and scope.
@@ -16,8 +17,12 @@ type ExpectedDivergence struct {
 
 // DivergencePolicy defines fail criteria for replay divergences.
 type DivergencePolicy struct {
-	TimingToleranceMS int64                `json:"timing_tolerance_ms"`
-	Expected          []ExpectedDivergence `json:"expected,omitempty"`
+	TimingToleranceMS int64 `json:"timing_tolerance_ms"`
+	// ScopeTimingTolerancesMS overrides TimingToleranceMS for matching scopes. A pattern that
+	// matches an invocation latency scope turns its always-failing threshold breach into one
+	// allowed to overshoot by at most the pattern tolerance.
+	ScopeTimingTolerancesMS replay.ScopeTolerances `json:"scope_timing_tolerances_ms,omitempty"`
+	Expected                []ExpectedDivergence   `json:"expected,omitempty"`
 }
 
 // DivergenceEvaluation returns policy outcomes for replay divergences.
@@ -63,7 +68,11 @@ func EvaluateDivergences(divergences []obs.ReplayDivergence, policy DivergencePo
 				}
 			}
 		case obs.TimingDivergence:
-			if isInvocationLatencyScope(entryCopy.Scope) || exceedsTimingTolerance(entryCopy, policy.TimingToleranceMS) {
+			tolerance, scoped := policy.ScopeTimingTolerancesMS.Resolve(entryCopy.Scope)
+			if !scoped {
+				tolerance = policy.TimingToleranceMS
+			}
+			if (!scoped && isInvocationLatencyScope(entryCopy.Scope)) || exceedsTimingTolerance(entryCopy, tolerance) {
 				evaluation.Failing = append(evaluation.Failing, entryCopy)
 			}
 		default:
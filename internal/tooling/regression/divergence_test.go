@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
 )
 
 func TestEvaluateDivergencesUnexplainedPlanFails(t *testing.T) {
@@ -120,3 +121,42 @@ func TestEvaluateDivergencesInvocationLatencyTimingAlwaysFails(t *testing.T) {
 		t.Fatalf("expected invocation latency timing divergence to fail regardless of tolerance, got %+v", eval.Failing)
 	}
 }
+
+func TestEvaluateDivergencesScopeTimingTolerances(t *testing.T) {
+	t.Parallel()
+
+	diff := func(ms int64) *int64 { return &ms }
+	divergences := []obs.ReplayDivergence{
+		{Class: obs.TimingDivergence, Scope: "turn_open:turn-1", Message: "timing mismatch", DiffMS: diff(12)},
+		{Class: obs.TimingDivergence, Scope: "turn:turn-1", Message: "timing mismatch", DiffMS: diff(12)},
+		{Class: obs.TimingDivergence, Scope: "invocation_latency_final:turn:turn-1", Message: "threshold exceeded", DiffMS: diff(40)},
+		{Class: obs.TimingDivergence, Scope: "invocation_latency_total:turn:turn-1", Message: "threshold exceeded", DiffMS: diff(1)},
+	}
+	evaluation := EvaluateDivergences(divergences, DivergencePolicy{
+		TimingToleranceMS: 15,
+		ScopeTimingTolerancesMS: replay.ScopeTolerances{
+			"turn_open:*":                10,
+			"invocation_latency_final:*": 50,
+		},
+	})
+
+	failing := map[string]bool{}
+	for _, entry := range evaluation.Failing {
+		failing[entry.Scope] = true
+	}
+	tests := []struct {
+		name  string
+		scope string
+		fail  bool
+	}{
+		{name: "tight scope pattern fails over its tolerance", scope: "turn_open:turn-1", fail: true},
+		{name: "unmatched scope uses blanket tolerance", scope: "turn:turn-1", fail: false},
+		{name: "scoped invocation latency allows overshoot", scope: "invocation_latency_final:turn:turn-1", fail: false},
+		{name: "unscoped invocation latency always fails", scope: "invocation_latency_total:turn:turn-1", fail: true},
+	}
+	for _, tc := range tests {
+		if failing[tc.scope] != tc.fail {
+			t.Fatalf("%s: expected failing=%t, got %+v", tc.name, tc.fail, evaluation.Failing)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package release
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Break-glass environment variables. Registered operators are listed as
+// operator=<sha256 hex of their token> pairs; the invoking operator proves their identity by
+// presenting the token itself, so naming another operator is not enough to bypass gates.
+const (
+	EnvBreakGlassOperators = "RSPP_BREAK_GLASS_OPERATORS"
+	EnvBreakGlassOperator  = "RSPP_BREAK_GLASS_OPERATOR"
+	EnvBreakGlassToken     = "RSPP_BREAK_GLASS_TOKEN"
+	EnvBreakGlassReason    = "RSPP_BREAK_GLASS_REASON"
+	EnvBreakGlassGates     = "RSPP_BREAK_GLASS_GATES"
+	EnvBreakGlassAuditLog  = "RSPP_BREAK_GLASS_AUDIT_LOG"
+)
+
+const (
+	DefaultBreakGlassAuditLogPath = ".codex/release/break-glass-audit.jsonl"
+	// BreakGlassGenesisHash is the previous hash of the first audit chain entry.
+	BreakGlassGenesisHash  = "0000000000000000000000000000000000000000000000000000000000000000"
+	minBreakGlassReasonLen = 12
+)
+
+// bypassableGates are the readiness checks break-glass may bypass. Rollout config validation,
+// environment matching, and spec_ref requirements are never bypassable.
+var bypassableGates = map[string]struct{}{
+	"contracts_report":         {},
+	"replay_regression_report": {},
+	"slo_gates_report":         {},
+	"llm_eval_report":          {},
+}
+
+// BreakGlassRequest is an emergency request to publish despite failing readiness gates.
+type BreakGlassRequest struct {
+	Operator string
+	Token    string
+	Reason   string
+	Gates    []string
+}
+
+// BreakGlassRecord is surfaced in the release manifest of a break-glass publish.
+type BreakGlassRecord struct {
+	Operator           string   `json:"operator"`
+	Reason             string   `json:"reason"`
+	BypassedGates      []string `json:"bypassed_gates"`
+	BypassedViolations []string `json:"bypassed_violations"`
+	AuditLogPath       string   `json:"audit_log_path"`
+	AuditSequence      int      `json:"audit_sequence"`
+	AuditEntryHash     string   `json:"audit_entry_hash"`
+}
+
+// BreakGlassAuditEntry is one link of the tamper-evident break-glass audit chain. EntryHash is
+// the sha256 of the entry encoded with an empty EntryHash, and PreviousHash links it to the
+// entry before it, so editing or removing any entry breaks every later link.
+type BreakGlassAuditEntry struct {
+	Sequence            int      `json:"sequence"`
+	TimestampUTC        string   `json:"timestamp_utc"`
+	Environment         string   `json:"environment,omitempty"`
+	ReleaseID           string   `json:"release_id"`
+	SpecRef             string   `json:"spec_ref"`
+	PipelineVersion     string   `json:"pipeline_version"`
+	Operator            string   `json:"operator"`
+	OperatorTokenSHA256 string   `json:"operator_token_sha256"`
+	Reason              string   `json:"reason"`
+	BypassedGates       []string `json:"bypassed_gates"`
+	BypassedViolations  []string `json:"bypassed_violations"`
+	PreviousHash        string   `json:"previous_hash"`
+	EntryHash           string   `json:"entry_hash"`
+}
+
+// BreakGlassRequestFromEnv reads a break-glass request; ok is false when none was made.
+func BreakGlassRequestFromEnv() (BreakGlassRequest, bool) {
+	req := BreakGlassRequest{
+		Operator: strings.TrimSpace(os.Getenv(EnvBreakGlassOperator)),
+		Token:    strings.TrimSpace(os.Getenv(EnvBreakGlassToken)),
+		Reason:   strings.TrimSpace(os.Getenv(EnvBreakGlassReason)),
+	}
+	for _, gate := range strings.Split(os.Getenv(EnvBreakGlassGates), ",") {
+		if gate = strings.TrimSpace(gate); gate != "" {
+			req.Gates = append(req.Gates, gate)
+		}
+	}
+	if req.Operator == "" && req.Token == "" && req.Reason == "" && len(req.Gates) == 0 {
+		return BreakGlassRequest{}, false
+	}
+	return req, true
+}
+
+// ParseBreakGlassOperators parses operator=<sha256 hex token digest> pairs separated by commas.
+func ParseBreakGlassOperators(raw string) (map[string]string, error) {
+	operators := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		operator, digest, ok := strings.Cut(pair, "=")
+		operator = strings.TrimSpace(operator)
+		digest = strings.ToLower(strings.TrimSpace(digest))
+		if !ok || operator == "" {
+			return nil, fmt.Errorf("%s entry %q must be operator=<sha256>", EnvBreakGlassOperators, pair)
+		}
+		if decoded, err := hex.DecodeString(digest); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("%s entry for %s must carry a sha256 hex token digest", EnvBreakGlassOperators, operator)
+		}
+		if _, dup := operators[operator]; dup {
+			return nil, fmt.Errorf("%s lists operator %s more than once", EnvBreakGlassOperators, operator)
+		}
+		operators[operator] = digest
+	}
+	return operators, nil
+}
+
+// AuthorizeBreakGlass verifies the request against the registered operators and returns the
+// operator's registered token digest.
+func AuthorizeBreakGlass(req BreakGlassRequest, operators map[string]string) (string, error) {
+	if req.Operator == "" {
+		return "", fmt.Errorf("break-glass operator is required")
+	}
+	if len(strings.TrimSpace(req.Reason)) < minBreakGlassReasonLen {
+		return "", fmt.Errorf("break-glass reason must be at least %d characters", minBreakGlassReasonLen)
+	}
+	if len(req.Gates) == 0 {
+		return "", fmt.Errorf("break-glass requires the gates to bypass")
+	}
+	for _, gate := range req.Gates {
+		if _, ok := bypassableGates[gate]; !ok {
+			return "", fmt.Errorf("break-glass cannot bypass gate %q", gate)
+		}
+	}
+	registered, ok := operators[req.Operator]
+	if !ok {
+		return "", fmt.Errorf("operator %s is not authorized for break-glass", req.Operator)
+	}
+	presented := sha256Hex([]byte(req.Token))
+	if req.Token == "" || subtle.ConstantTimeCompare([]byte(presented), []byte(registered)) != 1 {
+		return "", fmt.Errorf("break-glass token does not match operator %s", req.Operator)
+	}
+	return registered, nil
+}
+
+// ApplyBreakGlass marks the requested failing checks as bypassed and returns the readiness
+// result the manifest is built from. Every requested gate must actually be failing, so a
+// break-glass request never silently widens beyond what the emergency needs.
+func ApplyBreakGlass(readiness ReadinessResult, req BreakGlassRequest) (ReadinessResult, BreakGlassRecord, error) {
+	requested := make(map[string]struct{}, len(req.Gates))
+	for _, gate := range req.Gates {
+		requested[gate] = struct{}{}
+	}
+	out := readiness
+	out.Checks = append([]GateStatus(nil), readiness.Checks...)
+	out.Violations = nil
+	out.Passed = true
+	record := BreakGlassRecord{Operator: req.Operator, Reason: strings.TrimSpace(req.Reason)}
+	for i, check := range out.Checks {
+		if check.Passed {
+			if _, ok := requested[check.Name]; ok {
+				return ReadinessResult{}, BreakGlassRecord{}, fmt.Errorf("break-glass gate %s is passing; nothing to bypass", check.Name)
+			}
+			continue
+		}
+		violation := fmt.Sprintf("%s: %s", check.Name, check.Reason)
+		if _, ok := requested[check.Name]; !ok {
+			out.Passed = false
+			out.Violations = append(out.Violations, violation)
+			continue
+		}
+		out.Checks[i].BypassedByBreakGlass = true
+		record.BypassedGates = append(record.BypassedGates, check.Name)
+		record.BypassedViolations = append(record.BypassedViolations, violation)
+		delete(requested, check.Name)
+	}
+	if len(requested) > 0 {
+		missing := make([]string, 0, len(requested))
+		for gate := range requested {
+			missing = append(missing, gate)
+		}
+		sort.Strings(missing)
+		return ReadinessResult{}, BreakGlassRecord{}, fmt.Errorf("break-glass gates %v were not evaluated", missing)
+	}
+	return out, record, nil
+}
+
+// AppendBreakGlassAudit verifies the existing chain at path, links entry to its last link, and
+// appends it durably. The returned entry carries its sequence and hashes.
+func AppendBreakGlassAudit(path string, entry BreakGlassAuditEntry, now time.Time) (BreakGlassAuditEntry, error) {
+	trimmed := strings.TrimSpace(path)
+	if trimmed == "" {
+		return BreakGlassAuditEntry{}, fmt.Errorf("break-glass audit log path is required")
+	}
+	existing, err := VerifyBreakGlassAuditChain(trimmed)
+	if err != nil && !os.IsNotExist(err) {
+		return BreakGlassAuditEntry{}, err
+	}
+	entry.Sequence = len(existing) + 1
+	entry.PreviousHash = BreakGlassGenesisHash
+	if len(existing) > 0 {
+		entry.PreviousHash = existing[len(existing)-1].EntryHash
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+	entry.TimestampUTC = now.UTC().Format(time.RFC3339)
+	entry.EntryHash = ""
+	hash, err := breakGlassEntryHash(entry)
+	if err != nil {
+		return BreakGlassAuditEntry{}, err
+	}
+	entry.EntryHash = hash
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return BreakGlassAuditEntry{}, fmt.Errorf("encode break-glass audit entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(trimmed), 0o755); err != nil {
+		return BreakGlassAuditEntry{}, fmt.Errorf("create break-glass audit directory: %w", err)
+	}
+	f, err := os.OpenFile(trimmed, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return BreakGlassAuditEntry{}, fmt.Errorf("open break-glass audit log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return BreakGlassAuditEntry{}, fmt.Errorf("append break-glass audit entry: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return BreakGlassAuditEntry{}, fmt.Errorf("sync break-glass audit log: %w", err)
+	}
+	return entry, nil
+}
+
+// VerifyBreakGlassAuditChain reads the audit log at path and verifies every link. A missing
+// log returns an os.IsNotExist error.
+func VerifyBreakGlassAuditChain(path string) ([]BreakGlassAuditEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := make([]BreakGlassAuditEntry, 0)
+	previous := BreakGlassGenesisHash
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		entry := BreakGlassAuditEntry{}
+		if err := strictUnmarshal(raw, &entry); err != nil {
+			return nil, fmt.Errorf("break-glass audit log %s line %d: %w", path, line, err)
+		}
+		if entry.Sequence != len(entries)+1 {
+			return nil, fmt.Errorf("break-glass audit log %s line %d: sequence %d, expected %d", path, line, entry.Sequence, len(entries)+1)
+		}
+		if entry.PreviousHash != previous {
+			return nil, fmt.Errorf("break-glass audit log %s line %d: chain broken before sequence %d", path, line, entry.Sequence)
+		}
+		recorded := entry.EntryHash
+		entry.EntryHash = ""
+		hash, err := breakGlassEntryHash(entry)
+		if err != nil {
+			return nil, err
+		}
+		if recorded != hash {
+			return nil, fmt.Errorf("break-glass audit log %s line %d: entry hash mismatch at sequence %d", path, line, entry.Sequence)
+		}
+		entry.EntryHash = recorded
+		previous = recorded
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read break-glass audit log %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+func breakGlassEntryHash(entry BreakGlassAuditEntry) (string, error) {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return "", fmt.Errorf("encode break-glass audit entry: %w", err)
+	}
+	return sha256Hex(raw), nil
+}
@@ -0,0 +1,130 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuthorizeBreakGlassRequiresRegisteredOperatorToken(t *testing.T) {
+	t.Parallel()
+
+	operators, err := ParseBreakGlassOperators("alice=" + sha256Hex([]byte("alice-token")) + ", bob=" + strings.ToUpper(sha256Hex([]byte("bob-token"))))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	valid := BreakGlassRequest{Operator: "alice", Token: "alice-token", Reason: "SEV1 customer outage hotfix", Gates: []string{"slo_gates_report"}}
+	digest, err := AuthorizeBreakGlass(valid, operators)
+	if err != nil || digest != sha256Hex([]byte("alice-token")) {
+		t.Fatalf("expected alice to be authorized, got %q err=%v", digest, err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*BreakGlassRequest)
+	}{
+		{name: "impersonated operator", mutate: func(r *BreakGlassRequest) { r.Operator = "bob" }},
+		{name: "unregistered operator", mutate: func(r *BreakGlassRequest) { r.Operator = "mallory" }},
+		{name: "missing token", mutate: func(r *BreakGlassRequest) { r.Token = "" }},
+		{name: "short reason", mutate: func(r *BreakGlassRequest) { r.Reason = "urgent" }},
+		{name: "no gates", mutate: func(r *BreakGlassRequest) { r.Gates = nil }},
+		{name: "unbypassable gate", mutate: func(r *BreakGlassRequest) { r.Gates = []string{"rollout_config"} }},
+	}
+	for _, tc := range tests {
+		req := valid
+		tc.mutate(&req)
+		if _, err := AuthorizeBreakGlass(req, operators); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+
+	for _, raw := range []string{"alice", "alice=deadbeef", "=" + sha256Hex([]byte("x")), "alice=" + sha256Hex([]byte("a")) + ",alice=" + sha256Hex([]byte("b"))} {
+		if _, err := ParseBreakGlassOperators(raw); err == nil {
+			t.Fatalf("expected operator list %q to be rejected", raw)
+		}
+	}
+}
+
+func TestApplyBreakGlassBypassesOnlyRequestedFailingGates(t *testing.T) {
+	t.Parallel()
+
+	readiness := ReadinessResult{
+		Checks: []GateStatus{
+			{Name: "contracts_report", Passed: true},
+			{Name: "replay_regression_report", Reason: "replay regression report has failing_count=2"},
+			{Name: "slo_gates_report", Reason: "artifact is stale (older than 24h0m0s)"},
+		},
+		Violations: []string{"replay_regression_report: replay regression report has failing_count=2", "slo_gates_report: artifact is stale (older than 24h0m0s)"},
+	}
+
+	partial, record, err := ApplyBreakGlass(readiness, BreakGlassRequest{Operator: "alice", Reason: "SEV1 outage", Gates: []string{"slo_gates_report"}})
+	if err != nil {
+		t.Fatalf("unexpected apply error: %v", err)
+	}
+	if partial.Passed || len(partial.Violations) != 1 || !strings.HasPrefix(partial.Violations[0], "replay_regression_report") {
+		t.Fatalf("expected unrequested failing gate to keep readiness failing, got %+v", partial)
+	}
+	if !partial.Checks[2].BypassedByBreakGlass || readiness.Checks[2].BypassedByBreakGlass {
+		t.Fatalf("expected bypass marked on a copy of the checks, got %+v", partial.Checks)
+	}
+	if strings.Join(record.BypassedGates, ",") != "slo_gates_report" || len(record.BypassedViolations) != 1 {
+		t.Fatalf("unexpected break-glass record %+v", record)
+	}
+
+	full, _, err := ApplyBreakGlass(readiness, BreakGlassRequest{Gates: []string{"slo_gates_report", "replay_regression_report"}})
+	if err != nil || !full.Passed || full.Violations != nil {
+		t.Fatalf("expected readiness to pass once every failing gate is bypassed, got %+v err=%v", full, err)
+	}
+	if _, _, err := ApplyBreakGlass(readiness, BreakGlassRequest{Gates: []string{"contracts_report"}}); err == nil {
+		t.Fatalf("expected bypassing a passing gate to fail")
+	}
+	if _, _, err := ApplyBreakGlass(readiness, BreakGlassRequest{Gates: []string{"llm_eval_report"}}); err == nil {
+		t.Fatalf("expected bypassing an unevaluated gate to fail")
+	}
+}
+
+func TestBreakGlassAuditChainDetectsTampering(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit", "break-glass.jsonl")
+	now := time.Date(2026, 2, 11, 12, 0, 0, 0, time.UTC)
+	first, err := AppendBreakGlassAudit(path, BreakGlassAuditEntry{ReleaseID: "rel-1", Operator: "alice", Reason: "SEV1 outage hotfix"}, now)
+	if err != nil {
+		t.Fatalf("unexpected append error: %v", err)
+	}
+	second, err := AppendBreakGlassAudit(path, BreakGlassAuditEntry{ReleaseID: "rel-2", Operator: "bob", Reason: "SEV2 follow-up"}, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected append error: %v", err)
+	}
+	if first.Sequence != 1 || first.PreviousHash != BreakGlassGenesisHash || second.Sequence != 2 || second.PreviousHash != first.EntryHash {
+		t.Fatalf("expected linked entries, got %+v then %+v", first, second)
+	}
+	entries, err := VerifyBreakGlassAuditChain(path)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected intact two-entry chain, got %d err=%v", len(entries), err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(string(raw), `"operator":"alice"`, `"operator":"mallory"`, 1)), 0o600); err != nil {
+		t.Fatalf("tamper audit log: %v", err)
+	}
+	if _, err := VerifyBreakGlassAuditChain(path); err == nil || !strings.Contains(err.Error(), "entry hash mismatch") {
+		t.Fatalf("expected tampered entry to be detected, got %v", err)
+	}
+	if _, err := AppendBreakGlassAudit(path, BreakGlassAuditEntry{ReleaseID: "rel-3"}, now); err == nil {
+		t.Fatalf("expected append onto a tampered chain to fail")
+	}
+
+	lines := strings.SplitAfter(string(raw), "\n")
+	if err := os.WriteFile(path, []byte(lines[1]), 0o600); err != nil {
+		t.Fatalf("truncate audit log: %v", err)
+	}
+	if _, err := VerifyBreakGlassAuditChain(path); err == nil {
+		t.Fatalf("expected a removed entry to break the chain")
+	}
+}
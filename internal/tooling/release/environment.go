@@ -0,0 +1,64 @@
+package release
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+)
+
+// EnvEnvironment selects the deployment environment that reports and manifests belong to; the
+// CP distribution resolves the same environment's active pipeline version.
+const EnvEnvironment = distribution.EnvEnvironment
+
+const (
+	EnvironmentDev     = "dev"
+	EnvironmentStaging = "staging"
+	EnvironmentProd    = "prod"
+)
+
+// ValidateEnvironment accepts dev|staging|prod, or empty for the legacy unscoped layout.
+func ValidateEnvironment(environment string) error {
+	switch environment {
+	case "", EnvironmentDev, EnvironmentStaging, EnvironmentProd:
+		return nil
+	default:
+		return fmt.Errorf("environment must be one of dev|staging|prod, got %q", environment)
+	}
+}
+
+// EnvironmentFromEnv reads and validates RSPP_ENVIRONMENT.
+func EnvironmentFromEnv() (string, error) {
+	environment := strings.ToLower(strings.TrimSpace(os.Getenv(EnvEnvironment)))
+	if err := ValidateEnvironment(environment); err != nil {
+		return "", fmt.Errorf("%s: %w", EnvEnvironment, err)
+	}
+	return environment, nil
+}
+
+// EnvironmentArtifactPath namespaces a default .codex artifact path by environment, so
+// .codex/ops/x.json becomes .codex/<environment>/ops/x.json. Paths outside .codex and the
+// empty environment are returned unchanged.
+func EnvironmentArtifactPath(environment string, path string) string {
+	if environment == "" {
+		return path
+	}
+	clean := filepath.ToSlash(filepath.Clean(path))
+	rest, ok := strings.CutPrefix(clean, ".codex/")
+	if !ok {
+		return path
+	}
+	if first, _, _ := strings.Cut(rest, "/"); first == environment {
+		return path
+	}
+	return filepath.FromSlash(".codex/" + environment + "/" + rest)
+}
+
+func checkArtifactEnvironment(artifactEnvironment string, required string) error {
+	if artifactEnvironment != required {
+		return fmt.Errorf("environment mismatch: artifact=%q required=%q", artifactEnvironment, required)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package release
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnvironmentArtifactPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		environment string
+		path        string
+		want        string
+	}{
+		{environment: "", path: ".codex/ops/slo-gates-report.json", want: ".codex/ops/slo-gates-report.json"},
+		{environment: "prod", path: ".codex/ops/slo-gates-report.json", want: filepath.FromSlash(".codex/prod/ops/slo-gates-report.json")},
+		{environment: "staging", path: ".codex/staging/ops/x.json", want: ".codex/staging/ops/x.json"},
+		{environment: "prod", path: "/tmp/custom.json", want: "/tmp/custom.json"},
+	}
+	for _, tc := range tests {
+		if got := EnvironmentArtifactPath(tc.environment, tc.path); got != tc.want {
+			t.Fatalf("expected %s/%s -> %s, got %s", tc.environment, tc.path, tc.want, got)
+		}
+	}
+	if err := ValidateEnvironment("qa"); err == nil {
+		t.Fatalf("expected unsupported environment to fail")
+	}
+}
+
+func TestEvaluateReadinessRejectsCrossEnvironmentEvidence(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 11, 4, 0, 0, 0, time.UTC)
+	tmp := t.TempDir()
+	contractsPath := filepath.Join(tmp, "contracts.json")
+	replayPath := filepath.Join(tmp, "replay.json")
+	sloPath := filepath.Join(tmp, "slo.json")
+	generatedAt := now.Add(-10 * time.Minute).Format(time.RFC3339)
+
+	mustWriteJSON(t, contractsPath, map[string]any{"environment": "prod", "generated_at_utc": generatedAt, "passed": true})
+	mustWriteJSON(t, replayPath, map[string]any{"environment": "prod", "generated_at_utc": generatedAt, "failing_count": 0})
+	mustWriteJSON(t, sloPath, map[string]any{"environment": "staging", "generated_at_utc": generatedAt, "report": map[string]any{"passed": true}})
+
+	input := ReadinessInput{
+		Environment:                "prod",
+		ContractsReportPath:        contractsPath,
+		ReplayRegressionReportPath: replayPath,
+		SLOGatesReportPath:         sloPath,
+		Now:                        now,
+	}
+	readiness, _ := EvaluateReadiness(input)
+	if readiness.Passed || len(readiness.Violations) != 1 || !strings.Contains(readiness.Violations[0], "environment mismatch") {
+		t.Fatalf("expected staging slo evidence to fail prod readiness, got %+v", readiness)
+	}
+
+	mustWriteJSON(t, sloPath, map[string]any{"environment": "prod", "generated_at_utc": generatedAt, "report": map[string]any{"passed": true}})
+	readiness, sources := EvaluateReadiness(input)
+	if !readiness.Passed || readiness.Environment != "prod" {
+		t.Fatalf("expected prod readiness pass, got %+v", readiness)
+	}
+
+	cfg := RolloutConfig{Environment: "staging", PipelineVersion: "pipeline-v2", Strategy: "canary", RollbackPosture: RollbackPosture{Mode: "manual", Trigger: "slo_failure"}}
+	if _, err := BuildReleaseManifest("specs/pipeline-v2.json", cfg, readiness, sources, now); err == nil {
+		t.Fatalf("expected staging rollout config to be rejected for prod readiness")
+	}
+	cfg.Environment = "prod"
+	manifest, err := BuildReleaseManifest("specs/pipeline-v2.json", cfg, readiness, sources, now)
+	if err != nil || manifest.Environment != "prod" {
+		t.Fatalf("expected prod manifest, got %+v err=%v", manifest, err)
+	}
+}
@@ -10,6 +10,9 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/artifactwriter"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/schemaregistry"
 )
 
 const (
@@ -22,6 +25,9 @@ const (
 
 var DefaultMaxArtifactAge = 24 * time.Hour
 
+// artifactSchemas upgrades N-1 gate and manifest artifacts before they are decoded.
+var artifactSchemas = schemaregistry.Default()
+
 var allowedRolloutStrategies = map[string]struct{}{
 	"immediate": {},
 	"phased":    {},
@@ -37,10 +43,14 @@ var allowedRollbackModes = map[string]struct{}{
 type RollbackPosture struct {
 	Mode    string `json:"mode"`
 	Trigger string `json:"trigger"`
+	// TargetPipelineVersion is the known-good version execute-rollback restores.
+	TargetPipelineVersion string `json:"target_pipeline_version,omitempty"`
 }
 
 // RolloutConfig captures release rollout intent for DX-04.
 type RolloutConfig struct {
+	// Environment optionally pins the rollout to one environment; readiness must match.
+	Environment     string          `json:"environment,omitempty"`
 	PipelineVersion string          `json:"pipeline_version"`
 	Strategy        string          `json:"strategy"`
 	RollbackPosture RollbackPosture `json:"rollback_posture"`
@@ -61,10 +71,14 @@ type GateStatus struct {
 	Reason         string `json:"reason,omitempty"`
 	GeneratedAtUTC string `json:"generated_at_utc,omitempty"`
 	AgeMS          int64  `json:"age_ms,omitempty"`
+
+	// BypassedByBreakGlass marks a failing check an authorized operator bypassed.
+	BypassedByBreakGlass bool `json:"bypassed_by_break_glass,omitempty"`
 }
 
 // ReadinessResult captures release readiness gate status.
 type ReadinessResult struct {
+	Environment      string       `json:"environment,omitempty"`
 	Passed           bool         `json:"passed"`
 	MaxArtifactAgeMS int64        `json:"max_artifact_age_ms"`
 	Checks           []GateStatus `json:"checks"`
@@ -72,35 +86,58 @@ type ReadinessResult struct {
 }
 
 // ReadinessInput defines artifacts used by artifact-based release readiness checks.
+// LLMEvalReportPath is optional; when set, the LLM answer-quality eval report is gated too.
+// Every gate artifact must carry the same Environment so evidence cannot cross environments.
 type ReadinessInput struct {
+	Environment                string
 	ContractsReportPath        string
 	ReplayRegressionReportPath string
 	SLOGatesReportPath         string
+	LLMEvalReportPath          string
 	MaxArtifactAge             time.Duration
 	Now                        time.Time
 }
 
 // ReleaseManifest captures deterministic release publish output for deployment handoff.
 type ReleaseManifest struct {
+	SchemaVersion   string                    `json:"schema_version"`
 	ReleaseID       string                    `json:"release_id"`
+	Environment     string                    `json:"environment,omitempty"`
 	GeneratedAtUTC  string                    `json:"generated_at_utc"`
 	SpecRef         string                    `json:"spec_ref"`
+	SpecHash        string                    `json:"spec_hash,omitempty"`
 	RolloutConfig   RolloutConfig             `json:"rollout_config"`
 	Readiness       ReadinessResult           `json:"readiness"`
 	SourceArtifacts map[string]ArtifactSource `json:"source_artifacts"`
+
+	// BreakGlass is set when the release was published with readiness gates bypassed.
+	BreakGlass *BreakGlassRecord `json:"break_glass,omitempty"`
 }
 
 type contractsReportArtifact struct {
+	Environment    string `json:"environment,omitempty"`
 	GeneratedAtUTC string `json:"generated_at_utc"`
 	Passed         bool   `json:"passed"`
 }
 
 type replayRegressionArtifact struct {
+	SchemaVersion  string `json:"schema_version"`
+	Environment    string `json:"environment,omitempty"`
 	GeneratedAtUTC string `json:"generated_at_utc"`
 	FailingCount   int    `json:"failing_count"`
 }
 
+type llmEvalReportArtifact struct {
+	Environment    string          `json:"environment,omitempty"`
+	GeneratedAtUTC string          `json:"generated_at_utc"`
+	SuitePath      string          `json:"suite_path"`
+	Report         json.RawMessage `json:"report"`
+	Passed         bool            `json:"passed"`
+}
+
 type sloGatesReportArtifact struct {
+	SchemaVersion  string `json:"schema_version"`
+	Environment    string `json:"environment,omitempty"`
 	GeneratedAtUTC string `json:"generated_at_utc"`
 	Report         struct {
 		Passed bool `json:"passed"`
@@ -136,7 +173,11 @@ func ValidateRolloutConfig(cfg RolloutConfig) error {
 	cfg.Strategy = strings.ToLower(strings.TrimSpace(cfg.Strategy))
 	cfg.RollbackPosture.Mode = strings.ToLower(strings.TrimSpace(cfg.RollbackPosture.Mode))
 	cfg.RollbackPosture.Trigger = strings.TrimSpace(cfg.RollbackPosture.Trigger)
+	cfg.RollbackPosture.TargetPipelineVersion = strings.TrimSpace(cfg.RollbackPosture.TargetPipelineVersion)
 
+	if err := ValidateEnvironment(cfg.Environment); err != nil {
+		return fmt.Errorf("rollout config %w", err)
+	}
 	if cfg.PipelineVersion == "" {
 		return fmt.Errorf("rollout config pipeline_version is required")
 	}
@@ -149,6 +190,9 @@ func ValidateRolloutConfig(cfg RolloutConfig) error {
 	if cfg.RollbackPosture.Trigger == "" {
 		return fmt.Errorf("rollout config rollback_posture.trigger is required")
 	}
+	if cfg.RollbackPosture.TargetPipelineVersion == cfg.PipelineVersion {
+		return fmt.Errorf("rollout config rollback_posture.target_pipeline_version must differ from pipeline_version")
+	}
 	return nil
 }
 
@@ -156,6 +200,7 @@ func ValidateRolloutConfig(cfg RolloutConfig) error {
 func EvaluateReadiness(in ReadinessInput) (ReadinessResult, map[string]ArtifactSource) {
 	in = normalizeReadinessInput(in)
 	result := ReadinessResult{
+		Environment:      in.Environment,
 		Passed:           true,
 		MaxArtifactAgeMS: in.MaxArtifactAge.Milliseconds(),
 		Checks:           make([]GateStatus, 0, 3),
@@ -163,7 +208,7 @@ func EvaluateReadiness(in ReadinessInput) (ReadinessResult, map[string]ArtifactS
 	}
 	sources := make(map[string]ArtifactSource, 3)
 
-	contractsStatus, contractsSource := evaluateContractsCheck(in.ContractsReportPath, in.Now, in.MaxArtifactAge)
+	contractsStatus, contractsSource := evaluateContractsCheck(in.Environment, in.ContractsReportPath, in.Now, in.MaxArtifactAge)
 	result.Checks = append(result.Checks, contractsStatus)
 	if contractsStatus.Passed {
 		sources["contracts_report"] = contractsSource
@@ -172,7 +217,7 @@ func EvaluateReadiness(in ReadinessInput) (ReadinessResult, map[string]ArtifactS
 		result.Violations = append(result.Violations, fmt.Sprintf("contracts_report: %s", contractsStatus.Reason))
 	}
 
-	replayStatus, replaySource := evaluateReplayRegressionCheck(in.ReplayRegressionReportPath, in.Now, in.MaxArtifactAge)
+	replayStatus, replaySource := evaluateReplayRegressionCheck(in.Environment, in.ReplayRegressionReportPath, in.Now, in.MaxArtifactAge)
 	result.Checks = append(result.Checks, replayStatus)
 	if replayStatus.Passed {
 		sources["replay_regression_report"] = replaySource
@@ -181,7 +226,7 @@ func EvaluateReadiness(in ReadinessInput) (ReadinessResult, map[string]ArtifactS
 		result.Violations = append(result.Violations, fmt.Sprintf("replay_regression_report: %s", replayStatus.Reason))
 	}
 
-	sloStatus, sloSource := evaluateSLOGatesCheck(in.SLOGatesReportPath, in.Now, in.MaxArtifactAge)
+	sloStatus, sloSource := evaluateSLOGatesCheck(in.Environment, in.SLOGatesReportPath, in.Now, in.MaxArtifactAge)
 	result.Checks = append(result.Checks, sloStatus)
 	if sloStatus.Passed {
 		sources["slo_gates_report"] = sloSource
@@ -190,6 +235,17 @@ func EvaluateReadiness(in ReadinessInput) (ReadinessResult, map[string]ArtifactS
 		result.Violations = append(result.Violations, fmt.Sprintf("slo_gates_report: %s", sloStatus.Reason))
 	}
 
+	if strings.TrimSpace(in.LLMEvalReportPath) != "" {
+		evalStatus, evalSource := evaluateLLMEvalCheck(in.Environment, in.LLMEvalReportPath, in.Now, in.MaxArtifactAge)
+		result.Checks = append(result.Checks, evalStatus)
+		if evalStatus.Passed {
+			sources["llm_eval_report"] = evalSource
+		} else {
+			result.Passed = false
+			result.Violations = append(result.Violations, fmt.Sprintf("llm_eval_report: %s", evalStatus.Reason))
+		}
+	}
+
 	if len(result.Violations) == 0 {
 		result.Violations = nil
 	}
@@ -214,6 +270,9 @@ func BuildReleaseManifest(
 	if !readiness.Passed {
 		return ReleaseManifest{}, fmt.Errorf("release readiness failed: %v", readiness.Violations)
 	}
+	if cfg.Environment != "" && cfg.Environment != readiness.Environment {
+		return ReleaseManifest{}, fmt.Errorf("rollout config environment %q does not match readiness environment %q", cfg.Environment, readiness.Environment)
+	}
 	if now.IsZero() {
 		now = time.Now().UTC()
 	} else {
@@ -227,6 +286,9 @@ func BuildReleaseManifest(
 		cfg.RollbackPosture.Mode,
 		cfg.RollbackPosture.Trigger,
 	}, "|")
+	if readiness.Environment != "" {
+		seed = readiness.Environment + "|" + seed
+	}
 	releaseID := fmt.Sprintf("rel-%s-%s", now.Format("20060102150405"), shortHash(seed, 10))
 
 	normalizedSources := make(map[string]ArtifactSource, len(sources))
@@ -241,8 +303,14 @@ func BuildReleaseManifest(
 		normalizedSources[k] = source
 	}
 
+	schemaVersion, err := artifactSchemas.CurrentVersion(schemaregistry.KindReleaseManifest)
+	if err != nil {
+		return ReleaseManifest{}, err
+	}
 	return ReleaseManifest{
+		SchemaVersion:   schemaVersion,
 		ReleaseID:       releaseID,
+		Environment:     readiness.Environment,
 		GeneratedAtUTC:  now.Format(time.RFC3339),
 		SpecRef:         trimmedSpecRef,
 		RolloutConfig:   cfg,
@@ -252,14 +320,15 @@ func BuildReleaseManifest(
 }
 
 func normalizeReadinessInput(in ReadinessInput) ReadinessInput {
+	in.Environment = strings.ToLower(strings.TrimSpace(in.Environment))
 	if strings.TrimSpace(in.ContractsReportPath) == "" {
-		in.ContractsReportPath = DefaultContractsReportPath
+		in.ContractsReportPath = EnvironmentArtifactPath(in.Environment, DefaultContractsReportPath)
 	}
 	if strings.TrimSpace(in.ReplayRegressionReportPath) == "" {
-		in.ReplayRegressionReportPath = DefaultReplayRegressionReportPath
+		in.ReplayRegressionReportPath = EnvironmentArtifactPath(in.Environment, DefaultReplayRegressionReportPath)
 	}
 	if strings.TrimSpace(in.SLOGatesReportPath) == "" {
-		in.SLOGatesReportPath = DefaultSLOGatesReportPath
+		in.SLOGatesReportPath = EnvironmentArtifactPath(in.Environment, DefaultSLOGatesReportPath)
 	}
 	if in.Now.IsZero() {
 		in.Now = time.Now().UTC()
@@ -272,7 +341,7 @@ func normalizeReadinessInput(in ReadinessInput) ReadinessInput {
 	return in
 }
 
-func evaluateContractsCheck(path string, now time.Time, maxAge time.Duration) (GateStatus, ArtifactSource) {
+func evaluateContractsCheck(environment string, path string, now time.Time, maxAge time.Duration) (GateStatus, ArtifactSource) {
 	status := GateStatus{Name: "contracts_report", Path: path, Passed: false}
 	raw, source, err := readArtifact(path)
 	if err != nil {
@@ -286,6 +355,10 @@ func evaluateContractsCheck(path string, now time.Time, maxAge time.Duration) (G
 		return status, ArtifactSource{}
 	}
 	status.GeneratedAtUTC = artifact.GeneratedAtUTC
+	if err := checkArtifactEnvironment(artifact.Environment, environment); err != nil {
+		status.Reason = err.Error()
+		return status, ArtifactSource{}
+	}
 	generatedAt, freshnessErr := validateFreshness(artifact.GeneratedAtUTC, now, maxAge)
 	if freshnessErr != nil {
 		status.Reason = freshnessErr.Error()
@@ -302,7 +375,7 @@ func evaluateContractsCheck(path string, now time.Time, maxAge time.Duration) (G
 	return status, source
 }
 
-func evaluateReplayRegressionCheck(path string, now time.Time, maxAge time.Duration) (GateStatus, ArtifactSource) {
+func evaluateReplayRegressionCheck(environment string, path string, now time.Time, maxAge time.Duration) (GateStatus, ArtifactSource) {
 	status := GateStatus{Name: "replay_regression_report", Path: path, Passed: false}
 	raw, source, err := readArtifact(path)
 	if err != nil {
@@ -310,12 +383,21 @@ func evaluateReplayRegressionCheck(path string, now time.Time, maxAge time.Durat
 		return status, ArtifactSource{}
 	}
 
+	upgraded, err := artifactSchemas.Upgrade(schemaregistry.KindReplayRegressionReport, raw)
+	if err != nil {
+		status.Reason = err.Error()
+		return status, ArtifactSource{}
+	}
 	artifact := replayRegressionArtifact{}
-	if err := strictUnmarshal(raw, &artifact); err != nil {
+	if err := strictUnmarshal(upgraded.Raw, &artifact); err != nil {
 		status.Reason = fmt.Sprintf("decode replay regression report: %v", err)
 		return status, ArtifactSource{}
 	}
 	status.GeneratedAtUTC = artifact.GeneratedAtUTC
+	if err := checkArtifactEnvironment(artifact.Environment, environment); err != nil {
+		status.Reason = err.Error()
+		return status, ArtifactSource{}
+	}
 	generatedAt, freshnessErr := validateFreshness(artifact.GeneratedAtUTC, now, maxAge)
 	if freshnessErr != nil {
 		status.Reason = freshnessErr.Error()
@@ -332,7 +414,7 @@ func evaluateReplayRegressionCheck(path string, now time.Time, maxAge time.Durat
 	return status, source
 }
 
-func evaluateSLOGatesCheck(path string, now time.Time, maxAge time.Duration) (GateStatus, ArtifactSource) {
+func evaluateSLOGatesCheck(environment string, path string, now time.Time, maxAge time.Duration) (GateStatus, ArtifactSource) {
 	status := GateStatus{Name: "slo_gates_report", Path: path, Passed: false}
 	raw, source, err := readArtifact(path)
 	if err != nil {
@@ -340,12 +422,21 @@ func evaluateSLOGatesCheck(path string, now time.Time, maxAge time.Duration) (Ga
 		return status, ArtifactSource{}
 	}
 
+	upgraded, err := artifactSchemas.Upgrade(schemaregistry.KindSLOGatesReport, raw)
+	if err != nil {
+		status.Reason = err.Error()
+		return status, ArtifactSource{}
+	}
 	artifact := sloGatesReportArtifact{}
-	if err := strictUnmarshal(raw, &artifact); err != nil {
+	if err := strictUnmarshal(upgraded.Raw, &artifact); err != nil {
 		status.Reason = fmt.Sprintf("decode slo gates report: %v", err)
 		return status, ArtifactSource{}
 	}
 	status.GeneratedAtUTC = artifact.GeneratedAtUTC
+	if err := checkArtifactEnvironment(artifact.Environment, environment); err != nil {
+		status.Reason = err.Error()
+		return status, ArtifactSource{}
+	}
 	generatedAt, freshnessErr := validateFreshness(artifact.GeneratedAtUTC, now, maxAge)
 	if freshnessErr != nil {
 		status.Reason = freshnessErr.Error()
@@ -362,6 +453,40 @@ func evaluateSLOGatesCheck(path string, now time.Time, maxAge time.Duration) (Ga
 	return status, source
 }
 
+func evaluateLLMEvalCheck(environment string, path string, now time.Time, maxAge time.Duration) (GateStatus, ArtifactSource) {
+	status := GateStatus{Name: "llm_eval_report", Path: path, Passed: false}
+	raw, source, err := readArtifact(path)
+	if err != nil {
+		status.Reason = err.Error()
+		return status, ArtifactSource{}
+	}
+
+	artifact := llmEvalReportArtifact{}
+	if err := strictUnmarshal(raw, &artifact); err != nil {
+		status.Reason = fmt.Sprintf("decode llm eval report: %v", err)
+		return status, ArtifactSource{}
+	}
+	status.GeneratedAtUTC = artifact.GeneratedAtUTC
+	if err := checkArtifactEnvironment(artifact.Environment, environment); err != nil {
+		status.Reason = err.Error()
+		return status, ArtifactSource{}
+	}
+	generatedAt, freshnessErr := validateFreshness(artifact.GeneratedAtUTC, now, maxAge)
+	if freshnessErr != nil {
+		status.Reason = freshnessErr.Error()
+		return status, ArtifactSource{}
+	}
+	status.AgeMS = now.Sub(generatedAt).Milliseconds()
+	if !artifact.Passed {
+		status.Reason = "llm eval report indicates failure"
+		return status, ArtifactSource{}
+	}
+
+	status.Passed = true
+	source.GeneratedAtUTC = artifact.GeneratedAtUTC
+	return status, source
+}
+
 func readArtifact(path string) ([]byte, ArtifactSource, error) {
 	trimmed := strings.TrimSpace(path)
 	if trimmed == "" {
@@ -371,6 +496,9 @@ func readArtifact(path string) ([]byte, ArtifactSource, error) {
 	if err != nil {
 		return nil, ArtifactSource{}, fmt.Errorf("read artifact %s: %w", trimmed, err)
 	}
+	if err := artifactwriter.CheckIndexed(trimmed, raw); err != nil {
+		return nil, ArtifactSource{}, err
+	}
 	return raw, ArtifactSource{Path: trimmed, SHA256: sha256Hex(raw)}, nil
 }
 
@@ -2,11 +2,14 @@ package release
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/artifactwriter"
 )
 
 func TestLoadRolloutConfigAndValidate(t *testing.T) {
@@ -136,6 +139,134 @@ func TestEvaluateReadinessFailClosed(t *testing.T) {
 	}
 }
 
+func TestEvaluateReadinessReadsVersionedGateArtifacts(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 11, 4, 0, 0, 0, time.UTC)
+	generatedAt := now.Add(-10 * time.Minute).Format(time.RFC3339)
+	tmp := t.TempDir()
+	contractsPath := filepath.Join(tmp, "contracts.json")
+	mustWriteJSON(t, contractsPath, map[string]any{"generated_at_utc": generatedAt, "passed": true})
+
+	tests := []struct {
+		name          string
+		replayVersion string
+		sloVersion    string
+		wantPassed    bool
+	}{
+		{name: "current versions", replayVersion: "replay-regression-report/v2", sloVersion: "slo-gates-report/v2", wantPassed: true},
+		{name: "legacy unversioned", wantPassed: true},
+		{name: "newer slo schema", replayVersion: "replay-regression-report/v2", sloVersion: "slo-gates-report/v3"},
+	}
+	for i, tc := range tests {
+		replayPath := filepath.Join(tmp, fmt.Sprintf("replay-%d.json", i))
+		sloPath := filepath.Join(tmp, fmt.Sprintf("slo-%d.json", i))
+		replay := map[string]any{"generated_at_utc": generatedAt, "failing_count": 0}
+		slo := map[string]any{"generated_at_utc": generatedAt, "report": map[string]any{"passed": true}}
+		if tc.replayVersion != "" {
+			replay["schema_version"] = tc.replayVersion
+		}
+		if tc.sloVersion != "" {
+			slo["schema_version"] = tc.sloVersion
+		}
+		mustWriteJSON(t, replayPath, replay)
+		mustWriteJSON(t, sloPath, slo)
+
+		readiness, _ := EvaluateReadiness(ReadinessInput{
+			ContractsReportPath:        contractsPath,
+			ReplayRegressionReportPath: replayPath,
+			SLOGatesReportPath:         sloPath,
+			Now:                        now,
+		})
+		if readiness.Passed != tc.wantPassed {
+			t.Fatalf("%s: expected passed=%t, got %+v", tc.name, tc.wantPassed, readiness)
+		}
+	}
+}
+
+func TestEvaluateReadinessGatesOptionalLLMEvalReport(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 11, 4, 0, 0, 0, time.UTC)
+	tmp := t.TempDir()
+
+	contractsPath := filepath.Join(tmp, "contracts.json")
+	replayPath := filepath.Join(tmp, "replay.json")
+	sloPath := filepath.Join(tmp, "slo.json")
+	evalPath := filepath.Join(tmp, "llm-eval.json")
+
+	mustWriteJSON(t, contractsPath, map[string]any{
+		"generated_at_utc": now.Add(-1 * time.Hour).Format(time.RFC3339),
+		"passed":           true,
+	})
+	mustWriteJSON(t, replayPath, map[string]any{
+		"generated_at_utc": now.Add(-30 * time.Minute).Format(time.RFC3339),
+		"failing_count":    0,
+	})
+	mustWriteJSON(t, sloPath, map[string]any{
+		"generated_at_utc": now.Add(-10 * time.Minute).Format(time.RFC3339),
+		"report": map[string]any{
+			"passed": true,
+		},
+	})
+	mustWriteJSON(t, evalPath, map[string]any{
+		"generated_at_utc": now.Add(-5 * time.Minute).Format(time.RFC3339),
+		"suite_path":       "test/quality/fixtures/llm_eval_suite.json",
+		"report": map[string]any{
+			"providers": []any{},
+			"passed":    false,
+		},
+		"passed": false,
+	})
+
+	readiness, sources := EvaluateReadiness(ReadinessInput{
+		ContractsReportPath:        contractsPath,
+		ReplayRegressionReportPath: replayPath,
+		SLOGatesReportPath:         sloPath,
+		LLMEvalReportPath:          evalPath,
+		MaxArtifactAge:             24 * time.Hour,
+		Now:                        now,
+	})
+	if readiness.Passed || len(readiness.Checks) != 4 {
+		t.Fatalf("expected failing llm eval check to fail readiness, got %+v", readiness)
+	}
+	if len(readiness.Violations) != 1 || len(sources) != 3 {
+		t.Fatalf("expected only llm eval violation, got violations=%+v sources=%d", readiness.Violations, len(sources))
+	}
+}
+
+func TestEvaluateReadinessRejectsArtifactsModifiedOutsideTooling(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 11, 4, 0, 0, 0, time.UTC)
+	generatedAt := now.Add(-10 * time.Minute).Format(time.RFC3339)
+	opsDir := filepath.Join(t.TempDir(), ".codex", "ops")
+	contractsPath := filepath.Join(opsDir, "contracts-report.json")
+	replayPath := filepath.Join(opsDir, "regression-report.json")
+	sloPath := filepath.Join(opsDir, "slo-gates-report.json")
+	for path, payload := range map[string]any{
+		contractsPath: map[string]any{"generated_at_utc": generatedAt, "passed": true},
+		replayPath:    map[string]any{"generated_at_utc": generatedAt, "failing_count": 0},
+		sloPath:       map[string]any{"generated_at_utc": generatedAt, "report": map[string]any{"passed": false}},
+	} {
+		if err := artifactwriter.WriteJSON(path, payload); err != nil {
+			t.Fatalf("unexpected artifact write error: %v", err)
+		}
+	}
+	// Flip the failing SLO report to passing by hand, bypassing the index.
+	mustWriteJSON(t, sloPath, map[string]any{"generated_at_utc": generatedAt, "report": map[string]any{"passed": true}})
+
+	readiness, _ := EvaluateReadiness(ReadinessInput{
+		ContractsReportPath:        contractsPath,
+		ReplayRegressionReportPath: replayPath,
+		SLOGatesReportPath:         sloPath,
+		Now:                        now,
+	})
+	if readiness.Passed || len(readiness.Violations) != 1 || !strings.Contains(readiness.Violations[0], "modified outside the tooling") {
+		t.Fatalf("expected only the hand-edited slo report to fail, got %+v", readiness)
+	}
+}
+
 func TestBuildReleaseManifest(t *testing.T) {
 	t.Parallel()
 
@@ -157,7 +288,7 @@ func TestBuildReleaseManifest(t *testing.T) {
 	if !strings.HasPrefix(manifest.ReleaseID, "rel-20260211040506-") {
 		t.Fatalf("unexpected release id: %s", manifest.ReleaseID)
 	}
-	if manifest.SpecRef != "specs/pipeline-v2.json" || manifest.RolloutConfig.PipelineVersion != "pipeline-v2" {
+	if manifest.SchemaVersion != "release-manifest/v2" || manifest.SpecRef != "specs/pipeline-v2.json" || manifest.RolloutConfig.PipelineVersion != "pipeline-v2" {
 		t.Fatalf("unexpected manifest payload: %+v", manifest)
 	}
 
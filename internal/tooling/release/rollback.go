@@ -0,0 +1,188 @@
+package release
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/registry"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/rollout"
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/routingview"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/schemaregistry"
+)
+
+// DefaultRollbackReportPath is the default execute-rollback report artifact path.
+const DefaultRollbackReportPath = ".codex/release/rollback-report.json"
+
+// RollbackAuditSource names execute-rollback in the distribution audit log.
+const RollbackAuditSource = "rspp-cli execute-rollback"
+
+const (
+	RollbackStepActivate      = "activate_target_version"
+	RollbackStepVerifyRouting = "verify_session_routing"
+	RollbackStepSmokeReplay   = "smoke_replay_gate"
+)
+
+// RollbackSmokeGate runs the replay smoke gate against the rolled-back pipeline version and
+// returns the produced report artifact.
+type RollbackSmokeGate func(pipelineVersion string) (ArtifactSource, error)
+
+// RollbackInput defines one rollback execution against a published release manifest.
+type RollbackInput struct {
+	Environment      string
+	Manifest         ReleaseManifest
+	ManifestSource   ArtifactSource
+	DistributionPath string
+	SmokeGate        RollbackSmokeGate
+	Now              time.Time
+}
+
+// RollbackStep captures one ordered rollback execution step.
+type RollbackStep struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// RollbackReport is the execute-rollback artifact.
+type RollbackReport struct {
+	ReleaseID               string          `json:"release_id"`
+	Environment             string          `json:"environment,omitempty"`
+	GeneratedAtUTC          string          `json:"generated_at_utc"`
+	FromPipelineVersion     string          `json:"from_pipeline_version"`
+	ToPipelineVersion       string          `json:"to_pipeline_version"`
+	PreviousActiveVersion   string          `json:"previous_active_version,omitempty"`
+	ResolvedPipelineVersion string          `json:"resolved_pipeline_version,omitempty"`
+	RoutingViewSnapshot     string          `json:"routing_view_snapshot,omitempty"`
+	DistributionPath        string          `json:"distribution_path"`
+	Manifest                ArtifactSource  `json:"manifest"`
+	SmokeReport             *ArtifactSource `json:"smoke_report,omitempty"`
+	Steps                   []RollbackStep  `json:"steps"`
+	Passed                  bool            `json:"passed"`
+	Violations              []string        `json:"violations,omitempty"`
+}
+
+// LoadReleaseManifest loads a publish-release manifest.
+func LoadReleaseManifest(path string) (ReleaseManifest, ArtifactSource, error) {
+	raw, source, err := readArtifact(path)
+	if err != nil {
+		return ReleaseManifest{}, ArtifactSource{}, err
+	}
+	upgraded, err := artifactSchemas.Upgrade(schemaregistry.KindReleaseManifest, raw)
+	if err != nil {
+		return ReleaseManifest{}, ArtifactSource{}, fmt.Errorf("release manifest %s: %w", source.Path, err)
+	}
+	manifest := ReleaseManifest{}
+	if err := strictUnmarshal(upgraded.Raw, &manifest); err != nil {
+		return ReleaseManifest{}, ArtifactSource{}, fmt.Errorf("decode release manifest %s: %w", source.Path, err)
+	}
+	if strings.TrimSpace(manifest.ReleaseID) == "" {
+		return ReleaseManifest{}, ArtifactSource{}, fmt.Errorf("release manifest %s: release_id is required", source.Path)
+	}
+	if err := ValidateRolloutConfig(manifest.RolloutConfig); err != nil {
+		return ReleaseManifest{}, ArtifactSource{}, fmt.Errorf("release manifest %s: %w", source.Path, err)
+	}
+	source.GeneratedAtUTC = manifest.GeneratedAtUTC
+	return manifest, source, nil
+}
+
+// ExecuteRollback flips the control-plane active pipeline version back to the manifest's
+// rollback target, verifies fresh session routing resolves to it, and runs the smoke gate.
+// Steps stop at the first failure; the report is always returned alongside any error.
+func ExecuteRollback(in RollbackInput) (RollbackReport, error) {
+	now := in.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	cfg := in.Manifest.RolloutConfig
+	report := RollbackReport{
+		ReleaseID:           in.Manifest.ReleaseID,
+		Environment:         in.Manifest.Environment,
+		GeneratedAtUTC:      now.UTC().Format(time.RFC3339),
+		FromPipelineVersion: strings.TrimSpace(cfg.PipelineVersion),
+		ToPipelineVersion:   strings.TrimSpace(cfg.RollbackPosture.TargetPipelineVersion),
+		DistributionPath:    strings.TrimSpace(in.DistributionPath),
+		Manifest:            in.ManifestSource,
+	}
+
+	fail := func(step string, err error) (RollbackReport, error) {
+		report.Steps = append(report.Steps, RollbackStep{Name: step, Passed: false, Detail: err.Error()})
+		report.Violations = append(report.Violations, fmt.Sprintf("%s: %v", step, err))
+		return report, fmt.Errorf("rollback %s failed: %w", step, err)
+	}
+
+	if err := checkArtifactEnvironment(in.Manifest.Environment, in.Environment); err != nil {
+		return fail(RollbackStepActivate, err)
+	}
+	if report.ToPipelineVersion == "" {
+		return fail(RollbackStepActivate, fmt.Errorf("manifest rollback_posture.target_pipeline_version is required"))
+	}
+	if report.DistributionPath == "" {
+		return fail(RollbackStepActivate, fmt.Errorf("control-plane distribution path is required"))
+	}
+
+	applied, err := distribution.RollbackActivePipelineVersion(report.DistributionPath, report.ToPipelineVersion, RollbackAuditSource, now)
+	if err != nil {
+		return fail(RollbackStepActivate, err)
+	}
+	previous := applied.PreviousPipelineVersion
+	report.PreviousActiveVersion = previous
+	report.Steps = append(report.Steps, RollbackStep{
+		Name:   RollbackStepActivate,
+		Passed: true,
+		Detail: fmt.Sprintf("active pipeline version %s -> %s", previous, report.ToPipelineVersion),
+	})
+
+	resolved, snapshot, err := verifyRollbackRouting(report.DistributionPath, report.ReleaseID, report.ToPipelineVersion)
+	report.ResolvedPipelineVersion = resolved
+	report.RoutingViewSnapshot = snapshot
+	if err != nil {
+		return fail(RollbackStepVerifyRouting, err)
+	}
+	report.Steps = append(report.Steps, RollbackStep{
+		Name:   RollbackStepVerifyRouting,
+		Passed: true,
+		Detail: fmt.Sprintf("new sessions resolve %s via %s", resolved, snapshot),
+	})
+
+	if in.SmokeGate == nil {
+		return fail(RollbackStepSmokeReplay, fmt.Errorf("smoke gate is required"))
+	}
+	smoke, err := in.SmokeGate(report.ToPipelineVersion)
+	if smoke.Path != "" {
+		report.SmokeReport = &smoke
+	}
+	if err != nil {
+		return fail(RollbackStepSmokeReplay, err)
+	}
+	report.Steps = append(report.Steps, RollbackStep{Name: RollbackStepSmokeReplay, Passed: true, Detail: smoke.Path})
+
+	report.Passed = true
+	return report, nil
+}
+
+// verifyRollbackRouting reloads the distribution artifact the way runtime turn-start does and
+// confirms a fresh session resolves the target version with a valid routing snapshot.
+func verifyRollbackRouting(distributionPath string, releaseID string, target string) (string, string, error) {
+	backends, err := distribution.NewFileBackends(distribution.FileAdapterConfig{Path: distributionPath})
+	if err != nil {
+		return "", "", err
+	}
+	sessionID := "rollback-verify-" + releaseID
+	resolved, err := rollout.Service{Backend: backends.Rollout}.ResolvePipelineVersion(rollout.ResolveVersionInput{SessionID: sessionID})
+	if err != nil {
+		return "", "", err
+	}
+	if resolved.PipelineVersion != target {
+		return resolved.PipelineVersion, "", fmt.Errorf("session routing resolved %s, expected %s", resolved.PipelineVersion, target)
+	}
+	if _, err := (registry.Service{Backend: backends.Registry}).ResolvePipelineRecord(resolved.PipelineVersion); err != nil {
+		return resolved.PipelineVersion, "", err
+	}
+	snapshot, err := routingview.Service{Backend: backends.RoutingView}.GetSnapshot(routingview.Input{SessionID: sessionID, PipelineVersion: resolved.PipelineVersion})
+	if err != nil {
+		return resolved.PipelineVersion, "", err
+	}
+	return resolved.PipelineVersion, snapshot.RoutingViewSnapshot, nil
+}
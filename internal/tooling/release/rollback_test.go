@@ -0,0 +1,158 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/controlplane/distribution"
+)
+
+const rollbackDistributionFixture = `{
+  "schema_version": "cp-snapshot-distribution/v1",
+  "registry": {
+    "default_pipeline_version": "pipeline-v2",
+    "records": {
+      "pipeline-v1": {"pipeline_version": "pipeline-v1", "graph_definition_ref": "graph/v1", "execution_profile": "simple"},
+      "pipeline-v2": {"pipeline_version": "pipeline-v2", "graph_definition_ref": "graph/v2", "execution_profile": "simple"}
+    }
+  },
+  "rollout": {"default_pipeline_version": "pipeline-v2"},
+  "routing_view": {
+    "default": {"routing_view_snapshot": "routing-view/file", "admission_policy_snapshot": "admission-policy/file", "abi_compatibility_snapshot": "abi-compat/file"}
+  }
+}`
+
+func rollbackManifest(target string) ReleaseManifest {
+	return ReleaseManifest{
+		ReleaseID: "rel-20260101000000-abc",
+		RolloutConfig: RolloutConfig{
+			PipelineVersion: "pipeline-v2",
+			Strategy:        "canary",
+			RollbackPosture: RollbackPosture{Mode: "manual", Trigger: "slo_breach", TargetPipelineVersion: target},
+		},
+	}
+}
+
+func TestExecuteRollback(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		target      string
+		smokeErr    error
+		wantPassed  bool
+		wantSteps   int
+		wantVersion string
+	}{
+		{name: "rolls back and verifies", target: "pipeline-v1", wantPassed: true, wantSteps: 3, wantVersion: "pipeline-v1"},
+		{name: "missing target", target: "", wantSteps: 1, wantVersion: "pipeline-v2"},
+		{name: "unknown target record", target: "pipeline-v0", wantSteps: 1, wantVersion: "pipeline-v2"},
+		{name: "smoke gate fails", target: "pipeline-v1", smokeErr: errors.New("forbidden divergences"), wantSteps: 3, wantVersion: "pipeline-v1"},
+	}
+	for _, tc := range tests {
+		distributionPath := filepath.Join(t.TempDir(), "cp.json")
+		if err := os.WriteFile(distributionPath, []byte(rollbackDistributionFixture), 0o644); err != nil {
+			t.Fatalf("%s: write distribution: %v", tc.name, err)
+		}
+		smokeVersion := ""
+		report, err := ExecuteRollback(RollbackInput{
+			Manifest:         rollbackManifest(tc.target),
+			DistributionPath: distributionPath,
+			SmokeGate: func(pipelineVersion string) (ArtifactSource, error) {
+				smokeVersion = pipelineVersion
+				return ArtifactSource{Path: "smoke.json"}, tc.smokeErr
+			},
+			Now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		})
+		if tc.wantPassed != (err == nil) || report.Passed != tc.wantPassed {
+			t.Fatalf("%s: expected passed=%v, got report=%+v err=%v", tc.name, tc.wantPassed, report, err)
+		}
+		if len(report.Steps) != tc.wantSteps {
+			t.Fatalf("%s: expected %d steps, got %+v", tc.name, tc.wantSteps, report.Steps)
+		}
+		if tc.wantPassed && (smokeVersion != tc.target || report.ResolvedPipelineVersion != tc.target || report.RoutingViewSnapshot != "routing-view/file") {
+			t.Fatalf("%s: expected smoke and routing against %s, got smoke=%s report=%+v", tc.name, tc.target, smokeVersion, report)
+		}
+
+		audit, auditErr := distribution.ReadAuditLog(distributionPath)
+		if auditErr != nil {
+			t.Fatalf("%s: read audit log: %v", tc.name, auditErr)
+		}
+		wantAudit := 0
+		if tc.wantVersion == tc.target {
+			wantAudit = 1
+		}
+		if len(audit) != wantAudit {
+			t.Fatalf("%s: expected %d audit entries, got %+v", tc.name, wantAudit, audit)
+		}
+		if wantAudit == 1 && (audit[0].Action != distribution.AuditActionRollback || audit[0].Source != RollbackAuditSource || audit[0].PipelineVersion != tc.target || audit[0].PreviousPipelineVersion != "pipeline-v2") {
+			t.Fatalf("%s: expected execute-rollback audit entry, got %+v", tc.name, audit[0])
+		}
+
+		activeVersion, _, verifyErr := verifyRollbackRouting(distributionPath, "check", tc.wantVersion)
+		if verifyErr != nil || activeVersion != tc.wantVersion {
+			t.Fatalf("%s: expected active version %s after rollback, got %s err=%v", tc.name, tc.wantVersion, activeVersion, verifyErr)
+		}
+	}
+}
+
+func TestExecuteRollbackRejectsEnvironmentMismatch(t *testing.T) {
+	t.Parallel()
+
+	manifest := rollbackManifest("pipeline-v1")
+	manifest.Environment = EnvironmentStaging
+	report, err := ExecuteRollback(RollbackInput{
+		Environment:      EnvironmentProd,
+		Manifest:         manifest,
+		DistributionPath: "unused.json",
+		SmokeGate:        func(string) (ArtifactSource, error) { return ArtifactSource{}, nil },
+	})
+	if err == nil || report.Passed || len(report.Violations) != 1 {
+		t.Fatalf("expected environment mismatch failure, got report=%+v err=%v", report, err)
+	}
+}
+
+func TestValidateRolloutConfigRejectsSelfRollbackTarget(t *testing.T) {
+	t.Parallel()
+
+	cfg := rollbackManifest("pipeline-v2").RolloutConfig
+	if err := ValidateRolloutConfig(cfg); err == nil {
+		t.Fatalf("expected rollback target equal to pipeline_version to be rejected")
+	}
+}
+
+func TestLoadReleaseManifestUpgradesPreviousSchema(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	tests := []struct {
+		name          string
+		schemaVersion string
+		shouldErr     bool
+	}{
+		{name: "legacy unversioned"},
+		{name: "current", schemaVersion: "release-manifest/v2"},
+		{name: "newer than supported", schemaVersion: "release-manifest/v3", shouldErr: true},
+	}
+	for i, tc := range tests {
+		manifest := rollbackManifest("pipeline-v1")
+		manifest.SchemaVersion = tc.schemaVersion
+		path := filepath.Join(tmp, fmt.Sprintf("manifest-%d.json", i))
+		mustWriteJSON(t, path, manifest)
+
+		loaded, _, err := LoadReleaseManifest(path)
+		if tc.shouldErr {
+			if err == nil {
+				t.Fatalf("%s: expected load error", tc.name)
+			}
+			continue
+		}
+		if err != nil || loaded.SchemaVersion != "release-manifest/v2" || loaded.ReleaseID != manifest.ReleaseID {
+			t.Fatalf("%s: expected manifest upgraded to v2, got %+v err=%v", tc.name, loaded, err)
+		}
+	}
+}
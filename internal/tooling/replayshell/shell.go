@@ -0,0 +1,445 @@
+package replayshell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+	replaycmp "github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+// Prompt is printed before each command when the shell runs interactively.
+const Prompt = "replay> "
+
+// Step is one recorded decision outcome in runtime-sequence order.
+type Step struct {
+	Index           int
+	RuntimeSequence int64
+	// EntryIndex is the baseline artifact entry (turn) the decision was recorded in.
+	EntryIndex  int
+	Decision    controlplane.DecisionOutcome
+	Divergences []obs.ReplayDivergence
+}
+
+// Timeline is the stepped view of a baseline artifact, optionally compared to a candidate.
+type Timeline struct {
+	Entries []timeline.BaselineEvidence
+	Steps   []Step
+}
+
+// Build orders every decision outcome in baseline by runtime sequence, then runtime timestamp,
+// then recorded order. When candidate is non-nil its steps are ordered the same way and compared
+// index by index; each step carries the divergences found at that position.
+func Build(baseline []timeline.BaselineEvidence, candidate []timeline.BaselineEvidence, cfg replaycmp.CompareConfig) (Timeline, error) {
+	steps := orderedSteps(baseline)
+	if len(steps) == 0 {
+		return Timeline{}, fmt.Errorf("baseline artifact has no decision outcomes")
+	}
+	out := Timeline{Entries: baseline, Steps: steps}
+	if candidate == nil {
+		return out, nil
+	}
+
+	candidateSteps := orderedSteps(candidate)
+	for i := range out.Steps {
+		if i >= len(candidateSteps) {
+			out.Steps[i].Divergences = append(out.Steps[i].Divergences, obs.ReplayDivergence{
+				Class:   obs.OutcomeDivergence,
+				Scope:   divergenceScope(out.Steps[i].Decision),
+				Message: fmt.Sprintf("candidate has no step at index=%d baseline_event=%s", i, out.Steps[i].Decision.EventID),
+			})
+			continue
+		}
+		base := traceArtifact(baseline, out.Steps[i])
+		replayed := traceArtifact(candidate, candidateSteps[i])
+		for _, divergence := range replaycmp.CompareTraceArtifacts([]replaycmp.TraceArtifact{base}, []replaycmp.TraceArtifact{replayed}, cfg) {
+			// CompareTraceArtifacts indexes within the one-element slice; report the step index.
+			divergence.Message = strings.Replace(divergence.Message, "index=0", fmt.Sprintf("index=%d", i), 1)
+			out.Steps[i].Divergences = append(out.Steps[i].Divergences, divergence)
+		}
+	}
+	if extra := len(candidateSteps) - len(out.Steps); extra > 0 {
+		last := &out.Steps[len(out.Steps)-1]
+		last.Divergences = append(last.Divergences, obs.ReplayDivergence{
+			Class:   obs.OutcomeDivergence,
+			Scope:   "trace",
+			Message: fmt.Sprintf("candidate has %d steps after baseline end", extra),
+		})
+	}
+	return out, nil
+}
+
+func orderedSteps(entries []timeline.BaselineEvidence) []Step {
+	steps := make([]Step, 0)
+	for entryIdx, entry := range entries {
+		sequence := entryRuntimeSequence(entry)
+		for _, decision := range entry.DecisionOutcomes {
+			steps = append(steps, Step{RuntimeSequence: sequence, EntryIndex: entryIdx, Decision: decision})
+		}
+	}
+	sort.SliceStable(steps, func(i, j int) bool {
+		if steps[i].RuntimeSequence != steps[j].RuntimeSequence {
+			return steps[i].RuntimeSequence < steps[j].RuntimeSequence
+		}
+		return steps[i].Decision.RuntimeTimestampMS < steps[j].Decision.RuntimeTimestampMS
+	})
+	for i := range steps {
+		steps[i].Index = i
+	}
+	return steps
+}
+
+// entryRuntimeSequence reads the runtime_sequence ordering marker, falling back to the
+// determinism seed for entries recorded without one.
+func entryRuntimeSequence(entry timeline.BaselineEvidence) int64 {
+	for _, marker := range entry.OrderingMarkers {
+		if value, ok := strings.CutPrefix(marker, "runtime_sequence:"); ok {
+			if sequence, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return sequence
+			}
+		}
+	}
+	return entry.DeterminismSeed
+}
+
+func traceArtifact(entries []timeline.BaselineEvidence, step Step) replaycmp.TraceArtifact {
+	entry := entries[step.EntryIndex]
+	return replaycmp.TraceArtifact{
+		PlanHash:              entry.PlanHash,
+		SnapshotProvenanceRef: snapshotRef(entry.SnapshotProvenance),
+		Decision:              step.Decision,
+		OrderingMarker:        fmt.Sprintf("runtime_sequence:%d", step.RuntimeSequence),
+		AuthorityEpoch:        entry.AuthorityEpoch,
+		RuntimeTimestampMS:    step.Decision.RuntimeTimestampMS,
+		Locale:                entry.Locale,
+		SessionSummaryHash:    entry.SessionSummaryHash,
+		UnseededSampling:      unseededSampling(entry),
+	}
+}
+
+func unseededSampling(entry timeline.BaselineEvidence) []string {
+	var out []string
+	for _, outcome := range entry.InvocationOutcomes {
+		if outcome.SamplingSeedStatus == "ignored" || outcome.SamplingSeedStatus == "unsupported" {
+			out = append(out, outcome.ProviderID+"="+outcome.SamplingSeedStatus)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func snapshotRef(p controlplane.SnapshotProvenance) string {
+	return strings.Join([]string{
+		p.RoutingViewSnapshot,
+		p.AdmissionPolicySnapshot,
+		p.ABICompatibilitySnapshot,
+		p.VersionResolutionSnapshot,
+		p.PolicyResolutionSnapshot,
+		p.ProviderHealthSnapshot,
+	}, "|")
+}
+
+func divergenceScope(out controlplane.DecisionOutcome) string {
+	if out.TurnID != "" {
+		return "turn:" + out.TurnID
+	}
+	return "session:" + out.SessionID
+}
+
+// Shell is a cursor over a Timeline with divergence-class breakpoints.
+type Shell struct {
+	timeline    Timeline
+	cursor      int
+	breakpoints map[obs.DivergenceClass]bool
+	out         io.Writer
+}
+
+// New returns a shell positioned at the first step.
+func New(t Timeline, out io.Writer) *Shell {
+	return &Shell{timeline: t, breakpoints: map[obs.DivergenceClass]bool{}, out: out}
+}
+
+// Cursor returns the current step index.
+func (s *Shell) Cursor() int {
+	return s.cursor
+}
+
+// Run executes commands from in until EOF or quit. With interactive set, Prompt is printed
+// before each command.
+func (s *Shell) Run(in io.Reader, interactive bool) error {
+	scanner := bufio.NewScanner(in)
+	s.printStep()
+	for {
+		if interactive {
+			fmt.Fprint(s.out, Prompt)
+		}
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+		quit, err := s.Exec(scanner.Text())
+		if err != nil {
+			fmt.Fprintf(s.out, "error: %v\n", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// Exec runs one command line and reports whether the shell should exit.
+func (s *Shell) Exec(line string) (bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false, nil
+	}
+	args := fields[1:]
+	switch fields[0] {
+	case "n", "next":
+		return false, s.move(args, 1)
+	case "p", "prev":
+		return false, s.move(args, -1)
+	case "g", "goto":
+		return false, s.jump(args)
+	case "c", "continue":
+		return false, s.seek(1)
+	case "rc", "reverse-continue":
+		return false, s.seek(-1)
+	case "b", "break":
+		return false, s.setBreakpoints(args, true)
+	case "d", "delete":
+		return false, s.setBreakpoints(args, false)
+	case "breaks":
+		s.printBreakpoints()
+	case "s", "show":
+		s.printStep()
+	case "state":
+		s.printState()
+	case "diff":
+		s.printDivergences()
+	case "l", "list":
+		return false, s.list(args)
+	case "h", "help":
+		s.printHelp()
+	case "q", "quit", "exit":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown command %q (try help)", fields[0])
+	}
+	return false, nil
+}
+
+func (s *Shell) move(args []string, direction int) error {
+	count := 1
+	if len(args) > 0 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed < 1 {
+			return fmt.Errorf("step count must be a positive integer")
+		}
+		count = parsed
+	}
+	target := s.cursor + direction*count
+	if target < 0 || target >= len(s.timeline.Steps) {
+		return fmt.Errorf("step %d is outside 0..%d", target, len(s.timeline.Steps)-1)
+	}
+	s.cursor = target
+	s.printStep()
+	return nil
+}
+
+// jump moves the cursor to a step index or to the step recording an event_id.
+func (s *Shell) jump(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("goto requires a step index or event_id")
+	}
+	if index, err := strconv.Atoi(args[0]); err == nil {
+		if index < 0 || index >= len(s.timeline.Steps) {
+			return fmt.Errorf("step %d is outside 0..%d", index, len(s.timeline.Steps)-1)
+		}
+		s.cursor = index
+		s.printStep()
+		return nil
+	}
+	for _, step := range s.timeline.Steps {
+		if step.Decision.EventID == args[0] {
+			s.cursor = step.Index
+			s.printStep()
+			return nil
+		}
+	}
+	return fmt.Errorf("no step records event_id %s", args[0])
+}
+
+// seek moves to the next step in direction that hits a breakpoint. With no breakpoints set,
+// any divergence stops the seek.
+func (s *Shell) seek(direction int) error {
+	for i := s.cursor + direction; i >= 0 && i < len(s.timeline.Steps); i += direction {
+		if class, ok := s.hits(s.timeline.Steps[i]); ok {
+			s.cursor = i
+			fmt.Fprintf(s.out, "breakpoint %s\n", class)
+			s.printStep()
+			return nil
+		}
+	}
+	if direction > 0 {
+		return fmt.Errorf("no breakpoint hit before the end of the timeline")
+	}
+	return fmt.Errorf("no breakpoint hit before the start of the timeline")
+}
+
+func (s *Shell) hits(step Step) (obs.DivergenceClass, bool) {
+	for _, divergence := range step.Divergences {
+		if divergence.Expected {
+			continue
+		}
+		if len(s.breakpoints) == 0 || s.breakpoints[divergence.Class] {
+			return divergence.Class, true
+		}
+	}
+	return "", false
+}
+
+func (s *Shell) setBreakpoints(args []string, enabled bool) error {
+	if len(args) == 0 {
+		return fmt.Errorf("break/delete requires at least one divergence class")
+	}
+	for _, arg := range args {
+		class := obs.DivergenceClass(strings.ToUpper(arg))
+		if !strings.HasSuffix(string(class), "_DIVERGENCE") {
+			class += "_DIVERGENCE"
+		}
+		if !isDivergenceClass(class) {
+			return fmt.Errorf("unknown divergence class %q", arg)
+		}
+		if enabled {
+			s.breakpoints[class] = true
+		} else {
+			delete(s.breakpoints, class)
+		}
+	}
+	s.printBreakpoints()
+	return nil
+}
+
+func isDivergenceClass(class obs.DivergenceClass) bool {
+	switch class {
+	case obs.PlanDivergence, obs.OutcomeDivergence, obs.OrderingDivergence, obs.TimingDivergence, obs.AuthorityDivergence:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Shell) list(args []string) error {
+	window := 5
+	if len(args) > 0 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed < 1 {
+			return fmt.Errorf("list window must be a positive integer")
+		}
+		window = parsed
+	}
+	start := max(0, s.cursor-window)
+	end := min(len(s.timeline.Steps), s.cursor+window+1)
+	for _, step := range s.timeline.Steps[start:end] {
+		marker := " "
+		if step.Index == s.cursor {
+			marker = ">"
+		}
+		fmt.Fprintf(s.out, "%s %s\n", marker, stepLine(step))
+	}
+	return nil
+}
+
+func stepLine(step Step) string {
+	flag := ""
+	if len(step.Divergences) > 0 {
+		flag = fmt.Sprintf(" [%d divergence(s)]", len(step.Divergences))
+	}
+	return fmt.Sprintf("#%d seq=%d t=%dms %s %s/%s event=%s reason=%s%s",
+		step.Index, step.RuntimeSequence, step.Decision.RuntimeTimestampMS, divergenceScope(step.Decision),
+		step.Decision.Phase, step.Decision.OutcomeKind, step.Decision.EventID, step.Decision.Reason, flag)
+}
+
+func (s *Shell) printStep() {
+	fmt.Fprintf(s.out, "%s\n", stepLine(s.timeline.Steps[s.cursor]))
+}
+
+// printState reports the turn the cursor is in as it stood at the current step: decisions
+// observed so far and the terminal outcome only once its final decision has been reached.
+func (s *Shell) printState() {
+	step := s.timeline.Steps[s.cursor]
+	entry := s.timeline.Entries[step.EntryIndex]
+	seen := make([]string, 0)
+	remaining := 0
+	for _, other := range s.timeline.Steps {
+		if other.EntryIndex != step.EntryIndex {
+			continue
+		}
+		if other.Index <= s.cursor {
+			seen = append(seen, fmt.Sprintf("%s/%s", other.Decision.Phase, other.Decision.OutcomeKind))
+		} else {
+			remaining++
+		}
+	}
+	terminal := "open"
+	if remaining == 0 {
+		terminal = entry.TerminalOutcome
+		if entry.TerminalReason != "" {
+			terminal += " (" + entry.TerminalReason + ")"
+		}
+	}
+	fmt.Fprintf(s.out, "session=%s turn=%s pipeline=%s\n", entry.SessionID, entry.TurnID, entry.PipelineVersion)
+	fmt.Fprintf(s.out, "plan_hash=%s authority_epoch=%d\n", entry.PlanHash, entry.AuthorityEpoch)
+	fmt.Fprintf(s.out, "decisions=%s remaining=%d terminal=%s\n", strings.Join(seen, ","), remaining, terminal)
+	if step.Decision.Explain != nil {
+		fmt.Fprintf(s.out, "explain policy_rule=%s\n", step.Decision.Explain.PolicyRule)
+	}
+}
+
+func (s *Shell) printDivergences() {
+	step := s.timeline.Steps[s.cursor]
+	if len(step.Divergences) == 0 {
+		fmt.Fprintln(s.out, "no divergences at this step")
+		return
+	}
+	for _, divergence := range step.Divergences {
+		fmt.Fprintf(s.out, "%s %s: %s\n", divergence.Class, divergence.Scope, divergence.Message)
+	}
+}
+
+func (s *Shell) printBreakpoints() {
+	if len(s.breakpoints) == 0 {
+		fmt.Fprintln(s.out, "breakpoints: none (continue stops on any divergence)")
+		return
+	}
+	classes := make([]string, 0, len(s.breakpoints))
+	for class := range s.breakpoints {
+		classes = append(classes, string(class))
+	}
+	sort.Strings(classes)
+	fmt.Fprintf(s.out, "breakpoints: %s\n", strings.Join(classes, ","))
+}
+
+func (s *Shell) printHelp() {
+	fmt.Fprint(s.out, `commands:
+  next|n [k]            step forward k steps
+  prev|p [k]            step back k steps
+  goto|g <idx|event_id> jump the cursor
+  continue|c            run forward to the next breakpoint
+  reverse-continue|rc   run backward to the previous breakpoint
+  break|b <class...>    break on divergence classes (plan, outcome, ordering, timing, authority)
+  delete|d <class...>   remove breakpoints
+  breaks                list breakpoints
+  show|s                print the current step
+  state                 print turn state at the current step
+  diff                  print divergences at the current step
+  list|l [k]            list k steps around the cursor
+  quit|q                exit
+`)
+}
@@ -0,0 +1,212 @@
+package replayshell
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	obs "github.com/tiger/realtime-speech-pipeline/api/observability"
+	replaycmp "github.com/tiger/realtime-speech-pipeline/internal/observability/replay"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+func shellDecision(turnID string, eventID string, kind controlplane.OutcomeKind, atMS int64) controlplane.DecisionOutcome {
+	return controlplane.DecisionOutcome{
+		OutcomeKind:        kind,
+		Phase:              controlplane.PhasePreTurn,
+		Scope:              controlplane.ScopeTurn,
+		SessionID:          "sess-shell",
+		TurnID:             turnID,
+		EventID:            eventID,
+		RuntimeTimestampMS: atMS,
+		WallClockMS:        atMS,
+		EmittedBy:          controlplane.EmitterRK25,
+		Reason:             "admission_capacity_allow",
+	}
+}
+
+func shellEntries() []timeline.BaselineEvidence {
+	return []timeline.BaselineEvidence{
+		{
+			SessionID:        "sess-shell",
+			TurnID:           "turn-2",
+			PlanHash:         "plan-a",
+			AuthorityEpoch:   1,
+			OrderingMarkers:  []string{"runtime_sequence:20"},
+			DecisionOutcomes: []controlplane.DecisionOutcome{shellDecision("turn-2", "evt-3", controlplane.OutcomeAdmit, 200)},
+			TerminalOutcome:  "commit",
+		},
+		{
+			SessionID:       "sess-shell",
+			TurnID:          "turn-1",
+			PlanHash:        "plan-a",
+			AuthorityEpoch:  1,
+			OrderingMarkers: []string{"runtime_sequence:10"},
+			DecisionOutcomes: []controlplane.DecisionOutcome{
+				shellDecision("turn-1", "evt-2", controlplane.OutcomeShed, 120),
+				shellDecision("turn-1", "evt-1", controlplane.OutcomeAdmit, 100),
+			},
+			TerminalOutcome: "abort",
+			TerminalReason:  "scheduling_point_shed",
+		},
+	}
+}
+
+func TestBuildOrdersByRuntimeSequenceAndAttachesDivergences(t *testing.T) {
+	t.Parallel()
+
+	candidate := shellEntries()
+	candidate[0].PlanHash = "plan-b"
+	candidate[1].DecisionOutcomes[0].RuntimeTimestampMS = 180
+
+	tests := []struct {
+		name        string
+		candidate   []timeline.BaselineEvidence
+		wantClasses [][]obs.DivergenceClass
+	}{
+		{name: "baseline only", wantClasses: [][]obs.DivergenceClass{nil, nil, nil}},
+		{name: "identical candidate", candidate: shellEntries(), wantClasses: [][]obs.DivergenceClass{nil, nil, nil}},
+		{
+			name:        "plan and timing drift",
+			candidate:   candidate,
+			wantClasses: [][]obs.DivergenceClass{nil, {obs.OutcomeDivergence, obs.TimingDivergence}, {obs.PlanDivergence}},
+		},
+		{
+			name:        "truncated candidate",
+			candidate:   shellEntries()[1:],
+			wantClasses: [][]obs.DivergenceClass{nil, nil, {obs.OutcomeDivergence}},
+		},
+	}
+	for _, tc := range tests {
+		built, err := Build(shellEntries(), tc.candidate, replaycmp.CompareConfig{TimingToleranceMS: 10})
+		if err != nil {
+			t.Fatalf("%s: unexpected build error: %v", tc.name, err)
+		}
+		events := make([]string, 0, len(built.Steps))
+		for _, step := range built.Steps {
+			events = append(events, step.Decision.EventID)
+		}
+		if strings.Join(events, ",") != "evt-1,evt-2,evt-3" {
+			t.Fatalf("%s: expected runtime-sequence order, got %v", tc.name, events)
+		}
+		for i, step := range built.Steps {
+			if len(step.Divergences) != len(tc.wantClasses[i]) {
+				t.Fatalf("%s: step %d expected %v, got %+v", tc.name, i, tc.wantClasses[i], step.Divergences)
+			}
+			for j, divergence := range step.Divergences {
+				if divergence.Class != tc.wantClasses[i][j] {
+					t.Fatalf("%s: step %d expected %v, got %+v", tc.name, i, tc.wantClasses[i], step.Divergences)
+				}
+			}
+		}
+	}
+
+	if _, err := Build(nil, nil, replaycmp.CompareConfig{}); err == nil {
+		t.Fatalf("expected empty baseline error")
+	}
+}
+
+func TestBuildExplainsOutcomeDivergenceFromUnseededSampling(t *testing.T) {
+	t.Parallel()
+
+	baseline := shellEntries()
+	baseline[1].InvocationOutcomes = []timeline.InvocationOutcomeEvidence{
+		{ProviderInvocationID: "pvi-1", Modality: "llm", ProviderID: "llm-b", SamplingSeed: 11, SamplingSeedStatus: "unsupported"},
+		{ProviderInvocationID: "pvi-2", Modality: "llm", ProviderID: "llm-a", SamplingSeed: 12, SamplingSeedStatus: "applied"},
+	}
+	candidate := shellEntries()
+	candidate[1].InvocationOutcomes = []timeline.InvocationOutcomeEvidence{
+		{ProviderInvocationID: "pvi-1", Modality: "llm", ProviderID: "llm-a", SamplingSeed: 11, SamplingSeedStatus: "ignored"},
+	}
+	candidate[1].DecisionOutcomes[1].Reason = "admission_capacity_shed"
+
+	built, err := Build(baseline, candidate, replaycmp.CompareConfig{TimingToleranceMS: 10})
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+	divergences := built.Steps[0].Divergences
+	if len(divergences) != 1 || divergences[0].Class != obs.OutcomeDivergence {
+		t.Fatalf("expected one outcome divergence, got %+v", divergences)
+	}
+	if want := "(llm sampling not seeded: llm-a=ignored,llm-b=unsupported; provider output is not replay-deterministic)"; !strings.HasSuffix(divergences[0].Message, want) {
+		t.Fatalf("expected unseeded sampling explanation, got %q", divergences[0].Message)
+	}
+
+	seeded := shellEntries()
+	seeded[1].DecisionOutcomes[1].Reason = "admission_capacity_shed"
+	built, err = Build(shellEntries(), seeded, replaycmp.CompareConfig{TimingToleranceMS: 10})
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+	if message := built.Steps[0].Divergences[0].Message; strings.Contains(message, "not seeded") {
+		t.Fatalf("expected no sampling explanation without unseeded providers, got %q", message)
+	}
+}
+
+func TestShellCommands(t *testing.T) {
+	t.Parallel()
+
+	candidate := shellEntries()
+	candidate[0].PlanHash = "plan-b"
+	candidate[1].DecisionOutcomes[0].RuntimeTimestampMS = 180
+	built, err := Build(shellEntries(), candidate, replaycmp.CompareConfig{TimingToleranceMS: 10})
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		commands   []string
+		wantCursor int
+		wantOutput string
+		wantErr    bool
+	}{
+		{name: "next and prev", commands: []string{"next 2", "prev"}, wantCursor: 1},
+		{name: "goto event", commands: []string{"goto evt-3"}, wantCursor: 2},
+		{name: "continue stops on any divergence", commands: []string{"continue"}, wantCursor: 1, wantOutput: "breakpoint OUTCOME_DIVERGENCE"},
+		{name: "continue honors breakpoints", commands: []string{"break plan", "c"}, wantCursor: 2, wantOutput: "breakpoints: PLAN_DIVERGENCE"},
+		{name: "reverse continue", commands: []string{"goto 2", "break timing", "rc"}, wantCursor: 1, wantOutput: "breakpoint TIMING_DIVERGENCE"},
+		{name: "state before terminal", commands: []string{"state"}, wantOutput: "terminal=open"},
+		{name: "state at terminal", commands: []string{"n", "state"}, wantCursor: 1, wantOutput: "terminal=abort (scheduling_point_shed)"},
+		{name: "diff", commands: []string{"g 1", "diff"}, wantCursor: 1, wantOutput: "timing mismatch at index=1"},
+		{name: "out of range", commands: []string{"prev"}, wantErr: true},
+		{name: "unknown class", commands: []string{"break noise"}, wantErr: true},
+		{name: "unknown command", commands: []string{"rewind"}, wantErr: true},
+	}
+	for _, tc := range tests {
+		var out bytes.Buffer
+		shell := New(built, &out)
+		var lastErr error
+		for _, command := range tc.commands {
+			if _, err := shell.Exec(command); err != nil {
+				lastErr = err
+			}
+		}
+		if (lastErr != nil) != tc.wantErr {
+			t.Fatalf("%s: expected error=%t, got %v", tc.name, tc.wantErr, lastErr)
+		}
+		if shell.Cursor() != tc.wantCursor {
+			t.Fatalf("%s: expected cursor %d, got %d", tc.name, tc.wantCursor, shell.Cursor())
+		}
+		if !strings.Contains(out.String(), tc.wantOutput) {
+			t.Fatalf("%s: expected output containing %q, got %s", tc.name, tc.wantOutput, out.String())
+		}
+	}
+}
+
+func TestShellRunStopsOnQuit(t *testing.T) {
+	t.Parallel()
+
+	built, err := Build(shellEntries(), nil, replaycmp.CompareConfig{})
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+	var out bytes.Buffer
+	if err := New(built, &out).Run(strings.NewReader("next\nbogus\nquit\nnext\n"), true); err != nil {
+		t.Fatalf("unexpected run error: %v", err)
+	}
+	if strings.Count(out.String(), Prompt) != 3 || !strings.Contains(out.String(), "error: unknown command") || strings.Contains(out.String(), "#2") {
+		t.Fatalf("expected three prompts, one error, and no step after quit, got %s", out.String())
+	}
+}
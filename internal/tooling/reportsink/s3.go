@@ -0,0 +1,90 @@
+package reportsink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+type objectPutter interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// S3Sink uploads the artifact and summary to s3://Bucket/Prefix/<environment>/<command>/.
+// Credentials come from the default AWS chain.
+type S3Sink struct {
+	Bucket string
+	Prefix string
+	Region string
+
+	mu     sync.Mutex
+	client objectPutter
+}
+
+// Name identifies the sink in dispatch errors.
+func (s *S3Sink) Name() string {
+	return string(KindS3)
+}
+
+// Publish uploads report files.
+func (s *S3Sink) Publish(ctx context.Context, report Report) error {
+	client, err := s.resolveClient(ctx)
+	if err != nil {
+		return err
+	}
+	for _, file := range reportFiles(report) {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("read report file: %w", err)
+		}
+		key := s.objectKey(report, filepath.Base(file))
+		contentType := "application/json"
+		if strings.HasSuffix(file, ".md") {
+			contentType = "text/markdown"
+		}
+		if _, err := client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      &s.Bucket,
+			Key:         &key,
+			Body:        bytes.NewReader(raw),
+			ContentType: &contentType,
+		}); err != nil {
+			return fmt.Errorf("upload s3://%s/%s: %w", s.Bucket, key, err)
+		}
+	}
+	return nil
+}
+
+func (s *S3Sink) objectKey(report Report, name string) string {
+	parts := []string{strings.Trim(s.Prefix, "/")}
+	if report.Environment != "" {
+		parts = append(parts, report.Environment)
+	}
+	parts = append(parts, report.Command, name)
+	return strings.TrimPrefix(path.Join(parts...), "/")
+}
+
+func (s *S3Sink) resolveClient(ctx context.Context) (objectPutter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client != nil {
+		return s.client, nil
+	}
+	var opts []func(*awsconfig.LoadOptions) error
+	if strings.TrimSpace(s.Region) != "" {
+		opts = append(opts, awsconfig.WithRegion(s.Region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+	s.client = s3.NewFromConfig(cfg)
+	return s.client, nil
+}
@@ -0,0 +1,177 @@
+package reportsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ConfigPathEnv points at the sinks config file. When unset, gate commands keep the
+// filesystem-only behavior of writing artifacts to their output paths.
+const ConfigPathEnv = "RSPP_REPORT_SINKS_CONFIG"
+
+// Kind identifies a built-in report sink.
+type Kind string
+
+const (
+	KindFilesystem      Kind = "filesystem"
+	KindS3              Kind = "s3"
+	KindGitHubPRComment Kind = "github_pr_comment"
+	KindSlack           Kind = "slack"
+)
+
+// Report describes one completed gate command and the artifacts it wrote.
+type Report struct {
+	Command        string `json:"command"`
+	Environment    string `json:"environment,omitempty"`
+	ArtifactPath   string `json:"artifact_path"`
+	SummaryPath    string `json:"summary_path,omitempty"`
+	Passed         bool   `json:"passed"`
+	Detail         string `json:"detail,omitempty"`
+	CompletedAtUTC string `json:"completed_at_utc"`
+	// Summary is the rendered markdown summary, when the command wrote one.
+	Summary string `json:"-"`
+}
+
+// Status renders the report gate status.
+func (r Report) Status() string {
+	if r.Passed {
+		return "PASS"
+	}
+	return "FAIL"
+}
+
+// Sink publishes a completed gate report.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, report Report) error
+}
+
+// SinkConfig configures one sink. Fields apply per kind; secrets such as webhook URLs are
+// referenced by RSPP_* env var name and never stored in the config file.
+type SinkConfig struct {
+	Kind Kind `json:"kind"`
+	// Dir receives artifact copies for filesystem sinks. Empty leaves artifacts in place.
+	Dir string `json:"dir,omitempty"`
+	// Bucket, Prefix, and Region address S3 uploads.
+	Bucket string `json:"bucket,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	Region string `json:"region,omitempty"`
+	// OutputPath is the generated PR comment body for github_pr_comment sinks.
+	OutputPath string `json:"output_path,omitempty"`
+	// WebhookURLEnv names the env var holding the Slack webhook URL.
+	WebhookURLEnv string `json:"webhook_url_env,omitempty"`
+}
+
+// Validate enforces sink shape per kind.
+func (c SinkConfig) Validate() error {
+	switch c.Kind {
+	case KindFilesystem:
+	case KindS3:
+		if strings.TrimSpace(c.Bucket) == "" {
+			return fmt.Errorf("report sink s3 requires bucket")
+		}
+	case KindGitHubPRComment:
+		if strings.TrimSpace(c.OutputPath) == "" {
+			return fmt.Errorf("report sink github_pr_comment requires output_path")
+		}
+	case KindSlack:
+		if env := strings.TrimSpace(c.WebhookURLEnv); env != "" && !strings.HasPrefix(env, "RSPP_") {
+			return fmt.Errorf("report sink slack webhook_url_env must be an RSPP_* variable, got %q", env)
+		}
+	default:
+		return fmt.Errorf("report sink kind %q is unsupported", c.Kind)
+	}
+	return nil
+}
+
+// Config lists the sinks invoked after each gate command, in order.
+type Config struct {
+	Sinks []SinkConfig `json:"sinks"`
+}
+
+// Validate enforces sinks config requirements.
+func (c Config) Validate() error {
+	if len(c.Sinks) == 0 {
+		return fmt.Errorf("report sinks config requires at least one sink")
+	}
+	for _, sink := range c.Sinks {
+		if err := sink.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DefaultConfig keeps the current behavior: artifacts stay where the command wrote them.
+func DefaultConfig() Config {
+	return Config{Sinks: []SinkConfig{{Kind: KindFilesystem}}}
+}
+
+// LoadConfig reads and validates a sinks configuration.
+func LoadConfig(path string) (Config, error) {
+	trimmed := strings.TrimSpace(path)
+	if trimmed == "" {
+		return Config{}, fmt.Errorf("report sinks config path is required")
+	}
+	raw, err := os.ReadFile(trimmed)
+	if err != nil {
+		return Config{}, fmt.Errorf("read report sinks config %s: %w", trimmed, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("decode report sinks config %s: %w", trimmed, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("validate report sinks config %s: %w", trimmed, err)
+	}
+	return cfg, nil
+}
+
+// ConfigFromEnv loads the config named by RSPP_REPORT_SINKS_CONFIG, or DefaultConfig.
+func ConfigFromEnv() (Config, error) {
+	path := strings.TrimSpace(os.Getenv(ConfigPathEnv))
+	if path == "" {
+		return DefaultConfig(), nil
+	}
+	return LoadConfig(path)
+}
+
+// Build constructs the configured built-in sinks.
+func Build(cfg Config) ([]Sink, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	sinks := make([]Sink, 0, len(cfg.Sinks))
+	for _, sc := range cfg.Sinks {
+		switch sc.Kind {
+		case KindFilesystem:
+			sinks = append(sinks, FilesystemSink{Dir: sc.Dir})
+		case KindS3:
+			sinks = append(sinks, &S3Sink{Bucket: sc.Bucket, Prefix: sc.Prefix, Region: sc.Region})
+		case KindGitHubPRComment:
+			sinks = append(sinks, GitHubPRCommentSink{OutputPath: sc.OutputPath})
+		case KindSlack:
+			sinks = append(sinks, SlackSink{WebhookURLEnv: sc.WebhookURLEnv})
+		}
+	}
+	return sinks, nil
+}
+
+// Dispatch publishes report to every sink. A failing sink does not stop later sinks;
+// all failures are returned joined.
+func Dispatch(ctx context.Context, sinks []Sink, report Report) error {
+	var errs []error
+	for _, sink := range sinks {
+		if err := sink.Publish(ctx, report); err != nil {
+			errs = append(errs, fmt.Errorf("report sink %s: %w", sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
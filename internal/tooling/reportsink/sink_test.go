@@ -0,0 +1,233 @@
+package reportsink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/backoff"
+)
+
+func writeReportFiles(t *testing.T, command string, passed bool) Report {
+	t.Helper()
+	dir := t.TempDir()
+	artifactPath := filepath.Join(dir, command+".json")
+	summaryPath := filepath.Join(dir, command+".md")
+	if err := os.WriteFile(artifactPath, []byte(`{"passed":true}`), 0o644); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	summary := "# " + command + "\n\nStatus: PASS\n"
+	if err := os.WriteFile(summaryPath, []byte(summary), 0o644); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	return Report{
+		Command:        command,
+		Environment:    "staging",
+		ArtifactPath:   artifactPath,
+		SummaryPath:    summaryPath,
+		Summary:        summary,
+		Passed:         passed,
+		CompletedAtUTC: "2026-01-02T03:04:05Z",
+	}
+}
+
+func TestLoadConfigValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		raw       string
+		shouldErr bool
+	}{
+		{name: "all sinks", raw: `{"sinks":[{"kind":"filesystem","dir":"out"},{"kind":"s3","bucket":"reports","prefix":"ci"},{"kind":"github_pr_comment","output_path":"comment.md"},{"kind":"slack","webhook_url_env":"RSPP_SLACK_HOOK"}]}`},
+		{name: "empty sinks", raw: `{"sinks":[]}`, shouldErr: true},
+		{name: "unsupported kind", raw: `{"sinks":[{"kind":"email"}]}`, shouldErr: true},
+		{name: "s3 without bucket", raw: `{"sinks":[{"kind":"s3"}]}`, shouldErr: true},
+		{name: "pr comment without output", raw: `{"sinks":[{"kind":"github_pr_comment"}]}`, shouldErr: true},
+		{name: "slack webhook env outside RSPP namespace", raw: `{"sinks":[{"kind":"slack","webhook_url_env":"SLACK_URL"}]}`, shouldErr: true},
+		{name: "inline webhook url rejected", raw: `{"sinks":[{"kind":"slack","webhook_url":"https://hooks.slack.com/x"}]}`, shouldErr: true},
+	}
+	for _, tc := range tests {
+		path := filepath.Join(t.TempDir(), "sinks.json")
+		if err := os.WriteFile(path, []byte(tc.raw), 0o644); err != nil {
+			t.Fatalf("%s: unexpected write error: %v", tc.name, err)
+		}
+		cfg, err := LoadConfig(path)
+		if tc.shouldErr {
+			if err == nil {
+				t.Fatalf("%s: expected error", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		sinks, err := Build(cfg)
+		if err != nil || len(sinks) != len(cfg.Sinks) {
+			t.Fatalf("%s: expected %d sinks, got %d (%v)", tc.name, len(cfg.Sinks), len(sinks), err)
+		}
+	}
+}
+
+func TestFilesystemSinkCopiesArtifacts(t *testing.T) {
+	t.Parallel()
+
+	report := writeReportFiles(t, "slo-gates-report", true)
+	if err := (FilesystemSink{}).Publish(context.Background(), report); err != nil {
+		t.Fatalf("expected no-op without dir, got %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "published")
+	if err := (FilesystemSink{Dir: dir}).Publish(context.Background(), report); err != nil {
+		t.Fatalf("unexpected publish error: %v", err)
+	}
+	for _, name := range []string{"slo-gates-report.json", "slo-gates-report.md"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("expected %s copied: %v", name, err)
+		}
+	}
+}
+
+func TestGitHubPRCommentSinkUpsertsSections(t *testing.T) {
+	t.Parallel()
+
+	outputPath := filepath.Join(t.TempDir(), "pr-comment.md")
+	sink := GitHubPRCommentSink{OutputPath: outputPath}
+	slo := writeReportFiles(t, "slo-gates-report", true)
+	replay := writeReportFiles(t, "replay-regression-report", true)
+	for _, report := range []Report{slo, replay} {
+		if err := sink.Publish(context.Background(), report); err != nil {
+			t.Fatalf("unexpected publish error: %v", err)
+		}
+	}
+	slo.Passed = false
+	slo.Detail = "slo gates failed"
+	if err := sink.Publish(context.Background(), slo); err != nil {
+		t.Fatalf("unexpected republish error: %v", err)
+	}
+
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	body := string(raw)
+	if !strings.HasPrefix(body, prCommentHeader) {
+		t.Fatalf("expected comment header, got %q", body)
+	}
+	if strings.Count(body, "<!-- rspp-report:slo-gates-report -->") != 1 {
+		t.Fatalf("expected slo section replaced in place, got %q", body)
+	}
+	if !strings.Contains(body, "### slo-gates-report: FAIL") || strings.Contains(body, "### slo-gates-report: PASS") {
+		t.Fatalf("expected updated slo status, got %q", body)
+	}
+	if strings.Index(body, "slo-gates-report: FAIL") > strings.Index(body, "replay-regression-report: PASS") {
+		t.Fatalf("expected section order preserved, got %q", body)
+	}
+}
+
+func TestSlackSinkPostsSummary(t *testing.T) {
+	var payload slackPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	report := writeReportFiles(t, "llm-eval-report", false)
+	report.Detail = "llm eval gates failed"
+
+	t.Setenv("RSPP_TEST_SLACK_WEBHOOK", "")
+	sink := SlackSink{WebhookURLEnv: "RSPP_TEST_SLACK_WEBHOOK", Client: server.Client()}
+	if err := sink.Publish(context.Background(), report); err == nil {
+		t.Fatalf("expected error without webhook url")
+	}
+
+	t.Setenv("RSPP_TEST_SLACK_WEBHOOK", server.URL)
+	if err := sink.Publish(context.Background(), report); err != nil {
+		t.Fatalf("unexpected publish error: %v", err)
+	}
+	if !strings.Contains(payload.Text, "*llm-eval-report* FAIL (staging)") || !strings.Contains(payload.Text, "llm eval gates failed") {
+		t.Fatalf("unexpected slack payload: %+v", payload)
+	}
+}
+
+func TestSlackSinkRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Setenv("RSPP_TEST_SLACK_WEBHOOK", server.URL)
+	sink := SlackSink{
+		WebhookURLEnv: "RSPP_TEST_SLACK_WEBHOOK",
+		Client:        server.Client(),
+		Retry:         backoff.Policy{Base: time.Millisecond, Max: time.Millisecond},
+	}
+	if err := sink.Publish(context.Background(), writeReportFiles(t, "slo-gates-report", true)); err != nil {
+		t.Fatalf("unexpected publish error after retries: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected three deliveries, got %d", calls.Load())
+	}
+}
+
+type fakePutter struct {
+	keys []string
+	err  error
+}
+
+func (f *fakePutter) PutObject(_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.keys = append(f.keys, *params.Bucket+"/"+*params.Key)
+	return &s3.PutObjectOutput{}, nil
+}
+
+func TestS3SinkUploadsUnderEnvironmentAndCommand(t *testing.T) {
+	t.Parallel()
+
+	putter := &fakePutter{}
+	sink := &S3Sink{Bucket: "reports", Prefix: "/ci/", client: putter}
+	if err := sink.Publish(context.Background(), writeReportFiles(t, "slo-gates-report", true)); err != nil {
+		t.Fatalf("unexpected publish error: %v", err)
+	}
+	expected := "[reports/ci/staging/slo-gates-report/slo-gates-report.json reports/ci/staging/slo-gates-report/slo-gates-report.md]"
+	if got := strings.Join([]string{"[", strings.Join(putter.keys, " "), "]"}, ""); got != expected {
+		t.Fatalf("expected keys %s, got %s", expected, got)
+	}
+}
+
+func TestDispatchContinuesPastFailingSink(t *testing.T) {
+	t.Parallel()
+
+	report := writeReportFiles(t, "contract-coverage-report", true)
+	outputPath := filepath.Join(t.TempDir(), "pr-comment.md")
+	sinks := []Sink{
+		&S3Sink{Bucket: "reports", client: &fakePutter{err: errors.New("access denied")}},
+		GitHubPRCommentSink{OutputPath: outputPath},
+	}
+	err := Dispatch(context.Background(), sinks, report)
+	if err == nil || !strings.Contains(err.Error(), "report sink s3") {
+		t.Fatalf("expected s3 sink failure, got %v", err)
+	}
+	if _, statErr := os.Stat(outputPath); statErr != nil {
+		t.Fatalf("expected later sink to run: %v", statErr)
+	}
+}
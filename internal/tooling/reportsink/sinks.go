@@ -0,0 +1,216 @@
+package reportsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/backoff"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
+)
+
+// DefaultSlackWebhookURLEnv holds the Slack incoming-webhook URL when a slack sink does not
+// name its own variable.
+const DefaultSlackWebhookURLEnv = "RSPP_REPORT_SLACK_WEBHOOK_URL"
+
+// FilesystemSink copies the artifact and summary into Dir. With no Dir it is a no-op, which
+// is the historical behavior of leaving artifacts at the command output path.
+type FilesystemSink struct {
+	Dir string
+}
+
+// Name identifies the sink in dispatch errors.
+func (s FilesystemSink) Name() string {
+	return string(KindFilesystem)
+}
+
+// Publish copies report files into Dir.
+func (s FilesystemSink) Publish(_ context.Context, report Report) error {
+	if strings.TrimSpace(s.Dir) == "" {
+		return nil
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	for _, path := range reportFiles(report) {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read report file: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(s.Dir, filepath.Base(path)), raw, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GitHubPRCommentSink maintains a markdown PR comment body at OutputPath with one section
+// per gate command. Re-running a command replaces its section; CI posts the file.
+type GitHubPRCommentSink struct {
+	OutputPath string
+}
+
+const prCommentHeader = "<!-- rspp-report-sinks -->\n## RSPP gate reports"
+
+// Name identifies the sink in dispatch errors.
+func (s GitHubPRCommentSink) Name() string {
+	return string(KindGitHubPRComment)
+}
+
+// Publish upserts the report section in the comment body.
+func (s GitHubPRCommentSink) Publish(_ context.Context, report Report) error {
+	var existing string
+	raw, err := os.ReadFile(s.OutputPath)
+	switch {
+	case err == nil:
+		existing = string(raw)
+	case !os.IsNotExist(err):
+		return fmt.Errorf("read pr comment body: %w", err)
+	}
+	body := upsertCommentSection(existing, report.Command, renderCommentSection(report))
+	if err := os.MkdirAll(filepath.Dir(s.OutputPath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.OutputPath, []byte(body), 0o644)
+}
+
+func renderCommentSection(report Report) string {
+	lines := []string{fmt.Sprintf("### %s: %s", report.Command, report.Status())}
+	if report.Environment != "" {
+		lines = append(lines, "Environment: "+report.Environment)
+	}
+	lines = append(lines, "Artifact: `"+report.ArtifactPath+"`")
+	if report.Detail != "" {
+		lines = append(lines, "Detail: "+report.Detail)
+	}
+	if summary := strings.TrimSpace(report.Summary); summary != "" {
+		lines = append(lines, "", "<details><summary>Summary</summary>", "", summary, "", "</details>")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// upsertCommentSection replaces the marked section for command, or appends it, keeping
+// other sections in their original order.
+func upsertCommentSection(body, command, section string) string {
+	begin := "<!-- rspp-report:" + command + " -->"
+	end := "<!-- /rspp-report:" + command + " -->"
+	block := begin + "\n" + section + "\n" + end
+
+	if !strings.HasPrefix(body, prCommentHeader) {
+		return prCommentHeader + "\n\n" + block + "\n"
+	}
+	start := strings.Index(body, begin)
+	stop := strings.Index(body, end)
+	if start >= 0 && stop > start {
+		return body[:start] + block + body[stop+len(end):]
+	}
+	return strings.TrimRight(body, "\n") + "\n\n" + block + "\n"
+}
+
+// SlackSink posts a one-message gate summary to a Slack incoming webhook, retrying transport
+// errors and 5xx/429 responses.
+type SlackSink struct {
+	WebhookURLEnv string
+	Client        *http.Client
+	// Retry paces redelivery; a zero policy uses DefaultSlackRetryPolicy.
+	Retry backoff.Policy
+	// MaxAttempts caps deliveries per report. Zero means DefaultSlackMaxAttempts.
+	MaxAttempts int
+	// Clock waits retry delays; nil uses the real clock.
+	Clock clock.Clock
+}
+
+// DefaultSlackMaxAttempts is the default number of Slack summary deliveries.
+const DefaultSlackMaxAttempts = 3
+
+// DefaultSlackRetryPolicy returns the Slack summary redelivery pacing.
+func DefaultSlackRetryPolicy() backoff.Policy {
+	return backoff.Policy{Base: 200 * time.Millisecond, Max: 2 * time.Second, Jitter: backoff.JitterDecorrelated}
+}
+
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// Name identifies the sink in dispatch errors.
+func (s SlackSink) Name() string {
+	return string(KindSlack)
+}
+
+// Publish sends the summary and treats any non-2xx response as failure.
+func (s SlackSink) Publish(ctx context.Context, report Report) error {
+	env := strings.TrimSpace(s.WebhookURLEnv)
+	if env == "" {
+		env = DefaultSlackWebhookURLEnv
+	}
+	url := strings.TrimSpace(os.Getenv(env))
+	if url == "" {
+		return fmt.Errorf("%s is not set", env)
+	}
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	policy := s.Retry
+	if policy.Base <= 0 {
+		policy = DefaultSlackRetryPolicy()
+	}
+	maxAttempts := s.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = DefaultSlackMaxAttempts
+	}
+	body, err := json.Marshal(slackPayload{Text: renderSlackText(report)})
+	if err != nil {
+		return err
+	}
+	return policy.Normalize(DefaultSlackRetryPolicy().Base).Retry(ctx, s.Clock, report.Command+"|"+report.CompletedAtUTC, maxAttempts, func() (bool, error) {
+		return postSlack(ctx, client, url, body)
+	})
+}
+
+// postSlack makes one delivery and reports whether a failure is worth retrying.
+func postSlack(ctx context.Context, client *http.Client, url string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("build slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("send slack summary: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retryable, fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+func renderSlackText(report Report) string {
+	title := fmt.Sprintf("*%s* %s", report.Command, report.Status())
+	if report.Environment != "" {
+		title += " (" + report.Environment + ")"
+	}
+	lines := []string{title, "Artifact: " + report.ArtifactPath}
+	if report.Detail != "" {
+		lines = append(lines, report.Detail)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func reportFiles(report Report) []string {
+	files := []string{report.ArtifactPath}
+	if report.SummaryPath != "" {
+		files = append(files, report.SummaryPath)
+	}
+	return files
+}
@@ -0,0 +1,400 @@
+package runbook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/diagnostics"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/backoff"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/clock"
+)
+
+// DefaultDecisionsPath is the default ops decision artifact location.
+const DefaultDecisionsPath = ".codex/ops/runbook-decisions.json"
+
+// Mode guards whether matched actions are only proposed or actually executed.
+type Mode string
+
+const (
+	ModeSuggest Mode = "suggest"
+	ModeAuto    Mode = "auto"
+)
+
+// ActionKind identifies a runbook remediation.
+type ActionKind string
+
+const (
+	ActionOpenBreaker          ActionKind = "open_breaker"
+	ActionShiftProviderWeights ActionKind = "shift_provider_weights"
+	ActionScaleHintWebhook     ActionKind = "scale_hint_webhook"
+)
+
+// DecisionStatus records what happened to one matched action.
+type DecisionStatus string
+
+const (
+	StatusSuggested           DecisionStatus = "suggested"
+	StatusExecuted            DecisionStatus = "executed"
+	StatusFailed              DecisionStatus = "failed"
+	StatusExecutorUnavailable DecisionStatus = "executor_unavailable"
+	StatusAutoLimitReached    DecisionStatus = "auto_limit_reached"
+)
+
+// Action is one configured remediation bound to an SLO violation match.
+type Action struct {
+	ActionID string     `json:"action_id"`
+	Kind     ActionKind `json:"kind"`
+	// Match selects SLO gate violations containing this substring (case-insensitive).
+	Match      string             `json:"match"`
+	ProviderID string             `json:"provider_id,omitempty"`
+	Weights    map[string]float64 `json:"weights,omitempty"`
+	WebhookURL string             `json:"webhook_url,omitempty"`
+	// AllowAuto must be set per action for it to execute in auto mode.
+	AllowAuto bool `json:"allow_auto,omitempty"`
+}
+
+// Validate enforces action shape per kind.
+func (a Action) Validate() error {
+	if strings.TrimSpace(a.ActionID) == "" || strings.TrimSpace(a.Match) == "" {
+		return fmt.Errorf("runbook action_id and match are required")
+	}
+	switch a.Kind {
+	case ActionOpenBreaker:
+		if a.ProviderID == "" {
+			return fmt.Errorf("runbook action %s: open_breaker requires provider_id", a.ActionID)
+		}
+	case ActionShiftProviderWeights:
+		if len(a.Weights) == 0 {
+			return fmt.Errorf("runbook action %s: shift_provider_weights requires weights", a.ActionID)
+		}
+		total := 0.0
+		for providerID, weight := range a.Weights {
+			if providerID == "" || weight < 0 {
+				return fmt.Errorf("runbook action %s: weights require provider ids and values >=0", a.ActionID)
+			}
+			total += weight
+		}
+		if total <= 0 {
+			return fmt.Errorf("runbook action %s: weights must sum to >0", a.ActionID)
+		}
+	case ActionScaleHintWebhook:
+		if !strings.HasPrefix(a.WebhookURL, "http://") && !strings.HasPrefix(a.WebhookURL, "https://") {
+			return fmt.Errorf("runbook action %s: scale_hint_webhook requires http(s) webhook_url", a.ActionID)
+		}
+	default:
+		return fmt.Errorf("runbook action %s: unsupported kind %q", a.ActionID, a.Kind)
+	}
+	return nil
+}
+
+// Config is the operator runbook: a guard mode plus ordered actions.
+type Config struct {
+	Mode Mode `json:"mode"`
+	// MaxAutoActions caps executed actions per evaluation in auto mode. Zero means 1.
+	MaxAutoActions int      `json:"max_auto_actions,omitempty"`
+	Actions        []Action `json:"actions"`
+}
+
+// Validate enforces runbook configuration requirements.
+func (c Config) Validate() error {
+	if c.Mode != ModeSuggest && c.Mode != ModeAuto {
+		return fmt.Errorf("runbook mode must be one of suggest|auto")
+	}
+	if c.MaxAutoActions < 0 {
+		return fmt.Errorf("runbook max_auto_actions must be >=0")
+	}
+	if len(c.Actions) == 0 {
+		return fmt.Errorf("runbook requires at least one action")
+	}
+	seen := make(map[string]struct{}, len(c.Actions))
+	for _, action := range c.Actions {
+		if err := action.Validate(); err != nil {
+			return err
+		}
+		if _, ok := seen[action.ActionID]; ok {
+			return fmt.Errorf("duplicate runbook action_id: %s", action.ActionID)
+		}
+		seen[action.ActionID] = struct{}{}
+	}
+	return nil
+}
+
+// ValidateExecutors rejects auto-mode actions allowed to execute whose kind has no executor,
+// so a runbook cannot promise remediations the deployment cannot perform. Suggest-mode and
+// non-auto actions only propose the remediation and need no executor.
+func (c Config) ValidateExecutors(executors map[ActionKind]Executor) error {
+	if c.Mode != ModeAuto {
+		return nil
+	}
+	for _, action := range c.Actions {
+		if !action.AllowAuto {
+			continue
+		}
+		if executor, ok := executors[action.Kind]; !ok || executor == nil {
+			return fmt.Errorf("runbook action %s: no %s executor is available; set allow_auto=false to only suggest it", action.ActionID, action.Kind)
+		}
+	}
+	return nil
+}
+
+// LoadConfig reads and validates a runbook configuration.
+func LoadConfig(path string) (Config, error) {
+	trimmed := strings.TrimSpace(path)
+	if trimmed == "" {
+		return Config{}, fmt.Errorf("runbook config path is required")
+	}
+	raw, err := os.ReadFile(trimmed)
+	if err != nil {
+		return Config{}, fmt.Errorf("read runbook config %s: %w", trimmed, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("decode runbook config %s: %w", trimmed, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("validate runbook config %s: %w", trimmed, err)
+	}
+	return cfg, nil
+}
+
+// Executor applies one action kind. WebhookExecutor posts scale hints; AdminExecutor opens
+// breakers and shifts provider weights on a running runtime instance.
+type Executor interface {
+	Execute(ctx context.Context, action Action, violation string) error
+}
+
+// Decision is one ops decision artifact entry.
+type Decision struct {
+	DecisionID   string             `json:"decision_id"`
+	ActionID     string             `json:"action_id"`
+	Kind         ActionKind         `json:"kind"`
+	Mode         Mode               `json:"mode"`
+	Violation    string             `json:"violation"`
+	Status       DecisionStatus     `json:"status"`
+	ProviderID   string             `json:"provider_id,omitempty"`
+	Weights      map[string]float64 `json:"weights,omitempty"`
+	Error        string             `json:"error,omitempty"`
+	DecidedAtUTC string             `json:"decided_at_utc"`
+}
+
+// Evaluate matches SLO gate violations against the runbook and records one decision per
+// matched action. Each action fires at most once per evaluation, on its first matching
+// violation. In suggest mode nothing executes; in auto mode only AllowAuto actions with a
+// registered executor run, up to MaxAutoActions.
+func Evaluate(ctx context.Context, cfg Config, violations []string, executors map[ActionKind]Executor, now time.Time) ([]Decision, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	maxAuto := cfg.MaxAutoActions
+	if maxAuto == 0 {
+		maxAuto = 1
+	}
+	decidedAt := now.UTC().Format(time.RFC3339)
+
+	decisions := make([]Decision, 0)
+	executed := 0
+	for _, action := range cfg.Actions {
+		violation, ok := firstMatch(action.Match, violations)
+		if !ok {
+			continue
+		}
+		decision := Decision{
+			DecisionID:   fmt.Sprintf("%s-%d", action.ActionID, now.UnixMilli()),
+			ActionID:     action.ActionID,
+			Kind:         action.Kind,
+			Mode:         cfg.Mode,
+			Violation:    violation,
+			Status:       StatusSuggested,
+			ProviderID:   action.ProviderID,
+			Weights:      normalizedWeights(action.Weights),
+			DecidedAtUTC: decidedAt,
+		}
+		if cfg.Mode == ModeAuto && action.AllowAuto {
+			executor, ok := executors[action.Kind]
+			switch {
+			case !ok || executor == nil:
+				decision.Status = StatusExecutorUnavailable
+			case executed >= maxAuto:
+				decision.Status = StatusAutoLimitReached
+			default:
+				executed++
+				if err := executor.Execute(ctx, action, violation); err != nil {
+					decision.Status = StatusFailed
+					decision.Error = err.Error()
+				} else {
+					decision.Status = StatusExecuted
+				}
+			}
+		}
+		decisions = append(decisions, decision)
+	}
+	return decisions, nil
+}
+
+// WebhookExecutor posts scale hints to the action webhook URL, retrying transport errors and
+// 5xx/429 responses.
+type WebhookExecutor struct {
+	Client *http.Client
+	// Retry paces redelivery; a zero policy uses DefaultWebhookRetryPolicy.
+	Retry backoff.Policy
+	// MaxAttempts caps deliveries per hint. Zero means DefaultWebhookMaxAttempts.
+	MaxAttempts int
+	// Clock waits retry delays; nil uses the real clock.
+	Clock clock.Clock
+}
+
+// DefaultWebhookMaxAttempts is the default number of scale hint deliveries.
+const DefaultWebhookMaxAttempts = 3
+
+// DefaultWebhookRetryPolicy returns the scale hint redelivery pacing.
+func DefaultWebhookRetryPolicy() backoff.Policy {
+	return backoff.Policy{Base: 200 * time.Millisecond, Max: 2 * time.Second, Jitter: backoff.JitterDecorrelated}
+}
+
+type scaleHintPayload struct {
+	ActionID  string `json:"action_id"`
+	Violation string `json:"violation"`
+	Hint      string `json:"hint"`
+}
+
+// Execute sends the scale hint and treats any non-2xx response as failure.
+func (w WebhookExecutor) Execute(ctx context.Context, action Action, violation string) error {
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	policy := w.Retry
+	if policy.Base <= 0 {
+		policy = DefaultWebhookRetryPolicy()
+	}
+	maxAttempts := w.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = DefaultWebhookMaxAttempts
+	}
+	body, err := json.Marshal(scaleHintPayload{ActionID: action.ActionID, Violation: violation, Hint: "scale_out"})
+	if err != nil {
+		return err
+	}
+	return policy.Normalize(DefaultWebhookRetryPolicy().Base).Retry(ctx, w.Clock, action.ActionID+"|"+action.WebhookURL, maxAttempts, func() (bool, error) {
+		return postScaleHint(ctx, client, action.WebhookURL, body)
+	})
+}
+
+// postScaleHint makes one delivery and reports whether a failure is worth retrying.
+func postScaleHint(ctx context.Context, client *http.Client, url string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("build scale hint request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("send scale hint: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retryable, fmt.Errorf("scale hint webhook returned status %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+// DefaultAdminTimeout bounds each admin socket request of AdminExecutor.
+const DefaultAdminTimeout = 5 * time.Second
+
+// CatalogWeightScale is the integer total action weights are scaled to for the provider
+// catalog, whose weights are integers.
+const CatalogWeightScale = 100
+
+// AdminExecutor applies open_breaker and shift_provider_weights actions to a running
+// rspp-runtime instance through its admin socket. An opened breaker follows the runtime's
+// normal cooldown and half-open probe; a weight shift is written to the instance's provider
+// catalog file, so the instance must serve one.
+type AdminExecutor struct {
+	// SocketPath is the instance admin socket; empty uses diagnostics.AdminSocketPathFromEnv.
+	SocketPath string
+	// Timeout bounds each admin request. Zero means DefaultAdminTimeout.
+	Timeout time.Duration
+}
+
+// Execute applies the action and treats any admin socket error as failure.
+func (e AdminExecutor) Execute(ctx context.Context, action Action, _ string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	socketPath := e.SocketPath
+	if socketPath == "" {
+		socketPath = diagnostics.AdminSocketPathFromEnv()
+	}
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = DefaultAdminTimeout
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+	var err error
+	switch action.Kind {
+	case ActionOpenBreaker:
+		_, err = diagnostics.OpenBreaker(socketPath, timeout, action.ProviderID)
+	case ActionShiftProviderWeights:
+		_, err = diagnostics.ShiftProviderWeights(socketPath, timeout, CatalogWeights(action.Weights))
+	default:
+		return fmt.Errorf("admin executor cannot apply %s actions", action.Kind)
+	}
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", action.Kind, action.ActionID, err)
+	}
+	return nil
+}
+
+// CatalogWeights scales action weights to integer catalog weights summing to about
+// CatalogWeightScale. Weights are relative, so rounding keeps the candidate order except
+// between weights closer than 1/CatalogWeightScale of the total.
+func CatalogWeights(weights map[string]float64) map[string]int {
+	normalized := normalizedWeights(weights)
+	if normalized == nil {
+		return nil
+	}
+	out := make(map[string]int, len(normalized))
+	for providerID, weight := range normalized {
+		out[providerID] = int(math.Round(weight * CatalogWeightScale))
+	}
+	return out
+}
+
+func firstMatch(match string, violations []string) (string, bool) {
+	needle := strings.ToLower(strings.TrimSpace(match))
+	for _, violation := range violations {
+		if strings.Contains(strings.ToLower(violation), needle) {
+			return violation, true
+		}
+	}
+	return "", false
+}
+
+func normalizedWeights(weights map[string]float64) map[string]float64 {
+	if len(weights) == 0 {
+		return nil
+	}
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+	out := make(map[string]float64, len(weights))
+	for providerID, weight := range weights {
+		out[providerID] = weight / total
+	}
+	return out
+}
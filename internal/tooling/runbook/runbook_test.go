@@ -0,0 +1,253 @@
+package runbook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/diagnostics"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/backoff"
+)
+
+type recordingExecutor struct {
+	calls []string
+	err   error
+}
+
+func (r *recordingExecutor) Execute(_ context.Context, action Action, _ string) error {
+	r.calls = append(r.calls, action.ActionID)
+	return r.err
+}
+
+func testConfig(mode Mode) Config {
+	return Config{
+		Mode: mode,
+		Actions: []Action{
+			{ActionID: "breaker-llm-a", Kind: ActionOpenBreaker, Match: "first-output p95", ProviderID: "llm-a", AllowAuto: true},
+			{ActionID: "shift-llm", Kind: ActionShiftProviderWeights, Match: "first-output p95", Weights: map[string]float64{"llm-a": 1, "llm-b": 3}, AllowAuto: true},
+			{ActionID: "scale-hint", Kind: ActionScaleHintWebhook, Match: "turn-open p95", WebhookURL: "https://ops.example/scale"},
+		},
+	}
+}
+
+func TestEvaluateSuggestModeNeverExecutes(t *testing.T) {
+	t.Parallel()
+
+	executor := &recordingExecutor{}
+	violations := []string{"first-output p95=1800ms exceeds threshold=1500ms", "turn-open p95=140ms exceeds threshold=120ms"}
+	decisions, err := Evaluate(context.Background(), testConfig(ModeSuggest), violations, map[ActionKind]Executor{ActionOpenBreaker: executor}, time.UnixMilli(1000))
+	if err != nil {
+		t.Fatalf("unexpected evaluate error: %v", err)
+	}
+	if len(decisions) != 3 || len(executor.calls) != 0 {
+		t.Fatalf("expected 3 suggested decisions and no executions, got %+v calls=%v", decisions, executor.calls)
+	}
+	for _, decision := range decisions {
+		if decision.Status != StatusSuggested {
+			t.Fatalf("expected suggested status, got %+v", decision)
+		}
+	}
+	if decisions[1].Weights["llm-b"] != 0.75 {
+		t.Fatalf("expected normalized weights, got %+v", decisions[1].Weights)
+	}
+}
+
+func TestEvaluateAutoModeIsGuarded(t *testing.T) {
+	t.Parallel()
+
+	breaker := &recordingExecutor{}
+	weights := &recordingExecutor{err: errors.New("routing view unavailable")}
+	violations := []string{"first-output p95=1800ms exceeds threshold=1500ms", "turn-open p95=140ms exceeds threshold=120ms"}
+
+	cfg := testConfig(ModeAuto)
+	decisions, err := Evaluate(context.Background(), cfg, violations, map[ActionKind]Executor{ActionOpenBreaker: breaker, ActionShiftProviderWeights: weights}, time.UnixMilli(1000))
+	if err != nil {
+		t.Fatalf("unexpected evaluate error: %v", err)
+	}
+	if decisions[0].Status != StatusExecuted || decisions[1].Status != StatusAutoLimitReached || decisions[2].Status != StatusSuggested {
+		t.Fatalf("expected executed, auto-limited, and suggest-only decisions, got %+v", decisions)
+	}
+
+	cfg.MaxAutoActions = 2
+	decisions, err = Evaluate(context.Background(), cfg, violations, map[ActionKind]Executor{ActionOpenBreaker: breaker, ActionShiftProviderWeights: weights}, time.UnixMilli(2000))
+	if err != nil {
+		t.Fatalf("unexpected evaluate error: %v", err)
+	}
+	if decisions[1].Status != StatusFailed || decisions[1].Error == "" {
+		t.Fatalf("expected failed executor to be recorded, got %+v", decisions[1])
+	}
+
+	decisions, err = Evaluate(context.Background(), cfg, violations, nil, time.UnixMilli(3000))
+	if err != nil {
+		t.Fatalf("unexpected evaluate error: %v", err)
+	}
+	if decisions[0].Status != StatusExecutorUnavailable {
+		t.Fatalf("expected executor_unavailable without executors, got %+v", decisions[0])
+	}
+}
+
+func TestWebhookExecutorPostsScaleHint(t *testing.T) {
+	t.Parallel()
+
+	var payload scaleHintPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	action := Action{ActionID: "scale-hint", Kind: ActionScaleHintWebhook, Match: "p95", WebhookURL: server.URL}
+	if err := (WebhookExecutor{Client: server.Client()}).Execute(context.Background(), action, "turn-open p95 high"); err != nil {
+		t.Fatalf("unexpected webhook error: %v", err)
+	}
+	if payload.ActionID != "scale-hint" || payload.Hint != "scale_out" {
+		t.Fatalf("expected scale hint payload, got %+v", payload)
+	}
+}
+
+func TestAdminExecutorAppliesBreakerAndWeightActions(t *testing.T) {
+	t.Parallel()
+
+	opened := ""
+	socketPath := filepath.Join(t.TempDir(), "admin.sock")
+	admin, err := diagnostics.ListenAdmin(socketPath, diagnostics.Sources{Remediation: diagnostics.Remediation{
+		OpenBreaker: func(providerID string) bool {
+			opened = providerID
+			return true
+		},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected admin socket error: %v", err)
+	}
+	defer admin.Close()
+
+	executor := AdminExecutor{SocketPath: socketPath}
+	breaker := Action{ActionID: "breaker", Kind: ActionOpenBreaker, Match: "p95", ProviderID: "llm-a"}
+	if err := executor.Execute(context.Background(), breaker, "p95 high"); err != nil || opened != "llm-a" {
+		t.Fatalf("expected breaker opened on the runtime, got opened=%q err=%v", opened, err)
+	}
+	weights := Action{ActionID: "weights", Kind: ActionShiftProviderWeights, Match: "p95", Weights: map[string]float64{"llm-a": 1, "llm-b": 2}}
+	if err := executor.Execute(context.Background(), weights, "p95 high"); err == nil {
+		t.Fatalf("expected a runtime without a provider catalog to fail the weight shift, got %v", err)
+	}
+	scale := Action{ActionID: "scale", Kind: ActionScaleHintWebhook, Match: "p95", WebhookURL: "https://ops.example/scale"}
+	if err := executor.Execute(context.Background(), scale, "p95 high"); err == nil {
+		t.Fatalf("expected the admin executor to refuse webhook actions")
+	}
+}
+
+func TestCatalogWeightsScalesNormalizedWeights(t *testing.T) {
+	t.Parallel()
+
+	got := CatalogWeights(map[string]float64{"llm-a": 1, "llm-b": 2, "llm-c": 1})
+	if len(got) != 3 || got["llm-a"] != 25 || got["llm-b"] != 50 || got["llm-c"] != 25 {
+		t.Fatalf("expected weights scaled to %d, got %v", CatalogWeightScale, got)
+	}
+	if CatalogWeights(nil) != nil {
+		t.Fatalf("expected no weights for an empty action")
+	}
+}
+
+func TestWebhookExecutorRetriesTransientFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		statuses  []int
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "recovers after 503", statuses: []int{http.StatusServiceUnavailable, http.StatusAccepted}, wantCalls: 2},
+		{name: "gives up after max attempts", statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}, wantCalls: 3, wantErr: true},
+		{name: "client error is not retried", statuses: []int{http.StatusBadRequest}, wantCalls: 1, wantErr: true},
+	}
+	for _, tc := range tests {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.statuses[calls.Add(1)-1])
+		}))
+		executor := WebhookExecutor{
+			Client:      server.Client(),
+			Retry:       backoff.Policy{Base: time.Millisecond, Max: time.Millisecond},
+			MaxAttempts: 3,
+		}
+		action := Action{ActionID: "scale-hint", Kind: ActionScaleHintWebhook, Match: "p95", WebhookURL: server.URL}
+		err := executor.Execute(context.Background(), action, "turn-open p95 high")
+		server.Close()
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("%s: expected error=%t, got %v", tc.name, tc.wantErr, err)
+		}
+		if int(calls.Load()) != tc.wantCalls {
+			t.Fatalf("%s: expected %d deliveries, got %d", tc.name, tc.wantCalls, calls.Load())
+		}
+	}
+}
+
+func TestConfigValidateExecutorsRejectsAutoActionsWithoutExecutor(t *testing.T) {
+	t.Parallel()
+
+	executors := map[ActionKind]Executor{ActionScaleHintWebhook: WebhookExecutor{}}
+	tests := []struct {
+		name      string
+		cfg       Config
+		shouldErr bool
+	}{
+		{name: "suggest mode", cfg: testConfig(ModeSuggest)},
+		{name: "auto breaker without executor", cfg: testConfig(ModeAuto), shouldErr: true},
+		{name: "auto breaker only suggested", cfg: Config{Mode: ModeAuto, Actions: []Action{
+			{ActionID: "breaker", Kind: ActionOpenBreaker, Match: "p95", ProviderID: "llm-a"},
+		}}},
+		{name: "auto webhook", cfg: Config{Mode: ModeAuto, Actions: []Action{
+			{ActionID: "scale", Kind: ActionScaleHintWebhook, Match: "p95", WebhookURL: "https://ops.example/scale", AllowAuto: true},
+		}}},
+	}
+	for _, tc := range tests {
+		err := tc.cfg.ValidateExecutors(executors)
+		if tc.shouldErr != (err != nil) {
+			t.Fatalf("%s: expected error=%v, got %v", tc.name, tc.shouldErr, err)
+		}
+	}
+}
+
+func TestLoadConfigValidates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		raw       string
+		shouldErr bool
+	}{
+		{name: "valid", raw: `{"mode":"suggest","actions":[{"action_id":"a","kind":"open_breaker","match":"p95","provider_id":"stt-a"}]}`},
+		{name: "unknown mode", raw: `{"mode":"yolo","actions":[{"action_id":"a","kind":"open_breaker","match":"p95","provider_id":"stt-a"}]}`, shouldErr: true},
+		{name: "breaker without provider", raw: `{"mode":"auto","actions":[{"action_id":"a","kind":"open_breaker","match":"p95"}]}`, shouldErr: true},
+		{name: "webhook without url", raw: `{"mode":"auto","actions":[{"action_id":"a","kind":"scale_hint_webhook","match":"p95"}]}`, shouldErr: true},
+		{name: "unknown field", raw: `{"mode":"auto","extra":1,"actions":[]}`, shouldErr: true},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "runbook.json")
+			if err := os.WriteFile(path, []byte(tc.raw), 0o644); err != nil {
+				t.Fatalf("unexpected write error: %v", err)
+			}
+			_, err := LoadConfig(path)
+			if tc.shouldErr && err == nil {
+				t.Fatalf("expected config error, got nil")
+			}
+			if !tc.shouldErr && err != nil {
+				t.Fatalf("expected valid config, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package schemaregistry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kind identifies a versioned report artifact family.
+type Kind string
+
+const (
+	KindReplayRegressionReport Kind = "replay-regression-report"
+	KindSLOGatesReport         Kind = "slo-gates-report"
+	KindReleaseManifest        Kind = "release-manifest"
+)
+
+// legacyVersion is assumed for artifacts written before schema_version was embedded, where
+// the field is absent or empty.
+const legacyVersion = 1
+
+// Version renders the schema_version string for version n of kind, e.g. "slo-gates-report/v2".
+func Version(kind Kind, n int) string {
+	return fmt.Sprintf("%s/v%d", kind, n)
+}
+
+// Migration upgrades a decoded artifact by one schema version in place.
+type Migration func(artifact map[string]any) error
+
+// Schema declares the current version of an artifact kind and the migrations that reach it.
+// Migrations is keyed by the version it upgrades from.
+type Schema struct {
+	Kind       Kind
+	Current    int
+	Migrations map[int]Migration
+}
+
+// Registry upgrades report artifacts from supported older schema versions to the current one.
+type Registry struct {
+	schemas map[Kind]Schema
+}
+
+// NewRegistry validates that every schema can read at least its N-1 version.
+func NewRegistry(schemas ...Schema) (*Registry, error) {
+	registry := &Registry{schemas: make(map[Kind]Schema, len(schemas))}
+	for _, schema := range schemas {
+		if schema.Kind == "" {
+			return nil, fmt.Errorf("schema kind is required")
+		}
+		if _, exists := registry.schemas[schema.Kind]; exists {
+			return nil, fmt.Errorf("duplicate schema kind %q", schema.Kind)
+		}
+		if schema.Current < legacyVersion {
+			return nil, fmt.Errorf("schema %s current version must be >=%d", schema.Kind, legacyVersion)
+		}
+		if schema.Current > legacyVersion && schema.Migrations[schema.Current-1] == nil {
+			return nil, fmt.Errorf("schema %s requires a migration from v%d", schema.Kind, schema.Current-1)
+		}
+		registry.schemas[schema.Kind] = schema
+	}
+	return registry, nil
+}
+
+// Default returns the registry for the versioned gate and release artifacts. v1 is the layout
+// written before schema_version was embedded.
+func Default() *Registry {
+	registry, err := NewRegistry(
+		Schema{Kind: KindReplayRegressionReport, Current: 2, Migrations: map[int]Migration{1: stampVersionOnly}},
+		Schema{Kind: KindSLOGatesReport, Current: 2, Migrations: map[int]Migration{1: stampVersionOnly}},
+		Schema{Kind: KindReleaseManifest, Current: 2, Migrations: map[int]Migration{1: stampVersionOnly}},
+	)
+	if err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+// CurrentVersion returns the schema_version writers must embed for kind.
+func (r *Registry) CurrentVersion(kind Kind) (string, error) {
+	schema, ok := r.schemas[kind]
+	if !ok {
+		return "", fmt.Errorf("unknown artifact kind %q", kind)
+	}
+	return Version(kind, schema.Current), nil
+}
+
+// Upgraded is an artifact rewritten at the current schema version.
+type Upgraded struct {
+	Raw         []byte
+	FromVersion string
+	Migrated    bool
+}
+
+// Upgrade reads raw at any supported version of kind and returns it at the current version.
+// Current-version input is returned unchanged; versions older than the oldest migration or
+// newer than the current version are rejected.
+func (r *Registry) Upgrade(kind Kind, raw []byte) (Upgraded, error) {
+	schema, ok := r.schemas[kind]
+	if !ok {
+		return Upgraded{}, fmt.Errorf("unknown artifact kind %q", kind)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var artifact map[string]any
+	if err := dec.Decode(&artifact); err != nil {
+		return Upgraded{}, fmt.Errorf("decode %s artifact: %w", kind, err)
+	}
+	from, err := artifactVersion(kind, artifact)
+	if err != nil {
+		return Upgraded{}, err
+	}
+	if from > schema.Current {
+		return Upgraded{}, fmt.Errorf("%s schema v%d is newer than supported v%d", kind, from, schema.Current)
+	}
+	upgraded := Upgraded{Raw: raw, FromVersion: Version(kind, from)}
+	if from == schema.Current {
+		return upgraded, nil
+	}
+	for version := from; version < schema.Current; version++ {
+		migrate := schema.Migrations[version]
+		if migrate == nil {
+			return Upgraded{}, fmt.Errorf("%s schema v%d is no longer supported (current v%d)", kind, from, schema.Current)
+		}
+		if err := migrate(artifact); err != nil {
+			return Upgraded{}, fmt.Errorf("migrate %s v%d to v%d: %w", kind, version, version+1, err)
+		}
+	}
+	artifact["schema_version"] = Version(kind, schema.Current)
+	upgraded.Raw, err = json.Marshal(artifact)
+	if err != nil {
+		return Upgraded{}, err
+	}
+	upgraded.Migrated = true
+	return upgraded, nil
+}
+
+func artifactVersion(kind Kind, artifact map[string]any) (int, error) {
+	value, present := artifact["schema_version"]
+	if !present || value == "" {
+		return legacyVersion, nil
+	}
+	text, ok := value.(string)
+	if !ok {
+		return 0, fmt.Errorf("%s schema_version must be a string", kind)
+	}
+	prefix := string(kind) + "/v"
+	if !strings.HasPrefix(text, prefix) {
+		return 0, fmt.Errorf("schema_version %q is not a %s version", text, kind)
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(text, prefix))
+	if err != nil || version < legacyVersion {
+		return 0, fmt.Errorf("invalid %s schema_version %q", kind, text)
+	}
+	return version, nil
+}
+
+// stampVersionOnly upgrades a pre-versioning v1 artifact whose fields are unchanged in v2.
+func stampVersionOnly(map[string]any) error {
+	return nil
+}
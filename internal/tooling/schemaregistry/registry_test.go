@@ -0,0 +1,116 @@
+package schemaregistry
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDefaultUpgradesLegacyArtifacts(t *testing.T) {
+	t.Parallel()
+
+	registry := Default()
+	for _, kind := range []Kind{KindReplayRegressionReport, KindSLOGatesReport, KindReleaseManifest} {
+		current, err := registry.CurrentVersion(kind)
+		if err != nil || current != Version(kind, 2) {
+			t.Fatalf("%s: expected current v2, got %q err=%v", kind, current, err)
+		}
+		upgraded, err := registry.Upgrade(kind, []byte(`{"generated_at_utc":"2026-02-11T11:00:00Z","failing_count":9007199254740993}`))
+		if err != nil {
+			t.Fatalf("%s: unexpected upgrade error: %v", kind, err)
+		}
+		if !upgraded.Migrated || upgraded.FromVersion != Version(kind, 1) {
+			t.Fatalf("%s: expected v1 migration, got %+v", kind, upgraded)
+		}
+		if !strings.Contains(string(upgraded.Raw), `"schema_version":"`+current+`"`) || !strings.Contains(string(upgraded.Raw), "9007199254740993") {
+			t.Fatalf("%s: expected stamped artifact with preserved fields, got %s", kind, upgraded.Raw)
+		}
+
+		raw := []byte(`{"schema_version":"` + current + `","generated_at_utc":"2026-02-11T11:00:00Z"}`)
+		same, err := registry.Upgrade(kind, raw)
+		if err != nil || same.Migrated || string(same.Raw) != string(raw) {
+			t.Fatalf("%s: expected current artifact unchanged, got %+v err=%v", kind, same, err)
+		}
+	}
+}
+
+func TestUpgradeChainsMigrationsAndRejectsUnsupportedVersions(t *testing.T) {
+	t.Parallel()
+
+	registry, err := NewRegistry(Schema{Kind: KindSLOGatesReport, Current: 3, Migrations: map[int]Migration{
+		2: func(artifact map[string]any) error {
+			artifact["passed"] = artifact["ok"]
+			delete(artifact, "ok")
+			return nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected registry error: %v", err)
+	}
+
+	upgraded, err := registry.Upgrade(KindSLOGatesReport, []byte(`{"schema_version":"slo-gates-report/v2","ok":true}`))
+	if err != nil {
+		t.Fatalf("unexpected upgrade error: %v", err)
+	}
+	var artifact map[string]any
+	if err := json.Unmarshal(upgraded.Raw, &artifact); err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+	if artifact["passed"] != true || artifact["schema_version"] != "slo-gates-report/v3" || artifact["ok"] != nil {
+		t.Fatalf("expected N-1 artifact renamed and stamped, got %+v", artifact)
+	}
+
+	tests := []struct {
+		name string
+		kind Kind
+		raw  string
+	}{
+		{name: "N-2 legacy", kind: KindSLOGatesReport, raw: `{"ok":true}`},
+		{name: "newer than current", kind: KindSLOGatesReport, raw: `{"schema_version":"slo-gates-report/v4"}`},
+		{name: "other kind version", kind: KindSLOGatesReport, raw: `{"schema_version":"release-manifest/v3"}`},
+		{name: "non-string version", kind: KindSLOGatesReport, raw: `{"schema_version":3}`},
+		{name: "unknown kind", kind: KindReleaseManifest, raw: `{}`},
+		{name: "malformed json", kind: KindSLOGatesReport, raw: `{`},
+	}
+	for _, tc := range tests {
+		if _, err := registry.Upgrade(tc.kind, []byte(tc.raw)); err == nil {
+			t.Fatalf("%s: expected upgrade error", tc.name)
+		}
+	}
+}
+
+func TestNewRegistryRequiresNMinusOneMigration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		schemas   []Schema
+		shouldErr bool
+	}{
+		{name: "v1 needs no migration", schemas: []Schema{{Kind: KindReleaseManifest, Current: 1}}},
+		{name: "missing N-1 migration", schemas: []Schema{{Kind: KindReleaseManifest, Current: 3, Migrations: map[int]Migration{1: stampVersionOnly}}}, shouldErr: true},
+		{name: "duplicate kind", schemas: []Schema{{Kind: KindReleaseManifest, Current: 1}, {Kind: KindReleaseManifest, Current: 1}}, shouldErr: true},
+		{name: "zero version", schemas: []Schema{{Kind: KindReleaseManifest}}, shouldErr: true},
+	}
+	for _, tc := range tests {
+		_, err := NewRegistry(tc.schemas...)
+		if tc.shouldErr != (err != nil) {
+			t.Fatalf("%s: expected error=%t, got %v", tc.name, tc.shouldErr, err)
+		}
+	}
+}
+
+func TestMigrationErrorsAreWrapped(t *testing.T) {
+	t.Parallel()
+
+	registry, err := NewRegistry(Schema{Kind: KindReleaseManifest, Current: 2, Migrations: map[int]Migration{
+		1: func(map[string]any) error { return fmt.Errorf("missing release_id") },
+	}})
+	if err != nil {
+		t.Fatalf("unexpected registry error: %v", err)
+	}
+	if _, err := registry.Upgrade(KindReleaseManifest, []byte(`{}`)); err == nil || !strings.Contains(err.Error(), "migrate release-manifest v1 to v2: missing release_id") {
+		t.Fatalf("expected wrapped migration error, got %v", err)
+	}
+}
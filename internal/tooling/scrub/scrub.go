@@ -0,0 +1,217 @@
+package scrub
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+)
+
+// EnvSalt optionally pins the tokenization salt so separately scrubbed artifacts share tokens.
+// Without it every run draws a fresh salt and tokens cannot be linked across shares.
+const EnvSalt = "RSPP_SCRUB_SALT"
+
+// minRewriteLength keeps very short identifier values from being rewritten inside unrelated
+// strings.
+const minRewriteLength = 3
+
+// identifierKinds maps normalized field names to the token prefix for their values.
+var identifierKinds = map[string]string{
+	"tenant":            "tenant",
+	"tenantid":          "tenant",
+	"tenants":           "tenant",
+	"sessionid":         "session",
+	"turnid":            "turn",
+	"callsid":           "call",
+	"streamsid":         "stream",
+	"userid":            "user",
+	"accountid":         "account",
+	"callerid":          "caller",
+	"providersessionid": "provider_session",
+}
+
+// payloadClasses maps normalized field names carrying customer content to the payload class
+// reported when the enclosing object does not declare one.
+var payloadClasses = map[string]eventabi.PayloadClass{
+	"transcript":       eventabi.PayloadTextRaw,
+	"text":             eventabi.PayloadTextRaw,
+	"reply":            eventabi.PayloadTextRaw,
+	"prompt":           eventabi.PayloadTextRaw,
+	"utterance":        eventabi.PayloadTextRaw,
+	"content":          eventabi.PayloadTextRaw,
+	"payload":          eventabi.PayloadTextRaw,
+	"audio":            eventabi.PayloadAudioRaw,
+	"pcm":              eventabi.PayloadAudioRaw,
+	"summary":          eventabi.PayloadDerivedSummary,
+	"envelopesnapshot": eventabi.PayloadMetadata,
+	"metadata":         eventabi.PayloadMetadata,
+	"sessionmetadata":  eventabi.PayloadMetadata,
+}
+
+// Config configures one scrub run.
+type Config struct {
+	// Salt keys token and payload hashes; it must be non-empty and is never written out.
+	Salt []byte
+}
+
+// Report counts what a scrub run changed.
+type Report struct {
+	Identifiers       int `json:"identifiers"`
+	TokenizedFields   int `json:"tokenized_fields"`
+	HashedPayloads    int `json:"hashed_payloads"`
+	RewrittenStrings  int `json:"rewritten_strings"`
+	PreservedNumerics int `json:"preserved_numerics"`
+}
+
+// JSON scrubs a debug bundle, replay artifact, or any other JSON artifact for external
+// sharing. Identifier fields are replaced by salted tokens (stable within the run, so turns
+// still correlate) and identifier values embedded in other strings such as event ids and paths
+// are rewritten to the same tokens; customer payload fields are reduced to their payload class
+// and a salted hash; numbers, including every timestamp and latency, are kept as-is.
+func JSON(data []byte, cfg Config) ([]byte, Report, error) {
+	if len(cfg.Salt) == 0 {
+		return nil, Report{}, fmt.Errorf("scrub salt is required")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var root any
+	if err := decoder.Decode(&root); err != nil {
+		return nil, Report{}, fmt.Errorf("decode artifact: %w", err)
+	}
+
+	s := &scrubber{salt: cfg.Salt, tokens: make(map[string]string)}
+	s.collect(root, "")
+	values := make([]string, 0, len(s.tokens))
+	for value := range s.tokens {
+		if len(value) >= minRewriteLength {
+			values = append(values, value)
+		}
+	}
+	// Longest first, so an id that contains another id is rewritten whole.
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+		return values[i] < values[j]
+	})
+	replacements := make([]string, 0, 2*len(values))
+	for _, value := range values {
+		replacements = append(replacements, value, s.tokens[value])
+	}
+	s.replacer = strings.NewReplacer(replacements...)
+	s.report.Identifiers = len(s.tokens)
+
+	out, err := json.MarshalIndent(s.scrub(root, "", ""), "", "  ")
+	if err != nil {
+		return nil, Report{}, err
+	}
+	return append(out, '\n'), s.report, nil
+}
+
+type scrubber struct {
+	salt     []byte
+	tokens   map[string]string
+	replacer *strings.Replacer
+	report   Report
+}
+
+// collect assigns a token to every identifier value in the tree.
+func (s *scrubber) collect(node any, key string) {
+	switch value := node.(type) {
+	case map[string]any:
+		for childKey, child := range value {
+			s.collect(child, normalizeKey(childKey))
+		}
+	case []any:
+		for _, child := range value {
+			s.collect(child, key)
+		}
+	case string:
+		if kind, ok := identifierKinds[key]; ok && value != "" {
+			if _, seen := s.tokens[value]; !seen {
+				s.tokens[value] = kind + "_tok_" + s.digest("id", value)[:12]
+			}
+		}
+	}
+}
+
+// scrub returns the scrubbed copy of node. key is the normalized field name node sits under
+// and class the payload class declared by the enclosing object, if any.
+func (s *scrubber) scrub(node any, key string, class eventabi.PayloadClass) any {
+	if defaultClass, ok := payloadClasses[key]; ok && node != nil && node != "" {
+		if class == "" {
+			class = defaultClass
+		}
+		return s.hashPayload(node, class)
+	}
+	switch value := node.(type) {
+	case map[string]any:
+		declared := eventabi.PayloadClass("")
+		for childKey, child := range value {
+			if normalizeKey(childKey) == "payloadclass" {
+				if name, ok := child.(string); ok {
+					declared = eventabi.PayloadClass(name)
+				}
+			}
+		}
+		out := make(map[string]any, len(value))
+		for childKey, child := range value {
+			out[s.rewrite(childKey)] = s.scrub(child, normalizeKey(childKey), declared)
+		}
+		return out
+	case []any:
+		out := make([]any, len(value))
+		for i, child := range value {
+			out[i] = s.scrub(child, key, class)
+		}
+		return out
+	case string:
+		if _, ok := identifierKinds[key]; ok && value != "" {
+			s.report.TokenizedFields++
+			return s.tokens[value]
+		}
+		return s.rewrite(value)
+	case json.Number:
+		s.report.PreservedNumerics++
+		return value
+	default:
+		return value
+	}
+}
+
+// rewrite replaces identifier values embedded in a free-form string with their tokens.
+func (s *scrubber) rewrite(value string) string {
+	rewritten := s.replacer.Replace(value)
+	if rewritten != value {
+		s.report.RewrittenStrings++
+	}
+	return rewritten
+}
+
+// hashPayload reduces a payload value to "<payload_class>:<salted hash>".
+func (s *scrubber) hashPayload(node any, class eventabi.PayloadClass) string {
+	raw, ok := node.(string)
+	if !ok {
+		encoded, _ := json.Marshal(node)
+		raw = string(encoded)
+	}
+	s.report.HashedPayloads++
+	return string(class) + ":" + s.digest("payload", raw)[:16]
+}
+
+func (s *scrubber) digest(domain string, value string) string {
+	mac := hmac.New(sha256.New, s.salt)
+	mac.Write([]byte(domain + "\x00" + value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// normalizeKey folds snake_case, kebab-case, and Go field names onto one lookup key.
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+}
@@ -0,0 +1,135 @@
+package scrub
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const bundleFixture = `{
+  "generated_at_utc": "2026-01-02T03:04:05Z",
+  "baseline_artifact_path": ".codex/replay/tenant-acme/runtime-baseline.json",
+  "session_id": "sess-acme-1",
+  "tenant_id": "tenant-acme",
+  "baseline": [
+    {
+      "SessionID": "sess-acme-1",
+      "TurnID": "turn-7",
+      "EventID": "evt-sess-acme-1-turn-7",
+      "TurnOpenAtMS": 1200,
+      "FirstOutputAtMS": 1840,
+      "FallbackUtterance": {"Text": "Sorry, one moment please.", "PayloadClass": "PII"},
+      "SessionMetadata": {"crm_id": "cust-991"}
+    }
+  ],
+  "decisions": [
+    {"session_id": "sess-acme-1", "turn_id": "turn-7", "runtime_timestamp_ms": 1210, "reason": "admission ok for tenant-acme"}
+  ],
+  "transcript": "my card number is 4111",
+  "reply": ""
+}`
+
+func TestJSONTokenizesIdentifiersAndHashesPayloads(t *testing.T) {
+	t.Parallel()
+
+	out, report, err := JSON([]byte(bundleFixture), Config{Salt: []byte("salt-a")})
+	if err != nil {
+		t.Fatalf("unexpected scrub error: %v", err)
+	}
+	text := string(out)
+	for _, leaked := range []string{"sess-acme-1", "tenant-acme", "turn-7", "4111", "Sorry, one moment", "cust-991"} {
+		if strings.Contains(text, leaked) {
+			t.Fatalf("expected %q to be scrubbed, got\n%s", leaked, text)
+		}
+	}
+
+	var scrubbed struct {
+		BaselineArtifactPath string `json:"baseline_artifact_path"`
+		SessionID            string `json:"session_id"`
+		TenantID             string `json:"tenant_id"`
+		Transcript           string `json:"transcript"`
+		Reply                string `json:"reply"`
+		Baseline             []struct {
+			SessionID         string
+			EventID           string
+			TurnOpenAtMS      int64
+			FirstOutputAtMS   int64
+			FallbackUtterance struct {
+				Text         string
+				PayloadClass string
+			}
+			SessionMetadata string
+		} `json:"baseline"`
+		Decisions []struct {
+			TurnID             string `json:"turn_id"`
+			RuntimeTimestampMS int64  `json:"runtime_timestamp_ms"`
+			Reason             string `json:"reason"`
+		} `json:"decisions"`
+	}
+	if err := json.Unmarshal(out, &scrubbed); err != nil {
+		t.Fatalf("expected scrubbed output to stay valid JSON: %v", err)
+	}
+	entry := scrubbed.Baseline[0]
+	if !strings.HasPrefix(scrubbed.SessionID, "session_tok_") || entry.SessionID != scrubbed.SessionID || !strings.HasPrefix(scrubbed.TenantID, "tenant_tok_") {
+		t.Fatalf("expected consistent identifier tokens, got session=%q entry=%q tenant=%q", scrubbed.SessionID, entry.SessionID, scrubbed.TenantID)
+	}
+	if entry.EventID != "evt-"+scrubbed.SessionID+"-"+scrubbed.Decisions[0].TurnID || scrubbed.BaselineArtifactPath != ".codex/replay/"+scrubbed.TenantID+"/runtime-baseline.json" {
+		t.Fatalf("expected embedded identifiers to be rewritten to tokens, got event=%q path=%q", entry.EventID, scrubbed.BaselineArtifactPath)
+	}
+	if scrubbed.Decisions[0].Reason != "admission ok for "+scrubbed.TenantID {
+		t.Fatalf("expected free-form text to rewrite identifiers, got %q", scrubbed.Decisions[0].Reason)
+	}
+	if entry.TurnOpenAtMS != 1200 || entry.FirstOutputAtMS != 1840 || scrubbed.Decisions[0].RuntimeTimestampMS != 1210 {
+		t.Fatalf("expected timing to be preserved, got %+v %+v", entry, scrubbed.Decisions[0])
+	}
+	if !strings.HasPrefix(scrubbed.Transcript, "text_raw:") || !strings.HasPrefix(entry.FallbackUtterance.Text, "PII:") || !strings.HasPrefix(entry.SessionMetadata, "metadata:") {
+		t.Fatalf("expected payloads reduced to class and hash, got transcript=%q text=%q metadata=%q", scrubbed.Transcript, entry.FallbackUtterance.Text, entry.SessionMetadata)
+	}
+	if scrubbed.Reply != "" {
+		t.Fatalf("expected empty payloads to stay empty, got %q", scrubbed.Reply)
+	}
+	if report.Identifiers != 3 || report.TokenizedFields != 6 || report.HashedPayloads != 3 || report.PreservedNumerics != 3 || report.RewrittenStrings != 3 {
+		t.Fatalf("unexpected scrub report: %+v", report)
+	}
+}
+
+func TestJSONTokensDependOnSalt(t *testing.T) {
+	t.Parallel()
+
+	first, _, err := JSON([]byte(bundleFixture), Config{Salt: []byte("salt-a")})
+	if err != nil {
+		t.Fatalf("unexpected scrub error: %v", err)
+	}
+	again, _, err := JSON([]byte(bundleFixture), Config{Salt: []byte("salt-a")})
+	if err != nil {
+		t.Fatalf("unexpected scrub error: %v", err)
+	}
+	other, _, err := JSON([]byte(bundleFixture), Config{Salt: []byte("salt-b")})
+	if err != nil {
+		t.Fatalf("unexpected scrub error: %v", err)
+	}
+	if string(first) != string(again) {
+		t.Fatalf("expected the same salt to scrub deterministically")
+	}
+	if string(first) == string(other) {
+		t.Fatalf("expected a different salt to produce different tokens")
+	}
+}
+
+func TestJSONRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data string
+		salt string
+	}{
+		{name: "missing salt", data: bundleFixture},
+		{name: "invalid json", data: "{", salt: "salt-a"},
+	}
+	for _, tc := range tests {
+		if _, _, err := JSON([]byte(tc.data), Config{Salt: []byte(tc.salt)}); err == nil {
+			t.Fatalf("%s: expected scrub error", tc.name)
+		}
+	}
+}
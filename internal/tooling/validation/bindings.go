@@ -0,0 +1,131 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+// Binding finding codes reported by ValidateSpecBindings, alongside SpecFindingInvalidNode.
+const (
+	SpecFindingProviderUnavailable  = "provider_unavailable"
+	SpecFindingProviderDisabled     = "provider_disabled"
+	SpecFindingStreamingUnsupported = "streaming_unsupported"
+)
+
+// Binding resolution statuses recorded per node.
+const (
+	BindingStatusAvailable   = "available"
+	BindingStatusDisabled    = "disabled"
+	BindingStatusUnavailable = "unavailable"
+)
+
+// BindingCatalog is the registered provider set bindings resolve against, e.g. registry.Catalog.
+type BindingCatalog interface {
+	Adapter(modality contracts.Modality, providerID string) (contracts.Adapter, bool)
+}
+
+// DisabledProvider is a known provider left out of the catalog because its enable flag is off.
+type DisabledProvider struct {
+	ProviderID string             `json:"provider_id"`
+	Modality   contracts.Modality `json:"modality"`
+	EnableEnv  string             `json:"enable_env"`
+}
+
+// NodeBinding records how one spec node's provider binding resolved.
+type NodeBinding struct {
+	NodeID            string             `json:"node_id"`
+	Modality          contracts.Modality `json:"modality"`
+	ProviderID        string             `json:"provider_id"`
+	Status            string             `json:"status"`
+	RequiresStreaming bool               `json:"requires_streaming,omitempty"`
+	Streaming         bool               `json:"streaming"`
+}
+
+// BindingValidationReport reports provider binding resolution for every node of a spec.
+type BindingValidationReport struct {
+	PipelineVersion string        `json:"pipeline_version"`
+	Nodes           int           `json:"nodes"`
+	StreamingNodes  int           `json:"streaming_nodes"`
+	Bindings        []NodeBinding `json:"bindings,omitempty"`
+	Findings        []SpecFinding `json:"findings,omitempty"`
+	Passed          bool          `json:"passed"`
+}
+
+// ValidateSpecBindings dry-resolves each node's provider binding against the registered
+// catalog. A binding fails when its provider is not registered for the node modality (with a
+// distinct finding when the provider is only disabled by its enable flag) or when the node
+// requires streaming the adapter cannot provide. It returns an error only when the spec
+// identity itself is malformed.
+func ValidateSpecBindings(spec PipelineSpec, catalog BindingCatalog, disabled []DisabledProvider) (BindingValidationReport, error) {
+	if catalog == nil {
+		return BindingValidationReport{}, fmt.Errorf("binding catalog is required")
+	}
+	if err := validateSpecIdentity(spec); err != nil {
+		return BindingValidationReport{}, err
+	}
+
+	report := BindingValidationReport{PipelineVersion: spec.PipelineVersion, Nodes: len(spec.Nodes)}
+	seen := make(map[string]struct{}, len(spec.Nodes))
+	for _, node := range spec.Nodes {
+		finding := SpecFinding{NodeID: node.NodeID, ProviderID: node.ProviderID}
+		if err := validateSpecNode(node, seen); err != nil {
+			finding.Code, finding.Detail = SpecFindingInvalidNode, err.Error()
+			report.Findings = append(report.Findings, finding)
+			continue
+		}
+		seen[node.NodeID] = struct{}{}
+		if node.RequiresStreaming {
+			report.StreamingNodes++
+		}
+
+		binding := NodeBinding{NodeID: node.NodeID, Modality: node.Modality, ProviderID: node.ProviderID, RequiresStreaming: node.RequiresStreaming}
+		adapter, ok := catalog.Adapter(node.Modality, node.ProviderID)
+		flag := disabledProvider(disabled, node)
+		switch {
+		case ok:
+			binding.Status = BindingStatusAvailable
+			_, binding.Streaming = adapter.(contracts.StreamingAdapter)
+			if node.RequiresStreaming && !binding.Streaming {
+				finding.Code = SpecFindingStreamingUnsupported
+				finding.Detail = fmt.Sprintf("node requires streaming but %s adapter %s only supports unary invocation", node.Modality, node.ProviderID)
+				report.Findings = append(report.Findings, finding)
+			}
+		case flag != nil:
+			binding.Status = BindingStatusDisabled
+			finding.Code = SpecFindingProviderDisabled
+			finding.Detail = fmt.Sprintf("%s provider %s is disabled; set %s to enable it", node.Modality, node.ProviderID, flag.EnableEnv)
+			report.Findings = append(report.Findings, finding)
+		default:
+			binding.Status = BindingStatusUnavailable
+			finding.Code = SpecFindingProviderUnavailable
+			finding.Detail = fmt.Sprintf("no %s provider %s registered in the catalog", node.Modality, node.ProviderID)
+			report.Findings = append(report.Findings, finding)
+		}
+		report.Bindings = append(report.Bindings, binding)
+	}
+	report.Passed = len(report.Findings) == 0
+	return report, nil
+}
+
+// RenderBindingSummary renders a one-line-per-finding binding validation summary.
+func RenderBindingSummary(report BindingValidationReport) string {
+	lines := []string{fmt.Sprintf("pipeline spec %s bindings: nodes=%d streaming=%d findings=%d", report.PipelineVersion, report.Nodes, report.StreamingNodes, len(report.Findings))}
+	if len(report.Findings) > 0 {
+		lines = append(lines, "findings:")
+		for _, finding := range report.Findings {
+			lines = append(lines, fmt.Sprintf("- %s %s (%s): %s", finding.NodeID, finding.Code, finding.ProviderID, finding.Detail))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func disabledProvider(disabled []DisabledProvider, node SpecNode) *DisabledProvider {
+	for i := range disabled {
+		if disabled[i].ProviderID == node.ProviderID && disabled[i].Modality == node.Modality {
+			return &disabled[i]
+		}
+	}
+	return nil
+}
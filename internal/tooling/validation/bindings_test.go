@@ -0,0 +1,122 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+)
+
+// unaryAdapter implements Invoke only, without the streaming extension.
+type unaryAdapter struct {
+	id   string
+	mode contracts.Modality
+}
+
+func (a unaryAdapter) ProviderID() string           { return a.id }
+func (a unaryAdapter) Modality() contracts.Modality { return a.mode }
+func (a unaryAdapter) Invoke(contracts.InvocationRequest) (contracts.Outcome, error) {
+	return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+}
+
+func TestValidateSpecBindings(t *testing.T) {
+	t.Parallel()
+
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{ID: "stt-deepgram", Mode: contracts.ModalitySTT},
+		unaryAdapter{id: "llm-gemini", mode: contracts.ModalityLLM},
+		contracts.StaticAdapter{ID: "tts-elevenlabs", Mode: contracts.ModalityTTS},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	disabled := []DisabledProvider{{ProviderID: "tts-azure-speech", Modality: contracts.ModalityTTS, EnableEnv: "RSPP_TTS_AZURE_ENABLE"}}
+	specWith := func(nodes ...SpecNode) PipelineSpec {
+		return PipelineSpec{PipelineVersion: "pipeline-v1", GraphDefinitionRef: "graph/default", ExecutionProfile: "simple", Nodes: nodes}
+	}
+	tests := []struct {
+		name         string
+		spec         PipelineSpec
+		wantCodes    []string
+		wantStatuses []string
+	}{
+		{name: "registered bindings pass", spec: specWith(SpecNode{NodeID: "stt", Modality: contracts.ModalitySTT, ProviderID: "stt-deepgram", RequiresStreaming: true}, SpecNode{NodeID: "llm", Modality: contracts.ModalityLLM, ProviderID: "llm-gemini"}), wantStatuses: []string{BindingStatusAvailable, BindingStatusAvailable}},
+		{name: "streaming required from unary adapter", spec: specWith(SpecNode{NodeID: "llm", Modality: contracts.ModalityLLM, ProviderID: "llm-gemini", RequiresStreaming: true}), wantCodes: []string{SpecFindingStreamingUnsupported}, wantStatuses: []string{BindingStatusAvailable}},
+		{name: "disabled provider", spec: specWith(SpecNode{NodeID: "tts", Modality: contracts.ModalityTTS, ProviderID: "tts-azure-speech"}), wantCodes: []string{SpecFindingProviderDisabled}, wantStatuses: []string{BindingStatusDisabled}},
+		{name: "unknown provider", spec: specWith(SpecNode{NodeID: "tts", Modality: contracts.ModalityTTS, ProviderID: "tts-unknown"}), wantCodes: []string{SpecFindingProviderUnavailable}, wantStatuses: []string{BindingStatusUnavailable}},
+		{name: "provider bound to wrong modality", spec: specWith(SpecNode{NodeID: "tts", Modality: contracts.ModalityTTS, ProviderID: "stt-deepgram"}), wantCodes: []string{SpecFindingProviderUnavailable}, wantStatuses: []string{BindingStatusUnavailable}},
+		{name: "invalid node", spec: specWith(SpecNode{NodeID: "stt", Modality: contracts.ModalitySTT}), wantCodes: []string{SpecFindingInvalidNode}},
+	}
+	for _, tc := range tests {
+		report, err := ValidateSpecBindings(tc.spec, catalog, disabled)
+		if err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+		codes := make([]string, 0, len(report.Findings))
+		for _, finding := range report.Findings {
+			codes = append(codes, finding.Code)
+		}
+		statuses := make([]string, 0, len(report.Bindings))
+		for _, binding := range report.Bindings {
+			statuses = append(statuses, binding.Status)
+		}
+		if strings.Join(codes, ",") != strings.Join(tc.wantCodes, ",") || report.Passed != (len(tc.wantCodes) == 0) {
+			t.Fatalf("%s: expected findings %v, got\n%s", tc.name, tc.wantCodes, RenderBindingSummary(report))
+		}
+		if strings.Join(statuses, ",") != strings.Join(tc.wantStatuses, ",") {
+			t.Fatalf("%s: expected binding statuses %v, got %v", tc.name, tc.wantStatuses, statuses)
+		}
+	}
+}
+
+func TestValidateSpecBindingsReportsStreamingCapability(t *testing.T) {
+	t.Parallel()
+
+	catalog, err := registry.NewCatalog([]contracts.Adapter{
+		contracts.StaticAdapter{ID: "stt-deepgram", Mode: contracts.ModalitySTT},
+		unaryAdapter{id: "llm-gemini", mode: contracts.ModalityLLM},
+	})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	report, err := ValidateSpecBindings(PipelineSpec{
+		PipelineVersion:    "pipeline-v1",
+		GraphDefinitionRef: "graph/default",
+		ExecutionProfile:   "simple",
+		Nodes: []SpecNode{
+			{NodeID: "stt", Modality: contracts.ModalitySTT, ProviderID: "stt-deepgram", RequiresStreaming: true},
+			{NodeID: "llm", Modality: contracts.ModalityLLM, ProviderID: "llm-gemini"},
+		},
+	}, catalog, nil)
+	if err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	if report.StreamingNodes != 1 || !report.Bindings[0].Streaming || report.Bindings[1].Streaming {
+		t.Fatalf("expected per-binding streaming capability, got %+v", report)
+	}
+}
+
+func TestValidateSpecBindingsRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	catalog, err := registry.NewCatalog([]contracts.Adapter{contracts.StaticAdapter{ID: "stt-deepgram", Mode: contracts.ModalitySTT}})
+	if err != nil {
+		t.Fatalf("unexpected catalog error: %v", err)
+	}
+	node := SpecNode{NodeID: "stt", Modality: contracts.ModalitySTT, ProviderID: "stt-deepgram"}
+	tests := []struct {
+		name    string
+		spec    PipelineSpec
+		catalog BindingCatalog
+	}{
+		{name: "missing catalog", spec: PipelineSpec{PipelineVersion: "pipeline-v1", GraphDefinitionRef: "graph/default", ExecutionProfile: "simple", Nodes: []SpecNode{node}}},
+		{name: "missing identity", spec: PipelineSpec{Nodes: []SpecNode{node}}, catalog: catalog},
+		{name: "no nodes", spec: PipelineSpec{PipelineVersion: "pipeline-v1", GraphDefinitionRef: "graph/default", ExecutionProfile: "simple"}, catalog: catalog},
+	}
+	for _, tc := range tests {
+		if _, err := ValidateSpecBindings(tc.spec, tc.catalog, nil); err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/santhosh-tekuri/jsonschema/v5"
 	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/identifiers"
 )
 
 // ContractValidationSummary reports fixture validation totals.
@@ -41,6 +42,9 @@ func ValidateContractFixturesWithSchema(schemaPath, root string) (ContractValida
 	}
 
 	summary := ContractValidationSummary{}
+	// Valid fixtures stand in for one evidence corpus: an event_id minted twice would make
+	// joins across artifacts ambiguous.
+	eventIDs := identifiers.NewIndex()
 	compiled, err := compileSchema(schemaPath)
 	if err != nil {
 		return summary, err
@@ -83,6 +87,11 @@ func ValidateContractFixturesWithSchema(schemaPath, root string) (ContractValida
 					if typedErr != nil || schemaErr != nil {
 						summary.Failed++
 						summary.Failures = append(summary.Failures, fmt.Sprintf("%s: expected valid, typed_err=%v schema_err=%v", filePath, typedErr, schemaErr))
+						continue
+					}
+					if err := claimEventID(eventIDs, raw, filePath); err != nil {
+						summary.Failed++
+						summary.Failures = append(summary.Failures, fmt.Sprintf("%s: %v", filePath, err))
 					}
 					continue
 				}
@@ -98,6 +107,19 @@ func ValidateContractFixturesWithSchema(schemaPath, root string) (ContractValida
 	return summary, nil
 }
 
+func claimEventID(index *identifiers.Index, raw []byte, owner string) error {
+	var ids struct {
+		EventID string `json:"event_id"`
+	}
+	if err := json.Unmarshal(raw, &ids); err != nil {
+		return err
+	}
+	if ids.EventID == "" {
+		return nil
+	}
+	return index.Claim(identifiers.KindEvent, ids.EventID, owner)
+}
+
 func compileSchema(schemaPath string) (*jsonschema.Schema, error) {
 	absSchemaPath, err := filepath.Abs(schemaPath)
 	if err != nil {
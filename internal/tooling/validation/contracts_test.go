@@ -1,7 +1,9 @@
 package validation
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -21,3 +23,35 @@ func TestValidateContractFixtures(t *testing.T) {
 		t.Fatalf("expected zero failures, got %d\n%s", summary.Failed, RenderSummary(summary))
 	}
 }
+
+func TestValidateContractFixturesRejectsEventIDCollisions(t *testing.T) {
+	t.Parallel()
+
+	fixtureRoot := filepath.Join("..", "..", "..", "test", "contract", "fixtures")
+	root := t.TempDir()
+	for _, name := range []string{"event", "control_signal", "turn_transition", "resolved_turn_plan", "decision_outcome"} {
+		for _, validity := range []string{"valid", "invalid"} {
+			if err := os.MkdirAll(filepath.Join(root, name, validity), 0o755); err != nil {
+				t.Fatalf("mkdir fixtures: %v", err)
+			}
+		}
+	}
+	raw, err := os.ReadFile(filepath.Join(fixtureRoot, "event", "valid", "minimal_turn_event.json"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	for _, name := range []string{"a.json", "b.json"} {
+		if err := os.WriteFile(filepath.Join(root, "event", "valid", name), raw, 0o644); err != nil {
+			t.Fatalf("write fixture: %v", err)
+		}
+	}
+
+	schemaPath := filepath.Join("..", "..", "..", "docs", "ContractArtifacts.schema.json")
+	summary, err := ValidateContractFixturesWithSchema(schemaPath, root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Total != 2 || summary.Failed != 1 || !strings.Contains(RenderSummary(summary), "collides") {
+		t.Fatalf("expected the second fixture to collide on event_id, got:\n%s", RenderSummary(summary))
+	}
+}
@@ -0,0 +1,289 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultMinCoverageRatio is the minimum share of tracked schema elements that valid contract
+// fixtures must exercise.
+const DefaultMinCoverageRatio = 0.55
+
+// coverageDimension maps one schema enum set to the fixture fields that exercise it.
+type coverageDimension struct {
+	name string
+	// defPath locates the enum inside $defs: a def name, or def name plus property path.
+	defPath []string
+	// sources lists fixture kinds and dotted field paths whose values cover the enum. A "*"
+	// path segment matches every key of an object.
+	sources []coverageSource
+}
+
+type coverageSource struct {
+	kind string
+	path string
+}
+
+var coverageDimensions = []coverageDimension{
+	{name: "event_kind"},
+	{name: "control_signal", defPath: []string{"control_signal_name"}, sources: []coverageSource{{kind: "control_signal", path: "signal"}}},
+	{name: "control_signal_emitter", defPath: []string{"control_signal_emitter"}, sources: []coverageSource{{kind: "control_signal", path: "emitted_by"}}},
+	{name: "payload_class", defPath: []string{"payload_class"}, sources: []coverageSource{{kind: "event", path: "payload_class"}, {kind: "control_signal", path: "payload_class"}}},
+	{name: "lane", defPath: []string{"lane"}, sources: []coverageSource{{kind: "event", path: "lane"}, {kind: "control_signal", path: "lane"}, {kind: "control_signal", path: "target_lane"}}},
+	{name: "event_scope", defPath: []string{"event_scope"}, sources: []coverageSource{{kind: "event", path: "event_scope"}, {kind: "control_signal", path: "event_scope"}}},
+	{name: "failure_domain", defPath: []string{"failure_domain"}, sources: []coverageSource{{kind: "event", path: "failure_domain"}, {kind: "control_signal", path: "failure_domain"}, {kind: "decision_outcome", path: "failure_domain"}}},
+	{name: "turn_state", defPath: []string{"turn_state"}, sources: []coverageSource{{kind: "turn_transition", path: "from_state"}, {kind: "turn_transition", path: "to_state"}}},
+	{name: "turn_transition_trigger", defPath: []string{"turn_transition", "trigger"}, sources: []coverageSource{{kind: "turn_transition", path: "trigger"}}},
+	{name: "outcome_kind", defPath: []string{"decision_outcome", "outcome_kind"}, sources: []coverageSource{{kind: "decision_outcome", path: "outcome_kind"}}},
+	{name: "outcome_phase", defPath: []string{"decision_outcome", "phase"}, sources: []coverageSource{{kind: "decision_outcome", path: "phase"}}},
+	{name: "outcome_scope", defPath: []string{"decision_outcome", "scope"}, sources: []coverageSource{{kind: "decision_outcome", path: "scope"}}},
+	{name: "outcome_emitter", defPath: []string{"decision_outcome", "emitted_by"}, sources: []coverageSource{{kind: "decision_outcome", path: "emitted_by"}}},
+	{name: "buffer_strategy", defPath: []string{"buffer_strategy"}, sources: []coverageSource{{kind: "resolved_turn_plan", path: "edge_buffer_policies.*.strategy"}}},
+}
+
+// coverageKinds are the fixture directories and the schema defs whose fields they populate.
+var coverageKinds = []struct {
+	kind      string
+	fieldsDef string
+}{
+	{kind: "event", fieldsDef: "event_envelope"},
+	{kind: "control_signal", fieldsDef: "event_envelope"},
+	{kind: "turn_transition", fieldsDef: "turn_transition"},
+	{kind: "resolved_turn_plan", fieldsDef: "resolved_turn_plan"},
+	{kind: "decision_outcome", fieldsDef: "decision_outcome"},
+}
+
+// DimensionCoverage reports fixture coverage of one schema element set.
+type DimensionCoverage struct {
+	Name      string   `json:"name"`
+	Total     int      `json:"total"`
+	Covered   int      `json:"covered"`
+	Uncovered []string `json:"uncovered,omitempty"`
+}
+
+// ContractCoverageReport maps valid contract fixtures to the enums and fields of
+// ContractArtifacts.schema.json and gates on a minimum coverage ratio.
+type ContractCoverageReport struct {
+	Dimensions    []DimensionCoverage `json:"dimensions"`
+	Fields        []DimensionCoverage `json:"fields"`
+	TotalElements int                 `json:"total_elements"`
+	Covered       int                 `json:"covered"`
+	CoverageRatio float64             `json:"coverage_ratio"`
+	MinRatio      float64             `json:"min_ratio"`
+	Passed        bool                `json:"passed"`
+}
+
+// AnalyzeContractCoverage reports which schema enums and artifact fields the valid fixtures
+// under root exercise. minRatio <= 0 uses DefaultMinCoverageRatio.
+func AnalyzeContractCoverage(schemaPath, root string, minRatio float64) (ContractCoverageReport, error) {
+	if minRatio <= 0 {
+		minRatio = DefaultMinCoverageRatio
+	}
+	if minRatio > 1 {
+		return ContractCoverageReport{}, fmt.Errorf("contract coverage min ratio must be <=1, got %.2f", minRatio)
+	}
+	defs, err := loadSchemaDefs(schemaPath)
+	if err != nil {
+		return ContractCoverageReport{}, err
+	}
+	fixtures, err := loadValidFixtures(root)
+	if err != nil {
+		return ContractCoverageReport{}, err
+	}
+
+	report := ContractCoverageReport{MinRatio: minRatio}
+	for _, dimension := range coverageDimensions {
+		var elements []string
+		observed := make(map[string]bool)
+		if dimension.name == "event_kind" {
+			for _, entry := range coverageKinds {
+				elements = append(elements, entry.kind)
+				observed[entry.kind] = len(fixtures[entry.kind]) > 0
+			}
+		} else {
+			elements, err = schemaEnum(defs, dimension.defPath)
+			if err != nil {
+				return ContractCoverageReport{}, fmt.Errorf("coverage dimension %s: %w", dimension.name, err)
+			}
+			for _, source := range dimension.sources {
+				for _, fixture := range fixtures[source.kind] {
+					for _, value := range valuesAtPath(fixture, strings.Split(source.path, ".")) {
+						observed[value] = true
+					}
+				}
+			}
+		}
+		report.Dimensions = append(report.Dimensions, dimensionCoverage(dimension.name, elements, observed))
+	}
+
+	for _, entry := range coverageKinds {
+		fields, err := schemaProperties(defs, entry.fieldsDef)
+		if err != nil {
+			return ContractCoverageReport{}, fmt.Errorf("coverage fields %s: %w", entry.kind, err)
+		}
+		observed := make(map[string]bool)
+		for _, fixture := range fixtures[entry.kind] {
+			if object, ok := fixture.(map[string]any); ok {
+				for key := range object {
+					observed[key] = true
+				}
+			}
+		}
+		report.Fields = append(report.Fields, dimensionCoverage(entry.kind, fields, observed))
+	}
+
+	for _, coverage := range append(append([]DimensionCoverage(nil), report.Dimensions...), report.Fields...) {
+		report.TotalElements += coverage.Total
+		report.Covered += coverage.Covered
+	}
+	if report.TotalElements > 0 {
+		report.CoverageRatio = float64(report.Covered) / float64(report.TotalElements)
+	}
+	report.Passed = report.CoverageRatio >= report.MinRatio
+	return report, nil
+}
+
+// RenderCoverageSummary renders a one-line-per-dimension coverage summary.
+func RenderCoverageSummary(report ContractCoverageReport) string {
+	lines := []string{fmt.Sprintf("contract coverage: %d/%d (%.2f, min %.2f)", report.Covered, report.TotalElements, report.CoverageRatio, report.MinRatio)}
+	for _, group := range []struct {
+		label      string
+		dimensions []DimensionCoverage
+	}{
+		{label: "enums", dimensions: report.Dimensions},
+		{label: "fields", dimensions: report.Fields},
+	} {
+		lines = append(lines, group.label+":")
+		for _, coverage := range group.dimensions {
+			line := fmt.Sprintf("- %s: %d/%d", coverage.Name, coverage.Covered, coverage.Total)
+			if len(coverage.Uncovered) > 0 {
+				line += " uncovered=" + strings.Join(coverage.Uncovered, ",")
+			}
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func dimensionCoverage(name string, elements []string, observed map[string]bool) DimensionCoverage {
+	coverage := DimensionCoverage{Name: name, Total: len(elements)}
+	for _, element := range elements {
+		if observed[element] {
+			coverage.Covered++
+			continue
+		}
+		coverage.Uncovered = append(coverage.Uncovered, element)
+	}
+	return coverage
+}
+
+func loadSchemaDefs(schemaPath string) (map[string]any, error) {
+	raw, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("read schema file: %w", err)
+	}
+	var schema struct {
+		Defs map[string]any `json:"$defs"`
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, fmt.Errorf("decode schema file: %w", err)
+	}
+	if len(schema.Defs) == 0 {
+		return nil, fmt.Errorf("schema %s has no $defs", schemaPath)
+	}
+	return schema.Defs, nil
+}
+
+func schemaEnum(defs map[string]any, defPath []string) ([]string, error) {
+	node, ok := defs[defPath[0]].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("$defs/%s not found", defPath[0])
+	}
+	for _, property := range defPath[1:] {
+		properties, _ := node["properties"].(map[string]any)
+		next, ok := properties[property].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("$defs/%s property %s not found", defPath[0], property)
+		}
+		node = next
+	}
+	values, ok := node["enum"].([]any)
+	if !ok {
+		return nil, fmt.Errorf("$defs/%s has no enum", strings.Join(defPath, "."))
+	}
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func schemaProperties(defs map[string]any, def string) ([]string, error) {
+	node, ok := defs[def].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("$defs/%s not found", def)
+	}
+	properties, ok := node["properties"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("$defs/%s has no properties", def)
+	}
+	out := make([]string, 0, len(properties))
+	for name := range properties {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func loadValidFixtures(root string) (map[string][]any, error) {
+	fixtures := make(map[string][]any, len(coverageKinds))
+	for _, entry := range coverageKinds {
+		dir := filepath.Join(root, entry.kind, "valid")
+		items, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("read fixtures %s: %w", dir, err)
+		}
+		for _, item := range items {
+			if item.IsDir() || filepath.Ext(item.Name()) != ".json" {
+				continue
+			}
+			raw, err := os.ReadFile(filepath.Join(dir, item.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("read fixture %s: %w", item.Name(), err)
+			}
+			var payload any
+			if err := json.Unmarshal(raw, &payload); err != nil {
+				return nil, fmt.Errorf("decode fixture %s: %w", filepath.Join(dir, item.Name()), err)
+			}
+			fixtures[entry.kind] = append(fixtures[entry.kind], payload)
+		}
+	}
+	return fixtures, nil
+}
+
+func valuesAtPath(node any, path []string) []string {
+	if len(path) == 0 {
+		if s, ok := node.(string); ok {
+			return []string{s}
+		}
+		return nil
+	}
+	object, ok := node.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if path[0] != "*" {
+		return valuesAtPath(object[path[0]], path[1:])
+	}
+	var out []string
+	for _, child := range object {
+		out = append(out, valuesAtPath(child, path[1:])...)
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeContractCoverageOverRepoFixtures(t *testing.T) {
+	t.Parallel()
+
+	fixtureRoot := filepath.Join("..", "..", "..", "test", "contract", "fixtures")
+	schemaPath := filepath.Join("..", "..", "..", "docs", "ContractArtifacts.schema.json")
+	report, err := AnalyzeContractCoverage(schemaPath, fixtureRoot, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.Passed || report.MinRatio != DefaultMinCoverageRatio {
+		t.Fatalf("expected repo fixtures to meet default coverage, got\n%s", RenderCoverageSummary(report))
+	}
+	byName := map[string]DimensionCoverage{}
+	for _, coverage := range report.Dimensions {
+		byName[coverage.Name] = coverage
+	}
+	for _, name := range []string{"event_kind", "control_signal", "payload_class", "outcome_kind", "turn_transition_trigger"} {
+		if byName[name].Total == 0 {
+			t.Fatalf("expected schema elements for dimension %s, got %+v", name, byName[name])
+		}
+	}
+	if kinds := byName["outcome_kind"]; kinds.Covered != kinds.Total {
+		t.Fatalf("expected every outcome kind covered, uncovered=%v", kinds.Uncovered)
+	}
+}
+
+func TestAnalyzeContractCoverageReportsUncoveredAndGates(t *testing.T) {
+	t.Parallel()
+
+	schemaPath := filepath.Join("..", "..", "..", "docs", "ContractArtifacts.schema.json")
+	root := t.TempDir()
+	for _, kind := range []string{"event", "control_signal", "turn_transition", "resolved_turn_plan", "decision_outcome"} {
+		if err := os.MkdirAll(filepath.Join(root, kind, "valid"), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	fixture := `{"outcome_kind":"admit","phase":"pre_turn","scope":"session","session_id":"s","event_id":"e","runtime_timestamp_ms":1,"wall_clock_timestamp_ms":1,"emitted_by":"RK-25","reason":"admitted"}`
+	if err := os.WriteFile(filepath.Join(root, "decision_outcome", "valid", "admit.json"), []byte(fixture), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		minRatio   float64
+		wantPassed bool
+		wantErr    bool
+	}{
+		{name: "default threshold gates sparse fixtures", minRatio: 0, wantPassed: false},
+		{name: "low threshold passes", minRatio: 0.01, wantPassed: true},
+		{name: "threshold above one rejected", minRatio: 1.5, wantErr: true},
+	}
+	for _, tc := range tests {
+		report, err := AnalyzeContractCoverage(schemaPath, root, tc.minRatio)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected error", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if report.Passed != tc.wantPassed {
+			t.Fatalf("%s: expected passed=%t, got %+v", tc.name, tc.wantPassed, report)
+		}
+		var outcomes DimensionCoverage
+		for _, coverage := range report.Dimensions {
+			if coverage.Name == "outcome_kind" {
+				outcomes = coverage
+			}
+		}
+		if outcomes.Covered != 1 || strings.Join(outcomes.Uncovered, ",") != "reject,defer,shed,stale_epoch_reject,deauthorized_drain,budget_exceeded" {
+			t.Fatalf("%s: expected only admit covered, got %+v", tc.name, outcomes)
+		}
+	}
+}
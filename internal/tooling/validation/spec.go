@@ -0,0 +1,183 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	cpregistry "github.com/tiger/realtime-speech-pipeline/internal/controlplane/registry"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+// Spec finding codes reported by ValidatePipelineSpec.
+const (
+	SpecFindingInvalidNode        = "invalid_node"
+	SpecFindingUnknownProvider    = "unknown_provider"
+	SpecFindingLatencyBudgetUnmet = "latency_budget_unmet"
+	SpecFindingCostBudgetUnmet    = "cost_budget_unmet"
+)
+
+// PipelineSpec is a declarative pipeline spec with provider bindings and optional per-node
+// latency/cost budgets. Edges and edge buffer policies are optional; when present they are
+// checked by LintPipelineSpec.
+type PipelineSpec struct {
+	PipelineVersion    string                                   `json:"pipeline_version"`
+	GraphDefinitionRef string                                   `json:"graph_definition_ref"`
+	ExecutionProfile   string                                   `json:"execution_profile"`
+	Nodes              []SpecNode                               `json:"nodes"`
+	Edges              []SpecEdge                               `json:"edges,omitempty"`
+	EdgeBufferPolicies map[string]controlplane.EdgeBufferPolicy `json:"edge_buffer_policies,omitempty"`
+}
+
+// SpecNode binds one provider node to a provider and the budget the spec promises for it.
+// RequiresStreaming declares that the node needs incremental provider output.
+type SpecNode struct {
+	NodeID            string             `json:"node_id"`
+	Modality          contracts.Modality `json:"modality"`
+	ProviderID        string             `json:"provider_id"`
+	RequiresStreaming bool               `json:"requires_streaming,omitempty"`
+	Budget            *NodeBudget        `json:"budget,omitempty"`
+}
+
+// SpecEdge connects two spec nodes on one lane.
+type SpecEdge struct {
+	From string        `json:"from"`
+	To   string        `json:"to"`
+	Lane eventabi.Lane `json:"lane,omitempty"`
+}
+
+// NodeBudget declares node SLOs; zero fields are not budgeted.
+type NodeBudget struct {
+	MaxFirstOutputLatencyMS int64   `json:"max_first_output_latency_ms,omitempty"`
+	MaxCostPerTurnUSD       float64 `json:"max_cost_per_turn_usd,omitempty"`
+}
+
+// CapabilitySource supplies provider capability metadata, e.g. registry.Catalog or
+// registry.DefaultCapabilities.
+type CapabilitySource interface {
+	Capability(modality contracts.Modality, providerID string) (contracts.Capability, bool)
+}
+
+// SpecFinding is one spec node that fails validation.
+type SpecFinding struct {
+	NodeID     string `json:"node_id,omitempty"`
+	ProviderID string `json:"provider_id,omitempty"`
+	Code       string `json:"code"`
+	Detail     string `json:"detail"`
+}
+
+// SpecValidationReport reports budget checks for every node of a pipeline spec.
+type SpecValidationReport struct {
+	PipelineVersion string        `json:"pipeline_version"`
+	Nodes           int           `json:"nodes"`
+	BudgetedNodes   int           `json:"budgeted_nodes"`
+	Findings        []SpecFinding `json:"findings,omitempty"`
+	Passed          bool          `json:"passed"`
+}
+
+// LoadPipelineSpec strictly decodes a pipeline spec file.
+func LoadPipelineSpec(path string) (PipelineSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PipelineSpec{}, err
+	}
+	var spec PipelineSpec
+	if err := strictUnmarshal(data, &spec); err != nil {
+		return PipelineSpec{}, fmt.Errorf("decode pipeline spec %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// ValidatePipelineSpec checks each node binding against provider capability metadata and
+// fails nodes whose declared budget is tighter than the bound provider typically delivers.
+// It returns an error only when the spec identity itself is malformed.
+func ValidatePipelineSpec(spec PipelineSpec, capabilities CapabilitySource) (SpecValidationReport, error) {
+	if capabilities == nil {
+		return SpecValidationReport{}, fmt.Errorf("capability source is required")
+	}
+	if err := validateSpecIdentity(spec); err != nil {
+		return SpecValidationReport{}, err
+	}
+
+	report := SpecValidationReport{PipelineVersion: spec.PipelineVersion, Nodes: len(spec.Nodes)}
+	seen := make(map[string]struct{}, len(spec.Nodes))
+	for _, node := range spec.Nodes {
+		finding := SpecFinding{NodeID: node.NodeID, ProviderID: node.ProviderID}
+		if err := validateSpecNode(node, seen); err != nil {
+			finding.Code, finding.Detail = SpecFindingInvalidNode, err.Error()
+			report.Findings = append(report.Findings, finding)
+			continue
+		}
+		seen[node.NodeID] = struct{}{}
+		if node.Budget == nil {
+			continue
+		}
+		report.BudgetedNodes++
+
+		capability, ok := capabilities.Capability(node.Modality, node.ProviderID)
+		if !ok {
+			finding.Code = SpecFindingUnknownProvider
+			finding.Detail = fmt.Sprintf("no %s capability metadata for provider %s", node.Modality, node.ProviderID)
+			report.Findings = append(report.Findings, finding)
+			continue
+		}
+		if limit := node.Budget.MaxFirstOutputLatencyMS; limit > 0 && capability.TypicalFirstOutputLatencyMS > limit {
+			finding.Code = SpecFindingLatencyBudgetUnmet
+			finding.Detail = fmt.Sprintf("budget max_first_output_latency_ms=%d below provider typical %dms", limit, capability.TypicalFirstOutputLatencyMS)
+			report.Findings = append(report.Findings, finding)
+		}
+		if limit := node.Budget.MaxCostPerTurnUSD; limit > 0 && capability.TypicalCostPerTurnUSD > limit {
+			finding.Code = SpecFindingCostBudgetUnmet
+			finding.Detail = fmt.Sprintf("budget max_cost_per_turn_usd=%.4f below provider typical $%.4f", limit, capability.TypicalCostPerTurnUSD)
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+	report.Passed = len(report.Findings) == 0
+	return report, nil
+}
+
+// RenderSpecSummary renders a one-line-per-finding spec validation summary.
+func RenderSpecSummary(report SpecValidationReport) string {
+	lines := []string{fmt.Sprintf("pipeline spec %s: nodes=%d budgeted=%d findings=%d", report.PipelineVersion, report.Nodes, report.BudgetedNodes, len(report.Findings))}
+	if len(report.Findings) > 0 {
+		lines = append(lines, "findings:")
+		for _, finding := range report.Findings {
+			lines = append(lines, fmt.Sprintf("- %s %s (%s): %s", finding.NodeID, finding.Code, finding.ProviderID, finding.Detail))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// validateSpecIdentity checks the spec's pipeline record fields and that it has nodes.
+func validateSpecIdentity(spec PipelineSpec) error {
+	record := cpregistry.PipelineRecord{
+		PipelineVersion:    spec.PipelineVersion,
+		GraphDefinitionRef: spec.GraphDefinitionRef,
+		ExecutionProfile:   spec.ExecutionProfile,
+	}
+	if err := record.Validate(); err != nil {
+		return err
+	}
+	if len(spec.Nodes) == 0 {
+		return fmt.Errorf("pipeline spec requires at least one node")
+	}
+	return nil
+}
+
+func validateSpecNode(node SpecNode, seen map[string]struct{}) error {
+	if node.NodeID == "" || node.ProviderID == "" {
+		return fmt.Errorf("node_id and provider_id are required")
+	}
+	if _, dup := seen[node.NodeID]; dup {
+		return fmt.Errorf("duplicate node_id %s", node.NodeID)
+	}
+	if err := node.Modality.Validate(); err != nil {
+		return err
+	}
+	if node.Budget != nil && (node.Budget.MaxFirstOutputLatencyMS < 0 || node.Budget.MaxCostPerTurnUSD < 0) {
+		return fmt.Errorf("budget values must be >=0")
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/planresolver"
+)
+
+// Spec lint rules reported by LintPipelineSpec.
+const (
+	LintRuleMissingBufferPolicyDefault = "missing_buffer_policy_default"
+	LintRuleInvalidBufferPolicy        = "invalid_buffer_policy"
+	LintRuleMissingLane                = "missing_lane"
+	LintRuleInvalidLane                = "invalid_lane"
+	LintRuleUnknownEdgeNode            = "unknown_edge_node"
+	LintRuleUnreferencedNode           = "unreferenced_node"
+)
+
+// defaultBufferPolicyKey is the edge_buffer_policies key every resolved plan carries.
+const defaultBufferPolicyKey = "default"
+
+const (
+	lintSkipNotRequested = "autofix not requested"
+	lintSkipUnsafe       = "not a safe autofix; the fix changes pipeline topology or a declared policy"
+)
+
+// LintSuggestion is one concrete spec fix. Safe suggestions only add what the runtime would
+// otherwise default, so applying them never changes a resolved plan.
+type LintSuggestion struct {
+	Rule          string `json:"rule"`
+	Target        string `json:"target"`
+	Detail        string `json:"detail"`
+	Fix           string `json:"fix"`
+	Safe          bool   `json:"safe"`
+	Applied       bool   `json:"applied"`
+	SkippedReason string `json:"skipped_reason,omitempty"`
+}
+
+// SpecLintReport lists every lint suggestion for a spec with whether its fix was applied.
+type SpecLintReport struct {
+	PipelineVersion string           `json:"pipeline_version"`
+	FixRequested    bool             `json:"fix_requested"`
+	Suggestions     []LintSuggestion `json:"suggestions"`
+	Applied         int              `json:"applied"`
+	Skipped         int              `json:"skipped"`
+}
+
+// LintPipelineSpec checks spec edges, lanes, and buffer policies and suggests fixes. With fix
+// set, safe suggestions are applied to the returned copy of spec; spec itself is not modified.
+// Specs without edges have nothing to lint.
+func LintPipelineSpec(spec PipelineSpec, fix bool) (PipelineSpec, SpecLintReport) {
+	out := spec
+	out.Edges = append([]SpecEdge(nil), spec.Edges...)
+	report := SpecLintReport{PipelineVersion: spec.PipelineVersion, FixRequested: fix, Suggestions: make([]LintSuggestion, 0)}
+	if len(spec.Edges) == 0 {
+		return out, report
+	}
+	suggest := func(suggestion LintSuggestion, apply func()) {
+		switch {
+		case !suggestion.Safe:
+			suggestion.SkippedReason = lintSkipUnsafe
+		case !fix:
+			suggestion.SkippedReason = lintSkipNotRequested
+		default:
+			apply()
+			suggestion.Applied = true
+		}
+		if suggestion.Applied {
+			report.Applied++
+		} else {
+			report.Skipped++
+		}
+		report.Suggestions = append(report.Suggestions, suggestion)
+	}
+
+	if _, ok := spec.EdgeBufferPolicies[defaultBufferPolicyKey]; !ok {
+		suggest(LintSuggestion{
+			Rule:   LintRuleMissingBufferPolicyDefault,
+			Target: "edge_buffer_policies",
+			Detail: "spec declares edges but no default edge buffer policy",
+			Fix:    fmt.Sprintf("add edge_buffer_policies.%s from the execution profile default", defaultBufferPolicyKey),
+			Safe:   true,
+		}, func() {
+			out.EdgeBufferPolicies = make(map[string]controlplane.EdgeBufferPolicy, len(spec.EdgeBufferPolicies)+1)
+			for key, policy := range spec.EdgeBufferPolicies {
+				out.EdgeBufferPolicies[key] = policy
+			}
+			out.EdgeBufferPolicies[defaultBufferPolicyKey] = planresolver.DefaultEdgeBufferPolicy()
+		})
+	}
+	keys := make([]string, 0, len(spec.EdgeBufferPolicies))
+	for key := range spec.EdgeBufferPolicies {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := spec.EdgeBufferPolicies[key].Validate(); err != nil {
+			suggest(LintSuggestion{
+				Rule:   LintRuleInvalidBufferPolicy,
+				Target: "edge_buffer_policies." + key,
+				Detail: err.Error(),
+				Fix:    "correct the policy or remove it to use the execution profile default",
+			}, nil)
+		}
+	}
+
+	nodes := make(map[string]struct{}, len(spec.Nodes))
+	for _, node := range spec.Nodes {
+		nodes[node.NodeID] = struct{}{}
+	}
+	referenced := make(map[string]struct{}, len(spec.Nodes))
+	for i, edge := range spec.Edges {
+		target := fmt.Sprintf("edges[%d] %s->%s", i, edge.From, edge.To)
+		for _, nodeID := range []string{edge.From, edge.To} {
+			referenced[nodeID] = struct{}{}
+			if _, ok := nodes[nodeID]; !ok {
+				suggest(LintSuggestion{
+					Rule:   LintRuleUnknownEdgeNode,
+					Target: target,
+					Detail: fmt.Sprintf("edge references undeclared node %q", nodeID),
+					Fix:    "declare the node or remove the edge",
+				}, nil)
+			}
+		}
+		switch edge.Lane {
+		case "":
+			suggest(LintSuggestion{
+				Rule:   LintRuleMissingLane,
+				Target: target,
+				Detail: "edge has no lane assignment",
+				Fix:    fmt.Sprintf("set lane=%s, the lane provider node edges carry", eventabi.LaneData),
+				Safe:   true,
+			}, func() { out.Edges[i].Lane = eventabi.LaneData })
+		case eventabi.LaneData, eventabi.LaneControl, eventabi.LaneTelemetry:
+		default:
+			suggest(LintSuggestion{
+				Rule:   LintRuleInvalidLane,
+				Target: target,
+				Detail: fmt.Sprintf("unknown lane %q", edge.Lane),
+				Fix:    fmt.Sprintf("use one of %s, %s, %s", eventabi.LaneData, eventabi.LaneControl, eventabi.LaneTelemetry),
+			}, nil)
+		}
+	}
+	for _, node := range spec.Nodes {
+		if _, ok := referenced[node.NodeID]; !ok {
+			suggest(LintSuggestion{
+				Rule:   LintRuleUnreferencedNode,
+				Target: "nodes." + node.NodeID,
+				Detail: "node is not referenced by any edge",
+				Fix:    "connect the node with an edge or remove it",
+			}, nil)
+		}
+	}
+	return out, report
+}
+
+// RenderSpecLintSummary renders a one-line-per-suggestion spec lint summary.
+func RenderSpecLintSummary(report SpecLintReport) string {
+	lines := []string{fmt.Sprintf("pipeline spec %s lint: suggestions=%d applied=%d skipped=%d", report.PipelineVersion, len(report.Suggestions), report.Applied, report.Skipped)}
+	for _, suggestion := range report.Suggestions {
+		status := "applied"
+		if !suggestion.Applied {
+			status = "skipped: " + suggestion.SkippedReason
+		}
+		lines = append(lines, fmt.Sprintf("- %s %s: %s; fix: %s (%s)", suggestion.Target, suggestion.Rule, suggestion.Detail, suggestion.Fix, status))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,92 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/planresolver"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+func lintSpec(edges []SpecEdge, policies map[string]controlplane.EdgeBufferPolicy) PipelineSpec {
+	return PipelineSpec{
+		PipelineVersion:    "pipeline-v1",
+		GraphDefinitionRef: "graph/default",
+		ExecutionProfile:   "simple",
+		Nodes: []SpecNode{
+			{NodeID: "stt", Modality: contracts.ModalitySTT, ProviderID: "stt-deepgram"},
+			{NodeID: "llm", Modality: contracts.ModalityLLM, ProviderID: "llm-gemini"},
+			{NodeID: "tts", Modality: contracts.ModalityTTS, ProviderID: "tts-amazon-polly"},
+		},
+		Edges:              edges,
+		EdgeBufferPolicies: policies,
+	}
+}
+
+func TestLintPipelineSpecSuggestsFixes(t *testing.T) {
+	t.Parallel()
+
+	defaults := map[string]controlplane.EdgeBufferPolicy{"default": planresolver.DefaultEdgeBufferPolicy()}
+	chain := []SpecEdge{{From: "stt", To: "llm", Lane: eventabi.LaneData}, {From: "llm", To: "tts", Lane: eventabi.LaneData}}
+	tests := []struct {
+		name      string
+		spec      PipelineSpec
+		wantRules []string
+	}{
+		{name: "spec without edges", spec: lintSpec(nil, nil)},
+		{name: "clean chain", spec: lintSpec(chain, defaults)},
+		{name: "missing default policy", spec: lintSpec(chain, nil), wantRules: []string{LintRuleMissingBufferPolicyDefault}},
+		{name: "invalid policy", spec: lintSpec(chain, map[string]controlplane.EdgeBufferPolicy{"default": planresolver.DefaultEdgeBufferPolicy(), "audio": {}}), wantRules: []string{LintRuleInvalidBufferPolicy}},
+		{name: "missing lane", spec: lintSpec([]SpecEdge{chain[0], {From: "llm", To: "tts"}}, defaults), wantRules: []string{LintRuleMissingLane}},
+		{name: "invalid lane", spec: lintSpec([]SpecEdge{chain[0], {From: "llm", To: "tts", Lane: "FastLane"}}, defaults), wantRules: []string{LintRuleInvalidLane}},
+		{name: "unreferenced node", spec: lintSpec(chain[:1], defaults), wantRules: []string{LintRuleUnreferencedNode}},
+		{name: "unknown edge node", spec: lintSpec(append(chain, SpecEdge{From: "tts", To: "vad", Lane: eventabi.LaneData}), defaults), wantRules: []string{LintRuleUnknownEdgeNode}},
+	}
+	for _, tc := range tests {
+		_, report := LintPipelineSpec(tc.spec, false)
+		rules := make([]string, 0, len(report.Suggestions))
+		for _, suggestion := range report.Suggestions {
+			rules = append(rules, suggestion.Rule)
+			if suggestion.Applied || suggestion.SkippedReason == "" {
+				t.Fatalf("%s: expected suggestion to be skipped without fix, got %+v", tc.name, suggestion)
+			}
+		}
+		if strings.Join(rules, ",") != strings.Join(tc.wantRules, ",") || report.Applied != 0 || report.Skipped != len(tc.wantRules) {
+			t.Fatalf("%s: expected rules %v, got\n%s", tc.name, tc.wantRules, RenderSpecLintSummary(report))
+		}
+	}
+}
+
+func TestLintPipelineSpecAppliesOnlySafeFixes(t *testing.T) {
+	t.Parallel()
+
+	spec := lintSpec([]SpecEdge{{From: "stt", To: "llm"}}, nil)
+	fixed, report := LintPipelineSpec(spec, true)
+	if report.Applied != 2 || report.Skipped != 1 || !report.FixRequested {
+		t.Fatalf("expected two applied and one skipped suggestion, got\n%s", RenderSpecLintSummary(report))
+	}
+	for _, suggestion := range report.Suggestions {
+		if suggestion.Applied != suggestion.Safe {
+			t.Fatalf("expected only safe suggestions to be applied, got %+v", suggestion)
+		}
+	}
+	if fixed.Edges[0].Lane != eventabi.LaneData {
+		t.Fatalf("expected missing lane to be fixed, got %+v", fixed.Edges[0])
+	}
+	if policy, ok := fixed.EdgeBufferPolicies["default"]; !ok || policy.Validate() != nil {
+		t.Fatalf("expected a valid default buffer policy, got %+v", fixed.EdgeBufferPolicies)
+	}
+	if len(fixed.Nodes) != 3 {
+		t.Fatalf("expected unreferenced node to be kept, got %+v", fixed.Nodes)
+	}
+	if spec.Edges[0].Lane != "" || spec.EdgeBufferPolicies != nil {
+		t.Fatalf("expected input spec to be left unmodified, got %+v", spec)
+	}
+
+	_, relint := LintPipelineSpec(fixed, true)
+	if relint.Applied != 0 || len(relint.Suggestions) != 1 || relint.Suggestions[0].Rule != LintRuleUnreferencedNode {
+		t.Fatalf("expected fixed spec to keep only the unsafe suggestion, got\n%s", RenderSpecLintSummary(relint))
+	}
+}
@@ -0,0 +1,75 @@
+package validation
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/registry"
+)
+
+func TestValidatePipelineSpecRepoFixturePasses(t *testing.T) {
+	t.Parallel()
+
+	spec, err := LoadPipelineSpec(filepath.Join("..", "..", "..", "test", "spec", "fixtures", "budgeted-pipeline.json"))
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	report, err := ValidatePipelineSpec(spec, registry.DefaultCapabilities())
+	if err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	if !report.Passed || report.Nodes != 3 || report.BudgetedNodes != 3 {
+		t.Fatalf("expected repo spec fixture to pass, got\n%s", RenderSpecSummary(report))
+	}
+}
+
+func TestValidatePipelineSpecFlagsUnmeetableBudgets(t *testing.T) {
+	t.Parallel()
+
+	specWith := func(nodes ...SpecNode) PipelineSpec {
+		return PipelineSpec{PipelineVersion: "pipeline-v1", GraphDefinitionRef: "graph/default", ExecutionProfile: "simple", Nodes: nodes}
+	}
+	tests := []struct {
+		name      string
+		spec      PipelineSpec
+		wantCodes []string
+	}{
+		{name: "unbudgeted node passes", spec: specWith(SpecNode{NodeID: "llm", Modality: contracts.ModalityLLM, ProviderID: "llm-cohere"})},
+		{name: "budget met at typical latency", spec: specWith(SpecNode{NodeID: "stt", Modality: contracts.ModalitySTT, ProviderID: "stt-deepgram", Budget: &NodeBudget{MaxFirstOutputLatencyMS: 300}})},
+		{name: "latency budget unmet", spec: specWith(SpecNode{NodeID: "llm", Modality: contracts.ModalityLLM, ProviderID: "llm-anthropic", Budget: &NodeBudget{MaxFirstOutputLatencyMS: 200}}), wantCodes: []string{SpecFindingLatencyBudgetUnmet}},
+		{name: "latency and cost unmet", spec: specWith(SpecNode{NodeID: "tts", Modality: contracts.ModalityTTS, ProviderID: "tts-elevenlabs", Budget: &NodeBudget{MaxFirstOutputLatencyMS: 100, MaxCostPerTurnUSD: 0.001}}), wantCodes: []string{SpecFindingLatencyBudgetUnmet, SpecFindingCostBudgetUnmet}},
+		{name: "provider bound to wrong modality", spec: specWith(SpecNode{NodeID: "tts", Modality: contracts.ModalityTTS, ProviderID: "stt-deepgram", Budget: &NodeBudget{MaxFirstOutputLatencyMS: 1000}}), wantCodes: []string{SpecFindingUnknownProvider}},
+		{name: "duplicate node", spec: specWith(SpecNode{NodeID: "stt", Modality: contracts.ModalitySTT, ProviderID: "stt-google"}, SpecNode{NodeID: "stt", Modality: contracts.ModalitySTT, ProviderID: "stt-google"}), wantCodes: []string{SpecFindingInvalidNode}},
+		{name: "negative budget", spec: specWith(SpecNode{NodeID: "stt", Modality: contracts.ModalitySTT, ProviderID: "stt-google", Budget: &NodeBudget{MaxCostPerTurnUSD: -1}}), wantCodes: []string{SpecFindingInvalidNode}},
+	}
+	for _, tc := range tests {
+		report, err := ValidatePipelineSpec(tc.spec, registry.DefaultCapabilities())
+		if err != nil {
+			t.Fatalf("%s: unexpected validation error: %v", tc.name, err)
+		}
+		codes := make([]string, 0, len(report.Findings))
+		for _, finding := range report.Findings {
+			codes = append(codes, finding.Code)
+		}
+		if strings.Join(codes, ",") != strings.Join(tc.wantCodes, ",") || report.Passed != (len(tc.wantCodes) == 0) {
+			t.Fatalf("%s: expected findings %v, got\n%s", tc.name, tc.wantCodes, RenderSpecSummary(report))
+		}
+	}
+}
+
+func TestValidatePipelineSpecRejectsMalformedIdentity(t *testing.T) {
+	t.Parallel()
+
+	node := SpecNode{NodeID: "stt", Modality: contracts.ModalitySTT, ProviderID: "stt-google"}
+	if _, err := ValidatePipelineSpec(PipelineSpec{GraphDefinitionRef: "graph/default", ExecutionProfile: "simple", Nodes: []SpecNode{node}}, registry.DefaultCapabilities()); err == nil {
+		t.Fatalf("expected missing pipeline_version to fail")
+	}
+	if _, err := ValidatePipelineSpec(PipelineSpec{PipelineVersion: "pipeline-v1", GraphDefinitionRef: "graph/default", ExecutionProfile: "simple"}, registry.DefaultCapabilities()); err == nil {
+		t.Fatalf("expected spec without nodes to fail")
+	}
+	if _, err := ValidatePipelineSpec(PipelineSpec{PipelineVersion: "pipeline-v1", GraphDefinitionRef: "graph/default", ExecutionProfile: "simple", Nodes: []SpecNode{node}}, nil); err == nil {
+		t.Fatalf("expected missing capability source to fail")
+	}
+}
@@ -0,0 +1,454 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/cancellation"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/demo"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/bootstrap"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+// DefaultSubscriptionBuffer is the event buffer of a subscription created with buffer < 1.
+const DefaultSubscriptionBuffer = 64
+
+// ErrClosed is returned by calls on a closed engine or session.
+var ErrClosed = errors.New("pipeline closed")
+
+// Adapter is the runtime provider adapter contract. Every turn runs as an STT->LLM->TTS
+// execution plan whose nodes the invocation controller invokes through the adapters of their
+// modality, retrying and failing over in Config.Adapters order.
+type Adapter = contracts.Adapter
+
+// The adapter contract's request, outcome, and audio types.
+type (
+	Modality          = contracts.Modality
+	InvocationRequest = contracts.InvocationRequest
+	Outcome           = contracts.Outcome
+	OutcomeClass      = contracts.OutcomeClass
+	TokenUsage        = contracts.TokenUsage
+	AudioInput        = contracts.AudioInput
+	AudioOutput       = contracts.AudioOutput
+	// StaticAdapter adapts a provider id, modality, and invoke function to Adapter.
+	StaticAdapter = contracts.StaticAdapter
+)
+
+// Adapter modalities a session's turn plans invoke.
+const (
+	ModalitySTT = contracts.ModalitySTT
+	ModalityLLM = contracts.ModalityLLM
+	ModalityTTS = contracts.ModalityTTS
+)
+
+// Normalized adapter outcome classes.
+const (
+	OutcomeSuccess               = contracts.OutcomeSuccess
+	OutcomeTimeout               = contracts.OutcomeTimeout
+	OutcomeOverload              = contracts.OutcomeOverload
+	OutcomeBlocked               = contracts.OutcomeBlocked
+	OutcomeInfrastructureFailure = contracts.OutcomeInfrastructureFailure
+	OutcomeQualityFailure        = contracts.OutcomeQualityFailure
+	OutcomeCancelled             = contracts.OutcomeCancelled
+)
+
+// Config configures an Engine.
+type Config struct {
+	// TenantID and PipelineVersion tag every session's evidence; both have defaults.
+	TenantID        string
+	PipelineVersion string
+	// SampleRateHz is the PCM rate of pushed and returned audio. Defaults to 16000.
+	SampleRateHz int
+	// MaxCaptureMS ends an audio capture that was never ended. Defaults to 30000.
+	MaxCaptureMS int64
+	// ArtifactsDir receives each session's audio recording and OR-02 timeline baseline,
+	// readable by rspp-cli. Required.
+	ArtifactsDir string
+	// Adapters are the providers sessions invoke. The first adapter of a modality is preferred
+	// and later ones are failover candidates; a modality without an adapter uses the
+	// deterministic offline sandbox adapter, which needs no network or credentials.
+	Adapters []Adapter
+	// Clock defaults to time.Now.
+	Clock func() time.Time
+}
+
+// EventType names a session event.
+type EventType string
+
+const (
+	// EventCaptureStarted is published when pushed audio opens a capture.
+	EventCaptureStarted EventType = "capture_started"
+	// EventTurnCompleted is published with every completed turn.
+	EventTurnCompleted EventType = "turn_completed"
+	// EventCaptureCancelled is published when Cancel discards an open capture.
+	EventCaptureCancelled EventType = "capture_cancelled"
+	// EventSessionClosed is the last event of a session, carrying its artifact paths.
+	EventSessionClosed EventType = "session_closed"
+)
+
+// Turn is one completed user/assistant exchange.
+type Turn struct {
+	TurnID     string
+	Transcript string
+	Reply      string
+	// ReplyPCM is the reply audio at the engine sample rate.
+	ReplyPCM  []int16
+	Committed bool
+	// FallbackReason is set when Reply is a fallback utterance spoken because a provider
+	// stage failed.
+	FallbackReason string
+	// CaptureEndAtMS and FirstOutputAtMS are milliseconds since the session started.
+	CaptureEndAtMS  int64
+	FirstOutputAtMS int64
+}
+
+// Artifacts are the files a session writes under the engine artifacts dir; BaselinePath is
+// empty when the session completed no turn.
+type Artifacts struct {
+	SessionAudioPath string
+	BaselinePath     string
+}
+
+// Event is one session lifecycle or turn notification. Turn is set for turn_completed and
+// Artifacts for session_closed.
+type Event struct {
+	Type      EventType
+	SessionID string
+	Turn      *Turn
+	Artifacts *Artifacts
+}
+
+// Engine embeds the realtime speech pipeline in a Go service: every session it starts runs
+// turns through the turn arbiter and the RK-07 scheduler, whose execution plans invoke the
+// configured adapters through the invocation controller, speaks fallback utterances when a
+// stage fails, and records OR-02 evidence, without a separate runtime process. It is safe for
+// concurrent use.
+type Engine struct {
+	cfg         Config
+	providers   bootstrap.RuntimeProviders
+	preferred   map[contracts.Modality]string
+	cancelFence *cancellation.Fence
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+	closed   bool
+}
+
+// NewEngine returns an engine with cfg defaults applied.
+func NewEngine(cfg Config) (*Engine, error) {
+	if cfg.ArtifactsDir == "" {
+		return nil, fmt.Errorf("pipeline artifacts_dir is required")
+	}
+	if cfg.SampleRateHz < 0 || cfg.MaxCaptureMS < 0 {
+		return nil, fmt.Errorf("pipeline sample_rate_hz and max_capture_ms must be >=0")
+	}
+	adapters := append([]Adapter(nil), cfg.Adapters...)
+	preferred := make(map[contracts.Modality]string)
+	for _, adapter := range adapters {
+		if _, ok := preferred[adapter.Modality()]; !ok {
+			preferred[adapter.Modality()] = adapter.ProviderID()
+		}
+	}
+	for _, adapter := range demo.SandboxAdapters() {
+		if _, ok := preferred[adapter.Modality()]; !ok {
+			preferred[adapter.Modality()] = adapter.ProviderID()
+			adapters = append(adapters, adapter)
+		}
+	}
+	providers, err := bootstrap.BuildWithAdapters(adapters, bootstrap.Options{MinProvidersPerModality: 1})
+	if err != nil {
+		return nil, fmt.Errorf("pipeline adapters: %w", err)
+	}
+	return &Engine{cfg: cfg, providers: providers, preferred: preferred, cancelFence: cancellation.NewFence(), sessions: map[string]*Session{}}, nil
+}
+
+// StartSession starts a session; sessionID must be unique among the engine's open sessions.
+func (e *Engine) StartSession(sessionID string) (*Session, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil, ErrClosed
+	}
+	if _, ok := e.sessions[sessionID]; ok {
+		return nil, fmt.Errorf("pipeline session %s already started", sessionID)
+	}
+	inner, err := demo.NewSession(demo.SessionConfig{
+		SessionID:       sessionID,
+		TenantID:        e.cfg.TenantID,
+		PipelineVersion: e.cfg.PipelineVersion,
+		SampleRateHz:    e.cfg.SampleRateHz,
+		MaxCaptureMS:    e.cfg.MaxCaptureMS,
+		ArtifactsDir:    e.cfg.ArtifactsDir,
+		Clock:           e.cfg.Clock,
+		Invoker:         e.providers.Controller,
+		CancelFence:     e.cancelFence,
+		PreferredProvider: func(modality contracts.Modality) string {
+			return e.preferred[modality]
+		},
+	}, demo.Providers{})
+	if err != nil {
+		return nil, err
+	}
+	session := &Session{engine: e, id: sessionID, inner: inner}
+	e.sessions[sessionID] = session
+	return session, nil
+}
+
+// Sessions returns the ids of open sessions in order.
+func (e *Engine) Sessions() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ids := make([]string, 0, len(e.sessions))
+	for id := range e.sessions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Close closes every open session and rejects new ones.
+func (e *Engine) Close() error {
+	e.mu.Lock()
+	e.closed = true
+	sessions := make([]*Session, 0, len(e.sessions))
+	for _, session := range e.sessions {
+		sessions = append(sessions, session)
+	}
+	e.mu.Unlock()
+
+	var errs []error
+	for _, session := range sessions {
+		if err := session.Close(); err != nil && !errors.Is(err, ErrClosed) {
+			errs = append(errs, fmt.Errorf("close session %s: %w", session.id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *Engine) release(sessionID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.sessions, sessionID)
+}
+
+// Session is one conversation. Audio and text pushes run turns synchronously and return once
+// the turn's events are published; calls are serialized per session.
+type Session struct {
+	engine *Engine
+	id     string
+	inner  *demo.Session
+
+	mu            sync.Mutex
+	closed        bool
+	capturing     bool
+	subscriptions []*Subscription
+}
+
+// ID returns the session id.
+func (s *Session) ID() string {
+	return s.id
+}
+
+// PushAudio appends captured user audio, opening a capture when none is open. A capture that
+// reaches the engine MaxCaptureMS completes a turn.
+func (s *Session) PushAudio(pcm []int16) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	if !s.capturing {
+		if err := s.inner.StartCapture(); err != nil {
+			return err
+		}
+		s.capturing = true
+		s.publishLocked(Event{Type: EventCaptureStarted, SessionID: s.id})
+	}
+	turn, err := s.inner.AppendAudio(pcm)
+	if err != nil {
+		return err
+	}
+	if turn != nil {
+		s.capturing = false
+		s.publishTurnLocked(turn)
+	}
+	return nil
+}
+
+// EndAudio ends the open capture and runs its turn; it is a no-op without an open capture.
+func (s *Session) EndAudio() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	turn, err := s.inner.EndCapture()
+	s.capturing = false
+	if err != nil {
+		return err
+	}
+	if turn != nil {
+		s.publishTurnLocked(turn)
+	}
+	return nil
+}
+
+// PushText runs a turn on typed user text, skipping speech recognition. It fails while a
+// capture is open.
+func (s *Session) PushText(text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	turn, err := s.inner.SubmitText(text)
+	if err != nil {
+		return err
+	}
+	s.publishTurnLocked(turn)
+	return nil
+}
+
+// Cancel aborts the turn a concurrent push is running, cancelling its in-flight provider
+// invocations so the turn completes with its cancelled stages recorded, and discards the open
+// capture without running a turn, as on barge-in or a user abort. It is a no-op with neither.
+func (s *Session) Cancel() error {
+	// The running push holds the session lock until its turn completes.
+	if _, err := s.inner.CancelTurn(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	cancelled, err := s.inner.CancelCapture()
+	if err != nil {
+		return err
+	}
+	s.capturing = false
+	if cancelled {
+		s.publishLocked(Event{Type: EventCaptureCancelled, SessionID: s.id})
+	}
+	return nil
+}
+
+// Subscribe returns a subscription to the session's events, buffered to hold buffer events
+// (DefaultSubscriptionBuffer when buffer < 1). Publishing never blocks a turn: an event that
+// does not fit the buffer is dropped and counted. A closed session returns a closed
+// subscription.
+func (s *Session) Subscribe(buffer int) *Subscription {
+	if buffer < 1 {
+		buffer = DefaultSubscriptionBuffer
+	}
+	sub := &Subscription{events: make(chan Event, buffer)}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		sub.close()
+		return sub
+	}
+	s.subscriptions = append(s.subscriptions, sub)
+	return sub
+}
+
+// Close discards any open capture, writes the session artifacts, publishes session_closed,
+// and closes every subscription.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	s.closed = true
+	defer s.engine.release(s.id)
+
+	if _, err := s.inner.CancelCapture(); err != nil {
+		return err
+	}
+	written, err := s.inner.WriteArtifacts()
+	if err == nil {
+		artifacts := Artifacts{SessionAudioPath: written.SessionAudioPath}
+		// The baseline is only written once a turn has completed.
+		if _, statErr := os.Stat(written.BaselinePath); statErr == nil {
+			artifacts.BaselinePath = written.BaselinePath
+		}
+		s.publishLocked(Event{Type: EventSessionClosed, SessionID: s.id, Artifacts: &artifacts})
+	}
+	for _, sub := range s.subscriptions {
+		sub.close()
+	}
+	s.subscriptions = nil
+	return err
+}
+
+func (s *Session) publishTurnLocked(turn *demo.TurnResult) {
+	s.publishLocked(Event{Type: EventTurnCompleted, SessionID: s.id, Turn: &Turn{
+		TurnID:          turn.TurnID,
+		Transcript:      turn.Transcript,
+		Reply:           turn.Reply,
+		ReplyPCM:        turn.ReplyPCM,
+		Committed:       turn.Committed,
+		FallbackReason:  turn.FallbackReason,
+		CaptureEndAtMS:  turn.CaptureEndAtMS,
+		FirstOutputAtMS: turn.FirstOutputAtMS,
+	}})
+}
+
+func (s *Session) publishLocked(event Event) {
+	for _, sub := range s.subscriptions {
+		sub.send(event)
+	}
+}
+
+// Subscription delivers one subscriber's session events in publish order.
+type Subscription struct {
+	events chan Event
+
+	mu      sync.Mutex
+	closed  bool
+	dropped int64
+}
+
+// Events returns the event channel; it is closed after session_closed or Unsubscribe.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Dropped returns how many events did not fit the subscription buffer.
+func (s *Subscription) Dropped() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Unsubscribe stops delivery and closes the event channel.
+func (s *Subscription) Unsubscribe() {
+	s.close()
+}
+
+func (s *Subscription) send(event Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.events <- event:
+	default:
+		s.dropped++
+	}
+}
+
+func (s *Subscription) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.events)
+	}
+}
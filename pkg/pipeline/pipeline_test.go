@@ -0,0 +1,289 @@
+package pipeline
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+)
+
+// steppingClock advances by stepMS on every read so session timestamps are deterministic.
+func steppingClock(stepMS int64) func() time.Time {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(time.Duration(stepMS) * time.Millisecond)
+		return now
+	}
+}
+
+func voicedPCM(samples int) []int16 {
+	pcm := make([]int16, samples)
+	for i := range pcm {
+		pcm[i] = 4000
+	}
+	return pcm
+}
+
+// upperLLM is an embedder LLM adapter replying with the latest user line in upper case.
+type upperLLM struct{}
+
+func (upperLLM) ProviderID() string { return "llm-upper" }
+func (upperLLM) Modality() Modality { return ModalityLLM }
+
+func (upperLLM) Invoke(req InvocationRequest) (Outcome, error) {
+	transcript := req.Prompt
+	if idx := strings.LastIndex(transcript, "User: "); idx >= 0 {
+		transcript = transcript[idx+len("User: "):]
+	}
+	return Outcome{Class: OutcomeSuccess, Text: strings.ToUpper(transcript)}, nil
+}
+
+// overloadedLLM is a preferred LLM adapter whose provider is always overloaded.
+type overloadedLLM struct{}
+
+func (overloadedLLM) ProviderID() string { return "llm-overloaded" }
+func (overloadedLLM) Modality() Modality { return ModalityLLM }
+
+func (overloadedLLM) Invoke(InvocationRequest) (Outcome, error) {
+	return Outcome{Class: OutcomeOverload, Reason: "provider_overloaded"}, nil
+}
+
+// blockingLLM holds each invocation until its turn is cancelled, reporting whether the
+// cancel reached it.
+type blockingLLM struct {
+	started   chan struct{}
+	cancelled chan bool
+}
+
+func (blockingLLM) ProviderID() string { return "llm-blocking" }
+func (blockingLLM) Modality() Modality { return ModalityLLM }
+
+func (l blockingLLM) Invoke(req InvocationRequest) (Outcome, error) {
+	l.started <- struct{}{}
+	select {
+	case <-req.CancelSignal:
+		l.cancelled <- true
+		return Outcome{Class: OutcomeCancelled, Reason: "turn_cancelled"}, nil
+	case <-time.After(5 * time.Second):
+		l.cancelled <- false
+		return Outcome{Class: OutcomeTimeout, Reason: "cancel_never_arrived"}, nil
+	}
+}
+
+func drain(sub *Subscription) []Event {
+	var events []Event
+	for event := range sub.Events() {
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestSessionPublishesTurnEventsUntilClose(t *testing.T) {
+	t.Parallel()
+
+	engine, err := NewEngine(Config{ArtifactsDir: t.TempDir(), Adapters: []Adapter{upperLLM{}}, Clock: steppingClock(10)})
+	if err != nil {
+		t.Fatalf("unexpected engine error: %v", err)
+	}
+	session, err := engine.StartSession("sdk-1")
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	sub := session.Subscribe(0)
+
+	if err := session.PushAudio(voicedPCM(4000)); err != nil {
+		t.Fatalf("unexpected push audio error: %v", err)
+	}
+	if err := session.Cancel(); err != nil {
+		t.Fatalf("unexpected cancel error: %v", err)
+	}
+	if err := session.PushAudio(voicedPCM(8000)); err != nil {
+		t.Fatalf("unexpected push audio error: %v", err)
+	}
+	if err := session.EndAudio(); err != nil {
+		t.Fatalf("unexpected end audio error: %v", err)
+	}
+	if err := session.PushText("book a table"); err != nil {
+		t.Fatalf("unexpected push text error: %v", err)
+	}
+	if err := session.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	events := drain(sub)
+	want := []EventType{EventCaptureStarted, EventCaptureCancelled, EventCaptureStarted, EventTurnCompleted, EventTurnCompleted, EventSessionClosed}
+	if len(events) != len(want) {
+		t.Fatalf("expected events %v, got %+v", want, events)
+	}
+	for idx, event := range events {
+		if event.Type != want[idx] || event.SessionID != "sdk-1" {
+			t.Fatalf("event %d: expected %s for sdk-1, got %+v", idx, want[idx], event)
+		}
+	}
+	audioTurn, textTurn := events[3].Turn, events[4].Turn
+	if audioTurn.TurnID != "sdk-1-turn-1" || !strings.Contains(audioTurn.Transcript, "0.5 seconds") || len(audioTurn.ReplyPCM) == 0 || !audioTurn.Committed {
+		t.Fatalf("unexpected audio turn %+v", audioTurn)
+	}
+	if textTurn.Transcript != "book a table" || textTurn.Reply != "BOOK A TABLE" {
+		t.Fatalf("expected text turn answered by the bound llm, got %+v", textTurn)
+	}
+	artifacts := events[5].Artifacts
+	baseline, err := timeline.ReadBaselineArtifact(artifacts.BaselinePath)
+	if err != nil || len(baseline.Entries) != 2 {
+		t.Fatalf("expected a two-turn baseline, got %+v err=%v", baseline, err)
+	}
+	if sub.Dropped() != 0 || len(engine.Sessions()) != 0 {
+		t.Fatalf("expected no drops and the session released, got dropped=%d sessions=%v", sub.Dropped(), engine.Sessions())
+	}
+	if err := session.PushText("again"); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected closed session to reject pushes, got %v", err)
+	}
+}
+
+func TestSubscriptionDropsEventsBeyondBuffer(t *testing.T) {
+	t.Parallel()
+
+	engine, err := NewEngine(Config{ArtifactsDir: t.TempDir(), Clock: steppingClock(10)})
+	if err != nil {
+		t.Fatalf("unexpected engine error: %v", err)
+	}
+	session, err := engine.StartSession("sdk-2")
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	sub := session.Subscribe(1)
+	unsubscribed := session.Subscribe(1)
+	unsubscribed.Unsubscribe()
+	for _, text := range []string{"one", "two", "three"} {
+		if err := session.PushText(text); err != nil {
+			t.Fatalf("unexpected push text error: %v", err)
+		}
+	}
+	if err := engine.Close(); err != nil {
+		t.Fatalf("unexpected engine close error: %v", err)
+	}
+	events := drain(sub)
+	if len(events) != 1 || events[0].Turn.Transcript != "one" || sub.Dropped() != 3 {
+		t.Fatalf("expected the first turn kept and 3 events dropped, got %+v dropped=%d", events, sub.Dropped())
+	}
+	if leftover := drain(unsubscribed); len(leftover) != 0 || unsubscribed.Dropped() != 0 {
+		t.Fatalf("expected no delivery after unsubscribe, got %+v", leftover)
+	}
+	if _, err := engine.StartSession("sdk-3"); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected closed engine to reject sessions, got %v", err)
+	}
+	if late := drain(session.Subscribe(1)); len(late) != 0 {
+		t.Fatalf("expected a closed session subscription to be closed, got %+v", late)
+	}
+}
+
+func TestEngineValidatesConfigAndSessionIDs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		cfg       Config
+		sessionID string
+		shouldErr bool
+	}{
+		{name: "valid", cfg: Config{ArtifactsDir: t.TempDir()}, sessionID: "sdk-ok"},
+		{name: "missing artifacts dir", cfg: Config{}, shouldErr: true},
+		{name: "negative sample rate", cfg: Config{ArtifactsDir: t.TempDir(), SampleRateHz: -1}, shouldErr: true},
+		{name: "invalid session id", cfg: Config{ArtifactsDir: t.TempDir()}, sessionID: "", shouldErr: true},
+	}
+	for _, tc := range tests {
+		engine, err := NewEngine(tc.cfg)
+		if err == nil {
+			_, err = engine.StartSession(tc.sessionID)
+		}
+		if tc.shouldErr != (err != nil) {
+			t.Fatalf("%s: expected error=%t, got %v", tc.name, tc.shouldErr, err)
+		}
+	}
+
+	engine, err := NewEngine(Config{ArtifactsDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("unexpected engine error: %v", err)
+	}
+	if _, err := engine.StartSession("sdk-dup"); err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	if _, err := engine.StartSession("sdk-dup"); err == nil {
+		t.Fatalf("expected a duplicate open session id to fail")
+	}
+}
+
+func TestEngineFailsOverAcrossAdapters(t *testing.T) {
+	t.Parallel()
+
+	engine, err := NewEngine(Config{ArtifactsDir: t.TempDir(), Adapters: []Adapter{overloadedLLM{}, upperLLM{}}, Clock: steppingClock(10)})
+	if err != nil {
+		t.Fatalf("unexpected engine error: %v", err)
+	}
+	session, err := engine.StartSession("sdk-failover")
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	sub := session.Subscribe(0)
+	if err := session.PushText("hello there"); err != nil {
+		t.Fatalf("unexpected push text error: %v", err)
+	}
+	if err := session.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	events := drain(sub)
+	if len(events) != 2 || events[0].Turn == nil || events[0].Turn.Reply != "HELLO THERE" || !events[0].Turn.Committed {
+		t.Fatalf("expected the turn answered by the failover adapter, got %+v", events)
+	}
+	baseline, err := timeline.ReadBaselineArtifact(events[1].Artifacts.BaselinePath)
+	if err != nil {
+		t.Fatalf("unexpected baseline error: %v", err)
+	}
+	var llm *timeline.InvocationOutcomeEvidence
+	for idx, outcome := range baseline.Entries[0].InvocationOutcomes {
+		if outcome.Modality == string(ModalityLLM) {
+			llm = &baseline.Entries[0].InvocationOutcomes[idx]
+		}
+	}
+	if llm == nil || llm.ProviderID != "llm-upper" || llm.OutcomeClass != string(OutcomeSuccess) {
+		t.Fatalf("expected the llm invocation recorded against the failover adapter, got %+v", baseline.Entries[0].InvocationOutcomes)
+	}
+}
+
+func TestSessionCancelAbortsInFlightTurn(t *testing.T) {
+	t.Parallel()
+
+	llm := blockingLLM{started: make(chan struct{}, 1), cancelled: make(chan bool, 1)}
+	engine, err := NewEngine(Config{ArtifactsDir: t.TempDir(), Adapters: []Adapter{llm}})
+	if err != nil {
+		t.Fatalf("unexpected engine error: %v", err)
+	}
+	defer engine.Close()
+	session, err := engine.StartSession("sdk-cancel")
+	if err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	sub := session.Subscribe(0)
+	done := make(chan error, 1)
+	go func() { done <- session.PushText("hold on") }()
+	select {
+	case <-llm.started:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for the llm invocation")
+	}
+	if err := session.Cancel(); err != nil {
+		t.Fatalf("unexpected cancel error: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected push text error: %v", err)
+	}
+	if !<-llm.cancelled {
+		t.Fatalf("expected the cancel to reach the in-flight llm invocation")
+	}
+	event := <-sub.Events()
+	if event.Type != EventTurnCompleted || event.Turn.FallbackReason == "" {
+		t.Fatalf("expected the cancelled turn to complete with a fallback, got %s fallback=%q", event.Type, event.Turn.FallbackReason)
+	}
+}
@@ -1,19 +1,34 @@
 package httpadapter
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/recording"
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/httpclient"
+)
+
+const (
+	// maxValidatedResponseBytes caps response bodies buffered for ValidateResponse, ResponseText,
+	// and ResponseAudio.
+	maxValidatedResponseBytes = 32 << 20
+	// maxStreamLineBytes caps one line of a streamed response.
+	maxStreamLineBytes = 1 << 20
 )
 
 // Config configures a generic JSON-over-HTTP provider adapter.
@@ -29,12 +44,54 @@ type Config struct {
 	StaticHeaders    map[string]string
 	Timeout          time.Duration
 	BuildBody        func(req contracts.InvocationRequest) any
+	// BuildRawBody, when set and returning a non-nil body, sends that body with contentType in
+	// place of the JSON BuildBody payload, as for audio uploads.
+	BuildRawBody func(req contracts.InvocationRequest) (body []byte, contentType string, err error)
+	// ValidateResponse optionally inspects successful response bodies. A non-nil error
+	// normalizes the attempt to a non-retryable output failure.
+	ValidateResponse func(req contracts.InvocationRequest, body []byte) error
+	// ValidationFailureReason overrides the outcome reason for ValidateResponse failures.
+	ValidationFailureReason string
+	// ValidationFailureClass overrides the outcome class for ValidateResponse failures;
+	// quality checks set contracts.OutcomeQualityFailure. Defaults to infrastructure_failure.
+	ValidationFailureClass contracts.OutcomeClass
+	// StreamUsage, when set, consumes successful responses as a line-delimited stream (for
+	// example SSE) and folds each non-empty line into usage. The stream is aborted when the
+	// request CancelSignal closes. ValidateResponse is not applied to streamed responses.
+	StreamUsage func(line []byte, usage *contracts.TokenUsage)
+	// StreamText, when set with StreamUsage, returns the output text delta carried by one stream
+	// line; a completed stream reports the joined deltas as Outcome.Text.
+	StreamText func(line []byte) string
+	// ResponseText, when set, extracts the output text of a successful non-streamed response
+	// body into Outcome.Text.
+	ResponseText func(body []byte) string
+	// ResponseConfidence, when set, extracts the transcript confidence of a successful
+	// non-streamed STT response body into Outcome.Confidence.
+	ResponseConfidence func(body []byte) float64
+	// ResponseAudio, when set, decodes the synthesized audio of a successful non-streamed
+	// response body into Outcome.Audio; a nil output leaves it unset and an error normalizes the
+	// attempt to a non-retryable invalid-output failure.
+	ResponseAudio func(req contracts.InvocationRequest, body []byte) (*contracts.AudioOutput, error)
+	// ServerEndpointing declares provider-side STT endpointing; requests then carry
+	// InvocationRequest.Endpointing for BuildBody/BuildQuery to map onto provider parameters.
+	ServerEndpointing bool
+	// BuildQuery optionally adds per-request query parameters to the endpoint.
+	BuildQuery func(req contracts.InvocationRequest) map[string]string
+	// HTTPClient overrides the shared provider client factory (httpclient.Shared).
+	HTTPClient *http.Client
+	// DisableIdempotencyKey omits the Idempotency-Key header for providers that reject it.
+	DisableIdempotencyKey bool
+	// StandbyPath is the authenticated liveness route on the endpoint origin HoldStandby
+	// probes, "/" when empty; only a 2xx response there holds the standby.
+	StandbyPath string
 }
 
 // Adapter implements contracts.Adapter against a JSON-over-HTTP endpoint.
 type Adapter struct {
 	cfg    Config
 	client *http.Client
+	// standby is set while HoldStandby keeps an authenticated pooled connection open.
+	standby atomic.Bool
 }
 
 // New constructs a generic HTTP adapter.
@@ -62,7 +119,17 @@ func New(cfg Config) (*Adapter, error) {
 	if cfg.StaticHeaders == nil {
 		cfg.StaticHeaders = map[string]string{}
 	}
-	return &Adapter{cfg: cfg, client: &http.Client{}}, nil
+	if cfg.ValidationFailureReason == "" {
+		cfg.ValidationFailureReason = "provider_output_invalid"
+	}
+	if cfg.ValidationFailureClass == "" {
+		cfg.ValidationFailureClass = contracts.OutcomeInfrastructureFailure
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = httpclient.Shared().Client(cfg.ProviderID)
+	}
+	return &Adapter{cfg: cfg, client: client}, nil
 }
 
 // ProviderID returns provider identity.
@@ -75,6 +142,11 @@ func (a *Adapter) Modality() contracts.Modality {
 	return a.cfg.Modality
 }
 
+// SupportsServerEndpointing reports whether the provider applies STT endpointing itself.
+func (a *Adapter) SupportsServerEndpointing() bool {
+	return a.cfg.ServerEndpointing
+}
+
 // Invoke executes one provider attempt and normalizes the outcome.
 func (a *Adapter) Invoke(req contracts.InvocationRequest) (contracts.Outcome, error) {
 	if err := req.Validate(); err != nil {
@@ -87,7 +159,7 @@ func (a *Adapter) Invoke(req contracts.InvocationRequest) (contracts.Outcome, er
 		return contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_endpoint_missing"}, nil
 	}
 
-	body, err := json.Marshal(a.cfg.BuildBody(req))
+	body, contentType, err := a.requestBody(req)
 	if err != nil {
 		return contracts.Outcome{}, err
 	}
@@ -99,29 +171,270 @@ func (a *Adapter) Invoke(req contracts.InvocationRequest) (contracts.Outcome, er
 			return contracts.Outcome{}, err
 		}
 	}
+	if a.cfg.BuildQuery != nil {
+		for key, value := range a.cfg.BuildQuery(req) {
+			endpoint, err = withQuery(endpoint, key, value)
+			if err != nil {
+				return contracts.Outcome{}, err
+			}
+		}
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.Timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), req.AttemptTimeout(a.cfg.Timeout))
 	defer cancel()
+	if req.CancelSignal != nil {
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-req.CancelSignal:
+				cancel()
+			case <-done:
+			}
+		}()
+	}
 
 	httpReq, err := http.NewRequestWithContext(ctx, a.cfg.Method, endpoint, bytes.NewReader(body))
 	if err != nil {
 		return contracts.Outcome{}, err
 	}
-	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Content-Type", contentType)
 	if a.cfg.APIKeyHeader != "" && a.cfg.APIKey != "" {
 		httpReq.Header.Set(a.cfg.APIKeyHeader, a.cfg.APIKeyPrefix+a.cfg.APIKey)
 	}
+	if !a.cfg.DisableIdempotencyKey {
+		// Stable across attempts of one invocation so provider-side dedupe and transport
+		// replays on stale pooled connections never double-execute a request.
+		httpReq.Header.Set(httpclient.IdempotencyKeyHeader, req.ProviderInvocationID+":"+a.cfg.ProviderID)
+	}
 	for key, value := range a.cfg.StaticHeaders {
 		httpReq.Header.Set(key, value)
 	}
+	if req.Traceparent != "" {
+		httpReq.Header.Set(telemetry.TraceparentHeader, req.Traceparent)
+	}
 
+	started := time.Now()
 	resp, err := a.client.Do(httpReq)
 	if err != nil {
-		return normalizeNetworkError(err), nil
+		if req.CancelSignalled() {
+			return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, nil
+		}
+		return NormalizeNetworkError(err), nil
 	}
 	defer resp.Body.Close()
 
-	return normalizeStatus(resp.StatusCode, resp.Header.Get("Retry-After")), nil
+	outcome := NormalizeStatus(resp.StatusCode, resp.Header.Get("Retry-After"))
+	if outcome.Class == contracts.OutcomeSuccess && a.cfg.StreamUsage != nil {
+		return a.consumeStream(resp.Body, req, started), nil
+	}
+	if outcome.Class != contracts.OutcomeSuccess || (a.cfg.ValidateResponse == nil && a.cfg.ResponseText == nil && a.cfg.ResponseConfidence == nil && a.cfg.ResponseAudio == nil) {
+		return outcome, nil
+	}
+	payload, err := io.ReadAll(io.LimitReader(resp.Body, maxValidatedResponseBytes))
+	if err != nil {
+		return NormalizeNetworkError(err), nil
+	}
+	if a.cfg.ValidateResponse != nil {
+		if err := a.cfg.ValidateResponse(req, payload); err != nil {
+			return contracts.Outcome{Class: a.cfg.ValidationFailureClass, Retryable: false, Reason: a.cfg.ValidationFailureReason}, nil
+		}
+	}
+	if a.cfg.ResponseText != nil {
+		outcome.Text = a.cfg.ResponseText(payload)
+	}
+	if a.cfg.ResponseConfidence != nil {
+		outcome.Confidence = a.cfg.ResponseConfidence(payload)
+	}
+	if a.cfg.ResponseAudio != nil {
+		audio, err := a.cfg.ResponseAudio(req, payload)
+		if err != nil {
+			return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: false, Reason: "provider_output_invalid"}, nil
+		}
+		outcome.Audio = audio
+	}
+	return outcome, nil
+}
+
+// requestBody returns the raw body from BuildRawBody when it supplies one and otherwise the
+// JSON-encoded BuildBody payload.
+func (a *Adapter) requestBody(req contracts.InvocationRequest) ([]byte, string, error) {
+	if a.cfg.BuildRawBody != nil {
+		body, contentType, err := a.cfg.BuildRawBody(req)
+		if err != nil {
+			return nil, "", err
+		}
+		if body != nil {
+			return body, contentType, nil
+		}
+	}
+	body, err := json.Marshal(a.cfg.BuildBody(req))
+	if err != nil {
+		return nil, "", err
+	}
+	return body, "application/json", nil
+}
+
+// WAVBody encodes captured audio as a 16-bit mono WAV request body.
+func WAVBody(audio *contracts.AudioInput) ([]byte, error) {
+	var body bytes.Buffer
+	if err := (recording.WAVEncoder{}).Encode(&body, audio.PCM, audio.SampleRateHz, 1); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// consumeStream reads a streamed response until it ends or the turn is cancelled. A cancel
+// aborts the stream and reports cancelled (not timeout/failure) with the usage observed so far.
+// A completed stream reports the latency of its first non-empty line as first-chunk latency.
+func (a *Adapter) consumeStream(body io.Reader, req contracts.InvocationRequest, started time.Time) contracts.Outcome {
+	usage := &contracts.TokenUsage{}
+	var text strings.Builder
+	firstChunkLatencyMS := int64(0)
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 4096), maxStreamLineBytes)
+	for scanner.Scan() {
+		if req.CancelSignalled() {
+			break
+		}
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if firstChunkLatencyMS == 0 {
+			firstChunkLatencyMS = max(time.Since(started).Milliseconds(), 1)
+		}
+		a.cfg.StreamUsage(line, usage)
+		if a.cfg.StreamText != nil {
+			text.WriteString(a.cfg.StreamText(line))
+		}
+	}
+	if req.CancelSignalled() {
+		usage.Truncated = true
+		return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_stream_cancelled", Usage: usage}
+	}
+	if err := scanner.Err(); err != nil {
+		outcome := NormalizeNetworkError(err)
+		outcome.Usage = usage
+		return outcome
+	}
+	return contracts.Outcome{Class: contracts.OutcomeSuccess, Usage: usage, FirstChunkLatencyMS: firstChunkLatencyMS, Text: text.String()}
+}
+
+// Warm pre-establishes a pooled connection (including TLS) to the endpoint origin so the
+// first invocation skips DNS, dial, and handshake latency. Any HTTP response counts as
+// warmed; the request carries no credentials and no invocation payload.
+func (a *Adapter) Warm() contracts.WarmupResult {
+	result := contracts.WarmupResult{ProviderID: a.cfg.ProviderID, Modality: a.cfg.Modality}
+	if a.cfg.Endpoint == "" {
+		result.Reason = "provider_endpoint_missing"
+		return result
+	}
+	origin, err := url.Parse(a.cfg.Endpoint)
+	if err != nil || origin.Scheme == "" || origin.Host == "" {
+		result.Reason = "provider_endpoint_invalid"
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.Timeout)
+	defer cancel()
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			result.ConnectionReused = info.Reused
+		},
+	})
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodHead, origin.Scheme+"://"+origin.Host+"/", nil)
+	if err != nil {
+		result.Reason = "provider_endpoint_invalid"
+		return result
+	}
+	started := time.Now()
+	resp, err := a.client.Do(httpReq)
+	result.LatencyMS = time.Since(started).Milliseconds()
+	if err != nil {
+		result.Reason = NormalizeNetworkError(err).Reason
+		return result
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	result.Warmed = true
+	return result
+}
+
+// HoldStandby opens, or refreshes, an authenticated pooled connection to the endpoint origin
+// so a provider_switch onto this provider skips DNS, dial, handshake, and credential checks.
+// Unlike Warm the request carries credentials and the standby is held only when the
+// StandbyPath liveness route answers 2xx: an auth rejection, an error status, or a transport
+// failure leaves no standby held. Callers refresh the hold within the pool idle timeout to
+// keep the connection open.
+func (a *Adapter) HoldStandby() contracts.StandbyResult {
+	result := contracts.StandbyResult{ProviderID: a.cfg.ProviderID, Modality: a.cfg.Modality}
+	defer func() { a.standby.Store(result.Held) }()
+	if a.cfg.Endpoint == "" {
+		result.Reason = "provider_endpoint_missing"
+		return result
+	}
+	origin, err := url.Parse(a.cfg.Endpoint)
+	if err != nil || origin.Scheme == "" || origin.Host == "" {
+		result.Reason = "provider_endpoint_invalid"
+		return result
+	}
+	path := a.cfg.StandbyPath
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	target := origin.Scheme + "://" + origin.Host + path
+	if a.cfg.QueryAPIKeyParam != "" && a.cfg.APIKey != "" {
+		if target, err = withQuery(target, a.cfg.QueryAPIKeyParam, a.cfg.APIKey); err != nil {
+			result.Reason = "provider_endpoint_invalid"
+			return result
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.Timeout)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		result.Reason = "provider_endpoint_invalid"
+		return result
+	}
+	if a.cfg.APIKeyHeader != "" && a.cfg.APIKey != "" {
+		httpReq.Header.Set(a.cfg.APIKeyHeader, a.cfg.APIKeyPrefix+a.cfg.APIKey)
+	}
+	for key, value := range a.cfg.StaticHeaders {
+		httpReq.Header.Set(key, value)
+	}
+	started := time.Now()
+	resp, err := a.client.Do(httpReq)
+	result.LatencyMS = time.Since(started).Milliseconds()
+	if err != nil {
+		result.Reason = NormalizeNetworkError(err).Reason
+		return result
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		result.Reason = "provider_auth_rejected"
+		return result
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		result.Reason = NormalizeStatus(resp.StatusCode, "").Reason
+		return result
+	}
+	result.Held = true
+	return result
+}
+
+// ReleaseStandby stops treating the pooled connection as a held standby; the pool closes it
+// once idle.
+func (a *Adapter) ReleaseStandby() {
+	a.standby.Store(false)
+}
+
+// StandbyHeld reports whether the last HoldStandby left an authenticated connection open.
+func (a *Adapter) StandbyHeld() bool {
+	return a.standby.Load()
 }
 
 func withQuery(rawEndpoint string, key string, value string) (string, error) {
@@ -135,7 +448,8 @@ func withQuery(rawEndpoint string, key string, value string) (string, error) {
 	return u.String(), nil
 }
 
-func normalizeNetworkError(err error) contracts.Outcome {
+// NormalizeNetworkError maps a request transport error to a normalized outcome.
+func NormalizeNetworkError(err error) contracts.Outcome {
 	if errors.Is(err, context.Canceled) {
 		return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}
 	}
@@ -149,7 +463,8 @@ func normalizeNetworkError(err error) contracts.Outcome {
 	return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: true, Reason: "provider_transport_error"}
 }
 
-func normalizeStatus(status int, retryAfter string) contracts.Outcome {
+// NormalizeStatus maps a provider HTTP status (and Retry-After header) to a normalized outcome.
+func NormalizeStatus(status int, retryAfter string) contracts.Outcome {
 	switch {
 	case status >= 200 && status <= 299:
 		return contracts.Outcome{Class: contracts.OutcomeSuccess}
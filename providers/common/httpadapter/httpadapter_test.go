@@ -1,9 +1,12 @@
 package httpadapter
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
 )
@@ -103,3 +106,468 @@ func TestInvokeCancelledShortCircuit(t *testing.T) {
 		t.Fatalf("expected cancelled outcome, got %s", outcome.Class)
 	}
 }
+
+func TestInvokeValidateResponse(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("broken-audio"))
+	}))
+	defer ts.Close()
+
+	var inspected string
+	adapter, err := New(Config{
+		ProviderID:              "provider-a",
+		Modality:                contracts.ModalityTTS,
+		Endpoint:                ts.URL,
+		ValidationFailureReason: "provider_output_quality_failed",
+		ValidationFailureClass:  contracts.OutcomeQualityFailure,
+		ValidateResponse: func(_ contracts.InvocationRequest, body []byte) error {
+			inspected = string(body)
+			return errors.New("clipped")
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected adapter error: %v", err)
+	}
+	outcome, err := adapter.Invoke(contracts.InvocationRequest{
+		SessionID:            "sess-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-1",
+		ProviderInvocationID: "pvi-1",
+		ProviderID:           "provider-a",
+		Modality:             contracts.ModalityTTS,
+		Attempt:              1,
+		TransportSequence:    1,
+		RuntimeSequence:      1,
+		AuthorityEpoch:       1,
+		RuntimeTimestampMS:   1,
+		WallClockTimestampMS: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if inspected != "broken-audio" {
+		t.Fatalf("expected validator to inspect response body, got %q", inspected)
+	}
+	if outcome.Class != contracts.OutcomeQualityFailure || outcome.Retryable || outcome.Reason != "provider_output_quality_failed" {
+		t.Fatalf("expected non-retryable quality failure outcome, got %+v", outcome)
+	}
+}
+
+func TestInvokeResponseAudio(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte{0x01, 0x00})
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name       string
+		decodeErr  error
+		wantClass  contracts.OutcomeClass
+		wantSample bool
+	}{
+		{name: "decoded", wantClass: contracts.OutcomeSuccess, wantSample: true},
+		{name: "undecodable", decodeErr: errors.New("truncated"), wantClass: contracts.OutcomeInfrastructureFailure},
+	}
+	for _, tc := range tests {
+		adapter, err := New(Config{
+			ProviderID: "provider-a",
+			Modality:   contracts.ModalityTTS,
+			Endpoint:   ts.URL,
+			ResponseAudio: func(req contracts.InvocationRequest, body []byte) (*contracts.AudioOutput, error) {
+				if tc.decodeErr != nil {
+					return nil, tc.decodeErr
+				}
+				return &contracts.AudioOutput{PCM: []int16{int16(body[0])}, SampleRateHz: 16000}, nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected adapter error: %v", tc.name, err)
+		}
+		outcome, err := adapter.Invoke(contracts.InvocationRequest{
+			SessionID:            "sess-1",
+			PipelineVersion:      "pipeline-v1",
+			EventID:              "evt-1",
+			ProviderInvocationID: "pvi-1",
+			ProviderID:           "provider-a",
+			Modality:             contracts.ModalityTTS,
+			Attempt:              1,
+			Text:                 "hello",
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		if outcome.Class != tc.wantClass {
+			t.Fatalf("%s: expected %s, got %+v", tc.name, tc.wantClass, outcome)
+		}
+		if got := outcome.Audio != nil && len(outcome.Audio.PCM) == 1 && outcome.Audio.PCM[0] == 1; got != tc.wantSample {
+			t.Fatalf("%s: expected decoded audio %v, got %+v", tc.name, tc.wantSample, outcome.Audio)
+		}
+	}
+}
+
+func TestWarmPreEstablishesConnection(t *testing.T) {
+	t.Parallel()
+
+	var methods []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	adapter, err := New(Config{ProviderID: "provider-a", Modality: contracts.ModalityLLM, Endpoint: ts.URL + "/v1/messages"})
+	if err != nil {
+		t.Fatalf("unexpected constructor error: %v", err)
+	}
+	first := adapter.Warm()
+	if !first.Warmed || first.ProviderID != "provider-a" || first.ConnectionReused {
+		t.Fatalf("expected cold connection to warm, got %+v", first)
+	}
+	second := adapter.Warm()
+	if !second.Warmed || !second.ConnectionReused {
+		t.Fatalf("expected warmed connection to be reused, got %+v", second)
+	}
+	if len(methods) != 2 || methods[0] != http.MethodHead {
+		t.Fatalf("expected HEAD warm-up requests, got %v", methods)
+	}
+
+	missing, err := New(Config{ProviderID: "provider-b", Modality: contracts.ModalityLLM})
+	if err != nil {
+		t.Fatalf("unexpected constructor error: %v", err)
+	}
+	if result := missing.Warm(); result.Warmed || result.Reason != "provider_endpoint_missing" {
+		t.Fatalf("expected missing endpoint warm-up failure, got %+v", result)
+	}
+}
+
+func TestHoldStandbyAuthenticatesPooledConnection(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("x-api-key") != "secret":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v1/models":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/v1/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name        string
+		apiKey      string
+		standbyPath string
+		wantHeld    bool
+		wantReason  string
+	}{
+		{name: "valid credentials on liveness route", apiKey: "secret", standbyPath: "/v1/models", wantHeld: true},
+		{name: "rejected credentials", apiKey: "wrong", standbyPath: "/v1/models", wantReason: "provider_auth_rejected"},
+		{name: "server error", apiKey: "secret", standbyPath: "/v1/down", wantReason: "provider_server_error"},
+		{name: "no liveness route", apiKey: "secret", wantReason: "provider_client_error"},
+	}
+	for _, tc := range tests {
+		adapter, err := New(Config{ProviderID: "provider-a", Modality: contracts.ModalityLLM, Endpoint: ts.URL + "/v1/messages", APIKey: tc.apiKey, APIKeyHeader: "x-api-key", StandbyPath: tc.standbyPath})
+		if err != nil {
+			t.Fatalf("%s: unexpected constructor error: %v", tc.name, err)
+		}
+		result := adapter.HoldStandby()
+		if result.Held != tc.wantHeld || result.Reason != tc.wantReason || contracts.HoldsStandby(adapter) != tc.wantHeld {
+			t.Fatalf("%s: expected held=%t reason=%q, got %+v", tc.name, tc.wantHeld, tc.wantReason, result)
+		}
+		adapter.ReleaseStandby()
+		if adapter.StandbyHeld() {
+			t.Fatalf("%s: expected release to drop the standby", tc.name)
+		}
+	}
+}
+
+func TestInvokeForwardsTraceparent(t *testing.T) {
+	t.Parallel()
+
+	var headers []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Get("traceparent"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	adapter, err := New(Config{ProviderID: "provider-a", Modality: contracts.ModalityLLM, Endpoint: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected constructor error: %v", err)
+	}
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	for _, value := range []string{traceparent, ""} {
+		if _, err := adapter.Invoke(contracts.InvocationRequest{
+			SessionID:            "sess-1",
+			PipelineVersion:      "pipeline-v1",
+			EventID:              "evt-1",
+			ProviderInvocationID: "pvi-1",
+			ProviderID:           "provider-a",
+			Modality:             contracts.ModalityLLM,
+			Attempt:              1,
+			Traceparent:          value,
+		}); err != nil {
+			t.Fatalf("unexpected invoke error: %v", err)
+		}
+	}
+	if len(headers) != 2 || headers[0] != traceparent || headers[1] != "" {
+		t.Fatalf("expected traceparent forwarded only when set, got %v", headers)
+	}
+}
+
+func TestInvokeBoundsTimeoutByAttemptDeadline(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	adapter, err := New(Config{ProviderID: "provider-a", Modality: contracts.ModalityLLM, Endpoint: ts.URL, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("unexpected constructor error: %v", err)
+	}
+	started := time.Now()
+	outcome, err := adapter.Invoke(contracts.InvocationRequest{
+		SessionID:            "sess-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-1",
+		ProviderInvocationID: "pvi-1",
+		ProviderID:           "provider-a",
+		Modality:             contracts.ModalityLLM,
+		Attempt:              1,
+		DeadlineMS:           50,
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeTimeout || time.Since(started) > 5*time.Second {
+		t.Fatalf("expected the attempt deadline to time out the request, got %+v after %s", outcome, time.Since(started))
+	}
+}
+
+func TestInvokeSetsStableIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	var keys []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	adapter, err := New(Config{ProviderID: "provider-a", Modality: contracts.ModalityLLM, Endpoint: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected constructor error: %v", err)
+	}
+	for attempt := 1; attempt <= 2; attempt++ {
+		if _, err := adapter.Invoke(contracts.InvocationRequest{
+			SessionID:            "sess-1",
+			PipelineVersion:      "pipeline-v1",
+			EventID:              "evt-1",
+			ProviderInvocationID: "pvi-1",
+			ProviderID:           "provider-a",
+			Modality:             contracts.ModalityLLM,
+			Attempt:              attempt,
+		}); err != nil {
+			t.Fatalf("unexpected invoke error: %v", err)
+		}
+	}
+	if len(keys) != 2 || keys[0] != "pvi-1:provider-a" || keys[1] != keys[0] {
+		t.Fatalf("expected stable idempotency key across attempts, got %v", keys)
+	}
+}
+
+func TestInvokeStreamUsage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		cancelAfter   int
+		wantClass     contracts.OutcomeClass
+		wantOutput    int
+		wantTruncated bool
+	}{
+		{name: "stream completes", wantClass: contracts.OutcomeSuccess, wantOutput: 3},
+		{name: "cancel aborts stream", cancelAfter: 2, wantClass: contracts.OutcomeCancelled, wantOutput: 2, wantTruncated: true},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				flusher := w.(http.Flusher)
+				for _, line := range []string{"input", "token", "token", "token"} {
+					_, _ = w.Write([]byte(line + "\n\n"))
+					flusher.Flush()
+				}
+				if tc.cancelAfter > 0 {
+					// Hold the stream open so only the client abort ends it.
+					<-r.Context().Done()
+				}
+			}))
+			defer ts.Close()
+
+			cancelSignal := make(chan struct{})
+			adapter, err := New(Config{
+				ProviderID: "provider-a",
+				Modality:   contracts.ModalityLLM,
+				Endpoint:   ts.URL,
+				StreamUsage: func(line []byte, usage *contracts.TokenUsage) {
+					switch string(line) {
+					case "input":
+						usage.InputTokens = 7
+					case "token":
+						usage.OutputTokens++
+						if usage.OutputTokens == tc.cancelAfter {
+							close(cancelSignal)
+						}
+					}
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected adapter error: %v", err)
+			}
+			outcome, err := adapter.Invoke(contracts.InvocationRequest{
+				SessionID:            "sess-1",
+				PipelineVersion:      "pipeline-v1",
+				EventID:              "evt-1",
+				ProviderInvocationID: "pvi-1",
+				ProviderID:           "provider-a",
+				Modality:             contracts.ModalityLLM,
+				Attempt:              1,
+				CancelSignal:         cancelSignal,
+			})
+			if err != nil {
+				t.Fatalf("unexpected invoke error: %v", err)
+			}
+			if outcome.Class != tc.wantClass || outcome.Retryable {
+				t.Fatalf("expected non-retryable %s, got %+v", tc.wantClass, outcome)
+			}
+			if outcome.Usage == nil || outcome.Usage.InputTokens != 7 || outcome.Usage.OutputTokens != tc.wantOutput || outcome.Usage.Truncated != tc.wantTruncated {
+				t.Fatalf("expected usage output=%d truncated=%v, got %+v", tc.wantOutput, tc.wantTruncated, outcome.Usage)
+			}
+			if (outcome.FirstChunkLatencyMS > 0) != (tc.wantClass == contracts.OutcomeSuccess) {
+				t.Fatalf("expected first-chunk latency only on a completed stream, got %+v", outcome)
+			}
+			if err := outcome.Validate(); err != nil {
+				t.Fatalf("expected valid outcome, got %v", err)
+			}
+		})
+	}
+}
+
+func TestInvokeServerEndpointingQuery(t *testing.T) {
+	t.Parallel()
+
+	var gotQuery string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	adapter, err := New(Config{
+		ProviderID:        "stt-a",
+		Modality:          contracts.ModalitySTT,
+		Endpoint:          ts.URL + "?model=base",
+		ServerEndpointing: true,
+		BuildQuery: func(req contracts.InvocationRequest) map[string]string {
+			if req.Endpointing == nil {
+				return nil
+			}
+			return map[string]string{"endpointing": "500"}
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected adapter error: %v", err)
+	}
+	if !contracts.SupportsServerEndpointing(adapter) {
+		t.Fatalf("expected adapter to advertise server-side endpointing")
+	}
+	outcome, err := adapter.Invoke(contracts.InvocationRequest{
+		SessionID:            "sess-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-1",
+		ProviderInvocationID: "pvi-1",
+		ProviderID:           "stt-a",
+		Modality:             contracts.ModalitySTT,
+		Attempt:              1,
+		Endpointing:          &contracts.Endpointing{SilenceDurationMS: 500, MaxUtteranceMS: 1000, NoSpeechTimeoutMS: 1000},
+	})
+	if err != nil || outcome.Class != contracts.OutcomeSuccess {
+		t.Fatalf("expected success, got outcome=%+v err=%v", outcome, err)
+	}
+	if gotQuery != "endpointing=500&model=base" {
+		t.Fatalf("expected endpointing query parameter, got %q", gotQuery)
+	}
+}
+
+func TestInvokeSendsRawBody(t *testing.T) {
+	t.Parallel()
+
+	var gotContentType string
+	var gotBody []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		gotBody, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"transcript":"book a table"}`))
+	}))
+	defer ts.Close()
+
+	adapter, err := New(Config{
+		ProviderID: "stt-a",
+		Modality:   contracts.ModalitySTT,
+		Endpoint:   ts.URL,
+		BuildRawBody: func(req contracts.InvocationRequest) ([]byte, string, error) {
+			if req.Audio == nil {
+				return nil, "", nil
+			}
+			body, err := WAVBody(req.Audio)
+			return body, "audio/wav", err
+		},
+		ResponseText: func(body []byte) string { return string(body) },
+	})
+	if err != nil {
+		t.Fatalf("unexpected adapter error: %v", err)
+	}
+	req := contracts.InvocationRequest{
+		SessionID:            "sess-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-1",
+		ProviderInvocationID: "pvi-1",
+		ProviderID:           "stt-a",
+		Modality:             contracts.ModalitySTT,
+		Attempt:              1,
+	}
+	if _, err := adapter.Invoke(req); err != nil || gotContentType != "application/json" {
+		t.Fatalf("expected the JSON body without captured audio, got content type %q err=%v", gotContentType, err)
+	}
+
+	req.Audio = &contracts.AudioInput{PCM: []int16{1, -1, 2, -2}, SampleRateHz: 16000}
+	outcome, err := adapter.Invoke(req)
+	if err != nil || outcome.Class != contracts.OutcomeSuccess {
+		t.Fatalf("expected success, got outcome=%+v err=%v", outcome, err)
+	}
+	if gotContentType != "audio/wav" || len(gotBody) != 44+8 || string(gotBody[:4]) != "RIFF" {
+		t.Fatalf("expected a WAV upload, got %q with %d bytes", gotContentType, len(gotBody))
+	}
+	if outcome.Text != `{"transcript":"book a table"}` {
+		t.Fatalf("expected response text, got %q", outcome.Text)
+	}
+}
@@ -0,0 +1,327 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+)
+
+const (
+	// EnvDialTimeoutMS sets the provider HTTP dial timeout in milliseconds.
+	EnvDialTimeoutMS = "RSPP_PROVIDER_HTTP_DIAL_TIMEOUT_MS"
+	// EnvTLSHandshakeTimeoutMS sets the provider TLS handshake timeout in milliseconds.
+	EnvTLSHandshakeTimeoutMS = "RSPP_PROVIDER_HTTP_TLS_HANDSHAKE_TIMEOUT_MS"
+	// EnvResponseHeaderTimeoutMS sets the provider response-header timeout in milliseconds.
+	EnvResponseHeaderTimeoutMS = "RSPP_PROVIDER_HTTP_RESPONSE_HEADER_TIMEOUT_MS"
+	// EnvIdleConnTimeoutMS sets how long idle provider connections stay pooled.
+	EnvIdleConnTimeoutMS = "RSPP_PROVIDER_HTTP_IDLE_CONN_TIMEOUT_MS"
+	// EnvMaxIdleConns sets the total idle connection pool size.
+	EnvMaxIdleConns = "RSPP_PROVIDER_HTTP_MAX_IDLE_CONNS"
+	// EnvMaxIdleConnsPerHost sets the per-host idle connection pool size.
+	EnvMaxIdleConnsPerHost = "RSPP_PROVIDER_HTTP_MAX_IDLE_CONNS_PER_HOST"
+	// EnvMaxConnsPerHost caps concurrent connections per host (0 = unlimited).
+	EnvMaxConnsPerHost = "RSPP_PROVIDER_HTTP_MAX_CONNS_PER_HOST"
+	// EnvHTTP2 toggles HTTP/2 negotiation for provider connections.
+	EnvHTTP2 = "RSPP_PROVIDER_HTTP_HTTP2"
+
+	// IdempotencyKeyHeader is attached to provider requests so transport-level replays of
+	// requests on stale pooled connections are retry-safe.
+	IdempotencyKeyHeader = "Idempotency-Key"
+)
+
+// Config tunes the shared provider HTTP transport.
+type Config struct {
+	DialTimeout           time.Duration
+	KeepAlive             time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	IdleConnTimeout       time.Duration
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	MaxConnsPerHost       int
+	HTTP2                 bool
+}
+
+// DefaultConfig returns pool and timeout settings sized for low-latency provider calls.
+func DefaultConfig() Config {
+	return Config{
+		DialTimeout:           3 * time.Second,
+		KeepAlive:             30 * time.Second,
+		TLSHandshakeTimeout:   3 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConns:          128,
+		MaxIdleConnsPerHost:   16,
+		HTTP2:                 true,
+	}
+}
+
+// Validate enforces transport setting bounds.
+func (c Config) Validate() error {
+	if c.DialTimeout <= 0 || c.TLSHandshakeTimeout <= 0 || c.ResponseHeaderTimeout <= 0 || c.IdleConnTimeout <= 0 {
+		return fmt.Errorf("dial, tls handshake, response header, and idle conn timeouts must be >0")
+	}
+	if c.KeepAlive < 0 {
+		return fmt.Errorf("keepalive must be >=0")
+	}
+	if c.MaxIdleConns < 1 || c.MaxIdleConnsPerHost < 1 {
+		return fmt.Errorf("max_idle_conns and max_idle_conns_per_host must be >=1")
+	}
+	if c.MaxConnsPerHost < 0 {
+		return fmt.Errorf("max_conns_per_host must be >=0")
+	}
+	return nil
+}
+
+// ConfigFromEnv overlays RSPP_PROVIDER_HTTP_* settings on DefaultConfig.
+func ConfigFromEnv() (Config, error) {
+	cfg := DefaultConfig()
+	durations := []struct {
+		env    string
+		target *time.Duration
+	}{
+		{env: EnvDialTimeoutMS, target: &cfg.DialTimeout},
+		{env: EnvTLSHandshakeTimeoutMS, target: &cfg.TLSHandshakeTimeout},
+		{env: EnvResponseHeaderTimeoutMS, target: &cfg.ResponseHeaderTimeout},
+		{env: EnvIdleConnTimeoutMS, target: &cfg.IdleConnTimeout},
+	}
+	for _, d := range durations {
+		if raw := strings.TrimSpace(os.Getenv(d.env)); raw != "" {
+			v, err := strconv.Atoi(raw)
+			if err != nil || v < 1 {
+				return Config{}, fmt.Errorf("%s must be integer >=1", d.env)
+			}
+			*d.target = time.Duration(v) * time.Millisecond
+		}
+	}
+	ints := []struct {
+		env    string
+		min    int
+		target *int
+	}{
+		{env: EnvMaxIdleConns, min: 1, target: &cfg.MaxIdleConns},
+		{env: EnvMaxIdleConnsPerHost, min: 1, target: &cfg.MaxIdleConnsPerHost},
+		{env: EnvMaxConnsPerHost, min: 0, target: &cfg.MaxConnsPerHost},
+	}
+	for _, n := range ints {
+		if raw := strings.TrimSpace(os.Getenv(n.env)); raw != "" {
+			v, err := strconv.Atoi(raw)
+			if err != nil || v < n.min {
+				return Config{}, fmt.Errorf("%s must be integer >=%d", n.env, n.min)
+			}
+			*n.target = v
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv(EnvHTTP2)); raw != "" {
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s parse error: %w", EnvHTTP2, err)
+		}
+		cfg.HTTP2 = enabled
+	}
+	return cfg, nil
+}
+
+// Stats aggregates connection metrics for one provider.
+type Stats struct {
+	Requests          int64
+	ReusedConns       int64
+	NewConns          int64
+	DNSLookups        int64
+	DNSLatencyTotalMS int64
+	DNSLatencyMaxMS   int64
+}
+
+// ReuseRatio returns the fraction of requests served on pooled connections.
+func (s Stats) ReuseRatio() float64 {
+	total := s.ReusedConns + s.NewConns
+	if total == 0 {
+		return 0
+	}
+	return float64(s.ReusedConns) / float64(total)
+}
+
+// Factory builds provider HTTP clients that share one tuned transport and pool while
+// attributing connection metrics per provider.
+type Factory struct {
+	cfg       Config
+	transport *http.Transport
+
+	mu    sync.Mutex
+	stats map[string]*Stats
+}
+
+// NewFactory builds a client factory over a single shared transport.
+func NewFactory(cfg Config) (*Factory, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: cfg.DialTimeout, KeepAlive: cfg.KeepAlive}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		ForceAttemptHTTP2:     cfg.HTTP2,
+		ExpectContinueTimeout: time.Second,
+	}
+	if !cfg.HTTP2 {
+		// A non-nil empty map disables automatic HTTP/2 upgrade on TLS connections.
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return &Factory{cfg: cfg, transport: transport, stats: make(map[string]*Stats)}, nil
+}
+
+var (
+	sharedOnce    sync.Once
+	sharedFactory *Factory
+)
+
+// Shared returns the process-wide factory configured from environment. Invalid
+// environment settings fall back to DefaultConfig.
+func Shared() *Factory {
+	sharedOnce.Do(func() {
+		cfg, err := ConfigFromEnv()
+		if err != nil {
+			cfg = DefaultConfig()
+		}
+		factory, err := NewFactory(cfg)
+		if err != nil {
+			factory, _ = NewFactory(DefaultConfig())
+		}
+		sharedFactory = factory
+	})
+	return sharedFactory
+}
+
+// Config returns the factory transport settings.
+func (f *Factory) Config() Config {
+	return f.cfg
+}
+
+// Client returns an http.Client bound to the shared transport that records per-provider
+// connection reuse and DNS latency. Request deadlines are owned by callers via context.
+func (f *Factory) Client(providerID string) *http.Client {
+	return &http.Client{Transport: &tracingTransport{factory: f, providerID: providerID, base: f.transport}}
+}
+
+// Stats returns a copy of connection metrics for providerID.
+func (f *Factory) Stats(providerID string) Stats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if stats, ok := f.stats[providerID]; ok {
+		return *stats
+	}
+	return Stats{}
+}
+
+// ProviderIDs returns providers with recorded stats in deterministic order.
+func (f *Factory) ProviderIDs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := make([]string, 0, len(f.stats))
+	for providerID := range f.stats {
+		ids = append(ids, providerID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// CloseIdleConnections drops pooled idle connections.
+func (f *Factory) CloseIdleConnections() {
+	f.transport.CloseIdleConnections()
+}
+
+func (f *Factory) record(providerID string, reused bool, dnsLatency time.Duration, dnsObserved bool) {
+	f.mu.Lock()
+	stats, ok := f.stats[providerID]
+	if !ok {
+		stats = &Stats{}
+		f.stats[providerID] = stats
+	}
+	stats.Requests++
+	if reused {
+		stats.ReusedConns++
+	} else {
+		stats.NewConns++
+	}
+	dnsMS := dnsLatency.Milliseconds()
+	if dnsObserved {
+		stats.DNSLookups++
+		stats.DNSLatencyTotalMS += dnsMS
+		stats.DNSLatencyMaxMS = max(stats.DNSLatencyMaxMS, dnsMS)
+	}
+	f.mu.Unlock()
+
+	attributes := map[string]string{"provider_id": providerID}
+	reusedValue := 0.0
+	if reused {
+		reusedValue = 1
+	}
+	telemetry.DefaultEmitter().EmitMetric(telemetry.MetricProviderConnReused, reusedValue, "1", attributes, telemetry.Correlation{EmittedBy: "OR-01"})
+	if dnsObserved {
+		telemetry.DefaultEmitter().EmitMetric(telemetry.MetricProviderDNSLatencyMS, float64(dnsMS), "ms", attributes, telemetry.Correlation{EmittedBy: "OR-01"})
+	}
+}
+
+type tracingTransport struct {
+	factory    *Factory
+	providerID string
+	base       http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var (
+		mu          sync.Mutex
+		gotConn     bool
+		reused      bool
+		dnsStart    time.Time
+		dnsLatency  time.Duration
+		dnsObserved bool
+	)
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			mu.Lock()
+			dnsStart = time.Now()
+			mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			mu.Lock()
+			if !dnsStart.IsZero() {
+				dnsLatency = time.Since(dnsStart)
+				dnsObserved = true
+			}
+			mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			mu.Lock()
+			gotConn = true
+			reused = info.Reused
+			mu.Unlock()
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	resp, err := t.base.RoundTrip(req)
+
+	mu.Lock()
+	observed, connReused, dns, dnsSeen := gotConn, reused, dnsLatency, dnsObserved
+	mu.Unlock()
+	if observed {
+		t.factory.record(t.providerID, connReused, dns, dnsSeen)
+	}
+	return resp, err
+}
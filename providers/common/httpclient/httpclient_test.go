@@ -0,0 +1,87 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvDialTimeoutMS, "250")
+	t.Setenv(EnvMaxIdleConnsPerHost, "4")
+	t.Setenv(EnvMaxConnsPerHost, "0")
+	t.Setenv(EnvHTTP2, "false")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+	if cfg.DialTimeout != 250*time.Millisecond || cfg.MaxIdleConnsPerHost != 4 || cfg.HTTP2 {
+		t.Fatalf("unexpected env config: %+v", cfg)
+	}
+	if cfg.TLSHandshakeTimeout != DefaultConfig().TLSHandshakeTimeout {
+		t.Fatalf("expected unset settings to keep defaults, got %+v", cfg)
+	}
+
+	t.Setenv(EnvMaxIdleConns, "0")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Fatalf("expected invalid max idle conns error")
+	}
+}
+
+func TestNewFactoryValidation(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultConfig()
+	cfg.MaxIdleConnsPerHost = 0
+	if _, err := NewFactory(cfg); err == nil {
+		t.Fatalf("expected pool size validation error")
+	}
+	cfg = DefaultConfig()
+	cfg.HTTP2 = false
+	factory, err := NewFactory(cfg)
+	if err != nil {
+		t.Fatalf("unexpected factory error: %v", err)
+	}
+	if factory.transport.TLSNextProto == nil || factory.transport.ForceAttemptHTTP2 {
+		t.Fatalf("expected http2 disabled on transport")
+	}
+}
+
+func TestFactoryRecordsPerProviderConnectionReuse(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	factory, err := NewFactory(DefaultConfig())
+	if err != nil {
+		t.Fatalf("unexpected factory error: %v", err)
+	}
+	defer factory.CloseIdleConnections()
+
+	for _, providerID := range []string{"provider-a", "provider-a", "provider-b"} {
+		resp, err := factory.Client(providerID).Get(ts.URL)
+		if err != nil {
+			t.Fatalf("unexpected request error: %v", err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+
+	a := factory.Stats("provider-a")
+	if a.Requests != 2 || a.NewConns != 1 || a.ReusedConns != 1 || a.ReuseRatio() != 0.5 {
+		t.Fatalf("unexpected provider-a stats: %+v", a)
+	}
+	b := factory.Stats("provider-b")
+	if b.Requests != 1 || b.ReusedConns != 1 {
+		t.Fatalf("expected provider-b to reuse the shared pool, got %+v", b)
+	}
+	if ids := factory.ProviderIDs(); len(ids) != 2 || ids[0] != "provider-a" {
+		t.Fatalf("unexpected provider ids: %v", ids)
+	}
+}
@@ -0,0 +1,87 @@
+package localproc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+// WaitDelay bounds how long a killed run waits for output pipes held open by descendant
+// processes; set it as exec.Cmd.WaitDelay.
+const WaitDelay = 500 * time.Millisecond
+
+// Context bounds one local provider run by the request's attempt timeout and cancels it when
+// the turn CancelSignal closes, which kills a process started with exec.CommandContext.
+func Context(req contracts.InvocationRequest, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), req.AttemptTimeout(timeout))
+	if req.CancelSignal == nil {
+		return ctx, cancel
+	}
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-req.CancelSignal:
+			cancel()
+		case <-done:
+		}
+	}()
+	return ctx, func() {
+		close(done)
+		cancel()
+	}
+}
+
+// NormalizeError maps a failed local provider run onto the normalized outcome taxonomy. A
+// missing binary or model blocks the provider, since retrying cannot help; ctx is the run
+// context, so a deadline kill reports timeout rather than a process failure.
+func NormalizeError(ctx context.Context, req contracts.InvocationRequest, err error) contracts.Outcome {
+	if req.CancelSignalled() {
+		return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return contracts.Outcome{Class: contracts.OutcomeTimeout, Retryable: true, Reason: "provider_timeout"}
+	}
+	if errors.Is(err, exec.ErrNotFound) || errors.Is(err, os.ErrNotExist) || errors.Is(err, os.ErrPermission) {
+		return contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_binary_missing"}
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: false, Reason: "provider_process_failed"}
+	}
+	return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: true, Reason: "provider_process_failed"}
+}
+
+// FileMissing reports whether a required local model or input file is unset or unreadable.
+func FileMissing(path string) bool {
+	if path == "" {
+		return true
+	}
+	_, err := os.Stat(path)
+	return err != nil
+}
+
+// Resample converts mono PCM between sample rates by linear interpolation. Local engines run
+// at fixed model rates (whisper.cpp at 16 kHz, piper at the voice rate), so audio crossing the
+// chain boundary is converted here.
+func Resample(pcm []int16, fromHz int, toHz int) []int16 {
+	if fromHz == toHz || fromHz < 1 || toHz < 1 || len(pcm) == 0 {
+		return pcm
+	}
+	out := make([]int16, int(int64(len(pcm))*int64(toHz)/int64(fromHz)))
+	step := float64(fromHz) / float64(toHz)
+	for idx := range out {
+		pos := float64(idx) * step
+		left := int(pos)
+		if left >= len(pcm)-1 {
+			out[idx] = pcm[len(pcm)-1]
+			continue
+		}
+		frac := pos - float64(left)
+		out[idx] = int16(float64(pcm[left])*(1-frac) + float64(pcm[left+1])*frac)
+	}
+	return out
+}
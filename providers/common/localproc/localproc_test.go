@@ -0,0 +1,96 @@
+package localproc
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+func TestNormalizeError(t *testing.T) {
+	t.Parallel()
+
+	expired, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-expired.Done()
+	cancelSignal := make(chan struct{})
+	close(cancelSignal)
+	exitErr := exec.Command("sh", "-c", "exit 3").Run()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		req        contracts.InvocationRequest
+		err        error
+		wantClass  contracts.OutcomeClass
+		wantReason string
+	}{
+		{name: "turn cancelled", ctx: context.Background(), req: contracts.InvocationRequest{CancelSignal: cancelSignal}, err: exitErr, wantClass: contracts.OutcomeCancelled, wantReason: "provider_cancelled"},
+		{name: "deadline kill", ctx: expired, err: exitErr, wantClass: contracts.OutcomeTimeout, wantReason: "provider_timeout"},
+		{name: "binary missing", ctx: context.Background(), err: exec.ErrNotFound, wantClass: contracts.OutcomeBlocked, wantReason: "provider_binary_missing"},
+		{name: "non-zero exit", ctx: context.Background(), err: exitErr, wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_process_failed"},
+		{name: "pipe failure", ctx: context.Background(), err: errors.New("broken pipe"), wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_process_failed"},
+	}
+	for _, tc := range tests {
+		outcome := NormalizeError(tc.ctx, tc.req, tc.err)
+		if outcome.Class != tc.wantClass || outcome.Reason != tc.wantReason {
+			t.Fatalf("%s: expected class=%s reason=%q, got %+v", tc.name, tc.wantClass, tc.wantReason, outcome)
+		}
+		if err := outcome.Validate(); err != nil {
+			t.Fatalf("%s: expected a valid outcome, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestContextCancelsOnTurnCancel(t *testing.T) {
+	t.Parallel()
+
+	cancelSignal := make(chan struct{})
+	ctx, cancel := Context(contracts.InvocationRequest{CancelSignal: cancelSignal}, time.Minute)
+	defer cancel()
+	close(cancelSignal)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("expected the run context to be cancelled with the turn")
+	}
+}
+
+func TestResample(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		pcm     []int16
+		fromHz  int
+		toHz    int
+		wantLen int
+	}{
+		{name: "same rate", pcm: []int16{1, 2, 3, 4}, fromHz: 16000, toHz: 16000, wantLen: 4},
+		{name: "downsample", pcm: make([]int16, 22050), fromHz: 22050, toHz: 16000, wantLen: 16000},
+		{name: "upsample", pcm: make([]int16, 800), fromHz: 8000, toHz: 16000, wantLen: 1600},
+	}
+	for _, tc := range tests {
+		if got := Resample(tc.pcm, tc.fromHz, tc.toHz); len(got) != tc.wantLen {
+			t.Fatalf("%s: expected %d samples, got %d", tc.name, tc.wantLen, len(got))
+		}
+	}
+	if got := Resample([]int16{0, 100}, 1, 2); got[1] != 50 {
+		t.Fatalf("expected linear interpolation between samples, got %v", got)
+	}
+}
+
+func TestFileMissing(t *testing.T) {
+	t.Parallel()
+
+	if !FileMissing("") || !FileMissing(filepath.Join(t.TempDir(), "absent.bin")) {
+		t.Fatalf("expected unset and absent paths to be missing")
+	}
+	if FileMissing(t.TempDir()) {
+		t.Fatalf("expected an existing path to be present")
+	}
+}
@@ -0,0 +1,197 @@
+package ttsquality
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+// OutcomeReason is the normalized reason attached to TTS outputs that fail quality checks.
+const OutcomeReason = "provider_output_quality_failed"
+
+// Thresholds bounds acceptable synthesized audio for a given input text.
+type Thresholds struct {
+	MinMSPerChar         int64
+	MaxMSPerChar         int64
+	MinDurationMS        int64
+	MaxLeadingSilenceMS  int64
+	MaxTrailingSilenceMS int64
+	SilenceAmplitude     int16
+	ClipAmplitude        int16
+	MaxClippedRatio      float64
+}
+
+// DefaultThresholds returns conservative bounds for conversational speech.
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		MinMSPerChar:         25,
+		MaxMSPerChar:         200,
+		MinDurationMS:        200,
+		MaxLeadingSilenceMS:  1000,
+		MaxTrailingSilenceMS: 1500,
+		SilenceAmplitude:     500,
+		ClipAmplitude:        32000,
+		MaxClippedRatio:      0.01,
+	}
+}
+
+// EnabledFromEnv reports whether live TTS adapters should run audio quality checks. Checks are
+// on unless RSPP_TTS_QUALITY_CHECKS turns them off.
+func EnabledFromEnv() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("RSPP_TTS_QUALITY_CHECKS"))) {
+	case "0", "false", "no", "off":
+		return false
+	default:
+		return true
+	}
+}
+
+// FailureOutcome is the non-retryable outcome for synthesized audio that failed quality checks.
+func FailureOutcome() contracts.Outcome {
+	return contracts.Outcome{Class: contracts.OutcomeQualityFailure, Retryable: false, Reason: OutcomeReason}
+}
+
+// Report captures duration, silence, and clipping heuristics for one synthesized clip.
+type Report struct {
+	DurationMS        int64
+	ExpectedMinMS     int64
+	ExpectedMaxMS     int64
+	LeadingSilenceMS  int64
+	TrailingSilenceMS int64
+	ClippedRatio      float64
+	Violations        []string
+	Passed            bool
+}
+
+// Analyze evaluates mono PCM16 samples synthesized for text against thresholds.
+// Zero-value thresholds fall back to DefaultThresholds.
+func Analyze(samples []int16, sampleRateHz int, text string, th Thresholds) Report {
+	if th == (Thresholds{}) {
+		th = DefaultThresholds()
+	}
+	report := Report{}
+	if sampleRateHz <= 0 {
+		report.Violations = append(report.Violations, fmt.Sprintf("invalid sample rate %d", sampleRateHz))
+		return report
+	}
+
+	chars := int64(utf8.RuneCountInString(strings.TrimSpace(text)))
+	report.ExpectedMinMS = max(chars*th.MinMSPerChar, th.MinDurationMS)
+	report.ExpectedMaxMS = chars * th.MaxMSPerChar
+	report.DurationMS = samplesToMS(len(samples), sampleRateHz)
+
+	if report.DurationMS == 0 {
+		report.Violations = append(report.Violations, "synthesized audio has zero duration")
+		return report
+	}
+	if report.DurationMS < report.ExpectedMinMS {
+		report.Violations = append(report.Violations, fmt.Sprintf("duration %dms below expected minimum %dms for %d chars", report.DurationMS, report.ExpectedMinMS, chars))
+	}
+	if report.ExpectedMaxMS > 0 && report.DurationMS > report.ExpectedMaxMS {
+		report.Violations = append(report.Violations, fmt.Sprintf("duration %dms above expected maximum %dms for %d chars", report.DurationMS, report.ExpectedMaxMS, chars))
+	}
+
+	first, last := -1, -1
+	clipped := 0
+	for i, sample := range samples {
+		amplitude := abs16(sample)
+		if amplitude > th.SilenceAmplitude {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+		if amplitude >= th.ClipAmplitude {
+			clipped++
+		}
+	}
+	if first < 0 {
+		report.LeadingSilenceMS = report.DurationMS
+		report.TrailingSilenceMS = report.DurationMS
+		report.Violations = append(report.Violations, "synthesized audio is entirely silent")
+	} else {
+		report.LeadingSilenceMS = samplesToMS(first, sampleRateHz)
+		report.TrailingSilenceMS = samplesToMS(len(samples)-1-last, sampleRateHz)
+		if report.LeadingSilenceMS > th.MaxLeadingSilenceMS {
+			report.Violations = append(report.Violations, fmt.Sprintf("leading silence %dms exceeds %dms", report.LeadingSilenceMS, th.MaxLeadingSilenceMS))
+		}
+		if report.TrailingSilenceMS > th.MaxTrailingSilenceMS {
+			report.Violations = append(report.Violations, fmt.Sprintf("trailing silence %dms exceeds %dms", report.TrailingSilenceMS, th.MaxTrailingSilenceMS))
+		}
+	}
+
+	report.ClippedRatio = float64(clipped) / float64(len(samples))
+	if report.ClippedRatio > th.MaxClippedRatio {
+		report.Violations = append(report.Violations, fmt.Sprintf("clipped sample ratio %.4f exceeds %.4f", report.ClippedRatio, th.MaxClippedRatio))
+	}
+
+	report.Passed = len(report.Violations) == 0
+	return report
+}
+
+// CheckPCM16 decodes little-endian PCM16 (optionally WAV-wrapped) audio and returns an
+// error listing violations when the clip fails quality checks.
+func CheckPCM16(raw []byte, sampleRateHz int, text string, th Thresholds) error {
+	if pcm, wavRate, ok := StripWAVHeader(raw); ok {
+		raw = pcm
+		sampleRateHz = wavRate
+	}
+	report := Analyze(DecodePCM16LE(raw), sampleRateHz, text, th)
+	if !report.Passed {
+		return fmt.Errorf("tts quality check failed: %s", strings.Join(report.Violations, "; "))
+	}
+	return nil
+}
+
+// DecodePCM16LE converts little-endian 16-bit PCM bytes into samples. A trailing odd byte is ignored.
+func DecodePCM16LE(raw []byte) []int16 {
+	samples := make([]int16, len(raw)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(raw[2*i:]))
+	}
+	return samples
+}
+
+// StripWAVHeader returns the data chunk and sample rate of a RIFF/WAVE payload.
+func StripWAVHeader(raw []byte) ([]byte, int, bool) {
+	if len(raw) < 12 || !bytes.Equal(raw[0:4], []byte("RIFF")) || !bytes.Equal(raw[8:12], []byte("WAVE")) {
+		return nil, 0, false
+	}
+	sampleRate := 0
+	offset := 12
+	for offset+8 <= len(raw) {
+		chunkID := string(raw[offset : offset+4])
+		chunkSize := int(binary.LittleEndian.Uint32(raw[offset+4 : offset+8]))
+		body := offset + 8
+		switch chunkID {
+		case "fmt ":
+			if body+8 <= len(raw) {
+				sampleRate = int(binary.LittleEndian.Uint32(raw[body+4 : body+8]))
+			}
+		case "data":
+			end := min(body+chunkSize, len(raw))
+			return raw[body:end], sampleRate, sampleRate > 0
+		}
+		offset = body + chunkSize + chunkSize%2
+	}
+	return nil, 0, false
+}
+
+func samplesToMS(samples int, sampleRateHz int) int64 {
+	return int64(samples) * 1000 / int64(sampleRateHz)
+}
+
+func abs16(v int16) int16 {
+	if v < 0 {
+		if v == -32768 {
+			return 32767
+		}
+		return -v
+	}
+	return v
+}
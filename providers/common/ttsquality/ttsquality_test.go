@@ -0,0 +1,116 @@
+package ttsquality
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+const testSampleRateHz = 16000
+
+func TestAnalyzeHeuristics(t *testing.T) {
+	t.Parallel()
+
+	text := "Realtime speech pipeline live smoke test."
+	tests := []struct {
+		name      string
+		samples   []int16
+		passed    bool
+		violation string
+	}{
+		{name: "healthy speech", samples: clip(100, 2000, 100, 8000), passed: true},
+		{name: "zero duration", samples: nil, violation: "zero duration"},
+		{name: "too short", samples: clip(0, 200, 0, 8000), violation: "below expected minimum"},
+		{name: "too long", samples: clip(0, 9000, 0, 8000), violation: "above expected maximum"},
+		{name: "all silent", samples: clip(2000, 0, 0, 0), violation: "entirely silent"},
+		{name: "leading silence", samples: clip(1500, 1500, 0, 8000), violation: "leading silence"},
+		{name: "trailing silence", samples: clip(0, 1500, 2000, 8000), violation: "trailing silence"},
+		{name: "clipping", samples: clip(0, 2000, 0, 32767), violation: "clipped sample ratio"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			report := Analyze(tc.samples, testSampleRateHz, text, DefaultThresholds())
+			if report.Passed != tc.passed {
+				t.Fatalf("expected passed=%v, got %+v", tc.passed, report)
+			}
+			if tc.violation != "" && !strings.Contains(strings.Join(report.Violations, ";"), tc.violation) {
+				t.Fatalf("expected violation containing %q, got %+v", tc.violation, report.Violations)
+			}
+		})
+	}
+}
+
+func TestCheckPCM16AcceptsWAVPayload(t *testing.T) {
+	t.Parallel()
+
+	pcm := encodePCM16(clip(50, 2000, 50, 8000))
+	wav := wrapWAV(pcm, testSampleRateHz)
+	if err := CheckPCM16(wav, 0, "Realtime speech pipeline live smoke test.", Thresholds{}); err != nil {
+		t.Fatalf("expected WAV payload to pass quality checks, got %v", err)
+	}
+	if err := CheckPCM16(nil, testSampleRateHz, "hello", Thresholds{}); err == nil {
+		t.Fatalf("expected empty payload to fail quality checks")
+	}
+}
+
+// clip builds leading silence, voiced body, and trailing silence segments in milliseconds.
+func clip(leadingMS int, voicedMS int, trailingMS int, amplitude int16) []int16 {
+	perMS := testSampleRateHz / 1000
+	samples := make([]int16, 0, (leadingMS+voicedMS+trailingMS)*perMS)
+	samples = append(samples, make([]int16, leadingMS*perMS)...)
+	for i := 0; i < voicedMS*perMS; i++ {
+		if i%2 == 0 {
+			samples = append(samples, amplitude)
+		} else {
+			samples = append(samples, -amplitude)
+		}
+	}
+	return append(samples, make([]int16, trailingMS*perMS)...)
+}
+
+func TestEnabledFromEnvDefaultsOn(t *testing.T) {
+	cases := []struct {
+		value string
+		want  bool
+	}{
+		{value: "", want: true},
+		{value: "1", want: true},
+		{value: "off", want: false},
+		{value: "0", want: false},
+	}
+	for _, tc := range cases {
+		t.Setenv("RSPP_TTS_QUALITY_CHECKS", tc.value)
+		if got := EnabledFromEnv(); got != tc.want {
+			t.Fatalf("%q: expected enabled=%t, got %t", tc.value, tc.want, got)
+		}
+	}
+}
+
+func encodePCM16(samples []int16) []byte {
+	raw := make([]byte, 2*len(samples))
+	for i, sample := range samples {
+		binary.LittleEndian.PutUint16(raw[2*i:], uint16(sample))
+	}
+	return raw
+}
+
+func wrapWAV(pcm []byte, sampleRateHz int) []byte {
+	header := make([]byte, 44)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(36+len(pcm)))
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], 1)
+	binary.LittleEndian.PutUint16(header[22:24], 1)
+	binary.LittleEndian.PutUint32(header[24:28], uint32(sampleRateHz))
+	binary.LittleEndian.PutUint32(header[28:32], uint32(sampleRateHz*2))
+	binary.LittleEndian.PutUint16(header[32:34], 2)
+	binary.LittleEndian.PutUint16(header[34:36], 16)
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], uint32(len(pcm)))
+	return append(header, pcm...)
+}
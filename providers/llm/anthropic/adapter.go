@@ -1,7 +1,10 @@
 package anthropic
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
@@ -21,13 +24,17 @@ type Config struct {
 }
 
 func ConfigFromEnv() Config {
+	maxTokens, err := strconv.Atoi(os.Getenv("RSPP_LLM_ANTHROPIC_MAX_TOKENS"))
+	if err != nil || maxTokens < 1 {
+		maxTokens = 16
+	}
 	return Config{
 		APIKey:          os.Getenv("RSPP_LLM_ANTHROPIC_API_KEY"),
 		Endpoint:        defaultString(os.Getenv("RSPP_LLM_ANTHROPIC_ENDPOINT"), "https://api.anthropic.com/v1/messages"),
 		Model:           defaultString(os.Getenv("RSPP_LLM_ANTHROPIC_MODEL"), "claude-3-5-haiku-latest"),
 		Prompt:          defaultString(os.Getenv("RSPP_LLM_ANTHROPIC_PROMPT"), "Reply with the word: ok"),
 		AnthropicVerion: defaultString(os.Getenv("RSPP_LLM_ANTHROPIC_VERSION"), "2023-06-01"),
-		MaxTokens:       16,
+		MaxTokens:       maxTokens,
 		Timeout:         10 * time.Second,
 	}
 }
@@ -37,22 +44,88 @@ func NewAdapter(cfg Config) (contracts.Adapter, error) {
 		ProviderID:    ProviderID,
 		Modality:      contracts.ModalityLLM,
 		Endpoint:      cfg.Endpoint,
+		StandbyPath:   "/v1/models",
 		APIKey:        cfg.APIKey,
 		APIKeyHeader:  "x-api-key",
 		Timeout:       cfg.Timeout,
 		StaticHeaders: map[string]string{"anthropic-version": cfg.AnthropicVerion},
 		BuildBody: func(req contracts.InvocationRequest) any {
-			return map[string]any{
+			body := map[string]any{
 				"model":      cfg.Model,
-				"max_tokens": cfg.MaxTokens,
+				"max_tokens": req.OutputTokenLimit(cfg.MaxTokens),
+				"stream":     true,
 				"messages": []map[string]any{
-					{"role": "user", "content": cfg.Prompt},
+					{"role": "user", "content": req.LLMPrompt(cfg.Prompt)},
 				},
 			}
+			if summary := req.SessionSummaryContext(); summary != "" {
+				body["system"] = summary
+			}
+			return body
 		},
+		StreamUsage: streamUsage,
+		StreamText:  streamText,
 	})
 }
 
+type streamEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Usage streamEventUsage `json:"usage"`
+	} `json:"message"`
+	Usage *streamEventUsage `json:"usage"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+}
+
+type streamEventUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// streamUsage folds Messages API SSE events into token usage. Each content delta counts as one
+// output token until message_delta reports the provider total, so an aborted stream still
+// accounts for the tokens generated before the abort.
+func streamUsage(line []byte, usage *contracts.TokenUsage) {
+	payload, ok := bytes.CutPrefix(line, []byte("data:"))
+	if !ok {
+		return
+	}
+	var event streamEvent
+	if err := json.Unmarshal(bytes.TrimSpace(payload), &event); err != nil {
+		return
+	}
+	switch event.Type {
+	case "message_start":
+		usage.InputTokens = event.Message.Usage.InputTokens
+		usage.OutputTokens = event.Message.Usage.OutputTokens
+	case "content_block_delta":
+		usage.OutputTokens++
+	case "message_delta":
+		if event.Usage != nil {
+			usage.OutputTokens = event.Usage.OutputTokens
+		}
+	}
+}
+
+// streamText returns the text carried by a content_block_delta event.
+func streamText(line []byte) string {
+	payload, ok := bytes.CutPrefix(line, []byte("data:"))
+	if !ok {
+		return ""
+	}
+	var event streamEvent
+	if err := json.Unmarshal(bytes.TrimSpace(payload), &event); err != nil {
+		return ""
+	}
+	if event.Type != "content_block_delta" || event.Delta.Type != "text_delta" {
+		return ""
+	}
+	return event.Delta.Text
+}
+
 func NewAdapterFromEnv() (contracts.Adapter, error) {
 	return NewAdapter(ConfigFromEnv())
 }
@@ -1,6 +1,10 @@
 package anthropic
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
 
 func TestConfigFromEnv_DefaultModelUsesHaiku(t *testing.T) {
 	t.Setenv("RSPP_LLM_ANTHROPIC_MODEL", "")
@@ -10,3 +14,54 @@ func TestConfigFromEnv_DefaultModelUsesHaiku(t *testing.T) {
 		t.Fatalf("expected default anthropic model to be claude-3-5-haiku-latest, got %q", cfg.Model)
 	}
 }
+
+func TestStreamUsageAndText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		lines      []string
+		wantInput  int
+		wantOutput int
+		wantText   string
+	}{
+		{
+			name: "aborted stream counts deltas",
+			lines: []string{
+				"event: message_start",
+				`data: {"type":"message_start","message":{"usage":{"input_tokens":12,"output_tokens":1}}}`,
+				`data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"o"}}`,
+				`data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"k"}}`,
+			},
+			wantInput:  12,
+			wantOutput: 3,
+			wantText:   "ok",
+		},
+		{
+			name: "message_delta reports provider total",
+			lines: []string{
+				`data: {"type":"message_start","message":{"usage":{"input_tokens":12,"output_tokens":1}}}`,
+				`data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"ok"}}`,
+				`data: {"type":"message_delta","usage":{"output_tokens":5}}`,
+				"data: not-json",
+			},
+			wantInput:  12,
+			wantOutput: 5,
+			wantText:   "ok",
+		},
+	}
+	for _, tc := range tests {
+		usage := &contracts.TokenUsage{}
+		text := ""
+		for _, line := range tc.lines {
+			streamUsage([]byte(line), usage)
+			text += streamText([]byte(line))
+		}
+		if usage.InputTokens != tc.wantInput || usage.OutputTokens != tc.wantOutput {
+			t.Fatalf("%s: expected input=%d output=%d, got %+v", tc.name, tc.wantInput, tc.wantOutput, usage)
+		}
+		if text != tc.wantText {
+			t.Fatalf("%s: expected streamed text %q, got %q", tc.name, tc.wantText, text)
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package cohere
 
 import (
+	"bytes"
+	"encoding/json"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,6 +28,10 @@ type Config struct {
 }
 
 func ConfigFromEnv() Config {
+	maxTokens, err := strconv.Atoi(os.Getenv("RSPP_LLM_COHERE_MAX_TOKENS"))
+	if err != nil || maxTokens < 1 {
+		maxTokens = 16
+	}
 	return Config{
 		APIKey:            os.Getenv("RSPP_LLM_COHERE_API_KEY"),
 		Endpoint:          defaultString(os.Getenv("RSPP_LLM_COHERE_ENDPOINT"), "https://openrouter.ai/api/v1/chat/completions"),
@@ -33,11 +40,13 @@ func ConfigFromEnv() Config {
 		OpenRouter:        defaultBool(os.Getenv("RSPP_LLM_COHERE_OPENROUTER"), false),
 		OpenRouterReferer: os.Getenv("RSPP_LLM_COHERE_OPENROUTER_REFERER"),
 		OpenRouterTitle:   defaultString(os.Getenv("RSPP_LLM_COHERE_OPENROUTER_TITLE"), "RealtimeSpeechPipeline"),
-		MaxTokens:         16,
+		MaxTokens:         maxTokens,
 		Timeout:           10 * time.Second,
 	}
 }
 
+// NewAdapter streams replies from the Cohere v2 chat API, or an OpenRouter chat completion,
+// as server-sent events, so a turn cancel aborts generation with the usage reported so far.
 func NewAdapter(cfg Config) (contracts.Adapter, error) {
 	openRouter := shouldUseOpenRouter(cfg)
 	staticHeaders := map[string]string{}
@@ -62,23 +71,105 @@ func NewAdapter(cfg Config) (contracts.Adapter, error) {
 		BuildBody: func(req contracts.InvocationRequest) any {
 			if openRouter {
 				return map[string]any{
-					"model":      cfg.Model,
-					"max_tokens": cfg.MaxTokens,
+					"model":          cfg.Model,
+					"max_tokens":     req.OutputTokenLimit(cfg.MaxTokens),
+					"stream":         true,
+					"stream_options": map[string]any{"include_usage": true},
 					"messages": []map[string]any{
-						{"role": "user", "content": cfg.Prompt},
+						{"role": "user", "content": req.LLMPrompt(cfg.Prompt)},
 					},
 				}
 			}
 			return map[string]any{
-				"model": cfg.Model,
+				"model":  cfg.Model,
+				"stream": true,
 				"messages": []map[string]any{
-					{"role": "user", "content": cfg.Prompt},
+					{"role": "user", "content": req.LLMPrompt(cfg.Prompt)},
 				},
 			}
 		},
+		StreamUsage: streamUsage,
+		StreamText:  streamText,
 	})
 }
 
+// streamEvent is one Cohere v2 chat stream event or OpenRouter chat completion chunk.
+type streamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Message struct {
+			Content struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"message"`
+		Usage struct {
+			Tokens *struct {
+				InputTokens  int `json:"input_tokens"`
+				OutputTokens int `json:"output_tokens"`
+			} `json:"tokens"`
+		} `json:"usage"`
+	} `json:"delta"`
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+func decodeStreamEvent(line []byte) (streamEvent, bool) {
+	var event streamEvent
+	payload, ok := bytes.CutPrefix(line, []byte("data:"))
+	if !ok {
+		return event, false
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(payload), &event); err != nil {
+		return event, false
+	}
+	return event, true
+}
+
+// streamUsage folds stream events into token usage. Each content delta counts as one output
+// token until message-end (or the OpenRouter usage chunk) reports the provider totals, so an
+// aborted stream still accounts for the tokens generated before the abort.
+func streamUsage(line []byte, usage *contracts.TokenUsage) {
+	event, ok := decodeStreamEvent(line)
+	if !ok {
+		return
+	}
+	switch {
+	case event.Usage != nil:
+		usage.InputTokens, usage.OutputTokens = event.Usage.PromptTokens, event.Usage.CompletionTokens
+	case event.Type == "message-end" && event.Delta.Usage.Tokens != nil:
+		usage.InputTokens, usage.OutputTokens = event.Delta.Usage.Tokens.InputTokens, event.Delta.Usage.Tokens.OutputTokens
+	case streamEventText(event) != "":
+		usage.OutputTokens++
+	}
+}
+
+// streamText returns the reply text carried by one stream event.
+func streamText(line []byte) string {
+	event, ok := decodeStreamEvent(line)
+	if !ok {
+		return ""
+	}
+	return streamEventText(event)
+}
+
+func streamEventText(event streamEvent) string {
+	if event.Type == "content-delta" {
+		return event.Delta.Message.Content.Text
+	}
+	var text strings.Builder
+	for _, choice := range event.Choices {
+		text.WriteString(choice.Delta.Content)
+	}
+	return text.String()
+}
+
 func NewAdapterFromEnv() (contracts.Adapter, error) {
 	return NewAdapter(ConfigFromEnv())
 }
@@ -37,7 +37,8 @@ func TestNewAdapter_OpenRouterPayloadAndHeaders(t *testing.T) {
 			t.Fatalf("decode body: %v", err)
 		}
 		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`{"ok":true}`))
+		_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"ok\"}}]}\n\n" +
+			"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":5,\"completion_tokens\":1}}\n\ndata: [DONE]\n\n"))
 	}))
 	defer srv.Close()
 
@@ -73,8 +74,15 @@ func TestNewAdapter_OpenRouterPayloadAndHeaders(t *testing.T) {
 	if err != nil {
 		t.Fatalf("invoke: %v", err)
 	}
-	if outcome.Class != contracts.OutcomeSuccess {
-		t.Fatalf("expected success outcome, got class=%s reason=%s", outcome.Class, outcome.Reason)
+	if outcome.Class != contracts.OutcomeSuccess || outcome.Text != "ok" {
+		t.Fatalf("expected success outcome with reply text, got class=%s reason=%s text=%q", outcome.Class, outcome.Reason, outcome.Text)
+	}
+
+	if outcome.Usage == nil || outcome.Usage.InputTokens != 5 || outcome.Usage.OutputTokens != 1 {
+		t.Fatalf("expected streamed usage input=5 output=1, got %+v", outcome.Usage)
+	}
+	if gotBody["stream"] != true {
+		t.Fatalf("expected a streamed completion request, got stream=%v", gotBody["stream"])
 	}
 
 	if gotAuth != "Bearer test-key" {
@@ -104,3 +112,61 @@ func TestNewAdapter_OpenRouterPayloadAndHeaders(t *testing.T) {
 		t.Fatalf("unexpected content: %v", first["content"])
 	}
 }
+
+func TestStreamUsageAndText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		lines      []string
+		wantInput  int
+		wantOutput int
+		wantText   string
+	}{
+		{
+			name: "aborted cohere stream counts deltas",
+			lines: []string{
+				"event: content-delta",
+				`data: {"type":"content-delta","delta":{"message":{"content":{"text":"o"}}}}`,
+				`data: {"type":"content-delta","delta":{"message":{"content":{"text":"k"}}}}`,
+			},
+			wantOutput: 2,
+			wantText:   "ok",
+		},
+		{
+			name: "cohere message-end reports provider totals",
+			lines: []string{
+				`data: {"type":"content-delta","delta":{"message":{"content":{"text":"ok"}}}}`,
+				`data: {"type":"message-end","delta":{"finish_reason":"COMPLETE","usage":{"tokens":{"input_tokens":11,"output_tokens":3}}}}`,
+			},
+			wantInput:  11,
+			wantOutput: 3,
+			wantText:   "ok",
+		},
+		{
+			name: "aborted openrouter stream counts deltas",
+			lines: []string{
+				": OPENROUTER PROCESSING",
+				`data: {"choices":[{"delta":{"content":"o"}}]}`,
+				`data: {"choices":[{"delta":{"content":"k"}}]}`,
+				"data: [DONE]",
+			},
+			wantOutput: 2,
+			wantText:   "ok",
+		},
+	}
+	for _, tc := range tests {
+		usage := &contracts.TokenUsage{}
+		text := ""
+		for _, line := range tc.lines {
+			streamUsage([]byte(line), usage)
+			text += streamText([]byte(line))
+		}
+		if usage.InputTokens != tc.wantInput || usage.OutputTokens != tc.wantOutput {
+			t.Fatalf("%s: expected input=%d output=%d, got %+v", tc.name, tc.wantInput, tc.wantOutput, usage)
+		}
+		if text != tc.wantText {
+			t.Fatalf("%s: expected streamed text %q, got %q", tc.name, tc.wantText, text)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package gemini
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
@@ -20,30 +23,114 @@ type Config struct {
 func ConfigFromEnv() Config {
 	return Config{
 		APIKey:   os.Getenv("RSPP_LLM_GEMINI_API_KEY"),
-		Endpoint: defaultString(os.Getenv("RSPP_LLM_GEMINI_ENDPOINT"), "https://generativelanguage.googleapis.com/v1beta/models/gemini-1.5-flash:generateContent"),
+		Endpoint: defaultString(os.Getenv("RSPP_LLM_GEMINI_ENDPOINT"), "https://generativelanguage.googleapis.com/v1beta/models/gemini-1.5-flash:streamGenerateContent"),
 		Prompt:   defaultString(os.Getenv("RSPP_LLM_GEMINI_PROMPT"), "Reply with the word: ok"),
 		Timeout:  10 * time.Second,
 	}
 }
 
+// NewAdapter streams replies from the streamGenerateContent route as server-sent events, so a
+// turn cancel aborts generation with the usage reported so far. A generateContent endpoint is
+// rewritten to its streaming route.
 func NewAdapter(cfg Config) (contracts.Adapter, error) {
+	if route, ok := strings.CutSuffix(cfg.Endpoint, ":generateContent"); ok {
+		cfg.Endpoint = route + ":streamGenerateContent"
+	}
 	return httpadapter.New(httpadapter.Config{
 		ProviderID:       ProviderID,
 		Modality:         contracts.ModalityLLM,
 		Endpoint:         cfg.Endpoint,
+		StandbyPath:      "/v1beta/models",
 		APIKey:           cfg.APIKey,
 		QueryAPIKeyParam: "key",
 		Timeout:          cfg.Timeout,
+		BuildQuery: func(contracts.InvocationRequest) map[string]string {
+			return map[string]string{"alt": "sse"}
+		},
 		BuildBody: func(req contracts.InvocationRequest) any {
-			return map[string]any{
+			body := map[string]any{
 				"contents": []map[string]any{
-					{"parts": []map[string]any{{"text": cfg.Prompt}}},
+					{"parts": []map[string]any{{"text": req.LLMPrompt(cfg.Prompt)}}},
 				},
 			}
+			if limit := req.OutputTokenLimit(0); limit > 0 {
+				body["generationConfig"] = map[string]any{"maxOutputTokens": limit}
+			}
+			instructions := make([]map[string]any, 0, 2)
+			if req.Locale != nil && req.Locale.LLMPromptLanguage != "" {
+				instructions = append(instructions, map[string]any{"text": "Respond in language: " + req.Locale.LLMPromptLanguage})
+			}
+			if summary := req.SessionSummaryContext(); summary != "" {
+				instructions = append(instructions, map[string]any{"text": summary})
+			}
+			if len(instructions) > 0 {
+				body["systemInstruction"] = map[string]any{"parts": instructions}
+			}
+			return body
 		},
+		StreamUsage: streamUsage,
+		StreamText:  streamText,
 	})
 }
 
+// streamChunk is one streamGenerateContent server-sent event.
+type streamChunk struct {
+	Candidates []struct {
+		Content struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"content"`
+	} `json:"candidates"`
+	UsageMetadata *struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+func decodeStreamChunk(line []byte) (streamChunk, bool) {
+	var chunk streamChunk
+	payload, ok := bytes.CutPrefix(line, []byte("data:"))
+	if !ok {
+		return chunk, false
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(payload), &chunk); err != nil {
+		return chunk, false
+	}
+	return chunk, true
+}
+
+// streamUsage folds streamGenerateContent chunks into token usage. Chunks carry the running
+// usageMetadata totals; a chunk without them counts as one output token, so an aborted stream
+// still accounts for the tokens generated before the abort.
+func streamUsage(line []byte, usage *contracts.TokenUsage) {
+	chunk, ok := decodeStreamChunk(line)
+	if !ok {
+		return
+	}
+	if chunk.UsageMetadata != nil {
+		usage.InputTokens = chunk.UsageMetadata.PromptTokenCount
+		usage.OutputTokens = chunk.UsageMetadata.CandidatesTokenCount
+		return
+	}
+	if len(chunk.Candidates) > 0 {
+		usage.OutputTokens++
+	}
+}
+
+// streamText joins the text parts of the first candidate in one chunk.
+func streamText(line []byte) string {
+	chunk, ok := decodeStreamChunk(line)
+	if !ok || len(chunk.Candidates) == 0 {
+		return ""
+	}
+	var text strings.Builder
+	for _, part := range chunk.Candidates[0].Content.Parts {
+		text.WriteString(part.Text)
+	}
+	return text.String()
+}
+
 func NewAdapterFromEnv() (contracts.Adapter, error) {
 	return NewAdapter(ConfigFromEnv())
 }
@@ -0,0 +1,121 @@
+package gemini
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+func TestNewAdapterHonoursMaxOutputTokens(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		maxOutputTokens int
+		wantLimit       any
+	}{
+		{name: "plan cap", maxOutputTokens: 48, wantLimit: float64(48)},
+		{name: "uncapped", maxOutputTokens: 0, wantLimit: nil},
+	}
+	for _, tc := range tests {
+		var gotBody map[string]any
+		var gotPath, gotAlt string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotPath, gotAlt = r.URL.Path, r.URL.Query().Get("alt")
+			defer r.Body.Close()
+			if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+				t.Errorf("%s: decode body: %v", tc.name, err)
+			}
+			_, _ = w.Write([]byte("data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"o\"}]}}]}\n\n" +
+				"data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"k\"}]}}],\"usageMetadata\":{\"promptTokenCount\":6,\"candidatesTokenCount\":1}}\n\n"))
+		}))
+
+		adapter, err := NewAdapter(Config{APIKey: "test-key", Endpoint: srv.URL + "/models/gemini:generateContent", Prompt: "Reply with the word: ok", Timeout: time.Second})
+		if err != nil {
+			srv.Close()
+			t.Fatalf("%s: new adapter: %v", tc.name, err)
+		}
+		outcome, err := adapter.Invoke(contracts.InvocationRequest{
+			SessionID:            "sess",
+			TurnID:               "turn",
+			PipelineVersion:      "pipeline-v1",
+			EventID:              "evt",
+			ProviderInvocationID: "pvi/sess/turn/evt/llm",
+			ProviderID:           ProviderID,
+			Modality:             contracts.ModalityLLM,
+			Attempt:              1,
+			MaxOutputTokens:      tc.maxOutputTokens,
+		})
+		srv.Close()
+		if err != nil {
+			t.Fatalf("%s: invoke: %v", tc.name, err)
+		}
+		if outcome.Class != contracts.OutcomeSuccess || outcome.Text != "ok" {
+			t.Fatalf("%s: expected success outcome with reply text, got %+v", tc.name, outcome)
+		}
+		if gotPath != "/models/gemini:streamGenerateContent" || gotAlt != "sse" {
+			t.Fatalf("%s: expected the streaming route with alt=sse, got path=%q alt=%q", tc.name, gotPath, gotAlt)
+		}
+		if outcome.Usage == nil || outcome.Usage.InputTokens != 6 || outcome.Usage.OutputTokens != 1 {
+			t.Fatalf("%s: expected streamed usage input=6 output=1, got %+v", tc.name, outcome.Usage)
+		}
+		var gotLimit any
+		if config, ok := gotBody["generationConfig"].(map[string]any); ok {
+			gotLimit = config["maxOutputTokens"]
+		}
+		if gotLimit != tc.wantLimit {
+			t.Fatalf("%s: expected generationConfig.maxOutputTokens=%v, got %v", tc.name, tc.wantLimit, gotLimit)
+		}
+	}
+}
+
+func TestStreamUsageAndText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		lines      []string
+		wantInput  int
+		wantOutput int
+		wantText   string
+	}{
+		{
+			name: "aborted stream counts chunks",
+			lines: []string{
+				`data: {"candidates":[{"content":{"parts":[{"text":"o"}]}}]}`,
+				`data: {"candidates":[{"content":{"parts":[{"text":"k"}]}}]}`,
+			},
+			wantOutput: 2,
+			wantText:   "ok",
+		},
+		{
+			name: "usage metadata reports provider totals",
+			lines: []string{
+				`data: {"candidates":[{"content":{"parts":[{"text":"o"}]}}]}`,
+				`data: {"candidates":[{"content":{"parts":[{"text":"k"}]}}],"usageMetadata":{"promptTokenCount":9,"candidatesTokenCount":4}}`,
+				"data: not-json",
+			},
+			wantInput:  9,
+			wantOutput: 4,
+			wantText:   "ok",
+		},
+	}
+	for _, tc := range tests {
+		usage := &contracts.TokenUsage{}
+		text := ""
+		for _, line := range tc.lines {
+			streamUsage([]byte(line), usage)
+			text += streamText([]byte(line))
+		}
+		if usage.InputTokens != tc.wantInput || usage.OutputTokens != tc.wantOutput {
+			t.Fatalf("%s: expected input=%d output=%d, got %+v", tc.name, tc.wantInput, tc.wantOutput, usage)
+		}
+		if text != tc.wantText {
+			t.Fatalf("%s: expected streamed text %q, got %q", tc.name, tc.wantText, text)
+		}
+	}
+}
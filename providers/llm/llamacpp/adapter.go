@@ -0,0 +1,262 @@
+package llamacpp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/httpadapter"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/httpclient"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/localproc"
+)
+
+const (
+	ProviderID = "llm-llama-cpp"
+
+	// maxStreamLineBytes caps one server-sent event line.
+	maxStreamLineBytes = 1 << 20
+)
+
+type Config struct {
+	// Endpoint is the llama.cpp server's OpenAI-compatible chat completions route.
+	Endpoint string
+	// Model is informational for llama.cpp, which serves the model it was started with.
+	Model        string
+	Prompt       string
+	SystemPrompt string
+	MaxTokens    int
+	Timeout      time.Duration
+	// HTTPClient overrides the shared provider client factory (httpclient.Shared).
+	HTTPClient *http.Client
+}
+
+// Adapter streams chat completions from a local llama.cpp server (llama-server); no cloud
+// credentials are involved.
+type Adapter struct {
+	cfg    Config
+	client *http.Client
+}
+
+func ConfigFromEnv() Config {
+	return Config{
+		Endpoint:     defaultString(os.Getenv("RSPP_LLM_LLAMACPP_ENDPOINT"), "http://127.0.0.1:8080/v1/chat/completions"),
+		Model:        defaultString(os.Getenv("RSPP_LLM_LLAMACPP_MODEL"), "local"),
+		Prompt:       defaultString(os.Getenv("RSPP_LLM_LLAMACPP_PROMPT"), "Reply with the word: ok"),
+		SystemPrompt: defaultString(os.Getenv("RSPP_LLM_LLAMACPP_SYSTEM_PROMPT"), "You are a concise voice assistant. Answer in one or two short sentences."),
+		MaxTokens:    64,
+		Timeout:      30 * time.Second,
+	}
+}
+
+func NewAdapter(cfg Config) (contracts.Adapter, error) {
+	if strings.TrimSpace(cfg.Model) == "" {
+		cfg.Model = "local"
+	}
+	if cfg.MaxTokens < 1 {
+		cfg.MaxTokens = 64
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = httpclient.Shared().Client(ProviderID)
+	}
+	return &Adapter{cfg: cfg, client: client}, nil
+}
+
+func NewAdapterFromEnv() (contracts.Adapter, error) {
+	return NewAdapter(ConfigFromEnv())
+}
+
+func (a *Adapter) ProviderID() string {
+	return ProviderID
+}
+
+func (a *Adapter) Modality() contracts.Modality {
+	return contracts.ModalityLLM
+}
+
+// Invoke completes the request prompt (or the configured one) and reports the reply as
+// Outcome.Text.
+func (a *Adapter) Invoke(req contracts.InvocationRequest) (contracts.Outcome, error) {
+	return a.InvokeStream(req, nil)
+}
+
+// InvokeStream completes the request prompt (or the configured one) and calls onChunk with each streamed text delta.
+// A turn cancel aborts the stream with a cancelled outcome carrying the usage seen so far.
+func (a *Adapter) InvokeStream(req contracts.InvocationRequest, onChunk func(contracts.StreamChunk) error) (contracts.Outcome, error) {
+	if err := req.Validate(); err != nil {
+		return contracts.Outcome{}, err
+	}
+	if req.CancelRequested {
+		return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, nil
+	}
+	if a.cfg.Endpoint == "" {
+		return contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_endpoint_missing"}, nil
+	}
+	ctx, cancel := localproc.Context(req, a.cfg.Timeout)
+	defer cancel()
+	return a.complete(ctx, req, req.LLMPrompt(a.cfg.Prompt), req.OutputTokenLimit(a.cfg.MaxTokens), onChunk)
+}
+
+// Respond answers a transcript, so the adapter can serve the local demo and loopback chains
+// directly.
+func (a *Adapter) Respond(transcript string) (string, error) {
+	if a.cfg.Endpoint == "" {
+		return "", fmt.Errorf("llama.cpp endpoint is not configured")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.Timeout)
+	defer cancel()
+	var reply strings.Builder
+	outcome, err := a.complete(ctx, contracts.InvocationRequest{}, transcript, a.cfg.MaxTokens, func(chunk contracts.StreamChunk) error {
+		reply.WriteString(chunk.Text)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if outcome.Class != contracts.OutcomeSuccess {
+		return "", fmt.Errorf("llama.cpp: %s (%s)", outcome.Reason, outcome.Class)
+	}
+	return strings.TrimSpace(reply.String()), nil
+}
+
+// streamEvent is one OpenAI-compatible chat completion stream chunk.
+type streamEvent struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+func (a *Adapter) complete(ctx context.Context, req contracts.InvocationRequest, prompt string, maxTokens int, onChunk func(contracts.StreamChunk) error) (contracts.Outcome, error) {
+	messages := make([]map[string]string, 0, 2)
+	if a.cfg.SystemPrompt != "" {
+		messages = append(messages, map[string]string{"role": "system", "content": a.cfg.SystemPrompt})
+	}
+	messages = append(messages, map[string]string{"role": "user", "content": prompt})
+	body := map[string]any{
+		"model":          a.cfg.Model,
+		"messages":       messages,
+		"max_tokens":     maxTokens,
+		"stream":         true,
+		"stream_options": map[string]any{"include_usage": true},
+	}
+	if req.SamplingSeed != nil {
+		body["seed"] = *req.SamplingSeed
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return contracts.Outcome{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.cfg.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return contracts.Outcome{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+	if req.Traceparent != "" {
+		httpReq.Header.Set(telemetry.TraceparentHeader, req.Traceparent)
+	}
+
+	started := time.Now()
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		if req.CancelSignalled() {
+			return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, nil
+		}
+		return httpadapter.NormalizeNetworkError(err), nil
+	}
+	defer resp.Body.Close()
+	if outcome := httpadapter.NormalizeStatus(resp.StatusCode, resp.Header.Get("Retry-After")); outcome.Class != contracts.OutcomeSuccess {
+		return outcome, nil
+	}
+
+	outcome := contracts.Outcome{Class: contracts.OutcomeSuccess}
+	usage := &contracts.TokenUsage{}
+	var text strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineBytes)
+	reportedUsage := false
+	for scanner.Scan() {
+		if req.CancelSignalled() {
+			break
+		}
+		data, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "data:")
+		data = strings.TrimSpace(data)
+		if !ok || data == "" {
+			continue
+		}
+		if data == "[DONE]" {
+			break
+		}
+		var event streamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: false, Reason: "provider_output_invalid"}, nil
+		}
+		if event.Usage != nil {
+			usage.InputTokens, usage.OutputTokens = event.Usage.PromptTokens, event.Usage.CompletionTokens
+			reportedUsage = true
+		}
+		for _, choice := range event.Choices {
+			if choice.FinishReason == "length" && req.MaxOutputTokens > 0 {
+				outcome.LengthCapped = true
+			}
+			if choice.Delta.Content == "" {
+				continue
+			}
+			if outcome.FirstChunkLatencyMS == 0 {
+				outcome.FirstChunkLatencyMS = max(time.Since(started).Milliseconds(), 1)
+			}
+			if !reportedUsage {
+				// Each delta counts as one output token until the final usage chunk, so an
+				// aborted stream still accounts for the tokens generated before the abort.
+				usage.OutputTokens++
+			}
+			text.WriteString(choice.Delta.Content)
+			if onChunk != nil {
+				if err := onChunk(contracts.StreamChunk{Text: choice.Delta.Content}); err != nil {
+					return contracts.Outcome{}, err
+				}
+			}
+		}
+	}
+	err = scanner.Err()
+	if req.CancelSignalled() || errors.Is(err, context.Canceled) {
+		usage.Truncated = true
+		return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_stream_cancelled", Usage: usage}, nil
+	}
+	if err != nil {
+		outcome := httpadapter.NormalizeNetworkError(err)
+		outcome.Usage = usage
+		return outcome, nil
+	}
+	if usage.InputTokens > 0 || usage.OutputTokens > 0 {
+		outcome.Usage = usage
+	}
+	outcome.Text = text.String()
+	return outcome, nil
+}
+
+func defaultString(v string, fallback string) string {
+	if strings.TrimSpace(v) == "" {
+		return fallback
+	}
+	return v
+}
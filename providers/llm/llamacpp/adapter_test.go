@@ -0,0 +1,188 @@
+package llamacpp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+func testRequest() contracts.InvocationRequest {
+	return contracts.InvocationRequest{
+		SessionID:            "sess-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-1",
+		ProviderInvocationID: "pvi/sess-1/turn-1/evt-1/llm",
+		ProviderID:           ProviderID,
+		Modality:             contracts.ModalityLLM,
+		Attempt:              1,
+	}
+}
+
+// newTestServer streams events as server-sent events, recording the request body.
+func newTestServer(t *testing.T, status int, events ...string) (*httptest.Server, *map[string]any) {
+	t.Helper()
+	body := map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(payload, &body)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(status)
+		for _, event := range events {
+			_, _ = io.WriteString(w, "data: "+event+"\n\n")
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &body
+}
+
+func TestInvokeStreamDeliversTextDeltas(t *testing.T) {
+	t.Parallel()
+
+	server, body := newTestServer(t, http.StatusOK,
+		`{"choices":[{"delta":{"role":"assistant"}}]}`,
+		`{"choices":[{"delta":{"content":"Hello"}}]}`,
+		`{"choices":[{"delta":{"content":" there"},"finish_reason":"length"}]}`,
+		`{"choices":[],"usage":{"prompt_tokens":12,"completion_tokens":2}}`,
+		`[DONE]`,
+	)
+	adapter, err := NewAdapter(Config{Endpoint: server.URL, Prompt: "Say hello", HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+
+	req := testRequest()
+	req.MaxOutputTokens = 2
+	var text strings.Builder
+	outcome, err := adapter.(contracts.StreamingAdapter).InvokeStream(req, func(chunk contracts.StreamChunk) error {
+		text.WriteString(chunk.Text)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeSuccess || !outcome.LengthCapped || outcome.FirstChunkLatencyMS < 1 || text.String() != "Hello there" || outcome.Text != "Hello there" {
+		t.Fatalf("unexpected outcome %+v with text %q", outcome, text.String())
+	}
+	if outcome.Usage == nil || outcome.Usage.InputTokens != 12 || outcome.Usage.OutputTokens != 2 {
+		t.Fatalf("expected reported usage, got %+v", outcome.Usage)
+	}
+	if (*body)["max_tokens"] != float64(2) || (*body)["stream"] != true {
+		t.Fatalf("expected a streamed request capped at the turn token limit, got %v", *body)
+	}
+}
+
+func TestInvokeStreamCancelReportsPartialUsage(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range []string{`{"choices":[{"delta":{"content":"Hel"}}]}`, `{"choices":[{"delta":{"content":"lo"}}]}`} {
+			_, _ = io.WriteString(w, "data: "+event+"\n\n")
+			w.(http.Flusher).Flush()
+		}
+		// Hold the stream open so only the turn cancel ends it.
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+	adapter, err := NewAdapter(Config{Endpoint: server.URL, HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+
+	cancelSignal := make(chan struct{})
+	req := testRequest()
+	req.CancelSignal = cancelSignal
+	chunks := 0
+	outcome, err := adapter.(contracts.StreamingAdapter).InvokeStream(req, func(contracts.StreamChunk) error {
+		if chunks++; chunks == 2 {
+			close(cancelSignal)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeCancelled || outcome.Reason != "provider_stream_cancelled" {
+		t.Fatalf("expected a cancelled stream, got %+v", outcome)
+	}
+	if outcome.Usage == nil || outcome.Usage.OutputTokens != 2 || !outcome.Usage.Truncated {
+		t.Fatalf("expected truncated usage counting the streamed deltas, got %+v", outcome.Usage)
+	}
+}
+
+func TestRespondAnswersTranscript(t *testing.T) {
+	t.Parallel()
+
+	server, body := newTestServer(t, http.StatusOK,
+		`{"choices":[{"delta":{"content":" It is sunny. "}}]}`,
+		`[DONE]`,
+	)
+	adapter, err := NewAdapter(Config{Endpoint: server.URL, HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	reply, err := adapter.(*Adapter).Respond("what's the weather like")
+	if err != nil {
+		t.Fatalf("unexpected respond error: %v", err)
+	}
+	if reply != "It is sunny." {
+		t.Fatalf("expected trimmed reply, got %q", reply)
+	}
+	messages, _ := (*body)["messages"].([]any)
+	if len(messages) != 1 || !strings.Contains(string(mustJSON(t, messages[0])), "what's the weather like") {
+		t.Fatalf("expected the transcript as the user message, got %v", (*body)["messages"])
+	}
+}
+
+func TestInvokeClassifiesOutcomes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		status     int
+		events     []string
+		endpoint   string
+		wantClass  contracts.OutcomeClass
+		wantReason string
+	}{
+		{name: "overloaded", status: http.StatusServiceUnavailable, wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_server_error"},
+		{name: "bad request", status: http.StatusBadRequest, wantClass: contracts.OutcomeBlocked, wantReason: "provider_client_error"},
+		{name: "malformed event", status: http.StatusOK, events: []string{`{not json`}, wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_output_invalid"},
+		{name: "server not running", endpoint: "http://127.0.0.1:1/v1/chat/completions", wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_transport_error"},
+	}
+	for _, tc := range tests {
+		server, _ := newTestServer(t, tc.status, tc.events...)
+		endpoint := tc.endpoint
+		if endpoint == "" {
+			endpoint = server.URL
+		}
+		adapter, err := NewAdapter(Config{Endpoint: endpoint, HTTPClient: server.Client()})
+		if err != nil {
+			t.Fatalf("%s: new adapter: %v", tc.name, err)
+		}
+		outcome, err := adapter.Invoke(testRequest())
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		if outcome.Class != tc.wantClass || outcome.Reason != tc.wantReason {
+			t.Fatalf("%s: expected class=%s reason=%q, got %+v", tc.name, tc.wantClass, tc.wantReason, outcome)
+		}
+		if err := outcome.Validate(); err != nil {
+			t.Fatalf("%s: expected a valid outcome, got %v", tc.name, err)
+		}
+	}
+}
+
+func mustJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return out
+}
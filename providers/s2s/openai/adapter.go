@@ -0,0 +1,336 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/wscodec"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/httpadapter"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/httpclient"
+)
+
+const ProviderID = "s2s-openai-realtime"
+
+// Realtime API response.done statuses.
+const (
+	responseCompleted  = "completed"
+	responseCancelled  = "cancelled"
+	responseIncomplete = "incomplete"
+	responseFailed     = "failed"
+)
+
+type Config struct {
+	APIKey   string
+	Endpoint string
+	Model    string
+	Prompt   string
+	Voice    string
+	// OutputAudioFormat is the reply audio encoding streamed as StreamChunk.Audio.
+	OutputAudioFormat string
+	MaxTokens         int
+	Timeout           time.Duration
+	// HTTPClient overrides the shared provider client factory (httpclient.Shared) used for the
+	// WebSocket upgrade.
+	HTTPClient *http.Client
+}
+
+// Adapter invokes the OpenAI Realtime API over WebSocket: one response per invocation, with
+// reply audio and transcript deltas streamed through InvokeStream.
+type Adapter struct {
+	cfg    Config
+	client *http.Client
+}
+
+func ConfigFromEnv() Config {
+	maxTokens, err := strconv.Atoi(os.Getenv("RSPP_S2S_OPENAI_MAX_TOKENS"))
+	if err != nil || maxTokens < 0 {
+		maxTokens = 256
+	}
+	return Config{
+		APIKey:            os.Getenv("RSPP_S2S_OPENAI_API_KEY"),
+		Endpoint:          defaultString(os.Getenv("RSPP_S2S_OPENAI_ENDPOINT"), "wss://api.openai.com/v1/realtime"),
+		Model:             defaultString(os.Getenv("RSPP_S2S_OPENAI_MODEL"), "gpt-4o-realtime-preview"),
+		Prompt:            defaultString(os.Getenv("RSPP_S2S_OPENAI_PROMPT"), "Reply with the word: ok"),
+		Voice:             defaultString(os.Getenv("RSPP_S2S_OPENAI_VOICE"), "alloy"),
+		OutputAudioFormat: "pcm16",
+		MaxTokens:         maxTokens,
+		Timeout:           15 * time.Second,
+	}
+}
+
+func NewAdapter(cfg Config) (contracts.Adapter, error) {
+	if strings.TrimSpace(cfg.OutputAudioFormat) == "" {
+		cfg.OutputAudioFormat = "pcm16"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 15 * time.Second
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = httpclient.Shared().Client(ProviderID)
+	}
+	return &Adapter{cfg: cfg, client: client}, nil
+}
+
+func NewAdapterFromEnv() (contracts.Adapter, error) {
+	return NewAdapter(ConfigFromEnv())
+}
+
+func (a *Adapter) ProviderID() string {
+	return ProviderID
+}
+
+func (a *Adapter) Modality() contracts.Modality {
+	return contracts.ModalityS2S
+}
+
+// Invoke runs one speech-to-speech response and discards its streamed output.
+func (a *Adapter) Invoke(req contracts.InvocationRequest) (contracts.Outcome, error) {
+	return a.InvokeStream(req, nil)
+}
+
+// realtimeEvent is the subset of Realtime API server events the adapter consumes.
+type realtimeEvent struct {
+	Type     string            `json:"type"`
+	Delta    string            `json:"delta"`
+	Error    *realtimeError    `json:"error"`
+	Response *realtimeResponse `json:"response"`
+}
+
+type realtimeError struct {
+	Type    string `json:"type"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type realtimeResponse struct {
+	Status        string `json:"status"`
+	StatusDetails *struct {
+		Reason string         `json:"reason"`
+		Error  *realtimeError `json:"error"`
+	} `json:"status_details"`
+	Usage *struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// InvokeStream sends the prompt as a user turn, requests one audio+text response, and calls
+// onChunk with each audio and transcript delta. A turn cancel sends response.cancel and reports
+// cancelled with truncated usage.
+func (a *Adapter) InvokeStream(req contracts.InvocationRequest, onChunk func(contracts.StreamChunk) error) (contracts.Outcome, error) {
+	if err := req.Validate(); err != nil {
+		return contracts.Outcome{}, err
+	}
+	if req.CancelRequested {
+		return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, nil
+	}
+	if a.cfg.Endpoint == "" {
+		return contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_endpoint_missing"}, nil
+	}
+	endpoint, err := url.Parse(a.cfg.Endpoint)
+	if err != nil {
+		return contracts.Outcome{}, err
+	}
+	if a.cfg.Model != "" {
+		query := endpoint.Query()
+		query.Set("model", a.cfg.Model)
+		endpoint.RawQuery = query.Encode()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), req.AttemptTimeout(a.cfg.Timeout))
+	defer cancel()
+	if req.CancelSignal != nil {
+		go func() {
+			select {
+			case <-req.CancelSignal:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	header := http.Header{}
+	header.Set("OpenAI-Beta", "realtime=v1")
+	if a.cfg.APIKey != "" {
+		header.Set("Authorization", "Bearer "+a.cfg.APIKey)
+	}
+	if req.Traceparent != "" {
+		header.Set(telemetry.TraceparentHeader, req.Traceparent)
+	}
+
+	started := time.Now()
+	conn, err := dialRealtime(ctx, a.client, endpoint.String(), header)
+	if err != nil {
+		var rejected *wscodec.HandshakeError
+		switch {
+		case req.CancelSignalled():
+			return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, nil
+		case errors.As(err, &rejected):
+			return httpadapter.NormalizeStatus(rejected.StatusCode, rejected.RetryAfter), nil
+		case ctx.Err() != nil:
+			return httpadapter.NormalizeNetworkError(ctx.Err()), nil
+		default:
+			return httpadapter.NormalizeNetworkError(err), nil
+		}
+	}
+	defer conn.Close()
+	// A turn cancel asks the provider to stop generating before the connection is torn down,
+	// which unblocks the pending read.
+	stopWatch := context.AfterFunc(ctx, func() {
+		if req.CancelSignalled() {
+			_ = writeEvent(conn, map[string]any{"type": "response.cancel"})
+		}
+		_ = conn.Close()
+	})
+	defer stopWatch()
+
+	response := map[string]any{
+		"modalities":          []string{"audio", "text"},
+		"voice":               a.cfg.Voice,
+		"output_audio_format": a.cfg.OutputAudioFormat,
+	}
+	if limit := req.OutputTokenLimit(a.cfg.MaxTokens); limit > 0 {
+		response["max_output_tokens"] = limit
+	}
+	events := []any{
+		map[string]any{
+			"type": "conversation.item.create",
+			"item": map[string]any{
+				"type":    "message",
+				"role":    "user",
+				"content": []map[string]any{{"type": "input_text", "text": a.cfg.Prompt}},
+			},
+		},
+		map[string]any{"type": "response.create", "response": response},
+	}
+	for _, event := range events {
+		if err := writeEvent(conn, event); err != nil {
+			return a.interrupted(ctx, req, err), nil
+		}
+	}
+
+	firstChunkLatencyMS := int64(0)
+	for {
+		payload, err := readEvent(conn)
+		if err != nil {
+			return a.interrupted(ctx, req, err), nil
+		}
+		var event realtimeEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: false, Reason: "provider_output_invalid"}, nil
+		}
+		chunk := contracts.StreamChunk{}
+		switch event.Type {
+		case "response.audio.delta":
+			audio, err := base64.StdEncoding.DecodeString(event.Delta)
+			if err != nil {
+				return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: false, Reason: "provider_output_invalid"}, nil
+			}
+			chunk.Audio = audio
+		case "response.audio_transcript.delta", "response.text.delta":
+			chunk.Text = event.Delta
+		case "error":
+			return classifyRealtimeError(event.Error), nil
+		case "response.done":
+			outcome := classifyResponse(event.Response)
+			outcome.FirstChunkLatencyMS = firstChunkLatencyMS
+			return outcome, nil
+		default:
+			continue
+		}
+		if firstChunkLatencyMS == 0 {
+			firstChunkLatencyMS = max(time.Since(started).Milliseconds(), 1)
+		}
+		if onChunk != nil {
+			if err := onChunk(chunk); err != nil {
+				return contracts.Outcome{}, err
+			}
+		}
+	}
+}
+
+// interrupted normalizes a connection that failed before response.done.
+func (a *Adapter) interrupted(ctx context.Context, req contracts.InvocationRequest, err error) contracts.Outcome {
+	switch {
+	case req.CancelSignalled():
+		return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_stream_cancelled", Usage: &contracts.TokenUsage{Truncated: true}}
+	case ctx.Err() != nil:
+		return httpadapter.NormalizeNetworkError(ctx.Err())
+	default:
+		return httpadapter.NormalizeNetworkError(err)
+	}
+}
+
+// classifyResponse maps a response.done status onto the normalized outcome taxonomy. A response
+// stopped at max_output_tokens is a capped success.
+func classifyResponse(response *realtimeResponse) contracts.Outcome {
+	if response == nil {
+		return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: false, Reason: "provider_output_invalid"}
+	}
+	var usage *contracts.TokenUsage
+	if response.Usage != nil {
+		usage = &contracts.TokenUsage{InputTokens: response.Usage.InputTokens, OutputTokens: response.Usage.OutputTokens}
+	}
+	reason := ""
+	var providerErr *realtimeError
+	if response.StatusDetails != nil {
+		reason = response.StatusDetails.Reason
+		providerErr = response.StatusDetails.Error
+	}
+	switch response.Status {
+	case responseCompleted:
+		return contracts.Outcome{Class: contracts.OutcomeSuccess, Usage: usage}
+	case responseIncomplete:
+		switch reason {
+		case "max_output_tokens":
+			return contracts.Outcome{Class: contracts.OutcomeSuccess, Usage: usage, LengthCapped: true}
+		case "content_filter":
+			return contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_content_filtered", Usage: usage}
+		default:
+			return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: true, Reason: "provider_response_incomplete", Usage: usage}
+		}
+	case responseCancelled:
+		return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled", Usage: usage}
+	case responseFailed:
+		outcome := classifyRealtimeError(providerErr)
+		outcome.Usage = usage
+		return outcome
+	default:
+		return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: false, Reason: "provider_output_invalid", Usage: usage}
+	}
+}
+
+// classifyRealtimeError maps a Realtime API error event onto the normalized outcome taxonomy.
+func classifyRealtimeError(providerErr *realtimeError) contracts.Outcome {
+	if providerErr == nil {
+		return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: true, Reason: "provider_server_error", CircuitOpen: true}
+	}
+	switch {
+	case providerErr.Code == "rate_limit_exceeded" || providerErr.Type == "rate_limit_error":
+		return contracts.Outcome{Class: contracts.OutcomeOverload, Retryable: true, Reason: "provider_overload", BackoffMS: 500, CircuitOpen: true}
+	case providerErr.Code == "invalid_api_key" || providerErr.Type == "authentication_error" || providerErr.Type == "permission_error":
+		return contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_auth_or_policy_block"}
+	case providerErr.Type == "invalid_request_error":
+		return contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_client_error"}
+	default:
+		return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: true, Reason: "provider_server_error", CircuitOpen: true}
+	}
+}
+
+func defaultString(v string, fallback string) string {
+	if strings.TrimSpace(v) == "" {
+		return fallback
+	}
+	return v
+}
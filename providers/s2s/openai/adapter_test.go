@@ -0,0 +1,212 @@
+package openai
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/wscodec"
+)
+
+// fakeRealtime serves the Realtime API event exchange: it reads the item and response.create
+// events, then answers with events.
+type fakeRealtime struct {
+	events []string
+	// waitFor, when set, is a client event type the server blocks on after sending events.
+	waitFor  string
+	received chan string
+}
+
+func (f *fakeRealtime) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer test-key" || r.Header.Get("OpenAI-Beta") != "realtime=v1" || r.URL.Query().Get("model") != "gpt-test" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	conn, err := wscodec.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	for range 2 {
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var event struct {
+			Type string `json:"type"`
+		}
+		_ = json.Unmarshal(payload, &event)
+		f.received <- event.Type
+	}
+	for _, event := range f.events {
+		if err := conn.WriteMessage(wscodec.OpText, []byte(event)); err != nil {
+			return
+		}
+	}
+	if f.waitFor == "" {
+		return
+	}
+	for {
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if strings.Contains(string(payload), f.waitFor) {
+			f.received <- f.waitFor
+			return
+		}
+	}
+}
+
+func testRequest() contracts.InvocationRequest {
+	return contracts.InvocationRequest{
+		SessionID:            "sess-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-1",
+		ProviderInvocationID: "pvi/sess-1/turn-1/evt-1/s2s",
+		ProviderID:           ProviderID,
+		Modality:             contracts.ModalityS2S,
+		Attempt:              1,
+	}
+}
+
+func newTestAdapter(t *testing.T, handler http.Handler) contracts.StreamingAdapter {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	adapter, err := NewAdapter(Config{APIKey: "test-key", Endpoint: "ws" + strings.TrimPrefix(server.URL, "http"), Model: "gpt-test", Voice: "alloy", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	return adapter.(contracts.StreamingAdapter)
+}
+
+func TestInvokeStreamDeliversAudioAndTranscript(t *testing.T) {
+	t.Parallel()
+
+	audio := base64.StdEncoding.EncodeToString([]byte{1, 2, 3, 4})
+	server := &fakeRealtime{received: make(chan string, 4), events: []string{
+		`{"type":"session.created"}`,
+		`{"type":"response.audio_transcript.delta","delta":"o"}`,
+		`{"type":"response.audio.delta","delta":"` + audio + `"}`,
+		`{"type":"response.audio_transcript.delta","delta":"k"}`,
+		`{"type":"response.audio.delta","delta":"` + audio + `"}`,
+		`{"type":"response.done","response":{"status":"completed","usage":{"input_tokens":12,"output_tokens":40}}}`,
+	}}
+	adapter := newTestAdapter(t, server)
+
+	var transcript string
+	var audioBytes int
+	outcome, err := adapter.InvokeStream(testRequest(), func(chunk contracts.StreamChunk) error {
+		transcript += chunk.Text
+		audioBytes += len(chunk.Audio)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeSuccess || outcome.FirstChunkLatencyMS < 1 || outcome.Usage == nil || outcome.Usage.OutputTokens != 40 {
+		t.Fatalf("unexpected outcome: %+v", outcome)
+	}
+	if transcript != "ok" || audioBytes != 8 {
+		t.Fatalf("expected streamed transcript and audio, got %q and %d bytes", transcript, audioBytes)
+	}
+	if first, second := <-server.received, <-server.received; first != "conversation.item.create" || second != "response.create" {
+		t.Fatalf("unexpected client events: %s, %s", first, second)
+	}
+}
+
+func TestInvokeClassifiesOutcomes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		events       []string
+		apiKey       string
+		wantClass    contracts.OutcomeClass
+		wantReason   string
+		wantCapped   bool
+		wantRetrying bool
+	}{
+		{name: "rate limited", events: []string{`{"type":"error","error":{"type":"invalid_request_error","code":"rate_limit_exceeded"}}`}, wantClass: contracts.OutcomeOverload, wantReason: "provider_overload", wantRetrying: true},
+		{name: "invalid request", events: []string{`{"type":"error","error":{"type":"invalid_request_error","code":"unknown_parameter"}}`}, wantClass: contracts.OutcomeBlocked, wantReason: "provider_client_error"},
+		{name: "token capped", events: []string{`{"type":"response.done","response":{"status":"incomplete","status_details":{"reason":"max_output_tokens"}}}`}, wantClass: contracts.OutcomeSuccess, wantCapped: true},
+		{name: "content filtered", events: []string{`{"type":"response.done","response":{"status":"incomplete","status_details":{"reason":"content_filter"}}}`}, wantClass: contracts.OutcomeBlocked, wantReason: "provider_content_filtered"},
+		{name: "server failure", events: []string{`{"type":"response.done","response":{"status":"failed","status_details":{"error":{"type":"server_error"}}}}`}, wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_server_error", wantRetrying: true},
+		{name: "closed before done", events: []string{`{"type":"session.created"}`}, wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_transport_error", wantRetrying: true},
+		{name: "handshake rejected", apiKey: "wrong-key", wantClass: contracts.OutcomeBlocked, wantReason: "provider_auth_or_policy_block"},
+	}
+	for _, tc := range tests {
+		server := httptest.NewServer(&fakeRealtime{events: tc.events, received: make(chan string, 4)})
+		apiKey := tc.apiKey
+		if apiKey == "" {
+			apiKey = "test-key"
+		}
+		adapter, err := NewAdapter(Config{APIKey: apiKey, Endpoint: "ws" + strings.TrimPrefix(server.URL, "http"), Model: "gpt-test", Timeout: 5 * time.Second})
+		if err != nil {
+			t.Fatalf("%s: new adapter: %v", tc.name, err)
+		}
+		outcome, err := adapter.Invoke(testRequest())
+		server.Close()
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		if outcome.Class != tc.wantClass || outcome.Reason != tc.wantReason || outcome.LengthCapped != tc.wantCapped || outcome.Retryable != tc.wantRetrying {
+			t.Fatalf("%s: expected class=%s reason=%q capped=%t retryable=%t, got %+v", tc.name, tc.wantClass, tc.wantReason, tc.wantCapped, tc.wantRetrying, outcome)
+		}
+		if err := outcome.Validate(); err != nil {
+			t.Fatalf("%s: expected a valid outcome, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestInvokeStreamCancelSendsResponseCancel(t *testing.T) {
+	t.Parallel()
+
+	server := &fakeRealtime{received: make(chan string, 4), waitFor: "response.cancel", events: []string{
+		`{"type":"response.audio_transcript.delta","delta":"o"}`,
+	}}
+	adapter := newTestAdapter(t, server)
+
+	cancelSignal := make(chan struct{})
+	req := testRequest()
+	req.CancelSignal = cancelSignal
+	outcome, err := adapter.InvokeStream(req, func(contracts.StreamChunk) error {
+		close(cancelSignal)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeCancelled || outcome.Usage == nil || !outcome.Usage.Truncated {
+		t.Fatalf("expected cancelled outcome with truncated usage, got %+v", outcome)
+	}
+	<-server.received
+	<-server.received
+	select {
+	case event := <-server.received:
+		if event != "response.cancel" {
+			t.Fatalf("expected response.cancel, got %s", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the provider to receive response.cancel")
+	}
+}
+
+func TestInvokeWithoutEndpointIsBlocked(t *testing.T) {
+	t.Parallel()
+
+	adapter, err := NewAdapter(Config{})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	outcome, err := adapter.Invoke(testRequest())
+	if err != nil || outcome.Class != contracts.OutcomeBlocked || outcome.Reason != "provider_endpoint_missing" {
+		t.Fatalf("expected missing endpoint to block, got %+v err=%v", outcome, err)
+	}
+}
@@ -0,0 +1,45 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/wscodec"
+)
+
+// maxEventBytes bounds one reassembled server event; audio deltas are a few KB each.
+const maxEventBytes = 4 << 20
+
+// dialRealtime opens the Realtime API WebSocket through client. The handshake stops when ctx
+// is done.
+func dialRealtime(ctx context.Context, client *http.Client, endpoint string, header http.Header) (*wscodec.Conn, error) {
+	conn, err := wscodec.Dial(ctx, client, endpoint, header)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetMaxMessageBytes(maxEventBytes)
+	return conn, nil
+}
+
+// writeEvent sends one JSON event as a text message.
+func writeEvent(conn *wscodec.Conn, event any) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return conn.WriteMessage(wscodec.OpText, payload)
+}
+
+// readEvent returns the next JSON event; Realtime API events are always text messages.
+func readEvent(conn *wscodec.Conn) ([]byte, error) {
+	op, payload, err := conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	if op != wscodec.OpText {
+		return nil, fmt.Errorf("unexpected realtime binary message")
+	}
+	return payload, nil
+}
@@ -1,33 +1,72 @@
 package assemblyai
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
 	"github.com/tiger/realtime-speech-pipeline/providers/common/httpadapter"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/httpclient"
 )
 
-const ProviderID = "stt-assemblyai"
+const (
+	ProviderID = "stt-assemblyai"
+
+	// maxResponseBytes caps upload, submission, and poll response bodies.
+	maxResponseBytes = 1 << 20
+)
 
 type Config struct {
 	APIKey   string
 	Endpoint string
-	AudioURL string
-	Timeout  time.Duration
+	// UploadEndpoint receives captured audio before a transcript is submitted for it.
+	UploadEndpoint string
+	AudioURL       string
+	// PollInterval spaces transcript status polls for captured audio.
+	PollInterval time.Duration
+	Timeout      time.Duration
+	// HTTPClient overrides the shared provider client factory (httpclient.Shared).
+	HTTPClient *http.Client
+}
+
+// Adapter submits the configured sample clip through the generic HTTP adapter and recognizes
+// captured audio with the upload, submit, and poll flow of the AssemblyAI transcript API.
+type Adapter struct {
+	*httpadapter.Adapter
+	cfg    Config
+	client *http.Client
 }
 
 func ConfigFromEnv() Config {
 	return Config{
-		APIKey:   os.Getenv("RSPP_STT_ASSEMBLYAI_API_KEY"),
-		Endpoint: defaultString(os.Getenv("RSPP_STT_ASSEMBLYAI_ENDPOINT"), "https://api.assemblyai.com/v2/transcript"),
-		AudioURL: defaultString(os.Getenv("RSPP_STT_ASSEMBLYAI_AUDIO_URL"), "https://static.deepgram.com/examples/Bueller-Life-moves-pretty-fast.wav"),
-		Timeout:  10 * time.Second,
+		APIKey:         os.Getenv("RSPP_STT_ASSEMBLYAI_API_KEY"),
+		Endpoint:       defaultString(os.Getenv("RSPP_STT_ASSEMBLYAI_ENDPOINT"), "https://api.assemblyai.com/v2/transcript"),
+		UploadEndpoint: defaultString(os.Getenv("RSPP_STT_ASSEMBLYAI_UPLOAD_ENDPOINT"), "https://api.assemblyai.com/v2/upload"),
+		AudioURL:       defaultString(os.Getenv("RSPP_STT_ASSEMBLYAI_AUDIO_URL"), "https://static.deepgram.com/examples/Bueller-Life-moves-pretty-fast.wav"),
+		PollInterval:   500 * time.Millisecond,
+		Timeout:        10 * time.Second,
 	}
 }
 
 func NewAdapter(cfg Config) (contracts.Adapter, error) {
-	return httpadapter.New(httpadapter.Config{
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 500 * time.Millisecond
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = httpclient.Shared().Client(ProviderID)
+	}
+	sample, err := httpadapter.New(httpadapter.Config{
 		ProviderID:    ProviderID,
 		Modality:      contracts.ModalitySTT,
 		Endpoint:      cfg.Endpoint,
@@ -40,13 +79,142 @@ func NewAdapter(cfg Config) (contracts.Adapter, error) {
 				"audio_url": cfg.AudioURL,
 			}
 		},
+		HTTPClient: client,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &Adapter{Adapter: sample, cfg: cfg, client: client}, nil
 }
 
 func NewAdapterFromEnv() (contracts.Adapter, error) {
 	return NewAdapter(ConfigFromEnv())
 }
 
+// transcript is the subset of the transcript resource read while polling.
+type transcript struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Text   string `json:"text"`
+}
+
+// Invoke recognizes captured audio and returns the transcript; without captured audio it
+// submits the configured sample clip.
+func (a *Adapter) Invoke(req contracts.InvocationRequest) (contracts.Outcome, error) {
+	if req.Audio == nil {
+		return a.Adapter.Invoke(req)
+	}
+	if err := req.Validate(); err != nil {
+		return contracts.Outcome{}, err
+	}
+	if req.CancelRequested {
+		return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, nil
+	}
+	if a.cfg.Endpoint == "" || a.cfg.UploadEndpoint == "" {
+		return contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_endpoint_missing"}, nil
+	}
+	audio, err := httpadapter.WAVBody(req.Audio)
+	if err != nil {
+		return contracts.Outcome{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), req.AttemptTimeout(a.cfg.Timeout))
+	defer cancel()
+	if req.CancelSignal != nil {
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-req.CancelSignal:
+				cancel()
+			case <-done:
+			}
+		}()
+	}
+
+	started := time.Now()
+	var upload struct {
+		UploadURL string `json:"upload_url"`
+	}
+	if outcome, ok := a.call(ctx, req, http.MethodPost, a.cfg.UploadEndpoint, audio, "application/octet-stream", &upload); !ok {
+		return outcome, nil
+	}
+	submission, err := json.Marshal(map[string]any{"audio_url": upload.UploadURL})
+	if err != nil {
+		return contracts.Outcome{}, err
+	}
+	var result transcript
+	if outcome, ok := a.call(ctx, req, http.MethodPost, a.cfg.Endpoint, submission, "application/json", &result); !ok {
+		return outcome, nil
+	}
+	ticker := time.NewTicker(a.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		switch result.Status {
+		case "completed":
+			return contracts.Outcome{
+				Class:               contracts.OutcomeSuccess,
+				Text:                result.Text,
+				FirstChunkLatencyMS: max(time.Since(started).Milliseconds(), 1),
+			}, nil
+		case "error":
+			return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: false, Reason: "provider_transcription_failed"}, nil
+		}
+		if result.ID == "" {
+			return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: false, Reason: "provider_output_invalid"}, nil
+		}
+		select {
+		case <-ctx.Done():
+			if req.CancelSignalled() {
+				return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, nil
+			}
+			return httpadapter.NormalizeNetworkError(ctx.Err()), nil
+		case <-ticker.C:
+		}
+		if outcome, ok := a.call(ctx, req, http.MethodGet, strings.TrimRight(a.cfg.Endpoint, "/")+"/"+result.ID, nil, "", &result); !ok {
+			return outcome, nil
+		}
+	}
+}
+
+// call issues one transcript API request and decodes a successful JSON response into out. ok
+// is false when the request failed, with outcome describing why.
+func (a *Adapter) call(ctx context.Context, req contracts.InvocationRequest, method string, endpoint string, body []byte, contentType string, out any) (contracts.Outcome, bool) {
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_endpoint_invalid"}, false
+	}
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if a.cfg.APIKey != "" {
+		httpReq.Header.Set("Authorization", a.cfg.APIKey)
+	}
+	if req.Traceparent != "" {
+		httpReq.Header.Set(telemetry.TraceparentHeader, req.Traceparent)
+	}
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		if req.CancelSignalled() {
+			return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, false
+		}
+		return httpadapter.NormalizeNetworkError(err), false
+	}
+	defer resp.Body.Close()
+	if outcome := httpadapter.NormalizeStatus(resp.StatusCode, resp.Header.Get("Retry-After")); outcome.Class != contracts.OutcomeSuccess {
+		return outcome, false
+	}
+	payload, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return httpadapter.NormalizeNetworkError(err), false
+	}
+	if err := json.Unmarshal(payload, out); err != nil {
+		return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: false, Reason: "provider_output_invalid"}, false
+	}
+	return contracts.Outcome{}, true
+}
+
 func defaultString(v string, fallback string) string {
 	if v == "" {
 		return fallback
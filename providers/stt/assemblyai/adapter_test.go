@@ -0,0 +1,104 @@
+package assemblyai
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+func testRequest() contracts.InvocationRequest {
+	return contracts.InvocationRequest{
+		SessionID:            "sess-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-1",
+		ProviderInvocationID: "pvi/sess-1/turn-1/evt-1/stt",
+		ProviderID:           ProviderID,
+		Modality:             contracts.ModalitySTT,
+		Attempt:              1,
+		Audio:                &contracts.AudioInput{PCM: []int16{1, 2, 3, 4}, SampleRateHz: 16000},
+	}
+}
+
+// newTestServer serves the upload, submit, and poll endpoints; the transcript reaches
+// finalStatus on the second poll.
+func newTestServer(t *testing.T, finalStatus string) (*httptest.Server, *[]byte) {
+	t.Helper()
+	var uploaded []byte
+	var polls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v2/upload", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "test-key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		uploaded, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"upload_url":"https://cdn.example/clip"}`))
+	})
+	mux.HandleFunc("POST /v2/transcript", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["audio_url"] != "https://cdn.example/clip" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"tr-1","status":"queued"}`))
+	})
+	mux.HandleFunc("GET /v2/transcript/tr-1", func(w http.ResponseWriter, r *http.Request) {
+		if polls.Add(1) < 2 {
+			_, _ = w.Write([]byte(`{"id":"tr-1","status":"processing"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"tr-1","status":"` + finalStatus + `","text":"Book a table for two."}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &uploaded
+}
+
+func TestInvokeTranscribesRequestAudio(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		status     string
+		apiKey     string
+		wantClass  contracts.OutcomeClass
+		wantReason string
+		wantText   string
+	}{
+		{name: "completed", status: "completed", apiKey: "test-key", wantClass: contracts.OutcomeSuccess, wantText: "Book a table for two."},
+		{name: "transcription error", status: "error", apiKey: "test-key", wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_transcription_failed"},
+		{name: "auth rejected", status: "completed", apiKey: "wrong", wantClass: contracts.OutcomeBlocked, wantReason: "provider_auth_or_policy_block"},
+	}
+	for _, tc := range tests {
+		server, uploaded := newTestServer(t, tc.status)
+		adapter, err := NewAdapter(Config{
+			APIKey:         tc.apiKey,
+			Endpoint:       server.URL + "/v2/transcript",
+			UploadEndpoint: server.URL + "/v2/upload",
+			PollInterval:   time.Millisecond,
+			HTTPClient:     server.Client(),
+		})
+		if err != nil {
+			t.Fatalf("%s: new adapter: %v", tc.name, err)
+		}
+		outcome, err := adapter.Invoke(testRequest())
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		if outcome.Class != tc.wantClass || outcome.Reason != tc.wantReason || outcome.Text != tc.wantText {
+			t.Fatalf("%s: expected class=%s reason=%q text=%q, got %+v", tc.name, tc.wantClass, tc.wantReason, tc.wantText, outcome)
+		}
+		if tc.wantClass == contracts.OutcomeSuccess && (len(*uploaded) != 44+8 || string((*uploaded)[:4]) != "RIFF") {
+			t.Fatalf("%s: expected the request audio uploaded as WAV, got %d bytes", tc.name, len(*uploaded))
+		}
+		if err := outcome.Validate(); err != nil {
+			t.Fatalf("%s: expected a valid outcome, got %v", tc.name, err)
+		}
+	}
+}
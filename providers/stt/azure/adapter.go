@@ -0,0 +1,258 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/httpadapter"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/httpclient"
+)
+
+const (
+	ProviderID = "stt-azure-speech"
+
+	// EnableEnv opts the adapter into bootstrap.BuildMVPProviders; it is off by default.
+	EnableEnv = "RSPP_STT_AZURE_ENABLE"
+
+	// maxResultBytes caps the recognition result body.
+	maxResultBytes = 1 << 20
+)
+
+type Config struct {
+	APIKey   string
+	Endpoint string
+	Language string
+	// AudioURL is the WAV clip streamed to the recognizer, mirroring the sample-audio smoke
+	// inputs of the other STT adapters.
+	AudioURL    string
+	ContentType string
+	Timeout     time.Duration
+	// HTTPClient overrides the shared provider client factory (httpclient.Shared).
+	HTTPClient *http.Client
+}
+
+// Adapter invokes the Azure Speech short-audio recognition REST API. Audio is uploaded with
+// chunked transfer as it is read, so recognition starts before the clip is fully sent.
+type Adapter struct {
+	cfg    Config
+	client *http.Client
+}
+
+func ConfigFromEnv() Config {
+	region := defaultString(os.Getenv("RSPP_AZURE_SPEECH_REGION"), "eastus")
+	return Config{
+		APIKey:      os.Getenv("RSPP_STT_AZURE_API_KEY"),
+		Endpoint:    defaultString(os.Getenv("RSPP_STT_AZURE_ENDPOINT"), "https://"+region+".stt.speech.microsoft.com/speech/recognition/conversation/cognitiveservices/v1"),
+		Language:    defaultString(os.Getenv("RSPP_STT_AZURE_LANGUAGE"), "en-US"),
+		AudioURL:    defaultString(os.Getenv("RSPP_STT_AZURE_AUDIO_URL"), "https://raw.githubusercontent.com/Azure-Samples/cognitive-services-speech-sdk/master/samples/csharp/sharedcontent/console/whatstheweatherlike.wav"),
+		ContentType: "audio/wav; codecs=audio/pcm; samplerate=16000",
+		Timeout:     10 * time.Second,
+	}
+}
+
+// EnabledFromEnv reports whether EnableEnv opts the adapter in.
+func EnabledFromEnv() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(EnableEnv))) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+func NewAdapter(cfg Config) (contracts.Adapter, error) {
+	if strings.TrimSpace(cfg.Language) == "" {
+		cfg.Language = "en-US"
+	}
+	if strings.TrimSpace(cfg.ContentType) == "" {
+		cfg.ContentType = "audio/wav; codecs=audio/pcm; samplerate=16000"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = httpclient.Shared().Client(ProviderID)
+	}
+	return &Adapter{cfg: cfg, client: client}, nil
+}
+
+func NewAdapterFromEnv() (contracts.Adapter, error) {
+	return NewAdapter(ConfigFromEnv())
+}
+
+func (a *Adapter) ProviderID() string {
+	return ProviderID
+}
+
+func (a *Adapter) Modality() contracts.Modality {
+	return contracts.ModalitySTT
+}
+
+// Invoke recognizes the captured audio, or the configured clip, and returns the transcript.
+func (a *Adapter) Invoke(req contracts.InvocationRequest) (contracts.Outcome, error) {
+	return a.InvokeStream(req, nil)
+}
+
+// recognitionResult is the simple-format short-audio recognition response.
+type recognitionResult struct {
+	RecognitionStatus string `json:"RecognitionStatus"`
+	DisplayText       string `json:"DisplayText"`
+}
+
+// InvokeStream streams the captured audio, or the configured clip, to the recognizer and calls onChunk with the recognized text.
+// A clip with no recognizable speech succeeds without a chunk; the runtime owns no-speech
+// handling.
+func (a *Adapter) InvokeStream(req contracts.InvocationRequest, onChunk func(contracts.StreamChunk) error) (contracts.Outcome, error) {
+	if err := req.Validate(); err != nil {
+		return contracts.Outcome{}, err
+	}
+	if req.CancelRequested {
+		return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, nil
+	}
+	if a.cfg.Endpoint == "" {
+		return contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_endpoint_missing"}, nil
+	}
+	endpoint, err := url.Parse(a.cfg.Endpoint)
+	if err != nil {
+		return contracts.Outcome{}, err
+	}
+	language := a.cfg.Language
+	// Azure short-audio recognition takes one language; the first session hint wins.
+	if req.Locale != nil && len(req.Locale.STTLanguageHints) > 0 {
+		language = req.Locale.STTLanguageHints[0]
+	}
+	query := endpoint.Query()
+	query.Set("language", language)
+	query.Set("format", "simple")
+	endpoint.RawQuery = query.Encode()
+
+	ctx, cancel := context.WithTimeout(context.Background(), req.AttemptTimeout(a.cfg.Timeout))
+	defer cancel()
+	if req.CancelSignal != nil {
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-req.CancelSignal:
+				cancel()
+			case <-done:
+			}
+		}()
+	}
+
+	audio, outcome, ok := a.openAudio(ctx, req)
+	if !ok {
+		return outcome, nil
+	}
+	defer audio.Close()
+
+	// Hiding the length behind a plain reader makes the client use chunked transfer encoding.
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), io.NopCloser(audio))
+	if err != nil {
+		return contracts.Outcome{}, err
+	}
+	httpReq.Header.Set("Content-Type", a.cfg.ContentType)
+	httpReq.Header.Set("Accept", "application/json")
+	if a.cfg.APIKey != "" {
+		httpReq.Header.Set("Ocp-Apim-Subscription-Key", a.cfg.APIKey)
+	}
+	if req.Traceparent != "" {
+		httpReq.Header.Set(telemetry.TraceparentHeader, req.Traceparent)
+	}
+
+	started := time.Now()
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		if req.CancelSignalled() {
+			return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, nil
+		}
+		return httpadapter.NormalizeNetworkError(err), nil
+	}
+	defer resp.Body.Close()
+	if outcome := httpadapter.NormalizeStatus(resp.StatusCode, resp.Header.Get("Retry-After")); outcome.Class != contracts.OutcomeSuccess {
+		return outcome, nil
+	}
+	payload, err := io.ReadAll(io.LimitReader(resp.Body, maxResultBytes))
+	if err != nil {
+		if req.CancelSignalled() {
+			return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, nil
+		}
+		return httpadapter.NormalizeNetworkError(err), nil
+	}
+	var result recognitionResult
+	if err := json.Unmarshal(payload, &result); err != nil {
+		return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: false, Reason: "provider_output_invalid"}, nil
+	}
+	outcome = classifyRecognition(result.RecognitionStatus)
+	if outcome.Class != contracts.OutcomeSuccess || result.DisplayText == "" {
+		return outcome, nil
+	}
+	outcome.FirstChunkLatencyMS = max(time.Since(started).Milliseconds(), 1)
+	outcome.Text = result.DisplayText
+	if onChunk != nil {
+		if err := onChunk(contracts.StreamChunk{Text: result.DisplayText}); err != nil {
+			return contracts.Outcome{}, err
+		}
+	}
+	return outcome, nil
+}
+
+// openAudio opens the captured audio, or the configured clip, for streaming. ok is false when
+// the clip is unavailable, with outcome describing why.
+func (a *Adapter) openAudio(ctx context.Context, req contracts.InvocationRequest) (io.ReadCloser, contracts.Outcome, bool) {
+	if req.Audio != nil {
+		body, err := httpadapter.WAVBody(req.Audio)
+		if err != nil {
+			return nil, contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_audio_missing"}, false
+		}
+		return io.NopCloser(bytes.NewReader(body)), contracts.Outcome{}, true
+	}
+	if a.cfg.AudioURL == "" {
+		return nil, contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_audio_missing"}, false
+	}
+	audioReq, err := http.NewRequestWithContext(ctx, http.MethodGet, a.cfg.AudioURL, nil)
+	if err != nil {
+		return nil, contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_audio_missing"}, false
+	}
+	resp, err := a.client.Do(audioReq)
+	if err != nil {
+		if req.CancelSignalled() {
+			return nil, contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, false
+		}
+		return nil, httpadapter.NormalizeNetworkError(err), false
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		_ = resp.Body.Close()
+		return nil, contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: true, Reason: "provider_audio_unavailable"}, false
+	}
+	return resp.Body, contracts.Outcome{}, true
+}
+
+// classifyRecognition maps a RecognitionStatus onto the normalized outcome taxonomy.
+func classifyRecognition(status string) contracts.Outcome {
+	switch status {
+	case "Success", "NoMatch", "InitialSilenceTimeout", "BabbleTimeout":
+		return contracts.Outcome{Class: contracts.OutcomeSuccess}
+	case "Error":
+		return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: true, Reason: "provider_server_error", CircuitOpen: true}
+	default:
+		return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: false, Reason: "provider_output_invalid"}
+	}
+}
+
+func defaultString(v string, fallback string) string {
+	if strings.TrimSpace(v) == "" {
+		return fallback
+	}
+	return v
+}
@@ -0,0 +1,163 @@
+package azure
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+func testRequest() contracts.InvocationRequest {
+	return contracts.InvocationRequest{
+		SessionID:            "sess-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-1",
+		ProviderInvocationID: "pvi/sess-1/turn-1/evt-1/stt",
+		ProviderID:           ProviderID,
+		Modality:             contracts.ModalitySTT,
+		Attempt:              1,
+	}
+}
+
+// newTestServer serves a sample clip at /audio.wav and answers recognition at /recognize with
+// status and body, recording the uploaded audio and query.
+func newTestServer(t *testing.T, status int, body string) (*httptest.Server, *http.Request, *[]byte) {
+	t.Helper()
+	var recognized http.Request
+	var uploaded []byte
+	mux := http.NewServeMux()
+	mux.HandleFunc("/audio.wav", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("RIFF-sample-clip"))
+	})
+	mux.HandleFunc("/recognize", func(w http.ResponseWriter, r *http.Request) {
+		recognized = *r
+		uploaded, _ = io.ReadAll(r.Body)
+		if r.Header.Get("Ocp-Apim-Subscription-Key") != "test-key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &recognized, &uploaded
+}
+
+func TestInvokeStreamDeliversTranscript(t *testing.T) {
+	t.Parallel()
+
+	server, recognized, uploaded := newTestServer(t, http.StatusOK, `{"RecognitionStatus":"Success","DisplayText":"What's the weather like?","Offset":100,"Duration":2000}`)
+	adapter, err := NewAdapter(Config{APIKey: "test-key", Endpoint: server.URL + "/recognize", AudioURL: server.URL + "/audio.wav", HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+
+	req := testRequest()
+	req.Locale = &contracts.Locale{STTLanguageHints: []string{"de-DE", "en-US"}}
+	var transcript string
+	outcome, err := adapter.(contracts.StreamingAdapter).InvokeStream(req, func(chunk contracts.StreamChunk) error {
+		transcript += chunk.Text
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeSuccess || outcome.FirstChunkLatencyMS < 1 || transcript != "What's the weather like?" {
+		t.Fatalf("unexpected outcome %+v with transcript %q", outcome, transcript)
+	}
+	if string(*uploaded) != "RIFF-sample-clip" || len(recognized.TransferEncoding) != 1 || recognized.TransferEncoding[0] != "chunked" {
+		t.Fatalf("expected the clip to be uploaded with chunked transfer, got %q %v", *uploaded, recognized.TransferEncoding)
+	}
+	if query := recognized.URL.Query(); query.Get("language") != "de-DE" || query.Get("format") != "simple" {
+		t.Fatalf("expected the first locale hint as recognition language, got %s", recognized.URL.RawQuery)
+	}
+}
+
+func TestInvokeRecognizesRequestAudio(t *testing.T) {
+	t.Parallel()
+
+	server, _, uploaded := newTestServer(t, http.StatusOK, `{"RecognitionStatus":"Success","DisplayText":"Book a table for two."}`)
+	adapter, err := NewAdapter(Config{APIKey: "test-key", Endpoint: server.URL + "/recognize", HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	req := testRequest()
+	req.Audio = &contracts.AudioInput{PCM: []int16{1, 2, 3, 4}, SampleRateHz: 16000}
+	outcome, err := adapter.Invoke(req)
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeSuccess || outcome.Text != "Book a table for two." {
+		t.Fatalf("expected the transcript in the outcome, got %+v", outcome)
+	}
+	if len(*uploaded) != 44+8 || string((*uploaded)[:4]) != "RIFF" {
+		t.Fatalf("expected the request audio uploaded as WAV, got %d bytes", len(*uploaded))
+	}
+}
+
+func TestInvokeClassifiesOutcomes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		apiKey     string
+		status     int
+		body       string
+		audioPath  string
+		wantClass  contracts.OutcomeClass
+		wantReason string
+	}{
+		{name: "no speech", status: http.StatusOK, body: `{"RecognitionStatus":"InitialSilenceTimeout"}`, wantClass: contracts.OutcomeSuccess},
+		{name: "recognizer error", status: http.StatusOK, body: `{"RecognitionStatus":"Error"}`, wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_server_error"},
+		{name: "malformed result", status: http.StatusOK, body: `not json`, wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_output_invalid"},
+		{name: "rate limited", status: http.StatusTooManyRequests, wantClass: contracts.OutcomeOverload, wantReason: "provider_overload"},
+		{name: "bad key", apiKey: "wrong-key", status: http.StatusOK, wantClass: contracts.OutcomeBlocked, wantReason: "provider_auth_or_policy_block"},
+		{name: "audio unavailable", status: http.StatusOK, audioPath: "/missing.wav", wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_audio_unavailable"},
+	}
+	for _, tc := range tests {
+		server, _, _ := newTestServer(t, tc.status, tc.body)
+		apiKey := tc.apiKey
+		if apiKey == "" {
+			apiKey = "test-key"
+		}
+		audioPath := tc.audioPath
+		if audioPath == "" {
+			audioPath = "/audio.wav"
+		}
+		adapter, err := NewAdapter(Config{APIKey: apiKey, Endpoint: server.URL + "/recognize", AudioURL: server.URL + audioPath, HTTPClient: server.Client()})
+		if err != nil {
+			t.Fatalf("%s: new adapter: %v", tc.name, err)
+		}
+		outcome, err := adapter.Invoke(testRequest())
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		if outcome.Class != tc.wantClass || outcome.Reason != tc.wantReason {
+			t.Fatalf("%s: expected class=%s reason=%q, got %+v", tc.name, tc.wantClass, tc.wantReason, outcome)
+		}
+		if err := outcome.Validate(); err != nil {
+			t.Fatalf("%s: expected a valid outcome, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestEnabledFromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{value: "", want: false},
+		{value: "0", want: false},
+		{value: "1", want: true},
+		{value: "true", want: true},
+	}
+	for _, tc := range tests {
+		t.Setenv(EnableEnv, tc.value)
+		if got := EnabledFromEnv(); got != tc.want {
+			t.Fatalf("%q: expected enabled=%t, got %t", tc.value, tc.want, got)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package deepgram
 
 import (
+	"encoding/json"
 	"os"
 	"time"
 
@@ -28,11 +29,15 @@ func ConfigFromEnv() Config {
 	}
 }
 
+// NewAdapter transcribes through the prerecorded /v1/listen route, which ignores Deepgram's
+// streaming endpointing parameters, so the adapter does not claim server-side endpointing and
+// the runtime endpointer bounds captured audio before it is sent.
 func NewAdapter(cfg Config) (contracts.Adapter, error) {
 	return httpadapter.New(httpadapter.Config{
 		ProviderID:    ProviderID,
 		Modality:      contracts.ModalitySTT,
 		Endpoint:      cfg.Endpoint,
+		StandbyPath:   "/v1/projects",
 		APIKey:        cfg.APIKey,
 		APIKeyHeader:  "Authorization",
 		APIKeyPrefix:  "Token ",
@@ -44,9 +49,57 @@ func NewAdapter(cfg Config) (contracts.Adapter, error) {
 				"model": cfg.Model,
 			}
 		},
+		BuildRawBody:       audioBody,
+		ResponseText:       responseText,
+		ResponseConfidence: responseConfidence,
 	})
 }
 
+// audioBody uploads captured audio as a WAV body; without it the sample AudioURL is sent.
+func audioBody(req contracts.InvocationRequest) ([]byte, string, error) {
+	if req.Audio == nil {
+		return nil, "", nil
+	}
+	body, err := httpadapter.WAVBody(req.Audio)
+	return body, "audio/wav", err
+}
+
+// topAlternative is the first transcript alternative of the first channel.
+type topAlternative struct {
+	Transcript string  `json:"transcript"`
+	Confidence float64 `json:"confidence"`
+}
+
+func decodeTopAlternative(body []byte) (topAlternative, bool) {
+	var resp struct {
+		Results struct {
+			Channels []struct {
+				Alternatives []topAlternative `json:"alternatives"`
+			} `json:"channels"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || len(resp.Results.Channels) == 0 {
+		return topAlternative{}, false
+	}
+	alternatives := resp.Results.Channels[0].Alternatives
+	if len(alternatives) == 0 {
+		return topAlternative{}, false
+	}
+	return alternatives[0], true
+}
+
+// responseText returns the top transcript alternative of the first channel.
+func responseText(body []byte) string {
+	alternative, _ := decodeTopAlternative(body)
+	return alternative.Transcript
+}
+
+// responseConfidence returns the top alternative's transcript confidence.
+func responseConfidence(body []byte) float64 {
+	alternative, _ := decodeTopAlternative(body)
+	return alternative.Confidence
+}
+
 func NewAdapterFromEnv() (contracts.Adapter, error) {
 	return NewAdapter(ConfigFromEnv())
 }
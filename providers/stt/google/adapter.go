@@ -1,7 +1,11 @@
 package google
 
 import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
@@ -45,22 +49,67 @@ func NewAdapter(cfg Config) (contracts.Adapter, error) {
 		QueryAPIKeyParam: "key",
 		Timeout:          cfg.Timeout,
 		BuildBody: func(req contracts.InvocationRequest) any {
+			config := map[string]any{
+				"languageCode":               cfg.Language,
+				"model":                      cfg.Model,
+				"enableAutomaticPunctuation": cfg.EnablePunc,
+				"audioChannelCount":          cfg.ChannelMode,
+				"sampleRateHertz":            cfg.SampleRate,
+			}
+			// Session locale hints override the configured language; extra hints become alternates.
+			if req.Locale != nil && len(req.Locale.STTLanguageHints) > 0 {
+				config["languageCode"] = req.Locale.STTLanguageHints[0]
+				if len(req.Locale.STTLanguageHints) > 1 {
+					config["alternativeLanguageCodes"] = req.Locale.STTLanguageHints[1:]
+				}
+			}
+			audio := map[string]any{"uri": cfg.AudioURI}
+			// Captured audio is sent inline as LINEAR16 in place of the sample clip.
+			if req.Audio != nil {
+				config["encoding"] = "LINEAR16"
+				config["sampleRateHertz"] = req.Audio.SampleRateHz
+				config["audioChannelCount"] = 1
+				audio = map[string]any{"content": linear16(req.Audio.PCM)}
+			}
 			return map[string]any{
-				"config": map[string]any{
-					"languageCode":               cfg.Language,
-					"model":                      cfg.Model,
-					"enableAutomaticPunctuation": cfg.EnablePunc,
-					"audioChannelCount":          cfg.ChannelMode,
-					"sampleRateHertz":            cfg.SampleRate,
-				},
-				"audio": map[string]any{
-					"uri": cfg.AudioURI,
-				},
+				"config": config,
+				"audio":  audio,
 			}
 		},
+		ResponseText: responseText,
 	})
 }
 
+// linear16 base64-encodes PCM samples as little-endian LINEAR16.
+func linear16(pcm []int16) string {
+	raw := make([]byte, len(pcm)*2)
+	for i, sample := range pcm {
+		binary.LittleEndian.PutUint16(raw[i*2:], uint16(sample))
+	}
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+// responseText joins the top alternative of each recognition result.
+func responseText(body []byte) string {
+	var resp struct {
+		Results []struct {
+			Alternatives []struct {
+				Transcript string `json:"transcript"`
+			} `json:"alternatives"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return ""
+	}
+	parts := make([]string, 0, len(resp.Results))
+	for _, result := range resp.Results {
+		if len(result.Alternatives) > 0 {
+			parts = append(parts, strings.TrimSpace(result.Alternatives[0].Transcript))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
 func NewAdapterFromEnv() (contracts.Adapter, error) {
 	return NewAdapter(ConfigFromEnv())
 }
@@ -0,0 +1,205 @@
+package whispercpp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/recording"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/localproc"
+)
+
+const (
+	ProviderID = "stt-whisper-cpp"
+
+	// sampleRateHz is the only input rate whisper.cpp accepts.
+	sampleRateHz = 16000
+)
+
+type Config struct {
+	// BinaryPath is the whisper.cpp command-line binary (whisper-cli in current releases).
+	BinaryPath string
+	// ModelPath is the ggml model file, e.g. ggml-base.en.bin.
+	ModelPath string
+	Language  string
+	// AudioPath is the 16 kHz WAV clip Invoke recognizes, mirroring the sample-audio smoke
+	// inputs of the cloud STT adapters.
+	AudioPath string
+	Threads   int
+	Timeout   time.Duration
+}
+
+// Adapter runs whisper.cpp as a local process per recognition; no network or credentials.
+type Adapter struct {
+	cfg Config
+}
+
+func ConfigFromEnv() Config {
+	threads, _ := strconv.Atoi(os.Getenv("RSPP_STT_WHISPERCPP_THREADS"))
+	return Config{
+		BinaryPath: defaultString(os.Getenv("RSPP_STT_WHISPERCPP_BIN"), "whisper-cli"),
+		ModelPath:  os.Getenv("RSPP_STT_WHISPERCPP_MODEL"),
+		Language:   defaultString(os.Getenv("RSPP_STT_WHISPERCPP_LANGUAGE"), "en"),
+		AudioPath:  os.Getenv("RSPP_STT_WHISPERCPP_AUDIO"),
+		Threads:    threads,
+		Timeout:    30 * time.Second,
+	}
+}
+
+func NewAdapter(cfg Config) (contracts.Adapter, error) {
+	if strings.TrimSpace(cfg.BinaryPath) == "" {
+		cfg.BinaryPath = "whisper-cli"
+	}
+	if strings.TrimSpace(cfg.Language) == "" {
+		cfg.Language = "en"
+	}
+	if cfg.Threads < 0 {
+		return nil, fmt.Errorf("whisper.cpp threads must be >=0")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &Adapter{cfg: cfg}, nil
+}
+
+func NewAdapterFromEnv() (contracts.Adapter, error) {
+	return NewAdapter(ConfigFromEnv())
+}
+
+func (a *Adapter) ProviderID() string {
+	return ProviderID
+}
+
+func (a *Adapter) Modality() contracts.Modality {
+	return contracts.ModalitySTT
+}
+
+// Invoke recognizes the captured audio, or the configured clip, and returns the transcript.
+func (a *Adapter) Invoke(req contracts.InvocationRequest) (contracts.Outcome, error) {
+	return a.InvokeStream(req, nil)
+}
+
+// InvokeStream recognizes the captured audio, or the configured clip, and calls onChunk with the transcript. A clip with
+// no recognizable speech succeeds without a chunk.
+func (a *Adapter) InvokeStream(req contracts.InvocationRequest, onChunk func(contracts.StreamChunk) error) (contracts.Outcome, error) {
+	if err := req.Validate(); err != nil {
+		return contracts.Outcome{}, err
+	}
+	if req.CancelRequested {
+		return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, nil
+	}
+	if localproc.FileMissing(a.cfg.ModelPath) {
+		return contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_model_missing"}, nil
+	}
+	audioPath := a.cfg.AudioPath
+	if req.Audio != nil {
+		clip, err := writeClip(req.Audio.PCM, req.Audio.SampleRateHz)
+		if err != nil {
+			return contracts.Outcome{}, err
+		}
+		defer os.Remove(clip)
+		audioPath = clip
+	}
+	if localproc.FileMissing(audioPath) {
+		return contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_audio_missing"}, nil
+	}
+	language := a.cfg.Language
+	// whisper.cpp takes a bare language code; the first session hint wins.
+	if req.Locale != nil && len(req.Locale.STTLanguageHints) > 0 {
+		language, _, _ = strings.Cut(req.Locale.STTLanguageHints[0], "-")
+	}
+
+	ctx, cancel := localproc.Context(req, a.cfg.Timeout)
+	defer cancel()
+	started := time.Now()
+	transcript, err := a.run(ctx, audioPath, language)
+	if err != nil {
+		return localproc.NormalizeError(ctx, req, err), nil
+	}
+	outcome := contracts.Outcome{Class: contracts.OutcomeSuccess, Text: transcript}
+	if transcript == "" {
+		return outcome, nil
+	}
+	outcome.FirstChunkLatencyMS = max(time.Since(started).Milliseconds(), 1)
+	if onChunk != nil {
+		if err := onChunk(contracts.StreamChunk{Text: transcript}); err != nil {
+			return contracts.Outcome{}, err
+		}
+	}
+	return outcome, nil
+}
+
+// Transcribe recognizes captured mono PCM, so the adapter can serve the local demo and loopback
+// chains directly.
+func (a *Adapter) Transcribe(pcm []int16, sampleRate int) (string, error) {
+	if sampleRate < 1 {
+		return "", fmt.Errorf("sample_rate_hz must be >=1")
+	}
+	if localproc.FileMissing(a.cfg.ModelPath) {
+		return "", fmt.Errorf("whisper.cpp model %q is missing", a.cfg.ModelPath)
+	}
+	clip, err := writeClip(pcm, sampleRate)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(clip)
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.Timeout)
+	defer cancel()
+	transcript, err := a.run(ctx, clip, a.cfg.Language)
+	if err != nil {
+		return "", fmt.Errorf("whisper.cpp: %w", err)
+	}
+	return transcript, nil
+}
+
+// writeClip resamples mono PCM to the whisper.cpp input rate and writes it to a temporary WAV
+// file the caller removes.
+func writeClip(pcm []int16, sampleRate int) (string, error) {
+	clip, err := os.CreateTemp("", "rspp-whisper-*.wav")
+	if err != nil {
+		return "", err
+	}
+	if err := (recording.WAVEncoder{}).Encode(clip, localproc.Resample(pcm, sampleRate, sampleRateHz), sampleRateHz, 1); err != nil {
+		_ = clip.Close()
+		_ = os.Remove(clip.Name())
+		return "", err
+	}
+	if err := clip.Close(); err != nil {
+		_ = os.Remove(clip.Name())
+		return "", err
+	}
+	return clip.Name(), nil
+}
+
+// run recognizes one WAV file and returns the whitespace-joined transcript.
+func (a *Adapter) run(ctx context.Context, audioPath string, language string) (string, error) {
+	args := []string{"-m", a.cfg.ModelPath, "-f", audioPath, "-l", language, "-nt", "-np"}
+	if a.cfg.Threads > 0 {
+		args = append(args, "-t", strconv.Itoa(a.cfg.Threads))
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, a.cfg.BinaryPath, args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	cmd.WaitDelay = localproc.WaitDelay
+	if err := cmd.Run(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return "", fmt.Errorf("%w: %s", err, detail)
+		}
+		return "", err
+	}
+	return strings.Join(strings.Fields(stdout.String()), " "), nil
+}
+
+func defaultString(v string, fallback string) string {
+	if strings.TrimSpace(v) == "" {
+		return fallback
+	}
+	return v
+}
@@ -0,0 +1,157 @@
+package whispercpp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+func testRequest() contracts.InvocationRequest {
+	return contracts.InvocationRequest{
+		SessionID:            "sess-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-1",
+		ProviderInvocationID: "pvi/sess-1/turn-1/evt-1/stt",
+		ProviderID:           ProviderID,
+		Modality:             contracts.ModalitySTT,
+		Attempt:              1,
+	}
+}
+
+// writeFile writes a file into dir and returns its path.
+func writeFile(t *testing.T, dir string, name string, content string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+// fakeWhisper writes a stand-in whisper.cpp binary that records its arguments next to itself
+// and runs body.
+func fakeWhisper(t *testing.T, body string) (Config, string) {
+	t.Helper()
+	dir := t.TempDir()
+	argsPath := filepath.Join(dir, "args")
+	binary := writeFile(t, dir, "whisper-cli", "#!/bin/sh\necho \"$@\" > "+argsPath+"\n"+body+"\n", 0o755)
+	return Config{
+		BinaryPath: binary,
+		ModelPath:  writeFile(t, dir, "ggml-base.en.bin", "model", 0o644),
+		AudioPath:  writeFile(t, dir, "clip.wav", "RIFF", 0o644),
+	}, argsPath
+}
+
+func TestInvokeStreamDeliversTranscript(t *testing.T) {
+	t.Parallel()
+
+	cfg, argsPath := fakeWhisper(t, `printf ' What is the\n weather like?\n'`)
+	cfg.Threads = 4
+	adapter, err := NewAdapter(cfg)
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	req := testRequest()
+	req.Locale = &contracts.Locale{STTLanguageHints: []string{"de-DE", "en-US"}}
+	var transcript string
+	outcome, err := adapter.(contracts.StreamingAdapter).InvokeStream(req, func(chunk contracts.StreamChunk) error {
+		transcript += chunk.Text
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeSuccess || outcome.FirstChunkLatencyMS < 1 || transcript != "What is the weather like?" {
+		t.Fatalf("unexpected outcome %+v with transcript %q", outcome, transcript)
+	}
+	args, err := os.ReadFile(argsPath)
+	if err != nil {
+		t.Fatalf("read recorded args: %v", err)
+	}
+	if !strings.Contains(string(args), "-f "+cfg.AudioPath) || !strings.Contains(string(args), "-l de -nt -np -t 4") {
+		t.Fatalf("expected clip, bare language code, and thread flags, got %s", args)
+	}
+}
+
+func TestTranscribeRecognizesCapturedAudio(t *testing.T) {
+	t.Parallel()
+
+	// The stand-in echoes the RIFF sample rate field of the clip it was given.
+	cfg, _ := fakeWhisper(t, `for arg in "$@"; do case "$prev" in -f) od -An -tu4 -j24 -N4 "$arg";; esac; prev="$arg"; done`)
+	adapter, err := NewAdapter(cfg)
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	transcript, err := adapter.(*Adapter).Transcribe(make([]int16, 4800), 48000)
+	if err != nil {
+		t.Fatalf("unexpected transcribe error: %v", err)
+	}
+	if transcript != "16000" {
+		t.Fatalf("expected captured audio resampled to a 16 kHz clip, got %q", transcript)
+	}
+}
+
+func TestInvokeRecognizesRequestAudio(t *testing.T) {
+	t.Parallel()
+
+	cfg, _ := fakeWhisper(t, `for arg in "$@"; do case "$prev" in -f) od -An -tu4 -j24 -N4 "$arg";; esac; prev="$arg"; done`)
+	cfg.AudioPath = ""
+	adapter, err := NewAdapter(cfg)
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	req := testRequest()
+	req.Audio = &contracts.AudioInput{PCM: make([]int16, 800), SampleRateHz: 8000}
+	outcome, err := adapter.Invoke(req)
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeSuccess || outcome.Text != "16000" {
+		t.Fatalf("expected the request audio recognized as a 16 kHz clip, got %+v", outcome)
+	}
+}
+
+func TestInvokeClassifiesOutcomes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		body       string
+		mutate     func(*Config)
+		deadlineMS int64
+		wantClass  contracts.OutcomeClass
+		wantReason string
+	}{
+		{name: "no speech", body: "exit 0", wantClass: contracts.OutcomeSuccess},
+		{name: "process failure", body: "echo 'failed to load model' >&2; exit 1", wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_process_failed"},
+		{name: "deadline", body: "sleep 5", deadlineMS: 50, wantClass: contracts.OutcomeTimeout, wantReason: "provider_timeout"},
+		{name: "binary missing", body: "exit 0", mutate: func(cfg *Config) { cfg.BinaryPath = filepath.Join(filepath.Dir(cfg.BinaryPath), "absent") }, wantClass: contracts.OutcomeBlocked, wantReason: "provider_binary_missing"},
+		{name: "model missing", body: "exit 0", mutate: func(cfg *Config) { cfg.ModelPath = "" }, wantClass: contracts.OutcomeBlocked, wantReason: "provider_model_missing"},
+		{name: "audio missing", body: "exit 0", mutate: func(cfg *Config) { cfg.AudioPath = cfg.AudioPath + ".absent" }, wantClass: contracts.OutcomeBlocked, wantReason: "provider_audio_missing"},
+	}
+	for _, tc := range tests {
+		cfg, _ := fakeWhisper(t, tc.body)
+		if tc.mutate != nil {
+			tc.mutate(&cfg)
+		}
+		adapter, err := NewAdapter(cfg)
+		if err != nil {
+			t.Fatalf("%s: new adapter: %v", tc.name, err)
+		}
+		req := testRequest()
+		req.DeadlineMS = tc.deadlineMS
+		outcome, err := adapter.Invoke(req)
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		if outcome.Class != tc.wantClass || outcome.Reason != tc.wantReason {
+			t.Fatalf("%s: expected class=%s reason=%q, got %+v", tc.name, tc.wantClass, tc.wantReason, outcome)
+		}
+		if err := outcome.Validate(); err != nil {
+			t.Fatalf("%s: expected a valid outcome, got %v", tc.name, err)
+		}
+	}
+}
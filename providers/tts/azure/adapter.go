@@ -0,0 +1,256 @@
+package azure
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/observability/telemetry"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/httpadapter"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/httpclient"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/ttsquality"
+)
+
+const (
+	ProviderID = "tts-azure-speech"
+
+	// EnableEnv opts the adapter into bootstrap.BuildMVPProviders; it is off by default.
+	EnableEnv = "RSPP_TTS_AZURE_ENABLE"
+
+	// outputFormat is raw PCM so audio can be streamed in fixed-duration chunks, capped, and
+	// quality-checked without decoding.
+	outputFormat = "raw-16khz-16bit-mono-pcm"
+	sampleRateHz = 16000
+	// bytesPerMS is the 16 kHz, 16-bit mono PCM byte rate.
+	bytesPerMS = sampleRateHz * 2 / 1000
+	// chunkBytes is 100 ms of audio per streamed chunk.
+	chunkBytes = 100 * bytesPerMS
+)
+
+type Config struct {
+	APIKey     string
+	Endpoint   string
+	VoiceName  string
+	Language   string
+	SampleText string
+	Timeout    time.Duration
+	// HTTPClient overrides the shared provider client factory (httpclient.Shared).
+	HTTPClient *http.Client
+
+	QualityChecks bool
+	Thresholds    ttsquality.Thresholds
+}
+
+// Adapter invokes the Azure Speech text-to-speech REST API and streams the synthesized PCM as
+// it arrives.
+type Adapter struct {
+	cfg    Config
+	client *http.Client
+}
+
+func ConfigFromEnv() Config {
+	region := defaultString(os.Getenv("RSPP_AZURE_SPEECH_REGION"), "eastus")
+	return Config{
+		APIKey:     os.Getenv("RSPP_TTS_AZURE_API_KEY"),
+		Endpoint:   defaultString(os.Getenv("RSPP_TTS_AZURE_ENDPOINT"), "https://"+region+".tts.speech.microsoft.com/cognitiveservices/v1"),
+		VoiceName:  defaultString(os.Getenv("RSPP_TTS_AZURE_VOICE"), "en-US-JennyNeural"),
+		Language:   defaultString(os.Getenv("RSPP_TTS_AZURE_LANGUAGE"), "en-US"),
+		SampleText: defaultString(os.Getenv("RSPP_TTS_AZURE_TEXT"), "Realtime speech pipeline live smoke test."),
+		Timeout:    15 * time.Second,
+
+		QualityChecks: ttsquality.EnabledFromEnv(),
+		Thresholds:    ttsquality.DefaultThresholds(),
+	}
+}
+
+// EnabledFromEnv reports whether EnableEnv opts the adapter in.
+func EnabledFromEnv() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(EnableEnv))) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+func NewAdapter(cfg Config) (contracts.Adapter, error) {
+	if strings.TrimSpace(cfg.VoiceName) == "" {
+		cfg.VoiceName = "en-US-JennyNeural"
+	}
+	if strings.TrimSpace(cfg.Language) == "" {
+		cfg.Language = "en-US"
+	}
+	if strings.TrimSpace(cfg.SampleText) == "" {
+		cfg.SampleText = "Realtime speech pipeline live smoke test."
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 15 * time.Second
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = httpclient.Shared().Client(ProviderID)
+	}
+	return &Adapter{cfg: cfg, client: client}, nil
+}
+
+func NewAdapterFromEnv() (contracts.Adapter, error) {
+	return NewAdapter(ConfigFromEnv())
+}
+
+func (a *Adapter) ProviderID() string {
+	return ProviderID
+}
+
+func (a *Adapter) Modality() contracts.Modality {
+	return contracts.ModalityTTS
+}
+
+// Invoke synthesizes the request text, or the configured text without one. Request text is
+// returned as Outcome.Audio; the configured sample is discarded.
+func (a *Adapter) Invoke(req contracts.InvocationRequest) (contracts.Outcome, error) {
+	return a.InvokeStream(req, nil)
+}
+
+// InvokeStream synthesizes the request or configured text and calls onChunk with 100 ms PCM chunks as the
+// response body arrives. Synthesis stops at the request audio cap with a capped success, and a
+// turn cancel aborts the response with a cancelled outcome.
+func (a *Adapter) InvokeStream(req contracts.InvocationRequest, onChunk func(contracts.StreamChunk) error) (contracts.Outcome, error) {
+	if err := req.Validate(); err != nil {
+		return contracts.Outcome{}, err
+	}
+	if req.CancelRequested {
+		return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, nil
+	}
+	if a.cfg.Endpoint == "" {
+		return contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_endpoint_missing"}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), req.AttemptTimeout(a.cfg.Timeout))
+	defer cancel()
+	if req.CancelSignal != nil {
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-req.CancelSignal:
+				cancel()
+			case <-done:
+			}
+		}()
+	}
+
+	text := req.TTSText(a.cfg.SampleText)
+	voice, language := a.cfg.VoiceName, a.cfg.Language
+	if req.Locale != nil && req.Locale.TTSVoice != "" {
+		voice, language = req.Locale.TTSVoice, req.Locale.TTSVoiceLocale
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.cfg.Endpoint, strings.NewReader(ssml(voice, language, text)))
+	if err != nil {
+		return contracts.Outcome{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/ssml+xml")
+	httpReq.Header.Set("X-Microsoft-OutputFormat", outputFormat)
+	httpReq.Header.Set("User-Agent", "realtime-speech-pipeline")
+	if a.cfg.APIKey != "" {
+		httpReq.Header.Set("Ocp-Apim-Subscription-Key", a.cfg.APIKey)
+	}
+	if req.Traceparent != "" {
+		httpReq.Header.Set(telemetry.TraceparentHeader, req.Traceparent)
+	}
+
+	started := time.Now()
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		if req.CancelSignalled() {
+			return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, nil
+		}
+		return httpadapter.NormalizeNetworkError(err), nil
+	}
+	defer resp.Body.Close()
+	if outcome := httpadapter.NormalizeStatus(resp.StatusCode, resp.Header.Get("Retry-After")); outcome.Class != contracts.OutcomeSuccess {
+		return outcome, nil
+	}
+
+	limitBytes := req.AudioOutputLimitMS(0) * bytesPerMS
+	var received int64
+	var audio []byte
+	firstChunkLatencyMS := int64(0)
+	buf := make([]byte, chunkBytes)
+	for {
+		n, readErr := io.ReadFull(resp.Body, buf)
+		capped := false
+		if limitBytes > 0 && received+int64(n) >= limitBytes {
+			// Trim to whole samples at the cap.
+			n = int(limitBytes-received) &^ 1
+			capped = true
+		}
+		if n > 0 {
+			if firstChunkLatencyMS == 0 {
+				firstChunkLatencyMS = max(time.Since(started).Milliseconds(), 1)
+			}
+			received += int64(n)
+			chunk := append([]byte(nil), buf[:n]...)
+			if a.cfg.QualityChecks || req.Text != "" {
+				audio = append(audio, chunk...)
+			}
+			if onChunk != nil {
+				if err := onChunk(contracts.StreamChunk{Audio: chunk}); err != nil {
+					return contracts.Outcome{}, err
+				}
+			}
+		}
+		if capped {
+			return contracts.Outcome{Class: contracts.OutcomeSuccess, LengthCapped: true, FirstChunkLatencyMS: firstChunkLatencyMS, Audio: audioOutput(req, audio)}, nil
+		}
+		if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) {
+			break
+		}
+		if readErr != nil {
+			if req.CancelSignalled() {
+				return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_stream_cancelled"}, nil
+			}
+			return httpadapter.NormalizeNetworkError(readErr), nil
+		}
+	}
+	if received == 0 {
+		return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: true, Reason: "provider_empty_audio"}, nil
+	}
+	if a.cfg.QualityChecks {
+		if err := ttsquality.CheckPCM16(audio, sampleRateHz, text, a.cfg.Thresholds); err != nil {
+			return ttsquality.FailureOutcome(), nil
+		}
+	}
+	return contracts.Outcome{Class: contracts.OutcomeSuccess, FirstChunkLatencyMS: firstChunkLatencyMS, Audio: audioOutput(req, audio)}, nil
+}
+
+// audioOutput returns the synthesized PCM for requests that carry their own text.
+func audioOutput(req contracts.InvocationRequest, audio []byte) *contracts.AudioOutput {
+	if req.Text == "" {
+		return nil
+	}
+	return &contracts.AudioOutput{PCM: ttsquality.DecodePCM16LE(audio), SampleRateHz: sampleRateHz}
+}
+
+// ssml wraps text in the single-voice SSML document the synthesis endpoint requires.
+func ssml(voice string, language string, text string) string {
+	return `<speak version="1.0" xmlns="http://www.w3.org/2001/10/synthesis" xml:lang="` + escapeXML(language) + `"><voice name="` + escapeXML(voice) + `">` + escapeXML(text) + `</voice></speak>`
+}
+
+func escapeXML(v string) string {
+	var escaped strings.Builder
+	_ = xml.EscapeText(&escaped, []byte(v))
+	return escaped.String()
+}
+
+func defaultString(v string, fallback string) string {
+	if strings.TrimSpace(v) == "" {
+		return fallback
+	}
+	return v
+}
@@ -0,0 +1,160 @@
+package azure
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+func testRequest() contracts.InvocationRequest {
+	return contracts.InvocationRequest{
+		SessionID:            "sess-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-1",
+		ProviderInvocationID: "pvi/sess-1/turn-1/evt-1/tts",
+		ProviderID:           ProviderID,
+		Modality:             contracts.ModalityTTS,
+		Attempt:              1,
+	}
+}
+
+// newTestServer answers synthesis with status and audio, recording the SSML body.
+func newTestServer(t *testing.T, status int, audio []byte) (*httptest.Server, *string) {
+	t.Helper()
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		body = string(payload)
+		if r.Header.Get("Ocp-Apim-Subscription-Key") != "test-key" || r.Header.Get("X-Microsoft-OutputFormat") != outputFormat {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write(audio)
+	}))
+	t.Cleanup(server.Close)
+	return server, &body
+}
+
+func TestInvokeStreamDeliversAudioChunks(t *testing.T) {
+	t.Parallel()
+
+	server, body := newTestServer(t, http.StatusOK, make([]byte, 250*bytesPerMS))
+	adapter, err := NewAdapter(Config{APIKey: "test-key", Endpoint: server.URL, SampleText: "Fish & chips", HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+
+	req := testRequest()
+	req.Locale = &contracts.Locale{TTSVoice: "de-DE-KatjaNeural", TTSVoiceLocale: "de-DE"}
+	var chunks []int
+	outcome, err := adapter.(contracts.StreamingAdapter).InvokeStream(req, func(chunk contracts.StreamChunk) error {
+		chunks = append(chunks, len(chunk.Audio))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeSuccess || outcome.LengthCapped || outcome.FirstChunkLatencyMS < 1 {
+		t.Fatalf("unexpected outcome: %+v", outcome)
+	}
+	if len(chunks) != 3 || chunks[0] != chunkBytes || chunks[2] != 50*bytesPerMS {
+		t.Fatalf("expected 100 ms chunks with a short tail, got %v", chunks)
+	}
+	if !strings.Contains(*body, `xml:lang="de-DE"`) || !strings.Contains(*body, `<voice name="de-DE-KatjaNeural">Fish &amp; chips</voice>`) {
+		t.Fatalf("expected escaped SSML with the session voice, got %s", *body)
+	}
+}
+
+func TestInvokeReturnsRequestTextAudio(t *testing.T) {
+	t.Parallel()
+
+	server, body := newTestServer(t, http.StatusOK, make([]byte, 250*bytesPerMS))
+	adapter, err := NewAdapter(Config{APIKey: "test-key", Endpoint: server.URL, SampleText: "Sample", HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	req := testRequest()
+	req.Text = "Your order shipped."
+	outcome, err := adapter.Invoke(req)
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeSuccess || outcome.Audio == nil {
+		t.Fatalf("expected synthesized audio, got %+v", outcome)
+	}
+	if outcome.Audio.SampleRateHz != sampleRateHz || len(outcome.Audio.PCM) != 250*sampleRateHz/1000 {
+		t.Fatalf("expected 250 ms of pcm at %d Hz, got %d samples at %d Hz", sampleRateHz, len(outcome.Audio.PCM), outcome.Audio.SampleRateHz)
+	}
+	if !strings.Contains(*body, ">Your order shipped.</voice>") {
+		t.Fatalf("expected the request text in the SSML, got %s", *body)
+	}
+}
+
+func TestInvokeStreamStopsAtAudioCap(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newTestServer(t, http.StatusOK, make([]byte, 500*bytesPerMS))
+	adapter, err := NewAdapter(Config{APIKey: "test-key", Endpoint: server.URL, HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+
+	req := testRequest()
+	req.MaxAudioOutputMS = 150
+	received := 0
+	outcome, err := adapter.(contracts.StreamingAdapter).InvokeStream(req, func(chunk contracts.StreamChunk) error {
+		received += len(chunk.Audio)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeSuccess || !outcome.LengthCapped || received != 150*bytesPerMS {
+		t.Fatalf("expected a capped success after 150 ms of audio, got %+v with %d bytes", outcome, received)
+	}
+}
+
+func TestInvokeClassifiesOutcomes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		apiKey     string
+		status     int
+		audio      []byte
+		wantClass  contracts.OutcomeClass
+		wantReason string
+	}{
+		{name: "empty audio", status: http.StatusOK, wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_empty_audio"},
+		{name: "rate limited", status: http.StatusTooManyRequests, wantClass: contracts.OutcomeOverload, wantReason: "provider_overload"},
+		{name: "bad ssml", status: http.StatusBadRequest, wantClass: contracts.OutcomeBlocked, wantReason: "provider_client_error"},
+		{name: "bad key", apiKey: "wrong-key", status: http.StatusOK, wantClass: contracts.OutcomeBlocked, wantReason: "provider_auth_or_policy_block"},
+		{name: "server failure", status: http.StatusBadGateway, wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_server_error"},
+	}
+	for _, tc := range tests {
+		server, _ := newTestServer(t, tc.status, tc.audio)
+		apiKey := tc.apiKey
+		if apiKey == "" {
+			apiKey = "test-key"
+		}
+		adapter, err := NewAdapter(Config{APIKey: apiKey, Endpoint: server.URL, HTTPClient: server.Client()})
+		if err != nil {
+			t.Fatalf("%s: new adapter: %v", tc.name, err)
+		}
+		outcome, err := adapter.Invoke(testRequest())
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		if outcome.Class != tc.wantClass || outcome.Reason != tc.wantReason {
+			t.Fatalf("%s: expected class=%s reason=%q, got %+v", tc.name, tc.wantClass, tc.wantReason, outcome)
+		}
+		if err := outcome.Validate(); err != nil {
+			t.Fatalf("%s: expected a valid outcome, got %v", tc.name, err)
+		}
+	}
+}
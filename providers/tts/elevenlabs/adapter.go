@@ -1,14 +1,24 @@
 package elevenlabs
 
 import (
+	"fmt"
+	"net/url"
 	"os"
 	"time"
 
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
 	"github.com/tiger/realtime-speech-pipeline/providers/common/httpadapter"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/ttsquality"
 )
 
-const ProviderID = "tts-elevenlabs"
+const (
+	ProviderID = "tts-elevenlabs"
+
+	// pcmOutputFormat is requested for quality checks and for request text returned as
+	// Outcome.Audio, both of which need raw PCM rather than MP3.
+	pcmOutputFormat = "pcm_16000"
+	pcmSampleRateHz = 16000
+)
 
 type Config struct {
 	APIKey   string
@@ -17,6 +27,9 @@ type Config struct {
 	ModelID  string
 	Text     string
 	Timeout  time.Duration
+
+	QualityChecks bool
+	Thresholds    ttsquality.Thresholds
 }
 
 func ConfigFromEnv() Config {
@@ -28,14 +41,18 @@ func ConfigFromEnv() Config {
 		ModelID:  defaultString(os.Getenv("RSPP_TTS_ELEVENLABS_MODEL"), "eleven_multilingual_v2"),
 		Text:     defaultString(os.Getenv("RSPP_TTS_ELEVENLABS_TEXT"), "Realtime speech pipeline live smoke test."),
 		Timeout:  15 * time.Second,
+
+		QualityChecks: ttsquality.EnabledFromEnv(),
+		Thresholds:    ttsquality.DefaultThresholds(),
 	}
 }
 
 func NewAdapter(cfg Config) (contracts.Adapter, error) {
-	return httpadapter.New(httpadapter.Config{
+	httpCfg := httpadapter.Config{
 		ProviderID:    ProviderID,
 		Modality:      contracts.ModalityTTS,
 		Endpoint:      cfg.Endpoint,
+		StandbyPath:   "/v1/user",
 		APIKey:        cfg.APIKey,
 		APIKeyHeader:  "xi-api-key",
 		Timeout:       cfg.Timeout,
@@ -43,16 +60,57 @@ func NewAdapter(cfg Config) (contracts.Adapter, error) {
 		BuildBody: func(req contracts.InvocationRequest) any {
 			return map[string]any{
 				"model_id": cfg.ModelID,
-				"text":     cfg.Text,
+				"text":     req.TTSText(cfg.Text),
+			}
+		},
+		BuildQuery: func(req contracts.InvocationRequest) map[string]string {
+			if req.Text == "" {
+				return nil
+			}
+			return map[string]string{"output_format": pcmOutputFormat}
+		},
+		ResponseAudio: func(req contracts.InvocationRequest, body []byte) (*contracts.AudioOutput, error) {
+			if req.Text == "" {
+				return nil, nil
+			}
+			if len(body) == 0 {
+				return nil, fmt.Errorf("empty audio")
 			}
+			return &contracts.AudioOutput{PCM: ttsquality.DecodePCM16LE(body), SampleRateHz: pcmSampleRateHz}, nil
 		},
-	})
+	}
+	if cfg.QualityChecks {
+		// Quality checks need raw PCM so duration/silence/clipping can be measured.
+		endpoint, err := withOutputFormat(cfg.Endpoint, pcmOutputFormat)
+		if err != nil {
+			return nil, err
+		}
+		httpCfg.Endpoint = endpoint
+		httpCfg.StaticHeaders = map[string]string{"Accept": "audio/pcm"}
+		httpCfg.ValidationFailureReason = ttsquality.OutcomeReason
+		httpCfg.ValidationFailureClass = contracts.OutcomeQualityFailure
+		httpCfg.ValidateResponse = func(req contracts.InvocationRequest, body []byte) error {
+			return ttsquality.CheckPCM16(body, pcmSampleRateHz, req.TTSText(cfg.Text), cfg.Thresholds)
+		}
+	}
+	return httpadapter.New(httpCfg)
 }
 
 func NewAdapterFromEnv() (contracts.Adapter, error) {
 	return NewAdapter(ConfigFromEnv())
 }
 
+func withOutputFormat(rawEndpoint string, format string) (string, error) {
+	u, err := url.Parse(rawEndpoint)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("output_format", format)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
 func defaultString(v string, fallback string) string {
 	if v == "" {
 		return fallback
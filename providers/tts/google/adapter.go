@@ -1,14 +1,25 @@
 package google
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
 	"github.com/tiger/realtime-speech-pipeline/providers/common/httpadapter"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/ttsquality"
 )
 
-const ProviderID = "tts-google"
+const (
+	ProviderID = "tts-google"
+
+	// pcmAudioEncoding is requested for quality checks and for request text returned as
+	// Outcome.Audio, both of which need PCM rather than the configured encoding.
+	pcmAudioEncoding = "LINEAR16"
+	pcmSampleRateHz  = 24000
+)
 
 type Config struct {
 	APIKey      string
@@ -18,6 +29,9 @@ type Config struct {
 	SampleText  string
 	AudioFormat string
 	Timeout     time.Duration
+
+	QualityChecks bool
+	Thresholds    ttsquality.Thresholds
 }
 
 func ConfigFromEnv() Config {
@@ -29,11 +43,15 @@ func ConfigFromEnv() Config {
 		SampleText:  defaultString(os.Getenv("RSPP_TTS_GOOGLE_TEXT"), "Realtime speech pipeline live smoke test."),
 		AudioFormat: defaultString(os.Getenv("RSPP_TTS_GOOGLE_AUDIO_ENCODING"), "MP3"),
 		Timeout:     15 * time.Second,
+
+		QualityChecks: ttsquality.EnabledFromEnv(),
+		Thresholds:    ttsquality.DefaultThresholds(),
 	}
 }
 
 func NewAdapter(cfg Config) (contracts.Adapter, error) {
-	return httpadapter.New(httpadapter.Config{
+	pcmConfig := map[string]any{"audioEncoding": pcmAudioEncoding, "sampleRateHertz": pcmSampleRateHz}
+	httpCfg := httpadapter.Config{
 		ProviderID:       ProviderID,
 		Modality:         contracts.ModalityTTS,
 		Endpoint:         cfg.Endpoint,
@@ -41,13 +59,66 @@ func NewAdapter(cfg Config) (contracts.Adapter, error) {
 		QueryAPIKeyParam: "key",
 		Timeout:          cfg.Timeout,
 		BuildBody: func(req contracts.InvocationRequest) any {
+			voice := map[string]any{"name": cfg.VoiceName, "languageCode": cfg.Language}
+			if req.Locale != nil && req.Locale.TTSVoice != "" {
+				voice = map[string]any{"name": req.Locale.TTSVoice, "languageCode": req.Locale.TTSVoiceLocale}
+			}
+			audioConfig := map[string]any{"audioEncoding": cfg.AudioFormat}
+			if cfg.QualityChecks || req.Text != "" {
+				audioConfig = pcmConfig
+			}
 			return map[string]any{
-				"input":       map[string]any{"text": cfg.SampleText},
-				"voice":       map[string]any{"name": cfg.VoiceName, "languageCode": cfg.Language},
-				"audioConfig": map[string]any{"audioEncoding": cfg.AudioFormat},
+				"input":       map[string]any{"text": req.TTSText(cfg.SampleText)},
+				"voice":       voice,
+				"audioConfig": audioConfig,
 			}
 		},
-	})
+		ResponseAudio: func(req contracts.InvocationRequest, body []byte) (*contracts.AudioOutput, error) {
+			if req.Text == "" {
+				return nil, nil
+			}
+			pcm, err := decodeSynthesizeResponse(body)
+			if err != nil {
+				return nil, err
+			}
+			return &contracts.AudioOutput{PCM: ttsquality.DecodePCM16LE(pcm), SampleRateHz: pcmSampleRateHz}, nil
+		},
+	}
+	if cfg.QualityChecks {
+		httpCfg.ValidationFailureReason = ttsquality.OutcomeReason
+		httpCfg.ValidationFailureClass = contracts.OutcomeQualityFailure
+		httpCfg.ValidateResponse = func(req contracts.InvocationRequest, body []byte) error {
+			return checkSynthesizeResponse(body, req.TTSText(cfg.SampleText), cfg.Thresholds)
+		}
+	}
+	return httpadapter.New(httpCfg)
+}
+
+func checkSynthesizeResponse(body []byte, text string, th ttsquality.Thresholds) error {
+	audio, err := decodeSynthesizeResponse(body)
+	if err != nil {
+		return err
+	}
+	return ttsquality.CheckPCM16(audio, pcmSampleRateHz, text, th)
+}
+
+// decodeSynthesizeResponse returns the LINEAR16 samples of a synthesize response, without the
+// WAV header the API prepends.
+func decodeSynthesizeResponse(body []byte) ([]byte, error) {
+	var resp struct {
+		AudioContent string `json:"audioContent"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decode synthesize response: %w", err)
+	}
+	audio, err := base64.StdEncoding.DecodeString(resp.AudioContent)
+	if err != nil {
+		return nil, fmt.Errorf("decode audio content: %w", err)
+	}
+	if pcm, _, ok := ttsquality.StripWAVHeader(audio); ok {
+		return pcm, nil
+	}
+	return audio, nil
 }
 
 func NewAdapterFromEnv() (contracts.Adapter, error) {
@@ -0,0 +1,259 @@
+package piper
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/localproc"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/ttsquality"
+)
+
+const (
+	ProviderID = "tts-piper"
+
+	// defaultSampleRateHz is the output rate of the medium and high quality piper voices.
+	defaultSampleRateHz = 22050
+)
+
+type Config struct {
+	BinaryPath string
+	// ModelPath is the .onnx voice; piper expects its .onnx.json config alongside.
+	ModelPath string
+	// SampleRateHz is the voice's output rate from its .onnx.json config; --output_raw carries
+	// no header.
+	SampleRateHz int
+	SampleText   string
+	Timeout      time.Duration
+
+	QualityChecks bool
+	Thresholds    ttsquality.Thresholds
+}
+
+// Adapter runs piper as a local process per synthesis and streams its raw PCM output as it is
+// produced; no network or credentials.
+type Adapter struct {
+	cfg Config
+}
+
+func ConfigFromEnv() Config {
+	sampleRate, _ := strconv.Atoi(os.Getenv("RSPP_TTS_PIPER_SAMPLE_RATE_HZ"))
+	return Config{
+		BinaryPath:   defaultString(os.Getenv("RSPP_TTS_PIPER_BIN"), "piper"),
+		ModelPath:    os.Getenv("RSPP_TTS_PIPER_MODEL"),
+		SampleRateHz: sampleRate,
+		SampleText:   defaultString(os.Getenv("RSPP_TTS_PIPER_TEXT"), "Realtime speech pipeline local smoke test."),
+		Timeout:      15 * time.Second,
+
+		QualityChecks: ttsquality.EnabledFromEnv(),
+		Thresholds:    ttsquality.DefaultThresholds(),
+	}
+}
+
+func NewAdapter(cfg Config) (contracts.Adapter, error) {
+	if strings.TrimSpace(cfg.BinaryPath) == "" {
+		cfg.BinaryPath = "piper"
+	}
+	if cfg.SampleRateHz < 0 {
+		return nil, fmt.Errorf("piper sample_rate_hz must be >=0")
+	}
+	if cfg.SampleRateHz == 0 {
+		cfg.SampleRateHz = defaultSampleRateHz
+	}
+	if strings.TrimSpace(cfg.SampleText) == "" {
+		cfg.SampleText = "Realtime speech pipeline local smoke test."
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 15 * time.Second
+	}
+	return &Adapter{cfg: cfg}, nil
+}
+
+func NewAdapterFromEnv() (contracts.Adapter, error) {
+	return NewAdapter(ConfigFromEnv())
+}
+
+func (a *Adapter) ProviderID() string {
+	return ProviderID
+}
+
+func (a *Adapter) Modality() contracts.Modality {
+	return contracts.ModalityTTS
+}
+
+// Invoke synthesizes the request text, or the configured text without one. Request text is
+// returned as Outcome.Audio; the configured sample is discarded.
+func (a *Adapter) Invoke(req contracts.InvocationRequest) (contracts.Outcome, error) {
+	return a.InvokeStream(req, nil)
+}
+
+// InvokeStream synthesizes the request or configured text and calls onChunk with 100 ms PCM chunks as piper
+// writes them. Synthesis stops the process at the request audio cap with a capped success, and a
+// turn cancel kills it with a cancelled outcome.
+func (a *Adapter) InvokeStream(req contracts.InvocationRequest, onChunk func(contracts.StreamChunk) error) (contracts.Outcome, error) {
+	if err := req.Validate(); err != nil {
+		return contracts.Outcome{}, err
+	}
+	if req.CancelRequested {
+		return contracts.Outcome{Class: contracts.OutcomeCancelled, Retryable: false, Reason: "provider_cancelled"}, nil
+	}
+	if localproc.FileMissing(a.cfg.ModelPath) {
+		return contracts.Outcome{Class: contracts.OutcomeBlocked, Retryable: false, Reason: "provider_model_missing"}, nil
+	}
+
+	ctx, cancel := localproc.Context(req, a.cfg.Timeout)
+	defer cancel()
+	bytesPerMS := int64(a.cfg.SampleRateHz) * 2 / 1000
+	text := req.TTSText(a.cfg.SampleText)
+	var audio []byte
+	started := time.Now()
+	firstChunkLatencyMS := int64(0)
+	received, capped, err := a.run(ctx, text, req.AudioOutputLimitMS(0)*bytesPerMS, func(chunk []byte) error {
+		if firstChunkLatencyMS == 0 {
+			firstChunkLatencyMS = max(time.Since(started).Milliseconds(), 1)
+		}
+		if a.cfg.QualityChecks || req.Text != "" {
+			audio = append(audio, chunk...)
+		}
+		if onChunk != nil {
+			return onChunk(contracts.StreamChunk{Audio: chunk})
+		}
+		return nil
+	})
+	var callbackErr *callbackError
+	if errors.As(err, &callbackErr) {
+		return contracts.Outcome{}, callbackErr.err
+	}
+	if err != nil {
+		outcome := localproc.NormalizeError(ctx, req, err)
+		if outcome.Class == contracts.OutcomeCancelled && received > 0 {
+			outcome.Reason = "provider_stream_cancelled"
+		}
+		return outcome, nil
+	}
+	var output *contracts.AudioOutput
+	if req.Text != "" {
+		output = &contracts.AudioOutput{PCM: ttsquality.DecodePCM16LE(audio), SampleRateHz: a.cfg.SampleRateHz}
+	}
+	if capped {
+		return contracts.Outcome{Class: contracts.OutcomeSuccess, LengthCapped: true, FirstChunkLatencyMS: firstChunkLatencyMS, Audio: output}, nil
+	}
+	if received == 0 {
+		return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: true, Reason: "provider_empty_audio"}, nil
+	}
+	if a.cfg.QualityChecks {
+		if err := ttsquality.CheckPCM16(audio, a.cfg.SampleRateHz, text, a.cfg.Thresholds); err != nil {
+			return ttsquality.FailureOutcome(), nil
+		}
+	}
+	return contracts.Outcome{Class: contracts.OutcomeSuccess, FirstChunkLatencyMS: firstChunkLatencyMS, Audio: output}, nil
+}
+
+// Synthesize renders text as mono PCM at sampleRate, so the adapter can serve the local demo and
+// loopback chains directly.
+func (a *Adapter) Synthesize(text string, sampleRate int) ([]int16, error) {
+	if sampleRate < 1 {
+		return nil, fmt.Errorf("sample_rate_hz must be >=1")
+	}
+	if localproc.FileMissing(a.cfg.ModelPath) {
+		return nil, fmt.Errorf("piper voice %q is missing", a.cfg.ModelPath)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.Timeout)
+	defer cancel()
+	var raw []byte
+	if _, _, err := a.run(ctx, text, 0, func(chunk []byte) error {
+		raw = append(raw, chunk...)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("piper: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("piper produced no audio")
+	}
+	pcm := make([]int16, len(raw)/2)
+	for i := range pcm {
+		pcm[i] = int16(binary.LittleEndian.Uint16(raw[2*i:]))
+	}
+	return localproc.Resample(pcm, a.cfg.SampleRateHz, sampleRate), nil
+}
+
+// callbackError marks an onChunk failure so it is returned to the caller rather than classified.
+type callbackError struct {
+	err error
+}
+
+func (e *callbackError) Error() string {
+	return e.err.Error()
+}
+
+// run pipes text to piper and calls onChunk with 100 ms chunks of its raw output. A positive
+// limitBytes stops the process once that much audio was delivered, reporting capped.
+func (a *Adapter) run(ctx context.Context, text string, limitBytes int64, onChunk func([]byte) error) (int64, bool, error) {
+	// Killing piper at the cap must not surface as a process failure.
+	procCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(procCtx, a.cfg.BinaryPath, "--model", a.cfg.ModelPath, "--output_raw")
+	cmd.Stdin = strings.NewReader(text + "\n")
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = localproc.WaitDelay
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return 0, false, err
+	}
+	if err := cmd.Start(); err != nil {
+		return 0, false, err
+	}
+
+	chunkBytes := max(int64(a.cfg.SampleRateHz)*2/10, 2)
+	buf := make([]byte, chunkBytes)
+	var received int64
+	for {
+		n, readErr := io.ReadFull(stdout, buf)
+		capped := false
+		if limitBytes > 0 && received+int64(n) >= limitBytes {
+			// Trim to whole samples at the cap.
+			n = int(limitBytes-received) &^ 1
+			capped = true
+		}
+		if n > 0 {
+			received += int64(n)
+			if err := onChunk(append([]byte(nil), buf[:n]...)); err != nil {
+				stop()
+				_ = cmd.Wait()
+				return received, false, &callbackError{err: err}
+			}
+		}
+		if capped {
+			stop()
+			_ = cmd.Wait()
+			return received, true, nil
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	if err := cmd.Wait(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return received, false, fmt.Errorf("%w: %s", err, detail)
+		}
+		return received, false, err
+	}
+	return received, false, nil
+}
+
+func defaultString(v string, fallback string) string {
+	if strings.TrimSpace(v) == "" {
+		return fallback
+	}
+	return v
+}
@@ -0,0 +1,176 @@
+package piper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+)
+
+func testRequest() contracts.InvocationRequest {
+	return contracts.InvocationRequest{
+		SessionID:            "sess-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-1",
+		ProviderInvocationID: "pvi/sess-1/turn-1/evt-1/tts",
+		ProviderID:           ProviderID,
+		Modality:             contracts.ModalityTTS,
+		Attempt:              1,
+	}
+}
+
+// fakePiper writes a stand-in piper binary that records its stdin next to itself and runs body.
+func fakePiper(t *testing.T, body string) (Config, string) {
+	t.Helper()
+	dir := t.TempDir()
+	stdinPath := filepath.Join(dir, "stdin")
+	binary := filepath.Join(dir, "piper")
+	if err := os.WriteFile(binary, []byte("#!/bin/sh\ncat > "+stdinPath+"\n"+body+"\n"), 0o755); err != nil {
+		t.Fatalf("write fake piper: %v", err)
+	}
+	model := filepath.Join(dir, "en_US-lessac-medium.onnx")
+	if err := os.WriteFile(model, []byte("onnx"), 0o644); err != nil {
+		t.Fatalf("write fake voice: %v", err)
+	}
+	return Config{BinaryPath: binary, ModelPath: model}, stdinPath
+}
+
+func TestInvokeStreamDeliversAudioChunks(t *testing.T) {
+	t.Parallel()
+
+	// 250 ms of 22.05 kHz 16-bit audio.
+	cfg, stdinPath := fakePiper(t, "head -c 11025 /dev/zero")
+	cfg.SampleText = "Hello from piper"
+	adapter, err := NewAdapter(cfg)
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	var chunks []int
+	outcome, err := adapter.(contracts.StreamingAdapter).InvokeStream(testRequest(), func(chunk contracts.StreamChunk) error {
+		chunks = append(chunks, len(chunk.Audio))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeSuccess || outcome.LengthCapped || outcome.FirstChunkLatencyMS < 1 {
+		t.Fatalf("unexpected outcome: %+v", outcome)
+	}
+	if len(chunks) != 3 || chunks[0] != 4410 || chunks[2] != 2205 {
+		t.Fatalf("expected 100 ms chunks with a short tail, got %v", chunks)
+	}
+	if text, _ := os.ReadFile(stdinPath); string(text) != "Hello from piper\n" {
+		t.Fatalf("expected the sample text on stdin, got %q", text)
+	}
+}
+
+func TestInvokeReturnsRequestTextAudio(t *testing.T) {
+	t.Parallel()
+
+	cfg, stdinPath := fakePiper(t, "head -c 11025 /dev/zero")
+	adapter, err := NewAdapter(cfg)
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	req := testRequest()
+	req.Text = "Your order shipped."
+	outcome, err := adapter.Invoke(req)
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeSuccess || outcome.Audio == nil {
+		t.Fatalf("expected synthesized audio, got %+v", outcome)
+	}
+	if outcome.Audio.SampleRateHz != defaultSampleRateHz || len(outcome.Audio.PCM) != 11025/2 {
+		t.Fatalf("expected %d samples at %d Hz, got %d at %d Hz", 11025/2, defaultSampleRateHz, len(outcome.Audio.PCM), outcome.Audio.SampleRateHz)
+	}
+	if text, _ := os.ReadFile(stdinPath); string(text) != "Your order shipped.\n" {
+		t.Fatalf("expected the request text on stdin, got %q", text)
+	}
+}
+
+func TestInvokeStreamStopsAtAudioCap(t *testing.T) {
+	t.Parallel()
+
+	cfg, _ := fakePiper(t, "head -c 10000000 /dev/zero")
+	adapter, err := NewAdapter(cfg)
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	req := testRequest()
+	req.MaxAudioOutputMS = 150
+	received := 0
+	outcome, err := adapter.(contracts.StreamingAdapter).InvokeStream(req, func(chunk contracts.StreamChunk) error {
+		received += len(chunk.Audio)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeSuccess || !outcome.LengthCapped || received != 150*44 {
+		t.Fatalf("expected a capped success after 150 ms of audio, got %+v with %d bytes", outcome, received)
+	}
+}
+
+func TestSynthesizeResamplesToRequestedRate(t *testing.T) {
+	t.Parallel()
+
+	// 100 ms at the voice rate.
+	cfg, stdinPath := fakePiper(t, "head -c 4410 /dev/zero")
+	adapter, err := NewAdapter(cfg)
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	pcm, err := adapter.(*Adapter).Synthesize("It is sunny.", 16000)
+	if err != nil {
+		t.Fatalf("unexpected synthesize error: %v", err)
+	}
+	if len(pcm) != 1600 {
+		t.Fatalf("expected 100 ms at 16 kHz, got %d samples", len(pcm))
+	}
+	if text, _ := os.ReadFile(stdinPath); string(text) != "It is sunny.\n" {
+		t.Fatalf("expected the reply text on stdin, got %q", text)
+	}
+}
+
+func TestInvokeClassifiesOutcomes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		body       string
+		mutate     func(*Config)
+		deadlineMS int64
+		wantClass  contracts.OutcomeClass
+		wantReason string
+	}{
+		{name: "empty audio", body: "exit 0", wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_empty_audio"},
+		{name: "process failure", body: "echo 'voice config missing' >&2; exit 1", wantClass: contracts.OutcomeInfrastructureFailure, wantReason: "provider_process_failed"},
+		{name: "deadline", body: "sleep 5", deadlineMS: 50, wantClass: contracts.OutcomeTimeout, wantReason: "provider_timeout"},
+		{name: "binary missing", body: "exit 0", mutate: func(cfg *Config) { cfg.BinaryPath = filepath.Join(filepath.Dir(cfg.BinaryPath), "absent") }, wantClass: contracts.OutcomeBlocked, wantReason: "provider_binary_missing"},
+		{name: "model missing", body: "exit 0", mutate: func(cfg *Config) { cfg.ModelPath = "" }, wantClass: contracts.OutcomeBlocked, wantReason: "provider_model_missing"},
+	}
+	for _, tc := range tests {
+		cfg, _ := fakePiper(t, tc.body)
+		if tc.mutate != nil {
+			tc.mutate(&cfg)
+		}
+		adapter, err := NewAdapter(cfg)
+		if err != nil {
+			t.Fatalf("%s: new adapter: %v", tc.name, err)
+		}
+		req := testRequest()
+		req.DeadlineMS = tc.deadlineMS
+		outcome, err := adapter.Invoke(req)
+		if err != nil {
+			t.Fatalf("%s: unexpected invoke error: %v", tc.name, err)
+		}
+		if outcome.Class != tc.wantClass || outcome.Reason != tc.wantReason {
+			t.Fatalf("%s: expected class=%s reason=%q, got %+v", tc.name, tc.wantClass, tc.wantReason, outcome)
+		}
+		if err := outcome.Validate(); err != nil {
+			t.Fatalf("%s: expected a valid outcome, got %v", tc.name, err)
+		}
+	}
+}
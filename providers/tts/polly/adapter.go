@@ -16,9 +16,15 @@ import (
 	pollytypes "github.com/aws/aws-sdk-go-v2/service/polly/types"
 	"github.com/aws/smithy-go"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/ttsquality"
 )
 
-const ProviderID = "tts-amazon-polly"
+const (
+	ProviderID = "tts-amazon-polly"
+
+	qualitySampleRate   = "16000"
+	qualitySampleRateHz = 16000
+)
 
 type synthClient interface {
 	SynthesizeSpeech(ctx context.Context, params *polly.SynthesizeSpeechInput, optFns ...func(*polly.Options)) (*polly.SynthesizeSpeechOutput, error)
@@ -30,6 +36,9 @@ type Config struct {
 	Engine     string
 	SampleText string
 	Timeout    time.Duration
+
+	QualityChecks bool
+	Thresholds    ttsquality.Thresholds
 }
 
 type Adapter struct {
@@ -45,6 +54,9 @@ func ConfigFromEnv() Config {
 		Engine:     defaultString(os.Getenv("RSPP_TTS_POLLY_ENGINE"), "neural"),
 		SampleText: defaultString(os.Getenv("RSPP_TTS_POLLY_TEXT"), "Realtime speech pipeline live smoke test."),
 		Timeout:    15 * time.Second,
+
+		QualityChecks: ttsquality.EnabledFromEnv(),
+		Thresholds:    ttsquality.DefaultThresholds(),
 	}
 }
 
@@ -100,16 +112,26 @@ func (a *Adapter) Invoke(req contracts.InvocationRequest) (contracts.Outcome, er
 		engine = pollytypes.EngineNeural
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.Timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), req.AttemptTimeout(a.cfg.Timeout))
 	defer cancel()
 
-	output, err := client.SynthesizeSpeech(ctx, &polly.SynthesizeSpeechInput{
+	// Request text is returned as Outcome.Audio, and quality checks measure
+	// duration/silence/clipping, so both take raw PCM; the configured sample is MP3.
+	text := req.TTSText(a.cfg.SampleText)
+	rawPCM := a.cfg.QualityChecks || req.Text != ""
+	input := &polly.SynthesizeSpeechInput{
 		Engine:       engine,
 		OutputFormat: pollytypes.OutputFormatMp3,
-		Text:         &a.cfg.SampleText,
+		Text:         &text,
 		TextType:     pollytypes.TextTypeText,
 		VoiceId:      pollytypes.VoiceId(a.cfg.VoiceID),
-	})
+	}
+	if rawPCM {
+		sampleRate := qualitySampleRate
+		input.OutputFormat = pollytypes.OutputFormatPcm
+		input.SampleRate = &sampleRate
+	}
+	output, err := client.SynthesizeSpeech(ctx, input)
 	if err != nil {
 		return normalizePollyError(err), nil
 	}
@@ -117,8 +139,24 @@ func (a *Adapter) Invoke(req contracts.InvocationRequest) (contracts.Outcome, er
 		return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: true, Reason: "provider_empty_audio"}, nil
 	}
 	defer output.AudioStream.Close()
-	_, _ = io.Copy(io.Discard, output.AudioStream)
-	return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+	if !rawPCM {
+		_, _ = io.Copy(io.Discard, output.AudioStream)
+		return contracts.Outcome{Class: contracts.OutcomeSuccess}, nil
+	}
+	audio, err := io.ReadAll(output.AudioStream)
+	if err != nil {
+		return contracts.Outcome{Class: contracts.OutcomeInfrastructureFailure, Retryable: true, Reason: "provider_transport_error"}, nil
+	}
+	if a.cfg.QualityChecks {
+		if err := ttsquality.CheckPCM16(audio, qualitySampleRateHz, text, a.cfg.Thresholds); err != nil {
+			return ttsquality.FailureOutcome(), nil
+		}
+	}
+	outcome := contracts.Outcome{Class: contracts.OutcomeSuccess}
+	if req.Text != "" {
+		outcome.Audio = &contracts.AudioOutput{PCM: ttsquality.DecodePCM16LE(audio), SampleRateHz: qualitySampleRateHz}
+	}
+	return outcome, nil
 }
 
 func normalizePollyError(err error) contracts.Outcome {
@@ -151,6 +189,23 @@ func defaultString(v string, fallback string) string {
 	return v
 }
 
+// Warm resolves AWS configuration and the Polly client ahead of the first synthesis so
+// credential and region resolution stay off the first-turn path.
+func (a *Adapter) Warm() contracts.WarmupResult {
+	started := time.Now()
+	_, err := a.resolveClient()
+	result := contracts.WarmupResult{
+		ProviderID: ProviderID,
+		Modality:   contracts.ModalityTTS,
+		Warmed:     err == nil,
+		LatencyMS:  time.Since(started).Milliseconds(),
+	}
+	if err != nil {
+		result.Reason = "provider_client_init_failed"
+	}
+	return result
+}
+
 func (a *Adapter) resolveClient() (synthClient, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -1,14 +1,17 @@
 package polly
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
 	"testing"
 
 	pollysdk "github.com/aws/aws-sdk-go-v2/service/polly"
 	"github.com/aws/aws-sdk-go-v2/service/polly/types"
 	"github.com/aws/smithy-go"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/contracts"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/ttsquality"
 )
 
 type fakePollyClient struct {
@@ -73,6 +76,54 @@ func TestInvokeSuccess(t *testing.T) {
 	}
 }
 
+// capturingPollyClient records the synthesis input it receives.
+type capturingPollyClient struct {
+	input *pollysdk.SynthesizeSpeechInput
+	audio []byte
+}
+
+func (c *capturingPollyClient) SynthesizeSpeech(ctx context.Context, params *pollysdk.SynthesizeSpeechInput, optFns ...func(*pollysdk.Options)) (*pollysdk.SynthesizeSpeechOutput, error) {
+	c.input = params
+	return &pollysdk.SynthesizeSpeechOutput{AudioStream: io.NopCloser(bytes.NewReader(c.audio))}, nil
+}
+
+func TestInvokeReturnsRequestTextAudio(t *testing.T) {
+	t.Parallel()
+
+	client := &capturingPollyClient{audio: []byte{0x01, 0x00, 0xff, 0x7f}}
+	adapter, err := NewAdapterWithClient(Config{}, client)
+	if err != nil {
+		t.Fatalf("unexpected adapter error: %v", err)
+	}
+	outcome, err := adapter.Invoke(contracts.InvocationRequest{
+		SessionID:            "sess-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-1",
+		ProviderInvocationID: "pvi-1",
+		ProviderID:           ProviderID,
+		Modality:             contracts.ModalityTTS,
+		Attempt:              1,
+		TransportSequence:    1,
+		RuntimeSequence:      1,
+		AuthorityEpoch:       1,
+		RuntimeTimestampMS:   1,
+		WallClockTimestampMS: 1,
+		Text:                 "Your order shipped.",
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeSuccess {
+		t.Fatalf("expected success, got %s", outcome.Class)
+	}
+	if client.input == nil || *client.input.Text != "Your order shipped." || client.input.OutputFormat != types.OutputFormatPcm {
+		t.Fatalf("expected pcm synthesis of the request text, got %+v", client.input)
+	}
+	if outcome.Audio == nil || outcome.Audio.SampleRateHz != qualitySampleRateHz || len(outcome.Audio.PCM) != 2 || outcome.Audio.PCM[1] != 32767 {
+		t.Fatalf("expected decoded pcm audio, got %+v", outcome.Audio)
+	}
+}
+
 func TestInvokeErrorMapping(t *testing.T) {
 	t.Parallel()
 
@@ -155,3 +206,35 @@ func TestInvokeCancelled(t *testing.T) {
 
 var _ smithy.APIError = fakeAPIError{}
 var _ = types.OutputFormatMp3
+
+func TestInvokeQualityChecksRejectBrokenAudio(t *testing.T) {
+	t.Parallel()
+
+	adapter, err := NewAdapterWithClient(Config{QualityChecks: true}, fakePollyClient{
+		out: &pollysdk.SynthesizeSpeechOutput{AudioStream: io.NopCloser(bytes.NewReader(make([]byte, 32000)))},
+	})
+	if err != nil {
+		t.Fatalf("unexpected adapter error: %v", err)
+	}
+
+	outcome, err := adapter.Invoke(contracts.InvocationRequest{
+		SessionID:            "sess-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-1",
+		ProviderInvocationID: "pvi-1",
+		ProviderID:           ProviderID,
+		Modality:             contracts.ModalityTTS,
+		Attempt:              1,
+		TransportSequence:    1,
+		RuntimeSequence:      1,
+		AuthorityEpoch:       1,
+		RuntimeTimestampMS:   1,
+		WallClockTimestampMS: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if outcome.Class != contracts.OutcomeQualityFailure || outcome.Retryable || outcome.Reason != ttsquality.OutcomeReason {
+		t.Fatalf("expected non-retryable quality failure for silent audio, got %+v", outcome)
+	}
+}
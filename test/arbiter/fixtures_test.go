@@ -0,0 +1,19 @@
+package arbiter_test
+
+import (
+	"testing"
+
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/turnarbiter"
+)
+
+func TestArbiterFixtures(t *testing.T) {
+	t.Parallel()
+
+	summary, err := turnarbiter.ValidateFixtures("fixtures", nil)
+	if err != nil {
+		t.Fatalf("run arbiter fixtures: %v", err)
+	}
+	if summary.Total == 0 || summary.Failed > 0 {
+		t.Fatalf("expected all arbiter fixtures to pass\n%s", turnarbiter.RenderFixtureSummary(summary))
+	}
+}
@@ -29,6 +29,8 @@ import (
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/provider/invocation"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/transport"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/turnarbiter"
+	"github.com/tiger/realtime-speech-pipeline/internal/tooling/livecombo"
+	"github.com/tiger/realtime-speech-pipeline/providers/common/ttsquality"
 )
 
 const (
@@ -46,10 +48,42 @@ type a2ScenarioSpec struct {
 }
 
 type a2ScenarioOutcome struct {
-	Status    string
-	Detail    string
-	Err       error
-	Providers []a2ProviderOutcome
+	Status            string
+	Detail            string
+	Err               error
+	Providers         []a2ProviderOutcome
+	ComboSelection    *livecombo.Selection
+	Combos            []a2ComboOutcome
+	LatencyComparison *a2LatencyComparison
+}
+
+const (
+	a2ChainCascaded = "cascaded"
+	a2ChainS2S      = "s2s"
+)
+
+type a2ComboOutcome struct {
+	Combo string `json:"combo"`
+	// Chain is a2ChainCascaded for STT->LLM->TTS and a2ChainS2S for a single S2S hop.
+	Chain     string `json:"chain"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+	LatencyMS int64  `json:"latency_ms,omitempty"`
+}
+
+type a2ChainLatency struct {
+	Samples int   `json:"samples"`
+	P50MS   int64 `json:"p50_ms,omitempty"`
+	P95MS   int64 `json:"p95_ms,omitempty"`
+	MaxMS   int64 `json:"max_ms,omitempty"`
+}
+
+// a2LatencyComparison compares end-to-end latency of cascaded and S2S chains in one run.
+type a2LatencyComparison struct {
+	Cascaded a2ChainLatency `json:"cascaded"`
+	S2S      a2ChainLatency `json:"s2s"`
+	// P50DeltaMS is S2S p50 minus cascaded p50; negative means S2S answered faster.
+	P50DeltaMS *int64 `json:"p50_delta_ms,omitempty"`
 }
 
 type a2ProviderOutcome struct {
@@ -72,6 +106,11 @@ type a2ScenarioReport struct {
 	Error       string              `json:"error,omitempty"`
 	Evidence    []string            `json:"evidence"`
 	Providers   []a2ProviderOutcome `json:"providers,omitempty"`
+	// ComboSelection records the strategy and seed behind chain coverage claims.
+	ComboSelection *livecombo.Selection `json:"combo_selection,omitempty"`
+	Combos         []a2ComboOutcome     `json:"combos,omitempty"`
+	// LatencyComparison sets cascaded and S2S chain latency side by side.
+	LatencyComparison *a2LatencyComparison `json:"latency_comparison,omitempty"`
 }
 
 type a2ModuleReport struct {
@@ -116,14 +155,17 @@ func TestA2RuntimeLiveScenarios(t *testing.T) {
 	for _, spec := range specs {
 		outcome := spec.Run(strict)
 		scenario := a2ScenarioReport{
-			ID:          spec.ID,
-			Name:        spec.Name,
-			Description: spec.Description,
-			Modules:     append([]string(nil), spec.Modules...),
-			Status:      outcome.Status,
-			Detail:      outcome.Detail,
-			Evidence:    append([]string(nil), spec.Evidence...),
-			Providers:   append([]a2ProviderOutcome(nil), outcome.Providers...),
+			ID:                spec.ID,
+			Name:              spec.Name,
+			Description:       spec.Description,
+			Modules:           append([]string(nil), spec.Modules...),
+			Status:            outcome.Status,
+			Detail:            outcome.Detail,
+			Evidence:          append([]string(nil), spec.Evidence...),
+			Providers:         append([]a2ProviderOutcome(nil), outcome.Providers...),
+			ComboSelection:    outcome.ComboSelection,
+			Combos:            append([]a2ComboOutcome(nil), outcome.Combos...),
+			LatencyComparison: outcome.LatencyComparison,
 		}
 		if outcome.Err != nil {
 			scenario.Error = outcome.Err.Error()
@@ -269,6 +311,18 @@ func a2RuntimeScenarioSpecs() []a2ScenarioSpec {
 			Description: "In-turn authority revoke path emits deterministic deauthorized drain and terminal close sequence.",
 			Run:         runS6AuthorityRevoke,
 		},
+		{
+			ID:      "S7",
+			Name:    "provider-chain-combos",
+			Modules: []string{"RK-10", "RK-11"},
+			Evidence: []string{
+				"internal/tooling/livecombo/livecombo.go",
+				"internal/runtime/provider/invocation/controller.go",
+				"test/integration/a2_runtime_live_test.go",
+			},
+			Description: "STT->LLM->TTS provider chains selected by a recorded full-matrix, pairwise, or traffic-weighted strategy, plus single-hop S2S chains, with a cascaded-vs-S2S end-to-end latency comparison.",
+			Run:         runS7ProviderChainCombos,
+		},
 	}
 }
 
@@ -582,22 +636,7 @@ func runS5ProviderInvocationLive(strict bool) a2ScenarioOutcome {
 		return failf("provider bootstrap failed: %v", err)
 	}
 
-	cases := []struct {
-		ProviderID string
-		Modality   contracts.Modality
-		EnableEnv  string
-		Required   []string
-	}{
-		{ProviderID: "stt-deepgram", Modality: contracts.ModalitySTT, EnableEnv: "RSPP_STT_DEEPGRAM_ENABLE", Required: []string{"RSPP_STT_DEEPGRAM_API_KEY"}},
-		{ProviderID: "stt-google", Modality: contracts.ModalitySTT, EnableEnv: "RSPP_STT_GOOGLE_ENABLE", Required: []string{"RSPP_STT_GOOGLE_API_KEY"}},
-		{ProviderID: "stt-assemblyai", Modality: contracts.ModalitySTT, EnableEnv: "RSPP_STT_ASSEMBLYAI_ENABLE", Required: []string{"RSPP_STT_ASSEMBLYAI_API_KEY"}},
-		{ProviderID: "llm-anthropic", Modality: contracts.ModalityLLM, EnableEnv: "RSPP_LLM_ANTHROPIC_ENABLE", Required: []string{"RSPP_LLM_ANTHROPIC_API_KEY"}},
-		{ProviderID: "llm-gemini", Modality: contracts.ModalityLLM, EnableEnv: "RSPP_LLM_GEMINI_ENABLE", Required: []string{"RSPP_LLM_GEMINI_API_KEY"}},
-		{ProviderID: "llm-cohere", Modality: contracts.ModalityLLM, EnableEnv: "RSPP_LLM_COHERE_ENABLE", Required: []string{"RSPP_LLM_COHERE_API_KEY"}},
-		{ProviderID: "tts-elevenlabs", Modality: contracts.ModalityTTS, EnableEnv: "RSPP_TTS_ELEVENLABS_ENABLE", Required: []string{"RSPP_TTS_ELEVENLABS_API_KEY"}},
-		{ProviderID: "tts-google", Modality: contracts.ModalityTTS, EnableEnv: "RSPP_TTS_GOOGLE_ENABLE", Required: []string{"RSPP_TTS_GOOGLE_API_KEY"}},
-		{ProviderID: "tts-amazon-polly", Modality: contracts.ModalityTTS, EnableEnv: "RSPP_TTS_POLLY_ENABLE", Required: []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY"}},
-	}
+	cases := a2LiveProviderCases()
 
 	outcomes := make([]a2ProviderOutcome, 0, len(cases))
 	executed := 0
@@ -737,11 +776,230 @@ func runS5ProviderInvocationLive(strict bool) a2ScenarioOutcome {
 
 	return a2ScenarioOutcome{
 		Status:    "pass",
-		Detail:    fmt.Sprintf("executed_providers=%d first_success=%s scheduler_attempt_entries=%d", executed, firstSuccess.id, len(recorder.ProviderAttemptEntries())),
+		Detail:    fmt.Sprintf("executed_providers=%d first_success=%s scheduler_attempt_entries=%d tts_quality_checks=%t", executed, firstSuccess.id, len(recorder.ProviderAttemptEntries()), ttsquality.EnabledFromEnv()),
 		Providers: outcomes,
 	}
 }
 
+type a2LiveProviderCase struct {
+	ProviderID string
+	Modality   contracts.Modality
+	EnableEnv  string
+	Required   []string
+}
+
+func a2LiveProviderCases() []a2LiveProviderCase {
+	return []a2LiveProviderCase{
+		{ProviderID: "stt-deepgram", Modality: contracts.ModalitySTT, EnableEnv: "RSPP_STT_DEEPGRAM_ENABLE", Required: []string{"RSPP_STT_DEEPGRAM_API_KEY"}},
+		{ProviderID: "stt-google", Modality: contracts.ModalitySTT, EnableEnv: "RSPP_STT_GOOGLE_ENABLE", Required: []string{"RSPP_STT_GOOGLE_API_KEY"}},
+		{ProviderID: "stt-assemblyai", Modality: contracts.ModalitySTT, EnableEnv: "RSPP_STT_ASSEMBLYAI_ENABLE", Required: []string{"RSPP_STT_ASSEMBLYAI_API_KEY"}},
+		{ProviderID: "stt-azure-speech", Modality: contracts.ModalitySTT, EnableEnv: "RSPP_STT_AZURE_ENABLE", Required: []string{"RSPP_STT_AZURE_API_KEY"}},
+		{ProviderID: "llm-anthropic", Modality: contracts.ModalityLLM, EnableEnv: "RSPP_LLM_ANTHROPIC_ENABLE", Required: []string{"RSPP_LLM_ANTHROPIC_API_KEY"}},
+		{ProviderID: "llm-gemini", Modality: contracts.ModalityLLM, EnableEnv: "RSPP_LLM_GEMINI_ENABLE", Required: []string{"RSPP_LLM_GEMINI_API_KEY"}},
+		{ProviderID: "llm-cohere", Modality: contracts.ModalityLLM, EnableEnv: "RSPP_LLM_COHERE_ENABLE", Required: []string{"RSPP_LLM_COHERE_API_KEY"}},
+		{ProviderID: "tts-elevenlabs", Modality: contracts.ModalityTTS, EnableEnv: "RSPP_TTS_ELEVENLABS_ENABLE", Required: []string{"RSPP_TTS_ELEVENLABS_API_KEY"}},
+		{ProviderID: "tts-google", Modality: contracts.ModalityTTS, EnableEnv: "RSPP_TTS_GOOGLE_ENABLE", Required: []string{"RSPP_TTS_GOOGLE_API_KEY"}},
+		{ProviderID: "tts-amazon-polly", Modality: contracts.ModalityTTS, EnableEnv: "RSPP_TTS_POLLY_ENABLE", Required: []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY"}},
+		{ProviderID: "tts-azure-speech", Modality: contracts.ModalityTTS, EnableEnv: "RSPP_TTS_AZURE_ENABLE", Required: []string{"RSPP_TTS_AZURE_API_KEY"}},
+		{ProviderID: "s2s-openai-realtime", Modality: contracts.ModalityS2S, EnableEnv: "RSPP_S2S_OPENAI_ENABLE", Required: []string{"RSPP_S2S_OPENAI_API_KEY"}},
+	}
+}
+
+// runS7ProviderChainCombos runs cascaded STT->LLM->TTS chains over enabled providers, chosen by
+// the configured livecombo strategy so the report can state exactly which coverage was claimed,
+// then one single-hop chain per enabled S2S provider, and compares end-to-end chain latency.
+func runS7ProviderChainCombos(strict bool) a2ScenarioOutcome {
+	cfg, err := livecombo.ConfigFromEnv()
+	if err != nil {
+		return failf("live combo config invalid: %v", err)
+	}
+	enabled := map[contracts.Modality][]string{}
+	for _, tc := range a2LiveProviderCases() {
+		if envBool(tc.EnableEnv, false) && len(missingEnvs(tc.Required)) == 0 {
+			enabled[tc.Modality] = append(enabled[tc.Modality], tc.ProviderID)
+		}
+	}
+	cascaded := []contracts.Modality{contracts.ModalitySTT, contracts.ModalityLLM, contracts.ModalityTTS}
+	var selection *livecombo.Selection
+	dims := make([]livecombo.Dimension, 0, len(cascaded))
+	for _, modality := range cascaded {
+		if len(enabled[modality]) == 0 {
+			dims = nil
+			break
+		}
+		dims = append(dims, livecombo.Dimension{Name: string(modality), Providers: enabled[modality]})
+	}
+	if dims != nil {
+		selected, err := livecombo.Select(dims, cfg)
+		if err != nil {
+			return failf("live combo selection failed: %v", err)
+		}
+		selection = &selected
+	}
+	if selection == nil && len(enabled[contracts.ModalityS2S]) == 0 {
+		return a2ScenarioOutcome{Status: "skip", Detail: "no enabled STT/LLM/TTS provider set or S2S provider for chain combos"}
+	}
+
+	runtimeProviders, err := bootstrap.BuildMVPProviders()
+	if err != nil {
+		return a2ScenarioOutcome{Status: "fail", Detail: "provider bootstrap failed", Err: err, ComboSelection: selection}
+	}
+	outcomes := make([]a2ComboOutcome, 0)
+	sequence := int64(70)
+	runChain := func(index int, chainName string, key string, stages []contracts.Modality, providers map[string]string) a2ComboOutcome {
+		outcome := a2ComboOutcome{Combo: key, Chain: chainName, Status: "pass"}
+		started := time.Now()
+		for _, modality := range stages {
+			providerID := providers[string(modality)]
+			sequence++
+			now := time.Now().UnixMilli()
+			result, invokeErr := runtimeProviders.Controller.Invoke(invocation.InvocationInput{
+				SessionID:              "sess-a2-live-s7",
+				TurnID:                 fmt.Sprintf("turn-a2-live-s7-%s-%d", chainName, index),
+				PipelineVersion:        "pipeline-v1",
+				EventID:                fmt.Sprintf("evt-a2-live-s7-%s-%d-%s", chainName, index, providerID),
+				Modality:               modality,
+				PreferredProvider:      providerID,
+				AllowedAdaptiveActions: []string{"retry"},
+				TransportSequence:      sequence,
+				RuntimeSequence:        sequence,
+				AuthorityEpoch:         11,
+				RuntimeTimestampMS:     now,
+				WallClockTimestampMS:   now,
+			})
+			switch {
+			case invokeErr != nil:
+				outcome.Status, outcome.Reason = "fail", fmt.Sprintf("%s: %v", providerID, invokeErr)
+			case result.Outcome.Class != contracts.OutcomeSuccess:
+				outcome.Status, outcome.Reason = "fail", fmt.Sprintf("%s: class=%s reason=%s", providerID, result.Outcome.Class, result.Outcome.Reason)
+			}
+			if outcome.Status == "fail" {
+				return outcome
+			}
+		}
+		outcome.LatencyMS = time.Since(started).Milliseconds()
+		return outcome
+	}
+	failed := func(outcome a2ComboOutcome) a2ScenarioOutcome {
+		return a2ScenarioOutcome{
+			Status:         "fail",
+			Detail:         "provider chain combo failed",
+			Err:            fmt.Errorf("%s combo %s failed: %s", outcome.Chain, outcome.Combo, outcome.Reason),
+			ComboSelection: selection,
+			Combos:         outcomes,
+		}
+	}
+
+	if selection != nil {
+		for i, combo := range selection.Combos {
+			outcome := runChain(i, a2ChainCascaded, selection.Key(combo), cascaded, combo)
+			outcomes = append(outcomes, outcome)
+			if outcome.Status == "fail" {
+				return failed(outcome)
+			}
+		}
+	}
+	for i, providerID := range enabled[contracts.ModalityS2S] {
+		outcome := runChain(i, a2ChainS2S, string(contracts.ModalityS2S)+"="+providerID, []contracts.Modality{contracts.ModalityS2S}, map[string]string{string(contracts.ModalityS2S): providerID})
+		outcomes = append(outcomes, outcome)
+		if outcome.Status == "fail" {
+			return failed(outcome)
+		}
+	}
+
+	comparison := compareChainLatency(outcomes)
+	detail := fmt.Sprintf("s2s_chains=%d", comparison.S2S.Samples)
+	if selection != nil {
+		detail = fmt.Sprintf("strategy=%s seed=%d combos=%d/%d pair_coverage=%.2f %s", selection.Strategy, selection.Seed, len(selection.Combos), selection.MatrixSize, selection.PairCoverage, detail)
+	}
+	if comparison.P50DeltaMS != nil {
+		detail += fmt.Sprintf(" s2s_minus_cascaded_p50_ms=%d", *comparison.P50DeltaMS)
+	}
+	return a2ScenarioOutcome{
+		Status:            "pass",
+		Detail:            detail,
+		ComboSelection:    selection,
+		Combos:            outcomes,
+		LatencyComparison: &comparison,
+	}
+}
+
+// compareChainLatency summarizes passing chain latencies per chain kind; the p50 delta is set
+// only when both cascaded and S2S chains were measured.
+func compareChainLatency(outcomes []a2ComboOutcome) a2LatencyComparison {
+	latencies := map[string][]int64{}
+	for _, outcome := range outcomes {
+		if outcome.Status == "pass" {
+			latencies[outcome.Chain] = append(latencies[outcome.Chain], outcome.LatencyMS)
+		}
+	}
+	comparison := a2LatencyComparison{
+		Cascaded: summarizeChainLatency(latencies[a2ChainCascaded]),
+		S2S:      summarizeChainLatency(latencies[a2ChainS2S]),
+	}
+	if comparison.Cascaded.Samples > 0 && comparison.S2S.Samples > 0 {
+		delta := comparison.S2S.P50MS - comparison.Cascaded.P50MS
+		comparison.P50DeltaMS = &delta
+	}
+	return comparison
+}
+
+func summarizeChainLatency(latencies []int64) a2ChainLatency {
+	if len(latencies) == 0 {
+		return a2ChainLatency{}
+	}
+	sorted := append([]int64(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	nearestRank := func(p float64) int64 {
+		rank := int(p*float64(len(sorted)) + 0.999999)
+		return sorted[max(rank, 1)-1]
+	}
+	return a2ChainLatency{Samples: len(sorted), P50MS: nearestRank(0.5), P95MS: nearestRank(0.95), MaxMS: sorted[len(sorted)-1]}
+}
+
+func TestCompareChainLatency(t *testing.T) {
+	t.Parallel()
+
+	delta := func(v int64) *int64 { return &v }
+	cases := []struct {
+		name     string
+		outcomes []a2ComboOutcome
+		cascaded a2ChainLatency
+		s2s      a2ChainLatency
+		delta    *int64
+	}{
+		{
+			name: "both chains measured",
+			outcomes: []a2ComboOutcome{
+				{Chain: a2ChainCascaded, Status: "pass", LatencyMS: 900},
+				{Chain: a2ChainCascaded, Status: "pass", LatencyMS: 1500},
+				{Chain: a2ChainCascaded, Status: "pass", LatencyMS: 1100},
+				{Chain: a2ChainS2S, Status: "pass", LatencyMS: 700},
+			},
+			cascaded: a2ChainLatency{Samples: 3, P50MS: 1100, P95MS: 1500, MaxMS: 1500},
+			s2s:      a2ChainLatency{Samples: 1, P50MS: 700, P95MS: 700, MaxMS: 700},
+			delta:    delta(-400),
+		},
+		{
+			name: "failed chains excluded and no delta without s2s",
+			outcomes: []a2ComboOutcome{
+				{Chain: a2ChainCascaded, Status: "pass", LatencyMS: 1200},
+				{Chain: a2ChainS2S, Status: "fail"},
+			},
+			cascaded: a2ChainLatency{Samples: 1, P50MS: 1200, P95MS: 1200, MaxMS: 1200},
+		},
+	}
+	for _, tc := range cases {
+		got := compareChainLatency(tc.outcomes)
+		if got.Cascaded != tc.cascaded || got.S2S != tc.s2s {
+			t.Fatalf("%s: expected cascaded=%+v s2s=%+v, got %+v", tc.name, tc.cascaded, tc.s2s, got)
+		}
+		if (got.P50DeltaMS == nil) != (tc.delta == nil) || (tc.delta != nil && *got.P50DeltaMS != *tc.delta) {
+			t.Fatalf("%s: expected p50 delta %v, got %v", tc.name, tc.delta, got.P50DeltaMS)
+		}
+	}
+}
+
 func runS6AuthorityRevoke(strict bool) a2ScenarioOutcome {
 	now := time.Now().UnixMilli()
 	arbiter := turnarbiter.New()
@@ -937,6 +1195,35 @@ func renderA2RuntimeLiveMarkdown(report a2RuntimeLiveReport) string {
 		fmt.Fprintf(&b, "| _none_ | _n/a_ | _n/a_ | _n/a_ | _n/a_ | `0` | no provider scenarios emitted rows |\n")
 	}
 
+	for _, scenario := range report.Scenarios {
+		if len(scenario.Combos) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## Provider Chain Combos (%s)\n\n", scenario.ID)
+		if selection := scenario.ComboSelection; selection != nil {
+			fmt.Fprintf(&b, "- Strategy: `%s` seed=`%d` max_combos=`%d`\n", selection.Strategy, selection.Seed, selection.MaxCombos)
+			fmt.Fprintf(&b, "- Selected: `%d` of `%d` combinations, pair coverage `%.2f`", len(selection.Combos), selection.MatrixSize, selection.PairCoverage)
+			if selection.TrafficCoverage != nil {
+				fmt.Fprintf(&b, ", traffic coverage `%.2f`", *selection.TrafficCoverage)
+			}
+			fmt.Fprintf(&b, "\n")
+		}
+		fmt.Fprintf(&b, "\n| Combo | Chain | Status | Latency (ms) | Reason |\n| --- | --- | --- | --- | --- |\n")
+		for _, combo := range scenario.Combos {
+			fmt.Fprintf(&b, "| `%s` | `%s` | `%s` | `%d` | %s |\n", combo.Combo, combo.Chain, combo.Status, combo.LatencyMS, escapeMDCell(combo.Reason))
+		}
+		if comparison := scenario.LatencyComparison; comparison != nil {
+			fmt.Fprintf(&b, "\n### Cascaded vs S2S Latency (%s)\n\n| Chain | Samples | p50 (ms) | p95 (ms) | max (ms) |\n| --- | --- | --- | --- | --- |\n", scenario.ID)
+			fmt.Fprintf(&b, "| `%s` | `%d` | `%d` | `%d` | `%d` |\n", a2ChainCascaded, comparison.Cascaded.Samples, comparison.Cascaded.P50MS, comparison.Cascaded.P95MS, comparison.Cascaded.MaxMS)
+			fmt.Fprintf(&b, "| `%s` | `%d` | `%d` | `%d` | `%d` |\n", a2ChainS2S, comparison.S2S.Samples, comparison.S2S.P50MS, comparison.S2S.P95MS, comparison.S2S.MaxMS)
+			if comparison.P50DeltaMS != nil {
+				fmt.Fprintf(&b, "\n- S2S p50 minus cascaded p50: `%d` ms\n", *comparison.P50DeltaMS)
+			} else {
+				fmt.Fprintf(&b, "\n- S2S p50 minus cascaded p50: _n/a_ (both chains must be measured)\n")
+			}
+		}
+	}
+
 	return b.String()
 }
 
@@ -6,6 +6,7 @@ import (
 	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
 	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
 	"github.com/tiger/realtime-speech-pipeline/internal/observability/timeline"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/cancellation"
 	runtimetransport "github.com/tiger/realtime-speech-pipeline/internal/runtime/transport"
 	"github.com/tiger/realtime-speech-pipeline/internal/runtime/turnarbiter"
 )
@@ -141,3 +142,104 @@ func TestCF004CancelObservabilityMarkersInOR02(t *testing.T) {
 		t.Fatalf("expected cancel markers in OR-02 evidence, got %+v", entries[0])
 	}
 }
+
+func TestCF005ProviderLateOutputAfterBargeInIsDropped(t *testing.T) {
+	t.Parallel()
+
+	turnFence := cancellation.NewFence()
+	recorder := timeline.NewRecorder(timeline.StageAConfig{BaselineCapacity: 4, DetailCapacity: 4})
+	arbiter := turnarbiter.NewWithCancelFence(&recorder, nil, turnFence)
+	outputFence := runtimetransport.NewOutputFenceWithTurnFence(turnFence)
+
+	streaming, err := outputFence.EvaluateOutput(runtimetransport.OutputAttempt{
+		SessionID:            "sess-cf-005",
+		TurnID:               "turn-cf-005-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-cf-005-streaming",
+		TransportSequence:    1,
+		RuntimeSequence:      1,
+		AuthorityEpoch:       8,
+		RuntimeTimestampMS:   200,
+		WallClockTimestampMS: 200,
+	})
+	if err != nil || !streaming.Accepted {
+		t.Fatalf("expected streaming output accepted before barge-in, got %+v (%v)", streaming, err)
+	}
+
+	bargeIn, err := arbiter.HandleBargeIn(turnarbiter.BargeInInput{
+		Interrupted: turnarbiter.ActiveInput{
+			SessionID:            "sess-cf-005",
+			TurnID:               "turn-cf-005-1",
+			EventID:              "evt-cf-005-barge-in",
+			PipelineVersion:      "pipeline-v1",
+			TransportSequence:    2,
+			RuntimeSequence:      2,
+			RuntimeTimestampMS:   212,
+			WallClockTimestampMS: 212,
+			AuthorityEpoch:       8,
+		},
+		Next: turnarbiter.OpenRequest{
+			SessionID:             "sess-cf-005",
+			TurnID:                "turn-cf-005-2",
+			EventID:               "evt-cf-005-open",
+			RuntimeTimestampMS:    213,
+			WallClockTimestampMS:  213,
+			PipelineVersion:       "pipeline-v1",
+			AuthorityEpoch:        8,
+			SnapshotValid:         true,
+			AuthorityEpochValid:   true,
+			AuthorityAuthorized:   true,
+			SnapshotFailurePolicy: controlplane.OutcomeDefer,
+			PlanFailurePolicy:     controlplane.OutcomeReject,
+		},
+		UtteranceStartAtMS: 205,
+		OutputStreaming:    true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected barge-in error: %v", err)
+	}
+	if bargeIn.Interrupted.State != controlplane.TurnClosed || bargeIn.Next.State != controlplane.TurnActive {
+		t.Fatalf("expected interrupted turn closed and next turn opened in one step, got %+v", bargeIn)
+	}
+
+	late, err := outputFence.EvaluateOutput(runtimetransport.OutputAttempt{
+		SessionID:            "sess-cf-005",
+		TurnID:               "turn-cf-005-1",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-cf-005-late-provider",
+		TransportSequence:    3,
+		RuntimeSequence:      3,
+		AuthorityEpoch:       8,
+		RuntimeTimestampMS:   230,
+		WallClockTimestampMS: 230,
+	})
+	if err != nil {
+		t.Fatalf("unexpected late output evaluation error: %v", err)
+	}
+	if late.Accepted || late.Signal.Signal != "playback_cancelled" || late.Signal.Reason != "cancel_fence_applied" {
+		t.Fatalf("expected late provider output after barge-in fenced, got %+v", late)
+	}
+	next, err := outputFence.EvaluateOutput(runtimetransport.OutputAttempt{
+		SessionID:            "sess-cf-005",
+		TurnID:               "turn-cf-005-2",
+		PipelineVersion:      "pipeline-v1",
+		EventID:              "evt-cf-005-next-output",
+		TransportSequence:    4,
+		RuntimeSequence:      4,
+		AuthorityEpoch:       8,
+		RuntimeTimestampMS:   231,
+		WallClockTimestampMS: 231,
+	})
+	if err != nil || !next.Accepted {
+		t.Fatalf("expected next turn output accepted, got %+v (%v)", next, err)
+	}
+
+	entries := recorder.BaselineEntries()
+	if len(entries) != 1 || entries[0].TerminalReason != "barge_in" {
+		t.Fatalf("expected one abort(barge_in) OR-02 entry, got %+v", entries)
+	}
+	entry := entries[0]
+	if entry.CancelSentAtMS == nil || *entry.CancelSentAtMS != 205 || entry.CancelAcceptedAtMS == nil || *entry.CancelAcceptedAtMS != 212 || entry.CancelFenceAppliedAtMS == nil || *entry.CancelFenceAppliedAtMS != 212 {
+		t.Fatalf("expected barge-in cancel markers in OR-02 evidence, got %+v", entry)
+	}
+}
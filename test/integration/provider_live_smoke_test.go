@@ -37,12 +37,15 @@ func TestLiveProviderSmoke(t *testing.T) {
 		{providerID: "stt-deepgram", modality: contracts.ModalitySTT, enableEnv: "RSPP_STT_DEEPGRAM_ENABLE", required: []string{"RSPP_STT_DEEPGRAM_API_KEY"}},
 		{providerID: "stt-google", modality: contracts.ModalitySTT, enableEnv: "RSPP_STT_GOOGLE_ENABLE", required: []string{"RSPP_STT_GOOGLE_API_KEY"}},
 		{providerID: "stt-assemblyai", modality: contracts.ModalitySTT, enableEnv: "RSPP_STT_ASSEMBLYAI_ENABLE", required: []string{"RSPP_STT_ASSEMBLYAI_API_KEY"}},
+		{providerID: "stt-azure-speech", modality: contracts.ModalitySTT, enableEnv: "RSPP_STT_AZURE_ENABLE", required: []string{"RSPP_STT_AZURE_API_KEY"}},
 		{providerID: "llm-anthropic", modality: contracts.ModalityLLM, enableEnv: "RSPP_LLM_ANTHROPIC_ENABLE", required: []string{"RSPP_LLM_ANTHROPIC_API_KEY"}},
 		{providerID: "llm-gemini", modality: contracts.ModalityLLM, enableEnv: "RSPP_LLM_GEMINI_ENABLE", required: []string{"RSPP_LLM_GEMINI_API_KEY"}},
 		{providerID: "llm-cohere", modality: contracts.ModalityLLM, enableEnv: "RSPP_LLM_COHERE_ENABLE", required: []string{"RSPP_LLM_COHERE_API_KEY"}},
 		{providerID: "tts-elevenlabs", modality: contracts.ModalityTTS, enableEnv: "RSPP_TTS_ELEVENLABS_ENABLE", required: []string{"RSPP_TTS_ELEVENLABS_API_KEY"}},
 		{providerID: "tts-google", modality: contracts.ModalityTTS, enableEnv: "RSPP_TTS_GOOGLE_ENABLE", required: []string{"RSPP_TTS_GOOGLE_API_KEY"}},
 		{providerID: "tts-amazon-polly", modality: contracts.ModalityTTS, enableEnv: "RSPP_TTS_POLLY_ENABLE", required: []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY"}},
+		{providerID: "tts-azure-speech", modality: contracts.ModalityTTS, enableEnv: "RSPP_TTS_AZURE_ENABLE", required: []string{"RSPP_TTS_AZURE_API_KEY"}},
+		{providerID: "s2s-openai-realtime", modality: contracts.ModalityS2S, enableEnv: "RSPP_S2S_OPENAI_ENABLE", required: []string{"RSPP_S2S_OPENAI_API_KEY"}},
 	}
 
 	for _, tc := range cases {
@@ -100,12 +103,14 @@ func TestLiveProviderSmokeSwitchAndFallbackRouting(t *testing.T) {
 		{providerID: "stt-deepgram", modality: contracts.ModalitySTT, enableEnv: "RSPP_STT_DEEPGRAM_ENABLE", required: []string{"RSPP_STT_DEEPGRAM_API_KEY"}},
 		{providerID: "stt-google", modality: contracts.ModalitySTT, enableEnv: "RSPP_STT_GOOGLE_ENABLE", required: []string{"RSPP_STT_GOOGLE_API_KEY"}},
 		{providerID: "stt-assemblyai", modality: contracts.ModalitySTT, enableEnv: "RSPP_STT_ASSEMBLYAI_ENABLE", required: []string{"RSPP_STT_ASSEMBLYAI_API_KEY"}},
+		{providerID: "stt-azure-speech", modality: contracts.ModalitySTT, enableEnv: "RSPP_STT_AZURE_ENABLE", required: []string{"RSPP_STT_AZURE_API_KEY"}},
 		{providerID: "llm-anthropic", modality: contracts.ModalityLLM, enableEnv: "RSPP_LLM_ANTHROPIC_ENABLE", required: []string{"RSPP_LLM_ANTHROPIC_API_KEY"}},
 		{providerID: "llm-gemini", modality: contracts.ModalityLLM, enableEnv: "RSPP_LLM_GEMINI_ENABLE", required: []string{"RSPP_LLM_GEMINI_API_KEY"}},
 		{providerID: "llm-cohere", modality: contracts.ModalityLLM, enableEnv: "RSPP_LLM_COHERE_ENABLE", required: []string{"RSPP_LLM_COHERE_API_KEY"}},
 		{providerID: "tts-elevenlabs", modality: contracts.ModalityTTS, enableEnv: "RSPP_TTS_ELEVENLABS_ENABLE", required: []string{"RSPP_TTS_ELEVENLABS_API_KEY"}},
 		{providerID: "tts-google", modality: contracts.ModalityTTS, enableEnv: "RSPP_TTS_GOOGLE_ENABLE", required: []string{"RSPP_TTS_GOOGLE_API_KEY"}},
 		{providerID: "tts-amazon-polly", modality: contracts.ModalityTTS, enableEnv: "RSPP_TTS_POLLY_ENABLE", required: []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY"}},
+		{providerID: "tts-azure-speech", modality: contracts.ModalityTTS, enableEnv: "RSPP_TTS_AZURE_ENABLE", required: []string{"RSPP_TTS_AZURE_API_KEY"}},
 	}
 
 	var chosen *liveProviderCase
@@ -1044,17 +1044,52 @@ func TestExecutePlanPersistsAttemptEvidenceAndTerminalBaseline(t *testing.T) {
 		t.Fatalf("unexpected provider attempt ordering: %+v", attempts)
 	}
 
+	turnOpenAt := int64(95)
+	evidence, err := executor.BaselineEvidenceFromTrace(trace, executor.TurnEvidenceState{
+		SessionID:       "sess-integration-provider-plan-1",
+		TurnID:          "turn-integration-provider-plan-1",
+		PipelineVersion: "pipeline-v1",
+		EventID:         "evt-active-provider-plan-1",
+		PlanHash:        "plan/turn-integration-provider-plan-1",
+		SnapshotProvenance: controlplane.SnapshotProvenance{
+			RoutingViewSnapshot:       "routing-view/v1",
+			AdmissionPolicySnapshot:   "admission-policy/v1",
+			ABICompatibilitySnapshot:  "abi-compat/v1",
+			VersionResolutionSnapshot: "version-resolution/v1",
+			PolicyResolutionSnapshot:  "policy-resolution/v1",
+			ProviderHealthSnapshot:    "provider-health/v1",
+		},
+		AuthorityEpoch:  3,
+		RuntimeSequence: 11,
+		Admission: controlplane.DecisionOutcome{
+			OutcomeKind:        controlplane.OutcomeAdmit,
+			Phase:              controlplane.PhasePreTurn,
+			Scope:              controlplane.ScopeTurn,
+			SessionID:          "sess-integration-provider-plan-1",
+			TurnID:             "turn-integration-provider-plan-1",
+			EventID:            "evt-active-provider-plan-1-admit",
+			RuntimeTimestampMS: 95,
+			WallClockMS:        95,
+			EmittedBy:          controlplane.EmitterRK25,
+			Reason:             "admission_capacity_allow",
+		},
+		TurnOpenAtMS: &turnOpenAt,
+	})
+	if err != nil {
+		t.Fatalf("unexpected trace evidence error: %v", err)
+	}
+
 	active, err := arbiter.HandleActive(turnarbiter.ActiveInput{
-		SessionID:                  "sess-integration-provider-plan-1",
-		TurnID:                     "turn-integration-provider-plan-1",
-		EventID:                    "evt-active-provider-plan-1",
-		PipelineVersion:            "pipeline-v1",
-		RuntimeTimestampMS:         120,
-		WallClockTimestampMS:       120,
-		AuthorityEpoch:             3,
-		RuntimeSequence:            2,
-		TerminalSuccessReady:       true,
-		ProviderInvocationOutcomes: []timeline.InvocationOutcomeEvidence{trace.Nodes[0].Decision.Provider.ToInvocationOutcomeEvidence()},
+		SessionID:            "sess-integration-provider-plan-1",
+		TurnID:               "turn-integration-provider-plan-1",
+		EventID:              "evt-active-provider-plan-1",
+		PipelineVersion:      "pipeline-v1",
+		RuntimeTimestampMS:   120,
+		WallClockTimestampMS: 120,
+		AuthorityEpoch:       3,
+		RuntimeSequence:      2,
+		TerminalSuccessReady: true,
+		BaselineEvidence:     &evidence,
 	})
 	if err != nil {
 		t.Fatalf("unexpected active handling error: %v", err)
@@ -1062,6 +1097,17 @@ func TestExecutePlanPersistsAttemptEvidenceAndTerminalBaseline(t *testing.T) {
 	if active.State != controlplane.TurnClosed {
 		t.Fatalf("expected terminal close state, got %s", active.State)
 	}
+
+	entries := recorder.BaselineEntries()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 baseline entry, got %d", len(entries))
+	}
+	if len(entries[0].InvocationOutcomes) != 1 || entries[0].InvocationOutcomes[0].ProviderID != "stt-b" {
+		t.Fatalf("expected trace-derived invocation outcome, got %+v", entries[0].InvocationOutcomes)
+	}
+	if entries[0].TerminalOutcome != "commit" || !reflect.DeepEqual(entries[0].OrderingMarkers, []string{"runtime_sequence:11", "node:0:provider-stt:dispatched"}) {
+		t.Fatalf("expected trace-derived terminal and node span markers, got %+v", entries[0])
+	}
 }
 
 func TestExecutePlanPromotesAttemptEvidenceIntoTerminalBaseline(t *testing.T) {
@@ -1,6 +1,7 @@
 package replay_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
@@ -115,6 +116,61 @@ func TestRD004SnapshotProvenancePlanDivergence(t *testing.T) {
 	}
 }
 
+func TestRD004LocalePlanDivergenceExplainsProviderChoice(t *testing.T) {
+	t.Parallel()
+
+	decision := controlplane.DecisionOutcome{
+		OutcomeKind:        controlplane.OutcomeAdmit,
+		Phase:              controlplane.PhasePreTurn,
+		Scope:              controlplane.ScopeSession,
+		SessionID:          "sess-rd4-locale",
+		EventID:            "evt-rd4-locale-1",
+		RuntimeTimestampMS: 100,
+		WallClockMS:        100,
+		EmittedBy:          controlplane.EmitterRK25,
+		Reason:             "admission_capacity_allow",
+	}
+	baseline := []replaycmp.TraceArtifact{{PlanHash: "plan-rd4", SnapshotProvenanceRef: "snapshot-a", Locale: "es-MX", Decision: decision}}
+	replayed := []replaycmp.TraceArtifact{{PlanHash: "plan-rd4", SnapshotProvenanceRef: "snapshot-a", Locale: "es-MX", Decision: decision}}
+	if divergences := replaycmp.CompareTraceArtifacts(baseline, replayed, replaycmp.CompareConfig{}); len(divergences) != 0 {
+		t.Fatalf("expected no divergence for matching locale, got %+v", divergences)
+	}
+
+	replayed[0].Locale = ""
+	divergences := replaycmp.CompareTraceArtifacts(baseline, replayed, replaycmp.CompareConfig{})
+	if len(divergences) != 1 || divergences[0].Class != obs.PlanDivergence || !strings.Contains(divergences[0].Message, "locale mismatch") || !strings.Contains(divergences[0].Message, "replay=unset") {
+		t.Fatalf("expected PLAN_DIVERGENCE for locale mismatch, got %+v", divergences)
+	}
+}
+
+func TestRD004SessionSummaryPlanDivergenceExplainsLLMContext(t *testing.T) {
+	t.Parallel()
+
+	decision := controlplane.DecisionOutcome{
+		OutcomeKind:        controlplane.OutcomeAdmit,
+		Phase:              controlplane.PhasePreTurn,
+		Scope:              controlplane.ScopeSession,
+		SessionID:          "sess-rd4-summary",
+		EventID:            "evt-rd4-summary-1",
+		RuntimeTimestampMS: 100,
+		WallClockMS:        100,
+		EmittedBy:          controlplane.EmitterRK25,
+		Reason:             "admission_capacity_allow",
+	}
+	baseline := []replaycmp.TraceArtifact{{PlanHash: "plan-rd4", SnapshotProvenanceRef: "snapshot-a", SessionSummaryHash: "summary-hash-a", Decision: decision}}
+	replayed := []replaycmp.TraceArtifact{{PlanHash: "plan-rd4", SnapshotProvenanceRef: "snapshot-a", SessionSummaryHash: "summary-hash-b", Decision: decision}}
+	divergences := replaycmp.CompareTraceArtifacts(baseline, replayed, replaycmp.CompareConfig{})
+	if len(divergences) != 1 || divergences[0].Class != obs.PlanDivergence || !strings.Contains(divergences[0].Message, "session summary mismatch") {
+		t.Fatalf("expected PLAN_DIVERGENCE for session summary mismatch, got %+v", divergences)
+	}
+
+	replayed[0].SessionSummaryHash = ""
+	divergences = replaycmp.CompareTraceArtifacts(baseline, replayed, replaycmp.CompareConfig{})
+	if len(divergences) != 1 || !strings.Contains(divergences[0].Message, "replay=none") {
+		t.Fatalf("expected unbound summary to be labelled none, got %+v", divergences)
+	}
+}
+
 func baselineEvidence(turnID string) timeline.BaselineEvidence {
 	openProposed := int64(0)
 	open := int64(80)
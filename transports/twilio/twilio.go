@@ -0,0 +1,587 @@
+package twilio
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/planresolver"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/prelude"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/sequence"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/transport"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/identifiers"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/wscodec"
+	"github.com/tiger/realtime-speech-pipeline/transports/websocket"
+)
+
+// SampleRateHz is the Media Streams audio rate; audio reaches the pipeline as 8 kHz PCM16 mono
+// and reply audio must be produced at the same rate.
+const SampleRateHz = 8000
+
+// MediaEncoding is the only Media Streams encoding the adapter accepts.
+const MediaEncoding = "audio/x-mulaw"
+
+// DefaultSpeechRMS is the PCM16 frame RMS at or above which inbound audio counts as speech.
+const DefaultSpeechRMS = 500
+
+// Media Streams message events. Twilio sends connected, start, media, dtmf, mark, and stop;
+// the adapter sends media, mark, and clear.
+const (
+	EventConnected = "connected"
+	EventStart     = "start"
+	EventMedia     = "media"
+	EventDTMF      = "dtmf"
+	EventMark      = "mark"
+	EventStop      = "stop"
+	EventClear     = "clear"
+)
+
+// Turn lifecycle outcomes recorded in the Report.
+const (
+	OutcomeCommit    = "commit"
+	OutcomeAbort     = "abort"
+	OutcomeCancelled = "cancelled"
+)
+
+// frameSamples is one 20 ms outbound media chunk.
+const frameSamples = SampleRateHz / 50
+
+// Config configures the Twilio Media Streams transport handler.
+type Config struct {
+	PipelineVersion string
+	AuthorityEpoch  int64
+	// NewPipeline starts the pipeline for each stream, passing the traceparent header of the
+	// stream's WebSocket handshake (empty when absent).
+	NewPipeline func(sessionID, traceparent string) (websocket.Pipeline, error)
+	// OnReport, when set, receives each stream's Report after the WebSocket closes.
+	OnReport func(transport.Report)
+	// SpeechRMS defaults to DefaultSpeechRMS.
+	SpeechRMS float64
+	// Endpointing ends captures at utterance endpoints; nil uses
+	// planresolver.DefaultSTTEndpointing.
+	Endpointing *controlplane.STTEndpointing
+	// Clock defaults to time.Now.
+	Clock func() time.Time
+	// Logger defaults to log.Default().
+	Logger *log.Logger
+}
+
+// message is one Media Streams WebSocket message in either direction.
+type message struct {
+	Event          string        `json:"event"`
+	SequenceNumber string        `json:"sequenceNumber,omitempty"`
+	StreamSID      string        `json:"streamSid,omitempty"`
+	Start          *startPayload `json:"start,omitempty"`
+	Media          *mediaPayload `json:"media,omitempty"`
+	Mark           *markPayload  `json:"mark,omitempty"`
+}
+
+type startPayload struct {
+	StreamSID   string      `json:"streamSid"`
+	CallSID     string      `json:"callSid"`
+	MediaFormat mediaFormat `json:"mediaFormat"`
+}
+
+type mediaFormat struct {
+	Encoding   string `json:"encoding"`
+	SampleRate int    `json:"sampleRate"`
+	Channels   int    `json:"channels"`
+}
+
+type mediaPayload struct {
+	Track     string `json:"track,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Payload   string `json:"payload"`
+}
+
+type markPayload struct {
+	Name string `json:"name"`
+}
+
+// NewHandler upgrades each Twilio <Stream> connection and runs one pipeline session per
+// stream. Inbound mulaw audio is segmented into captures by an energy VAD and the prelude
+// endpointer, since calls have no push-to-talk; each reply is sent back as mulaw media
+// followed by a mark named after the turn, whose echo acknowledges playback.
+func NewHandler(cfg Config) (http.Handler, error) {
+	if cfg.PipelineVersion == "" {
+		return nil, fmt.Errorf("pipeline_version is required")
+	}
+	if cfg.NewPipeline == nil {
+		return nil, fmt.Errorf("pipeline factory is required")
+	}
+	if cfg.AuthorityEpoch < 0 {
+		return nil, fmt.Errorf("authority_epoch must be >=0")
+	}
+	if cfg.SpeechRMS == 0 {
+		cfg.SpeechRMS = DefaultSpeechRMS
+	}
+	if cfg.SpeechRMS < 0 {
+		return nil, fmt.Errorf("speech_rms must be >=0")
+	}
+	if cfg.Endpointing == nil {
+		endpointing := planresolver.DefaultSTTEndpointing
+		cfg.Endpointing = &endpointing
+	}
+	if err := cfg.Endpointing.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.Default()
+	}
+	ids := identifiers.NewGenerator(cfg.Clock, nil)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wscodec.Upgrade(w, r)
+		if err != nil {
+			cfg.Logger.Printf("twilio transport: upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		sessionID, err := ids.New(identifiers.KindSession)
+		if err != nil {
+			cfg.Logger.Printf("twilio transport: session id: %v", err)
+			return
+		}
+		s, err := newSession(cfg, ids, sessionID, conn)
+		if err != nil {
+			cfg.Logger.Printf("twilio transport: session %s: %v", sessionID, err)
+			return
+		}
+		s.report.Traceparent = r.Header.Get("traceparent")
+		if err := s.serve(); err != nil {
+			cfg.Logger.Printf("twilio transport: session %s ended: %v", sessionID, err)
+		}
+		if cfg.OnReport != nil {
+			cfg.OnReport(s.finish())
+		}
+	}), nil
+}
+
+// session is one stream's transport state.
+type session struct {
+	cfg       Config
+	ids       *identifiers.Generator
+	conn      *wscodec.Conn
+	startedAt time.Time
+	pipeline  websocket.Pipeline
+
+	trigger    *prelude.Trigger
+	endpointer *prelude.Endpointer
+	sequences  *sequence.Allocator
+	meter      *transport.BandwidthMeter
+	playback   *transport.PlaybackTracker
+	// transportSequence counts stream messages; signals carry the sequence of the message that
+	// caused them.
+	transportSequence int64
+	// openedAtMS is when the current capture opened; captureEnds maps turn ids to capture end.
+	openedAtMS  int64
+	captureEnds map[string]int64
+	// replies maps turn ids awaiting their mark echo to the reply duration and lifecycle entry.
+	replies map[string]pendingReply
+	report  transport.Report
+}
+
+type pendingReply struct {
+	durationMS int64
+	lifecycle  int
+}
+
+func newSession(cfg Config, ids *identifiers.Generator, sessionID string, conn *wscodec.Conn) (*session, error) {
+	trigger, err := prelude.NewTrigger(controlplane.SessionTrigger{Mode: controlplane.TriggerContinuous}, nil)
+	if err != nil {
+		return nil, err
+	}
+	endpointer, err := prelude.NewEndpointer(*cfg.Endpointing, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &session{
+		cfg:         cfg,
+		ids:         ids,
+		conn:        conn,
+		startedAt:   cfg.Clock(),
+		trigger:     trigger,
+		endpointer:  endpointer,
+		sequences:   sequence.NewAllocator(),
+		meter:       transport.NewBandwidthMeter(transport.BandwidthConfig{SessionID: sessionID, PipelineVersion: cfg.PipelineVersion}),
+		playback:    transport.NewPlaybackTracker(transport.PlaybackConfig{SessionID: sessionID, PipelineVersion: cfg.PipelineVersion}),
+		captureEnds: map[string]int64{},
+		replies:     map[string]pendingReply{},
+		report:      transport.Report{SessionID: sessionID, PipelineVersion: cfg.PipelineVersion},
+	}, nil
+}
+
+func (s *session) serve() error {
+	defer func() {
+		if s.pipeline == nil {
+			return
+		}
+		if err := s.pipeline.Close(); err != nil {
+			s.cfg.Logger.Printf("twilio transport: session %s pipeline close: %v", s.report.SessionID, err)
+		}
+	}()
+	for {
+		_, payload, err := s.conn.ReadMessage()
+		switch {
+		case errors.Is(err, wscodec.ErrClosed):
+			return s.signal("ended", "client_closed", "")
+		case errors.Is(err, io.EOF):
+			return s.signal("disconnected", "connection_lost", "")
+		case err != nil:
+			_ = s.signal("disconnected", "read_error", "")
+			return err
+		}
+		s.transportSequence++
+
+		var msg message
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			s.report.Errors++
+			s.cfg.Logger.Printf("twilio transport: session %s: decode message: %v", s.report.SessionID, err)
+			continue
+		}
+		if msg.Event == EventMedia {
+			err = s.handleMedia(msg, len(payload))
+		} else {
+			var stop bool
+			stop, err = s.handleControl(msg, len(payload))
+			if stop {
+				return err
+			}
+		}
+		if err != nil {
+			s.report.Errors++
+			s.cfg.Logger.Printf("twilio transport: session %s: %v", s.report.SessionID, err)
+		}
+	}
+}
+
+// handleControl applies one non-media stream event; it reports true when the stream ended.
+func (s *session) handleControl(msg message, size int) (bool, error) {
+	_ = s.meter.Record(transport.DirectionIngress, eventabi.LaneControl, eventabi.PayloadMetadata, size)
+	s.report.ControlMessages++
+	switch msg.Event {
+	case EventConnected, EventDTMF:
+		return false, nil
+	case EventStart:
+		return s.start(msg)
+	case EventMark:
+		if msg.Mark == nil {
+			return false, fmt.Errorf("mark message without mark")
+		}
+		if err := s.ingress(eventabi.LaneTelemetry, eventabi.PayloadMetadata, nil); err != nil {
+			return false, err
+		}
+		return false, s.acknowledgeMark(msg.Mark.Name)
+	case EventStop:
+		return true, s.signal("ended", "call_ended", "")
+	default:
+		return false, fmt.Errorf("unsupported stream event %q", msg.Event)
+	}
+}
+
+func (s *session) start(msg message) (bool, error) {
+	if s.pipeline != nil {
+		return false, fmt.Errorf("duplicate start message")
+	}
+	if msg.Start == nil {
+		return true, s.signal("disconnected", "invalid_start", "")
+	}
+	format := msg.Start.MediaFormat
+	if format.Encoding != MediaEncoding || format.SampleRate != SampleRateHz || format.Channels > 1 {
+		_ = s.signal("disconnected", "unsupported_media_format", "")
+		return true, fmt.Errorf("unsupported media format %s/%dHz/%dch", format.Encoding, format.SampleRate, format.Channels)
+	}
+	s.report.StreamSID = msg.Start.StreamSID
+	if s.report.StreamSID == "" {
+		s.report.StreamSID = msg.StreamSID
+	}
+	s.report.CallSID = msg.Start.CallSID
+	pipeline, err := s.cfg.NewPipeline(s.report.SessionID, s.report.Traceparent)
+	if err != nil {
+		_ = s.signal("disconnected", "pipeline_unavailable", "")
+		return true, err
+	}
+	s.pipeline = pipeline
+	return false, s.signal("connected", "", "")
+}
+
+// handleMedia feeds one inbound audio chunk through the trigger, the pipeline, and the
+// endpointer. Media timestamps, in ms since the stream started, order the VAD decisions.
+func (s *session) handleMedia(msg message, size int) error {
+	_ = s.meter.Record(transport.DirectionIngress, eventabi.LaneData, eventabi.PayloadAudioRaw, size)
+	if s.pipeline == nil {
+		return fmt.Errorf("media before start")
+	}
+	if msg.Media == nil || (msg.Media.Track != "" && msg.Media.Track != "inbound") {
+		return nil
+	}
+	audio, err := base64.StdEncoding.DecodeString(msg.Media.Payload)
+	if err != nil {
+		return fmt.Errorf("decode media payload: %w", err)
+	}
+	atMS, err := strconv.ParseInt(msg.Media.Timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("decode media timestamp %q: %w", msg.Media.Timestamp, err)
+	}
+	sampleIndex := s.report.AudioSamples
+	if err := s.ingress(eventabi.LaneData, eventabi.PayloadAudioRaw, &eventabi.MediaTime{SampleIndex: &sampleIndex}); err != nil {
+		return err
+	}
+	pcm := transport.DecodeMuLaw(audio)
+	s.report.AudioFrames++
+	s.report.AudioSamples += int64(len(pcm))
+	speech := rms(pcm) >= s.cfg.SpeechRMS
+
+	_, action, err := s.trigger.ObserveAudio(prelude.AudioFrame{TimestampMS: atMS, Speech: speech})
+	if err != nil {
+		return err
+	}
+	if action == prelude.TriggerOpenTurn {
+		if err := s.openCapture(); err != nil {
+			return err
+		}
+	}
+	if s.trigger.Capturing() {
+		turn, err := s.pipeline.AppendAudio(pcm)
+		if err != nil {
+			return err
+		}
+		if turn != nil {
+			// The pipeline force-ended a capture the endpointer never closed.
+			if _, _, err := s.trigger.EndCapture(prelude.Endpoint{DecidedAtMS: atMS}); err != nil {
+				return err
+			}
+			s.captureEnds[turn.TurnID] = s.nowMS()
+			return s.egressTurn(*turn)
+		}
+	}
+
+	endpoint, ok, err := s.endpointer.Observe(prelude.VADFrame{TimestampMS: atMS, Speech: speech})
+	if err != nil || !ok {
+		return err
+	}
+	if _, action, err := s.trigger.EndCapture(endpoint); err != nil || action != prelude.TriggerCaptureEnded {
+		return err
+	}
+	return s.endCapture()
+}
+
+// openCapture starts a capture at speech onset. Speech over a reply still playing is
+// barge-in: the caller's playback is cleared and the interrupted turn is cancelled.
+func (s *session) openCapture() error {
+	if len(s.replies) > 0 {
+		if err := s.writeMessage(message{Event: EventClear, StreamSID: s.report.StreamSID}); err != nil {
+			return err
+		}
+		turnIDs := make([]string, 0, len(s.replies))
+		for turnID := range s.replies {
+			turnIDs = append(turnIDs, turnID)
+		}
+		sort.Strings(turnIDs)
+		for _, turnID := range turnIDs {
+			if err := s.signal("playback_cancelled", "barge_in", turnID); err != nil {
+				return err
+			}
+			lifecycle := &s.report.TurnLifecycle[s.replies[turnID].lifecycle]
+			lifecycle.Outcome, lifecycle.Reason, lifecycle.TerminalAtMS = OutcomeCancelled, "barge_in", s.nowMS()
+			delete(s.replies, turnID)
+			s.recordPlayback(turnID, func(recorder websocket.PlaybackRecorder) error {
+				return recorder.CancelPlayback(turnID, "barge_in")
+			})
+		}
+	}
+	s.openedAtMS = s.nowMS()
+	if err := s.signal("capture_start", "", ""); err != nil {
+		return err
+	}
+	return s.pipeline.StartCapture()
+}
+
+func (s *session) endCapture() error {
+	if err := s.signal("capture_end", "", ""); err != nil {
+		return err
+	}
+	captureEndMS := s.nowMS()
+	turn, err := s.pipeline.EndCapture()
+	if err != nil || turn == nil {
+		return err
+	}
+	s.captureEnds[turn.TurnID] = captureEndMS
+	return s.egressTurn(*turn)
+}
+
+// egressTurn sends the reply as 20 ms mulaw media chunks followed by a mark named after the
+// turn; Twilio echoes the mark once the chunks before it have played.
+func (s *session) egressTurn(turn websocket.Turn) error {
+	s.report.Turns++
+	lifecycle := transport.TurnLifecycle{TurnID: turn.TurnID, OpenedAtMS: s.openedAtMS, TerminalAtMS: s.nowMS(), Outcome: OutcomeCommit}
+	if !turn.Committed {
+		lifecycle.Outcome, lifecycle.Reason = OutcomeAbort, turn.FallbackReason
+	}
+	s.report.TurnLifecycle = append(s.report.TurnLifecycle, lifecycle)
+	if len(turn.ReplyPCM) == 0 {
+		return nil
+	}
+	for start := 0; start < len(turn.ReplyPCM); start += frameSamples {
+		chunk := transport.EncodeMuLaw(turn.ReplyPCM[start:min(start+frameSamples, len(turn.ReplyPCM))])
+		if err := s.writeMessage(message{Event: EventMedia, StreamSID: s.report.StreamSID, Media: &mediaPayload{Payload: base64.StdEncoding.EncodeToString(chunk)}}); err != nil {
+			return err
+		}
+	}
+	if err := s.playback.RecordEgress(turn.TurnID, s.nowMS()); err != nil {
+		return err
+	}
+	s.recordPlayback(turn.TurnID, func(recorder websocket.PlaybackRecorder) error {
+		return recorder.RecordReplyEgress(turn.TurnID)
+	})
+	s.replies[turn.TurnID] = pendingReply{
+		durationMS: int64(len(turn.ReplyPCM)) * 1000 / SampleRateHz,
+		lifecycle:  len(s.report.TurnLifecycle) - 1,
+	}
+	return s.writeMessage(message{Event: EventMark, StreamSID: s.report.StreamSID, Mark: &markPayload{Name: turn.TurnID}})
+}
+
+// acknowledgeMark treats a mark echo as the caller having heard the whole reply. Marks of
+// replies cancelled by barge-in, which Twilio echoes on clear, are ignored.
+func (s *session) acknowledgeMark(turnID string) error {
+	reply, ok := s.replies[turnID]
+	if !ok {
+		return nil
+	}
+	delete(s.replies, turnID)
+	ack := transport.PlaybackAck{TurnID: turnID, PlayedUntilMS: max(reply.durationMS, 1)}
+	playback, first, err := s.playback.Acknowledge(ack, s.nowMS())
+	if err != nil || !first {
+		return err
+	}
+	s.recordPlayback(turnID, func(recorder websocket.PlaybackRecorder) error {
+		return recorder.AcknowledgePlayback(ack)
+	})
+	s.report.FirstAudio = append(s.report.FirstAudio, transport.FirstAudio{
+		TurnID:                       playback.TurnID,
+		PerceivedFirstAudioLatencyMS: playback.PlayedAtMS - s.captureEnds[playback.TurnID],
+		PlaybackStartDelayMS:         playback.PlayedAtMS - playback.EgressAtMS,
+	})
+	return nil
+}
+
+// recordPlayback reports reply playback to a pipeline that records it in its turn evidence, so
+// the session baseline carries first-audio and barge-in times. A failure to record is logged
+// and does not end the call.
+func (s *session) recordPlayback(turnID string, record func(websocket.PlaybackRecorder) error) {
+	recorder, ok := s.pipeline.(websocket.PlaybackRecorder)
+	if !ok {
+		return
+	}
+	if err := record(recorder); err != nil {
+		s.cfg.Logger.Printf("twilio transport: session %s turn %s playback evidence: %v", s.report.SessionID, turnID, err)
+	}
+}
+
+// ingress tags and sequences the event record for the current stream message and validates it
+// against the event ABI before the pipeline sees the payload.
+func (s *session) ingress(lane eventabi.Lane, class eventabi.PayloadClass, mediaTime *eventabi.MediaTime) error {
+	eventID, err := s.ids.New(identifiers.KindEvent)
+	if err != nil {
+		return err
+	}
+	transportSequence := s.transportSequence
+	record, err := transport.TagIngressEventRecord(eventabi.EventRecord{
+		SchemaVersion:      "v1.0",
+		EventScope:         eventabi.ScopeSession,
+		SessionID:          s.report.SessionID,
+		PipelineVersion:    s.cfg.PipelineVersion,
+		EventID:            eventID,
+		Lane:               lane,
+		TransportSequence:  &transportSequence,
+		RuntimeTimestampMS: s.nowMS(),
+		WallClockMS:        s.cfg.Clock().UnixMilli(),
+		PayloadClass:       class,
+		MediaTime:          mediaTime,
+	}, transport.IngressClassificationConfig{Sequences: s.sequences})
+	if err != nil {
+		return err
+	}
+	if err := record.Validate(); err != nil {
+		return fmt.Errorf("ingress event record: %w", err)
+	}
+	s.report.LastRuntimeSequence = record.RuntimeSequence
+	return nil
+}
+
+// signal records an RK-23 control signal in the report; a turn id scopes it to that turn.
+func (s *session) signal(name string, reason string, turnID string) error {
+	eventID, err := s.ids.New(identifiers.KindEvent)
+	if err != nil {
+		return err
+	}
+	runtimeSequence, err := s.sequences.Next(s.report.SessionID)
+	if err != nil {
+		return err
+	}
+	signal, err := transport.BuildConnectionSignal(transport.ConnectionSignalInput{
+		SessionID:            s.report.SessionID,
+		TurnID:               turnID,
+		PipelineVersion:      s.cfg.PipelineVersion,
+		EventID:              eventID,
+		Signal:               name,
+		TransportSequence:    s.transportSequence,
+		RuntimeSequence:      runtimeSequence,
+		AuthorityEpoch:       s.cfg.AuthorityEpoch,
+		RuntimeTimestampMS:   s.nowMS(),
+		WallClockTimestampMS: s.cfg.Clock().UnixMilli(),
+		Reason:               reason,
+	})
+	if err != nil {
+		return err
+	}
+	s.report.Signals = append(s.report.Signals, signal)
+	s.report.LastRuntimeSequence = runtimeSequence
+	return nil
+}
+
+func (s *session) finish() transport.Report {
+	s.report.Bandwidth = s.meter.Report()
+	s.sequences.Forget(s.report.SessionID)
+	return s.report
+}
+
+func (s *session) writeMessage(msg message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if msg.Event == EventMedia {
+		_ = s.meter.Record(transport.DirectionEgress, eventabi.LaneData, eventabi.PayloadAudioRaw, len(data))
+	} else {
+		_ = s.meter.Record(transport.DirectionEgress, eventabi.LaneControl, eventabi.PayloadMetadata, len(data))
+	}
+	return s.conn.WriteMessage(wscodec.OpText, data)
+}
+
+func (s *session) nowMS() int64 {
+	return s.cfg.Clock().Sub(s.startedAt).Milliseconds()
+}
+
+func rms(pcm []int16) float64 {
+	if len(pcm) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, sample := range pcm {
+		sum += float64(sample) * float64(sample)
+	}
+	return math.Sqrt(sum / float64(len(pcm)))
+}
@@ -0,0 +1,372 @@
+package twilio
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/controlplane"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/transport"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/wscodec"
+	"github.com/tiger/realtime-speech-pipeline/transports/websocket"
+)
+
+// testTraceparent is the W3C traceparent header test clients send with the handshake.
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// echoPipeline completes a turn on capture end, replying with the captured audio, and records
+// the playback evidence the transport reports.
+type echoPipeline struct {
+	captured []int16
+	turns    int
+	closed   chan struct{}
+	playback []string
+}
+
+func (p *echoPipeline) StartCapture() error {
+	p.captured = nil
+	return nil
+}
+
+func (p *echoPipeline) AppendAudio(pcm []int16) (*websocket.Turn, error) {
+	p.captured = append(p.captured, pcm...)
+	return nil, nil
+}
+
+func (p *echoPipeline) EndCapture() (*websocket.Turn, error) {
+	p.turns++
+	return &websocket.Turn{TurnID: fmt.Sprintf("turn-%d", p.turns), Transcript: "hello", Reply: "hello back", ReplyPCM: p.captured, Committed: p.turns != 3, FallbackReason: "provider_timeout"}, nil
+}
+
+func (p *echoPipeline) Close() error {
+	close(p.closed)
+	return nil
+}
+
+func (p *echoPipeline) RecordReplyEgress(turnID string) error {
+	p.playback = append(p.playback, "egress:"+turnID)
+	return nil
+}
+
+func (p *echoPipeline) AcknowledgePlayback(ack transport.PlaybackAck) error {
+	p.playback = append(p.playback, fmt.Sprintf("ack:%s:%d", ack.TurnID, ack.PlayedUntilMS))
+	return nil
+}
+
+func (p *echoPipeline) CancelPlayback(turnID string, reason string) error {
+	p.playback = append(p.playback, "cancel:"+turnID+":"+reason)
+	return nil
+}
+
+// testStream is a minimal masking WebSocket client speaking the Media Streams protocol.
+type testStream struct {
+	t        *testing.T
+	conn     net.Conn
+	reader   *bufio.Reader
+	sequence int
+	atMS     int64
+}
+
+func dialTestStream(t *testing.T, serverURL string) *testStream {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(serverURL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	request := "GET /twilio HTTP/1.1\r\nHost: transport\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Key: " + key + "\r\nTraceparent: " + testTraceparent + "\r\n\r\n"
+	if _, err := conn.Write([]byte(request)); err != nil {
+		t.Fatalf("handshake write: %v", err)
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("handshake read: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != wscodec.AcceptKey(key) {
+		t.Fatalf("unexpected handshake response: %d %v", resp.StatusCode, resp.Header)
+	}
+	return &testStream{t: t, conn: conn, reader: reader}
+}
+
+func (c *testStream) send(op byte, payload []byte) {
+	c.t.Helper()
+	frame := []byte{0x80 | op}
+	if len(payload) < 126 {
+		frame = append(frame, 0x80|byte(len(payload)))
+	} else {
+		frame = append(frame, 0x80|126, byte(len(payload)>>8), byte(len(payload)))
+	}
+	mask := [4]byte{7, 1, 3, 9}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		c.t.Fatalf("frame write: %v", err)
+	}
+}
+
+func (c *testStream) sendJSON(msg message) {
+	c.t.Helper()
+	c.sequence++
+	msg.SequenceNumber = strconv.Itoa(c.sequence)
+	msg.StreamSID = "MZ-stream"
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatalf("encode stream message: %v", err)
+	}
+	c.send(wscodec.OpText, payload)
+}
+
+// sendAudio streams durationMS of 20 ms mulaw chunks at the given amplitude.
+func (c *testStream) sendAudio(amplitude int16, durationMS int64) {
+	c.t.Helper()
+	pcm := make([]int16, frameSamples)
+	for i := range pcm {
+		if i%2 == 0 {
+			pcm[i] = amplitude
+		} else {
+			pcm[i] = -amplitude
+		}
+	}
+	payload := base64.StdEncoding.EncodeToString(transport.EncodeMuLaw(pcm))
+	for end := c.atMS + durationMS; c.atMS < end; c.atMS += 20 {
+		c.sendJSON(message{Event: EventMedia, Media: &mediaPayload{Track: "inbound", Timestamp: strconv.FormatInt(c.atMS, 10), Payload: payload}})
+	}
+}
+
+func (c *testStream) readJSON() message {
+	c.t.Helper()
+	_ = c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		c.t.Fatalf("frame read: %v", err)
+	}
+	length := uint64(header[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			c.t.Fatalf("frame length read: %v", err)
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		c.t.Fatalf("payload read: %v", err)
+	}
+	var msg message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		c.t.Fatalf("decode stream message: %v", err)
+	}
+	return msg
+}
+
+// readReply reads reply media chunks up to the turn's mark and returns the mark name and the
+// number of reply samples.
+func (c *testStream) readReply() (string, int) {
+	c.t.Helper()
+	samples := 0
+	for {
+		msg := c.readJSON()
+		switch {
+		case msg.Event == EventMedia && msg.StreamSID == "MZ-stream":
+			audio, err := base64.StdEncoding.DecodeString(msg.Media.Payload)
+			if err != nil {
+				c.t.Fatalf("decode reply media: %v", err)
+			}
+			samples += len(audio)
+		case msg.Event == EventMark:
+			return msg.Mark.Name, samples
+		default:
+			c.t.Fatalf("unexpected message while reading reply: %+v", msg)
+		}
+	}
+}
+
+func TestHandlerSegmentsCallAudioIntoTurns(t *testing.T) {
+	t.Parallel()
+
+	pipeline := &echoPipeline{closed: make(chan struct{})}
+	reports := make(chan transport.Report, 1)
+	var gotTraceparent string
+	handler, err := NewHandler(Config{
+		PipelineVersion: "pipeline-twilio",
+		AuthorityEpoch:  2,
+		NewPipeline: func(_ string, traceparent string) (websocket.Pipeline, error) {
+			gotTraceparent = traceparent
+			return pipeline, nil
+		},
+		OnReport: func(report transport.Report) { reports <- report },
+		Logger:   log.New(io.Discard, "", 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	stream := dialTestStream(t, server.URL)
+
+	stream.sendJSON(message{Event: EventConnected})
+	stream.sendJSON(message{Event: EventStart, Start: &startPayload{StreamSID: "MZ-stream", CallSID: "CA-call", MediaFormat: mediaFormat{Encoding: MediaEncoding, SampleRate: SampleRateHz, Channels: 1}}})
+	stream.sendAudio(0, 200)
+	stream.sendAudio(4000, 400)
+	stream.sendAudio(0, 600)
+	name, samples := stream.readReply()
+	if name != "turn-1" || samples == 0 {
+		t.Fatalf("expected turn-1 reply audio and mark, got %q with %d samples", name, samples)
+	}
+	stream.sendJSON(message{Event: EventMark, Mark: &markPayload{Name: "turn-1"}})
+
+	// The second reply is interrupted by caller speech before its mark returns.
+	stream.sendAudio(4000, 400)
+	stream.sendAudio(0, 600)
+	if name, _ := stream.readReply(); name != "turn-2" {
+		t.Fatalf("expected turn-2 reply, got %q", name)
+	}
+	stream.sendAudio(4000, 20)
+	if msg := stream.readJSON(); msg.Event != EventClear || msg.StreamSID != "MZ-stream" {
+		t.Fatalf("expected barge-in to clear playback, got %+v", msg)
+	}
+	stream.sendJSON(message{Event: EventMark, Mark: &markPayload{Name: "turn-2"}})
+	stream.sendAudio(4000, 380)
+	stream.sendAudio(0, 600)
+	if name, _ := stream.readReply(); name != "turn-3" {
+		t.Fatalf("expected turn-3 reply, got %q", name)
+	}
+	stream.sendJSON(message{Event: "dance"})
+	stream.sendJSON(message{Event: EventStop})
+
+	var report transport.Report
+	select {
+	case report = <-reports:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for transport report")
+	}
+	if gotTraceparent != testTraceparent || report.Traceparent != testTraceparent {
+		t.Fatalf("expected the handshake traceparent passed to the pipeline and reported, got %q and %q", gotTraceparent, report.Traceparent)
+	}
+	select {
+	case <-pipeline.closed:
+	default:
+		t.Fatalf("expected pipeline to be closed with the stream")
+	}
+	if report.StreamSID != "MZ-stream" || report.CallSID != "CA-call" || report.Turns != 3 || report.Errors != 1 || report.AudioSamples != int64(report.AudioFrames*frameSamples) {
+		t.Fatalf("unexpected report counts: %+v", report)
+	}
+	if len(report.FirstAudio) != 1 || report.FirstAudio[0].TurnID != "turn-1" {
+		t.Fatalf("expected first audio only for the uninterrupted turn, got %+v", report.FirstAudio)
+	}
+	wantPlayback := []string{"egress:turn-1", "ack:turn-1:900", "egress:turn-2", "cancel:turn-2:barge_in", "egress:turn-3"}
+	if strings.Join(pipeline.playback, ",") != strings.Join(wantPlayback, ",") {
+		t.Fatalf("expected playback evidence %v reported to the pipeline, got %v", wantPlayback, pipeline.playback)
+	}
+	wantLifecycle := []struct {
+		turnID  string
+		outcome string
+		reason  string
+	}{
+		{turnID: "turn-1", outcome: OutcomeCommit},
+		{turnID: "turn-2", outcome: OutcomeCancelled, reason: "barge_in"},
+		{turnID: "turn-3", outcome: OutcomeAbort, reason: "provider_timeout"},
+	}
+	if len(report.TurnLifecycle) != len(wantLifecycle) {
+		t.Fatalf("expected %d turn lifecycles, got %+v", len(wantLifecycle), report.TurnLifecycle)
+	}
+	for i, want := range wantLifecycle {
+		got := report.TurnLifecycle[i]
+		if got.TurnID != want.turnID || got.Outcome != want.outcome || got.Reason != want.reason || got.TerminalAtMS < got.OpenedAtMS {
+			t.Fatalf("turn lifecycle %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	wantSignals := []string{"connected", "capture_start", "capture_end", "capture_start", "capture_end", "playback_cancelled", "capture_start", "capture_end", "ended"}
+	if len(report.Signals) != len(wantSignals) {
+		t.Fatalf("expected signals %v, got %+v", wantSignals, report.Signals)
+	}
+	var lastSequence int64 = -1
+	for i, sig := range report.Signals {
+		if sig.Signal != wantSignals[i] || sig.EmittedBy != "RK-23" || sig.Lane != eventabi.LaneControl || sig.AuthorityEpoch != 2 {
+			t.Fatalf("signal %d: expected %s, got %+v", i, wantSignals[i], sig)
+		}
+		if err := sig.Validate(); err != nil {
+			t.Fatalf("signal %d: unexpected validation error: %v", i, err)
+		}
+		if sig.RuntimeSequence <= lastSequence {
+			t.Fatalf("signal %d: expected increasing runtime sequence, got %d after %d", i, sig.RuntimeSequence, lastSequence)
+		}
+		lastSequence = sig.RuntimeSequence
+	}
+	if cancelled := report.Signals[5]; cancelled.TurnID != "turn-2" || cancelled.EventScope != eventabi.ScopeTurn {
+		t.Fatalf("expected barge-in cancel scoped to turn-2, got %+v", cancelled)
+	}
+	if last := report.Signals[len(report.Signals)-1]; last.Reason != "call_ended" {
+		t.Fatalf("expected stop to end the call, got %+v", last)
+	}
+}
+
+func TestHandlerRejectsUnsupportedMediaFormat(t *testing.T) {
+	t.Parallel()
+
+	reports := make(chan transport.Report, 1)
+	created := false
+	handler, err := NewHandler(Config{
+		PipelineVersion: "pipeline-twilio",
+		NewPipeline: func(string, string) (websocket.Pipeline, error) {
+			created = true
+			return &echoPipeline{closed: make(chan struct{})}, nil
+		},
+		OnReport: func(report transport.Report) { reports <- report },
+		Logger:   log.New(io.Discard, "", 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	stream := dialTestStream(t, server.URL)
+	stream.sendJSON(message{Event: EventStart, Start: &startPayload{StreamSID: "MZ-stream", MediaFormat: mediaFormat{Encoding: "audio/l16", SampleRate: 16000, Channels: 1}}})
+
+	select {
+	case report := <-reports:
+		last := report.Signals[len(report.Signals)-1]
+		if created || last.Signal != "disconnected" || last.Reason != "unsupported_media_format" {
+			t.Fatalf("expected unsupported format to disconnect before the pipeline starts, got %+v", last)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for transport report")
+	}
+}
+
+func TestNewHandlerValidatesConfig(t *testing.T) {
+	t.Parallel()
+
+	factory := func(string, string) (websocket.Pipeline, error) { return nil, nil }
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "missing pipeline version", cfg: Config{NewPipeline: factory}},
+		{name: "missing factory", cfg: Config{PipelineVersion: "pipeline-twilio"}},
+		{name: "negative epoch", cfg: Config{PipelineVersion: "pipeline-twilio", NewPipeline: factory, AuthorityEpoch: -1}},
+		{name: "negative speech threshold", cfg: Config{PipelineVersion: "pipeline-twilio", NewPipeline: factory, SpeechRMS: -1}},
+		{name: "invalid endpointing", cfg: Config{PipelineVersion: "pipeline-twilio", NewPipeline: factory, Endpointing: &controlplane.STTEndpointing{}}},
+	}
+	for _, tc := range tests {
+		if _, err := NewHandler(tc.cfg); err == nil {
+			t.Fatalf("%s: expected config error", tc.name)
+		}
+	}
+}
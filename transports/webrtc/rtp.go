@@ -0,0 +1,41 @@
+package webrtc
+
+// rtpPosition is one in-order RTP packet's place in the session: its extended sequence
+// number and its first sample's index since the first packet.
+type rtpPosition struct {
+	sequence    int64
+	sampleIndex int64
+}
+
+// rtpClock extends 16-bit RTP sequence numbers and 32-bit timestamps across wraparound and
+// counts sequence gaps as lost packets.
+type rtpClock struct {
+	started      bool
+	lastSequence int64
+	lastTime     int64
+	baseTime     int64
+	lastRaw      uint32
+	lost         int64
+}
+
+// advance places a packet, returning false for duplicates and packets older than the last
+// accepted one; the pipeline consumes audio in order, so those are dropped.
+func (c *rtpClock) advance(sequenceNumber uint16, timestamp uint32) (rtpPosition, bool) {
+	if !c.started {
+		c.started = true
+		c.lastSequence = int64(sequenceNumber)
+		c.lastTime = int64(timestamp)
+		c.baseTime = c.lastTime
+		c.lastRaw = timestamp
+		return rtpPosition{sequence: c.lastSequence}, true
+	}
+	delta := int64(int16(sequenceNumber - uint16(c.lastSequence)))
+	if delta <= 0 {
+		return rtpPosition{}, false
+	}
+	c.lost += delta - 1
+	c.lastSequence += delta
+	c.lastTime += int64(int32(timestamp - c.lastRaw))
+	c.lastRaw = timestamp
+	return rtpPosition{sequence: c.lastSequence, sampleIndex: c.lastTime - c.baseTime}, true
+}
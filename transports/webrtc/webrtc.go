@@ -0,0 +1,545 @@
+package webrtc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/pion/rtp"
+	pion "github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/sequence"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/transport"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/identifiers"
+	"github.com/tiger/realtime-speech-pipeline/transports/websocket"
+)
+
+// SampleRateHz is the PCMU clock rate; audio reaches the pipeline as 8 kHz PCM16 mono and
+// reply audio must be produced at the same rate.
+const SampleRateHz = 8000
+
+// ControlChannelLabel is the label of the client-opened data channel carrying JSON control
+// messages in both directions.
+const ControlChannelLabel = "control"
+
+// MessageSignal carries one RK-23 control signal to the client over the control channel. The
+// other message types are the WebSocket transport's.
+const MessageSignal = "signal"
+
+// frameSamples is one 20 ms PCMU frame.
+const frameSamples = SampleRateHz / 50
+
+const maxOfferBytes = 1 << 20
+
+// Config configures the WebRTC transport handler.
+type Config struct {
+	PipelineVersion string
+	AuthorityEpoch  int64
+	// NewPipeline starts the pipeline for each accepted peer connection, passing the
+	// traceparent header of the SDP offer request (empty when absent).
+	NewPipeline func(sessionID, traceparent string) (websocket.Pipeline, error)
+	// OnReport, when set, receives each peer connection's Report after it ends.
+	OnReport func(transport.Report)
+	// ICEServers lists STUN/TURN URLs for the peer connection; empty uses host candidates only.
+	ICEServers []string
+	// SettingEngine tunes ICE gathering, for example loopback candidates for local testing.
+	SettingEngine pion.SettingEngine
+	// Clock defaults to time.Now.
+	Clock func() time.Time
+	// Logger defaults to log.Default().
+	Logger *log.Logger
+}
+
+// answer is the signaling response to an SDP offer.
+type answer struct {
+	Type      string `json:"type"`
+	SDP       string `json:"sdp"`
+	SessionID string `json:"session_id"`
+}
+
+type clientMessage struct {
+	Type          string `json:"type"`
+	TurnID        string `json:"turn_id,omitempty"`
+	PlayedUntilMS int64  `json:"played_until_ms,omitempty"`
+}
+
+type serverMessage struct {
+	Type                         string                  `json:"type"`
+	SessionID                    string                  `json:"session_id,omitempty"`
+	SampleRateHz                 int                     `json:"sample_rate_hz,omitempty"`
+	TurnID                       string                  `json:"turn_id,omitempty"`
+	Transcript                   string                  `json:"transcript,omitempty"`
+	Reply                        string                  `json:"reply,omitempty"`
+	Committed                    bool                    `json:"committed,omitempty"`
+	FallbackReason               string                  `json:"fallback_reason,omitempty"`
+	FirstOutputLatencyMS         int64                   `json:"first_output_latency_ms,omitempty"`
+	PerceivedFirstAudioLatencyMS int64                   `json:"perceived_first_audio_latency_ms,omitempty"`
+	Signal                       *eventabi.ControlSignal `json:"signal,omitempty"`
+	Error                        string                  `json:"error,omitempty"`
+}
+
+// NewHandler answers each POSTed JSON SDP offer ({"type":"offer","sdp":...}) with a
+// non-trickle answer and runs one pipeline session over the resulting peer connection. The
+// client sends its microphone as a PCMU audio track and opens the "control" data channel;
+// reply audio returns on a PCMU track.
+func NewHandler(cfg Config) (http.Handler, error) {
+	if cfg.PipelineVersion == "" {
+		return nil, fmt.Errorf("pipeline_version is required")
+	}
+	if cfg.NewPipeline == nil {
+		return nil, fmt.Errorf("pipeline factory is required")
+	}
+	if cfg.AuthorityEpoch < 0 {
+		return nil, fmt.Errorf("authority_epoch must be >=0")
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.Default()
+	}
+	mediaEngine := &pion.MediaEngine{}
+	if err := mediaEngine.RegisterCodec(pion.RTPCodecParameters{RTPCodecCapability: pcmuCapability(), PayloadType: 0}, pion.RTPCodecTypeAudio); err != nil {
+		return nil, fmt.Errorf("register pcmu codec: %w", err)
+	}
+	api := pion.NewAPI(pion.WithMediaEngine(mediaEngine), pion.WithSettingEngine(cfg.SettingEngine))
+	ids := identifiers.NewGenerator(cfg.Clock, nil)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "POST an SDP offer", http.StatusMethodNotAllowed)
+			return
+		}
+		var offer pion.SessionDescription
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxOfferBytes)).Decode(&offer); err != nil {
+			http.Error(w, fmt.Sprintf("decode offer: %v", err), http.StatusBadRequest)
+			return
+		}
+		if offer.Type != pion.SDPTypeOffer {
+			http.Error(w, fmt.Sprintf("expected an offer, got %q", offer.Type.String()), http.StatusBadRequest)
+			return
+		}
+		sessionID, err := ids.New(identifiers.KindSession)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s, local, err := startSession(cfg, api, ids, sessionID, offer)
+		if err != nil {
+			cfg.Logger.Printf("webrtc transport: session %s negotiation failed: %v", sessionID, err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.report.Traceparent = r.Header.Get("traceparent")
+		go func() {
+			if err := s.serve(); err != nil {
+				cfg.Logger.Printf("webrtc transport: session %s ended: %v", sessionID, err)
+			}
+			if cfg.OnReport != nil {
+				cfg.OnReport(s.finish())
+			}
+		}()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(answer{Type: local.Type.String(), SDP: local.SDP, SessionID: sessionID})
+	}), nil
+}
+
+func pcmuCapability() pion.RTPCodecCapability {
+	return pion.RTPCodecCapability{MimeType: pion.MimeTypePCMU, ClockRate: SampleRateHz, Channels: 1}
+}
+
+type eventKind int
+
+const (
+	eventControlOpen eventKind = iota
+	eventControlMessage
+	eventAudio
+	eventEnd
+)
+
+// event funnels pion callbacks, which arrive on their own goroutines, into the session loop so
+// the pipeline is only ever called from one goroutine.
+type event struct {
+	kind    eventKind
+	channel *pion.DataChannel
+	payload []byte
+	packet  *rtp.Packet
+	signal  string
+	reason  string
+}
+
+// session is one peer connection's transport state.
+type session struct {
+	cfg       Config
+	ids       *identifiers.Generator
+	pc        *pion.PeerConnection
+	reply     *pion.TrackLocalStaticSample
+	control   *pion.DataChannel
+	startedAt time.Time
+	events    chan event
+	done      chan struct{}
+
+	sequences *sequence.Allocator
+	meter     *transport.BandwidthMeter
+	playback  *transport.PlaybackTracker
+	// controlSequence counts control channel messages; signals carry the sequence of the
+	// message that caused them. Audio records carry the extended RTP sequence number.
+	controlSequence int64
+	rtp             rtpClock
+	// captureEnds maps turn ids to capture end, the origin of perceived first-audio latency.
+	captureEnds map[string]int64
+	report      transport.Report
+}
+
+func startSession(cfg Config, api *pion.API, ids *identifiers.Generator, sessionID string, offer pion.SessionDescription) (*session, *pion.SessionDescription, error) {
+	iceServers := make([]pion.ICEServer, 0, len(cfg.ICEServers))
+	for _, url := range cfg.ICEServers {
+		iceServers = append(iceServers, pion.ICEServer{URLs: []string{url}})
+	}
+	pc, err := api.NewPeerConnection(pion.Configuration{ICEServers: iceServers})
+	if err != nil {
+		return nil, nil, err
+	}
+	s := &session{
+		cfg:         cfg,
+		ids:         ids,
+		pc:          pc,
+		startedAt:   cfg.Clock(),
+		events:      make(chan event, 256),
+		done:        make(chan struct{}),
+		sequences:   sequence.NewAllocator(),
+		meter:       transport.NewBandwidthMeter(transport.BandwidthConfig{SessionID: sessionID, PipelineVersion: cfg.PipelineVersion}),
+		playback:    transport.NewPlaybackTracker(transport.PlaybackConfig{SessionID: sessionID, PipelineVersion: cfg.PipelineVersion}),
+		captureEnds: map[string]int64{},
+		report:      transport.Report{SessionID: sessionID, PipelineVersion: cfg.PipelineVersion},
+	}
+	local, err := s.negotiate(offer)
+	if err != nil {
+		_ = pc.Close()
+		return nil, nil, err
+	}
+	return s, local, nil
+}
+
+func (s *session) negotiate(offer pion.SessionDescription) (*pion.SessionDescription, error) {
+	reply, err := pion.NewTrackLocalStaticSample(pcmuCapability(), "reply", s.report.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	sender, err := s.pc.AddTrack(reply)
+	if err != nil {
+		return nil, err
+	}
+	s.reply = reply
+	go drainRTCP(sender)
+
+	s.pc.OnTrack(func(track *pion.TrackRemote, _ *pion.RTPReceiver) {
+		if track.Kind() != pion.RTPCodecTypeAudio {
+			return
+		}
+		for {
+			packet, _, err := track.ReadRTP()
+			if err != nil {
+				return
+			}
+			s.push(event{kind: eventAudio, packet: packet})
+		}
+	})
+	s.pc.OnDataChannel(func(channel *pion.DataChannel) {
+		if channel.Label() != ControlChannelLabel {
+			return
+		}
+		channel.OnOpen(func() { s.push(event{kind: eventControlOpen, channel: channel}) })
+		channel.OnMessage(func(msg pion.DataChannelMessage) {
+			s.push(event{kind: eventControlMessage, payload: msg.Data})
+		})
+		channel.OnClose(func() { s.push(event{kind: eventEnd, signal: "ended", reason: "client_closed"}) })
+	})
+	s.pc.OnConnectionStateChange(func(state pion.PeerConnectionState) {
+		switch state {
+		case pion.PeerConnectionStateFailed:
+			s.push(event{kind: eventEnd, signal: "disconnected", reason: "connection_lost"})
+		case pion.PeerConnectionStateClosed:
+			s.push(event{kind: eventEnd, signal: "ended", reason: "client_closed"})
+		}
+	})
+
+	if err := s.pc.SetRemoteDescription(offer); err != nil {
+		return nil, fmt.Errorf("set remote description: %w", err)
+	}
+	localAnswer, err := s.pc.CreateAnswer(nil)
+	if err != nil {
+		return nil, fmt.Errorf("create answer: %w", err)
+	}
+	gathered := pion.GatheringCompletePromise(s.pc)
+	if err := s.pc.SetLocalDescription(localAnswer); err != nil {
+		return nil, fmt.Errorf("set local description: %w", err)
+	}
+	<-gathered
+	return s.pc.LocalDescription(), nil
+}
+
+func (s *session) push(ev event) {
+	select {
+	case s.events <- ev:
+	case <-s.done:
+	}
+}
+
+func (s *session) serve() error {
+	defer close(s.done)
+	defer func() { _ = s.pc.Close() }()
+
+	pipeline, err := s.cfg.NewPipeline(s.report.SessionID, s.report.Traceparent)
+	if err != nil {
+		_ = s.signal("disconnected", "pipeline_unavailable")
+		return err
+	}
+	defer func() {
+		if err := pipeline.Close(); err != nil {
+			s.cfg.Logger.Printf("webrtc transport: session %s pipeline close: %v", s.report.SessionID, err)
+		}
+	}()
+
+	for ev := range s.events {
+		var turn *websocket.Turn
+		var err error
+		switch ev.kind {
+		case eventEnd:
+			return s.signal(ev.signal, ev.reason)
+		case eventControlOpen:
+			if s.control != nil {
+				continue
+			}
+			s.control = ev.channel
+			if err := s.send(serverMessage{Type: websocket.MessageSession, SessionID: s.report.SessionID, SampleRateHz: SampleRateHz}); err != nil {
+				return err
+			}
+			err = s.signal("connected", "")
+		case eventControlMessage:
+			s.controlSequence++
+			turn, err = s.handleControl(pipeline, ev.payload)
+		case eventAudio:
+			turn, err = s.handleAudio(pipeline, ev.packet)
+		}
+		if err != nil {
+			s.report.Errors++
+			if werr := s.send(serverMessage{Type: websocket.MessageError, Error: err.Error()}); werr != nil {
+				return werr
+			}
+			continue
+		}
+		if turn != nil {
+			if err := s.egressTurn(*turn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *session) handleAudio(pipeline websocket.Pipeline, packet *rtp.Packet) (*websocket.Turn, error) {
+	s.report.AudioFrames++
+	_ = s.meter.Record(transport.DirectionIngress, eventabi.LaneData, eventabi.PayloadAudioRaw, len(packet.Payload))
+	position, ok := s.rtp.advance(packet.SequenceNumber, packet.Timestamp)
+	if !ok {
+		s.report.LatePackets++
+		return nil, nil
+	}
+	s.report.LostPackets = s.rtp.lost
+	if err := s.ingress(eventabi.LaneData, eventabi.PayloadAudioRaw, position.sequence, &eventabi.MediaTime{SampleIndex: &position.sampleIndex}); err != nil {
+		return nil, err
+	}
+	pcm := transport.DecodeMuLaw(packet.Payload)
+	s.report.AudioSamples += int64(len(pcm))
+	turn, err := pipeline.AppendAudio(pcm)
+	if turn != nil {
+		// The pipeline force-ended a capture the client never released.
+		s.captureEnds[turn.TurnID] = s.nowMS()
+	}
+	return turn, err
+}
+
+func (s *session) handleControl(pipeline websocket.Pipeline, payload []byte) (*websocket.Turn, error) {
+	_ = s.meter.Record(transport.DirectionIngress, eventabi.LaneControl, eventabi.PayloadMetadata, len(payload))
+	s.report.ControlMessages++
+	var msg clientMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return nil, fmt.Errorf("decode client message: %w", err)
+	}
+	switch msg.Type {
+	case websocket.MessageCaptureStart:
+		if err := s.signal("capture_start", ""); err != nil {
+			return nil, err
+		}
+		return nil, pipeline.StartCapture()
+	case websocket.MessageCaptureEnd:
+		if err := s.signal("capture_end", ""); err != nil {
+			return nil, err
+		}
+		captureEndMS := s.nowMS()
+		turn, err := pipeline.EndCapture()
+		if turn != nil {
+			s.captureEnds[turn.TurnID] = captureEndMS
+		}
+		return turn, err
+	case websocket.MessagePlaybackAck:
+		if err := s.ingress(eventabi.LaneTelemetry, eventabi.PayloadMetadata, s.controlSequence, nil); err != nil {
+			return nil, err
+		}
+		return nil, s.acknowledgePlayback(transport.PlaybackAck{TurnID: msg.TurnID, PlayedUntilMS: msg.PlayedUntilMS})
+	default:
+		return nil, fmt.Errorf("unsupported client message type %q", msg.Type)
+	}
+}
+
+func (s *session) acknowledgePlayback(ack transport.PlaybackAck) error {
+	playback, first, err := s.playback.Acknowledge(ack, s.nowMS())
+	if err != nil || !first {
+		return err
+	}
+	firstAudio := transport.FirstAudio{
+		TurnID:                       playback.TurnID,
+		PerceivedFirstAudioLatencyMS: playback.PlayedAtMS - s.captureEnds[playback.TurnID],
+		PlaybackStartDelayMS:         playback.PlayedAtMS - playback.EgressAtMS,
+	}
+	s.report.FirstAudio = append(s.report.FirstAudio, firstAudio)
+	return s.send(serverMessage{Type: websocket.MessagePlayback, TurnID: firstAudio.TurnID, PerceivedFirstAudioLatencyMS: firstAudio.PerceivedFirstAudioLatencyMS})
+}
+
+// egressTurn sends the turn's text on the control channel and paces its reply audio onto the
+// reply track in real time, 20 ms per PCMU frame.
+func (s *session) egressTurn(turn websocket.Turn) error {
+	s.report.Turns++
+	if err := s.send(serverMessage{
+		Type:                 websocket.MessageTurn,
+		TurnID:               turn.TurnID,
+		Transcript:           turn.Transcript,
+		Reply:                turn.Reply,
+		Committed:            turn.Committed,
+		FallbackReason:       turn.FallbackReason,
+		FirstOutputLatencyMS: s.nowMS() - s.captureEnds[turn.TurnID],
+	}); err != nil {
+		return err
+	}
+	frameDuration := time.Second * frameSamples / SampleRateHz
+	for start := 0; start < len(turn.ReplyPCM); start += frameSamples {
+		frame := transport.EncodeMuLaw(turn.ReplyPCM[start:min(start+frameSamples, len(turn.ReplyPCM))])
+		_ = s.meter.Record(transport.DirectionEgress, eventabi.LaneData, eventabi.PayloadAudioRaw, len(frame))
+		if err := s.reply.WriteSample(media.Sample{Data: frame, Duration: frameDuration}); err != nil {
+			return err
+		}
+		if start == 0 {
+			if err := s.playback.RecordEgress(turn.TurnID, s.nowMS()); err != nil {
+				return err
+			}
+		}
+		time.Sleep(frameDuration)
+	}
+	return nil
+}
+
+// ingress tags and sequences the event record for the current RTP packet or control message
+// and validates it against the event ABI before the pipeline sees the payload.
+func (s *session) ingress(lane eventabi.Lane, class eventabi.PayloadClass, transportSequence int64, mediaTime *eventabi.MediaTime) error {
+	eventID, err := s.ids.New(identifiers.KindEvent)
+	if err != nil {
+		return err
+	}
+	record, err := transport.TagIngressEventRecord(eventabi.EventRecord{
+		SchemaVersion:      "v1.0",
+		EventScope:         eventabi.ScopeSession,
+		SessionID:          s.report.SessionID,
+		PipelineVersion:    s.cfg.PipelineVersion,
+		EventID:            eventID,
+		Lane:               lane,
+		TransportSequence:  &transportSequence,
+		RuntimeTimestampMS: s.nowMS(),
+		WallClockMS:        s.cfg.Clock().UnixMilli(),
+		PayloadClass:       class,
+		MediaTime:          mediaTime,
+	}, transport.IngressClassificationConfig{Sequences: s.sequences})
+	if err != nil {
+		return err
+	}
+	if err := record.Validate(); err != nil {
+		return fmt.Errorf("ingress event record: %w", err)
+	}
+	s.report.LastRuntimeSequence = record.RuntimeSequence
+	return nil
+}
+
+// signal records an RK-23 control signal in the report and sends it on the control channel
+// once the channel is open.
+func (s *session) signal(name string, reason string) error {
+	eventID, err := s.ids.New(identifiers.KindEvent)
+	if err != nil {
+		return err
+	}
+	runtimeSequence, err := s.sequences.Next(s.report.SessionID)
+	if err != nil {
+		return err
+	}
+	signal, err := transport.BuildConnectionSignal(transport.ConnectionSignalInput{
+		SessionID:            s.report.SessionID,
+		PipelineVersion:      s.cfg.PipelineVersion,
+		EventID:              eventID,
+		Signal:               name,
+		TransportSequence:    s.controlSequence,
+		RuntimeSequence:      runtimeSequence,
+		AuthorityEpoch:       s.cfg.AuthorityEpoch,
+		RuntimeTimestampMS:   s.nowMS(),
+		WallClockTimestampMS: s.cfg.Clock().UnixMilli(),
+		Reason:               reason,
+	})
+	if err != nil {
+		return err
+	}
+	s.report.Signals = append(s.report.Signals, signal)
+	s.report.LastRuntimeSequence = runtimeSequence
+	if name == "ended" || name == "disconnected" {
+		return nil
+	}
+	return s.send(serverMessage{Type: MessageSignal, Signal: &signal})
+}
+
+func (s *session) finish() transport.Report {
+	s.report.Bandwidth = s.meter.Report()
+	s.sequences.Forget(s.report.SessionID)
+	return s.report
+}
+
+// send writes msg on the control channel; messages before the channel opens are dropped.
+func (s *session) send(msg serverMessage) error {
+	if s.control == nil || s.control.ReadyState() != pion.DataChannelStateOpen {
+		return nil
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if msg.Type == websocket.MessageTurn {
+		_ = s.meter.Record(transport.DirectionEgress, eventabi.LaneData, eventabi.PayloadTextRaw, len(data))
+	} else {
+		_ = s.meter.Record(transport.DirectionEgress, eventabi.LaneControl, eventabi.PayloadMetadata, len(data))
+	}
+	return s.control.SendText(string(data))
+}
+
+func (s *session) nowMS() int64 {
+	return s.cfg.Clock().Sub(s.startedAt).Milliseconds()
+}
+
+func drainRTCP(sender *pion.RTPSender) {
+	buf := make([]byte, 1500)
+	for {
+		if _, _, err := sender.Read(buf); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,371 @@
+package webrtc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	pion "github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/transport"
+	"github.com/tiger/realtime-speech-pipeline/transports/websocket"
+)
+
+// testTraceparent is the W3C traceparent header test clients send with the handshake.
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// echoPipeline completes a turn on capture end, replying with the captured audio.
+type echoPipeline struct {
+	mu       sync.Mutex
+	captured []int16
+	turns    int
+	closed   chan struct{}
+}
+
+func (p *echoPipeline) StartCapture() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.captured = nil
+	return nil
+}
+
+func (p *echoPipeline) AppendAudio(pcm []int16) (*websocket.Turn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.captured = append(p.captured, pcm...)
+	return nil, nil
+}
+
+func (p *echoPipeline) EndCapture() (*websocket.Turn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.captured) == 0 {
+		return nil, fmt.Errorf("no audio captured")
+	}
+	p.turns++
+	return &websocket.Turn{TurnID: fmt.Sprintf("turn-%d", p.turns), Transcript: "hello", Reply: "hello back", ReplyPCM: p.captured, Committed: true}, nil
+}
+
+func (p *echoPipeline) Close() error {
+	close(p.closed)
+	return nil
+}
+
+func (p *echoPipeline) capturedSamples() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.captured)
+}
+
+func loopbackSettings() pion.SettingEngine {
+	settings := pion.SettingEngine{}
+	settings.SetNetworkTypes([]pion.NetworkType{pion.NetworkTypeUDP4})
+	settings.SetIncludeLoopbackCandidate(true)
+	settings.SetInterfaceFilter(func(name string) bool { return name == "lo" })
+	return settings
+}
+
+// testPeer is a pion client that sends microphone audio and control messages.
+type testPeer struct {
+	t        *testing.T
+	pc       *pion.PeerConnection
+	control  *pion.DataChannel
+	mic      *pion.TrackLocalStaticSample
+	messages chan serverMessage
+	reply    chan struct{}
+}
+
+func dialTestPeer(t *testing.T, serverURL string) *testPeer {
+	t.Helper()
+	mediaEngine := &pion.MediaEngine{}
+	if err := mediaEngine.RegisterCodec(pion.RTPCodecParameters{RTPCodecCapability: pcmuCapability(), PayloadType: 0}, pion.RTPCodecTypeAudio); err != nil {
+		t.Fatalf("register codec: %v", err)
+	}
+	api := pion.NewAPI(pion.WithMediaEngine(mediaEngine), pion.WithSettingEngine(loopbackSettings()))
+	pc, err := api.NewPeerConnection(pion.Configuration{})
+	if err != nil {
+		t.Fatalf("new peer connection: %v", err)
+	}
+	peer := &testPeer{t: t, pc: pc, messages: make(chan serverMessage, 32), reply: make(chan struct{}, 1)}
+	if peer.mic, err = pion.NewTrackLocalStaticSample(pcmuCapability(), "mic", "client"); err != nil {
+		t.Fatalf("new track: %v", err)
+	}
+	if _, err := pc.AddTrack(peer.mic); err != nil {
+		t.Fatalf("add track: %v", err)
+	}
+	pc.OnTrack(func(track *pion.TrackRemote, _ *pion.RTPReceiver) {
+		if _, _, err := track.ReadRTP(); err == nil {
+			peer.reply <- struct{}{}
+		}
+	})
+	if peer.control, err = pc.CreateDataChannel(ControlChannelLabel, nil); err != nil {
+		t.Fatalf("create data channel: %v", err)
+	}
+	peer.control.OnMessage(func(msg pion.DataChannelMessage) {
+		var decoded serverMessage
+		if err := json.Unmarshal(msg.Data, &decoded); err == nil {
+			peer.messages <- decoded
+		}
+	})
+
+	offer, err := pc.CreateOffer(nil)
+	if err != nil {
+		t.Fatalf("create offer: %v", err)
+	}
+	gathered := pion.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(offer); err != nil {
+		t.Fatalf("set local description: %v", err)
+	}
+	<-gathered
+	body, _ := json.Marshal(pc.LocalDescription())
+	req, err := http.NewRequest(http.MethodPost, serverURL, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("new offer request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Traceparent", testTraceparent)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("post offer: %v", err)
+	}
+	defer resp.Body.Close()
+	var ans answer
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&ans) != nil || ans.SessionID == "" {
+		t.Fatalf("unexpected answer response: %d %+v", resp.StatusCode, ans)
+	}
+	if err := pc.SetRemoteDescription(pion.SessionDescription{Type: pion.SDPTypeAnswer, SDP: ans.SDP}); err != nil {
+		t.Fatalf("set remote description: %v", err)
+	}
+	return peer
+}
+
+func (p *testPeer) sendJSON(msg clientMessage) {
+	p.t.Helper()
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		p.t.Fatalf("encode client message: %v", err)
+	}
+	if err := p.control.SendText(string(payload)); err != nil {
+		p.t.Fatalf("send client message: %v", err)
+	}
+}
+
+func (p *testPeer) next() serverMessage {
+	p.t.Helper()
+	select {
+	case msg := <-p.messages:
+		return msg
+	case <-time.After(10 * time.Second):
+		p.t.Fatalf("timed out waiting for server message")
+	}
+	return serverMessage{}
+}
+
+func TestHandlerRunsSessionOverPeerConnection(t *testing.T) {
+	t.Parallel()
+
+	pipeline := &echoPipeline{closed: make(chan struct{})}
+	reports := make(chan transport.Report, 1)
+	var gotTraceparent string
+	handler, err := NewHandler(Config{
+		PipelineVersion: "pipeline-webrtc",
+		AuthorityEpoch:  3,
+		NewPipeline: func(_ string, traceparent string) (websocket.Pipeline, error) {
+			gotTraceparent = traceparent
+			return pipeline, nil
+		},
+		OnReport:      func(report transport.Report) { reports <- report },
+		SettingEngine: loopbackSettings(),
+		Logger:        log.New(io.Discard, "", 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	peer := dialTestPeer(t, server.URL)
+
+	hello := peer.next()
+	if hello.Type != websocket.MessageSession || hello.SessionID == "" || hello.SampleRateHz != SampleRateHz {
+		t.Fatalf("unexpected session message: %+v", hello)
+	}
+	if msg := peer.next(); msg.Type != MessageSignal || msg.Signal == nil || msg.Signal.Signal != "connected" {
+		t.Fatalf("expected connected signal, got %+v", msg)
+	}
+	peer.sendJSON(clientMessage{Type: websocket.MessageCaptureStart})
+	if msg := peer.next(); msg.Type != MessageSignal || msg.Signal.Signal != "capture_start" {
+		t.Fatalf("expected capture_start signal, got %+v", msg)
+	}
+	frame := transport.EncodeMuLaw(make([]int16, frameSamples))
+	deadline := time.Now().Add(10 * time.Second)
+	for pipeline.capturedSamples() < 3*frameSamples {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for audio to reach the pipeline")
+		}
+		if err := peer.mic.WriteSample(media.Sample{Data: frame, Duration: 20 * time.Millisecond}); err != nil {
+			t.Fatalf("write sample: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	peer.sendJSON(clientMessage{Type: websocket.MessageCaptureEnd})
+	if msg := peer.next(); msg.Type != MessageSignal || msg.Signal.Signal != "capture_end" {
+		t.Fatalf("expected capture_end signal, got %+v", msg)
+	}
+	turn := peer.next()
+	if turn.Type != websocket.MessageTurn || turn.TurnID != "turn-1" || turn.Transcript != "hello" || !turn.Committed {
+		t.Fatalf("unexpected turn message: %+v", turn)
+	}
+	select {
+	case <-peer.reply:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out waiting for reply audio")
+	}
+	peer.sendJSON(clientMessage{Type: websocket.MessagePlaybackAck, TurnID: "turn-1", PlayedUntilMS: 5})
+	if msg := peer.next(); msg.Type != websocket.MessagePlayback || msg.TurnID != "turn-1" {
+		t.Fatalf("expected playback message, got %+v", msg)
+	}
+	peer.sendJSON(clientMessage{Type: "dance"})
+	if msg := peer.next(); msg.Type != websocket.MessageError {
+		t.Fatalf("expected unsupported message error, got %+v", msg)
+	}
+	_ = peer.pc.Close()
+
+	var report transport.Report
+	select {
+	case report = <-reports:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out waiting for transport report")
+	}
+	if gotTraceparent != testTraceparent || report.Traceparent != testTraceparent {
+		t.Fatalf("expected the handshake traceparent passed to the pipeline and reported, got %q and %q", gotTraceparent, report.Traceparent)
+	}
+	select {
+	case <-pipeline.closed:
+	default:
+		t.Fatalf("expected pipeline to be closed with the peer connection")
+	}
+	if report.SessionID != hello.SessionID || report.AudioFrames < 3 || report.AudioSamples < 3*frameSamples || report.ControlMessages != 4 || report.Turns != 1 || report.Errors != 1 {
+		t.Fatalf("unexpected report counts: %+v", report)
+	}
+	if len(report.FirstAudio) != 1 || report.FirstAudio[0].TurnID != "turn-1" {
+		t.Fatalf("expected one first-audio measurement, got %+v", report.FirstAudio)
+	}
+	if report.Bandwidth.IngressBytes == 0 || report.Bandwidth.EgressBytes == 0 {
+		t.Fatalf("expected ingress and egress bandwidth, got %+v", report.Bandwidth)
+	}
+	wantSignals := []string{"connected", "capture_start", "capture_end"}
+	if len(report.Signals) != len(wantSignals)+1 {
+		t.Fatalf("expected signals %v and a terminal signal, got %+v", wantSignals, report.Signals)
+	}
+	if last := report.Signals[len(report.Signals)-1]; last.Signal != "ended" && last.Signal != "disconnected" {
+		t.Fatalf("expected a terminal signal, got %+v", last)
+	}
+	var lastSequence int64 = -1
+	for i, sig := range report.Signals {
+		if i < len(wantSignals) && sig.Signal != wantSignals[i] {
+			t.Fatalf("signal %d: expected %s, got %+v", i, wantSignals[i], sig)
+		}
+		if sig.EmittedBy != "RK-23" || sig.Lane != eventabi.LaneControl || sig.AuthorityEpoch != 3 || sig.PipelineVersion != "pipeline-webrtc" {
+			t.Fatalf("signal %d: unexpected boundary fields %+v", i, sig)
+		}
+		if err := sig.Validate(); err != nil {
+			t.Fatalf("signal %d: unexpected validation error: %v", i, err)
+		}
+		if sig.RuntimeSequence <= lastSequence {
+			t.Fatalf("signal %d: expected increasing runtime sequence, got %d after %d", i, sig.RuntimeSequence, lastSequence)
+		}
+		lastSequence = sig.RuntimeSequence
+	}
+}
+
+func TestHandlerRejectsInvalidOffers(t *testing.T) {
+	t.Parallel()
+
+	handler, err := NewHandler(Config{
+		PipelineVersion: "pipeline-webrtc",
+		NewPipeline: func(string, string) (websocket.Pipeline, error) {
+			return &echoPipeline{closed: make(chan struct{})}, nil
+		},
+		Logger: log.New(io.Discard, "", 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		want   int
+	}{
+		{name: "get", method: http.MethodGet, want: http.StatusMethodNotAllowed},
+		{name: "malformed json", method: http.MethodPost, body: "{", want: http.StatusBadRequest},
+		{name: "answer instead of offer", method: http.MethodPost, body: `{"type":"answer","sdp":"v=0"}`, want: http.StatusBadRequest},
+		{name: "unparseable sdp", method: http.MethodPost, body: `{"type":"offer","sdp":"not sdp"}`, want: http.StatusBadRequest},
+	}
+	for _, tc := range tests {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(tc.method, "/offer", bytes.NewBufferString(tc.body)))
+		if recorder.Code != tc.want {
+			t.Fatalf("%s: expected status %d, got %d", tc.name, tc.want, recorder.Code)
+		}
+	}
+}
+
+func TestNewHandlerValidatesConfig(t *testing.T) {
+	t.Parallel()
+
+	factory := func(string, string) (websocket.Pipeline, error) { return nil, nil }
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "missing pipeline version", cfg: Config{NewPipeline: factory}},
+		{name: "missing factory", cfg: Config{PipelineVersion: "pipeline-webrtc"}},
+		{name: "negative epoch", cfg: Config{PipelineVersion: "pipeline-webrtc", NewPipeline: factory, AuthorityEpoch: -1}},
+	}
+	for _, tc := range tests {
+		if _, err := NewHandler(tc.cfg); err == nil {
+			t.Fatalf("%s: expected config error", tc.name)
+		}
+	}
+}
+
+func TestRTPClockExtendsSequenceAndTime(t *testing.T) {
+	t.Parallel()
+
+	var clock rtpClock
+	tests := []struct {
+		name       string
+		sequence   uint16
+		timestamp  uint32
+		ok         bool
+		wantSeq    int64
+		wantSample int64
+	}{
+		{name: "first packet", sequence: 65534, timestamp: 4294967136, ok: true, wantSeq: 65534, wantSample: 0},
+		{name: "next packet", sequence: 65535, timestamp: 0, ok: true, wantSeq: 65535, wantSample: 160},
+		{name: "gap across wraparound", sequence: 1, timestamp: 320, ok: true, wantSeq: 65537, wantSample: 480},
+		{name: "duplicate", sequence: 1, timestamp: 320, ok: false},
+		{name: "late", sequence: 0, timestamp: 160, ok: false},
+	}
+	for _, tc := range tests {
+		position, ok := clock.advance(tc.sequence, tc.timestamp)
+		if ok != tc.ok {
+			t.Fatalf("%s: expected ok=%v", tc.name, tc.ok)
+		}
+		if ok && (position.sequence != tc.wantSeq || position.sampleIndex != tc.wantSample) {
+			t.Fatalf("%s: expected sequence %d sample %d, got %+v", tc.name, tc.wantSeq, tc.wantSample, position)
+		}
+	}
+	if clock.lost != 1 {
+		t.Fatalf("expected one lost packet, got %d", clock.lost)
+	}
+}
@@ -0,0 +1,423 @@
+package websocket
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/demo"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/sequence"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/transport"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/identifiers"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/wscodec"
+)
+
+// DefaultSampleRateHz is the PCM16LE mono rate announced to clients when Config leaves it unset.
+const DefaultSampleRateHz = demo.DefaultSampleRateHz
+
+// Client and server message types. The protocol is the one the embedded demo web client
+// speaks, so a browser capturing the microphone works against this transport unchanged.
+const (
+	MessageSession      = "session"
+	MessageTurn         = "turn"
+	MessagePlayback     = "playback"
+	MessageError        = "error"
+	MessageCaptureStart = "capture_start"
+	MessageCaptureEnd   = "capture_end"
+	MessagePlaybackAck  = "playback_ack"
+)
+
+// Turn is one completed turn a Pipeline hands back for egress.
+type Turn struct {
+	TurnID         string
+	Transcript     string
+	Reply          string
+	ReplyPCM       []int16
+	Committed      bool
+	FallbackReason string
+}
+
+// Pipeline runs the conversation behind one connection. The transport calls it from a single
+// goroutine; a nil Turn means the call completed no turn.
+type Pipeline interface {
+	StartCapture() error
+	AppendAudio(pcm []int16) (*Turn, error)
+	EndCapture() (*Turn, error)
+	// Close is called once after the connection ends.
+	Close() error
+}
+
+// PlaybackRecorder is implemented by pipelines that record reply playback in their turn
+// evidence. Transports that learn when the caller heard or stopped hearing a reply report it
+// through a pipeline implementing it.
+type PlaybackRecorder interface {
+	RecordReplyEgress(turnID string) error
+	AcknowledgePlayback(ack transport.PlaybackAck) error
+	CancelPlayback(turnID string, reason string) error
+}
+
+// Config configures the WebSocket transport handler.
+type Config struct {
+	PipelineVersion string
+	AuthorityEpoch  int64
+	SampleRateHz    int
+	// NewPipeline starts the pipeline for each accepted connection, passing the traceparent
+	// header of the upgrade request (empty when absent).
+	NewPipeline func(sessionID, traceparent string) (Pipeline, error)
+	// OnReport, when set, receives each connection's Report after it closes.
+	OnReport func(transport.Report)
+	// Clock defaults to time.Now.
+	Clock func() time.Time
+	// Logger defaults to log.Default().
+	Logger *log.Logger
+}
+
+type clientMessage struct {
+	Type          string `json:"type"`
+	TurnID        string `json:"turn_id,omitempty"`
+	PlayedUntilMS int64  `json:"played_until_ms,omitempty"`
+}
+
+type serverMessage struct {
+	Type                         string `json:"type"`
+	SessionID                    string `json:"session_id,omitempty"`
+	SampleRateHz                 int    `json:"sample_rate_hz,omitempty"`
+	TurnID                       string `json:"turn_id,omitempty"`
+	Transcript                   string `json:"transcript,omitempty"`
+	Reply                        string `json:"reply,omitempty"`
+	Committed                    bool   `json:"committed,omitempty"`
+	FallbackReason               string `json:"fallback_reason,omitempty"`
+	FirstOutputLatencyMS         int64  `json:"first_output_latency_ms,omitempty"`
+	PerceivedFirstAudioLatencyMS int64  `json:"perceived_first_audio_latency_ms,omitempty"`
+	Error                        string `json:"error,omitempty"`
+}
+
+// NewHandler upgrades each request to a WebSocket and runs one pipeline session over it.
+// Binary client frames are PCM16LE audio on the data lane; text frames are JSON control
+// messages.
+func NewHandler(cfg Config) (http.Handler, error) {
+	if cfg.PipelineVersion == "" {
+		return nil, fmt.Errorf("pipeline_version is required")
+	}
+	if cfg.NewPipeline == nil {
+		return nil, fmt.Errorf("pipeline factory is required")
+	}
+	if cfg.AuthorityEpoch < 0 {
+		return nil, fmt.Errorf("authority_epoch must be >=0")
+	}
+	if cfg.SampleRateHz == 0 {
+		cfg.SampleRateHz = DefaultSampleRateHz
+	}
+	if cfg.SampleRateHz < 1 {
+		return nil, fmt.Errorf("sample_rate_hz must be >=1")
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.Default()
+	}
+	ids := identifiers.NewGenerator(cfg.Clock, nil)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wscodec.Upgrade(w, r)
+		if err != nil {
+			cfg.Logger.Printf("websocket transport: upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		sessionID, err := ids.New(identifiers.KindSession)
+		if err != nil {
+			cfg.Logger.Printf("websocket transport: session id: %v", err)
+			return
+		}
+		s := newSession(cfg, ids, sessionID, conn)
+		s.report.Traceparent = r.Header.Get("traceparent")
+		if err := s.serve(); err != nil {
+			cfg.Logger.Printf("websocket transport: session %s ended: %v", sessionID, err)
+		}
+		if cfg.OnReport != nil {
+			cfg.OnReport(s.finish())
+		}
+	}), nil
+}
+
+// session is one connection's transport state.
+type session struct {
+	cfg       Config
+	ids       *identifiers.Generator
+	conn      *wscodec.Conn
+	startedAt time.Time
+
+	sequences *sequence.Allocator
+	meter     *transport.BandwidthMeter
+	playback  *transport.PlaybackTracker
+	// transportSequence counts client messages; signals carry the sequence of the message that
+	// caused them.
+	transportSequence int64
+	// captureEnds maps turn ids to capture end, the origin of perceived first-audio latency.
+	captureEnds map[string]int64
+	report      transport.Report
+}
+
+func newSession(cfg Config, ids *identifiers.Generator, sessionID string, conn *wscodec.Conn) *session {
+	return &session{
+		cfg:         cfg,
+		ids:         ids,
+		conn:        conn,
+		startedAt:   cfg.Clock(),
+		sequences:   sequence.NewAllocator(),
+		meter:       transport.NewBandwidthMeter(transport.BandwidthConfig{SessionID: sessionID, PipelineVersion: cfg.PipelineVersion}),
+		playback:    transport.NewPlaybackTracker(transport.PlaybackConfig{SessionID: sessionID, PipelineVersion: cfg.PipelineVersion}),
+		captureEnds: map[string]int64{},
+		report:      transport.Report{SessionID: sessionID, PipelineVersion: cfg.PipelineVersion},
+	}
+}
+
+func (s *session) serve() error {
+	pipeline, err := s.cfg.NewPipeline(s.report.SessionID, s.report.Traceparent)
+	if err != nil {
+		_ = s.writeJSON(serverMessage{Type: MessageError, Error: err.Error()})
+		_ = s.signal("disconnected", "pipeline_unavailable")
+		return err
+	}
+	defer func() {
+		if err := pipeline.Close(); err != nil {
+			s.cfg.Logger.Printf("websocket transport: session %s pipeline close: %v", s.report.SessionID, err)
+		}
+	}()
+	if err := s.signal("connected", ""); err != nil {
+		return err
+	}
+	if err := s.writeJSON(serverMessage{Type: MessageSession, SessionID: s.report.SessionID, SampleRateHz: s.cfg.SampleRateHz}); err != nil {
+		return err
+	}
+
+	for {
+		op, payload, err := s.conn.ReadMessage()
+		switch {
+		case errors.Is(err, wscodec.ErrClosed):
+			return s.signal("ended", "client_closed")
+		case errors.Is(err, io.EOF):
+			return s.signal("disconnected", "connection_lost")
+		case err != nil:
+			_ = s.signal("disconnected", "read_error")
+			return err
+		}
+		s.transportSequence++
+
+		var turn *Turn
+		if op == wscodec.OpBinary {
+			turn, err = s.handleAudio(pipeline, payload)
+		} else {
+			turn, err = s.handleControl(pipeline, payload)
+		}
+		if err != nil {
+			s.report.Errors++
+			if werr := s.writeJSON(serverMessage{Type: MessageError, Error: err.Error()}); werr != nil {
+				return werr
+			}
+			continue
+		}
+		if turn != nil {
+			if err := s.egressTurn(*turn); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *session) handleAudio(pipeline Pipeline, payload []byte) (*Turn, error) {
+	_ = s.meter.Record(transport.DirectionIngress, eventabi.LaneData, eventabi.PayloadAudioRaw, len(payload))
+	if len(payload)%2 != 0 {
+		return nil, fmt.Errorf("audio frame must be whole PCM16LE samples, got %d bytes", len(payload))
+	}
+	sampleIndex := s.report.AudioSamples
+	if err := s.ingress(eventabi.LaneData, eventabi.PayloadAudioRaw, &eventabi.MediaTime{SampleIndex: &sampleIndex}); err != nil {
+		return nil, err
+	}
+	pcm := decodePCM16(payload)
+	s.report.AudioFrames++
+	s.report.AudioSamples += int64(len(pcm))
+	turn, err := pipeline.AppendAudio(pcm)
+	if turn != nil {
+		// The pipeline force-ended a capture the client never released.
+		s.captureEnds[turn.TurnID] = s.nowMS()
+	}
+	return turn, err
+}
+
+func (s *session) handleControl(pipeline Pipeline, payload []byte) (*Turn, error) {
+	_ = s.meter.Record(transport.DirectionIngress, eventabi.LaneControl, eventabi.PayloadMetadata, len(payload))
+	s.report.ControlMessages++
+	var msg clientMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return nil, fmt.Errorf("decode client message: %w", err)
+	}
+	switch msg.Type {
+	case MessageCaptureStart:
+		if err := s.signal("capture_start", ""); err != nil {
+			return nil, err
+		}
+		return nil, pipeline.StartCapture()
+	case MessageCaptureEnd:
+		if err := s.signal("capture_end", ""); err != nil {
+			return nil, err
+		}
+		captureEndMS := s.nowMS()
+		turn, err := pipeline.EndCapture()
+		if turn != nil {
+			s.captureEnds[turn.TurnID] = captureEndMS
+		}
+		return turn, err
+	case MessagePlaybackAck:
+		if err := s.ingress(eventabi.LaneTelemetry, eventabi.PayloadMetadata, nil); err != nil {
+			return nil, err
+		}
+		return nil, s.acknowledgePlayback(transport.PlaybackAck{TurnID: msg.TurnID, PlayedUntilMS: msg.PlayedUntilMS})
+	default:
+		return nil, fmt.Errorf("unsupported client message type %q", msg.Type)
+	}
+}
+
+func (s *session) acknowledgePlayback(ack transport.PlaybackAck) error {
+	playback, first, err := s.playback.Acknowledge(ack, s.nowMS())
+	if err != nil || !first {
+		return err
+	}
+	firstAudio := transport.FirstAudio{
+		TurnID:                       playback.TurnID,
+		PerceivedFirstAudioLatencyMS: playback.PlayedAtMS - s.captureEnds[playback.TurnID],
+		PlaybackStartDelayMS:         playback.PlayedAtMS - playback.EgressAtMS,
+	}
+	s.report.FirstAudio = append(s.report.FirstAudio, firstAudio)
+	return s.writeJSON(serverMessage{Type: MessagePlayback, TurnID: firstAudio.TurnID, PerceivedFirstAudioLatencyMS: firstAudio.PerceivedFirstAudioLatencyMS})
+}
+
+// egressTurn sends the turn's text as a JSON message followed by its reply audio as one binary
+// PCM16LE frame.
+func (s *session) egressTurn(turn Turn) error {
+	s.report.Turns++
+	if err := s.writeJSON(serverMessage{
+		Type:                 MessageTurn,
+		TurnID:               turn.TurnID,
+		Transcript:           turn.Transcript,
+		Reply:                turn.Reply,
+		Committed:            turn.Committed,
+		FallbackReason:       turn.FallbackReason,
+		FirstOutputLatencyMS: s.nowMS() - s.captureEnds[turn.TurnID],
+	}); err != nil {
+		return err
+	}
+	reply := encodePCM16(turn.ReplyPCM)
+	_ = s.meter.Record(transport.DirectionEgress, eventabi.LaneData, eventabi.PayloadAudioRaw, len(reply))
+	if err := s.conn.WriteMessage(wscodec.OpBinary, reply); err != nil {
+		return err
+	}
+	return s.playback.RecordEgress(turn.TurnID, s.nowMS())
+}
+
+// ingress tags and sequences the event record for the current client message and validates it
+// against the event ABI before the pipeline sees the payload.
+func (s *session) ingress(lane eventabi.Lane, class eventabi.PayloadClass, mediaTime *eventabi.MediaTime) error {
+	eventID, err := s.ids.New(identifiers.KindEvent)
+	if err != nil {
+		return err
+	}
+	transportSequence := s.transportSequence
+	record, err := transport.TagIngressEventRecord(eventabi.EventRecord{
+		SchemaVersion:      "v1.0",
+		EventScope:         eventabi.ScopeSession,
+		SessionID:          s.report.SessionID,
+		PipelineVersion:    s.cfg.PipelineVersion,
+		EventID:            eventID,
+		Lane:               lane,
+		TransportSequence:  &transportSequence,
+		RuntimeTimestampMS: s.nowMS(),
+		WallClockMS:        s.cfg.Clock().UnixMilli(),
+		PayloadClass:       class,
+		MediaTime:          mediaTime,
+	}, transport.IngressClassificationConfig{Sequences: s.sequences})
+	if err != nil {
+		return err
+	}
+	if err := record.Validate(); err != nil {
+		return fmt.Errorf("ingress event record: %w", err)
+	}
+	s.report.LastRuntimeSequence = record.RuntimeSequence
+	return nil
+}
+
+// signal records an RK-23 control signal in the report.
+func (s *session) signal(name string, reason string) error {
+	eventID, err := s.ids.New(identifiers.KindEvent)
+	if err != nil {
+		return err
+	}
+	runtimeSequence, err := s.sequences.Next(s.report.SessionID)
+	if err != nil {
+		return err
+	}
+	signal, err := transport.BuildConnectionSignal(transport.ConnectionSignalInput{
+		SessionID:            s.report.SessionID,
+		PipelineVersion:      s.cfg.PipelineVersion,
+		EventID:              eventID,
+		Signal:               name,
+		TransportSequence:    s.transportSequence,
+		RuntimeSequence:      runtimeSequence,
+		AuthorityEpoch:       s.cfg.AuthorityEpoch,
+		RuntimeTimestampMS:   s.nowMS(),
+		WallClockTimestampMS: s.cfg.Clock().UnixMilli(),
+		Reason:               reason,
+	})
+	if err != nil {
+		return err
+	}
+	s.report.Signals = append(s.report.Signals, signal)
+	s.report.LastRuntimeSequence = runtimeSequence
+	return nil
+}
+
+func (s *session) finish() transport.Report {
+	s.report.Bandwidth = s.meter.Report()
+	s.sequences.Forget(s.report.SessionID)
+	return s.report
+}
+
+func (s *session) writeJSON(msg serverMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if msg.Type == MessageTurn {
+		_ = s.meter.Record(transport.DirectionEgress, eventabi.LaneData, eventabi.PayloadTextRaw, len(data))
+	} else {
+		_ = s.meter.Record(transport.DirectionEgress, eventabi.LaneControl, eventabi.PayloadMetadata, len(data))
+	}
+	return s.conn.WriteMessage(wscodec.OpText, data)
+}
+
+func (s *session) nowMS() int64 {
+	return s.cfg.Clock().Sub(s.startedAt).Milliseconds()
+}
+
+func decodePCM16(payload []byte) []int16 {
+	pcm := make([]int16, len(payload)/2)
+	for i := range pcm {
+		pcm[i] = int16(binary.LittleEndian.Uint16(payload[2*i:]))
+	}
+	return pcm
+}
+
+func encodePCM16(pcm []int16) []byte {
+	out := make([]byte, 2*len(pcm))
+	for i, sample := range pcm {
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(sample))
+	}
+	return out
+}
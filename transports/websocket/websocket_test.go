@@ -0,0 +1,297 @@
+package websocket
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tiger/realtime-speech-pipeline/api/eventabi"
+	"github.com/tiger/realtime-speech-pipeline/internal/runtime/transport"
+	"github.com/tiger/realtime-speech-pipeline/internal/shared/wscodec"
+)
+
+// testTraceparent is the W3C traceparent header test clients send with the handshake.
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// echoPipeline completes a turn on capture end, replying with the captured audio.
+type echoPipeline struct {
+	captured []int16
+	turns    int
+	closed   chan struct{}
+}
+
+func (p *echoPipeline) StartCapture() error {
+	p.captured = nil
+	return nil
+}
+
+func (p *echoPipeline) AppendAudio(pcm []int16) (*Turn, error) {
+	p.captured = append(p.captured, pcm...)
+	return nil, nil
+}
+
+func (p *echoPipeline) EndCapture() (*Turn, error) {
+	if len(p.captured) == 0 {
+		return nil, fmt.Errorf("no audio captured")
+	}
+	p.turns++
+	return &Turn{TurnID: fmt.Sprintf("turn-%d", p.turns), Transcript: "hello", Reply: "hello back", ReplyPCM: p.captured, Committed: true}, nil
+}
+
+func (p *echoPipeline) Close() error {
+	close(p.closed)
+	return nil
+}
+
+// testClient is a minimal masking WebSocket client.
+type testClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dialTestClient(t *testing.T, serverURL string) *testClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(serverURL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	request := "GET /ws HTTP/1.1\r\nHost: transport\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Key: " + key + "\r\nTraceparent: " + testTraceparent + "\r\n\r\n"
+	if _, err := conn.Write([]byte(request)); err != nil {
+		t.Fatalf("handshake write: %v", err)
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("handshake read: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != wscodec.AcceptKey(key) {
+		t.Fatalf("unexpected handshake response: %d %v", resp.StatusCode, resp.Header)
+	}
+	return &testClient{t: t, conn: conn, reader: reader}
+}
+
+func (c *testClient) send(op byte, payload []byte) {
+	c.t.Helper()
+	frame := []byte{0x80 | op}
+	if len(payload) < 126 {
+		frame = append(frame, 0x80|byte(len(payload)))
+	} else {
+		frame = append(frame, 0x80|126, byte(len(payload)>>8), byte(len(payload)))
+	}
+	mask := [4]byte{7, 1, 3, 9}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		c.t.Fatalf("frame write: %v", err)
+	}
+}
+
+func (c *testClient) sendJSON(msg clientMessage) {
+	c.t.Helper()
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatalf("encode client message: %v", err)
+	}
+	c.send(wscodec.OpText, payload)
+}
+
+func (c *testClient) read() (byte, []byte) {
+	c.t.Helper()
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		c.t.Fatalf("frame read: %v", err)
+	}
+	length := uint64(header[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			c.t.Fatalf("frame length read: %v", err)
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		c.t.Fatalf("payload read: %v", err)
+	}
+	return header[0] & 0x0F, payload
+}
+
+func (c *testClient) readJSON() serverMessage {
+	c.t.Helper()
+	op, payload := c.read()
+	if op != wscodec.OpText {
+		c.t.Fatalf("expected text frame, got opcode 0x%x", op)
+	}
+	var msg serverMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		c.t.Fatalf("decode server message: %v", err)
+	}
+	return msg
+}
+
+func TestHandlerRunsSessionAndReportsBoundaryEvidence(t *testing.T) {
+	t.Parallel()
+
+	pipeline := &echoPipeline{closed: make(chan struct{})}
+	reports := make(chan transport.Report, 1)
+	var gotTraceparent string
+	handler, err := NewHandler(Config{
+		PipelineVersion: "pipeline-ws",
+		AuthorityEpoch:  4,
+		NewPipeline: func(_ string, traceparent string) (Pipeline, error) {
+			gotTraceparent = traceparent
+			return pipeline, nil
+		},
+		OnReport: func(report transport.Report) { reports <- report },
+		Logger:   log.New(io.Discard, "", 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	client := dialTestClient(t, server.URL)
+
+	hello := client.readJSON()
+	if hello.Type != MessageSession || hello.SessionID == "" || hello.SampleRateHz != DefaultSampleRateHz {
+		t.Fatalf("unexpected session message: %+v", hello)
+	}
+	client.sendJSON(clientMessage{Type: MessageCaptureStart})
+	audio := make([]byte, 320)
+	for i := range audio {
+		audio[i] = byte(i)
+	}
+	client.send(wscodec.OpBinary, audio)
+	client.send(wscodec.OpBinary, audio[:161])
+	if msg := client.readJSON(); msg.Type != MessageError || !strings.Contains(msg.Error, "whole PCM16LE samples") {
+		t.Fatalf("expected odd-length audio to be rejected, got %+v", msg)
+	}
+	client.sendJSON(clientMessage{Type: MessageCaptureEnd})
+	turn := client.readJSON()
+	if turn.Type != MessageTurn || turn.TurnID != "turn-1" || turn.Transcript != "hello" || !turn.Committed {
+		t.Fatalf("unexpected turn message: %+v", turn)
+	}
+	if op, reply := client.read(); op != wscodec.OpBinary || string(reply) != string(audio) {
+		t.Fatalf("expected echoed reply audio, got opcode 0x%x with %d bytes", op, len(reply))
+	}
+	client.sendJSON(clientMessage{Type: MessagePlaybackAck, TurnID: "turn-1", PlayedUntilMS: 0})
+	client.sendJSON(clientMessage{Type: MessagePlaybackAck, TurnID: "turn-1", PlayedUntilMS: 5})
+	if msg := client.readJSON(); msg.Type != MessagePlayback || msg.TurnID != "turn-1" {
+		t.Fatalf("expected playback message, got %+v", msg)
+	}
+	client.sendJSON(clientMessage{Type: "dance"})
+	if msg := client.readJSON(); msg.Type != MessageError {
+		t.Fatalf("expected unsupported message error, got %+v", msg)
+	}
+	client.send(0x8, nil)
+
+	var report transport.Report
+	select {
+	case report = <-reports:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for transport report")
+	}
+	if gotTraceparent != testTraceparent || report.Traceparent != testTraceparent {
+		t.Fatalf("expected the handshake traceparent passed to the pipeline and reported, got %q and %q", gotTraceparent, report.Traceparent)
+	}
+	select {
+	case <-pipeline.closed:
+	default:
+		t.Fatalf("expected pipeline to be closed with the connection")
+	}
+	if report.SessionID != hello.SessionID || report.AudioFrames != 1 || report.AudioSamples != 160 || report.ControlMessages != 5 || report.Turns != 1 || report.Errors != 2 {
+		t.Fatalf("unexpected report counts: %+v", report)
+	}
+	if len(report.FirstAudio) != 1 || report.FirstAudio[0].TurnID != "turn-1" {
+		t.Fatalf("expected one first-audio measurement, got %+v", report.FirstAudio)
+	}
+	if report.Bandwidth.IngressBytes == 0 || report.Bandwidth.EgressBytes == 0 {
+		t.Fatalf("expected ingress and egress bandwidth, got %+v", report.Bandwidth)
+	}
+
+	wantSignals := []string{"connected", "capture_start", "capture_end", "ended"}
+	if len(report.Signals) != len(wantSignals) {
+		t.Fatalf("expected signals %v, got %+v", wantSignals, report.Signals)
+	}
+	var lastSequence int64 = -1
+	for i, sig := range report.Signals {
+		if sig.Signal != wantSignals[i] || sig.EmittedBy != "RK-23" || sig.Lane != eventabi.LaneControl || sig.AuthorityEpoch != 4 || sig.PipelineVersion != "pipeline-ws" {
+			t.Fatalf("signal %d: expected %s, got %+v", i, wantSignals[i], sig)
+		}
+		if err := sig.Validate(); err != nil {
+			t.Fatalf("signal %d: unexpected validation error: %v", i, err)
+		}
+		if sig.RuntimeSequence <= lastSequence {
+			t.Fatalf("signal %d: expected increasing runtime sequence, got %d after %d", i, sig.RuntimeSequence, lastSequence)
+		}
+		lastSequence = sig.RuntimeSequence
+	}
+	if report.LastRuntimeSequence != lastSequence {
+		t.Fatalf("expected ingress records and signals to share one sequence, last=%d final signal=%d", report.LastRuntimeSequence, lastSequence)
+	}
+}
+
+func TestHandlerReportsLostConnections(t *testing.T) {
+	t.Parallel()
+
+	reports := make(chan transport.Report, 1)
+	handler, err := NewHandler(Config{
+		PipelineVersion: "pipeline-ws",
+		NewPipeline: func(string, string) (Pipeline, error) {
+			return &echoPipeline{closed: make(chan struct{})}, nil
+		},
+		OnReport: func(report transport.Report) { reports <- report },
+		Logger:   log.New(io.Discard, "", 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	client := dialTestClient(t, server.URL)
+	client.readJSON()
+	_ = client.conn.Close()
+
+	select {
+	case report := <-reports:
+		last := report.Signals[len(report.Signals)-1]
+		if last.Signal != "disconnected" || last.Reason != "connection_lost" {
+			t.Fatalf("expected disconnected signal, got %+v", last)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for transport report")
+	}
+}
+
+func TestNewHandlerValidatesConfig(t *testing.T) {
+	t.Parallel()
+
+	factory := func(string, string) (Pipeline, error) { return nil, nil }
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "missing pipeline version", cfg: Config{NewPipeline: factory}},
+		{name: "missing factory", cfg: Config{PipelineVersion: "pipeline-ws"}},
+		{name: "negative epoch", cfg: Config{PipelineVersion: "pipeline-ws", NewPipeline: factory, AuthorityEpoch: -1}},
+		{name: "negative sample rate", cfg: Config{PipelineVersion: "pipeline-ws", NewPipeline: factory, SampleRateHz: -1}},
+	}
+	for _, tc := range tests {
+		if _, err := NewHandler(tc.cfg); err == nil {
+			t.Fatalf("%s: expected config error", tc.name)
+		}
+	}
+}